@@ -24,6 +24,38 @@ type Client struct {
 	formats   strfmt.Registry
 }
 
+/*
+GetCapabilities retrieves the capabilities of the agent and the node
+
+Returns the kernel features detected by the agent, the enabled
+subsystems, the sizes of the BPF maps and the version of the API.
+
+*/
+func (a *Client) GetCapabilities(params *GetCapabilitiesParams) (*GetCapabilitiesOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetCapabilitiesParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetCapabilities",
+		Method:             "GET",
+		PathPattern:        "/capabilities",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetCapabilitiesReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetCapabilitiesOK), nil
+
+}
+
 /*
 GetConfig gets configuration of cilium daemon
 
@@ -40,8 +72,8 @@ func (a *Client) GetConfig(params *GetConfigParams) (*GetConfigOK, error) {
 		ID:                 "GetConfig",
 		Method:             "GET",
 		PathPattern:        "/config",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetConfigReader{formats: a.formats},
@@ -68,8 +100,8 @@ func (a *Client) GetDebuginfo(params *GetDebuginfoParams) (*GetDebuginfoOK, erro
 		ID:                 "GetDebuginfo",
 		Method:             "GET",
 		PathPattern:        "/debuginfo",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetDebuginfoReader{formats: a.formats},
@@ -101,8 +133,8 @@ func (a *Client) GetHealthz(params *GetHealthzParams) (*GetHealthzOK, error) {
 		ID:                 "GetHealthz",
 		Method:             "GET",
 		PathPattern:        "/healthz",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetHealthzReader{formats: a.formats},
@@ -129,8 +161,8 @@ func (a *Client) GetMap(params *GetMapParams) (*GetMapOK, error) {
 		ID:                 "GetMap",
 		Method:             "GET",
 		PathPattern:        "/map",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetMapReader{formats: a.formats},
@@ -157,8 +189,8 @@ func (a *Client) GetMapName(params *GetMapNameParams) (*GetMapNameOK, error) {
 		ID:                 "GetMapName",
 		Method:             "GET",
 		PathPattern:        "/map/{name}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetMapNameReader{formats: a.formats},
@@ -172,6 +204,39 @@ func (a *Client) GetMapName(params *GetMapNameParams) (*GetMapNameOK, error) {
 
 }
 
+/*
+GetMapNameEvents retrieves the recent mutations of a b p f map
+
+Returns the most recent updates and deletions of entries of the BPF
+map performed by the agent, including failed ones, in the order in
+which they were performed.
+
+*/
+func (a *Client) GetMapNameEvents(params *GetMapNameEventsParams) (*GetMapNameEventsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetMapNameEventsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetMapNameEvents",
+		Method:             "GET",
+		PathPattern:        "/map/{name}/events",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetMapNameEventsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetMapNameEventsOK), nil
+
+}
+
 /*
 PatchConfig modifies daemon configuration
 
@@ -190,8 +255,8 @@ func (a *Client) PatchConfig(params *PatchConfigParams) (*PatchConfigOK, error)
 		ID:                 "PatchConfig",
 		Method:             "PATCH",
 		PathPattern:        "/config",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PatchConfigReader{formats: a.formats},
@@ -48,8 +48,8 @@ func (a *Client) DeleteEndpointID(params *DeleteEndpointIDParams) (*DeleteEndpoi
 		ID:                 "DeleteEndpointID",
 		Method:             "DELETE",
 		PathPattern:        "/endpoint/{id}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &DeleteEndpointIDReader{formats: a.formats},
@@ -85,8 +85,8 @@ func (a *Client) GetEndpoint(params *GetEndpointParams) (*GetEndpointOK, error)
 		ID:                 "GetEndpoint",
 		Method:             "GET",
 		PathPattern:        "/endpoint",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetEndpointReader{formats: a.formats},
@@ -116,8 +116,8 @@ func (a *Client) GetEndpointID(params *GetEndpointIDParams) (*GetEndpointIDOK, e
 		ID:                 "GetEndpointID",
 		Method:             "GET",
 		PathPattern:        "/endpoint/{id}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetEndpointIDReader{formats: a.formats},
@@ -147,8 +147,8 @@ func (a *Client) GetEndpointIDConfig(params *GetEndpointIDConfigParams) (*GetEnd
 		ID:                 "GetEndpointIDConfig",
 		Method:             "GET",
 		PathPattern:        "/endpoint/{id}/config",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetEndpointIDConfigReader{formats: a.formats},
@@ -162,6 +162,34 @@ func (a *Client) GetEndpointIDConfig(params *GetEndpointIDConfigParams) (*GetEnd
 
 }
 
+/*
+GetEndpointIDControllers retrieves the status of the controllers of this endpoint
+*/
+func (a *Client) GetEndpointIDControllers(params *GetEndpointIDControllersParams) (*GetEndpointIDControllersOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetEndpointIDControllersParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetEndpointIDControllers",
+		Method:             "GET",
+		PathPattern:        "/endpoint/{id}/controllers",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetEndpointIDControllersReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetEndpointIDControllersOK), nil
+
+}
+
 /*
 GetEndpointIDHealthz retrieves the status logs associated with this endpoint
 */
@@ -175,8 +203,8 @@ func (a *Client) GetEndpointIDHealthz(params *GetEndpointIDHealthzParams) (*GetE
 		ID:                 "GetEndpointIDHealthz",
 		Method:             "GET",
 		PathPattern:        "/endpoint/{id}/healthz",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetEndpointIDHealthzReader{formats: a.formats},
@@ -190,6 +218,34 @@ func (a *Client) GetEndpointIDHealthz(params *GetEndpointIDHealthzParams) (*GetE
 
 }
 
+/*
+GetEndpointIDL7stats retrieves the l7 statistics of the endpoint per port and l7 parser
+*/
+func (a *Client) GetEndpointIDL7stats(params *GetEndpointIDL7statsParams) (*GetEndpointIDL7statsOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetEndpointIDL7statsParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetEndpointIDL7stats",
+		Method:             "GET",
+		PathPattern:        "/endpoint/{id}/l7stats",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetEndpointIDL7statsReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetEndpointIDL7statsOK), nil
+
+}
+
 /*
 GetEndpointIDLabels retrieves the list of labels associated with an endpoint
 */
@@ -203,8 +259,8 @@ func (a *Client) GetEndpointIDLabels(params *GetEndpointIDLabelsParams) (*GetEnd
 		ID:                 "GetEndpointIDLabels",
 		Method:             "GET",
 		PathPattern:        "/endpoint/{id}/labels",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetEndpointIDLabelsReader{formats: a.formats},
@@ -231,8 +287,8 @@ func (a *Client) GetEndpointIDLog(params *GetEndpointIDLogParams) (*GetEndpointI
 		ID:                 "GetEndpointIDLog",
 		Method:             "GET",
 		PathPattern:        "/endpoint/{id}/log",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetEndpointIDLogReader{formats: a.formats},
@@ -246,6 +302,41 @@ func (a *Client) GetEndpointIDLog(params *GetEndpointIDLogParams) (*GetEndpointI
 
 }
 
+/*
+PatchEndpoint modifies labels of multiple endpoints
+
+Applies label modifications to all endpoints matched by the selector
+of each operation. All operations are validated before any endpoint
+is modified. The result of each modified endpoint is returned, an
+endpoint which could not be modified does not prevent the
+modification of the remaining endpoints.
+
+*/
+func (a *Client) PatchEndpoint(params *PatchEndpointParams) (*PatchEndpointOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPatchEndpointParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "PatchEndpoint",
+		Method:             "PATCH",
+		PathPattern:        "/endpoint",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PatchEndpointReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*PatchEndpointOK), nil
+
+}
+
 /*
 PatchEndpointID modifies existing endpoint
 
@@ -262,8 +353,8 @@ func (a *Client) PatchEndpointID(params *PatchEndpointIDParams) (*PatchEndpointI
 		ID:                 "PatchEndpointID",
 		Method:             "PATCH",
 		PathPattern:        "/endpoint/{id}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PatchEndpointIDReader{formats: a.formats},
@@ -294,8 +385,8 @@ func (a *Client) PatchEndpointIDConfig(params *PatchEndpointIDConfigParams) (*Pa
 		ID:                 "PatchEndpointIDConfig",
 		Method:             "PATCH",
 		PathPattern:        "/endpoint/{id}/config",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PatchEndpointIDConfigReader{formats: a.formats},
@@ -326,8 +417,8 @@ func (a *Client) PatchEndpointIDLabels(params *PatchEndpointIDLabelsParams) (*Pa
 		ID:                 "PatchEndpointIDLabels",
 		Method:             "PATCH",
 		PathPattern:        "/endpoint/{id}/labels",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PatchEndpointIDLabelsReader{formats: a.formats},
@@ -357,8 +448,8 @@ func (a *Client) PutEndpointID(params *PutEndpointIDParams) (*PutEndpointIDCreat
 		ID:                 "PutEndpointID",
 		Method:             "PUT",
 		PathPattern:        "/endpoint/{id}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PutEndpointIDReader{formats: a.formats},
@@ -372,6 +463,74 @@ func (a *Client) PutEndpointID(params *PutEndpointIDParams) (*PutEndpointIDCreat
 
 }
 
+/*
+PutEndpointIDDebug enables or disable debugging of endpoint
+
+Enables debug logging of the endpoint's code paths in the agent and
+the debug events of the endpoint's datapath, or disables them again.
+If a duration is given, debugging is disabled automatically once the
+duration has expired.
+
+*/
+func (a *Client) PutEndpointIDDebug(params *PutEndpointIDDebugParams) (*PutEndpointIDDebugOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPutEndpointIDDebugParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "PutEndpointIDDebug",
+		Method:             "PUT",
+		PathPattern:        "/endpoint/{id}/debug",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PutEndpointIDDebugReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*PutEndpointIDDebugOK), nil
+
+}
+
+/*
+PutEndpointIDQuarantine quarantines endpoint
+
+Isolates the endpoint by enforcing a deny-all policy for all of its
+traffic, overriding the rules of the policy repository, or lifts the
+quarantine again. The request completes when the endpoint has been
+regenerated with the new policy.
+
+*/
+func (a *Client) PutEndpointIDQuarantine(params *PutEndpointIDQuarantineParams) (*PutEndpointIDQuarantineOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewPutEndpointIDQuarantineParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "PutEndpointIDQuarantine",
+		Method:             "PUT",
+		PathPattern:        "/endpoint/{id}/quarantine",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &PutEndpointIDQuarantineReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*PutEndpointIDQuarantineOK), nil
+
+}
+
 // SetTransport changes the transport on the client
 func (a *Client) SetTransport(transport runtime.ClientTransport) {
 	a.transport = transport
@@ -32,6 +32,13 @@ func (o *PatchEndpointIDLabelsReader) ReadResponse(response runtime.ClientRespon
 		}
 		return result, nil
 
+	case 400:
+		result := NewPatchEndpointIDLabelsInvalid()
+		if err := result.readResponse(response, consumer, o.formats); err != nil {
+			return nil, err
+		}
+		return nil, result
+
 	case 404:
 		result := NewPatchEndpointIDLabelsNotFound()
 		if err := result.readResponse(response, consumer, o.formats); err != nil {
@@ -72,6 +79,33 @@ func (o *PatchEndpointIDLabelsOK) readResponse(response runtime.ClientResponse,
 	return nil
 }
 
+// NewPatchEndpointIDLabelsInvalid creates a PatchEndpointIDLabelsInvalid with default headers values
+func NewPatchEndpointIDLabelsInvalid() *PatchEndpointIDLabelsInvalid {
+	return &PatchEndpointIDLabelsInvalid{}
+}
+
+/*PatchEndpointIDLabelsInvalid handles this case with default header values.
+
+Invalid label configuration
+*/
+type PatchEndpointIDLabelsInvalid struct {
+	Payload models.Error
+}
+
+func (o *PatchEndpointIDLabelsInvalid) Error() string {
+	return fmt.Sprintf("[PATCH /endpoint/{id}/labels][%d] patchEndpointIdLabelsInvalid  %+v", 400, o.Payload)
+}
+
+func (o *PatchEndpointIDLabelsInvalid) readResponse(response runtime.ClientResponse, consumer runtime.Consumer, formats strfmt.Registry) error {
+
+	// response payload
+	if err := consumer.Consume(response.Body(), &o.Payload); err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
 // NewPatchEndpointIDLabelsNotFound creates a PatchEndpointIDLabelsNotFound with default headers values
 func NewPatchEndpointIDLabelsNotFound() *PatchEndpointIDLabelsNotFound {
 	return &PatchEndpointIDLabelsNotFound{}
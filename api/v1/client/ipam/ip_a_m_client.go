@@ -37,8 +37,8 @@ func (a *Client) DeleteIPAMIP(params *DeleteIPAMIPParams) (*DeleteIPAMIPOK, erro
 		ID:                 "DeleteIPAMIP",
 		Method:             "DELETE",
 		PathPattern:        "/ipam/{ip}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &DeleteIPAMIPReader{formats: a.formats},
@@ -65,8 +65,8 @@ func (a *Client) PostIPAM(params *PostIPAMParams) (*PostIPAMCreated, error) {
 		ID:                 "PostIPAM",
 		Method:             "POST",
 		PathPattern:        "/ipam",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PostIPAMReader{formats: a.formats},
@@ -93,8 +93,8 @@ func (a *Client) PostIPAMIP(params *PostIPAMIPParams) (*PostIPAMIPOK, error) {
 		ID:                 "PostIPAMIP",
 		Method:             "POST",
 		PathPattern:        "/ipam/{ip}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PostIPAMIPReader{formats: a.formats},
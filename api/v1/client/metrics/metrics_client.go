@@ -37,8 +37,8 @@ func (a *Client) GetMetrics(params *GetMetricsParams) (*GetMetricsOK, error) {
 		ID:                 "GetMetrics",
 		Method:             "GET",
 		PathPattern:        "/metrics/",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetMetricsReader{formats: a.formats},
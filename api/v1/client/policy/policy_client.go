@@ -24,6 +24,39 @@ type Client struct {
 	formats   strfmt.Registry
 }
 
+/*
+DeleteFqdnCache removes DNS lookups from the cache
+
+Removes the cached IPs of a DNS name, or of all DNS names if no name
+is given. Only the IPs returned by the next lookup of a name are
+retained, removing stale IPs from the generated ToCIDRSet rules.
+
+*/
+func (a *Client) DeleteFqdnCache(params *DeleteFqdnCacheParams) (*DeleteFqdnCacheOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewDeleteFqdnCacheParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "DeleteFqdnCache",
+		Method:             "DELETE",
+		PathPattern:        "/fqdn/cache",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &DeleteFqdnCacheReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*DeleteFqdnCacheOK), nil
+
+}
+
 /*
 DeletePolicy deletes a policy sub tree
 */
@@ -37,8 +70,8 @@ func (a *Client) DeletePolicy(params *DeletePolicyParams) (*DeletePolicyOK, erro
 		ID:                 "DeletePolicy",
 		Method:             "DELETE",
 		PathPattern:        "/policy",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &DeletePolicyReader{formats: a.formats},
@@ -52,6 +85,38 @@ func (a *Client) DeletePolicy(params *DeletePolicyParams) (*DeletePolicyOK, erro
 
 }
 
+/*
+GetFqdnCache retrieves the DNS lookups cached for ToFQDNs policies
+
+Returns the IPs of the DNS names polled for ToFQDNs policies together
+with the TTL of each IP and the security identity allocated for it.
+
+*/
+func (a *Client) GetFqdnCache(params *GetFqdnCacheParams) (*GetFqdnCacheOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetFqdnCacheParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetFqdnCache",
+		Method:             "GET",
+		PathPattern:        "/fqdn/cache",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetFqdnCacheReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetFqdnCacheOK), nil
+
+}
+
 /*
 GetIdentity retrieves a list of identities that have metadata matching the provided parameters
 
@@ -68,8 +133,8 @@ func (a *Client) GetIdentity(params *GetIdentityParams) (*GetIdentityOK, error)
 		ID:                 "GetIdentity",
 		Method:             "GET",
 		PathPattern:        "/identity",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetIdentityReader{formats: a.formats},
@@ -96,8 +161,8 @@ func (a *Client) GetIdentityID(params *GetIdentityIDParams) (*GetIdentityIDOK, e
 		ID:                 "GetIdentityID",
 		Method:             "GET",
 		PathPattern:        "/identity/{id}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetIdentityIDReader{formats: a.formats},
@@ -111,6 +176,71 @@ func (a *Client) GetIdentityID(params *GetIdentityIDParams) (*GetIdentityIDOK, e
 
 }
 
+/*
+GetIP lists IP identity mappings in the ipcache
+
+Returns the prefixes of all endpoints, nodes and CIDR policies known
+to the agent together with the security identity they map to.
+
+*/
+func (a *Client) GetIP(params *GetIPParams) (*GetIPOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetIPParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetIP",
+		Method:             "GET",
+		PathPattern:        "/ip",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetIPReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetIPOK), nil
+
+}
+
+/*
+GetIPAddr retrieves the identity of an IP address
+
+Returns the ipcache mapping of the longest prefix containing the
+address. The identity of the mapping is used by the datapath for
+traffic from and to the address.
+
+*/
+func (a *Client) GetIPAddr(params *GetIPAddrParams) (*GetIPAddrOK, error) {
+	// TODO: Validate the params before sending
+	if params == nil {
+		params = NewGetIPAddrParams()
+	}
+
+	result, err := a.transport.Submit(&runtime.ClientOperation{
+		ID:                 "GetIPAddr",
+		Method:             "GET",
+		PathPattern:        "/ip/{addr}",
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
+		Schemes:            []string{"http"},
+		Params:             params,
+		Reader:             &GetIPAddrReader{formats: a.formats},
+		Context:            params.Context,
+		Client:             params.HTTPClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*GetIPAddrOK), nil
+
+}
+
 /*
 GetPolicy retrieves entire policy tree
 
@@ -127,8 +257,8 @@ func (a *Client) GetPolicy(params *GetPolicyParams) (*GetPolicyOK, error) {
 		ID:                 "GetPolicy",
 		Method:             "GET",
 		PathPattern:        "/policy",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetPolicyReader{formats: a.formats},
@@ -155,8 +285,8 @@ func (a *Client) GetPolicyResolve(params *GetPolicyResolveParams) (*GetPolicyRes
 		ID:                 "GetPolicyResolve",
 		Method:             "GET",
 		PathPattern:        "/policy/resolve",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetPolicyResolveReader{formats: a.formats},
@@ -183,8 +313,8 @@ func (a *Client) PutPolicy(params *PutPolicyParams) (*PutPolicyOK, error) {
 		ID:                 "PutPolicy",
 		Method:             "PUT",
 		PathPattern:        "/policy",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PutPolicyReader{formats: a.formats},
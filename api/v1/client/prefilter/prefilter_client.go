@@ -37,8 +37,8 @@ func (a *Client) GetPrefilter(params *GetPrefilterParams) (*GetPrefilterOK, erro
 		ID:                 "GetPrefilter",
 		Method:             "GET",
 		PathPattern:        "/prefilter",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetPrefilterReader{formats: a.formats},
@@ -65,8 +65,8 @@ func (a *Client) PatchPrefilter(params *PatchPrefilterParams) (*PatchPrefilterOK
 		ID:                 "PatchPrefilter",
 		Method:             "PATCH",
 		PathPattern:        "/prefilter",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PatchPrefilterReader{formats: a.formats},
@@ -37,8 +37,8 @@ func (a *Client) DeleteServiceID(params *DeleteServiceIDParams) (*DeleteServiceI
 		ID:                 "DeleteServiceID",
 		Method:             "DELETE",
 		PathPattern:        "/service/{id}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &DeleteServiceIDReader{formats: a.formats},
@@ -65,8 +65,8 @@ func (a *Client) GetService(params *GetServiceParams) (*GetServiceOK, error) {
 		ID:                 "GetService",
 		Method:             "GET",
 		PathPattern:        "/service",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetServiceReader{formats: a.formats},
@@ -93,8 +93,8 @@ func (a *Client) GetServiceID(params *GetServiceIDParams) (*GetServiceIDOK, erro
 		ID:                 "GetServiceID",
 		Method:             "GET",
 		PathPattern:        "/service/{id}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &GetServiceIDReader{formats: a.formats},
@@ -121,8 +121,8 @@ func (a *Client) PutServiceID(params *PutServiceIDParams) (*PutServiceIDOK, *Put
 		ID:                 "PutServiceID",
 		Method:             "PUT",
 		PathPattern:        "/service/{id}",
-		ProducesMediaTypes: []string{"application/json"},
-		ConsumesMediaTypes: []string{"application/json"},
+		ProducesMediaTypes: []string{"application/json", "application/x-yaml"},
+		ConsumesMediaTypes: []string{"application/json", "application/x-yaml"},
 		Schemes:            []string{"http"},
 		Params:             params,
 		Reader:             &PutServiceIDReader{formats: a.formats},
@@ -24,12 +24,17 @@ type BPFMap struct {
 
 	// Path to BPF map
 	Path string `json:"path,omitempty"`
+
+	// pressure
+	Pressure *BPFMapPressure `json:"pressure,omitempty"`
 }
 
 /* polymorph BPFMap cache false */
 
 /* polymorph BPFMap path false */
 
+/* polymorph BPFMap pressure false */
+
 // Validate validates this b p f map
 func (m *BPFMap) Validate(formats strfmt.Registry) error {
 	var res []error
@@ -39,6 +44,11 @@ func (m *BPFMap) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validatePressure(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -72,6 +82,25 @@ func (m *BPFMap) validateCache(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *BPFMap) validatePressure(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Pressure) { // not required
+		return nil
+	}
+
+	if m.Pressure != nil {
+
+		if err := m.Pressure.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("pressure")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *BPFMap) MarshalBinary() ([]byte, error) {
 	if m == nil {
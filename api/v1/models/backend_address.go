@@ -6,6 +6,8 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"encoding/json"
+
 	strfmt "github.com/go-openapi/strfmt"
 
 	"github.com/go-openapi/errors"
@@ -25,6 +27,11 @@ type BackendAddress struct {
 	// Layer 4 port number
 	Port uint16 `json:"port,omitempty"`
 
+	// State of the backend. Draining backends do not receive new
+	// connections, established connections are not affected.
+	//
+	State string `json:"state,omitempty"`
+
 	// Weight for Round Robin
 	Weight uint16 `json:"weight,omitempty"`
 }
@@ -33,6 +40,8 @@ type BackendAddress struct {
 
 /* polymorph BackendAddress port false */
 
+/* polymorph BackendAddress state false */
+
 /* polymorph BackendAddress weight false */
 
 // Validate validates this backend address
@@ -44,6 +53,11 @@ func (m *BackendAddress) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateState(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -59,6 +73,47 @@ func (m *BackendAddress) validateIP(formats strfmt.Registry) error {
 	return nil
 }
 
+var backendAddressTypeStatePropEnum []interface{}
+
+func init() {
+	var res []string
+	if err := json.Unmarshal([]byte(`["active","draining"]`), &res); err != nil {
+		panic(err)
+	}
+	for _, v := range res {
+		backendAddressTypeStatePropEnum = append(backendAddressTypeStatePropEnum, v)
+	}
+}
+
+const (
+	// BackendAddressStateActive captures enum value "active"
+	BackendAddressStateActive string = "active"
+	// BackendAddressStateDraining captures enum value "draining"
+	BackendAddressStateDraining string = "draining"
+)
+
+// prop value enum
+func (m *BackendAddress) validateStateEnum(path, location string, value string) error {
+	if err := validate.Enum(path, location, value, backendAddressTypeStatePropEnum); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *BackendAddress) validateState(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.State) { // not required
+		return nil
+	}
+
+	// value enum
+	if err := m.validateStateEnum("state", "body", m.State); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *BackendAddress) MarshalBinary() ([]byte, error) {
 	if m == nil {
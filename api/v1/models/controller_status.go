@@ -125,6 +125,12 @@ type ControllerStatusConfiguration struct {
 	// Base error retry back-off time
 	ErrorRetryBase strfmt.Duration `json:"error-retry-base,omitempty"`
 
+	// Back-off time doubles on each consecutive error
+	ErrorRetryExponential bool `json:"error-retry-exponential,omitempty"`
+
+	// Maximum error retry back-off time
+	ErrorRetryMax strfmt.Duration `json:"error-retry-max,omitempty"`
+
 	// Regular synchronization interval
 	Interval strfmt.Duration `json:"interval,omitempty"`
 }
@@ -133,6 +139,10 @@ type ControllerStatusConfiguration struct {
 
 /* polymorph ControllerStatusConfiguration error-retry-base false */
 
+/* polymorph ControllerStatusConfiguration error-retry-exponential false */
+
+/* polymorph ControllerStatusConfiguration error-retry-max false */
+
 /* polymorph ControllerStatusConfiguration interval false */
 
 // Validate validates this controller status configuration
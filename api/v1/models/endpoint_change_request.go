@@ -6,6 +6,8 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"strconv"
+
 	strfmt "github.com/go-openapi/strfmt"
 
 	"github.com/go-openapi/errors"
@@ -18,6 +20,9 @@ import (
 
 type EndpointChangeRequest struct {
 
+	// IP4/6 addresses assigned to the endpoint in addition to addressing
+	AdditionalAddressing []*AddressPair `json:"additional-addressing"`
+
 	// addressing
 	Addressing *AddressPair `json:"addressing,omitempty"`
 
@@ -27,6 +32,9 @@ type EndpointChangeRequest struct {
 	// Name assigned to container
 	ContainerName string `json:"container-name,omitempty"`
 
+	// Container runtime managing the container
+	ContainerRuntime string `json:"container-runtime,omitempty"`
+
 	// Docker endpoint ID
 	DockerEndpointID string `json:"docker-endpoint-id,omitempty"`
 
@@ -45,6 +53,12 @@ type EndpointChangeRequest struct {
 	// Name of network device
 	InterfaceName string `json:"interface-name,omitempty"`
 
+	// Kubernetes namespace of the pod of the endpoint
+	K8sNamespace string `json:"k8s-namespace,omitempty"`
+
+	// Kubernetes pod name of the endpoint
+	K8sPodName string `json:"k8s-pod-name,omitempty"`
+
 	// Labels describing the identity
 	Labels Labels `json:"labels"`
 
@@ -63,12 +77,16 @@ type EndpointChangeRequest struct {
 	SyncBuildEndpoint bool `json:"sync-build-endpoint,omitempty"`
 }
 
+/* polymorph EndpointChangeRequest additional-addressing false */
+
 /* polymorph EndpointChangeRequest addressing false */
 
 /* polymorph EndpointChangeRequest container-id false */
 
 /* polymorph EndpointChangeRequest container-name false */
 
+/* polymorph EndpointChangeRequest container-runtime false */
+
 /* polymorph EndpointChangeRequest docker-endpoint-id false */
 
 /* polymorph EndpointChangeRequest docker-network-id false */
@@ -81,6 +99,10 @@ type EndpointChangeRequest struct {
 
 /* polymorph EndpointChangeRequest interface-name false */
 
+/* polymorph EndpointChangeRequest k8s-namespace false */
+
+/* polymorph EndpointChangeRequest k8s-pod-name false */
+
 /* polymorph EndpointChangeRequest labels false */
 
 /* polymorph EndpointChangeRequest mac false */
@@ -95,6 +117,11 @@ type EndpointChangeRequest struct {
 func (m *EndpointChangeRequest) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateAdditionalAddressing(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateAddressing(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -111,6 +138,33 @@ func (m *EndpointChangeRequest) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *EndpointChangeRequest) validateAdditionalAddressing(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.AdditionalAddressing) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.AdditionalAddressing); i++ {
+
+		if swag.IsZero(m.AdditionalAddressing[i]) { // not required
+			continue
+		}
+
+		if m.AdditionalAddressing[i] != nil {
+
+			if err := m.AdditionalAddressing[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("additional-addressing" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *EndpointChangeRequest) validateAddressing(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Addressing) { // not required
@@ -6,6 +6,8 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"strconv"
+
 	strfmt "github.com/go-openapi/strfmt"
 
 	"github.com/go-openapi/errors"
@@ -20,24 +22,49 @@ type EndpointHealth struct {
 	// bpf
 	Bpf EndpointHealthStatus `json:"bpf,omitempty"`
 
+	// Health of the BPF program and map synchronization
+	BpfSync *EndpointSubsystemHealth `json:"bpf-sync,omitempty"`
+
 	// Is this endpoint reachable
 	Connected bool `json:"connected,omitempty"`
 
+	// Health of the endpoint's network connectivity
+	Connectivity *EndpointSubsystemHealth `json:"connectivity,omitempty"`
+
 	// overall health
 	OverallHealth EndpointHealthStatus `json:"overallHealth,omitempty"`
 
 	// policy
 	Policy EndpointHealthStatus `json:"policy,omitempty"`
+
+	// Health of the policy calculation for this endpoint
+	PolicySync *EndpointSubsystemHealth `json:"policy-sync,omitempty"`
+
+	// Health of the L7 proxy redirects of the endpoint
+	ProxyRedirects []*ProxyRedirectHealth `json:"proxy-redirects"`
+
+	// Health of the L7 proxy redirect configuration
+	ProxySync *EndpointSubsystemHealth `json:"proxy-sync,omitempty"`
 }
 
 /* polymorph EndpointHealth bpf false */
 
+/* polymorph EndpointHealth bpf-sync false */
+
 /* polymorph EndpointHealth connected false */
 
+/* polymorph EndpointHealth connectivity false */
+
 /* polymorph EndpointHealth overallHealth false */
 
 /* polymorph EndpointHealth policy false */
 
+/* polymorph EndpointHealth policy-sync false */
+
+/* polymorph EndpointHealth proxy-redirects false */
+
+/* polymorph EndpointHealth proxy-sync false */
+
 // Validate validates this endpoint health
 func (m *EndpointHealth) Validate(formats strfmt.Registry) error {
 	var res []error
@@ -47,6 +74,16 @@ func (m *EndpointHealth) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateBpfSync(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateConnectivity(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateOverallHealth(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -57,6 +94,21 @@ func (m *EndpointHealth) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validatePolicySync(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateProxyRedirects(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateProxySync(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -79,6 +131,44 @@ func (m *EndpointHealth) validateBpf(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *EndpointHealth) validateBpfSync(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.BpfSync) { // not required
+		return nil
+	}
+
+	if m.BpfSync != nil {
+
+		if err := m.BpfSync.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("bpf-sync")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *EndpointHealth) validateConnectivity(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Connectivity) { // not required
+		return nil
+	}
+
+	if m.Connectivity != nil {
+
+		if err := m.Connectivity.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("connectivity")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *EndpointHealth) validateOverallHealth(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.OverallHealth) { // not required
@@ -111,6 +201,71 @@ func (m *EndpointHealth) validatePolicy(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *EndpointHealth) validatePolicySync(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.PolicySync) { // not required
+		return nil
+	}
+
+	if m.PolicySync != nil {
+
+		if err := m.PolicySync.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("policy-sync")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *EndpointHealth) validateProxyRedirects(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ProxyRedirects) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.ProxyRedirects); i++ {
+
+		if swag.IsZero(m.ProxyRedirects[i]) { // not required
+			continue
+		}
+
+		if m.ProxyRedirects[i] != nil {
+
+			if err := m.ProxyRedirects[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("proxy-redirects" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *EndpointHealth) validateProxySync(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.ProxySync) { // not required
+		return nil
+	}
+
+	if m.ProxySync != nil {
+
+		if err := m.ProxySync.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("proxy-sync")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *EndpointHealth) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -23,12 +23,21 @@ type EndpointIdentifiers struct {
 	// Name assigned to container
 	ContainerName string `json:"container-name,omitempty"`
 
+	// Container runtime managing the container
+	ContainerRuntime string `json:"container-runtime,omitempty"`
+
 	// Docker endpoint ID
 	DockerEndpointID string `json:"docker-endpoint-id,omitempty"`
 
 	// Docker network ID
 	DockerNetworkID string `json:"docker-network-id,omitempty"`
 
+	// Kubernetes namespace of the pod of the endpoint
+	K8sNamespace string `json:"k8s-namespace,omitempty"`
+
+	// Kubernetes pod name of the endpoint
+	K8sPodName string `json:"k8s-pod-name,omitempty"`
+
 	// K8s pod for this endpoint
 	PodName string `json:"pod-name,omitempty"`
 }
@@ -37,10 +46,16 @@ type EndpointIdentifiers struct {
 
 /* polymorph EndpointIdentifiers container-name false */
 
+/* polymorph EndpointIdentifiers container-runtime false */
+
 /* polymorph EndpointIdentifiers docker-endpoint-id false */
 
 /* polymorph EndpointIdentifiers docker-network-id false */
 
+/* polymorph EndpointIdentifiers k8s-namespace false */
+
+/* polymorph EndpointIdentifiers k8s-pod-name false */
+
 /* polymorph EndpointIdentifiers pod-name false */
 
 // Validate validates this endpoint identifiers
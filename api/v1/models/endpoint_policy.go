@@ -6,6 +6,8 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"strconv"
+
 	strfmt "github.com/go-openapi/strfmt"
 
 	"github.com/go-openapi/errors"
@@ -40,6 +42,9 @@ type EndpointPolicy struct {
 	// Whether policy enforcement is enabled (ingress, egress, both or none)
 	PolicyEnabled EndpointPolicyEnabled `json:"policy-enabled,omitempty"`
 
+	// Entries of the policy map of the endpoint
+	PolicyMap []*PolicyMapEntry `json:"policy-map"`
+
 	// The agent-local policy revision
 	PolicyRevision int64 `json:"policy-revision,omitempty"`
 }
@@ -58,6 +63,8 @@ type EndpointPolicy struct {
 
 /* polymorph EndpointPolicy policy-enabled false */
 
+/* polymorph EndpointPolicy policy-map false */
+
 /* polymorph EndpointPolicy policy-revision false */
 
 // Validate validates this endpoint policy
@@ -89,6 +96,11 @@ func (m *EndpointPolicy) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validatePolicyMap(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
@@ -167,6 +179,33 @@ func (m *EndpointPolicy) validatePolicyEnabled(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *EndpointPolicy) validatePolicyMap(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.PolicyMap) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.PolicyMap); i++ {
+
+		if swag.IsZero(m.PolicyMap[i]) { // not required
+			continue
+		}
+
+		if m.PolicyMap[i] != nil {
+
+			if err := m.PolicyMap[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("policy-map" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *EndpointPolicy) MarshalBinary() ([]byte, error) {
 	if m == nil {
@@ -19,6 +19,12 @@ import (
 
 type EndpointPolicyStatus struct {
 
+	// Statistics of the L7 traffic of this endpoint per port and L7 parser, only reported by GET /endpoint/{id}
+	L7Statistics []*L7Statistics `json:"l7-statistics"`
+
+	// Difference between the desired and the realized policy map entries of the endpoint, only reported by GET /endpoint/{id}
+	PolicyMapDiff *PolicyMapDiff `json:"policy-map-diff,omitempty"`
+
 	// The policy revision currently enforced in the proxy for this endpoint
 	ProxyPolicyRevision int64 `json:"proxy-policy-revision,omitempty"`
 
@@ -32,6 +38,10 @@ type EndpointPolicyStatus struct {
 	Spec *EndpointPolicy `json:"spec,omitempty"`
 }
 
+/* polymorph EndpointPolicyStatus l7-statistics false */
+
+/* polymorph EndpointPolicyStatus policy-map-diff false */
+
 /* polymorph EndpointPolicyStatus proxy-policy-revision false */
 
 /* polymorph EndpointPolicyStatus proxy-statistics false */
@@ -44,6 +54,16 @@ type EndpointPolicyStatus struct {
 func (m *EndpointPolicyStatus) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateL7Statistics(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validatePolicyMapDiff(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateProxyStatistics(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -65,6 +85,52 @@ func (m *EndpointPolicyStatus) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *EndpointPolicyStatus) validateL7Statistics(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.L7Statistics) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.L7Statistics); i++ {
+
+		if swag.IsZero(m.L7Statistics[i]) { // not required
+			continue
+		}
+
+		if m.L7Statistics[i] != nil {
+
+			if err := m.L7Statistics[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("l7-statistics" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
+func (m *EndpointPolicyStatus) validatePolicyMapDiff(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.PolicyMapDiff) { // not required
+		return nil
+	}
+
+	if m.PolicyMapDiff != nil {
+
+		if err := m.PolicyMapDiff.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("policy-map-diff")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *EndpointPolicyStatus) validateProxyStatistics(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.ProxyStatistics) { // not required
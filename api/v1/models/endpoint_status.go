@@ -20,6 +20,12 @@ type EndpointStatus struct {
 	// Status of internal controllers attached to this endpoint
 	Controllers ControllerStatuses `json:"controllers"`
 
+	// Debugging of the endpoint enabled at runtime
+	Debug *EndpointDebug `json:"debug,omitempty"`
+
+	// Current egress rate of the endpoint in bits per second while an egress bandwidth limit is configured, only reported by GET /endpoint/{id}
+	EgressRate int64 `json:"egress-rate,omitempty"`
+
 	// Unique identifiers for this endpoint from outside cilium
 	ExternalIdentifiers *EndpointIdentifiers `json:"external-identifiers,omitempty"`
 
@@ -41,6 +47,9 @@ type EndpointStatus struct {
 	// The policy applied to this endpoint from the policy repository
 	Policy *EndpointPolicyStatus `json:"policy,omitempty"`
 
+	// Quarantine of the endpoint, overriding the policy applied from the policy repository
+	Quarantine *EndpointQuarantine `json:"quarantine,omitempty"`
+
 	// The configuration in effect on this endpoint
 	Realized *EndpointConfigurationSpec `json:"realized,omitempty"`
 
@@ -51,6 +60,10 @@ type EndpointStatus struct {
 
 /* polymorph EndpointStatus controllers false */
 
+/* polymorph EndpointStatus debug false */
+
+/* polymorph EndpointStatus egress-rate false */
+
 /* polymorph EndpointStatus external-identifiers false */
 
 /* polymorph EndpointStatus health false */
@@ -65,6 +78,8 @@ type EndpointStatus struct {
 
 /* polymorph EndpointStatus policy false */
 
+/* polymorph EndpointStatus quarantine false */
+
 /* polymorph EndpointStatus realized false */
 
 /* polymorph EndpointStatus state false */
@@ -73,6 +88,11 @@ type EndpointStatus struct {
 func (m *EndpointStatus) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateDebug(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateExternalIdentifiers(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -103,6 +123,11 @@ func (m *EndpointStatus) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateQuarantine(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateRealized(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -119,6 +144,25 @@ func (m *EndpointStatus) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *EndpointStatus) validateDebug(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Debug) { // not required
+		return nil
+	}
+
+	if m.Debug != nil {
+
+		if err := m.Debug.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("debug")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *EndpointStatus) validateExternalIdentifiers(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.ExternalIdentifiers) { // not required
@@ -233,6 +277,25 @@ func (m *EndpointStatus) validatePolicy(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *EndpointStatus) validateQuarantine(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Quarantine) { // not required
+		return nil
+	}
+
+	if m.Quarantine != nil {
+
+		if err := m.Quarantine.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("quarantine")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *EndpointStatus) validateRealized(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Realized) { // not required
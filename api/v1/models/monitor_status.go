@@ -23,36 +23,70 @@ type MonitorStatus struct {
 	// Number of samples lost by perf.
 	Lost int64 `json:"lost,omitempty"`
 
+	// Number of samples lost by perf on each CPU, indexed by CPU.
+	LostPerCPU []int64 `json:"lost-per-cpu"`
+
 	// Number of pages used for the perf ring buffer.
 	Npages int64 `json:"npages,omitempty"`
 
+	// Whether the oldest samples are overwritten when the perf ring buffer is full.
+	Overwrite bool `json:"overwrite,omitempty"`
+
+	// Number of reads which found that samples had been overwritten before they were read.
+	Overwritten int64 `json:"overwritten,omitempty"`
+
 	// Pages size used for the perf ring buffer.
 	Pagesize int64 `json:"pagesize,omitempty"`
 
 	// Number of unknown samples.
 	Unknown int64 `json:"unknown,omitempty"`
+
+	// Number of samples after which the reader of the perf ring buffer is woken up.
+	WakeupEvents int64 `json:"wakeup-events,omitempty"`
 }
 
 /* polymorph MonitorStatus cpus false */
 
 /* polymorph MonitorStatus lost false */
 
+/* polymorph MonitorStatus lost-per-cpu false */
+
 /* polymorph MonitorStatus npages false */
 
+/* polymorph MonitorStatus overwrite false */
+
+/* polymorph MonitorStatus overwritten false */
+
 /* polymorph MonitorStatus pagesize false */
 
 /* polymorph MonitorStatus unknown false */
 
+/* polymorph MonitorStatus wakeup-events false */
+
 // Validate validates this monitor status
 func (m *MonitorStatus) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateLostPerCPU(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+func (m *MonitorStatus) validateLostPerCPU(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.LostPerCPU) { // not required
+		return nil
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *MonitorStatus) MarshalBinary() ([]byte, error) {
 	if m == nil {
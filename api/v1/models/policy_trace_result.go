@@ -17,27 +17,80 @@ import (
 
 type PolicyTraceResult struct {
 
+	// L4 and L7 ingress filters applying to the destination
+	L4 *L4Policy `json:"l4,omitempty"`
+
 	// log
 	Log string `json:"log,omitempty"`
 
+	// Labels of the rules of the policy repository selecting the destination for ingress
+	MatchedRules [][]string `json:"matched-rules"`
+
+	// Whether policy is enforced between the source and the destination
+	PolicyEnforced bool `json:"policy-enforced,omitempty"`
+
 	// verdict
 	Verdict string `json:"verdict,omitempty"`
 }
 
+/* polymorph PolicyTraceResult l4 false */
+
 /* polymorph PolicyTraceResult log false */
 
+/* polymorph PolicyTraceResult matched-rules false */
+
+/* polymorph PolicyTraceResult policy-enforced false */
+
 /* polymorph PolicyTraceResult verdict false */
 
 // Validate validates this policy trace result
 func (m *PolicyTraceResult) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateL4(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateMatchedRules(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if len(res) > 0 {
 		return errors.CompositeValidationError(res...)
 	}
 	return nil
 }
 
+func (m *PolicyTraceResult) validateL4(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.L4) { // not required
+		return nil
+	}
+
+	if m.L4 != nil {
+
+		if err := m.L4.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("l4")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *PolicyTraceResult) validateMatchedRules(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.MatchedRules) { // not required
+		return nil
+	}
+
+	return nil
+}
+
 // MarshalBinary interface implementation
 func (m *PolicyTraceResult) MarshalBinary() ([]byte, error) {
 	if m == nil {
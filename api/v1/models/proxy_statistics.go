@@ -23,6 +23,12 @@ type ProxyStatistics struct {
 	// The port the proxy is listening on
 	AllocatedProxyPort int64 `json:"allocated-proxy-port,omitempty"`
 
+	// Time at which the draining proxy redirect is closed
+	DrainDeadline strfmt.DateTime `json:"drain-deadline,omitempty"`
+
+	// The port of a removed proxy redirect which keeps serving existing connections
+	DrainingProxyPort int64 `json:"draining-proxy-port,omitempty"`
+
 	// Location of where the redirect is installed
 	Location string `json:"location,omitempty"`
 
@@ -38,6 +44,10 @@ type ProxyStatistics struct {
 
 /* polymorph ProxyStatistics allocated-proxy-port false */
 
+/* polymorph ProxyStatistics drain-deadline false */
+
+/* polymorph ProxyStatistics draining-proxy-port false */
+
 /* polymorph ProxyStatistics location false */
 
 /* polymorph ProxyStatistics port false */
@@ -6,6 +6,8 @@ package models
 // Editing this file might prove futile when you re-run the swagger generate command
 
 import (
+	"strconv"
+
 	strfmt "github.com/go-openapi/strfmt"
 
 	"github.com/go-openapi/errors"
@@ -17,6 +19,9 @@ import (
 
 type StatusResponse struct {
 
+	// Status of the BPF maps
+	BpfMaps *Status `json:"bpf-maps,omitempty"`
+
 	// Status of Cilium daemon
 	Cilium *Status `json:"cilium,omitempty"`
 
@@ -29,6 +34,9 @@ type StatusResponse struct {
 	// Status of all endpoint controllers
 	Controllers ControllerStatuses `json:"controllers"`
 
+	// Status of the restoration of endpoints after an agent restart
+	EndpointRestore *EndpointRestoreStatus `json:"endpoint-restore,omitempty"`
+
 	// Status of IP address management
 	IPAM *IPAMStatus `json:"ipam,omitempty"`
 
@@ -41,10 +49,18 @@ type StatusResponse struct {
 	// Status of the node monitor
 	NodeMonitor *MonitorStatus `json:"nodeMonitor,omitempty"`
 
+	// Status of the policy repository
+	Policy *PolicyStatus `json:"policy,omitempty"`
+
+	// Result of probing each subsystem, ordered by name
+	Probes []*StatusProbe `json:"probes"`
+
 	// Status of proxy
 	Proxy *ProxyStatus `json:"proxy,omitempty"`
 }
 
+/* polymorph StatusResponse bpf-maps false */
+
 /* polymorph StatusResponse cilium false */
 
 /* polymorph StatusResponse cluster false */
@@ -53,6 +69,8 @@ type StatusResponse struct {
 
 /* polymorph StatusResponse controllers false */
 
+/* polymorph StatusResponse endpoint-restore false */
+
 /* polymorph StatusResponse ipam false */
 
 /* polymorph StatusResponse kubernetes false */
@@ -61,12 +79,21 @@ type StatusResponse struct {
 
 /* polymorph StatusResponse nodeMonitor false */
 
+/* polymorph StatusResponse policy false */
+
+/* polymorph StatusResponse probes false */
+
 /* polymorph StatusResponse proxy false */
 
 // Validate validates this status response
 func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 	var res []error
 
+	if err := m.validateBpfMaps(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateCilium(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -82,6 +109,11 @@ func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validateEndpointRestore(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateIPAM(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -102,6 +134,16 @@ func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 		res = append(res, err)
 	}
 
+	if err := m.validatePolicy(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
+	if err := m.validateProbes(formats); err != nil {
+		// prop
+		res = append(res, err)
+	}
+
 	if err := m.validateProxy(formats); err != nil {
 		// prop
 		res = append(res, err)
@@ -113,6 +155,25 @@ func (m *StatusResponse) Validate(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *StatusResponse) validateBpfMaps(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.BpfMaps) { // not required
+		return nil
+	}
+
+	if m.BpfMaps != nil {
+
+		if err := m.BpfMaps.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("bpf-maps")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) validateCilium(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Cilium) { // not required
@@ -170,6 +231,25 @@ func (m *StatusResponse) validateContainerRuntime(formats strfmt.Registry) error
 	return nil
 }
 
+func (m *StatusResponse) validateEndpointRestore(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.EndpointRestore) { // not required
+		return nil
+	}
+
+	if m.EndpointRestore != nil {
+
+		if err := m.EndpointRestore.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("endpoint-restore")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) validateIPAM(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.IPAM) { // not required
@@ -246,6 +326,52 @@ func (m *StatusResponse) validateNodeMonitor(formats strfmt.Registry) error {
 	return nil
 }
 
+func (m *StatusResponse) validatePolicy(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Policy) { // not required
+		return nil
+	}
+
+	if m.Policy != nil {
+
+		if err := m.Policy.Validate(formats); err != nil {
+			if ve, ok := err.(*errors.Validation); ok {
+				return ve.ValidateName("policy")
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *StatusResponse) validateProbes(formats strfmt.Registry) error {
+
+	if swag.IsZero(m.Probes) { // not required
+		return nil
+	}
+
+	for i := 0; i < len(m.Probes); i++ {
+
+		if swag.IsZero(m.Probes[i]) { // not required
+			continue
+		}
+
+		if m.Probes[i] != nil {
+
+			if err := m.Probes[i].Validate(formats); err != nil {
+				if ve, ok := err.(*errors.Validation); ok {
+					return ve.ValidateName("probes" + "." + strconv.Itoa(i))
+				}
+				return err
+			}
+		}
+
+	}
+
+	return nil
+}
+
 func (m *StatusResponse) validateProxy(formats strfmt.Registry) error {
 
 	if swag.IsZero(m.Proxy) { // not required
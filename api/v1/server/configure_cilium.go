@@ -8,10 +8,11 @@ import (
 
 	errors "github.com/go-openapi/errors"
 	runtime "github.com/go-openapi/runtime"
+	middleware "github.com/go-openapi/runtime/middleware"
 	graceful "github.com/tylerb/graceful"
 
 	"github.com/cilium/cilium/api/v1/server/restapi"
-	"github.com/cilium/cilium/pkg/api"
+	ciliumapi "github.com/cilium/cilium/pkg/api"
 	"github.com/cilium/cilium/pkg/metrics"
 )
 
@@ -37,9 +38,15 @@ func configureAPI(api *restapi.CiliumAPI) http.Handler {
 
 	api.JSONProducer = runtime.JSONProducer()
 
+	api.YamlConsumer = ciliumapi.YAMLConsumer()
+
+	api.YamlProducer = ciliumapi.YAMLProducer()
+
 	api.ServerShutdown = func() {}
 
-	return setupGlobalMiddleware(api.Serve(setupMiddlewares))
+	return setupGlobalMiddleware(api.Serve(func(handler http.Handler) http.Handler {
+		return setupMiddlewares(api.Context(), handler)
+	}))
 }
 
 // The TLS configuration before HTTPS server starts.
@@ -56,8 +63,11 @@ func configureServer(s *graceful.Server, scheme, addr string) {
 
 // The middleware configuration is for the handler executors. These do not apply to the swagger.json document.
 // The middleware executes after routing but before authentication, binding and validation
-func setupMiddlewares(handler http.Handler) http.Handler {
-	return handler
+func setupMiddlewares(ctx *middleware.Context, handler http.Handler) http.Handler {
+	return &ciliumapi.APIResponseHandler{
+		Next:    handler,
+		Context: ctx,
+	}
 }
 
 // The middleware configuration happens before anything, this middleware also applies to serving the swagger.json document.
@@ -68,7 +78,7 @@ func setupGlobalMiddleware(handler http.Handler) http.Handler {
 		TSGauge: metrics.EventTSAPI,
 	}
 
-	return &api.APIPanicHandler{
+	return &ciliumapi.APIPanicHandler{
 		Next: eventsHelper,
 	}
 }
@@ -15,10 +15,12 @@ var SwaggerJSON json.RawMessage
 func init() {
 	SwaggerJSON = json.RawMessage([]byte(`{
   "consumes": [
-    "application/json"
+    "application/json",
+    "application/x-yaml"
   ],
   "produces": [
-    "application/json"
+    "application/json",
+    "application/x-yaml"
   ],
   "swagger": "2.0",
   "info": {
@@ -28,6 +30,30 @@ func init() {
   },
   "basePath": "/v1",
   "paths": {
+    "/capabilities": {
+      "get": {
+        "description": "Returns the kernel features detected by the agent, the enabled\nsubsystems, the sizes of the BPF maps and the version of the API.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Retrieve the capabilities of the agent and the node",
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/Capabilities"
+            }
+          },
+          "500": {
+            "description": "Capabilities get failed",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failure"
+          }
+        }
+      }
+    },
     "/config": {
       "get": {
         "description": "Returns the configuration of the Cilium daemon.\n",
@@ -129,6 +155,41 @@ func init() {
             "description": "Endpoints with provided parameters not found"
           }
         }
+      },
+      "patch": {
+        "description": "Applies label modifications to all endpoints matched by the selector\nof each operation. All operations are validated before any endpoint\nis modified. The result of each modified endpoint is returned, an\nendpoint which could not be modified does not prevent the\nmodification of the remaining endpoints.\n",
+        "tags": [
+          "endpoint"
+        ],
+        "summary": "Modify labels of multiple endpoints",
+        "parameters": [
+          {
+            "name": "batch",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/EndpointLabelsBatch"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/EndpointLabelsResult"
+              }
+            }
+          },
+          "400": {
+            "description": "Invalid label operation",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Invalid"
+          }
+        }
       }
     },
     "/endpoint/{id}": {
@@ -337,6 +398,75 @@ func init() {
         }
       }
     },
+    "/endpoint/{id}/controllers": {
+      "get": {
+        "tags": [
+          "endpoint"
+        ],
+        "summary": "Retrieves the status of the controllers of this endpoint.",
+        "parameters": [
+          {
+            "$ref": "#/parameters/endpoint-id"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/ControllerStatuses"
+            }
+          },
+          "400": {
+            "description": "Invalid identity provided",
+            "x-go-name": "Invalid"
+          },
+          "404": {
+            "description": "Endpoint not found"
+          }
+        }
+      }
+    },
+    "/endpoint/{id}/debug": {
+      "put": {
+        "description": "Enables debug logging of the endpoint's code paths in the agent and\nthe debug events of the endpoint's datapath, or disables them again.\nIf a duration is given, debugging is disabled automatically once the\nduration has expired.\n",
+        "tags": [
+          "endpoint"
+        ],
+        "summary": "Enable or disable debugging of endpoint",
+        "parameters": [
+          {
+            "$ref": "#/parameters/endpoint-id"
+          },
+          {
+            "name": "debug",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/EndpointDebug"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          },
+          "400": {
+            "description": "Invalid debug request",
+            "x-go-name": "Invalid"
+          },
+          "404": {
+            "description": "Endpoint not found"
+          },
+          "500": {
+            "description": "Update of debugging failed. Details in message.",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failed"
+          }
+        }
+      }
+    },
     "/endpoint/{id}/healthz": {
       "get": {
         "tags": [
@@ -365,6 +495,37 @@ func init() {
         }
       }
     },
+    "/endpoint/{id}/l7stats": {
+      "get": {
+        "tags": [
+          "endpoint"
+        ],
+        "summary": "Retrieves the L7 statistics of the endpoint per port and L7 parser",
+        "parameters": [
+          {
+            "$ref": "#/parameters/endpoint-id"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/L7Statistics"
+              }
+            }
+          },
+          "400": {
+            "description": "Invalid identity provided",
+            "x-go-name": "Invalid"
+          },
+          "404": {
+            "description": "Endpoint not found"
+          }
+        }
+      }
+    },
     "/endpoint/{id}/labels": {
       "get": {
         "tags": [
@@ -411,6 +572,13 @@ func init() {
           "200": {
             "description": "Success"
           },
+          "400": {
+            "description": "Invalid label configuration",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Invalid"
+          },
           "404": {
             "description": "Endpoint not found"
           },
@@ -452,6 +620,102 @@ func init() {
         }
       }
     },
+    "/endpoint/{id}/quarantine": {
+      "put": {
+        "description": "Isolates the endpoint by enforcing a deny-all policy for all of its\ntraffic, overriding the rules of the policy repository, or lifts the\nquarantine again. The request completes when the endpoint has been\nregenerated with the new policy.\n",
+        "tags": [
+          "endpoint"
+        ],
+        "summary": "Quarantine endpoint",
+        "parameters": [
+          {
+            "$ref": "#/parameters/endpoint-id"
+          },
+          {
+            "name": "quarantine",
+            "in": "body",
+            "required": true,
+            "schema": {
+              "$ref": "#/definitions/EndpointQuarantine"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          },
+          "400": {
+            "description": "Invalid quarantine request",
+            "x-go-name": "Invalid"
+          },
+          "404": {
+            "description": "Endpoint not found"
+          },
+          "500": {
+            "description": "Quarantine failed. Details in message.",
+            "schema": {
+              "$ref": "#/definitions/Error"
+            },
+            "x-go-name": "Failed"
+          }
+        }
+      }
+    },
+    "/fqdn/cache": {
+      "get": {
+        "description": "Returns the IPs of the DNS names polled for ToFQDNs policies together\nwith the TTL of each IP and the security identity allocated for it.\n",
+        "tags": [
+          "policy"
+        ],
+        "summary": "Retrieve the DNS lookups cached for ToFQDNs policies",
+        "parameters": [
+          {
+            "$ref": "#/parameters/fqdn-matchname"
+          },
+          {
+            "type": "string",
+            "description": "Only return the DNS names of ToFQDNs rules selecting this endpoint.\nSee the endpoint id parameter for the supported formats.\n",
+            "name": "endpoint",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/DNSLookup"
+              }
+            }
+          },
+          "400": {
+            "description": "Invalid endpoint ID",
+            "x-go-name": "Invalid"
+          },
+          "404": {
+            "description": "Endpoint not found"
+          }
+        }
+      },
+      "delete": {
+        "description": "Removes the cached IPs of a DNS name, or of all DNS names if no name\nis given. Only the IPs returned by the next lookup of a name are\nretained, removing stale IPs from the generated ToCIDRSet rules.\n",
+        "tags": [
+          "policy"
+        ],
+        "summary": "Remove DNS lookups from the cache",
+        "parameters": [
+          {
+            "$ref": "#/parameters/fqdn-matchname"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success"
+          }
+        }
+      }
+    },
     "/healthz": {
       "get": {
         "description": "Returns health and status information of the Cilium daemon and related\ncomponents such as the local container runtime, connected datastore,\nKubernetes integration.\n",
@@ -552,6 +816,71 @@ func init() {
         }
       }
     },
+    "/ip": {
+      "get": {
+        "description": "Returns the prefixes of all endpoints, nodes and CIDR policies known\nto the agent together with the security identity they map to.\n",
+        "tags": [
+          "policy"
+        ],
+        "summary": "List IP-identity mappings in the ipcache",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "Only return the mappings of prefixes within this CIDR range",
+            "name": "cidr",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/IPListEntry"
+              }
+            }
+          },
+          "400": {
+            "description": "Invalid CIDR range",
+            "x-go-name": "Invalid"
+          }
+        }
+      }
+    },
+    "/ip/{addr}": {
+      "get": {
+        "description": "Returns the ipcache mapping of the longest prefix containing the\naddress. The identity of the mapping is used by the datapath for\ntraffic from and to the address.\n",
+        "tags": [
+          "policy"
+        ],
+        "summary": "Retrieve the identity of an IP address",
+        "parameters": [
+          {
+            "type": "string",
+            "description": "IPv4 or IPv6 address",
+            "name": "addr",
+            "in": "path",
+            "required": true
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "$ref": "#/definitions/IPListEntry"
+            }
+          },
+          "400": {
+            "description": "Invalid IP address",
+            "x-go-name": "Invalid"
+          },
+          "404": {
+            "description": "No mapping found for the address"
+          }
+        }
+      }
+    },
     "/ipam": {
       "post": {
         "tags": [
@@ -691,6 +1020,40 @@ func init() {
         }
       }
     },
+    "/map/{name}/events": {
+      "get": {
+        "description": "Returns the most recent updates and deletions of entries of the BPF\nmap performed by the agent, including failed ones, in the order in\nwhich they were performed.\n",
+        "tags": [
+          "daemon"
+        ],
+        "summary": "Retrieve the recent mutations of a BPF map",
+        "parameters": [
+          {
+            "$ref": "#/parameters/map-name"
+          },
+          {
+            "type": "integer",
+            "description": "Only return events with a sequence number larger than this",
+            "name": "since",
+            "in": "query"
+          }
+        ],
+        "responses": {
+          "200": {
+            "description": "Success",
+            "schema": {
+              "type": "array",
+              "items": {
+                "$ref": "#/definitions/BPFMapEvent"
+              }
+            }
+          },
+          "404": {
+            "description": "Map not found"
+          }
+        }
+      }
+    },
     "/metrics/": {
       "get": {
         "tags": [
@@ -1045,6 +1408,9 @@ func init() {
         "path": {
           "description": "Path to BPF map",
           "type": "string"
+        },
+        "pressure": {
+          "$ref": "#/definitions/BPFMapPressure"
         }
       }
     },
@@ -1075,6 +1441,46 @@ func init() {
         }
       }
     },
+    "BPFMapEvent": {
+      "description": "Mutation of a BPF map performed by the agent",
+      "type": "object",
+      "properties": {
+        "action": {
+          "description": "Type of the mutation",
+          "type": "string",
+          "enum": [
+            "update",
+            "delete",
+            "delete-all"
+          ]
+        },
+        "caller": {
+          "description": "Function of the agent which performed the mutation",
+          "type": "string"
+        },
+        "error": {
+          "description": "Error returned by the kernel, empty on success",
+          "type": "string"
+        },
+        "key": {
+          "description": "Key of the map entry",
+          "type": "string"
+        },
+        "seq": {
+          "description": "Sequence number of the event, increasing for each event of the map",
+          "type": "integer"
+        },
+        "timestamp": {
+          "description": "Time of the mutation",
+          "type": "string",
+          "format": "date-time"
+        },
+        "value": {
+          "description": "Value of the map entry",
+          "type": "string"
+        }
+      }
+    },
     "BPFMapList": {
       "description": "List of BPF Maps",
       "type": "object",
@@ -1088,6 +1494,24 @@ func init() {
         }
       }
     },
+    "BPFMapPressure": {
+      "description": "Fill ratio of a BPF map as of the last sample",
+      "type": "object",
+      "properties": {
+        "entries": {
+          "description": "Number of entries in the map",
+          "type": "integer"
+        },
+        "max-entries": {
+          "description": "Maximum number of entries of the map",
+          "type": "integer"
+        },
+        "ratio": {
+          "description": "Ratio of the number of entries to the maximum number of entries",
+          "type": "number"
+        }
+      }
+    },
     "BackendAddress": {
       "description": "Service backend address",
       "type": "object",
@@ -1104,6 +1528,14 @@ func init() {
           "type": "integer",
           "format": "uint16"
         },
+        "state": {
+          "description": "State of the backend. Draining backends do not receive new\nconnections, established connections are not affected.\n",
+          "type": "string",
+          "enum": [
+            "active",
+            "draining"
+          ]
+        },
         "weight": {
           "description": "Weight for Round Robin",
           "type": "integer",
@@ -1146,6 +1578,45 @@ func init() {
         }
       }
     },
+    "Capabilities": {
+      "description": "Capabilities of the agent and the node it is running on",
+      "type": "object",
+      "properties": {
+        "api-version": {
+          "description": "Version of the API served by the agent",
+          "type": "string"
+        },
+        "cilium-version": {
+          "description": "Version of the agent",
+          "type": "string"
+        },
+        "kernel-features": {
+          "description": "Kernel features probed by the agent and whether they are supported",
+          "type": "object",
+          "additionalProperties": {
+            "type": "boolean"
+          }
+        },
+        "kernel-version": {
+          "description": "Version of the running kernel",
+          "type": "string"
+        },
+        "map-sizes": {
+          "description": "Maximum number of entries of each BPF map opened by the agent",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer"
+          }
+        },
+        "subsystems": {
+          "description": "Subsystems of the agent and whether they are enabled",
+          "type": "object",
+          "additionalProperties": {
+            "type": "boolean"
+          }
+        }
+      }
+    },
     "ClusterStatus": {
       "description": "Status of cluster",
       "properties": {
@@ -1190,6 +1661,15 @@ func init() {
               "type": "string",
               "format": "duration"
             },
+            "error-retry-exponential": {
+              "description": "Back-off time doubles on each consecutive error",
+              "type": "boolean"
+            },
+            "error-retry-max": {
+              "description": "Maximum error retry back-off time",
+              "type": "string",
+              "format": "duration"
+            },
             "interval": {
               "description": "Regular synchronization interval",
               "type": "string",
@@ -1247,6 +1727,38 @@ func init() {
         "$ref": "#/definitions/ControllerStatus"
       }
     },
+    "DNSLookup": {
+      "description": "An IP of a DNS name cached for ToFQDNs policies",
+      "type": "object",
+      "properties": {
+        "expiration-time": {
+          "description": "Time at which the IP expires unless it is returned by a later lookup",
+          "type": "string",
+          "format": "date-time"
+        },
+        "fqdn": {
+          "description": "Fully qualified DNS name",
+          "type": "string"
+        },
+        "identity": {
+          "description": "Security identity allocated for the IP, 0 if none",
+          "type": "integer"
+        },
+        "ip": {
+          "description": "IP returned by the DNS lookup",
+          "type": "string"
+        },
+        "lookup-time": {
+          "description": "Time of the DNS lookup",
+          "type": "string",
+          "format": "date-time"
+        },
+        "ttl": {
+          "description": "TTL of the IP in seconds",
+          "type": "integer"
+        }
+      }
+    },
     "DaemonConfiguration": {
       "description": "Response to a daemon configuration request.\n",
       "type": "object",
@@ -1385,6 +1897,13 @@ func init() {
         "state"
       ],
       "properties": {
+        "additional-addressing": {
+          "description": "IP4/6 addresses assigned to the endpoint in addition to addressing",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/AddressPair"
+          }
+        },
         "addressing": {
           "$ref": "#/definitions/AddressPair"
         },
@@ -1396,6 +1915,10 @@ func init() {
           "description": "Name assigned to container",
           "type": "string"
         },
+        "container-runtime": {
+          "description": "Container runtime managing the container",
+          "type": "string"
+        },
         "docker-endpoint-id": {
           "description": "Docker endpoint ID",
           "type": "string"
@@ -1420,6 +1943,14 @@ func init() {
           "description": "Name of network device",
           "type": "string"
         },
+        "k8s-namespace": {
+          "description": "Kubernetes namespace of the pod of the endpoint",
+          "type": "string"
+        },
+        "k8s-pod-name": {
+          "description": "Kubernetes pod name of the endpoint",
+          "type": "string"
+        },
         "labels": {
           "description": "Labels describing the identity",
           "$ref": "#/definitions/Labels"
@@ -1474,6 +2005,26 @@ func init() {
         }
       }
     },
+    "EndpointDebug": {
+      "description": "Debugging of an endpoint",
+      "type": "object",
+      "properties": {
+        "duration": {
+          "description": "Duration after which debugging is disabled again, unlimited if not set. Only used to enable debugging, the expiration is reported instead",
+          "type": "string",
+          "format": "duration"
+        },
+        "enabled": {
+          "description": "True if debugging of the endpoint is enabled",
+          "type": "boolean"
+        },
+        "expiration": {
+          "description": "Time at which debugging is disabled again",
+          "type": "string",
+          "format": "date-time"
+        }
+      }
+    },
     "EndpointHealth": {
       "description": "Health of the endpoint",
       "type": "object",
@@ -1481,15 +2032,38 @@ func init() {
         "bpf": {
           "$ref": "#/definitions/EndpointHealthStatus"
         },
+        "bpf-sync": {
+          "description": "Health of the BPF program and map synchronization",
+          "$ref": "#/definitions/EndpointSubsystemHealth"
+        },
         "connected": {
           "description": "Is this endpoint reachable",
           "type": "boolean"
         },
+        "connectivity": {
+          "description": "Health of the endpoint's network connectivity",
+          "$ref": "#/definitions/EndpointSubsystemHealth"
+        },
         "overallHealth": {
           "$ref": "#/definitions/EndpointHealthStatus"
         },
         "policy": {
           "$ref": "#/definitions/EndpointHealthStatus"
+        },
+        "policy-sync": {
+          "description": "Health of the policy calculation for this endpoint",
+          "$ref": "#/definitions/EndpointSubsystemHealth"
+        },
+        "proxy-redirects": {
+          "description": "Health of the L7 proxy redirects of the endpoint",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/ProxyRedirectHealth"
+          }
+        },
+        "proxy-sync": {
+          "description": "Health of the L7 proxy redirect configuration",
+          "$ref": "#/definitions/EndpointSubsystemHealth"
         }
       }
     },
@@ -1505,29 +2079,90 @@ func init() {
         "Disabled"
       ]
     },
-    "EndpointIdentifiers": {
-      "description": "Unique identifiers for this endpoint from outside cilium",
+    "EndpointIdentifiers": {
+      "description": "Unique identifiers for this endpoint from outside cilium",
+      "type": "object",
+      "properties": {
+        "container-id": {
+          "description": "ID assigned by container runtime",
+          "type": "string"
+        },
+        "container-name": {
+          "description": "Name assigned to container",
+          "type": "string"
+        },
+        "container-runtime": {
+          "description": "Container runtime managing the container",
+          "type": "string"
+        },
+        "docker-endpoint-id": {
+          "description": "Docker endpoint ID",
+          "type": "string"
+        },
+        "docker-network-id": {
+          "description": "Docker network ID",
+          "type": "string"
+        },
+        "k8s-namespace": {
+          "description": "Kubernetes namespace of the pod of the endpoint",
+          "type": "string"
+        },
+        "k8s-pod-name": {
+          "description": "Kubernetes pod name of the endpoint",
+          "type": "string"
+        },
+        "pod-name": {
+          "description": "K8s pod for this endpoint",
+          "type": "string"
+        }
+      }
+    },
+    "EndpointLabelsBatch": {
+      "description": "Label modifications of multiple endpoints",
+      "type": "object",
+      "properties": {
+        "operations": {
+          "description": "Operations applied in order",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/EndpointLabelsOperation"
+          }
+        }
+      }
+    },
+    "EndpointLabelsOperation": {
+      "description": "Label modification of all endpoints matching a selector",
       "type": "object",
       "properties": {
-        "container-id": {
-          "description": "ID assigned by container runtime",
-          "type": "string"
+        "add": {
+          "description": "Labels to add to the matched endpoints",
+          "$ref": "#/definitions/Labels"
         },
-        "container-name": {
-          "description": "Name assigned to container",
-          "type": "string"
+        "delete": {
+          "description": "Labels to delete from the matched endpoints",
+          "$ref": "#/definitions/Labels"
         },
-        "docker-endpoint-id": {
-          "description": "Docker endpoint ID",
+        "selector": {
+          "description": "Labels which an endpoint must all have to be modified",
+          "$ref": "#/definitions/Labels"
+        }
+      }
+    },
+    "EndpointLabelsResult": {
+      "description": "Result of modifying the labels of an endpoint",
+      "type": "object",
+      "properties": {
+        "error": {
+          "description": "Reason the modification failed, empty on success",
           "type": "string"
         },
-        "docker-network-id": {
-          "description": "Docker network ID",
-          "type": "string"
+        "id": {
+          "description": "ID of the endpoint",
+          "type": "integer"
         },
-        "pod-name": {
-          "description": "K8s pod for this endpoint",
-          "type": "string"
+        "operation": {
+          "description": "Index of the operation which matched the endpoint",
+          "type": "integer"
         }
       }
     },
@@ -1599,6 +2234,13 @@ func init() {
           "description": "Whether policy enforcement is enabled (ingress, egress, both or none)",
           "$ref": "#/definitions/EndpointPolicyEnabled"
         },
+        "policy-map": {
+          "description": "Entries of the policy map of the endpoint",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/PolicyMapEntry"
+          }
+        },
         "policy-revision": {
           "description": "The agent-local policy revision",
           "type": "integer"
@@ -1619,6 +2261,17 @@ func init() {
       "description": "Policy information of an endpoint",
       "type": "object",
       "properties": {
+        "l7-statistics": {
+          "description": "Statistics of the L7 traffic of this endpoint per port and L7 parser, only reported by GET /endpoint/{id}",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/L7Statistics"
+          }
+        },
+        "policy-map-diff": {
+          "description": "Difference between the desired and the realized policy map entries of the endpoint, only reported by GET /endpoint/{id}",
+          "$ref": "#/definitions/PolicyMapDiff"
+        },
         "proxy-policy-revision": {
           "description": "The policy revision currently enforced in the proxy for this endpoint",
           "type": "integer"
@@ -1640,6 +2293,50 @@ func init() {
         }
       }
     },
+    "EndpointQuarantine": {
+      "description": "Quarantine of an endpoint. All traffic of a quarantined endpoint is denied regardless of the policy repository.",
+      "type": "object",
+      "properties": {
+        "enabled": {
+          "description": "True if the endpoint is quarantined",
+          "type": "boolean"
+        },
+        "reason": {
+          "description": "Reason for quarantining the endpoint",
+          "type": "string"
+        }
+      }
+    },
+    "EndpointRestoreStatus": {
+      "description": "Progress of the restoration of endpoints after an agent restart",
+      "type": "object",
+      "properties": {
+        "deferred": {
+          "description": "Number of restored endpoints deferred until all active endpoints have been regenerated",
+          "type": "integer"
+        },
+        "failed": {
+          "description": "Number of restored endpoints which failed to regenerate",
+          "type": "integer"
+        },
+        "regenerated": {
+          "description": "Number of restored endpoints which have been regenerated",
+          "type": "integer"
+        },
+        "state": {
+          "description": "State of the restoration",
+          "type": "string",
+          "enum": [
+            "restoring",
+            "complete"
+          ]
+        },
+        "total": {
+          "description": "Number of endpoints being restored",
+          "type": "integer"
+        }
+      }
+    },
     "EndpointState": {
       "description": "State of endpoint",
       "type": "string",
@@ -1666,6 +2363,14 @@ func init() {
           "description": "Status of internal controllers attached to this endpoint",
           "$ref": "#/definitions/ControllerStatuses"
         },
+        "debug": {
+          "description": "Debugging of the endpoint enabled at runtime",
+          "$ref": "#/definitions/EndpointDebug"
+        },
+        "egress-rate": {
+          "description": "Current egress rate of the endpoint in bits per second while an egress bandwidth limit is configured, only reported by GET /endpoint/{id}",
+          "type": "integer"
+        },
         "external-identifiers": {
           "description": "Unique identifiers for this endpoint from outside cilium",
           "$ref": "#/definitions/EndpointIdentifiers"
@@ -1694,6 +2399,10 @@ func init() {
           "description": "The policy applied to this endpoint from the policy repository",
           "$ref": "#/definitions/EndpointPolicyStatus"
         },
+        "quarantine": {
+          "description": "Quarantine of the endpoint, overriding the policy applied from the policy repository",
+          "$ref": "#/definitions/EndpointQuarantine"
+        },
         "realized": {
           "description": "The configuration in effect on this endpoint",
           "$ref": "#/definitions/EndpointConfigurationSpec"
@@ -1736,6 +2445,25 @@ func init() {
         "$ref": "#/definitions/EndpointStatusChange"
       }
     },
+    "EndpointSubsystemHealth": {
+      "description": "Health of a single subsystem of an endpoint",
+      "type": "object",
+      "properties": {
+        "cause": {
+          "description": "Human readable cause of a non-OK status",
+          "type": "string"
+        },
+        "status": {
+          "description": "Health status of the subsystem",
+          "type": "string",
+          "enum": [
+            "OK",
+            "Degraded",
+            "Failure"
+          ]
+        }
+      }
+    },
     "Error": {
       "type": "string"
     },
@@ -1796,6 +2524,24 @@ func init() {
         }
       }
     },
+    "IPListEntry": {
+      "description": "Mapping of an IP prefix to a security identity",
+      "type": "object",
+      "properties": {
+        "cidr": {
+          "description": "Prefix of the mapping in CIDR notation",
+          "type": "string"
+        },
+        "host-ip": {
+          "description": "IP of the node hosting the endpoint of the prefix, if any",
+          "type": "string"
+        },
+        "identity": {
+          "description": "Security identity of the prefix",
+          "type": "integer"
+        }
+      }
+    },
     "Identity": {
       "description": "Security identity",
       "type": "object",
@@ -1876,6 +2622,47 @@ func init() {
         }
       }
     },
+    "L7Statistics": {
+      "description": "Statistics of the L7 traffic of an endpoint on a port subject to an L7 parser",
+      "type": "object",
+      "properties": {
+        "denied": {
+          "description": "Number of requests denied by policy",
+          "type": "integer"
+        },
+        "errors": {
+          "description": "Number of errors by error class",
+          "type": "object",
+          "additionalProperties": {
+            "type": "integer"
+          }
+        },
+        "latency": {
+          "description": "Latency between requests and their responses",
+          "$ref": "#/definitions/LatencyPercentiles"
+        },
+        "location": {
+          "description": "Location of the L7 filter",
+          "type": "string",
+          "enum": [
+            "ingress",
+            "egress"
+          ]
+        },
+        "parser": {
+          "description": "Name of the L7 parser",
+          "type": "string"
+        },
+        "port": {
+          "description": "The port subject to the L7 filter",
+          "type": "integer"
+        },
+        "requests": {
+          "description": "Number of requests received",
+          "type": "integer"
+        }
+      }
+    },
     "LabelConfiguration": {
       "description": "Label configuration of an endpoint",
       "type": "object",
@@ -1929,6 +2716,28 @@ func init() {
         "type": "string"
       }
     },
+    "LatencyPercentiles": {
+      "description": "Percentiles of a latency in microseconds over the most recent samples",
+      "type": "object",
+      "properties": {
+        "p50": {
+          "description": "50th percentile in microseconds",
+          "type": "integer"
+        },
+        "p90": {
+          "description": "90th percentile in microseconds",
+          "type": "integer"
+        },
+        "p99": {
+          "description": "99th percentile in microseconds",
+          "type": "integer"
+        },
+        "samples": {
+          "description": "Number of samples the percentiles are computed from",
+          "type": "integer"
+        }
+      }
+    },
     "MessageForwardingStatistics": {
       "description": "Statistics of a message forwarding entity",
       "type": "object",
@@ -1983,10 +2792,25 @@ func init() {
           "description": "Number of samples lost by perf.",
           "type": "integer"
         },
+        "lost-per-cpu": {
+          "description": "Number of samples lost by perf on each CPU, indexed by CPU.",
+          "type": "array",
+          "items": {
+            "type": "integer"
+          }
+        },
         "npages": {
           "description": "Number of pages used for the perf ring buffer.",
           "type": "integer"
         },
+        "overwrite": {
+          "description": "Whether the oldest samples are overwritten when the perf ring buffer is full.",
+          "type": "boolean"
+        },
+        "overwritten": {
+          "description": "Number of reads which found that samples had been overwritten before they were read.",
+          "type": "integer"
+        },
         "pagesize": {
           "description": "Pages size used for the perf ring buffer.",
           "type": "integer"
@@ -1994,6 +2818,10 @@ func init() {
         "unknown": {
           "description": "Number of unknown samples.",
           "type": "integer"
+        },
+        "wakeup-events": {
+          "description": "Number of samples after which the reader of the perf ring buffer is woken up.",
+          "type": "integer"
         }
       }
     },
@@ -2069,6 +2897,60 @@ func init() {
         }
       }
     },
+    "PolicyMapDiff": {
+      "description": "Difference between the desired and the realized policy map entries of an endpoint",
+      "type": "object",
+      "properties": {
+        "missing": {
+          "description": "Desired entries which are not installed in the policy map",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/PolicyMapEntry"
+          }
+        },
+        "unexpected": {
+          "description": "Entries installed in the policy map which are not desired",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/PolicyMapEntry"
+          }
+        }
+      }
+    },
+    "PolicyMapEntry": {
+      "description": "Entry of the policy map of an endpoint",
+      "type": "object",
+      "properties": {
+        "bytes": {
+          "description": "Number of bytes which matched the entry in the datapath, only reported for realized entries by GET /endpoint/{id}",
+          "type": "integer"
+        },
+        "direction": {
+          "description": "Direction of the traffic the entry applies to (Ingress or Egress)",
+          "type": "string"
+        },
+        "identity": {
+          "description": "Numeric identity of the peer",
+          "type": "integer"
+        },
+        "packets": {
+          "description": "Number of packets which matched the entry in the datapath, only reported for realized entries by GET /endpoint/{id}",
+          "type": "integer"
+        },
+        "port": {
+          "description": "Destination port, 0 for all ports",
+          "type": "integer"
+        },
+        "protocol": {
+          "description": "Protocol of the traffic",
+          "type": "string"
+        },
+        "proxy-port": {
+          "description": "Port of the proxy the traffic is redirected to, 0 if not redirected",
+          "type": "integer"
+        }
+      }
+    },
     "PolicyRule": {
       "description": "A policy rule including the rule labels it derives from",
       "properties": {
@@ -2088,13 +2970,41 @@ func init() {
         }
       }
     },
+    "PolicyStatus": {
+      "description": "Status of the policy repository",
+      "type": "object",
+      "properties": {
+        "revision": {
+          "description": "Current revision of the policy repository",
+          "type": "integer"
+        }
+      }
+    },
     "PolicyTraceResult": {
       "description": "Response to a policy resolution process",
       "type": "object",
       "properties": {
+        "l4": {
+          "description": "L4 and L7 ingress filters applying to the destination",
+          "$ref": "#/definitions/L4Policy"
+        },
         "log": {
           "type": "string"
         },
+        "matched-rules": {
+          "description": "Labels of the rules of the policy repository selecting the destination for ingress",
+          "type": "array",
+          "items": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          }
+        },
+        "policy-enforced": {
+          "description": "Whether policy is enforced between the source and the destination",
+          "type": "boolean"
+        },
         "verdict": {
           "type": "string"
         }
@@ -2156,6 +3066,41 @@ func init() {
         }
       }
     },
+    "ProxyRedirectHealth": {
+      "description": "Health of an L7 proxy redirect of an endpoint",
+      "type": "object",
+      "properties": {
+        "id": {
+          "description": "Identifier of the redirect",
+          "type": "string"
+        },
+        "last-error": {
+          "description": "The last error of the redirect",
+          "type": "string"
+        },
+        "last-error-time": {
+          "description": "Time at which the last error occurred",
+          "type": "string",
+          "format": "date-time"
+        },
+        "listener-bound": {
+          "description": "Whether the proxy is listening on the proxy port",
+          "type": "boolean"
+        },
+        "protocol": {
+          "description": "Name of the L7 protocol",
+          "type": "string"
+        },
+        "proxy-port": {
+          "description": "The port the proxy is listening on",
+          "type": "integer"
+        },
+        "rules-loaded": {
+          "description": "Whether the L7 rules of the redirect have been loaded into the proxy",
+          "type": "boolean"
+        }
+      }
+    },
     "ProxyStatistics": {
       "description": "Statistics of a set of proxy redirects for an endpoint",
       "type": "object",
@@ -2164,6 +3109,15 @@ func init() {
           "description": "The port the proxy is listening on",
           "type": "integer"
         },
+        "drain-deadline": {
+          "description": "Time at which the draining proxy redirect is closed",
+          "type": "string",
+          "format": "date-time"
+        },
+        "draining-proxy-port": {
+          "description": "The port of a removed proxy redirect which keeps serving existing connections",
+          "type": "integer"
+        },
         "location": {
           "description": "Location of where the redirect is installed",
           "type": "string",
@@ -2291,10 +3245,38 @@ func init() {
         }
       }
     },
+    "StatusProbe": {
+      "description": "Result of probing an individual subsystem",
+      "type": "object",
+      "properties": {
+        "msg": {
+          "description": "Human readable status/error/warning message",
+          "type": "string"
+        },
+        "name": {
+          "description": "Name of the subsystem",
+          "type": "string"
+        },
+        "state": {
+          "description": "Severity of the subsystem state",
+          "type": "string",
+          "enum": [
+            "Ok",
+            "Warning",
+            "Failure",
+            "Disabled"
+          ]
+        }
+      }
+    },
     "StatusResponse": {
       "description": "Health and status information of daemon",
       "type": "object",
       "properties": {
+        "bpf-maps": {
+          "description": "Status of the BPF maps",
+          "$ref": "#/definitions/Status"
+        },
         "cilium": {
           "description": "Status of Cilium daemon",
           "$ref": "#/definitions/Status"
@@ -2311,6 +3293,10 @@ func init() {
           "description": "Status of all endpoint controllers",
           "$ref": "#/definitions/ControllerStatuses"
         },
+        "endpoint-restore": {
+          "description": "Status of the restoration of endpoints after an agent restart",
+          "$ref": "#/definitions/EndpointRestoreStatus"
+        },
         "ipam": {
           "description": "Status of IP address management",
           "$ref": "#/definitions/IPAMStatus"
@@ -2327,6 +3313,17 @@ func init() {
           "description": "Status of the node monitor",
           "$ref": "#/definitions/MonitorStatus"
         },
+        "policy": {
+          "description": "Status of the policy repository",
+          "$ref": "#/definitions/PolicyStatus"
+        },
+        "probes": {
+          "description": "Result of probing each subsystem, ordered by name",
+          "type": "array",
+          "items": {
+            "$ref": "#/definitions/StatusProbe"
+          }
+        },
         "proxy": {
           "description": "Status of proxy",
           "$ref": "#/definitions/ProxyStatus"
@@ -2389,6 +3386,12 @@ func init() {
       "in": "path",
       "required": true
     },
+    "fqdn-matchname": {
+      "type": "string",
+      "description": "DNS name to match, all names if not given",
+      "name": "matchname",
+      "in": "query"
+    },
     "identity-id": {
       "type": "string",
       "description": "Cluster wide unique identifier of a security identity.\n",
@@ -46,6 +46,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		EndpointDeleteEndpointIDHandler: endpoint.DeleteEndpointIDHandlerFunc(func(params endpoint.DeleteEndpointIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointDeleteEndpointID has not yet been implemented")
 		}),
+		PolicyDeleteFqdnCacheHandler: policy.DeleteFqdnCacheHandlerFunc(func(params policy.DeleteFqdnCacheParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyDeleteFqdnCache has not yet been implemented")
+		}),
 		IPAMDeleteIPAMIPHandler: ipam.DeleteIPAMIPHandlerFunc(func(params ipam.DeleteIPAMIPParams) middleware.Responder {
 			return middleware.NotImplemented("operation IPAMDeleteIPAMIP has not yet been implemented")
 		}),
@@ -55,6 +58,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		ServiceDeleteServiceIDHandler: service.DeleteServiceIDHandlerFunc(func(params service.DeleteServiceIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation ServiceDeleteServiceID has not yet been implemented")
 		}),
+		DaemonGetCapabilitiesHandler: daemon.GetCapabilitiesHandlerFunc(func(params daemon.GetCapabilitiesParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetCapabilities has not yet been implemented")
+		}),
 		DaemonGetConfigHandler: daemon.GetConfigHandlerFunc(func(params daemon.GetConfigParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetConfig has not yet been implemented")
 		}),
@@ -70,15 +76,24 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		EndpointGetEndpointIDConfigHandler: endpoint.GetEndpointIDConfigHandlerFunc(func(params endpoint.GetEndpointIDConfigParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointGetEndpointIDConfig has not yet been implemented")
 		}),
+		EndpointGetEndpointIDControllersHandler: endpoint.GetEndpointIDControllersHandlerFunc(func(params endpoint.GetEndpointIDControllersParams) middleware.Responder {
+			return middleware.NotImplemented("operation EndpointGetEndpointIDControllers has not yet been implemented")
+		}),
 		EndpointGetEndpointIDHealthzHandler: endpoint.GetEndpointIDHealthzHandlerFunc(func(params endpoint.GetEndpointIDHealthzParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointGetEndpointIDHealthz has not yet been implemented")
 		}),
+		EndpointGetEndpointIDL7statsHandler: endpoint.GetEndpointIDL7statsHandlerFunc(func(params endpoint.GetEndpointIDL7statsParams) middleware.Responder {
+			return middleware.NotImplemented("operation EndpointGetEndpointIDL7stats has not yet been implemented")
+		}),
 		EndpointGetEndpointIDLabelsHandler: endpoint.GetEndpointIDLabelsHandlerFunc(func(params endpoint.GetEndpointIDLabelsParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointGetEndpointIDLabels has not yet been implemented")
 		}),
 		EndpointGetEndpointIDLogHandler: endpoint.GetEndpointIDLogHandlerFunc(func(params endpoint.GetEndpointIDLogParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointGetEndpointIDLog has not yet been implemented")
 		}),
+		PolicyGetFqdnCacheHandler: policy.GetFqdnCacheHandlerFunc(func(params policy.GetFqdnCacheParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyGetFqdnCache has not yet been implemented")
+		}),
 		DaemonGetHealthzHandler: daemon.GetHealthzHandlerFunc(func(params daemon.GetHealthzParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetHealthz has not yet been implemented")
 		}),
@@ -88,12 +103,21 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		PolicyGetIdentityIDHandler: policy.GetIdentityIDHandlerFunc(func(params policy.GetIdentityIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyGetIdentityID has not yet been implemented")
 		}),
+		PolicyGetIPHandler: policy.GetIPHandlerFunc(func(params policy.GetIPParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyGetIP has not yet been implemented")
+		}),
+		PolicyGetIPAddrHandler: policy.GetIPAddrHandlerFunc(func(params policy.GetIPAddrParams) middleware.Responder {
+			return middleware.NotImplemented("operation PolicyGetIPAddr has not yet been implemented")
+		}),
 		DaemonGetMapHandler: daemon.GetMapHandlerFunc(func(params daemon.GetMapParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetMap has not yet been implemented")
 		}),
 		DaemonGetMapNameHandler: daemon.GetMapNameHandlerFunc(func(params daemon.GetMapNameParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonGetMapName has not yet been implemented")
 		}),
+		DaemonGetMapNameEventsHandler: daemon.GetMapNameEventsHandlerFunc(func(params daemon.GetMapNameEventsParams) middleware.Responder {
+			return middleware.NotImplemented("operation DaemonGetMapNameEvents has not yet been implemented")
+		}),
 		MetricsGetMetricsHandler: metrics.GetMetricsHandlerFunc(func(params metrics.GetMetricsParams) middleware.Responder {
 			return middleware.NotImplemented("operation MetricsGetMetrics has not yet been implemented")
 		}),
@@ -115,6 +139,9 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		DaemonPatchConfigHandler: daemon.PatchConfigHandlerFunc(func(params daemon.PatchConfigParams) middleware.Responder {
 			return middleware.NotImplemented("operation DaemonPatchConfig has not yet been implemented")
 		}),
+		EndpointPatchEndpointHandler: endpoint.PatchEndpointHandlerFunc(func(params endpoint.PatchEndpointParams) middleware.Responder {
+			return middleware.NotImplemented("operation EndpointPatchEndpoint has not yet been implemented")
+		}),
 		EndpointPatchEndpointIDHandler: endpoint.PatchEndpointIDHandlerFunc(func(params endpoint.PatchEndpointIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointPatchEndpointID has not yet been implemented")
 		}),
@@ -136,6 +163,12 @@ func NewCiliumAPI(spec *loads.Document) *CiliumAPI {
 		EndpointPutEndpointIDHandler: endpoint.PutEndpointIDHandlerFunc(func(params endpoint.PutEndpointIDParams) middleware.Responder {
 			return middleware.NotImplemented("operation EndpointPutEndpointID has not yet been implemented")
 		}),
+		EndpointPutEndpointIDDebugHandler: endpoint.PutEndpointIDDebugHandlerFunc(func(params endpoint.PutEndpointIDDebugParams) middleware.Responder {
+			return middleware.NotImplemented("operation EndpointPutEndpointIDDebug has not yet been implemented")
+		}),
+		EndpointPutEndpointIDQuarantineHandler: endpoint.PutEndpointIDQuarantineHandlerFunc(func(params endpoint.PutEndpointIDQuarantineParams) middleware.Responder {
+			return middleware.NotImplemented("operation EndpointPutEndpointIDQuarantine has not yet been implemented")
+		}),
 		PolicyPutPolicyHandler: policy.PutPolicyHandlerFunc(func(params policy.PutPolicyParams) middleware.Responder {
 			return middleware.NotImplemented("operation PolicyPutPolicy has not yet been implemented")
 		}),
@@ -171,14 +204,24 @@ type CiliumAPI struct {
 	// JSONProducer registers a producer for a "application/json" mime type
 	JSONProducer runtime.Producer
 
+	// YamlConsumer registers a consumer for a "application/x-yaml" mime type
+	YamlConsumer runtime.Consumer
+
+	// YamlProducer registers a producer for a "application/x-yaml" mime type
+	YamlProducer runtime.Producer
+
 	// EndpointDeleteEndpointIDHandler sets the operation handler for the delete endpoint ID operation
 	EndpointDeleteEndpointIDHandler endpoint.DeleteEndpointIDHandler
+	// PolicyDeleteFqdnCacheHandler sets the operation handler for the delete fqdn cache operation
+	PolicyDeleteFqdnCacheHandler policy.DeleteFqdnCacheHandler
 	// IPAMDeleteIPAMIPHandler sets the operation handler for the delete IP a m IP operation
 	IPAMDeleteIPAMIPHandler ipam.DeleteIPAMIPHandler
 	// PolicyDeletePolicyHandler sets the operation handler for the delete policy operation
 	PolicyDeletePolicyHandler policy.DeletePolicyHandler
 	// ServiceDeleteServiceIDHandler sets the operation handler for the delete service ID operation
 	ServiceDeleteServiceIDHandler service.DeleteServiceIDHandler
+	// DaemonGetCapabilitiesHandler sets the operation handler for the get capabilities operation
+	DaemonGetCapabilitiesHandler daemon.GetCapabilitiesHandler
 	// DaemonGetConfigHandler sets the operation handler for the get config operation
 	DaemonGetConfigHandler daemon.GetConfigHandler
 	// DaemonGetDebuginfoHandler sets the operation handler for the get debuginfo operation
@@ -189,22 +232,34 @@ type CiliumAPI struct {
 	EndpointGetEndpointIDHandler endpoint.GetEndpointIDHandler
 	// EndpointGetEndpointIDConfigHandler sets the operation handler for the get endpoint ID config operation
 	EndpointGetEndpointIDConfigHandler endpoint.GetEndpointIDConfigHandler
+	// EndpointGetEndpointIDControllersHandler sets the operation handler for the get endpoint ID controllers operation
+	EndpointGetEndpointIDControllersHandler endpoint.GetEndpointIDControllersHandler
 	// EndpointGetEndpointIDHealthzHandler sets the operation handler for the get endpoint ID healthz operation
 	EndpointGetEndpointIDHealthzHandler endpoint.GetEndpointIDHealthzHandler
+	// EndpointGetEndpointIDL7statsHandler sets the operation handler for the get endpoint ID l7stats operation
+	EndpointGetEndpointIDL7statsHandler endpoint.GetEndpointIDL7statsHandler
 	// EndpointGetEndpointIDLabelsHandler sets the operation handler for the get endpoint ID labels operation
 	EndpointGetEndpointIDLabelsHandler endpoint.GetEndpointIDLabelsHandler
 	// EndpointGetEndpointIDLogHandler sets the operation handler for the get endpoint ID log operation
 	EndpointGetEndpointIDLogHandler endpoint.GetEndpointIDLogHandler
+	// PolicyGetFqdnCacheHandler sets the operation handler for the get fqdn cache operation
+	PolicyGetFqdnCacheHandler policy.GetFqdnCacheHandler
 	// DaemonGetHealthzHandler sets the operation handler for the get healthz operation
 	DaemonGetHealthzHandler daemon.GetHealthzHandler
 	// PolicyGetIdentityHandler sets the operation handler for the get identity operation
 	PolicyGetIdentityHandler policy.GetIdentityHandler
 	// PolicyGetIdentityIDHandler sets the operation handler for the get identity ID operation
 	PolicyGetIdentityIDHandler policy.GetIdentityIDHandler
+	// PolicyGetIPHandler sets the operation handler for the get IP operation
+	PolicyGetIPHandler policy.GetIPHandler
+	// PolicyGetIPAddrHandler sets the operation handler for the get IP addr operation
+	PolicyGetIPAddrHandler policy.GetIPAddrHandler
 	// DaemonGetMapHandler sets the operation handler for the get map operation
 	DaemonGetMapHandler daemon.GetMapHandler
 	// DaemonGetMapNameHandler sets the operation handler for the get map name operation
 	DaemonGetMapNameHandler daemon.GetMapNameHandler
+	// DaemonGetMapNameEventsHandler sets the operation handler for the get map name events operation
+	DaemonGetMapNameEventsHandler daemon.GetMapNameEventsHandler
 	// MetricsGetMetricsHandler sets the operation handler for the get metrics operation
 	MetricsGetMetricsHandler metrics.GetMetricsHandler
 	// PolicyGetPolicyHandler sets the operation handler for the get policy operation
@@ -219,6 +274,8 @@ type CiliumAPI struct {
 	ServiceGetServiceIDHandler service.GetServiceIDHandler
 	// DaemonPatchConfigHandler sets the operation handler for the patch config operation
 	DaemonPatchConfigHandler daemon.PatchConfigHandler
+	// EndpointPatchEndpointHandler sets the operation handler for the patch endpoint operation
+	EndpointPatchEndpointHandler endpoint.PatchEndpointHandler
 	// EndpointPatchEndpointIDHandler sets the operation handler for the patch endpoint ID operation
 	EndpointPatchEndpointIDHandler endpoint.PatchEndpointIDHandler
 	// EndpointPatchEndpointIDConfigHandler sets the operation handler for the patch endpoint ID config operation
@@ -233,6 +290,10 @@ type CiliumAPI struct {
 	IPAMPostIPAMIPHandler ipam.PostIPAMIPHandler
 	// EndpointPutEndpointIDHandler sets the operation handler for the put endpoint ID operation
 	EndpointPutEndpointIDHandler endpoint.PutEndpointIDHandler
+	// EndpointPutEndpointIDDebugHandler sets the operation handler for the put endpoint ID debug operation
+	EndpointPutEndpointIDDebugHandler endpoint.PutEndpointIDDebugHandler
+	// EndpointPutEndpointIDQuarantineHandler sets the operation handler for the put endpoint ID quarantine operation
+	EndpointPutEndpointIDQuarantineHandler endpoint.PutEndpointIDQuarantineHandler
 	// PolicyPutPolicyHandler sets the operation handler for the put policy operation
 	PolicyPutPolicyHandler policy.PutPolicyHandler
 	// ServicePutServiceIDHandler sets the operation handler for the put service ID operation
@@ -300,10 +361,22 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "JSONProducer")
 	}
 
+	if o.YamlConsumer == nil {
+		unregistered = append(unregistered, "YamlConsumer")
+	}
+
+	if o.YamlProducer == nil {
+		unregistered = append(unregistered, "YamlProducer")
+	}
+
 	if o.EndpointDeleteEndpointIDHandler == nil {
 		unregistered = append(unregistered, "endpoint.DeleteEndpointIDHandler")
 	}
 
+	if o.PolicyDeleteFqdnCacheHandler == nil {
+		unregistered = append(unregistered, "policy.DeleteFqdnCacheHandler")
+	}
+
 	if o.IPAMDeleteIPAMIPHandler == nil {
 		unregistered = append(unregistered, "ipam.DeleteIPAMIPHandler")
 	}
@@ -316,6 +389,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "service.DeleteServiceIDHandler")
 	}
 
+	if o.DaemonGetCapabilitiesHandler == nil {
+		unregistered = append(unregistered, "daemon.GetCapabilitiesHandler")
+	}
+
 	if o.DaemonGetConfigHandler == nil {
 		unregistered = append(unregistered, "daemon.GetConfigHandler")
 	}
@@ -336,10 +413,18 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "endpoint.GetEndpointIDConfigHandler")
 	}
 
+	if o.EndpointGetEndpointIDControllersHandler == nil {
+		unregistered = append(unregistered, "endpoint.GetEndpointIDControllersHandler")
+	}
+
 	if o.EndpointGetEndpointIDHealthzHandler == nil {
 		unregistered = append(unregistered, "endpoint.GetEndpointIDHealthzHandler")
 	}
 
+	if o.EndpointGetEndpointIDL7statsHandler == nil {
+		unregistered = append(unregistered, "endpoint.GetEndpointIDL7statsHandler")
+	}
+
 	if o.EndpointGetEndpointIDLabelsHandler == nil {
 		unregistered = append(unregistered, "endpoint.GetEndpointIDLabelsHandler")
 	}
@@ -348,6 +433,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "endpoint.GetEndpointIDLogHandler")
 	}
 
+	if o.PolicyGetFqdnCacheHandler == nil {
+		unregistered = append(unregistered, "policy.GetFqdnCacheHandler")
+	}
+
 	if o.DaemonGetHealthzHandler == nil {
 		unregistered = append(unregistered, "daemon.GetHealthzHandler")
 	}
@@ -360,6 +449,14 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "policy.GetIdentityIDHandler")
 	}
 
+	if o.PolicyGetIPHandler == nil {
+		unregistered = append(unregistered, "policy.GetIPHandler")
+	}
+
+	if o.PolicyGetIPAddrHandler == nil {
+		unregistered = append(unregistered, "policy.GetIPAddrHandler")
+	}
+
 	if o.DaemonGetMapHandler == nil {
 		unregistered = append(unregistered, "daemon.GetMapHandler")
 	}
@@ -368,6 +465,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "daemon.GetMapNameHandler")
 	}
 
+	if o.DaemonGetMapNameEventsHandler == nil {
+		unregistered = append(unregistered, "daemon.GetMapNameEventsHandler")
+	}
+
 	if o.MetricsGetMetricsHandler == nil {
 		unregistered = append(unregistered, "metrics.GetMetricsHandler")
 	}
@@ -396,6 +497,10 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "daemon.PatchConfigHandler")
 	}
 
+	if o.EndpointPatchEndpointHandler == nil {
+		unregistered = append(unregistered, "endpoint.PatchEndpointHandler")
+	}
+
 	if o.EndpointPatchEndpointIDHandler == nil {
 		unregistered = append(unregistered, "endpoint.PatchEndpointIDHandler")
 	}
@@ -424,6 +529,14 @@ func (o *CiliumAPI) Validate() error {
 		unregistered = append(unregistered, "endpoint.PutEndpointIDHandler")
 	}
 
+	if o.EndpointPutEndpointIDDebugHandler == nil {
+		unregistered = append(unregistered, "endpoint.PutEndpointIDDebugHandler")
+	}
+
+	if o.EndpointPutEndpointIDQuarantineHandler == nil {
+		unregistered = append(unregistered, "endpoint.PutEndpointIDQuarantineHandler")
+	}
+
 	if o.PolicyPutPolicyHandler == nil {
 		unregistered = append(unregistered, "policy.PutPolicyHandler")
 	}
@@ -468,6 +581,9 @@ func (o *CiliumAPI) ConsumersFor(mediaTypes []string) map[string]runtime.Consume
 		case "application/json":
 			result["application/json"] = o.JSONConsumer
 
+		case "application/x-yaml":
+			result["application/x-yaml"] = o.YamlConsumer
+
 		}
 	}
 	return result
@@ -484,6 +600,9 @@ func (o *CiliumAPI) ProducersFor(mediaTypes []string) map[string]runtime.Produce
 		case "application/json":
 			result["application/json"] = o.JSONProducer
 
+		case "application/x-yaml":
+			result["application/x-yaml"] = o.YamlProducer
+
 		}
 	}
 	return result
@@ -527,6 +646,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["DELETE"]["/endpoint/{id}"] = endpoint.NewDeleteEndpointID(o.context, o.EndpointDeleteEndpointIDHandler)
 
+	if o.handlers["DELETE"] == nil {
+		o.handlers["DELETE"] = make(map[string]http.Handler)
+	}
+	o.handlers["DELETE"]["/fqdn/cache"] = policy.NewDeleteFqdnCache(o.context, o.PolicyDeleteFqdnCacheHandler)
+
 	if o.handlers["DELETE"] == nil {
 		o.handlers["DELETE"] = make(map[string]http.Handler)
 	}
@@ -542,6 +666,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["DELETE"]["/service/{id}"] = service.NewDeleteServiceID(o.context, o.ServiceDeleteServiceIDHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/capabilities"] = daemon.NewGetCapabilities(o.context, o.DaemonGetCapabilitiesHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -567,11 +696,21 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/endpoint/{id}/config"] = endpoint.NewGetEndpointIDConfig(o.context, o.EndpointGetEndpointIDConfigHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/endpoint/{id}/controllers"] = endpoint.NewGetEndpointIDControllers(o.context, o.EndpointGetEndpointIDControllersHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
 	o.handlers["GET"]["/endpoint/{id}/healthz"] = endpoint.NewGetEndpointIDHealthz(o.context, o.EndpointGetEndpointIDHealthzHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/endpoint/{id}/l7stats"] = endpoint.NewGetEndpointIDL7stats(o.context, o.EndpointGetEndpointIDL7statsHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -582,6 +721,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/endpoint/{id}/log"] = endpoint.NewGetEndpointIDLog(o.context, o.EndpointGetEndpointIDLogHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/fqdn/cache"] = policy.NewGetFqdnCache(o.context, o.PolicyGetFqdnCacheHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -597,6 +741,16 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/identity/{id}"] = policy.NewGetIdentityID(o.context, o.PolicyGetIdentityIDHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/ip"] = policy.NewGetIP(o.context, o.PolicyGetIPHandler)
+
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/ip/{addr}"] = policy.NewGetIPAddr(o.context, o.PolicyGetIPAddrHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -607,6 +761,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["GET"]["/map/{name}"] = daemon.NewGetMapName(o.context, o.DaemonGetMapNameHandler)
 
+	if o.handlers["GET"] == nil {
+		o.handlers["GET"] = make(map[string]http.Handler)
+	}
+	o.handlers["GET"]["/map/{name}/events"] = daemon.NewGetMapNameEvents(o.context, o.DaemonGetMapNameEventsHandler)
+
 	if o.handlers["GET"] == nil {
 		o.handlers["GET"] = make(map[string]http.Handler)
 	}
@@ -642,6 +801,11 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["PATCH"]["/config"] = daemon.NewPatchConfig(o.context, o.DaemonPatchConfigHandler)
 
+	if o.handlers["PATCH"] == nil {
+		o.handlers["PATCH"] = make(map[string]http.Handler)
+	}
+	o.handlers["PATCH"]["/endpoint"] = endpoint.NewPatchEndpoint(o.context, o.EndpointPatchEndpointHandler)
+
 	if o.handlers["PATCH"] == nil {
 		o.handlers["PATCH"] = make(map[string]http.Handler)
 	}
@@ -677,6 +841,16 @@ func (o *CiliumAPI) initHandlerCache() {
 	}
 	o.handlers["PUT"]["/endpoint/{id}"] = endpoint.NewPutEndpointID(o.context, o.EndpointPutEndpointIDHandler)
 
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/endpoint/{id}/debug"] = endpoint.NewPutEndpointIDDebug(o.context, o.EndpointPutEndpointIDDebugHandler)
+
+	if o.handlers["PUT"] == nil {
+		o.handlers["PUT"] = make(map[string]http.Handler)
+	}
+	o.handlers["PUT"]["/endpoint/{id}/quarantine"] = endpoint.NewPutEndpointIDQuarantine(o.context, o.EndpointPutEndpointIDQuarantineHandler)
+
 	if o.handlers["PUT"] == nil {
 		o.handlers["PUT"] = make(map[string]http.Handler)
 	}
@@ -34,6 +34,48 @@ func (o *PatchEndpointIDLabelsOK) WriteResponse(rw http.ResponseWriter, producer
 	rw.WriteHeader(200)
 }
 
+// PatchEndpointIDLabelsInvalidCode is the HTTP code returned for type PatchEndpointIDLabelsInvalid
+const PatchEndpointIDLabelsInvalidCode int = 400
+
+/*PatchEndpointIDLabelsInvalid Invalid label configuration
+
+swagger:response patchEndpointIdLabelsInvalid
+*/
+type PatchEndpointIDLabelsInvalid struct {
+
+	/*
+	  In: Body
+	*/
+	Payload models.Error `json:"body,omitempty"`
+}
+
+// NewPatchEndpointIDLabelsInvalid creates PatchEndpointIDLabelsInvalid with default headers values
+func NewPatchEndpointIDLabelsInvalid() *PatchEndpointIDLabelsInvalid {
+	return &PatchEndpointIDLabelsInvalid{}
+}
+
+// WithPayload adds the payload to the patch endpoint Id labels invalid response
+func (o *PatchEndpointIDLabelsInvalid) WithPayload(payload models.Error) *PatchEndpointIDLabelsInvalid {
+	o.Payload = payload
+	return o
+}
+
+// SetPayload sets the payload to the patch endpoint Id labels invalid response
+func (o *PatchEndpointIDLabelsInvalid) SetPayload(payload models.Error) {
+	o.Payload = payload
+}
+
+// WriteResponse to the client
+func (o *PatchEndpointIDLabelsInvalid) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+
+	rw.WriteHeader(400)
+	payload := o.Payload
+	if err := producer.Produce(rw, payload); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+
+}
+
 // PatchEndpointIDLabelsNotFoundCode is the HTTP code returned for type PatchEndpointIDLabelsNotFound
 const PatchEndpointIDLabelsNotFoundCode int = 404
 
@@ -47,6 +47,8 @@
 #include "lib/csum.h"
 #include "lib/conntrack.h"
 #include "lib/encap.h"
+#include "lib/throttle.h"
+#include "lib/mirror.h"
 
 #define POLICY_ID ((LXC_ID << 16) | SECLABEL)
 
@@ -228,6 +230,8 @@ skip_service_lookup:
 		return verdict;
 	}
 
+	mirror_packet(skb, tuple->nexthdr, tuple->sport, tuple->dport);
+
 	switch (ret) {
 	case CT_NEW:
 		/* New connection implies that rev_nat_index remains untouched
@@ -534,6 +538,8 @@ skip_service_lookup:
 		return verdict;
 	}
 
+	mirror_packet(skb, tuple.nexthdr, tuple.sport, tuple.dport);
+
 	switch (ret) {
 	case CT_NEW:
 		/* New connection implies that rev_nat_index remains untouched
@@ -599,6 +605,14 @@ skip_service_lookup:
 
 	orig_dip = ip4->daddr;
 
+#ifdef ENABLE_PROXY_TRANSPARENT
+	/* Replies to a connection which the ingress proxy has made from the
+	 * original source address of the client must be delivered to the
+	 * transparent socket of the proxy via the stack of the host. */
+	if (forwarding_reason == CT_REPLY && ct_state.proxy_redirect)
+		goto to_host;
+#endif
+
 	/* Lookup IPv4 address, this will return a match if:
 	 *  - The destination IP address belongs to a local endpoint managed by
 	 *    cilium
@@ -642,6 +656,29 @@ skip_service_lookup:
 		if (ret != DROP_NO_TUNNEL_ENDPOINT)
 			return ret;
 	}
+#endif
+#ifdef ENABLE_NODE_NEIGH
+	/* In direct routing mode, packets to endpoints on other nodes are
+	 * forwarded to the native device directly if the agent has resolved
+	 * the next hop towards the node. Otherwise the stack routes them. */
+	if (tunnel_endpoint) {
+		struct node_neigh_entry *neigh;
+
+		neigh = map_lookup_elem(&cilium_node_neigh4, &tunnel_endpoint);
+		if (neigh) {
+			union macaddr dev_mac = NATIVE_DEV_MAC;
+
+			ret = ipv4_l3(skb, l3_off, (__u8 *) &dev_mac.addr, neigh->mac, ip4);
+			if (ret != TC_ACT_OK)
+				return ret;
+
+			send_trace_notify(skb, TRACE_TO_STACK, SECLABEL, *dstID, 0,
+					  NATIVE_DEV_IFINDEX, forwarding_reason, monitor);
+
+			cilium_dbg_capture(skb, DBG_CAPTURE_DELIVERY, NATIVE_DEV_IFINDEX);
+			return redirect(NATIVE_DEV_IFINDEX, 0);
+		}
+	}
 #endif
 	goto pass_to_stack;
 
@@ -715,6 +752,11 @@ int handle_ingress(struct __sk_buff *skb)
 	send_trace_notify(skb, TRACE_FROM_LXC, SECLABEL, 0, 0, 0, 0,
 			  TRACE_PAYLOAD_LEN);
 
+	ret = throttle_egress(skb);
+	if (IS_ERR(ret))
+		return send_drop_notify(skb, SECLABEL, 0, 0, 0, ret, TC_ACT_SHOT,
+					METRIC_EGRESS);
+
 	switch (skb->protocol) {
 	case bpf_htons(ETH_P_IPV6):
 		ep_tail_call(skb, CILIUM_CALL_IPV6_FROM_LXC);
@@ -826,6 +868,8 @@ ipv6_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 		return DROP_POLICY;
 	}
 
+	mirror_packet(skb, tuple.nexthdr, tuple.sport, tuple.dport);
+
 	if (skip_proxy)
 		verdict = 0;
 
@@ -960,12 +1004,17 @@ ipv4_policy(struct __sk_buff *skb, int ifindex, __u32 src_label, int *forwarding
 		return DROP_POLICY;
 	}
 
+	mirror_packet(skb, tuple.nexthdr, tuple.sport, tuple.dport);
+
 	if (skip_proxy)
 		verdict = 0;
 
 	if (ret == CT_NEW) {
 		ct_state_new.orig_dport = tuple.dport;
 		ct_state_new.src_sec_id = src_label;
+#ifdef ENABLE_PROXY_TRANSPARENT
+		ct_state_new.proxy_redirect = redirect_to_proxy(verdict, ret);
+#endif
 		ret = ct_create4(get_ct_map4(&tuple), &tuple, skb, CT_INGRESS, &ct_state_new);
 		if (IS_ERR(ret))
 			return ret;
@@ -1027,7 +1076,14 @@ __section_tail(CILIUM_MAP_CALLS, CILIUM_CALL_IPV4_TO_LXC) int tail_ipv4_policy(s
  * passed into the endpoint or if it needs further inspection by a userspace
  * proxy.
  */
-__section_tail(CILIUM_MAP_POLICY, LXC_ID) int handle_policy(struct __sk_buff *skb)
+#ifdef TEMPLATE_LXC_ID
+/* The section name of template objects is renamed when the template is
+ * instantiated for an endpoint, see bpf/include/bpf/static_data.h */
+__section_tail(CILIUM_MAP_POLICY, TEMPLATE_LXC_ID)
+#else
+__section_tail(CILIUM_MAP_POLICY, LXC_ID)
+#endif
+int handle_policy(struct __sk_buff *skb)
 {
 	int ret, ifindex = skb->cb[CB_IFINDEX];
 	__u32 src_label = skb->cb[CB_SRC_LABEL];
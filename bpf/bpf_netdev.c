@@ -46,6 +46,7 @@
 #include "lib/policy.h"
 #include "lib/drop.h"
 #include "lib/encap.h"
+#include "lib/nat.h"
 
 static inline __u32 derive_sec_ctx(struct __sk_buff *skb, const union v6addr *node_ip,
 				   struct ipv6hdr *ip6)
@@ -362,6 +363,19 @@ static inline int handle_ipv4(struct __sk_buff *skb, __u32 src_identity)
 	if (!revalidate_data(skb, &data, &data_end, &ip4))
 		return DROP_INVALID;
 
+#if defined ENABLE_MASQUERADE && !defined FROM_HOST
+	{
+		/* Translate replies to masqueraded connections back to the
+		 * endpoint before looking up the destination. */
+		int ret = snat_v4_ingress(skb);
+		if (IS_ERR(ret))
+			return ret;
+
+		if (!revalidate_data(skb, &data, &data_end, &ip4))
+			return DROP_INVALID;
+	}
+#endif
+
 	l4_off = ETH_HLEN + ipv4_hdrlen(ip4);
 	secctx = derive_ipv4_sec_ctx(skb, ip4);
 	tuple.nexthdr = ip4->protocol;
@@ -514,4 +528,28 @@ int from_netdev(struct __sk_buff *skb)
 	return ret;
 }
 
+#if defined ENABLE_MASQUERADE && !defined FROM_HOST
+__section("to-netdev")
+int to_netdev(struct __sk_buff *skb)
+{
+	int ret = TC_ACT_OK;
+
+	switch (skb->protocol) {
+#ifdef ENABLE_IPV4
+	case bpf_htons(ETH_P_IP):
+		ret = snat_v4_egress(skb);
+		break;
+#endif
+
+	default:
+		break;
+	}
+
+	if (IS_ERR(ret))
+		return send_drop_notify_error(skb, ret, TC_ACT_SHOT, METRIC_EGRESS);
+
+	return ret;
+}
+#endif
+
 BPF_LICENSE("GPL");
@@ -0,0 +1,56 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+#ifndef __BPF_STATIC_DATA_H_
+#define __BPF_STATIC_DATA_H_
+
+#include <linux/type_mapper.h>
+
+/*
+ * Endpoint specific values of template objects
+ *
+ * Template objects are compiled once per endpoint configuration and
+ * instantiated for each endpoint by the agent without invoking the compiler,
+ * see pkg/elf. The values specific to an endpoint are declared as undefined
+ * symbols by DEFINE_*() and fetched by the address of the symbol. The agent
+ * rewrites the 64 bit immediate load of the address with the value of the
+ * endpoint, an object which was not instantiated can not be loaded.
+ *
+ * Values are fetched in host byte order:
+ *  - DEFINE_U32(NAME): 32 bit value NAME
+ *  - DEFINE_MAC(NAME): 32 bit values NAME_1, NAME_2 holding the first 4 and
+ *    the last 2 bytes of a union macaddr
+ *  - DEFINE_IPV6(NAME): 32 bit values NAME_1 to NAME_4 holding the bytes of
+ *    the address, most significant byte first
+ */
+#define DEFINE_U32(NAME)	extern __u32 NAME
+#define fetch_u32(NAME)		((__u32)(unsigned long)&NAME)
+
+#define DEFINE_MAC(NAME)	DEFINE_U32(NAME##_1); DEFINE_U32(NAME##_2)
+#define fetch_mac(NAME)		{ .p1 = fetch_u32(NAME##_1), .p2 = fetch_u32(NAME##_2) }
+
+#define DEFINE_IPV6(NAME)	DEFINE_U32(NAME##_1); DEFINE_U32(NAME##_2); \
+				DEFINE_U32(NAME##_3); DEFINE_U32(NAME##_4)
+#define __fetch_bytes(x)	((x) >> 24) & 0xff, ((x) >> 16) & 0xff, \
+				((x) >> 8) & 0xff, (x) & 0xff
+/* Expands to the 16 bytes expected by BPF_V6() */
+#define fetch_ipv6(NAME)	__fetch_bytes(fetch_u32(NAME##_1)), \
+				__fetch_bytes(fetch_u32(NAME##_2)), \
+				__fetch_bytes(fetch_u32(NAME##_3)), \
+				__fetch_bytes(fetch_u32(NAME##_4))
+
+#endif /* __BPF_STATIC_DATA_H_ */
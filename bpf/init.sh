@@ -327,6 +327,12 @@ if [ "$MODE" = "direct" ]; then
 		OPTS="-DSECLABEL=${ID_WORLD} -DPOLICY_MAP=${POLICY_MAP}"
 		bpf_load $NATIVE_DEV "$OPTS" "ingress" bpf_netdev.c bpf_netdev.o from-netdev $CALLS_MAP
 
+		# BPF masquerading translates packets leaving the node at egress
+		# of the same device, bpf_load has already set up the qdisc.
+		if grep -q "^#define ENABLE_MASQUERADE" $RUNDIR/globals/node_config.h; then
+			tc filter add dev $NATIVE_DEV egress prio 1 handle 1 bpf da obj bpf_netdev.o sec to-netdev
+		fi
+
 		echo "$NATIVE_DEV" > $RUNDIR/device.state
 	fi
 elif [ "$MODE" = "lb" ]; then
@@ -26,10 +26,8 @@
 #include <stdint.h>
 #include <stdbool.h>
 
-// FIXME: GH-3239 LRU logic is not handling timeouts gracefully enough
-// #ifndef HAVE_LRU_MAP_TYPE
-// #define NEEDS_TIMEOUT 1
-// #endif
+// Timeouts are required with LRU maps as well, LRU maps only evict entries
+// once they are full.
 #define NEEDS_TIMEOUT 1
 
 #ifndef EVENT_SOURCE
@@ -182,7 +180,8 @@ struct policy_key {
 	__u16		dport;
 	__u8		protocol;
 	__u8		egress:1,
-			pad:7;
+			dport_wildcard:4, /* Number of wildcarded low-order bits of dport */
+			pad:3;
 };
 
 struct policy_entry {
@@ -267,6 +266,9 @@ enum {
 #define DROP_NO_TUNNEL_ENDPOINT -160
 #define DROP_PROXYMAP_CREATE_FAILED	-161
 #define DROP_POLICY_CIDR		-162
+#define DROP_RATE_LIMITED	-163
+#define DROP_NAT_NO_MAPPING	-164
+#define DROP_FRAG_NOT_FOUND	-165
 
 /* Cilium metrics reason for forwarding packet.
  * If reason > 0 then this is a drop reason and value corresponds to -(DROP_*)
@@ -388,7 +390,8 @@ struct ct_entry {
 	      nat46:1,
 	      lb_loopback:1,
 	      seen_non_syn:1,
-	      reserve:11;
+	      proxy_redirect:1, /* Connection is redirected to a proxy */
+	      reserve:10;
 	__u16 rev_nat_index;
 	__u16 slave;
 
@@ -452,7 +455,8 @@ struct lb_sequence {
 struct ct_state {
 	__u16 rev_nat_index;
 	__u16 loopback:1,
-	      reserved:15;
+	      proxy_redirect:1,
+	      reserved:14;
 	__be16 orig_dport;
 	__be32 addr;
 	__be32 svc_addr;
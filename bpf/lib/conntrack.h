@@ -30,6 +30,7 @@
 
 #define CT_DEFAULT_LIFETIME_TCP		21600	/* 6 hours */
 #define CT_DEFAULT_LIFETIME_NONTCP	60	/* 60 seconds */
+#define CT_DEFAULT_LIFETIME_ICMP	60	/* 60 seconds */
 #define CT_DEFAULT_SYN_TIMEOUT		60	/* 60 seconds */
 #define CT_DEFAULT_CLOSE_TIMEOUT	10	/* 10 seconds */
 #define CT_DEFAULT_REPORT_INTERVAL	5	/* 5 seconds */
@@ -42,6 +43,10 @@
 #define CT_LIFETIME_NONTCP CT_DEFAULT_LIFETIME_NONTCP
 #endif
 
+#ifndef CT_LIFETIME_ICMP
+#define CT_LIFETIME_ICMP CT_DEFAULT_LIFETIME_ICMP
+#endif
+
 #ifndef CT_SYN_TIMEOUT
 #define CT_SYN_TIMEOUT CT_DEFAULT_SYN_TIMEOUT
 #endif
@@ -189,19 +194,25 @@ static inline __u32 __inline__ __ct_update_timeout(struct ct_entry *entry,
  * last_updated timestamp and returns true. Otherwise returns false.
  */
 static inline __u32 __inline__ ct_update_timeout(struct ct_entry *entry,
-						 bool tcp, int dir,
+						 __u8 nexthdr, int dir,
 						 union tcp_flags seen_flags)
 {
 	__u32 lifetime = CT_LIFETIME_NONTCP;
 	bool syn = seen_flags.syn;
 
-	if (tcp) {
+	switch (nexthdr) {
+	case IPPROTO_TCP:
 		entry->seen_non_syn |= !syn;
 
 		if (entry->seen_non_syn)
 			lifetime = CT_LIFETIME_TCP;
 		else
 			lifetime = CT_SYN_TIMEOUT;
+		break;
+	case IPPROTO_ICMP:
+	case IPPROTO_ICMPV6:
+		lifetime = CT_LIFETIME_ICMP;
+		break;
 	}
 
 	return __ct_update_timeout(entry, lifetime, dir, seen_flags);
@@ -221,7 +232,7 @@ static inline bool __inline__ ct_entry_alive(const struct ct_entry *entry)
 static inline int __inline__ __ct_lookup(void *map, struct __sk_buff *skb,
 					 void *tuple, int action, int dir,
 					 struct ct_state *ct_state,
-					 bool is_tcp, union tcp_flags seen_flags,
+					 __u8 nexthdr, union tcp_flags seen_flags,
 					 __u32 *monitor)
 {
 	struct ct_entry *entry;
@@ -230,11 +241,12 @@ static inline int __inline__ __ct_lookup(void *map, struct __sk_buff *skb,
 	if ((entry = map_lookup_elem(map, tuple))) {
 		cilium_dbg(skb, DBG_CT_MATCH, entry->lifetime, entry->rev_nat_index);
 		if (ct_entry_alive(entry)) {
-			*monitor = ct_update_timeout(entry, is_tcp, dir, seen_flags);
+			*monitor = ct_update_timeout(entry, nexthdr, dir, seen_flags);
 		}
 		if (ct_state) {
 			ct_state->rev_nat_index = entry->rev_nat_index;
 			ct_state->loopback = entry->lb_loopback;
+			ct_state->proxy_redirect = entry->proxy_redirect;
 			ct_state->slave = entry->slave;
 		}
 
@@ -260,7 +272,7 @@ static inline int __inline__ __ct_lookup(void *map, struct __sk_buff *skb,
 			ret = entry->rx_closing + entry->tx_closing;
 			if (unlikely(ret >= 1)) {
 				ct_reset_closing(entry);
-				*monitor = ct_update_timeout(entry, is_tcp, dir, seen_flags);
+				*monitor = ct_update_timeout(entry, nexthdr, dir, seen_flags);
 			}
 			break;
 		case ACTION_CLOSE:
@@ -312,7 +324,6 @@ static inline int __inline__ ct_lookup6(void *map, struct ipv6_ct_tuple *tuple,
 					struct ct_state *ct_state, __u32 *monitor)
 {
 	int ret = CT_NEW, action = ACTION_UNSPEC;
-	bool is_tcp = tuple->nexthdr == IPPROTO_TCP;
 	union tcp_flags tcp_flags = { 0 };
 
 	/* The tuple is created in reverse order initially to find a
@@ -407,8 +418,8 @@ static inline int __inline__ ct_lookup6(void *map, struct ipv6_ct_tuple *tuple,
 	cilium_dbg3(skb, DBG_CT_LOOKUP6_1, (__u32) tuple->saddr.p4, (__u32) tuple->daddr.p4,
 		      (bpf_ntohs(tuple->sport) << 16) | bpf_ntohs(tuple->dport));
 	cilium_dbg3(skb, DBG_CT_LOOKUP6_2, (tuple->nexthdr << 8) | tuple->flags, 0, 0);
-	ret = __ct_lookup(map, skb, tuple, action, dir, ct_state, is_tcp,
-			  tcp_flags, monitor);
+	ret = __ct_lookup(map, skb, tuple, action, dir, ct_state,
+			  tuple->nexthdr, tcp_flags, monitor);
 	if (ret != CT_NEW) {
 		if (likely(ret == CT_ESTABLISHED)) {
 			if (unlikely(tuple->flags & TUPLE_F_RELATED))
@@ -423,7 +434,7 @@ static inline int __inline__ ct_lookup6(void *map, struct ipv6_ct_tuple *tuple,
 	if (dir != CT_SERVICE) {
 		ipv6_ct_tuple_reverse(tuple);
 		ret = __ct_lookup(map, skb, tuple, action, dir, ct_state,
-				  is_tcp, tcp_flags, monitor);
+				  tuple->nexthdr, tcp_flags, monitor);
 	}
 
 #ifdef LXC_NAT46
@@ -469,7 +480,6 @@ static inline int __inline__ ct_lookup4(void *map, struct ipv4_ct_tuple *tuple,
 					struct ct_state *ct_state, __u32 *monitor)
 {
 	int ret = CT_NEW, action = ACTION_UNSPEC;
-	bool is_tcp = tuple->nexthdr == IPPROTO_TCP;
 	union tcp_flags tcp_flags = { 0 };
 
 	/* The tuple is created in reverse order initially to find a
@@ -564,8 +574,8 @@ static inline int __inline__ ct_lookup4(void *map, struct ipv4_ct_tuple *tuple,
 		      (bpf_ntohs(tuple->sport) << 16) | bpf_ntohs(tuple->dport));
 	cilium_dbg3(skb, DBG_CT_LOOKUP4_2, (tuple->nexthdr << 8) | tuple->flags, 0, 0);
 #endif
-	ret = __ct_lookup(map, skb, tuple, action, dir, ct_state, is_tcp,
-			  tcp_flags, monitor);
+	ret = __ct_lookup(map, skb, tuple, action, dir, ct_state,
+			  tuple->nexthdr, tcp_flags, monitor);
 	if (ret != CT_NEW) {
 		if (likely(ret == CT_ESTABLISHED)) {
 			if (unlikely(tuple->flags & TUPLE_F_RELATED))
@@ -580,7 +590,7 @@ static inline int __inline__ ct_lookup4(void *map, struct ipv4_ct_tuple *tuple,
 	if (dir != CT_SERVICE) {
 		ipv4_ct_tuple_reverse(tuple);
 		ret = __ct_lookup(map, skb, tuple, action, dir, ct_state,
-				  is_tcp, tcp_flags, monitor);
+				  tuple->nexthdr, tcp_flags, monitor);
 	}
 out:
 	cilium_dbg(skb, DBG_CT_VERDICT, ret < 0 ? -ret : ret, ct_state->rev_nat_index);
@@ -624,9 +634,10 @@ static inline int __inline__ ct_create6(void *map, struct ipv6_ct_tuple *tuple,
 
 	entry.rev_nat_index = ct_state->rev_nat_index;
 	entry.lb_loopback = ct_state->loopback;
+	entry.proxy_redirect = ct_state->proxy_redirect;
 	entry.slave = ct_state->slave;
 	seen_flags.syn = is_tcp;
-	ct_update_timeout(&entry, is_tcp, dir, seen_flags);
+	ct_update_timeout(&entry, tuple->nexthdr, dir, seen_flags);
 
 	if (dir == CT_INGRESS) {
 		entry.rx_packets = 1;
@@ -699,9 +710,10 @@ static inline int __inline__ ct_create4(void *map, struct ipv4_ct_tuple *tuple,
 
 	entry.rev_nat_index = ct_state->rev_nat_index;
 	entry.lb_loopback = ct_state->loopback;
+	entry.proxy_redirect = ct_state->proxy_redirect;
 	entry.slave = ct_state->slave;
 	seen_flags.syn = is_tcp;
-	ct_update_timeout(&entry, is_tcp, dir, seen_flags);
+	ct_update_timeout(&entry, tuple->nexthdr, dir, seen_flags);
 
 	if (dir == CT_INGRESS) {
 		entry.rx_packets = 1;
@@ -27,6 +27,7 @@
 #include "trace.h"
 #include "csum.h"
 #include "l4.h"
+#include "maps.h"
 
 #ifndef DISABLE_SMAC_VERIFICATION
 static inline int is_valid_lxc_src_mac(struct ethhdr *eth)
@@ -43,19 +44,57 @@ static inline int is_valid_lxc_src_mac(struct ethhdr *eth)
 #endif
 
 #ifndef DISABLE_SIP_VERIFICATION
+#ifdef ENABLE_MULTI_IP
+/* Endpoints with additional addresses may send from any address which maps
+ * to the endpoint in the endpoints map.
+ */
+static inline int is_owned_lxc_ip(struct endpoint_key *key)
+{
+	struct endpoint_info *ep = map_lookup_elem(&cilium_lxc, key);
+
+	return ep && ep->lxc_id == LXC_ID;
+}
+#endif
+
 static inline int is_valid_lxc_src_ip(struct ipv6hdr *ip6)
 {
 	union v6addr valid = {};
 
 	BPF_V6(valid, LXC_IP);
 
-	return !ipv6_addrcmp((union v6addr *) &ip6->saddr, &valid);
+	if (!ipv6_addrcmp((union v6addr *) &ip6->saddr, &valid))
+		return 1;
+
+#ifdef ENABLE_MULTI_IP
+	{
+		struct endpoint_key key = {};
+
+		key.ip6 = *((union v6addr *) &ip6->saddr);
+		key.family = ENDPOINT_KEY_IPV6;
+
+		return is_owned_lxc_ip(&key);
+	}
+#else
+	return 0;
+#endif
 }
 
 static inline int is_valid_lxc_src_ipv4(struct iphdr *ip4)
 {
 #ifdef LXC_IPV4
-	return ip4->saddr == LXC_IPV4;
+	if (ip4->saddr == LXC_IPV4)
+		return 1;
+#endif
+
+#ifdef ENABLE_MULTI_IP
+	{
+		struct endpoint_key key = {};
+
+		key.ip4 = ip4->saddr;
+		key.family = ENDPOINT_KEY_IPV4;
+
+		return is_owned_lxc_ip(&key);
+	}
 #else
 	/* Can't send IPv4 if no IPv4 address is configured */
 	return 0;
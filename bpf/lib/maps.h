@@ -158,6 +158,24 @@ struct bpf_elf_map __section_maps cilium_ipcache = {
 	.flags		= BPF_F_NO_PREALLOC,
 };
 
+/* MAC address of the next hop towards a node on the native device */
+struct node_neigh_entry {
+	__u8		mac[6];
+	__u16		pad;
+};
+
+#ifdef ENABLE_NODE_NEIGH
+/* Global node IPv4 -> next hop MAC map for direct routing, maintained by the
+ * agent from the neighbor table of the kernel */
+struct bpf_elf_map __section_maps cilium_node_neigh4 = {
+	.type		= BPF_MAP_TYPE_HASH,
+	.size_key	= sizeof(__be32),
+	.size_value	= sizeof(struct node_neigh_entry),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= NODE_NEIGH_MAP_SIZE,
+};
+#endif /* ENABLE_NODE_NEIGH */
+
 #ifndef SKIP_CALLS_MAP
 static __always_inline void ep_tail_call(struct __sk_buff *skb, uint32_t index)
 {
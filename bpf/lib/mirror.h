@@ -0,0 +1,87 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+#ifndef __LIB_MIRROR_H_
+#define __LIB_MIRROR_H_
+
+/*
+ * Policy controlled packet mirroring
+ *
+ * API:
+ * void mirror_packet(skb, nexthdr, sport, dport)
+ *
+ * Mirrors a copy of the packet to the collector configured by the mirror
+ * policy of the endpoint:
+ *  - MIRROR_IFINDEX: interface index of a local collector endpoint
+ *  - MIRROR_VXLAN_IPV4, MIRROR_VXLAN_VNI: remote VXLAN collector (host byte
+ *    order), requires ENCAP_IFINDEX
+ *  - MIRROR_PORT_MATCH(proto, port): optional, limits mirroring to packets
+ *    from or to matching ports (network byte order)
+ *  - MIRROR_SAMPLING: optional, mirrors one out of MIRROR_SAMPLING packets
+ *
+ * If no collector is defined, the API will be compiled in as a NOP.
+ */
+
+#include "common.h"
+#include "utils.h"
+
+#if defined MIRROR_IFINDEX || (defined MIRROR_VXLAN_IPV4 && defined ENCAP_IFINDEX)
+
+#ifndef MIRROR_PORT_MATCH
+#define MIRROR_PORT_MATCH(proto, port) true
+#endif
+
+static inline void __inline__ mirror_packet(struct __sk_buff *skb, __u8 nexthdr,
+					    __be16 sport, __be16 dport)
+{
+#ifdef MIRROR_VXLAN_IPV4
+	struct bpf_tunnel_key key = {};
+#endif
+
+	if (!MIRROR_PORT_MATCH(nexthdr, dport) &&
+	    !MIRROR_PORT_MATCH(nexthdr, sport))
+		return;
+
+#if defined MIRROR_SAMPLING && MIRROR_SAMPLING > 1
+	if (get_prandom_u32() % MIRROR_SAMPLING)
+		return;
+#endif
+
+#ifdef MIRROR_IFINDEX
+	clone_redirect(skb, MIRROR_IFINDEX, 0);
+#else
+	key.tunnel_id = MIRROR_VXLAN_VNI;
+	key.remote_ipv4 = MIRROR_VXLAN_IPV4;
+
+	/* The tunnel key is only considered by the tunnel device, it does
+	 * not alter the forwarding of the original packet. */
+	if (skb_set_tunnel_key(skb, &key, sizeof(key), 0) < 0)
+		return;
+
+	clone_redirect(skb, ENCAP_IFINDEX, 0);
+#endif
+}
+
+#else /* MIRROR_IFINDEX || MIRROR_VXLAN_IPV4 */
+
+static inline void __inline__ mirror_packet(struct __sk_buff *skb, __u8 nexthdr,
+					    __be16 sport, __be16 dport)
+{
+}
+
+#endif /* MIRROR_IFINDEX || MIRROR_VXLAN_IPV4 */
+#endif /* __LIB_MIRROR_H_ */
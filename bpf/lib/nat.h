@@ -0,0 +1,400 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+#ifndef __LIB_NAT_H_
+#define __LIB_NAT_H_
+
+/*
+ * Source NAT of IPv4 traffic leaving the node (masquerading)
+ *
+ * API:
+ * int snat_v4_egress(skb)
+ * int snat_v4_ingress(skb)
+ *
+ * snat_v4_egress() rewrites the source of TCP and UDP packets from
+ * SNAT_IPV4_SRC_CIDR to SNAT_IPV4_EXTERNAL unless the destination is part of
+ * SNAT_IPV4_EXCLUDE_DST_CIDR. snat_v4_ingress() reverts the translation for
+ * replies. Each translated flow is represented by a pair of entries in
+ * cilium_snat_v4_external, one per direction. The agent removes pairs of
+ * expired entries along with the connection tracking garbage collection.
+ * Other protocols are left to iptables.
+ *
+ * The ports of a fragmented datagram are only present in its first fragment.
+ * They are stored in cilium_snat_v4_frags to translate the remaining
+ * fragments, which must not arrive before the first one.
+ *
+ * If ENABLE_MASQUERADE is not defined, the API will be compiled in as a NOP.
+ */
+
+#include <linux/ip.h>
+
+#include "common.h"
+#include "utils.h"
+#include "ipv4.h"
+#include "csum.h"
+#include "l4.h"
+
+#define NAT_DIR_EGRESS		0
+#define NAT_DIR_INGRESS		1
+
+/* Number of random ports tried if the original source port is in use */
+#define SNAT_COLLISION_RETRIES	16
+
+struct ipv4_nat_key {
+	__be32	saddr;
+	__be32	daddr;
+	__be16	sport;
+	__be16	dport;
+	__u8	nexthdr;
+	__u8	dir;
+	__u16	pad;
+};
+
+struct ipv4_nat_frag_key {
+	__be32	saddr;
+	__be32	daddr;
+	__be16	id;
+	__u8	nexthdr;
+	__u8	pad;
+};
+
+struct ipv4_nat_frag_ports {
+	__be16	sport;
+	__be16	dport;
+};
+
+struct ipv4_nat_entry {
+	__u64	created;	/* sec */
+	__u32	lifetime;	/* sec */
+	__be32	to_addr;
+	__be16	to_port;
+	__u16	pad1;
+	__u32	pad2;
+};
+
+#if defined ENABLE_MASQUERADE && defined ENABLE_IPV4
+
+#ifdef HAVE_LRU_MAP_TYPE
+#define SNAT_MAP_TYPE BPF_MAP_TYPE_LRU_HASH
+#else
+#define SNAT_MAP_TYPE BPF_MAP_TYPE_HASH
+#endif
+
+struct bpf_elf_map __section_maps cilium_snat_v4_external = {
+	.type		= SNAT_MAP_TYPE,
+	.size_key	= sizeof(struct ipv4_nat_key),
+	.size_value	= sizeof(struct ipv4_nat_entry),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= SNAT_MAPPING_IPV4_SIZE,
+};
+
+struct bpf_elf_map __section_maps cilium_snat_v4_frags = {
+	.type		= SNAT_MAP_TYPE,
+	.size_key	= sizeof(struct ipv4_nat_frag_key),
+	.size_value	= sizeof(struct ipv4_nat_frag_ports),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= SNAT_FRAG_MAPPING_IPV4_SIZE,
+};
+
+static inline __u32 __inline__ snat_v4_lifetime(__u8 nexthdr)
+{
+	if (nexthdr == IPPROTO_TCP)
+		return CT_LIFETIME_TCP;
+	return CT_LIFETIME_NONTCP;
+}
+
+static inline bool __inline__ ipv4_is_first_fragment(struct iphdr *ip4)
+{
+	return !(ip4->frag_off & bpf_htons(0x1FFF));
+}
+
+/**
+ * Load the tuple of the packet into key. *has_ports is set to false for
+ * fragments without the L4 header.
+ *
+ * Returns 1 if the packet is subject to translation, 0 if it is left to the
+ * stack, DROP_FRAG_NOT_FOUND if the first fragment of the datagram has not
+ * been seen or a negative DROP_* reason.
+ */
+static inline int __inline__ snat_v4_load_tuple(struct __sk_buff *skb,
+						struct iphdr *ip4, int l4_off,
+						struct ipv4_nat_key *key,
+						bool *has_ports)
+{
+	struct ipv4_nat_frag_key frag_key = {};
+	struct ipv4_nat_frag_ports *frag_ports;
+
+	key->saddr = ip4->saddr;
+	key->daddr = ip4->daddr;
+	key->nexthdr = ip4->protocol;
+
+	switch (key->nexthdr) {
+	case IPPROTO_TCP:
+	case IPPROTO_UDP:
+		break;
+	default:
+		/* Other protocols are left to the stack */
+		return 0;
+	}
+
+	*has_ports = ipv4_is_first_fragment(ip4);
+	if (*has_ports) {
+		/* load sport + dport in one go */
+		if (skb_load_bytes(skb, l4_off, &key->sport, 4) < 0)
+			return DROP_CT_INVALID_HDR;
+		if (!ipv4_is_fragment(ip4))
+			return 1;
+	}
+
+	frag_key.saddr = ip4->saddr;
+	frag_key.daddr = ip4->daddr;
+	frag_key.id = ip4->id;
+	frag_key.nexthdr = ip4->protocol;
+
+	if (*has_ports) {
+		struct ipv4_nat_frag_ports ports = {
+			.sport = key->sport,
+			.dport = key->dport,
+		};
+
+		if (map_update_elem(&cilium_snat_v4_frags, &frag_key, &ports, 0) < 0)
+			return DROP_NAT_NO_MAPPING;
+		return 1;
+	}
+
+	frag_ports = map_lookup_elem(&cilium_snat_v4_frags, &frag_key);
+	if (!frag_ports)
+		return DROP_FRAG_NOT_FOUND;
+	key->sport = frag_ports->sport;
+	key->dport = frag_ports->dport;
+	return 1;
+}
+
+/**
+ * Allocate a port of SNAT_IPV4_EXTERNAL for the flow described by the
+ * egress key and create both NAT entries. The original source port is kept
+ * if it is in the range and not in use for the same peer.
+ *
+ * Returns the new entry for the egress direction or NULL.
+ */
+static inline struct ipv4_nat_entry * __inline__
+snat_v4_create(struct ipv4_nat_key *key)
+{
+	struct ipv4_nat_entry ostate = {}, rstate = {};
+	struct ipv4_nat_key rkey = {
+		.saddr = key->daddr,
+		.daddr = SNAT_IPV4_EXTERNAL,
+		.sport = key->dport,
+		.nexthdr = key->nexthdr,
+		.dir = NAT_DIR_INGRESS,
+	};
+	__u16 port = bpf_ntohs(key->sport);
+	__u32 now = bpf_ktime_get_sec();
+	int i;
+
+	rstate.created = now;
+	rstate.lifetime = now + snat_v4_lifetime(key->nexthdr);
+	rstate.to_addr = key->saddr;
+	rstate.to_port = key->sport;
+
+	if (port < SNAT_MIN_PORT || port > SNAT_MAX_PORT)
+		port = SNAT_MIN_PORT + get_prandom_u32() % (SNAT_MAX_PORT - SNAT_MIN_PORT + 1);
+
+#pragma unroll
+	for (i = 0; i < SNAT_COLLISION_RETRIES; i++) {
+		rkey.dport = bpf_htons(port);
+		if (map_update_elem(&cilium_snat_v4_external, &rkey, &rstate, BPF_NOEXIST) == 0)
+			goto create_egress;
+		port = SNAT_MIN_PORT + get_prandom_u32() % (SNAT_MAX_PORT - SNAT_MIN_PORT + 1);
+	}
+
+	return NULL;
+
+create_egress:
+	ostate.created = now;
+	ostate.lifetime = rstate.lifetime;
+	ostate.to_addr = SNAT_IPV4_EXTERNAL;
+	ostate.to_port = rkey.dport;
+
+	if (map_update_elem(&cilium_snat_v4_external, key, &ostate, 0) < 0) {
+		map_delete_elem(&cilium_snat_v4_external, &rkey);
+		return NULL;
+	}
+
+	return map_lookup_elem(&cilium_snat_v4_external, key);
+}
+
+/**
+ * Rewrite the source (egress) or destination (ingress) address and port of
+ * the packet to the address and port of the NAT entry. Only the address is
+ * rewritten if the packet is a fragment without the L4 header.
+ *
+ * NOTE: Calling this function will invalidate any pkt context offset
+ * validation for direct packet access.
+ *
+ * Return 0 on success or a negative DROP_* reason
+ */
+static inline int __inline__ snat_v4_rewrite(struct __sk_buff *skb, int l4_off,
+					     struct ipv4_nat_key *key,
+					     struct ipv4_nat_entry *state,
+					     bool has_ports)
+{
+	struct csum_offset csum = {};
+	__be32 old_addr, new_addr = state->to_addr;
+	__be16 old_port, new_port = state->to_port;
+	int addr_off, port_off;
+
+	if (key->dir == NAT_DIR_EGRESS) {
+		old_addr = key->saddr;
+		old_port = key->sport;
+		addr_off = ETH_HLEN + offsetof(struct iphdr, saddr);
+		port_off = TCP_SPORT_OFF;
+	} else {
+		old_addr = key->daddr;
+		old_port = key->dport;
+		addr_off = ETH_HLEN + offsetof(struct iphdr, daddr);
+		port_off = TCP_DPORT_OFF;
+	}
+
+	if (has_ports)
+		csum_l4_offset_and_flags(key->nexthdr, &csum);
+
+	if (has_ports && new_port != old_port &&
+	    l4_modify_port(skb, l4_off, port_off, &csum, new_port, old_port) < 0)
+		return DROP_WRITE_ERROR;
+
+	if (skb_store_bytes(skb, addr_off, &new_addr, 4, 0) < 0)
+		return DROP_WRITE_ERROR;
+
+	if (l3_csum_replace(skb, ETH_HLEN + offsetof(struct iphdr, check), old_addr, new_addr, 4) < 0)
+		return DROP_CSUM_L3;
+
+	if (csum.offset &&
+	    csum_l4_replace(skb, l4_off, &csum, old_addr, new_addr, 4 | BPF_F_PSEUDO_HDR) < 0)
+		return DROP_CSUM_L4;
+
+	return 0;
+}
+
+static inline bool __inline__ snat_v4_needed(struct iphdr *ip4)
+{
+	return (ip4->saddr & SNAT_IPV4_SRC_MASK) == SNAT_IPV4_SRC_CIDR &&
+	       (ip4->daddr & SNAT_IPV4_EXCLUDE_DST_MASK) != SNAT_IPV4_EXCLUDE_DST_CIDR;
+}
+
+/**
+ * Masquerade a packet leaving the node.
+ *
+ * Returns TC_ACT_OK if the packet may pass or a negative DROP_* reason.
+ */
+static inline int __inline__ snat_v4_egress(struct __sk_buff *skb)
+{
+	struct ipv4_nat_entry *state;
+	struct ipv4_nat_key key = {};
+	void *data, *data_end;
+	bool has_ports = true;
+	struct iphdr *ip4;
+	int l4_off, ret;
+
+	if (!revalidate_data(skb, &data, &data_end, &ip4))
+		return DROP_INVALID;
+
+	if (!snat_v4_needed(ip4))
+		return TC_ACT_OK;
+
+	l4_off = ETH_HLEN + ipv4_hdrlen(ip4);
+	ret = snat_v4_load_tuple(skb, ip4, l4_off, &key, &has_ports);
+	if (ret <= 0)
+		return ret < 0 ? ret : TC_ACT_OK;
+
+	key.dir = NAT_DIR_EGRESS;
+	state = map_lookup_elem(&cilium_snat_v4_external, &key);
+	if (state) {
+		state->lifetime = bpf_ktime_get_sec() + snat_v4_lifetime(key.nexthdr);
+	} else {
+		/* Mappings are only created by packets carrying the ports */
+		if (!has_ports)
+			return DROP_NAT_NO_MAPPING;
+		state = snat_v4_create(&key);
+		if (!state)
+			return DROP_NAT_NO_MAPPING;
+	}
+
+	ret = snat_v4_rewrite(skb, l4_off, &key, state, has_ports);
+	if (IS_ERR(ret))
+		return ret;
+
+	return TC_ACT_OK;
+}
+
+/**
+ * Revert the masquerading of a reply entering the node. Packets to
+ * SNAT_IPV4_EXTERNAL without a NAT entry are passed on unmodified.
+ *
+ * Returns TC_ACT_OK if the packet may pass or a negative DROP_* reason.
+ */
+static inline int __inline__ snat_v4_ingress(struct __sk_buff *skb)
+{
+	struct ipv4_nat_entry *state;
+	struct ipv4_nat_key key = {};
+	void *data, *data_end;
+	bool has_ports = true;
+	struct iphdr *ip4;
+	int l4_off, ret;
+
+	if (!revalidate_data(skb, &data, &data_end, &ip4))
+		return DROP_INVALID;
+
+	if (ip4->daddr != SNAT_IPV4_EXTERNAL)
+		return TC_ACT_OK;
+
+	l4_off = ETH_HLEN + ipv4_hdrlen(ip4);
+	ret = snat_v4_load_tuple(skb, ip4, l4_off, &key, &has_ports);
+	/* Fragments of datagrams of the node itself are left to the stack */
+	if (ret == DROP_FRAG_NOT_FOUND)
+		return TC_ACT_OK;
+	if (ret <= 0)
+		return ret < 0 ? ret : TC_ACT_OK;
+
+	key.dir = NAT_DIR_INGRESS;
+	state = map_lookup_elem(&cilium_snat_v4_external, &key);
+	if (!state)
+		return TC_ACT_OK;
+
+	state->lifetime = bpf_ktime_get_sec() + snat_v4_lifetime(key.nexthdr);
+
+	ret = snat_v4_rewrite(skb, l4_off, &key, state, has_ports);
+	if (IS_ERR(ret))
+		return ret;
+
+	return TC_ACT_OK;
+}
+
+#else /* ENABLE_MASQUERADE && ENABLE_IPV4 */
+
+static inline int __inline__ snat_v4_egress(struct __sk_buff *skb)
+{
+	return TC_ACT_OK;
+}
+
+static inline int __inline__ snat_v4_ingress(struct __sk_buff *skb)
+{
+	return TC_ACT_OK;
+}
+
+#endif /* ENABLE_MASQUERADE && ENABLE_IPV4 */
+#endif /* __LIB_NAT_H_ */
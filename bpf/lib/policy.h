@@ -43,6 +43,37 @@ static inline bool identity_is_reserved(__u32 identity)
 	return identity < UNMANAGED_ID;
 }
 
+#ifdef POLICY_PORT_RANGES
+/* Maximum number of wildcarded low-order bits of the destination port in a
+ * policy key, see PortWildcardMax in pkg/maps/policymap. */
+#define POLICY_PORT_WILDCARD_MAX 15
+
+/**
+ * Look up the policy entry of a port range covering the destination port of
+ * key. A port range is stored as one or more keys of which the lowest
+ * dport_wildcard bits of dport are cleared. The dport and dport_wildcard
+ * fields of key are modified by the lookups.
+ */
+static inline struct policy_entry * __inline__
+policy_lookup_port_range(void *map, struct policy_key *key, __u16 dport)
+{
+	struct policy_entry *policy;
+	__u16 port = bpf_ntohs(dport);
+	int i;
+
+#pragma unroll
+	for (i = 1; i <= POLICY_PORT_WILDCARD_MAX; i++) {
+		key->dport = bpf_htons(port & ~((1 << i) - 1));
+		key->dport_wildcard = i;
+		policy = map_lookup_elem(map, key);
+		if (policy)
+			return policy;
+	}
+
+	return NULL;
+}
+#endif /* POLICY_PORT_RANGES */
+
 static inline int __inline__
 __policy_can_access(void *map, struct __sk_buff *skb, __u32 identity,
 		    __u16 dport, __u8 proto, size_t cidr_addr_size,
@@ -55,6 +86,7 @@ __policy_can_access(void *map, struct __sk_buff *skb, __u32 identity,
 		.dport = dport,
 		.protocol = proto,
 		.egress = !dir,
+		.dport_wildcard = 0,
 		.pad = 0,
 	};
 
@@ -93,6 +125,26 @@ __policy_can_access(void *map, struct __sk_buff *skb, __u32 identity,
 			__sync_fetch_and_add(&policy->bytes, skb->len);
 			goto get_proxy_port;
 		}
+
+#ifdef POLICY_PORT_RANGES
+		/* Port ranges are only looked up if no entry for the exact
+		 * port or for all ports exists, so that traffic allowed by
+		 * such entries does not pay for the additional lookups. The
+		 * lookups are only compiled in for endpoints with the
+		 * PolicyPortRanges option enabled. */
+		key.sec_label = identity;
+		policy = policy_lookup_port_range(map, &key, dport);
+		if (!policy) {
+			key.sec_label = 0;
+			policy = policy_lookup_port_range(map, &key, dport);
+		}
+		if (policy) {
+			/* FIXME: Use per cpu counters */
+			__sync_fetch_and_add(&policy->packets, 1);
+			__sync_fetch_and_add(&policy->bytes, skb->len);
+			goto get_proxy_port;
+		}
+#endif /* POLICY_PORT_RANGES */
 	}
 
 	if (skb->cb[CB_POLICY])
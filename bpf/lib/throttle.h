@@ -0,0 +1,116 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+#ifndef __LIB_THROTTLE_H_
+#define __LIB_THROTTLE_H_
+
+/*
+ * Egress bandwidth limiting of an endpoint
+ *
+ * API:
+ * int throttle_egress(skb)
+ *
+ * If EGRESS_BANDWIDTH (in bits per second) is not defined, the API will be
+ * compiled in as a NOP.
+ */
+
+#include "common.h"
+#include "utils.h"
+
+#ifdef EGRESS_BANDWIDTH
+
+/* The bucket holds the number of bytes which can be sent at the configured
+ * rate within 1/THROTTLE_BURST_DIV seconds, but at least one full frame. */
+#define THROTTLE_BURST_DIV	10
+#define THROTTLE_MIN_BURST	1514
+
+struct throttle_state {
+	__u64	tokens;		/* bytes */
+	__u64	last_refill;	/* ns */
+	__u64	bytes;		/* passed bytes, sampled by the agent */
+};
+
+/* The state is pinned per endpoint so the agent can derive the current
+ * rate from the number of passed bytes. It is kept across regenerations. */
+struct bpf_elf_map __section_maps THROTTLE_MAP = {
+	.type		= BPF_MAP_TYPE_ARRAY,
+	.size_key	= sizeof(__u32),
+	.size_value	= sizeof(struct throttle_state),
+	.pinning	= PIN_GLOBAL_NS,
+	.max_elem	= 1,
+};
+
+/**
+ * Token bucket limiting the rate of packets leaving the endpoint.
+ *
+ * The state is shared among all CPUs without synchronization, concurrent
+ * updates may result in a slightly higher rate than configured.
+ *
+ * Returns TC_ACT_OK if the packet may pass or DROP_RATE_LIMITED.
+ */
+static inline int __inline__ throttle_egress(struct __sk_buff *skb)
+{
+	const __u64 rate = EGRESS_BANDWIDTH / 8;
+	__u64 burst = rate / THROTTLE_BURST_DIV;
+	struct throttle_state *state;
+	__u64 now, elapsed, added, tokens;
+	__u32 zero = 0;
+
+	state = map_lookup_elem(&THROTTLE_MAP, &zero);
+	if (!state)
+		return TC_ACT_OK;
+
+	if (burst < THROTTLE_MIN_BURST)
+		burst = THROTTLE_MIN_BURST;
+
+	now = bpf_ktime_get_nsec();
+	elapsed = now - state->last_refill;
+
+	/* Avoid overflows, the bucket is full after a second anyway */
+	if (elapsed >= NSEC_PER_SEC) {
+		tokens = burst;
+		state->last_refill = now;
+	} else {
+		added = elapsed * rate / NSEC_PER_SEC;
+		tokens = state->tokens + added;
+		/* Keep accumulating time until at least one token is added,
+		 * otherwise low rates would never refill the bucket. */
+		if (added)
+			state->last_refill = now;
+	}
+	if (tokens > burst)
+		tokens = burst;
+
+	if (tokens < skb->len) {
+		state->tokens = tokens;
+		return DROP_RATE_LIMITED;
+	}
+
+	state->tokens = tokens - skb->len;
+	__sync_fetch_and_add(&state->bytes, skb->len);
+	return TC_ACT_OK;
+}
+
+#else /* EGRESS_BANDWIDTH */
+
+static inline int __inline__ throttle_egress(struct __sk_buff *skb)
+{
+	return TC_ACT_OK;
+}
+
+#endif /* EGRESS_BANDWIDTH */
+#endif /* __LIB_THROTTLE_H_ */
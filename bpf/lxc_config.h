@@ -26,6 +26,7 @@
 #define LXC_ID 0x1010
 #define LXC_ID_NB 0x1010
 #define ENABLE_NAT46
+#define ENABLE_MULTI_IP 1
 #ifndef SECLABEL
 #define SECLABEL 0xfffff
 #define SECLABEL_NB 0xfffff
@@ -44,11 +45,16 @@
 #define CT_MAP_SIZE_TCP 4096
 #define CT_MAP_SIZE_ANY 4096
 #define CALLS_MAP cilium_calls_111
+#define THROTTLE_MAP cilium_throttle_111
 #define LB_L3
 #define LB_L4
 #define CONNTRACK
 #define CONNTRACK_ACCOUNTING
+#define EGRESS_BANDWIDTH 1000000000
 #define ENABLE_IPv4
+#define MIRROR_IFINDEX 1
+#define MIRROR_PORT_MATCH(proto, port) (((proto) == IPPROTO_TCP && (port) == bpf_htons(80)))
+#define MIRROR_SAMPLING 10
 
 /* It appears that we can support around the below number of prefixes in an
  * unrolled loop for LPM CIDR handling in older kernels along with the rest of
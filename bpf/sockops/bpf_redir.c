@@ -0,0 +1,47 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+#include <node_config.h>
+
+#include "bpf_sockops.h"
+
+/* Redirects messages to the socket of the peer if it is part of the sockhash
+ * map. The key of the peer is the reversed key of the sending socket.
+ */
+__section("sk_msg")
+int bpf_redir(struct sk_msg_md *msg)
+{
+	struct sock_key key = {};
+
+	if (msg->family != AF_INET)
+		return SK_PASS;
+
+	key.sip4 = msg->remote_ip4;
+	key.dip4 = msg->local_ip4;
+	key.family = ENDPOINT_KEY_IPV4;
+	key.sport = msg->remote_port >> 16;
+	key.dport = bpf_htonl(msg->local_port) >> 16;
+
+	/* Messages of sockets without a peer in the map continue on the
+	 * regular stack.
+	 */
+	msg_redirect_hash(msg, &cilium_sock_ops, &key, BPF_F_INGRESS);
+
+	return SK_PASS;
+}
+
+BPF_LICENSE("GPL");
@@ -0,0 +1,74 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+#include <node_config.h>
+
+#include "bpf_sockops.h"
+
+struct bpf_elf_map __section_maps cilium_lxc = {
+	.type		= BPF_MAP_TYPE_HASH,
+	.size_key	= sizeof(struct endpoint_key),
+	.size_value	= sizeof(struct endpoint_info),
+	.max_elem	= ENDPOINTS_MAP_SIZE,
+};
+
+static __always_inline bool is_local_ipv4(__u32 ip4)
+{
+	struct endpoint_key key = {};
+
+	key.ip4 = ip4;
+	key.family = ENDPOINT_KEY_IPV4;
+
+	return map_lookup_elem(&cilium_lxc, &key) != NULL;
+}
+
+static __always_inline void sockops_ipv4(struct bpf_sock_ops *skops)
+{
+	struct sock_key key = {};
+
+	key.sip4 = skops->local_ip4;
+	key.dip4 = skops->remote_ip4;
+	key.family = ENDPOINT_KEY_IPV4;
+	key.sport = bpf_htonl(skops->local_port) >> 16;
+	key.dport = skops->remote_port >> 16;
+
+	/* Only connections between local endpoints, including the host
+	 * and thus the proxy, can be short-circuited.
+	 */
+	if (!is_local_ipv4(key.sip4) || !is_local_ipv4(key.dip4))
+		return;
+
+	sock_hash_update(skops, &cilium_sock_ops, &key, BPF_NOEXIST);
+}
+
+__section("sockops")
+int bpf_sockmap(struct bpf_sock_ops *skops)
+{
+	switch (skops->op) {
+	case BPF_SOCK_OPS_ACTIVE_ESTABLISHED_CB:
+	case BPF_SOCK_OPS_PASSIVE_ESTABLISHED_CB:
+		if (skops->family == AF_INET)
+			sockops_ipv4(skops);
+		break;
+	default:
+		break;
+	}
+
+	return 0;
+}
+
+BPF_LICENSE("GPL");
@@ -0,0 +1,57 @@
+/*
+ *  Copyright (C) 2018 Authors of Cilium
+ *
+ *  This program is free software; you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation; either version 2 of the License, or
+ *  (at your option) any later version.
+ *
+ *  This program is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with this program; if not, write to the Free Software
+ *  Foundation, Inc., 51 Franklin St, Fifth Floor, Boston, MA  02110-1301  USA
+ */
+#ifndef __BPF_SOCKOPS__
+#define __BPF_SOCKOPS__
+
+#include <bpf/api.h>
+
+#include "../lib/common.h"
+#include "../lib/utils.h"
+
+#ifndef AF_INET
+#define AF_INET 2
+#endif
+
+#ifndef SOCKOPS_MAP_SIZE
+#define SOCKOPS_MAP_SIZE 65535
+#endif
+
+/* Key of the sockhash map, identifies a socket by its IPv4 4-tuple as seen
+ * from the socket itself. Ports are stored in network byte order.
+ */
+struct sock_key {
+	__u32 sip4;
+	__u32 dip4;
+	__u8  family;
+	__u8  pad1;
+	__u16 pad2;
+	__u32 sport;
+	__u32 dport;
+} __attribute__((packed));
+
+/* Maps are not pinned by the loader, the agent passes the pinned maps to
+ * bpftool when loading the programs.
+ */
+struct bpf_elf_map __section_maps cilium_sock_ops = {
+	.type		= BPF_MAP_TYPE_SOCKHASH,
+	.size_key	= sizeof(struct sock_key),
+	.size_value	= sizeof(int),
+	.max_elem	= SOCKOPS_MAP_SIZE,
+};
+
+#endif /* __BPF_SOCKOPS__ */
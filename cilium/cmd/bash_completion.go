@@ -28,7 +28,7 @@ var bashCompletionCmd = &cobra.Command{
 	Hidden: true,
 	Run: func(cmd *cobra.Command, args []string) {
 		out := new(bytes.Buffer)
-		rootCmd.GenBashCompletion(out)
+		writeBashCompletion(out, rootCmd)
 		fmt.Println(out.String())
 	},
 }
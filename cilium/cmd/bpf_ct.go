@@ -15,6 +15,11 @@
 package cmd
 
 import (
+	"net"
+
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/u8proto"
+
 	"github.com/spf13/cobra"
 )
 
@@ -27,3 +32,77 @@ var bpfCtCmd = &cobra.Command{
 func init() {
 	bpfCmd.AddCommand(bpfCtCmd)
 }
+
+// ctFilterFlags holds the tuple filter options of the bpf ct commands
+type ctFilterFlags struct {
+	src      string
+	dst      string
+	port     uint16
+	proto    string
+	endpoint string
+}
+
+var ctFilter ctFilterFlags
+
+func addCtFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&ctFilter.src, "src", "", "Only select entries with the given source IP")
+	cmd.Flags().StringVar(&ctFilter.dst, "dst", "", "Only select entries with the given destination IP")
+	cmd.Flags().Uint16Var(&ctFilter.port, "port", 0, "Only select entries with the given source or destination port")
+	cmd.Flags().StringVar(&ctFilter.proto, "proto", "", "Only select entries with the given L4 protocol (tcp, udp, icmp, icmpv6)")
+	cmd.Flags().StringVar(&ctFilter.endpoint, "endpoint", "", "Only select entries from or to an IP of the given endpoint")
+	setFlagCompletion(cmd, "endpoint", completeEndpoints)
+}
+
+// isSet returns true if any of the filter flags has been specified
+func (f *ctFilterFlags) isSet() bool {
+	return f.src != "" || f.dst != "" || f.port != 0 || f.proto != "" || f.endpoint != ""
+}
+
+// getTupleFilter parses the filter flags into a conntrack tuple filter. It
+// returns nil if no filter has been specified.
+func (f *ctFilterFlags) getTupleFilter(cmd *cobra.Command) *ctmap.TupleFilter {
+	if !f.isSet() {
+		return nil
+	}
+
+	filter := &ctmap.TupleFilter{Port: f.port}
+
+	if f.src != "" {
+		if filter.SrcIP = net.ParseIP(f.src); filter.SrcIP == nil {
+			Usagef(cmd, "Invalid source IP %q", f.src)
+		}
+	}
+
+	if f.dst != "" {
+		if filter.DstIP = net.ParseIP(f.dst); filter.DstIP == nil {
+			Usagef(cmd, "Invalid destination IP %q", f.dst)
+		}
+	}
+
+	if f.proto != "" {
+		proto, err := u8proto.ParseProtocol(f.proto)
+		if err != nil {
+			Usagef(cmd, "Invalid protocol: %s", err)
+		}
+		filter.Proto = proto
+	}
+
+	if f.endpoint != "" {
+		ep, err := client.EndpointGet(f.endpoint)
+		if err != nil {
+			Fatalf("Cannot get endpoint %s: %s\n", f.endpoint, err)
+		}
+		filter.MatchIPs = map[string]struct{}{}
+		if ep.Status != nil && ep.Status.Networking != nil {
+			for _, addr := range ep.Status.Networking.Addressing {
+				for _, ip := range []string{addr.IPV4, addr.IPV6} {
+					if parsed := net.ParseIP(ip); parsed != nil {
+						filter.MatchIPs[parsed.String()] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	return filter
+}
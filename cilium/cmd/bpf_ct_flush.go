@@ -26,17 +26,23 @@ import (
 
 // bpfCtFlushCmd represents the bpf_ct_flush command
 var bpfCtFlushCmd = &cobra.Command{
-	Use:    "flush ( <endpoint identifier> | global )",
-	Short:  "Flush all connection tracking entries",
+	Use:   "flush ( <endpoint identifier> | global )",
+	Short: "Flush connection tracking entries",
+	Long: `Flush connection tracking entries.
+
+By default, all entries are flushed. The filter options restrict the flush
+to the selected entries.`,
 	PreRun: requireEndpointIDorGlobal,
 	Run: func(cmd *cobra.Command, args []string) {
 		common.RequireRootPrivilege("cilium bpf ct flush")
-		flushCt(args[0])
+		flushCt(args[0], ctFilter.getTupleFilter(cmd))
 	},
 }
 
 func init() {
 	bpfCtCmd.AddCommand(bpfCtFlushCmd)
+	setArgCompletion(bpfCtFlushCmd, completeEndpoints)
+	addCtFilterFlags(bpfCtFlushCmd)
 }
 
 type dummyEndpoint struct {
@@ -47,7 +53,7 @@ func (d dummyEndpoint) StringID() string {
 	return d.ID
 }
 
-func flushCt(eID string) {
+func flushCt(eID string, filter *ctmap.TupleFilter) {
 	var maps []*ctmap.Map
 	if eID == "global" {
 		maps = ctmap.GlobalMaps(true, true)
@@ -68,7 +74,12 @@ func flushCt(eID string) {
 			continue
 		}
 		defer m.Close()
-		entries := m.Flush()
+		var entries int
+		if filter != nil {
+			entries = m.FlushMatching(filter)
+		} else {
+			entries = m.Flush()
+		}
 		fmt.Printf("Flushed %d entries from %s\n", entries, path)
 	}
 }
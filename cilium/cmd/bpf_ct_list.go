@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 
@@ -33,16 +34,21 @@ var bpfCtListCmd = &cobra.Command{
 	PreRun:  requireEndpointIDorGlobal,
 	Run: func(cmd *cobra.Command, args []string) {
 		common.RequireRootPrivilege("cilium bpf ct list")
-		dumpCt(args[0])
+		if ctFilter.isSet() && command.OutputJSON() {
+			Usagef(cmd, "Filters cannot be combined with --output")
+		}
+		dumpCt(args[0], ctFilter.getTupleFilter(cmd))
 	},
 }
 
 func init() {
 	bpfCtCmd.AddCommand(bpfCtListCmd)
+	setArgCompletion(bpfCtListCmd, completeEndpoints)
+	addCtFilterFlags(bpfCtListCmd)
 	command.AddJSONOutput(bpfCtListCmd)
 }
 
-func dumpCt(eID string) {
+func dumpCt(eID string, filter *ctmap.TupleFilter) {
 	var maps []*ctmap.Map
 	if eID == "global" {
 		maps = ctmap.GlobalMaps(true, true)
@@ -65,14 +71,18 @@ func dumpCt(eID string) {
 		defer m.Close()
 		if command.OutputJSON() {
 			if err := command.PrintOutput(m); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 		} else {
-			out, err := m.DumpEntries()
+			// Entries are streamed to stdout as large CT maps would
+			// otherwise be buffered in memory as a whole.
+			out := bufio.NewWriter(os.Stdout)
+			err := m.WriteEntries(out, filter)
+			fmt.Fprintln(out)
+			out.Flush()
 			if err != nil {
 				Fatalf("Error while dumping BPF Map: %s", err)
 			}
-			fmt.Println(out)
 		}
 	}
 }
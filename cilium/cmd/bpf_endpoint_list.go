@@ -15,8 +15,6 @@
 package cmd
 
 import (
-	"os"
-
 	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/maps/lxcmap"
@@ -38,12 +36,12 @@ var bpfEndpointListCmd = &cobra.Command{
 
 		bpfEndpointList := make(map[string][]string)
 		if err := lxcmap.LXCMap.Dump(bpfEndpointList); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to dump contents of map: %s", err)
 		}
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(bpfEndpointList); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
@@ -17,10 +17,10 @@ package cmd
 import (
 	"fmt"
 	"net"
-	"os"
 	"strings"
 
 	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/maps/ipcache"
 
 	"github.com/hashicorp/go-immutable-radix"
@@ -49,21 +49,25 @@ var bpfIPCacheGetCmd = &cobra.Command{
 		bpfIPCache := dumpIPCache()
 
 		if len(bpfIPCache) == 0 {
-			fmt.Fprintf(os.Stderr, "No entries found.\n")
-			os.Exit(1)
+			Exitf(ExitNotFound, "No entries found.")
 		}
 
 		value, exists := getLPMValue(ip, bpfIPCache)
 
 		if !exists {
-			fmt.Printf("%s does not map to any identity\n", arg)
-			os.Exit(1)
+			Exitf(ExitNotFound, "%s does not map to any identity", arg)
 		}
 
 		v := value.([]string)
 		if len(v) == 0 {
-			fmt.Printf("Unable to retrieve identity for LPM entry %s\n", arg)
-			os.Exit(1)
+			Exitf(ExitNotFound, "Unable to retrieve identity for LPM entry %s", arg)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(map[string][]string{arg: v}); err != nil {
+				Fatalf("Unable to print output: %s", err)
+			}
+			return
 		}
 
 		ids := strings.Join(v, ",")
@@ -73,6 +77,7 @@ var bpfIPCacheGetCmd = &cobra.Command{
 
 func init() {
 	bpfIPCacheCmd.AddCommand(bpfIPCacheGetCmd)
+	command.AddJSONOutput(bpfIPCacheGetCmd)
 }
 
 func dumpIPCache() map[string][]string {
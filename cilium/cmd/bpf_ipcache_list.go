@@ -47,14 +47,12 @@ var bpfIPCacheListCmd = &cobra.Command{
 
 		bpfIPCacheList := make(map[string][]string)
 		if err := ipcache.IPCache.Dump(bpfIPCacheList); err != nil {
-			fmt.Fprintf(os.Stderr, "error dumping contents of map: %s\n", err)
-			os.Exit(1)
+			Fatalf("Unable to dump contents of map: %s", err)
 		}
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(bpfIPCacheList); err != nil {
-				fmt.Fprintf(os.Stderr, "error getting output of map in JSON: %s\n", err)
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
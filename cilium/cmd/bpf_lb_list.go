@@ -15,8 +15,6 @@
 package cmd
 
 import (
-	"os"
-
 	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/maps/lbmap"
@@ -45,24 +43,24 @@ var bpfLBListCmd = &cobra.Command{
 		if listRevNAT {
 			firstTitle = idTitle
 			if err := lbmap.RevNat4Map.Dump(serviceList); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to dump contents of map: %s", err)
 			}
 			if err := lbmap.RevNat6Map.Dump(serviceList); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to dump contents of map: %s", err)
 			}
 		} else {
 			firstTitle = serviceAddressTitle
 			if err := lbmap.Service4Map.Dump(serviceList); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to dump contents of map: %s", err)
 			}
 			if err := lbmap.Service6Map.Dump(serviceList); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to dump contents of map: %s", err)
 			}
 		}
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(serviceList); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
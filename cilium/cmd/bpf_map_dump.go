@@ -0,0 +1,189 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+// mapEntry is a decoded entry of a BPF map
+type mapEntry struct {
+	Key   bpf.MapKey   `json:"key"`
+	Value bpf.MapValue `json:"value"`
+}
+
+// mapDump is the metadata and the decoded entries of a BPF map
+type mapDump struct {
+	Info    bpf.MapInfo `json:"info"`
+	Entries []mapEntry  `json:"entries"`
+}
+
+var bpfMapDumpCmd = &cobra.Command{
+	Use:   "dump <path>",
+	Short: "Dump the entries of a pinned BPF map",
+	Long: `Dump the metadata and the entries of the BPF map pinned at the given path.
+Relative paths are resolved against the directory where the agent pins its maps.
+
+The entries of the endpoint, ipcache, connection tracking and policy maps are
+decoded. The keys and values of other maps are printed in hexadecimal.
+
+With --snapshot, the entries of a snapshot file written by
+"cilium bpf map snapshot" are dumped instead of a pinned map.`,
+	Example: `  cilium bpf map dump cilium_lxc
+  cilium bpf map dump /sys/fs/bpf/tc/globals/cilium_policy_1234
+  cilium bpf map dump --snapshot /tmp/ct4.snap`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			Usagef(cmd, "Missing map path")
+		}
+
+		var (
+			dump *mapDump
+			err  error
+		)
+		if dumpSnapshot {
+			dump, err = dumpSnapshotFile(args[0])
+		} else {
+			common.RequireRootPrivilege("cilium bpf map dump")
+			dump, err = dumpMapPath(args[0])
+		}
+		if err != nil {
+			Fatalf("Unable to dump map %s: %s", args[0], err)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(dump); err != nil {
+				Fatalf("Unable to print output: %s", err)
+			}
+			return
+		}
+		printMapDump(os.Stdout, dump)
+	},
+}
+
+var dumpSnapshot bool
+
+func init() {
+	bpfMapCmd.AddCommand(bpfMapDumpCmd)
+	bpfMapDumpCmd.Flags().BoolVar(&dumpSnapshot, "snapshot", false, "Dump the entries of a snapshot file")
+	command.AddJSONOutput(bpfMapDumpCmd)
+}
+
+// dumpMapPath returns the metadata and the entries of the map pinned at path.
+func dumpMapPath(path string) (*mapDump, error) {
+	m, err := bpf.OpenMap(path)
+	if err != nil {
+		return nil, err
+	}
+	defer m.Close()
+
+	return dumpPinnedMap(m)
+}
+
+// dumpSnapshotFile returns the metadata and the entries of the snapshot file
+// at path sorted by key.
+func dumpSnapshotFile(path string) (*mapDump, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	snapshot, err := bpf.NewSnapshotReader(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return dumpSnapshotEntries(snapshot)
+}
+
+// dumpSnapshotEntries returns the metadata and the decoded entries of the
+// snapshot sorted by key.
+func dumpSnapshotEntries(snapshot *bpf.SnapshotReader) (*mapDump, error) {
+	dump := &mapDump{Info: snapshot.Info, Entries: []mapEntry{}}
+	for {
+		key, value, err := snapshot.NextDecoded()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		dump.Entries = append(dump.Entries, mapEntry{Key: key, Value: value})
+	}
+
+	sortMapEntries(dump.Entries)
+	return dump, nil
+}
+
+// dumpPinnedMap returns the metadata and the entries of m sorted by key.
+func dumpPinnedMap(m *bpf.Map) (*mapDump, error) {
+	switch m.MapType {
+	case bpf.MapTypePerCPUHash, bpf.MapTypePerCPUArray, bpf.MapTypeLRUPerCPUHash:
+		return nil, fmt.Errorf("dumping maps of type %s is not supported", m.MapType)
+	}
+
+	dump := &mapDump{Info: m.MapInfo, Entries: []mapEntry{}}
+	err := m.DumpWithCallback(func(key bpf.MapKey, value bpf.MapValue) {
+		dump.Entries = append(dump.Entries, mapEntry{Key: key, Value: value})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortMapEntries(dump.Entries)
+	return dump, nil
+}
+
+// sortMapEntries sorts entries by the string representation of their keys
+func sortMapEntries(entries []mapEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key.String() < entries[j].Key.String()
+	})
+}
+
+// printMapDump prints the metadata of the map followed by a table of its
+// entries to w.
+func printMapDump(w io.Writer, dump *mapDump) {
+	tab := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tab, "Type:\t%s\n", dump.Info.MapType)
+	fmt.Fprintf(tab, "Key size:\t%d\n", dump.Info.KeySize)
+	fmt.Fprintf(tab, "Value size:\t%d\n", dump.Info.ValueSize)
+	fmt.Fprintf(tab, "Max entries:\t%d\n", dump.Info.MaxEntries)
+	fmt.Fprintf(tab, "Entries:\t%d\n", len(dump.Entries))
+	tab.Flush()
+
+	if len(dump.Entries) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	tab = tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintln(tab, "KEY\tVALUE")
+	for _, e := range dump.Entries {
+		fmt.Fprintf(tab, "%s\t%s\n", e.Key, e.Value)
+	}
+	tab.Flush()
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/cilium/cilium/pkg/bpf"
+
+	. "gopkg.in/check.v1"
+)
+
+type BPFMapDumpSuite struct{}
+
+var _ = Suite(&BPFMapDumpSuite{})
+
+func (s *BPFMapDumpSuite) TestPrintMapDump(c *C) {
+	dump := &mapDump{
+		Info: bpf.MapInfo{
+			MapType:    bpf.MapTypeHash,
+			KeySize:    4,
+			ValueSize:  2,
+			MaxEntries: 1024,
+		},
+		Entries: []mapEntry{
+			{Key: bpf.RawKey{0x0a, 0x00, 0x00, 0x01}, Value: bpf.RawValue{0xff, 0x01}},
+		},
+	}
+
+	var buf bytes.Buffer
+	printMapDump(&buf, dump)
+	c.Assert(buf.String(), Equals, "Type:          Hash\n"+
+		"Key size:      4\n"+
+		"Value size:    2\n"+
+		"Max entries:   1024\n"+
+		"Entries:       1\n"+
+		"\n"+
+		"KEY        VALUE\n"+
+		"0a000001   ff01\n")
+
+	buf.Reset()
+	dump.Entries = nil
+	printMapDump(&buf, dump)
+	c.Assert(buf.String(), Matches, "(?s).*Entries:       0\n")
+}
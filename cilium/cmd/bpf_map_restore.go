@@ -0,0 +1,82 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/bpf"
+
+	"github.com/spf13/cobra"
+)
+
+var bpfMapRestoreCmd = &cobra.Command{
+	Use:   "restore <file> [<path>]",
+	Short: "Restore the entries of a pinned BPF map from a snapshot",
+	Long: `Insert all entries of a snapshot written by "cilium bpf map snapshot" into the
+BPF map pinned at the given path. If no path is given, the entries are restored
+into the pinned map with the name of the map the snapshot was taken of.
+Existing entries with the same key are overwritten, other entries are kept.
+
+The key and value size of the map must match the snapshot. Entries are
+restored as they are: connection tracking entries carry timestamps relative to
+the boot time of the host and must therefore only be restored on the host the
+snapshot was taken on and before it is rebooted.`,
+	Example: `  cilium bpf map restore /tmp/ct4.snap
+  cilium bpf map restore /tmp/ct4.snap cilium_ct4_global`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 || len(args) > 2 {
+			Usagef(cmd, "Missing snapshot file")
+		}
+		common.RequireRootPrivilege("cilium bpf map restore")
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			Fatalf("Unable to open snapshot %s: %s", args[0], err)
+		}
+		defer f.Close()
+
+		snapshot, err := bpf.NewSnapshotReader(f)
+		if err != nil {
+			Fatalf("Unable to read snapshot %s: %s", args[0], err)
+		}
+
+		path := snapshot.Name
+		if len(args) == 2 {
+			path = args[1]
+		}
+
+		m, err := bpf.OpenMap(path)
+		if err != nil {
+			Fatalf("Unable to open map %s: %s", path, err)
+		}
+		defer m.Close()
+
+		restored, failed, err := m.RestoreSnapshot(snapshot)
+		if err != nil {
+			Fatalf("Unable to restore map %s after %d entries: %s", path, restored, err)
+		}
+		fmt.Printf("Restored %d entries of map %s\n", restored, path)
+		if failed > 0 {
+			Fatalf("Unable to restore %d entries of map %s", failed, path)
+		}
+	},
+}
+
+func init() {
+	bpfMapCmd.AddCommand(bpfMapRestoreCmd)
+}
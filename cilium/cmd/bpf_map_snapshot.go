@@ -0,0 +1,84 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/bpf"
+
+	"github.com/spf13/cobra"
+)
+
+var bpfMapSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <path> <file>",
+	Short: "Write a snapshot of the entries of a pinned BPF map to a file",
+	Long: `Write the metadata and the raw entries of the BPF map pinned at the given path
+to a file. Relative paths are resolved against the directory where the agent
+pins its maps.
+
+Snapshots can be inspected with "cilium bpf map dump --snapshot" and restored
+with "cilium bpf map restore", e.g. to analyze the state of the connection
+tracking, ipcache or load balancer maps after a failure, or to carry the
+connection tracking state over a planned restart of the agent.`,
+	Example: `  cilium bpf map snapshot cilium_ct4_global /tmp/ct4.snap
+  cilium bpf map snapshot cilium_ipcache /tmp/ipcache.snap`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			Usagef(cmd, "Missing map path or file")
+		}
+		common.RequireRootPrivilege("cilium bpf map snapshot")
+
+		m, err := bpf.OpenMap(args[0])
+		if err != nil {
+			Fatalf("Unable to open map %s: %s", args[0], err)
+		}
+		defer m.Close()
+
+		n, err := snapshotMap(m, args[1])
+		if err != nil {
+			Fatalf("Unable to write snapshot of map %s: %s", args[0], err)
+		}
+		fmt.Printf("Wrote %d entries of map %s to %s\n", n, args[0], args[1])
+	},
+}
+
+func init() {
+	bpfMapCmd.AddCommand(bpfMapSnapshotCmd)
+}
+
+// snapshotMap writes a snapshot of m to the file at path. The file is only
+// created if the snapshot succeeds.
+func snapshotMap(m *bpf.Map, path string) (int, error) {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp)
+
+	n, err := m.WriteSnapshot(f)
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+
+	return n, os.Rename(tmp, path)
+}
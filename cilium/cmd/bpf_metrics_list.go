@@ -44,16 +44,19 @@ var bpfMetricsListCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		common.RequireRootPrivilege("cilium bpf metrics list")
 
-		bpfMetricsList := make(map[string][]string)
-		if err := metricsmap.Metrics.Dump(bpfMetricsList); err != nil {
-			fmt.Fprintf(os.Stderr, "error dumping contents of map: %s\n", err)
-			os.Exit(1)
+		totals, err := metricsmap.DumpTotals()
+		if err != nil {
+			Fatalf("Unable to dump contents of map: %s", err)
+		}
+
+		bpfMetricsList := make(map[string][]string, len(totals))
+		for key, value := range totals {
+			bpfMetricsList[key.String()] = []string{value.String()}
 		}
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(bpfMetricsList); err != nil {
-				fmt.Fprintf(os.Stderr, "error getting output of map in JSON: %s\n", err)
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
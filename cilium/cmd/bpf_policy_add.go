@@ -22,8 +22,20 @@ import (
 
 // bpfPolicyAddCmd represents the bpf_policy_add command
 var bpfPolicyAddCmd = &cobra.Command{
-	Use:    "add <endpoint id> <traffic-direction> <identity> [port/proto]",
-	Short:  "Add/update policy entry",
+	Use:   "add <endpoint id> <traffic-direction> <identity|CIDR|labels> [port[-port][/proto][,...]]",
+	Short: "Add/update policy entry",
+	Long: `The peer may be given as a numeric or reserved identity, as a CIDR or IP
+address, which is resolved to the identity allocated for it, or as a comma
+separated list of labels, which is resolved to all identities carrying all of
+these labels. Resolving a CIDR or labels requires a running agent.
+
+A port range is added as the smallest set of masked port entries covering it,
+e.g. 80-90 is added as 80-87, 88-89 and 90. Port range entries are only
+looked up by endpoints with the PolicyPortRanges option enabled, which is
+enabled through the agent when a range is added.`,
+	Example: `  cilium bpf policy add 1234 ingress 5678 80-90/tcp,443/tcp
+  cilium bpf policy add 1234 egress 10.0.0.0/8 443/tcp
+  cilium bpf policy add 1234 ingress k8s:app=frontend,k8s:io.kubernetes.pod.namespace=default 80/tcp`,
 	PreRun: requireEndpointID,
 	Run: func(cmd *cobra.Command, args []string) {
 		common.RequireRootPrivilege("cilium bpf policy add")
@@ -33,4 +45,5 @@ var bpfPolicyAddCmd = &cobra.Command{
 
 func init() {
 	bpfPolicyCmd.AddCommand(bpfPolicyAddCmd)
+	setArgCompletion(bpfPolicyAddCmd, completeEndpoints)
 }
@@ -22,8 +22,15 @@ import (
 
 // bpfPolicyDeleteCmd represents the bpf_policy_delete command
 var bpfPolicyDeleteCmd = &cobra.Command{
-	Use:    "delete <endpoint id> <identity> [port/proto]",
-	Short:  "Delete a policy entry",
+	Use:   "delete <endpoint id> <traffic-direction> <identity|CIDR|labels> [port[-port][/proto][,...]]",
+	Short: "Delete a policy entry",
+	Long: `The peer may be given as a numeric or reserved identity, as a CIDR or IP
+address, which is resolved to the identity allocated for it, or as a comma
+separated list of labels, which is resolved to all identities carrying all of
+these labels. Resolving a CIDR or labels requires a running agent.`,
+	Example: `  cilium bpf policy delete 1234 ingress 5678 80-90/tcp,443/tcp
+  cilium bpf policy delete 1234 egress 10.0.0.0/8 443/tcp
+  cilium bpf policy delete 1234 ingress k8s:app=frontend,k8s:io.kubernetes.pod.namespace=default 80/tcp`,
 	PreRun: requireEndpointID,
 	Run: func(cmd *cobra.Command, args []string) {
 		common.RequireRootPrivilege("cilium bpf policy delete")
@@ -33,4 +40,5 @@ var bpfPolicyDeleteCmd = &cobra.Command{
 
 func init() {
 	bpfPolicyCmd.AddCommand(bpfPolicyDeleteCmd)
+	setArgCompletion(bpfPolicyDeleteCmd, completeEndpoints)
 }
@@ -21,12 +21,15 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/color"
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/maps/policymap"
@@ -36,8 +39,10 @@ import (
 )
 
 var (
-	printIDs bool
-	allList  bool
+	printIDs      bool
+	allList       bool
+	watchPolicy   bool
+	watchInterval time.Duration
 )
 
 // bpfPolicyListCmd represents the bpf_policy_list command
@@ -51,14 +56,24 @@ var bpfPolicyListCmd = &cobra.Command{
 			return
 		}
 		requireEndpointID(cmd, args)
+		if watchPolicy {
+			if allList || command.OutputJSON() {
+				Usagef(cmd, "--watch cannot be combined with --all or --output")
+			}
+			watchMap(policyMapPath(args))
+			return
+		}
 		listMap(args)
 	},
 }
 
 func init() {
 	bpfPolicyCmd.AddCommand(bpfPolicyListCmd)
+	setArgCompletion(bpfPolicyListCmd, completeEndpoints)
 	bpfPolicyListCmd.Flags().BoolVarP(&printIDs, "numeric", "n", false, "Do not resolve IDs")
 	bpfPolicyListCmd.Flags().BoolVarP(&allList, "all", "", false, "Dump all policy maps")
+	bpfPolicyListCmd.Flags().BoolVarP(&watchPolicy, "watch", "w", false, "Watch the policy map and print added and removed entries")
+	bpfPolicyListCmd.Flags().DurationVar(&watchInterval, "watch-interval", time.Second, "Interval at which the policy map is polled in watch mode")
 	command.AddJSONOutput(bpfPolicyListCmd)
 }
 
@@ -81,6 +96,12 @@ func listAllMaps() {
 }
 
 func listMap(args []string) {
+	dumpMap(policyMapPath(args))
+}
+
+// policyMapPath returns the path to the policy map of the endpoint identified
+// by the first element of args.
+func policyMapPath(args []string) string {
 	lbl := args[0]
 
 	if lbl != "" {
@@ -91,12 +112,11 @@ func listMap(args []string) {
 		Fatalf("Need ID or label\n")
 	}
 
-	file := bpf.MapPath(policymap.MapName + lbl)
-	dumpMap(file)
+	return bpf.MapPath(policymap.MapName + lbl)
 }
 
-func dumpMap(file string) {
-
+// readMap returns the sorted contents of the policy map at file.
+func readMap(file string) policymap.PolicyEntriesDump {
 	fd, err := bpf.ObjGet(file)
 	if err != nil {
 		Fatalf("%s\n", err)
@@ -109,10 +129,15 @@ func dumpMap(file string) {
 		Fatalf("Error while opening bpf Map: %s\n", err)
 	}
 	sort.Slice(statsMap, statsMap.Less)
+	return statsMap
+}
+
+func dumpMap(file string) {
+	statsMap := readMap(file)
 
 	if command.OutputJSON() {
 		if err := command.PrintOutput(statsMap); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to print output: %s", err)
 		}
 	} else {
 		w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
@@ -125,6 +150,21 @@ func dumpMap(file string) {
 
 }
 
+// formatPolicyPort returns the port and protocol of key, a port range for
+// keys matching a masked port.
+func formatPolicyPort(key policymap.PolicyKey) string {
+	if key.DestPort == 0 && key.PortWildcardBits() == 0 {
+		return models.PortProtocolANY
+	}
+	proto := u8proto.U8proto(key.Nexthdr)
+	if key.PortWildcardBits() != 0 {
+		first, last := key.PortRange()
+		return fmt.Sprintf("%d-%d/%s", first, last, proto.String())
+	}
+	dport := byteorder.NetworkToHost(key.DestPort).(uint16)
+	return fmt.Sprintf("%d/%s", dport, proto.String())
+}
+
 func formatMap(w io.Writer, statsMap []policymap.PolicyEntryDump) {
 	const (
 		trafficDirectionTitle = "DIRECTION"
@@ -157,14 +197,9 @@ func formatMap(w io.Writer, statsMap []policymap.PolicyEntryDump) {
 	}
 	for _, stat := range statsMap {
 		id := identity.NumericIdentity(stat.Key.Identity)
-		trafficDirection := policymap.TrafficDirection(stat.Key.TrafficDirection)
+		trafficDirection := policymap.TrafficDirection(stat.Key.GetDirection())
 		trafficDirectionString := trafficDirection.String()
-		port := models.PortProtocolANY
-		if stat.Key.DestPort != 0 {
-			dport := byteorder.NetworkToHost(stat.Key.DestPort).(uint16)
-			proto := u8proto.U8proto(stat.Key.Nexthdr)
-			port = fmt.Sprintf("%d/%s", dport, proto.String())
-		}
+		port := formatPolicyPort(stat.Key)
 		proxyPort := "NONE"
 		if stat.ProxyPort != 0 {
 			proxyPort = strconv.FormatUint(uint64(byteorder.NetworkToHost(stat.ProxyPort).(uint16)), 10)
@@ -186,3 +221,80 @@ func formatMap(w io.Writer, statsMap []policymap.PolicyEntryDump) {
 		}
 	}
 }
+
+// diffPolicyEntries returns the entries present in cur but not in old
+// (added) and the entries present in old but not in cur (removed). Entries
+// are compared by key and proxy port; counters are ignored.
+func diffPolicyEntries(old, cur []policymap.PolicyEntryDump) (added, removed []policymap.PolicyEntryDump) {
+	oldEntries := make(map[policymap.PolicyKey]uint16, len(old))
+	for _, e := range old {
+		oldEntries[e.Key] = e.ProxyPort
+	}
+	curEntries := make(map[policymap.PolicyKey]uint16, len(cur))
+	for _, e := range cur {
+		curEntries[e.Key] = e.ProxyPort
+	}
+
+	for _, e := range cur {
+		if proxyPort, ok := oldEntries[e.Key]; !ok || proxyPort != e.ProxyPort {
+			added = append(added, e)
+		}
+	}
+	for _, e := range old {
+		if proxyPort, ok := curEntries[e.Key]; !ok || proxyPort != e.ProxyPort {
+			removed = append(removed, e)
+		}
+	}
+	return
+}
+
+// watchMap polls the policy map at file every watchInterval and prints the
+// entries which have been added or removed since the previous poll.
+func watchMap(file string) {
+	labelsID := map[identity.NumericIdentity]string{}
+	resolve := func(id identity.NumericIdentity) string {
+		if printIDs {
+			return id.StringID()
+		}
+		if lbls, ok := labelsID[id]; ok {
+			return lbls
+		}
+		lbls := id.StringID()
+		if model, err := client.IdentityGet(id.StringID()); err != nil {
+			fmt.Fprintf(os.Stderr, "Was impossible to retrieve label ID %d: %s\n", id, err)
+		} else if ident := identity.NewIdentityFromModel(model); ident != nil && len(ident.Labels) > 0 {
+			lbls = strings.Join(ident.Labels.GetPrintableModel(), ",")
+		}
+		labelsID[id] = lbls
+		return lbls
+	}
+
+	var previous []policymap.PolicyEntryDump
+	for {
+		current := readMap(file)
+		added, removed := diffPolicyEntries(previous, current)
+
+		w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
+		for _, e := range removed {
+			fmt.Fprintf(w, "%s\n", color.Red(formatPolicyDelta("-", e, resolve)))
+		}
+		for _, e := range added {
+			fmt.Fprintf(w, "%s\n", color.Green(formatPolicyDelta("+", e, resolve)))
+		}
+		w.Flush()
+
+		previous = current
+		time.Sleep(watchInterval)
+	}
+}
+
+func formatPolicyDelta(prefix string, e policymap.PolicyEntryDump, resolve func(identity.NumericIdentity) string) string {
+	port := formatPolicyPort(e.Key)
+	proxyPort := "NONE"
+	if e.ProxyPort != 0 {
+		proxyPort = strconv.FormatUint(uint64(byteorder.NetworkToHost(e.ProxyPort).(uint16)), 10)
+	}
+	direction := policymap.TrafficDirection(e.Key.GetDirection()).String()
+	return fmt.Sprintf("%s %s\t%s\t%s\t%s\t", prefix, direction,
+		resolve(identity.NumericIdentity(e.Key.Identity)), port, proxyPort)
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/cilium/cilium/pkg/maps/policymap"
+
+	. "gopkg.in/check.v1"
+)
+
+type BPFPolicyGetSuite struct{}
+
+var _ = Suite(&BPFPolicyGetSuite{})
+
+func (s *BPFPolicyGetSuite) TestDiffPolicyEntries(c *C) {
+	entry := func(id uint32, port, proxyPort uint16, packets uint64) policymap.PolicyEntryDump {
+		return policymap.PolicyEntryDump{
+			Key:         policymap.PolicyKey{Identity: id, DestPort: port, Nexthdr: 6},
+			PolicyEntry: policymap.PolicyEntry{ProxyPort: proxyPort, Packets: packets},
+		}
+	}
+
+	added, removed := diffPolicyEntries(nil, nil)
+	c.Assert(added, HasLen, 0)
+	c.Assert(removed, HasLen, 0)
+
+	old := []policymap.PolicyEntryDump{entry(1, 80, 0, 1), entry(2, 80, 0, 1), entry(3, 80, 0, 1)}
+	cur := []policymap.PolicyEntryDump{entry(1, 80, 0, 10), entry(3, 80, 1234, 1), entry(4, 443, 0, 0)}
+
+	added, removed = diffPolicyEntries(nil, old)
+	c.Assert(added, DeepEquals, old)
+	c.Assert(removed, HasLen, 0)
+
+	added, removed = diffPolicyEntries(old, cur)
+	c.Assert(added, DeepEquals, []policymap.PolicyEntryDump{entry(3, 80, 1234, 1), entry(4, 443, 0, 0)})
+	c.Assert(removed, DeepEquals, []policymap.PolicyEntryDump{entry(2, 80, 0, 1), entry(3, 80, 0, 1)})
+}
@@ -15,8 +15,6 @@
 package cmd
 
 import (
-	"os"
-
 	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/maps/proxymap"
@@ -38,15 +36,15 @@ var bpfProxyListCmd = &cobra.Command{
 
 		proxyList := make(map[string][]string)
 		if err := proxymap.Proxy4Map.Dump(proxyList); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to dump contents of map: %s", err)
 		}
 		if err := proxymap.Proxy6Map.Dump(proxyList); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to dump contents of map: %s", err)
 		}
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(proxyList); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
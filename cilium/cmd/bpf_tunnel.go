@@ -15,6 +15,10 @@
 package cmd
 
 import (
+	"fmt"
+	"net"
+	"strings"
+
 	"github.com/spf13/cobra"
 )
 
@@ -26,3 +30,20 @@ var bpfTunnelCmd = &cobra.Command{
 func init() {
 	bpfCmd.AddCommand(bpfTunnelCmd)
 }
+
+// parseTunnelPrefix parses the key of a tunnel map entry, either the network
+// address of a node's allocation prefix or the prefix in CIDR notation.
+func parseTunnelPrefix(s string) (net.IP, error) {
+	if strings.Contains(s, "/") {
+		_, prefix, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		return prefix.IP, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("unable to parse prefix '%s'", s)
+	}
+	return ip, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/maps/tunnel"
+
+	"github.com/spf13/cobra"
+)
+
+var bpfTunnelDeleteCmd = &cobra.Command{
+	Use:   "delete <prefix>...",
+	Short: "Delete tunnel endpoint entries",
+	Long: `Delete the entries of the tunnel endpoint map for the given prefixes. A
+prefix is the network address of the allocation prefix of a node, or the
+prefix in CIDR notation.`,
+	Example: "  cilium bpf tunnel delete 10.2.0.0 f00d::a0f:0:0:0/96",
+	Run: func(cmd *cobra.Command, args []string) {
+		common.RequireRootPrivilege("cilium bpf tunnel delete")
+
+		if len(args) == 0 {
+			Usagef(cmd, "At least one prefix must be specified")
+		}
+
+		prefixes := make([]net.IP, 0, len(args))
+		for _, arg := range args {
+			prefix, err := parseTunnelPrefix(arg)
+			if err != nil {
+				Usagef(cmd, "%s", err)
+			}
+			prefixes = append(prefixes, prefix)
+		}
+
+		failed := 0
+		for _, prefix := range prefixes {
+			if err := tunnel.DeleteTunnelEndpoint(prefix); err != nil {
+				fmt.Fprintf(os.Stderr, "Unable to delete tunnel entry %s: %s\n", prefix, err)
+				failed++
+				continue
+			}
+			fmt.Printf("Deleted tunnel entry %s\n", prefix)
+		}
+
+		switch {
+		case failed > 0 && failed == len(prefixes):
+			Exitf(ExitFailure, "Unable to delete any tunnel entry")
+		case failed > 0:
+			Exitf(ExitPartialSuccess, "Unable to delete %d/%d tunnel entries", failed, len(prefixes))
+		}
+	},
+}
+
+func init() {
+	bpfTunnelCmd.AddCommand(bpfTunnelDeleteCmd)
+}
@@ -15,8 +15,6 @@
 package cmd
 
 import (
-	"os"
-
 	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/maps/tunnel"
@@ -37,12 +35,12 @@ var bpfTunnelListCmd = &cobra.Command{
 
 		tunnelList := make(map[string][]string)
 		if err := tunnel.TunnelMap.Dump(tunnelList); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to dump contents of map: %s", err)
 		}
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(tunnelList); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
@@ -0,0 +1,46 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type BPFTunnelSuite struct{}
+
+var _ = Suite(&BPFTunnelSuite{})
+
+func (s *BPFTunnelSuite) TestParseTunnelPrefix(c *C) {
+	for _, t := range []struct {
+		input  string
+		prefix string
+	}{
+		{"10.2.0.0", "10.2.0.0"},
+		{"10.2.0.0/16", "10.2.0.0"},
+		// The host bits of a CIDR are masked
+		{"10.2.3.4/16", "10.2.0.0"},
+		{"f00d::a0f:0:0:0/96", "f00d::a0f:0:0:0"},
+		{"f00d::a0f:0:0:0", "f00d::a0f:0:0:0"},
+	} {
+		prefix, err := parseTunnelPrefix(t.input)
+		c.Assert(err, IsNil, Commentf("input %s", t.input))
+		c.Assert(prefix.String(), Equals, t.prefix, Commentf("input %s", t.input))
+	}
+
+	for _, input := range []string{"", "10.2.0", "10.2.0.0/33", "node1"} {
+		_, err := parseTunnelPrefix(input)
+		c.Assert(err, NotNil, Commentf("input %s", input))
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/maps/tunnel"
+
+	"github.com/spf13/cobra"
+)
+
+var bpfTunnelUpdateCmd = &cobra.Command{
+	Use:   "update <prefix> <endpoint>",
+	Short: "Create or update a tunnel endpoint entry",
+	Long: `Create or update the entry of the tunnel endpoint map for the prefix. Packets
+to the prefix are encapsulated and sent to the endpoint IP. The prefix is the
+network address of the allocation prefix of a node, or the prefix in CIDR
+notation.
+
+The agent manages this map based on the nodes in the cluster and may overwrite
+the entry on the next update of the node.`,
+	Example: "  cilium bpf tunnel update 10.2.0.0/16 192.168.33.12",
+	Run: func(cmd *cobra.Command, args []string) {
+		common.RequireRootPrivilege("cilium bpf tunnel update")
+
+		if len(args) != 2 {
+			Usagef(cmd, "Prefix and endpoint must be specified")
+		}
+
+		prefix, err := parseTunnelPrefix(args[0])
+		if err != nil {
+			Usagef(cmd, "%s", err)
+		}
+
+		endpoint := net.ParseIP(args[1])
+		if endpoint == nil {
+			Usagef(cmd, "Unable to parse endpoint IP '%s'", args[1])
+		}
+
+		if err := tunnel.SetTunnelEndpoint(prefix, endpoint); err != nil {
+			Fatalf("Unable to update tunnel entry: %s", err)
+		}
+		fmt.Printf("%s => %s\n", prefix, endpoint)
+	},
+}
+
+func init() {
+	bpfTunnelCmd.AddCommand(bpfTunnelUpdateCmd)
+}
@@ -16,9 +16,11 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/cilium/cilium/common"
@@ -30,17 +32,34 @@ import (
 	"github.com/vishvananda/netlink"
 )
 
-// configCmd represents the config command
+// cleanupCmd represents the cleanup command
 var cleanupCmd = &cobra.Command{
 	Use:   "cleanup",
 	Short: "Reset the agent state",
+	Long: `Remove the state the agent leaves on the node. The resources to remove can
+be limited with --resources to the following classes:
+
+  bpf     BPF maps of Cilium
+  links   Cilium and endpoint network interfaces
+  routes  Routes via Cilium and endpoint network interfaces
+  cni     CNI configuration of Cilium
+  state   Endpoint state and library code of the agent
+
+With --dry-run, the resources which would be removed are listed and nothing
+is removed.`,
+	Example: `  cilium cleanup --dry-run
+  cilium cleanup --resources bpf,routes`,
 	Run: func(cmd *cobra.Command, args []string) {
 		common.RequireRootPrivilege("cleanup")
 		runCleanup()
 	},
 }
 
-var force bool
+var (
+	force            bool
+	cleanupDryRun    bool
+	cleanupResources []string
+)
 
 const (
 	ciliumLinkPrefix = "cilium_"
@@ -51,25 +70,96 @@ const (
 	cniConfigV3      = "/etc/cni/net.d/05-cilium-cni.conf"
 )
 
+// Resource classes which can be selected with --resources
+const (
+	cleanupBPF    = "bpf"
+	cleanupLinks  = "links"
+	cleanupRoutes = "routes"
+	cleanupCNI    = "cni"
+	cleanupState  = "state"
+)
+
+var cleanupClasses = []string{cleanupBPF, cleanupLinks, cleanupRoutes, cleanupCNI, cleanupState}
+
 func init() {
 	rootCmd.AddCommand(cleanupCmd)
 	cleanupCmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation")
+	cleanupCmd.Flags().BoolVar(&cleanupDryRun, "dry-run", false, "List the resources which would be removed without removing them")
+	cleanupCmd.Flags().StringSliceVar(&cleanupResources, "resources", cleanupClasses, "Resource classes to remove")
+}
+
+// parseCleanupResources returns the set of resource classes in resources.
+func parseCleanupResources(resources []string) (map[string]bool, error) {
+	selected := map[string]bool{}
+	for _, r := range resources {
+		r = strings.ToLower(strings.TrimSpace(r))
+		valid := false
+		for _, class := range cleanupClasses {
+			if r == class {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown resource class %q, use one of %s",
+				r, strings.Join(cleanupClasses, ", "))
+		}
+		selected[r] = true
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no resource class selected")
+	}
+	return selected, nil
+}
+
+// cleanupPlan is the list of resources which are removed by cleanup
+type cleanupPlan struct {
+	maps   []string
+	links  map[int]netlink.Link
+	routes map[int]netlink.Route
+	cni    []string
+	dirs   []string
+}
+
+// empty returns true if there is nothing to remove.
+func (p *cleanupPlan) empty() bool {
+	return len(p.maps) == 0 && len(p.links) == 0 && len(p.routes) == 0 &&
+		len(p.cni) == 0 && len(p.dirs) == 0
 }
 
 func runCleanup() {
+	selected, err := parseCleanupResources(cleanupResources)
+	if err != nil {
+		Exitf(ExitUsage, "%s", err)
+	}
+
 	// Abort if the agent is running, err == nil is handled correctly by Stat.
 	if _, err := os.Stat(defaults.PidFilePath); !os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Agent should not be running when cleaning up\n"+
-			"Found pidfile %s\n", defaults.PidFilePath)
-		os.Exit(1)
+		if !cleanupDryRun {
+			Exitf(ExitFailure, "Agent should not be running when cleaning up\n"+
+				"Found pidfile %s", defaults.PidFilePath)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: Agent is running, found pidfile %s\n", defaults.PidFilePath)
 	}
 
-	routes, links, err := findRoutesAndLinks()
+	plan, err := findCleanupResources(selected)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 	}
 
-	showWhatWillBeRemoved(routes, links)
+	if plan.empty() {
+		fmt.Printf("Nothing to remove\n")
+		return
+	}
+
+	if cleanupDryRun {
+		fmt.Printf("The following resources would be removed:\n")
+		printCleanupPlan(os.Stdout, plan)
+		return
+	}
+
+	fmt.Printf("Warning: Destructive operation. You are about to remove:\n")
+	printCleanupPlan(os.Stdout, plan)
 	if !force && !confirmCleanup() {
 		return
 	}
@@ -77,40 +167,114 @@ func runCleanup() {
 	// ENOENT and similar errors are ignored. Should print all other
 	// errors seen, but continue.  So that one remove function does not
 	// prevent the remaining from running.
-	type cleanupFunc func() error
-	checks := []cleanupFunc{removeAllMaps, removeDirs, removeCNI}
+	type cleanupFunc func(p *cleanupPlan) error
+	checks := []cleanupFunc{removeMaps, removeDirs, removeCNI, removeRoutesAndLinks}
 	for _, clean := range checks {
-		if err := clean(); err != nil {
+		if err := clean(plan); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 		}
 	}
+}
 
-	if err := removeRoutesAndLinks(routes, links); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+// findCleanupResources returns the resources of the selected classes which
+// exist on the node.
+func findCleanupResources(selected map[string]bool) (*cleanupPlan, error) {
+	plan := &cleanupPlan{
+		links:  map[int]netlink.Link{},
+		routes: map[int]netlink.Route{},
 	}
+	var errs []string
+
+	if selected[cleanupBPF] {
+		maps, err := findMaps()
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		plan.maps = maps
+	}
+
+	if selected[cleanupLinks] || selected[cleanupRoutes] {
+		routes, links, err := findRoutesAndLinks()
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		if selected[cleanupRoutes] {
+			plan.routes = routes
+		}
+		if selected[cleanupLinks] {
+			plan.links = links
+		}
+	}
+
+	if selected[cleanupCNI] {
+		plan.cni = existingPaths([]string{cniConfigV1, cniConfigV2, cniConfigV3})
+	}
+
+	if selected[cleanupState] {
+		plan.dirs = existingPaths([]string{defaults.RuntimePath, defaults.LibraryPath})
+	}
+
+	if len(errs) > 0 {
+		return plan, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return plan, nil
 }
 
-func showWhatWillBeRemoved(routes map[int]netlink.Route, links map[int]netlink.Link) {
-	fmt.Printf("Warning: Destructive operation. You are about to remove:\n"+
-		"- all BPF maps in %s containing '%s' and '%s'\n"+
-		"- mounted bpffs at %s\n"+
-		"- library code in %s\n"+
-		"- endpoint state in %s\n"+
-		"- CNI configuration at %s, %s, %s\n",
-		bpf.MapPrefixPath(), ciliumLinkPrefix, tunnel.MapName, bpf.GetMapRoot(),
-		defaults.LibraryPath, defaults.RuntimePath, cniConfigV1, cniConfigV2, cniConfigV3)
+// existingPaths returns the paths which exist.
+func existingPaths(paths []string) []string {
+	existing := []string{}
+	for _, path := range paths {
+		if _, err := os.Lstat(path); err == nil {
+			existing = append(existing, path)
+		}
+	}
+	return existing
+}
 
-	if len(routes) > 0 {
-		fmt.Printf("- routes\n")
-		for _, v := range routes {
-			fmt.Printf("%v\n", v)
+// printCleanupPlan writes the resources of plan to w, grouped by class.
+func printCleanupPlan(w io.Writer, p *cleanupPlan) {
+	if len(p.maps) > 0 {
+		fmt.Fprintf(w, "- BPF maps\n")
+		for _, m := range p.maps {
+			fmt.Fprintf(w, "  %s\n", m)
 		}
 	}
 
-	if len(links) > 0 {
-		fmt.Println("- links")
-		for _, v := range links {
-			fmt.Printf("%v\n", v)
+	if len(p.routes) > 0 {
+		fmt.Fprintf(w, "- routes\n")
+		routes := make([]string, 0, len(p.routes))
+		for _, r := range p.routes {
+			routes = append(routes, r.String())
+		}
+		sort.Strings(routes)
+		for _, r := range routes {
+			fmt.Fprintf(w, "  %s\n", r)
+		}
+	}
+
+	if len(p.links) > 0 {
+		fmt.Fprintf(w, "- links\n")
+		links := make([]string, 0, len(p.links))
+		for _, l := range p.links {
+			links = append(links, l.Attrs().Name)
+		}
+		sort.Strings(links)
+		for _, l := range links {
+			fmt.Fprintf(w, "  %s\n", l)
+		}
+	}
+
+	if len(p.cni) > 0 {
+		fmt.Fprintf(w, "- CNI configuration\n")
+		for _, f := range p.cni {
+			fmt.Fprintf(w, "  %s\n", f)
+		}
+	}
+
+	if len(p.dirs) > 0 {
+		fmt.Fprintf(w, "- endpoint state and library code\n")
+		for _, d := range p.dirs {
+			fmt.Fprintf(w, "  %s\n", d)
 		}
 	}
 }
@@ -122,20 +286,18 @@ func confirmCleanup() bool {
 	return res == "y"
 }
 
-func removeCNI() error {
-	os.Remove(cniConfigV1)
-	os.Remove(cniConfigV2)
-
-	err := os.Remove(cniConfigV3)
-	if os.IsNotExist(err) {
-		return nil
+func removeCNI(p *cleanupPlan) error {
+	for _, f := range p.cni {
+		if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		fmt.Printf("removed CNI configuration %s\n", f)
 	}
-	return err
+	return nil
 }
 
-func removeDirs() error {
-	dirs := []string{defaults.RuntimePath, defaults.LibraryPath}
-	for _, dir := range dirs {
+func removeDirs(p *cleanupPlan) error {
+	for _, dir := range p.dirs {
 		// In the unlikely case one of the constants is the root directory, abort.
 		if dir == "/" {
 			return fmt.Errorf("will not remove root directory %s", dir)
@@ -146,32 +308,42 @@ func removeDirs() error {
 			}
 			return err
 		}
+		fmt.Printf("removed directory %s\n", dir)
 	}
 	return nil
 }
 
-func removeAllMaps() error {
+// findMaps returns the paths of all pinned Cilium BPF maps.
+func findMaps() ([]string, error) {
 	mapDir := bpf.MapPrefixPath()
 	maps, err := ioutil.ReadDir(mapDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
 	}
 
+	paths := []string{}
 	for _, m := range maps {
 		name := m.Name()
 		// Skip non Cilium looking maps
 		if !strings.HasPrefix(name, ciliumLinkPrefix) && name != tunnel.MapName {
 			continue
 		}
-		if err = os.Remove(filepath.Join(mapDir, name)); err != nil {
+		paths = append(paths, filepath.Join(mapDir, name))
+	}
+	return paths, nil
+}
+
+func removeMaps(p *cleanupPlan) error {
+	for _, path := range p.maps {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
 			return err
 		}
-		fmt.Printf("removed map %s\n", m.Name())
+		fmt.Printf("removed map %s\n", filepath.Base(path))
 	}
-	return err
+	return nil
 }
 
 func linkMatch(linkName string) bool {
@@ -214,15 +386,15 @@ func findRoutesAndLinks() (map[int]netlink.Route, map[int]netlink.Link, error) {
 	return routesToRemove, linksToRemove, nil
 }
 
-func removeRoutesAndLinks(routes map[int]netlink.Route, links map[int]netlink.Link) error {
-	for _, route := range routes {
+func removeRoutesAndLinks(p *cleanupPlan) error {
+	for _, route := range p.routes {
 		if err := netlink.RouteDel(&route); err != nil {
 			return err
 		}
 		fmt.Printf("removed route %v\n", route)
 	}
 
-	for _, link := range links {
+	for _, link := range p.links {
 		if err := netlink.LinkDel(link); err != nil {
 			if strings.Contains(err.Error(), "Link not found") ||
 				strings.Contains(err.Error(), "no such device") {
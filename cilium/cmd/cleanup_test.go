@@ -0,0 +1,80 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/vishvananda/netlink"
+	. "gopkg.in/check.v1"
+)
+
+type CleanupSuite struct{}
+
+var _ = Suite(&CleanupSuite{})
+
+func (s *CleanupSuite) TestParseCleanupResources(c *C) {
+	selected, err := parseCleanupResources(cleanupClasses)
+	c.Assert(err, IsNil)
+	c.Assert(selected, HasLen, len(cleanupClasses))
+
+	selected, err = parseCleanupResources([]string{"BPF", " routes"})
+	c.Assert(err, IsNil)
+	c.Assert(selected, DeepEquals, map[string]bool{cleanupBPF: true, cleanupRoutes: true})
+
+	_, err = parseCleanupResources([]string{"bpf", "veths"})
+	c.Assert(err, ErrorMatches, `unknown resource class "veths".*`)
+
+	_, err = parseCleanupResources([]string{})
+	c.Assert(err, NotNil)
+}
+
+func (s *CleanupSuite) TestExistingPaths(c *C) {
+	dir, err := ioutil.TempDir("", "cilium-cleanup")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	present := filepath.Join(dir, "05-cilium-cni.conf")
+	c.Assert(ioutil.WriteFile(present, []byte("{}"), 0644), IsNil)
+
+	c.Assert(existingPaths([]string{present, filepath.Join(dir, "missing")}), DeepEquals, []string{present})
+}
+
+func (s *CleanupSuite) TestPrintCleanupPlan(c *C) {
+	plan := &cleanupPlan{
+		maps: []string{"/sys/fs/bpf/tc/globals/cilium_lxc"},
+		links: map[int]netlink.Link{
+			5: &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "lxc12345"}},
+			3: &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "cilium_host"}},
+		},
+		routes: map[int]netlink.Route{},
+		cni:    []string{cniConfigV3},
+	}
+	c.Assert(plan.empty(), Equals, false)
+	c.Assert((&cleanupPlan{}).empty(), Equals, true)
+
+	var buf bytes.Buffer
+	printCleanupPlan(&buf, plan)
+	c.Assert(buf.String(), Equals, "- BPF maps\n"+
+		"  /sys/fs/bpf/tc/globals/cilium_lxc\n"+
+		"- links\n"+
+		"  cilium_host\n"+
+		"  lxc12345\n"+
+		"- CNI configuration\n"+
+		"  "+cniConfigV3+"\n")
+}
@@ -0,0 +1,535 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/cilium/api/v1/client/policy"
+	"github.com/cilium/cilium/pkg/api"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// completionAnnotation annotates commands whose arguments are completed
+	// with the objects listed by 'cilium completion list'
+	completionAnnotation = "cilium.io/completion"
+
+	completeEndpoints  = "endpoints"
+	completeIdentities = "identities"
+	completeMaps       = "maps"
+)
+
+var completionKinds = []string{completeEndpoints, completeIdentities, completeMaps}
+
+const copyRightHeader = `
+# Copyright 2017 Authors of Cilium
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+`
+
+var (
+	completionExample = `
+# Installing bash completion on macOS using homebrew
+## If running Bash 3.2 included with macOS
+	brew install bash-completion
+## or, if running Bash 4.1+
+	brew install bash-completion@2
+## afterwards you only need to run
+	cilium completion bash > $(brew --prefix)/etc/bash_completion.d/cilium
+
+
+# Installing bash completion on Linux
+## Load the cilium completion code for bash into the current shell
+	source <(cilium completion bash)
+## Write bash completion code to a file and source if from .bash_profile
+	cilium completion bash > ~/.cilium/completion.bash.inc
+	printf "
+	  # Cilium shell completion
+	  source '$HOME/.cilium/completion.bash.inc'
+	  " >> $HOME/.bash_profile
+	source $HOME/.bash_profile
+
+
+# Load the cilium completion code for zsh into the current shell
+	source <(cilium completion zsh)
+
+
+# Load the cilium completion code for fish into the current shell
+	cilium completion fish | source
+## Install the fish completion code
+	cilium completion fish > ~/.config/fish/completions/cilium.fish`
+)
+
+func newCmdCompletion(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish]",
+		Short: "Output shell completion code",
+		Long: `Output shell completion code for the given shell, bash by default.
+Endpoint IDs, identity IDs and BPF map names are completed dynamically by
+querying the agent.`,
+		Example: completionExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runCompletion(out, cmd, args); err != nil {
+				Fatalf("%s", err)
+			}
+		},
+		ValidArgs: []string{"bash", "zsh", "fish"},
+	}
+	cmd.AddCommand(completionListCmd)
+
+	return cmd
+}
+
+func runCompletion(out io.Writer, cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("Too many arguments. Expected only the shell type.")
+	}
+
+	shell := "bash"
+	if len(args) == 1 {
+		shell = args[0]
+	}
+
+	var gen func(w io.Writer, root *cobra.Command) error
+	switch shell {
+	case "bash":
+		gen = writeBashCompletion
+	case "zsh":
+		// The compdef tag must be on the first line
+		if _, err := fmt.Fprintf(out, "#compdef %s\n", cmd.Parent().Name()); err != nil {
+			return err
+		}
+		gen = writeZshCompletion
+	case "fish":
+		gen = writeFishCompletion
+	default:
+		return fmt.Errorf("Unsupported shell type %q.", shell)
+	}
+
+	if _, err := out.Write([]byte(copyRightHeader)); err != nil {
+		return err
+	}
+
+	return gen(out, cmd.Parent())
+}
+
+// completionListCmd lists the objects used for dynamic completion
+var completionListCmd = &cobra.Command{
+	Use:       "list (endpoints | identities | maps)",
+	Short:     "List the objects used for dynamic shell completion",
+	Hidden:    true,
+	ValidArgs: completionKinds,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			Usagef(cmd, "Missing object kind")
+		}
+		candidates, err := completionCandidates(args[0])
+		if err != nil {
+			Fatalf("%s", err)
+		}
+		for _, c := range candidates {
+			fmt.Println(c)
+		}
+	},
+}
+
+// setArgCompletion completes the arguments of cmd with the objects of the
+// given kind.
+func setArgCompletion(cmd *cobra.Command, kind string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[completionAnnotation] = kind
+}
+
+// setFlagCompletion completes the values of the flag of cmd with the objects
+// of the given kind.
+func setFlagCompletion(cmd *cobra.Command, flag, kind string) {
+	cmd.MarkFlagCustom(flag, "__cilium_complete_"+kind)
+}
+
+// flagCompletionKind returns the kind of objects the values of flag are
+// completed with, or an empty string.
+func flagCompletionKind(flag *pflag.Flag) string {
+	for _, f := range flag.Annotations[cobra.BashCompCustom] {
+		if strings.HasPrefix(f, "__cilium_complete_") {
+			return strings.TrimPrefix(f, "__cilium_complete_")
+		}
+	}
+	return ""
+}
+
+// completionCandidates returns the IDs or names of the objects of the given
+// kind known to the agent.
+func completionCandidates(kind string) ([]string, error) {
+	var candidates []string
+	switch kind {
+	case completeEndpoints:
+		eps, err := client.EndpointList()
+		if err != nil {
+			return nil, err
+		}
+		for _, ep := range eps {
+			candidates = append(candidates, strconv.FormatInt(ep.ID, 10))
+		}
+	case completeIdentities:
+		params := policy.NewGetIdentityParams().WithTimeout(api.ClientTimeout)
+		resp, err := client.Policy.GetIdentity(params)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range resp.Payload {
+			candidates = append(candidates, strconv.FormatInt(id.ID, 10))
+		}
+	case completeMaps:
+		resp, err := client.Daemon.GetMap(nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.Payload != nil {
+			for _, m := range resp.Payload.Maps {
+				candidates = append(candidates, path.Base(m.Path))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown object kind %q, use one of %s",
+			kind, strings.Join(completionKinds, ", "))
+	}
+	sort.Strings(candidates)
+	return candidates, nil
+}
+
+// walkCommands calls fn for cmd and all its visible descendants.
+func walkCommands(cmd *cobra.Command, fn func(cmd *cobra.Command)) {
+	fn(cmd)
+	for _, c := range cmd.Commands() {
+		if c.IsAvailableCommand() {
+			walkCommands(c, fn)
+		}
+	}
+}
+
+// bashCompletionFunction returns the bash functions completing the arguments
+// and flags annotated with setArgCompletion and setFlagCompletion.
+func bashCompletionFunction(root *cobra.Command) string {
+	commands := map[string][]string{}
+	walkCommands(root, func(cmd *cobra.Command) {
+		if kind := cmd.Annotations[completionAnnotation]; kind != "" {
+			name := strings.Replace(cmd.CommandPath(), " ", "_", -1)
+			commands[kind] = append(commands[kind], name)
+		}
+	})
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `__cilium_complete()
+{
+    local cilium_out
+    if cilium_out=$(%s completion list "$1" 2>/dev/null); then
+        COMPREPLY=( $( compgen -W "${cilium_out[*]}" -- "$cur" ) )
+    fi
+}
+`, root.Name())
+	for _, kind := range completionKinds {
+		fmt.Fprintf(&b, "\n__cilium_complete_%s()\n{\n    __cilium_complete %s\n}\n", kind, kind)
+	}
+
+	b.WriteString("\n__custom_func() {\n    case ${last_command} in\n")
+	for _, kind := range completionKinds {
+		if len(commands[kind]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "        %s)\n            __cilium_complete %s\n            return\n            ;;\n",
+			strings.Join(commands[kind], " | "), kind)
+	}
+	b.WriteString("        *)\n            ;;\n    esac\n}\n")
+	return b.String()
+}
+
+// writeBashCompletion writes the bash completion code for root to w.
+func writeBashCompletion(w io.Writer, root *cobra.Command) error {
+	root.BashCompletionFunction = bashCompletionFunction(root)
+	return root.GenBashCompletion(w)
+}
+
+// writeZshCompletion writes the zsh completion code for root to w. The bash
+// completion code is loaded through the bash compatibility layer of zsh.
+func writeZshCompletion(w io.Writer, root *cobra.Command) error {
+	if _, err := io.WriteString(w, zshCompletionHead); err != nil {
+		return err
+	}
+	if err := writeBashCompletion(w, root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, zshCompletionTail)
+	return err
+}
+
+// fishQuote quotes s as a single-quoted fish string.
+func fishQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
+
+// writeFishFlag writes the fish completion of flag for the command
+// identified by condition.
+func writeFishFlag(w io.Writer, name, condition string, flag *pflag.Flag) {
+	if flag.Hidden {
+		return
+	}
+
+	fmt.Fprintf(w, "complete -c %s", name)
+	if condition != "" {
+		fmt.Fprintf(w, " -n %s", fishQuote(condition))
+	}
+	fmt.Fprintf(w, " -l %s", flag.Name)
+	if flag.Shorthand != "" {
+		fmt.Fprintf(w, " -s %s", flag.Shorthand)
+	}
+	if kind := flagCompletionKind(flag); kind != "" {
+		fmt.Fprintf(w, " -x -a '(__cilium_complete %s)'", kind)
+	} else if flag.NoOptDefVal == "" {
+		fmt.Fprint(w, " -r")
+	}
+	fmt.Fprintf(w, " -d %s\n", fishQuote(flag.Usage))
+}
+
+// writeFishCompletion writes the fish completion code for root to w.
+func writeFishCompletion(w io.Writer, root *cobra.Command) error {
+	name := root.Name()
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `
+function __cilium_using_command
+    set -l cmd (commandline -opc)
+    set -e cmd[1]
+    set -l words
+    for w in $cmd
+        switch $w
+            case '-*'
+            case '*'
+                set words $words $w
+        end
+    end
+    test "$words" = "$argv"
+end
+
+function __cilium_complete
+    %s completion list $argv 2>/dev/null
+end
+
+complete -c %s -f
+`, name, name)
+
+	root.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		writeFishFlag(&b, name, "", flag)
+	})
+
+	walkCommands(root, func(cmd *cobra.Command) {
+		path := strings.TrimSpace(strings.TrimPrefix(cmd.CommandPath(), name))
+		condition := strings.TrimSpace("__cilium_using_command " + path)
+
+		for _, c := range cmd.Commands() {
+			if !c.IsAvailableCommand() {
+				continue
+			}
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s -d %s\n", name,
+				fishQuote(condition), c.Name(), fishQuote(c.Short))
+		}
+		for _, arg := range cmd.ValidArgs {
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s\n", name,
+				fishQuote(condition), fishQuote(arg))
+		}
+		if kind := cmd.Annotations[completionAnnotation]; kind != "" {
+			fmt.Fprintf(&b, "complete -c %s -n %s -a '(__cilium_complete %s)'\n", name,
+				fishQuote(condition), kind)
+		}
+		if cmd != root {
+			cmd.NonInheritedFlags().VisitAll(func(flag *pflag.Flag) {
+				if root.PersistentFlags().Lookup(flag.Name) == nil {
+					writeFishFlag(&b, name, condition, flag)
+				}
+			})
+		}
+	})
+
+	_, err := b.WriteTo(w)
+	return err
+}
+
+const zshCompletionHead = `
+__cilium_bash_source() {
+	alias shopt=':'
+	alias _expand=_bash_expand
+	alias _complete=_bash_comp
+	emulate -L sh
+	setopt kshglob noshglob braceexpand
+
+	source "$@"
+}
+
+__cilium_type() {
+	# -t is not supported by zsh
+	if [ "$1" == "-t" ]; then
+		shift
+
+		# fake Bash 4 to disable "complete -o nospace". Instead
+		# "compopt +-o nospace" is used in the code to toggle trailing
+		# spaces. We don't support that, but leave trailing spaces on
+		# all the time
+		if [ "$1" = "__cilium_compopt" ]; then
+			echo builtin
+			return 0
+		fi
+	fi
+	type "$@"
+}
+
+__cilium_compgen() {
+	local completions w
+	completions=( $(compgen "$@") ) || return $?
+
+	# filter by given word as prefix
+	while [[ "$1" = -* && "$1" != -- ]]; do
+		shift
+		shift
+	done
+	if [[ "$1" == -- ]]; then
+		shift
+	fi
+	for w in "${completions[@]}"; do
+		if [[ "${w}" = "$1"* ]]; then
+			echo "${w}"
+		fi
+	done
+}
+
+__cilium_compopt() {
+	true # don't do anything. Not supported by bashcompinit in zsh
+}
+
+__cilium_ltrim_colon_completions()
+{
+	if [[ "$1" == *:* && "$COMP_WORDBREAKS" == *:* ]]; then
+		# Remove colon-word prefix from COMPREPLY items
+		local colon_word=${1%${1##*:}}
+		local i=${#COMPREPLY[*]}
+		while [[ $((--i)) -ge 0 ]]; do
+			COMPREPLY[$i]=${COMPREPLY[$i]#"$colon_word"}
+		done
+	fi
+}
+
+__cilium_get_comp_words_by_ref() {
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	prev="${COMP_WORDS[${COMP_CWORD}-1]}"
+	words=("${COMP_WORDS[@]}")
+	cword=("${COMP_CWORD[@]}")
+}
+
+__cilium_filedir() {
+	local RET OLD_IFS w qw
+
+	if [[ "$1" = \~* ]]; then
+		# somehow does not work. Maybe, zsh does not call this at all
+		eval echo "$1"
+		return 0
+	fi
+
+	OLD_IFS="$IFS"
+	IFS=$'\n'
+	if [ "$1" = "-d" ]; then
+		shift
+		RET=( $(compgen -d) )
+	else
+		RET=( $(compgen -f) )
+	fi
+	IFS="$OLD_IFS"
+
+	for w in ${RET[@]}; do
+		if [[ ! "${w}" = "${cur}"* ]]; then
+			continue
+		fi
+		if eval "[[ \"\${w}\" = *.$1 || -d \"\${w}\" ]]"; then
+			qw="$(__cilium_quote "${w}")"
+			if [ -d "${w}" ]; then
+				COMPREPLY+=("${qw}/")
+			else
+				COMPREPLY+=("${qw}")
+			fi
+		fi
+	done
+}
+
+__cilium_quote() {
+	if [[ $1 == \'* || $1 == \"* ]]; then
+		# Leave out first character
+		printf %q "${1:1}"
+	else
+		printf %q "$1"
+	fi
+}
+
+autoload -U +X bashcompinit && bashcompinit
+
+# use word boundary patterns for BSD or GNU sed
+LWORD='[[:<:]]'
+RWORD='[[:>:]]'
+if sed --help 2>&1 | grep -q GNU; then
+	LWORD='\<'
+	RWORD='\>'
+fi
+
+__cilium_convert_bash_to_zsh() {
+	sed \
+	-e 's/declare -F/whence -w/' \
+	-e 's/_get_comp_words_by_ref "\$@"/_get_comp_words_by_ref "\$*"/' \
+	-e 's/local \([a-zA-Z0-9_]*\)=/local \1; \1=/' \
+	-e 's/flags+=("\(--.*\)=")/flags+=("\1"); two_word_flags+=("\1")/' \
+	-e 's/must_have_one_flag+=("\(--.*\)=")/must_have_one_flag+=("\1")/' \
+	-e "s/${LWORD}_filedir${RWORD}/__cilium_filedir/g" \
+	-e "s/${LWORD}_get_comp_words_by_ref${RWORD}/__cilium_get_comp_words_by_ref/g" \
+	-e "s/${LWORD}__ltrim_colon_completions${RWORD}/__cilium_ltrim_colon_completions/g" \
+	-e "s/${LWORD}compgen${RWORD}/__cilium_compgen/g" \
+	-e "s/${LWORD}compopt${RWORD}/__cilium_compopt/g" \
+	-e "s/${LWORD}declare${RWORD}/builtin declare/g" \
+	-e "s/\\\$(type${RWORD}/\$(__cilium_type/g" \
+	<<'BASH_COMPLETION_EOF'
+`
+
+const zshCompletionTail = `
+BASH_COMPLETION_EOF
+}
+
+__cilium_bash_source <(__cilium_convert_bash_to_zsh)
+_complete cilium 2>/dev/null
+`
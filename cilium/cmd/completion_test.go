@@ -0,0 +1,97 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/spf13/cobra"
+
+	. "gopkg.in/check.v1"
+)
+
+type CompletionSuite struct{}
+
+var _ = Suite(&CompletionSuite{})
+
+// newCompletionTestTree returns a command tree with completion annotations
+func newCompletionTestTree() *cobra.Command {
+	run := func(cmd *cobra.Command, args []string) {}
+	root := &cobra.Command{Use: "cilium", Run: run}
+	root.PersistentFlags().BoolP("debug", "D", false, "Enable debug messages")
+
+	ep := &cobra.Command{Use: "endpoint", Short: "Manage endpoints"}
+	epGet := &cobra.Command{Use: "get <endpoint id>", Short: "Display endpoint information", Run: run}
+	epGet.Flags().StringP("output", "o", "", "json or 'yaml'")
+	setArgCompletion(epGet, completeEndpoints)
+	ep.AddCommand(epGet)
+
+	monitor := &cobra.Command{Use: "monitor", Short: "Display events", Run: run}
+	monitor.Flags().String("from-identity", "", "Filter by source identity")
+	setFlagCompletion(monitor, "from-identity", completeIdentities)
+
+	hidden := &cobra.Command{Use: "hidden", Hidden: true, Run: run}
+	setArgCompletion(hidden, completeMaps)
+
+	root.AddCommand(ep, monitor, hidden)
+	return root
+}
+
+func (s *CompletionSuite) TestBashCompletionFunction(c *C) {
+	f := bashCompletionFunction(newCompletionTestTree())
+	c.Assert(f, Matches, `(?s).*__cilium_complete_identities\(\)\n\{\n    __cilium_complete identities\n\}.*`)
+	c.Assert(f, Matches, `(?s).*        cilium_endpoint_get\)\n            __cilium_complete endpoints\n.*`)
+	c.Assert(f, Not(Matches), `(?s).*cilium_hidden.*`)
+}
+
+func (s *CompletionSuite) TestWriteFishCompletion(c *C) {
+	var buf bytes.Buffer
+	c.Assert(writeFishCompletion(&buf, newCompletionTestTree()), IsNil)
+	out := buf.String()
+
+	for _, line := range []string{
+		"complete -c cilium -l debug -s D -d 'Enable debug messages'\n",
+		"complete -c cilium -n '__cilium_using_command' -a endpoint -d 'Manage endpoints'\n",
+		"complete -c cilium -n '__cilium_using_command endpoint' -a get -d 'Display endpoint information'\n",
+		"complete -c cilium -n '__cilium_using_command endpoint get' -a '(__cilium_complete endpoints)'\n",
+		"complete -c cilium -n '__cilium_using_command endpoint get' -l output -s o -r -d 'json or \\'yaml\\''\n",
+		"complete -c cilium -n '__cilium_using_command monitor' -l from-identity -x -a '(__cilium_complete identities)' -d 'Filter by source identity'\n",
+	} {
+		c.Assert(bytes.Contains(buf.Bytes(), []byte(line)), Equals, true, Commentf("missing %q in:\n%s", line, out))
+	}
+	c.Assert(bytes.Contains(buf.Bytes(), []byte("hidden")), Equals, false)
+}
+
+func (s *CompletionSuite) TestRunCompletion(c *C) {
+	var buf bytes.Buffer
+	root := newCompletionTestTree()
+	cmd := newCmdCompletion(&buf)
+	root.AddCommand(cmd)
+
+	c.Assert(runCompletion(&buf, cmd, nil), IsNil)
+	c.Assert(buf.String(), Matches, "(?s)\n# Copyright .*__start_cilium.*")
+
+	buf.Reset()
+	c.Assert(runCompletion(&buf, cmd, []string{"zsh"}), IsNil)
+	c.Assert(buf.String(), Matches, "(?s)#compdef cilium\n\n# Copyright .*"+
+		"<<'BASH_COMPLETION_EOF'\n.*__start_cilium.*\nBASH_COMPLETION_EOF\n.*")
+
+	buf.Reset()
+	c.Assert(runCompletion(&buf, cmd, []string{"fish"}), IsNil)
+	c.Assert(buf.String(), Matches, "(?s)\n# Copyright .*function __cilium_using_command.*")
+
+	c.Assert(runCompletion(&buf, cmd, []string{"tcsh"}), ErrorMatches, "Unsupported shell type.*")
+	c.Assert(runCompletion(&buf, cmd, []string{"bash", "zsh"}), NotNil)
+}
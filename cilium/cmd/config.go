@@ -16,7 +16,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
@@ -71,7 +70,7 @@ func configDaemon(cmd *cobra.Command, opts []string) {
 	} else if len(opts) == 0 {
 		if command.OutputJSON() {
 			if err := command.PrintOutput(cfgStatus.Realized.Options); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
@@ -93,7 +92,7 @@ func configDaemon(cmd *cobra.Command, opts []string) {
 		// TODO FIXME - this is a hack, and is not clean
 		optionSplit := strings.SplitN(opts[k], "=", 2)
 		if len(optionSplit) < 2 {
-			Fatalf("Improper configuration format provided")
+			Exitf(ExitUsage, "Improper configuration format provided")
 		}
 		arg := optionSplit[0]
 		if arg == "PolicyEnforcement" {
@@ -103,8 +102,7 @@ func configDaemon(cmd *cobra.Command, opts []string) {
 
 		name, value, err := option.ParseDaemonOption(opts[k])
 		if err != nil {
-			fmt.Printf("%s\n", err)
-			os.Exit(1)
+			Exitf(ExitUsage, "%s", err)
 		}
 
 		if opt, ok := option.DaemonMutableOptionLibrary[name]; !ok || opt.Parse == nil {
@@ -0,0 +1,376 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	healthClient "github.com/cilium/cilium/api/v1/health/client"
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/command"
+	healthDefaults "github.com/cilium/cilium/pkg/health/defaults"
+	"github.com/cilium/cilium/pkg/identity"
+
+	"github.com/servak/go-fastping"
+	"github.com/spf13/cobra"
+)
+
+const (
+	connectivityPass = "PASS"
+	connectivityFail = "FAIL"
+)
+
+var (
+	connectivityEndpoints []string
+	connectivityPorts     []int
+	connectivityHTTPPorts []int
+	connectivityHTTPPath  string
+	connectivityTimeout   time.Duration
+)
+
+// connectivityCheckCmd represents the connectivity-check command
+var connectivityCheckCmd = &cobra.Command{
+	Use:   "connectivity-check",
+	Short: "Check connectivity from the host to local endpoints",
+	Long: `Probe the connectivity from the host to the cilium-health responder of the
+host, to the local cilium-health endpoint and to selected endpoints. Each
+target is probed on the following paths:
+
+  L3  ICMP echo request
+  L4  TCP connection establishment
+  L7  HTTP GET request
+
+The health responders are probed on port ` + strconv.Itoa(healthDefaults.HTTPPathPort) + `. Endpoints selected with
+--endpoint are probed with ICMP and on the ports given with --port and
+--http-port. ICMP probes require root privileges.
+
+The command exits with a non-zero status if any probe fails.`,
+	Example: "  cilium connectivity-check --endpoint 1234 --port 6379 --http-port 80",
+	Run: func(cmd *cobra.Command, args []string) {
+		runConnectivityCheck()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(connectivityCheckCmd)
+	connectivityCheckCmd.Flags().StringSliceVarP(&connectivityEndpoints, "endpoint", "e", []string{}, "Endpoint IDs to probe in addition to the health responders")
+	connectivityCheckCmd.Flags().IntSliceVar(&connectivityPorts, "port", []int{}, "TCP ports to probe on selected endpoints")
+	connectivityCheckCmd.Flags().IntSliceVar(&connectivityHTTPPorts, "http-port", []int{}, "HTTP ports to probe on selected endpoints")
+	connectivityCheckCmd.Flags().StringVar(&connectivityHTTPPath, "http-path", "/", "Path of HTTP requests to selected endpoints")
+	connectivityCheckCmd.Flags().DurationVar(&connectivityTimeout, "timeout", 2*time.Second, "Timeout of each probe")
+	setFlagCompletion(connectivityCheckCmd, "endpoint", completeEndpoints)
+	command.AddJSONOutput(connectivityCheckCmd)
+}
+
+// probeFunc probes connectivity to ip and returns the measured latency.
+type probeFunc func(ip string) (time.Duration, error)
+
+// connectivityPath is a single path on which a target is probed.
+type connectivityPath struct {
+	name  string
+	probe probeFunc
+}
+
+// connectivityTarget is a destination to be probed on a set of paths.
+type connectivityTarget struct {
+	name  string
+	ips   []string
+	paths []connectivityPath
+}
+
+// connectivityResult is the result of probing a target on a path.
+type connectivityResult struct {
+	Target  string        `json:"target"`
+	IP      string        `json:"ip"`
+	Path    string        `json:"path"`
+	Status  string        `json:"status"`
+	Latency time.Duration `json:"latency,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// icmpProbe returns a probeFunc sending an ICMP echo request.
+func icmpProbe(timeout time.Duration) probeFunc {
+	return func(ip string) (time.Duration, error) {
+		addr, err := net.ResolveIPAddr("ip", ip)
+		if err != nil {
+			return 0, err
+		}
+
+		var (
+			rtt      time.Duration
+			received bool
+		)
+		p := fastping.NewPinger()
+		p.MaxRTT = timeout
+		p.AddIPAddr(addr)
+		p.OnRecv = func(_ *net.IPAddr, d time.Duration) {
+			rtt = d
+			received = true
+		}
+		if err := p.Run(); err != nil {
+			return 0, err
+		}
+		if !received {
+			return 0, fmt.Errorf("no reply within %s", timeout)
+		}
+		return rtt, nil
+	}
+}
+
+// tcpProbe returns a probeFunc establishing a TCP connection to port.
+func tcpProbe(port int, timeout time.Duration) probeFunc {
+	return func(ip string) (time.Duration, error) {
+		start := time.Now()
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(ip, strconv.Itoa(port)), timeout)
+		if err != nil {
+			return 0, err
+		}
+		rtt := time.Since(start)
+		conn.Close()
+		return rtt, nil
+	}
+}
+
+// httpProbe returns a probeFunc sending an HTTP GET request for path to
+// port. Any response with a status code below 400 is considered a success.
+func httpProbe(port int, path string, timeout time.Duration) probeFunc {
+	return func(ip string) (time.Duration, error) {
+		c := &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{DisableKeepAlives: true},
+		}
+		url := fmt.Sprintf("http://%s%s", net.JoinHostPort(ip, strconv.Itoa(port)), path)
+
+		start := time.Now()
+		resp, err := c.Get(url)
+		if err != nil {
+			return 0, err
+		}
+		rtt := time.Since(start)
+		resp.Body.Close()
+
+		if resp.StatusCode >= 400 {
+			return 0, fmt.Errorf("GET %s returned %s", url, resp.Status)
+		}
+		return rtt, nil
+	}
+}
+
+// healthPaths returns the paths on which a cilium-health responder is
+// probed.
+func healthPaths(timeout time.Duration) []connectivityPath {
+	port := healthDefaults.HTTPPathPort
+	return []connectivityPath{
+		{name: "L3 ICMP", probe: icmpProbe(timeout)},
+		{name: fmt.Sprintf("L4 TCP/%d", port), probe: tcpProbe(port, timeout)},
+		{name: fmt.Sprintf("L7 HTTP/%d", port), probe: httpProbe(port, healthClient.DefaultBasePath+"/hello", timeout)},
+	}
+}
+
+// endpointPaths returns the paths on which selected endpoints are probed.
+func endpointPaths(ports, httpPorts []int, httpPath string, timeout time.Duration) []connectivityPath {
+	paths := []connectivityPath{{name: "L3 ICMP", probe: icmpProbe(timeout)}}
+	for _, port := range ports {
+		paths = append(paths, connectivityPath{name: fmt.Sprintf("L4 TCP/%d", port), probe: tcpProbe(port, timeout)})
+	}
+	for _, port := range httpPorts {
+		paths = append(paths, connectivityPath{name: fmt.Sprintf("L7 HTTP/%d", port), probe: httpProbe(port, httpPath, timeout)})
+	}
+	return paths
+}
+
+// endpointIPs returns the IPv4 and IPv6 addresses of an endpoint.
+func endpointIPs(ep *models.Endpoint) []string {
+	ips := []string{}
+	if ep.Status == nil || ep.Status.Networking == nil {
+		return ips
+	}
+	for _, pair := range ep.Status.Networking.Addressing {
+		if pair.IPV4 != "" {
+			ips = append(ips, pair.IPV4)
+		}
+		if pair.IPV6 != "" {
+			ips = append(ips, pair.IPV6)
+		}
+	}
+	return ips
+}
+
+// nodeIPs returns the IPv4 and IPv6 addresses of the node.
+func nodeIPs(addressing *models.NodeAddressing) []string {
+	ips := []string{}
+	if addressing == nil {
+		return ips
+	}
+	for _, elem := range []*models.NodeAddressingElement{addressing.IPV4, addressing.IPV6} {
+		if elem != nil && elem.Enabled && elem.IP != "" {
+			ips = append(ips, elem.IP)
+		}
+	}
+	return ips
+}
+
+// connectivityTargets returns the targets to probe: the host, the health
+// endpoint and all endpoints with IDs listed in selected.
+func connectivityTargets(addressing *models.NodeAddressing, eps []*models.Endpoint, selected []string,
+	health, endpoint []connectivityPath) ([]connectivityTarget, error) {
+
+	targets := []connectivityTarget{{name: "host", ips: nodeIPs(addressing), paths: health}}
+
+	byID := map[string]*models.Endpoint{}
+	for _, ep := range eps {
+		byID[strconv.FormatInt(ep.ID, 10)] = ep
+		if ep.Status != nil && ep.Status.Identity != nil &&
+			ep.Status.Identity.ID == int64(identity.ReservedIdentityHealth) {
+			targets = append(targets, connectivityTarget{
+				name:  fmt.Sprintf("health (endpoint %d)", ep.ID),
+				ips:   endpointIPs(ep),
+				paths: health,
+			})
+		}
+	}
+
+	for _, id := range selected {
+		ep, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("endpoint %s not found", id)
+		}
+		targets = append(targets, connectivityTarget{
+			name:  fmt.Sprintf("endpoint %s", id),
+			ips:   endpointIPs(ep),
+			paths: endpoint,
+		})
+	}
+
+	return targets, nil
+}
+
+// probeTargets probes all targets on all of their paths concurrently and
+// returns the results in the order of targets, IPs and paths.
+func probeTargets(targets []connectivityTarget) []connectivityResult {
+	type job struct {
+		ip    string
+		probe probeFunc
+	}
+
+	results := []connectivityResult{}
+	jobs := map[int]job{}
+	for _, t := range targets {
+		if len(t.ips) == 0 {
+			results = append(results, connectivityResult{
+				Target: t.name,
+				Status: connectivityFail,
+				Error:  "no IP address",
+			})
+			continue
+		}
+		for _, ip := range t.ips {
+			for _, p := range t.paths {
+				jobs[len(results)] = job{ip: ip, probe: p.probe}
+				results = append(results, connectivityResult{Target: t.name, IP: ip, Path: p.name})
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Add(1)
+		go func(r *connectivityResult, j job) {
+			defer wg.Done()
+			latency, err := j.probe(j.ip)
+			if err != nil {
+				r.Status = connectivityFail
+				r.Error = err.Error()
+			} else {
+				r.Status = connectivityPass
+				r.Latency = latency
+			}
+		}(&results[i], j)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// printConnectivityResults writes results as a table to w and returns the
+// number of failed probes.
+func printConnectivityResults(w io.Writer, results []connectivityResult) int {
+	failed := 0
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "TARGET\tIP\tPATH\tSTATUS\tLATENCY\n")
+	for _, r := range results {
+		detail := ""
+		if r.Status == connectivityPass {
+			detail = r.Latency.String()
+		} else {
+			failed++
+			detail = r.Error
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Target, r.IP, r.Path, r.Status, detail)
+	}
+	tw.Flush()
+	return failed
+}
+
+func runConnectivityCheck() {
+	cfg, err := client.ConfigGet()
+	if err != nil {
+		Fatalf("Cannot get daemon configuration: %s", err)
+	}
+	var addressing *models.NodeAddressing
+	if cfg.Status != nil {
+		addressing = cfg.Status.Addressing
+	}
+
+	eps, err := client.EndpointList()
+	if err != nil {
+		Fatalf("Cannot get endpoint list: %s", err)
+	}
+
+	targets, err := connectivityTargets(addressing, eps, connectivityEndpoints,
+		healthPaths(connectivityTimeout),
+		endpointPaths(connectivityPorts, connectivityHTTPPorts, connectivityHTTPPath, connectivityTimeout))
+	if err != nil {
+		Fatalf("%s", err)
+	}
+
+	results := probeTargets(targets)
+
+	failed := 0
+	if command.OutputJSON() {
+		for _, r := range results {
+			if r.Status != connectivityPass {
+				failed++
+			}
+		}
+		if err := command.PrintOutput(results); err != nil {
+			Fatalf("Unable to provide JSON output: %s", err)
+		}
+	} else {
+		failed = printConnectivityResults(os.Stdout, results)
+	}
+
+	if failed > 0 {
+		Fatalf("%d of %d probes failed", failed, len(results))
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/identity"
+
+	. "gopkg.in/check.v1"
+)
+
+type ConnectivityCheckSuite struct{}
+
+var _ = Suite(&ConnectivityCheckSuite{})
+
+func (s *ConnectivityCheckSuite) TestTCPAndHTTPProbe(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hello" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	host, portStr, err := net.SplitHostPort(srv.Listener.Addr().String())
+	c.Assert(err, IsNil)
+	port, err := strconv.Atoi(portStr)
+	c.Assert(err, IsNil)
+
+	_, err = tcpProbe(port, time.Second)(host)
+	c.Assert(err, IsNil)
+	_, err = httpProbe(port, "/hello", time.Second)(host)
+	c.Assert(err, IsNil)
+	_, err = httpProbe(port, "/", time.Second)(host)
+	c.Assert(err, ErrorMatches, ".*404 Not Found")
+
+	// Nothing is listening on the port anymore
+	srv.Close()
+	_, err = tcpProbe(port, time.Second)(host)
+	c.Assert(err, NotNil)
+}
+
+func (s *ConnectivityCheckSuite) TestConnectivityTargets(c *C) {
+	addressing := &models.NodeAddressing{
+		IPV4: &models.NodeAddressingElement{Enabled: true, IP: "192.168.0.1"},
+		IPV6: &models.NodeAddressingElement{Enabled: false, IP: "f00d::1"},
+	}
+	eps := []*models.Endpoint{
+		{
+			ID: 10,
+			Status: &models.EndpointStatus{
+				Identity: &models.Identity{ID: int64(identity.ReservedIdentityHealth)},
+				Networking: &models.EndpointNetworking{
+					Addressing: []*models.AddressPair{{IPV4: "10.0.0.10", IPV6: "f00d::10"}},
+				},
+			},
+		},
+		{
+			ID: 20,
+			Status: &models.EndpointStatus{
+				Identity: &models.Identity{ID: 1000},
+				Networking: &models.EndpointNetworking{
+					Addressing: []*models.AddressPair{{IPV4: "10.0.0.20"}},
+				},
+			},
+		},
+	}
+	health := []connectivityPath{{name: "health"}}
+	endpoint := []connectivityPath{{name: "endpoint"}}
+
+	targets, err := connectivityTargets(addressing, eps, []string{"20"}, health, endpoint)
+	c.Assert(err, IsNil)
+	c.Assert(targets, HasLen, 3)
+	c.Assert(targets[0].name, Equals, "host")
+	c.Assert(targets[0].ips, DeepEquals, []string{"192.168.0.1"})
+	c.Assert(targets[0].paths[0].name, Equals, "health")
+	c.Assert(targets[1].name, Equals, "health (endpoint 10)")
+	c.Assert(targets[1].ips, DeepEquals, []string{"10.0.0.10", "f00d::10"})
+	c.Assert(targets[1].paths[0].name, Equals, "health")
+	c.Assert(targets[2].name, Equals, "endpoint 20")
+	c.Assert(targets[2].ips, DeepEquals, []string{"10.0.0.20"})
+	c.Assert(targets[2].paths[0].name, Equals, "endpoint")
+
+	_, err = connectivityTargets(addressing, eps, []string{"30"}, health, endpoint)
+	c.Assert(err, ErrorMatches, "endpoint 30 not found")
+}
+
+func (s *ConnectivityCheckSuite) TestProbeTargets(c *C) {
+	pass := func(ip string) (time.Duration, error) { return time.Millisecond, nil }
+	fail := func(ip string) (time.Duration, error) { return 0, fmt.Errorf("%s unreachable", ip) }
+
+	targets := []connectivityTarget{
+		{name: "a", ips: []string{"10.0.0.1", "10.0.0.2"}, paths: []connectivityPath{
+			{name: "L3", probe: pass},
+			{name: "L4", probe: fail},
+		}},
+		{name: "b", paths: []connectivityPath{{name: "L3", probe: pass}}},
+		{name: "c", ips: []string{"10.0.0.3"}, paths: []connectivityPath{{name: "L7", probe: pass}}},
+	}
+
+	results := probeTargets(targets)
+	c.Assert(results, DeepEquals, []connectivityResult{
+		{Target: "a", IP: "10.0.0.1", Path: "L3", Status: connectivityPass, Latency: time.Millisecond},
+		{Target: "a", IP: "10.0.0.1", Path: "L4", Status: connectivityFail, Error: "10.0.0.1 unreachable"},
+		{Target: "a", IP: "10.0.0.2", Path: "L3", Status: connectivityPass, Latency: time.Millisecond},
+		{Target: "a", IP: "10.0.0.2", Path: "L4", Status: connectivityFail, Error: "10.0.0.2 unreachable"},
+		{Target: "b", Status: connectivityFail, Error: "no IP address"},
+		{Target: "c", IP: "10.0.0.3", Path: "L7", Status: connectivityPass, Latency: time.Millisecond},
+	})
+
+	buf := &bytes.Buffer{}
+	c.Assert(printConnectivityResults(buf, results), Equals, 3)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	c.Assert(lines, HasLen, 7)
+	c.Assert(strings.Fields(lines[0]), DeepEquals, []string{"TARGET", "IP", "PATH", "STATUS", "LATENCY"})
+	c.Assert(strings.Fields(lines[1]), DeepEquals, []string{"a", "10.0.0.1", "L3", "PASS", "1ms"})
+	c.Assert(strings.Fields(lines[2]), DeepEquals, []string{"a", "10.0.0.1", "L4", "FAIL", "10.0.0.1", "unreachable"})
+}
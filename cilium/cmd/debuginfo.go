@@ -25,6 +25,7 @@ import (
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	pkg "github.com/cilium/cilium/pkg/client"
@@ -60,6 +61,12 @@ var (
 	file           string
 	html           string
 	filePerCommand bool
+
+	archive                string
+	archiveMaxSize         int64
+	archiveMaxFileSize     int64
+	archiveMonitorDuration time.Duration
+	archiveLogLines        int
 )
 
 type addSection func(*tabwriter.Writer, *models.DebugInfo)
@@ -80,6 +87,11 @@ func init() {
 	debuginfoCmd.Flags().StringVarP(&file, "file", "f", "", "Redirect output to file")
 	debuginfoCmd.Flags().StringVarP(&html, "html-file", "", "", "Convert default output to HTML file")
 	debuginfoCmd.Flags().BoolVarP(&filePerCommand, "file-per-command", "", false, "Generate a single file per command")
+	debuginfoCmd.Flags().StringVar(&archive, "archive", "", "Collect debug information, BPF maps, endpoint state and logs into a gzip compressed tarball")
+	debuginfoCmd.Flags().Int64Var(&archiveMaxSize, "archive-max-size", 100<<20, "Maximum uncompressed size of the archive in bytes")
+	debuginfoCmd.Flags().Int64Var(&archiveMaxFileSize, "archive-max-file-size", 10<<20, "Maximum size of a single file in the archive in bytes")
+	debuginfoCmd.Flags().DurationVar(&archiveMonitorDuration, "archive-monitor-duration", 5*time.Second, "Duration of the monitor sample in the archive")
+	debuginfoCmd.Flags().IntVar(&archiveLogLines, "archive-log-lines", 1000, "Number of recent agent log lines in the archive")
 }
 
 func runDebugInfo(cmd *cobra.Command, args []string) {
@@ -89,8 +101,15 @@ func runDebugInfo(cmd *cobra.Command, args []string) {
 
 	resp, err := client.Daemon.GetDebuginfo(nil)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "%s\n", pkg.Hint(err))
-		os.Exit(1)
+		Fatalf("%s", pkg.Hint(err))
+	}
+
+	if len(archive) > 0 {
+		if err := writeDebugArchive(archive, resp.Payload); err != nil {
+			Fatalf("Unable to write archive %s: %s", archive, err)
+		}
+		fmt.Printf("Archive written to %s\n", archive)
+		return
 	}
 
 	// define output type and file path
@@ -0,0 +1,270 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/defaults"
+)
+
+const (
+	truncatedMarker = "\n... truncated ...\n"
+
+	// archiveCommandTimeout is the maximum time a command collecting
+	// information for the archive may run
+	archiveCommandTimeout = 30 * time.Second
+)
+
+// debugArchive writes files into a gzip compressed tarball. Files larger than
+// maxFileSize are truncated and files which would exceed the total size
+// budget are skipped.
+type debugArchive struct {
+	gz          *gzip.Writer
+	tw          *tar.Writer
+	prefix      string
+	modTime     time.Time
+	maxFileSize int64
+	remaining   int64
+	skipped     []string
+}
+
+func newDebugArchive(w io.Writer, prefix string, maxFileSize, maxSize int64) *debugArchive {
+	gz := gzip.NewWriter(w)
+	return &debugArchive{
+		gz:          gz,
+		tw:          tar.NewWriter(gz),
+		prefix:      prefix,
+		modTime:     time.Now(),
+		maxFileSize: maxFileSize,
+		remaining:   maxSize,
+	}
+}
+
+// add writes a file with the given name and content to the archive.
+func (a *debugArchive) add(name string, data []byte) error {
+	if int64(len(data)) > a.maxFileSize {
+		data = append(data[:a.maxFileSize:a.maxFileSize], truncatedMarker...)
+	}
+	if int64(len(data)) > a.remaining {
+		a.skipped = append(a.skipped, name)
+		return nil
+	}
+	a.remaining -= int64(len(data))
+
+	return a.write(name, data)
+}
+
+// write writes a file to the archive regardless of the size limits.
+func (a *debugArchive) write(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    filepath.Join(a.prefix, name),
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: a.modTime,
+	}
+	if err := a.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := a.tw.Write(data)
+	return err
+}
+
+// addJSON writes the JSON representation of v to the archive.
+func (a *debugArchive) addJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return a.add(name, []byte(fmt.Sprintf("Unable to marshal: %s\n", err)))
+	}
+	return a.add(name, data)
+}
+
+// addDir writes all regular files below dir to the archive under name.
+func (a *debugArchive) addDir(name, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		data, err := readFileLimited(path, a.maxFileSize)
+		if err != nil {
+			data = []byte(fmt.Sprintf("Unable to read %s: %s\n", path, err))
+		}
+		return a.add(filepath.Join(name, rel), data)
+	})
+}
+
+// close lists the skipped files in the archive and flushes it.
+func (a *debugArchive) close() error {
+	if len(a.skipped) > 0 {
+		msg := "Skipped due to the archive size limit:\n" + strings.Join(a.skipped, "\n") + "\n"
+		if err := a.write("skipped.txt", []byte(msg)); err != nil {
+			return err
+		}
+	}
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	return a.gz.Close()
+}
+
+// readFileLimited reads at most limit bytes of the file at path, marking the
+// content as truncated if the file is larger.
+func readFileLimited(path string, limit int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		data = append(data[:limit:limit], truncatedMarker...)
+	}
+	return data, nil
+}
+
+// commandOutput runs the command for at most timeout and returns its combined
+// output, including the error if the command failed. On timeout, the command
+// and all processes it started are killed.
+func commandOutput(timeout time.Duration, name string, args ...string) []byte {
+	var out bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	err := cmd.Start()
+	if err == nil {
+		timer := time.AfterFunc(timeout, func() {
+			syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		})
+		err = cmd.Wait()
+		if !timer.Stop() {
+			// Killed due to the timeout
+			err = nil
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(&out, "\n%s %s: %s\n", name, strings.Join(args, " "), err)
+	}
+	return out.Bytes()
+}
+
+// dumpPinnedMaps dumps each BPF map pinned in dir in the order of the map
+// names and passes the dump to add as soon as it has been taken, so that only
+// a single dump is held in memory at a time.
+func dumpPinnedMaps(dir string, add func(name string, data []byte) error) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return add("error.txt", []byte(err.Error()+"\n"))
+	}
+
+	var buf bytes.Buffer
+	for _, f := range files {
+		buf.Reset()
+		m, err := bpf.OpenMap(filepath.Join(dir, f.Name()))
+		if err != nil {
+			fmt.Fprintf(&buf, "Unable to open map: %s\n", err)
+		} else {
+			if dump, err := dumpPinnedMap(m); err != nil {
+				fmt.Fprintf(&buf, "Unable to dump map: %s\n", err)
+			} else {
+				printMapDump(&buf, dump)
+			}
+			m.Close()
+		}
+		if err := add(f.Name()+".txt", buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDebugArchive writes the debug information of the agent and the node
+// into a gzip compressed tarball at path.
+func writeDebugArchive(path string, p *models.DebugInfo) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	prefix := fmt.Sprintf("cilium-debuginfo-%s", time.Now().UTC().Format("20060102-150405"))
+	a := newDebugArchive(f, prefix, archiveMaxFileSize, archiveMaxSize)
+
+	var report bytes.Buffer
+	w := tabwriter.NewWriter(&report, 5, 0, 3, ' ', 0)
+	addHeader(w)
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sections[name](w, p)
+	}
+	w.Flush()
+
+	stateDir := filepath.Join(defaults.RuntimePath, defaults.StateDir)
+	steps := []func() error{
+		func() error { return a.add("debuginfo.md", report.Bytes()) },
+		func() error { return a.addJSON("status.json", p.CiliumStatus) },
+		func() error { return a.addJSON("endpoints.json", p.EndpointList) },
+		func() error { return a.addJSON("policy.json", p.Policy) },
+		func() error { return a.addDir("state", stateDir) },
+		func() error {
+			return dumpPinnedMaps(bpf.MapPrefixPath(), func(name string, data []byte) error {
+				return a.add(filepath.Join("bpf-maps", name), data)
+			})
+		},
+		func() error {
+			return a.add("monitor.txt", commandOutput(archiveMonitorDuration, "cilium", "monitor", "-v"))
+		},
+		func() error {
+			return a.add("cilium-agent.log", commandOutput(archiveCommandTimeout,
+				"journalctl", "-u", "cilium", "--no-pager", "-n", fmt.Sprintf("%d", archiveLogLines)))
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+
+	return a.close()
+}
@@ -0,0 +1,121 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type DebugArchiveSuite struct{}
+
+var _ = Suite(&DebugArchiveSuite{})
+
+// readArchive returns the content of the files in the gzip compressed
+// tarball by file name.
+func readArchive(c *C, data []byte) map[string]string {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	c.Assert(err, IsNil)
+	tr := tar.NewReader(gz)
+
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, IsNil)
+		content, err := ioutil.ReadAll(tr)
+		c.Assert(err, IsNil)
+		files[hdr.Name] = string(content)
+	}
+	return files
+}
+
+func (s *DebugArchiveSuite) TestDebugArchive(c *C) {
+	dir := c.MkDir()
+	c.Assert(os.MkdirAll(filepath.Join(dir, "42"), 0755), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, "42", "lxc_config.h"), []byte("#define FOO"), 0644), IsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, "42", "bpf_lxc.o"), []byte(strings.Repeat("x", 40)), 0644), IsNil)
+
+	var buf bytes.Buffer
+	a := newDebugArchive(&buf, "debug", 24, 100)
+	c.Assert(a.add("small.txt", []byte("hello")), IsNil)
+	c.Assert(a.addJSON("status.json", map[string]string{"state": "Ok"}), IsNil)
+	c.Assert(a.addDir("state", dir), IsNil)
+	c.Assert(a.add("large.txt", []byte(strings.Repeat("y", 32))), IsNil)
+	c.Assert(a.close(), IsNil)
+
+	files := readArchive(c, buf.Bytes())
+	c.Assert(files["debug/small.txt"], Equals, "hello")
+	c.Assert(files["debug/status.json"], Equals, "{\n  \"state\": \"Ok\"\n}")
+	c.Assert(files["debug/state/42/lxc_config.h"], Equals, "#define FOO")
+	c.Assert(files["debug/state/42/bpf_lxc.o"], Equals, strings.Repeat("x", 24)+truncatedMarker)
+
+	// The remaining budget does not allow for another truncated file
+	_, ok := files["debug/large.txt"]
+	c.Assert(ok, Equals, false)
+	c.Assert(files["debug/skipped.txt"], Equals, "Skipped due to the archive size limit:\nlarge.txt\n")
+}
+
+func (s *DebugArchiveSuite) TestCommandOutput(c *C) {
+	out := commandOutput(time.Second, "sh", "-c", "echo foo; exit 3")
+	c.Assert(string(out), Matches, "foo\n\nsh -c echo foo; exit 3: exit status 3\n")
+
+	start := time.Now()
+	out = commandOutput(100*time.Millisecond, "sh", "-c", "echo bar; sleep 10")
+	c.Assert(time.Since(start) < 5*time.Second, Equals, true)
+	c.Assert(string(out), Equals, "bar\n")
+}
+
+func (s *DebugArchiveSuite) TestDumpPinnedMaps(c *C) {
+	dir := c.MkDir()
+	for _, name := range []string{"cilium_b", "cilium_a"} {
+		c.Assert(ioutil.WriteFile(filepath.Join(dir, name), nil, 0644), IsNil)
+	}
+
+	var names []string
+	err := dumpPinnedMaps(dir, func(name string, data []byte) error {
+		names = append(names, name)
+		c.Assert(strings.HasPrefix(string(data), "Unable to open map"), Equals, true)
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(names, DeepEquals, []string{"cilium_a.txt", "cilium_b.txt"})
+
+	// Errors of the archive abort the dump
+	calls := 0
+	err = dumpPinnedMaps(dir, func(name string, data []byte) error {
+		calls++
+		return io.ErrShortWrite
+	})
+	c.Assert(err, Equals, io.ErrShortWrite)
+	c.Assert(calls, Equals, 1)
+
+	err = dumpPinnedMaps(filepath.Join(dir, "missing"), func(name string, data []byte) error {
+		c.Assert(name, Equals, "error.txt")
+		return nil
+	})
+	c.Assert(err, IsNil)
+}
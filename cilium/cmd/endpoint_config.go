@@ -16,7 +16,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/endpoint"
@@ -46,6 +45,7 @@ var endpointConfigCmd = &cobra.Command{
 
 func init() {
 	endpointCmd.AddCommand(endpointConfigCmd)
+	setArgCompletion(endpointConfigCmd, completeEndpoints)
 	endpointConfigCmd.Flags().BoolVarP(&listOptions, "list-options", "", false, "List available options")
 	command.AddJSONOutput(endpointConfigCmd)
 }
@@ -67,7 +67,7 @@ func configEndpoint(cmd *cobra.Command, args []string) {
 	if len(opts) == 0 {
 		if command.OutputJSON() {
 			if err := command.PrintOutput(cfg); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
@@ -0,0 +1,86 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/spf13/cobra"
+)
+
+// endpointControllersCmd represents the endpoint_controllers command
+var endpointControllersCmd = &cobra.Command{
+	Use:     "controllers <endpoint id>",
+	Short:   "View status of endpoint controllers",
+	Example: "cilium endpoint controllers 5421",
+	Run: func(cmd *cobra.Command, args []string) {
+		requireEndpointID(cmd, args)
+		getEndpointControllers(cmd, args)
+	},
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointControllersCmd)
+	setArgCompletion(endpointControllersCmd, completeEndpoints)
+	command.AddJSONOutput(endpointControllersCmd)
+}
+
+func formatControllerTimestamp(t strfmt.DateTime) string {
+	if time.Time(t).IsZero() {
+		return "never"
+	}
+	return time.Since(time.Time(t)).Truncate(time.Second).String() + " ago"
+}
+
+func getEndpointControllers(cmd *cobra.Command, args []string) {
+	eID := args[0]
+	ctrls, err := client.EndpointControllersGet(eID)
+	if err != nil {
+		Fatalf("Cannot get controllers of endpoint %s: %s\n", eID, err)
+	}
+
+	if command.OutputJSON() {
+		if err := command.PrintOutput(ctrls); err != nil {
+			Fatalf("Unable to print output: %s", err)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "Name\tLast success\tLast error\tCount\tMessage\n")
+	for _, ctrl := range ctrls {
+		status := ctrl.Status
+		if status == nil {
+			continue
+		}
+
+		msg := "no error"
+		if status.LastFailureMsg != "" {
+			msg = status.LastFailureMsg
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", ctrl.Name,
+			formatControllerTimestamp(status.LastSuccessTimestamp),
+			formatControllerTimestamp(status.LastFailureTimestamp),
+			status.ConsecutiveFailureCount, msg)
+	}
+	w.Flush()
+}
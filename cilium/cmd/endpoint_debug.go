@@ -0,0 +1,75 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/spf13/cobra"
+)
+
+var (
+	debugDuration time.Duration
+	debugDisable  bool
+)
+
+// endpointDebugCmd represents the endpoint_debug command
+var endpointDebugCmd = &cobra.Command{
+	Use:   "debug <endpoint-id>",
+	Short: "Enable or disable debugging of an endpoint",
+	Long: `Enables the debug logs of the endpoint's code paths in the agent and
+the debug events of the endpoint's datapath without enabling debugging
+globally. Debugging is disabled again once the duration has expired or
+when --disable is given.`,
+	Example: "  cilium endpoint debug 5421 --duration 10m\n" +
+		"  cilium endpoint debug 5421 --disable",
+	PreRun: requireEndpointID,
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+		if debugDisable && cmd.Flags().Changed("duration") {
+			Fatalf("--duration cannot be used with --disable\n")
+		}
+
+		debug := &models.EndpointDebug{
+			Enabled: !debugDisable,
+		}
+		if !debugDisable {
+			debug.Duration = strfmt.Duration(debugDuration)
+		}
+		if err := client.EndpointDebugPut(id, debug); err != nil {
+			Fatalf("Cannot update debugging of endpoint %s: %s\n", id, err)
+		}
+
+		switch {
+		case debugDisable:
+			fmt.Printf("Debugging of endpoint %s disabled\n", id)
+		case debugDuration != 0:
+			fmt.Printf("Debugging of endpoint %s enabled for %s\n", id, debugDuration)
+		default:
+			fmt.Printf("Debugging of endpoint %s enabled\n", id)
+		}
+	},
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointDebugCmd)
+	setArgCompletion(endpointDebugCmd, completeEndpoints)
+	endpointDebugCmd.Flags().DurationVar(&debugDuration, "duration", 15*time.Minute, "Duration after which debugging is disabled again, 0 to never disable it")
+	endpointDebugCmd.Flags().BoolVar(&debugDisable, "disable", false, "Disable debugging of the endpoint")
+}
@@ -37,5 +37,6 @@ var endpointDisconnectCmd = &cobra.Command{
 
 func init() {
 	endpointCmd.AddCommand(endpointDisconnectCmd)
+	setArgCompletion(endpointDisconnectCmd, completeEndpoints)
 
 }
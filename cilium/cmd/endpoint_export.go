@@ -0,0 +1,151 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cilium/cilium/api/v1/models"
+	endpointid "github.com/cilium/cilium/pkg/endpoint/id"
+
+	"github.com/spf13/cobra"
+)
+
+// endpointBundleVersion is the version of the format of endpoint bundles
+const endpointBundleVersion = 1
+
+// endpointBundle is the portable description of an endpoint written by
+// 'cilium endpoint export' and read by 'cilium endpoint import' to recreate
+// the endpoint on another node, e.g. after the container of the endpoint
+// has been checkpointed and restored on that node.
+type endpointBundle struct {
+	Version int `json:"version"`
+
+	ID               int64  `json:"id"`
+	ContainerID      string `json:"container-id,omitempty"`
+	ContainerName    string `json:"container-name,omitempty"`
+	ContainerRuntime string `json:"container-runtime,omitempty"`
+	K8sPodName       string `json:"k8s-pod-name,omitempty"`
+	K8sNamespace     string `json:"k8s-namespace,omitempty"`
+	DockerEndpointID string `json:"docker-endpoint-id,omitempty"`
+	DockerNetworkID  string `json:"docker-network-id,omitempty"`
+	InterfaceName    string `json:"interface-name,omitempty"`
+	Mac              string `json:"mac,omitempty"`
+
+	// Labels are the security relevant labels of the endpoint
+	Labels models.Labels `json:"labels"`
+
+	// Identity and PolicyRevision are the identity and the policy
+	// revision of the endpoint at the time of the export. They are
+	// recorded to verify that the imported endpoint is subject to the same
+	// policy, the imported endpoint is assigned the identity for its
+	// labels and the policy revision of the node it is imported on.
+	Identity       int64 `json:"identity,omitempty"`
+	PolicyRevision int64 `json:"policy-revision,omitempty"`
+
+	Addressing *models.AddressPair     `json:"addressing,omitempty"`
+	Options    models.ConfigurationMap `json:"options,omitempty"`
+}
+
+var exportOutput string
+
+// endpointExportCmd represents the endpoint_export command
+var endpointExportCmd = &cobra.Command{
+	Use:     "export <endpoint id>",
+	Short:   "Export an endpoint for import on another node",
+	Example: "cilium endpoint export 4598 -o ep.json",
+	PreRun:  requireEndpointID,
+	Run: func(cmd *cobra.Command, args []string) {
+		_, id, _ := endpointid.ValidateID(args[0])
+		ep, err := client.EndpointGet(id)
+		if err != nil {
+			Fatalf("Cannot get endpoint %s: %s\n", id, err)
+		}
+
+		bundle, err := newEndpointBundle(ep)
+		if err != nil {
+			Fatalf("Cannot export endpoint %s: %s\n", id, err)
+		}
+
+		var w io.Writer = os.Stdout
+		if exportOutput != "" {
+			f, err := os.Create(exportOutput)
+			if err != nil {
+				Fatalf("Cannot create %s: %s\n", exportOutput, err)
+			}
+			defer f.Close()
+			w = f
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(bundle); err != nil {
+			Fatalf("Cannot write endpoint bundle: %s\n", err)
+		}
+	},
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointExportCmd)
+	setArgCompletion(endpointExportCmd, completeEndpoints)
+	endpointExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the bundle to this file instead of stdout")
+}
+
+// newEndpointBundle returns the bundle describing the endpoint ep.
+func newEndpointBundle(ep *models.Endpoint) (*endpointBundle, error) {
+	status := ep.Status
+	if status == nil {
+		return nil, fmt.Errorf("endpoint has no status")
+	}
+
+	bundle := &endpointBundle{
+		Version: endpointBundleVersion,
+		ID:      ep.ID,
+	}
+
+	if ids := status.ExternalIdentifiers; ids != nil {
+		bundle.ContainerID = ids.ContainerID
+		bundle.ContainerName = ids.ContainerName
+		bundle.ContainerRuntime = ids.ContainerRuntime
+		bundle.K8sPodName = ids.K8sPodName
+		bundle.K8sNamespace = ids.K8sNamespace
+		bundle.DockerEndpointID = ids.DockerEndpointID
+		bundle.DockerNetworkID = ids.DockerNetworkID
+	}
+	if networking := status.Networking; networking != nil {
+		bundle.InterfaceName = networking.InterfaceName
+		bundle.Mac = networking.Mac
+		if len(networking.Addressing) > 0 {
+			bundle.Addressing = networking.Addressing[0]
+		}
+	}
+	if status.Labels != nil {
+		bundle.Labels = status.Labels.SecurityRelevant
+	}
+	if status.Identity != nil {
+		bundle.Identity = status.Identity.ID
+	}
+	if status.Policy != nil && status.Policy.Realized != nil {
+		bundle.PolicyRevision = status.Policy.Realized.PolicyRevision
+	}
+	if status.Realized != nil {
+		bundle.Options = status.Realized.Options
+	}
+
+	return bundle, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *CMDHelpersSuite) TestEndpointBundle(c *C) {
+	ep := &models.Endpoint{
+		ID: 4598,
+		Status: &models.EndpointStatus{
+			ExternalIdentifiers: &models.EndpointIdentifiers{
+				ContainerID:      "c0ffee",
+				ContainerName:    "foo",
+				ContainerRuntime: "containerd",
+				K8sPodName:       "foo-7d4b9",
+				K8sNamespace:     "default",
+			},
+			Networking: &models.EndpointNetworking{
+				Addressing: []*models.AddressPair{
+					{IPV4: "10.11.0.1", IPV6: "f00d::a0b:0:0:1"},
+				},
+				InterfaceName:  "lxc12345",
+				InterfaceIndex: 12,
+				HostMac:        "0a:58:0a:00:00:01",
+				Mac:            "0a:58:0a:00:00:02",
+			},
+			Labels: &models.LabelConfigurationStatus{
+				SecurityRelevant: models.Labels{"container:app=foo", "reserved:init"},
+			},
+			Identity: &models.Identity{ID: 23456},
+			Policy: &models.EndpointPolicyStatus{
+				Realized: &models.EndpointPolicy{PolicyRevision: 7},
+			},
+			Realized: &models.EndpointConfigurationSpec{
+				Options: models.ConfigurationMap{"Policy": "enabled"},
+			},
+		},
+	}
+
+	bundle, err := newEndpointBundle(ep)
+	c.Assert(err, IsNil)
+	c.Assert(bundle.Identity, Equals, int64(23456))
+	c.Assert(bundle.PolicyRevision, Equals, int64(7))
+
+	path := filepath.Join(c.MkDir(), "ep.json")
+	data, err := json.Marshal(bundle)
+	c.Assert(err, IsNil)
+	c.Assert(ioutil.WriteFile(path, data, 0644), IsNil)
+
+	imported, err := readEndpointBundle(path)
+	c.Assert(err, IsNil)
+	c.Assert(imported, DeepEquals, bundle)
+
+	// Node specific values are not part of the bundle and reserved labels
+	// are assigned by the agent
+	c.Assert(imported.changeRequest(), DeepEquals, &models.EndpointChangeRequest{
+		ID:                4598,
+		ContainerID:       "c0ffee",
+		ContainerName:     "foo",
+		ContainerRuntime:  "containerd",
+		K8sPodName:        "foo-7d4b9",
+		K8sNamespace:      "default",
+		InterfaceName:     "lxc12345",
+		Mac:               "0a:58:0a:00:00:02",
+		Addressing:        &models.AddressPair{IPV4: "10.11.0.1", IPV6: "f00d::a0b:0:0:1"},
+		Labels:            []string{"container:app=foo"},
+		State:             models.EndpointStateWaitingForIdentity,
+		SyncBuildEndpoint: true,
+	})
+
+	bundle.Version = endpointBundleVersion + 1
+	data, err = json.Marshal(bundle)
+	c.Assert(err, IsNil)
+	c.Assert(ioutil.WriteFile(path, data, 0644), IsNil)
+	_, err = readEndpointBundle(path)
+	c.Assert(err, ErrorMatches, "unsupported bundle version 2")
+
+	_, err = newEndpointBundle(&models.Endpoint{ID: 1})
+	c.Assert(err, NotNil)
+}
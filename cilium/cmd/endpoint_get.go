@@ -18,7 +18,6 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
 
 	endpointApi "github.com/cilium/cilium/api/v1/client/endpoint"
 	"github.com/cilium/cilium/api/v1/models"
@@ -62,7 +61,7 @@ var endpointGetCmd = &cobra.Command{
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(endpointInst); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		} else {
@@ -74,7 +73,7 @@ var endpointGetCmd = &cobra.Command{
 				Fatalf("Cannot marshal endpoints %s", err.Error())
 			}
 
-			expandedResult, err := expandNestedJSON(result)
+			expandedResult, err := command.ExpandNestedJSON(result)
 			if err != nil {
 				Fatalf(err.Error())
 			}
@@ -85,6 +84,7 @@ var endpointGetCmd = &cobra.Command{
 
 func init() {
 	endpointCmd.AddCommand(endpointGetCmd)
+	setArgCompletion(endpointGetCmd, completeEndpoints)
 	endpointGetCmd.Flags().StringSliceVarP(&lbls, "labels", "l", []string{}, "list of labels")
 	command.AddJSONOutput(endpointGetCmd)
 }
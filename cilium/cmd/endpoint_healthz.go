@@ -19,6 +19,7 @@ import (
 	"os"
 	"text/tabwriter"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/command"
 
 	"github.com/spf13/cobra"
@@ -37,6 +38,7 @@ var endpointHealthCmd = &cobra.Command{
 
 func init() {
 	endpointCmd.AddCommand(endpointHealthCmd)
+	setArgCompletion(endpointHealthCmd, completeEndpoints)
 	command.AddJSONOutput(endpointHealthCmd)
 }
 
@@ -50,7 +52,7 @@ func getEndpointHealth(cmd *cobra.Command, args []string) {
 
 	if command.OutputJSON() {
 		if err := command.PrintOutput(epHealth); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to print output: %s", err)
 		}
 	} else {
 		w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
@@ -59,6 +61,42 @@ func getEndpointHealth(cmd *cobra.Command, args []string) {
 		fmt.Fprintf(w, "Policy Health:\t%s\n", epHealth.Policy)
 		connected := map[bool]string{true: "yes", false: "no"}
 		fmt.Fprintf(w, "Connected:\t%s\n", connected[epHealth.Connected])
+		fmt.Fprintf(w, "Policy Sync:\t%s\n", formatSubsystemHealth(epHealth.PolicySync))
+		fmt.Fprintf(w, "BPF Sync:\t%s\n", formatSubsystemHealth(epHealth.BpfSync))
+		fmt.Fprintf(w, "Proxy Sync:\t%s\n", formatSubsystemHealth(epHealth.ProxySync))
+		fmt.Fprintf(w, "Connectivity:\t%s\n", formatSubsystemHealth(epHealth.Connectivity))
+		for _, r := range epHealth.ProxyRedirects {
+			fmt.Fprintf(w, "Proxy Redirect %s:\t%s\n", r.ID, formatProxyRedirectHealth(r))
+		}
 		w.Flush()
 	}
 }
+
+// formatSubsystemHealth returns the status of the subsystem followed by the
+// cause of the status, if any.
+func formatSubsystemHealth(h *models.EndpointSubsystemHealth) string {
+	if h == nil {
+		return "unknown"
+	}
+	if h.Cause == "" {
+		return h.Status
+	}
+	return fmt.Sprintf("%s (%s)", h.Status, h.Cause)
+}
+
+// formatProxyRedirectHealth returns the protocol and port of the proxy
+// redirect followed by its state and last error, if any.
+func formatProxyRedirectHealth(r *models.ProxyRedirectHealth) string {
+	state := "listening"
+	switch {
+	case !r.ListenerBound:
+		state = "not listening"
+	case !r.RulesLoaded:
+		state = "rules not loaded"
+	}
+	s := fmt.Sprintf("%s on port %d, %s", r.Protocol, r.ProxyPort, state)
+	if r.LastError != "" {
+		s += fmt.Sprintf(" (last error at %s: %s)", r.LastErrorTime, r.LastError)
+	}
+	return s
+}
@@ -0,0 +1,152 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/labels"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	importID             int64
+	importInterfaceName  string
+	importInterfaceIndex int64
+	importHostMac        string
+)
+
+// endpointImportCmd represents the endpoint_import command
+var endpointImportCmd = &cobra.Command{
+	Use:   "import ( <bundle file> | - )",
+	Short: "Recreate an endpoint exported on another node",
+	Long: `Recreate an endpoint from a bundle written by 'cilium endpoint export'.
+
+The network interface of the endpoint must have been set up on this node
+before the endpoint is imported. The interface index and the MAC address of
+the host side of the interface are specific to this node and must be
+provided.`,
+	Example: "cilium endpoint import ep.json --interface-index 42 --host-mac 0a:58:0a:00:00:01",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			Usagef(cmd, "Missing endpoint bundle argument")
+		}
+
+		bundle, err := readEndpointBundle(args[0])
+		if err != nil {
+			Fatalf("Cannot read endpoint bundle %s: %s\n", args[0], err)
+		}
+
+		ep := bundle.changeRequest()
+		if importID != 0 {
+			ep.ID = importID
+		}
+		if importInterfaceName != "" {
+			ep.InterfaceName = importInterfaceName
+		}
+		ep.InterfaceIndex = importInterfaceIndex
+		ep.HostMac = importHostMac
+
+		id := strconv.FormatInt(ep.ID, 10)
+		if err := client.EndpointCreate(ep); err != nil {
+			Fatalf("Cannot create endpoint %s: %s\n", id, err)
+		}
+
+		if len(bundle.Options) > 0 {
+			cfg := &models.EndpointConfigurationSpec{Options: bundle.Options}
+			if err := client.EndpointConfigPatch(id, cfg); err != nil {
+				Fatalf("Cannot configure endpoint %s: %s\n", id, err)
+			}
+		}
+
+		fmt.Printf("Endpoint %s successfully imported\n", id)
+
+		imported, err := client.EndpointGet(id)
+		if err != nil {
+			Fatalf("Cannot get endpoint %s: %s\n", id, err)
+		}
+		if status := imported.Status; status != nil && status.Identity != nil &&
+			bundle.Identity != 0 && status.Identity.ID != bundle.Identity {
+			fmt.Fprintf(os.Stderr, "Warning: endpoint %s has identity %d, it had identity %d when it was exported\n",
+				id, status.Identity.ID, bundle.Identity)
+		}
+	},
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointImportCmd)
+	endpointImportCmd.Flags().Int64Var(&importID, "id", 0, "Endpoint ID to use instead of the exported endpoint ID")
+	endpointImportCmd.Flags().StringVar(&importInterfaceName, "interface-name", "", "Name of the host side interface if different from the exported endpoint")
+	endpointImportCmd.Flags().Int64Var(&importInterfaceIndex, "interface-index", 0, "Index of the host side interface")
+	endpointImportCmd.Flags().StringVar(&importHostMac, "host-mac", "", "MAC address of the host side interface")
+}
+
+// readEndpointBundle reads the endpoint bundle from the file at path, or
+// from stdin if path is "-".
+func readEndpointBundle(path string) (*endpointBundle, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	bundle := &endpointBundle{}
+	if err := json.NewDecoder(r).Decode(bundle); err != nil {
+		return nil, err
+	}
+	if bundle.Version != endpointBundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", bundle.Version)
+	}
+	return bundle, nil
+}
+
+// changeRequest returns the request to create the endpoint described by the
+// bundle. Reserved labels are assigned by the agent and are not part of the
+// request.
+func (b *endpointBundle) changeRequest() *models.EndpointChangeRequest {
+	lbls := labels.Labels{}
+	for k, l := range labels.NewLabelsFromModel(b.Labels) {
+		if l.Source != labels.LabelSourceReserved {
+			lbls[k] = l
+		}
+	}
+
+	return &models.EndpointChangeRequest{
+		ID:                b.ID,
+		ContainerID:       b.ContainerID,
+		ContainerName:     b.ContainerName,
+		ContainerRuntime:  b.ContainerRuntime,
+		K8sPodName:        b.K8sPodName,
+		K8sNamespace:      b.K8sNamespace,
+		DockerEndpointID:  b.DockerEndpointID,
+		DockerNetworkID:   b.DockerNetworkID,
+		InterfaceName:     b.InterfaceName,
+		Mac:               b.Mac,
+		Addressing:        b.Addressing,
+		Labels:            lbls.GetModel(),
+		State:             models.EndpointStateWaitingForIdentity,
+		SyncBuildEndpoint: true,
+	}
+}
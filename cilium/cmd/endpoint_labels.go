@@ -15,10 +15,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"text/tabwriter"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/color"
 	endpointid "github.com/cilium/cilium/pkg/endpoint/id"
 	"github.com/cilium/cilium/pkg/labels"
@@ -29,16 +33,51 @@ import (
 )
 
 var (
-	toAdd    []string
-	toDelete []string
+	toAdd          []string
+	toDelete       []string
+	labelsFromFile string
 )
 
 // endpointLabelsCmd represents the endpoint_labels command
 var endpointLabelsCmd = &cobra.Command{
-	Use:    "labels",
-	Short:  "Manage label configuration of endpoint",
-	PreRun: requireEndpointID,
+	Use:   "labels ( <endpoint id> | --from-file <file> )",
+	Short: "Manage label configuration of endpoint",
+	Long: `Manage label configuration of endpoint.
+
+With --from-file, label operations read from a JSON file are applied to all
+endpoints matched by the selector of each operation in a single request to
+the agent. Each operation adds and deletes labels of all endpoints which have
+all labels of its selector:
+
+  {
+    "operations": [
+      {
+        "selector": ["k8s:app=web"],
+        "add": ["tier=frontend"],
+        "delete": ["tier=legacy"]
+      }
+    ]
+  }
+
+All operations are validated before any endpoint is modified. The file is
+read from standard input if it is '-'.`,
+	Example: `  cilium endpoint labels 5421 --add tier=frontend
+  cilium endpoint labels --from-file labels.json`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if labelsFromFile != "" {
+			if len(args) > 0 || len(toAdd) > 0 || len(toDelete) > 0 {
+				Usagef(cmd, "--from-file cannot be combined with an endpoint id, --add or --delete")
+			}
+			return
+		}
+		requireEndpointID(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if labelsFromFile != "" {
+			runEndpointLabelsBatch(labelsFromFile)
+			return
+		}
+
 		_, id, _ := endpointid.ValidateID(args[0])
 		addLabels := labels.NewLabelsFromModel(toAdd).GetModel()
 
@@ -64,8 +103,88 @@ var endpointLabelsCmd = &cobra.Command{
 
 func init() {
 	endpointCmd.AddCommand(endpointLabelsCmd)
+	setArgCompletion(endpointLabelsCmd, completeEndpoints)
 	endpointLabelsCmd.Flags().StringSliceVarP(&toAdd, "add", "a", []string{}, "Add/enable labels")
 	endpointLabelsCmd.Flags().StringSliceVarP(&toDelete, "delete", "d", []string{}, "Delete/disable labels")
+	endpointLabelsCmd.Flags().StringVar(&labelsFromFile, "from-file", "", "Apply label operations from a JSON file to all endpoints matching their selectors")
+}
+
+// readLabelsBatch parses the label operations in r and checks that each
+// operation has a selector and labels to add or delete.
+func readLabelsBatch(r io.Reader) (*models.EndpointLabelsBatch, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &models.EndpointLabelsBatch{}
+	if err := json.Unmarshal(content, batch); err != nil {
+		return nil, fmt.Errorf("invalid label operations: %s", err)
+	}
+	if len(batch.Operations) == 0 {
+		return nil, fmt.Errorf("no label operations found")
+	}
+	for i, op := range batch.Operations {
+		switch {
+		case op == nil:
+			return nil, fmt.Errorf("operation %d: empty operation", i)
+		case len(op.Selector) == 0:
+			return nil, fmt.Errorf("operation %d: selector must not be empty", i)
+		case len(op.Add) == 0 && len(op.Delete) == 0:
+			return nil, fmt.Errorf("operation %d: no labels to add or delete", i)
+		}
+	}
+
+	return batch, nil
+}
+
+// printLabelsBatchResults writes the result of each endpoint to w and
+// returns the number of endpoints which could not be modified.
+func printLabelsBatchResults(w io.Writer, results []*models.EndpointLabelsResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			fmt.Fprintf(w, "Endpoint %d (operation %d): cannot modify labels: %s\n", r.ID, r.Operation, r.Error)
+		} else {
+			fmt.Fprintf(w, "Endpoint %d (operation %d): labels modified\n", r.ID, r.Operation)
+		}
+	}
+	return failed
+}
+
+func runEndpointLabelsBatch(path string) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			Fatalf("Cannot open label operations: %s", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	batch, err := readLabelsBatch(r)
+	if err != nil {
+		Exitf(ExitInvalid, "Cannot read %s: %s", path, err)
+	}
+
+	results, err := client.EndpointLabelsBatchPatch(batch)
+	if err != nil {
+		Fatalf("Cannot modify endpoint labels: %s", err)
+	}
+	if len(results) == 0 {
+		Exitf(ExitNotFound, "No endpoint matched any selector")
+	}
+
+	failed := printLabelsBatchResults(os.Stdout, results)
+	fmt.Printf("Modified labels of %d/%d endpoints\n", len(results)-failed, len(results))
+	switch {
+	case failed == len(results):
+		Exitf(ExitFailure, "Cannot modify labels of any endpoint")
+	case failed > 0:
+		Exitf(ExitPartialSuccess, "Cannot modify labels of %d/%d endpoints", failed, len(results))
+	}
 }
 
 // printEndpointLabels pretty prints labels with tabs
@@ -0,0 +1,64 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+type EndpointLabelsSuite struct{}
+
+var _ = Suite(&EndpointLabelsSuite{})
+
+func (s *EndpointLabelsSuite) TestReadLabelsBatch(c *C) {
+	batch, err := readLabelsBatch(strings.NewReader(`{"operations": [
+		{"selector": ["k8s:app=web"], "add": ["tier=frontend"], "delete": ["tier=legacy"]},
+		{"selector": ["k8s:app=db"], "delete": ["debug"]}
+	]}`))
+	c.Assert(err, IsNil)
+	c.Assert(batch.Operations, HasLen, 2)
+	c.Assert(batch.Operations[0].Selector, DeepEquals, models.Labels{"k8s:app=web"})
+	c.Assert(batch.Operations[0].Add, DeepEquals, models.Labels{"tier=frontend"})
+	c.Assert(batch.Operations[0].Delete, DeepEquals, models.Labels{"tier=legacy"})
+	c.Assert(batch.Operations[1].Add, HasLen, 0)
+
+	invalid := map[string]string{
+		`not json`:                                  "invalid label operations: .*",
+		`{"operations": []}`:                        "no label operations found",
+		`{"operations": [null]}`:                    "operation 0: empty operation",
+		`{"operations": [{"add": ["a"]}]}`:          "operation 0: selector must not be empty",
+		`{"operations": [{"selector": ["k8s:a"]}]}`: "operation 0: no labels to add or delete",
+	}
+	for input, msg := range invalid {
+		_, err := readLabelsBatch(strings.NewReader(input))
+		c.Assert(err, ErrorMatches, msg, Commentf("input %s", input))
+	}
+}
+
+func (s *EndpointLabelsSuite) TestPrintLabelsBatchResults(c *C) {
+	var buf bytes.Buffer
+	failed := printLabelsBatchResults(&buf, []*models.EndpointLabelsResult{
+		{ID: 10, Operation: 0},
+		{ID: 11, Operation: 1, Error: "label tier not found"},
+	})
+	c.Assert(failed, Equals, 1)
+	c.Assert(buf.String(), Equals, "Endpoint 10 (operation 0): labels modified\n"+
+		"Endpoint 11 (operation 1): cannot modify labels: label tier not found\n")
+}
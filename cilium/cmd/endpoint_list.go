@@ -20,21 +20,32 @@ import (
 	"sort"
 	"text/tabwriter"
 
+	endpointApi "github.com/cilium/cilium/api/v1/client/endpoint"
 	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/api"
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/labels"
+	policyApi "github.com/cilium/cilium/pkg/policy/api"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// PolicyEnabled and PolicyDisabled represent the endpoint policy status
+// PolicyEnabled, PolicyDisabled and PolicyQuarantined represent the endpoint
+// policy status
 const (
-	PolicyEnabled  = "Enabled"
-	PolicyDisabled = "Disabled"
-	UnknownState   = "Unknown"
+	PolicyEnabled     = "Enabled"
+	PolicyDisabled    = "Disabled"
+	PolicyQuarantined = "Quarantined"
+	UnknownState      = "Unknown"
 )
 
-var noHeaders bool
+var (
+	noHeaders        bool
+	listLabels       []string
+	endpointSelector string
+)
 
 // endpointListCmd represents the endpoint_list command
 var endpointListCmd = &cobra.Command{
@@ -49,6 +60,8 @@ var endpointListCmd = &cobra.Command{
 func init() {
 	endpointCmd.AddCommand(endpointListCmd)
 	endpointListCmd.Flags().BoolVar(&noHeaders, "no-headers", false, "Do not print headers")
+	endpointListCmd.Flags().StringSliceVarP(&listLabels, "labels", "l", []string{}, "Only list endpoints with all of the given labels (source:key=value)")
+	endpointListCmd.Flags().StringVar(&endpointSelector, "selector", "", "Only list endpoints matching the label selector (e.g. 'k8s.app=web,env in (prod,staging)')")
 	command.AddJSONOutput(endpointListCmd)
 }
 
@@ -57,6 +70,10 @@ func endpointPolicyMode(ep *models.Endpoint) (string, string) {
 		return UnknownState, UnknownState
 	}
 
+	if ep.Status.Quarantine != nil && ep.Status.Quarantine.Enabled {
+		return PolicyQuarantined, PolicyQuarantined
+	}
+
 	switch ep.Status.Policy.Realized.PolicyEnabled {
 	case models.EndpointPolicyEnabledNone:
 		return PolicyDisabled, PolicyDisabled
@@ -110,23 +127,96 @@ func endpointID(ep *models.Endpoint) string {
 	return id
 }
 
+// endpointWorkload returns the namespace and name of the pod of the endpoint
+// and the container runtime managing its container.
+func endpointWorkload(ep *models.Endpoint) (string, string) {
+	if ep.Status == nil || ep.Status.ExternalIdentifiers == nil {
+		return "", ""
+	}
+
+	ids := ep.Status.ExternalIdentifiers
+	pod := ""
+	if ids.K8sPodName != "" {
+		pod = ids.K8sNamespace + "/" + ids.K8sPodName
+	}
+	return pod, ids.ContainerRuntime
+}
+
 func listEndpoint(w *tabwriter.Writer, ep *models.Endpoint, id string, label string) {
 	policyIngress, policyEgress := endpointPolicyMode(ep)
 	ipv6, ipv4 := endpointAddressPair(ep)
+	pod, runtime := endpointWorkload(ep)
+
+	fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", ep.ID,
+		policyIngress, policyEgress, id, label, ipv6, ipv4, endpointState(ep), pod, runtime)
+}
 
-	fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", ep.ID,
-		policyIngress, policyEgress, id, label, ipv6, ipv4, endpointState(ep))
+// getEndpoints returns the endpoints carrying all of the given labels, or all
+// endpoints if no labels are given.
+func getEndpoints(lbls []string) ([]*models.Endpoint, error) {
+	if len(lbls) == 0 {
+		eps, err := client.EndpointList()
+		if err != nil {
+			return nil, fmt.Errorf("cannot get endpoint list: %s", err)
+		}
+		return eps, nil
+	}
+
+	params := endpointApi.NewGetEndpointParams().WithLabels(lbls).WithTimeout(api.ClientTimeout)
+	result, err := client.Endpoint.GetEndpoint(params)
+	switch err.(type) {
+	case nil:
+		return result.Payload, nil
+	case *endpointApi.GetEndpointNotFound:
+		// No endpoint carries the requested labels
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("cannot get endpoints for given list of labels %s: %s", lbls, err)
+	}
 }
 
 func listEndpoints() {
-	eps, err := client.EndpointList()
+	eps, err := getEndpoints(listLabels)
 	if err != nil {
-		Fatalf("cannot get endpoint list: %s\n", err)
+		Fatalf("%s\n", err)
 	}
+
+	if endpointSelector != "" {
+		var err error
+		eps, err = filterEndpointsBySelector(eps, endpointSelector)
+		if err != nil {
+			Fatalf("invalid selector %q: %s\n", endpointSelector, err)
+		}
+	}
+
 	w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
 	printEndpointList(w, eps)
 }
 
+// filterEndpointsBySelector returns the endpoints whose security relevant
+// labels are matched by the given label selector. Selector keys may be
+// prefixed with the label source, e.g. `k8s.app=web`; keys without a source
+// match labels of any source.
+func filterEndpointsBySelector(eps []*models.Endpoint, selector string) ([]*models.Endpoint, error) {
+	ls, err := metav1.ParseToLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	es := policyApi.NewESFromK8sLabelSelector("", ls)
+
+	var filtered []*models.Endpoint
+	for _, ep := range eps {
+		var lbls labels.LabelArray
+		if ep.Status != nil && ep.Status.Labels != nil {
+			lbls = labels.ParseLabelArrayFromArray(ep.Status.Labels.SecurityRelevant)
+		}
+		if es.Matches(lbls) {
+			filtered = append(filtered, ep)
+		}
+	}
+	return filtered, nil
+}
+
 func printEndpointList(w *tabwriter.Writer, eps []*models.Endpoint) {
 	endpoint.OrderEndpointAsc(eps)
 
@@ -140,17 +230,20 @@ func printEndpointList(w *tabwriter.Writer, eps []*models.Endpoint) {
 		policyIngressTitle = "POLICY (ingress)"
 		policyEgressTitle  = "POLICY (egress)"
 		enforcementTitle   = "ENFORCEMENT"
+		podTitle           = "POD"
+		runtimeTitle       = "RUNTIME"
 	)
 
 	if !noHeaders {
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
-			endpointTitle, policyIngressTitle, policyEgressTitle, labelsIDTitle, labelsDesTitle, ipv6Title, ipv4Title, statusTitle)
-		fmt.Fprintf(w, "\t%s\t%s\t\t\t\t\t\n", enforcementTitle, enforcementTitle)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			endpointTitle, policyIngressTitle, policyEgressTitle, labelsIDTitle, labelsDesTitle, ipv6Title, ipv4Title, statusTitle,
+			podTitle, runtimeTitle)
+		fmt.Fprintf(w, "\t%s\t%s\t\t\t\t\t\t\t\n", enforcementTitle, enforcementTitle)
 	}
 
 	if command.OutputJSON() {
 		if err := command.PrintOutput(eps); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to print output: %s", err)
 		}
 		return
 	}
@@ -160,7 +253,7 @@ func printEndpointList(w *tabwriter.Writer, eps []*models.Endpoint) {
 			if i == 0 {
 				listEndpoint(w, ep, endpointID(ep), lbl)
 			} else {
-				fmt.Fprintf(w, "\t\t\t\t%s\t\t\t\t\n", lbl)
+				fmt.Fprintf(w, "\t\t\t\t%s\t\t\t\t\t\t\n", lbl)
 			}
 		}
 	}
@@ -0,0 +1,92 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+type EndpointListSuite struct{}
+
+var _ = Suite(&EndpointListSuite{})
+
+func (s *EndpointListSuite) TestFilterEndpointsBySelector(c *C) {
+	newEndpoint := func(id int64, lbls ...string) *models.Endpoint {
+		return &models.Endpoint{
+			ID: id,
+			Status: &models.EndpointStatus{
+				Labels: &models.LabelConfigurationStatus{SecurityRelevant: lbls},
+			},
+		}
+	}
+
+	eps := []*models.Endpoint{
+		newEndpoint(1, "k8s:app=web", "k8s:env=prod"),
+		newEndpoint(2, "k8s:app=web", "k8s:env=dev"),
+		newEndpoint(3, "container:app=db"),
+		{ID: 4},
+	}
+
+	ids := func(eps []*models.Endpoint) []int64 {
+		res := []int64{}
+		for _, ep := range eps {
+			res = append(res, ep.ID)
+		}
+		return res
+	}
+
+	tests := []struct {
+		selector string
+		expected []int64
+	}{
+		{"app=web", []int64{1, 2}},
+		{"k8s.app=web", []int64{1, 2}},
+		{"container.app", []int64{3}},
+		{"app", []int64{1, 2, 3}},
+		{"k8s.app=web,env notin (prod)", []int64{2}},
+		{"app in (web,db)", []int64{1, 2, 3}},
+		{"!app", []int64{4}},
+		{"app=nginx", []int64{}},
+	}
+
+	for _, tt := range tests {
+		filtered, err := filterEndpointsBySelector(eps, tt.selector)
+		c.Assert(err, IsNil, Commentf("selector %q", tt.selector))
+		c.Assert(ids(filtered), DeepEquals, tt.expected, Commentf("selector %q", tt.selector))
+	}
+
+	_, err := filterEndpointsBySelector(eps, "app!=web")
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *EndpointListSuite) TestEndpointWorkload(c *C) {
+	pod, runtime := endpointWorkload(&models.Endpoint{})
+	c.Assert(pod, Equals, "")
+	c.Assert(runtime, Equals, "")
+
+	pod, runtime = endpointWorkload(&models.Endpoint{
+		Status: &models.EndpointStatus{
+			ExternalIdentifiers: &models.EndpointIdentifiers{
+				K8sNamespace:     "default",
+				K8sPodName:       "web-0",
+				ContainerRuntime: "containerd",
+			},
+		},
+	})
+	c.Assert(pod, Equals, "default/web-0")
+	c.Assert(runtime, Equals, "containerd")
+}
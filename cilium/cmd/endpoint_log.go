@@ -37,6 +37,7 @@ var endpointLogCmd = &cobra.Command{
 
 func init() {
 	endpointCmd.AddCommand(endpointLogCmd)
+	setArgCompletion(endpointLogCmd, completeEndpoints)
 	command.AddJSONOutput(endpointLogCmd)
 }
 
@@ -50,7 +51,7 @@ func getEndpointLog(cmd *cobra.Command, args []string) {
 
 	if command.OutputJSON() {
 		if err := command.PrintOutput(epLog); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to print output: %s", err)
 		}
 	} else {
 		w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
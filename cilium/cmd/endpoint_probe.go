@@ -0,0 +1,318 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/command"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	"github.com/spf13/cobra"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	probeLayerAgent = "agent"
+	probeLayerL2    = "L2"
+	probeLayerL3    = "L3"
+	probeLayerL4    = "L4"
+	probeLayerProxy = "proxy"
+
+	probeSkip = "SKIP"
+)
+
+var (
+	endpointProbePorts   []int
+	endpointProbeTimeout time.Duration
+)
+
+// endpointHealthzCmd represents the endpoint_healthz command
+var endpointHealthzCmd = &cobra.Command{
+	Use:   "healthz <endpoint id>",
+	Short: "Actively probe the datapath of an endpoint",
+	Long: `Actively probe the datapath of an endpoint layer by layer and report the
+first layer which fails:
+
+  agent  The endpoint is known to the agent and in ready state
+  L2     The host side interface of the endpoint exists and is up
+  L3     A route to each endpoint IP exists and the IP answers ICMP echo requests
+  L4     TCP connections to the ports given with --port can be established
+  proxy  For each L7 redirect of the endpoint, the proxy accepts connections
+         on its port and the policy map of the endpoint redirects to it
+
+The policy map is only inspected with root privileges, ICMP probes require
+root privileges as well. Unlike 'cilium endpoint health', which reports the
+state known to the agent, this command sends traffic to the endpoint. It exits
+with a non-zero status if any check fails.`,
+	Example: "  cilium endpoint healthz 5421 --port 80",
+	PreRun:  requireEndpointID,
+	Run: func(cmd *cobra.Command, args []string) {
+		runEndpointHealthz(args[0])
+	},
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointHealthzCmd)
+	setArgCompletion(endpointHealthzCmd, completeEndpoints)
+	endpointHealthzCmd.Flags().IntSliceVar(&endpointProbePorts, "port", []int{}, "TCP ports to probe on the endpoint")
+	endpointHealthzCmd.Flags().DurationVar(&endpointProbeTimeout, "timeout", 2*time.Second, "Timeout of each probe")
+	command.AddJSONOutput(endpointHealthzCmd)
+}
+
+// endpointCheck is the result of a single check of an endpoint probe.
+type endpointCheck struct {
+	Layer  string `json:"layer"`
+	Check  string `json:"check"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// endpointProbeReport is the result of probing an endpoint.
+type endpointProbeReport struct {
+	Endpoint string          `json:"endpoint"`
+	Checks   []endpointCheck `json:"checks"`
+
+	// FailedLayer is the first layer with a failed check, empty if all
+	// checks passed
+	FailedLayer string `json:"failed-layer,omitempty"`
+}
+
+// add appends the result of a check to the report. err is the reason of
+// the failure, nil if the check passed.
+func (r *endpointProbeReport) add(layer, check string, err error, detail string) {
+	c := endpointCheck{Layer: layer, Check: check, Status: connectivityPass, Detail: detail}
+	if err != nil {
+		c.Status = connectivityFail
+		c.Detail = err.Error()
+		if r.FailedLayer == "" {
+			r.FailedLayer = layer
+		}
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+// skip appends a check which could not be performed to the report.
+func (r *endpointProbeReport) skip(layer, check, reason string) {
+	r.Checks = append(r.Checks, endpointCheck{Layer: layer, Check: check, Status: probeSkip, Detail: reason})
+}
+
+// checkLink checks that the host side interface of the endpoint is up.
+func checkLink(name string) error {
+	if name == "" {
+		return fmt.Errorf("endpoint has no interface")
+	}
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return fmt.Errorf("interface %s: %s", name, err)
+	}
+	if link.Attrs().Flags&net.FlagUp == 0 {
+		return fmt.Errorf("interface %s is down", name)
+	}
+	return nil
+}
+
+// checkRoute checks that the host has a route to ip and returns the name of
+// the interface of the route.
+func checkRoute(ip string) (string, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return "", fmt.Errorf("invalid IP %s", ip)
+	}
+	routes, err := netlink.RouteGet(addr)
+	if err != nil {
+		return "", err
+	}
+	if len(routes) == 0 {
+		return "", fmt.Errorf("no route to %s", ip)
+	}
+	if link, err := netlink.LinkByIndex(routes[0].LinkIndex); err == nil {
+		return "via " + link.Attrs().Name, nil
+	}
+	return "", nil
+}
+
+// proxyTrafficDirection returns the policy map direction of the location of
+// a proxy redirect.
+func proxyTrafficDirection(location string) policymap.TrafficDirection {
+	if location == models.ProxyStatisticsLocationEgress {
+		return policymap.Egress
+	}
+	return policymap.Ingress
+}
+
+// findRedirect returns an error unless entries contain an entry redirecting
+// TCP traffic to the port and direction of the proxy redirect.
+func findRedirect(entries policymap.PolicyEntriesDump, redirect *models.ProxyStatistics) error {
+	port := byteorder.HostToNetwork(uint16(redirect.Port)).(uint16)
+	proxyPort := byteorder.HostToNetwork(uint16(redirect.AllocatedProxyPort)).(uint16)
+	direction := uint8(proxyTrafficDirection(redirect.Location))
+
+	for _, e := range entries {
+		if e.Key.DestPort != port || e.Key.Nexthdr != uint8(u8proto.TCP) ||
+			e.Key.TrafficDirection != direction {
+			continue
+		}
+		if e.ProxyPort == proxyPort {
+			return nil
+		}
+		return fmt.Errorf("policy map redirects to port %d instead of %d",
+			byteorder.NetworkToHost(e.ProxyPort).(uint16), redirect.AllocatedProxyPort)
+	}
+	return fmt.Errorf("no policy map entry for %s port %d/TCP", redirect.Location, redirect.Port)
+}
+
+// readPolicyMap returns the contents of the policy map of endpoint id.
+func readPolicyMap(id string) (policymap.PolicyEntriesDump, error) {
+	fd, err := bpf.ObjGet(bpf.MapPath(policymap.MapName + id))
+	if err != nil {
+		return nil, err
+	}
+	defer bpf.ObjClose(fd)
+
+	m := policymap.PolicyMap{Fd: fd}
+	return m.DumpToSlice()
+}
+
+// probeEndpoint probes the datapath of ep layer by layer. TCP probes are
+// skipped if a lower layer failed as they would only time out.
+func probeEndpoint(ep *models.Endpoint, ports []int, timeout time.Duration) *endpointProbeReport {
+	id := strconv.FormatInt(ep.ID, 10)
+	r := &endpointProbeReport{Endpoint: id}
+
+	var state models.EndpointState
+	if ep.Status != nil {
+		state = ep.Status.State
+	}
+	var err error
+	if state != models.EndpointStateReady {
+		err = fmt.Errorf("endpoint is in state %q", state)
+	}
+	r.add(probeLayerAgent, "state", err, string(state))
+
+	var ifName string
+	if ep.Status != nil && ep.Status.Networking != nil {
+		ifName = ep.Status.Networking.InterfaceName
+	}
+	r.add(probeLayerL2, "link", checkLink(ifName), ifName)
+
+	ips := endpointIPs(ep)
+	if len(ips) == 0 {
+		r.add(probeLayerL3, "address", fmt.Errorf("no IP address"), "")
+	}
+	icmp := icmpProbe(timeout)
+	for _, ip := range ips {
+		via, err := checkRoute(ip)
+		r.add(probeLayerL3, "route "+ip, err, via)
+		if err != nil {
+			continue
+		}
+		rtt, err := icmp(ip)
+		r.add(probeLayerL3, "ICMP "+ip, err, rtt.String())
+	}
+
+	for _, port := range ports {
+		tcp := tcpProbe(port, timeout)
+		for _, ip := range ips {
+			check := fmt.Sprintf("TCP %s", net.JoinHostPort(ip, strconv.Itoa(port)))
+			if r.FailedLayer != "" {
+				r.skip(probeLayerL4, check, "lower layer failed")
+				continue
+			}
+			rtt, err := tcp(ip)
+			r.add(probeLayerL4, check, err, rtt.String())
+		}
+	}
+
+	var redirects []*models.ProxyStatistics
+	if ep.Status != nil && ep.Status.Policy != nil {
+		redirects = ep.Status.Policy.ProxyStatistics
+	}
+	var (
+		entries policymap.PolicyEntriesDump
+		mapErr  error
+	)
+	if len(redirects) > 0 {
+		if os.Getuid() != 0 {
+			mapErr = fmt.Errorf("requires root privileges")
+		} else {
+			entries, mapErr = readPolicyMap(id)
+		}
+	}
+	for _, redirect := range redirects {
+		if redirect == nil {
+			continue
+		}
+		name := fmt.Sprintf("%s %s %d/TCP", redirect.Protocol, redirect.Location, redirect.Port)
+
+		rtt, err := tcpProbe(int(redirect.AllocatedProxyPort), timeout)("127.0.0.1")
+		r.add(probeLayerProxy, name+" listener", err, fmt.Sprintf("port %d, %s", redirect.AllocatedProxyPort, rtt))
+
+		if mapErr != nil {
+			r.skip(probeLayerProxy, name+" redirect", mapErr.Error())
+			continue
+		}
+		r.add(probeLayerProxy, name+" redirect", findRedirect(entries, redirect), "")
+	}
+
+	return r
+}
+
+// printEndpointProbeReport writes the report as a table to w.
+func printEndpointProbeReport(w io.Writer, r *endpointProbeReport) {
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "LAYER\tCHECK\tSTATUS\tDETAIL\n")
+	for _, c := range r.Checks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", c.Layer, c.Check, c.Status, c.Detail)
+	}
+	tw.Flush()
+
+	if r.FailedLayer == "" {
+		fmt.Fprintf(w, "\nAll checks of endpoint %s passed\n", r.Endpoint)
+	} else {
+		fmt.Fprintf(w, "\nEndpoint %s fails at layer %s\n", r.Endpoint, r.FailedLayer)
+	}
+}
+
+func runEndpointHealthz(id string) {
+	ep, err := client.EndpointGet(id)
+	if err != nil {
+		Fatalf("Cannot get endpoint %s: %s", id, err)
+	}
+
+	r := probeEndpoint(ep, endpointProbePorts, endpointProbeTimeout)
+
+	if command.OutputJSON() {
+		if err := command.PrintOutput(r); err != nil {
+			Fatalf("Unable to provide JSON output: %s", err)
+		}
+	} else {
+		printEndpointProbeReport(os.Stdout, r)
+	}
+
+	if r.FailedLayer != "" {
+		Fatalf("Endpoint %s fails at layer %s", id, r.FailedLayer)
+	}
+}
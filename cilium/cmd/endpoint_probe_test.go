@@ -0,0 +1,82 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	. "gopkg.in/check.v1"
+)
+
+type EndpointProbeSuite struct{}
+
+var _ = Suite(&EndpointProbeSuite{})
+
+func policyEntry(dport, proxyPort uint16, direction policymap.TrafficDirection) policymap.PolicyEntryDump {
+	e := policymap.PolicyEntryDump{}
+	e.Key.DestPort = byteorder.HostToNetwork(dport).(uint16)
+	e.Key.Nexthdr = uint8(u8proto.TCP)
+	e.Key.TrafficDirection = uint8(direction)
+	e.ProxyPort = byteorder.HostToNetwork(proxyPort).(uint16)
+	return e
+}
+
+func (s *EndpointProbeSuite) TestFindRedirect(c *C) {
+	entries := policymap.PolicyEntriesDump{
+		policyEntry(80, 10001, policymap.Ingress),
+		policyEntry(9092, 10002, policymap.Egress),
+	}
+
+	redirect := &models.ProxyStatistics{
+		Location:           models.ProxyStatisticsLocationIngress,
+		Port:               80,
+		AllocatedProxyPort: 10001,
+	}
+	c.Assert(findRedirect(entries, redirect), IsNil)
+
+	redirect.AllocatedProxyPort = 10005
+	c.Assert(findRedirect(entries, redirect), ErrorMatches, "policy map redirects to port 10001 instead of 10005")
+
+	redirect.Location = models.ProxyStatisticsLocationEgress
+	c.Assert(findRedirect(entries, redirect), ErrorMatches, "no policy map entry for egress port 80/TCP")
+
+	redirect.Port = 9092
+	redirect.AllocatedProxyPort = 10002
+	c.Assert(findRedirect(entries, redirect), IsNil)
+}
+
+func (s *EndpointProbeSuite) TestEndpointProbeReport(c *C) {
+	r := &endpointProbeReport{Endpoint: "1234"}
+	r.add(probeLayerAgent, "state", nil, "ready")
+	r.add(probeLayerL2, "link", nil, "lxc1234")
+	r.add(probeLayerL3, "ICMP 10.0.0.1", fmt.Errorf("no reply within 2s"), "")
+	r.add(probeLayerL4, "TCP 10.0.0.1:80", fmt.Errorf("timeout"), "")
+	r.skip(probeLayerProxy, "http ingress 80/TCP redirect", "requires root privileges")
+	c.Assert(r.FailedLayer, Equals, probeLayerL3)
+	c.Assert(r.Checks, HasLen, 5)
+	c.Assert(r.Checks[2].Status, Equals, connectivityFail)
+	c.Assert(r.Checks[2].Detail, Equals, "no reply within 2s")
+	c.Assert(r.Checks[4].Status, Equals, probeSkip)
+
+	var buf bytes.Buffer
+	printEndpointProbeReport(&buf, r)
+	c.Assert(buf.String(), Matches, `(?s)LAYER +CHECK +STATUS +DETAIL\n.*L3 +ICMP 10\.0\.0\.1 +FAIL +no reply within 2s\n.*Endpoint 1234 fails at layer L3\n`)
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	quarantineReason string
+	quarantineLift   bool
+)
+
+// endpointQuarantineCmd represents the endpoint_quarantine command
+var endpointQuarantineCmd = &cobra.Command{
+	Use:   "quarantine <endpoint-id>",
+	Short: "Deny all traffic of an endpoint regardless of policy",
+	Long: `Quarantines an endpoint by enforcing a deny-all policy for all of its
+traffic, overriding the rules of the policy repository. The quarantine is
+kept across agent restarts until it is lifted with --lift.`,
+	Example: "  cilium endpoint quarantine 5421 --reason \"suspected compromise\"\n" +
+		"  cilium endpoint quarantine 5421 --lift",
+	PreRun: requireEndpointID,
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+		if quarantineLift && quarantineReason != "" {
+			Fatalf("--reason cannot be used with --lift\n")
+		}
+
+		quarantine := &models.EndpointQuarantine{
+			Enabled: !quarantineLift,
+			Reason:  quarantineReason,
+		}
+		if err := client.EndpointQuarantinePut(id, quarantine); err != nil {
+			Fatalf("Cannot update quarantine of endpoint %s: %s\n", id, err)
+		}
+
+		if quarantineLift {
+			fmt.Printf("Quarantine of endpoint %s lifted\n", id)
+		} else {
+			fmt.Printf("Endpoint %s quarantined\n", id)
+		}
+	},
+}
+
+func init() {
+	endpointCmd.AddCommand(endpointQuarantineCmd)
+	setArgCompletion(endpointQuarantineCmd, completeEndpoints)
+	endpointQuarantineCmd.Flags().StringVar(&quarantineReason, "reason", "", "Reason for quarantining the endpoint")
+	endpointQuarantineCmd.Flags().BoolVar(&quarantineLift, "lift", false, "Lift the quarantine of the endpoint")
+}
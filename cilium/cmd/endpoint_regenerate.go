@@ -16,25 +16,120 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/cilium/cilium/pkg/lock"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	regenerateAll      bool
+	regenerateLabels   []string
+	regenerateParallel int
+)
+
 // endpointRegenerateCmd represents the endpoint_regenerate command
 var endpointRegenerateCmd = &cobra.Command{
-	Use:    "regenerate <endpoint-id>",
-	Short:  "Force regeneration of endpoint program",
-	PreRun: requireEndpointID,
+	Use:   "regenerate ( <endpoint-id> | --all | -l <endpoint labels> )",
+	Short: "Force regeneration of endpoint program",
+	Example: `  cilium endpoint regenerate 1234
+  cilium endpoint regenerate --all --parallel 8
+  cilium endpoint regenerate -l k8s:app=web`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if regenerateAll || len(regenerateLabels) > 0 {
+			if len(args) > 0 {
+				Usagef(cmd, "Endpoint id cannot be combined with --all or --labels")
+			}
+			if regenerateParallel < 1 {
+				Usagef(cmd, "--parallel must be at least 1")
+			}
+			return
+		}
+		requireEndpointID(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		id := args[0]
-		if err := client.EndpointConfigPatch(id, nil); err != nil {
-			Fatalf("Cannot regenerate endpoint %s: %s\n", id, err)
-		} else {
-			fmt.Printf("Endpoint %s successfully regenerated\n", id)
+		if !regenerateAll && len(regenerateLabels) == 0 {
+			id := args[0]
+			if err := client.EndpointConfigPatch(id, nil); err != nil {
+				Fatalf("Cannot regenerate endpoint %s: %s\n", id, err)
+			} else {
+				fmt.Printf("Endpoint %s successfully regenerated\n", id)
+			}
+			return
+		}
+
+		eps, err := getEndpoints(regenerateLabels)
+		if err != nil {
+			Fatalf("%s\n", err)
+		}
+		ids := make([]string, 0, len(eps))
+		for _, ep := range eps {
+			ids = append(ids, strconv.FormatInt(ep.ID, 10))
+		}
+
+		regenerate := func(id string) error {
+			return client.EndpointConfigPatch(id, nil)
+		}
+		failed := regenerateEndpoints(os.Stdout, ids, regenerateParallel, regenerate)
+		fmt.Printf("Triggered regeneration of %d/%d endpoints\n", len(ids)-failed, len(ids))
+		switch {
+		case failed == len(ids) && failed > 0:
+			Exitf(ExitFailure, "Cannot regenerate any endpoint")
+		case failed > 0:
+			Exitf(ExitPartialSuccess, "Cannot regenerate %d/%d endpoints", failed, len(ids))
 		}
 	},
 }
 
 func init() {
 	endpointCmd.AddCommand(endpointRegenerateCmd)
+	setArgCompletion(endpointRegenerateCmd, completeEndpoints)
+	endpointRegenerateCmd.Flags().BoolVar(&regenerateAll, "all", false, "Regenerate all endpoints")
+	endpointRegenerateCmd.Flags().StringSliceVarP(&regenerateLabels, "labels", "l", []string{}, "Regenerate endpoints with all of the given labels (source:key=value)")
+	endpointRegenerateCmd.Flags().IntVar(&regenerateParallel, "parallel", 4, "Maximum number of endpoints to regenerate concurrently")
+}
+
+// regenerateEndpoints calls regenerate for each of the endpoint ids with at
+// most parallel calls in flight, reports the progress to w and returns the
+// number of endpoints which failed to regenerate.
+func regenerateEndpoints(w io.Writer, ids []string, parallel int, regenerate func(id string) error) int {
+	var (
+		mutex   lock.Mutex
+		done    int
+		failed  int
+		wg      sync.WaitGroup
+		pending = make(chan string)
+	)
+
+	for i := 0; i < parallel && i < len(ids); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range pending {
+				err := regenerate(id)
+
+				mutex.Lock()
+				done++
+				if err != nil {
+					failed++
+					fmt.Fprintf(w, "[%d/%d] Endpoint %s: cannot regenerate: %s\n", done, len(ids), id, err)
+				} else {
+					fmt.Fprintf(w, "[%d/%d] Endpoint %s: regeneration triggered\n", done, len(ids), id)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		pending <- id
+	}
+	close(pending)
+	wg.Wait()
+
+	return failed
 }
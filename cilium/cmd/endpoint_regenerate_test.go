@@ -0,0 +1,85 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/cilium/cilium/pkg/lock"
+
+	. "gopkg.in/check.v1"
+)
+
+type EndpointRegenerateSuite struct{}
+
+var _ = Suite(&EndpointRegenerateSuite{})
+
+func (s *EndpointRegenerateSuite) TestRegenerateEndpointsProgress(c *C) {
+	var buf bytes.Buffer
+	failed := regenerateEndpoints(&buf, []string{"1", "2", "3"}, 1, func(id string) error {
+		if id == "2" {
+			return fmt.Errorf("endpoint not found")
+		}
+		return nil
+	})
+	c.Assert(failed, Equals, 1)
+	c.Assert(buf.String(), Equals, "[1/3] Endpoint 1: regeneration triggered\n"+
+		"[2/3] Endpoint 2: cannot regenerate: endpoint not found\n"+
+		"[3/3] Endpoint 3: regeneration triggered\n")
+
+	buf.Reset()
+	c.Assert(regenerateEndpoints(&buf, nil, 4, nil), Equals, 0)
+	c.Assert(buf.Len(), Equals, 0)
+}
+
+func (s *EndpointRegenerateSuite) TestRegenerateEndpointsParallel(c *C) {
+	var (
+		mutex    lock.Mutex
+		seen     []string
+		inFlight int32
+		maxSeen  int32
+	)
+
+	ids := []string{}
+	for i := 0; i < 20; i++ {
+		ids = append(ids, fmt.Sprintf("%02d", i))
+	}
+
+	var buf bytes.Buffer
+	failed := regenerateEndpoints(&buf, ids, 3, func(id string) error {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		mutex.Lock()
+		seen = append(seen, id)
+		if n > maxSeen {
+			maxSeen = n
+		}
+		mutex.Unlock()
+
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	c.Assert(failed, Equals, 0)
+
+	sort.Strings(seen)
+	c.Assert(seen, DeepEquals, ids)
+	c.Assert(maxSeen <= 3, Equals, true, Commentf("%d regenerations in flight", maxSeen))
+	c.Assert(bytes.Count(buf.Bytes(), []byte("\n")), Equals, len(ids))
+}
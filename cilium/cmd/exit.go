@@ -0,0 +1,160 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	"github.com/pkg/errors"
+)
+
+// Exit codes of the cilium command. Automation may rely on them, existing
+// codes must not be changed.
+const (
+	// ExitFailure is returned on errors not covered by a more specific
+	// exit code
+	ExitFailure = 1
+
+	// ExitUsage is returned if the command was invoked with missing or
+	// malformed arguments or flags
+	ExitUsage = 2
+
+	// ExitUnreachable is returned if the API of the agent cannot be reached
+	ExitUnreachable = 3
+
+	// ExitNotFound is returned if the requested object does not exist
+	ExitNotFound = 4
+
+	// ExitInvalid is returned if the input was rejected as invalid by the
+	// agent or by local validation
+	ExitInvalid = 5
+
+	// ExitPartialSuccess is returned by commands operating on multiple
+	// objects if the operation failed for some but not all of them
+	ExitPartialSuccess = 6
+)
+
+const (
+	errorFormatText = "text"
+	errorFormatJSON = "json"
+)
+
+// errorFormat is the format in which errors are written to stderr
+var errorFormat = errorFormatText
+
+var exitReasons = map[int]string{
+	ExitFailure:        "failure",
+	ExitUsage:          "usage",
+	ExitUnreachable:    "unreachable",
+	ExitNotFound:       "not-found",
+	ExitInvalid:        "invalid",
+	ExitPartialSuccess: "partial-success",
+}
+
+// errorEnvelope is the representation of an error with --error-format=json
+type errorEnvelope struct {
+	Error errorDetails `json:"error"`
+}
+
+type errorDetails struct {
+	Code    int    `json:"code"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// apiStatusRegex matches the HTTP status code in the message of the errors
+// returned by the generated API client, e.g. "[GET /endpoint/{id}][404] ..."
+var apiStatusRegex = regexp.MustCompile(`^\[[A-Z]+ [^\]]*\]\[([0-9]{3})\]`)
+
+// exitCodeForStatus returns the exit code for an error response of the API
+// with the given HTTP status code.
+func exitCodeForStatus(status int) int {
+	switch {
+	case status == http.StatusNotFound:
+		return ExitNotFound
+	case status == http.StatusConflict:
+		return ExitFailure
+	case status >= 400 && status < 500:
+		return ExitInvalid
+	}
+	return ExitFailure
+}
+
+// exitCodeForError returns the exit code describing err. Errors wrapped by
+// pkg/client.Hint are inspected as well.
+func exitCodeForError(err error) int {
+	if err == nil {
+		return ExitFailure
+	}
+
+	switch e := errors.Cause(err).(type) {
+	case *url.Error, *net.OpError:
+		return ExitUnreachable
+	case *runtime.APIError:
+		return exitCodeForStatus(e.Code)
+	case error:
+		if m := apiStatusRegex.FindStringSubmatch(e.Error()); m != nil {
+			status, _ := strconv.Atoi(m[1])
+			return exitCodeForStatus(status)
+		}
+	}
+	return ExitFailure
+}
+
+// exitCodeForArgs returns the exit code describing the last error in args.
+func exitCodeForArgs(args []interface{}) int {
+	for i := len(args) - 1; i >= 0; i-- {
+		if err, ok := args[i].(error); ok {
+			return exitCodeForError(err)
+		}
+	}
+	return ExitFailure
+}
+
+// writeError writes msg to w in the format selected with --error-format.
+func writeError(w io.Writer, code int, msg string) {
+	msg = strings.TrimSpace(msg)
+	if errorFormat != errorFormatJSON {
+		fmt.Fprintf(w, "Error: %s\n", msg)
+		return
+	}
+
+	reason, ok := exitReasons[code]
+	if !ok {
+		reason = exitReasons[ExitFailure]
+	}
+	out, _ := json.Marshal(errorEnvelope{
+		Error: errorDetails{Code: code, Reason: reason, Message: msg},
+	})
+	fmt.Fprintf(w, "%s\n", out)
+}
+
+// Exitf prints the Printf formatted message to stderr and exits the program
+// with the given exit code
+// Note: os.Exit() is not recoverable
+func Exitf(code int, msg string, args ...interface{}) {
+	writeError(os.Stderr, code, fmt.Sprintf(msg, args...))
+	os.Exit(code)
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+
+	"github.com/cilium/cilium/api/v1/client/endpoint"
+	"github.com/cilium/cilium/api/v1/client/policy"
+	pkg "github.com/cilium/cilium/pkg/client"
+
+	"github.com/go-openapi/runtime"
+	. "gopkg.in/check.v1"
+)
+
+type ExitSuite struct{}
+
+var _ = Suite(&ExitSuite{})
+
+func (s *ExitSuite) TestExitCodeForError(c *C) {
+	unreachable := &url.Error{Op: "Get", URL: "http://localhost/v1/healthz", Err: fmt.Errorf("connection refused")}
+
+	for _, t := range []struct {
+		err  error
+		code int
+	}{
+		{fmt.Errorf("something failed"), ExitFailure},
+		{unreachable, ExitUnreachable},
+		{pkg.Hint(unreachable), ExitUnreachable},
+		{endpoint.NewGetEndpointIDNotFound(), ExitNotFound},
+		{pkg.Hint(endpoint.NewGetEndpointIDNotFound()), ExitNotFound},
+		{policy.NewPutPolicyInvalidPolicy(), ExitInvalid},
+		{endpoint.NewPutEndpointIDExists(), ExitFailure},
+		{runtime.NewAPIError("getEndpoint", nil, 404), ExitNotFound},
+		{runtime.NewAPIError("getEndpoint", nil, 500), ExitFailure},
+	} {
+		c.Assert(exitCodeForError(t.err), Equals, t.code, Commentf("error %q", t.err))
+	}
+
+	c.Assert(exitCodeForArgs([]interface{}{"1234", endpoint.NewGetEndpointIDNotFound()}), Equals, ExitNotFound)
+	c.Assert(exitCodeForArgs([]interface{}{"1234"}), Equals, ExitFailure)
+}
+
+func (s *ExitSuite) TestWriteError(c *C) {
+	defer func(format string) { errorFormat = format }(errorFormat)
+
+	var buf bytes.Buffer
+	errorFormat = errorFormatText
+	writeError(&buf, ExitNotFound, "Cannot get endpoint 1234\n")
+	c.Assert(buf.String(), Equals, "Error: Cannot get endpoint 1234\n")
+
+	buf.Reset()
+	errorFormat = errorFormatJSON
+	writeError(&buf, ExitNotFound, "Cannot get endpoint 1234\n")
+	c.Assert(buf.String(), Equals, `{"error":{"code":4,"reason":"not-found","message":"Cannot get endpoint 1234"}}`+"\n")
+}
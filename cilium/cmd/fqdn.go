@@ -0,0 +1,36 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// fqdnCmd represents the fqdn command
+var fqdnCmd = &cobra.Command{
+	Use:   "fqdn",
+	Short: "Manage DNS data of ToFQDNs policies",
+}
+
+// fqdnCacheCmd represents the fqdn cache command
+var fqdnCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the DNS lookups cached for ToFQDNs policies",
+}
+
+func init() {
+	rootCmd.AddCommand(fqdnCmd)
+	fqdnCmd.AddCommand(fqdnCacheCmd)
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var fqdnCleanMatchName string
+
+// fqdnCacheCleanCmd represents the fqdn cache clean command
+var fqdnCacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove DNS lookups from the cache of ToFQDNs policies",
+	Long: `Remove the cached IPs of a DNS name, or of all DNS names if no name is given.
+The names are looked up again on the next poll and only the IPs returned by
+that lookup are retained, removing stale IPs from the rules generated for
+ToFQDNs policies.`,
+	Example: "  cilium fqdn cache clean --match-name cilium.io",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.FqdnCacheDelete(fqdnCleanMatchName); err != nil {
+			Fatalf("Cannot clean DNS cache: %s", err)
+		}
+		fmt.Println("DNS cache cleaned")
+	},
+}
+
+func init() {
+	fqdnCacheCmd.AddCommand(fqdnCacheCleanCmd)
+	fqdnCacheCleanCmd.Flags().StringVarP(&fqdnCleanMatchName, "match-name", "n", "", "Only remove the IPs of the given DNS name")
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fqdnMatchName string
+	fqdnEndpoint  string
+)
+
+// fqdnCacheListCmd represents the fqdn cache list command
+var fqdnCacheListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the DNS lookups cached for ToFQDNs policies",
+	Long: `List the IPs of the DNS names polled for ToFQDNs policies, together with the
+security identity allocated for each IP and the time left until the IP expires
+unless it is returned by a later lookup.`,
+	Example: `  cilium fqdn cache list --match-name cilium.io
+  cilium fqdn cache list --endpoint 1234`,
+	Run: func(cmd *cobra.Command, args []string) {
+		lookups, err := client.FqdnCacheGet(fqdnMatchName, fqdnEndpoint)
+		if err != nil {
+			Fatalf("Cannot get DNS cache: %s", err)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(lookups); err != nil {
+				Fatalf("Unable to print output: %s", err)
+			}
+			return
+		}
+
+		if len(lookups) == 0 {
+			fmt.Println("No DNS lookups cached")
+			return
+		}
+		printFqdnCache(os.Stdout, lookups, time.Now())
+	},
+}
+
+func init() {
+	fqdnCacheCmd.AddCommand(fqdnCacheListCmd)
+	fqdnCacheListCmd.Flags().StringVarP(&fqdnMatchName, "match-name", "n", "", "Only list the IPs of the given DNS name")
+	fqdnCacheListCmd.Flags().StringVarP(&fqdnEndpoint, "endpoint", "e", "", "Only list the DNS names of ToFQDNs rules selecting the given endpoint")
+	setFlagCompletion(fqdnCacheListCmd, "endpoint", completeEndpoints)
+	command.AddJSONOutput(fqdnCacheListCmd)
+}
+
+// printFqdnCache writes a table of the DNS lookups to w, with the time left
+// until expiration relative to now.
+func printFqdnCache(w io.Writer, lookups []*models.DNSLookup, now time.Time) {
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "FQDN\tIP\tIDENTITY\tTTL\tEXPIRES IN\n")
+	for _, l := range lookups {
+		expiresIn := time.Time(l.ExpirationTime).Sub(now).Round(time.Second)
+		if expiresIn < 0 {
+			expiresIn = 0
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", l.Fqdn, l.IP, l.Identity, l.TTL, expiresIn)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	"github.com/go-openapi/strfmt"
+	. "gopkg.in/check.v1"
+)
+
+type FqdnCacheListSuite struct{}
+
+var _ = Suite(&FqdnCacheListSuite{})
+
+func (s *FqdnCacheListSuite) TestPrintFqdnCache(c *C) {
+	now := time.Now()
+	lookups := []*models.DNSLookup{
+		{
+			Fqdn:           "cilium.io.",
+			IP:             "104.198.14.52",
+			Identity:       16777217,
+			TTL:            60,
+			LookupTime:     strfmt.DateTime(now.Add(-20 * time.Second)),
+			ExpirationTime: strfmt.DateTime(now.Add(40 * time.Second)),
+		},
+		{
+			Fqdn:           "github.com.",
+			IP:             "140.82.118.4",
+			TTL:            10,
+			LookupTime:     strfmt.DateTime(now.Add(-20 * time.Second)),
+			ExpirationTime: strfmt.DateTime(now.Add(-10 * time.Second)),
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	printFqdnCache(buf, lookups, now)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	c.Assert(lines, HasLen, 3)
+	c.Assert(strings.Fields(lines[0]), DeepEquals, []string{"FQDN", "IP", "IDENTITY", "TTL", "EXPIRES", "IN"})
+	c.Assert(strings.Fields(lines[1]), DeepEquals, []string{"cilium.io.", "104.198.14.52", "16777217", "60", "40s"})
+	c.Assert(strings.Fields(lines[2]), DeepEquals, []string{"github.com.", "140.82.118.4", "0", "10", "0s"})
+}
@@ -15,20 +15,24 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
+	"net"
 	"os"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
 
+	identityApi "github.com/cilium/cilium/api/v1/client/policy"
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/api"
 	"github.com/cilium/cilium/pkg/bpf"
+	pkg "github.com/cilium/cilium/pkg/client"
 	"github.com/cilium/cilium/pkg/color"
 	endpointid "github.com/cilium/cilium/pkg/endpoint/id"
 	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/labels/cidr"
 	"github.com/cilium/cilium/pkg/maps/policymap"
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/u8proto"
@@ -36,21 +40,23 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// Fatalf prints the Printf formatted message to stderr and exits the program
-// Note: os.Exit(1) is not recoverable
+// Fatalf prints the Printf formatted message to stderr and exits the program.
+// The exit code is derived from the last error in args, see exitCodeForError.
+// Note: os.Exit() is not recoverable
 func Fatalf(msg string, args ...interface{}) {
-	fmt.Fprintf(os.Stderr, "Error: %s\n", fmt.Sprintf(msg, args...))
-	os.Exit(1)
+	Exitf(exitCodeForArgs(args), msg, args...)
 }
 
 // Usagef prints the Printf formatted message to stderr, prints usage help and
-// exits the program
-// Note: os.Exit(1) is not recoverable
+// exits the program with ExitUsage
+// Note: os.Exit() is not recoverable
 func Usagef(cmd *cobra.Command, msg string, args ...interface{}) {
-	txt := fmt.Sprintf(msg, args...)
-	fmt.Fprintf(os.Stderr, "Error: %s\n\n", txt)
-	cmd.Help()
-	os.Exit(1)
+	writeError(os.Stderr, ExitUsage, fmt.Sprintf(msg, args...))
+	if errorFormat != errorFormatJSON {
+		fmt.Fprintln(os.Stderr)
+		cmd.Help()
+	}
+	os.Exit(ExitUsage)
 }
 
 func requireEndpointID(cmd *cobra.Command, args []string) {
@@ -62,7 +68,7 @@ func requireEndpointID(cmd *cobra.Command, args []string) {
 		_, _, err := endpointid.ValidateID(args[0])
 
 		if err != nil {
-			Fatalf("Cannot parse endpoint id \"%s\": %s", args[0], err)
+			Exitf(ExitUsage, "Cannot parse endpoint id \"%s\": %s", args[0], err)
 		}
 	}
 }
@@ -117,82 +123,6 @@ func TablePrinter(firstTitle, secondTitle string, data map[string][]string) {
 	w.Flush()
 }
 
-// Search 'result' for strings with escaped JSON inside, and expand the JSON.
-func expandNestedJSON(result bytes.Buffer) (bytes.Buffer, error) {
-	reStringWithJSON := regexp.MustCompile(`"[^"\\{]*{.*[^\\]"`)
-	reJSON := regexp.MustCompile(`{.*}`)
-	for {
-		var (
-			loc    []int
-			indent string
-		)
-
-		// Search for nested JSON; if we don't find any, then break.
-		resBytes := result.Bytes()
-		if loc = reStringWithJSON.FindIndex(resBytes); loc == nil {
-			break
-		}
-
-		// Determine the current indentation
-		for i := 0; i < loc[0]-1; i++ {
-			idx := loc[0] - i - 1
-			if resBytes[idx] != ' ' {
-				break
-			}
-			indent = fmt.Sprintf("\t%s\t", indent)
-		}
-
-		stringStart := loc[0]
-		stringEnd := loc[1]
-
-		// Unquote the string with the nested json.
-		quotedBytes := resBytes[stringStart:stringEnd]
-		unquoted, err := strconv.Unquote(string(quotedBytes))
-		if err != nil {
-			return bytes.Buffer{}, fmt.Errorf("Failed to Unquote string: %s\n%s", err.Error(), string(quotedBytes))
-		}
-
-		// Find the JSON within the quoted string.
-		nestedStart := 0
-		nestedEnd := 0
-		if locs := reJSON.FindAllStringIndex(unquoted, -1); locs != nil {
-			// The last match is the longest one.
-			last := len(locs) - 1
-			nestedStart = locs[last][0]
-			nestedEnd = locs[last][1]
-		} else if reJSON.Match(quotedBytes) {
-			// The entire string is JSON
-			nestedEnd = len(unquoted)
-		}
-
-		// Decode the nested JSON
-		decoded := ""
-		if nestedEnd != 0 {
-			m := make(map[string]interface{})
-			nested := bytes.NewBufferString(unquoted[nestedStart:nestedEnd])
-			if err := json.NewDecoder(nested).Decode(&m); err != nil {
-				return bytes.Buffer{}, fmt.Errorf("Failed to decode nested JSON: %s", err.Error())
-			}
-			decodedBytes, err := json.MarshalIndent(m, indent, "  ")
-			if err != nil {
-				return bytes.Buffer{}, fmt.Errorf("Cannot marshal nested JSON: %s", err.Error())
-			}
-			decoded = string(decodedBytes)
-		}
-
-		// Serialize
-		nextResult := bytes.Buffer{}
-		nextResult.Write(resBytes[0:stringStart])
-		nextResult.WriteString(string(unquoted[:nestedStart]))
-		nextResult.WriteString(string(decoded))
-		nextResult.WriteString(string(unquoted[nestedEnd:]))
-		nextResult.Write(resBytes[stringEnd:])
-		result = nextResult
-	}
-
-	return result, nil
-}
-
 // PolicyUpdateArgs is the parsed representation of a
 // bpf policy {add,delete} command.
 type PolicyUpdateArgs struct {
@@ -205,14 +135,27 @@ type PolicyUpdateArgs struct {
 	// as an argument e.g. `ingress`
 	trafficDirection policymap.TrafficDirection
 
-	// label represents the identity of the label provided as argument.
-	label uint32
+	// identities represents the numeric identities of the peer provided
+	// as argument.
+	identities []uint32
+
+	// ports represents the list of ports associated with the command.
+	// If no port was specified, it contains a single entry for port 0
+	// and protocol 0.
+	ports []policyPort
+}
 
-	// port represents the port associated with the command, if specified.
+// policyPort is a port or a masked port range and the set of protocols
+// associated with it in a bpf policy {add,delete} command.
+type policyPort struct {
+	// port is the destination port, 0 for all ports.
 	port uint16
 
-	// protocols represents the set of protocols associated with the
-	// command, if specified.
+	// wildcardBits is the number of low-order bits of port which are
+	// wildcarded, see policymap.PortMask.
+	wildcardBits uint8
+
+	// protocols represents the set of protocols associated with port.
 	protocols []uint8
 }
 
@@ -235,14 +178,14 @@ func parseTrafficString(td string) (policymap.TrafficDirection, error) {
 
 // parsePolicyUpdateArgs parses the arguments to a bpf policy {add,delete}
 // command, provided as a list containing the endpoint ID, traffic direction,
-// identity and optionally, a list of ports.
+// peer and optionally, a comma separated list of ports or port ranges.
 // Returns a parsed representation of the command arguments.
 func parsePolicyUpdateArgs(cmd *cobra.Command, args []string) *PolicyUpdateArgs {
 	if len(args) < 3 {
 		Usagef(cmd, "<endpoint id>, <traffic-direction>, and <identity> required")
 	}
 
-	pa, err := parsePolicyUpdateArgsHelper(args)
+	pa, err := parsePolicyUpdateArgsHelper(args, resolvePeerIdentities)
 	if err != nil {
 		Fatalf("%s", err)
 	}
@@ -250,7 +193,10 @@ func parsePolicyUpdateArgs(cmd *cobra.Command, args []string) *PolicyUpdateArgs
 	return pa
 }
 
-func parsePolicyUpdateArgsHelper(args []string) (*PolicyUpdateArgs, error) {
+// parsePolicyUpdateArgsHelper parses the arguments to a bpf policy
+// {add,delete} command. If the peer is neither a numeric nor a reserved
+// identity, it is resolved to a list of identities with resolvePeer.
+func parsePolicyUpdateArgsHelper(args []string, resolvePeer func(string) ([]uint32, error)) (*PolicyUpdateArgs, error) {
 	trafficDirection := args[1]
 	parsedTd, err := parseTrafficString(trafficDirection)
 	if err != nil {
@@ -262,21 +208,129 @@ func parsePolicyUpdateArgsHelper(args []string) (*PolicyUpdateArgs, error) {
 		endpointID = "reserved_" + strconv.FormatUint(uint64(numericIdentity), 10)
 	}
 
-	peerLbl, err := strconv.ParseUint(args[2], 10, 32)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to convert %s", args[2])
+	var identities []uint32
+	if peerLbl, err := strconv.ParseUint(args[2], 10, 32); err == nil {
+		identities = []uint32{uint32(peerLbl)}
+	} else if numericIdentity := identity.GetReservedID(args[2]); numericIdentity != identity.IdentityUnknown {
+		identities = []uint32{numericIdentity.Uint32()}
+	} else if identities, err = resolvePeer(args[2]); err != nil {
+		return nil, fmt.Errorf("Failed to resolve identity of %s: %s", args[2], err)
 	}
-	label := uint32(peerLbl)
 
-	port := uint16(0)
-	protos := []uint8{}
+	ports := []policyPort{{port: 0, protocols: []uint8{0}}}
 	if len(args) > 3 {
-		pp, err := parseL4PortsSlice([]string{args[3]})
+		ports, err = parsePolicyPorts(args[3])
 		if err != nil {
 			return nil, fmt.Errorf("Failed to parse L4: %s", err)
 		}
-		port = pp[0].Port
-		if port != 0 {
+	}
+
+	pa := &PolicyUpdateArgs{
+		endpointID:       endpointID,
+		trafficDirection: parsedTd,
+		identities:       identities,
+		ports:            ports,
+	}
+
+	return pa, nil
+}
+
+// parsePeerCIDR returns the prefix represented by peer if it is a CIDR or an
+// IP address, or nil otherwise.
+func parsePeerCIDR(peer string) *net.IPNet {
+	if _, prefix, err := net.ParseCIDR(peer); err == nil {
+		return prefix
+	}
+	ip := net.ParseIP(peer)
+	if ip == nil {
+		return nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// matchIdentities returns the sorted numeric IDs of all identities carrying
+// all of the needed labels.
+func matchIdentities(identities []*models.Identity, needed labels.LabelArray) []uint32 {
+	ids := []uint32{}
+	for _, id := range filterIdentities(identities, needed) {
+		ids = append(ids, uint32(id.ID))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// resolvePeerIdentities resolves the peer of a bpf policy {add,delete}
+// command to numeric identities via the API. A CIDR or IP address resolves
+// to the identity allocated for it, a comma separated list of labels
+// resolves to all identities carrying all of these labels.
+func resolvePeerIdentities(peer string) ([]uint32, error) {
+	if prefix := parsePeerCIDR(peer); prefix != nil {
+		lbls := cidr.GetCIDRLabels(prefix)
+		params := identityApi.NewGetIdentityParams().WithLabels(lbls.GetModel()).WithTimeout(api.ClientTimeout)
+		resp, err := client.Policy.GetIdentity(params)
+		if err != nil {
+			return nil, fmt.Errorf("no identity allocated for CIDR %s: %s", prefix, pkg.Hint(err))
+		}
+		return matchIdentities(resp.Payload, nil), nil
+	}
+
+	needed := labels.ParseSelectLabelArray(strings.Split(peer, ",")...)
+	params := identityApi.NewGetIdentityParams().WithTimeout(api.ClientTimeout)
+	resp, err := client.Policy.GetIdentity(params)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list identities: %s", pkg.Hint(err))
+	}
+	ids := matchIdentities(resp.Payload, needed)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no identity matches labels %s", needed)
+	}
+	return ids, nil
+}
+
+// parsePolicyPorts parses a comma separated list of ports or port ranges,
+// each optionally followed by a protocol, e.g. "80-90/tcp,443/tcp,53", and
+// expands it into the list of ports the policy entries apply to. A port
+// range is expanded into the smallest list of masked ports covering it. A
+// port without protocol applies to all protocols.
+func parsePolicyPorts(arg string) ([]policyPort, error) {
+	ports := []policyPort{}
+	numKeys := 0
+	for _, token := range strings.Split(arg, ",") {
+		portStr, protoStr := token, ""
+		if i := strings.IndexByte(token, '/'); i >= 0 {
+			portStr, protoStr = token[:i], token[i:]
+		}
+
+		first, last := portStr, portStr
+		if i := strings.IndexByte(portStr, '-'); i >= 0 {
+			first, last = portStr[:i], portStr[i+1:]
+		}
+
+		// Validate the first port and the protocol in the same way
+		// as a single port.
+		pp, err := parseL4PortsSlice([]string{first + protoStr})
+		if err != nil {
+			return nil, err
+		}
+		lo := int(pp[0].Port)
+		hi := lo
+		if last != first {
+			end, err := strconv.ParseUint(last, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid port %q: %s", last, err)
+			}
+			hi = int(end)
+			if lo == 0 || hi < lo {
+				return nil, fmt.Errorf("invalid port range %q", portStr)
+			}
+		}
+
+		protos := []uint8{0}
+		if lo != 0 {
+			protos = []uint8{}
 			proto, _ := u8proto.ParseProtocol(pp[0].Protocol)
 			if proto == 0 {
 				for _, proto := range u8proto.ProtoIDs {
@@ -286,20 +340,18 @@ func parsePolicyUpdateArgsHelper(args []string) (*PolicyUpdateArgs, error) {
 				protos = append(protos, uint8(proto))
 			}
 		}
-	}
-	if len(protos) == 0 {
-		protos = append(protos, 0)
-	}
 
-	pa := &PolicyUpdateArgs{
-		endpointID:       endpointID,
-		trafficDirection: parsedTd,
-		label:            label,
-		port:             port,
-		protocols:        protos,
+		masks := policymap.PortRangeMasks(uint16(lo), uint16(hi))
+		numKeys += len(masks) * len(protos)
+		if numKeys > policymap.MaxEntries {
+			return nil, fmt.Errorf("%q expands to more than %d policy entries", arg, policymap.MaxEntries)
+		}
+		for _, mask := range masks {
+			ports = append(ports, policyPort{port: mask.Port, wildcardBits: mask.WildcardBits, protocols: protos})
+		}
 	}
 
-	return pa, nil
+	return ports, nil
 }
 
 // updatePolicyKey updates an entry in the PolicyMap for the provided
@@ -308,25 +360,56 @@ func parsePolicyUpdateArgsHelper(args []string) (*PolicyUpdateArgs, error) {
 // deleted.
 func updatePolicyKey(pa *PolicyUpdateArgs, add bool) {
 	policyMapPath := bpf.MapPath(policymap.MapName + pa.endpointID)
-	policyMap, _, err := policymap.OpenMap(policyMapPath)
+	// The map is not created as its size is configured in the agent
+	policyMap, err := policymap.OpenGlobalMap(policyMapPath)
 	if err != nil {
 		Fatalf("Cannot open policymap '%s' : %s", policyMapPath, err)
 	}
 
-	for _, proto := range pa.protocols {
-		u8p := u8proto.U8proto(proto)
-		entry := fmt.Sprintf("%d %d/%s", pa.label, pa.port, u8p.String())
-		if add {
-			var proxyPort uint16
-			if err := policyMap.Allow(pa.label, pa.port, u8p, pa.trafficDirection, proxyPort); err != nil {
-				Fatalf("Cannot add policy key '%s': %s\n", entry, err)
+	hasRanges := false
+	for _, label := range pa.identities {
+		for _, pp := range pa.ports {
+			mask := policymap.PortMask{Port: pp.port, WildcardBits: pp.wildcardBits}
+			port := strconv.FormatUint(uint64(pp.port), 10)
+			if pp.wildcardBits != 0 {
+				port = fmt.Sprintf("%d-%d", mask.Port, mask.Last())
+				hasRanges = true
 			}
-		} else {
-			if err := policyMap.Delete(pa.label, pa.port, u8p, pa.trafficDirection); err != nil {
-				Fatalf("Cannot delete policy key '%s': %s\n", entry, err)
+			for _, proto := range pp.protocols {
+				u8p := u8proto.U8proto(proto)
+				entry := fmt.Sprintf("%d %s/%s", label, port, u8p.String())
+				if add {
+					var proxyPort uint16
+					if err := policyMap.AllowPortMask(label, mask, u8p, pa.trafficDirection, proxyPort); err != nil {
+						Fatalf("Cannot add policy key '%s': %s\n", entry, err)
+					}
+				} else {
+					if err := policyMap.DeletePortMask(label, mask, u8p, pa.trafficDirection); err != nil {
+						Fatalf("Cannot delete policy key '%s': %s\n", entry, err)
+					}
+				}
 			}
 		}
 	}
+
+	if add && hasRanges {
+		enablePolicyPortRanges(pa.endpointID)
+	}
+}
+
+// enablePolicyPortRanges enables the option of the endpoint with the given
+// ID which compiles the lookup of port range entries into its datapath.
+// Port range entries are ignored by the datapath until it is enabled.
+func enablePolicyPortRanges(id string) {
+	cfg, err := client.EndpointConfigGet(id)
+	if err == nil {
+		cfg.Realized.Options[option.PolicyPortRanges] = fmt.Sprintf("%d", option.OptionEnabled)
+		err = client.EndpointConfigPatch(id, cfg.Realized)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot enable option %s of endpoint %s, port ranges are not enforced: %s\n",
+			option.PolicyPortRanges, id, err)
+	}
 }
 
 // dumpConfig pretty prints boolean options
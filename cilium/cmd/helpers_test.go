@@ -1,11 +1,13 @@
 package cmd
 
 import (
-	"bytes"
+	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/maps/policymap"
@@ -20,34 +22,6 @@ type CMDHelpersSuite struct{}
 
 var _ = Suite(&CMDHelpersSuite{})
 
-func (s *CMDHelpersSuite) TestExpandNestedJSON(c *C) {
-	buf := bytes.NewBufferString("not json at all")
-	_, err := expandNestedJSON(*buf)
-	c.Assert(err, IsNil)
-
-	buf = bytes.NewBufferString(`{\n\"escapedJson\": \"foo\"}`)
-	_, err = expandNestedJSON(*buf)
-	c.Assert(err, IsNil)
-
-	buf = bytes.NewBufferString(`nonjson={\n\"escapedJson\": \"foo\"}`)
-	_, err = expandNestedJSON(*buf)
-	c.Assert(err, IsNil)
-
-	buf = bytes.NewBufferString(`nonjson:morenonjson={\n\"escapedJson\": \"foo\"}`)
-	_, err = expandNestedJSON(*buf)
-	c.Assert(err, IsNil)
-
-	buf = bytes.NewBufferString(`{"foo": ["{\n  \"port\": 8080,\n  \"protocol\": \"TCP\"\n}"]}`)
-	_, err = expandNestedJSON(*buf)
-	c.Assert(err, IsNil)
-
-	buf = bytes.NewBufferString(`"foo": [
-  "bar:baz/alice={\"bob\":{\"charlie\":4}}\n"
-]`)
-	_, err = expandNestedJSON(*buf)
-	c.Assert(err, IsNil)
-}
-
 func (s *CMDHelpersSuite) TestParseTrafficString(c *C) {
 
 	validIngressCases := []string{"ingress", "Ingress", "InGrEss"}
@@ -85,41 +59,128 @@ func (s *CMDHelpersSuite) TestParsePolicyUpdateArgsHelper(c *C) {
 		allProtos = append(allProtos, uint8(proto))
 	}
 
+	resolvePeer := func(peer string) ([]uint32, error) {
+		if peer == "k8s:app=foo" {
+			return []uint32{1000, 1001}, nil
+		}
+		return nil, fmt.Errorf("no identity matches labels %s", peer)
+	}
+
 	tests := []struct {
 		args             []string
 		invalid          bool
 		endpointID       string
 		trafficDirection policymap.TrafficDirection
-		peerLbl          uint32
-		port             uint16
-		protos           []uint8
+		identities       []uint32
+		ports            []policyPort
 	}{
 		{
 			args:             []string{labels.IDNameHost, "ingress", "12345"},
 			invalid:          false,
 			endpointID:       "reserved_" + strconv.Itoa(int(identity.ReservedIdentityHost)),
 			trafficDirection: policymap.Ingress,
-			peerLbl:          12345,
-			port:             0,
-			protos:           []uint8{0},
+			identities:       []uint32{12345},
+			ports:            []policyPort{{port: 0, protocols: []uint8{0}}},
 		},
 		{
 			args:             []string{"123", "egress", "12345", "1/tcp"},
 			invalid:          false,
 			endpointID:       "123",
 			trafficDirection: policymap.Egress,
-			peerLbl:          12345,
-			port:             1,
-			protos:           []uint8{uint8(u8proto.TCP)},
+			identities:       []uint32{12345},
+			ports:            []policyPort{{port: 1, protocols: []uint8{uint8(u8proto.TCP)}}},
 		},
 		{
 			args:             []string{"123", "ingress", "12345", "1"},
 			invalid:          false,
 			endpointID:       "123",
 			trafficDirection: policymap.Ingress,
-			peerLbl:          12345,
-			port:             1,
-			protos:           allProtos,
+			identities:       []uint32{12345},
+			ports:            []policyPort{{port: 1, protocols: allProtos}},
+		},
+		{
+			args:             []string{"123", "egress", "12345", "80-82/tcp,53/udp"},
+			invalid:          false,
+			endpointID:       "123",
+			trafficDirection: policymap.Egress,
+			identities:       []uint32{12345},
+			ports: []policyPort{
+				{port: 80, wildcardBits: 1, protocols: []uint8{uint8(u8proto.TCP)}},
+				{port: 82, protocols: []uint8{uint8(u8proto.TCP)}},
+				{port: 53, protocols: []uint8{uint8(u8proto.UDP)}},
+			},
+		},
+		{
+			// Range of all ports, one masked port per power of two.
+			args:             []string{"123", "ingress", "12345", "1-65535/tcp"},
+			invalid:          false,
+			endpointID:       "123",
+			trafficDirection: policymap.Ingress,
+			identities:       []uint32{12345},
+			ports: func() []policyPort {
+				var ports []policyPort
+				for bits := uint8(0); bits < 16; bits++ {
+					ports = append(ports, policyPort{port: 1 << bits, wildcardBits: bits, protocols: []uint8{uint8(u8proto.TCP)}})
+				}
+				return ports
+			}(),
+		},
+		{
+			args:             []string{"123", "ingress", "12345", "65535-65535,8080"},
+			invalid:          false,
+			endpointID:       "123",
+			trafficDirection: policymap.Ingress,
+			identities:       []uint32{12345},
+			ports: []policyPort{
+				{port: 65535, protocols: allProtos},
+				{port: 8080, protocols: allProtos},
+			},
+		},
+		{
+			args:             []string{"123", "egress", labels.IDNameWorld, "80"},
+			invalid:          false,
+			endpointID:       "123",
+			trafficDirection: policymap.Egress,
+			identities:       []uint32{identity.ReservedIdentityWorld.Uint32()},
+			ports:            []policyPort{{port: 80, protocols: allProtos}},
+		},
+		{
+			args:             []string{"123", "ingress", "k8s:app=foo"},
+			invalid:          false,
+			endpointID:       "123",
+			trafficDirection: policymap.Ingress,
+			identities:       []uint32{1000, 1001},
+			ports:            []policyPort{{port: 0, protocols: []uint8{0}}},
+		},
+		{
+			// Peer which cannot be resolved.
+			args:    []string{"123", "ingress", "k8s:app=bar"},
+			invalid: true,
+		},
+		{
+			// Invalid port range.
+			args:    []string{"123", "ingress", "12345", "90-80/tcp"},
+			invalid: true,
+		},
+		{
+			// Port range including port 0.
+			args:    []string{"123", "ingress", "12345", "0-80"},
+			invalid: true,
+		},
+		{
+			// Empty port in list.
+			args:    []string{"123", "ingress", "12345", "80,"},
+			invalid: true,
+		},
+		{
+			// Invalid protocol in list.
+			args:    []string{"123", "ingress", "12345", "80/tcp,443/udt"},
+			invalid: true,
+		},
+		{
+			// Ranges expanding to too many policy entries.
+			args:    []string{"123", "ingress", "12345", strings.Repeat("1-65535,", 1024) + "1-65535"},
+			invalid: true,
 		},
 		{
 			// Invalid traffic direction.
@@ -134,7 +195,7 @@ func (s *CMDHelpersSuite) TestParsePolicyUpdateArgsHelper(c *C) {
 	}
 
 	for _, tt := range tests {
-		args, err := parsePolicyUpdateArgsHelper(tt.args)
+		args, err := parsePolicyUpdateArgsHelper(tt.args, resolvePeer)
 
 		if tt.invalid {
 			c.Assert(err, NotNil)
@@ -143,12 +204,37 @@ func (s *CMDHelpersSuite) TestParsePolicyUpdateArgsHelper(c *C) {
 
 			c.Assert(args.endpointID, Equals, tt.endpointID)
 			c.Assert(args.trafficDirection, Equals, tt.trafficDirection)
-			c.Assert(args.label, Equals, tt.peerLbl)
-			c.Assert(args.port, Equals, tt.port)
-
-			sortProtos(args.protocols)
-			sortProtos(tt.protos)
-			c.Assert(args.protocols, DeepEquals, tt.protos)
+			c.Assert(args.identities, DeepEquals, tt.identities)
+			c.Assert(args.ports, HasLen, len(tt.ports))
+			for i := range args.ports {
+				c.Assert(args.ports[i].port, Equals, tt.ports[i].port)
+				c.Assert(args.ports[i].wildcardBits, Equals, tt.ports[i].wildcardBits)
+				sortProtos(args.ports[i].protocols)
+				sortProtos(tt.ports[i].protocols)
+				c.Assert(args.ports[i].protocols, DeepEquals, tt.ports[i].protocols)
+			}
 		}
 	}
 }
+
+func (s *CMDHelpersSuite) TestParsePeerCIDR(c *C) {
+	c.Assert(parsePeerCIDR("10.0.0.0/8").String(), Equals, "10.0.0.0/8")
+	c.Assert(parsePeerCIDR("10.1.2.3/8").String(), Equals, "10.0.0.0/8")
+	c.Assert(parsePeerCIDR("10.1.2.3").String(), Equals, "10.1.2.3/32")
+	c.Assert(parsePeerCIDR("f00d::1").String(), Equals, "f00d::1/128")
+	c.Assert(parsePeerCIDR("k8s:app=foo"), IsNil)
+	c.Assert(parsePeerCIDR("12345"), IsNil)
+}
+
+func (s *CMDHelpersSuite) TestMatchIdentities(c *C) {
+	identities := []*models.Identity{
+		{ID: 1002, Labels: []string{"k8s:app=foo", "k8s:io.kubernetes.pod.namespace=default"}},
+		{ID: 1001, Labels: []string{"k8s:app=foo", "k8s:io.kubernetes.pod.namespace=kube-system"}},
+		{ID: 1003, Labels: []string{"k8s:app=bar", "k8s:io.kubernetes.pod.namespace=default"}},
+	}
+
+	c.Assert(matchIdentities(identities, labels.ParseSelectLabelArray("app=foo")), DeepEquals, []uint32{1001, 1002})
+	c.Assert(matchIdentities(identities, labels.ParseSelectLabelArray("k8s:app=foo", "k8s:io.kubernetes.pod.namespace=default")), DeepEquals, []uint32{1002})
+	c.Assert(matchIdentities(identities, labels.ParseSelectLabelArray("container:app=foo")), HasLen, 0)
+	c.Assert(matchIdentities(identities, nil), DeepEquals, []uint32{1001, 1002, 1003})
+}
@@ -87,6 +87,7 @@ var identityGetCmd = &cobra.Command{
 
 func init() {
 	identityCmd.AddCommand(identityGetCmd)
+	setArgCompletion(identityGetCmd, completeIdentities)
 	identityGetCmd.Flags().StringSliceVar(&lookupLabels, "label", []string{}, "Label to lookup")
 	command.AddJSONOutput(identityGetCmd)
 }
@@ -18,19 +18,25 @@ import (
 	"sort"
 
 	identityApi "github.com/cilium/cilium/api/v1/client/policy"
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/api"
 	pkg "github.com/cilium/cilium/pkg/client"
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/labels"
 
 	"github.com/spf13/cobra"
 )
 
+var filterLabels []string
+
 // identityListCmd represents the identity_list command
 var identityListCmd = &cobra.Command{
 	Use:     "list [LABELS]",
 	Aliases: []string{"ls"},
 	Short:   "List identities",
+	Example: `  cilium identity list -l k8s:app=frontend
+  cilium identity list -l app=frontend,io.kubernetes.pod.namespace=default`,
 	Run: func(cmd *cobra.Command, args []string) {
 		listIdentities(args)
 	},
@@ -38,9 +44,23 @@ var identityListCmd = &cobra.Command{
 
 func init() {
 	identityCmd.AddCommand(identityListCmd)
+	identityListCmd.Flags().StringSliceVarP(&filterLabels, "labels", "l", []string{},
+		"Only list identities carrying all of the given labels")
 	command.AddJSONOutput(identityListCmd)
 }
 
+// filterIdentities returns all identities carrying all of the needed labels.
+// Labels without source match labels of any source.
+func filterIdentities(identities []*models.Identity, needed labels.LabelArray) []*models.Identity {
+	result := []*models.Identity{}
+	for _, id := range identities {
+		if labels.NewLabelsFromModel(id.Labels).LabelArray().Contains(needed) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
 func listIdentities(args []string) {
 	params := identityApi.NewGetIdentityParams().WithTimeout(api.ClientTimeout)
 	if len(args) != 0 {
@@ -56,8 +76,13 @@ func listIdentities(args []string) {
 		}
 	}
 
+	result := identities.Payload
+	if len(filterLabels) != 0 {
+		result = filterIdentities(result, labels.ParseSelectLabelArray(filterLabels...))
+	}
+
 	// sort identities by ID
-	im := identity.IdentitiesModel(identities.Payload)
+	im := identity.IdentitiesModel(result)
 	sort.Slice(im, im.Less)
-	printIdentities(identities.Payload)
+	printIdentities(result)
 }
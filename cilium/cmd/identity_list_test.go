@@ -0,0 +1,60 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/labels"
+
+	. "gopkg.in/check.v1"
+)
+
+type IdentityListSuite struct{}
+
+var _ = Suite(&IdentityListSuite{})
+
+func (s *IdentityListSuite) TestFilterIdentities(c *C) {
+	identities := []*models.Identity{
+		{ID: 1, Labels: []string{"reserved:host"}},
+		{ID: 1000, Labels: []string{"k8s:app=frontend", "k8s:io.kubernetes.pod.namespace=default"}},
+		{ID: 1001, Labels: []string{"k8s:app=frontend", "k8s:io.kubernetes.pod.namespace=prod"}},
+		{ID: 1002, Labels: []string{"container:app=frontend"}},
+	}
+
+	ids := func(identities []*models.Identity) []int64 {
+		result := []int64{}
+		for _, id := range identities {
+			result = append(result, id.ID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		labels []string
+		ids    []int64
+	}{
+		{labels: nil, ids: []int64{1, 1000, 1001, 1002}},
+		{labels: []string{"app=frontend"}, ids: []int64{1000, 1001, 1002}},
+		{labels: []string{"k8s:app=frontend"}, ids: []int64{1000, 1001}},
+		{labels: []string{"k8s:app=frontend", "io.kubernetes.pod.namespace=prod"}, ids: []int64{1001}},
+		{labels: []string{"reserved:host"}, ids: []int64{1}},
+		{labels: []string{"k8s:app=backend"}, ids: []int64{}},
+	}
+
+	for _, tt := range tests {
+		result := filterIdentities(identities, labels.ParseSelectLabelArray(tt.labels...))
+		c.Assert(ids(result), DeepEquals, tt.ids, Commentf("labels %v", tt.labels))
+	}
+}
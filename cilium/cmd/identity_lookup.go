@@ -0,0 +1,53 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	identityApi "github.com/cilium/cilium/api/v1/client/policy"
+	"github.com/cilium/cilium/pkg/api"
+	pkg "github.com/cilium/cilium/pkg/client"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+// identityLookupCmd represents the identity_lookup command
+var identityLookupCmd = &cobra.Command{
+	Use:   "lookup <labels>...",
+	Short: "Lookup the identity allocated for a set of labels",
+	Long: `Lookup the identity the identity allocator has allocated for exactly the given
+set of labels, e.g. the identity labels of a pod. Use 'cilium identity list -l'
+to list all identities carrying a subset of labels, and 'cilium identity get'
+to lookup the labels of a numeric identity.`,
+	Example: "  cilium identity lookup k8s:app=frontend k8s:io.kubernetes.pod.namespace=default",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			Usagef(cmd, "At least one label is required")
+		}
+
+		params := identityApi.NewGetIdentityParams().WithLabels(args).WithTimeout(api.ClientTimeout)
+		resp, err := client.Policy.GetIdentity(params)
+		if err != nil {
+			Fatalf("No identity allocated for labels %s: %s\n"+
+				"Use 'cilium identity list -l' to list identities carrying these labels", args, pkg.Hint(err))
+		}
+		printIdentities(resp.Payload)
+	},
+}
+
+func init() {
+	identityCmd.AddCommand(identityLookupCmd)
+	command.AddJSONOutput(identityLookupCmd)
+}
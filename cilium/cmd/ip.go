@@ -0,0 +1,49 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	"github.com/spf13/cobra"
+)
+
+// ipCmd represents the ip command
+var ipCmd = &cobra.Command{
+	Use:   "ip",
+	Short: "Manage IP to security identity mappings",
+}
+
+func init() {
+	rootCmd.AddCommand(ipCmd)
+}
+
+// printIPEntries writes a table of the ipcache mappings to w
+func printIPEntries(w io.Writer, entries []*models.IPListEntry) {
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "PREFIX\tIDENTITY\tHOST IP\n")
+	for _, e := range entries {
+		hostIP := e.HostIP
+		if hostIP == "" {
+			hostIP = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", e.Cidr, e.Identity, hostIP)
+	}
+	tw.Flush()
+}
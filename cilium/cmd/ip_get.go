@@ -0,0 +1,57 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+// ipGetCmd represents the ip get command
+var ipGetCmd = &cobra.Command{
+	Use:   "get <ip address>",
+	Short: "Display the security identity of an IP address",
+	Long: `Display the mapping of the longest prefix containing the IP address, which
+holds the security identity the datapath uses for traffic from and to the
+address.`,
+	Example: `  cilium ip get 10.0.0.15`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 || args[0] == "" {
+			Usagef(cmd, "Missing IP address argument")
+		}
+
+		entry, err := client.IPGet(args[0])
+		if err != nil {
+			Fatalf("Cannot get IP mapping: %s", err)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(entry); err != nil {
+				Fatalf("Unable to print output: %s", err)
+			}
+			return
+		}
+		printIPEntries(os.Stdout, []*models.IPListEntry{entry})
+	},
+}
+
+func init() {
+	ipCmd.AddCommand(ipGetCmd)
+	command.AddJSONOutput(ipGetCmd)
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var ipListCIDR string
+
+// ipListCmd represents the ip list command
+var ipListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List IP to security identity mappings",
+	Long: `List the prefixes of all endpoints, nodes and CIDR policies known to the
+agent together with the security identity the datapath uses for traffic from
+and to each prefix.`,
+	Example: `  cilium ip list --cidr 10.0.0.0/8`,
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := client.IPList(ipListCIDR)
+		if err != nil {
+			Fatalf("Cannot get IP mappings: %s", err)
+		}
+
+		if command.OutputJSON() {
+			if err := command.PrintOutput(entries); err != nil {
+				Fatalf("Unable to print output: %s", err)
+			}
+			return
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No IP mappings found")
+			return
+		}
+		printIPEntries(os.Stdout, entries)
+	},
+}
+
+func init() {
+	ipCmd.AddCommand(ipListCmd)
+	ipListCmd.Flags().StringVarP(&ipListCIDR, "cidr", "c", "", "Only list the mappings of prefixes within the given CIDR range")
+	command.AddJSONOutput(ipListCmd)
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+type IPSuite struct{}
+
+var _ = Suite(&IPSuite{})
+
+func (s *IPSuite) TestPrintIPEntries(c *C) {
+	entries := []*models.IPListEntry{
+		{Cidr: "10.0.0.0/8", Identity: 16777217},
+		{Cidr: "10.0.0.15/32", Identity: 68, HostIP: "192.168.1.1"},
+	}
+
+	buf := &bytes.Buffer{}
+	printIPEntries(buf, entries)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	c.Assert(lines, HasLen, 3)
+	c.Assert(strings.Fields(lines[0]), DeepEquals, []string{"PREFIX", "IDENTITY", "HOST", "IP"})
+	c.Assert(strings.Fields(lines[1]), DeepEquals, []string{"10.0.0.0/8", "16777217", "-"})
+	c.Assert(strings.Fields(lines[2]), DeepEquals, []string{"10.0.0.15/32", "68", "192.168.1.1"})
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/kvstore"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	casExpect     string
+	casCreateOnly bool
+)
+
+var kvstoreCasCmd = &cobra.Command{
+	Use:   "cas [options] <key> <value>",
+	Short: "Atomically compare and swap the value of a key",
+	Long: `Atomically set the value of a key if its current value matches the value
+given with --expect, or if the key does not exist with --create-only. If the
+key was modified concurrently, the key is left unchanged and the current value
+is reported.`,
+	Example: `  cilium kvstore cas --expect bar foo baz
+  cilium kvstore cas --create-only foo bar`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			Usagef(cmd, "Please specify a key and a value")
+		}
+		expected, err := casExpectedValue(cmd)
+		if err != nil {
+			Usagef(cmd, "%s", err)
+		}
+
+		setupKvstore()
+
+		key, value := args[0], args[1]
+		if err := kvstore.CompareAndSwap(key, expected, []byte(value)); err != nil {
+			if err != kvstore.ErrCompareFailed {
+				Fatalf("Unable to compare and swap key: %s", err)
+			}
+			current, _ := kvstore.GetWithConsistency(key, kvstore.ConsistencyLinearizable)
+			if current == nil {
+				Fatalf("Unable to compare and swap key %s: key does not exist", key)
+			}
+			Fatalf("Unable to compare and swap key %s: current value is %q", key, string(current))
+		}
+	},
+}
+
+// casExpectedValue returns the value the key is expected to have, nil if the
+// key is expected to not exist.
+func casExpectedValue(cmd *cobra.Command) ([]byte, error) {
+	expect := cmd.Flags().Changed("expect")
+	switch {
+	case expect && casCreateOnly:
+		return nil, fmt.Errorf("--expect and --create-only are mutually exclusive")
+	case casCreateOnly:
+		return nil, nil
+	case expect:
+		return []byte(casExpect), nil
+	}
+	return nil, fmt.Errorf("expected value must be given with --expect or --create-only")
+}
+
+func init() {
+	kvstoreCmd.AddCommand(kvstoreCasCmd)
+	kvstoreCasCmd.Flags().StringVar(&casExpect, "expect", "", "Expected current value of the key")
+	kvstoreCasCmd.Flags().BoolVar(&casCreateOnly, "create-only", false, "Only create the key if it does not exist")
+}
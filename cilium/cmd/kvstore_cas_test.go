@@ -0,0 +1,55 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	. "gopkg.in/check.v1"
+)
+
+type KvstoreCasSuite struct{}
+
+var _ = Suite(&KvstoreCasSuite{})
+
+func (s *KvstoreCasSuite) TestCasExpectedValue(c *C) {
+	newCmd := func(args ...string) *cobra.Command {
+		casExpect, casCreateOnly = "", false
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&casExpect, "expect", "", "")
+		cmd.Flags().BoolVar(&casCreateOnly, "create-only", false, "")
+		c.Assert(cmd.Flags().Parse(args), IsNil)
+		return cmd
+	}
+
+	_, err := casExpectedValue(newCmd())
+	c.Assert(err, ErrorMatches, "expected value must be given.*")
+
+	_, err = casExpectedValue(newCmd("--expect=foo", "--create-only"))
+	c.Assert(err, ErrorMatches, ".*mutually exclusive")
+
+	expected, err := casExpectedValue(newCmd("--create-only"))
+	c.Assert(err, IsNil)
+	c.Assert(expected, IsNil)
+
+	// An empty expected value is different from a missing key
+	expected, err = casExpectedValue(newCmd("--expect="))
+	c.Assert(err, IsNil)
+	c.Assert(expected, DeepEquals, []byte{})
+
+	expected, err = casExpectedValue(newCmd("--expect=foo"))
+	c.Assert(err, IsNil)
+	c.Assert(expected, DeepEquals, []byte("foo"))
+}
@@ -25,12 +25,12 @@ var kvstoreDeleteCmd = &cobra.Command{
 	Short:   "Delete a key",
 	Example: "cilium kvstore delete --recursive foo",
 	Run: func(cmd *cobra.Command, args []string) {
-		setupKvstore()
-
 		if len(args) < 1 {
-			Fatalf("Please specify a key or key prefix to delete")
+			Usagef(cmd, "Please specify a key or key prefix to delete")
 		}
 
+		setupKvstore()
+
 		if recursive {
 			if err := kvstore.DeletePrefix(args[0]); err != nil {
 				Fatalf("Unable to delete keys: %s", err)
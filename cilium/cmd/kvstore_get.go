@@ -16,7 +16,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/kvstore"
@@ -24,13 +23,26 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var getConsistency string
+
 var kvstoreGetCmd = &cobra.Command{
-	Use:     "get [options] <key>",
-	Short:   "Retrieve a key",
+	Use:   "get [options] <key>",
+	Short: "Retrieve a key",
+	Long: `Retrieve a key or, with --recursive, all keys with the given prefix.
+
+Reads are linearizable by default and reflect all writes committed before the
+read. Serializable reads with --consistency=serializable may be served by any
+member of the kvstore cluster and can return stale data, but do not require a
+quorum.`,
 	Example: "cilium kvstore get --recursive foo",
 	Run: func(cmd *cobra.Command, args []string) {
 		key := ""
 
+		consistency, err := kvstore.ParseConsistency(getConsistency)
+		if err != nil {
+			Usagef(cmd, "%s", err)
+		}
+
 		setupKvstore()
 
 		if len(args) > 0 {
@@ -38,13 +50,13 @@ var kvstoreGetCmd = &cobra.Command{
 		}
 
 		if recursive {
-			pairs, err := kvstore.ListPrefix(key)
+			pairs, err := kvstore.ListPrefixWithConsistency(key, consistency)
 			if err != nil {
 				Fatalf("Unable to list keys: %s", err)
 			}
 			if command.OutputJSON() {
 				if err := command.PrintOutput(pairs); err != nil {
-					os.Exit(1)
+					Fatalf("Unable to print output: %s", err)
 				}
 				return
 			}
@@ -52,13 +64,13 @@ var kvstoreGetCmd = &cobra.Command{
 				fmt.Printf("%s => %s\n", k, string(v))
 			}
 		} else {
-			val, err := kvstore.Get(key)
+			val, err := kvstore.GetWithConsistency(key, consistency)
 			if err != nil {
 				Fatalf("Unable to retrieve key: %s", err)
 			}
 			if command.OutputJSON() {
 				if err := command.PrintOutput(val); err != nil {
-					os.Exit(1)
+					Fatalf("Unable to print output: %s", err)
 				}
 				return
 			}
@@ -70,5 +82,7 @@ var kvstoreGetCmd = &cobra.Command{
 func init() {
 	kvstoreCmd.AddCommand(kvstoreGetCmd)
 	kvstoreGetCmd.Flags().BoolVar(&recursive, "recursive", false, "Recursive lookup")
+	kvstoreGetCmd.Flags().StringVar(&getConsistency, "consistency", string(kvstore.ConsistencyLinearizable),
+		fmt.Sprintf("Consistency of the read { %s | %s }", kvstore.ConsistencyLinearizable, kvstore.ConsistencySerializable))
 	command.AddJSONOutput(kvstoreGetCmd)
 }
@@ -26,17 +26,27 @@ var (
 )
 
 var kvstoreSetCmd = &cobra.Command{
-	Use:     "set [options] <key>",
-	Short:   "Set a key and value",
-	Example: "cilium kvstore set foo=bar",
+	Use:   "set [options] <key> <value>",
+	Short: "Set a key and value",
+	Long: `Set a key and value unconditionally. Use 'cilium kvstore cas' to only set
+the key if its current value is known.`,
+	Example: `  cilium kvstore set foo bar
+  cilium kvstore set --key=foo --value=bar`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if key == "" {
-			Fatalf("--key attribute reqiured")
+		k, v := key, value
+		if len(args) > 0 {
+			k = args[0]
+		}
+		if len(args) > 1 {
+			v = args[1]
+		}
+		if k == "" {
+			Usagef(cmd, "Please specify a key")
 		}
 
 		setupKvstore()
 
-		err := kvstore.Set(key, []byte(value))
+		err := kvstore.Set(k, []byte(v))
 		if err != nil {
 			Fatalf("Unable to set key: %s", err)
 		}
@@ -0,0 +1,110 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	daemonAPI "github.com/cilium/cilium/api/v1/client/daemon"
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/api"
+	"github.com/cilium/cilium/pkg/command"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	followMapEvents  bool
+	mapEventInterval time.Duration
+)
+
+// mapEventsCmd represents the map_events command
+var mapEventsCmd = &cobra.Command{
+	Use:   "events <name>",
+	Short: "Display the recent mutations of a BPF map",
+	Long: `Display the most recent updates and deletions of entries of a BPF map
+performed by the agent, including the ones which failed, together with the
+agent function which requested them.`,
+	Example: "cilium map events cilium_lb4_services --follow",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			Fatalf("map name must be specified")
+		}
+
+		var since int64
+		printHeader := true
+		for {
+			events := getMapEvents(args[0], since)
+			if len(events) > 0 {
+				since = events[len(events)-1].Seq
+			}
+
+			if command.OutputJSON() {
+				if err := command.PrintOutput(events); err != nil {
+					Fatalf("Unable to print output: %s", err)
+				}
+			} else if len(events) > 0 || printHeader {
+				printMapEvents(os.Stdout, events, printHeader)
+				printHeader = false
+			}
+
+			if !followMapEvents {
+				return
+			}
+			time.Sleep(mapEventInterval)
+		}
+	},
+}
+
+func getMapEvents(name string, since int64) []*models.BPFMapEvent {
+	params := daemonAPI.NewGetMapNameEventsParams().WithName(name).WithTimeout(api.ClientTimeout)
+	if since > 0 {
+		params.SetSince(&since)
+	}
+
+	resp, err := client.Daemon.GetMapNameEvents(params)
+	if err != nil {
+		Fatalf("%s", err)
+	}
+	return resp.Payload
+}
+
+func printMapEvents(out io.Writer, events []*models.BPFMapEvent, header bool) {
+	w := tabwriter.NewWriter(out, 5, 0, 3, ' ', 0)
+	if header {
+		fmt.Fprintf(w, "Seq\tTime\tAction\tKey\tValue\tCaller\tError\n")
+	}
+	for _, e := range events {
+		if e == nil {
+			continue
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", e.Seq,
+			time.Time(e.Timestamp).Format(time.RFC3339), e.Action,
+			e.Key, e.Value, e.Caller, e.Error)
+	}
+	w.Flush()
+}
+
+func init() {
+	mapCmd.AddCommand(mapEventsCmd)
+	setArgCompletion(mapEventsCmd, completeMaps)
+	mapEventsCmd.Flags().BoolVarP(&followMapEvents, "follow", "f", false, "Keep printing new events as they occur")
+	mapEventsCmd.Flags().DurationVar(&mapEventInterval, "interval", time.Second, "Interval at which new events are retrieved with --follow")
+	command.AddJSONOutput(mapEventsCmd)
+}
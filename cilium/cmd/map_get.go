@@ -51,7 +51,7 @@ var mapGetCmd = &cobra.Command{
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(m); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 		} else {
 			printMapEntries(m)
@@ -83,5 +83,6 @@ func printMapEntries(m *models.BPFMap) {
 
 func init() {
 	mapCmd.AddCommand(mapGetCmd)
+	setArgCompletion(mapGetCmd, completeMaps)
 	command.AddJSONOutput(mapGetCmd)
 }
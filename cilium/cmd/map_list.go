@@ -16,6 +16,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"text/tabwriter"
@@ -28,8 +29,12 @@ import (
 
 // mapListCmd represents the map_list command
 var mapListCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List all open BPF maps",
+	Use:   "list",
+	Short: "List all open BPF maps",
+	Long: `List all BPF maps opened by the agent. The pressure of a map is the ratio
+of the number of entries to the maximum number of entries, sampled by the
+agent every minute. New entries are dropped once a map is full. Maps without
+meaningful pressure, such as arrays, and maps not sampled yet show "-".`,
 	Example: "cilium map list",
 	Run: func(cmd *cobra.Command, args []string) {
 		resp, err := client.Daemon.GetMap(nil)
@@ -44,13 +49,13 @@ var mapListCmd = &cobra.Command{
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(mapList); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 		} else if mapList.Maps != nil {
 			if verbose {
 				printMapListVerbose(mapList)
 			} else {
-				printMapList(mapList)
+				printMapList(os.Stdout, mapList)
 			}
 		}
 	},
@@ -64,9 +69,18 @@ func printMapListVerbose(mapList *models.BPFMapList) {
 	}
 }
 
-func printMapList(mapList *models.BPFMapList) {
-	w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
-	fmt.Fprintf(w, "Name\tNum entries\tNum errors\tCache enabled\n")
+// formatMapPressure returns the fill ratio of a map as percentage, "-" if it
+// has not been sampled.
+func formatMapPressure(p *models.BPFMapPressure) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", p.Ratio*100)
+}
+
+func printMapList(w io.Writer, mapList *models.BPFMapList) {
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "Name\tNum entries\tNum errors\tCache enabled\tPressure\n")
 	for _, m := range mapList.Maps {
 		entries, errors := 0, 0
 		cacheEnabled := m.Cache != nil
@@ -79,10 +93,10 @@ func printMapList(mapList *models.BPFMapList) {
 				entries++
 			}
 		}
-		fmt.Fprintf(w, "%s\t%d\t%d\t%t\n",
-			path.Base(m.Path), entries, errors, cacheEnabled)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%t\t%s\n",
+			path.Base(m.Path), entries, errors, cacheEnabled, formatMapPressure(m.Pressure))
 	}
-	w.Flush()
+	tw.Flush()
 }
 
 func init() {
@@ -0,0 +1,56 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+type MapListSuite struct{}
+
+var _ = Suite(&MapListSuite{})
+
+func (s *MapListSuite) TestPrintMapList(c *C) {
+	mapList := &models.BPFMapList{
+		Maps: []*models.BPFMap{
+			{
+				Path:     "/sys/fs/bpf/tc/globals/cilium_lxc",
+				Cache:    []*models.BPFMapEntry{{Key: "1"}, {Key: "2", LastError: "E2BIG"}},
+				Pressure: &models.BPFMapPressure{Entries: 2, MaxEntries: 65535, Ratio: 2.0 / 65535},
+			},
+			{
+				Path:     "/sys/fs/bpf/tc/globals/cilium_ct4_global",
+				Pressure: &models.BPFMapPressure{Entries: 950, MaxEntries: 1000, Ratio: 0.95},
+			},
+			{
+				Path: "/sys/fs/bpf/tc/globals/cilium_call_policy",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	printMapList(&buf, mapList)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	c.Assert(lines, HasLen, 4)
+	c.Assert(strings.Fields(lines[0]), DeepEquals, []string{"Name", "Num", "entries", "Num", "errors", "Cache", "enabled", "Pressure"})
+	c.Assert(strings.Fields(lines[1]), DeepEquals, []string{"cilium_lxc", "2", "1", "true", "0.0%"})
+	c.Assert(strings.Fields(lines[2]), DeepEquals, []string{"cilium_ct4_global", "0", "0", "false", "95.0%"})
+	c.Assert(strings.Fields(lines[3]), DeepEquals, []string{"cilium_call_policy", "0", "0", "false", "-"})
+}
@@ -2,47 +2,57 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/spf13/cobra"
 )
 
 // MetricsListCmd dumps all metrics into stdout
 var MetricsListCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [pattern]",
 	Short: "List all metrics",
+	Long: `List the metrics of the agent. The metrics are available via the API of the
+agent even if serving them to Prometheus is disabled.
+
+If a pattern is given, only metrics whose name matches the regular expression
+are listed.`,
+	Example: `  cilium metrics list
+  cilium metrics list 'endpoint_regeneration'
+  cilium metrics list '^cilium_policy_.*_total$'`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			Usagef(cmd, "Too many arguments, expected at most one pattern")
+		}
+		var pattern *regexp.Regexp
+		if len(args) == 1 {
+			var err error
+			if pattern, err = regexp.Compile(args[0]); err != nil {
+				Usagef(cmd, "Invalid pattern %q: %s", args[0], err)
+			}
+		}
+
 		res, err := client.Metrics.GetMetrics(nil)
 		if err != nil {
 			Fatalf("Cannot get metrics list: %s", err)
 		}
 
+		metrics := filterMetrics(res.Payload, pattern)
+
 		if command.OutputJSON() {
-			if err := command.PrintOutput(res.Payload); err != nil {
-				os.Exit(1)
+			if err := command.PrintOutput(metrics); err != nil {
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
 
-		w := tabwriter.NewWriter(os.Stdout, 5, 0, 3, ' ', 0)
-
-		fmt.Fprintln(w, "Metric\tLabels\tValue")
-		for _, metric := range res.Payload {
-			label := ""
-			if len(metric.Labels) > 0 {
-				labelArray := []string{}
-				for key, value := range metric.Labels {
-					labelArray = append(labelArray, fmt.Sprintf(`%s="%s"`, key, value))
-				}
-				label = strings.Join(labelArray, " ")
-			}
-			fmt.Fprintf(w, "%s\t%s\t%f\n", metric.Name, label, metric.Value)
-		}
-		w.Flush()
-		os.Exit(0)
+		printMetrics(os.Stdout, metrics)
 	},
 }
 
@@ -50,3 +60,45 @@ func init() {
 	metricsCmd.AddCommand(MetricsListCmd)
 	command.AddJSONOutput(MetricsListCmd)
 }
+
+// formatMetricLabels returns the labels of a metric sorted by name in
+// Prometheus notation.
+func formatMetricLabels(labels map[string]string) string {
+	labelArray := make([]string, 0, len(labels))
+	for key, value := range labels {
+		labelArray = append(labelArray, fmt.Sprintf(`%s="%s"`, key, value))
+	}
+	sort.Strings(labelArray)
+	return strings.Join(labelArray, " ")
+}
+
+// filterMetrics returns the metrics whose name matches pattern, sorted by
+// name and labels. All metrics are returned if pattern is nil.
+func filterMetrics(metrics []*models.Metric, pattern *regexp.Regexp) []*models.Metric {
+	result := make([]*models.Metric, 0, len(metrics))
+	for _, metric := range metrics {
+		if metric == nil || (pattern != nil && !pattern.MatchString(metric.Name)) {
+			continue
+		}
+		result = append(result, metric)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return formatMetricLabels(result[i].Labels) < formatMetricLabels(result[j].Labels)
+	})
+	return result
+}
+
+// printMetrics writes the metrics as a table to w.
+func printMetrics(w io.Writer, metrics []*models.Metric) {
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+
+	fmt.Fprintln(tw, "Metric\tLabels\tValue")
+	for _, metric := range metrics {
+		fmt.Fprintf(tw, "%s\t%s\t%f\n", metric.Name, formatMetricLabels(metric.Labels), metric.Value)
+	}
+	tw.Flush()
+}
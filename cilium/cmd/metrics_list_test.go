@@ -0,0 +1,60 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+type MetricsListSuite struct{}
+
+var _ = Suite(&MetricsListSuite{})
+
+func (s *MetricsListSuite) TestFilterMetrics(c *C) {
+	metrics := []*models.Metric{
+		{Name: "cilium_policy_count", Value: 3},
+		{Name: "cilium_drop_count_total", Labels: map[string]string{"reason": "b", "direction": "INGRESS"}, Value: 2},
+		{Name: "cilium_drop_count_total", Labels: map[string]string{"reason": "a"}, Value: 1},
+		nil,
+	}
+
+	filtered := filterMetrics(metrics, nil)
+	c.Assert(filtered, HasLen, 3)
+	c.Assert(filtered[0].Labels["reason"], Equals, "b")
+	c.Assert(filtered[1].Labels["reason"], Equals, "a")
+	c.Assert(filtered[2].Name, Equals, "cilium_policy_count")
+
+	filtered = filterMetrics(metrics, regexp.MustCompile("^cilium_policy"))
+	c.Assert(filtered, HasLen, 1)
+	c.Assert(filtered[0].Name, Equals, "cilium_policy_count")
+
+	c.Assert(filterMetrics(metrics, regexp.MustCompile("endpoint")), HasLen, 0)
+
+	var buf bytes.Buffer
+	printMetrics(&buf, filtered)
+	c.Assert(buf.String(), Equals, "Metric                Labels   Value\n"+
+		"cilium_policy_count            3.000000\n")
+}
+
+func (s *MetricsListSuite) TestFormatMetricLabels(c *C) {
+	c.Assert(formatMetricLabels(nil), Equals, "")
+	c.Assert(formatMetricLabels(map[string]string{"reason": "a", "direction": "INGRESS"}),
+		Equals, `direction="INGRESS" reason="a"`)
+}
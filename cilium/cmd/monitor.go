@@ -31,6 +31,7 @@ import (
 	"github.com/cilium/cilium/pkg/monitor/payload"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 const (
@@ -46,12 +47,28 @@ var (
 programs attached to endpoints and devices. This includes:
   * Dropped packet notifications
   * Captured packet traces
-  * Debugging information`,
+  * Debugging information
+
+With --format pcap, the packets carried by drop, trace and capture events are
+written to a pcapng file which can be opened in Wireshark. Each packet is
+annotated with a comment describing the event. With --format hexdump, the
+packets are written as hexdump preceded by the description of the event.
+Other events are not written in these formats.`,
+		Example: `  cilium monitor --type drop
+  cilium monitor --format pcap --out drops.pcap --type drop
+  cilium monitor --format pcap --related-to 1234 | wireshark -k -i -`,
 		Run: func(cmd *cobra.Command, args []string) {
 			runMonitor(args)
 		},
 	}
 	printer = format.NewMonitorFormatter(format.INFO)
+
+	captureFormat string
+	captureOut    string
+
+	// packetWriter writes the packets of events if a capture format
+	// other than text is selected
+	packetWriter format.PacketWriter
 )
 
 func init() {
@@ -60,9 +77,18 @@ func init() {
 	monitorCmd.Flags().VarP(&printer.EventTypes, "type", "t", fmt.Sprintf("Filter by event types %v", monitor.GetAllTypes()))
 	monitorCmd.Flags().Var(&printer.FromSource, "from", "Filter by source endpoint id")
 	monitorCmd.Flags().Var(&printer.ToDst, "to", "Filter by destination endpoint id")
+	monitorCmd.Flags().Var(&printer.ToDst, "to-endpoint", "Filter by destination endpoint id (same as --to)")
+	monitorCmd.Flags().Var(&printer.FromIdentity, "from-identity", "Filter by source security identity")
+	monitorCmd.Flags().Var(&printer.Verdicts, "verdict", fmt.Sprintf("Filter by event verdict %v", monitor.GetAllVerdicts()))
 	monitorCmd.Flags().Var(&printer.Related, "related-to", "Filter by either source or destination endpoint id")
 	monitorCmd.Flags().BoolVarP(&printer.Verbose, "verbose", "v", false, "Enable verbose output")
-	monitorCmd.Flags().BoolVarP(&printer.JSONOutput, "json", "j", false, "Enable json output. Shadows -v flag")
+	monitorCmd.Flags().BoolVarP(&printer.JSONOutput, "json", "j", false, "Enable json output with one event per line. Shadows -v flag")
+	monitorCmd.Flags().StringVar(&captureFormat, "format", format.CaptureText, fmt.Sprintf("Output format %v", format.GetAllCaptureFormats()))
+	monitorCmd.Flags().StringVar(&captureOut, "out", "", "Write the output of --format pcap or hexdump to a file instead of stdout")
+	for _, flag := range []string{"from", "to", "to-endpoint", "related-to"} {
+		setFlagCompletion(monitorCmd, flag, completeEndpoints)
+	}
+	setFlagCompletion(monitorCmd, "from-identity", completeIdentities)
 }
 
 func setVerbosity() {
@@ -75,6 +101,42 @@ func setVerbosity() {
 	}
 }
 
+// setupPacketWriter sets up packetWriter according to --format and --out.
+func setupPacketWriter() {
+	if captureOut != "" && captureFormat == format.CaptureText {
+		Exitf(ExitUsage, "--out requires --format %s or %s", format.CapturePcap, format.CaptureHexdump)
+	}
+
+	switch captureFormat {
+	case format.CaptureText:
+		return
+	case format.CapturePcap, format.CaptureHexdump:
+	default:
+		Exitf(ExitUsage, "unknown format %q, must be one of %v", captureFormat, format.GetAllCaptureFormats())
+	}
+
+	out := os.Stdout
+	if captureOut != "" {
+		f, err := os.Create(captureOut)
+		if err != nil {
+			Fatalf("Unable to create %s: %s", captureOut, err)
+		}
+		out = f
+	} else if captureFormat == format.CapturePcap && terminal.IsTerminal(int(os.Stdout.Fd())) {
+		Exitf(ExitUsage, "refusing to write pcap data to a terminal, use --out or redirect stdout")
+	}
+
+	if captureFormat == format.CaptureHexdump {
+		packetWriter = format.NewHexdumpWriter(out)
+		return
+	}
+	w, err := format.NewPcapWriter(out)
+	if err != nil {
+		Fatalf("Unable to write pcap header: %s", err)
+	}
+	packetWriter = w
+}
+
 func setupSigHandler() {
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt)
@@ -91,6 +153,13 @@ func setupSigHandler() {
 func openMonitorSock() (conn net.Conn, version listener.Version, err error) {
 	errors := make([]string, 0)
 
+	// try the 1.3 socket
+	conn, err = net.Dial("unix", defaults.MonitorSockPath1_3)
+	if err == nil {
+		return conn, listener.Version1_3, nil
+	}
+	errors = append(errors, defaults.MonitorSockPath1_3+": "+err.Error())
+
 	// try the 1.2 socket
 	conn, err = net.Dial("unix", defaults.MonitorSockPath1_2)
 	if err == nil {
@@ -124,6 +193,16 @@ func consumeMonitorEvents(conn net.Conn, version listener.Version) error {
 		if err != nil {
 			return err
 		}
+		if packetWriter != nil {
+			if pl.Type == payload.RecordLost {
+				log.Warnf("Lost %d events on CPU %d", pl.Lost, pl.CPU)
+			} else if p := printer.EventPacket(pl); p != nil {
+				if err := packetWriter.WritePacket(p); err != nil {
+					log.WithError(err).Fatal("Unable to write packet")
+				}
+			}
+			continue
+		}
 		if !printer.FormatEvent(pl) {
 			// earlier code used an else to handle this case, along with pl.Type ==
 			// payload.RecordLost above. It should be safe to call lostEvent to match
@@ -156,6 +235,13 @@ func getMonitorParser(conn net.Conn, version listener.Version) (parser eventPars
 			return &pl, nil
 		}, nil
 
+	case listener.Version1_3:
+		// The node monitor only sends the events matching the filter
+		if err := gob.NewEncoder(conn).Encode(printer.Filter()); err != nil {
+			return nil, fmt.Errorf("unable to send event filter: %s", err)
+		}
+		fallthrough
+
 	case listener.Version1_2:
 		var (
 			pl  payload.Payload
@@ -177,19 +263,24 @@ func getMonitorParser(conn net.Conn, version listener.Version) (parser eventPars
 
 func runMonitor(args []string) {
 	if len(args) > 0 {
-		fmt.Println("Error: arguments not recognized")
-		os.Exit(1)
+		Exitf(ExitUsage, "arguments not recognized")
 	}
 
 	setVerbosity()
+	setupPacketWriter()
 	setupSigHandler()
-	if resp, err := client.Daemon.GetHealthz(nil); err == nil {
-		if nm := resp.Payload.NodeMonitor; nm != nil {
-			fmt.Printf("Listening for events on %d CPUs with %dx%d of shared memory\n",
-				nm.Cpus, nm.Npages, nm.Pagesize)
+	// In JSON mode, only events are written to stdout so that the output
+	// can be consumed line by line. The same applies to captures written to
+	// stdout.
+	if !printer.JSONOutput && (packetWriter == nil || captureOut != "") {
+		if resp, err := client.Daemon.GetHealthz(nil); err == nil {
+			if nm := resp.Payload.NodeMonitor; nm != nil {
+				fmt.Printf("Listening for events on %d CPUs with %dx%d of shared memory\n",
+					nm.Cpus, nm.Npages, nm.Pagesize)
+			}
 		}
+		fmt.Printf("Press Ctrl-C to quit\n")
 	}
-	fmt.Printf("Press Ctrl-C to quit\n")
 
 	// On EOF, retry
 	// On other errors, exit
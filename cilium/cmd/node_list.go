@@ -35,8 +35,7 @@ var nodeListCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		resp, err := client.Daemon.GetHealthz(nil)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "%s\n", pkg.Hint(err))
-			os.Exit(1)
+			Fatalf("%s", pkg.Hint(err))
 		}
 
 		cluster := resp.Payload.Cluster
@@ -46,7 +45,7 @@ var nodeListCmd = &cobra.Command{
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(cluster); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 		} else {
 			w := tabwriter.NewWriter(os.Stdout, 2, 0, 3, ' ', 0)
@@ -16,8 +16,10 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
@@ -25,11 +27,16 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/cilium/cilium/pkg/k8s"
+	"github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/policy/api"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sYaml "k8s.io/apimachinery/pkg/util/yaml"
 )
 
 // policyCmd represents the policy command
@@ -134,6 +141,12 @@ func loadPolicyFile(path string) (api.Rules, error) {
 		return nil, err
 	}
 
+	if ruleList, ok, err := loadK8sPolicies(content); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	} else if ok {
+		return ruleList, nil
+	}
+
 	var ruleList api.Rules
 	err = json.Unmarshal(content, &ruleList)
 	if err != nil {
@@ -143,6 +156,68 @@ func loadPolicyFile(path string) (api.Rules, error) {
 	return ruleList, nil
 }
 
+// loadK8sPolicies parses content as a stream of YAML or JSON documents holding
+// CiliumNetworkPolicy or NetworkPolicy objects and translates them into
+// policy rules the same way the agent does when it watches Kubernetes. It
+// returns false if the first document is not a Kubernetes object, in which
+// case content is expected to be a list of rules.
+func loadK8sPolicies(content []byte) (api.Rules, bool, error) {
+	dec := k8sYaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	result := api.Rules{}
+	found := false
+
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			if !found {
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		var typeMeta metav1.TypeMeta
+		if err := json.Unmarshal(raw, &typeMeta); err != nil || typeMeta.Kind == "" {
+			if !found {
+				return nil, false, nil
+			}
+			return nil, false, fmt.Errorf("document without kind in Kubernetes manifest")
+		}
+		found = true
+
+		var (
+			ruleList api.Rules
+			err      error
+		)
+		switch typeMeta.Kind {
+		case "CiliumNetworkPolicy":
+			cnp := &v2.CiliumNetworkPolicy{}
+			if err = json.Unmarshal(raw, cnp); err == nil {
+				ruleList, err = cnp.Parse()
+			}
+		case "NetworkPolicy":
+			np := &networkingv1.NetworkPolicy{}
+			if err = json.Unmarshal(raw, np); err == nil {
+				ruleList, err = k8s.ParseNetworkPolicy(np)
+			}
+		default:
+			err = fmt.Errorf("unsupported kind %q, expected CiliumNetworkPolicy or NetworkPolicy", typeMeta.Kind)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		result = append(result, ruleList...)
+	}
+
+	return result, found, nil
+}
+
 func loadPolicy(name string) (api.Rules, error) {
 	logrus.WithField(logfields.Path, name).Debug("Entering directory")
 
@@ -16,7 +16,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/cilium/cilium/pkg/command"
 
@@ -38,7 +37,7 @@ var policyDeleteCmd = &cobra.Command{
 			Fatalf("Cannot delete policy: %s\n", err)
 		} else if command.OutputJSON() {
 			if err := command.PrintOutput(resp); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 		} else {
 			fmt.Printf("Revision: %d\n", resp.Revision)
@@ -15,26 +15,53 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
-	"os"
+	"sort"
 
 	"github.com/cilium/cilium/pkg/command"
 
 	"github.com/spf13/cobra"
 )
 
+var canonicalPolicy bool
+
 // policyGetCmd represents the policy_get command
 var policyGetCmd = &cobra.Command{
 	Use:   "get [<labels>]",
 	Short: "Display policy node information",
+	Long: `Display the rules of the policy repository.
+
+With --canonical, rules and all lists nested within them are sorted and the
+revision is omitted, so that the output of different nodes, or of the same
+node over time, can be compared with diff.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if resp, err := client.PolicyGet(args); err != nil {
+		resp, err := client.PolicyGet(args)
+		if err != nil {
 			Fatalf("Cannot get policy: %s\n", err)
-		} else if command.OutputJSON() {
+		}
+		if resp == nil {
+			return
+		}
+
+		if canonicalPolicy {
+			policy, err := canonicalJSON([]byte(resp.Policy))
+			if err != nil {
+				Fatalf("Cannot canonicalize policy: %s\n", err)
+			}
+			// The revision differs between nodes and is omitted
+			// from the JSON output as well.
+			resp.Policy, resp.Revision = string(policy), 0
+		}
+
+		if command.OutputJSON() {
 			if err := command.PrintOutput(resp); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
-		} else if resp != nil {
+		} else if canonicalPolicy {
+			fmt.Printf("%s\n", resp.Policy)
+		} else {
 			fmt.Printf("%s\nRevision: %d\n", resp.Policy, resp.Revision)
 		}
 	},
@@ -42,5 +69,70 @@ var policyGetCmd = &cobra.Command{
 
 func init() {
 	policyCmd.AddCommand(policyGetCmd)
+	policyGetCmd.Flags().BoolVar(&canonicalPolicy, "canonical", false,
+		"Print rules in canonical order without revision")
 	command.AddJSONOutput(policyGetCmd)
 }
+
+// canonicalJSON returns the indented canonical representation of the JSON
+// document in data. Object keys are sorted by encoding/json, arrays are
+// sorted by the canonical representation of their elements. The order of
+// rules and of the lists within rules has no effect on the policy enforced.
+func canonicalJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	doc, err := canonicalize(doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// canonicalize recursively sorts all arrays in doc, as decoded by
+// encoding/json.
+func canonicalize(doc interface{}) (interface{}, error) {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			c, err := canonicalize(value)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = c
+		}
+		return v, nil
+
+	case []interface{}:
+		type element struct {
+			value   interface{}
+			encoded []byte
+		}
+		elements := make([]element, 0, len(v))
+		for _, value := range v {
+			c, err := canonicalize(value)
+			if err != nil {
+				return nil, err
+			}
+			encoded, err := json.Marshal(c)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element{value: c, encoded: encoded})
+		}
+		sort.SliceStable(elements, func(i, j int) bool {
+			return bytes.Compare(elements[i].encoded, elements[j].encoded) < 0
+		})
+		for i := range elements {
+			v[i] = elements[i].value
+		}
+		return v, nil
+
+	default:
+		return doc, nil
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+type PolicyGetSuite struct{}
+
+var _ = Suite(&PolicyGetSuite{})
+
+func (s *PolicyGetSuite) TestCanonicalJSON(c *C) {
+	policyA := `[
+  {
+    "endpointSelector": {"matchLabels": {"any:app": "web"}},
+    "ingress": [{
+      "fromEndpoints": [
+        {"matchLabels": {"any:app": "lb"}},
+        {"matchLabels": {"any:app": "client"}}
+      ],
+      "toPorts": [{"ports": [{"port": "443", "protocol": "TCP"}, {"port": "80", "protocol": "TCP"}]}]
+    }],
+    "labels": [{"key": "name", "source": "unspec", "value": "web"}]
+  },
+  {
+    "endpointSelector": {"matchLabels": {"any:app": "db"}},
+    "labels": [{"key": "name", "source": "unspec", "value": "db"}]
+  }
+]`
+	policyB := `[{"labels": [{"value": "db", "key": "name", "source": "unspec"}],
+  "endpointSelector": {"matchLabels": {"any:app": "db"}}},
+ {"labels": [{"key": "name", "source": "unspec", "value": "web"}],
+  "ingress": [{
+    "toPorts": [{"ports": [{"port": "80", "protocol": "TCP"}, {"port": "443", "protocol": "TCP"}]}],
+    "fromEndpoints": [{"matchLabels": {"any:app": "client"}}, {"matchLabels": {"any:app": "lb"}}]
+  }],
+  "endpointSelector": {"matchLabels": {"any:app": "web"}}}]`
+
+	a, err := canonicalJSON([]byte(policyA))
+	c.Assert(err, IsNil)
+	b, err := canonicalJSON([]byte(policyB))
+	c.Assert(err, IsNil)
+	c.Assert(string(a), Equals, string(b))
+
+	// Canonicalization is idempotent
+	again, err := canonicalJSON(a)
+	c.Assert(err, IsNil)
+	c.Assert(string(again), Equals, string(a))
+
+	c.Assert(string(a), Equals, `[
+  {
+    "endpointSelector": {
+      "matchLabels": {
+        "any:app": "db"
+      }
+    },
+    "labels": [
+      {
+        "key": "name",
+        "source": "unspec",
+        "value": "db"
+      }
+    ]
+  },
+  {
+    "endpointSelector": {
+      "matchLabels": {
+        "any:app": "web"
+      }
+    },
+    "ingress": [
+      {
+        "fromEndpoints": [
+          {
+            "matchLabels": {
+              "any:app": "client"
+            }
+          },
+          {
+            "matchLabels": {
+              "any:app": "lb"
+            }
+          }
+        ],
+        "toPorts": [
+          {
+            "ports": [
+              {
+                "port": "443",
+                "protocol": "TCP"
+              },
+              {
+                "port": "80",
+                "protocol": "TCP"
+              }
+            ]
+          }
+        ]
+      }
+    ],
+    "labels": [
+      {
+        "key": "name",
+        "source": "unspec",
+        "value": "web"
+      }
+    ]
+  }
+]`)
+
+	_, err = canonicalJSON([]byte("[{"))
+	c.Assert(err, NotNil)
+}
@@ -17,7 +17,6 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/logging/logfields"
@@ -30,8 +29,14 @@ var printPolicy bool
 var policyImportCmd = &cobra.Command{
 	Use:   "import <path>",
 	Short: "Import security policy in JSON format",
+	Long: `Import security policy in JSON format.
+
+Kubernetes manifests in YAML or JSON format containing CiliumNetworkPolicy
+or NetworkPolicy objects are detected and translated into policy rules the
+same way the agent translates them when watching Kubernetes.`,
 	Example: `  cilium policy import ~/policy.json
-  cilium policy import ./policies/app/`,
+  cilium policy import ./policies/app/
+  cilium policy import ./manifests/cnp.yaml`,
 	PreRun: requirePath,
 	Run: func(cmd *cobra.Command, args []string) {
 		path := args[0]
@@ -48,7 +53,7 @@ var policyImportCmd = &cobra.Command{
 
 			for _, r := range ruleList {
 				if err := r.Sanitize(); err != nil {
-					Fatalf("%s", err)
+					Exitf(ExitInvalid, "%s", err)
 				}
 			}
 
@@ -60,7 +65,7 @@ var policyImportCmd = &cobra.Command{
 				Fatalf("Cannot import policy: %s\n", err)
 			} else if command.OutputJSON() {
 				if err := command.PrintOutput(resp); err != nil {
-					os.Exit(1)
+					Fatalf("Unable to print output: %s", err)
 				}
 			} else if printPolicy {
 				fmt.Printf("%s\nRevision: %d\n", resp.Policy, resp.Revision)
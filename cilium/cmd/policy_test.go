@@ -0,0 +1,91 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	k8sConst "github.com/cilium/cilium/pkg/k8s/apis/cilium.io"
+	"github.com/cilium/cilium/pkg/labels"
+
+	. "gopkg.in/check.v1"
+)
+
+type PolicySuite struct{}
+
+var _ = Suite(&PolicySuite{})
+
+func (s *PolicySuite) TestLoadK8sPolicies(c *C) {
+	manifest := []byte(`
+apiVersion: cilium.io/v2
+kind: CiliumNetworkPolicy
+metadata:
+  name: allow-frontend
+  namespace: prod
+spec:
+  endpointSelector:
+    matchLabels:
+      app: backend
+  ingress:
+  - fromEndpoints:
+    - matchLabels:
+        app: frontend
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: deny-all
+  namespace: prod
+spec:
+  podSelector: {}
+---
+`)
+
+	policyNameKey := labels.LabelSourceK8s + "." + k8sConst.PolicyLabelName
+
+	rules, ok, err := loadK8sPolicies(manifest)
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, true)
+	c.Assert(rules, HasLen, 2)
+	c.Assert(rules[0].Labels.Get(policyNameKey), Equals, "allow-frontend")
+	c.Assert(rules[0].Ingress, HasLen, 1)
+	c.Assert(rules[1].Labels.Get(policyNameKey), Equals, "deny-all")
+
+	// A plain list of rules is not a Kubernetes manifest
+	rules, ok, err = loadK8sPolicies([]byte(`[{"endpointSelector": {"matchLabels": {"app": "backend"}}}]`))
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+	c.Assert(rules, IsNil)
+
+	_, ok, err = loadK8sPolicies([]byte("not a policy"))
+	c.Assert(err, IsNil)
+	c.Assert(ok, Equals, false)
+
+	_, _, err = loadK8sPolicies([]byte(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: foo
+`))
+	c.Assert(err, Not(IsNil))
+
+	_, _, err = loadK8sPolicies([]byte(`
+apiVersion: cilium.io/v2
+kind: CiliumNetworkPolicy
+metadata:
+  namespace: prod
+spec:
+  endpointSelector: {}
+`))
+	c.Assert(err, Not(IsNil))
+}
@@ -16,7 +16,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 
@@ -175,7 +174,7 @@ If multiple sources and / or destinations are provided, each source is tested wh
 					Fatalf("Error while retrieving policy assessment result: %s\n", err)
 				} else if command.OutputJSON() {
 					if err := command.PrintOutput(scr); err != nil {
-						os.Exit(1)
+						Fatalf("Unable to print output: %s", err)
 					}
 				} else if scr != nil && scr.Payload != nil {
 					fmt.Println("----------------------------------------------------------------")
@@ -202,6 +201,10 @@ func init() {
 	policyTraceCmd.Flags().StringVarP(&srcK8sYaml, "src-k8s-yaml", "", "", "Path to YAML file for source")
 	policyTraceCmd.Flags().StringVarP(&dstK8sYaml, "dst-k8s-yaml", "", "", "Path to YAML file for destination")
 	command.AddJSONOutput(policyTraceCmd)
+	setFlagCompletion(policyTraceCmd, "src-identity", completeIdentities)
+	setFlagCompletion(policyTraceCmd, "dst-identity", completeIdentities)
+	setFlagCompletion(policyTraceCmd, "src-endpoint", completeEndpoints)
+	setFlagCompletion(policyTraceCmd, "dst-endpoint", completeEndpoints)
 }
 
 func appendIdentityLabelsToSlice(labelSlice []string, secID string) []string {
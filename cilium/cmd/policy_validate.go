@@ -1,4 +1,4 @@
-// Copyright 2017 Authors of Cilium
+// Copyright 2017-2018 Authors of Cilium
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -17,34 +17,49 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
 
 	"github.com/spf13/cobra"
 )
 
 // policyValidateCmd represents the policy_validate command
 var policyValidateCmd = &cobra.Command{
-	Use:    "validate <path>",
-	Short:  "Validate a policy",
-	PreRun: requirePath,
+	Use:   "validate <path>",
+	Short: "Validate a policy",
+	Long: `Validate a policy file or a directory of policy files without a running
+agent. Each rule is sanitized and the rules are then checked against each other
+for conflicts which would cause the agent to fail to compute the policy of the
+selected endpoints. All errors are reported, indexed by the position of the
+rule in the policy, and the command exits with a non-zero status if any rule is
+invalid.`,
+	Example: "  cilium policy validate ./policies/",
+	PreRun:  requirePath,
 	Run: func(cmd *cobra.Command, args []string) {
 		path := args[0]
-		if ruleList, err := loadPolicy(path); err != nil {
+		ruleList, err := loadPolicy(path)
+		if err != nil {
 			Fatalf("Validation of policy has failed: %s\n", err)
-		} else {
-			for _, r := range ruleList {
-				if err := r.Sanitize(); err != nil {
-					Fatalf("Validation of policy has failed: %s\n", err)
-				}
+		}
+
+		if errs := validateRules(ruleList); len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Fprintf(os.Stderr, "%s\n", err)
 			}
-			fmt.Printf("All policy elements are valid.\n")
-
-			if printPolicy {
-				jsonPolicy, err := json.MarshalIndent(ruleList, "", "  ")
-				if err != nil {
-					Fatalf("Cannot marshal policy: %s\n", err)
-				}
-				fmt.Printf("%s", string(jsonPolicy))
+			Exitf(ExitInvalid, "Validation of policy has failed: %d error(s) found\n", len(errs))
+		}
+		fmt.Printf("All policy elements are valid.\n")
+
+		if printPolicy {
+			jsonPolicy, err := json.MarshalIndent(ruleList, "", "  ")
+			if err != nil {
+				Fatalf("Cannot marshal policy: %s\n", err)
 			}
+			fmt.Printf("%s", string(jsonPolicy))
 		}
 	},
 }
@@ -54,3 +69,80 @@ func init() {
 	policyValidateCmd.Flags().BoolVarP(&printPolicy, "print", "", false, "Print policy after validation")
 
 }
+
+// ruleError is a validation error of the rule at position index in the list
+// of rules being validated.
+type ruleError struct {
+	index  int
+	labels labels.LabelArray
+	err    error
+}
+
+func (e ruleError) Error() string {
+	if len(e.labels) == 0 {
+		return fmt.Sprintf("rule %d: %s", e.index, e.err)
+	}
+	return fmt.Sprintf("rule %d (%s): %s", e.index, e.labels, e.err)
+}
+
+// selectedLabels returns a set of labels which is selected by the match
+// labels of the given endpoint selector. It is used to resolve the policy of
+// a synthetic endpoint selected by a rule.
+func selectedLabels(es api.EndpointSelector) labels.LabelArray {
+	lbls := labels.LabelArray{}
+	if es.LabelSelector == nil {
+		return lbls
+	}
+	for k, v := range es.MatchLabels {
+		lbls = append(lbls, labels.ParseLabel(labels.GetCiliumKeyFrom(k)+"="+v))
+	}
+	sort.Slice(lbls, func(i, j int) bool { return lbls[i].String() < lbls[j].String() })
+	return lbls
+}
+
+// validateRules performs the same validation on rules as the agent does on
+// import, without requiring a running agent. Each rule is sanitized, and the
+// L4 policy of the endpoints selected by each valid rule is resolved against
+// all valid rules to detect rules conflicting with each other. The returned
+// errors are indexed by the position of the offending rule in rules.
+func validateRules(rules api.Rules) []error {
+	var (
+		errs      []error
+		indices   []int
+		sanitized api.Rules
+		repo      = policy.NewPolicyRepository()
+	)
+
+	for i, r := range rules {
+		if err := r.Sanitize(); err != nil {
+			errs = append(errs, ruleError{index: i, labels: r.Labels, err: err})
+			continue
+		}
+		indices = append(indices, i)
+		sanitized = append(sanitized, r)
+	}
+	repo.AddList(sanitized)
+
+	for j, r := range sanitized {
+		i := indices[j]
+		lbls := selectedLabels(r.EndpointSelector)
+
+		if len(r.Ingress) > 0 {
+			if _, err := repo.ResolveL4IngressPolicy(&policy.SearchContext{To: lbls}); err != nil {
+				errs = append(errs, ruleError{index: i, labels: r.Labels,
+					err: fmt.Errorf("conflicting ingress policy for endpoints with labels %s: %s", lbls, err)})
+			}
+		}
+		if len(r.Egress) > 0 {
+			if _, err := repo.ResolveL4EgressPolicy(&policy.SearchContext{From: lbls}); err != nil {
+				errs = append(errs, ruleError{index: i, labels: r.Labels,
+					err: fmt.Errorf("conflicting egress policy for endpoints with labels %s: %s", lbls, err)})
+			}
+		}
+	}
+
+	sort.SliceStable(errs, func(a, b int) bool {
+		return errs[a].(ruleError).index < errs[b].(ruleError).index
+	})
+	return errs
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	. "gopkg.in/check.v1"
+)
+
+type PolicyValidateSuite struct{}
+
+var _ = Suite(&PolicyValidateSuite{})
+
+func parseRules(c *C, s string) api.Rules {
+	var rules api.Rules
+	c.Assert(json.Unmarshal([]byte(s), &rules), IsNil)
+	return rules
+}
+
+func (s *PolicyValidateSuite) TestValidateRules(c *C) {
+	rules := parseRules(c, `[{
+		"endpointSelector": {"matchLabels": {"app": "web"}},
+		"ingress": [{"toPorts": [{"ports": [{"port": "80", "protocol": "TCP"}],
+			"rules": {"http": [{"method": "GET"}]}}]}]
+	}, {
+		"endpointSelector": {"matchLabels": {"app": "db"}},
+		"ingress": [{"toPorts": [{"ports": [{"port": "99999", "protocol": "TCP"}]}]}]
+	}, {
+		"endpointSelector": {"matchLabels": {"k8s:app": "web"}},
+		"ingress": [{"toPorts": [{"ports": [{"port": "80", "protocol": "TCP"}],
+			"rules": {"kafka": [{"topic": "foo"}]}}]}]
+	}, {
+		"endpointSelector": {"matchLabels": {"app": "db"}},
+		"egress": [{"toPorts": [{"ports": [{"port": "53", "protocol": "UDP"}]}]}]
+	}]`)
+
+	errs := validateRules(rules)
+	c.Assert(errs, HasLen, 2)
+
+	// The invalid port is reported against the rule containing it
+	c.Assert(errs[0].(ruleError).index, Equals, 1)
+	c.Assert(errs[0], ErrorMatches, "rule 1: .*99999.*")
+
+	// The conflicting L7 parsers are reported against the rule selecting
+	// k8s:app=web, which is also selected by the any:app=web rule.
+	c.Assert(errs[1].(ruleError).index, Equals, 2)
+	c.Assert(errs[1], ErrorMatches, "rule 2: conflicting ingress policy .*Cannot merge conflicting L7 parsers.*")
+
+	c.Assert(validateRules(append(rules[:1], rules[3])), HasLen, 0)
+}
+
+func (s *PolicyValidateSuite) TestSelectedLabels(c *C) {
+	es := api.NewESFromMatchRequirements(map[string]string{
+		"any.app":  "web",
+		"k8s.role": "frontend",
+	}, nil)
+	lbls := selectedLabels(es)
+	c.Assert(fmt.Sprintf("%s", lbls), Equals, "[any:app=web k8s:role=frontend]")
+	c.Assert(es.Matches(lbls), Equals, true)
+}
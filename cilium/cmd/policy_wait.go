@@ -16,7 +16,10 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"strconv"
+	"text/tabwriter"
 	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
@@ -24,23 +27,50 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var waitTime, failWaitTime, maxWaitTime int
+var (
+	waitTime, failWaitTime, maxWaitTime int
+	waitRevision                        int64
+	policyWaitTimeout                   time.Duration
+)
+
 var policyWaitCmd = &cobra.Command{
-	Use:   "wait <revision>",
+	Use:   "wait [<revision>]",
 	Short: "Wait for all endpoints to have updated to a given policy revision",
+	Long: `Wait until all local endpoints are ready and have realized the given policy
+revision. The revision can be passed as argument or with --revision, it
+defaults to the current policy revision of the agent.
+
+If the endpoints do not realize the revision in time, the endpoints which
+have not realized it are listed and the command exits with a non-zero status.`,
+	Example: `  rev=$(cilium policy import rules.json | awk '/Revision/ { print $2 }')
+  cilium policy wait --revision $rev --timeout 2m`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) < 1 || args[0] == "" {
-			Usagef(cmd, "invalid revision")
+		reqRevision := waitRevision
+		switch {
+		case len(args) > 0 && cmd.Flags().Changed("revision"):
+			Usagef(cmd, "revision cannot be given both as argument and with --revision")
+		case len(args) > 0:
+			rev, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				Exitf(ExitUsage, "invalid revision '%s': %s", args[0], err)
+			}
+			reqRevision = rev
+		case !cmd.Flags().Changed("revision"):
+			policy, err := client.PolicyGet(nil)
+			if err != nil {
+				Fatalf("cannot get policy revision: %s", err)
+			}
+			reqRevision = policy.Revision
 		}
 
-		reqRevision, err := strconv.ParseInt(args[0], 10, 64)
-		if err != nil {
-			Fatalf("invalid revision '%s': %s", args[0], err)
+		maxWait := time.Duration(maxWaitTime) * time.Second
+		if cmd.Flags().Changed("timeout") {
+			maxWait = policyWaitTimeout
 		}
 
 		startTime := time.Now()
 		failDeadline := startTime.Add(time.Duration(failWaitTime) * time.Second)
-		maxDeadline := startTime.Add(time.Duration(maxWaitTime) * time.Second)
+		maxDeadline := startTime.Add(maxWait)
 
 		haveWaited := false
 
@@ -51,22 +81,8 @@ var policyWaitCmd = &cobra.Command{
 			}
 
 			needed := len(eps)
-			ready := 0
-			notReady := 0
-
-			for _, ep := range eps {
-				switch {
-				case ep.Status.Policy == nil || ep.Status.Policy.Realized == nil:
-					notReady++
-
-				case ep.Status.Policy.Realized.PolicyRevision >= reqRevision &&
-					ep.Status.State == models.EndpointStateReady:
-					ready++
-
-				case ep.Status.State == models.EndpointStateNotReady:
-					notReady++
-				}
-			}
+			stragglers, notReady := policyWaitStragglers(eps, reqRevision)
+			ready := needed - len(stragglers)
 
 			if ready == needed {
 				if haveWaited {
@@ -75,10 +91,14 @@ var policyWaitCmd = &cobra.Command{
 				return
 			} else if time.Now().After(failDeadline) && notReady > 0 {
 				// Fail earlier if any endpoints have a failed state
-				Fatalf("\n%d endpoints have failed regeneration after %s\n", notReady, time.Since(startTime))
+				fmt.Printf("\n")
+				printStragglers(os.Stderr, stragglers, reqRevision)
+				Fatalf("%d endpoints have failed regeneration after %s\n", notReady, time.Since(startTime))
 			} else if time.Now().After(maxDeadline) {
 				// Fail after timeout
-				Fatalf("\n%d endpoints still not ready after %s (%d failed)\n", needed-ready, time.Since(startTime), notReady)
+				fmt.Printf("\n")
+				printStragglers(os.Stderr, stragglers, reqRevision)
+				Fatalf("%d endpoints still not ready after %s (%d failed)\n", needed-ready, time.Since(startTime), notReady)
 			}
 
 			fmt.Printf("\rWaiting for endpoints to run policy revision %d: %d/%d              ",
@@ -94,4 +114,54 @@ func init() {
 	policyWaitCmd.Flags().IntVar(&waitTime, "sleep-time", 1, "Sleep interval between checks (seconds)")
 	policyWaitCmd.Flags().IntVar(&failWaitTime, "fail-wait-time", 60, "Wait time after which command fails if endpoint regeration fails (seconds)")
 	policyWaitCmd.Flags().IntVar(&maxWaitTime, "max-wait-time", 360, "Wait time after which command fails (seconds)")
+	policyWaitCmd.Flags().Int64Var(&waitRevision, "revision", 0, "Policy revision to wait for, defaults to the current revision")
+	policyWaitCmd.Flags().DurationVar(&policyWaitTimeout, "timeout", 0, "Wait time after which command fails, overrides --max-wait-time")
+}
+
+// endpointPolicyRevision returns the policy revision realized by ep, or 0
+// if it is unknown.
+func endpointPolicyRevision(ep *models.Endpoint) int64 {
+	if ep.Status == nil || ep.Status.Policy == nil || ep.Status.Policy.Realized == nil {
+		return 0
+	}
+	return ep.Status.Policy.Realized.PolicyRevision
+}
+
+// policyWaitStragglers returns the endpoints which are not ready or have not
+// realized the policy revision, as well as the number of those endpoints
+// which failed to regenerate.
+func policyWaitStragglers(eps []*models.Endpoint, revision int64) ([]*models.Endpoint, int) {
+	var (
+		stragglers []*models.Endpoint
+		notReady   int
+	)
+	for _, ep := range eps {
+		var state models.EndpointState
+		if ep.Status != nil {
+			state = ep.Status.State
+		}
+		if state == models.EndpointStateReady && endpointPolicyRevision(ep) >= revision {
+			continue
+		}
+		stragglers = append(stragglers, ep)
+		if state == models.EndpointStateNotReady {
+			notReady++
+		}
+	}
+	return stragglers, notReady
+}
+
+// printStragglers writes the endpoints which have not realized the policy
+// revision as a table to w.
+func printStragglers(w io.Writer, stragglers []*models.Endpoint, revision int64) {
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "ENDPOINT\tSTATE\tREALIZED REVISION\n")
+	for _, ep := range stragglers {
+		var state models.EndpointState
+		if ep.Status != nil {
+			state = ep.Status.State
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%d/%d\n", ep.ID, state, endpointPolicyRevision(ep), revision)
+	}
+	tw.Flush()
 }
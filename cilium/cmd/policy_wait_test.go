@@ -0,0 +1,61 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+func newPolicyWaitEndpoint(id int64, state models.EndpointState, revision int64) *models.Endpoint {
+	return &models.Endpoint{
+		ID: id,
+		Status: &models.EndpointStatus{
+			State: state,
+			Policy: &models.EndpointPolicyStatus{
+				Realized: &models.EndpointPolicy{PolicyRevision: revision},
+			},
+		},
+	}
+}
+
+func (s *PolicySuite) TestPolicyWaitStragglers(c *C) {
+	eps := []*models.Endpoint{
+		newPolicyWaitEndpoint(1, models.EndpointStateReady, 5),
+		newPolicyWaitEndpoint(2, models.EndpointStateReady, 4),
+		newPolicyWaitEndpoint(3, models.EndpointStateRegenerating, 5),
+		newPolicyWaitEndpoint(4, models.EndpointStateNotReady, 3),
+		{ID: 5, Status: &models.EndpointStatus{State: models.EndpointStateReady}},
+	}
+
+	stragglers, notReady := policyWaitStragglers(eps, 5)
+	c.Assert(notReady, Equals, 1)
+	ids := []int64{}
+	for _, ep := range stragglers {
+		ids = append(ids, ep.ID)
+	}
+	c.Assert(ids, DeepEquals, []int64{2, 3, 4, 5})
+
+	stragglers, notReady = policyWaitStragglers(eps[:1], 5)
+	c.Assert(stragglers, HasLen, 0)
+	c.Assert(notReady, Equals, 0)
+
+	var buf bytes.Buffer
+	printStragglers(&buf, eps[1:2], 5)
+	c.Assert(buf.String(), Matches, `ENDPOINT +STATE +REALIZED REVISION\n2 +ready +4/5\n`)
+}
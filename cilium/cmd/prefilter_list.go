@@ -47,7 +47,7 @@ func listFilters(cmd *cobra.Command, args []string) {
 
 	if command.OutputJSON() {
 		if err := command.PrintOutput(spec); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to print output: %s", err)
 		}
 		return
 	}
@@ -0,0 +1,312 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/versioncheck"
+
+	go_version "github.com/hashicorp/go-version"
+	"github.com/spf13/cobra"
+)
+
+const (
+	checkOK      = "OK"
+	checkWarning = "WARNING"
+	checkFailed  = "FAILED"
+	checkFixed   = "FIXED"
+)
+
+var (
+	checkTarget   string
+	checkStateDir string
+	checkFix      bool
+	checkLayout   string
+)
+
+// preflightCheckCmd represents the preflight_check command
+var preflightCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Validate that the node can be upgraded or downgraded to an agent version",
+	Long: `Validate that the node can run the given version of the agent by checking
+the kernel version, the layout of the pinned BPF maps, the persisted endpoint
+state and the policy rules imported into the running agent.
+
+Persisted endpoint state which cannot be restored by the target version is
+transformed with --fix, in which case the agent must not be running. Policy
+rules using constructs unsupported by the target version must be removed
+manually. The command exits with a non-zero status if any check failed.
+
+The pinned BPF maps are compared against the map layout of the target version
+given with --map-layout, as printed by 'cilium preflight map-layout' of that
+version. The check is skipped without it.`,
+	Example: `  cilium preflight check --to 1.1 --map-layout layout-1.1.json
+  cilium preflight check --to 1.1 --fix`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if checkTarget == "" {
+			Usagef(cmd, "Missing target version, use --to")
+		}
+		target, err := go_version.NewVersion(checkTarget)
+		if err != nil {
+			Usagef(cmd, "Invalid target version %q: %s", checkTarget, err)
+		}
+		if checkFix {
+			common.RequireRootPrivilege("preflight check --fix")
+			if _, err := os.Stat(defaults.PidFilePath); !os.IsNotExist(err) {
+				Fatalf("Agent should not be running when fixing the endpoint state\n"+
+					"Found pidfile %s\n", defaults.PidFilePath)
+			}
+		}
+
+		results := []checkResult{
+			checkKernel(),
+			checkBPFMaps(checkLayout),
+			checkEndpointState(checkStateDir, checkTarget, checkFix),
+		}
+		if checkFix {
+			results = append(results, checkResult{"policy", checkWarning,
+				"Skipped, the agent is not running"})
+		} else {
+			results = append(results, checkPolicy(target))
+		}
+
+		if !printCheckResults(os.Stdout, results) {
+			Exitf(ExitFailure, "Preflight checks failed")
+		}
+	},
+}
+
+func init() {
+	preflightCmd.AddCommand(preflightCheckCmd)
+	preflightCheckCmd.Flags().StringVar(&checkTarget, "to", "", "Agent version to validate the node against")
+	preflightCheckCmd.Flags().StringVar(&checkStateDir, "state-dir",
+		filepath.Join(defaults.RuntimePath, defaults.StateDir), "Directory of the persisted endpoint state")
+	preflightCheckCmd.Flags().BoolVar(&checkFix, "fix", false, "Transform the persisted endpoint state for the target version")
+	preflightCheckCmd.Flags().StringVar(&checkLayout, "map-layout", "", "File with the BPF map layout of the target version")
+}
+
+// checkResult is the outcome of a single preflight check
+type checkResult struct {
+	name    string
+	status  string
+	message string
+}
+
+// printCheckResults prints results as a table to w and returns false if any
+// of the checks failed.
+func printCheckResults(w io.Writer, results []checkResult) bool {
+	tab := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintln(tab, "CHECK\tSTATUS\tMESSAGE")
+
+	passed := true
+	for _, r := range results {
+		if r.status == checkFailed {
+			passed = false
+		}
+		fmt.Fprintf(tab, "%s\t%s\t%s\n", r.name, r.status, r.message)
+	}
+	tab.Flush()
+
+	return passed
+}
+
+// checkKernel verifies that the running kernel satisfies the minimal kernel
+// version required by the agent.
+func checkKernel() checkResult {
+	verOut, err := exec.Command("uname", "-r").CombinedOutput()
+	if err != nil {
+		return checkResult{"kernel", checkFailed, fmt.Sprintf("Unable to get kernel version: %s", err)}
+	}
+	kernelVersion, err := versioncheck.ParseKernelVersion(string(verOut))
+	if err != nil {
+		return checkResult{"kernel", checkFailed, err.Error()}
+	}
+	if !versioncheck.MinKernelVersion.Check(kernelVersion) {
+		return checkResult{"kernel", checkFailed, fmt.Sprintf("Kernel %s does not satisfy %s",
+			kernelVersion, versioncheck.MinKernelVersion)}
+	}
+	return checkResult{"kernel", checkOK, kernelVersion.String()}
+}
+
+// mapInfoDiff returns the properties of the pinned map which differ from the
+// desired map properties.
+func mapInfoDiff(pinned, desired *bpf.MapInfo) []string {
+	var diff []string
+	if pinned.MapType != desired.MapType {
+		diff = append(diff, fmt.Sprintf("type %s != %s", pinned.MapType, desired.MapType))
+	}
+	if pinned.KeySize != desired.KeySize {
+		diff = append(diff, fmt.Sprintf("key size %d != %d", pinned.KeySize, desired.KeySize))
+	}
+	if pinned.ValueSize != desired.ValueSize {
+		diff = append(diff, fmt.Sprintf("value size %d != %d", pinned.ValueSize, desired.ValueSize))
+	}
+	if pinned.MaxEntries != desired.MaxEntries {
+		diff = append(diff, fmt.Sprintf("max entries %d != %d", pinned.MaxEntries, desired.MaxEntries))
+	}
+	return diff
+}
+
+// checkBPFMaps compares the pinned BPF maps shared between all endpoints
+// against the map layout of the target version read from layoutPath. Maps
+// which do not match are recreated by the agent, losing their content.
+func checkBPFMaps(layoutPath string) checkResult {
+	if layoutPath == "" {
+		return checkResult{"bpf-maps", checkWarning, "Skipped, no map layout of the target version given"}
+	}
+	layout, err := readMapLayout(layoutPath)
+	if err != nil {
+		return checkResult{"bpf-maps", checkFailed, fmt.Sprintf("Unable to read map layout: %s", err)}
+	}
+
+	names := make([]string, 0, len(layout))
+	for name := range layout {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mismatches []string
+	for _, name := range names {
+		path := bpf.MapPath(name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		pinned, err := bpf.OpenMap(path)
+		if err != nil {
+			return checkResult{"bpf-maps", checkFailed, fmt.Sprintf("Unable to open %s: %s", path, err)}
+		}
+		desired := layout[name]
+		diff := mapInfoDiff(&pinned.MapInfo, &desired)
+		pinned.Close()
+		if len(diff) > 0 {
+			mismatches = append(mismatches, fmt.Sprintf("%s (%s)", name, strings.Join(diff, ", ")))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return checkResult{"bpf-maps", checkWarning, "Will be recreated, losing their content: " +
+			strings.Join(mismatches, "; ")}
+	}
+	return checkResult{"bpf-maps", checkOK, ""}
+}
+
+// endpointHeaderfiles returns the C header files of the endpoints persisted
+// in stateDir.
+func endpointHeaderfiles(stateDir string) ([]string, error) {
+	dirFiles, err := ioutil.ReadDir(stateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var headers []string
+	for _, epDirName := range endpoint.FilterEPDir(dirFiles) {
+		epDir := filepath.Join(stateDir, epDirName)
+		epFiles, err := ioutil.ReadDir(epDir)
+		if err != nil {
+			return nil, err
+		}
+		if cHeaderFile := common.FindEPConfigCHeader(epDir, epFiles); cHeaderFile != "" {
+			headers = append(headers, cHeaderFile)
+		}
+	}
+	return headers, nil
+}
+
+// checkEndpointState verifies that the endpoints persisted in stateDir can be
+// restored by the target version and transforms them if fix is true.
+func checkEndpointState(stateDir, target string, fix bool) checkResult {
+	headers, err := endpointHeaderfiles(stateDir)
+	if os.IsNotExist(err) {
+		return checkResult{"endpoint-state", checkOK, "No persisted endpoints"}
+	} else if err != nil {
+		return checkResult{"endpoint-state", checkFailed, err.Error()}
+	}
+
+	var pending []string
+	for _, path := range headers {
+		needed, err := endpoint.HeaderfileNeedsDowngrade(path, target)
+		if err != nil {
+			return checkResult{"endpoint-state", checkFailed, fmt.Sprintf("%s: %s", path, err)}
+		}
+		if needed {
+			pending = append(pending, path)
+		}
+	}
+
+	switch {
+	case len(pending) == 0:
+		return checkResult{"endpoint-state", checkOK, fmt.Sprintf("%d endpoints", len(headers))}
+	case !fix:
+		return checkResult{"endpoint-state", checkFailed, fmt.Sprintf(
+			"%d of %d endpoints must be transformed, use --fix", len(pending), len(headers))}
+	}
+
+	for _, path := range pending {
+		if err := endpoint.DowngradeHeaderfile(path, target); err != nil {
+			return checkResult{"endpoint-state", checkFailed, fmt.Sprintf("%s: %s", path, err)}
+		}
+	}
+	return checkResult{"endpoint-state", checkFixed, fmt.Sprintf(
+		"Transformed %d of %d endpoints", len(pending), len(headers))}
+}
+
+// unsupportedRules returns a description of each rule using policy
+// constructs which are not supported by the target version.
+func unsupportedRules(rules api.Rules, target *go_version.Version) []string {
+	var unsupported []string
+	for _, r := range rules {
+		if features := r.UnsupportedFeatures(target); len(features) > 0 {
+			unsupported = append(unsupported, fmt.Sprintf("%s (%s)",
+				r.Labels, strings.Join(features, ", ")))
+		}
+	}
+	return unsupported
+}
+
+// checkPolicy verifies that the policy rules imported into the running agent
+// only use constructs supported by the target version.
+func checkPolicy(target *go_version.Version) checkResult {
+	resp, err := client.PolicyGet(nil)
+	if err != nil {
+		return checkResult{"policy", checkWarning, fmt.Sprintf("Skipped, unable to get policy: %s", err)}
+	}
+
+	var rules api.Rules
+	if err := json.Unmarshal([]byte(resp.Policy), &rules); err != nil {
+		return checkResult{"policy", checkFailed, fmt.Sprintf("Unable to parse policy: %s", err)}
+	}
+
+	if unsupported := unsupportedRules(rules, target); len(unsupported) > 0 {
+		return checkResult{"policy", checkFailed, "Rules unsupported by the target version: " +
+			strings.Join(unsupported, "; ")}
+	}
+	return checkResult{"policy", checkOK, fmt.Sprintf("%d rules, revision %d", len(rules), resp.Revision)}
+}
@@ -0,0 +1,152 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	go_version "github.com/hashicorp/go-version"
+	. "gopkg.in/check.v1"
+)
+
+type PreflightCheckSuite struct{}
+
+var _ = Suite(&PreflightCheckSuite{})
+
+func (s *PreflightCheckSuite) TestPrintCheckResults(c *C) {
+	var buf bytes.Buffer
+	passed := printCheckResults(&buf, []checkResult{
+		{"kernel", checkOK, "4.9.0"},
+		{"bpf-maps", checkWarning, "foo"},
+	})
+	c.Assert(passed, Equals, true)
+	c.Assert(buf.String(), Equals, "CHECK      STATUS    MESSAGE\n"+
+		"kernel     OK        4.9.0\n"+
+		"bpf-maps   WARNING   foo\n")
+
+	buf.Reset()
+	passed = printCheckResults(&buf, []checkResult{
+		{"kernel", checkOK, "4.9.0"},
+		{"policy", checkFailed, "bar"},
+	})
+	c.Assert(passed, Equals, false)
+}
+
+func (s *PreflightCheckSuite) TestMapInfoDiff(c *C) {
+	desired := bpf.MapInfo{MapType: bpf.MapTypeHash, KeySize: 4, ValueSize: 8, MaxEntries: 1024}
+	pinned := desired
+	c.Assert(mapInfoDiff(&pinned, &desired), IsNil)
+
+	pinned.ValueSize = 16
+	pinned.MaxEntries = 512
+	c.Assert(mapInfoDiff(&pinned, &desired), DeepEquals, []string{
+		"value size 16 != 8",
+		"max entries 512 != 1024",
+	})
+}
+
+func (s *PreflightCheckSuite) TestCheckEndpointState(c *C) {
+	stateDir := c.MkDir()
+
+	result := checkEndpointState(filepath.Join(stateDir, "missing"), "1.1", false)
+	c.Assert(result.status, Equals, checkOK)
+
+	ep := endpoint.NewEndpointWithState(42, endpoint.StateReady)
+	ep.Options.Opts["foo"] = 1
+	jsonBytes, err := json.Marshal(ep)
+	c.Assert(err, IsNil)
+	header := " * " + common.CiliumCHeaderPrefix + "dmVyc2lvbg==:" +
+		base64.StdEncoding.EncodeToString(jsonBytes) + "\n"
+	c.Assert(os.Mkdir(filepath.Join(stateDir, "42"), 0755), IsNil)
+	path := filepath.Join(stateDir, "42", common.CHeaderFileName)
+	c.Assert(ioutil.WriteFile(path, []byte(header), 0644), IsNil)
+
+	result = checkEndpointState(stateDir, "1.2", false)
+	c.Assert(result.status, Equals, checkOK)
+
+	result = checkEndpointState(stateDir, "1.1", false)
+	c.Assert(result.status, Equals, checkFailed)
+	c.Assert(result.message, Equals, "1 of 1 endpoints must be transformed, use --fix")
+
+	result = checkEndpointState(stateDir, "1.1", true)
+	c.Assert(result.status, Equals, checkFixed)
+
+	result = checkEndpointState(stateDir, "1.1", false)
+	c.Assert(result.status, Equals, checkOK)
+
+	result = checkEndpointState(stateDir, "0.9", false)
+	c.Assert(result.status, Equals, checkFailed)
+}
+
+func (s *PreflightCheckSuite) TestUnsupportedRules(c *C) {
+	collector := api.NewESFromLabels(labels.ParseSelectLabel("app=ids"))
+	rules := api.Rules{
+		{
+			EndpointSelector: api.WildcardEndpointSelector,
+			Labels:           labels.ParseLabelArray("name=web"),
+		},
+		{
+			EndpointSelector: api.WildcardEndpointSelector,
+			Labels:           labels.ParseLabelArray("name=mirror"),
+			Mirror: []api.MirrorRule{{
+				Collector: api.MirrorCollector{Endpoint: &collector},
+			}},
+		},
+	}
+
+	target, err := go_version.NewVersion("1.3")
+	c.Assert(err, IsNil)
+	c.Assert(unsupportedRules(rules, target), IsNil)
+
+	target, err = go_version.NewVersion("1.2")
+	c.Assert(err, IsNil)
+	c.Assert(unsupportedRules(rules, target), DeepEquals, []string{
+		"[unspec:name=mirror] (mirror)",
+	})
+}
+
+func (s *PreflightCheckSuite) TestMapLayout(c *C) {
+	layout := mapLayout{
+		"cilium_lxc": bpf.MapInfo{MapType: bpf.MapTypeHash, KeySize: 8, ValueSize: 64, MaxEntries: 65535},
+	}
+
+	dir := c.MkDir()
+	path := filepath.Join(dir, "layout.json")
+	f, err := os.Create(path)
+	c.Assert(err, IsNil)
+	c.Assert(writeMapLayout(f, layout), IsNil)
+	f.Close()
+
+	read, err := readMapLayout(path)
+	c.Assert(err, IsNil)
+	c.Assert(read, DeepEquals, layout)
+
+	_, err = readMapLayout(filepath.Join(dir, "missing.json"))
+	c.Assert(err, Not(IsNil))
+
+	c.Assert(checkBPFMaps("").status, Equals, checkWarning)
+	c.Assert(checkBPFMaps(filepath.Join(dir, "missing.json")).status, Equals, checkFailed)
+}
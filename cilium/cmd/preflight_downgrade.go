@@ -0,0 +1,83 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/endpoint"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	downgradeTarget   string
+	downgradeStateDir string
+)
+
+// preflightDowngradeCmd represents the preflight_downgrade command
+var preflightDowngradeCmd = &cobra.Command{
+	Use:   "downgrade",
+	Short: "Transform the persisted endpoint state for an older agent version",
+	Long: `Transform the endpoint state persisted by the agent so that it can be
+restored by an older version of the agent. The agent must not be running.`,
+	Example: "cilium preflight downgrade --to 1.1",
+	Run: func(cmd *cobra.Command, args []string) {
+		if downgradeTarget == "" {
+			Usagef(cmd, "Missing target version, use --to")
+		}
+		common.RequireRootPrivilege("preflight downgrade")
+
+		if _, err := os.Stat(defaults.PidFilePath); !os.IsNotExist(err) {
+			Fatalf("Agent should not be running when downgrading the endpoint state\n"+
+				"Found pidfile %s\n", defaults.PidFilePath)
+		}
+
+		n, err := downgradeEndpointState(downgradeStateDir, downgradeTarget)
+		if err != nil {
+			Fatalf("Unable to downgrade endpoint state: %s\n", err)
+		}
+		fmt.Printf("Transformed %d endpoints for version %s\n", n, downgradeTarget)
+	},
+}
+
+func init() {
+	preflightCmd.AddCommand(preflightDowngradeCmd)
+	preflightDowngradeCmd.Flags().StringVar(&downgradeTarget, "to", "", "Agent version to downgrade to")
+	preflightDowngradeCmd.Flags().StringVar(&downgradeStateDir, "state-dir",
+		filepath.Join(defaults.RuntimePath, defaults.StateDir), "Directory of the persisted endpoint state")
+}
+
+// downgradeEndpointState transforms the endpoints persisted in stateDir for
+// the target version and returns the number of transformed endpoints.
+func downgradeEndpointState(stateDir, target string) (int, error) {
+	headers, err := endpointHeaderfiles(stateDir)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, cHeaderFile := range headers {
+		if err := endpoint.DowngradeHeaderfile(cHeaderFile, target); err != nil {
+			return n, fmt.Errorf("%s: %s", cHeaderFile, err)
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/endpoint"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *CMDHelpersSuite) TestDowngradeEndpointState(c *C) {
+	stateDir := c.MkDir()
+
+	ep := endpoint.NewEndpointWithState(42, endpoint.StateReady)
+	jsonBytes, err := json.Marshal(ep)
+	c.Assert(err, IsNil)
+	header := " * " + common.CiliumCHeaderPrefix + "dmVyc2lvbg==:" +
+		base64.StdEncoding.EncodeToString(jsonBytes) + "\n"
+
+	for _, dir := range []string{"42", "43", "templates"} {
+		c.Assert(os.Mkdir(filepath.Join(stateDir, dir), 0755), IsNil)
+	}
+	for _, dir := range []string{"42", "templates"} {
+		path := filepath.Join(stateDir, dir, common.CHeaderFileName)
+		c.Assert(ioutil.WriteFile(path, []byte(header), 0644), IsNil)
+	}
+
+	n, err := downgradeEndpointState(stateDir, "1.1")
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 1)
+
+	_, err = downgradeEndpointState(stateDir, "0.9")
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/maps/ipcache"
+	"github.com/cilium/cilium/pkg/maps/lxcmap"
+	"github.com/cilium/cilium/pkg/maps/metricsmap"
+	"github.com/cilium/cilium/pkg/maps/tunnel"
+
+	"github.com/spf13/cobra"
+)
+
+// mapLayout is the layout of the BPF maps shared between all endpoints,
+// indexed by the name of the map.
+type mapLayout map[string]bpf.MapInfo
+
+// preflightMapLayoutCmd represents the preflight_map-layout command
+var preflightMapLayoutCmd = &cobra.Command{
+	Use:   "map-layout",
+	Short: "Print the layout of the shared BPF maps of this version",
+	Long: `Print the layout of the BPF maps shared between all endpoints expected by this
+version of the agent. The output of the target version is the input of
+'cilium preflight check --map-layout'.`,
+	Example: "cilium preflight map-layout > layout.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		layout, err := currentMapLayout()
+		if err != nil {
+			Fatalf("Unable to get map layout: %s\n", err)
+		}
+		if err := writeMapLayout(os.Stdout, layout); err != nil {
+			Fatalf("Unable to print map layout: %s\n", err)
+		}
+	},
+}
+
+func init() {
+	preflightCmd.AddCommand(preflightMapLayoutCmd)
+}
+
+// currentMapLayout returns the layout of the shared BPF maps expected by this
+// version of the agent.
+func currentMapLayout() (mapLayout, error) {
+	layout := mapLayout{}
+	for _, m := range []*bpf.Map{lxcmap.LXCMap, &ipcache.IPCache.Map, metricsmap.Metrics, tunnel.TunnelMap} {
+		path, err := m.Path()
+		if err != nil {
+			return nil, err
+		}
+		layout[filepath.Base(path)] = m.MapInfo
+	}
+	return layout, nil
+}
+
+// writeMapLayout writes layout to w in JSON.
+func writeMapLayout(w io.Writer, layout mapLayout) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(layout)
+}
+
+// readMapLayout reads a layout written by writeMapLayout from the file at
+// path.
+func readMapLayout(path string) (mapLayout, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	layout := mapLayout{}
+	if err := json.NewDecoder(f).Decode(&layout); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
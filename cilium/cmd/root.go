@@ -16,10 +16,11 @@ package cmd
 
 import (
 	"fmt"
-	"io"
 	"os"
 
+	"github.com/cilium/cilium/pkg/bpf"
 	clientPkg "github.com/cilium/cilium/pkg/client"
+	"github.com/cilium/cilium/pkg/option"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -37,15 +38,28 @@ var (
 var rootCmd = &cobra.Command{
 	Use:   "cilium",
 	Short: "CLI",
-	Long:  `CLI for interacting with the local Cilium Agent`,
+	Long: `CLI for interacting with the local Cilium Agent
+
+Exit codes:
+  0  Success
+  1  Failure not covered by a more specific exit code
+  2  Missing or malformed arguments or flags
+  3  The API of the agent cannot be reached
+  4  The requested object does not exist
+  5  The input was rejected as invalid
+  6  The operation failed for some but not all objects
+
+With --error-format=json, errors are written to stderr as a single line:
+  {"error":{"code":4,"reason":"not-found","message":"..."}}`,
 }
 
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(-1)
+		// Errors returned by cobra are caused by unknown commands,
+		// flags or invalid flag values
+		Exitf(ExitUsage, "%s", err)
 	}
 }
 
@@ -55,7 +69,9 @@ func init() {
 	flags.StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cilium.yaml)")
 	flags.BoolP("debug", "D", false, "Enable debug messages")
 	flags.StringP("host", "H", "", "URI to server-side API")
+	flags.StringVar(&errorFormat, "error-format", errorFormatText, "Format of errors written to stderr: text or json")
 	viper.BindPFlags(flags)
+	rootCmd.SilenceErrors = true
 	rootCmd.AddCommand(newCmdCompletion(os.Stdout))
 }
 
@@ -75,6 +91,21 @@ func initConfig() {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
 	}
 
+	if errorFormat != errorFormatText && errorFormat != errorFormatJSON {
+		format := errorFormat
+		errorFormat = errorFormatText
+		Exitf(ExitUsage, "Unknown error format %q, use %s or %s", format,
+			errorFormatText, errorFormatJSON)
+	}
+
+	// BPF maps of an agent with a pin prefix are looked up in the same
+	// subdirectory of the BPF filesystem.
+	pinPrefix := os.Getenv(option.BPFPinPrefixEnv)
+	if err := option.ValidatePinPrefix(pinPrefix); err != nil {
+		Exitf(ExitUsage, "Invalid %s: %s", option.BPFPinPrefixEnv, err)
+	}
+	bpf.SetPinPrefix(pinPrefix)
+
 	if viper.GetBool("debug") {
 		log.Level = logrus.DebugLevel
 	} else {
@@ -87,68 +118,3 @@ func initConfig() {
 		client = cl
 	}
 }
-
-const copyRightHeader = `
-# Copyright 2017 Authors of Cilium
-#
-# Licensed under the Apache License, Version 2.0 (the "License");
-# you may not use this file except in compliance with the License.
-# You may obtain a copy of the License at
-#
-#     http://www.apache.org/licenses/LICENSE-2.0
-#
-# Unless required by applicable law or agreed to in writing, software
-# distributed under the License is distributed on an "AS IS" BASIS,
-# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-# See the License for the specific language governing permissions and
-# limitations under the License.
-`
-
-var (
-	completionExample = `
-# Installing bash completion on macOS using homebrew
-## If running Bash 3.2 included with macOS
-	brew install bash-completion
-## or, if running Bash 4.1+
-	brew install bash-completion@2
-## afterwards you only need to run
-	cilium completion bash > $(brew --prefix)/etc/bash_completion.d/cilium
-
-
-# Installing bash completion on Linux
-## Load the cilium completion code for bash into the current shell
-	source <(cilium completion bash)
-## Write bash completion code to a file and source if from .bash_profile
-	cilium completion bash > ~/.cilium/completion.bash.inc
-	printf "
-	  # Cilium shell completion
-	  source '$HOME/.cilium/completion.bash.inc'
-	  " >> $HOME/.bash_profile
-	source $HOME/.bash_profile`
-)
-
-func newCmdCompletion(out io.Writer) *cobra.Command {
-	cmd := &cobra.Command{
-		Use:     "completion [bash]",
-		Short:   "Output shell completion code for bash",
-		Long:    ``,
-		Example: completionExample,
-		Run: func(cmd *cobra.Command, args []string) {
-			runCompletion(out, cmd, args)
-		},
-		ValidArgs: []string{"bash"},
-	}
-
-	return cmd
-}
-
-func runCompletion(out io.Writer, cmd *cobra.Command, args []string) error {
-	if len(args) > 1 {
-		return fmt.Errorf("Too many arguments. Expected only the shell type.")
-	}
-	if _, err := out.Write([]byte(copyRightHeader)); err != nil {
-		return err
-	}
-
-	return cmd.Parent().GenBashCompletion(out)
-}
@@ -56,7 +56,7 @@ var serviceGetCmd = &cobra.Command{
 
 		if command.OutputJSON() {
 			if err := command.PrintOutput(svc); err != nil {
-				os.Exit(1)
+				Fatalf("Unable to print output: %s", err)
 			}
 			return
 		}
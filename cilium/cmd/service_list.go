@@ -50,7 +50,7 @@ func listServices(cmd *cobra.Command, args []string) {
 
 	if command.OutputJSON() {
 		if err := command.PrintOutput(list); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to print output: %s", err)
 		}
 		return
 	}
@@ -88,11 +88,12 @@ func printServiceList(w *tabwriter.Writer, list []*models.Service) {
 				fmt.Fprintf(os.Stderr, "error parsing backend %+v", be)
 				continue
 			}
-			var str string
+			str := fmt.Sprintf("%d => %s", i+1, beA.String())
 			if be.Weight != 0 {
-				str = fmt.Sprintf("%d => %s (W: %d)", i+1, beA.String(), be.Weight)
-			} else {
-				str = fmt.Sprintf("%d => %s", i+1, beA.String())
+				str += fmt.Sprintf(" (W: %d)", be.Weight)
+			}
+			if be.State == models.BackendAddressStateDraining {
+				str += " (draining)"
 			}
 			backendAddresses = append(backendAddresses, str)
 		}
@@ -29,16 +29,26 @@ import (
 )
 
 var (
-	addRev   bool
-	idU      uint64
-	frontend string
-	backends []string
+	addRev           bool
+	idU              uint64
+	frontend         string
+	backends         []string
+	drainBackends    []string
+	activateBackends []string
 )
 
 // serviceUpdateCmd represents the service_update command
 var serviceUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update a service",
+	Long: `Create or update a service. Backends are specified as <IP:Port> followed by
+an optional weight and an optional state, separated by '/'. Draining backends do
+not receive new connections while established connections are not affected.
+
+The state of backends of an existing service can be changed with --drain and
+--activate without specifying the list of backends again.`,
+	Example: `  cilium service update --id 1 --frontend 10.0.0.1:80 --backends 10.1.0.1:80/2,10.1.0.2:80/1/draining
+  cilium service update --id 1 --drain 10.1.0.1:80`,
 	Run: func(cmd *cobra.Command, args []string) {
 		updateService(cmd, args)
 	},
@@ -49,7 +59,9 @@ func init() {
 	serviceUpdateCmd.Flags().BoolVarP(&addRev, "rev", "", true, "Add reverse translation")
 	serviceUpdateCmd.Flags().Uint64VarP(&idU, "id", "", 0, "Identifier")
 	serviceUpdateCmd.Flags().StringVarP(&frontend, "frontend", "", "", "Frontend address")
-	serviceUpdateCmd.Flags().StringSliceVarP(&backends, "backends", "", []string{}, "Backend address or addresses followed by optional weight (<IP:Port>[/weight])")
+	serviceUpdateCmd.Flags().StringSliceVarP(&backends, "backends", "", []string{}, "Backend address or addresses followed by optional weight and state (<IP:Port>[/weight][/active|draining])")
+	serviceUpdateCmd.Flags().StringSliceVarP(&drainBackends, "drain", "", []string{}, "Set backend address or addresses of an existing service to draining (<IP:Port>)")
+	serviceUpdateCmd.Flags().StringSliceVarP(&activateBackends, "activate", "", []string{}, "Set backend address or addresses of an existing service to active (<IP:Port>)")
 }
 
 func parseFrontendAddress(address string) (*models.FrontendAddress, net.IP) {
@@ -66,9 +78,76 @@ func parseFrontendAddress(address string) (*models.FrontendAddress, net.IP) {
 	}, frontend.IP
 }
 
+// parseBackend parses a backend specification in the form
+// <IP:Port>[/weight][/active|draining].
+func parseBackend(backend string) (*loadbalancer.LBBackEnd, error) {
+	tmp := strings.Split(backend, "/")
+	if len(tmp) > 3 {
+		return nil, fmt.Errorf("incorrect backend specification %s", backend)
+	}
+
+	weight := uint64(0)
+	draining := false
+	for i, field := range tmp[1:] {
+		switch field {
+		case models.BackendAddressStateActive:
+			draining = false
+		case models.BackendAddressStateDraining:
+			draining = true
+		default:
+			if i != 0 {
+				return nil, fmt.Errorf("invalid backend state %q in %s", field, backend)
+			}
+			var err error
+			weight, err = strconv.ParseUint(field, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("error converting weight %s", err)
+			}
+		}
+	}
+
+	beAddr, err := net.ResolveTCPAddr("tcp", tmp[0])
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse backend address \"%s\": %s", backend, err)
+	}
+
+	be, err := loadbalancer.NewLBBackEnd(loadbalancer.TCP, beAddr.IP, uint16(beAddr.Port), uint16(weight))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a new L3n4Addr for backend %s: %s", backend, err)
+	}
+	be.Draining = draining
+
+	return be, nil
+}
+
+// setBackendState sets the state of the backends in spec matching any of the
+// addresses to state. It returns an error if any address does not match a
+// backend of spec.
+func setBackendState(spec *models.ServiceSpec, addresses []string, state string) error {
+	for _, address := range addresses {
+		beAddr, err := net.ResolveTCPAddr("tcp", address)
+		if err != nil {
+			return fmt.Errorf("cannot parse backend address \"%s\": %s", address, err)
+		}
+
+		found := false
+		for _, ba := range spec.BackendAddresses {
+			if ba.IP == nil || !net.ParseIP(*ba.IP).Equal(beAddr.IP) || ba.Port != uint16(beAddr.Port) {
+				continue
+			}
+			ba.State = state
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("service %d has no backend %s", spec.ID, address)
+		}
+	}
+	return nil
+}
+
 func updateService(cmd *cobra.Command, args []string) {
 	id := int64(idU)
-	fa, faIP := parseFrontendAddress(frontend)
+	changeState := len(drainBackends) > 0 || len(activateBackends) > 0
 
 	var spec *models.ServiceSpec
 	svc, err := client.GetServiceID(id)
@@ -80,6 +159,9 @@ func updateService(cmd *cobra.Command, args []string) {
 		spec = svc.Status.Realized
 		fmt.Printf("Updating existing service with id '%v'\n", id)
 
+	case changeState:
+		Fatalf("Cannot change state of backends of service %d: %s", id, err)
+
 	default:
 		spec = &models.ServiceSpec{ID: id}
 		fmt.Printf("Creating new service with id '%v'\n", id)
@@ -91,10 +173,15 @@ func updateService(cmd *cobra.Command, args []string) {
 		spec.Flags = &models.ServiceSpecFlags{}
 	}
 
+	if frontend == "" && spec.FrontendAddress != nil {
+		frontend = net.JoinHostPort(spec.FrontendAddress.IP, strconv.Itoa(int(spec.FrontendAddress.Port)))
+	}
+	fa, faIP := parseFrontendAddress(frontend)
+
 	spec.FrontendAddress = fa
 	spec.Flags.DirectServerReturn = addRev
 
-	if len(backends) == 0 {
+	if len(backends) == 0 && !changeState {
 		fmt.Printf("Reading backend list from stdin...\n")
 
 		scanner := bufio.NewScanner(os.Stdin)
@@ -103,43 +190,34 @@ func updateService(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	spec.BackendAddresses = nil
+	if len(backends) != 0 || !changeState {
+		spec.BackendAddresses = nil
+	}
 	for _, backend := range backends {
-		tmp := strings.Split(backend, "/")
-		if len(tmp) > 2 {
-			Fatalf("Incorrect backend specification %s\n", backend)
-		}
-		addr := tmp[0]
-		weight := uint64(0)
-		if len(tmp) == 2 {
-			var err error
-			weight, err = strconv.ParseUint(tmp[1], 10, 16)
-			if err != nil {
-				Fatalf("Error converting weight %s\n", err)
-			}
-		}
-		beAddr, err := net.ResolveTCPAddr("tcp", addr)
-		if err != nil {
-			Fatalf("Cannot parse backend address \"%s\": %s", backend, err)
-		}
-
-		be, err := loadbalancer.NewLBBackEnd(loadbalancer.TCP, beAddr.IP, uint16(beAddr.Port), uint16(weight))
+		be, err := parseBackend(backend)
 		if err != nil {
-			Fatalf("Unable to create a new L3n4Addr for backend %s: %s", backend, err)
+			Fatalf("%s", err)
 		}
 
 		if be.IsIPv6() && faIP.To4() != nil {
 			Fatalf("Address mismatch between frontend and backend %s", backend)
 		}
 
-		if fa.Port == 0 && beAddr.Port != 0 {
-			Fatalf("L4 backend found (%v) with L3 frontend", beAddr)
+		if fa.Port == 0 && be.Port != 0 {
+			Fatalf("L4 backend found (%v) with L3 frontend", be.L3n4Addr.String())
 		}
 
 		ba := be.GetBackendModel()
 		spec.BackendAddresses = append(spec.BackendAddresses, ba)
 	}
 
+	if err := setBackendState(spec, drainBackends, models.BackendAddressStateDraining); err != nil {
+		Fatalf("%s", err)
+	}
+	if err := setBackendState(spec, activateBackends, models.BackendAddressStateActive); err != nil {
+		Fatalf("%s", err)
+	}
+
 	if created, err := client.PutServiceID(id, spec); err != nil {
 		Fatalf("Cannot add/update service: %s", err)
 	} else if created {
@@ -0,0 +1,79 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+type ServiceUpdateSuite struct{}
+
+var _ = Suite(&ServiceUpdateSuite{})
+
+func (s *ServiceUpdateSuite) TestParseBackend(c *C) {
+	tests := []struct {
+		spec     string
+		invalid  bool
+		addr     string
+		weight   uint16
+		draining bool
+	}{
+		{spec: "10.0.0.1:80", addr: "10.0.0.1:80"},
+		{spec: "10.0.0.1:80/3", addr: "10.0.0.1:80", weight: 3},
+		{spec: "10.0.0.1:80/draining", addr: "10.0.0.1:80", draining: true},
+		{spec: "10.0.0.1:80/3/draining", addr: "10.0.0.1:80", weight: 3, draining: true},
+		{spec: "[f00d::1]:80/active", addr: "[f00d::1]:80"},
+		{spec: "10.0.0.1:80/draining/3", invalid: true},
+		{spec: "10.0.0.1:80/3/foo", invalid: true},
+		{spec: "10.0.0.1:80/3/draining/1", invalid: true},
+		{spec: "10.0.0.1:80/70000", invalid: true},
+		{spec: "foo/3", invalid: true},
+	}
+
+	for _, tt := range tests {
+		be, err := parseBackend(tt.spec)
+		if tt.invalid {
+			c.Assert(err, NotNil, Commentf("spec %s", tt.spec))
+			continue
+		}
+		c.Assert(err, IsNil, Commentf("spec %s", tt.spec))
+		c.Assert(be.L3n4Addr.String(), Equals, tt.addr)
+		c.Assert(be.Weight, Equals, tt.weight)
+		c.Assert(be.Draining, Equals, tt.draining)
+	}
+}
+
+func (s *ServiceUpdateSuite) TestSetBackendState(c *C) {
+	ip1, ip2 := "10.0.0.1", "10.0.0.2"
+	spec := &models.ServiceSpec{
+		ID: 1,
+		BackendAddresses: []*models.BackendAddress{
+			{IP: &ip1, Port: 80, State: models.BackendAddressStateActive},
+			{IP: &ip2, Port: 80},
+		},
+	}
+
+	c.Assert(setBackendState(spec, []string{"10.0.0.2:80"}, models.BackendAddressStateDraining), IsNil)
+	c.Assert(spec.BackendAddresses[0].State, Equals, models.BackendAddressStateActive)
+	c.Assert(spec.BackendAddresses[1].State, Equals, models.BackendAddressStateDraining)
+
+	c.Assert(setBackendState(spec, []string{"10.0.0.2:80"}, models.BackendAddressStateActive), IsNil)
+	c.Assert(spec.BackendAddresses[1].State, Equals, models.BackendAddressStateActive)
+
+	c.Assert(setBackendState(spec, []string{"10.0.0.2:8080"}, models.BackendAddressStateDraining), NotNil)
+	c.Assert(setBackendState(spec, []string{"10.0.0.3:80"}, models.BackendAddressStateDraining), NotNil)
+}
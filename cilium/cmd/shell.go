@@ -0,0 +1,313 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const shellPrompt = "cilium> "
+
+// shellCmd represents the shell command
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run cilium commands in an interactive shell",
+	Long: `Run cilium commands in an interactive shell with command history and
+tab completion of commands, flags, endpoint IDs, identity IDs and BPF map
+names. Each line is run as a cilium command against the same agent, the
+"cilium" prefix is optional. Type "exit" or press Ctrl-D to leave the shell.
+
+If standard input is not a terminal, commands are read from it line by line,
+empty lines and lines starting with '#' are ignored.`,
+	Example: `cilium shell
+cilium> endpoint list
+cilium> bpf policy get <TAB>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := newShell(rootCmd)
+		if err != nil {
+			Fatalf("%s", err)
+		}
+
+		// Interrupts are meant for the command being run, not the shell
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		defer signal.Stop(interrupts)
+
+		fd := int(os.Stdin.Fd())
+		if terminal.IsTerminal(fd) {
+			err = s.runInteractive(fd)
+		} else {
+			err = s.runScript(os.Stdin)
+		}
+		if err != nil {
+			Fatalf("%s", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+}
+
+// shell runs each line entered by the user as a cilium command
+type shell struct {
+	root *cobra.Command
+
+	// binary is the path of the cilium binary used to run commands
+	binary string
+
+	// globalArgs are the global flags the shell was invoked with and which
+	// are passed on to every command
+	globalArgs []string
+
+	// stdin is passed to the commands run by the shell
+	stdin io.Reader
+
+	term *terminal.Terminal
+}
+
+func newShell(root *cobra.Command) (*shell, error) {
+	binary, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine path of cilium binary: %s", err)
+	}
+
+	var globalArgs []string
+	if cfgFile != "" {
+		globalArgs = append(globalArgs, "--config", cfgFile)
+	}
+	if host := viper.GetString("host"); host != "" {
+		globalArgs = append(globalArgs, "--host", host)
+	}
+	if viper.GetBool("debug") {
+		globalArgs = append(globalArgs, "--debug")
+	}
+	if errorFormat != errorFormatText {
+		globalArgs = append(globalArgs, "--error-format", errorFormat)
+	}
+
+	return &shell{
+		root:       root,
+		binary:     binary,
+		globalArgs: globalArgs,
+	}, nil
+}
+
+// runInteractive reads commands from the terminal fd until the user exits
+// the shell.
+func (s *shell) runInteractive(fd int) error {
+	oldState, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer terminal.Restore(fd, oldState)
+
+	s.stdin = os.Stdin
+	s.term = terminal.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, shellPrompt)
+	s.term.AutoCompleteCallback = s.autoComplete
+
+	for {
+		if width, height, err := terminal.GetSize(fd); err == nil {
+			s.term.SetSize(width, height)
+		}
+
+		line, err := s.term.ReadLine()
+		if err == io.EOF {
+			fmt.Fprintln(s.term)
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// Commands expect the terminal in its original mode
+		terminal.Restore(fd, oldState)
+		exit := s.run(line)
+		if exit {
+			return nil
+		}
+		if _, err := terminal.MakeRaw(fd); err != nil {
+			return err
+		}
+	}
+}
+
+// runScript runs the commands read from r line by line.
+func (s *shell) runScript(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if exit := s.run(line); exit {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// run runs the cilium command in line and returns true if the shell should
+// exit.
+func (s *shell) run(line string) bool {
+	args, err := shellwords.Parse(line)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return false
+	}
+	if len(args) > 0 && args[0] == s.root.Name() {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return false
+	}
+
+	switch args[0] {
+	case "exit", "quit":
+		return true
+	case "shell":
+		fmt.Fprintf(os.Stderr, "Error: already running in a shell\n")
+		return false
+	}
+
+	cmd := exec.Command(s.binary, append(s.globalArgs, args...)...)
+	cmd.Stdin = s.stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		// The command reports its own errors, only report failures to
+		// run it.
+		if _, ok := err.(*exec.ExitError); !ok {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		}
+	}
+	return false
+}
+
+// autoComplete completes the word before the cursor when the user presses
+// tab. If the word cannot be completed unambiguously, all candidates are
+// printed above the prompt.
+func (s *shell) autoComplete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	start, candidates := shellCompletions(s.root, line[:pos], completionCandidates)
+	if len(candidates) == 0 {
+		return "", 0, false
+	}
+
+	completion := candidates[0] + " "
+	if len(candidates) > 1 {
+		completion = commonPrefix(candidates)
+		if len(completion) == pos-start {
+			fmt.Fprintf(s.term, "%s\n", strings.Join(candidates, "  "))
+			return "", 0, false
+		}
+	}
+
+	return line[:start] + completion + line[pos:], start + len(completion), true
+}
+
+// shellCompletions returns the candidates for the last, possibly empty, word
+// of line and the offset in line at which that word starts. Dynamic
+// candidates of commands annotated with setArgCompletion are retrieved via
+// dynamic.
+func shellCompletions(root *cobra.Command, line string, dynamic func(kind string) ([]string, error)) (int, []string) {
+	start := strings.LastIndexAny(line, " \t") + 1
+	word := line[start:]
+
+	cmd := root
+	for i, w := range strings.Fields(line[:start]) {
+		if i == 0 && w == root.Name() {
+			continue
+		}
+		if strings.HasPrefix(w, "-") {
+			continue
+		}
+		for _, c := range cmd.Commands() {
+			if c.IsAvailableCommand() && (c.Name() == w || c.HasAlias(w)) {
+				cmd = c
+				break
+			}
+		}
+	}
+
+	var candidates []string
+	if strings.HasPrefix(word, "-") {
+		addFlag := func(flag *pflag.Flag) {
+			if !flag.Hidden {
+				candidates = append(candidates, "--"+flag.Name)
+			}
+		}
+		cmd.Flags().VisitAll(addFlag)
+		cmd.InheritedFlags().VisitAll(addFlag)
+	} else {
+		for _, c := range cmd.Commands() {
+			if c.IsAvailableCommand() {
+				candidates = append(candidates, c.Name())
+			}
+		}
+		candidates = append(candidates, cmd.ValidArgs...)
+		if kind := cmd.Annotations[completionAnnotation]; kind != "" {
+			// The agent may be unreachable, complete what is known
+			if objects, err := dynamic(kind); err == nil {
+				candidates = append(candidates, objects...)
+			}
+		}
+	}
+
+	matches := make([]string, 0, len(candidates))
+	seen := map[string]struct{}{}
+	for _, c := range candidates {
+		if _, ok := seen[c]; ok || !strings.HasPrefix(c, word) {
+			continue
+		}
+		seen[c] = struct{}{}
+		matches = append(matches, c)
+	}
+	sort.Strings(matches)
+
+	return start, matches
+}
+
+// commonPrefix returns the longest common prefix of all strings in s.
+func commonPrefix(s []string) string {
+	if len(s) == 0 {
+		return ""
+	}
+	prefix := s[0]
+	for _, str := range s[1:] {
+		for !strings.HasPrefix(str, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	. "gopkg.in/check.v1"
+)
+
+type ShellSuite struct{}
+
+var _ = Suite(&ShellSuite{})
+
+func (s *ShellSuite) TestShellCompletions(c *C) {
+	root := newCompletionTestTree()
+	dynamic := func(kind string) ([]string, error) {
+		if kind != completeEndpoints {
+			return nil, fmt.Errorf("unexpected kind %s", kind)
+		}
+		return []string{"1234", "1301", "29"}, nil
+	}
+
+	for _, t := range []struct {
+		line       string
+		start      int
+		candidates []string
+	}{
+		{"", 0, []string{"endpoint", "monitor"}},
+		{"end", 0, []string{"endpoint"}},
+		{"cilium m", 7, []string{"monitor"}},
+		{"endpoint ", 9, []string{"get"}},
+		{"endpoint get 1", 13, []string{"1234", "1301"}},
+		{"endpoint get -o json ", 21, []string{"1234", "1301", "29"}},
+		{"endpoint get --", 13, []string{"--debug", "--output"}},
+		{"monitor --f", 8, []string{"--from-identity"}},
+		{"foo", 0, []string{}},
+	} {
+		start, candidates := shellCompletions(root, t.line, dynamic)
+		c.Assert(start, Equals, t.start, Commentf("line %q", t.line))
+		c.Assert(candidates, DeepEquals, t.candidates, Commentf("line %q", t.line))
+	}
+}
+
+func (s *ShellSuite) TestCommonPrefix(c *C) {
+	c.Assert(commonPrefix(nil), Equals, "")
+	c.Assert(commonPrefix([]string{"1234"}), Equals, "1234")
+	c.Assert(commonPrefix([]string{"1234", "1301"}), Equals, "1")
+	c.Assert(commonPrefix([]string{"endpoint", "monitor"}), Equals, "")
+}
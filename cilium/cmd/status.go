@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	pkg "github.com/cilium/cilium/pkg/client"
@@ -42,6 +43,8 @@ var (
 	allNodes       bool
 	allRedirects   bool
 	brief          bool
+	waitReady      bool
+	waitTimeout    time.Duration
 	healthLines    = 10
 )
 
@@ -54,6 +57,8 @@ func init() {
 	statusCmd.Flags().BoolVar(&allRedirects, "all-redirects", false, "Show all redirects")
 	statusCmd.Flags().BoolVar(&brief, "brief", false, "Only print a one-line status message")
 	statusCmd.Flags().BoolVar(&verbose, "verbose", false, "Equivalent to --all-addresses --all-controllers --all-nodes --all-health")
+	statusCmd.Flags().BoolVar(&waitReady, "wait-until-ready", false, "Wait until the daemon is reachable and no subsystem probe reports a failure")
+	statusCmd.Flags().DurationVar(&waitTimeout, "wait-timeout", 5*time.Minute, "Maximum time to wait with --wait-until-ready")
 	command.AddJSONOutput(statusCmd)
 }
 
@@ -68,16 +73,18 @@ func statusDaemon() {
 	if allHealth {
 		healthLines = 0
 	}
+	if waitReady {
+		waitUntilReady()
+	}
 	if resp, err := client.Daemon.GetHealthz(nil); err != nil {
-		if brief {
+		if brief && errorFormat == errorFormatText {
 			fmt.Fprintf(os.Stderr, "%s\n", "cilium: daemon unreachable")
-		} else {
-			fmt.Fprintf(os.Stderr, "%s\n", pkg.Hint(err))
+			os.Exit(exitCodeForError(err))
 		}
-		os.Exit(1)
+		Fatalf("%s", pkg.Hint(err))
 	} else if command.OutputJSON() {
 		if err := command.PrintOutput(resp.Payload); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to print output: %s", err)
 		}
 	} else if brief {
 		pkg.FormatStatusResponseBrief(os.Stdout, resp.Payload)
@@ -90,7 +97,7 @@ func statusDaemon() {
 		if sr.Cilium != nil {
 			state := sr.Cilium.State
 			if state != models.StatusStateOk && state != models.StatusStateDisabled {
-				os.Exit(1)
+				Exitf(ExitFailure, "Cilium is in %s state", state)
 			}
 		}
 
@@ -98,3 +105,40 @@ func statusDaemon() {
 		w.Flush()
 	}
 }
+
+// statusReady returns true if the daemon reports itself as Ok and none of its
+// subsystem probes reports a failure.
+func statusReady(sr *models.StatusResponse) bool {
+	if sr == nil || sr.Cilium == nil || sr.Cilium.State != models.StatusStateOk {
+		return false
+	}
+	for _, probe := range sr.Probes {
+		if probe.State == models.StatusProbeStateFailure {
+			return false
+		}
+	}
+	return true
+}
+
+// waitUntilReady polls the daemon status until statusReady is true. It exits
+// the program if this does not happen within waitTimeout.
+func waitUntilReady() {
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		resp, err := client.Daemon.GetHealthz(nil)
+		if err == nil && statusReady(resp.Payload) {
+			return
+		}
+		if time.Now().After(deadline) {
+			if brief && errorFormat == errorFormatText {
+				fmt.Fprintf(os.Stderr, "%s\n", "cilium: timeout waiting for daemon to become ready")
+				os.Exit(ExitFailure)
+			}
+			if err != nil {
+				Fatalf("Timeout waiting for daemon to become ready: %s", pkg.Hint(err))
+			}
+			Exitf(ExitFailure, "Timeout waiting for daemon to become ready")
+		}
+		time.Sleep(time.Second)
+	}
+}
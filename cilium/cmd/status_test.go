@@ -0,0 +1,47 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+type StatusSuite struct{}
+
+var _ = Suite(&StatusSuite{})
+
+func (s *StatusSuite) TestStatusReady(c *C) {
+	c.Assert(statusReady(nil), Equals, false)
+	c.Assert(statusReady(&models.StatusResponse{}), Equals, false)
+
+	sr := &models.StatusResponse{
+		Cilium: &models.Status{State: models.StatusStateWarning},
+	}
+	c.Assert(statusReady(sr), Equals, false)
+
+	sr.Cilium.State = models.StatusStateOk
+	c.Assert(statusReady(sr), Equals, true)
+
+	sr.Probes = []*models.StatusProbe{
+		{Name: "controllers", State: models.StatusProbeStateWarning},
+		{Name: "proxy", State: models.StatusProbeStateDisabled},
+	}
+	c.Assert(statusReady(sr), Equals, true)
+
+	sr.Probes = append(sr.Probes, &models.StatusProbe{Name: "bpf-maps", State: models.StatusProbeStateFailure})
+	c.Assert(statusReady(sr), Equals, false)
+}
@@ -0,0 +1,302 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	topSortForwarded = "forwarded"
+	topSortDropped   = "dropped"
+	topSortBytes     = "bytes"
+
+	// clearScreen moves the cursor to the top left corner and clears the
+	// terminal.
+	clearScreen = "\033[H\033[2J"
+)
+
+var (
+	topInterval   time.Duration
+	topSortBy     string
+	topMaxRows    int
+	topIterations int
+)
+
+// topCmd represents the top command
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Display a live view of the busiest endpoints",
+	Long: `Display a continuously refreshing table of endpoints sorted by the rate of
+packets forwarded or dropped by the datapath.
+
+The rates are computed from the trace and drop notifications emitted by the
+BPF programs and are therefore subject to the monitor aggregation level of
+the agent and of each endpoint. With aggregation enabled, forwarded packets
+of established connections are not reported and the rates are lower than
+the actual traffic.`,
+	Example: "  cilium top --sort dropped --interval 5s",
+	Run: func(cmd *cobra.Command, args []string) {
+		switch topSortBy {
+		case topSortForwarded, topSortDropped, topSortBytes:
+		default:
+			Usagef(cmd, "Invalid sort order %q, must be one of %s, %s or %s",
+				topSortBy, topSortForwarded, topSortDropped, topSortBytes)
+		}
+		if topInterval <= 0 {
+			Usagef(cmd, "Interval must be positive")
+		}
+		runTop()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+	topCmd.Flags().DurationVarP(&topInterval, "interval", "i", 2*time.Second, "Refresh interval")
+	topCmd.Flags().StringVarP(&topSortBy, "sort", "s", topSortForwarded,
+		fmt.Sprintf("Sort endpoints by rate of %s packets, %s packets or %s", topSortForwarded, topSortDropped, topSortBytes))
+	topCmd.Flags().IntVarP(&topMaxRows, "max", "n", 20, "Maximum number of endpoints to display, 0 for all")
+	topCmd.Flags().IntVar(&topIterations, "iterations", 0, "Exit after the given number of refreshes, 0 to run until interrupted")
+}
+
+// topCounters are the packet and byte counters of a single endpoint.
+type topCounters struct {
+	forwarded      uint64
+	forwardedBytes uint64
+	dropped        uint64
+	droppedBytes   uint64
+}
+
+// topStats aggregates datapath notifications by source endpoint.
+type topStats struct {
+	mutex     lock.Mutex
+	endpoints map[uint16]*topCounters
+	lost      uint64
+}
+
+func newTopStats() *topStats {
+	return &topStats{endpoints: map[uint16]*topCounters{}}
+}
+
+func (s *topStats) counters(id uint16) *topCounters {
+	c, ok := s.endpoints[id]
+	if !ok {
+		c = &topCounters{}
+		s.endpoints[id] = c
+	}
+	return c
+}
+
+// addPayload accounts a monitor payload. Notifications other than trace and
+// drop notifications are ignored.
+func (s *topStats) addPayload(pl *payload.Payload) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch pl.Type {
+	case payload.RecordLost:
+		s.lost += pl.Lost
+		return
+	case payload.EventSample:
+	default:
+		return
+	}
+
+	if len(pl.Data) == 0 {
+		return
+	}
+
+	switch pl.Data[0] {
+	case monitor.MessageTypeTrace:
+		tn := monitor.TraceNotify{}
+		if err := binary.Read(bytes.NewReader(pl.Data), byteorder.Native, &tn); err != nil {
+			return
+		}
+		c := s.counters(tn.Source)
+		c.forwarded++
+		c.forwardedBytes += uint64(tn.OrigLen)
+	case monitor.MessageTypeDrop:
+		dn := monitor.DropNotify{}
+		if err := binary.Read(bytes.NewReader(pl.Data), byteorder.Native, &dn); err != nil {
+			return
+		}
+		c := s.counters(dn.Source)
+		c.dropped++
+		c.droppedBytes += uint64(dn.OrigLen)
+	}
+}
+
+// reset returns the counters accumulated since the last reset and the
+// number of lost events, and starts a new interval.
+func (s *topStats) reset() (map[uint16]*topCounters, uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	endpoints, lost := s.endpoints, s.lost
+	s.endpoints = map[uint16]*topCounters{}
+	s.lost = 0
+	return endpoints, lost
+}
+
+// topRow is the rate of packets and bytes of a single endpoint over an
+// interval.
+type topRow struct {
+	id        uint16
+	forwarded float64
+	dropped   float64
+	bytes     float64
+}
+
+// topRows converts the counters accumulated over interval into rates per
+// second, sorted by sortBy in descending order.
+func topRows(endpoints map[uint16]*topCounters, interval time.Duration, sortBy string) []topRow {
+	secs := interval.Seconds()
+	rows := make([]topRow, 0, len(endpoints))
+	for id, c := range endpoints {
+		rows = append(rows, topRow{
+			id:        id,
+			forwarded: float64(c.forwarded) / secs,
+			dropped:   float64(c.dropped) / secs,
+			bytes:     float64(c.forwardedBytes+c.droppedBytes) / secs,
+		})
+	}
+
+	key := func(r topRow) float64 {
+		switch sortBy {
+		case topSortDropped:
+			return r.dropped
+		case topSortBytes:
+			return r.bytes
+		default:
+			return r.forwarded
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		ki, kj := key(rows[i]), key(rows[j])
+		if ki != kj {
+			return ki > kj
+		}
+		return rows[i].id < rows[j].id
+	})
+	return rows
+}
+
+// endpointNames returns the pod or container name of all endpoints indexed
+// by endpoint ID. Errors are ignored as names are informational only.
+func endpointNames() map[uint16]string {
+	names := map[uint16]string{}
+	eps, err := client.EndpointList()
+	if err != nil {
+		return names
+	}
+	for _, ep := range eps {
+		if ep.Status == nil || ep.Status.ExternalIdentifiers == nil {
+			continue
+		}
+		ids := ep.Status.ExternalIdentifiers
+		switch {
+		case ids.PodName != "":
+			names[uint16(ep.ID)] = ids.PodName
+		case ids.ContainerName != "":
+			names[uint16(ep.ID)] = ids.ContainerName
+		}
+	}
+	return names
+}
+
+// printTop writes the table of endpoint rates to w, limited to maxRows rows
+// unless maxRows is 0.
+func printTop(w io.Writer, rows []topRow, names map[uint16]string, interval time.Duration, lost uint64, maxRows int) {
+	var forwarded, dropped float64
+	for _, r := range rows {
+		forwarded += r.forwarded
+		dropped += r.dropped
+	}
+	fmt.Fprintf(w, "cilium top - %s, interval %s\n", time.Now().Format("15:04:05"), interval)
+	fmt.Fprintf(w, "Endpoints: %d active, %.1f forwarded/s, %.1f dropped/s", len(rows), forwarded, dropped)
+	if lost > 0 {
+		fmt.Fprintf(w, ", %d events lost", lost)
+	}
+	fmt.Fprintf(w, "\n\n")
+
+	if maxRows > 0 && len(rows) > maxRows {
+		rows = rows[:maxRows]
+	}
+
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "ENDPOINT\tFORWARDED/s\tDROPPED/s\tBYTES/s\tNAME\n")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%d\t%.1f\t%.1f\t%.0f\t%s\n", r.id, r.forwarded, r.dropped, r.bytes, names[r.id])
+	}
+	tw.Flush()
+}
+
+func runTop() {
+	conn, version, err := openMonitorSock()
+	if err != nil {
+		Fatalf("%s", err)
+	}
+	getParsedPayload, err := getMonitorParser(conn, version)
+	if err != nil {
+		Fatalf("%s", err)
+	}
+
+	stats := newTopStats()
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			pl, err := getParsedPayload()
+			if err != nil {
+				errs <- err
+				return
+			}
+			stats.addPayload(pl)
+		}
+	}()
+
+	ticker := time.NewTicker(topInterval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	for i := 0; topIterations == 0 || i < topIterations; i++ {
+		select {
+		case err := <-errs:
+			Fatalf("Connection to monitor lost: %s", err)
+		case now := <-ticker.C:
+			endpoints, lost := stats.reset()
+			interval := now.Sub(start)
+			start = now
+
+			rows := topRows(endpoints, interval, topSortBy)
+			fmt.Print(clearScreen)
+			printTop(os.Stdout, rows, endpointNames(), topInterval, lost, topMaxRows)
+		}
+	}
+	conn.Close()
+}
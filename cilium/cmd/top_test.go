@@ -0,0 +1,105 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+
+	. "gopkg.in/check.v1"
+)
+
+type TopSuite struct{}
+
+var _ = Suite(&TopSuite{})
+
+func encodeNotify(c *C, n interface{}) *payload.Payload {
+	buf := &bytes.Buffer{}
+	c.Assert(binary.Write(buf, byteorder.Native, n), IsNil)
+	return &payload.Payload{Type: payload.EventSample, Data: buf.Bytes()}
+}
+
+func (s *TopSuite) TestTopStats(c *C) {
+	stats := newTopStats()
+
+	for i := 0; i < 4; i++ {
+		stats.addPayload(encodeNotify(c, &monitor.TraceNotify{
+			Type: monitor.MessageTypeTrace, Source: 10, OrigLen: 100,
+		}))
+	}
+	stats.addPayload(encodeNotify(c, &monitor.TraceNotify{
+		Type: monitor.MessageTypeTrace, Source: 20, OrigLen: 1000,
+	}))
+	for i := 0; i < 6; i++ {
+		stats.addPayload(encodeNotify(c, &monitor.DropNotify{
+			Type: monitor.MessageTypeDrop, Source: 20, OrigLen: 50,
+		}))
+	}
+	stats.addPayload(encodeNotify(c, &monitor.DebugMsg{Type: monitor.MessageTypeDebug, Source: 30}))
+	stats.addPayload(&payload.Payload{Type: payload.RecordLost, Lost: 3})
+
+	endpoints, lost := stats.reset()
+	c.Assert(lost, Equals, uint64(3))
+	c.Assert(endpoints, HasLen, 2)
+	c.Assert(*endpoints[10], Equals, topCounters{forwarded: 4, forwardedBytes: 400})
+	c.Assert(*endpoints[20], Equals, topCounters{forwarded: 1, forwardedBytes: 1000, dropped: 6, droppedBytes: 300})
+
+	// reset starts a new interval
+	endpoints, lost = stats.reset()
+	c.Assert(lost, Equals, uint64(0))
+	c.Assert(endpoints, HasLen, 0)
+}
+
+func (s *TopSuite) TestTopRows(c *C) {
+	endpoints := map[uint16]*topCounters{
+		10: {forwarded: 4, forwardedBytes: 400},
+		20: {forwarded: 2, forwardedBytes: 2000, dropped: 6, droppedBytes: 300},
+		30: {forwarded: 4},
+	}
+
+	ids := func(rows []topRow) []uint16 {
+		result := []uint16{}
+		for _, r := range rows {
+			result = append(result, r.id)
+		}
+		return result
+	}
+
+	rows := topRows(endpoints, 2*time.Second, topSortForwarded)
+	c.Assert(ids(rows), DeepEquals, []uint16{10, 30, 20})
+	c.Assert(rows[0], Equals, topRow{id: 10, forwarded: 2, bytes: 200})
+
+	rows = topRows(endpoints, 2*time.Second, topSortDropped)
+	c.Assert(ids(rows), DeepEquals, []uint16{20, 10, 30})
+	c.Assert(rows[0], Equals, topRow{id: 20, forwarded: 1, dropped: 3, bytes: 1150})
+
+	rows = topRows(endpoints, 2*time.Second, topSortBytes)
+	c.Assert(ids(rows), DeepEquals, []uint16{20, 10, 30})
+
+	buf := &bytes.Buffer{}
+	printTop(buf, rows, map[uint16]string{20: "default:frontend"}, 2*time.Second, 5, 2)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	c.Assert(lines, HasLen, 6)
+	c.Assert(lines[1], Equals, "Endpoints: 3 active, 5.0 forwarded/s, 3.0 dropped/s, 5 events lost")
+	c.Assert(strings.Fields(lines[3]), DeepEquals, []string{"ENDPOINT", "FORWARDED/s", "DROPPED/s", "BYTES/s", "NAME"})
+	c.Assert(strings.Fields(lines[4]), DeepEquals, []string{"20", "1.0", "3.0", "1150", "default:frontend"})
+	c.Assert(strings.Fields(lines[5]), DeepEquals, []string{"10", "2.0", "0.0", "200"})
+}
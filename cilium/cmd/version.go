@@ -16,8 +16,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"text/tabwriter"
 
+	"github.com/cilium/cilium/api/v1/models"
+	pkg "github.com/cilium/cilium/pkg/client"
 	"github.com/cilium/cilium/pkg/command"
 	"github.com/cilium/cilium/pkg/version"
 
@@ -26,9 +31,16 @@ import (
 
 const notResponding = "Not responding"
 
+var versionServer bool
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
+	Long: `Print version information of the client and the daemon.
+
+With --server, the daemon is queried for its capabilities as well: the kernel
+features it detected, the subsystems which are enabled, the sizes of the BPF
+maps it opened and the version of the API it serves.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		getVersion(cmd, args)
 	},
@@ -36,27 +48,81 @@ var versionCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&versionServer, "server", false, "Query the daemon for its capabilities")
 	command.AddJSONOutput(versionCmd)
 }
 
 func getVersion(cmd *cobra.Command, args []string) {
+	var capabilities *models.Capabilities
+	if versionServer {
+		resp, err := client.Daemon.GetCapabilities(nil)
+		if err != nil {
+			Fatalf("Unable to retrieve capabilities: %s", pkg.Hint(err))
+		}
+		capabilities = resp.Payload
+	}
+
 	// -o argument is set
 	if command.OutputJSON() {
 		data := struct {
-			Client version.CiliumVersion
-			Daemon version.CiliumVersion
+			Client       version.CiliumVersion
+			Daemon       version.CiliumVersion
+			Capabilities *models.Capabilities `json:",omitempty"`
 		}{
 			getClientVersionAsStruct(),
 			getDaemonVersionAsStruct(),
+			capabilities,
 		}
 		if err := command.PrintOutput(data); err != nil {
-			os.Exit(1)
+			Fatalf("Unable to print output: %s", err)
 		}
 		return
 	}
 	// default output
 	fmt.Printf("Client: %s\n", getClientVersionAsString())
 	fmt.Printf("Daemon: %s\n", getDaemonVersionAsString())
+	if capabilities != nil {
+		fmt.Println()
+		printCapabilities(os.Stdout, capabilities)
+	}
+}
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// printCapabilities writes the capabilities of the daemon as tables to w.
+func printCapabilities(w io.Writer, c *models.Capabilities) {
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "API version:\t%s\n", c.APIVersion)
+	fmt.Fprintf(tw, "Kernel version:\t%s\n", c.KernelVersion)
+
+	fmt.Fprintf(tw, "\nKERNEL FEATURE\tSUPPORTED\n")
+	for _, feature := range sortedKeys(c.KernelFeatures) {
+		fmt.Fprintf(tw, "%s\t%t\n", feature, c.KernelFeatures[feature])
+	}
+
+	fmt.Fprintf(tw, "\nSUBSYSTEM\tENABLED\n")
+	for _, subsystem := range sortedKeys(c.Subsystems) {
+		fmt.Fprintf(tw, "%s\t%t\n", subsystem, c.Subsystems[subsystem])
+	}
+
+	maps := make([]string, 0, len(c.MapSizes))
+	for name := range c.MapSizes {
+		maps = append(maps, name)
+	}
+	sort.Strings(maps)
+	fmt.Fprintf(tw, "\nMAP\tMAX ENTRIES\n")
+	for _, name := range maps {
+		fmt.Fprintf(tw, "%s\t%d\n", name, c.MapSizes[name])
+	}
+	tw.Flush()
 }
 
 func getClientVersionAsString() string {
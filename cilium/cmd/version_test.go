@@ -0,0 +1,63 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+type VersionSuite struct{}
+
+var _ = Suite(&VersionSuite{})
+
+func (s *VersionSuite) TestPrintCapabilities(c *C) {
+	var buf bytes.Buffer
+	printCapabilities(&buf, &models.Capabilities{
+		APIVersion:    "v1beta",
+		KernelVersion: "4.15.0",
+		KernelFeatures: map[string]bool{
+			"HAVE_LRU_MAP_TYPE": true,
+			"HAVE_LPM_MAP_TYPE": false,
+		},
+		Subsystems: map[string]bool{
+			"tunnel": true,
+			"ipv4":   false,
+		},
+		MapSizes: map[string]int64{
+			"cilium_lxc":        65535,
+			"cilium_ct4_global": 1000000,
+		},
+	})
+
+	c.Assert(buf.String(), Equals, `API version:      v1beta
+Kernel version:   4.15.0
+
+KERNEL FEATURE      SUPPORTED
+HAVE_LPM_MAP_TYPE   false
+HAVE_LRU_MAP_TYPE   true
+
+SUBSYSTEM   ENABLED
+ipv4        false
+tunnel      true
+
+MAP                 MAX ENTRIES
+cilium_ct4_global   1000000
+cilium_lxc          65535
+`)
+}
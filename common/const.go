@@ -33,6 +33,9 @@ const (
 	// CHeaderFileName is the name of the C header file for BPF programs for a
 	// particular endpoint.
 	CHeaderFileName = "lxc_config.h"
+	// CHeaderBackupFileName is the name of the copy of the endpoint C header
+	// file of the previous successful build of the endpoint.
+	CHeaderBackupFileName = "lxc_config.h.prev"
 	// NetdevHeaderFileName is the name of the header file used for bpf_netdev.c and bpf_overlay.c.
 	NetdevHeaderFileName = "netdev_config.h"
 	// PreFilterHeaderFileName is the name of the header file used for bpf_xdp.c.
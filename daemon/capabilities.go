@@ -0,0 +1,78 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/api/v1/models"
+	restapi "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/k8s"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/version"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+type getCapabilities struct {
+	daemon     *Daemon
+	apiVersion string
+}
+
+// NewGetCapabilitiesHandler returns the capabilities endpoint handler for the
+// agent. apiVersion is the version of the API served by the agent.
+func NewGetCapabilitiesHandler(d *Daemon, apiVersion string) restapi.GetCapabilitiesHandler {
+	return &getCapabilities{daemon: d, apiVersion: apiVersion}
+}
+
+func (h *getCapabilities) Handle(params restapi.GetCapabilitiesParams) middleware.Responder {
+	c := &models.Capabilities{
+		APIVersion:     h.apiVersion,
+		CiliumVersion:  version.Version,
+		KernelFeatures: bpf.GetKernelFeatures(),
+		Subsystems:     h.daemon.getSubsystems(),
+		MapSizes:       bpf.GetOpenMapsMaxEntries(),
+	}
+
+	if kver, err := getKernelVersion(); err != nil {
+		c.KernelVersion = fmt.Sprintf("Error: %s", err)
+	} else {
+		c.KernelVersion = kver.String()
+	}
+
+	return restapi.NewGetCapabilitiesOK().WithPayload(c)
+}
+
+// getSubsystems returns the subsystems of the agent and whether they are
+// enabled.
+func (d *Daemon) getSubsystems() map[string]bool {
+	return map[string]bool{
+		"ipv4":           !option.Config.IPv4Disabled,
+		"ipv6":           true,
+		"tunnel":         option.Config.Tunnel != option.TunnelDisabled,
+		"conntrack":      option.Config.Opts.IsEnabled(option.Conntrack),
+		"policy-tracing": option.Config.Opts.IsEnabled(option.PolicyTracing),
+		"kubernetes":     k8s.IsEnabled(),
+		"kvstore":        kvStore != "",
+		"clustermesh":    option.Config.ClusterMeshConfig != "",
+		"prefilter":      option.Config.DevicePreFilter != "undefined",
+		"load-balancer":  option.Config.LBInterface != "",
+		"node-monitor":   d.nodeMonitor.State() != nil,
+		"proxy-tracing":  option.Config.ProxyTraceCollector != "",
+		"proxy-lua":      option.Config.ProxyLuaScript != "",
+		"endpoint-hooks": option.Config.EndpointHooks != "",
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
@@ -44,6 +45,7 @@ import (
 	"github.com/cilium/cilium/pkg/counter"
 	bpfIPCache "github.com/cilium/cilium/pkg/datapath/ipcache"
 	"github.com/cilium/cilium/pkg/datapath/prefilter"
+	"github.com/cilium/cilium/pkg/datapath/route"
 	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/endpointmanager"
@@ -63,6 +65,8 @@ import (
 	"github.com/cilium/cilium/pkg/maps/lbmap"
 	"github.com/cilium/cilium/pkg/maps/lxcmap"
 	"github.com/cilium/cilium/pkg/maps/metricsmap"
+	"github.com/cilium/cilium/pkg/maps/natmap"
+	"github.com/cilium/cilium/pkg/maps/neighmap"
 	"github.com/cilium/cilium/pkg/maps/policymap"
 	"github.com/cilium/cilium/pkg/maps/proxymap"
 	"github.com/cilium/cilium/pkg/maps/tunnel"
@@ -75,7 +79,7 @@ import (
 	"github.com/cilium/cilium/pkg/proxy"
 	"github.com/cilium/cilium/pkg/proxy/logger"
 	"github.com/cilium/cilium/pkg/revert"
-	"github.com/cilium/cilium/pkg/u8proto"
+	"github.com/cilium/cilium/pkg/sockops"
 	"github.com/cilium/cilium/pkg/workloads"
 
 	"github.com/go-openapi/runtime/middleware"
@@ -121,6 +125,23 @@ type Daemon struct {
 	statusResponse          models.StatusResponse
 	statusResponseTimestamp time.Time
 
+	// degradedReason is the reason why the watchdog marked the node as
+	// degraded, empty if the node is not degraded. Protected by
+	// statusCollectMutex.
+	degradedReason string
+
+	// restoreProgress tracks the regeneration of the endpoints restored
+	// at startup
+	restoreProgress endpointRestoreProgress
+
+	// staleMaps tracks the removal of pinned maps of endpoints which no
+	// longer exist
+	staleMaps staleMapGC
+
+	// mirrorCollectors caches the local endpoints which traffic can be
+	// mirrored to
+	mirrorCollectors mirrorCollectors
+
 	uniqueIDMU lock.Mutex
 	uniqueID   map[uint64]bool
 
@@ -386,6 +407,7 @@ const (
 	ciliumOutputChain     = "CILIUM_OUTPUT"
 	ciliumPostNatChain    = "CILIUM_POST"
 	ciliumPostMangleChain = "CILIUM_POST_mangle"
+	ciliumPreMangleChain  = "CILIUM_PRE_mangle"
 	ciliumForwardChain    = "CILIUM_FORWARD"
 	feederDescription     = "cilium-feeder:"
 )
@@ -518,6 +540,12 @@ var ciliumChains = []customChain{
 		hook:       "FORWARD",
 		feederArgs: []string{""},
 	},
+	{
+		name:       ciliumPreMangleChain,
+		table:      "mangle",
+		hook:       "PREROUTING",
+		feederArgs: []string{""},
+	},
 }
 
 func (d *Daemon) removeIptablesRules() {
@@ -608,6 +636,21 @@ func (d *Daemon) installIptablesRules() error {
 		return err
 	}
 
+	if option.Config.ProxyTransparent {
+		// Mark all packets destined to a transparent socket of a proxy
+		// so that they are delivered locally although the destination
+		// is the original source address of a proxied connection.
+		markAsToProxy := fmt.Sprintf("%#08x/%#08x", proxy.MagicMarkIsToProxy, proxy.MagicMarkHostMask)
+		if err := runProg("iptables", []string{
+			"-t", "mangle",
+			"-A", ciliumPreMangleChain,
+			"-m", "socket", "--transparent",
+			"-m", "comment", "--comment", "cilium: any->proxy transparent socket",
+			"-j", "MARK", "--set-xmark", markAsToProxy}, false); err != nil {
+			return err
+		}
+	}
+
 	if masquerade {
 		ingressSnatSrcAddrExclusion := node.GetHostMasqueradeIPv4().String()
 		if option.Config.Tunnel == option.TunnelDisabled {
@@ -648,11 +691,6 @@ func (d *Daemon) installIptablesRules() error {
 			return err
 		}
 
-		egressSnatDstAddrExclusion := node.GetIPv4AllocRange().String()
-		if option.Config.Tunnel == option.TunnelDisabled {
-			egressSnatDstAddrExclusion = node.GetIPv4ClusterRange().String()
-		}
-
 		// Masquerade all egress traffic leaving the node
 		//
 		// The following conditions must be met:
@@ -664,11 +702,31 @@ func (d *Daemon) installIptablesRules() error {
 		//     range
 		// * Non-tunnel mode:
 		//   * May not be targeted to an IP in the cluster range
+		//
+		// With BPF masquerading, the program attached to the device
+		// translates TCP and UDP instead, this traffic skips the
+		// masquerading rule which must remain the last rule of the
+		// chain.
+		if viper.GetBool(option.BPFMasqueradeName) {
+			for _, proto := range []string{"tcp", "udp"} {
+				if err := runProg("iptables", []string{
+					"-t", "nat",
+					"-A", ciliumPostNatChain,
+					"-s", node.GetIPv4AllocRange().String(),
+					"!", "-d", egressSnatDstAddrExclusion().String(),
+					"!", "-o", "cilium_+",
+					"-p", proto,
+					"-m", "comment", "--comment", "cilium masquerade non-cluster " + proto + " in bpf",
+					"-j", "RETURN"}, false); err != nil {
+					return err
+				}
+			}
+		}
 		if err := runProg("iptables", []string{
 			"-t", "nat",
-			"-A", "CILIUM_POST",
+			"-A", ciliumPostNatChain,
 			"-s", node.GetIPv4AllocRange().String(),
-			"!", "-d", egressSnatDstAddrExclusion,
+			"!", "-d", egressSnatDstAddrExclusion().String(),
 			"!", "-o", "cilium_+",
 			"-m", "comment", "--comment", "cilium masquerade non-cluster",
 			"-j", "MASQUERADE"}, false); err != nil {
@@ -685,6 +743,71 @@ func (d *Daemon) installIptablesRules() error {
 	return nil
 }
 
+// proxyTransparentRule routes all packets marked as destined to a transparent
+// socket of a proxy via a routing table which delivers them locally.
+var proxyTransparentRule = route.Rule{
+	Priority: 9,
+	Mark:     proxy.MagicMarkIsToProxy,
+	Mask:     proxy.MagicMarkHostMask,
+	Table:    2005,
+}
+
+// installProxyTransparentRouting installs the routing of packets to
+// transparent proxy sockets if --proxy-transparent is set and removes it
+// otherwise.
+func installProxyTransparentRouting() error {
+	if !option.Config.ProxyTransparent {
+		if err := route.DeleteRule(proxyTransparentRule); err != nil {
+			return err
+		}
+		return route.DeleteLocalRoute(proxyTransparentRule.Table)
+	}
+
+	if err := route.ReplaceLocalRoute(proxyTransparentRule.Table); err != nil {
+		return err
+	}
+	return route.ReplaceRule(proxyTransparentRule)
+}
+
+// egressSnatDstAddrExclusion returns the destinations of traffic from local
+// endpoints which is not masqueraded when leaving the node.
+func egressSnatDstAddrExclusion() *net.IPNet {
+	if option.Config.Tunnel == option.TunnelDisabled {
+		return node.GetIPv4ClusterRange()
+	}
+	return node.GetIPv4AllocRange()
+}
+
+// writeMasqueradeConfig writes the defines of the BPF masquerading of
+// traffic leaving the node on the device to fw.
+func writeMasqueradeConfig(fw io.Writer) {
+	srcRange := node.GetIPv4AllocRange()
+	dstExclusion := egressSnatDstAddrExclusion()
+
+	fmt.Fprintf(fw, "#define ENABLE_MASQUERADE 1\n")
+	fmt.Fprintf(fw, "#define SNAT_IPV4_EXTERNAL %#x\n", byteorder.HostSliceToNetwork(node.GetExternalIPv4().To4(), reflect.Uint32).(uint32))
+	fmt.Fprintf(fw, "#define SNAT_IPV4_SRC_CIDR %#x\n", byteorder.HostSliceToNetwork(srcRange.IP.Mask(srcRange.Mask), reflect.Uint32).(uint32))
+	fmt.Fprintf(fw, "#define SNAT_IPV4_SRC_MASK %#x\n", byteorder.HostSliceToNetwork(srcRange.Mask, reflect.Uint32).(uint32))
+	fmt.Fprintf(fw, "#define SNAT_IPV4_EXCLUDE_DST_CIDR %#x\n", byteorder.HostSliceToNetwork(dstExclusion.IP.Mask(dstExclusion.Mask), reflect.Uint32).(uint32))
+	fmt.Fprintf(fw, "#define SNAT_IPV4_EXCLUDE_DST_MASK %#x\n", byteorder.HostSliceToNetwork(dstExclusion.Mask, reflect.Uint32).(uint32))
+	natmap.WriteBPFMacros(fw)
+}
+
+// writeNodeNeighborConfig writes the defines of the forwarding to the next
+// hops of the node neighbor table via the device to fw.
+func writeNodeNeighborConfig(fw io.Writer) error {
+	link, err := netlink.LinkByName(option.Config.Device)
+	if err != nil {
+		return fmt.Errorf("unable to find device %s: %s", option.Config.Device, err)
+	}
+
+	fmt.Fprintf(fw, "#define ENABLE_NODE_NEIGH 1\n")
+	fmt.Fprintf(fw, "#define NODE_NEIGH_MAP_SIZE %d\n", neighmap.MaxEntries)
+	fmt.Fprintf(fw, "#define NATIVE_DEV_IFINDEX %d\n", link.Attrs().Index)
+	fmt.Fprint(fw, common.FmtDefineAddress("NATIVE_DEV_MAC", link.Attrs().HardwareAddr))
+	return nil
+}
+
 // GetCompilationLock returns the mutex responsible for synchronizing compilation
 // of BPF programs.
 func (d *Daemon) GetCompilationLock() *lock.RWMutex {
@@ -786,6 +909,10 @@ func (d *Daemon) compileBase() error {
 		if err := d.installIptablesRules(); err != nil {
 			return err
 		}
+
+		if err := installProxyTransparentRouting(); err != nil {
+			return fmt.Errorf("unable to install transparent proxy routing: %s", err)
+		}
 	}
 
 	log.Info("Setting sysctl net.core.bpf_jit_enable=1")
@@ -795,6 +922,25 @@ func (d *Daemon) compileBase() error {
 	return nil
 }
 
+// initSockops enables the sockops acceleration if requested and otherwise
+// removes the programs of a previous run. The acceleration is best effort, the
+// agent continues without it if the kernel lacks support.
+func initSockops() {
+	cgroupRoot := viper.GetString(option.CgroupRootName)
+	if !viper.GetBool(option.SockopsEnableName) {
+		sockops.Disable(cgroupRoot)
+		return
+	}
+
+	dir := filepath.Join(option.Config.StateDir, defaults.SockopsDir)
+	ctx, cancel := context.WithTimeout(context.Background(), ExecTimeout)
+	defer cancel()
+	if err := sockops.Enable(ctx, dir, cgroupRoot); err != nil {
+		log.WithError(err).Warning("Unable to enable sockops acceleration, continuing without it")
+		sockops.Disable(cgroupRoot)
+	}
+}
+
 func (d *Daemon) init() error {
 
 	var err error
@@ -830,6 +976,8 @@ func (d *Daemon) init() error {
 			return err
 		}
 
+		initSockops()
+
 		// Start the controller for periodic sync
 		// The purpose of the controller is to ensure that the host entries are
 		// reinserted to the bpf maps if they are ever removed from them.
@@ -965,13 +1113,33 @@ func createNodeConfigHeaderfile() error {
 	fmt.Fprintf(fw, "#define PROXY_MAP_SIZE %d\n", proxymap.MaxEntries)
 	fmt.Fprintf(fw, "#define ENDPOINTS_MAP_SIZE %d\n", lxcmap.MaxEntries)
 	fmt.Fprintf(fw, "#define METRICS_MAP_SIZE %d\n", metricsmap.MaxEntries)
+	fmt.Fprintf(fw, "#define SOCKOPS_MAP_SIZE %d\n", sockops.MaxEntries)
 	fmt.Fprintf(fw, "#define POLICY_MAP_SIZE %d\n", policymap.MaxEntries)
 	fmt.Fprintf(fw, "#define IPCACHE_MAP_SIZE %d\n", ipcachemap.MaxEntries)
 	fmt.Fprintf(fw, "#define POLICY_PROG_MAP_SIZE %d\n", policymap.ProgArrayMaxEntries)
+	fmt.Fprintf(fw, "#define CT_LIFETIME_TCP %d\n", option.Config.CTTimeoutTCP)
+	fmt.Fprintf(fw, "#define CT_SYN_TIMEOUT %d\n", option.Config.CTTimeoutTCPSyn)
+	fmt.Fprintf(fw, "#define CT_LIFETIME_NONTCP %d\n", option.Config.CTTimeoutUDP)
+	fmt.Fprintf(fw, "#define CT_LIFETIME_ICMP %d\n", option.Config.CTTimeoutICMP)
 
 	fmt.Fprintf(fw, "#define TRACE_PAYLOAD_LEN %dULL\n", tracePayloadLen)
 	fmt.Fprintf(fw, "#define MTU %d\n", mtu.GetDeviceMTU())
 
+	if viper.GetBool(option.BPFMasqueradeName) {
+		writeMasqueradeConfig(fw)
+	}
+
+	if option.Config.ProxyTransparent {
+		fmt.Fprintf(fw, "#define ENABLE_PROXY_TRANSPARENT 1\n")
+	}
+
+	if viper.GetBool(option.NodeNeighborTableName) {
+		if err := writeNodeNeighborConfig(fw); err != nil {
+			f.Close()
+			return err
+		}
+	}
+
 	fw.Flush()
 	f.Close()
 
@@ -1100,12 +1268,17 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 	}
 
 	lb := loadbalancer.NewLoadBalancer()
+	monitorConfig := monitorLaunch.RingBufferConfig{
+		NumPages:     viper.GetInt(option.MonitorNumPagesName),
+		WakeupEvents: viper.GetInt(option.MonitorWakeupEventsName),
+		Overwrite:    viper.GetBool(option.MonitorOverwriteName),
+	}
 
 	d := Daemon{
 		loadBalancer:  lb,
 		policy:        policy.NewPolicyRepository(),
 		uniqueID:      map[uint64]bool{},
-		nodeMonitor:   monitorLaunch.NewNodeMonitor(),
+		nodeMonitor:   monitorLaunch.NewNodeMonitor(monitorConfig),
 		prefixLengths: createPrefixLengthCounter(),
 
 		// FIXME
@@ -1322,9 +1495,22 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 	// we populate the IPCache with the host's IP(s).
 	ipcache.InitIPIdentityWatcher()
 
+	for _, rawurl := range option.Config.AccessLogSinks {
+		sink, err := logger.NewSink(rawurl)
+		if err != nil {
+			return nil, nil, err
+		}
+		logger.AddSink(rawurl, sink, viper.GetInt(option.AccessLogSinkBufferSizeName))
+	}
+
 	// FIXME: Make the port range configurable.
 	d.l7Proxy = proxy.StartProxySupport(10000, 20000, option.Config.RunDir,
-		option.Config.AccessLog, &d, option.Config.AgentLabels)
+		option.Config.AccessLog, logger.LogfileRotation{
+			MaxSize:    viper.GetInt(option.AccessLogMaxSizeName),
+			MaxBackups: viper.GetInt(option.AccessLogMaxBackupsName),
+			MaxAge:     viper.GetInt(option.AccessLogMaxAgeName),
+			Compress:   viper.GetBool(option.AccessLogCompressName),
+		}, &d, option.Config.AgentLabels)
 
 	d.startStatusCollector()
 
@@ -1343,76 +1529,11 @@ func NewDaemon() (*Daemon, *endpointRestoreState, error) {
 		}})
 	fqdn.StartDNSPoller(d.dnsPoller)
 
-	return &d, restoredEndpoints, nil
-}
+	// Let the DNS proxy feed the IPs of forwarded DNS responses into the
+	// ToFQDNs rules before the responses reach the endpoints.
+	d.l7Proxy.SetDNSResponseNotifier(d.notifyOnDNSResponse)
 
-func (d *Daemon) collectStaleMapGarbage() {
-	if option.Config.DryMode {
-		return
-	}
-	walker := func(path string, _ os.FileInfo, _ error) error {
-		return d.staleMapWalker(path)
-	}
-
-	if err := filepath.Walk(bpf.MapPrefixPath(), walker); err != nil {
-		log.WithError(err).Warn("Error while scanning for stale maps")
-	}
-}
-
-func (d *Daemon) removeStaleMap(path string) {
-	if err := os.RemoveAll(path); err != nil {
-		log.WithError(err).WithField(logfields.Path, path).Warn("Error while deleting stale map file")
-	} else {
-		log.WithField(logfields.Path, path).Info("Removed stale bpf map")
-	}
-}
-
-func (d *Daemon) removeStaleIDFromPolicyMap(id uint32) {
-	gpm, err := policymap.OpenGlobalMap(bpf.MapPath(endpoint.PolicyGlobalMapName))
-	if err == nil {
-		gpm.Delete(id, policymap.AllPorts, u8proto.All, policymap.Ingress)
-		gpm.Delete(id, policymap.AllPorts, u8proto.All, policymap.Egress)
-		gpm.Close()
-	}
-}
-
-func (d *Daemon) checkStaleMap(path string, filename string, id string) {
-	if tmp, err := strconv.ParseUint(id, 0, 16); err == nil {
-		if ep := endpointmanager.LookupCiliumID(uint16(tmp)); ep == nil {
-			d.removeStaleIDFromPolicyMap(uint32(tmp))
-			d.removeStaleMap(path)
-		}
-	}
-}
-
-func (d *Daemon) checkStaleGlobalMap(path string, filename string) {
-	globalCTinUse := endpointmanager.HasGlobalCT()
-
-	if !globalCTinUse && ctmap.NameIsGlobal(filename) {
-		d.removeStaleMap(path)
-	}
-}
-
-func (d *Daemon) staleMapWalker(path string) error {
-	filename := filepath.Base(path)
-
-	mapPrefix := []string{
-		policymap.MapName,
-		ctmap.MapNamePrefix,
-		endpoint.CallsMapName,
-	}
-
-	d.checkStaleGlobalMap(path, filename)
-
-	for _, m := range mapPrefix {
-		if strings.HasPrefix(filename, m) {
-			if id := strings.TrimPrefix(filename, m); id != filename {
-				d.checkStaleMap(path, filename, id)
-			}
-		}
-	}
-
-	return nil
+	return &d, restoredEndpoints, nil
 }
 
 // TriggerReloadWithoutCompile causes all BPF programs and maps to be reloaded,
@@ -1463,6 +1584,19 @@ func (h *patchConfig) Handle(params PatchConfigParams) middleware.Responder {
 
 	cfgSpec := params.Configuration
 
+	// The number of pages of the ring buffers of the node monitor is not
+	// an option of the daemon and is therefore validated separately
+	numPages := 0
+	if numPagesEntry, ok := cfgSpec.Options["MonitorNumPages"]; ok {
+		var err error
+		numPages, err = strconv.Atoi(numPagesEntry)
+		if err != nil || numPages < 1 || numPages&(numPages-1) != 0 {
+			msg := fmt.Errorf("Invalid number of monitor pages %s, must be a power of two", numPagesEntry)
+			return api.Error(PatchConfigBadRequestCode, msg)
+		}
+		delete(cfgSpec.Options, "MonitorNumPages")
+	}
+
 	om, err := option.Config.Opts.Library.ValidateConfigurationMap(cfgSpec.Options)
 	if err != nil {
 		msg := fmt.Errorf("Invalid configuration option %s", err)
@@ -1473,16 +1607,11 @@ func (h *patchConfig) Handle(params PatchConfigParams) middleware.Responder {
 	option.Config.ConfigPatchMutex.Lock()
 	defer option.Config.ConfigPatchMutex.Unlock()
 
-	nmArgs := d.nodeMonitor.GetArgs()
-	if numPagesEntry, ok := cfgSpec.Options["MonitorNumPages"]; ok && nmArgs[0] != numPagesEntry {
-		if len(nmArgs) == 0 || nmArgs[0] != numPagesEntry {
-			args := []string{"--num-pages %s", numPagesEntry}
-			d.nodeMonitor.Restart(args)
-		}
+	if numPages > 0 {
+		d.nodeMonitor.SetNumPages(numPages)
 		if len(cfgSpec.Options) == 0 {
 			return NewPatchConfigOK()
 		}
-		delete(cfgSpec.Options, "MonitorNumPages")
 	}
 
 	// Track changes to daemon's configuration
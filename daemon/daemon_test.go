@@ -34,6 +34,7 @@ import (
 	"github.com/cilium/cilium/pkg/monitor"
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/cilium/cilium/pkg/proxy/accesslog"
 
 	. "gopkg.in/check.v1"
@@ -66,6 +67,8 @@ type DaemonSuite struct {
 	OnGetCompilationLock      func() *lock.RWMutex
 	OnSendNotification        func(typ monitor.AgentNotification, text string) error
 	OnNewProxyLogRecord       func(l *accesslog.LogRecord) error
+	OnLookupEndpointIfIndex   func(selector api.EndpointSelector) (int, bool)
+	OnUpdateMirrorCollectors  func()
 }
 
 func (ds *DaemonSuite) SetUpTest(c *C) {
@@ -121,6 +124,8 @@ func (ds *DaemonSuite) SetUpTest(c *C) {
 	ds.OnGetCompilationLock = nil
 	ds.OnSendNotification = nil
 	ds.OnNewProxyLogRecord = nil
+	ds.OnLookupEndpointIfIndex = nil
+	ds.OnUpdateMirrorCollectors = nil
 }
 
 func (ds *DaemonSuite) TearDownTest(c *C) {
@@ -267,3 +272,18 @@ func (ds *DaemonSuite) NewProxyLogRecord(l *accesslog.LogRecord) error {
 	}
 	panic("NewProxyLogRecord should not have been called")
 }
+
+func (ds *DaemonSuite) LookupEndpointIfIndex(selector api.EndpointSelector) (int, bool) {
+	if ds.OnLookupEndpointIfIndex != nil {
+		return ds.OnLookupEndpointIfIndex(selector)
+	}
+	panic("LookupEndpointIfIndex should not have been called")
+}
+
+func (ds *DaemonSuite) UpdateMirrorCollectors() {
+	if ds.OnUpdateMirrorCollectors != nil {
+		ds.OnUpdateMirrorCollectors()
+		return
+	}
+	panic("UpdateMirrorCollectors should not have been called")
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"github.com/cilium/cilium/pkg/endpoint"
 	endpointid "github.com/cilium/cilium/pkg/endpoint/id"
 	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/hooks"
 	"github.com/cilium/cilium/pkg/ipam"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/logging/logfields"
@@ -120,7 +122,15 @@ func (h *getEndpointID) Handle(params GetEndpointIDParams) middleware.Responder
 	} else if ep == nil {
 		return NewGetEndpointIDNotFound()
 	} else {
-		return NewGetEndpointIDOK().WithPayload(ep.GetModel())
+		mdl := ep.GetModel()
+		if mdl != nil && mdl.Status != nil && mdl.Status.Policy != nil {
+			ep.AddPolicyMapModel(mdl.Status.Policy)
+			mdl.Status.Policy.L7Statistics = ep.GetL7StatisticsModel()
+		}
+		if mdl != nil && mdl.Status != nil {
+			mdl.Status.EgressRate = ep.GetEgressRate()
+		}
+		return NewGetEndpointIDOK().WithPayload(mdl)
 	}
 }
 
@@ -177,6 +187,11 @@ func (d *Daemon) createEndpoint(epTemplate *models.EndpointChangeRequest, id str
 		log.WithError(err).Warn("Aborting endpoint join")
 		return PutEndpointIDFailedCode, err
 	}
+	d.UpdateMirrorCollectors()
+
+	if hooks.Enabled() {
+		hooks.Notify(hooks.EventEndpointCreate, ep.GetModel())
+	}
 
 	// Only used for CRI-O since it does not support events.
 	if d.workloadsEventsCh != nil && ep.GetContainerID() != "" {
@@ -350,10 +365,26 @@ func (h *patchEndpointID) Handle(params PatchEndpointIDParams) middleware.Respon
 	}
 
 	changed := false
+	ifIndexChanged := false
+
+	// The additional addresses are not allocated by IPAM, they are
+	// replaced as a whole. They are replaced first so that the endpoint
+	// is left untouched if any of them is used by another endpoint.
+	if epTemplate.AdditionalAddressing != nil {
+		addrChanged, err := endpointmanager.UpdateAdditionalAddresses(ep, newEp.AdditionalIPv4, newEp.AdditionalIPv6)
+		if err != nil {
+			ep.Unlock()
+			return api.Error(PatchEndpointIDInvalidCode, err)
+		}
+		if addrChanged {
+			changed = true
+		}
+	}
 
 	if epTemplate.InterfaceIndex != 0 && ep.IfIndex != newEp.IfIndex {
 		ep.IfIndex = newEp.IfIndex
 		changed = true
+		ifIndexChanged = true
 	}
 
 	if epTemplate.InterfaceName != "" && ep.IfName != newEp.IfName {
@@ -431,6 +462,10 @@ func (h *patchEndpointID) Handle(params PatchEndpointIDParams) middleware.Respon
 	ep.UpdateLogger(nil)
 	ep.Unlock()
 
+	if ifIndexChanged {
+		h.d.UpdateMirrorCollectors()
+	}
+
 	if reason != "" {
 		if err := ep.RegenerateWait(h.d, reason); err != nil {
 			return api.Error(PatchEndpointIDFailedCode, err)
@@ -486,9 +521,14 @@ func (d *Daemon) deleteEndpointQuiet(ep *endpoint.Endpoint, releaseIP bool) []er
 	}
 	ep.SetStateLocked(endpoint.StateDisconnecting, "Deleting endpoint")
 
+	if hooks.Enabled() {
+		hooks.Notify(hooks.EventEndpointDelete, ep.GetModelRLocked())
+	}
+
 	// Remove the endpoint before we clean up. This ensures it is no longer
 	// listed or queued for rebuilds.
 	endpointmanager.Remove(ep)
+	d.UpdateMirrorCollectors()
 
 	// If dry mode is enabled, no changes to BPF maps are performed
 	if !option.Config.DryMode {
@@ -711,6 +751,28 @@ func (h *getEndpointIDLog) Handle(params GetEndpointIDLogParams) middleware.Resp
 	}
 }
 
+type getEndpointIDControllers struct {
+	d *Daemon
+}
+
+func NewGetEndpointIDControllersHandler(d *Daemon) GetEndpointIDControllersHandler {
+	return &getEndpointIDControllers{d: d}
+}
+
+func (h *getEndpointIDControllers) Handle(params GetEndpointIDControllersParams) middleware.Responder {
+	log.WithField(logfields.EndpointID, params.ID).Debug("GET /endpoint/{id}/controllers request")
+
+	ep, err := endpointmanager.Lookup(params.ID)
+
+	if err != nil {
+		return api.Error(GetEndpointIDControllersInvalidCode, err)
+	} else if ep == nil {
+		return NewGetEndpointIDControllersNotFound()
+	} else {
+		return NewGetEndpointIDControllersOK().WithPayload(ep.GetControllersModel())
+	}
+}
+
 type getEndpointIDHealthz struct {
 	d *Daemon
 }
@@ -733,6 +795,28 @@ func (h *getEndpointIDHealthz) Handle(params GetEndpointIDHealthzParams) middlew
 	}
 }
 
+type getEndpointIDL7stats struct {
+	d *Daemon
+}
+
+func NewGetEndpointIDL7statsHandler(d *Daemon) GetEndpointIDL7statsHandler {
+	return &getEndpointIDL7stats{d: d}
+}
+
+func (h *getEndpointIDL7stats) Handle(params GetEndpointIDL7statsParams) middleware.Responder {
+	log.WithField(logfields.EndpointID, params.ID).Debug("GET /endpoint/{id}/l7stats request")
+
+	ep, err := endpointmanager.Lookup(params.ID)
+
+	if err != nil {
+		return api.Error(GetEndpointIDL7statsInvalidCode, err)
+	} else if ep == nil {
+		return NewGetEndpointIDL7statsNotFound()
+	} else {
+		return NewGetEndpointIDL7statsOK().WithPayload(ep.GetL7StatisticsModel())
+	}
+}
+
 func checkLabels(add, del labels.Labels) (addLabels, delLabels labels.Labels, ok bool) {
 	addLabels, _ = labels.FilterLabels(add)
 	delLabels, _ = labels.FilterLabels(del)
@@ -763,13 +847,13 @@ func (d *Daemon) modifyEndpointIdentityLabelsFromAPI(id string, add, del labels.
 
 	ep, err := endpointmanager.Lookup(id)
 	if err != nil {
-		return PatchEndpointIDInvalidCode, err
+		return PatchEndpointIDLabelsInvalidCode, err
 	}
 	if ep == nil {
 		return PatchEndpointIDLabelsNotFoundCode, fmt.Errorf("Endpoint ID %s not found", id)
 	}
 	if err = endpoint.APICanModify(ep); err != nil {
-		return PatchEndpointIDInvalidCode, err
+		return PatchEndpointIDLabelsInvalidCode, err
 	}
 
 	if err := ep.ModifyIdentityLabels(d, addLabels, delLabels); err != nil {
@@ -779,6 +863,29 @@ func (d *Daemon) modifyEndpointIdentityLabelsFromAPI(id string, add, del labels.
 	return PatchEndpointIDLabelsOKCode, nil
 }
 
+// userLabelsDelta returns the labels which need to be added and deleted to
+// transform the current user labels into the wanted user labels. A label
+// whose value changed is only added, as adding a label replaces the value of
+// an existing label with the same key.
+func userLabelsDelta(current, wanted labels.Labels) (add, del labels.Labels) {
+	add = labels.Labels{}
+	del = labels.Labels{}
+
+	for _, lbl := range wanted {
+		if cur := current[lbl.Key]; cur == nil || !cur.Equals(lbl) {
+			add[lbl.Key] = lbl
+		}
+	}
+
+	for _, currLbl := range current {
+		if wanted[currLbl.Key] == nil {
+			del[currLbl.Key] = currLbl
+		}
+	}
+
+	return add, del
+}
+
 type putEndpointIDLabels struct {
 	daemon *Daemon
 }
@@ -794,31 +901,21 @@ func (h *putEndpointIDLabels) Handle(params PatchEndpointIDLabelsParams) middlew
 	mod := params.Configuration
 	lbls := labels.NewLabelsFromModel(mod.User)
 
-	add := labels.Labels{}
-	del := labels.Labels{}
-
 	ep, err := endpointmanager.Lookup(params.ID)
 	if err != nil {
+		return api.Error(PatchEndpointIDLabelsInvalidCode, err)
+	}
+	if ep == nil {
 		return NewPatchEndpointIDLabelsNotFound()
 	}
 
 	if err := ep.RLockAlive(); err != nil {
-		return api.Error(PutEndpointIDInvalidCode, err)
+		return api.Error(PatchEndpointIDLabelsInvalidCode, err)
 	}
 	currentLbls := ep.OpLabels.DeepCopy()
 	ep.RUnlock()
 
-	for _, lbl := range lbls {
-		if currentLbls.Custom[lbl.Key] == nil {
-			add[lbl.Key] = lbl
-		}
-	}
-
-	for _, currLbl := range currentLbls.Custom {
-		if lbls[currLbl.Key] == nil {
-			del[currLbl.Key] = currLbl
-		}
-	}
+	add, del := userLabelsDelta(currentLbls.Custom, lbls)
 
 	// FIXME Somewhere in the code we crash if these are non-nil but length 0. We
 	// retain this behaviour here because it's easier.
@@ -835,3 +932,149 @@ func (h *putEndpointIDLabels) Handle(params PatchEndpointIDLabelsParams) middlew
 	}
 	return NewPatchEndpointIDLabelsOK()
 }
+
+// labelsOperation is a validated label modification of all endpoints
+// matching selector
+type labelsOperation struct {
+	selector labels.Labels
+	add      labels.Labels
+	del      labels.Labels
+}
+
+// parseLabelsBatch validates all operations of batch. An operation must
+// have a selector, as an empty selector would match all endpoints, and
+// must not modify reserved labels.
+func parseLabelsBatch(batch *models.EndpointLabelsBatch) ([]labelsOperation, error) {
+	if batch == nil || len(batch.Operations) == 0 {
+		return nil, fmt.Errorf("no label operations provided")
+	}
+
+	ops := make([]labelsOperation, 0, len(batch.Operations))
+	for i, op := range batch.Operations {
+		if op == nil {
+			return nil, fmt.Errorf("operation %d: empty operation", i)
+		}
+		selector := labels.NewLabelsFromModel(op.Selector)
+		if len(selector) == 0 {
+			return nil, fmt.Errorf("operation %d: selector must not be empty", i)
+		}
+		add, del, ok := checkLabels(labels.NewLabelsFromModel(op.Add), labels.NewLabelsFromModel(op.Delete))
+		if !ok {
+			return nil, fmt.Errorf("operation %d: no valid labels to add or delete", i)
+		}
+		if lbls := add.FindReserved(); lbls != nil {
+			return nil, fmt.Errorf("operation %d: not allowed to add reserved labels: %s", i, lbls)
+		} else if lbls := del.FindReserved(); lbls != nil {
+			return nil, fmt.Errorf("operation %d: not allowed to delete reserved labels: %s", i, lbls)
+		}
+		ops = append(ops, labelsOperation{selector: selector, add: add, del: del})
+	}
+
+	return ops, nil
+}
+
+type patchEndpoint struct {
+	daemon *Daemon
+}
+
+func NewPatchEndpointHandler(d *Daemon) PatchEndpointHandler {
+	return &patchEndpoint{daemon: d}
+}
+
+func (h *patchEndpoint) Handle(params PatchEndpointParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("PATCH /endpoint request")
+
+	ops, err := parseLabelsBatch(params.Batch)
+	if err != nil {
+		return api.Error(PatchEndpointInvalidCode, err)
+	}
+
+	eps := endpointmanager.GetEndpoints()
+	sort.Slice(eps, func(i, j int) bool { return eps[i].ID < eps[j].ID })
+
+	results := []*models.EndpointLabelsResult{}
+	for i, op := range ops {
+		for _, ep := range eps {
+			if !ep.HasLabels(op.selector) {
+				continue
+			}
+
+			res := &models.EndpointLabelsResult{ID: int64(ep.ID), Operation: int64(i)}
+			err := endpoint.APICanModify(ep)
+			if err == nil {
+				err = ep.ModifyIdentityLabels(h.daemon, op.add, op.del)
+			}
+			if err != nil {
+				res.Error = err.Error()
+			}
+			results = append(results, res)
+		}
+	}
+
+	return NewPatchEndpointOK().WithPayload(results)
+}
+
+type putEndpointIDQuarantine struct {
+	daemon *Daemon
+}
+
+func NewPutEndpointIDQuarantineHandler(d *Daemon) PutEndpointIDQuarantineHandler {
+	return &putEndpointIDQuarantine{daemon: d}
+}
+
+func (h *putEndpointIDQuarantine) Handle(params PutEndpointIDQuarantineParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("PUT /endpoint/{id}/quarantine request")
+
+	ep, err := endpointmanager.Lookup(params.ID)
+	if err != nil {
+		return api.Error(PutEndpointIDQuarantineInvalidCode, err)
+	}
+	if ep == nil {
+		return NewPutEndpointIDQuarantineNotFound()
+	}
+	if err := endpoint.APICanModify(ep); err != nil {
+		return api.Error(PutEndpointIDQuarantineInvalidCode, err)
+	}
+
+	q := params.Quarantine
+	if err := ep.Quarantine(h.daemon, q.Enabled, q.Reason); err != nil {
+		return api.Error(PutEndpointIDQuarantineFailedCode, err)
+	}
+
+	return NewPutEndpointIDQuarantineOK()
+}
+
+type putEndpointIDDebug struct {
+	daemon *Daemon
+}
+
+func NewPutEndpointIDDebugHandler(d *Daemon) PutEndpointIDDebugHandler {
+	return &putEndpointIDDebug{daemon: d}
+}
+
+func (h *putEndpointIDDebug) Handle(params PutEndpointIDDebugParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("PUT /endpoint/{id}/debug request")
+
+	dbg := params.Debug
+	duration := time.Duration(dbg.Duration)
+	if duration < 0 {
+		return api.Error(PutEndpointIDDebugInvalidCode, fmt.Errorf("invalid negative duration %s", duration))
+	}
+
+	ep, err := endpointmanager.Lookup(params.ID)
+	if err != nil {
+		return api.Error(PutEndpointIDDebugInvalidCode, err)
+	}
+	if ep == nil {
+		return NewPutEndpointIDDebugNotFound()
+	}
+	if err := endpoint.APICanModify(ep); err != nil {
+		return api.Error(PutEndpointIDDebugInvalidCode, err)
+	}
+
+	if err := ep.SetDebug(h.daemon, dbg.Enabled, duration); err != nil {
+		return api.Error(PutEndpointIDDebugFailedCode, err)
+	}
+
+	return NewPutEndpointIDDebugOK()
+}
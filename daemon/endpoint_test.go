@@ -132,3 +132,16 @@ func (ds *DaemonSuite) TestUpdateSecLabels(c *C) {
 	c.Assert(err, Not(IsNil))
 	c.Assert(code, Equals, apiEndpoint.PatchEndpointIDLabelsUpdateFailedCode)
 }
+
+func (ds *DaemonSuite) TestUserLabelsDelta(c *C) {
+	current := labels.NewLabelsFromModel([]string{"unspec:foo=bar", "unspec:keep=me", "unspec:old=one"})
+	wanted := labels.NewLabelsFromModel([]string{"unspec:foo=baz", "unspec:keep=me", "unspec:new=one"})
+
+	add, del := userLabelsDelta(current, wanted)
+	c.Assert(add, checker.DeepEquals, labels.NewLabelsFromModel([]string{"unspec:foo=baz", "unspec:new=one"}))
+	c.Assert(del, checker.DeepEquals, labels.NewLabelsFromModel([]string{"unspec:old=one"}))
+
+	add, del = userLabelsDelta(current, current)
+	c.Assert(len(add), Equals, 0)
+	c.Assert(len(del), Equals, 0)
+}
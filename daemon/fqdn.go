@@ -0,0 +1,167 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	. "github.com/cilium/cilium/api/v1/server/restapi/policy"
+	"github.com/cilium/cilium/pkg/api"
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/fqdn"
+	"github.com/cilium/cilium/pkg/ipcache"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/miekg/dns"
+)
+
+// notifyOnDNSResponse updates the ToFQDNs rules with the IPs of a DNS
+// response forwarded by the DNS proxy. If the rules have changed, it waits
+// until the endpoint with ID epID, which is about to receive the response,
+// has applied the resulting policy so that its connections to the IPs are not
+// dropped.
+func (d *Daemon) notifyOnDNSResponse(lookupTime time.Time, epID uint64, name string, ips []net.IP, ttl int) error {
+	oldRev := d.policy.GetRevision()
+	if err := d.dnsPoller.UpdateFromDNSResponse(lookupTime, name, ips, ttl); err != nil {
+		return err
+	}
+
+	rev := d.policy.GetRevision()
+	if epID == 0 || rev == oldRev {
+		return nil
+	}
+
+	ep := endpointmanager.LookupCiliumID(uint16(epID))
+	if ep == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaults.ToFQDNsProxyUpdateTimeout)
+	defer cancel()
+	select {
+	case <-ctx.Done():
+	case <-ep.WaitForPolicyRevision(ctx, rev):
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("endpoint %d did not apply policy revision %d within %s", epID, rev, defaults.ToFQDNsProxyUpdateTimeout)
+	}
+
+	return nil
+}
+
+// lookupIPIdentity returns the security identity of ip in the ipcache, or 0 if
+// it has none.
+func lookupIPIdentity(ip net.IP) int64 {
+	prefix := &net.IPNet{IP: ip, Mask: net.CIDRMask(net.IPv6len*8, net.IPv6len*8)}
+	if ip4 := ip.To4(); ip4 != nil {
+		prefix = &net.IPNet{IP: ip4, Mask: net.CIDRMask(net.IPv4len*8, net.IPv4len*8)}
+	}
+
+	if id, exists := ipcache.IPIdentityCache.LookupByPrefix(prefix.String()); exists {
+		return int64(id.ID)
+	}
+	return 0
+}
+
+type getFqdnCache struct {
+	daemon *Daemon
+}
+
+func newGetFqdnCacheHandler(d *Daemon) GetFqdnCacheHandler {
+	return &getFqdnCache{daemon: d}
+}
+
+func (h *getFqdnCache) Handle(params GetFqdnCacheParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /fqdn/cache request")
+
+	var names map[string]struct{}
+	if params.Endpoint != nil {
+		ep, err := endpointmanager.Lookup(*params.Endpoint)
+		if err != nil {
+			return api.Error(GetFqdnCacheInvalidCode, err)
+		} else if ep == nil {
+			return NewGetFqdnCacheNotFound()
+		}
+
+		if err := ep.RLockAlive(); err != nil {
+			return NewGetFqdnCacheNotFound()
+		}
+		var selecting []string
+		if ep.SecurityIdentity != nil {
+			selecting = h.daemon.dnsPoller.GetDNSNamesSelecting(ep.SecurityIdentity.LabelArray)
+		}
+		ep.RUnlock()
+
+		names = make(map[string]struct{}, len(selecting))
+		for _, name := range selecting {
+			names[name] = struct{}{}
+		}
+	}
+
+	matchName := ""
+	if params.Matchname != nil {
+		matchName = dns.Fqdn(*params.Matchname)
+	}
+
+	lookups := []*models.DNSLookup{}
+	for _, lookup := range fqdn.DefaultDNSCache.Dump() {
+		if matchName != "" && lookup.Name != matchName {
+			continue
+		}
+		if names != nil {
+			if _, ok := names[lookup.Name]; !ok {
+				continue
+			}
+		}
+
+		lookups = append(lookups, &models.DNSLookup{
+			Fqdn:           lookup.Name,
+			IP:             lookup.IP.String(),
+			Identity:       lookupIPIdentity(lookup.IP),
+			LookupTime:     strfmt.DateTime(lookup.LookupTime),
+			TTL:            int64(lookup.TTL),
+			ExpirationTime: strfmt.DateTime(lookup.ExpirationTime),
+		})
+	}
+
+	return NewGetFqdnCacheOK().WithPayload(lookups)
+}
+
+type deleteFqdnCache struct{}
+
+func newDeleteFqdnCacheHandler(d *Daemon) DeleteFqdnCacheHandler { return &deleteFqdnCache{} }
+
+func (h *deleteFqdnCache) Handle(params DeleteFqdnCacheParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("DELETE /fqdn/cache request")
+
+	matchName := ""
+	if params.Matchname != nil {
+		matchName = dns.Fqdn(*params.Matchname)
+	}
+
+	// The IPs retained in the generated rules are only replaced on the next
+	// poll, when the cache has been refilled with the current lookup only.
+	removed := fqdn.DefaultDNSCache.ForceExpire(matchName)
+	log.WithField(logfields.DNSName, removed).Info("Removed DNS names from ToFQDNs cache")
+
+	return NewDeleteFqdnCacheOK()
+}
@@ -0,0 +1,64 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"net"
+
+	. "github.com/cilium/cilium/api/v1/server/restapi/policy"
+	"github.com/cilium/cilium/pkg/api"
+	"github.com/cilium/cilium/pkg/ipcache"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/go-openapi/runtime/middleware"
+)
+
+type getIP struct{}
+
+func newGetIPHandler() GetIPHandler { return &getIP{} }
+
+func (h *getIP) Handle(params GetIPParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /ip request")
+
+	var cidr *net.IPNet
+	if params.Cidr != nil {
+		var err error
+		if _, cidr, err = net.ParseCIDR(*params.Cidr); err != nil {
+			return api.Error(GetIPInvalidCode, err)
+		}
+	}
+
+	return NewGetIPOK().WithPayload(ipcache.IPIdentityCache.GetIPIdentityMapModel(cidr))
+}
+
+type getIPAddr struct{}
+
+func newGetIPAddrHandler() GetIPAddrHandler { return &getIPAddr{} }
+
+func (h *getIPAddr) Handle(params GetIPAddrParams) middleware.Responder {
+	log.WithField(logfields.Params, logfields.Repr(params)).Debug("GET /ip/{addr} request")
+
+	addr := net.ParseIP(params.Addr)
+	if addr == nil {
+		return api.Error(GetIPAddrInvalidCode, fmt.Errorf("invalid IP address %q", params.Addr))
+	}
+
+	entry, exists := ipcache.IPIdentityCache.LookupByAddr(addr)
+	if !exists {
+		return NewGetIPAddrNotFound()
+	}
+	return NewGetIPAddrOK().WithPayload(entry)
+}
@@ -25,6 +25,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/annotation"
 	"github.com/cilium/cilium/pkg/comparator"
 	"github.com/cilium/cilium/pkg/controller"
@@ -598,7 +599,9 @@ func (d *Daemon) EnableK8sWatcher(reSyncPeriod time.Duration) error {
 		k8sUtils.ResourceEventHandlerFactory(
 			func(i interface{}) func() error {
 				return func() error {
-					err := d.addK8sPodV1(i.(*v1.Pod))
+					pod := i.(*v1.Pod)
+					err := d.addK8sPodV1(pod)
+					d.updatePodAnnotationOptions(nil, pod)
 					updateK8sEventMetric(metricPod, metricCreate, err == nil)
 					return nil
 				}
@@ -2038,6 +2041,62 @@ func (d *Daemon) addK8sPodV1(pod *v1.Pod) error {
 	return err
 }
 
+// podAnnotationOptions maps the pod annotations which set an option of the
+// endpoint managing the pod to the name of the option
+var podAnnotationOptions = map[string]string{
+	annotation.EgressBandwidth: option.EgressBandwidth,
+	annotation.DNSVisibility:   option.DNSVisibility,
+}
+
+// updatePodAnnotationOptions applies the options set by the annotations of
+// newPod to the endpoint managing the pod. An option is disabled if its
+// annotation was removed since oldPod.
+func (d *Daemon) updatePodAnnotationOptions(oldPod, newPod *v1.Pod) {
+	for name, opt := range podAnnotationOptions {
+		d.updatePodAnnotationOption(oldPod, newPod, name, opt)
+	}
+}
+
+// updatePodAnnotationOption applies the option opt set by the annotation name
+// of newPod to the endpoint managing the pod.
+func (d *Daemon) updatePodAnnotationOption(oldPod, newPod *v1.Pod, name, opt string) {
+	value, ok := newPod.GetAnnotations()[name]
+	if !ok {
+		if oldPod == nil {
+			return
+		}
+		if _, ok := oldPod.GetAnnotations()[name]; !ok {
+			return
+		}
+		value = "disabled"
+	}
+
+	podNSName := k8sUtils.GetObjNamespaceName(&newPod.ObjectMeta)
+	scopedLog := log.WithFields(logrus.Fields{
+		"pod":             podNSName,
+		"annotation":      name,
+		"annotationValue": value,
+	})
+
+	_, setting, err := option.ParseKeyValue(&endpoint.EndpointMutableOptionLibrary, opt, value)
+	if err != nil {
+		scopedLog.WithError(err).Warning("Ignoring invalid pod annotation")
+		return
+	}
+
+	podEP := endpointmanager.LookupPodName(podNSName)
+	if podEP == nil || podEP.Options.GetValue(opt) == setting {
+		return
+	}
+
+	cfg := &models.EndpointConfigurationSpec{
+		Options: models.ConfigurationMap{opt: value},
+	}
+	// Updating the endpoint waits for it to be ready for regeneration, do
+	// not block the watcher in the meantime.
+	podEP.UpdateAsync(d, "pod-annotation-"+opt, cfg)
+}
+
 func (d *Daemon) updateK8sPodV1(oldK8sPod, newK8sPod *v1.Pod) error {
 	if oldK8sPod == nil || newK8sPod == nil {
 		return nil
@@ -2046,6 +2105,7 @@ func (d *Daemon) updateK8sPodV1(oldK8sPod, newK8sPod *v1.Pod) error {
 	// The pod IP can never change, it can only switch from unassigned to
 	// assigned
 	d.addK8sPodV1(newK8sPod)
+	d.updatePodAnnotationOptions(oldK8sPod, newK8sPod)
 
 	// We only care about label updates
 	oldPodLabels := oldK8sPod.GetLabels()
@@ -39,6 +39,7 @@ import (
 	"github.com/cilium/cilium/pkg/endpointmanager"
 	"github.com/cilium/cilium/pkg/envoy"
 	"github.com/cilium/cilium/pkg/flowdebug"
+	"github.com/cilium/cilium/pkg/hooks"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/ipam"
 	"github.com/cilium/cilium/pkg/k8s"
@@ -47,6 +48,10 @@ import (
 	"github.com/cilium/cilium/pkg/loadinfo"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/lbmap"
+	"github.com/cilium/cilium/pkg/maps/lxcmap"
+	"github.com/cilium/cilium/pkg/maps/natmap"
+	"github.com/cilium/cilium/pkg/maps/policymap"
 	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/monitor"
 	"github.com/cilium/cilium/pkg/mtu"
@@ -58,6 +63,7 @@ import (
 	"github.com/cilium/cilium/pkg/service"
 	"github.com/cilium/cilium/pkg/version"
 	"github.com/cilium/cilium/pkg/versioncheck"
+	"github.com/cilium/cilium/pkg/watchdog"
 	"github.com/cilium/cilium/pkg/workloads"
 
 	"github.com/go-openapi/loads"
@@ -71,7 +77,7 @@ import (
 )
 
 var (
-	minKernelVer = versioncheck.MustCompile(">= 4.8.0")
+	minKernelVer = versioncheck.MinKernelVersion
 	minClangVer  = versioncheck.MustCompile(">= 3.8.0")
 
 	recKernelVer = versioncheck.MustCompile(">= 4.9.0")
@@ -111,7 +117,6 @@ var (
 	logstashProbeTimer    uint32
 	masquerade            bool
 	nat46prefix           string
-	prometheusServeAddr   string
 	socketPath            string
 	tracePayloadLen       int
 	v4Address             string
@@ -185,34 +190,12 @@ func main() {
 	}
 }
 
-func parseKernelVersion(ver string) (*go_version.Version, error) {
-	verStrs := strings.Split(ver, ".")
-	switch {
-	case len(verStrs) < 2:
-		return nil, fmt.Errorf("unable to get kernel version from %q", ver)
-	case len(verStrs) < 3:
-		verStrs = append(verStrs, "0")
-	}
-	// We are assuming the kernel version will be something as:
-	// 4.9.17-040917-generic
-
-	// If verStrs is []string{ "4", "9", "17-040917-generic" }
-	// then we need to retrieve patch number.
-	patch := regexp.MustCompilePOSIX(`^[0-9]+`).FindString(verStrs[2])
-	if patch == "" {
-		verStrs[2] = "0"
-	} else {
-		verStrs[2] = patch
-	}
-	return go_version.NewVersion(strings.Join(verStrs[:3], "."))
-}
-
 func getKernelVersion() (*go_version.Version, error) {
 	verOut, err := exec.Command("uname", "-r").CombinedOutput()
 	if err != nil {
 		log.WithError(err).Fatal("kernel version: NOT OK")
 	}
-	return parseKernelVersion(string(verOut))
+	return versioncheck.ParseKernelVersion(string(verOut))
 }
 
 func getClangVersion(filePath string) (*go_version.Version, error) {
@@ -338,6 +321,9 @@ func checkMinRequirements() {
 	checkBPFLogs("bpf_requirements", true)
 	checkBPFLogs("bpf_features", false)
 	bpf.ReadFeatureProbes(featuresFilePath)
+	if bpf.GetLRUMapType() != bpf.MapTypeLRUHash {
+		log.Warning("Kernel does not support LRU hash maps, new connections are dropped once the connection tracking table is full")
+	}
 }
 
 func init() {
@@ -346,6 +332,9 @@ func init() {
 	flags.StringVar(&option.Config.AccessLog,
 		"access-log", "", "Path to access log of supported L7 requests observed")
 	viper.BindEnv("access-log", "CILIUM_ACCESS_LOG")
+	flags.StringSliceVar(&option.Config.AccessLogSinks,
+		"access-log-sink", []string{}, "URLs of sinks to stream access log records to { syslog:// | syslog://host:port | unix:///path | kafka://broker[,broker...]/topic }")
+	viper.BindEnv("access-log-sink", "CILIUM_ACCESS_LOG_SINK")
 	flags.StringSliceVar(&option.Config.AgentLabels,
 		"agent-labels", []string{}, "Additional labels to identify this agent")
 	viper.BindEnv("access-labels", "CILIUM_ACCESS_LABELS")
@@ -355,16 +344,9 @@ func init() {
 		option.AutoIPv6NodeRoutesName, false, "Automatically adds IPv6 L3 routes to reach other nodes for non-overlay mode (--device) (BETA)")
 	flags.StringVar(&bpfRoot,
 		"bpf-root", "", "Path to BPF filesystem")
-	flags.Bool(option.BPFCompileDebugName, false, "Enable debugging of the BPF compilation process")
-	flags.Int(option.ClusterIDName, 0, "Unique identifier of the cluster")
-	viper.BindEnv(option.ClusterIDName, option.ClusterIDEnv)
-	flags.String(option.ClusterName, defaults.ClusterName, "Name of the cluster")
-	viper.BindEnv(option.ClusterName, option.ClusterNameEnv)
-	flags.String(option.ClusterMeshConfigName, "", "Path to the ClusterMesh configuration directory")
-	viper.BindEnv(option.ClusterMeshConfigName, option.ClusterMeshConfigNameEnv)
 	flags.StringVar(&cfgFile,
 		"config", "", `Configuration file (default "$HOME/ciliumd.yaml")`)
-	flags.Uint("conntrack-garbage-collector-interval", 60, "Garbage collection interval for the connection tracking table (in seconds)")
+	flags.Uint("conntrack-garbage-collector-interval", 0, "Garbage collection interval for the connection tracking table (in seconds, 0 adapts the interval to the usage of the table)")
 	flags.StringSliceVar(&option.Config.Workloads,
 		"container-runtime", []string{"auto"}, `Sets the container runtime(s) used by Cilium { containerd | crio | docker | none | auto } ( "auto" uses the container runtime found in the order: "docker", "containerd", "crio" )`)
 	flags.Var(option.NewNamedMapOptions("container-runtime-endpoints", &containerRuntimesOpts, nil),
@@ -443,7 +425,6 @@ func init() {
 		"logstash-agent", "127.0.0.1:8080", "Logstash agent address")
 	flags.Uint32Var(&logstashProbeTimer,
 		"logstash-probe-timer", 10, "Logstash probe timer (seconds)")
-	flags.Bool(option.LogSystemLoadConfigName, false, "Enable periodic logging of system load")
 	flags.StringVar(&nat46prefix,
 		"nat46-range", defaults.DefaultNAT46Prefix, "IPv6 prefix to map IPv4 addresses to")
 	flags.BoolVar(&masquerade,
@@ -452,13 +433,8 @@ func init() {
 		"Maximum interval (in seconds) between controller runs. Zero is no limit.")
 	viper.BindEnv(option.MaxCtrlIntervalName, option.MaxCtrlIntervalNameEnv)
 	flags.MarkHidden(option.MaxCtrlIntervalName)
-	flags.String(option.MonitorAggregationName, "None",
-		"Level of monitor aggregation for traces from the datapath")
-	viper.BindEnv(option.MonitorAggregationName, "CILIUM_MONITOR_AGGREGATION_LEVEL")
 	flags.IntVar(&option.Config.MTU,
 		option.MTUName, mtu.AutoDetect(), "Overwrite auto-detected MTU of underlying network")
-	flags.Bool(option.PrependIptablesChainsName, true, "Prepend custom iptables chains instead of appending")
-	viper.BindEnv(option.PrependIptablesChainsName, option.PrependIptablesChainsNameEnv)
 	flags.StringVar(&v6Address,
 		"ipv6-node", "auto", "IPv6 address of node")
 	flags.StringVar(&v4Address,
@@ -471,14 +447,10 @@ func init() {
 	flags.String("sidecar-istio-proxy-image", workloads.DefaultSidecarIstioProxyImageRegexp,
 		"Regular expression matching compatible Istio sidecar istio-proxy container image names")
 	viper.BindEnv("sidecar-istio-proxy-image", "CILIUM_SIDECAR_ISTIO_PROXY_IMAGE")
-	flags.Bool(option.SingleClusterRouteName, false,
-		"Use a single cluster route instead of per node routes")
 	flags.StringVar(&socketPath,
 		"socket-path", defaults.SockPath, "Sets daemon's socket path to listen for connections")
 	flags.StringVar(&option.Config.RunDir,
 		"state-dir", defaults.RuntimePath, "Directory path to store runtime state")
-	flags.StringP(option.TunnelName, "t", option.TunnelVXLAN, fmt.Sprintf("Tunnel mode {%s}", option.GetTunnelModes()))
-	viper.BindEnv(option.TunnelName, option.TunnelNameEnv)
 	flags.IntVar(&tracePayloadLen,
 		"trace-payloadlen", 128, "Length of payload to capture when tracing")
 	flags.Bool(
@@ -489,20 +461,6 @@ func init() {
 		"prefilter-device", "", "undefined", "Device facing external network for XDP prefiltering")
 	flags.StringVarP(&option.Config.ModePreFilter,
 		"prefilter-mode", "", option.ModePreFilterNative, "Prefilter mode { "+option.ModePreFilterNative+" | "+option.ModePreFilterGeneric+" } (default: "+option.ModePreFilterNative+")")
-	// We expect only one of the possible variables to be filled. The evaluation order is:
-	// --prometheus-serve-addr, CILIUM_PROMETHEUS_SERVE_ADDR, then PROMETHEUS_SERVE_ADDR
-	// The second environment variable (without the CILIUM_ prefix) is here to
-	// handle the case where someone uses a new image with an older spec, and the
-	// older spec used the older variable name.
-	flags.StringVar(&prometheusServeAddr,
-		"prometheus-serve-addr", "", "IP:Port on which to serve prometheus metrics (pass \":Port\" to bind on all interfaces, \"\" is off)")
-	viper.BindEnv("prometheus-serve-addr", "CILIUM_PROMETHEUS_SERVE_ADDR")
-	viper.BindEnv("prometheus-serve-addr-deprecated", "PROMETHEUS_SERVE_ADDR")
-
-	flags.Int(option.CTMapEntriesGlobalTCPName, option.CTMapEntriesGlobalTCPDefault, "Maximum number of entries in TCP CT table")
-	viper.BindEnv(option.CTMapEntriesGlobalTCPName, option.CTMapEntriesGlobalTCPNameEnv)
-	flags.Int(option.CTMapEntriesGlobalAnyName, option.CTMapEntriesGlobalAnyDefault, "Maximum number of entries in non-TCP CT table")
-	viper.BindEnv(option.CTMapEntriesGlobalAnyName, option.CTMapEntriesGlobalAnyNameEnv)
 
 	flags.StringVar(&cmdRefDir,
 		"cmdref", "", "Path to cmdref output directory")
@@ -511,6 +469,9 @@ func init() {
 	flags.IntVar(&toFQDNsMinTTL,
 		"tofqdns-min-ttl", defaults.ToFQDNsMinTTL, "The minimum time, in seconds, to use DNS data for toFQDNs policies.")
 
+	// Options described by a ConfigSpec in pkg/option
+	option.AddConfigFlags(flags)
+
 	viper.BindPFlags(flags)
 }
 
@@ -558,11 +519,29 @@ func initConfig() {
 	}
 }
 
+// writeConfigReference writes the reference of all options described by a
+// ConfigSpec to the file at path.
+func writeConfigReference(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(filePrepend(path)); err != nil {
+		return err
+	}
+	return option.WriteConfigSpecReference(f)
+}
+
 func initEnv(cmd *cobra.Command) {
 
 	// Logging should always be bootstrapped first. Do not add any code above this!
 	logging.SetupLogging(loggers, logOpts, "cilium-agent", viper.GetBool("debug"))
 
+	for _, warning := range option.MigrateRenamedOptions() {
+		log.Warn(warning)
+	}
+
 	for _, grp := range debugVerboseFlags {
 		switch grp {
 		case argDebugVerboseFlow:
@@ -584,6 +563,9 @@ func initEnv(cmd *cobra.Command) {
 		if err := doc.GenMarkdownTreeCustom(cmd, cmdRefDir, filePrepend, linkHandler); err != nil {
 			log.Fatal(err)
 		}
+		if err := writeConfigReference(filepath.Join(cmdRefDir, "cilium-agent-options.md")); err != nil {
+			log.Fatal(err)
+		}
 		os.Exit(0)
 	}
 
@@ -643,6 +625,7 @@ func initEnv(cmd *cobra.Command) {
 	if err := os.MkdirAll(option.Config.StateDir, defaults.StateDirRights); err != nil {
 		scopedLog.WithError(err).Fatal("Could not create state directory")
 	}
+	bpf.SetSchemaDir(filepath.Join(option.Config.StateDir, defaults.MapSchemaDir))
 
 	if err := os.MkdirAll(option.Config.LibDir, defaults.RuntimePathRights); err != nil {
 		scopedLog.WithError(err).Fatal("Could not create library directory")
@@ -697,11 +680,29 @@ func initEnv(cmd *cobra.Command) {
 		scopedLog.WithError(err).Fatal("Cannot remove existing Cilium sock")
 	}
 
+	// Maps are opened as soon as the BPF filesystem is mounted, their
+	// sizes must be set before. Existing maps of a different size are
+	// migrated when they are opened.
+	if err := option.Config.ValidateMapSizes(); err != nil {
+		log.WithError(err).Fatal("Invalid BPF map size")
+	}
+	policymap.SetMaxEntries(option.Config.PolicyMapEntries)
+	lxcmap.SetMaxEntries(option.Config.LXCMapEntries)
+	lbmap.SetMaxEntries(option.Config.LBMapEntries)
+
 	// The standard operation is to mount the BPF filesystem to the
 	// standard location (/sys/fs/bpf). The user may chose to specify
 	// the path to an already mounted filesystem instead. This is
 	// useful if the daemon is being round inside a namespace and the
-	// BPF filesystem is mapped into the slave namespace.
+	// BPF filesystem is mapped into the slave namespace. All BPF objects
+	// are pinned in the optional pin prefix directory of the filesystem,
+	// which allows multiple agents to share a host.
+	pinPrefix := viper.GetString(option.BPFPinPrefixName)
+	if err := option.ValidatePinPrefix(pinPrefix); err != nil {
+		log.WithError(err).Fatal("Invalid BPF pin prefix")
+	}
+	bpf.SetPinPrefix(pinPrefix)
+	bpf.SetMapEventsEnabled(viper.GetBool(option.BPFMapEventsName))
 	bpf.CheckOrMountFS(bpfRoot)
 
 	logging.DefaultLogLevel = defaults.DefaultLogLevel
@@ -747,6 +748,34 @@ func initEnv(cmd *cobra.Command) {
 
 	option.Config.NAT46Prefix = r
 
+	if viper.GetBool(option.BPFMasqueradeName) {
+		switch {
+		case !masquerade:
+			log.Fatalf("--%s requires --masquerade", option.BPFMasqueradeName)
+		case option.Config.IPv4Disabled:
+			log.Fatalf("--%s requires IPv4", option.BPFMasqueradeName)
+		case option.Config.Device == "undefined":
+			log.Fatalf("--%s requires --device", option.BPFMasqueradeName)
+		case option.Config.IsLBEnabled():
+			log.Fatalf("--%s cannot be combined with --lb", option.BPFMasqueradeName)
+		}
+		if err := natmap.CheckLocalPortRange(); err != nil {
+			log.WithError(err).Fatalf("--%s requires net.ipv4.ip_local_port_range to end below %d",
+				option.BPFMasqueradeName, natmap.MinPort)
+		}
+	}
+
+	if viper.GetBool(option.NodeNeighborTableName) {
+		switch {
+		case option.Config.IPv4Disabled:
+			log.Fatalf("--%s requires IPv4", option.NodeNeighborTableName)
+		case option.Config.Device == "undefined":
+			log.Fatalf("--%s requires --device", option.NodeNeighborTableName)
+		case option.Config.Tunnel != option.TunnelDisabled:
+			log.Fatalf("--%s requires --tunnel=%s", option.NodeNeighborTableName, option.TunnelDisabled)
+		}
+	}
+
 	// If device has been specified, use it to derive better default
 	// allocation prefixes
 	if option.Config.Device != "undefined" {
@@ -835,6 +864,12 @@ func runCiliumHealthEndpoint(d *Daemon) error {
 }
 
 func runDaemon() {
+	endpointHooks, err := hooks.ParseHooks(option.Config.EndpointHooks)
+	if err != nil {
+		log.WithError(err).Fatalf("Invalid value for option --%s", option.EndpointHooksName)
+	}
+	hooks.Configure(endpointHooks)
+
 	log.Info("Initializing daemon")
 	d, restoredEndpoints, err := NewDaemon()
 	if err != nil {
@@ -844,9 +879,17 @@ func runDaemon() {
 
 	log.Info("Starting connection tracking garbage collector")
 	endpointmanager.EnableConntrackGC(!option.Config.IPv4Disabled, true,
+		viper.GetBool(option.BPFMasqueradeName),
 		viper.GetInt("conntrack-garbage-collector-interval"),
 		restoredEndpoints.restored)
 
+	if viper.GetBool(option.NodeNeighborTableName) {
+		log.Info("Starting node neighbor table")
+		if err := node.EnableNeighborTable(option.Config.Device); err != nil {
+			log.WithError(err).Fatal("Unable to start node neighbor table")
+		}
+	}
+
 	if enableLogstash {
 		log.Info("Enabling Logstash")
 		go EnableLogstash(logstashAddr, int(logstashProbeTimer))
@@ -855,6 +898,24 @@ func runDaemon() {
 	log.Info("Launching node monitor daemon")
 	go d.nodeMonitor.Run(path.Join(defaults.RuntimePath, defaults.EventsPipe), bpf.GetMapRoot())
 
+	watchdogActions, err := watchdog.ParseActions(option.Config.WatchdogActions)
+	if err != nil {
+		log.WithError(err).Fatalf("Invalid value for option --%s", option.WatchdogActionsName)
+	}
+	d.startWatchdog(watchdog.Budget{
+		RSS:        uint64(option.Config.WatchdogRSSBudget) << 20,
+		Goroutines: option.Config.WatchdogGoroutineBudget,
+		MapMemory:  uint64(option.Config.WatchdogMapMemoryBudget) << 20,
+	}, watchdogActions)
+	bpf.RegisterPressureSampler(endpointmanager.SamplePolicyMapPressure)
+	bpf.StartMapPressureSampler()
+
+	if interval := viper.GetInt(option.BPFMapCheckIntervalName); interval > 0 {
+		log.Info("Starting BPF map consistency checker")
+		endpointmanager.EnableMapConsistencyCheck(time.Duration(interval)*time.Second,
+			viper.GetBool(option.BPFMapRepairName))
+	}
+
 	if err := d.EnableK8sWatcher(5 * time.Minute); err != nil {
 		log.WithError(err).Fatal("Unable to establish connection to Kubernetes apiserver")
 	}
@@ -909,7 +970,7 @@ func runDaemon() {
 		workloads.IgnoreRunningWorkloads()
 	}
 
-	d.collectStaleMapGarbage()
+	d.startStaleMapGC()
 
 	// The workload event listener *must* be enabled *after* restored endpoints
 	// are added into the endpoint manager; otherwise, updates to important
@@ -974,10 +1035,7 @@ func runDaemon() {
 		log.WithError(err).Fatal("Cannot load swagger spec")
 	}
 
-	promAddr := viper.GetString("prometheus-serve-addr")
-	if promAddr == "" {
-		promAddr = viper.GetString("prometheus-serve-addr-deprecated")
-	}
+	promAddr := viper.GetString(option.PrometheusServeAddrName)
 	if promAddr != "" {
 		log.Infof("Serving prometheus metrics on %s", promAddr)
 		if err := metrics.Enable(promAddr); err != nil {
@@ -993,12 +1051,16 @@ func runDaemon() {
 	// /healthz/
 	api.DaemonGetHealthzHandler = NewGetHealthzHandler(d)
 
+	// /capabilities/
+	api.DaemonGetCapabilitiesHandler = NewGetCapabilitiesHandler(d, swaggerSpec.Spec().Info.Version)
+
 	// /config/
 	api.DaemonGetConfigHandler = NewGetConfigHandler(d)
 	api.DaemonPatchConfigHandler = NewPatchConfigHandler(d)
 
 	// /endpoint/
 	api.EndpointGetEndpointHandler = NewGetEndpointHandler(d)
+	api.EndpointPatchEndpointHandler = NewPatchEndpointHandler(d)
 
 	// /endpoint/{id}
 	api.EndpointGetEndpointIDHandler = NewGetEndpointIDHandler(d)
@@ -1017,13 +1079,29 @@ func runDaemon() {
 	// /endpoint/{id}/log/
 	api.EndpointGetEndpointIDLogHandler = NewGetEndpointIDLogHandler(d)
 
+	// /endpoint/{id}/controllers
+	api.EndpointGetEndpointIDControllersHandler = NewGetEndpointIDControllersHandler(d)
+
+	// /endpoint/{id}/debug
+	api.EndpointPutEndpointIDDebugHandler = NewPutEndpointIDDebugHandler(d)
+
+	// /endpoint/{id}/quarantine
+	api.EndpointPutEndpointIDQuarantineHandler = NewPutEndpointIDQuarantineHandler(d)
+
 	// /endpoint/{id}/healthz
 	api.EndpointGetEndpointIDHealthzHandler = NewGetEndpointIDHealthzHandler(d)
 
+	// /endpoint/{id}/l7stats
+	api.EndpointGetEndpointIDL7statsHandler = NewGetEndpointIDL7statsHandler(d)
+
 	// /identity/
 	api.PolicyGetIdentityHandler = newGetIdentityHandler(d)
 	api.PolicyGetIdentityIDHandler = newGetIdentityIDHandler(d)
 
+	// /ip/
+	api.PolicyGetIPHandler = newGetIPHandler()
+	api.PolicyGetIPAddrHandler = newGetIPAddrHandler()
+
 	// /policy/
 	api.PolicyGetPolicyHandler = newGetPolicyHandler(d)
 	api.PolicyPutPolicyHandler = newPutPolicyHandler(d)
@@ -1032,6 +1110,10 @@ func runDaemon() {
 	// /policy/resolve/
 	api.PolicyGetPolicyResolveHandler = NewGetPolicyResolveHandler(d)
 
+	// /fqdn/cache/
+	api.PolicyGetFqdnCacheHandler = newGetFqdnCacheHandler(d)
+	api.PolicyDeleteFqdnCacheHandler = newDeleteFqdnCacheHandler(d)
+
 	// /service/{id}/
 	api.ServiceGetServiceIDHandler = NewGetServiceIDHandler(d)
 	api.ServiceDeleteServiceIDHandler = NewDeleteServiceIDHandler(d)
@@ -1055,6 +1137,7 @@ func runDaemon() {
 	// /map
 	api.DaemonGetMapHandler = NewGetMapHandler(d)
 	api.DaemonGetMapNameHandler = NewGetMapNameHandler(d)
+	api.DaemonGetMapNameEventsHandler = NewGetMapNameEventsHandler(d)
 
 	// metrics
 	api.MetricsGetMetricsHandler = NewGetMetricsHandler(d)
@@ -54,3 +54,31 @@ func (h *getMap) Handle(params restapi.GetMapParams) middleware.Responder {
 
 	return restapi.NewGetMapOK().WithPayload(mapList)
 }
+
+type getMapNameEvents struct {
+	daemon *Daemon
+}
+
+func NewGetMapNameEventsHandler(d *Daemon) restapi.GetMapNameEventsHandler {
+	return &getMapNameEvents{daemon: d}
+}
+
+func (h *getMapNameEvents) Handle(params restapi.GetMapNameEventsParams) middleware.Responder {
+	journal := bpf.GetMapEvents(params.Name)
+	if journal == nil {
+		return restapi.NewGetMapNameEventsNotFound()
+	}
+
+	var since uint64
+	if params.Since != nil && *params.Since > 0 {
+		since = uint64(*params.Since)
+	}
+
+	events := journal.Since(since)
+	payload := make([]*models.BPFMapEvent, 0, len(events))
+	for i := range events {
+		payload = append(payload, events[i].GetModel())
+	}
+
+	return restapi.NewGetMapNameEventsOK().WithPayload(payload)
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+	"github.com/cilium/cilium/pkg/maps/throttlemap"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+// staleMapGCInterval is the interval in which pinned maps of endpoints which
+// no longer exist are removed
+const staleMapGCInterval = 10 * time.Minute
+
+// endpointMapPrefixes are the prefixes of the names of maps pinned for each
+// endpoint, followed by the endpoint ID
+var endpointMapPrefixes = []string{
+	policymap.MapName,
+	endpoint.CallsMapName,
+	throttlemap.MapName,
+	ctmap.MapNameTCP6,
+	ctmap.MapNameTCP4,
+	ctmap.MapNameAny6,
+	ctmap.MapNameAny4,
+}
+
+// staleMapGC tracks the removal of pinned maps of endpoints which no longer
+// exist
+type staleMapGC struct {
+	mutex lock.Mutex
+
+	// removed is the number of maps removed since the agent started
+	removed int
+
+	// failed are the paths of the maps which could not be removed in the
+	// last run
+	failed []string
+
+	// lastRun is the time of the last run, zero if it has not run yet
+	lastRun time.Time
+}
+
+// record records the result of a run
+func (g *staleMapGC) record(removed int, failed []string) {
+	g.mutex.Lock()
+	g.removed += removed
+	g.failed = failed
+	g.lastRun = time.Now()
+	g.mutex.Unlock()
+}
+
+// getStatus returns a summary of the removal of stale maps and whether maps
+// could not be removed in the last run. The summary is empty if no run has
+// completed yet.
+func (g *staleMapGC) getStatus() (string, bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.lastRun.IsZero() {
+		return "", true
+	}
+	if len(g.failed) > 0 {
+		return fmt.Sprintf("%d stale endpoint maps removed, unable to remove %s",
+			g.removed, strings.Join(g.failed, ", ")), false
+	}
+	return fmt.Sprintf("%d stale endpoint maps removed", g.removed), true
+}
+
+// endpointMapID returns the endpoint ID in the name of a map pinned for an
+// endpoint. Returns false if filename is not the name of such a map.
+func endpointMapID(filename string) (uint16, bool) {
+	for _, prefix := range endpointMapPrefixes {
+		if !strings.HasPrefix(filename, prefix) {
+			continue
+		}
+		if id, err := strconv.ParseUint(strings.TrimPrefix(filename, prefix), 0, 16); err == nil {
+			return uint16(id), true
+		}
+	}
+	return 0, false
+}
+
+// startStaleMapGC starts a controller which removes the pinned maps of
+// endpoints which no longer exist. The first run happens immediately, so it
+// must be started after restored endpoints have been added to the endpoint
+// manager. Endpoints are added to the endpoint manager before their maps are
+// created, so the maps of endpoints being created are never removed.
+func (d *Daemon) startStaleMapGC() {
+	if option.Config.DryMode {
+		return
+	}
+
+	controller.NewManager().UpdateController("stale-map-gc",
+		controller.ControllerParams{
+			DoFunc:      d.collectStaleMapGarbage,
+			RunInterval: staleMapGCInterval,
+		})
+}
+
+// collectStaleMapGarbage removes the pinned maps of endpoints which no longer
+// exist. Global maps are never removed, as they are in use by the datapath
+// even while no endpoint exists.
+func (d *Daemon) collectStaleMapGarbage() error {
+	var (
+		removed int
+		failed  []string
+	)
+
+	walker := func(path string, _ os.FileInfo, _ error) error {
+		id, isEndpointMap := endpointMapID(filepath.Base(path))
+		if !isEndpointMap || endpointmanager.LookupCiliumID(id) != nil {
+			return nil
+		}
+		d.removeStaleIDFromPolicyMap(uint32(id))
+
+		if err := d.removeStaleMap(path); err != nil {
+			failed = append(failed, filepath.Base(path))
+		} else {
+			removed++
+		}
+		return nil
+	}
+
+	err := filepath.Walk(bpf.MapPrefixPath(), walker)
+	d.staleMaps.record(removed, failed)
+	if err != nil {
+		log.WithError(err).Warn("Error while scanning for stale maps")
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to remove stale maps %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func (d *Daemon) removeStaleMap(path string) error {
+	if err := os.RemoveAll(path); err != nil {
+		log.WithError(err).WithField(logfields.Path, path).Warn("Error while deleting stale map file")
+		return err
+	}
+	log.WithField(logfields.Path, path).Info("Removed stale bpf map")
+	return nil
+}
+
+func (d *Daemon) removeStaleIDFromPolicyMap(id uint32) {
+	gpm, err := policymap.OpenGlobalMap(bpf.MapPath(endpoint.PolicyGlobalMapName))
+	if err == nil {
+		gpm.Delete(id, policymap.AllPorts, u8proto.All, policymap.Ingress)
+		gpm.Delete(id, policymap.AllPorts, u8proto.All, policymap.Egress)
+		gpm.Close()
+	}
+}
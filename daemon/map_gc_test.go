@@ -0,0 +1,63 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (ds *DaemonSuite) TestEndpointMapID(c *C) {
+	for name, id := range map[string]uint16{
+		"cilium_policy_1234":  1234,
+		"cilium_calls_42":     42,
+		"cilium_ct4_7":        7,
+		"cilium_ct_any6_4095": 4095,
+	} {
+		got, ok := endpointMapID(name)
+		c.Assert(ok, Equals, true, Commentf("%s", name))
+		c.Assert(got, Equals, id, Commentf("%s", name))
+	}
+
+	for _, name := range []string{
+		"cilium_ct4_global",
+		"cilium_ct_any6_global",
+		"cilium_policy",
+		"cilium_lxc",
+		"cilium_calls_netdev_ns_1",
+		"cilium_policy_99999",
+	} {
+		_, ok := endpointMapID(name)
+		c.Assert(ok, Equals, false, Commentf("%s", name))
+	}
+}
+
+func (ds *DaemonSuite) TestStaleMapGCStatus(c *C) {
+	var gc staleMapGC
+
+	msg, ok := gc.getStatus()
+	c.Assert(msg, Equals, "")
+	c.Assert(ok, Equals, true)
+
+	gc.record(3, nil)
+	gc.record(1, []string{"cilium_calls_12"})
+	msg, ok = gc.getStatus()
+	c.Assert(msg, Equals, "4 stale endpoint maps removed, unable to remove cilium_calls_12")
+	c.Assert(ok, Equals, false)
+
+	gc.record(0, nil)
+	msg, ok = gc.getStatus()
+	c.Assert(msg, Equals, "4 stale endpoint maps removed")
+	c.Assert(ok, Equals, true)
+}
@@ -0,0 +1,150 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/endpointmanager"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/lock"
+	policyApi "github.com/cilium/cilium/pkg/policy/api"
+)
+
+// mirrorCollector is a local endpoint which traffic can be mirrored to
+type mirrorCollector struct {
+	labels  labels.LabelArray
+	ifIndex int
+}
+
+// mirrorCollectors caches the identity labels and interface index of all
+// local endpoints. Endpoints resolve their mirror collector while holding
+// their own lock, the cache allows them to do so without locking any other
+// endpoint.
+type mirrorCollectors struct {
+	mutex lock.RWMutex
+
+	// endpoints maps the ID of each local endpoint with an identity and
+	// an interface index to the endpoint
+	endpoints map[uint16]mirrorCollector
+
+	// controllers runs the synchronization of the cache with the local
+	// endpoints
+	controllers controller.Manager
+}
+
+// lookup returns the interface index of the endpoint with the lowest ID
+// selected by selector.
+func (m *mirrorCollectors) lookup(selector policyApi.EndpointSelector) (int, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var foundID uint16
+	ifIndex := 0
+	for id, c := range m.endpoints {
+		if ifIndex != 0 && id > foundID {
+			continue
+		}
+		if selector.Matches(c.labels) {
+			foundID, ifIndex = id, c.ifIndex
+		}
+	}
+	return ifIndex, ifIndex != 0
+}
+
+// replace replaces the cached endpoints and returns true if they changed.
+func (m *mirrorCollectors) replace(endpoints map[uint16]mirrorCollector) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if reflect.DeepEqual(m.endpoints, endpoints) {
+		return false
+	}
+	m.endpoints = endpoints
+	return true
+}
+
+// getMirrorCollectors returns the identity labels and interface index of
+// all local endpoints. Must be called without any endpoint lock held.
+func getMirrorCollectors() map[uint16]mirrorCollector {
+	collectors := make(map[uint16]mirrorCollector)
+	for _, ep := range endpointmanager.GetEndpoints() {
+		if err := ep.RLockAlive(); err != nil {
+			continue
+		}
+		if ep.SecurityIdentity != nil && ep.IfIndex != 0 {
+			collectors[ep.ID] = mirrorCollector{
+				labels:  ep.SecurityIdentity.LabelArray,
+				ifIndex: ep.IfIndex,
+			}
+		}
+		ep.RUnlock()
+	}
+	return collectors
+}
+
+// mirrorsToEndpoint returns true if ep mirrors its traffic to a local
+// endpoint. Must be called without any endpoint lock held.
+func mirrorsToEndpoint(ep *endpoint.Endpoint) bool {
+	if err := ep.RLockAlive(); err != nil {
+		return false
+	}
+	defer ep.RUnlock()
+	return ep.MirrorPolicy != nil && ep.MirrorPolicy.Collector.Endpoint != nil
+}
+
+// regenerateMirroringEndpoint regenerates ep to update the interface index
+// of the local endpoint it mirrors its traffic to.
+func regenerateMirroringEndpoint(owner endpoint.Owner, ep *endpoint.Endpoint) {
+	if err := ep.LockAlive(); err != nil {
+		return
+	}
+	regen := ep.SetStateLocked(endpoint.StateWaitingToRegenerate, "Triggering endpoint regeneration due to mirror collector change")
+	ep.Unlock()
+	if regen {
+		ep.Regenerate(owner, endpoint.NewRegenerationContext("mirror collector change"))
+	}
+}
+
+// LookupEndpointIfIndex returns the interface index of the local endpoint with
+// the lowest ID selected by selector.
+func (d *Daemon) LookupEndpointIfIndex(selector policyApi.EndpointSelector) (int, bool) {
+	return d.mirrorCollectors.lookup(selector)
+}
+
+// UpdateMirrorCollectors resynchronizes the local endpoints which traffic can
+// be mirrored to in the background. All endpoints mirroring traffic to a local
+// endpoint are regenerated if any local endpoint has been added or removed, or
+// its identity or interface index has changed.
+func (d *Daemon) UpdateMirrorCollectors() {
+	d.mirrorCollectors.controllers.UpdateController("mirror-collectors",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				if !d.mirrorCollectors.replace(getMirrorCollectors()) {
+					return nil
+				}
+
+				for _, ep := range endpointmanager.GetEndpoints() {
+					if mirrorsToEndpoint(ep) {
+						regenerateMirroringEndpoint(d, ep)
+					}
+				}
+				return nil
+			},
+		},
+	)
+}
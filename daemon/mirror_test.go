@@ -0,0 +1,57 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ds *DaemonSuite) TestMirrorCollectorsLookup(c *C) {
+	collector := api.NewESFromLabels(labels.ParseSelectLabel("app=collector"))
+
+	m := &mirrorCollectors{}
+	_, ok := m.lookup(collector)
+	c.Assert(ok, Equals, false)
+
+	c.Assert(m.replace(map[uint16]mirrorCollector{
+		1: {labels: labels.ParseLabelArray("app=web"), ifIndex: 10},
+		7: {labels: labels.ParseLabelArray("app=collector"), ifIndex: 70},
+		3: {labels: labels.ParseLabelArray("app=collector"), ifIndex: 30},
+	}), Equals, true)
+
+	// The selected endpoint with the lowest ID is the collector
+	ifIndex, ok := m.lookup(collector)
+	c.Assert(ok, Equals, true)
+	c.Assert(ifIndex, Equals, 30)
+
+	// Unchanged endpoints don't require regenerations
+	c.Assert(m.replace(map[uint16]mirrorCollector{
+		1: {labels: labels.ParseLabelArray("app=web"), ifIndex: 10},
+		7: {labels: labels.ParseLabelArray("app=collector"), ifIndex: 70},
+		3: {labels: labels.ParseLabelArray("app=collector"), ifIndex: 30},
+	}), Equals, false)
+
+	// The collector has been deleted
+	c.Assert(m.replace(map[uint16]mirrorCollector{
+		1: {labels: labels.ParseLabelArray("app=web"), ifIndex: 10},
+		7: {labels: labels.ParseLabelArray("app=collector"), ifIndex: 70},
+	}), Equals, true)
+	ifIndex, ok = m.lookup(collector)
+	c.Assert(ok, Equals, true)
+	c.Assert(ifIndex, Equals, 70)
+}
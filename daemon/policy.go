@@ -74,6 +74,10 @@ func (h *getPolicyResolve) Handle(params GetPolicyResolveParams) middleware.Resp
 
 	d.policy.Mutex.RLock()
 
+	matchedRules, l4 := d.getPolicyTraceDetailsRLocked(
+		labels.NewSelectLabelArrayFromModel(params.TraceSelector.From.Labels),
+		labels.NewSelectLabelArrayFromModel(params.TraceSelector.To.Labels))
+
 	// If policy enforcement isn't enabled, then traffic is allowed.
 	if policy.GetPolicyEnabled() == option.NeverEnforce {
 		policyEnforcementMsg = "Policy enforcement is disabled for the daemon."
@@ -113,8 +117,10 @@ func (h *getPolicyResolve) Handle(params GetPolicyResolveParams) middleware.Resp
 		searchCtx.PolicyTrace("Label verdict: %s\n", verdict)
 		msg := fmt.Sprintf("%s\n  %s\n%s", searchCtx.String(), policyEnforcementMsg, buffer.String())
 		return NewGetPolicyResolveOK().WithPayload(&models.PolicyTraceResult{
-			Log:     msg,
-			Verdict: verdict,
+			Log:          msg,
+			Verdict:      verdict,
+			MatchedRules: matchedRules,
+			L4:           l4,
 		})
 	}
 
@@ -147,13 +153,35 @@ func (h *getPolicyResolve) Handle(params GetPolicyResolveParams) middleware.Resp
 	d.policy.Mutex.RUnlock()
 
 	result := models.PolicyTraceResult{
-		Verdict: ingressVerdict.String(),
-		Log:     ingressBuffer.String(),
+		Verdict:        ingressVerdict.String(),
+		Log:            ingressBuffer.String(),
+		PolicyEnforced: true,
+		MatchedRules:   matchedRules,
+		L4:             l4,
 	}
 
 	return NewGetPolicyResolveOK().WithPayload(&result)
 }
 
+// getPolicyTraceDetailsRLocked returns the labels of the rules allowing
+// ingress from the source to the destination of a policy trace and the L4
+// and L7 ingress filters applying to traffic from the source to the
+// destination. The filters are nil if they cannot be resolved.
+//
+// Must be called with d.policy.Mutex held for reading.
+func (d *Daemon) getPolicyTraceDetailsRLocked(from, to labels.LabelArray) ([][]string, *models.L4Policy) {
+	matchedRules := d.policy.GetIngressRuleLabelsMatching(from, to).GetModel()
+
+	ingress, err := d.policy.ResolveL4IngressPolicy(&policy.SearchContext{From: from, To: to})
+	if err != nil {
+		log.WithError(err).Warning("Unable to resolve L4 ingress policy for policy trace")
+		return matchedRules, nil
+	}
+	l4 := &policy.L4Policy{Ingress: ingress.AllowingSource(from), Egress: policy.L4PolicyMap{}}
+
+	return matchedRules, l4.GetModel()
+}
+
 // AddOptions are options which can be passed to PolicyAdd
 type AddOptions struct {
 	// Replace if true indicates that existing rules with identical labels should be replaced
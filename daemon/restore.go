@@ -0,0 +1,196 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/endpoint"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+)
+
+// restorePriority describes how urgently an endpoint must be restored.
+type restorePriority struct {
+	// running is true if the endpoint is associated with a running
+	// workload
+	running bool
+
+	// activity is the number of packets the endpoint has exchanged
+	// before the restart, 0 if unknown
+	activity uint64
+}
+
+// prioritized returns true if the endpoint is restored before all endpoints
+// which are not prioritized. Endpoints which are not associated with a
+// running workload are candidates for stale endpoints and are not
+// prioritized, regardless of their activity.
+func (p restorePriority) prioritized() bool {
+	return p.running
+}
+
+// endpointActivity returns the number of packets which have been subject to
+// the policy of the endpoint before the restart, according to the statistics
+// of the policy map of the endpoint. Returns 0 if the policy map can't be
+// read. Must be called with ep.Mutex held.
+func endpointActivity(ep *endpoint.Endpoint) uint64 {
+	pm, err := policymap.OpenGlobalMap(ep.PolicyMapPathLocked())
+	if err != nil {
+		return 0
+	}
+	defer pm.Close()
+
+	entries, err := pm.DumpToSlice()
+	if err != nil {
+		return 0
+	}
+
+	var packets uint64
+	for _, entry := range entries {
+		packets += entry.Packets
+	}
+	return packets
+}
+
+// sortByRestorePriority sorts the endpoints to restore by decreasing
+// priority and returns the number of prioritized endpoints, which are
+// sorted by decreasing activity. The order of the other endpoints is kept.
+func sortByRestorePriority(eps []*endpoint.Endpoint, priorities map[*endpoint.Endpoint]restorePriority) int {
+	sort.SliceStable(eps, func(i, j int) bool {
+		pi, pj := priorities[eps[i]], priorities[eps[j]]
+		if pi.prioritized() != pj.prioritized() {
+			return pi.prioritized()
+		}
+		return pi.prioritized() && pi.activity > pj.activity
+	})
+
+	n := 0
+	for n < len(eps) && priorities[eps[n]].prioritized() {
+		n++
+	}
+	return n
+}
+
+// restoreQueue admits restored endpoints to the regeneration in the order of
+// their rank, with at most a fixed number of endpoints being regenerated at
+// the same time. Endpoints which are not waiting yet when a slot becomes
+// available are skipped.
+type restoreQueue struct {
+	mutex   lock.Mutex
+	free    int
+	waiting restoreWaiters
+}
+
+func newRestoreQueue(slots int) *restoreQueue {
+	return &restoreQueue{free: slots}
+}
+
+// wait blocks until the endpoint with the given rank is admitted.
+func (q *restoreQueue) wait(rank int) {
+	q.mutex.Lock()
+	if q.free > 0 {
+		q.free--
+		q.mutex.Unlock()
+		return
+	}
+	w := &restoreWaiter{rank: rank, admitted: make(chan struct{})}
+	heap.Push(&q.waiting, w)
+	q.mutex.Unlock()
+
+	<-w.admitted
+}
+
+// done releases the slot of an admitted endpoint to the waiting endpoint
+// with the lowest rank.
+func (q *restoreQueue) done() {
+	q.mutex.Lock()
+	if len(q.waiting) > 0 {
+		close(heap.Pop(&q.waiting).(*restoreWaiter).admitted)
+	} else {
+		q.free++
+	}
+	q.mutex.Unlock()
+}
+
+type restoreWaiter struct {
+	rank     int
+	admitted chan struct{}
+}
+
+// restoreWaiters implements heap.Interface ordered by rank.
+type restoreWaiters []*restoreWaiter
+
+func (w restoreWaiters) Len() int           { return len(w) }
+func (w restoreWaiters) Less(i, j int) bool { return w[i].rank < w[j].rank }
+func (w restoreWaiters) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
+
+func (w *restoreWaiters) Push(x interface{}) {
+	*w = append(*w, x.(*restoreWaiter))
+}
+
+func (w *restoreWaiters) Pop() interface{} {
+	old := *w
+	n := len(old)
+	x := old[n-1]
+	*w = old[:n-1]
+	return x
+}
+
+// endpointRestoreProgress tracks the progress of the regeneration of the
+// restored endpoints for the status API.
+type endpointRestoreProgress struct {
+	mutex  lock.Mutex
+	status *models.EndpointRestoreStatus
+}
+
+func (p *endpointRestoreProgress) start(total, deferred int) {
+	p.mutex.Lock()
+	p.status = &models.EndpointRestoreStatus{
+		State:    models.EndpointRestoreStatusStateRestoring,
+		Total:    int64(total),
+		Deferred: int64(deferred),
+	}
+	p.mutex.Unlock()
+}
+
+func (p *endpointRestoreProgress) update(success bool) {
+	p.mutex.Lock()
+	if success {
+		p.status.Regenerated++
+	} else {
+		p.status.Failed++
+	}
+	p.mutex.Unlock()
+}
+
+func (p *endpointRestoreProgress) complete() {
+	p.mutex.Lock()
+	p.status.State = models.EndpointRestoreStatusStateComplete
+	p.mutex.Unlock()
+}
+
+// getModel returns the progress of the restoration, nil if no endpoints are
+// being restored.
+func (p *endpointRestoreProgress) getModel() *models.EndpointRestoreStatus {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.status == nil {
+		return nil
+	}
+	status := *p.status
+	return &status
+}
@@ -0,0 +1,72 @@
+// Copyright 2016-2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/endpoint"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ds *DaemonSuite) TestSortByRestorePriority(c *C) {
+	idle := &endpoint.Endpoint{ID: 1}
+	busy := &endpoint.Endpoint{ID: 2}
+	stale := &endpoint.Endpoint{ID: 3}
+	active := &endpoint.Endpoint{ID: 4}
+	eps := []*endpoint.Endpoint{idle, busy, stale, active}
+	priorities := map[*endpoint.Endpoint]restorePriority{
+		idle:   {running: true},
+		busy:   {running: true, activity: 1000},
+		stale:  {activity: 5000},
+		active: {running: true, activity: 10},
+	}
+
+	n := sortByRestorePriority(eps, priorities)
+	c.Assert(n, Equals, 3)
+	c.Assert(eps, DeepEquals, []*endpoint.Endpoint{busy, active, idle, stale})
+}
+
+func (ds *DaemonSuite) TestRestoreQueue(c *C) {
+	q := newRestoreQueue(1)
+	q.wait(5)
+
+	admitted := make(chan int, 3)
+	for _, rank := range []int{3, 1, 2} {
+		go func(rank int) {
+			q.wait(rank)
+			admitted <- rank
+		}(rank)
+	}
+
+	// Wait for all endpoints to be queued
+	for i := 0; i < 100; i++ {
+		q.mutex.Lock()
+		n := len(q.waiting)
+		q.mutex.Unlock()
+		if n == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, rank := range []int{1, 2, 3} {
+		q.done()
+		c.Assert(<-admitted, Equals, rank)
+	}
+	q.done()
+	c.Assert(q.free, Equals, 1)
+}
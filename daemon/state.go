@@ -20,6 +20,7 @@ import (
 	"net"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cilium/cilium/common"
@@ -41,8 +42,12 @@ import (
 )
 
 type endpointRestoreState struct {
-	restored []*endpoint.Endpoint
-	toClean  []*endpoint.Endpoint
+	// restored holds the endpoints to restore in the order of their
+	// priority, the first numPrioritized endpoints back running
+	// workloads, the ones with the most traffic before the restart first.
+	restored       []*endpoint.Endpoint
+	numPrioritized int
+	toClean        []*endpoint.Endpoint
 }
 
 // restoreOldEndpoints reads the list of existing endpoints previously managed
@@ -53,7 +58,8 @@ type endpointRestoreState struct {
 // regenerateRestoredEndpoints() once the endpoint builder is ready.
 //
 // If clean is true, endpoints which cannot be associated with a container
-// workloads are deleted.
+// workloads are deleted. Otherwise they are restored after all endpoints
+// associated with a workload.
 func (d *Daemon) restoreOldEndpoints(dir string, clean bool) (*endpointRestoreState, error) {
 	state := &endpointRestoreState{
 		restored: []*endpoint.Endpoint{},
@@ -85,6 +91,7 @@ func (d *Daemon) restoreOldEndpoints(dir string, clean bool) (*endpointRestoreSt
 		return state, nil
 	}
 
+	priorities := make(map[*endpoint.Endpoint]restorePriority, len(possibleEPs))
 	for _, ep := range possibleEPs {
 		scopedLog := log.WithField(logfields.EndpointID, ep.ID)
 		skipRestore := false
@@ -128,19 +135,28 @@ func (d *Daemon) restoreOldEndpoints(dir string, clean bool) (*endpointRestoreSt
 			ep.SetEgressPolicyEnabledLocked(alwaysEnforce)
 		}
 
+		priorities[ep] = restorePriority{
+			running:  !skipRestore,
+			activity: endpointActivity(ep),
+		}
+
 		ep.Unlock()
 
 		ep.SkipStateClean()
 
 		state.restored = append(state.restored, ep)
 
-		delete(existingEndpoints, ep.IPv4.String())
-		delete(existingEndpoints, ep.IPv6.String())
+		for _, ip := range ep.IPs() {
+			delete(existingEndpoints, ip.String())
+		}
 	}
 
+	state.numPrioritized = sortByRestorePriority(state.restored, priorities)
+
 	log.WithFields(logrus.Fields{
-		"count.restored": len(state.restored),
-		"count.total":    len(possibleEPs),
+		"count.restored":    len(state.restored),
+		"count.prioritized": state.numPrioritized,
+		"count.total":       len(possibleEPs),
 	}).Info("Endpoints restored")
 
 	for hostIP, info := range existingEndpoints {
@@ -167,6 +183,16 @@ func (d *Daemon) regenerateRestoredEndpoints(state *endpointRestoreState) {
 	// they have finished to rebuild after being restored.
 	epRegenerated := make(chan bool, len(state.restored))
 
+	d.restoreProgress.start(len(state.restored), len(state.restored)-state.numPrioritized)
+
+	// Endpoints are admitted to the regeneration in the order of their
+	// priority. Endpoints which are not prioritized are only admitted once
+	// all prioritized endpoints have been regenerated so that the node
+	// becomes usable for its active workloads as fast as possible.
+	queue := newRestoreQueue(numWorkerThreads())
+	var prioritized sync.WaitGroup
+	prioritized.Add(state.numPrioritized)
+
 	// Insert all endpoints into the endpoint list first before starting
 	// the regeneration. This is required to ensure that if an individual
 	// regeneration causes an identity change of an endpoint, the new
@@ -190,8 +216,12 @@ func (d *Daemon) regenerateRestoredEndpoints(state *endpointRestoreState) {
 		endpointmanager.Insert(ep)
 	}
 
-	for _, ep := range state.restored {
-		go func(ep *endpoint.Endpoint, epRegenerated chan<- bool) {
+	for i, ep := range state.restored {
+		go func(rank int, ep *endpoint.Endpoint, epRegenerated chan<- bool) {
+			if rank < state.numPrioritized {
+				defer prioritized.Done()
+			}
+
 			if err := ep.RLockAlive(); err != nil {
 				ep.LogDisconnectedMutexAction(err, "before filtering labels during regenerating restored endpoint")
 				return
@@ -272,14 +302,22 @@ func (d *Daemon) regenerateRestoredEndpoints(state *endpointRestoreState) {
 			ready := ep.SetStateLocked(endpoint.StateWaitingToRegenerate, "Triggering synchronous endpoint regeneration while syncing state to host")
 			ep.Unlock()
 
+			d.UpdateMirrorCollectors()
+
 			if !ready {
 				scopedLog.WithField(logfields.EndpointState, ep.GetState()).Warn("Endpoint in inconsistent state")
 				epRegenerated <- false
 				return
 			}
+			if rank >= state.numPrioritized {
+				prioritized.Wait()
+			}
+			queue.wait(rank)
 			regenContext := endpoint.NewRegenerationContext(
 				"syncing state to host")
-			if buildSuccess := <-ep.Regenerate(d, regenContext); !buildSuccess {
+			buildSuccess := <-ep.Regenerate(d, regenContext)
+			queue.done()
+			if !buildSuccess {
 				scopedLog.Warn("Failed while regenerating endpoint")
 				epRegenerated <- false
 				return
@@ -290,7 +328,7 @@ func (d *Daemon) regenerateRestoredEndpoints(state *endpointRestoreState) {
 			scopedLog.WithField(logfields.IPAddr, []string{ep.IPv4.String(), ep.IPv6.String()}).Info("Restored endpoint")
 			ep.RUnlock()
 			epRegenerated <- true
-		}(ep, epRegenerated)
+		}(i, ep, epRegenerated)
 	}
 
 	for _, ep := range state.toClean {
@@ -304,6 +342,7 @@ func (d *Daemon) regenerateRestoredEndpoints(state *endpointRestoreState) {
 				if buildSuccess {
 					regenerated++
 				}
+				d.restoreProgress.update(buildSuccess)
 				total++
 				if total >= len(state.restored) {
 					break
@@ -311,6 +350,7 @@ func (d *Daemon) regenerateRestoredEndpoints(state *endpointRestoreState) {
 			}
 		}
 		close(epRegenerated)
+		d.restoreProgress.complete()
 
 		log.WithFields(logrus.Fields{
 			"regenerated": regenerated,
@@ -347,6 +387,7 @@ func (d *Daemon) allocateIPsLocked(ep *endpoint.Endpoint) error {
 // from a list of directory names that can possible contain an endpoint.
 func readEPsFromDirNames(basePath string, eptsDirNames []string) map[uint16]*endpoint.Endpoint {
 	possibleEPs := map[uint16]*endpoint.Endpoint{}
+	dirNames := map[uint16]string{}
 	for _, epDirName := range eptsDirNames {
 		epDir := filepath.Join(basePath, epDirName)
 		readDir := func() string {
@@ -384,25 +425,55 @@ func readEPsFromDirNames(basePath string, eptsDirNames []string) map[uint16]*end
 
 		scopedLog.Debug("Found endpoint C header file")
 
-		strEp, err := common.GetCiliumVersionString(cHeaderFile)
-		if err != nil {
-			scopedLog.WithError(err).Warn("Unable to read the C header file")
-			continue
-		}
-		ep, err := endpoint.ParseEndpoint(strEp)
+		ep, err := parseEndpointHeaderfile(cHeaderFile)
 		if err != nil {
-			scopedLog.WithError(err).Warn("Unable to parse the C header file")
-			continue
+			// The header file may be corrupt if the node lost power
+			// while it was written, fall back to the state of the
+			// previous build of the endpoint.
+			backupFile := filepath.Join(epDir, common.CHeaderBackupFileName)
+			scopedLog.WithError(err).Warn("Unable to parse the C header file, falling back to the previous endpoint state")
+			ep, err = parseEndpointHeaderfile(backupFile)
+			if err != nil {
+				scopedLog.WithError(err).WithField(logfields.Path, backupFile).Warn("Unable to parse the previous C header file")
+				continue
+			}
 		}
 		if _, ok := possibleEPs[ep.ID]; ok {
 			// If the endpoint already exists then give priority to the directory
 			// that contains an endpoint that didn't fail to be build.
-			if strings.HasSuffix(ep.DirectoryPath(), epDirName) {
+			if epDirPriority(ep, epDirName) > epDirPriority(possibleEPs[ep.ID], dirNames[ep.ID]) {
 				possibleEPs[ep.ID] = ep
+				dirNames[ep.ID] = epDirName
 			}
 		} else {
 			possibleEPs[ep.ID] = ep
+			dirNames[ep.ID] = epDirName
 		}
 	}
 	return possibleEPs
 }
+
+// parseEndpointHeaderfile parses the endpoint stored in the C header file at
+// path.
+func parseEndpointHeaderfile(path string) (*endpoint.Endpoint, error) {
+	strEp, err := common.GetCiliumVersionString(path)
+	if err != nil {
+		return nil, err
+	}
+	return endpoint.ParseEndpoint(strEp)
+}
+
+// epDirPriority returns the priority of the endpoint directory epDirName to
+// restore the endpoint ep from. The directory of the last successful build is
+// preferred over the backup directory of an interrupted directory
+// synchronization, which is preferred over the directory of a failed build.
+func epDirPriority(ep *endpoint.Endpoint, epDirName string) int {
+	switch {
+	case strings.HasSuffix(ep.DirectoryPath(), epDirName):
+		return 2
+	case strings.HasSuffix(epDirName, "_stale"):
+		return 1
+	default:
+		return 0
+	}
+}
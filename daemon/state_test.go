@@ -15,13 +15,16 @@
 package main
 
 import (
+	"encoding/base64"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
 
+	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/common/addressing"
 	"github.com/cilium/cilium/pkg/completion"
 	e "github.com/cilium/cilium/pkg/endpoint"
@@ -197,3 +200,36 @@ func (ds *DaemonSuite) TestReadEPsFromDirNames(c *C) {
 		}
 	}
 }
+
+func (ds *DaemonSuite) TestReadEPsFromDirNamesFallback(c *C) {
+	tmpDir := c.MkDir()
+	writeHeader := func(dir, name string, ep *e.Endpoint) {
+		content := "/*\n * truncated"
+		if ep != nil {
+			jsonBytes, err := json.Marshal(ep)
+			c.Assert(err, IsNil)
+			content = "/*\n * " + common.CiliumCHeaderPrefix + "dmVyc2lvbg==:" +
+				base64.StdEncoding.EncodeToString(jsonBytes) + "\n * \n */\n"
+		}
+		c.Assert(os.MkdirAll(filepath.Join(tmpDir, dir), 0755), IsNil)
+		c.Assert(ioutil.WriteFile(filepath.Join(tmpDir, dir, name), []byte(content), 0644), IsNil)
+	}
+
+	// Corrupt header with a valid previous header
+	prev := e.NewEndpointWithState(300, e.StateReady)
+	prev.NodeMAC = mac.MAC([]byte{0x02, 0xff, 0xf2, 0x12, 0xc1, 0xc1})
+	writeHeader("300", common.CHeaderFileName, nil)
+	writeHeader("300", common.CHeaderBackupFileName, prev)
+
+	// Directory synchronization interrupted after the endpoint directory
+	// was moved to the backup location
+	stale := e.NewEndpointWithState(301, e.StateReady)
+	stale.NodeMAC = mac.MAC([]byte{0x02, 0xff, 0xf2, 0x12, 0xc1, 0xc2})
+	writeHeader("301_stale", common.CHeaderFileName, stale)
+	writeHeader("301_next_fail", common.CHeaderFileName, e.NewEndpointWithState(301, e.StateReady))
+
+	eps := readEPsFromDirNames(tmpDir, []string{"300", "301_next_fail", "301_stale"})
+	c.Assert(len(eps), Equals, 2)
+	c.Assert(eps[300].NodeMAC, DeepEquals, prev.NodeMAC)
+	c.Assert(eps[301].NodeMAC, DeepEquals, stale.NodeMAC)
+}
@@ -16,13 +16,20 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	. "github.com/cilium/cilium/api/v1/server/restapi/daemon"
+	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/controller"
 	"github.com/cilium/cilium/pkg/k8s"
 	"github.com/cilium/cilium/pkg/kvstore"
+	ipcachemap "github.com/cilium/cilium/pkg/maps/ipcache"
+	"github.com/cilium/cilium/pkg/maps/lxcmap"
+	"github.com/cilium/cilium/pkg/maps/metricsmap"
+	"github.com/cilium/cilium/pkg/maps/tunnel"
 	"github.com/cilium/cilium/pkg/node"
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/workloads"
@@ -153,6 +160,11 @@ func (d *Daemon) getStatus() models.StatusResponse {
 			State: sr.Kubernetes.State,
 			Msg:   "Kubernetes service is not ready",
 		}
+	} else if reason := d.getDegradedReason(); reason != "" {
+		sr.Cilium = &models.Status{
+			State: models.StatusStateWarning,
+			Msg:   "Node is degraded: " + reason,
+		}
 	} else {
 		sr.Cilium = &models.Status{State: models.StatusStateOk, Msg: "OK"}
 	}
@@ -170,5 +182,120 @@ func (d *Daemon) getStatus() models.StatusResponse {
 		sr.Proxy = d.l7Proxy.GetStatusModel()
 	}
 
+	sr.EndpointRestore = d.restoreProgress.getModel()
+
+	sr.Policy = &models.PolicyStatus{Revision: int64(d.policy.GetRevision())}
+	sr.BpfMaps = getBPFMapStatus(&d.staleMaps)
+
+	sr.Probes = getStatusProbes(&sr)
+
 	return sr
 }
+
+// getBPFMapStatus checks that the BPF maps shared by all endpoints are pinned
+// in the BPF filesystem and reports the removal of stale endpoint maps.
+func getBPFMapStatus(staleMaps *staleMapGC) *models.Status {
+	maps := []string{lxcmap.MapName, ipcachemap.Name, metricsmap.MapName}
+	if option.Config.Tunnel != option.TunnelDisabled {
+		maps = append(maps, tunnel.MapName)
+	}
+
+	var missing []string
+	for _, name := range maps {
+		if _, err := os.Stat(bpf.MapPath(name)); err != nil {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &models.Status{
+			State: models.StatusStateFailure,
+			Msg:   fmt.Sprintf("Maps not pinned: %s", strings.Join(missing, ", ")),
+		}
+	}
+
+	status := &models.Status{
+		State: models.StatusStateOk,
+		Msg:   fmt.Sprintf("%d maps pinned", len(maps)),
+	}
+	if msg, ok := staleMaps.getStatus(); msg != "" {
+		status.Msg += ", " + msg
+		if !ok {
+			status.State = models.StatusStateWarning
+		}
+	}
+	return status
+}
+
+// getStatusProbes summarizes the state of each subsystem in sr as a list of
+// probes with a uniform severity, ordered by name.
+func getStatusProbes(sr *models.StatusResponse) []*models.StatusProbe {
+	probes := []*models.StatusProbe{}
+	addStatus := func(name string, status *models.Status) {
+		if status != nil {
+			probes = append(probes, &models.StatusProbe{Name: name, State: status.State, Msg: status.Msg})
+		}
+	}
+
+	addStatus("bpf-maps", sr.BpfMaps)
+	addStatus("cilium", sr.Cilium)
+	if sr.Cluster != nil {
+		addStatus("cilium-health", sr.Cluster.CiliumHealth)
+	}
+	addStatus("container-runtime", sr.ContainerRuntime)
+
+	failing := 0
+	for _, ctrl := range sr.Controllers {
+		if ctrl.Status != nil && ctrl.Status.ConsecutiveFailureCount > 0 {
+			failing++
+		}
+	}
+	ctrlProbe := &models.StatusProbe{
+		Name:  "controllers",
+		State: models.StatusProbeStateOk,
+		Msg:   fmt.Sprintf("%d/%d controllers failing", failing, len(sr.Controllers)),
+	}
+	if failing > 0 {
+		ctrlProbe.State = models.StatusProbeStateWarning
+	}
+	probes = append(probes, ctrlProbe)
+
+	if sr.IPAM != nil {
+		probes = append(probes, &models.StatusProbe{
+			Name:  "ipam",
+			State: models.StatusProbeStateOk,
+			Msg:   fmt.Sprintf("%d IPv4, %d IPv6 addresses allocated", len(sr.IPAM.IPV4), len(sr.IPAM.IPV6)),
+		})
+	}
+
+	if sr.Kubernetes != nil {
+		probes = append(probes, &models.StatusProbe{Name: "kubernetes", State: sr.Kubernetes.State, Msg: sr.Kubernetes.Msg})
+	}
+	addStatus("kvstore", sr.Kvstore)
+
+	if sr.NodeMonitor != nil {
+		probes = append(probes, &models.StatusProbe{Name: "node-monitor", State: models.StatusProbeStateOk})
+	} else {
+		probes = append(probes, &models.StatusProbe{Name: "node-monitor", State: models.StatusProbeStateDisabled})
+	}
+
+	if sr.Policy != nil {
+		probes = append(probes, &models.StatusProbe{
+			Name:  "policy",
+			State: models.StatusProbeStateOk,
+			Msg:   fmt.Sprintf("Revision %d", sr.Policy.Revision),
+		})
+	}
+
+	if sr.Proxy != nil {
+		probes = append(probes, &models.StatusProbe{
+			Name:  "proxy",
+			State: models.StatusProbeStateOk,
+			Msg:   fmt.Sprintf("%s, ports %s", sr.Proxy.IP, sr.Proxy.PortRange),
+		})
+	} else {
+		probes = append(probes, &models.StatusProbe{Name: "proxy", State: models.StatusProbeStateDisabled})
+	}
+
+	return probes
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ds *DaemonSuite) TestGetStatusProbes(c *C) {
+	sr := &models.StatusResponse{
+		Cilium:     &models.Status{State: models.StatusStateOk, Msg: "OK"},
+		Kvstore:    &models.Status{State: models.StatusStateFailure, Msg: "unreachable"},
+		Kubernetes: &models.K8sStatus{State: models.K8sStatusStateDisabled},
+		Controllers: models.ControllerStatuses{
+			{Name: "a", Status: &models.ControllerStatusStatus{}},
+			{Name: "b", Status: &models.ControllerStatusStatus{ConsecutiveFailureCount: 2}},
+		},
+		Policy: &models.PolicyStatus{Revision: 7},
+	}
+
+	probes := map[string]*models.StatusProbe{}
+	names := []string{}
+	for _, probe := range getStatusProbes(sr) {
+		probes[probe.Name] = probe
+		names = append(names, probe.Name)
+	}
+
+	c.Assert(names, DeepEquals, []string{"cilium", "controllers", "kubernetes", "kvstore", "node-monitor", "policy", "proxy"})
+	c.Assert(probes["kvstore"].State, Equals, models.StatusProbeStateFailure)
+	c.Assert(probes["kvstore"].Msg, Equals, "unreachable")
+	c.Assert(probes["kubernetes"].State, Equals, models.StatusProbeStateDisabled)
+	c.Assert(probes["controllers"].State, Equals, models.StatusProbeStateWarning)
+	c.Assert(probes["controllers"].Msg, Equals, "1/2 controllers failing")
+	c.Assert(probes["policy"].Msg, Equals, "Revision 7")
+	c.Assert(probes["proxy"].State, Equals, models.StatusProbeStateDisabled)
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/watchdog"
+)
+
+// watchdogInterval is the interval in which the resource usage of the agent
+// is compared against the watchdog budget
+const watchdogInterval = 10 * time.Second
+
+// startWatchdog starts a controller comparing the resource usage of the agent
+// against budget, taking actions in order when it is exceeded
+func (d *Daemon) startWatchdog(budget watchdog.Budget, actions []watchdog.Action) {
+	if !budget.IsEnabled() {
+		return
+	}
+
+	w := watchdog.NewWatchdog(budget, actions, watchdog.Handlers{
+		SendEvent: func(exceeded []string) {
+			repr, err := monitor.ResourceBudgetRepr(exceeded)
+			if err != nil {
+				log.WithError(err).Warning("Unable to create resource budget notification")
+				return
+			}
+			d.SendNotification(monitor.AgentNotifyResourceBudgetExceeded, repr)
+		},
+		RestartMonitor: func() {
+			d.nodeMonitor.Restart(d.nodeMonitor.GetArgs())
+		},
+		SetDegraded: d.setDegradedReason,
+		MapMemory:   bpf.GetOpenMapsMemory,
+	})
+
+	controller.NewManager().UpdateController("agent-watchdog",
+		controller.ControllerParams{
+			DoFunc:      w.Check,
+			RunInterval: watchdogInterval,
+		})
+}
+
+// setDegradedReason marks the node as degraded in the agent status, an empty
+// reason clears the mark
+func (d *Daemon) setDegradedReason(reason string) {
+	d.statusCollectMutex.Lock()
+	d.degradedReason = reason
+	d.statusCollectMutex.Unlock()
+}
+
+func (d *Daemon) getDegradedReason() string {
+	d.statusCollectMutex.RLock()
+	defer d.statusCollectMutex.RUnlock()
+	return d.degradedReason
+}
@@ -81,7 +81,8 @@ createHostMap(Server::Configuration::ListenerFactoryContext& context) {
 } // namespace
 
 Config::Config(const ::cilium::BpfMetadata &config, Server::Configuration::ListenerFactoryContext& context)
-    : is_ingress_(config.is_ingress()) {
+    : is_ingress_(config.is_ingress()),
+      use_original_source_address_(config.use_original_source_address()) {
   // Note: all instances use the bpf root of the first filter with non-empty bpf_root instantiated!
   std::string bpf_root = config.bpf_root();
   if (bpf_root.length() > 0) {
@@ -115,7 +116,15 @@ bool Config::getMetadata(Network::ConnectionSocket& socket) {
     if (hosts_ && socket.localAddress()->ip()) {
       destination_identity = hosts_->resolve(socket.localAddress()->ip());
     }
-    socket.addOption(std::make_shared<Cilium::SocketOption>(maps_, source_identity, destination_identity, is_ingress_, orig_dport, proxy_port));
+    // Upstream connections can only be made from the original source
+    // address of IPv4 connections.
+    Network::Address::InstanceConstSharedPtr original_source_address = nullptr;
+    if (use_original_source_address_ && socket.remoteAddress()->ip() &&
+	socket.remoteAddress()->ip()->ipv4()) {
+      original_source_address = socket.remoteAddress();
+    }
+    socket.addOption(std::make_shared<Cilium::SocketOption>(maps_, source_identity, destination_identity, is_ingress_, orig_dport, proxy_port,
+							    original_source_address));
   }
   return ok;
 }
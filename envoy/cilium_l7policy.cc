@@ -8,6 +8,7 @@
 
 #include "common/buffer/buffer_impl.h"
 #include "common/common/enum_to_int.h"
+#include "common/common/macros.h"
 #include "common/config/utility.h"
 #include "common/http/header_map_impl.h"
 
@@ -20,6 +21,16 @@ namespace Cilium {
 // Singleton registration via macro defined in envoy/singleton/manager.h
 SINGLETON_MANAGER_REGISTRATION(cilium_network_policy);
 
+// Identity headers injected by rules with 'inject_identity_headers' set.
+// Any headers of the same name sent by the source are always removed.
+static const Http::LowerCaseString& SourceIdentityHeader() {
+  CONSTRUCT_ON_FIRST_USE(Http::LowerCaseString, "x-cilium-source-identity");
+}
+
+static const Http::LowerCaseString& SourceLabelsHeader() {
+  CONSTRUCT_ON_FIRST_USE(Http::LowerCaseString, "x-cilium-source-labels");
+}
+
 class ConfigFactory
     : public Server::Configuration::NamedHttpFilterConfigFactory {
 public:
@@ -126,9 +137,12 @@ void AccessFilter::onDestroy() {}
 
 Http::FilterHeadersStatus AccessFilter::decodeHeaders(Http::HeaderMap& headers, bool) {
   headers.remove(Http::Headers::get().EnvoyOriginalDstHost);
+  headers.remove(SourceIdentityHeader());
+  headers.remove(SourceLabelsHeader());
   const auto& conn = callbacks_->connection();
   bool ingress = false;
   bool allowed = false;
+  HttpRuleActions actions;
   if (config_->npmap_ && conn) {
     const auto& options_ = conn->socketOptions();
     if (options_) {
@@ -143,10 +157,29 @@ Http::FilterHeadersStatus AccessFilter::decodeHeaders(Http::HeaderMap& headers,
 	  }
 	  if (ingress) {
 	    allowed = config_->npmap_->Allowed(config_->policy_name_, ingress, option->port_,
-					       option->identity_, headers);
+					       option->identity_, headers, actions);
 	  } else {
 	    allowed = config_->npmap_->Allowed(config_->policy_name_, ingress, option->port_,
-					       option->destination_identity_, headers);
+					       option->destination_identity_, headers, actions);
+	  }
+	  if (allowed && actions.inject_identity_headers_) {
+	    headers.addCopy(SourceIdentityHeader(), option->identity_);
+	    const auto& labels = config_->npmap_->IdentityLabels(config_->policy_name_, option->identity_);
+	    if (labels.length() > 0) {
+	      headers.addCopy(SourceLabelsHeader(), labels);
+	    }
+	  }
+	  // Override the timeout and retries of the router with those of the policy.
+	  if (allowed && actions.timeout_ms_ > 0) {
+	    headers.insertEnvoyUpstreamRequestTimeoutMs().value(actions.timeout_ms_);
+	  }
+	  if (allowed && actions.retry_policy_ != nullptr) {
+	    const auto& retry_policy = *actions.retry_policy_;
+	    headers.insertEnvoyMaxRetries().value(uint64_t(retry_policy.num_retries()));
+	    headers.insertEnvoyRetryOn().value(retry_policy.retry_on());
+	    if (retry_policy.per_try_timeout_ms() > 0) {
+	      headers.insertEnvoyUpstreamRequestPerTryTimeoutMs().value(retry_policy.per_try_timeout_ms());
+	    }
 	  }
 	  ENVOY_LOG(debug, "Cilium L7: {} ({}->{}) policy lookup for endpoint {}: {}",
 		    ingress ? "Ingress" : "Egress",
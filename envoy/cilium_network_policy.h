@@ -4,6 +4,7 @@
 #include "envoy/upstream/cluster_manager.h"
 #include "envoy/event/dispatcher.h"
 
+#include "common/common/empty_string.h"
 #include "common/common/logger.h"
 #include "common/http/header_utility.h"
 #include "envoy/config/subscription.h"
@@ -16,6 +17,16 @@
 namespace Envoy {
 namespace Cilium {
 
+// Actions applied to a request by the HTTP rules allowing it.
+struct HttpRuleActions {
+  // Inject the identity headers if any of the rules allowing the request
+  // injects them.
+  bool inject_identity_headers_{false};
+  // Timeout and retry policy of the first rule allowing the request setting them.
+  uint64_t timeout_ms_{0};
+  const cilium::HttpNetworkPolicyRule::RetryPolicy* retry_policy_{nullptr};
+};
+
 class NetworkPolicyMap : public Singleton::Instance,
                          Config::SubscriptionCallbacks<cilium::NetworkPolicy>,
                          public std::enable_shared_from_this<NetworkPolicyMap>,
@@ -49,8 +60,11 @@ public:
   protected:
     class HttpNetworkPolicyRule : public Logger::Loggable<Logger::Id::config> {
     public:
-      HttpNetworkPolicyRule(const cilium::HttpNetworkPolicyRule& rule) {
-	ENVOY_LOG(trace, "Cilium L7 HttpNetworkPolicyRule():");
+      HttpNetworkPolicyRule(const cilium::HttpNetworkPolicyRule& rule)
+	: inject_identity_headers_(rule.inject_identity_headers()), timeout_ms_(rule.timeout_ms()),
+	  retry_policy_(rule.retry_policy()), has_retry_policy_(rule.has_retry_policy()) {
+	ENVOY_LOG(trace, "Cilium L7 HttpNetworkPolicyRule(): inject_identity_headers: {}, timeout_ms: {}, retry_policy: {}",
+		  inject_identity_headers_, timeout_ms_, has_retry_policy_ ? retry_policy_.DebugString() : "none");
 	for (const auto& header: rule.headers()) {
 	  headers_.emplace_back(header);
 	  const auto& header_data = headers_.back();
@@ -70,7 +84,24 @@ public:
 	return Envoy::Http::HeaderUtility::matchHeaders(headers, headers_);
       }
 
+      // Merge the actions of this rule into 'actions' of a matching request.
+      void Apply(HttpRuleActions& actions) const {
+	if (inject_identity_headers_) {
+	  actions.inject_identity_headers_ = true;
+	}
+	if (actions.timeout_ms_ == 0) {
+	  actions.timeout_ms_ = timeout_ms_;
+	}
+	if (actions.retry_policy_ == nullptr && has_retry_policy_) {
+	  actions.retry_policy_ = &retry_policy_;
+	}
+      }
+
       std::vector<Envoy::Http::HeaderUtility::HeaderData> headers_; // Allowed if empty.
+      bool inject_identity_headers_;
+      uint64_t timeout_ms_; // Default timeout if zero.
+      const cilium::HttpNetworkPolicyRule::RetryPolicy retry_policy_;
+      bool has_retry_policy_;
     };
     
     class PortNetworkPolicyRule : public Logger::Loggable<Logger::Id::config> {
@@ -87,7 +118,9 @@ public:
 	}
       }
 
-      bool Matches(uint64_t remote_id, const Envoy::Http::HeaderMap& headers) const {
+      // The actions of all matching HTTP rules are merged into 'actions'.
+      bool Matches(uint64_t remote_id, const Envoy::Http::HeaderMap& headers,
+		   HttpRuleActions& actions) const {
 	// Remote ID must match if we have any.
 	if (allowed_remotes_.size() > 0) {
 	  auto search = allowed_remotes_.find(remote_id);
@@ -96,12 +129,14 @@ public:
 	  }
 	}
 	if (http_rules_.size() > 0) {
+	  bool matched = false;
 	  for (const auto& rule: http_rules_) {
 	    if (rule.Matches(headers)) {
-	      return true;
+	      matched = true;
+	      rule.Apply(actions);
 	    }
 	  }
-	  return false;
+	  return matched;
 	}
 	// Empty set matches any payload
 	return true;
@@ -125,7 +160,8 @@ public:
 	}
       }
 
-      bool Matches(uint64_t remote_id, const Envoy::Http::HeaderMap& headers) const {
+      bool Matches(uint64_t remote_id, const Envoy::Http::HeaderMap& headers,
+		   HttpRuleActions& actions) const {
 	if (!have_http_rules_) {
 	  // If there are no L7 rules, host proxy will not create a proxy redirect at all,
 	  // whereby the decicion made by the bpf datapath is final. Emulate the same behavior
@@ -137,12 +173,13 @@ public:
 	if (rules_.size() == 0) {
 	  return true;
 	}
+	bool matched = false;
 	for (const auto& rule: rules_) {
-	  if (rule.Matches(remote_id, headers)) {
-	    return true;
+	  if (rule.Matches(remote_id, headers, actions)) {
+	    matched = true;
 	  }
 	}
-	return false;
+	return matched;
       }
 
       std::vector<PortNetworkPolicyRule> rules_; // Allowed if empty.
@@ -166,23 +203,29 @@ public:
 	}
       }
 
-      bool Matches(uint32_t port, uint64_t remote_id, const Envoy::Http::HeaderMap& headers) const {
+      bool Matches(uint32_t port, uint64_t remote_id, const Envoy::Http::HeaderMap& headers,
+		   HttpRuleActions& actions) const {
 	bool found_port_rule = false;
+	bool matched = false;
 	auto it = rules_.find(port);
 	if (it != rules_.end()) {
-	  if (it->second.Matches(remote_id, headers)) {
-	    return true;
+	  // Keep looking for the actions of the rules that wildcard the port.
+	  if (it->second.Matches(remote_id, headers, actions)) {
+	    matched = true;
 	  }
 	  found_port_rule = true;
 	}
 	// Check for any rules that wildcard the port
 	it = rules_.find(0);
 	if (it != rules_.end()) {
-	  if (it->second.Matches(remote_id, headers)) {
+	  if (it->second.Matches(remote_id, headers, actions)) {
 	    return true;
 	  }
 	  found_port_rule = true;
 	}
+	if (matched) {
+	  return true;
+	}
 
 	// No policy for the port was found. Cilium always creates a policy for redirects it
 	// creates, so the host proxy never gets here. Sidecar gets all the traffic, which we need
@@ -196,10 +239,21 @@ public:
 
   public:
     bool Allowed(bool ingress, uint32_t port, uint64_t remote_id,
-		 const Envoy::Http::HeaderMap& headers) const {
+		 const Envoy::Http::HeaderMap& headers, HttpRuleActions& actions) const {
       return ingress
-	? ingress_.Matches(port, remote_id, headers)
-	: egress_.Matches(port, remote_id, headers);
+	? ingress_.Matches(port, remote_id, headers, actions)
+	: egress_.Matches(port, remote_id, headers, actions);
+    }
+
+    // Returns the labels of the given source identity for the identity headers,
+    // or an empty string if the labels are not known.
+    const std::string& IdentityLabels(uint64_t identity) const {
+      const auto& labels = policy_proto_.identity_labels();
+      auto it = labels.find(identity);
+      if (it == labels.end()) {
+	return EMPTY_STRING;
+      }
+      return it->second;
     }
 
   private:
@@ -220,8 +274,9 @@ public:
     return it->second;
   }
 
+  // The actions of the rules allowing the request are returned in 'actions'.
   bool Allowed(const std::string& endpoint_policy_name, bool ingress, uint32_t port, uint64_t remote_id,
-	       const Envoy::Http::HeaderMap& headers) const {
+	       const Envoy::Http::HeaderMap& headers, HttpRuleActions& actions) const {
     ENVOY_LOG(trace, "Cilium L7 NetworkPolicyMap::Allowed(): {} policy lookup for endpoint {}, port {}, remote_id: {}", ingress ? "Ingress" : "Egress", endpoint_policy_name, port, remote_id);
     if (tls_->get().get() == nullptr) {
       ENVOY_LOG(warn, "Cilium L7 NetworkPolicyMap::Allowed(): NULL TLS object!");
@@ -233,7 +288,15 @@ public:
       ENVOY_LOG(trace, "Cilium L7 NetworkPolicyMap::Allowed(): No policy found for endpoint {}", endpoint_policy_name);
       return false;
     }
-    return it->second->Allowed(ingress, port, remote_id, headers);
+    return it->second->Allowed(ingress, port, remote_id, headers, actions);
+  }
+
+  const std::string& IdentityLabels(const std::string& endpoint_policy_name, uint64_t identity) const {
+    const auto& policy = GetPolicyInstance(endpoint_policy_name);
+    if (policy == nullptr) {
+      return EMPTY_STRING;
+    }
+    return policy->IdentityLabels(identity);
   }
 
   // Config::SubscriptionCallbacks
@@ -1,6 +1,9 @@
 #pragma once
 
+#include <netinet/in.h>
+
 #include "envoy/api/v2/core/base.pb.h"
+#include "envoy/network/address.h"
 #include "envoy/network/listen_socket.h"
 #include "common/common/logger.h"
 
@@ -11,7 +14,9 @@ namespace Cilium {
 
 class SocketMarkOption : public Network::Socket::Option, public Logger::Loggable<Logger::Id::filter> {
 public:
-  SocketMarkOption(uint32_t identity, bool ingress) : identity_(identity), ingress_(ingress) {}
+  SocketMarkOption(uint32_t identity, bool ingress,
+		   Network::Address::InstanceConstSharedPtr original_source_address = nullptr)
+    : identity_(identity), ingress_(ingress), original_source_address_(std::move(original_source_address)) {}
 
   bool setOption(Network::Socket& socket, envoy::api::v2::core::SocketOption::SocketState state) const override {
     // Only set the option once per socket
@@ -36,24 +41,66 @@ public:
       }
     }
     ENVOY_LOG(trace, "Set socket ({}) option SO_MARK to {:x} (magic mark: {:x}, id: {}, cluster: {})", socket.fd(), mark, mark & 0xff00, mark >> 16, mark & 0xff);
+    if (original_source_address_) {
+      return bindOriginalSource(socket);
+    }
     return true;
   }
+
+  // Bind the socket to the original source address of the downstream
+  // connection. IP_TRANSPARENT allows binding to the address although it
+  // is not local.
+  bool bindOriginalSource(Network::Socket& socket) const {
+    int one = 1;
+    int rc = setsockopt(socket.fd(), SOL_IP, IP_TRANSPARENT, &one, sizeof(one));
+    if (rc < 0) {
+      ENVOY_LOG(critical, "Socket option failure. Failed to set IP_TRANSPARENT: {}", strerror(errno));
+      return false;
+    }
+    const auto* ip = original_source_address_->ip();
+    struct sockaddr_in addr = {};
+    addr.sin_family = AF_INET;
+    addr.sin_addr.s_addr = ip->ipv4()->address();
+    addr.sin_port = htons(ip->port());
+    rc = bind(socket.fd(), reinterpret_cast<const struct sockaddr*>(&addr), sizeof(addr));
+    if (rc < 0) {
+      ENVOY_LOG(critical, "Failed to bind socket ({}) to original source address {}: {}",
+		socket.fd(), original_source_address_->asString(), strerror(errno));
+      return false;
+    }
+    ENVOY_LOG(trace, "Bound socket ({}) to original source address {}", socket.fd(), original_source_address_->asString());
+    return true;
+  }
+
   void hashKey(std::vector<uint8_t>& key) const override {
     // Add the source identity to the hash key. This will separate upstream connection pools
     // per security ID.
     key.emplace_back(uint8_t(identity_ >> 16));
     key.emplace_back(uint8_t(identity_ >> 8));
     key.emplace_back(uint8_t(identity_));
+    // Connections made from the original source address can never be
+    // shared with other downstream connections.
+    if (original_source_address_) {
+      const auto* ip = original_source_address_->ip();
+      uint32_t addr = ip->ipv4()->address();
+      uint16_t port = ip->port();
+      const uint8_t* addr_bytes = reinterpret_cast<const uint8_t*>(&addr);
+      key.insert(key.end(), addr_bytes, addr_bytes + sizeof(addr));
+      key.emplace_back(uint8_t(port >> 8));
+      key.emplace_back(uint8_t(port));
+    }
   }
 
   uint32_t identity_;
   bool ingress_;
+  Network::Address::InstanceConstSharedPtr original_source_address_;
 };
 
 class SocketOption : public SocketMarkOption {
 public:
-  SocketOption(const ProxyMapSharedPtr& maps, uint32_t source_identity, uint32_t destination_identity, bool ingress, uint16_t port, uint16_t proxy_port)
-    : SocketMarkOption(source_identity, ingress), maps_(maps), destination_identity_(destination_identity), port_(port), proxy_port_(proxy_port) {
+  SocketOption(const ProxyMapSharedPtr& maps, uint32_t source_identity, uint32_t destination_identity, bool ingress, uint16_t port, uint16_t proxy_port,
+	       Network::Address::InstanceConstSharedPtr original_source_address = nullptr)
+    : SocketMarkOption(source_identity, ingress, std::move(original_source_address)), maps_(maps), destination_identity_(destination_identity), port_(port), proxy_port_(proxy_port) {
     ENVOY_LOG(debug, "Cilium SocketOption(): source_identity: {}, destination_identity: {}, ingress: {}, port: {}, proxy_port: {}", identity_, destination_identity_, ingress_, port_, proxy_port_);
   }
 
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -43,12 +44,40 @@ const (
 	queueSize = 524288
 )
 
+// RingBufferConfig is the configuration of the per-CPU perf ring buffers the
+// node monitor reads events from
+type RingBufferConfig struct {
+	// NumPages is the number of pages of each ring buffer, must be a
+	// power of two
+	NumPages int
+
+	// WakeupEvents is the number of events after which the node monitor
+	// is woken up
+	WakeupEvents int
+
+	// Overwrite makes the kernel overwrite the oldest events of full ring
+	// buffers instead of dropping new events
+	Overwrite bool
+}
+
+// args returns the command line arguments of the node monitor
+func (c RingBufferConfig) args() []string {
+	return []string{
+		"--num-pages", strconv.Itoa(c.NumPages),
+		"--wakeup-events", strconv.Itoa(c.WakeupEvents),
+		"--overwrite=" + strconv.FormatBool(c.Overwrite),
+	}
+}
+
 // NodeMonitor is used to wrap the node executable binary.
 type NodeMonitor struct {
 	launcher.Launcher
 
 	state *models.MonitorStatus
 
+	// config is the ring buffer configuration, protected by Mutex
+	config RingBufferConfig
+
 	// The following members are protected by pipeLock
 	pipeLock lock.Mutex
 	pipe     *os.File
@@ -58,10 +87,12 @@ type NodeMonitor struct {
 	queue chan []byte
 }
 
-// NewNodeMonitor returns a new node monitor
-func NewNodeMonitor() *NodeMonitor {
+// NewNodeMonitor returns a new node monitor which reads events from ring
+// buffers with the given configuration
+func NewNodeMonitor(config RingBufferConfig) *NodeMonitor {
 	nm := &NodeMonitor{
-		queue: make(chan []byte, queueSize),
+		queue:  make(chan []byte, queueSize),
+		config: config,
 	}
 
 	go nm.eventDrainer()
@@ -98,7 +129,7 @@ func (nm *NodeMonitor) run(sockPath, bpfRoot string) error {
 	nm.pipe = pipe
 	nm.pipeLock.Unlock()
 
-	nm.Launcher.SetArgs([]string{"--bpf-root", bpfRoot})
+	nm.Launcher.SetArgs(append([]string{"--bpf-root", bpfRoot}, nm.Config().args()...))
 	if err := nm.Launcher.Run(); err != nil {
 		return err
 	}
@@ -137,6 +168,27 @@ func (nm *NodeMonitor) Run(sockPath, bpfRoot string) {
 	}
 }
 
+// Config returns the ring buffer configuration.
+func (nm *NodeMonitor) Config() RingBufferConfig {
+	nm.Mutex.RLock()
+	config := nm.config
+	nm.Mutex.RUnlock()
+	return config
+}
+
+// SetNumPages changes the number of pages of each ring buffer and restarts
+// the node monitor if the number changed.
+func (nm *NodeMonitor) SetNumPages(numPages int) {
+	nm.Mutex.Lock()
+	changed := nm.config.NumPages != numPages
+	nm.config.NumPages = numPages
+	nm.Mutex.Unlock()
+
+	if changed {
+		nm.Restart(nm.GetArgs())
+	}
+}
+
 // State returns the monitor status.
 func (nm *NodeMonitor) State() *models.MonitorStatus {
 	nm.Mutex.RLock()
@@ -23,12 +23,15 @@ import (
 )
 
 // Version is the version of a node-monitor listener client. There are
-// two API versions:
+// three API versions:
 // - 1.0 which encodes the gob type information with each payload sent, and
 //   adds a meta object before it.
 // - 1.2 which maintains a gob session per listener, thus only encoding the
 //   type information on the first payload sent. It does NOT prepend the a meta
 //   object.
+// - 1.3 which is the 1.2 protocol preceded by a gob encoded
+//   monitor.EventFilter sent by the listener. Only the events matching the
+//   filter are sent to the listener.
 type Version string
 
 const (
@@ -40,6 +43,9 @@ const (
 
 	// Version1_2 is the API 1.0 version of the protocol (see above).
 	Version1_2 = Version("1.2")
+
+	// Version1_3 is the API 1.3 version of the protocol (see above).
+	Version1_3 = Version("1.3")
 )
 
 // MonitorListener is a generic consumer of monitor events. Implementers are
@@ -0,0 +1,94 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/gob"
+	"net"
+
+	"github.com/cilium/cilium/monitor/listener"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+)
+
+// listenerv1_3 implements the cilium-node-monitor API protocol compatible with
+// cilium 1.3. The listener sends an event filter first, only the events
+// matching it are sent to the listener.
+// cleanupFn is called on exit
+type listenerv1_3 struct {
+	conn      net.Conn
+	queue     chan *payload.Payload
+	cleanupFn func(listener.MonitorListener)
+}
+
+func newListenerv1_3(c net.Conn, queueSize int, cleanupFn func(listener.MonitorListener)) *listenerv1_3 {
+	ml := &listenerv1_3{
+		conn:      c,
+		queue:     make(chan *payload.Payload, queueSize),
+		cleanupFn: cleanupFn,
+	}
+
+	go ml.drainQueue()
+
+	return ml
+}
+
+func (ml *listenerv1_3) Enqueue(pl *payload.Payload) {
+	select {
+	case ml.queue <- pl:
+	default:
+		log.Debug("Per listener queue is full, dropping message")
+	}
+}
+
+// drainQueue reads the event filter of the listener, then filters, encodes
+// and sends monitor payloads to the listener. It is intended to be a
+// goroutine. The events are filtered here rather than when they are
+// enqueued so that decoding them does not hold up the distribution of events
+// to other listeners.
+func (ml *listenerv1_3) drainQueue() {
+	defer func() {
+		ml.conn.Close()
+		ml.cleanupFn(ml)
+	}()
+
+	filter := monitor.EventFilter{}
+	if err := gob.NewDecoder(ml.conn).Decode(&filter); err != nil {
+		log.WithError(err).Warn("Removing listener due to invalid event filter")
+		return
+	}
+
+	enc := gob.NewEncoder(ml.conn)
+	for pl := range ml.queue {
+		if pl.Type == payload.EventSample && !filter.MatchSample(pl.Data) {
+			continue
+		}
+		if err := pl.EncodeBinary(enc); err != nil {
+			switch {
+			case listener.IsDisconnected(err):
+				log.Debug("Listener disconnected")
+				return
+
+			default:
+				log.WithError(err).Warn("Removing listener due to write failure")
+				return
+			}
+		}
+	}
+}
+
+func (ml *listenerv1_3) Version() listener.Version {
+	return listener.Version1_3
+}
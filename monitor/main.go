@@ -51,6 +51,14 @@ var (
 	}
 	npages int
 
+	// wakeupEvents is the number of events after which the reader of a
+	// ring buffer is woken up
+	wakeupEvents int
+
+	// overwrite makes the kernel overwrite the oldest events of full ring
+	// buffers instead of dropping new events
+	overwrite bool
+
 	// bpfRoot is the path to the BPF mount. This can be non-default if
 	// cilium-agent mounts bpf at an alternate location.
 	bpfRoot string
@@ -58,6 +66,8 @@ var (
 
 func init() {
 	rootCmd.Flags().IntVar(&npages, "num-pages", 64, "Number of pages for ring buffer")
+	rootCmd.Flags().IntVar(&wakeupEvents, "wakeup-events", 1, "Number of events after which the ring buffer reader is woken up")
+	rootCmd.Flags().BoolVar(&overwrite, "overwrite", false, "Overwrite the oldest events when the ring buffer is full instead of dropping new events")
 	rootCmd.Flags().StringVar(&bpfRoot, "bpf-root", "/sys/fs/bpf", "Path to the root of the bpf mount")
 }
 
@@ -118,9 +128,18 @@ func runNodeMonitor() {
 	defer server1_2.Close() // Stop accepting new v1.2 connections
 	log.Infof("Serving cilium node monitor v1.2 API at unix://%s", defaults.MonitorSockPath1_2)
 
+	server1_3 := buildServerOrExit(defaults.MonitorSockPath1_3)
+	defer server1_3.Close() // Stop accepting new v1.3 connections
+	log.Infof("Serving cilium node monitor v1.3 API at unix://%s", defaults.MonitorSockPath1_3)
+
 	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
 
-	monitorSingleton, err = NewMonitor(mainCtx, npages, pipe, server1_0, server1_2)
+	perfConfig := bpf.DefaultPerfEventConfig()
+	perfConfig.NumPages = npages
+	perfConfig.WakeupEvents = wakeupEvents
+	perfConfig.Overwrite = overwrite
+
+	monitorSingleton, err = NewMonitor(mainCtx, *perfConfig, pipe, server1_0, server1_2, server1_3)
 	if err != nil {
 		log.WithError(err).Fatal("Error initialising monitor handlers")
 	}
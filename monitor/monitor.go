@@ -70,7 +70,7 @@ type Monitor struct {
 	ctx              context.Context
 	perfReaderCancel context.CancelFunc
 	listeners        map[listener.MonitorListener]struct{}
-	nPages           int
+	perfConfig       bpf.PerfEventConfig
 	monitorEvents    *bpf.PerCpuEvents
 }
 
@@ -102,18 +102,19 @@ func (m *Monitor) agentPipeReader(ctx context.Context, agentPipe io.Reader) {
 // NewMonitor creates a Monitor, and starts client connection handling and agent event
 // handling.
 // Note that the perf buffer reader is started only when listeners are
-// connected.
-func NewMonitor(ctx context.Context, nPages int, agentPipe io.Reader, server1_0, server1_2 net.Listener) (m *Monitor, err error) {
+// connected. perfConfig is the configuration of the perf ring buffers.
+func NewMonitor(ctx context.Context, perfConfig bpf.PerfEventConfig, agentPipe io.Reader, server1_0, server1_2, server1_3 net.Listener) (m *Monitor, err error) {
 	m = &Monitor{
 		ctx:              ctx,
 		listeners:        make(map[listener.MonitorListener]struct{}),
-		nPages:           nPages,
+		perfConfig:       perfConfig,
 		perfReaderCancel: func() {}, // no-op to avoid doing null checks everywhere
 	}
 
 	// start new MonitorListener handler
 	go m.connectionHandler1_0(ctx, server1_0)
 	go m.connectionHandler1_2(ctx, server1_2)
+	go m.connectionHandler1_3(ctx, server1_3)
 
 	// start agent event pipe reader
 	go m.agentPipeReader(ctx, agentPipe)
@@ -135,7 +136,7 @@ func (m *Monitor) registerNewListener(parentCtx context.Context, conn net.Conn,
 		m.perfReaderCancel() // don't leak any old readers, just in case.
 		perfEventReaderCtx, cancel := context.WithCancel(parentCtx)
 		m.perfReaderCancel = cancel
-		go m.perfEventReader(perfEventReaderCtx, m.nPages)
+		go m.perfEventReader(perfEventReaderCtx, m.perfConfig)
 	}
 
 	switch version {
@@ -147,6 +148,10 @@ func (m *Monitor) registerNewListener(parentCtx context.Context, conn net.Conn,
 		newListener := newListenerv1_2(conn, queueSize, m.removeListener)
 		m.listeners[newListener] = struct{}{}
 
+	case listener.Version1_3:
+		newListener := newListenerv1_3(conn, queueSize, m.removeListener)
+		m.listeners[newListener] = struct{}{}
+
 	default:
 		conn.Close()
 		log.WithField("version", version).Error("Closing new connection from unsupported monitor client version")
@@ -184,16 +189,12 @@ func (m *Monitor) removeListener(ml listener.MonitorListener) {
 // will exit when stopCtx is done. Note, however, that it will block in the
 // Poll call but assumes enough events are generated that these blocks are
 // short.
-func (m *Monitor) perfEventReader(stopCtx context.Context, nPages int) {
+func (m *Monitor) perfEventReader(stopCtx context.Context, c bpf.PerfEventConfig) {
 	scopedLog := log.WithField(logfields.StartTime, time.Now())
 	scopedLog.Info("Beginning to read perf buffer")
 	defer scopedLog.Info("Stopped reading perf buffer")
 
-	// configure BPF perf buffer reader
-	c := bpf.DefaultPerfEventConfig()
-	c.NumPages = nPages
-
-	monitorEvents, err := bpf.NewPerCpuEvents(c)
+	monitorEvents, err := bpf.NewPerCpuEvents(&c)
 	if err != nil {
 		scopedLog.WithError(err).Fatal("Cannot initialise BPF perf ring buffer sockets")
 	}
@@ -241,11 +242,7 @@ func (m *Monitor) dumpStat() {
 	m.Lock()
 	defer m.Unlock()
 
-	c := int64(m.monitorEvents.Cpus)
-	n := int64(m.monitorEvents.Npages)
-	p := int64(m.monitorEvents.Pagesize)
-	l, _, u := m.monitorEvents.Stats()
-	ms := models.MonitorStatus{Cpus: c, Npages: n, Pagesize: p, Lost: int64(l), Unknown: int64(u)}
+	ms := getMonitorStatus(m.monitorEvents)
 
 	mp, err := json.Marshal(ms)
 	if err != nil {
@@ -255,6 +252,26 @@ func (m *Monitor) dumpStat() {
 	fmt.Println(string(mp))
 }
 
+// getMonitorStatus returns the configuration and the statistics of the perf
+// ring buffers
+func getMonitorStatus(e *bpf.PerCpuEvents) models.MonitorStatus {
+	l, _, u := e.Stats()
+	ms := models.MonitorStatus{
+		Cpus:         int64(e.Cpus),
+		Npages:       int64(e.Npages),
+		Pagesize:     int64(e.Pagesize),
+		WakeupEvents: int64(e.WakeupEvents),
+		Overwrite:    e.Overwrite,
+		Lost:         int64(l),
+		Overwritten:  int64(e.Overwritten()),
+		Unknown:      int64(u),
+	}
+	for _, lost := range e.LostPerCPU() {
+		ms.LostPerCPU = append(ms.LostPerCPU, int64(lost))
+	}
+	return ms
+}
+
 // connectionHandler1_0 handles all the incoming connections and sets up the
 // listener objects. It will block on Accept, but expects the caller to close
 // server, inducing a return.
@@ -301,6 +318,29 @@ func (m *Monitor) connectionHandler1_2(parentCtx context.Context, server net.Lis
 	}
 }
 
+// connectionHandler1_3 handles all the incoming connections and sets up the
+// listener objects. It will block on Accept, but expects the caller to close
+// server, inducing a return.
+func (m *Monitor) connectionHandler1_3(parentCtx context.Context, server net.Listener) {
+	for !isCtxDone(parentCtx) {
+		conn, err := server.Accept()
+		switch {
+		case isCtxDone(parentCtx) && conn != nil:
+			conn.Close()
+			fallthrough
+
+		case isCtxDone(parentCtx) && conn == nil:
+			return
+
+		case err != nil:
+			log.WithError(err).Warn("Error accepting connection")
+			continue
+		}
+
+		m.registerNewListener(parentCtx, conn, listener.Version1_3)
+	}
+}
+
 // send enqueues the payload to all listeners.
 func (m *Monitor) send(pl *payload.Payload) {
 	m.Lock()
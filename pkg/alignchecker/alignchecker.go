@@ -28,6 +28,7 @@ package alignchecker
 #include "node_config.h"
 #include "lib/conntrack.h"
 #include "lib/maps.h"
+#include "lib/nat.h"
 */
 import "C"
 
@@ -42,6 +43,8 @@ import (
 	"github.com/cilium/cilium/pkg/maps/lbmap"
 	"github.com/cilium/cilium/pkg/maps/lxcmap"
 	"github.com/cilium/cilium/pkg/maps/metricsmap"
+	"github.com/cilium/cilium/pkg/maps/natmap"
+	"github.com/cilium/cilium/pkg/maps/neighmap"
 	"github.com/cilium/cilium/pkg/maps/proxymap"
 )
 
@@ -141,6 +144,18 @@ var cToGO = map[reflect.Type]valueToCheck{
 		sizeOfC:  C.sizeof_struct_proxy6_tbl_value,
 		goStruct: reflect.TypeOf(proxymap.Proxy6Value{}),
 	},
+	reflect.TypeOf(C.struct_ipv4_nat_key{}): {
+		sizeOfC:  C.sizeof_struct_ipv4_nat_key,
+		goStruct: reflect.TypeOf(natmap.NatKey4{}),
+	},
+	reflect.TypeOf(C.struct_ipv4_nat_entry{}): {
+		sizeOfC:  C.sizeof_struct_ipv4_nat_entry,
+		goStruct: reflect.TypeOf(natmap.NatEntry4{}),
+	},
+	reflect.TypeOf(C.struct_node_neigh_entry{}): {
+		sizeOfC:  C.sizeof_struct_node_neigh_entry,
+		goStruct: reflect.TypeOf(neighmap.Entry{}),
+	},
 }
 
 func init() {
@@ -37,4 +37,13 @@ const (
 	// CiliumHostIP is the annotation name used to store the IPv4 address
 	// of the cilium host interface in the node's annotations.
 	CiliumHostIP = "io.cilium.network.ipv4-cilium-host"
+
+	// EgressBandwidth is the annotation name used to limit the egress
+	// bandwidth of a pod, e.g. "10M" for 10 Mbit/s. The name is shared with
+	// the bandwidth CNI plugin.
+	EgressBandwidth = "kubernetes.io/egress-bandwidth"
+
+	// DNSVisibility is the annotation name used to redirect the DNS
+	// traffic of a pod to the DNS proxy for visibility, e.g. "true".
+	DNSVisibility = "io.cilium.proxy-visibility.dns"
 )
@@ -0,0 +1,70 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+)
+
+const (
+	// SchemaVersion is the version of the API schema implemented by this
+	// agent and expected by this client. It must be kept in sync with
+	// info.version of api/v1/openapi.yaml.
+	SchemaVersion = "v1beta"
+
+	// SchemaVersionHeader is the response header carrying the version of
+	// the API schema implemented by the agent.
+	SchemaVersionHeader = "Cilium-Api-Version"
+
+	// DeprecationHeader is set to "true" in responses to API operations
+	// which are marked as deprecated and will be removed in the future.
+	DeprecationHeader = "Deprecation"
+
+	// WarningHeader carries a human readable deprecation notice.
+	WarningHeader = "Warning"
+)
+
+// APIResponseHandler negotiates the wire format of API responses, annotates
+// all responses with the API schema version and marks responses of deprecated
+// operations with a deprecation header so that clients can warn about them
+// before the operations are removed.
+type APIResponseHandler struct {
+	Next    http.Handler
+	Context *middleware.Context
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *APIResponseHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The go-openapi middleware prefers any non-default media type for
+	// requests accepting everything, only respond with YAML if the client
+	// prefers it over JSON.
+	req.Header.Set(runtime.HeaderAccept, middleware.NegotiateContentType(req,
+		[]string{runtime.JSONMime, runtime.YAMLMime}, runtime.JSONMime))
+
+	rw.Header().Set(SchemaVersionHeader, SchemaVersion)
+
+	if route, _, ok := h.Context.RouteInfo(req); ok &&
+		route.Operation != nil && route.Operation.Deprecated {
+		rw.Header().Set(DeprecationHeader, "true")
+		rw.Header().Set(WarningHeader, fmt.Sprintf("299 cilium %q",
+			fmt.Sprintf("%s %s is deprecated", req.Method, route.PathPattern)))
+	}
+
+	h.Next.ServeHTTP(rw, req)
+}
@@ -0,0 +1,121 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/runtime/middleware/untyped"
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type APISuite struct{}
+
+var _ = Suite(&APISuite{})
+
+const testSpec = `{
+  "swagger": "2.0",
+  "info": {"title": "test", "version": "v1beta"},
+  "basePath": "/v1",
+  "produces": ["application/json", "application/x-yaml"],
+  "paths": {
+    "/current": {"get": {"responses": {"200": {"description": "Success"}}}},
+    "/old": {"get": {"deprecated": true, "responses": {"200": {"description": "Success"}}}}
+  }
+}`
+
+func (s *APISuite) TestYAML(c *C) {
+	status := &models.EndpointStatusChange{
+		Code:    "ok",
+		Message: "Policy revision 2 applied",
+		State:   models.EndpointStateReady,
+	}
+
+	buf := &bytes.Buffer{}
+	err := YAMLProducer().Produce(buf, status)
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, "code: ok\nmessage: Policy revision 2 applied\nstate: ready\n")
+
+	decoded := &models.EndpointStatusChange{}
+	err = YAMLConsumer().Consume(buf, decoded)
+	c.Assert(err, IsNil)
+	c.Assert(decoded, DeepEquals, status)
+}
+
+func (s *APISuite) TestAPIResponseHandler(c *C) {
+	spec, err := loads.Analyzed(json.RawMessage(testSpec), "")
+	c.Assert(err, IsNil)
+
+	api := untyped.NewAPI(spec)
+	api.RegisterProducer(runtime.YAMLMime, YAMLProducer())
+	ok := runtime.OperationHandlerFunc(func(interface{}) (interface{}, error) {
+		return map[string]string{"foo": "bar"}, nil
+	})
+	api.RegisterOperation("GET", "/current", ok)
+	api.RegisterOperation("GET", "/old", ok)
+
+	ctx := middleware.NewContext(spec, api, nil)
+	handler := ctx.APIHandler(func(next http.Handler) http.Handler {
+		return &APIResponseHandler{Next: next, Context: ctx}
+	})
+
+	req := httptest.NewRequest("GET", "/v1/current", nil)
+	req.Header.Set(runtime.HeaderAccept, runtime.YAMLMime)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	c.Assert(rec.Code, Equals, http.StatusOK)
+	c.Assert(rec.Header().Get(runtime.HeaderContentType), Equals, runtime.YAMLMime)
+	c.Assert(rec.Body.String(), Equals, "foo: bar\n")
+	c.Assert(rec.Header().Get(SchemaVersionHeader), Equals, SchemaVersion)
+	c.Assert(rec.Header().Get(DeprecationHeader), Equals, "")
+
+	req = httptest.NewRequest("GET", "/v1/old", nil)
+	req.Header.Set(runtime.HeaderAccept, "*/*")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	c.Assert(rec.Code, Equals, http.StatusOK)
+	c.Assert(rec.Header().Get(runtime.HeaderContentType), Equals, runtime.JSONMime)
+	c.Assert(rec.Header().Get(SchemaVersionHeader), Equals, SchemaVersion)
+	c.Assert(rec.Header().Get(DeprecationHeader), Equals, "true")
+	c.Assert(rec.Header().Get(WarningHeader), Equals, `299 cilium "GET /v1/old is deprecated"`)
+
+	// JSON is preferred unless the client prefers YAML
+	for accept, mime := range map[string]string{
+		"":                                     runtime.JSONMime,
+		"application/json, application/x-yaml": runtime.JSONMime,
+		"application/x-yaml, application/json;q=0.5": runtime.YAMLMime,
+		"text/plain": runtime.JSONMime,
+	} {
+		req = httptest.NewRequest("GET", "/v1/current", nil)
+		req.Header.Set(runtime.HeaderAccept, accept)
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		c.Assert(rec.Header().Get(runtime.HeaderContentType), Equals, mime, Commentf("Accept: %s", accept))
+	}
+}
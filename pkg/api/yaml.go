@@ -0,0 +1,49 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	"github.com/go-openapi/runtime"
+)
+
+// YAMLConsumer returns a runtime.Consumer decoding YAML request and response
+// bodies. The YAML is converted to JSON first so that the JSON field names
+// and custom JSON unmarshallers of the API models are honored.
+func YAMLConsumer() runtime.Consumer {
+	return runtime.ConsumerFunc(func(reader io.Reader, data interface{}) error {
+		buf, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		return yaml.Unmarshal(buf, data)
+	})
+}
+
+// YAMLProducer returns a runtime.Producer encoding request and response
+// bodies as YAML, using the JSON field names of the API models.
+func YAMLProducer() runtime.Producer {
+	return runtime.ProducerFunc(func(writer io.Writer, data interface{}) error {
+		buf, err := yaml.Marshal(data)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write(buf)
+		return err
+	})
+}
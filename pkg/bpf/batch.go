@@ -0,0 +1,239 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// BPF syscall commands operating on many elements at once. Must match
+	// enum bpf_cmd from linux/bpf.h
+	BPF_MAP_UPDATE_BATCH = 26
+	BPF_MAP_DELETE_BATCH = 27
+
+	// errnoNotSupported is ENOTSUPP, returned by the kernel for map types
+	// which do not implement batch operations
+	errnoNotSupported = syscall.Errno(524)
+)
+
+// This struct must be in sync with union bpf_attr's anonymous struct used by
+// BPF_MAP_*_BATCH commands
+type bpfAttrMapOpBatch struct {
+	inBatch   uint64
+	outBatch  uint64
+	keys      uint64
+	values    uint64
+	count     uint32
+	mapFd     uint32
+	elemFlags uint64
+	flags     uint64
+}
+
+// batchElements runs the batch command cmd on count elements. Returns the
+// number of elements processed before the first failure. The count is
+// returned unchanged if the kernel rejects the command itself.
+func batchElements(cmd int, fd int, keys, values []byte, count int) (int, syscall.Errno) {
+	uba := bpfAttrMapOpBatch{
+		keys:  uint64(uintptr(unsafe.Pointer(&keys[0]))),
+		count: uint32(count),
+		mapFd: uint32(fd),
+	}
+	if values != nil {
+		uba.values = uint64(uintptr(unsafe.Pointer(&values[0])))
+	}
+
+	_, _, err := unix.Syscall(
+		unix.SYS_BPF,
+		uintptr(cmd),
+		uintptr(unsafe.Pointer(&uba)),
+		unsafe.Sizeof(uba),
+	)
+
+	return int(uba.count), err
+}
+
+// batchOps performs the map operations of a MapBatch
+type batchOps interface {
+	updateBatch(fd int, keys, values []byte, count int) (int, syscall.Errno)
+	deleteBatch(fd int, keys []byte, count int) (int, syscall.Errno)
+	update(fd int, key, value []byte) error
+	delete(fd int, key []byte) error
+}
+
+type syscallBatchOps struct{}
+
+func (syscallBatchOps) updateBatch(fd int, keys, values []byte, count int) (int, syscall.Errno) {
+	return batchElements(BPF_MAP_UPDATE_BATCH, fd, keys, values, count)
+}
+
+func (syscallBatchOps) deleteBatch(fd int, keys []byte, count int) (int, syscall.Errno) {
+	return batchElements(BPF_MAP_DELETE_BATCH, fd, keys, nil, count)
+}
+
+func (syscallBatchOps) update(fd int, key, value []byte) error {
+	return UpdateElement(fd, unsafe.Pointer(&key[0]), unsafe.Pointer(&value[0]), 0)
+}
+
+func (syscallBatchOps) delete(fd int, key []byte) error {
+	return DeleteElement(fd, unsafe.Pointer(&key[0]))
+}
+
+// batchUnsupported returns true if errno indicates that the kernel or the
+// map type does not support batch operations.
+func batchUnsupported(errno syscall.Errno) bool {
+	switch errno {
+	case unix.EINVAL, unix.EOPNOTSUPP, errnoNotSupported:
+		return true
+	}
+	return false
+}
+
+// MapBatch collects updates and deletions of map elements and applies them
+// with a single syscall per kind of operation. Kernels or map types lacking
+// batch operations are detected on the first flush, the operations are then
+// applied element by element.
+//
+// A MapBatch is not safe for concurrent use.
+type MapBatch struct {
+	fd        int
+	keySize   int
+	valueSize int
+	ops       batchOps
+
+	// unsupported is true once batch operations were rejected
+	unsupported bool
+
+	// updateKeys and updateValues hold the keys and values of all updates,
+	// updateIdx the index of each update in the order of all operations
+	updateKeys   []byte
+	updateValues []byte
+	updateIdx    []int
+
+	// deleteKeys holds the keys of all deletions, deleteIdx the index of
+	// each deletion in the order of all operations
+	deleteKeys []byte
+	deleteIdx  []int
+}
+
+// NewMapBatch returns a batch for the map in fd with the given key and value
+// sizes.
+func NewMapBatch(fd, keySize, valueSize int) *MapBatch {
+	return &MapBatch{
+		fd:        fd,
+		keySize:   keySize,
+		valueSize: valueSize,
+		ops:       syscallBatchOps{},
+	}
+}
+
+// Len returns the number of pending operations
+func (b *MapBatch) Len() int {
+	return len(b.updateIdx) + len(b.deleteIdx)
+}
+
+// Update queues an update of the element with the given key to value. The
+// key and value are copied. Returns the index of the operation.
+func (b *MapBatch) Update(key, value unsafe.Pointer) int {
+	idx := b.Len()
+	b.updateKeys = append(b.updateKeys, (*[1 << 16]byte)(key)[:b.keySize]...)
+	b.updateValues = append(b.updateValues, (*[1 << 16]byte)(value)[:b.valueSize]...)
+	b.updateIdx = append(b.updateIdx, idx)
+	return idx
+}
+
+// Delete queues a deletion of the element with the given key. The key is
+// copied. Returns the index of the operation.
+func (b *MapBatch) Delete(key unsafe.Pointer) int {
+	idx := b.Len()
+	b.deleteKeys = append(b.deleteKeys, (*[1 << 16]byte)(key)[:b.keySize]...)
+	b.deleteIdx = append(b.deleteIdx, idx)
+	return idx
+}
+
+// Flush applies all pending operations and resets the batch. Deletions are
+// applied before updates so that a full map has room for new elements.
+// Returns the errors of all failed operations, keyed by the index of the
+// operation.
+func (b *MapBatch) Flush() map[int]error {
+	errors := map[int]error{}
+
+	deleteOne := func(i int) error {
+		return b.ops.delete(b.fd, b.deleteKeys[i*b.keySize:(i+1)*b.keySize])
+	}
+	deleteMany := func(i, n int) (int, syscall.Errno) {
+		return b.ops.deleteBatch(b.fd, b.deleteKeys[i*b.keySize:], n)
+	}
+	b.flush(b.deleteIdx, deleteMany, deleteOne, errors)
+
+	updateOne := func(i int) error {
+		return b.ops.update(b.fd,
+			b.updateKeys[i*b.keySize:(i+1)*b.keySize],
+			b.updateValues[i*b.valueSize:(i+1)*b.valueSize])
+	}
+	updateMany := func(i, n int) (int, syscall.Errno) {
+		return b.ops.updateBatch(b.fd, b.updateKeys[i*b.keySize:], b.updateValues[i*b.valueSize:], n)
+	}
+	b.flush(b.updateIdx, updateMany, updateOne, errors)
+
+	b.updateKeys, b.updateValues, b.updateIdx = b.updateKeys[:0], b.updateValues[:0], b.updateIdx[:0]
+	b.deleteKeys, b.deleteIdx = b.deleteKeys[:0], b.deleteIdx[:0]
+
+	return errors
+}
+
+// flush applies the operations in idx. The batch stops at the first failing
+// element, the element is then retried on its own to retrieve its error and
+// the batch continues with the following element.
+func (b *MapBatch) flush(idx []int, many func(i, n int) (int, syscall.Errno), one func(i int) error, errors map[int]error) {
+	for i := 0; i < len(idx); {
+		if b.unsupported {
+			if err := one(i); err != nil {
+				errors[idx[i]] = err
+			}
+			i++
+			continue
+		}
+
+		n := len(idx) - i
+		done, errno := many(i, n)
+		if errno == 0 {
+			return
+		}
+		// The kernel leaves the count untouched if it rejects the batch
+		// command as a whole, e.g. because it doesn't know it. Nothing
+		// has been applied then.
+		if done >= n {
+			done = 0
+		}
+		i += done
+		if i >= len(idx) {
+			return
+		}
+
+		err := one(i)
+		switch {
+		case err != nil:
+			errors[idx[i]] = err
+		case done == 0 && batchUnsupported(errno):
+			log.WithField("fd", b.fd).Debug("Batch operations not supported, falling back to single element operations")
+			b.unsupported = true
+		}
+		i++
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+	. "gopkg.in/check.v1"
+)
+
+// fakeBatchOps is a map with single byte keys and values, keys with the
+// value 0xff cannot be updated
+type fakeBatchOps struct {
+	elems       map[byte]byte
+	batchErrno  syscall.Errno
+	batchCalls  int
+	singleCalls int
+}
+
+func (f *fakeBatchOps) updateBatch(fd int, keys, values []byte, count int) (int, syscall.Errno) {
+	f.batchCalls++
+	if f.batchErrno != 0 {
+		// Like the kernel, leave the count untouched
+		return count, f.batchErrno
+	}
+	for i := 0; i < count; i++ {
+		if keys[i] == 0xff {
+			return i, unix.E2BIG
+		}
+		f.elems[keys[i]] = values[i]
+	}
+	return count, 0
+}
+
+func (f *fakeBatchOps) deleteBatch(fd int, keys []byte, count int) (int, syscall.Errno) {
+	f.batchCalls++
+	if f.batchErrno != 0 {
+		// Like the kernel, leave the count untouched
+		return count, f.batchErrno
+	}
+	for i := 0; i < count; i++ {
+		if _, ok := f.elems[keys[i]]; !ok {
+			return i, unix.ENOENT
+		}
+		delete(f.elems, keys[i])
+	}
+	return count, 0
+}
+
+func (f *fakeBatchOps) update(fd int, key, value []byte) error {
+	f.singleCalls++
+	if key[0] == 0xff {
+		return fmt.Errorf("full")
+	}
+	f.elems[key[0]] = value[0]
+	return nil
+}
+
+func (f *fakeBatchOps) delete(fd int, key []byte) error {
+	f.singleCalls++
+	if _, ok := f.elems[key[0]]; !ok {
+		return fmt.Errorf("not found")
+	}
+	delete(f.elems, key[0])
+	return nil
+}
+
+func newFakeBatch(ops *fakeBatchOps) *MapBatch {
+	b := NewMapBatch(0, 1, 1)
+	b.ops = ops
+	return b
+}
+
+func batchUpdate(b *MapBatch, key, value byte) int {
+	return b.Update(unsafe.Pointer(&key), unsafe.Pointer(&value))
+}
+
+func batchDelete(b *MapBatch, key byte) int {
+	return b.Delete(unsafe.Pointer(&key))
+}
+
+func (s *BPFTestSuite) TestMapBatch(c *C) {
+	ops := &fakeBatchOps{elems: map[byte]byte{1: 1, 2: 2}}
+	b := newFakeBatch(ops)
+
+	c.Assert(batchDelete(b, 1), Equals, 0)
+	c.Assert(batchUpdate(b, 3, 3), Equals, 1)
+	c.Assert(batchDelete(b, 2), Equals, 2)
+	c.Assert(batchUpdate(b, 4, 4), Equals, 3)
+	c.Assert(b.Len(), Equals, 4)
+
+	errors := b.Flush()
+	c.Assert(errors, HasLen, 0)
+	c.Assert(ops.elems, DeepEquals, map[byte]byte{3: 3, 4: 4})
+	c.Assert(ops.batchCalls, Equals, 2)
+	c.Assert(ops.singleCalls, Equals, 0)
+	c.Assert(b.Len(), Equals, 0)
+
+	// Failing elements are reported, the remaining elements are applied
+	ops.batchCalls = 0
+	batchDelete(b, 5)
+	batchDelete(b, 3)
+	batchUpdate(b, 6, 6)
+	batchUpdate(b, 0xff, 1)
+	batchUpdate(b, 7, 7)
+
+	errors = b.Flush()
+	c.Assert(errors, HasLen, 2)
+	c.Assert(errors[0], Not(IsNil))
+	c.Assert(errors[3], Not(IsNil))
+	c.Assert(ops.elems, DeepEquals, map[byte]byte{4: 4, 6: 6, 7: 7})
+	c.Assert(ops.batchCalls, Equals, 4)
+	c.Assert(ops.singleCalls, Equals, 2)
+}
+
+func (s *BPFTestSuite) TestMapBatchUnsupported(c *C) {
+	ops := &fakeBatchOps{elems: map[byte]byte{1: 1}, batchErrno: unix.EINVAL}
+	b := newFakeBatch(ops)
+
+	batchDelete(b, 1)
+	batchUpdate(b, 2, 2)
+	batchUpdate(b, 0xff, 1)
+	batchUpdate(b, 3, 3)
+
+	errors := b.Flush()
+	c.Assert(errors, HasLen, 1)
+	c.Assert(errors[2], Not(IsNil))
+	c.Assert(ops.elems, DeepEquals, map[byte]byte{2: 2, 3: 3})
+	c.Assert(b.unsupported, Equals, true)
+
+	// Batch operations are only attempted once
+	c.Assert(ops.batchCalls, Equals, 1)
+	c.Assert(ops.singleCalls, Equals, 4)
+}
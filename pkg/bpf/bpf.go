@@ -242,6 +242,36 @@ func GetNextKey(fd int, key, nextKey unsafe.Pointer) error {
 	return nil
 }
 
+// IterateKeys calls cb with the key of each entry of the map in fd until cb
+// returns an error, which is then returned. The key is reused between calls.
+// Iteration starts at the key following the all-zero key, which is passed to
+// cb first if it is present in the map. The number of keys following the
+// all-zero key is capped at maxEntries as the iteration restarts from the
+// first key if the current key is removed concurrently.
+func IterateKeys(fd int, keySize, valueSize, maxEntries uint32, cb func(key []byte) error) error {
+	key := make([]byte, keySize)
+	nextKey := make([]byte, keySize)
+	value := make([]byte, valueSize)
+
+	if LookupElement(fd, unsafe.Pointer(&key[0]), unsafe.Pointer(&value[0])) == nil {
+		if err := cb(key); err != nil {
+			return err
+		}
+	}
+
+	for i := uint32(0); i < maxEntries; i++ {
+		if GetNextKey(fd, unsafe.Pointer(&key[0]), unsafe.Pointer(&nextKey[0])) != nil {
+			break
+		}
+		copy(key, nextKey)
+		if err := cb(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // This struct must be in sync with union bpf_attr's anonymous struct used by
 // BPF_OBJ_*_ commands
 type bpfAttrObjOp struct {
@@ -303,13 +333,27 @@ func ObjClose(fd int) error {
 	return nil
 }
 
-func objCheck(fd int, path string, mapType int, keySize, valueSize, maxEntries, flags uint32) bool {
+// objCheck compares the properties of the map in fd pinned at path with the
+// desired properties. If only the maximum number of entries or the choice
+// between a hash and an LRU hash map differs, the entries are migrated to a
+// new map with the desired properties pinned at path and migrated is true.
+// Otherwise, on mismatch, the map is removed and redo is true.
+func objCheck(fd int, path string, mapType int, keySize, valueSize, maxEntries, flags uint32) (redo, migrated bool) {
 	info, err := GetMapInfo(os.Getpid(), fd)
 	if err != nil {
-		return false
+		return false, false
 	}
 
 	scopedLog := log.WithField(logfields.Path, path)
+
+	if canMigrate(info, mapType, keySize, valueSize, maxEntries, flags) {
+		err := migrateMap(fd, path, info, mapType, maxEntries)
+		if err == nil {
+			return false, true
+		}
+		scopedLog.WithError(err).Warning("Unable to migrate entries of BPF map")
+	}
+
 	mismatch := false
 
 	if int(info.MapType) != mapType {
@@ -353,7 +397,7 @@ func objCheck(fd int, path string, mapType int, keySize, valueSize, maxEntries,
 
 	if mismatch {
 		if info.MapType == MapTypeProgArray {
-			return false
+			return false, false
 		}
 
 		scopedLog.Info("Removing map to allow for property upgrade (expect map data loss)")
@@ -362,10 +406,10 @@ func objCheck(fd int, path string, mapType int, keySize, valueSize, maxEntries,
 		// Only exception is prog array, but that is already resolved
 		// differently.
 		os.Remove(path)
-		return true
+		return true, false
 	}
 
-	return false
+	return false, false
 }
 
 func OpenOrCreateMap(path string, mapType int, keySize, valueSize, maxEntries, flags uint32) (int, bool, error) {
@@ -428,7 +472,8 @@ recreate:
 
 	fd, err = ObjGet(path)
 	if err == nil {
-		redo = objCheck(
+		var migrated bool
+		redo, migrated = objCheck(
 			fd,
 			path,
 			mapType,
@@ -437,7 +482,10 @@ recreate:
 			maxEntries,
 			flags,
 		)
-		if redo == true {
+		if migrated {
+			ObjClose(fd)
+			fd, err = ObjGet(path)
+		} else if redo == true {
 			ObjClose(fd)
 			goto recreate
 		}
@@ -35,6 +35,13 @@ var (
 	// Prefix for all maps (default: tc/globals)
 	mapPrefix = defaults.DefaultMapPrefix
 
+	// Optional subdirectory of mapRoot under which all BPF objects are
+	// pinned, allowing multiple agents to share a single BPF filesystem
+	pinPrefix = ""
+
+	// Set to true if the map root has been configured explicitly
+	mapRootSet = false
+
 	// Set to true on first get request to detect misorder
 	lockedDown      = false
 	once            sync.Once
@@ -51,11 +58,30 @@ func SetMapRoot(path string) {
 		panic("SetMapRoot() call after MapRoot was read")
 	}
 	mapRoot = path
+	mapRootSet = true
 }
 
+// GetMapRoot returns the directory in which BPF objects are pinned. This is
+// the BPF filesystem mount point extended by the pin prefix, if any.
 func GetMapRoot() string {
 	once.Do(lockDown)
-	return mapRoot
+	return filepath.Join(mapRoot, pinPrefix)
+}
+
+// SetPinPrefix sets the subdirectory of the BPF filesystem in which all BPF
+// objects are pinned.
+func SetPinPrefix(prefix string) {
+	if lockedDown {
+		panic("SetPinPrefix() call after MapRoot was read")
+	}
+	pinPrefix = prefix
+}
+
+// GetPinPrefix returns the subdirectory of the BPF filesystem in which all
+// BPF objects are pinned.
+func GetPinPrefix() string {
+	once.Do(lockDown)
+	return pinPrefix
 }
 
 func SetMapPrefix(path string) {
@@ -72,7 +98,7 @@ func GetMapPrefix() string {
 
 func MapPrefixPath() string {
 	once.Do(lockDown)
-	return filepath.Join(mapRoot, mapPrefix)
+	return filepath.Join(mapRoot, pinPrefix, mapPrefix)
 }
 
 func mapPathFromMountInfo(name string) string {
@@ -84,7 +110,7 @@ func mapPathFromMountInfo(name string) string {
 
 		for _, mountInfo := range mountInfos {
 			if mountInfo.FilesystemType == mountinfo.FilesystemTypeBPFFS {
-				mountInfoPrefix = filepath.Join(mountInfo.MountPoint, mapPrefix)
+				mountInfoPrefix = filepath.Join(mountInfo.MountPoint, pinPrefix, mapPrefix)
 				return
 			}
 		}
@@ -95,11 +121,13 @@ func mapPathFromMountInfo(name string) string {
 	return filepath.Join(mountInfoPrefix, name)
 }
 
-// MapPath returns a path for a BPF map with a given name.
+// MapPath returns a path for a BPF map with a given name. Components other
+// than the agent look up the BPF filesystem in the mount table unless the
+// map root has been set explicitly.
 func MapPath(name string) string {
-	if components.IsCiliumAgent() {
+	if components.IsCiliumAgent() || mapRootSet {
 		once.Do(lockDown)
-		return filepath.Join(mapRoot, mapPrefix, name)
+		return filepath.Join(mapRoot, pinPrefix, mapPrefix, name)
 	}
 	return mapPathFromMountInfo(name)
 }
@@ -315,6 +343,13 @@ func checkOrMountFS(bpfRoot string) error {
 		return fmt.Errorf("multiple mount points detected at %s", mapRoot)
 	}
 
+	// tc requires the directory in which it pins objects to exist.
+	if pinPrefix != "" {
+		if err := os.MkdirAll(filepath.Join(mapRoot, pinPrefix), 0755); err != nil {
+			return fmt.Errorf("unable to create pin prefix directory: %s", err)
+		}
+	}
+
 	mountMutex.Lock()
 	for _, m := range delayedOpens {
 		if _, err := m.OpenOrCreate(); err != nil {
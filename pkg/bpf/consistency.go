@@ -0,0 +1,220 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DriftMissing is the kind of drift of desired entries which are
+	// missing in the map
+	DriftMissing = "missing"
+
+	// DriftMismatch is the kind of drift of entries whose value differs
+	// from the desired value
+	DriftMismatch = "mismatch"
+
+	// DriftStale is the kind of drift of entries in the map which are not
+	// desired
+	DriftStale = "stale"
+)
+
+// MapDrift is the number of entries of a map which differ from the desired
+// state
+type MapDrift struct {
+	// Missing is the number of desired entries missing in the map
+	Missing int
+
+	// Mismatch is the number of entries with a value other than the
+	// desired value
+	Mismatch int
+
+	// Stale is the number of entries in the map which are not desired
+	Stale int
+}
+
+// Total returns the number of entries which differ from the desired state
+func (d MapDrift) Total() int {
+	return d.Missing + d.Mismatch + d.Stale
+}
+
+// Add adds the drift of another map
+func (d *MapDrift) Add(o MapDrift) {
+	d.Missing += o.Missing
+	d.Mismatch += o.Mismatch
+	d.Stale += o.Stale
+}
+
+func (d MapDrift) String() string {
+	return fmt.Sprintf("%d missing, %d mismatching, %d stale entries", d.Missing, d.Mismatch, d.Stale)
+}
+
+// SetMetrics exports the drift as metric of the map with the given name
+func (d MapDrift) SetMetrics(mapName string) {
+	metrics.BPFMapDrift.WithLabelValues(mapName, DriftMissing).Set(float64(d.Missing))
+	metrics.BPFMapDrift.WithLabelValues(mapName, DriftMismatch).Set(float64(d.Mismatch))
+	metrics.BPFMapDrift.WithLabelValues(mapName, DriftStale).Set(float64(d.Stale))
+}
+
+// diffCache compares the cache with the actual entries of a map, keyed by the
+// string representation of their keys. Entries with pending operations are
+// ignored as they are retried by the error resolver. Returns the drift and the
+// cache entries which need to be reinserted to restore the desired state.
+func diffCache(cache map[string]*cacheEntry, actual map[string][]byte, valueSize int) (MapDrift, []*cacheEntry) {
+	var (
+		drift    MapDrift
+		reinsert []*cacheEntry
+	)
+
+	for k, entry := range cache {
+		if entry.DesiredAction != OK {
+			continue
+		}
+		value, ok := actual[k]
+		switch {
+		case !ok:
+			drift.Missing++
+		case !bytes.Equal(value, valueBytes(entry.Value, valueSize)):
+			drift.Mismatch++
+		default:
+			continue
+		}
+		reinsert = append(reinsert, entry)
+	}
+
+	for k := range actual {
+		if _, ok := cache[k]; !ok {
+			drift.Stale++
+		}
+	}
+
+	return drift, reinsert
+}
+
+// valueBytes returns the raw bytes of a map value of the given size
+func valueBytes(value MapValue, size int) []byte {
+	return (*[1 << 16]byte)(value.GetValuePtr())[:size:size]
+}
+
+// dumpRawLocked returns the raw values of all entries of the map keyed by the
+// string representation of their keys. m.lock must be held.
+func (m *Map) dumpRawLocked() (map[string][]byte, error) {
+	if m.dumpParser == nil {
+		return nil, fmt.Errorf("map %s has no dump parser", m.name)
+	}
+
+	actual := map[string][]byte{}
+	err := m.iterateRawLocked(func(key, value []byte) error {
+		k, _, err := m.dumpParser(key, value)
+		if err != nil {
+			return err
+		}
+		actual[k.String()] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return actual, nil
+}
+
+// CheckCache compares the cache of the map, holding the entries inserted by
+// the agent, with the entries in the kernel. If repair is true, missing and
+// mismatching entries are reinserted. Stale entries are only reported as
+// they may have been inserted before the cache was populated.
+func (m *Map) CheckCache(repair bool) (MapDrift, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.cache == nil {
+		return MapDrift{}, fmt.Errorf("map %s has no cache", m.name)
+	}
+
+	if err := m.Open(); err != nil {
+		return MapDrift{}, err
+	}
+
+	actual, err := m.dumpRawLocked()
+	if err != nil {
+		return MapDrift{}, err
+	}
+
+	drift, reinsert := diffCache(m.cache, actual, int(m.ValueSize))
+	if !repair {
+		return drift, nil
+	}
+
+	for _, entry := range reinsert {
+		err := UpdateElement(m.fd, entry.Key.GetKeyPtr(), entry.Value.GetValuePtr(), 0)
+		m.recordEvent(MapEventUpdate, entry.Key, entry.Value, err, checkerCaller)
+		if err != nil {
+			entry.DesiredAction = Insert
+			entry.LastError = err
+			m.scheduleErrorResolver()
+			continue
+		}
+		metrics.BPFMapRepairs.WithLabelValues(m.name).Inc()
+	}
+
+	return drift, nil
+}
+
+// CheckCachedMaps checks all open maps with a cache for entries which differ
+// from the desired state and exports the drift as metrics. If repair is true,
+// missing and mismatching entries are reinserted.
+func CheckCachedMaps(repair bool) error {
+	mutex.RLock()
+	maps := make([]*Map, 0, len(mapRegister))
+	for _, m := range mapRegister {
+		maps = append(maps, m)
+	}
+	mutex.RUnlock()
+
+	var lastErr error
+	for _, m := range maps {
+		m.lock.RLock()
+		cached := m.cache != nil
+		m.lock.RUnlock()
+		if !cached {
+			continue
+		}
+
+		scopedLog := log.WithField(logfields.BPFMapName, m.name)
+		drift, err := m.CheckCache(repair)
+		if err != nil {
+			scopedLog.WithError(err).Debug("Unable to check consistency of BPF map")
+			lastErr = err
+			continue
+		}
+		drift.SetMetrics(m.name)
+
+		if drift.Missing+drift.Mismatch > 0 {
+			scopedLog.WithFields(logrus.Fields{
+				"drift":  drift.String(),
+				"repair": repair,
+			}).Warning("BPF map differs from the desired state")
+		}
+	}
+
+	return lastErr
+}
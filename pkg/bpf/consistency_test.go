@@ -0,0 +1,66 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func rawCacheEntry(key, value byte, action DesiredAction) *cacheEntry {
+	return &cacheEntry{
+		Key:           RawKey{key},
+		Value:         RawValue{value},
+		DesiredAction: action,
+	}
+}
+
+func (s *BPFTestSuite) TestDiffCache(c *C) {
+	cache := map[string]*cacheEntry{}
+	for _, entry := range []*cacheEntry{
+		rawCacheEntry(1, 1, OK),
+		rawCacheEntry(2, 2, OK),
+		rawCacheEntry(3, 3, OK),
+		rawCacheEntry(4, 4, Insert),
+		rawCacheEntry(5, 0, Delete),
+	} {
+		cache[entry.Key.String()] = entry
+	}
+
+	actual := map[string][]byte{
+		RawKey{1}.String(): {1},
+		RawKey{3}.String(): {0xff},
+		RawKey{5}.String(): {5},
+		RawKey{6}.String(): {6},
+	}
+
+	drift, reinsert := diffCache(cache, actual, 1)
+	c.Assert(drift, Equals, MapDrift{Missing: 1, Mismatch: 1, Stale: 1})
+	c.Assert(drift.Total(), Equals, 3)
+	c.Assert(reinsert, HasLen, 2)
+
+	keys := map[string]bool{}
+	for _, entry := range reinsert {
+		keys[entry.Key.String()] = true
+	}
+	c.Assert(keys, DeepEquals, map[string]bool{
+		RawKey{2}.String(): true,
+		RawKey{3}.String(): true,
+	})
+
+	// A map matching its cache has no drift
+	drift, reinsert = diffCache(map[string]*cacheEntry{}, map[string][]byte{}, 1)
+	c.Assert(drift.Total(), Equals, 0)
+	c.Assert(reinsert, HasLen, 0)
+}
@@ -0,0 +1,214 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/lock"
+
+	"github.com/go-openapi/strfmt"
+)
+
+const (
+	// maxMapEvents is the number of most recent events retained per map
+	maxMapEvents = 128
+
+	// bpfFunctionPrefix and mapsFunctionPrefix are the function name
+	// prefixes of the map abstractions which are skipped when looking up
+	// the caller of a mutation
+	bpfFunctionPrefix  = "github.com/cilium/cilium/pkg/bpf."
+	mapsFunctionPrefix = "github.com/cilium/cilium/pkg/maps/"
+
+	// callerTrimPrefix is removed from the function name of callers
+	callerTrimPrefix = "github.com/cilium/cilium/"
+
+	// resolverCaller is the caller of events emitted while resolving
+	// errors of previous mutations
+	resolverCaller = "bpf-map-sync"
+
+	// checkerCaller is the caller of events emitted while repairing
+	// entries which differ from the desired state
+	checkerCaller = "bpf-map-check"
+)
+
+// MapEventAction is the type of mutation of a MapEvent
+type MapEventAction string
+
+const (
+	// MapEventUpdate is the creation or update of a map entry
+	MapEventUpdate MapEventAction = "update"
+
+	// MapEventDelete is the deletion of a map entry
+	MapEventDelete MapEventAction = "delete"
+
+	// MapEventDeleteAll is the deletion of all entries of a map
+	MapEventDeleteAll MapEventAction = "delete-all"
+)
+
+// MapEvent is a mutation of a BPF map performed by the agent
+type MapEvent struct {
+	// Seq is the sequence number of the event, starting at 1 for the
+	// first event of the map
+	Seq uint64
+
+	Timestamp time.Time
+	Action    MapEventAction
+	Key       string
+	Value     string
+
+	// Error is the error returned by the kernel, if any
+	Error error
+
+	// Caller is the function which requested the mutation
+	Caller string
+}
+
+// GetModel returns the event in the representation served via the API
+func (e *MapEvent) GetModel() *models.BPFMapEvent {
+	model := &models.BPFMapEvent{
+		Seq:       int64(e.Seq),
+		Timestamp: strfmt.DateTime(e.Timestamp),
+		Action:    string(e.Action),
+		Key:       e.Key,
+		Value:     e.Value,
+		Caller:    e.Caller,
+	}
+	if e.Error != nil {
+		model.Error = e.Error.Error()
+	}
+	return model
+}
+
+// mapEventsEnabled is true if map mutations are journaled
+var mapEventsEnabled bool
+
+// SetMapEventsEnabled enables or disables the journaling of map mutations.
+// It must be called before any map is opened.
+func SetMapEventsEnabled(enabled bool) {
+	mapEventsEnabled = enabled
+}
+
+// MapEventsEnabled returns true if map mutations are journaled
+func MapEventsEnabled() bool {
+	return mapEventsEnabled
+}
+
+// MapEvents is a ring buffer of the most recent events of a map
+type MapEvents struct {
+	mutex  lock.RWMutex
+	buffer []MapEvent
+	// next is the index in buffer at which the next event is stored
+	next int
+	// seq is the sequence number of the last event
+	seq uint64
+}
+
+// add stores event with the next sequence number, replacing the oldest event
+// if the buffer is full.
+//
+// e.mutex must be held for writing
+func (e *MapEvents) add(event MapEvent) {
+	e.seq++
+	event.Seq = e.seq
+
+	if len(e.buffer) < maxMapEvents {
+		e.buffer = append(e.buffer, event)
+		return
+	}
+	e.buffer[e.next] = event
+	e.next = (e.next + 1) % maxMapEvents
+}
+
+// Record adds an event to the journal if journaling of map mutations is
+// enabled. The key and value are only formatted in this case.
+func (e *MapEvents) Record(action MapEventAction, key, value fmt.Stringer, err error, caller string) {
+	if !mapEventsEnabled {
+		return
+	}
+
+	event := MapEvent{
+		Timestamp: time.Now(),
+		Action:    action,
+		Error:     err,
+		Caller:    caller,
+	}
+	if key != nil {
+		event.Key = key.String()
+	}
+	if value != nil {
+		event.Value = value.String()
+	}
+
+	e.mutex.Lock()
+	e.add(event)
+	e.mutex.Unlock()
+}
+
+// Since returns all retained events with a sequence number larger than seq,
+// oldest first.
+func (e *MapEvents) Since(seq uint64) []MapEvent {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	events := make([]MapEvent, 0, len(e.buffer))
+	for i := range e.buffer {
+		event := e.buffer[(e.next+i)%len(e.buffer)]
+		if event.Seq > seq {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// MapEventCaller returns the name of the first function on the stack which is
+// not part of the map abstractions in pkg/bpf and pkg/maps. It returns an
+// empty string without walking the stack if journaling of map mutations is
+// disabled.
+func MapEventCaller() string {
+	if !mapEventsEnabled {
+		return ""
+	}
+
+	pc := make([]uintptr, 16)
+	n := runtime.Callers(2, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, bpfFunctionPrefix) &&
+			!strings.HasPrefix(frame.Function, mapsFunctionPrefix) {
+			return strings.TrimPrefix(frame.Function, callerTrimPrefix)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// recordEvent adds an event to the journal of the map.
+func (m *Map) recordEvent(action MapEventAction, key MapKey, value MapValue, err error, caller string) {
+	var k, v fmt.Stringer
+	if key != nil {
+		k = key
+	}
+	if value != nil {
+		v = value
+	}
+	m.events.Record(action, k, v, err, caller)
+}
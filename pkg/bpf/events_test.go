@@ -0,0 +1,71 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"fmt"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BPFTestSuite) TestMapEvents(c *C) {
+	var e MapEvents
+
+	c.Assert(e.Since(0), HasLen, 0)
+
+	for i := 0; i < 3; i++ {
+		e.add(MapEvent{Action: MapEventUpdate, Key: fmt.Sprintf("%d", i)})
+	}
+	events := e.Since(0)
+	c.Assert(events, HasLen, 3)
+	c.Assert(events[0].Seq, Equals, uint64(1))
+	c.Assert(events[2].Key, Equals, "2")
+	c.Assert(e.Since(2), HasLen, 1)
+	c.Assert(e.Since(3), HasLen, 0)
+
+	// Overflow the buffer, only the most recent events are retained
+	for i := 3; i < maxMapEvents+10; i++ {
+		e.add(MapEvent{Action: MapEventDelete, Key: fmt.Sprintf("%d", i)})
+	}
+	events = e.Since(0)
+	c.Assert(events, HasLen, maxMapEvents)
+	c.Assert(events[0].Seq, Equals, uint64(11))
+	c.Assert(events[0].Key, Equals, "10")
+	c.Assert(events[maxMapEvents-1].Seq, Equals, uint64(maxMapEvents+10))
+
+	events = e.Since(uint64(maxMapEvents + 8))
+	c.Assert(events, HasLen, 2)
+	c.Assert(events[0].Seq, Equals, uint64(maxMapEvents+9))
+}
+
+func (s *BPFTestSuite) TestMapEventsRecord(c *C) {
+	var e MapEvents
+
+	defer SetMapEventsEnabled(MapEventsEnabled())
+
+	SetMapEventsEnabled(false)
+	e.Record(MapEventDeleteAll, nil, nil, nil, "")
+	c.Assert(e.Since(0), HasLen, 0)
+	c.Assert(MapEventCaller(), Equals, "")
+
+	SetMapEventsEnabled(true)
+	e.Record(MapEventDeleteAll, nil, nil, fmt.Errorf("failed"), "test")
+	events := e.Since(0)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].Action, Equals, MapEventDeleteAll)
+	c.Assert(events[0].Key, Equals, "")
+	c.Assert(events[0].Caller, Equals, "test")
+	c.Assert(events[0].Error, ErrorMatches, "failed")
+}
@@ -81,8 +81,24 @@ var (
 	// supportedMapTypes maps from a MapType to a bool indicating whether
 	// the currently running kernel supports the map type.
 	supportedMapTypes = make(map[MapType]bool)
+
+	// kernelFeatures maps from the name of a feature emitted by the kernel
+	// feature probes in bpf/probes to a bool indicating whether the
+	// currently running kernel supports the feature.
+	kernelFeatures = make(map[string]bool)
 )
 
+// probedKernelFeatures is the list of features emitted by the kernel feature
+// probes in bpf/probes. Features missing in bpf_features.h are unsupported.
+var probedKernelFeatures = []string{
+	"HAVE_LPM_MAP_TYPE",
+	"HAVE_LRU_MAP_TYPE",
+	"HAVE_MAP_VAL_ADJ",
+	"HAVE_MARK_MAP_VALS",
+	"HAVE_SET_HASH_INVALID",
+	"HAVE_SKB_CHANGE_TAIL",
+}
+
 func (t MapType) String() string {
 	switch t {
 	case MapTypeHash:
@@ -208,6 +224,20 @@ type Map struct {
 	// outstandingErrors is the number of outsanding errors syncing with
 	// the kernel
 	outstandingErrors int
+
+	// events is the journal of the most recent mutations of the map
+	events MapEvents
+
+	// pressure is the fill ratio of the map as of the last sample, nil
+	// if the map has not been sampled
+	pressure *models.BPFMapPressure
+
+	// schemaVersion is the version of the layout of keys and values, 0 if
+	// the map is not versioned
+	schemaVersion int
+
+	// converters convert entries of older schema versions, see WithSchema
+	converters map[int]MapConverter
 }
 
 // NewMap creates a new Map instance - object representing a BPF map
@@ -335,6 +365,9 @@ func GetMapInfo(pid int, fd int) (*MapInfo, error) {
 	return info, nil
 }
 
+// OpenMap opens the pinned BPF map with the given name or absolute path. The
+// entries of the map are decoded with the parser registered for the map name
+// via RegisterMapCodec, or returned as RawKey and RawValue otherwise.
 func OpenMap(name string) (*Map, error) {
 	// Expand path if needed
 	if !path.IsAbs(name) {
@@ -360,10 +393,14 @@ func OpenMap(name string) (*Map, error) {
 	}
 
 	m := &Map{
-		MapInfo: *info,
-		fd:      fd,
-		name:    path.Base(name),
-		path:    name,
+		MapInfo:    *info,
+		fd:         fd,
+		name:       path.Base(name),
+		path:       name,
+		dumpParser: lookupMapCodec(path.Base(name), info),
+	}
+	if m.dumpParser == nil {
+		m.dumpParser = rawDumpParser
 	}
 
 	registerMap(name, m)
@@ -399,6 +436,8 @@ func (m *Map) OpenOrCreate() (bool, error) {
 	// before opening or creating.
 	if m.NonPersistent {
 		os.Remove(m.path)
+	} else {
+		m.upgradeSchema()
 	}
 
 retry:
@@ -417,6 +456,7 @@ retry:
 	}
 
 	registerMap(m.path, m)
+	m.recordSchema()
 
 	m.fd = fd
 	return isNew, nil
@@ -523,6 +563,19 @@ func (m *Map) DumpWithCallback(cb DumpCallback) error {
 	return nil
 }
 
+// iterateRawLocked calls cb with the raw key and value of every entry of the
+// map. The value is not reused between calls. m.lock must be held.
+func (m *Map) iterateRawLocked(cb func(key, value []byte) error) error {
+	return IterateKeys(m.fd, m.KeySize, m.ValueSize, m.MaxEntries, func(key []byte) error {
+		value := make([]byte, m.ValueSize)
+		if LookupElement(m.fd, unsafe.Pointer(&key[0]), unsafe.Pointer(&value[0])) != nil {
+			// Removed concurrently
+			return nil
+		}
+		return cb(key, value)
+	})
+}
+
 // DumpReliablyWithCallback is similar to DumpWithCallback, but performs
 // additional tracking of the current and recently seen keys, so that if an
 // element is removed from the underlying kernel map during the dump, the dump
@@ -608,6 +661,44 @@ func (m *Map) DumpReliablyWithCallback(cb DumpCallback, stats *DumpStats) error
 	return nil
 }
 
+// DumpChunkCallback is called with a chunk of the entries of a map. The
+// slices are reused for the next chunk and must not be retained, the keys
+// and values themselves may be retained.
+type DumpChunkCallback func(keys []MapKey, values []MapValue)
+
+// DumpChunkedWithCallback is similar to DumpReliablyWithCallback, but passes
+// the entries to the callback in chunks of up to chunkSize entries. Memory
+// usage is bounded by the chunk size regardless of the size of the map, the
+// callback may modify the map between chunks, e.g. to delete the entries of
+// the previous chunk with a single batch operation. Entries read before an
+// error occurred are passed to the callback before the error is returned.
+// If stats is nil, the statistics of the dump are discarded.
+func (m *Map) DumpChunkedWithCallback(chunkSize int, cb DumpChunkCallback, stats *DumpStats) error {
+	if stats == nil {
+		stats = NewDumpStats(m)
+	}
+
+	keys := make([]MapKey, 0, chunkSize)
+	values := make([]MapValue, 0, chunkSize)
+	flush := func() {
+		if len(keys) > 0 {
+			cb(keys, values)
+			keys, values = keys[:0], values[:0]
+		}
+	}
+
+	err := m.DumpReliablyWithCallback(func(key MapKey, value MapValue) {
+		keys = append(keys, key)
+		values = append(values, value)
+		if len(keys) >= chunkSize {
+			flush()
+		}
+	}, stats)
+	flush()
+
+	return err
+}
+
 // Dump returns the map (type map[string][]string) which contains all
 // data stored in BPF map.
 func (m *Map) Dump(hash map[string][]string) error {
@@ -672,10 +763,14 @@ func (m *Map) Lookup(key MapKey) (MapValue, error) {
 func (m *Map) Update(key MapKey, value MapValue) error {
 	var err error
 
+	caller := MapEventCaller()
+
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
 	defer func() {
+		m.recordEvent(MapEventUpdate, key, value, err, caller)
+
 		if m.cache == nil {
 			return
 		}
@@ -737,10 +832,15 @@ func (m *Map) DeleteWithErrno(key MapKey) (error, syscall.Errno) {
 		errno syscall.Errno
 	)
 
+	caller := MapEventCaller()
+
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
 	defer m.deleteCacheEntry(key, err)
+	defer func() {
+		m.recordEvent(MapEventDelete, key, nil, err, caller)
+	}()
 
 	if err = m.Open(); err != nil {
 		return err, 0
@@ -760,6 +860,37 @@ func (m *Map) Delete(key MapKey) error {
 	return err
 }
 
+// DeleteBatch deletes all given keys from the map using as few syscalls as
+// possible. Returns the errors of all failed deletions, keyed by the index of
+// the key in keys.
+func (m *Map) DeleteBatch(keys []MapKey) map[int]error {
+	caller := MapEventCaller()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	errors := map[int]error{}
+	if err := m.Open(); err != nil {
+		for i := range keys {
+			errors[i] = err
+		}
+	} else {
+		batch := NewMapBatch(m.fd, int(m.KeySize), int(m.ValueSize))
+		for _, key := range keys {
+			batch.Delete(key.GetKeyPtr())
+		}
+		errors = batch.Flush()
+	}
+
+	for i, key := range keys {
+		err := errors[i]
+		m.recordEvent(MapEventDelete, key, nil, err, caller)
+		m.deleteCacheEntry(key, err)
+	}
+
+	return errors
+}
+
 // scopedLogger returns a logger scoped for the map. m.lock must be held.
 func (m *Map) scopedLogger() *logrus.Entry {
 	return log.WithFields(logrus.Fields{logfields.Path: m.path, "name": m.name})
@@ -768,10 +899,16 @@ func (m *Map) scopedLogger() *logrus.Entry {
 // DeleteAll deletes all entries of a map by traversing the map and deleting individual
 // entries. Note that if entries are added while the taversal is in progress,
 // such entries may survive the deletion process.
-func (m *Map) DeleteAll() error {
+func (m *Map) DeleteAll() (err error) {
+	caller := MapEventCaller()
+
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
+	defer func() {
+		m.recordEvent(MapEventDeleteAll, nil, nil, err, caller)
+	}()
+
 	scopedLog := m.scopedLogger()
 	scopedLog.Debug("deleting all entries in map")
 
@@ -875,6 +1012,11 @@ func (m *Map) GetModel() *models.BPFMap {
 		Path: m.path,
 	}
 
+	if m.pressure != nil {
+		pressure := *m.pressure
+		mapModel.Pressure = &pressure
+	}
+
 	if m.cache != nil {
 		mapModel.Cache = make([]*models.BPFMapEntry, len(m.cache))
 		i := 0
@@ -930,6 +1072,7 @@ func (m *Map) resolveErrors() error {
 		case OK:
 		case Insert:
 			err := UpdateElement(m.fd, e.Key.GetKeyPtr(), e.Value.GetValuePtr(), 0)
+			m.recordEvent(MapEventUpdate, e.Key, e.Value, err, resolverCaller)
 			if err == nil {
 				e.DesiredAction = OK
 				e.LastError = nil
@@ -942,6 +1085,11 @@ func (m *Map) resolveErrors() error {
 
 		case Delete:
 			_, err := deleteElement(m.fd, e.Key.GetKeyPtr())
+			if err == 0 {
+				m.recordEvent(MapEventDelete, e.Key, nil, nil, resolverCaller)
+			} else {
+				m.recordEvent(MapEventDelete, e.Key, nil, err, resolverCaller)
+			}
 			if err == 0 || err == unix.ENOENT {
 				delete(m.cache, k)
 				resolved++
@@ -976,11 +1124,12 @@ func (m *Map) resolveErrors() error {
 
 // CheckAndUpgrade checks the received map's properties (for the map currently
 // loaded into the kernel) against the desired properties, and if they do not
-// match, deletes the map.
+// match, deletes the map. If only the size of the map differs, its entries
+// are migrated to a new map of the desired size instead.
 //
 // Returns true if the map was upgraded.
 func (m *Map) CheckAndUpgrade(desired *MapInfo) bool {
-	return objCheck(
+	redo, migrated := objCheck(
 		m.fd,
 		m.path,
 		int(desired.MapType),
@@ -989,6 +1138,7 @@ func (m *Map) CheckAndUpgrade(desired *MapInfo) bool {
 		desired.MaxEntries,
 		desired.Flags,
 	)
+	return redo || migrated
 }
 
 // mapTypeToFeatureString maps a MapType into a string defined by run_probes.sh
@@ -1017,8 +1167,16 @@ func ReadFeatureProbes(filename string) {
 		}).WithError(err).Fatal("Failed to read feature probes")
 	}
 	defer f.Close()
+	features := make(map[string]bool, len(probedKernelFeatures))
+	for _, feature := range probedKernelFeatures {
+		features[feature] = false
+	}
+
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
+		if feature := bytes.TrimPrefix(scanner.Bytes(), []byte("#define HAVE_")); len(feature) < len(scanner.Bytes()) {
+			features["HAVE_"+string(bytes.TrimSpace(feature))] = true
+		}
 		for mapType := MapTypeHash; mapType < MapTypeMaximum; mapType++ {
 			featureString := mapTypeToFeatureString(mapType)
 			if featureString != "" &&
@@ -1037,6 +1195,18 @@ func ReadFeatureProbes(filename string) {
 			supportedMapTypes[mapType] = false
 		}
 	}
+
+	kernelFeatures = features
+}
+
+// GetKernelFeatures returns the kernel features read by ReadFeatureProbes and
+// whether they are supported by the currently running kernel.
+func GetKernelFeatures() map[string]bool {
+	features := make(map[string]bool, len(kernelFeatures))
+	for feature, supported := range kernelFeatures {
+		features[feature] = supported
+	}
+	return features
 }
 
 // GetLRUMapType determines whether the kernel supports LRU hash maps, and if
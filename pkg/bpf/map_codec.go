@@ -0,0 +1,97 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"encoding/hex"
+	"strings"
+	"unsafe"
+
+	"github.com/cilium/cilium/pkg/lock"
+)
+
+// mapCodec decodes the keys and values of BPF maps with a particular layout
+type mapCodec struct {
+	keySize   uint32
+	valueSize uint32
+	parser    DumpParser
+}
+
+var (
+	codecMutex lock.RWMutex
+	mapCodecs  = map[string]mapCodec{}
+)
+
+// RegisterMapCodec registers parser to decode the entries of BPF maps whose
+// name starts with prefix. The parser is only used for maps with the given key
+// and value sizes so that maps pinned by other versions of Cilium with a
+// different layout are not decoded incorrectly.
+func RegisterMapCodec(prefix string, keySize, valueSize int, parser DumpParser) {
+	codecMutex.Lock()
+	mapCodecs[prefix] = mapCodec{
+		keySize:   uint32(keySize),
+		valueSize: uint32(valueSize),
+		parser:    parser,
+	}
+	codecMutex.Unlock()
+}
+
+// lookupMapCodec returns the parser registered with the longest prefix of
+// name, or nil if no parser matching the layout of the map was registered.
+func lookupMapCodec(name string, info *MapInfo) DumpParser {
+	codecMutex.RLock()
+	defer codecMutex.RUnlock()
+
+	var (
+		longest string
+		parser  DumpParser
+	)
+	for prefix, codec := range mapCodecs {
+		if !strings.HasPrefix(name, prefix) || len(prefix) <= len(longest) {
+			continue
+		}
+		if codec.keySize == info.KeySize && codec.valueSize == info.ValueSize {
+			longest = prefix
+			parser = codec.parser
+		}
+	}
+	return parser
+}
+
+// RawKey is the key of a BPF map for which no codec is registered
+type RawKey []byte
+
+// String returns the key as a hexadecimal string
+func (k RawKey) String() string { return hex.EncodeToString(k) }
+
+// GetKeyPtr returns the pointer to the start of the key
+func (k RawKey) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(&k[0]) }
+
+// NewValue returns a new empty value
+func (k RawKey) NewValue() MapValue { return RawValue{} }
+
+// RawValue is the value of a BPF map for which no codec is registered
+type RawValue []byte
+
+// String returns the value as a hexadecimal string
+func (v RawValue) String() string { return hex.EncodeToString(v) }
+
+// GetValuePtr returns the pointer to the start of the value
+func (v RawValue) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(&v[0]) }
+
+// rawDumpParser returns copies of key and value as RawKey and RawValue
+func rawDumpParser(key []byte, value []byte) (MapKey, MapValue, error) {
+	return RawKey(append([]byte(nil), key...)), RawValue(append([]byte(nil), value...)), nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *BPFTestSuite) TestLookupMapCodec(c *C) {
+	parser := func(prefix string) DumpParser {
+		return func(key []byte, value []byte) (MapKey, MapValue, error) {
+			return RawKey(prefix), RawValue(prefix), nil
+		}
+	}
+	RegisterMapCodec("test_foo", 4, 8, parser("test_foo"))
+	RegisterMapCodec("test_foo_bar", 4, 8, parser("test_foo_bar"))
+	RegisterMapCodec("test_foo_baz", 4, 16, parser("test_foo_baz"))
+
+	for _, t := range []struct {
+		name      string
+		valueSize uint32
+		codec     string
+	}{
+		{"test_foo_1", 8, "test_foo"},
+		{"test_foo_bar_1", 8, "test_foo_bar"},
+		// Longest prefix with a mismatching layout
+		{"test_foo_baz_1", 8, "test_foo"},
+		{"test_foo_baz_1", 16, "test_foo_baz"},
+		{"test_foo_baz_1", 32, ""},
+		{"test_other", 8, ""},
+	} {
+		p := lookupMapCodec(t.name, &MapInfo{KeySize: 4, ValueSize: t.valueSize})
+		if t.codec == "" {
+			c.Assert(p, IsNil, Commentf("map %s", t.name))
+			continue
+		}
+		c.Assert(p, NotNil, Commentf("map %s", t.name))
+		k, _, err := p(nil, nil)
+		c.Assert(err, IsNil)
+		c.Assert(k.String(), Equals, RawKey(t.codec).String(), Commentf("map %s", t.name))
+	}
+}
+
+func (s *BPFTestSuite) TestRawDumpParser(c *C) {
+	key := []byte{0x0a, 0x00, 0x00, 0x01}
+	k, v, err := rawDumpParser(key, []byte{0xff, 0x01})
+	c.Assert(err, IsNil)
+	c.Assert(k.String(), Equals, "0a000001")
+	c.Assert(v.String(), Equals, "ff01")
+
+	// The parsed key must not alias the buffer reused by the dump
+	key[0] = 0
+	c.Assert(k.String(), Equals, "0a000001")
+}
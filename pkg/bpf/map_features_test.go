@@ -0,0 +1,47 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BPFTestSuite) TestReadFeatureProbes(c *C) {
+	f, err := ioutil.TempFile("", "bpf_features")
+	c.Assert(err, IsNil)
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(`#ifndef BPF_FEATURES_H_
+#define BPF_FEATURES_H_
+
+#define HAVE_LRU_MAP_TYPE
+#define HAVE_NEW_FEATURE
+#endif /* BPF_FEATURES_H_ */
+`)
+	c.Assert(err, IsNil)
+	c.Assert(f.Close(), IsNil)
+
+	ReadFeatureProbes(f.Name())
+
+	features := GetKernelFeatures()
+	c.Assert(features, HasLen, len(probedKernelFeatures)+1)
+	c.Assert(features["HAVE_LRU_MAP_TYPE"], Equals, true)
+	c.Assert(features["HAVE_NEW_FEATURE"], Equals, true)
+	c.Assert(features["HAVE_LPM_MAP_TYPE"], Equals, false)
+	c.Assert(GetLRUMapType(), Equals, MapTypeLRUHash)
+}
@@ -19,11 +19,16 @@ import (
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/metrics"
 )
 
 var (
 	mutex       lock.RWMutex
 	mapRegister = map[string]*Map{}
+
+	// eventsRegister contains the event journals of maps which are not
+	// managed via Map, indexed by the path of the map
+	eventsRegister = map[string]*MapEvents{}
 )
 
 func registerMap(path string, m *Map) {
@@ -39,6 +44,8 @@ func unregisterMap(path string, m *Map) {
 	delete(mapRegister, path)
 	mutex.Unlock()
 
+	metrics.BPFMapPressure.DeleteLabelValues(m.name)
+
 	log.WithField("path", path).Debug("Unregistered BPF map")
 }
 
@@ -54,6 +61,40 @@ func GetMap(name string) *Map {
 	return mapRegister[name]
 }
 
+// RegisterMapEvents registers the event journal of a map which is not managed
+// via Map, so that its events can be retrieved with GetMapEvents().
+func RegisterMapEvents(path string, events *MapEvents) {
+	mutex.Lock()
+	eventsRegister[path] = events
+	mutex.Unlock()
+}
+
+// UnregisterMapEvents removes the event journal registered for the map at
+// path, unless the map has been registered again with a different journal.
+func UnregisterMapEvents(path string, events *MapEvents) {
+	mutex.Lock()
+	if eventsRegister[path] == events {
+		delete(eventsRegister, path)
+	}
+	mutex.Unlock()
+}
+
+// GetMapEvents returns the event journal of the map with the given name or
+// absolute path, or nil if no such map is known
+func GetMapEvents(name string) *MapEvents {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	if !path.IsAbs(name) {
+		name = MapPath(name)
+	}
+
+	if m, ok := mapRegister[name]; ok {
+		return &m.events
+	}
+	return eventsRegister[name]
+}
+
 // GetOpenMaps returns a slice of all open BPF maps. This is identical to
 // calling GetMap() on all open maps.
 func GetOpenMaps() []*models.BPFMap {
@@ -76,3 +117,29 @@ func GetOpenMaps() []*models.BPFMap {
 
 	return mapList
 }
+
+// GetOpenMapsMemory returns the memory of all open BPF maps in bytes, assuming
+// all maps are fully populated.
+func GetOpenMapsMemory() uint64 {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	var total uint64
+	for _, m := range mapRegister {
+		total += uint64(m.MaxEntries) * uint64(m.KeySize+m.ValueSize)
+	}
+	return total
+}
+
+// GetOpenMapsMaxEntries returns the maximum number of entries of all open BPF
+// maps indexed by the name of the map.
+func GetOpenMapsMaxEntries() map[string]int64 {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	sizes := make(map[string]int64, len(mapRegister))
+	for _, m := range mapRegister {
+		sizes[m.name] = int64(m.MaxEntries)
+	}
+	return sizes
+}
@@ -0,0 +1,147 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+// migrateSuffix is appended to the path of a map to pin the resized map
+// while its entries are copied.
+const migrateSuffix = ".migrate"
+
+// isMigratable returns true if the entries of maps of type t can be copied
+// into a map of a different size.
+func isMigratable(t MapType) bool {
+	switch t {
+	case MapTypeHash, MapTypeLRUHash, MapTypeLPMTrie:
+		return true
+	}
+	return false
+}
+
+// isConvertible returns true if the entries of a map of type from can be
+// copied into a map of type to. Hash maps and LRU hash maps share the same
+// semantics apart from the eviction of entries once the map is full.
+func isConvertible(from, to MapType) bool {
+	if from == to {
+		return true
+	}
+	return (from == MapTypeHash || from == MapTypeLRUHash) &&
+		(to == MapTypeHash || to == MapTypeLRUHash)
+}
+
+// canMigrate returns true if the map described by info only differs from the
+// desired properties in its maximum number of entries or in being an LRU
+// hash map and can thus be migrated to a map of the desired properties
+// without losing its entries.
+func canMigrate(info *MapInfo, mapType int, keySize, valueSize, maxEntries, flags uint32) bool {
+	return isConvertible(info.MapType, MapType(mapType)) &&
+		info.KeySize == keySize &&
+		info.ValueSize == valueSize &&
+		info.Flags == flags &&
+		(info.MaxEntries != maxEntries || int(info.MapType) != mapType) &&
+		isMigratable(info.MapType)
+}
+
+// entryConverter converts the key and value of a map entry before it is
+// copied into another map.
+type entryConverter func(key, value []byte) (newKey, newValue []byte, err error)
+
+// copyMapEntries copies all entries of the map in fd to the map in newFd.
+// Entries which do not fit into the new map are dropped. If convert is not
+// nil, entries are converted before they are copied, entries failing
+// conversion are dropped.
+func copyMapEntries(fd, newFd int, keySize, valueSize, maxEntries uint32, convert entryConverter) (copied, dropped int) {
+	value := make([]byte, valueSize)
+
+	IterateKeys(fd, keySize, valueSize, maxEntries, func(key []byte) error {
+		if err := LookupElement(fd, unsafe.Pointer(&key[0]), unsafe.Pointer(&value[0])); err != nil {
+			// Removed concurrently
+			return nil
+		}
+		newKey, newValue := key, value
+		if convert != nil {
+			var err error
+			if newKey, newValue, err = convert(key, value); err != nil {
+				dropped++
+				return nil
+			}
+		}
+		if err := UpdateElement(newFd, unsafe.Pointer(&newKey[0]), unsafe.Pointer(&newValue[0]), 0); err != nil {
+			dropped++
+			return nil
+		}
+		copied++
+		return nil
+	})
+
+	return
+}
+
+// replacePin atomically replaces the map pinned at path with the map in fd.
+func replacePin(fd int, path string) error {
+	tmpPath := path + migrateSuffix
+	os.Remove(tmpPath)
+	if err := ObjPin(fd, tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("unable to replace map: %s", err)
+	}
+	return nil
+}
+
+// migrateMap replaces the map in fd pinned at path with a map of type
+// mapType and maxEntries entries holding the same entries. The new map is populated before it
+// atomically replaces the pin of the old map. BPF programs referring to the
+// old map continue to use it until they are reloaded, entries they create in
+// the meantime are lost.
+func migrateMap(fd int, path string, info *MapInfo, mapType int, maxEntries uint32) error {
+	newFd, err := CreateMap(mapType, info.KeySize, info.ValueSize, maxEntries, info.Flags)
+	if err != nil {
+		return err
+	}
+	defer ObjClose(newFd)
+
+	copied, dropped := copyMapEntries(fd, newFd, info.KeySize, info.ValueSize, info.MaxEntries, nil)
+
+	if err := replacePin(newFd, path); err != nil {
+		return err
+	}
+
+	scopedLog := log.WithFields(logrus.Fields{
+		logfields.Path: path,
+		"old":          info.MaxEntries,
+		"new":          maxEntries,
+		"oldType":      info.MapType,
+		"newType":      MapType(mapType),
+		"copied":       copied,
+	})
+	if dropped > 0 {
+		scopedLog.WithField("dropped", dropped).Warning("Migrated BPF map, entries exceeding the new size were dropped")
+	} else {
+		scopedLog.Info("Migrated BPF map")
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *BPFTestSuite) TestCanMigrate(c *C) {
+	info := &MapInfo{
+		MapType:    MapTypeHash,
+		KeySize:    8,
+		ValueSize:  24,
+		MaxEntries: 16384,
+	}
+
+	// Only the size differs
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_HASH, 8, 24, 65536, 0), Equals, true)
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_HASH, 8, 24, 1024, 0), Equals, true)
+
+	// Nothing to migrate
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_HASH, 8, 24, 16384, 0), Equals, false)
+
+	// Hash maps are converted to LRU hash maps and back
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_LRU_HASH, 8, 24, 16384, 0), Equals, true)
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_LRU_HASH, 8, 24, 65536, 0), Equals, true)
+	info.MapType = MapTypeLRUHash
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_HASH, 8, 24, 16384, 0), Equals, true)
+	info.MapType = MapTypeHash
+
+	// Other properties differ, the map must be recreated
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_LPM_TRIE, 8, 24, 65536, 0), Equals, false)
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_HASH, 12, 24, 65536, 0), Equals, false)
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_HASH, 8, 32, 65536, 0), Equals, false)
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_HASH, 8, 24, 65536, BPF_F_NO_PREALLOC), Equals, false)
+
+	// Entries of program arrays cannot be copied
+	info.MapType = MapTypeProgArray
+	c.Assert(canMigrate(info, BPF_MAP_TYPE_PROG_ARRAY, 8, 24, 65536, 0), Equals, false)
+}
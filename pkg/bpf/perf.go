@@ -98,7 +98,7 @@ static inline void perf_write_tail(struct perf_event_mmap_page *up,
 #endif
 
 void create_perf_event_attr(int type, int config, int sample_type,
-			    int wakeup_events, void *attr)
+			    int wakeup_events, int write_backward, void *attr)
 {
 	struct perf_event_attr *ptr = attr;
 
@@ -109,6 +109,7 @@ void create_perf_event_attr(int type, int config, int sample_type,
 	ptr->sample_type = sample_type;
 	ptr->sample_period = 1;
 	ptr->wakeup_events = wakeup_events;
+	ptr->write_backward = write_backward;
 }
 
 static void dump_data(uint8_t *data, size_t size, int cpu)
@@ -133,6 +134,11 @@ struct read_state {
 	uint64_t last_size;
 };
 
+uint64_t perf_event_head(void *_page)
+{
+	return perf_read_head(_page);
+}
+
 void perf_event_reset_tail(void *_page)
 {
 	struct perf_event_mmap_page *up = _page;
@@ -273,6 +279,12 @@ type PerfEventConfig struct {
 	Config       int
 	SampleType   int
 	WakeupEvents int
+
+	// Overwrite makes the kernel overwrite the oldest events when the
+	// ring buffer is full instead of dropping new events. Events which
+	// are overwritten before they are read are not reported as lost but
+	// counted as overwrites. Requires Linux 4.7 or later.
+	Overwrite bool
 }
 
 func DefaultPerfEventConfig() *PerfEventConfig {
@@ -296,6 +308,17 @@ type PerfEvent struct {
 	trunc    uint64
 	unknown  uint64
 	data     []byte
+
+	// overwrite is true if the ring buffer is written backward and
+	// overwritten when full, see PerfEventConfig.Overwrite
+	overwrite bool
+	// prevHead is the position of the newest event read from a ring
+	// buffer in overwrite mode
+	prevHead uint64
+	// overwritten is the number of reads from a ring buffer in overwrite
+	// mode which found that events had been overwritten
+	overwritten uint64
+
 	// state is placed here to reduce memory allocations
 	state unsafe.Pointer
 	// buf is placed here to reduce memory allocations
@@ -341,11 +364,17 @@ type ErrorFunc func(msg *PerfEvent)
 func PerfEventOpen(config *PerfEventConfig, pid int, cpu int, groupFD int, flags int) (*PerfEvent, error) {
 	attr := C.struct_perf_event_attr{}
 
+	writeBackward := 0
+	if config.Overwrite {
+		writeBackward = 1
+	}
+
 	C.create_perf_event_attr(
 		C.int(config.Type),
 		C.int(config.Config),
 		C.int(config.SampleType),
 		C.int(config.WakeupEvents),
+		C.int(writeBackward),
 		unsafe.Pointer(&attr),
 	)
 
@@ -359,8 +388,9 @@ func PerfEventOpen(config *PerfEventConfig, pid int, cpu int, groupFD int, flags
 
 	if int(ret) > 0 && err == 0 {
 		return &PerfEvent{
-			cpu: cpu,
-			Fd:  int(ret),
+			cpu:       cpu,
+			Fd:        int(ret),
+			overwrite: config.Overwrite,
 		}, nil
 	}
 	return nil, fmt.Errorf("Unable to open perf event: %s", err)
@@ -372,11 +402,18 @@ func (e *PerfEvent) Mmap(pagesize int, npages int) error {
 		return fmt.Errorf("Unable to mmap perf event: ring size not power of 2")
 	}
 
+	// A read-only mapping tells the kernel that the reader does not
+	// update data_tail, i.e. that the ring buffer may be overwritten.
+	prot := unix.PROT_READ | unix.PROT_WRITE
+	if e.overwrite {
+		prot = unix.PROT_READ
+	}
+
 	size := pagesize * (npages + 1)
 	data, err := unix.Mmap(e.Fd,
 		0,
 		size,
-		unix.PROT_READ|unix.PROT_WRITE,
+		prot,
 		unix.MAP_SHARED)
 	if err != nil {
 		return fmt.Errorf("Unable to mmap perf event: %s", err)
@@ -442,6 +479,11 @@ func (e *PerfEvent) Disable() error {
 // If all events are not read within a time period (default 20s), it will call
 // errFn() and stop reading events.
 func (e *PerfEvent) Read(receive ReceiveFunc, lostFn LostFunc, err ErrorFunc) {
+	if e.overwrite {
+		e.readBackward(receive, lostFn)
+		return
+	}
+
 	// Prepare for reading and check if events are available
 	available := C.perf_event_read_init(C.int(e.npages), C.int(e.pagesize),
 		unsafe.Pointer(&e.data[0]), unsafe.Pointer(e.state))
@@ -502,6 +544,85 @@ read:
 	}
 }
 
+// readBackward reads the events written since the last read from a ring
+// buffer in overwrite mode. The kernel writes such a buffer from the end to
+// the beginning, data_head points to the newest event. The output is paused
+// while reading so that events are not overwritten concurrently. The events
+// are passed to receive and lostFn in the order they were written.
+func (e *PerfEvent) readBackward(receive ReceiveFunc, lostFn LostFunc) {
+	if err := unix.IoctlSetInt(e.Fd, unix.PERF_EVENT_IOC_PAUSE_OUTPUT, 1); err != nil {
+		log.WithError(err).Warning("Unable to pause perf event output")
+		return
+	}
+	head := uint64(C.perf_event_head(unsafe.Pointer(&e.data[0])))
+	records, overwritten := backwardRecords(e.data[e.pagesize:], head, e.prevHead)
+	e.prevHead = head
+	unix.IoctlSetInt(e.Fd, unix.PERF_EVENT_IOC_PAUSE_OUTPUT, 0)
+
+	if overwritten {
+		e.overwritten++
+	}
+
+	for i := len(records) - 1; i >= 0; i-- {
+		msg := (*PerfEventHeader)(unsafe.Pointer(&records[i][0]))
+		switch msg.Type {
+		case C.PERF_RECORD_SAMPLE:
+			receive((*PerfEventSample)(unsafe.Pointer(msg)), e.cpu)
+		case C.PERF_RECORD_LOST:
+			lost := (*PerfEventLost)(unsafe.Pointer(msg))
+			e.lost += lost.Lost
+			if lostFn != nil {
+				lostFn(lost, e.cpu)
+			}
+		default:
+			e.unknown++
+		}
+	}
+}
+
+// perfEventHeaderSize is the size of struct perf_event_header
+const perfEventHeaderSize = uint64(unsafe.Sizeof(PerfEventHeader{}))
+
+// backwardRecords returns copies of the records written to ring, the data
+// area of a ring buffer written backward, between the positions head and
+// prevHead, newest first. As the ring is written backward, head decreases
+// with every record and prevHead - head is the number of bytes written since
+// the last read. Returns true if records were overwritten before
+// they could be read, in which case only the newest records fitting into
+// the ring are returned.
+func backwardRecords(ring []byte, head, prevHead uint64) ([][]byte, bool) {
+	size := uint64(len(ring))
+	available := prevHead - head
+	overwritten := false
+	if available > size {
+		available = size
+		overwritten = true
+	}
+
+	var records [][]byte
+	for offset := uint64(0); offset+perfEventHeaderSize <= available; {
+		start := (head + offset) & (size - 1)
+		header := ringCopy(ring, start, perfEventHeaderSize)
+		recordSize := uint64((*PerfEventHeader)(unsafe.Pointer(&header[0])).TotalSize)
+		if recordSize < perfEventHeaderSize || offset+recordSize > available {
+			break
+		}
+		records = append(records, ringCopy(ring, start, recordSize))
+		offset += recordSize
+	}
+
+	return records, overwritten
+}
+
+// ringCopy returns a copy of n bytes of ring starting at start, wrapping
+// around at the end of ring.
+func ringCopy(ring []byte, start, n uint64) []byte {
+	buf := make([]byte, n)
+	copied := copy(buf, ring[start:])
+	copy(buf[copied:], ring)
+	return buf
+}
+
 func (e *PerfEvent) Close() {
 	if e == nil {
 		return
@@ -582,6 +703,14 @@ type PerCpuEvents struct {
 	Cpus     int
 	Npages   int
 	Pagesize int
+
+	// WakeupEvents is the number of events after which the reader is
+	// woken up
+	WakeupEvents int
+
+	// Overwrite is true if the ring buffers are overwritten when full
+	Overwrite bool
+
 	eventMap *EventMap
 	event    map[int]*PerfEvent
 	poll     EPoll
@@ -591,10 +720,12 @@ func NewPerCpuEvents(config *PerfEventConfig) (*PerCpuEvents, error) {
 	var err error
 
 	e := &PerCpuEvents{
-		Cpus:     config.NumCpus,
-		Npages:   config.NumPages,
-		Pagesize: os.Getpagesize(),
-		event:    make(map[int]*PerfEvent),
+		Cpus:         config.NumCpus,
+		Npages:       config.NumPages,
+		Pagesize:     os.Getpagesize(),
+		WakeupEvents: config.WakeupEvents,
+		Overwrite:    config.Overwrite,
+		event:        make(map[int]*PerfEvent),
 	}
 
 	defer func() {
@@ -677,6 +808,27 @@ func (e *PerCpuEvents) Stats() (uint64, uint64, uint64) {
 	return lost, trunc, unknown
 }
 
+// LostPerCPU returns the number of events lost on each CPU, indexed by CPU
+func (e *PerCpuEvents) LostPerCPU() []uint64 {
+	lost := make([]uint64, e.Cpus)
+	for _, event := range e.event {
+		if event.cpu < len(lost) {
+			lost[event.cpu] = event.lost
+		}
+	}
+	return lost
+}
+
+// Overwritten returns the number of reads from ring buffers in overwrite mode
+// which found that events had been overwritten before they were read
+func (e *PerCpuEvents) Overwritten() uint64 {
+	var overwritten uint64
+	for _, event := range e.event {
+		overwritten += event.overwritten
+	}
+	return overwritten
+}
+
 func (e *PerCpuEvents) CloseAll() error {
 	var retErr error
 
@@ -0,0 +1,76 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"unsafe"
+
+	. "gopkg.in/check.v1"
+)
+
+// writeBackward writes a record of the given size and type to ring the way
+// the kernel writes a ring buffer in overwrite mode and returns the new head
+func writeBackward(ring []byte, head uint64, size uint16, typ uint32) uint64 {
+	record := make([]byte, size)
+	header := (*PerfEventHeader)(unsafe.Pointer(&record[0]))
+	header.Type = typ
+	header.TotalSize = size
+	for i := perfEventHeaderSize; i < uint64(size); i++ {
+		record[i] = byte(size)
+	}
+
+	head -= uint64(size)
+	for i := range record {
+		ring[(head+uint64(i))&uint64(len(ring)-1)] = record[i]
+	}
+	return head
+}
+
+func (s *BPFTestSuite) TestBackwardRecords(c *C) {
+	ring := make([]byte, 64)
+
+	records, overwritten := backwardRecords(ring, 0, 0)
+	c.Assert(records, HasLen, 0)
+	c.Assert(overwritten, Equals, false)
+
+	head := writeBackward(ring, 0, 16, 9)
+	head = writeBackward(ring, head, 24, 9)
+	records, overwritten = backwardRecords(ring, head, 0)
+	c.Assert(overwritten, Equals, false)
+	c.Assert(records, HasLen, 2)
+	c.Assert(records[0], HasLen, 24)
+	c.Assert(records[1], HasLen, 16)
+
+	// The fourth record wraps around the end of the ring
+	prevHead := head
+	head = writeBackward(ring, head, 16, 9)
+	head = writeBackward(ring, head, 16, 9)
+	records, overwritten = backwardRecords(ring, head, prevHead)
+	c.Assert(overwritten, Equals, false)
+	c.Assert(records, HasLen, 2)
+
+	// Records not read before the ring is full are overwritten
+	head = writeBackward(ring, head, 24, 9)
+	head = writeBackward(ring, head, 24, 9)
+	head = writeBackward(ring, head, 8, 9)
+	records, overwritten = backwardRecords(ring, head, prevHead)
+	c.Assert(overwritten, Equals, true)
+	c.Assert(records, HasLen, 3)
+	for i, size := range []int{8, 24, 24} {
+		c.Assert(records[i], HasLen, size)
+		header := (*PerfEventHeader)(unsafe.Pointer(&records[i][0]))
+		c.Assert(int(header.TotalSize), Equals, size)
+	}
+}
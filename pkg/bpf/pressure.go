@@ -0,0 +1,169 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// mapPressureInterval is the interval in which the fill ratio of all
+	// open maps is sampled
+	mapPressureInterval = time.Minute
+
+	// mapPressureWarnRatio is the fill ratio above which a warning is
+	// logged as new entries are dropped once a map is full
+	mapPressureWarnRatio = 0.9
+)
+
+// hasPressure returns true if the fill ratio of maps of type t is
+// meaningful. Array maps always contain all of their entries.
+func hasPressure(t MapType) bool {
+	switch t {
+	case MapTypeHash, MapTypeLRUHash, MapTypeLPMTrie:
+		return true
+	}
+	return false
+}
+
+// countEntries returns the number of entries in the map.
+func (m *Map) countEntries() (int, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if err := m.Open(); err != nil {
+		return 0, err
+	}
+
+	return CountEntries(m.fd, m.KeySize, m.ValueSize, m.MaxEntries), nil
+}
+
+// CountEntries returns the number of entries in the map referred to by fd.
+func CountEntries(fd int, keySize, valueSize, maxEntries uint32) int {
+	entries := 0
+	IterateKeys(fd, keySize, valueSize, maxEntries, func(key []byte) error {
+		entries++
+		return nil
+	})
+
+	return entries
+}
+
+// PressureExceeded returns true if the fill ratio of a map crossed the ratio
+// above which a warning is logged since the previous sample.
+func PressureExceeded(ratio, previous float64) bool {
+	return ratio >= mapPressureWarnRatio && previous < mapPressureWarnRatio
+}
+
+// newMapPressure returns the fill ratio of a map with the given number of
+// entries.
+func newMapPressure(entries int, maxEntries uint32) *models.BPFMapPressure {
+	return &models.BPFMapPressure{
+		Entries:    int64(entries),
+		MaxEntries: int64(maxEntries),
+		Ratio:      float64(entries) / float64(maxEntries),
+	}
+}
+
+// samplePressure updates the fill ratio of the map and logs a warning when
+// it exceeds mapPressureWarnRatio.
+func (m *Map) samplePressure() {
+	if !hasPressure(m.MapType) || m.MaxEntries == 0 {
+		return
+	}
+
+	scopedLog := log.WithField(logfields.BPFMapName, m.name)
+	entries, err := m.countEntries()
+	if err != nil {
+		scopedLog.WithError(err).Debug("Unable to sample pressure of BPF map")
+		return
+	}
+	pressure := newMapPressure(entries, m.MaxEntries)
+
+	m.lock.Lock()
+	previous := m.pressure
+	m.pressure = pressure
+	m.lock.Unlock()
+
+	metrics.BPFMapPressure.WithLabelValues(m.name).Set(pressure.Ratio)
+
+	previousRatio := 0.0
+	if previous != nil {
+		previousRatio = previous.Ratio
+	}
+	if PressureExceeded(pressure.Ratio, previousRatio) {
+		scopedLog.WithFields(logrus.Fields{
+			"entries":    pressure.Entries,
+			"maxEntries": pressure.MaxEntries,
+		}).Warning("BPF map is almost full, new entries are dropped once it is full")
+	}
+}
+
+var (
+	pressureSamplersMutex lock.Mutex
+	pressureSamplers      []func()
+)
+
+// RegisterPressureSampler registers a function which samples the fill ratio
+// of maps that are not opened as Map, such as the maps existing once per
+// endpoint. It is run along with the sampling of all open maps.
+func RegisterPressureSampler(sampler func()) {
+	pressureSamplersMutex.Lock()
+	pressureSamplers = append(pressureSamplers, sampler)
+	pressureSamplersMutex.Unlock()
+}
+
+// sampleMapPressure samples the fill ratio of all open maps and runs all
+// registered pressure samplers
+func sampleMapPressure() error {
+	mutex.RLock()
+	maps := make([]*Map, 0, len(mapRegister))
+	for _, m := range mapRegister {
+		maps = append(maps, m)
+	}
+	mutex.RUnlock()
+
+	for _, m := range maps {
+		m.samplePressure()
+	}
+
+	pressureSamplersMutex.Lock()
+	samplers := append([]func(){}, pressureSamplers...)
+	pressureSamplersMutex.Unlock()
+
+	for _, sampler := range samplers {
+		sampler()
+	}
+	return nil
+}
+
+// StartMapPressureSampler starts a controller which periodically samples the
+// fill ratio of all open maps. The ratio is exported as metric and as part
+// of the map models.
+func StartMapPressureSampler() {
+	mapControllers.UpdateController("bpf-map-pressure",
+		controller.ControllerParams{
+			DoFunc:      sampleMapPressure,
+			RunInterval: mapPressureInterval,
+		})
+}
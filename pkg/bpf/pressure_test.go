@@ -0,0 +1,49 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *BPFTestSuite) TestMapPressure(c *C) {
+	p := newMapPressure(256, 1024)
+	c.Assert(p.Entries, Equals, int64(256))
+	c.Assert(p.MaxEntries, Equals, int64(1024))
+	c.Assert(p.Ratio, Equals, 0.25)
+
+	c.Assert(newMapPressure(1024, 1024).Ratio, Equals, 1.0)
+
+	c.Assert(hasPressure(MapTypeHash), Equals, true)
+	c.Assert(hasPressure(MapTypeLRUHash), Equals, true)
+	c.Assert(hasPressure(MapTypeArray), Equals, false)
+	c.Assert(hasPressure(MapTypeProgArray), Equals, false)
+}
+
+func (s *BPFTestSuite) TestPressureExceeded(c *C) {
+	c.Assert(PressureExceeded(0.95, 0.5), Equals, true)
+	c.Assert(PressureExceeded(0.95, 0.0), Equals, true)
+	c.Assert(PressureExceeded(0.95, 0.92), Equals, false)
+	c.Assert(PressureExceeded(0.5, 0.95), Equals, false)
+}
+
+func (s *BPFTestSuite) TestPressureSampler(c *C) {
+	defer func() { pressureSamplers = nil }()
+
+	sampled := 0
+	RegisterPressureSampler(func() { sampled++ })
+	c.Assert(sampleMapPressure(), IsNil)
+	c.Assert(sampled, Equals, 1)
+}
@@ -0,0 +1,225 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+// schemaSuffix is appended to the name of a map to form the name of the file
+// recording its schema version.
+const schemaSuffix = ".version"
+
+var (
+	schemaMutex lock.RWMutex
+
+	// schemaDir is the directory holding the schema versions of pinned
+	// maps. Schema versioning is disabled if empty.
+	schemaDir string
+)
+
+// MapConverter converts the key and value of a map entry from the layout of
+// one schema version of the map to the layout of the following version.
+type MapConverter func(key, value []byte) (newKey, newValue []byte, err error)
+
+// SetSchemaDir sets the directory in which the schema versions of pinned maps
+// are recorded. The BPF filesystem can only hold BPF objects, the versions
+// are therefore kept outside of it.
+func SetSchemaDir(dir string) {
+	schemaMutex.Lock()
+	schemaDir = dir
+	schemaMutex.Unlock()
+}
+
+func getSchemaDir() string {
+	schemaMutex.RLock()
+	defer schemaMutex.RUnlock()
+	return schemaDir
+}
+
+// readSchemaVersion returns the schema version recorded for the map with the
+// given name. Maps pinned before their schema version was recorded are of
+// version 1.
+func readSchemaVersion(dir, name string) int {
+	data, err := ioutil.ReadFile(filepath.Join(dir, name+schemaSuffix))
+	if err != nil {
+		return 1
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || version < 1 {
+		return 1
+	}
+	return version
+}
+
+// writeSchemaVersion records the schema version of the map with the given
+// name.
+func writeSchemaVersion(dir, name string, version int) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, name+schemaSuffix), []byte(strconv.Itoa(version)+"\n"), 0644)
+}
+
+// WithSchema sets the schema version of the layout of the map's keys and
+// values and returns the map. converters[v] converts an entry of version v
+// into an entry of version v+1. When a pinned map of an older version is
+// opened, its entries are converted to the current version instead of
+// removing the map.
+func (m *Map) WithSchema(version int, converters map[int]MapConverter) *Map {
+	m.schemaVersion = version
+	m.converters = converters
+	return m
+}
+
+// schemaConverter returns a converter of entries of the given version into
+// entries of the map's schema version, or nil if a converter of an
+// intermediate version is missing.
+func (m *Map) schemaConverter(from int) entryConverter {
+	if from > m.schemaVersion {
+		return nil
+	}
+	for v := from; v < m.schemaVersion; v++ {
+		if m.converters[v] == nil {
+			return nil
+		}
+	}
+
+	return func(key, value []byte) ([]byte, []byte, error) {
+		var err error
+		for v := from; v < m.schemaVersion; v++ {
+			if key, value, err = m.converters[v](key, value); err != nil {
+				return nil, nil, err
+			}
+		}
+		if len(key) != int(m.KeySize) || len(value) != int(m.ValueSize) {
+			return nil, nil, fmt.Errorf("converted entry has key size %d and value size %d, expected %d and %d",
+				len(key), len(value), m.KeySize, m.ValueSize)
+		}
+		return key, value, nil
+	}
+}
+
+// upgradeSchema converts the entries of the map pinned at m.path from the
+// recorded schema version to the schema version of m. The converted entries
+// atomically replace the pinned map. If the entries cannot be converted, the
+// pinned map is removed so that it is recreated.
+//
+// m.lock must be held for writing
+func (m *Map) upgradeSchema() {
+	dir := getSchemaDir()
+	if m.schemaVersion == 0 || dir == "" {
+		return
+	}
+	if _, err := os.Stat(m.path); err != nil {
+		return
+	}
+
+	version := readSchemaVersion(dir, m.name)
+	if version == m.schemaVersion {
+		return
+	}
+
+	scopedLog := log.WithFields(logrus.Fields{
+		logfields.Path: m.path,
+		"old":          version,
+		"new":          m.schemaVersion,
+	})
+
+	convert := m.schemaConverter(version)
+	if convert == nil {
+		scopedLog.Warning("Removing BPF map, its entries cannot be converted to the new schema (expect map data loss)")
+		os.Remove(m.path)
+		return
+	}
+
+	copied, dropped, err := m.convertPinned(convert)
+	if err != nil {
+		scopedLog.WithError(err).Warning("Unable to convert entries of BPF map, removing map (expect map data loss)")
+		os.Remove(m.path)
+		return
+	}
+
+	scopedLog = scopedLog.WithField("copied", copied)
+	if dropped > 0 {
+		scopedLog.WithField("dropped", dropped).Warning("Upgraded schema of BPF map, entries failing conversion were dropped")
+	} else {
+		scopedLog.Info("Upgraded schema of BPF map")
+	}
+	m.recordSchema()
+}
+
+// convertPinned creates a map with the properties of m, populates it with
+// the converted entries of the map pinned at m.path and pins it in its place.
+func (m *Map) convertPinned(convert entryConverter) (copied, dropped int, err error) {
+	fd, err := ObjGet(m.path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer ObjClose(fd)
+
+	info, err := GetMapInfo(os.Getpid(), fd)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	newFd, err := CreateMap(int(m.MapType), m.KeySize, m.ValueSize, m.MaxEntries, m.Flags)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer ObjClose(newFd)
+
+	copied, dropped = copyMapEntries(fd, newFd, info.KeySize, info.ValueSize, info.MaxEntries, convert)
+
+	if err := replacePin(newFd, m.path); err != nil {
+		return 0, 0, err
+	}
+	return copied, dropped, nil
+}
+
+// recordSchema records the schema version of the map if it has one.
+func (m *Map) recordSchema() {
+	dir := getSchemaDir()
+	if m.schemaVersion == 0 || dir == "" {
+		return
+	}
+	if err := writeSchemaVersion(dir, m.name, m.schemaVersion); err != nil {
+		log.WithError(err).WithField(logfields.BPFMapName, m.name).Warning("Unable to record schema version of BPF map")
+	}
+}
+
+// UpgradeSchema converts the entries of the map pinned at the path of m to
+// the schema version of m if the pinned map is of an older version. Maps
+// which are not pinned yet are left untouched.
+func (m *Map) UpgradeSchema() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if err := m.setPathIfUnset(); err != nil {
+		return err
+	}
+	m.upgradeSchema()
+	return nil
+}
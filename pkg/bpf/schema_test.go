@@ -0,0 +1,84 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BPFTestSuite) TestSchemaVersion(c *C) {
+	dir, err := ioutil.TempDir("", "cilium-schema")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	// Maps without a recorded version are of version 1
+	c.Assert(readSchemaVersion(dir, "cilium_test"), Equals, 1)
+
+	c.Assert(writeSchemaVersion(filepath.Join(dir, "maps"), "cilium_test", 3), IsNil)
+	c.Assert(readSchemaVersion(filepath.Join(dir, "maps"), "cilium_test"), Equals, 3)
+
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, "cilium_test"+schemaSuffix), []byte("garbage"), 0644), IsNil)
+	c.Assert(readSchemaVersion(dir, "cilium_test"), Equals, 1)
+}
+
+func (s *BPFTestSuite) TestSchemaConverter(c *C) {
+	m := NewMap("cilium_test", MapTypeHash, 2, 3, 16, 0, nil)
+
+	// Version 1 has one byte keys and values, version 2 adds a byte to
+	// keys, version 3 adds a byte to values
+	m.WithSchema(3, map[int]MapConverter{
+		1: func(key, value []byte) ([]byte, []byte, error) {
+			return append([]byte{0}, key...), value, nil
+		},
+		2: func(key, value []byte) ([]byte, []byte, error) {
+			if value[0] == 0xff {
+				return nil, nil, fmt.Errorf("invalid value")
+			}
+			return key, append(value, 0, 0), nil
+		},
+	})
+
+	convert := m.schemaConverter(1)
+	c.Assert(convert, Not(IsNil))
+	key, value, err := convert([]byte{1}, []byte{2})
+	c.Assert(err, IsNil)
+	c.Assert(key, DeepEquals, []byte{0, 1})
+	c.Assert(value, DeepEquals, []byte{2, 0, 0})
+
+	_, _, err = convert([]byte{1}, []byte{0xff})
+	c.Assert(err, Not(IsNil))
+
+	// Entries of version 2 skip the first converter
+	key, value, err = m.schemaConverter(2)([]byte{0, 1}, []byte{2})
+	c.Assert(err, IsNil)
+	c.Assert(key, DeepEquals, []byte{0, 1})
+	c.Assert(value, DeepEquals, []byte{2, 0, 0})
+
+	// Converted entries must match the size of the map
+	_, _, err = m.schemaConverter(2)([]byte{1}, []byte{2})
+	c.Assert(err, Not(IsNil))
+
+	// Newer versions cannot be converted
+	c.Assert(m.schemaConverter(4), IsNil)
+
+	// A missing converter prevents the conversion
+	m.WithSchema(4, m.converters)
+	c.Assert(m.schemaConverter(1), IsNil)
+}
@@ -0,0 +1,258 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+	"unsafe"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+)
+
+const (
+	// snapshotMagic identifies a file as a snapshot of a BPF map and
+	// denotes the version of the file format
+	snapshotMagic = "CMSNAP01"
+
+	// maxSnapshotHeaderLen is the maximum length of the encoded header
+	maxSnapshotHeaderLen = 1 << 16
+
+	// snapshotRestoreBatchSize is the number of entries restored with a
+	// single batch of updates
+	snapshotRestoreBatchSize = 4096
+)
+
+// MapSnapshot is the header of a snapshot of the entries of a BPF map.
+//
+// A snapshot file consists of the magic string "CMSNAP01", the length of the
+// JSON encoded header as 32 bit integer in host byte order, the header, and
+// the raw key and value of every entry of the map. As the file holds the
+// entries in the layout of the datapath, a snapshot can only be restored on a
+// host with the same byte order into a map with the same key and value size.
+type MapSnapshot struct {
+	// Name is the name of the map the snapshot was taken of
+	Name string `json:"name"`
+
+	// Info is the metadata of the map the snapshot was taken of
+	Info MapInfo `json:"info"`
+
+	// Timestamp is the time the snapshot was taken at
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// snapshotSupported returns an error if the entries of maps of type t cannot
+// be snapshotted.
+func snapshotSupported(t MapType) error {
+	switch t {
+	case MapTypeHash, MapTypeArray, MapTypeLRUHash, MapTypeLPMTrie:
+		return nil
+	}
+	return fmt.Errorf("snapshots of maps of type %s are not supported", t)
+}
+
+// writeSnapshot writes the header followed by all entries returned by iterate
+// to w. Returns the number of entries written.
+func writeSnapshot(w io.Writer, snapshot *MapSnapshot, iterate func(cb func(key, value []byte) error) error) (int, error) {
+	header, err := json.Marshal(snapshot)
+	if err != nil {
+		return 0, err
+	}
+
+	bw := bufio.NewWriter(w)
+	length := make([]byte, 4)
+	byteorder.Native.PutUint32(length, uint32(len(header)))
+	bw.WriteString(snapshotMagic)
+	bw.Write(length)
+	bw.Write(header)
+
+	entries := 0
+	err = iterate(func(key, value []byte) error {
+		if _, err := bw.Write(key); err != nil {
+			return err
+		}
+		if _, err := bw.Write(value); err != nil {
+			return err
+		}
+		entries++
+		return nil
+	})
+	if err != nil {
+		return entries, err
+	}
+
+	return entries, bw.Flush()
+}
+
+// WriteSnapshot writes a snapshot of all entries of the map to w. Entries
+// which are added or removed concurrently may or may not be part of the
+// snapshot. Returns the number of entries written.
+func (m *Map) WriteSnapshot(w io.Writer) (int, error) {
+	if err := snapshotSupported(m.MapType); err != nil {
+		return 0, err
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if err := m.Open(); err != nil {
+		return 0, err
+	}
+
+	snapshot := &MapSnapshot{
+		Name:      m.name,
+		Info:      m.MapInfo,
+		Timestamp: time.Now(),
+	}
+	return writeSnapshot(w, snapshot, m.iterateRawLocked)
+}
+
+// SnapshotReader reads the entries of a snapshot written by WriteSnapshot
+type SnapshotReader struct {
+	MapSnapshot
+
+	r     *bufio.Reader
+	key   []byte
+	value []byte
+
+	// parser decodes the entries of the map the snapshot was taken of
+	parser DumpParser
+}
+
+// NewSnapshotReader reads the header of the snapshot in r and returns a reader
+// for its entries.
+func NewSnapshotReader(r io.Reader) (*SnapshotReader, error) {
+	br := bufio.NewReader(r)
+
+	prefix := make([]byte, len(snapshotMagic)+4)
+	if _, err := io.ReadFull(br, prefix); err != nil {
+		return nil, fmt.Errorf("unable to read snapshot header: %s", err)
+	}
+	if !bytes.Equal(prefix[:len(snapshotMagic)], []byte(snapshotMagic)) {
+		return nil, fmt.Errorf("not a BPF map snapshot")
+	}
+
+	length := byteorder.Native.Uint32(prefix[len(snapshotMagic):])
+	if length > maxSnapshotHeaderLen {
+		return nil, fmt.Errorf("invalid snapshot header length %d", length)
+	}
+	header := make([]byte, length)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("unable to read snapshot header: %s", err)
+	}
+
+	s := &SnapshotReader{r: br}
+	if err := json.Unmarshal(header, &s.MapSnapshot); err != nil {
+		return nil, fmt.Errorf("unable to decode snapshot header: %s", err)
+	}
+	if s.Info.KeySize == 0 || s.Info.ValueSize == 0 {
+		return nil, fmt.Errorf("invalid key or value size in snapshot header")
+	}
+
+	s.key = make([]byte, s.Info.KeySize)
+	s.value = make([]byte, s.Info.ValueSize)
+	s.parser = lookupMapCodec(s.Name, &s.Info)
+	if s.parser == nil {
+		s.parser = rawDumpParser
+	}
+
+	return s, nil
+}
+
+// Next returns the raw key and value of the next entry. The returned slices
+// are only valid until the next call. Returns io.EOF after the last entry.
+func (s *SnapshotReader) Next() ([]byte, []byte, error) {
+	if _, err := io.ReadFull(s.r, s.key); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, nil, fmt.Errorf("truncated snapshot entry")
+		}
+		return nil, nil, err
+	}
+	if _, err := io.ReadFull(s.r, s.value); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, nil, fmt.Errorf("truncated snapshot entry")
+		}
+		return nil, nil, err
+	}
+	return s.key, s.value, nil
+}
+
+// NextDecoded returns the next entry decoded with the codec registered for
+// the map the snapshot was taken of, or as RawKey and RawValue if no codec is
+// registered. Returns io.EOF after the last entry.
+func (s *SnapshotReader) NextDecoded() (MapKey, MapValue, error) {
+	key, value, err := s.Next()
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.parser(key, value)
+}
+
+// RestoreSnapshot inserts all entries of the snapshot into the map, existing
+// entries with the same key are overwritten. The key and value size of the
+// map must match the snapshot. Returns the number of restored entries and the
+// number of entries which could not be inserted, e.g. because the map is full.
+//
+// The entries are restored as they are. Values holding timestamps relative to
+// the boot time of the host, such as the lifetime of connection tracking
+// entries, are only meaningful if the snapshot was taken since the last boot.
+func (m *Map) RestoreSnapshot(s *SnapshotReader) (int, int, error) {
+	if err := snapshotSupported(m.MapType); err != nil {
+		return 0, 0, err
+	}
+	if s.Info.KeySize != m.KeySize || s.Info.ValueSize != m.ValueSize {
+		return 0, 0, fmt.Errorf("snapshot of map with key size %d and value size %d does not match key size %d and value size %d",
+			s.Info.KeySize, s.Info.ValueSize, m.KeySize, m.ValueSize)
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if err := m.Open(); err != nil {
+		return 0, 0, err
+	}
+
+	var restored, failed int
+	batch := NewMapBatch(m.fd, int(m.KeySize), int(m.ValueSize))
+	flush := func() {
+		n := batch.Len()
+		errs := batch.Flush()
+		restored += n - len(errs)
+		failed += len(errs)
+	}
+
+	for {
+		key, value, err := s.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			flush()
+			return restored, failed, err
+		}
+
+		batch.Update(unsafe.Pointer(&key[0]), unsafe.Pointer(&value[0]))
+		if batch.Len() >= snapshotRestoreBatchSize {
+			flush()
+		}
+	}
+	flush()
+
+	return restored, failed, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpf
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BPFTestSuite) TestSnapshotRoundTrip(c *C) {
+	entries := [][2][]byte{
+		{{0x0a, 0x00, 0x00, 0x01}, {0x01, 0x02}},
+		{{0x0a, 0x00, 0x00, 0x02}, {0x03, 0x04}},
+	}
+	iterate := func(cb func(key, value []byte) error) error {
+		for _, e := range entries {
+			if err := cb(e[0], e[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	snapshot := &MapSnapshot{
+		Name: "cilium_test",
+		Info: MapInfo{
+			MapType:    MapTypeHash,
+			KeySize:    4,
+			ValueSize:  2,
+			MaxEntries: 1024,
+		},
+		Timestamp: time.Unix(1500000000, 0).UTC(),
+	}
+
+	var buf bytes.Buffer
+	n, err := writeSnapshot(&buf, snapshot, iterate)
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, 2)
+
+	r, err := NewSnapshotReader(bytes.NewReader(buf.Bytes()))
+	c.Assert(err, IsNil)
+	c.Assert(r.Name, Equals, snapshot.Name)
+	c.Assert(r.Info, Equals, snapshot.Info)
+	c.Assert(r.Timestamp.Equal(snapshot.Timestamp), Equals, true)
+
+	for _, e := range entries {
+		key, value, err := r.NextDecoded()
+		c.Assert(err, IsNil)
+		c.Assert(key, DeepEquals, MapKey(RawKey(e[0])))
+		c.Assert(value, DeepEquals, MapValue(RawValue(e[1])))
+	}
+	_, _, err = r.Next()
+	c.Assert(err, Equals, io.EOF)
+
+	// Truncated entry
+	r, err = NewSnapshotReader(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+	c.Assert(err, IsNil)
+	_, _, err = r.Next()
+	c.Assert(err, IsNil)
+	_, _, err = r.Next()
+	c.Assert(err, ErrorMatches, "truncated snapshot entry")
+}
+
+func (s *BPFTestSuite) TestSnapshotInvalid(c *C) {
+	_, err := NewSnapshotReader(bytes.NewReader([]byte("not a snapshot")))
+	c.Assert(err, ErrorMatches, "not a BPF map snapshot")
+
+	_, err = NewSnapshotReader(bytes.NewReader([]byte(snapshotMagic)))
+	c.Assert(err, NotNil)
+
+	c.Assert(snapshotSupported(MapTypeLRUHash), IsNil)
+	c.Assert(snapshotSupported(MapTypePerCPUHash), NotNil)
+}
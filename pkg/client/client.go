@@ -23,18 +23,25 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"text/tabwriter"
 	"time"
 
 	clientapi "github.com/cilium/cilium/api/v1/client"
 	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/api"
 	"github.com/cilium/cilium/pkg/defaults"
 	"github.com/cilium/cilium/pkg/ip"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
 
+	"github.com/go-openapi/runtime"
 	runtime_client "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 )
 
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "client")
+
 type Client struct {
 	clientapi.Cilium
 }
@@ -71,6 +78,36 @@ func configureTransport(tr *http.Transport, proto, addr string) *http.Transport
 	return tr
 }
 
+// versionTransport warns about responses of agents implementing a different
+// API schema version and about responses of deprecated API operations, so
+// that users notice before an incompatible agent or client breaks.
+type versionTransport struct {
+	next     http.RoundTripper
+	mismatch sync.Once
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *versionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if v := resp.Header.Get(api.SchemaVersionHeader); v != "" && v != api.SchemaVersion {
+		t.mismatch.Do(func() {
+			log.Warningf("Agent implements API version %s, client expects %s. "+
+				"Some fields may be missing or ignored.", v, api.SchemaVersion)
+		})
+	}
+
+	if resp.Header.Get(api.DeprecationHeader) == "true" {
+		log.Warningf("API operation %s %s is deprecated: %s",
+			req.Method, req.URL.Path, resp.Header.Get(api.WarningHeader))
+	}
+
+	return resp, nil
+}
+
 // NewDefaultClient creates a client with default parameters connecting to UNIX domain socket.
 func NewDefaultClient() (*Client, error) {
 	return NewClient("")
@@ -99,12 +136,30 @@ func NewClient(host string) (*Client, error) {
 	}
 
 	transport := configureTransport(nil, tmp[0], host)
-	httpClient := &http.Client{Transport: transport}
+	httpClient := &http.Client{Transport: &versionTransport{next: transport}}
 	clientTrans := runtime_client.NewWithClient(tmp[1], clientapi.DefaultBasePath,
 		clientapi.DefaultSchemes, httpClient)
+	clientTrans.Consumers[runtime.YAMLMime] = api.YAMLConsumer()
+	clientTrans.Producers[runtime.YAMLMime] = api.YAMLProducer()
 	return &Client{*clientapi.New(clientTrans, strfmt.Default)}, nil
 }
 
+// hintError is an error with an improved message, see Hint
+type hintError struct {
+	msg   string
+	cause error
+}
+
+func (e *hintError) Error() string {
+	return e.msg
+}
+
+// Cause returns the original error, it allows to inspect the error returned
+// by the API client via errors.Cause() of github.com/pkg/errors.
+func (e *hintError) Cause() error {
+	return e.cause
+}
+
 // Hint tries to improve the error message displayed to the user.
 func Hint(err error) error {
 	if err == nil {
@@ -112,9 +167,9 @@ func Hint(err error) error {
 	}
 	e, _ := url.PathUnescape(err.Error())
 	if strings.Contains(err.Error(), defaults.SockPath) {
-		return fmt.Errorf("%s\nIs the agent running?", e)
+		e += "\nIs the agent running?"
 	}
-	return fmt.Errorf("%s", e)
+	return &hintError{msg: e, cause: err}
 }
 
 func timeSince(since time.Time) string {
@@ -208,13 +263,34 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allAddresses,
 	if nm := sr.NodeMonitor; nm != nil {
 		fmt.Fprintf(w, "NodeMonitor:\tListening for events on %d CPUs with %dx%d of shared memory\n",
 			nm.Cpus, nm.Npages, nm.Pagesize)
+		if nm.Overwrite {
+			fmt.Fprintf(w, "\tWaking up every %d events, overwriting the oldest events when full\n", nm.WakeupEvents)
+		} else if nm.WakeupEvents > 1 {
+			fmt.Fprintf(w, "\tWaking up every %d events\n", nm.WakeupEvents)
+		}
 		if nm.Lost != 0 || nm.Unknown != 0 {
 			fmt.Fprintf(w, "\t%d events lost, %d unknown notifications\n", nm.Lost, nm.Unknown)
 		}
+		if nm.Lost != 0 && len(nm.LostPerCPU) > 0 {
+			lost := make([]string, 0, len(nm.LostPerCPU))
+			for cpu, n := range nm.LostPerCPU {
+				if n != 0 {
+					lost = append(lost, fmt.Sprintf("CPU %d: %d", cpu, n))
+				}
+			}
+			fmt.Fprintf(w, "\tEvents lost per CPU: %s\n", strings.Join(lost, ", "))
+		}
+		if nm.Overwritten != 0 {
+			fmt.Fprintf(w, "\tEvents overwritten before being read %d times\n", nm.Overwritten)
+		}
 	} else {
 		fmt.Fprintf(w, "NodeMonitor:\tDisabled\n")
 	}
 
+	if sr.BpfMaps != nil {
+		fmt.Fprintf(w, "BPF maps:\t%s\t%s\n", sr.BpfMaps.State, sr.BpfMaps.Msg)
+	}
+
 	var localNode *models.NodeElement
 	if sr.Cluster != nil {
 		if sr.Cluster.CiliumHealth != nil {
@@ -254,6 +330,10 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allAddresses,
 		}
 	}
 
+	if sr.Policy != nil {
+		fmt.Fprintf(w, "Policy revision:\t%d\n", sr.Policy.Revision)
+	}
+
 	if sr.Controllers != nil {
 		nFailing, out := 0, []string{"  Name\tLast success\tLast error\tCount\tMessage\n"}
 		for _, ctrl := range sr.Controllers {
@@ -293,6 +373,11 @@ func FormatStatusResponse(w io.Writer, sr *models.StatusResponse, allAddresses,
 
 	}
 
+	if er := sr.EndpointRestore; er != nil {
+		fmt.Fprintf(w, "Endpoint Restore:\t%s, %d/%d regenerated, %d failed, %d deferred\n",
+			er.State, er.Regenerated, er.Total, er.Failed, er.Deferred)
+	}
+
 	if sr.Proxy != nil {
 		fmt.Fprintf(w, "Proxy Status:\tOK, ip %s, port-range %s\n",
 			sr.Proxy.IP, sr.Proxy.PortRange)
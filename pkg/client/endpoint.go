@@ -76,6 +76,16 @@ func (c *Client) EndpointLogGet(id string) (models.EndpointStatusLog, error) {
 	return resp.Payload, nil
 }
 
+// EndpointControllersGet returns the status of the endpoint controllers
+func (c *Client) EndpointControllersGet(id string) (models.ControllerStatuses, error) {
+	params := endpoint.NewGetEndpointIDControllersParams().WithID(id).WithTimeout(api.ClientTimeout)
+	resp, err := c.Endpoint.GetEndpointIDControllers(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
 // EndpointHealthGet returns endpoint healthz
 func (c *Client) EndpointHealthGet(id string) (*models.EndpointHealth, error) {
 	params := endpoint.NewGetEndpointIDHealthzParams().WithID(id).WithTimeout(api.ClientTimeout)
@@ -86,6 +96,16 @@ func (c *Client) EndpointHealthGet(id string) (*models.EndpointHealth, error) {
 	return resp.Payload, nil
 }
 
+// EndpointL7StatsGet returns the L7 statistics of an endpoint
+func (c *Client) EndpointL7StatsGet(id string) ([]*models.L7Statistics, error) {
+	params := endpoint.NewGetEndpointIDL7statsParams().WithID(id).WithTimeout(api.ClientTimeout)
+	resp, err := c.Endpoint.GetEndpointIDL7stats(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
 // EndpointConfigGet returns endpoint configuration
 func (c *Client) EndpointConfigGet(id string) (*models.EndpointConfigurationStatus, error) {
 	params := endpoint.NewGetEndpointIDConfigParams().WithID(id).WithTimeout(api.ClientTimeout)
@@ -137,9 +157,7 @@ func (c *Client) EndpointLabelsPatch(id string, toAdd, toDelete models.Labels) e
 	userLbl := labels.NewLabelsFromModel(currentCfg.Status.Realized.User)
 	for _, lbl := range toAdd {
 		lblParsed := labels.ParseLabel(lbl)
-		if _, found := userLbl[lblParsed.Key]; !found {
-			userLbl[lblParsed.Key] = lblParsed
-		}
+		userLbl[lblParsed.Key] = lblParsed
 	}
 	for _, lbl := range toDelete {
 		lblParsed := labels.ParseLabel(lbl)
@@ -153,3 +171,29 @@ func (c *Client) EndpointLabelsPatch(id string, toAdd, toDelete models.Labels) e
 	_, err = c.Endpoint.PatchEndpointIDLabels(params.WithConfiguration(currentCfg.Spec))
 	return Hint(err)
 }
+
+// EndpointLabelsBatchPatch applies the label operations of batch to all
+// endpoints matched by their selectors and returns the result of each
+// matched endpoint
+func (c *Client) EndpointLabelsBatchPatch(batch *models.EndpointLabelsBatch) ([]*models.EndpointLabelsResult, error) {
+	params := endpoint.NewPatchEndpointParams().WithBatch(batch).WithTimeout(api.ClientTimeout)
+	resp, err := c.Endpoint.PatchEndpoint(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// EndpointDebugPut enables or disables debugging of the endpoint
+func (c *Client) EndpointDebugPut(id string, debug *models.EndpointDebug) error {
+	params := endpoint.NewPutEndpointIDDebugParams().WithID(id).WithTimeout(api.ClientTimeout)
+	_, err := c.Endpoint.PutEndpointIDDebug(params.WithDebug(debug))
+	return Hint(err)
+}
+
+// EndpointQuarantinePut quarantines the endpoint or lifts its quarantine
+func (c *Client) EndpointQuarantinePut(id string, quarantine *models.EndpointQuarantine) error {
+	params := endpoint.NewPutEndpointIDQuarantineParams().WithID(id).WithTimeout(api.ClientTimeout)
+	_, err := c.Endpoint.PutEndpointIDQuarantine(params.WithQuarantine(quarantine))
+	return Hint(err)
+}
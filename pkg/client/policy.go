@@ -59,3 +59,55 @@ func (c *Client) PolicyResolveGet(traceSelector *models.TraceSelector) (*models.
 	}
 	return resp.Payload, nil
 }
+
+// FqdnCacheGet returns the DNS lookups cached for ToFQDNs policies, optionally
+// restricted to matchName and to the DNS names relevant to endpointID.
+func (c *Client) FqdnCacheGet(matchName, endpointID string) ([]*models.DNSLookup, error) {
+	params := policy.NewGetFqdnCacheParams().WithTimeout(api.ClientTimeout)
+	if matchName != "" {
+		params.SetMatchname(&matchName)
+	}
+	if endpointID != "" {
+		params.SetEndpoint(&endpointID)
+	}
+	resp, err := c.Policy.GetFqdnCache(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// FqdnCacheDelete removes the DNS lookups of matchName, or of all names if
+// matchName is empty, from the cache of ToFQDNs policies.
+func (c *Client) FqdnCacheDelete(matchName string) error {
+	params := policy.NewDeleteFqdnCacheParams().WithTimeout(api.ClientTimeout)
+	if matchName != "" {
+		params.SetMatchname(&matchName)
+	}
+	_, err := c.Policy.DeleteFqdnCache(params)
+	return Hint(err)
+}
+
+// IPList returns the ipcache mappings of all prefixes within cidr, or of all
+// prefixes if cidr is empty.
+func (c *Client) IPList(cidr string) ([]*models.IPListEntry, error) {
+	params := policy.NewGetIPParams().WithTimeout(api.ClientTimeout)
+	if cidr != "" {
+		params.SetCidr(&cidr)
+	}
+	resp, err := c.Policy.GetIP(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
+
+// IPGet returns the ipcache mapping of the longest prefix containing addr.
+func (c *Client) IPGet(addr string) (*models.IPListEntry, error) {
+	params := policy.NewGetIPAddrParams().WithAddr(addr).WithTimeout(api.ClientTimeout)
+	resp, err := c.Policy.GetIPAddr(params)
+	if err != nil {
+		return nil, Hint(err)
+	}
+	return resp.Payload, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ExpandNestedJSON searches 'result' for strings with escaped JSON inside,
+// and expands the JSON.
+func ExpandNestedJSON(result bytes.Buffer) (bytes.Buffer, error) {
+	reStringWithJSON := regexp.MustCompile(`"[^"\\{]*{.*[^\\]"`)
+	reJSON := regexp.MustCompile(`{.*}`)
+	for {
+		var (
+			loc    []int
+			indent string
+		)
+
+		// Search for nested JSON; if we don't find any, then break.
+		resBytes := result.Bytes()
+		if loc = reStringWithJSON.FindIndex(resBytes); loc == nil {
+			break
+		}
+
+		// Determine the current indentation
+		for i := 0; i < loc[0]-1; i++ {
+			idx := loc[0] - i - 1
+			if resBytes[idx] != ' ' {
+				break
+			}
+			indent = fmt.Sprintf("\t%s\t", indent)
+		}
+
+		stringStart := loc[0]
+		stringEnd := loc[1]
+
+		// Unquote the string with the nested json.
+		quotedBytes := resBytes[stringStart:stringEnd]
+		unquoted, err := strconv.Unquote(string(quotedBytes))
+		if err != nil {
+			return bytes.Buffer{}, fmt.Errorf("Failed to Unquote string: %s\n%s", err.Error(), string(quotedBytes))
+		}
+
+		// Find the JSON within the quoted string.
+		nestedStart := 0
+		nestedEnd := 0
+		if locs := reJSON.FindAllStringIndex(unquoted, -1); locs != nil {
+			// The last match is the longest one.
+			last := len(locs) - 1
+			nestedStart = locs[last][0]
+			nestedEnd = locs[last][1]
+		} else if reJSON.Match(quotedBytes) {
+			// The entire string is JSON
+			nestedEnd = len(unquoted)
+		}
+
+		// Decode the nested JSON
+		decoded := ""
+		if nestedEnd != 0 {
+			m := make(map[string]interface{})
+			nested := bytes.NewBufferString(unquoted[nestedStart:nestedEnd])
+			if err := json.NewDecoder(nested).Decode(&m); err != nil {
+				return bytes.Buffer{}, fmt.Errorf("Failed to decode nested JSON: %s", err.Error())
+			}
+			decodedBytes, err := json.MarshalIndent(m, indent, "  ")
+			if err != nil {
+				return bytes.Buffer{}, fmt.Errorf("Cannot marshal nested JSON: %s", err.Error())
+			}
+			decoded = string(decodedBytes)
+		}
+
+		// Serialize
+		nextResult := bytes.Buffer{}
+		nextResult.Write(resBytes[0:stringStart])
+		nextResult.WriteString(string(unquoted[:nestedStart]))
+		nextResult.WriteString(string(decoded))
+		nextResult.WriteString(string(unquoted[nestedEnd:]))
+		nextResult.Write(resBytes[stringEnd:])
+		result = nextResult
+	}
+
+	return result, nil
+}
+
+// expandJSONStrings returns a generic representation of 'data' in which
+// every string value that holds an encoded JSON object or array is replaced
+// by its decoded form, so that jsonpath expressions can descend into it.
+func expandJSONStrings(data interface{}) (interface{}, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return nil, err
+	}
+
+	return expandJSONValue(generic), nil
+}
+
+func expandJSONValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = expandJSONValue(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = expandJSONValue(val)
+		}
+	case string:
+		trimmed := strings.TrimSpace(t)
+		if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+			return t
+		}
+		var nested interface{}
+		if err := json.Unmarshal([]byte(trimmed), &nested); err != nil {
+			return t
+		}
+		return expandJSONValue(nested)
+	}
+	return v
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *CMDHelpersSuite) TestExpandNestedJSON(c *C) {
+	buf := bytes.NewBufferString("not json at all")
+	_, err := ExpandNestedJSON(*buf)
+	c.Assert(err, IsNil)
+
+	buf = bytes.NewBufferString(`{\n\"escapedJson\": \"foo\"}`)
+	_, err = ExpandNestedJSON(*buf)
+	c.Assert(err, IsNil)
+
+	buf = bytes.NewBufferString(`nonjson={\n\"escapedJson\": \"foo\"}`)
+	_, err = ExpandNestedJSON(*buf)
+	c.Assert(err, IsNil)
+
+	buf = bytes.NewBufferString(`nonjson:morenonjson={\n\"escapedJson\": \"foo\"}`)
+	_, err = ExpandNestedJSON(*buf)
+	c.Assert(err, IsNil)
+
+	buf = bytes.NewBufferString(`{"foo": ["{\n  \"port\": 8080,\n  \"protocol\": \"TCP\"\n}"]}`)
+	_, err = ExpandNestedJSON(*buf)
+	c.Assert(err, IsNil)
+
+	buf = bytes.NewBufferString(`"foo": [
+  "bar:baz/alice={\"bob\":{\"charlie\":4}}\n"
+]`)
+	_, err = ExpandNestedJSON(*buf)
+	c.Assert(err, IsNil)
+}
+
+func (s *CMDHelpersSuite) TestExpandJSONStrings(c *C) {
+	type sampleData struct {
+		Policy string   `json:"policy"`
+		Rules  []string `json:"rules"`
+		Name   string   `json:"name"`
+	}
+
+	tt := sampleData{
+		Policy: `[{"endpointSelector": {"matchLabels": {"id": "foo"}}}]`,
+		Rules:  []string{"{\n  \"port\": 8080,\n  \"protocol\": \"TCP\"\n}"},
+		Name:   "{not json",
+	}
+
+	res, err := expandJSONStrings(tt)
+	c.Assert(err, IsNil)
+
+	m := res.(map[string]interface{})
+	c.Assert(m["name"], Equals, "{not json")
+
+	policy := m["policy"].([]interface{})
+	c.Assert(policy, HasLen, 1)
+	selector := policy[0].(map[string]interface{})["endpointSelector"].(map[string]interface{})
+	c.Assert(selector["matchLabels"], DeepEquals, map[string]interface{}{"id": "foo"})
+
+	rules := m["rules"].([]interface{})
+	c.Assert(rules[0].(map[string]interface{})["port"], Equals, float64(8080))
+	c.Assert(rules[0].(map[string]interface{})["protocol"], Equals, "TCP")
+
+	err = dumpJSON(tt, "{.policy[0].endpointSelector.matchLabels.id}")
+	c.Assert(err, IsNil)
+}
@@ -18,44 +18,61 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/util/jsonpath"
 )
 
 var outputOpt string
 
-// OutputJSON returns true if the JSON output option was specified
+// OutputJSON returns true if the JSON, YAML or JSONPath output option was
+// specified
 func OutputJSON() bool {
 	return len(outputOpt) > 0
 }
 
 //AddJSONOutput adds the -o|--output option to any cmd to export to json
+//or yaml
 func AddJSONOutput(cmd *cobra.Command) {
-	cmd.Flags().StringVarP(&outputOpt, "output", "o", "", "json| jsonpath='{}'")
+	cmd.Flags().StringVarP(&outputOpt, "output", "o", "", "json| yaml| jsonpath='{}'| custom-columns=<header>:<jsonpath>[,...]")
 }
 
 //PrintOutput receives an interface and dump the data using the --output flag.
-//ATM only json or jsonpath. In the future yaml
+//ATM only json, yaml, jsonpath or custom-columns.
 func PrintOutput(data interface{}) error {
 	var re = regexp.MustCompile(`^jsonpath\=(.*)`)
+	var reColumns = regexp.MustCompile(`^custom-columns\=(.*)`)
 
 	if outputOpt == "json" {
 		return dumpJSON(data, "")
 	}
 
+	if outputOpt == "yaml" {
+		return dumpYAML(data)
+	}
+
 	if re.MatchString(outputOpt) {
 		return dumpJSON(data, re.ReplaceAllString(outputOpt, "$1"))
 	}
 
+	if reColumns.MatchString(outputOpt) {
+		return dumpColumns(os.Stdout, data, reColumns.ReplaceAllString(outputOpt, "$1"))
+	}
+
 	return fmt.Errorf("Couldn't found output printer")
 }
 
 // dumpJSON dump the data variable to the stdout as json.
 // If somethings fail, it'll return an error
-// If jsonPath is passed, it'll run the json query over data var.
+// If jsonPath is passed, it'll run the json query over data var. Strings
+// holding escaped JSON are expanded first so that the query can descend
+// into them.
 func dumpJSON(data interface{}, jsonPath string) error {
 
 	if len(jsonPath) == 0 {
@@ -74,8 +91,14 @@ func dumpJSON(data interface{}, jsonPath string) error {
 		return err
 	}
 
+	expanded, err := expandJSONStrings(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't expand nested json: '%s'\n", err)
+		return err
+	}
+
 	buf := new(bytes.Buffer)
-	if err := parser.Execute(buf, data); err != nil {
+	if err := parser.Execute(buf, expanded); err != nil {
 		fmt.Fprintf(os.Stderr, "Couldn't parse jsonpath expression: '%s'\n", err)
 		return err
 
@@ -83,3 +106,88 @@ func dumpJSON(data interface{}, jsonPath string) error {
 	fmt.Println(buf.String())
 	return nil
 }
+
+// dumpYAML dump the data variable to the stdout as yaml.
+// If somethings fail, it'll return an error
+func dumpYAML(data interface{}) error {
+	result, err := yaml.Marshal(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't marshal to yaml: '%s'\n", err)
+		return err
+	}
+	fmt.Print(string(result))
+	return nil
+}
+
+// parseColumns parses a custom-columns specification of the form
+// `HEADER:.json.path,HEADER2:{.other.path}` and returns the headers and the
+// parsed jsonpath expression of each column.
+func parseColumns(spec string) ([]string, []*jsonpath.JSONPath, error) {
+	var (
+		headers []string
+		parsers []*jsonpath.JSONPath
+	)
+
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, nil, fmt.Errorf("invalid custom column '%s', expected <header>:<jsonpath>", column)
+		}
+
+		expr := parts[1]
+		if !strings.HasPrefix(expr, "{") {
+			expr = "{" + expr + "}"
+		}
+
+		parser := jsonpath.New(parts[0]).AllowMissingKeys(true)
+		if err := parser.Parse(expr); err != nil {
+			return nil, nil, fmt.Errorf("invalid jsonpath '%s' for column %s: %s", parts[1], parts[0], err)
+		}
+
+		headers = append(headers, parts[0])
+		parsers = append(parsers, parser)
+	}
+
+	return headers, parsers, nil
+}
+
+// dumpColumns writes data to w as a table with the columns described by
+// spec. If data is a list, one row is printed per element.
+func dumpColumns(w io.Writer, data interface{}, spec string) error {
+	headers, parsers, err := parseColumns(spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't parse custom columns: '%s'\n", err)
+		return err
+	}
+
+	expanded, err := expandJSONStrings(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't expand nested json: '%s'\n", err)
+		return err
+	}
+
+	rows, ok := expanded.([]interface{})
+	if !ok {
+		rows = []interface{}{expanded}
+	}
+
+	tw := tabwriter.NewWriter(w, 5, 0, 3, ' ', 0)
+	fmt.Fprintf(tw, "%s\t\n", strings.Join(headers, "\t"))
+	for _, row := range rows {
+		values := make([]string, 0, len(parsers))
+		for _, parser := range parsers {
+			buf := new(bytes.Buffer)
+			if err := parser.Execute(buf, row); err != nil {
+				fmt.Fprintf(os.Stderr, "Couldn't evaluate jsonpath expression: '%s'\n", err)
+				return err
+			}
+			value := buf.String()
+			if value == "" {
+				value = "<none>"
+			}
+			values = append(values, value)
+		}
+		fmt.Fprintf(tw, "%s\t\n", strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
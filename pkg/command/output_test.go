@@ -15,6 +15,7 @@
 package command
 
 import (
+	"bytes"
 	"testing"
 
 	. "gopkg.in/check.v1"
@@ -48,3 +49,52 @@ func (s *CMDHelpersSuite) TestDumpJSON(c *C) {
 		c.Fatalf("Dumpjson jsonpath no error with invalid path '%s'", err)
 	}
 }
+
+func (s *CMDHelpersSuite) TestDumpYAML(c *C) {
+	type sampleData struct {
+		ID   int
+		Name string
+	}
+
+	tt := sampleData{
+		ID:   1,
+		Name: "test",
+	}
+
+	err := dumpYAML(tt)
+	c.Assert(err, IsNil)
+
+	err = dumpYAML(func() {})
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *CMDHelpersSuite) TestDumpColumns(c *C) {
+	type sampleData struct {
+		ID     int               `json:"id"`
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels,omitempty"`
+	}
+
+	tt := []sampleData{
+		{ID: 1, Name: "foo", Labels: map[string]string{"app": "web"}},
+		{ID: 2, Name: "bar"},
+	}
+
+	buf := new(bytes.Buffer)
+	err := dumpColumns(buf, tt, "ID:.id,NAME:{.name},APP:.labels.app")
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, ""+
+		"ID   NAME   APP      \n"+
+		"1    foo    web      \n"+
+		"2    bar    <none>   \n")
+
+	buf.Reset()
+	err = dumpColumns(buf, tt[0], "NAME:.name")
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, "NAME   \nfoo    \n")
+
+	for _, spec := range []string{"", "ID", "ID:", ":.id", "ID:{{.id}}"} {
+		err = dumpColumns(buf, tt, spec)
+		c.Assert(err, Not(IsNil), Commentf("spec %q", spec))
+	}
+}
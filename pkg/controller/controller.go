@@ -30,6 +30,11 @@ import (
 const (
 	success = "success"
 	failure = "failure"
+
+	// DefaultErrorRetryMaxDuration is the maximum time to wait to run
+	// DoFunc again on return of an error when the back off is exponential
+	// and no ErrorRetryMaxDuration has been specified
+	DefaultErrorRetryMaxDuration = 5 * time.Minute
 )
 
 // ControllerFunc is a function that the controller runs. This type is used for
@@ -73,10 +78,53 @@ type ControllerParams struct {
 	// constant back off. The default is 1s.
 	ErrorRetryBaseDuration time.Duration
 
+	// ErrorRetryExponential when set to true, doubles the time to wait to
+	// run DoFunc again on each consecutive error instead of increasing it
+	// linearly.
+	ErrorRetryExponential bool
+
+	// ErrorRetryMaxDuration is the maximum time to wait to run DoFunc
+	// again on return of an error. If 0, the linear back off is unlimited
+	// and the exponential back off is limited to
+	// DefaultErrorRetryMaxDuration.
+	ErrorRetryMaxDuration time.Duration
+
 	// NoErrorRetry when set to true, disabled retries on errors
 	NoErrorRetry bool
 }
 
+// errorRetryInterval returns the time to wait to run DoFunc again after the
+// given number of consecutive errors.
+func (p *ControllerParams) errorRetryInterval(consecutiveErrors int) time.Duration {
+	base := p.ErrorRetryBaseDuration
+	if base == time.Duration(0) {
+		base = time.Second
+	}
+
+	maxInterval := p.ErrorRetryMaxDuration
+	if maxInterval == time.Duration(0) && p.ErrorRetryExponential {
+		maxInterval = DefaultErrorRetryMaxDuration
+	}
+
+	var interval time.Duration
+	if p.ErrorRetryExponential {
+		interval = base
+		for i := 1; i < consecutiveErrors; i++ {
+			interval *= 2
+			if maxInterval != time.Duration(0) && interval >= maxInterval {
+				break
+			}
+		}
+	} else {
+		interval = time.Duration(consecutiveErrors) * base
+	}
+
+	if maxInterval != time.Duration(0) && interval > maxInterval {
+		interval = maxInterval
+	}
+	return interval
+}
+
 // undefinedDoFunc is used when no DoFunc is set. controller.DoFunc is set to this
 // when the controller is incorrectly initialised.
 func undefinedDoFunc(name string) error {
@@ -218,12 +266,7 @@ func (c *Controller) runController() {
 					c.recordError(err)
 
 					if !params.NoErrorRetry {
-						if params.ErrorRetryBaseDuration != time.Duration(0) {
-							interval = time.Duration(errorRetries) * params.ErrorRetryBaseDuration
-						} else {
-							interval = time.Duration(errorRetries) * time.Second
-						}
-
+						interval = params.errorRetryInterval(errorRetries)
 						errorRetries++
 					}
 				}
@@ -315,9 +358,11 @@ func (c *Controller) GetStatusModel() *models.ControllerStatus {
 		Name: c.name,
 		UUID: strfmt.UUID(c.uuid),
 		Configuration: &models.ControllerStatusConfiguration{
-			ErrorRetry:     !c.params.NoErrorRetry,
-			ErrorRetryBase: strfmt.Duration(c.params.ErrorRetryBaseDuration),
-			Interval:       strfmt.Duration(c.params.RunInterval),
+			ErrorRetry:            !c.params.NoErrorRetry,
+			ErrorRetryBase:        strfmt.Duration(c.params.ErrorRetryBaseDuration),
+			ErrorRetryExponential: c.params.ErrorRetryExponential,
+			ErrorRetryMax:         strfmt.Duration(c.params.ErrorRetryMaxDuration),
+			Interval:              strfmt.Duration(c.params.RunInterval),
 		},
 		Status: &models.ControllerStatusStatus{
 			SuccessCount:            int64(c.successCount),
@@ -174,3 +174,26 @@ func (b *ControllerSuite) TestWaitForTermination(c *C) {
 		c.Fail()
 	}
 }
+
+func (b *ControllerSuite) TestErrorRetryInterval(c *C) {
+	linear := ControllerParams{}
+	c.Assert(linear.errorRetryInterval(1), Equals, time.Second)
+	c.Assert(linear.errorRetryInterval(3), Equals, 3*time.Second)
+	c.Assert(linear.errorRetryInterval(1000), Equals, 1000*time.Second)
+
+	linear.ErrorRetryMaxDuration = 10 * time.Second
+	c.Assert(linear.errorRetryInterval(1000), Equals, 10*time.Second)
+
+	exponential := ControllerParams{
+		ErrorRetryBaseDuration: 100 * time.Millisecond,
+		ErrorRetryExponential:  true,
+	}
+	c.Assert(exponential.errorRetryInterval(1), Equals, 100*time.Millisecond)
+	c.Assert(exponential.errorRetryInterval(2), Equals, 200*time.Millisecond)
+	c.Assert(exponential.errorRetryInterval(4), Equals, 800*time.Millisecond)
+	c.Assert(exponential.errorRetryInterval(1000), Equals, DefaultErrorRetryMaxDuration)
+
+	exponential.ErrorRetryMaxDuration = time.Second
+	c.Assert(exponential.errorRetryInterval(4), Equals, 800*time.Millisecond)
+	c.Assert(exponential.errorRetryInterval(5), Equals, time.Second)
+}
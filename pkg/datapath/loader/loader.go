@@ -130,3 +130,19 @@ func ReloadDatapath(ctx context.Context, ep endpoint) error {
 	}
 	return reloadDatapath(ctx, ep, &dirs)
 }
+
+// CompileObject compiles the BPF program at the path source, relative to the
+// BPF library directory, into the object file output in the directory dir.
+func CompileObject(ctx context.Context, source, output, dir string) error {
+	prog := progInfo{
+		Source:     source,
+		Output:     output,
+		OutputType: outputObject,
+	}
+	dirs := directoryInfo{
+		Library: option.Config.BpfDir,
+		Runtime: option.Config.StateDir,
+		Output:  dir,
+	}
+	return compile(ctx, &prog, &dirs, false)
+}
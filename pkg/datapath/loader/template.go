@@ -0,0 +1,74 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loader
+
+import (
+	"context"
+	"path"
+
+	"github.com/cilium/cilium/pkg/elf"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CompileTemplate compiles the endpoint BPF program from the header file in
+// the directory dir and returns the path of the resulting template object.
+//
+// The header file must only refer to endpoint specific values through the
+// symbols declared by bpf/include/bpf/static_data.h.
+func CompileTemplate(ctx context.Context, dir string) (string, error) {
+	dirs := directoryInfo{
+		Library: option.Config.BpfDir,
+		Runtime: option.Config.StateDir,
+		Output:  dir,
+	}
+	if err := compile(ctx, datapathProg, &dirs, false); err != nil {
+		log.WithError(err).WithField(logfields.Path, dir).Warn("Failed to compile template")
+		return "", err
+	}
+	return path.Join(dir, endpointObj), nil
+}
+
+// LoadFromTemplate instantiates the template object at the path template for
+// the specified endpoint by substituting the endpoint specific values in
+// intOptions and strOptions, and loads the resulting object onto the
+// interface associated with the endpoint.
+//
+// Expects the caller to have created the directory at the path ep.StateDir().
+func LoadFromTemplate(ctx context.Context, ep endpoint, template string, intOptions map[string]uint32, strOptions map[string]string) error {
+	obj, err := elf.Open(template)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	dirs := directoryInfo{
+		Library: option.Config.BpfDir,
+		Runtime: option.Config.StateDir,
+		Output:  ep.StateDir(),
+	}
+	objPath := path.Join(dirs.Output, endpointObj)
+	if err := obj.Write(objPath, intOptions, strOptions); err != nil {
+		ep.Logger(Subsystem).WithError(err).WithFields(logrus.Fields{
+			"template":     template,
+			logfields.Path: objPath,
+		}).Warn("Failed to instantiate template")
+		return err
+	}
+
+	return reloadDatapath(ctx, ep, &dirs)
+}
@@ -94,3 +94,30 @@ func (p *RouteSuite) TestReplaceRoute(c *C) {
 	testReplaceRoute(c, "2.2.0.0/16", "1.2.3.4")
 	testReplaceRoute(c, "f00d::a02:200:0:0/96", "f00d::a02:100:0:815b")
 }
+
+func (p *RouteSuite) TestReplaceRule(c *C) {
+	rule := Rule{Priority: 9, Mark: 0x200, Mask: 0xf00, Table: 2005}
+
+	// delete rule in case it exists from a previous failed run
+	DeleteRule(rule)
+
+	c.Assert(ReplaceRule(rule), IsNil)
+	exists, err := lookupRule(rule)
+	c.Assert(err, IsNil)
+	c.Assert(exists, Equals, true)
+
+	// installing the rule again must not duplicate it
+	c.Assert(ReplaceRule(rule), IsNil)
+
+	c.Assert(DeleteRule(rule), IsNil)
+	exists, err = lookupRule(rule)
+	c.Assert(err, IsNil)
+	c.Assert(exists, Equals, false)
+}
+
+func (p *RouteSuite) TestReplaceLocalRoute(c *C) {
+	c.Assert(ReplaceLocalRoute(2005), IsNil)
+	c.Assert(ReplaceLocalRoute(2005), IsNil)
+	c.Assert(DeleteLocalRoute(2005), IsNil)
+	c.Assert(DeleteLocalRoute(2005), IsNil)
+}
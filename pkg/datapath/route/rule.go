@@ -0,0 +1,138 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package route
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// Rule is the specification of an IPv4 rule which looks up the routes of
+// Table for all packets carrying Mark in the bits of Mask
+type Rule struct {
+	Priority int
+	Mark     int
+	Mask     int
+	Table    int
+}
+
+func (r Rule) getNetlinkRule() *netlink.Rule {
+	rule := netlink.NewRule()
+	rule.Family = netlink.FAMILY_V4
+	rule.Priority = r.Priority
+	rule.Mark = r.Mark
+	rule.Mask = r.Mask
+	rule.Table = r.Table
+	return rule
+}
+
+// lookupRule returns true if a rule matching spec is installed
+func lookupRule(spec Rule) (bool, error) {
+	rules, err := netlink.RuleList(netlink.FAMILY_V4)
+	if err != nil {
+		return false, err
+	}
+
+	for _, r := range rules {
+		if r.Priority == spec.Priority && r.Mark == spec.Mark &&
+			r.Mask == spec.Mask && r.Table == spec.Table {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ReplaceRule adds the specified rule if it is not installed yet
+func ReplaceRule(spec Rule) error {
+	exists, err := lookupRule(spec)
+	if err != nil {
+		return fmt.Errorf("unable to list rules: %s", err)
+	}
+
+	if !exists {
+		if err := netlink.RuleAdd(spec.getNetlinkRule()); err != nil {
+			return fmt.Errorf("unable to add rule: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteRule removes the specified rule if it is installed
+func DeleteRule(spec Rule) error {
+	exists, err := lookupRule(spec)
+	if err != nil {
+		return fmt.Errorf("unable to list rules: %s", err)
+	}
+
+	if exists {
+		if err := netlink.RuleDel(spec.getNetlinkRule()); err != nil {
+			return fmt.Errorf("unable to delete rule: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// getLocalRoute returns the default route of table which delivers all IPv4
+// packets to the local host
+func getLocalRoute(table int) (*netlink.Route, error) {
+	lo, err := netlink.LinkByName("lo")
+	if err != nil {
+		return nil, fmt.Errorf("unable to lookup interface lo: %s", err)
+	}
+
+	return &netlink.Route{
+		Dst:       &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)},
+		LinkIndex: lo.Attrs().Index,
+		Scope:     netlink.SCOPE_HOST,
+		Table:     table,
+		Type:      unix.RTN_LOCAL,
+	}, nil
+}
+
+// ReplaceLocalRoute adds or replaces the default route of table which
+// delivers all IPv4 packets to the local host
+func ReplaceLocalRoute(table int) error {
+	route, err := getLocalRoute(table)
+	if err != nil {
+		return err
+	}
+
+	if err := netlink.RouteReplace(route); err != nil {
+		return fmt.Errorf("unable to add local route to table %d: %s", table, err)
+	}
+
+	return nil
+}
+
+// DeleteLocalRoute removes the default route of table which delivers all
+// IPv4 packets to the local host
+func DeleteLocalRoute(table int) error {
+	route, err := getLocalRoute(table)
+	if err != nil {
+		return err
+	}
+
+	if err := netlink.RouteDel(route); err != nil && err != unix.ESRCH {
+		return fmt.Errorf("unable to delete local route from table %d: %s", table, err)
+	}
+
+	return nil
+}
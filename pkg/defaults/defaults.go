@@ -39,6 +39,18 @@ const (
 	//StateDir is the default path for the state directory relative to RuntimePath
 	StateDir = "state"
 
+	// MapSchemaDir is the path for the schema versions of pinned BPF maps
+	// relative to StateDir
+	MapSchemaDir = "maps"
+
+	// SockopsDir is the path for the compiled sockops programs relative to
+	// StateDir
+	SockopsDir = "sockops"
+
+	// CgroupRoot is the default path to the cgroup2 filesystem the sockops
+	// programs are attached to
+	CgroupRoot = RuntimePath + "/cgroupv2"
+
 	// BpfDir is the default path for template files relative to LibDir
 	BpfDir = "bpf"
 
@@ -61,6 +73,11 @@ const (
 	// This is the 1.2 protocol version.
 	MonitorSockPath1_2 = RuntimePath + "/monitor1_2.sock"
 
+	// MonitorSockPath1_3 is the path to the UNIX domain socket used to
+	// distribute BPF and agent events to listeners.
+	// This is the 1.3 protocol version.
+	MonitorSockPath1_3 = RuntimePath + "/monitor1_3.sock"
+
 	// PidFilePath is the path to the pid file for the agent.
 	PidFilePath = RuntimePath + "/cilium.pid"
 
@@ -88,10 +105,18 @@ const (
 	// ToFQDNsMinTTL is the default lower bound for TTLs used with ToFQDNs rules.
 	ToFQDNsMinTTL = 3600 // 1 hour in seconds
 
+	// ToFQDNsProxyUpdateTimeout is the maximum time the DNS proxy holds back
+	// a response until the policy of the querying endpoint allows its IPs.
+	ToFQDNsProxyUpdateTimeout = 2 * time.Second
+
 	// IdentityChangeGracePeriod is the grace period that needs to pass
 	// before an endpoint that has changed its identity will start using
 	// that new identity. During the grace period, the new identity has
 	// already been allocated and other nodes in the cluster have a chance
 	// to whitelist the new upcoming identity of the endpoint.
 	IdentityChangeGracePeriod = 25 * time.Second
+
+	// WatchdogActions is the default list of actions taken in order when a
+	// watchdog budget is exceeded
+	WatchdogActions = "log,gc,restart-monitor,degrade"
 )
@@ -0,0 +1,372 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elf
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "elf")
+
+const (
+	// Offsets into the ELF64 file header
+	ehdrShoff     = 0x28
+	ehdrShentsize = 0x3a
+	ehdrShstrndx  = 0x3e
+
+	// Offsets into an ELF64 section header
+	shdrName = 0
+	shdrSize = 32
+
+	// Size of an ELF64 symbol table entry and offsets into it
+	symSize  = 24
+	symName  = 0
+	symShndx = 6
+
+	// Size of an ELF64 relocation entry without addend
+	relSize = 16
+
+	// opLdImm64 is the opcode of the BPF instruction loading a 64 bit
+	// immediate spread over two instruction slots.
+	opLdImm64 = 0x18
+	insnSize  = 8
+)
+
+// ELF is a BPF ELF object which can be instantiated with endpoint specific
+// values.
+//
+// Values substituted as integers must be referenced in the object by the
+// address of an undefined symbol, see bpf/include/bpf/static_data.h. The 64
+// bit immediate load of every such reference is rewritten to load the value
+// and the relocation is removed. Values substituted as strings are names in
+// the string tables of the object, e.g. map names or section names. The new
+// name must not be longer than the name in the template.
+type ELF struct {
+	path  string
+	file  *elf.File
+	data  []byte
+	order binary.ByteOrder
+
+	symtab  *elf.Section
+	symbols []symbol
+}
+
+type symbol struct {
+	name       string
+	nameOffset uint32
+	undefined  bool
+}
+
+// Open opens the BPF ELF object at the given path.
+func Open(path string) (*ELF, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := elf.NewFile(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", path, err)
+	}
+	if file.Class != elf.ELFCLASS64 || file.Machine != elf.EM_BPF {
+		return nil, fmt.Errorf("%s is not a 64 bit BPF object", path)
+	}
+
+	e := &ELF{
+		path:  path,
+		file:  file,
+		data:  data,
+		order: file.ByteOrder,
+	}
+	if err := e.readSymbols(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// readSymbols reads the raw symbol table as debug/elf does not expose the
+// offsets of the symbol names.
+func (e *ELF) readSymbols() error {
+	for _, s := range e.file.Sections {
+		if s.Type == elf.SHT_SYMTAB {
+			e.symtab = s
+			break
+		}
+	}
+	if e.symtab == nil {
+		return fmt.Errorf("%s has no symbol table", e.path)
+	}
+	if int(e.symtab.Link) >= len(e.file.Sections) {
+		return fmt.Errorf("%s has an invalid symbol table", e.path)
+	}
+	strtab, err := e.sectionData(e.file.Sections[e.symtab.Link])
+	if err != nil {
+		return err
+	}
+	symtab, err := e.sectionData(e.symtab)
+	if err != nil {
+		return err
+	}
+
+	e.symbols = make([]symbol, len(symtab)/symSize)
+	for i := range e.symbols {
+		entry := symtab[i*symSize : (i+1)*symSize]
+		nameOffset := e.order.Uint32(entry[symName:])
+		e.symbols[i] = symbol{
+			name:       cString(strtab, nameOffset),
+			nameOffset: nameOffset,
+			undefined:  elf.SectionIndex(e.order.Uint16(entry[symShndx:])) == elf.SHN_UNDEF,
+		}
+	}
+	return nil
+}
+
+// sectionData returns the contents of section s in data.
+func (e *ELF) sectionData(s *elf.Section) ([]byte, error) {
+	if s.Type == elf.SHT_NOBITS {
+		return nil, nil
+	}
+	end := s.Offset + s.Size
+	if end < s.Offset || end > uint64(len(e.data)) {
+		return nil, fmt.Errorf("section %s of %s is out of bounds", s.Name, e.path)
+	}
+	return e.data[s.Offset:end], nil
+}
+
+// cString returns the NUL terminated string at offset in table.
+func cString(table []byte, offset uint32) string {
+	if int(offset) >= len(table) {
+		return ""
+	}
+	s := table[offset:]
+	if end := bytes.IndexByte(s, 0); end >= 0 {
+		s = s[:end]
+	}
+	return string(s)
+}
+
+// Write writes a copy of the object to path, with the symbols in intOptions
+// substituted by their values and the names in strOptions renamed. Returns
+// an error if the object references an undefined symbol which is not
+// substituted, as such an object can not be loaded.
+func (e *ELF) Write(path string, intOptions map[string]uint32, strOptions map[string]string) error {
+	data := make([]byte, len(e.data))
+	copy(data, e.data)
+
+	w := &writer{ELF: e, data: data}
+	if err := w.substituteInts(intOptions); err != nil {
+		return err
+	}
+	if err := w.substituteStrings(strOptions); err != nil {
+		return err
+	}
+
+	log.WithFields(logrus.Fields{
+		"template":     e.path,
+		logfields.Path: path,
+	}).Debug("Writing object from template")
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Close releases the resources of the object.
+func (e *ELF) Close() error {
+	return e.file.Close()
+}
+
+// writer modifies a copy of the data of an ELF.
+type writer struct {
+	*ELF
+	data []byte
+}
+
+// sectionHeader returns the raw section header of the section with index i.
+func (w *writer) sectionHeader(i int) []byte {
+	shoff := w.order.Uint64(w.data[ehdrShoff:])
+	shentsize := uint64(w.order.Uint16(w.data[ehdrShentsize:]))
+	offset := shoff + uint64(i)*shentsize
+	return w.data[offset : offset+shentsize]
+}
+
+func (w *writer) section(s *elf.Section) []byte {
+	return w.data[s.Offset : s.Offset+s.Size]
+}
+
+// substituteInts rewrites all instructions referencing the symbols in
+// intOptions to load the values of the symbols, and removes the
+// relocations of these instructions.
+func (w *writer) substituteInts(intOptions map[string]uint32) error {
+	for i, s := range w.file.Sections {
+		if s.Type != elf.SHT_REL || int(s.Link) >= len(w.file.Sections) ||
+			w.file.Sections[s.Link] != w.symtab {
+			continue
+		}
+		if int(s.Info) >= len(w.file.Sections) {
+			return fmt.Errorf("relocation section %s has an invalid target", s.Name)
+		}
+		target := w.file.Sections[s.Info]
+		if _, err := w.sectionData(s); err != nil {
+			return err
+		}
+		if _, err := w.sectionData(target); err != nil {
+			return err
+		}
+		rels := w.section(s)
+		text := w.section(target)
+
+		kept := 0
+		for j := 0; j+relSize <= len(rels); j += relSize {
+			offset := w.order.Uint64(rels[j:])
+			symIndex := w.order.Uint64(rels[j+8:]) >> 32
+			if symIndex >= uint64(len(w.symbols)) {
+				return fmt.Errorf("relocation in %s references invalid symbol %d", s.Name, symIndex)
+			}
+			sym := w.symbols[symIndex]
+
+			value, ok := intOptions[sym.name]
+			if !ok {
+				if sym.undefined {
+					return fmt.Errorf("no value for symbol %s", sym.name)
+				}
+				copy(rels[kept*relSize:], rels[j:j+relSize])
+				kept++
+				continue
+			}
+
+			if offset+2*insnSize > uint64(len(text)) || text[offset] != opLdImm64 {
+				return fmt.Errorf("symbol %s is not referenced by a 64 bit load in %s", sym.name, target.Name)
+			}
+			insn := text[offset : offset+2*insnSize]
+			// The immediate holds the addend of the relocation
+			imm := uint64(w.order.Uint32(insn[4:])) | uint64(w.order.Uint32(insn[insnSize+4:]))<<32
+			imm += uint64(value)
+			w.order.PutUint32(insn[4:], uint32(imm))
+			w.order.PutUint32(insn[insnSize+4:], uint32(imm>>32))
+		}
+
+		for j := kept * relSize; j < len(rels); j++ {
+			rels[j] = 0
+		}
+		w.order.PutUint64(w.sectionHeader(i)[shdrSize:], uint64(kept*relSize))
+	}
+	return nil
+}
+
+// substituteStrings renames all names in the string tables of the object
+// which match a key of strOptions.
+//
+// The compiler stores names which are the tail of another name only once,
+// e.g. the name of a section is shared with the name of its relocation
+// section ".rel<name>". A string is renamed if either the string itself or
+// a name sharing its tail is renamed, as long as all names sharing the
+// string are renamed consistently.
+func (w *writer) substituteStrings(strOptions map[string]string) error {
+	if len(strOptions) == 0 {
+		return nil
+	}
+
+	shstrndx := int(w.order.Uint16(w.data[ehdrShstrndx:]))
+	for i, s := range w.file.Sections {
+		if s.Type != elf.SHT_STRTAB {
+			continue
+		}
+		if _, err := w.sectionData(s); err != nil {
+			return err
+		}
+
+		// Offsets of all names referencing this string table
+		var refs []int
+		if i == int(w.symtab.Link) {
+			for _, sym := range w.symbols {
+				refs = append(refs, int(sym.nameOffset))
+			}
+		}
+		if i == shstrndx {
+			for j := range w.file.Sections {
+				refs = append(refs, int(w.order.Uint32(w.sectionHeader(j)[shdrName:])))
+			}
+		}
+
+		table := w.section(s)
+		for start := 0; start < len(table); {
+			end := bytes.IndexByte(table[start:], 0)
+			if end < 0 {
+				end = len(table)
+			} else {
+				end += start
+			}
+			old := string(table[start:end])
+			if err := renameString(table[start:end], old, refs, start, strOptions); err != nil {
+				return err
+			}
+			start = end + 1
+		}
+	}
+	return nil
+}
+
+// renameString renames the string old stored in str at offset start of the
+// string table, with refs being the offsets of all names in the table.
+func renameString(str []byte, old string, refs []int, start int, strOptions map[string]string) error {
+	if old == "" {
+		return nil
+	}
+
+	name, ok := strOptions[old]
+	for _, ref := range refs {
+		if ok {
+			break
+		}
+		if ref > start && ref < start+len(old) {
+			var tail string
+			if tail, ok = strOptions[old[ref-start:]]; ok {
+				name = old[:ref-start] + tail
+			}
+		}
+	}
+	if !ok {
+		return nil
+	}
+	if len(name) > len(old) {
+		return fmt.Errorf("name %s is longer than %s", name, old)
+	}
+
+	for _, ref := range refs {
+		if ref <= start || ref >= start+len(old) {
+			continue
+		}
+		want, renamed := strOptions[old[ref-start:]]
+		if !renamed {
+			want = old[ref-start:]
+		}
+		if ref-start > len(name) || name[ref-start:] != want {
+			return fmt.Errorf("name %s shares its storage with %s", old, old[ref-start:])
+		}
+	}
+
+	n := copy(str, name)
+	for j := n; j < len(str); j++ {
+		str[j] = 0
+	}
+	return nil
+}
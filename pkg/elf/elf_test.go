@@ -0,0 +1,247 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elf
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type ELFSuite struct {
+	dir string
+}
+
+var _ = Suite(&ELFSuite{})
+
+func (s *ELFSuite) SetUpTest(c *C) {
+	var err error
+	s.dir, err = ioutil.TempDir("", "cilium-elf-test")
+	c.Assert(err, IsNil)
+}
+
+func (s *ELFSuite) TearDownTest(c *C) {
+	os.RemoveAll(s.dir)
+}
+
+type testSection struct {
+	name    string
+	typ     elf.SectionType
+	link    uint32
+	info    uint32
+	entsize uint64
+	data    []byte
+}
+
+// ldImm64 returns a 64 bit immediate load into r1 with the given immediate.
+func ldImm64(imm uint64) []byte {
+	insn := make([]byte, 2*insnSize)
+	insn[0] = opLdImm64
+	insn[1] = 1
+	binary.LittleEndian.PutUint32(insn[4:], uint32(imm))
+	binary.LittleEndian.PutUint32(insn[insnSize+4:], uint32(imm>>32))
+	return insn
+}
+
+// writeTestObject writes a minimal BPF object equivalent to what the
+// compiler emits for static data and map references, including the sharing
+// of the program section name with its relocation section name:
+//
+//	1/65535:                      .symtab:
+//	  r1 = LXC_ID ll                [1] LXC_ID (undefined)
+//	  r1 = LXC_IPV4 + 4 ll          [2] LXC_IPV4 (undefined)
+//	  r1 = cilium_calls_65535 ll    [3] cilium_calls_65535 (maps)
+func writeTestObject(c *C, path string) {
+	strtab := []byte("\x00LXC_ID\x00LXC_IPV4\x00cilium_calls_65535\x00")
+	symbols := []struct {
+		name  uint32
+		shndx uint16
+	}{{0, 0}, {1, 0}, {8, 0}, {17, 4}}
+	symtab := &bytes.Buffer{}
+	for _, sym := range symbols {
+		entry := make([]byte, symSize)
+		binary.LittleEndian.PutUint32(entry[symName:], sym.name)
+		binary.LittleEndian.PutUint16(entry[symShndx:], sym.shndx)
+		symtab.Write(entry)
+	}
+
+	text := &bytes.Buffer{}
+	rels := &bytes.Buffer{}
+	for i, insn := range []struct {
+		sym    uint64
+		addend uint64
+	}{{1, 0}, {2, 4}, {3, 0}} {
+		text.Write(ldImm64(insn.addend))
+		rel := make([]byte, relSize)
+		binary.LittleEndian.PutUint64(rel, uint64(i*2*insnSize))
+		binary.LittleEndian.PutUint64(rel[8:], insn.sym<<32|1)
+		rels.Write(rel)
+	}
+
+	sections := []testSection{
+		{},
+		{name: "1/65535", typ: elf.SHT_PROGBITS, data: text.Bytes()},
+		{name: ".rel1/65535", typ: elf.SHT_REL, link: 5, info: 1, entsize: relSize, data: rels.Bytes()},
+		{name: ".strtab", typ: elf.SHT_STRTAB, data: strtab},
+		{name: "maps", typ: elf.SHT_PROGBITS, data: make([]byte, 20)},
+		{name: ".symtab", typ: elf.SHT_SYMTAB, link: 3, info: 1, entsize: symSize, data: symtab.Bytes()},
+		{name: ".shstrtab", typ: elf.SHT_STRTAB},
+	}
+	shstrtab := []byte{0}
+	names := make([]uint32, len(sections))
+	for i := 2; i < len(sections); i++ {
+		names[i] = uint32(len(shstrtab))
+		shstrtab = append(append(shstrtab, sections[i].name...), 0)
+	}
+	// The program section name is the tail of its relocation section name
+	names[1] = names[2] + uint32(len(".rel"))
+	sections[len(sections)-1].data = shstrtab
+
+	const ehdrSize, shdrEntSize = 64, 64
+	out := make([]byte, ehdrSize)
+	copy(out, elf.ELFMAG)
+	out[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	out[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	out[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	binary.LittleEndian.PutUint16(out[16:], uint16(elf.ET_REL))
+	binary.LittleEndian.PutUint16(out[18:], uint16(elf.EM_BPF))
+	binary.LittleEndian.PutUint32(out[20:], uint32(elf.EV_CURRENT))
+	binary.LittleEndian.PutUint16(out[52:], ehdrSize)
+	binary.LittleEndian.PutUint16(out[ehdrShentsize:], shdrEntSize)
+	binary.LittleEndian.PutUint16(out[60:], uint16(len(sections)))
+	binary.LittleEndian.PutUint16(out[ehdrShstrndx:], uint16(len(sections)-1))
+
+	offsets := make([]uint64, len(sections))
+	for i, section := range sections {
+		offsets[i] = uint64(len(out))
+		out = append(out, section.data...)
+		for len(out)%8 != 0 {
+			out = append(out, 0)
+		}
+	}
+	binary.LittleEndian.PutUint64(out[ehdrShoff:], uint64(len(out)))
+	for i, section := range sections {
+		shdr := make([]byte, shdrEntSize)
+		if i > 0 {
+			binary.LittleEndian.PutUint32(shdr[shdrName:], names[i])
+			binary.LittleEndian.PutUint32(shdr[4:], uint32(section.typ))
+			binary.LittleEndian.PutUint64(shdr[24:], offsets[i])
+			binary.LittleEndian.PutUint64(shdr[shdrSize:], uint64(len(section.data)))
+			binary.LittleEndian.PutUint32(shdr[40:], section.link)
+			binary.LittleEndian.PutUint32(shdr[44:], section.info)
+			binary.LittleEndian.PutUint64(shdr[48:], 8)
+			binary.LittleEndian.PutUint64(shdr[56:], section.entsize)
+		}
+		out = append(out, shdr...)
+	}
+
+	c.Assert(ioutil.WriteFile(path, out, 0644), IsNil)
+}
+
+func readImm64(c *C, text []byte, offset int) uint64 {
+	c.Assert(text[offset], Equals, byte(opLdImm64))
+	return uint64(binary.LittleEndian.Uint32(text[offset+4:])) |
+		uint64(binary.LittleEndian.Uint32(text[offset+insnSize+4:]))<<32
+}
+
+func (s *ELFSuite) TestWrite(c *C) {
+	template := filepath.Join(s.dir, "template.o")
+	writeTestObject(c, template)
+
+	obj, err := Open(template)
+	c.Assert(err, IsNil)
+	defer obj.Close()
+
+	out := filepath.Join(s.dir, "bpf_lxc.o")
+	err = obj.Write(out, map[string]uint32{
+		"LXC_ID":   42,
+		"LXC_IPV4": 0x0100000a,
+	}, map[string]string{
+		"cilium_calls_65535": "cilium_calls_42",
+		"1/65535":            "1/42",
+	})
+	c.Assert(err, IsNil)
+
+	f, err := elf.Open(out)
+	c.Assert(err, IsNil)
+	defer f.Close()
+
+	prog := f.Section("1/42")
+	c.Assert(prog, NotNil)
+	c.Assert(f.Section("1/65535"), IsNil)
+	c.Assert(f.Sections[2].Name, Equals, ".rel1/42")
+	text, err := prog.Data()
+	c.Assert(err, IsNil)
+	c.Assert(readImm64(c, text, 0), Equals, uint64(42))
+	c.Assert(readImm64(c, text, 16), Equals, uint64(0x0100000a+4))
+	c.Assert(readImm64(c, text, 32), Equals, uint64(0))
+
+	// Only the relocation of the map reference is left
+	rels := f.Sections[2]
+	c.Assert(rels.Size, Equals, uint64(relSize))
+	relData, err := rels.Data()
+	c.Assert(err, IsNil)
+	c.Assert(binary.LittleEndian.Uint64(relData), Equals, uint64(32))
+	c.Assert(binary.LittleEndian.Uint64(relData[8:])>>32, Equals, uint64(3))
+
+	symbols, err := f.Symbols()
+	c.Assert(err, IsNil)
+	c.Assert(symbols[2].Name, Equals, "cilium_calls_42")
+
+	// The template is not modified
+	tf, err := elf.Open(template)
+	c.Assert(err, IsNil)
+	defer tf.Close()
+	c.Assert(tf.Section("1/65535"), NotNil)
+}
+
+func (s *ELFSuite) TestWriteErrors(c *C) {
+	template := filepath.Join(s.dir, "template.o")
+	writeTestObject(c, template)
+
+	obj, err := Open(template)
+	c.Assert(err, IsNil)
+	defer obj.Close()
+
+	out := filepath.Join(s.dir, "bpf_lxc.o")
+
+	// Undefined symbol without a value
+	err = obj.Write(out, map[string]uint32{"LXC_ID": 42}, nil)
+	c.Assert(err, ErrorMatches, "no value for symbol LXC_IPV4")
+
+	// Name longer than the template name
+	err = obj.Write(out, map[string]uint32{"LXC_ID": 42, "LXC_IPV4": 1},
+		map[string]string{"1/65535": "1/655350"})
+	c.Assert(err, ErrorMatches, "name .rel1/655350 is longer than .rel1/65535")
+
+	// Name whose tail is the name of another section
+	err = obj.Write(out, map[string]uint32{"LXC_ID": 42, "LXC_IPV4": 1},
+		map[string]string{".rel1/65535": ".rel1/42"})
+	c.Assert(err, ErrorMatches, "name .rel1/65535 shares its storage with 1/65535")
+
+	_, err = Open(filepath.Join(s.dir, "missing.o"))
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,114 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/maps/throttlemap"
+	"github.com/cilium/cilium/pkg/option"
+)
+
+const (
+	// egressRateInterval is the interval in which the egress rate of an
+	// endpoint with an egress bandwidth limit is sampled
+	egressRateInterval = 10 * time.Second
+
+	// egressRateMinInterval is the minimum time between two samples for
+	// the rate to be updated, shorter intervals are too imprecise
+	egressRateMinInterval = time.Second
+)
+
+// egressRate is the rate at which packets pass the egress bandwidth limit of
+// an endpoint, derived from the number of passed bytes in the datapath
+type egressRate struct {
+	mutex lock.Mutex
+
+	// bytes is the number of passed bytes at the time of the last sample
+	bytes uint64
+
+	// sampled is the time of the last sample, zero if there is none
+	sampled time.Time
+
+	// rate is the rate between the last two samples in bits per second
+	rate int64
+}
+
+// sample updates the rate from the given number of passed bytes
+func (r *egressRate) sample(bytes uint64, now time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	elapsed := now.Sub(r.sampled)
+	if !r.sampled.IsZero() && elapsed < egressRateMinInterval {
+		return
+	}
+	if !r.sampled.IsZero() && bytes >= r.bytes {
+		r.rate = int64(float64(bytes-r.bytes) * 8 / elapsed.Seconds())
+	}
+	r.bytes = bytes
+	r.sampled = now
+}
+
+// reset forgets all samples
+func (r *egressRate) reset() {
+	r.mutex.Lock()
+	r.bytes, r.sampled, r.rate = 0, time.Time{}, 0
+	r.mutex.Unlock()
+}
+
+// get returns the rate in bits per second
+func (r *egressRate) get() int64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.rate
+}
+
+// GetEgressRate returns the current egress rate of the endpoint in bits per
+// second. It is 0 if no egress bandwidth limit is configured.
+func (e *Endpoint) GetEgressRate() int64 {
+	return e.egressRate.get()
+}
+
+// egressRateController samples the egress rate of the endpoint while an
+// egress bandwidth limit is configured.
+// Must be called with e.Mutex held.
+func (e *Endpoint) egressRateController() {
+	ctrlName := fmt.Sprintf("egress-rate (%d)", e.ID)
+
+	if !e.Options.IsEnabled(option.EgressBandwidth) {
+		e.controllers.RemoveController(ctrlName)
+		e.egressRate.reset()
+		return
+	}
+
+	id := e.ID
+	e.controllers.UpdateController(ctrlName,
+		controller.ControllerParams{
+			DoFunc: func() error {
+				bytes, err := throttlemap.ReadBytes(id)
+				if err != nil {
+					return err
+				}
+				e.egressRate.sample(bytes, time.Now())
+				return nil
+			},
+			RunInterval: egressRateInterval,
+		},
+	)
+}
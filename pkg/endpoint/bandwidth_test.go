@@ -0,0 +1,51 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *EndpointSuite) TestEgressRate(c *C) {
+	var r egressRate
+	now := time.Now()
+
+	// The first sample only establishes the baseline
+	r.sample(1000, now)
+	c.Assert(r.get(), Equals, int64(0))
+
+	// 125000 bytes in 10 seconds are 100 kbit/s
+	now = now.Add(10 * time.Second)
+	r.sample(126000, now)
+	c.Assert(r.get(), Equals, int64(100000))
+
+	// Samples in quick succession are ignored
+	r.sample(1126000, now.Add(time.Millisecond))
+	c.Assert(r.get(), Equals, int64(100000))
+
+	// A counter which went backwards, e.g. after the map was recreated,
+	// only establishes a new baseline
+	now = now.Add(10 * time.Second)
+	r.sample(500, now)
+	c.Assert(r.get(), Equals, int64(100000))
+	now = now.Add(time.Second)
+	r.sample(1500, now)
+	c.Assert(r.get(), Equals, int64(8000))
+
+	r.reset()
+	c.Assert(r.get(), Equals, int64(0))
+}
@@ -18,14 +18,17 @@ import (
 	"bufio"
 	"context"
 	"crypto/md5"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"hash"
 	"io"
+	"net"
 	"os"
 	"path"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
@@ -41,11 +44,14 @@ import (
 	"github.com/cilium/cilium/pkg/maps/ipcache"
 	"github.com/cilium/cilium/pkg/maps/lxcmap"
 	"github.com/cilium/cilium/pkg/maps/policymap"
+	"github.com/cilium/cilium/pkg/maps/throttlemap"
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/cilium/cilium/pkg/revert"
 	"github.com/cilium/cilium/pkg/version"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/sirupsen/logrus"
 )
 
@@ -85,11 +91,16 @@ func WriteIPCachePrefixes(fw *bufio.Writer, getBPFData getBPFDataCallback) {
 	}
 }
 
+// writeHeaderfile writes the header file of the endpoint into prefix. The
+// header file is replaced atomically and is on stable storage when
+// writeHeaderfile returns, it either contains the previous or the new state of
+// the endpoint even if the node loses power.
 func (e *Endpoint) writeHeaderfile(prefix string, owner Owner) error {
 	headerPath := filepath.Join(prefix, common.CHeaderFileName)
-	f, err := os.Create(headerPath)
+	tmpPath := headerPath + ".tmp"
+	f, err := os.Create(tmpPath)
 	if err != nil {
-		return fmt.Errorf("failed to open file %s for writing: %s", headerPath, err)
+		return fmt.Errorf("failed to open file %s for writing: %s", tmpPath, err)
 
 	}
 	defer f.Close()
@@ -151,11 +162,35 @@ func (e *Endpoint) writeHeaderfile(prefix string, owner Owner) error {
 	}
 	fw.WriteString(" */\n\n")
 
+	e.writeStaticData(fw)
+	e.writeConfig(fw, owner, e)
+
+	if err := fw.Flush(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file %s: %s", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, headerPath); err != nil {
+		return err
+	}
+	return syncDirectory(prefix)
+}
+
+// writeStaticData writes the values specific to the endpoint into the header
+// file. Must be kept in sync with writeTemplateStaticData() and
+// templateOptions().
+func (e *Endpoint) writeStaticData(fw *bufio.Writer) {
 	fw.WriteString(common.FmtDefineAddress("LXC_MAC", e.LXCMAC))
 	fw.WriteString(common.FmtDefineComma("LXC_IP", e.IPv6))
 	if e.IPv4 != nil {
 		fmt.Fprintf(fw, "#define LXC_IPV4 %#x\n", byteorder.HostSliceToNetwork(e.IPv4, reflect.Uint32))
 	}
+	if e.HasAdditionalIPs() {
+		// The datapath validates additional source addresses against
+		// the endpoints map.
+		fw.WriteString("#define ENABLE_MULTI_IP 1\n")
+	}
 	fw.WriteString(common.FmtDefineAddress("NODE_MAC", e.NodeMAC))
 	fmt.Fprintf(fw, "#define LXC_ID %#x\n", e.ID)
 	fmt.Fprintf(fw, "#define LXC_ID_NB %#x\n", byteorder.HostToNetwork(e.ID))
@@ -169,8 +204,14 @@ func (e *Endpoint) writeHeaderfile(prefix string, owner Owner) error {
 	}
 	fmt.Fprintf(fw, "#define POLICY_MAP %s\n", path.Base(e.PolicyMapPathLocked()))
 	fmt.Fprintf(fw, "#define CALLS_MAP %s\n", path.Base(e.CallsMapPathLocked()))
+	fmt.Fprintf(fw, "#define THROTTLE_MAP %s%d\n", throttlemap.MapName, e.ID)
+}
+
+// writeConfig writes the configuration of the endpoint into the header file.
+// The names of the local conntrack maps are derived from ctEndpoint.
+func (e *Endpoint) writeConfig(fw *bufio.Writer, owner Owner, ctEndpoint ctmap.CtEndpoint) {
 	if e.ConntrackLocalLocked() {
-		ctmap.WriteBPFMacros(fw, e)
+		ctmap.WriteBPFMacros(fw, ctEndpoint)
 	} else {
 		ctmap.WriteBPFMacros(fw, nil)
 	}
@@ -182,13 +223,73 @@ func (e *Endpoint) writeHeaderfile(prefix string, owner Owner) error {
 	// Endpoint options
 	fw.WriteString(e.Options.GetFmtList())
 
+	e.writeMirrorConfig(fw, owner)
+
 	if e.L3Policy == nil {
 		WriteIPCachePrefixes(fw, nil)
 	} else {
 		WriteIPCachePrefixes(fw, e.L3Policy.ToBPFData)
 	}
+}
 
-	return fw.Flush()
+// writeMirrorConfig writes the packet mirroring configuration of the endpoint
+// to fw. Must be called with e.Mutex held.
+func (e *Endpoint) writeMirrorConfig(fw io.Writer, owner Owner) {
+	mp := e.MirrorPolicy
+	if mp == nil {
+		return
+	}
+
+	switch c := mp.Collector; {
+	case c.Endpoint != nil:
+		ifIndex, ok := owner.LookupEndpointIfIndex(*c.Endpoint)
+		if !ok {
+			e.logStatusLocked(BPF, Warning, fmt.Sprintf(
+				"No local endpoint found for mirror collector %s", c.Endpoint.LabelSelectorString()))
+			return
+		}
+		fmt.Fprintf(fw, "#define MIRROR_IFINDEX %d\n", ifIndex)
+	case c.VXLAN != nil:
+		if option.Config.Tunnel != option.TunnelVXLAN {
+			e.logStatusLocked(BPF, Warning, fmt.Sprintf(
+				"Mirroring to VXLAN collector %s requires tunnel mode %s", c.VXLAN.IP, option.TunnelVXLAN))
+			return
+		}
+		ip := net.ParseIP(c.VXLAN.IP).To4()
+		if ip == nil {
+			return
+		}
+		fmt.Fprintf(fw, "#define MIRROR_VXLAN_IPV4 %#x\n", binary.BigEndian.Uint32(ip))
+		fmt.Fprintf(fw, "#define MIRROR_VXLAN_VNI %d\n", c.VXLAN.VNI)
+	default:
+		return
+	}
+
+	if len(mp.Ports) > 0 {
+		fmt.Fprintf(fw, "#define MIRROR_PORT_MATCH(proto, port) (%s)\n", formatMirrorPortMatch(mp.Ports))
+	}
+	if mp.SamplingRate > 1 {
+		fmt.Fprintf(fw, "#define MIRROR_SAMPLING %d\n", mp.SamplingRate)
+	}
+}
+
+// formatMirrorPortMatch returns a C expression matching the given ports on
+// the 'proto' and 'port' arguments of MIRROR_PORT_MATCH.
+func formatMirrorPortMatch(ports []api.PortProtocol) string {
+	exprs := make([]string, 0, len(ports))
+	for _, p := range ports {
+		expr := fmt.Sprintf("(port) == bpf_htons(%s)", p.Port)
+		switch p.Protocol {
+		case api.ProtoTCP:
+			expr = "((proto) == IPPROTO_TCP && " + expr + ")"
+		case api.ProtoUDP:
+			expr = "((proto) == IPPROTO_UDP && " + expr + ")"
+		default:
+			expr = "(" + expr + ")"
+		}
+		exprs = append(exprs, expr)
+	}
+	return strings.Join(exprs, " || ")
 }
 
 // hashEndpointHeaderFiles returns the MD5 hash of any header files that are
@@ -260,6 +361,7 @@ func (e *Endpoint) addNewRedirectsFromMap(owner Owner, m policy.L4PolicyMap, des
 	var finalizeList revert.FinalizeList
 	var revertStack revert.RevertStack
 	var updatedStats []*models.ProxyStatistics
+	drainedStats := make(map[uint16]*models.ProxyStatistics)
 	insertedDesiredMapState := make(map[policymap.PolicyKey]struct{})
 	updatedDesiredMapState := make(PolicyMapState)
 
@@ -285,7 +387,8 @@ func (e *Endpoint) addNewRedirectsFromMap(owner Owner, m policy.L4PolicyMap, des
 				if e.realizedRedirects == nil {
 					e.realizedRedirects = make(map[string]uint16)
 				}
-				if _, found := e.realizedRedirects[proxyID]; !found {
+				oldRedirectPort, found := e.realizedRedirects[proxyID]
+				if !found {
 					revertStack.Push(func() error {
 						delete(e.realizedRedirects, proxyID)
 						return nil
@@ -296,10 +399,20 @@ func (e *Endpoint) addNewRedirectsFromMap(owner Owner, m policy.L4PolicyMap, des
 				desiredRedirects[proxyID] = true
 
 				// Update the endpoint API model to report that Cilium manages a
-				// redirect for that port.
+				// redirect for that port. A redirect replaced with a different
+				// port, e.g. because the L7 parser changed, is draining.
 				e.proxyStatisticsMutex.Lock()
+				if found && oldRedirectPort != redirectPort {
+					if stats := e.drainProxyStatisticsLocked(oldRedirectPort); stats != nil {
+						drainedStats[oldRedirectPort] = stats
+					}
+				}
 				proxyStats := e.getProxyStatisticsLocked(string(l4.L7Parser), uint16(l4.Port), l4.Ingress)
 				proxyStats.AllocatedProxyPort = int64(redirectPort)
+				if proxyStats.DrainingProxyPort == int64(redirectPort) {
+					proxyStats.DrainingProxyPort = 0
+					proxyStats.DrainDeadline = strfmt.DateTime{}
+				}
 				e.proxyStatisticsMutex.Unlock()
 
 				updatedStats = append(updatedStats, proxyStats)
@@ -332,6 +445,9 @@ func (e *Endpoint) addNewRedirectsFromMap(owner Owner, m policy.L4PolicyMap, des
 		for _, stats := range updatedStats {
 			stats.AllocatedProxyPort = 0
 		}
+		for redirectPort, stats := range drainedStats {
+			undrainProxyStatisticsLocked(stats, redirectPort)
+		}
 		e.proxyStatisticsMutex.Unlock()
 
 		// Restore the desired policy map state.
@@ -418,20 +534,10 @@ func (e *Endpoint) removeOldRedirects(owner Owner, desiredRedirects map[string]b
 		// Update the endpoint API model to report that no redirect is
 		// active or known for that port anymore. We never delete stats
 		// until an endpoint is deleted, so we only set the redirect port
-		// to 0.
-		//
-		// We don't know the L7 protocol of the redirect, so we can't just
-		// build a ProxyStatistics and lookup e.proxyStatistics by key.
-		// We have to loop to find which entry has the same redirect port.
-		// Looping is acceptable since there should be only a few redirects
-		// for each endpoint.
+		// to 0 and report the redirect as draining.
 		e.proxyStatisticsMutex.Lock()
-		for _, stats := range e.proxyStatistics {
-			if stats.AllocatedProxyPort == int64(redirectPort) {
-				updatedStats[redirectPort] = stats
-				stats.AllocatedProxyPort = 0
-				break
-			}
+		if stats := e.drainProxyStatisticsLocked(redirectPort); stats != nil {
+			updatedStats[redirectPort] = stats
 		}
 		e.proxyStatisticsMutex.Unlock()
 	}
@@ -443,7 +549,7 @@ func (e *Endpoint) removeOldRedirects(owner Owner, desiredRedirects map[string]b
 			// Restore the proxy stats.
 			e.proxyStatisticsMutex.Lock()
 			for redirectPort, stats := range updatedStats {
-				stats.AllocatedProxyPort = int64(redirectPort)
+				undrainProxyStatisticsLocked(stats, redirectPort)
 			}
 			e.proxyStatisticsMutex.Unlock()
 
@@ -617,6 +723,7 @@ func (e *Endpoint) regenerateBPF(owner Owner, currentDir, nextDir string, regenC
 		err, networkPolicyRevertFunc = e.updateNetworkPolicy(owner, proxyWaitGroup)
 		stats.proxyPolicyCalculation.End(err == nil)
 		if err != nil {
+			e.logStatusLocked(Proxy, Failure, "Error while updating proxy network policy: "+err.Error())
 			e.Unlock()
 			return 0, compilationExecuted, err
 		}
@@ -634,6 +741,7 @@ func (e *Endpoint) regenerateBPF(owner Owner, currentDir, nextDir string, regenC
 		desiredRedirects, err, finalizeFunc, revertFunc = e.addNewRedirects(owner, e.DesiredL4Policy, proxyWaitGroup)
 		if err != nil {
 			stats.proxyConfiguration.End(false)
+			e.logStatusLocked(Proxy, Failure, "Error while adding proxy redirects: "+err.Error())
 			e.Unlock()
 			return 0, compilationExecuted, err
 		}
@@ -652,7 +760,10 @@ func (e *Endpoint) regenerateBPF(owner Owner, currentDir, nextDir string, regenC
 
 	// Generate header file specific to this endpoint for use in compiling
 	// BPF programs for this endpoint.
-	if err = e.writeHeaderfile(nextDir, owner); err != nil {
+	stats.headerWrite.Start()
+	err = e.writeHeaderfile(nextDir, owner)
+	stats.headerWrite.End(err == nil)
+	if err != nil {
 		stats.prepareBuild.End(false)
 		e.Unlock()
 		return 0, compilationExecuted, fmt.Errorf("unable to write header file: %s", err)
@@ -686,6 +797,9 @@ func (e *Endpoint) regenerateBPF(owner Owner, currentDir, nextDir string, regenC
 		err = fmt.Errorf("Unable to cache endpoint information")
 		return 0, compilationExecuted, err
 	}
+	if bpfHeaderfilesChanged && templatesEnabled() {
+		epInfoCache.template = e.createTemplateInfo(owner)
+	}
 
 	// TODO: In Cilium v1.4 or later cycle, remove this.
 	os.RemoveAll(e.IPv6EgressMapPathLocked())
@@ -693,11 +807,17 @@ func (e *Endpoint) regenerateBPF(owner Owner, currentDir, nextDir string, regenC
 	os.RemoveAll(e.IPv6IngressMapPathLocked())
 	os.RemoveAll(e.IPv4IngressMapPathLocked())
 
+	// If the endpoint switched between the global and its local conntrack
+	// maps, the existing entries must be migrated to the new maps.
+	ctMigration := e.getCTMigrationLocked()
+	ctIPs := e.getIPsLocked()
+
 	e.Unlock()
 
 	// Wait for connection tracking cleaning to complete
 	stats.waitingForCTClean.Start()
 	<-ctCleaned
+	e.migrateConntrack(ctMigration, ctIPs)
 	stats.waitingForCTClean.End(true)
 
 	e.getLogger().WithField("bpfHeaderfilesChanged", bpfHeaderfilesChanged).Debug("Preparing to compile BPF")
@@ -710,7 +830,7 @@ func (e *Endpoint) regenerateBPF(owner Owner, currentDir, nextDir string, regenC
 		ctx, cancel := context.WithTimeout(context.Background(), ExecTimeout)
 		if bpfHeaderfilesChanged {
 			stats.bpfCompilation.Start()
-			err = loader.CompileAndLoad(ctx, epInfoCache)
+			err = e.compileAndLoad(ctx, epInfoCache)
 			stats.bpfCompilation.End(err == nil)
 			e.getLogger().WithError(err).
 				WithField(logfields.BPFCompilationTime, stats.bpfCompilation.Total().String()).
@@ -727,6 +847,7 @@ func (e *Endpoint) regenerateBPF(owner Owner, currentDir, nextDir string, regenC
 			return epInfoCache.revision, compilationExecuted, err
 		}
 		e.bpfHeaderfileHash = bpfHeaderfilesHash
+		e.finalizeConntrackMigration(ctMigration, ctIPs)
 	} else {
 		e.getLogger().WithField(logfields.BPFHeaderfileHash, bpfHeaderfilesHash).
 			Debug("BPF header file unchanged, skipping BPF compilation and installation")
@@ -736,7 +857,9 @@ func (e *Endpoint) regenerateBPF(owner Owner, currentDir, nextDir string, regenC
 	err = e.WaitForProxyCompletions(proxyWaitGroup)
 	stats.proxyWaitForAck.End(err == nil)
 	if err != nil {
-		return 0, compilationExecuted, fmt.Errorf("Error while configuring proxy redirects: %s", err)
+		err = fmt.Errorf("Error while configuring proxy redirects: %s", err)
+		e.LogStatus(Proxy, Failure, err.Error())
+		return 0, compilationExecuted, err
 	}
 
 	stats.waitingForLock.Start()
@@ -749,6 +872,10 @@ func (e *Endpoint) regenerateBPF(owner Owner, currentDir, nextDir string, regenC
 
 	e.ctCleaned = true
 
+	if e.Status.getStatusCode(Proxy) != OK {
+		e.LogStatusOKLocked(Proxy, "Successfully configured proxy redirects")
+	}
+
 	// Synchronously try to update PolicyMap for this endpoint. If any
 	// part of updating the PolicyMap fails, bail out and do not generate
 	// BPF. Unfortunately, this means that the map will be in an inconsistent
@@ -795,6 +922,11 @@ func (e *Endpoint) DeleteMapsLocked() []error {
 		errors = append(errors, fmt.Errorf("unable to remove calls map file %s: %s", e.CallsMapPathLocked(), err))
 	}
 
+	// Remove egress bandwidth limiting BPF map
+	if err := os.RemoveAll(throttlemap.Path(e.ID)); err != nil {
+		errors = append(errors, fmt.Errorf("unable to remove throttle map file %s: %s", throttlemap.Path(e.ID), err))
+	}
+
 	if e.ConntrackLocalLocked() {
 		// Remove local connection tracking maps
 		for _, m := range ctmap.LocalMaps(e, !option.Config.IPv4Disabled, true) {
@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 
 	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/policy/api"
 
 	. "gopkg.in/check.v1"
 )
@@ -97,3 +98,13 @@ ignored
 
 	c.Assert(hashToString3, Not(Equals), hashToString4)
 }
+
+func (s *EndpointSuite) TestFormatMirrorPortMatch(c *C) {
+	c.Assert(formatMirrorPortMatch([]api.PortProtocol{
+		{Port: "80", Protocol: api.ProtoTCP},
+		{Port: "53", Protocol: api.ProtoUDP},
+		{Port: "8080", Protocol: api.ProtoAny},
+	}), Equals, "((proto) == IPPROTO_TCP && (port) == bpf_htons(80)) || "+
+		"((proto) == IPPROTO_UDP && (port) == bpf_htons(53)) || "+
+		"((port) == bpf_htons(8080))")
+}
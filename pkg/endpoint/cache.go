@@ -39,6 +39,10 @@ type epInfoCache struct {
 	id       string
 	ifName   string
 	endpoint *Endpoint // Used to get the endpoint's logger.
+
+	// template is set if the BPF program of the endpoint is to be
+	// instantiated from a template.
+	template *templateInfo
 }
 
 // Must be called when endpoint is still locked.
@@ -0,0 +1,143 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ctMigration describes a pending migration of conntrack entries between the
+// global conntrack maps and the local conntrack maps of an endpoint.
+type ctMigration int
+
+const (
+	// ctMigrationNone means that no migration is required.
+	ctMigrationNone ctMigration = iota
+
+	// ctMigrationToLocal means that the endpoint switched from the global
+	// conntrack maps to its local conntrack maps.
+	ctMigrationToLocal
+
+	// ctMigrationToGlobal means that the endpoint switched from its local
+	// conntrack maps to the global conntrack maps.
+	ctMigrationToGlobal
+)
+
+// getCTMigrationLocked returns the conntrack migration required to apply the
+// current value of the ConntrackLocal option to the datapath. The currently
+// realized mode is derived from the presence of the local conntrack maps, as
+// these are removed when an endpoint switches to the global maps.
+//
+// Entries are never migrated during the first regeneration of an endpoint,
+// as the conntrack table is scrubbed from the endpoint's IPs at that point.
+//
+// The endpoint lock must be held.
+func (e *Endpoint) getCTMigrationLocked() ctMigration {
+	if !e.ctCleaned || option.Config.DryMode {
+		return ctMigrationNone
+	}
+
+	localExists := ctmap.Exists(e, !option.Config.IPv4Disabled, true)
+	switch {
+	case e.ConntrackLocalLocked() && !localExists:
+		return ctMigrationToLocal
+	case !e.ConntrackLocalLocked() && localExists:
+		return ctMigrationToGlobal
+	}
+	return ctMigrationNone
+}
+
+// getIPsLocked returns the IPs of the endpoint in the form used by the
+// conntrack GC filters.
+//
+// The endpoint lock must be held.
+func (e *Endpoint) getIPsLocked() map[string]struct{} {
+	ips := map[string]struct{}{
+		e.IPv4.String(): {},
+		e.IPv6.String(): {},
+	}
+	for _, ip := range e.AdditionalIPv4 {
+		ips[ip.String()] = struct{}{}
+	}
+	for _, ip := range e.AdditionalIPv6 {
+		ips[ip.String()] = struct{}{}
+	}
+	return ips
+}
+
+// migrateConntrack copies the conntrack entries of the endpoint with the
+// given IPs into the conntrack maps that the endpoint is about to use. It
+// must be called before the BPF program using the new maps is loaded, so that
+// existing connections are not interrupted by the switch.
+//
+// The endpoint lock must NOT be held.
+func (e *Endpoint) migrateConntrack(migration ctMigration, ips map[string]struct{}) {
+	if migration == ctMigrationNone {
+		return
+	}
+
+	toLocal := migration == ctMigrationToLocal
+	migrated, err := ctmap.MigrateEntries(e, ips, toLocal, !option.Config.IPv4Disabled, true)
+	scopedLog := e.getLogger().WithFields(logrus.Fields{
+		"toLocal": toLocal,
+		"count":   migrated,
+	})
+	if err != nil {
+		scopedLog.WithError(err).Warn("Unable to migrate all conntrack entries, existing connections may be interrupted")
+		e.LogStatus(BPF, Warning, fmt.Sprintf("Unable to migrate conntrack entries: %s", err))
+		return
+	}
+	scopedLog.Info("Migrated conntrack entries")
+}
+
+// finalizeConntrackMigration removes the migrated conntrack entries from the
+// maps which are no longer used by the endpoint. It must be called after the
+// BPF program using the new maps has been loaded.
+//
+// The endpoint lock must NOT be held.
+func (e *Endpoint) finalizeConntrackMigration(migration ctMigration, ips map[string]struct{}) {
+	switch migration {
+	case ctMigrationToLocal:
+		for _, m := range ctmap.GlobalMaps(!option.Config.IPv4Disabled, true) {
+			path, err := m.Path()
+			if err == nil {
+				err = m.Open()
+			}
+			if err != nil {
+				e.getLogger().WithError(err).WithField(logfields.Path, path).Warn("Unable to open map")
+				continue
+			}
+			ctmap.GC(m, &ctmap.GCFilter{MatchIPs: ips})
+			m.Close()
+		}
+	case ctMigrationToGlobal:
+		for _, m := range ctmap.LocalMaps(e, !option.Config.IPv4Disabled, true) {
+			path, err := m.Path()
+			if err == nil {
+				err = os.RemoveAll(path)
+			}
+			if err != nil {
+				e.getLogger().WithError(err).WithField(logfields.Path, path).Warn("Unable to remove local CT map")
+			}
+		}
+	}
+}
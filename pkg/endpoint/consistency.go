@@ -0,0 +1,126 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+)
+
+// diffPolicyMap compares the desired policy map state with the entries of a
+// policy map. Returns the drift and the keys of all missing and mismatching
+// entries. Stale entries are removed by every synchronization of the map.
+func diffPolicyMap(desired PolicyMapState, actual policymap.PolicyEntriesDump) (bpf.MapDrift, []policymap.PolicyKey) {
+	var (
+		drift   bpf.MapDrift
+		drifted []policymap.PolicyKey
+	)
+
+	present := make(PolicyMapState, len(actual))
+	for _, entry := range actual {
+		key := entry.Key.ToHost()
+		present[key] = PolicyMapStateEntry{
+			ProxyPort: byteorder.NetworkToHost(entry.ProxyPort).(uint16),
+		}
+		if _, ok := desired[key]; !ok {
+			drift.Stale++
+		}
+	}
+
+	for key, entry := range desired {
+		actualEntry, ok := present[key]
+		switch {
+		case !ok:
+			drift.Missing++
+		case actualEntry != entry:
+			drift.Mismatch++
+		default:
+			continue
+		}
+		drifted = append(drifted, key)
+	}
+
+	return drift, drifted
+}
+
+// CheckPolicyMap compares the policy map of the endpoint with its desired
+// state and reports any difference in the BPF status of the endpoint. If
+// repair is true, the policy map is synchronized with the desired state.
+// Endpoints which are not ready are skipped as their policy map is about to
+// change.
+func (e *Endpoint) CheckPolicyMap(repair bool) (bpf.MapDrift, error) {
+	if err := e.LockAlive(); err != nil {
+		return bpf.MapDrift{}, nil
+	}
+	defer e.Unlock()
+
+	if e.PolicyMap == nil || e.GetStateLocked() != StateReady {
+		return bpf.MapDrift{}, nil
+	}
+
+	contents, err := e.PolicyMap.DumpToSlice()
+	if err != nil {
+		return bpf.MapDrift{}, err
+	}
+
+	drift, drifted := diffPolicyMap(e.desiredMapState, contents)
+	switch {
+	case drift.Total() == 0:
+		if e.policyMapDrift {
+			e.policyMapDrift = false
+			e.LogStatusOKLocked(BPF, "Policy map matches the desired state")
+		}
+
+	case repair:
+		// Entries missing from the realized state are reinserted
+		for _, key := range drifted {
+			delete(e.realizedMapState, key)
+		}
+		if err := e.syncPolicyMap(); err != nil {
+			e.policyMapDrift = true
+			e.logStatusLocked(BPF, Warning, fmt.Sprintf("Unable to repair policy map (%s): %s", drift, err))
+			return drift, err
+		}
+		e.policyMapDrift = false
+		e.LogStatusOKLocked(BPF, fmt.Sprintf("Repaired policy map: %s", drift))
+
+	default:
+		e.policyMapDrift = true
+		e.logStatusLocked(BPF, Warning, fmt.Sprintf("Policy map differs from the desired state: %s", drift))
+	}
+
+	return drift, nil
+}
+
+// SamplePolicyMapPressure returns the fill ratio of the policy map of the
+// endpoint along with the ratio returned by the previous call. ok is false
+// if the endpoint has no policy map.
+func (e *Endpoint) SamplePolicyMapPressure() (ratio, previous float64, ok bool) {
+	if err := e.LockAlive(); err != nil {
+		return 0, 0, false
+	}
+	defer e.Unlock()
+
+	if e.PolicyMap == nil {
+		return 0, 0, false
+	}
+
+	ratio = float64(e.PolicyMap.CountEntries()) / float64(policymap.MaxEntries)
+	previous, e.policyMapPressure = e.policyMapPressure, ratio
+	return ratio, previous, true
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+
+	. "gopkg.in/check.v1"
+)
+
+func policyEntryDump(identity uint32, port, proxyPort uint16) policymap.PolicyEntryDump {
+	return policymap.PolicyEntryDump{
+		Key: policymap.PolicyKey{
+			Identity: identity,
+			DestPort: byteorder.HostToNetwork(port).(uint16),
+			Nexthdr:  6,
+		},
+		PolicyEntry: policymap.PolicyEntry{
+			ProxyPort: byteorder.HostToNetwork(proxyPort).(uint16),
+		},
+	}
+}
+
+func (s *EndpointSuite) TestDiffPolicyMap(c *C) {
+	key := func(identity uint32, port uint16) policymap.PolicyKey {
+		return policymap.PolicyKey{Identity: identity, DestPort: port, Nexthdr: 6}
+	}
+
+	desired := PolicyMapState{
+		key(1, 80):  {},
+		key(2, 80):  {ProxyPort: 15000},
+		key(3, 443): {},
+	}
+	actual := policymap.PolicyEntriesDump{
+		policyEntryDump(1, 80, 0),
+		policyEntryDump(2, 80, 16000),
+		policyEntryDump(4, 53, 0),
+	}
+
+	drift, drifted := diffPolicyMap(desired, actual)
+	c.Assert(drift.Missing, Equals, 1)
+	c.Assert(drift.Mismatch, Equals, 1)
+	c.Assert(drift.Stale, Equals, 1)
+	c.Assert(drifted, HasLen, 2)
+
+	keys := map[policymap.PolicyKey]bool{}
+	for _, k := range drifted {
+		keys[k] = true
+	}
+	c.Assert(keys, DeepEquals, map[policymap.PolicyKey]bool{
+		key(2, 80):  true,
+		key(3, 443): true,
+	})
+
+	drift, drifted = diffPolicyMap(PolicyMapState{key(1, 80): {}}, actual[:1])
+	c.Assert(drift.Total(), Equals, 0)
+	c.Assert(drifted, HasLen, 0)
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/sirupsen/logrus"
+)
+
+// SetDebug enables or disables debugging of the endpoint at runtime, i.e.
+// the debug logs of the endpoint's code paths in the agent and the debug
+// events of its datapath. If duration is not 0, debugging is disabled again
+// once the duration has expired. The expiration is not preserved across
+// restarts of the agent.
+func (e *Endpoint) SetDebug(owner Owner, enabled bool, duration time.Duration) error {
+	if err := e.LockAlive(); err != nil {
+		return err
+	}
+
+	e.stopDebugTimerLocked()
+
+	if enabled && duration != 0 {
+		expiration := time.Now().Add(duration)
+		e.debugExpiration = expiration
+		e.debugTimer = time.AfterFunc(duration, func() {
+			e.expireDebug(owner, expiration)
+		})
+	}
+
+	e.getLogger().WithFields(logrus.Fields{
+		"enabled":  enabled,
+		"duration": duration,
+	}).Info("Debugging of endpoint updated via API")
+
+	return e.applyDebugLocked(owner, enabled)
+}
+
+// expireDebug disables debugging of the endpoint if it was enabled with the
+// given expiration and has not been updated since.
+func (e *Endpoint) expireDebug(owner Owner, expiration time.Time) {
+	if err := e.LockAlive(); err != nil {
+		return
+	}
+
+	if !e.debugExpiration.Equal(expiration) {
+		e.Unlock()
+		return
+	}
+	e.debugTimer = nil
+	e.debugExpiration = time.Time{}
+
+	e.getLogger().Info("Debugging of endpoint expired")
+
+	if err := e.applyDebugLocked(owner, false); err != nil {
+		e.getLogger().WithError(err).Warning("Unable to disable debugging of endpoint")
+	}
+}
+
+// applyDebugLocked sets the debug option of the endpoint and regenerates the
+// endpoint if the option has changed.
+// Must be called with e.Mutex held, which is released by this function.
+func (e *Endpoint) applyDebugLocked(owner Owner, enabled bool) error {
+	value, msg := option.OptionDisabled, "Debugging disabled"
+	if enabled {
+		value, msg = option.OptionEnabled, "Debugging enabled"
+	}
+
+	if !e.updateAndOverrideEndpointOptions(option.OptionMap{option.Debug: value}) {
+		e.Unlock()
+		return nil
+	}
+	e.logStatusLocked(Other, OK, msg)
+
+	_, err := e.regenerateWhenReady(owner, "debugging of endpoint updated via API")
+	return err
+}
+
+// stopDebugTimerLocked cancels the expiration of debugging of the endpoint.
+// Must be called with e.Mutex held.
+func (e *Endpoint) stopDebugTimerLocked() {
+	if e.debugTimer != nil {
+		e.debugTimer.Stop()
+		e.debugTimer = nil
+	}
+	e.debugExpiration = time.Time{}
+}
+
+// getDebugModel returns the debugging state of the endpoint, nil if
+// debugging is disabled.
+// Must be called with e.Mutex held.
+func (e *Endpoint) getDebugModel() *models.EndpointDebug {
+	if e.Options == nil || !e.Options.IsEnabled(option.Debug) {
+		return nil
+	}
+
+	// Only the absolute expiration is reported, the remaining duration
+	// would change the model on every call.
+	mdl := &models.EndpointDebug{Enabled: true}
+	if !e.debugExpiration.IsZero() {
+		mdl.Expiration = strfmt.DateTime(e.debugExpiration)
+	}
+	return mdl
+}
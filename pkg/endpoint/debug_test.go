@@ -0,0 +1,51 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/go-openapi/strfmt"
+	. "gopkg.in/check.v1"
+)
+
+func (s *EndpointSuite) TestDebugModel(c *C) {
+	e := NewEndpointWithState(42, StateReady)
+	e.Options = option.NewIntOptions(&EndpointMutableOptionLibrary)
+	c.Assert(e.getDebugModel(), IsNil)
+
+	e.Options.SetBool(option.Debug, true)
+	mdl := e.getDebugModel()
+	c.Assert(mdl, Not(IsNil))
+	c.Assert(mdl.Enabled, Equals, true)
+	c.Assert(time.Time(mdl.Expiration).IsZero(), Equals, true)
+
+	expiration := time.Now().Add(time.Minute)
+	e.debugExpiration = expiration
+	mdl = e.getDebugModel()
+	c.Assert(mdl.Expiration, Equals, strfmt.DateTime(expiration))
+	c.Assert(mdl.Duration, Equals, strfmt.Duration(0))
+
+	// An expiration which has been replaced in the meantime is ignored
+	e.expireDebug(nil, expiration.Add(-time.Second))
+	c.Assert(e.Options.IsEnabled(option.Debug), Equals, true)
+	c.Assert(e.debugExpiration, Equals, expiration)
+
+	e.stopDebugTimerLocked()
+	c.Assert(e.debugExpiration.IsZero(), Equals, true)
+	c.Assert(e.getDebugModel().Expiration, Equals, strfmt.DateTime{})
+}
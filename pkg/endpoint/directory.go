@@ -100,6 +100,13 @@ func (e *Endpoint) synchronizeDirectories(origDir string, compilationExecuted bo
 			return fmt.Errorf("restored original endpoint directory, atomic directory move failed: %s", err)
 		}
 
+		// Keep the state of the previous build to fall back to it on
+		// restore if the new state turns out to be corrupt
+		prevHeader := filepath.Join(backupDir, common.CHeaderFileName)
+		if err := os.Rename(prevHeader, filepath.Join(origDir, common.CHeaderBackupFileName)); err != nil {
+			scopedLog.WithError(err).Debug("unable to keep the previous endpoint state")
+		}
+
 		// If the compilation was skipped then we need to copy the old
 		// bpf objects into the new directory
 		if !compilationExecuted {
@@ -122,6 +129,16 @@ func (e *Endpoint) synchronizeDirectories(origDir string, compilationExecuted bo
 		if err := os.Rename(tmpDir, origDir); err != nil {
 			return fmt.Errorf("atomic endpoint directory move failed: %s", err)
 		}
+
+		// The endpoint may have been restored from the backup directory
+		// of an interrupted synchronization which is no longer needed.
+		e.removeDirectory(e.backupDirectoryPath())
+	}
+
+	// Make sure the renames are on stable storage before the backup
+	// directory is removed
+	if err := syncDirectory(filepath.Dir(origDir)); err != nil {
+		scopedLog.WithError(err).Warn("unable to sync the state directory")
 	}
 
 	// The build succeeded and is in place, any eventual existing failure
@@ -131,6 +148,20 @@ func (e *Endpoint) synchronizeDirectories(origDir string, compilationExecuted bo
 	return nil
 }
 
+// syncDirectory flushes the entries of the directory at path to stable
+// storage so that files renamed into the directory survive a power loss.
+func syncDirectory(path string) error {
+	d, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync directory %s: %s", path, err)
+	}
+	return nil
+}
+
 func (e *Endpoint) removeDirectory(path string) error {
 	e.getLogger().WithField("directory", path).Debug("removing directory")
 	return os.RemoveAll(path)
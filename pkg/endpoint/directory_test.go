@@ -0,0 +1,65 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/common"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *EndpointSuite) TestSynchronizeDirectories(c *C) {
+	oldDir, err := os.Getwd()
+	c.Assert(err, IsNil)
+	stateDir := c.MkDir()
+	c.Assert(os.Chdir(stateDir), IsNil)
+	defer os.Chdir(oldDir)
+
+	e := NewEndpointWithState(42, StateReady)
+	writeHeader := func(dir, content string) {
+		c.Assert(os.MkdirAll(dir, 0755), IsNil)
+		path := filepath.Join(dir, common.CHeaderFileName)
+		c.Assert(ioutil.WriteFile(path, []byte(content), 0644), IsNil)
+	}
+	readFile := func(path string) string {
+		data, err := ioutil.ReadFile(path)
+		c.Assert(err, IsNil)
+		return string(data)
+	}
+
+	// Leftover backup directory of an interrupted synchronization
+	writeHeader(e.backupDirectoryPath(), "stale")
+	writeHeader(e.NextDirectoryPath(), "first")
+	c.Assert(e.synchronizeDirectories(e.DirectoryPath(), true), IsNil)
+	c.Assert(readFile(filepath.Join(e.DirectoryPath(), common.CHeaderFileName)), Equals, "first")
+	_, err = os.Stat(e.backupDirectoryPath())
+	c.Assert(os.IsNotExist(err), Equals, true)
+
+	// The header of the previous build is kept next to the new one
+	writeHeader(e.NextDirectoryPath(), "second")
+	c.Assert(e.synchronizeDirectories(e.DirectoryPath(), true), IsNil)
+	c.Assert(readFile(filepath.Join(e.DirectoryPath(), common.CHeaderFileName)), Equals, "second")
+	c.Assert(readFile(filepath.Join(e.DirectoryPath(), common.CHeaderBackupFileName)), Equals, "first")
+	_, err = os.Stat(e.backupDirectoryPath())
+	c.Assert(os.IsNotExist(err), Equals, true)
+
+	dirFiles, err := ioutil.ReadDir(stateDir)
+	c.Assert(err, IsNil)
+	c.Assert(FilterEPDir(dirFiles), DeepEquals, []string{"42"})
+}
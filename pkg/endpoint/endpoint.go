@@ -34,6 +34,7 @@ import (
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common/addressing"
 	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
 	"github.com/cilium/cilium/pkg/completion"
 	"github.com/cilium/cilium/pkg/controller"
 	"github.com/cilium/cilium/pkg/defaults"
@@ -57,6 +58,7 @@ import (
 	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/proxy/accesslog"
+	"github.com/cilium/cilium/pkg/proxy/logger"
 	"github.com/cilium/cilium/pkg/u8proto"
 	"github.com/cilium/cilium/pkg/versioncheck"
 
@@ -64,6 +66,7 @@ import (
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 
+	"github.com/go-openapi/strfmt"
 	"github.com/sirupsen/logrus"
 )
 
@@ -318,6 +321,32 @@ type Endpoint struct {
 	// libnetwork
 	DockerEndpointID string
 
+	// ContainerRuntime is the name of the container runtime managing the
+	// container of the endpoint
+	ContainerRuntime string `json:"containerRuntime,omitempty"`
+
+	// K8sPodName is the name of the pod if the endpoint represents a
+	// Kubernetes pod
+	K8sPodName string `json:"k8sPodName,omitempty"`
+
+	// K8sNamespace is the namespace of the pod if the endpoint represents
+	// a Kubernetes pod
+	K8sNamespace string `json:"k8sNamespace,omitempty"`
+
+	// Quarantined is true if all traffic of the endpoint is denied
+	// regardless of the rules in the policy repository
+	Quarantined bool `json:"quarantined,omitempty"`
+
+	// QuarantineReason is the reason given when quarantining the endpoint
+	QuarantineReason string `json:"quarantineReason,omitempty"`
+
+	// debugExpiration is the time at which debugging enabled via SetDebug
+	// is disabled again, zero if it does not expire
+	debugExpiration time.Time
+
+	// debugTimer disables debugging at debugExpiration
+	debugTimer *time.Timer
+
 	// IfName is the name of the host facing interface (veth pair) which
 	// connects into the endpoint
 	IfName string
@@ -345,6 +374,14 @@ type Endpoint struct {
 	// IPv4 is the IPv4 address of the endpoint
 	IPv4 addressing.CiliumIPv4
 
+	// AdditionalIPv6 are the IPv6 addresses of the endpoint in addition
+	// to IPv6, e.g. of multi-homed pods
+	AdditionalIPv6 []addressing.CiliumIPv6 `json:"additionalIPv6,omitempty"`
+
+	// AdditionalIPv4 are the IPv4 addresses of the endpoint in addition
+	// to IPv4, e.g. of multi-homed pods
+	AdditionalIPv4 []addressing.CiliumIPv4 `json:"additionalIPv4,omitempty"`
+
 	// NodeMAC is the MAC of the node (agent). The MAC is different for every endpoint.
 	NodeMAC mac.MAC
 
@@ -377,6 +414,10 @@ type Endpoint struct {
 	// CIDRPolicy is the CIDR based policy configuration of the endpoint.
 	L3Policy *policy.CIDRPolicy `json:"-"`
 
+	// MirrorPolicy is the packet mirroring configuration of the endpoint,
+	// nil if no packets are mirrored.
+	MirrorPolicy *policy.MirrorPolicy `json:"-"`
+
 	// Options determine the datapath configuration of the endpoint.
 	Options *option.IntOptions
 
@@ -390,9 +431,6 @@ type Endpoint struct {
 	// compiled and installed.
 	bpfHeaderfileHash string
 
-	k8sPodName   string
-	k8sNamespace string
-
 	// policyRevision is the policy revision this endpoint is currently on
 	// to modify this field please use endpoint.setPolicyRevision instead
 	policyRevision uint64
@@ -406,7 +444,7 @@ type Endpoint struct {
 	proxyPolicyRevision uint64
 
 	// proxyStatisticsMutex is the mutex that must be held to read or write
-	// proxyStatistics.
+	// proxyStatistics and proxyRedirectHealth.
 	proxyStatisticsMutex lock.RWMutex
 
 	// proxyStatistics contains statistics of proxy redirects.
@@ -415,6 +453,19 @@ type Endpoint struct {
 	// You must hold Endpoint.proxyStatisticsMutex to read or write it.
 	proxyStatistics map[models.ProxyStatistics]*models.ProxyStatistics
 
+	// proxyRedirectHealth contains the health of the proxy redirects of
+	// the endpoint as reported by the proxy, indexed by redirect ID.
+	// You must hold Endpoint.proxyStatisticsMutex to read or write it.
+	proxyRedirectHealth map[string]*models.ProxyRedirectHealth
+
+	// l7Statistics contains the statistics of the L7 traffic of the
+	// endpoint per port and L7 parser. It has its own lock.
+	l7Statistics logger.L7Statistics
+
+	// egressRate is the rate at which packets pass the egress bandwidth
+	// limit of the endpoint. It has its own lock.
+	egressRate egressRate
+
 	// nextPolicyRevision is the policy revision that the endpoint has
 	// updated to and that will become effective with the next regenerate
 	nextPolicyRevision uint64
@@ -459,6 +510,14 @@ type Endpoint struct {
 	// All fields within the PolicyKey and the proxy port must be in host byte-order.
 	desiredMapState PolicyMapState
 
+	// policyMapDrift is true while a difference between the policy map and
+	// desiredMapState is reported in the BPF status of the endpoint
+	policyMapDrift bool
+
+	// policyMapPressure is the fill ratio of the policy map at the time it
+	// was last sampled
+	policyMapPressure float64
+
 	// ctCleaned indicates whether the conntrack table has already been
 	// cleaned when this endpoint was first created
 	ctCleaned bool
@@ -601,7 +660,8 @@ func (e *Endpoint) RunK8sCiliumEndpointSync() {
 	// NOTE: The controller functions do NOT hold the endpoint locks
 	e.controllers.UpdateController(controllerName,
 		controller.ControllerParams{
-			RunInterval: 10 * time.Second,
+			RunInterval:           10 * time.Second,
+			ErrorRetryExponential: true,
 			DoFunc: func() (err error) {
 				// Update logger as scopeLog might not have the podName when it
 				// was created.
@@ -728,6 +788,9 @@ func NewEndpointFromChangeModel(base *models.EndpointChangeRequest) (*Endpoint,
 		ContainerID:      base.ContainerID,
 		DockerNetworkID:  base.DockerNetworkID,
 		DockerEndpointID: base.DockerEndpointID,
+		ContainerRuntime: base.ContainerRuntime,
+		K8sPodName:       base.K8sPodName,
+		K8sNamespace:     base.K8sNamespace,
 		IfName:           base.InterfaceName,
 		IfIndex:          int(base.InterfaceIndex),
 		OpLabels: pkgLabels.OpLabels{
@@ -776,9 +839,39 @@ func NewEndpointFromChangeModel(base *models.EndpointChangeRequest) (*Endpoint,
 		}
 	}
 
+	for _, pair := range base.AdditionalAddressing {
+		if pair == nil {
+			continue
+		}
+
+		if ip := pair.IPV6; ip != "" {
+			ip6, err := addressing.NewCiliumIPv6(ip)
+			if err != nil {
+				return nil, err
+			}
+			ep.AdditionalIPv6 = append(ep.AdditionalIPv6, ip6)
+		}
+
+		if ip := pair.IPV4; ip != "" {
+			ip4, err := addressing.NewCiliumIPv4(ip)
+			if err != nil {
+				return nil, err
+			}
+			ep.AdditionalIPv4 = append(ep.AdditionalIPv4, ip4)
+		}
+	}
+
 	return ep, nil
 }
 
+// GetControllersModel returns the status of all controllers of the endpoint,
+// sorted by name.
+func (e *Endpoint) GetControllersModel() models.ControllerStatuses {
+	controllerMdl := e.controllers.GetStatusModel()
+	sort.Slice(controllerMdl, func(i, j int) bool { return controllerMdl[i].Name < controllerMdl[j].Name })
+	return controllerMdl
+}
+
 // GetModelRLocked returns the API model of endpoint e.
 // e.mutex must be RLocked.
 func (e *Endpoint) GetModelRLocked() *models.Endpoint {
@@ -815,8 +908,7 @@ func (e *Endpoint) GetModelRLocked() *models.Endpoint {
 	sort.StringSlice(lblMdl.SecurityRelevant).Sort()
 	sort.StringSlice(lblMdl.Derived).Sort()
 
-	controllerMdl := e.controllers.GetStatusModel()
-	sort.Slice(controllerMdl, func(i, j int) bool { return controllerMdl[i].Name < controllerMdl[j].Name })
+	controllerMdl := e.GetControllersModel()
 
 	spec := &models.EndpointConfigurationSpec{
 		LabelConfiguration: lblSpec,
@@ -833,10 +925,7 @@ func (e *Endpoint) GetModelRLocked() *models.Endpoint {
 			Identity: e.SecurityIdentity.GetModel(),
 			Labels:   lblMdl,
 			Networking: &models.EndpointNetworking{
-				Addressing: []*models.AddressPair{{
-					IPV4: e.IPv4.String(),
-					IPV6: e.IPv6.String(),
-				}},
+				Addressing:     e.getAddressingModel(),
 				InterfaceIndex: int64(e.IfIndex),
 				InterfaceName:  e.IfName,
 				Mac:            e.LXCMAC.String(),
@@ -845,13 +934,18 @@ func (e *Endpoint) GetModelRLocked() *models.Endpoint {
 			ExternalIdentifiers: &models.EndpointIdentifiers{
 				ContainerID:      e.ContainerID,
 				ContainerName:    e.ContainerName,
+				ContainerRuntime: e.ContainerRuntime,
 				DockerEndpointID: e.DockerEndpointID,
 				DockerNetworkID:  e.DockerNetworkID,
+				K8sNamespace:     e.K8sNamespace,
+				K8sPodName:       e.K8sPodName,
 				PodName:          e.GetK8sNamespaceAndPodNameLocked(),
 			},
 			// FIXME GH-3280 When we begin returning endpoint revisions this should
 			// change to return the configured and in-datapath policies.
 			Policy:      e.GetPolicyModel(),
+			Debug:       e.getDebugModel(),
+			Quarantine:  e.getQuarantineModel(),
 			Log:         statusLog,
 			Controllers: controllerMdl,
 			State:       currentState, // TODO: Validate
@@ -923,9 +1017,65 @@ func (e *Endpoint) getHealthModel() *models.EndpointHealth {
 		}
 	}
 
+	h.PolicySync = e.Status.getSubsystemHealthModel(Policy)
+	h.BpfSync = e.Status.getSubsystemHealthModel(BPF)
+	h.ProxySync = e.Status.getSubsystemHealthModel(Proxy)
+	h.ProxyRedirects = e.getProxyRedirectHealthModel()
+	h.Connectivity = getConnectivityHealthModel(currentState)
+
+	// A redirect whose proxy is not listening silently drops the traffic
+	// redirected to it, so report it even if the redirect was configured
+	// successfully.
+	if h.ProxySync.Status == models.EndpointSubsystemHealthStatusOK {
+		if proxySync := getProxyRedirectSyncHealthModel(h.ProxyRedirects); proxySync != nil {
+			h.ProxySync = proxySync
+			if proxySync.Status == models.EndpointSubsystemHealthStatusFailure &&
+				h.OverallHealth == models.EndpointHealthStatusOK {
+				h.OverallHealth = models.EndpointHealthStatusWarning
+			}
+		}
+	}
+
 	return &h
 }
 
+// getConnectivityHealthModel returns the health of the network connectivity
+// of an endpoint in the given state.
+func getConnectivityHealthModel(state models.EndpointState) *models.EndpointSubsystemHealth {
+	switch state {
+	case models.EndpointStateDisconnecting, models.EndpointStateDisconnected:
+		return &models.EndpointSubsystemHealth{
+			Status: models.EndpointSubsystemHealthStatusFailure,
+			Cause:  "Endpoint is disconnected",
+		}
+	case models.EndpointStateCreating, models.EndpointStateRestoring:
+		return &models.EndpointSubsystemHealth{
+			Status: models.EndpointSubsystemHealthStatusDegraded,
+			Cause:  "Endpoint datapath is not yet set up",
+		}
+	case models.EndpointStateWaitingForIdentity:
+		return &models.EndpointSubsystemHealth{
+			Status: models.EndpointSubsystemHealthStatusDegraded,
+			Cause:  "Waiting for security identity, all traffic is dropped",
+		}
+	}
+	return &models.EndpointSubsystemHealth{
+		Status: models.EndpointSubsystemHealthStatusOK,
+	}
+}
+
+// GetL7Statistics returns the statistics of the L7 traffic of the endpoint,
+// updated by the proxies when logging the records of the endpoint.
+func (e *Endpoint) GetL7Statistics() *logger.L7Statistics {
+	return &e.l7Statistics
+}
+
+// GetL7StatisticsModel returns the statistics of the L7 traffic of the
+// endpoint per port and L7 parser as observed by the proxies.
+func (e *Endpoint) GetL7StatisticsModel() []*models.L7Statistics {
+	return e.l7Statistics.GetModel()
+}
+
 // GetHealthModel returns the endpoint's health object.
 func (e *Endpoint) GetHealthModel() *models.EndpointHealth {
 	// NOTE: Using rlock on mutex directly because getHealthModel handles removed endpoint properly
@@ -1007,8 +1157,14 @@ func (e *Endpoint) GetPolicyModel() *models.EndpointPolicyStatus {
 	// Make a shallow copy of the stats.
 	e.proxyStatisticsMutex.RLock()
 	proxyStats := make([]*models.ProxyStatistics, 0, len(e.proxyStatistics))
+	now := time.Now()
 	for _, stats := range e.proxyStatistics {
 		statsCopy := *stats
+		// The redirect is closed once the drain deadline has passed.
+		if statsCopy.DrainingProxyPort != 0 && now.After(time.Time(statsCopy.DrainDeadline)) {
+			statsCopy.DrainingProxyPort = 0
+			statsCopy.DrainDeadline = strfmt.DateTime{}
+		}
 		proxyStats = append(proxyStats, &statsCopy)
 	}
 	e.proxyStatisticsMutex.RUnlock()
@@ -1024,6 +1180,7 @@ func (e *Endpoint) GetPolicyModel() *models.EndpointPolicyStatus {
 		CidrPolicy:               e.L3Policy.GetModel(),
 		L4:                       e.RealizedL4Policy.GetModel(),
 		PolicyEnabled:            policyEnabled,
+		PolicyMap:                e.realizedMapState.GetModel(),
 	}
 
 	desiredMdl := &models.EndpointPolicy{
@@ -1036,6 +1193,7 @@ func (e *Endpoint) GetPolicyModel() *models.EndpointPolicyStatus {
 		CidrPolicy:               e.L3Policy.GetModel(),
 		L4:                       e.DesiredL4Policy.GetModel(),
 		PolicyEnabled:            policyEnabled,
+		PolicyMap:                e.desiredMapState.GetModel(),
 	}
 	// FIXME GH-3280 Once we start returning revisions Realized should be the
 	// policy implemented in the data path
@@ -1047,6 +1205,134 @@ func (e *Endpoint) GetPolicyModel() *models.EndpointPolicyStatus {
 	}
 }
 
+// AddPolicyMapModel adds the packet and byte counters of the realized
+// policy map entries and the difference between the desired policy map state
+// and the entries of the BPF policy map of the endpoint to mdl. Both are
+// omitted if the policy map cannot be read.
+//
+// The BPF policy map is dumped, so this is only done on request and not as
+// part of GetModel(). The counters change with every packet and would
+// otherwise cause the model to differ on every call.
+func (e *Endpoint) AddPolicyMapModel(mdl *models.EndpointPolicyStatus) {
+	if mdl == nil {
+		return
+	}
+
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	contents := e.policyMapContents()
+	if contents == nil {
+		return
+	}
+	if mdl.Realized != nil {
+		mdl.Realized.PolicyMap = e.realizedMapState.getModel(contents)
+	}
+	mdl.PolicyMapDiff = diffPolicyMapState(e.desiredMapState, policyMapStateOf(contents))
+}
+
+// GetModel returns the entries of the policy map state as API model, sorted
+// by direction, identity, port and protocol.
+func (pms PolicyMapState) GetModel() []*models.PolicyMapEntry {
+	return pms.getModel(nil)
+}
+
+// getModel returns the entries of the policy map state as API model like
+// GetModel. The packet and byte counters of each entry are taken from the
+// entry with the same key in counters.
+func (pms PolicyMapState) getModel(counters map[policymap.PolicyKey]policymap.PolicyEntry) []*models.PolicyMapEntry {
+	keys := make([]policymap.PolicyKey, 0, len(pms))
+	for key := range pms {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ki, kj := keys[i], keys[j]
+		switch {
+		case ki.TrafficDirection != kj.TrafficDirection:
+			return ki.TrafficDirection < kj.TrafficDirection
+		case ki.Identity != kj.Identity:
+			return ki.Identity < kj.Identity
+		case ki.DestPort != kj.DestPort:
+			return ki.DestPort < kj.DestPort
+		}
+		return ki.Nexthdr < kj.Nexthdr
+	})
+
+	entries := make([]*models.PolicyMapEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, &models.PolicyMapEntry{
+			Direction: policymap.TrafficDirection(key.TrafficDirection).String(),
+			Identity:  int64(key.Identity),
+			Port:      int64(key.DestPort),
+			Protocol:  u8proto.U8proto(key.Nexthdr).String(),
+			ProxyPort: int64(pms[key].ProxyPort),
+			Packets:   int64(counters[key].Packets),
+			Bytes:     int64(counters[key].Bytes),
+		})
+	}
+	return entries
+}
+
+// policyMapContents returns the entries of the BPF policy map of the
+// endpoint keyed by their key in host byte-order. The entries carry the packet
+// and byte counters maintained by the datapath. Returns nil if the policy map
+// cannot be read.
+//
+// Must be called with e.Mutex locked.
+func (e *Endpoint) policyMapContents() map[policymap.PolicyKey]policymap.PolicyEntry {
+	if e.PolicyMap == nil {
+		return nil
+	}
+
+	entries, err := e.PolicyMap.DumpToSlice()
+	if err != nil {
+		e.getLogger().WithError(err).Debug("Unable to read policy map")
+		return nil
+	}
+
+	contents := make(map[policymap.PolicyKey]policymap.PolicyEntry, len(entries))
+	for _, entry := range entries {
+		contents[entry.Key.ToHost()] = entry.PolicyEntry
+	}
+	return contents
+}
+
+// policyMapStateOf returns the policy map state installed by the given
+// contents of a BPF policy map.
+func policyMapStateOf(contents map[policymap.PolicyKey]policymap.PolicyEntry) PolicyMapState {
+	state := make(PolicyMapState, len(contents))
+	for key, entry := range contents {
+		state[key] = PolicyMapStateEntry{
+			ProxyPort: byteorder.NetworkToHost(entry.ProxyPort).(uint16),
+		}
+	}
+	return state
+}
+
+// diffPolicyMapState returns the entries of the desired policy map state
+// which are not realized and the realized entries which are not desired. An
+// entry which is realized with a different proxy port is reported as both.
+func diffPolicyMapState(desired, realized PolicyMapState) *models.PolicyMapDiff {
+	missing := make(PolicyMapState)
+	for key, entry := range desired {
+		if realizedEntry, ok := realized[key]; !ok || realizedEntry != entry {
+			missing[key] = entry
+		}
+	}
+
+	unexpected := make(PolicyMapState)
+	for key, entry := range realized {
+		if desiredEntry, ok := desired[key]; !ok || desiredEntry != entry {
+			unexpected[key] = entry
+		}
+	}
+
+	return &models.PolicyMapDiff{
+		Missing:    missing.GetModel(),
+		Unexpected: unexpected.GetModel(),
+	}
+}
+
 // policyStatus returns the endpoint's policy status
 //
 // Must be called with e.Mutex locked.
@@ -1284,6 +1570,42 @@ func (e *EndpointStatus) CurrentStatus() StatusCode {
 	return OK
 }
 
+// getSubsystemHealthModel returns the health of the subsystem whose status is
+// logged with the given StatusType. The cause is the message of the last
+// status logged for that subsystem if it was not OK.
+func (e *EndpointStatus) getSubsystemHealthModel(typ StatusType) *models.EndpointSubsystemHealth {
+	e.indexMU.RLock()
+	defer e.indexMU.RUnlock()
+
+	h := &models.EndpointSubsystemHealth{
+		Status: models.EndpointSubsystemHealthStatusOK,
+	}
+	s, ok := e.CurrentStatuses[typ]
+	if !ok {
+		return h
+	}
+	switch s.Status.Code {
+	case Warning, Disabled:
+		h.Status = models.EndpointSubsystemHealthStatusDegraded
+		h.Cause = s.Status.Msg
+	case Failure:
+		h.Status = models.EndpointSubsystemHealthStatusFailure
+		h.Cause = s.Status.Msg
+	}
+	return h
+}
+
+// getStatusCode returns the code of the last status logged with the given
+// StatusType, or OK if no such status was logged.
+func (e *EndpointStatus) getStatusCode(typ StatusType) StatusCode {
+	e.indexMU.RLock()
+	defer e.indexMU.RUnlock()
+	if s, ok := e.CurrentStatuses[typ]; ok {
+		return s.Status.Code
+	}
+	return OK
+}
+
 func (e *EndpointStatus) String() string {
 	return e.CurrentStatus().String()
 }
@@ -1333,11 +1655,16 @@ func optionChanged(key string, value option.OptionSetting, data interface{}) {
 // applyOptsLocked applies the given options to the endpoint's options and
 // returns true if there were any options changed.
 func (e *Endpoint) applyOptsLocked(opts option.OptionMap) bool {
+	dnsVisibility := e.Options.IsEnabled(option.DNSVisibility)
 	changed := e.Options.ApplyValidated(opts, optionChanged, e) > 0
 	_, exists := opts[option.Debug]
 	if exists && changed {
 		e.UpdateLogger(nil)
 	}
+	// The DNS visibility redirect is part of the L4 policy of the endpoint
+	if e.Options.IsEnabled(option.DNSVisibility) != dnsVisibility {
+		e.forcePolicyCompute = true
+	}
 	return changed
 }
 
@@ -1448,7 +1775,8 @@ func FilterEPDir(dirFiles []os.FileInfo) []string {
 	for _, file := range dirFiles {
 		if file.IsDir() {
 			_, err := strconv.ParseUint(file.Name(), 10, 16)
-			if err == nil || strings.HasSuffix(file.Name(), "_next_fail") {
+			if err == nil || strings.HasSuffix(file.Name(), "_next_fail") ||
+				strings.HasSuffix(file.Name(), "_stale") {
 				eptsID = append(eptsID, file.Name())
 			}
 		}
@@ -1501,14 +1829,21 @@ func (e *Endpoint) RemoveFromGlobalPolicyMap() error {
 // GetBPFKeys returns all keys which should represent this endpoint in the BPF
 // endpoints map
 func (e *Endpoint) GetBPFKeys() []*lxcmap.EndpointKey {
-	key := lxcmap.NewEndpointKey(e.IPv6.IP())
+	keys := []*lxcmap.EndpointKey{lxcmap.NewEndpointKey(e.IPv6.IP())}
 
 	if e.IPv4 != nil {
-		key4 := lxcmap.NewEndpointKey(e.IPv4.IP())
-		return []*lxcmap.EndpointKey{key, key4}
+		keys = append(keys, lxcmap.NewEndpointKey(e.IPv4.IP()))
+	}
+
+	for _, ip := range e.AdditionalIPv6 {
+		keys = append(keys, lxcmap.NewEndpointKey(ip.IP()))
+	}
+
+	for _, ip := range e.AdditionalIPv4 {
+		keys = append(keys, lxcmap.NewEndpointKey(ip.IP()))
 	}
 
-	return []*lxcmap.EndpointKey{key}
+	return keys
 }
 
 // GetBPFValue returns the value which should represent this endpoint in the
@@ -1681,45 +2016,66 @@ func (e *Endpoint) Update(owner Owner, cfg *models.EndpointConfigurationSpec) er
 		e.getLogger().Debug("need to regenerate endpoint; checking state before" +
 			" attempting to regenerate")
 
-		// TODO / FIXME: GH-3281: need ways to queue up regenerations per-endpoint.
+		_, err := e.regenerateWhenReady(owner, reason)
+		return err
+	}
 
-		// Default timeout for PATCH /endpoint/{id}/config is 60 seconds, so put
-		// timeout in this function a bit below that timeout. If the timeout
-		// for clients in API is below this value, they will get a message containing
-		// "context deadline exceeded".
-		timeout := time.After(EndpointGenerationTimeout)
+	e.Unlock()
+	return nil
+}
 
-		// Check for endpoint state every second.
-		ticker := time.NewTicker(1 * time.Second)
-		defer ticker.Stop()
+// UpdateAsync applies cfg to the endpoint like Update, but in the background
+// through the controller with the given name. Only the latest configuration
+// is applied if the controller is updated again before the previous one has
+// been applied, so at most one update per name is pending for the endpoint.
+func (e *Endpoint) UpdateAsync(owner Owner, name string, cfg *models.EndpointConfigurationSpec) {
+	e.controllers.UpdateController(fmt.Sprintf("%s (%d)", name, e.ID),
+		controller.ControllerParams{
+			DoFunc: func() error {
+				return e.Update(owner, cfg)
+			},
+		},
+	)
+}
 
-		e.Unlock()
-		for {
-			select {
-			case <-ticker.C:
-				if err := e.LockAlive(); err != nil {
-					return err
-				}
-				// Check endpoint state before attempting configuration update because
-				// configuration updates can only be applied when the endpoint is in
-				// specific states. See GH-3058.
-				stateTransitionSucceeded := e.SetStateLocked(StateWaitingToRegenerate, reason)
-				if stateTransitionSucceeded {
-					e.Unlock()
-					e.Regenerate(owner, NewRegenerationContext(reason))
-					return nil
-				}
+// regenerateWhenReady waits for the endpoint to reach a state in which it can
+// be regenerated and triggers the regeneration. Returns the channel which
+// receives the result of the regeneration.
+// Must be called with e.Mutex held, which is released by this function.
+func (e *Endpoint) regenerateWhenReady(owner Owner, reason string) (<-chan bool, error) {
+	// TODO / FIXME: GH-3281: need ways to queue up regenerations per-endpoint.
+
+	// Default timeout for PATCH /endpoint/{id}/config is 60 seconds, so put
+	// timeout in this function a bit below that timeout. If the timeout
+	// for clients in API is below this value, they will get a message containing
+	// "context deadline exceeded".
+	timeout := time.After(EndpointGenerationTimeout)
+
+	// Check for endpoint state every second.
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	e.Unlock()
+	for {
+		select {
+		case <-ticker.C:
+			if err := e.LockAlive(); err != nil {
+				return nil, err
+			}
+			// Check endpoint state before attempting configuration update because
+			// configuration updates can only be applied when the endpoint is in
+			// specific states. See GH-3058.
+			stateTransitionSucceeded := e.SetStateLocked(StateWaitingToRegenerate, reason)
+			if stateTransitionSucceeded {
 				e.Unlock()
-			case <-timeout:
-				e.getLogger().Warningf("timed out waiting for endpoint state to change")
-				return UpdateStateChangeError{fmt.Sprintf("unable to regenerate endpoint program because state transition to %s was unsuccessful; check `cilium endpoint log %d` for more information", StateWaitingToRegenerate, e.ID)}
+				return e.Regenerate(owner, NewRegenerationContext(reason)), nil
 			}
+			e.Unlock()
+		case <-timeout:
+			e.getLogger().Warningf("timed out waiting for endpoint state to change")
+			return nil, UpdateStateChangeError{fmt.Sprintf("unable to regenerate endpoint program because state transition to %s was unsuccessful; check `cilium endpoint log %d` for more information", StateWaitingToRegenerate, e.ID)}
 		}
-
 	}
-
-	e.Unlock()
-	return nil
 }
 
 // HasLabels returns whether endpoint e contains all labels l. Will return 'false'
@@ -1843,6 +2199,7 @@ func (e *Endpoint) LeaveLocked(owner Owner, proxyWaitGroup *completion.WaitGroup
 	e.removeDirectories()
 	e.controllers.RemoveAll()
 	e.cleanPolicySignals()
+	e.stopDebugTimerLocked()
 
 	if !e.ConntrackLocalLocked() {
 		e.scrubIPsInConntrackTableLocked()
@@ -1873,11 +2230,28 @@ func (e *Endpoint) SetContainerName(name string) {
 	e.Unlock()
 }
 
+// GetContainerRuntime returns the name of the container runtime managing the
+// endpoint's container
+func (e *Endpoint) GetContainerRuntime() string {
+	e.UnconditionalRLock()
+	runtime := e.ContainerRuntime
+	e.RUnlock()
+	return runtime
+}
+
+// SetContainerRuntime modifies the name of the container runtime managing
+// the endpoint's container
+func (e *Endpoint) SetContainerRuntime(runtime string) {
+	e.UnconditionalLock()
+	e.ContainerRuntime = runtime
+	e.Unlock()
+}
+
 // GetK8sNamespace returns the name of the pod if the endpoint represents a
 // Kubernetes pod
 func (e *Endpoint) GetK8sNamespace() string {
 	e.UnconditionalRLock()
-	ns := e.k8sNamespace
+	ns := e.K8sNamespace
 	e.RUnlock()
 	return ns
 }
@@ -1885,7 +2259,7 @@ func (e *Endpoint) GetK8sNamespace() string {
 // SetK8sNamespace modifies the endpoint's pod name
 func (e *Endpoint) SetK8sNamespace(name string) {
 	e.UnconditionalLock()
-	e.k8sNamespace = name
+	e.K8sNamespace = name
 	e.UpdateLogger(map[string]interface{}{
 		logfields.K8sPodName: e.GetK8sNamespaceAndPodNameLocked(),
 	})
@@ -1896,7 +2270,7 @@ func (e *Endpoint) SetK8sNamespace(name string) {
 // Kubernetes pod
 func (e *Endpoint) GetK8sPodName() string {
 	e.UnconditionalRLock()
-	k8sPodName := e.k8sPodName
+	k8sPodName := e.K8sPodName
 	e.RUnlock()
 
 	return k8sPodName
@@ -1905,13 +2279,13 @@ func (e *Endpoint) GetK8sPodName() string {
 // GetK8sNamespaceAndPodNameLocked returns the namespace and pod name.  This
 // function requires e.Mutex to be held.
 func (e *Endpoint) GetK8sNamespaceAndPodNameLocked() string {
-	return e.k8sNamespace + "/" + e.k8sPodName
+	return e.K8sNamespace + "/" + e.K8sPodName
 }
 
 // SetK8sPodName modifies the endpoint's pod name
 func (e *Endpoint) SetK8sPodName(name string) {
 	e.UnconditionalLock()
-	e.k8sPodName = name
+	e.K8sPodName = name
 	e.UpdateLogger(map[string]interface{}{
 		logfields.K8sPodName: e.GetK8sNamespaceAndPodNameLocked(),
 	})
@@ -2153,6 +2527,9 @@ OKState:
 func (e *Endpoint) bumpPolicyRevisionLocked(revision uint64) {
 	if revision > e.policyRevision {
 		e.setPolicyRevision(revision)
+		// Logged as Other so that it does not hide policy warnings of
+		// the same regeneration from the endpoint health.
+		e.logStatusLocked(Other, OK, fmt.Sprintf("Policy revision %d applied", revision))
 	}
 }
 
@@ -2202,6 +2579,39 @@ func (e *Endpoint) getProxyStatisticsLocked(l7Protocol string, port uint16, ingr
 	return proxyStats
 }
 
+// drainProxyStatisticsLocked updates the ProxyStatistics of the redirect to
+// the given proxy port to report that the redirect has been removed and is
+// draining its existing connections. Returns the updated ProxyStatistics, or
+// nil if none has the given proxy port.
+// Must be called with e.proxyStatisticsMutex held.
+func (e *Endpoint) drainProxyStatisticsLocked(redirectPort uint16) *models.ProxyStatistics {
+	// We don't know the L7 protocol of the redirect, so we can't just
+	// build a ProxyStatistics and lookup e.proxyStatistics by key.
+	// We have to loop to find which entry has the same redirect port.
+	// Looping is acceptable since there should be only a few redirects
+	// for each endpoint.
+	for _, stats := range e.proxyStatistics {
+		if stats.AllocatedProxyPort == int64(redirectPort) {
+			stats.AllocatedProxyPort = 0
+			if option.Config.ProxyDrainTimeout > 0 {
+				stats.DrainingProxyPort = int64(redirectPort)
+				stats.DrainDeadline = strfmt.DateTime(time.Now().Add(option.Config.ProxyDrainTimeout))
+			}
+			return stats
+		}
+	}
+	return nil
+}
+
+// undrainProxyStatisticsLocked reverts drainProxyStatisticsLocked for the given
+// ProxyStatistics.
+// Must be called with e.proxyStatisticsMutex held.
+func undrainProxyStatisticsLocked(stats *models.ProxyStatistics, redirectPort uint16) {
+	stats.AllocatedProxyPort = int64(redirectPort)
+	stats.DrainingProxyPort = 0
+	stats.DrainDeadline = strfmt.DateTime{}
+}
+
 // UpdateProxyStatistics updates the Endpoint's proxy  statistics to account
 // for a new observed flow with the given characteristics.
 func (e *Endpoint) UpdateProxyStatistics(l7Protocol string, port uint16, ingress, request bool, verdict accesslog.FlowVerdict) {
@@ -2233,6 +2643,42 @@ func (e *Endpoint) UpdateProxyStatistics(l7Protocol string, port uint16, ingress
 	}
 }
 
+// UpdateProxyRedirectHealth updates the health of the proxy redirect with the
+// given ID. A nil health removes the redirect from the endpoint's health.
+func (e *Endpoint) UpdateProxyRedirectHealth(id string, health *models.ProxyRedirectHealth) {
+	e.proxyStatisticsMutex.Lock()
+	defer e.proxyStatisticsMutex.Unlock()
+
+	if health == nil {
+		delete(e.proxyRedirectHealth, id)
+		return
+	}
+	if e.proxyRedirectHealth == nil {
+		e.proxyRedirectHealth = make(map[string]*models.ProxyRedirectHealth)
+	}
+	e.proxyRedirectHealth[id] = health
+}
+
+// getProxyRedirectHealthModel returns a copy of the health of the proxy
+// redirects of the endpoint, sorted by redirect ID.
+func (e *Endpoint) getProxyRedirectHealthModel() []*models.ProxyRedirectHealth {
+	e.proxyStatisticsMutex.RLock()
+	defer e.proxyStatisticsMutex.RUnlock()
+
+	if len(e.proxyRedirectHealth) == 0 {
+		return nil
+	}
+	redirects := make([]*models.ProxyRedirectHealth, 0, len(e.proxyRedirectHealth))
+	for _, health := range e.proxyRedirectHealth {
+		healthCopy := *health
+		redirects = append(redirects, &healthCopy)
+	}
+	sort.Slice(redirects, func(i, j int) bool {
+		return redirects[i].ID < redirects[j].ID
+	})
+	return redirects
+}
+
 // APICanModify determines whether API requests from a user are allowed to
 // modify this endpoint.
 func APICanModify(e *Endpoint) error {
@@ -2521,6 +2967,8 @@ func (e *Endpoint) identityLabelsChanged(owner Owner, myChangeRev int) error {
 
 	e.Unlock()
 
+	owner.UpdateMirrorCollectors()
+
 	if readyToRegenerate {
 		e.Regenerate(owner, NewRegenerationContext("updated security labels"))
 	}
@@ -2591,16 +3039,121 @@ func (e *Endpoint) WaitForPolicyRevision(ctx context.Context, rev uint64) <-chan
 	return ch
 }
 
-// IPs returns the slice of valid IPs for this endpoint.
+// IPs returns the slice of valid IPs for this endpoint, including the
+// additional IPs.
 func (e *Endpoint) IPs() []net.IP {
 	ips := []net.IP{}
+	for _, ip := range e.IPv4Addresses() {
+		ips = append(ips, ip.IP())
+	}
+	for _, ip := range e.IPv6Addresses() {
+		ips = append(ips, ip.IP())
+	}
+	return ips
+}
+
+// IPv4Addresses returns all IPv4 addresses of the endpoint, starting with
+// the primary address.
+func (e *Endpoint) IPv4Addresses() []addressing.CiliumIPv4 {
+	ips := []addressing.CiliumIPv4{}
 	if e.IPv4 != nil {
-		ips = append(ips, e.IPv4.IP())
+		ips = append(ips, e.IPv4)
 	}
+	return append(ips, e.AdditionalIPv4...)
+}
+
+// IPv6Addresses returns all IPv6 addresses of the endpoint, starting with
+// the primary address.
+func (e *Endpoint) IPv6Addresses() []addressing.CiliumIPv6 {
+	ips := []addressing.CiliumIPv6{}
 	if e.IPv6 != nil {
-		ips = append(ips, e.IPv6.IP())
+		ips = append(ips, e.IPv6)
 	}
-	return ips
+	return append(ips, e.AdditionalIPv6...)
+}
+
+// HasAdditionalIPs returns true if the endpoint has addresses in addition
+// to its primary IPv4 and IPv6 address.
+func (e *Endpoint) HasAdditionalIPs() bool {
+	return len(e.AdditionalIPv4) != 0 || len(e.AdditionalIPv6) != 0
+}
+
+// SetAdditionalAddressesLocked replaces the additional addresses of the
+// endpoint. The endpoints map entries and the IP to identity mappings of the
+// removed addresses are deleted, the IP to identity mappings of the added
+// addresses are synchronized to the key-value store if the endpoint has an
+// identity. The endpoint must be
+// regenerated for the added addresses to be written to the endpoints map.
+// Returns true if the addresses changed.
+// Must be called with e.Mutex locked.
+func (e *Endpoint) SetAdditionalAddressesLocked(ipv4 []addressing.CiliumIPv4, ipv6 []addressing.CiliumIPv6) bool {
+	if reflect.DeepEqual(e.AdditionalIPv4, ipv4) && reflect.DeepEqual(e.AdditionalIPv6, ipv6) {
+		return false
+	}
+
+	oldIPs := map[string]addressing.CiliumIP{}
+	for _, ip := range e.AdditionalIPv4 {
+		oldIPs[ip.String()] = ip
+	}
+	for _, ip := range e.AdditionalIPv6 {
+		oldIPs[ip.String()] = ip
+	}
+
+	newIPs := map[string]addressing.CiliumIP{}
+	for _, ip := range ipv4 {
+		newIPs[ip.String()] = ip
+	}
+	for _, ip := range ipv6 {
+		newIPs[ip.String()] = ip
+	}
+
+	e.AdditionalIPv4 = ipv4
+	e.AdditionalIPv6 = ipv6
+
+	for s, ip := range oldIPs {
+		if _, ok := newIPs[s]; ok {
+			continue
+		}
+		if err := lxcmap.DeleteEntry(ip.IP()); err != nil {
+			e.getLogger().WithError(err).WithField(logfields.IPAddr, s).
+				Warn("Unable to delete removed address from endpoints map")
+		}
+		e.stopIPIdentitySync(ip)
+	}
+
+	// The mappings are synchronized once the endpoint has an identity
+	if e.SecurityIdentity != nil {
+		for s, ip := range newIPs {
+			if _, ok := oldIPs[s]; !ok {
+				e.runIPIdentitySync(ip, true)
+			}
+		}
+	}
+
+	return true
+}
+
+// getAddressingModel returns the addressing of the endpoint. The primary
+// addresses are returned first, followed by pairs of the additional
+// addresses.
+func (e *Endpoint) getAddressingModel() []*models.AddressPair {
+	pairs := []*models.AddressPair{{
+		IPV4: e.IPv4.String(),
+		IPV6: e.IPv6.String(),
+	}}
+
+	for i := 0; i < len(e.AdditionalIPv4) || i < len(e.AdditionalIPv6); i++ {
+		pair := &models.AddressPair{}
+		if i < len(e.AdditionalIPv4) {
+			pair.IPV4 = e.AdditionalIPv4[i].String()
+		}
+		if i < len(e.AdditionalIPv6) {
+			pair.IPV6 = e.AdditionalIPv6[i].String()
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return pairs
 }
 
 // InsertEvent is called when the endpoint is inserted into the endpoint
@@ -2661,6 +3214,13 @@ func (e *Endpoint) syncPolicyMap() error {
 
 	errors := []error{}
 
+	// All changes are applied as a batch to keep the number of syscalls
+	// low on large policy changes. Operations are identified by the index
+	// returned when queuing them.
+	batch := e.PolicyMap.NewBatch()
+	deletes := map[int]policymap.PolicyKey{}
+	adds := map[int]policymap.PolicyKey{}
+
 	for _, entry := range currentMapContents {
 		// Convert key to host-byte order for lookup in the desiredMapState.
 		keyHostOrder := entry.Key.ToHost()
@@ -2669,27 +3229,36 @@ func (e *Endpoint) syncPolicyMap() error {
 		if _, ok := e.desiredMapState[keyHostOrder]; !ok {
 			// Can pass key with host byte-order fields, as it will get
 			// converted to network byte-order.
-			err := e.PolicyMap.DeleteKey(keyHostOrder)
-			if err != nil {
-				e.getLogger().WithError(err).Errorf("Failed to delete PolicyMap key %s", entry.Key.String())
-				errors = append(errors, err)
-			} else {
-				// Operation was successful, remove from realized state.
-				delete(e.realizedMapState, keyHostOrder)
-			}
+			deletes[batch.DeleteKey(keyHostOrder)] = keyHostOrder
 		}
 	}
 
 	for keyToAdd, entry := range e.desiredMapState {
 		if oldEntry, ok := e.realizedMapState[keyToAdd]; !ok || oldEntry != entry {
-			err := e.PolicyMap.AllowKey(keyToAdd, entry.ProxyPort)
-			if err != nil {
-				e.getLogger().WithError(err).Errorf("Failed to add PolicyMap key %s %d", keyToAdd.String(), entry.ProxyPort)
-				errors = append(errors, err)
-			} else {
-				// Operation was successful, add to realized state.
-				e.realizedMapState[keyToAdd] = entry
-			}
+			adds[batch.AllowKey(keyToAdd, entry.ProxyPort)] = keyToAdd
+		}
+	}
+
+	batchErrors := batch.Flush()
+
+	for idx, key := range deletes {
+		if err, ok := batchErrors[idx]; ok {
+			e.getLogger().WithError(err).Errorf("Failed to delete PolicyMap key %s", key.String())
+			errors = append(errors, err)
+		} else {
+			// Operation was successful, remove from realized state.
+			delete(e.realizedMapState, key)
+		}
+	}
+
+	for idx, key := range adds {
+		entry := e.desiredMapState[key]
+		if err, ok := batchErrors[idx]; ok {
+			e.getLogger().WithError(err).Errorf("Failed to add PolicyMap key %s %d", key.String(), entry.ProxyPort)
+			errors = append(errors, err)
+		} else {
+			// Operation was successful, add to realized state.
+			e.realizedMapState[key] = entry
 		}
 	}
 
@@ -2714,7 +3283,8 @@ func (e *Endpoint) syncPolicyMapController() {
 				defer e.Unlock()
 				return e.syncPolicyMap()
 			},
-			RunInterval: 1 * time.Minute,
+			RunInterval:           1 * time.Minute,
+			ErrorRetryExponential: true,
 		},
 	)
 }
@@ -2760,10 +3330,7 @@ func (e *Endpoint) garbageCollectConntrack(filter *ctmap.GCFilter) {
 
 func (e *Endpoint) scrubIPsInConntrackTableLocked() {
 	e.garbageCollectConntrack(&ctmap.GCFilter{
-		MatchIPs: map[string]struct{}{
-			e.IPv4.String(): {},
-			e.IPv6.String(): {},
-		},
+		MatchIPs: e.getIPsLocked(),
 	})
 }
 
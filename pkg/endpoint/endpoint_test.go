@@ -22,14 +22,21 @@ import (
 	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/common"
 	"github.com/cilium/cilium/common/addressing"
+	"github.com/cilium/cilium/pkg/byteorder"
 	"github.com/cilium/cilium/pkg/checker"
+	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/k8s/apis/cilium.io"
 	pkgLabels "github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/u8proto"
 
+	"github.com/go-openapi/strfmt"
 	. "gopkg.in/check.v1"
 )
 
@@ -175,6 +182,101 @@ func (s *EndpointSuite) TestEndpointStatus(c *C) {
 	c.Assert(eps.String(), Equals, "OK")
 }
 
+func (s *EndpointSuite) TestEndpointSubsystemHealth(c *C) {
+	eps := NewEndpointStatus()
+
+	h := eps.getSubsystemHealthModel(Proxy)
+	c.Assert(h.Status, Equals, models.EndpointSubsystemHealthStatusOK)
+	c.Assert(h.Cause, Equals, "")
+
+	eps.addStatusLog(&statusLogMsg{
+		Status: Status{
+			Code: Failure,
+			Msg:  "Proxy redirect failed",
+			Type: Proxy,
+		},
+		Timestamp: time.Now(),
+	})
+	eps.addStatusLog(&statusLogMsg{
+		Status: Status{
+			Code: Warning,
+			Msg:  "Unable to open CT map",
+			Type: BPF,
+		},
+		Timestamp: time.Now(),
+	})
+
+	h = eps.getSubsystemHealthModel(Proxy)
+	c.Assert(h.Status, Equals, models.EndpointSubsystemHealthStatusFailure)
+	c.Assert(h.Cause, Equals, "Proxy redirect failed")
+	h = eps.getSubsystemHealthModel(BPF)
+	c.Assert(h.Status, Equals, models.EndpointSubsystemHealthStatusDegraded)
+	c.Assert(h.Cause, Equals, "Unable to open CT map")
+	h = eps.getSubsystemHealthModel(Policy)
+	c.Assert(h.Status, Equals, models.EndpointSubsystemHealthStatusOK)
+	c.Assert(eps.getStatusCode(Proxy), Equals, Failure)
+
+	eps.addStatusLog(&statusLogMsg{
+		Status: Status{
+			Code: OK,
+			Msg:  "Proxy redirects configured",
+			Type: Proxy,
+		},
+		Timestamp: time.Now(),
+	})
+	h = eps.getSubsystemHealthModel(Proxy)
+	c.Assert(h.Status, Equals, models.EndpointSubsystemHealthStatusOK)
+	c.Assert(h.Cause, Equals, "")
+
+	h = getConnectivityHealthModel(models.EndpointStateDisconnected)
+	c.Assert(h.Status, Equals, models.EndpointSubsystemHealthStatusFailure)
+	h = getConnectivityHealthModel(models.EndpointStateReady)
+	c.Assert(h.Status, Equals, models.EndpointSubsystemHealthStatusOK)
+}
+
+func (s *EndpointSuite) TestProxyRedirectHealth(c *C) {
+	e := &Endpoint{}
+	c.Assert(e.getProxyRedirectHealthModel(), IsNil)
+
+	e.UpdateProxyRedirectHealth("1:ingress:TCP:80", &models.ProxyRedirectHealth{
+		ID:            "1:ingress:TCP:80",
+		Protocol:      "http",
+		ProxyPort:     10000,
+		ListenerBound: true,
+		RulesLoaded:   true,
+	})
+	e.UpdateProxyRedirectHealth("1:egress:UDP:53", &models.ProxyRedirectHealth{
+		ID:          "1:egress:UDP:53",
+		Protocol:    "dns",
+		ProxyPort:   10001,
+		RulesLoaded: true,
+		LastError:   "address already in use",
+	})
+
+	redirects := e.getProxyRedirectHealthModel()
+	c.Assert(redirects, HasLen, 2)
+	c.Assert(redirects[0].ID, Equals, "1:egress:UDP:53")
+	c.Assert(redirects[1].ID, Equals, "1:ingress:TCP:80")
+
+	// A redirect that failed to listen fails the proxy synchronization
+	h := getProxyRedirectSyncHealthModel(redirects)
+	c.Assert(h, Not(IsNil))
+	c.Assert(h.Status, Equals, models.EndpointSubsystemHealthStatusFailure)
+	c.Assert(h.Cause, Equals, "Proxy redirect 1:egress:UDP:53 is not listening on port 10001: address already in use")
+
+	e.UpdateProxyRedirectHealth("1:egress:UDP:53", nil)
+	redirects = e.getProxyRedirectHealthModel()
+	c.Assert(redirects, HasLen, 1)
+	c.Assert(getProxyRedirectSyncHealthModel(redirects), IsNil)
+
+	// Errors of a listening redirect are reported but do not degrade it
+	redirects[0].LastError = "accept: too many open files"
+	c.Assert(getProxyRedirectSyncHealthModel(redirects), IsNil)
+	redirects[0].RulesLoaded = false
+	h = getProxyRedirectSyncHealthModel(redirects)
+	c.Assert(h.Status, Equals, models.EndpointSubsystemHealthStatusDegraded)
+}
+
 func (s *EndpointSuite) TestEndpointUpdateLabels(c *C) {
 	e := Endpoint{
 		ID:     IPv6Addr.EndpointID(),
@@ -411,6 +513,20 @@ func (s *EndpointSuite) TestWaitForPolicyRevision(c *C) {
 	c.Assert(len(e.policyRevisionSignals), Equals, 0)
 }
 
+func (s *EndpointSuite) TestPolicyRevisionStatusLog(c *C) {
+	e := &Endpoint{Status: NewEndpointStatus()}
+
+	e.bumpPolicyRevisionLocked(2)
+	log := e.Status.GetModel()
+	c.Assert(len(log), Equals, 1)
+	c.Assert(log[0].Message, Equals, "Policy revision 2 applied")
+
+	// Older revisions are neither applied nor logged
+	e.bumpPolicyRevisionLocked(1)
+	c.Assert(e.policyRevision, Equals, uint64(2))
+	c.Assert(len(e.Status.GetModel()), Equals, 1)
+}
+
 func (s *EndpointSuite) TestProxyID(c *C) {
 	e := &Endpoint{ID: 123, policyRevision: 0}
 
@@ -488,3 +604,214 @@ func TestEndpoint_GetK8sPodLabels(t *testing.T) {
 		})
 	}
 }
+
+func (s *EndpointSuite) TestConntrackBypass(c *C) {
+	e := Endpoint{
+		ID:     IPv6Addr.EndpointID(),
+		IPv6:   IPv6Addr,
+		IPv4:   IPv4Addr,
+		Status: NewEndpointStatus(),
+	}
+	e.UnconditionalLock()
+	defer e.Unlock()
+	e.SetDefaultOpts(nil)
+	e.Options.SetBool(option.Conntrack, true)
+
+	// Bypassing conntrack disables it
+	changed := e.updateAndOverrideEndpointOptions(option.OptionMap{option.ConntrackBypass: option.OptionEnabled})
+	c.Assert(changed, Equals, true)
+	c.Assert(e.Options.IsEnabled(option.Conntrack), Equals, false)
+
+	// L4 policy alone does not require conntrack while bypassed
+	e.DesiredL4Policy = &policy.L4Policy{
+		Ingress: policy.L4PolicyMap{"80/TCP": {Port: 80, Protocol: api.ProtoTCP}},
+	}
+	changed = e.updateAndOverrideEndpointOptions(nil)
+	c.Assert(changed, Equals, false)
+	c.Assert(e.Options.IsEnabled(option.Conntrack), Equals, false)
+
+	// L7 redirects require conntrack even while bypassed
+	e.DesiredL4Policy = &policy.L4Policy{
+		Ingress: policy.L4PolicyMap{"80/TCP": {Port: 80, Protocol: api.ProtoTCP, L7Parser: policy.ParserTypeHTTP}},
+	}
+	changed = e.updateAndOverrideEndpointOptions(nil)
+	c.Assert(changed, Equals, true)
+	c.Assert(e.Options.IsEnabled(option.Conntrack), Equals, true)
+
+	// Turning off the bypass falls back to the daemon setting
+	e.DesiredL4Policy = nil
+	e.updateAndOverrideEndpointOptions(option.OptionMap{option.ConntrackBypass: option.OptionEnabled})
+	c.Assert(e.Options.IsEnabled(option.Conntrack), Equals, false)
+	option.Config.Opts.SetBool(option.Conntrack, true)
+	defer option.Config.Opts.SetBool(option.Conntrack, false)
+	e.updateAndOverrideEndpointOptions(option.OptionMap{option.ConntrackBypass: option.OptionDisabled})
+	c.Assert(e.Options.IsEnabled(option.ConntrackBypass), Equals, false)
+	c.Assert(e.Options.IsEnabled(option.Conntrack), Equals, true)
+}
+
+func (s *EndpointSuite) TestWorkloadMetadataRestore(c *C) {
+	e, err := NewEndpointFromChangeModel(&models.EndpointChangeRequest{
+		ID:               42,
+		ContainerID:      "c0ffee",
+		ContainerRuntime: "containerd",
+		K8sPodName:       "foo-7d4b9",
+		K8sNamespace:     "default",
+		State:            models.EndpointStateWaitingForIdentity,
+	})
+	c.Assert(err, IsNil)
+	e.Options = option.NewIntOptions(&EndpointMutableOptionLibrary)
+
+	ids := e.GetModel().Status.ExternalIdentifiers
+	c.Assert(ids.ContainerRuntime, Equals, "containerd")
+	c.Assert(ids.K8sPodName, Equals, "foo-7d4b9")
+	c.Assert(ids.K8sNamespace, Equals, "default")
+	c.Assert(ids.PodName, Equals, "default/foo-7d4b9")
+
+	epStr64, err := e.base64()
+	c.Assert(err, IsNil)
+	restored, err := ParseEndpoint(common.CiliumCHeaderPrefix + "dmVyc2lvbg==:" + epStr64)
+	c.Assert(err, IsNil)
+	c.Assert(restored.GetContainerID(), Equals, "c0ffee")
+	c.Assert(restored.ContainerRuntime, Equals, "containerd")
+	c.Assert(restored.GetK8sPodName(), Equals, "foo-7d4b9")
+	c.Assert(restored.GetK8sNamespace(), Equals, "default")
+}
+
+func (s *EndpointSuite) TestAdditionalAddressing(c *C) {
+	e, err := NewEndpointFromChangeModel(&models.EndpointChangeRequest{
+		ID: 42,
+		Addressing: &models.AddressPair{
+			IPV4: "10.11.12.13",
+			IPV6: "beef:beef:beef:beef:aaaa:aaaa:1111:1112",
+		},
+		AdditionalAddressing: []*models.AddressPair{
+			{IPV4: "10.11.12.14", IPV6: "beef:beef:beef:beef:aaaa:aaaa:1111:1113"},
+			{IPV4: "10.11.12.15"},
+		},
+		State: models.EndpointStateWaitingForIdentity,
+	})
+	c.Assert(err, IsNil)
+	e.Options = option.NewIntOptions(&EndpointMutableOptionLibrary)
+	c.Assert(e.HasAdditionalIPs(), Equals, true)
+	c.Assert(len(e.IPv4Addresses()), Equals, 3)
+	c.Assert(len(e.IPv6Addresses()), Equals, 2)
+	c.Assert(len(e.IPs()), Equals, 5)
+	c.Assert(len(e.GetBPFKeys()), Equals, 5)
+	c.Assert(e.getAddressingModel(), checker.DeepEquals, []*models.AddressPair{
+		{IPV4: "10.11.12.13", IPV6: "beef:beef:beef:beef:aaaa:aaaa:1111:1112"},
+		{IPV4: "10.11.12.14", IPV6: "beef:beef:beef:beef:aaaa:aaaa:1111:1113"},
+		{IPV4: "10.11.12.15"},
+	})
+	c.Assert(len(e.getIPsLocked()), Equals, 5)
+
+	epStr64, err := e.base64()
+	c.Assert(err, IsNil)
+	restored, err := ParseEndpoint(common.CiliumCHeaderPrefix + "dmVyc2lvbg==:" + epStr64)
+	c.Assert(err, IsNil)
+	c.Assert(restored.AdditionalIPv4, checker.DeepEquals, e.AdditionalIPv4)
+	c.Assert(restored.AdditionalIPv6, checker.DeepEquals, e.AdditionalIPv6)
+
+	_, err = NewEndpointFromChangeModel(&models.EndpointChangeRequest{
+		ID:                   42,
+		AdditionalAddressing: []*models.AddressPair{{IPV4: "invalid"}},
+	})
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *EndpointSuite) TestPolicyMapStateModel(c *C) {
+	ingress80 := policymap.PolicyKey{
+		Identity:         1000,
+		DestPort:         80,
+		Nexthdr:          uint8(u8proto.TCP),
+		TrafficDirection: policymap.Ingress.Uint8(),
+	}
+	ingressL3 := policymap.PolicyKey{Identity: 1001, TrafficDirection: policymap.Ingress.Uint8()}
+	egressL3 := policymap.PolicyKey{Identity: 1000, TrafficDirection: policymap.Egress.Uint8()}
+
+	desired := PolicyMapState{
+		ingress80: {ProxyPort: 10080},
+		ingressL3: {},
+	}
+	c.Assert(desired.GetModel(), checker.DeepEquals, []*models.PolicyMapEntry{
+		{Direction: "Ingress", Identity: 1000, Port: 80, Protocol: "TCP", ProxyPort: 10080},
+		{Direction: "Ingress", Identity: 1001, Protocol: "all"},
+	})
+
+	counters := map[policymap.PolicyKey]policymap.PolicyEntry{
+		ingress80: {Packets: 3, Bytes: 180},
+		egressL3:  {Packets: 1, Bytes: 60},
+	}
+	c.Assert(desired.getModel(counters), checker.DeepEquals, []*models.PolicyMapEntry{
+		{Direction: "Ingress", Identity: 1000, Port: 80, Protocol: "TCP", ProxyPort: 10080, Packets: 3, Bytes: 180},
+		{Direction: "Ingress", Identity: 1001, Protocol: "all"},
+	})
+
+	// The installed state is taken from the entries of the BPF policy map
+	// which are in network byte-order
+	installed := policyMapStateOf(map[policymap.PolicyKey]policymap.PolicyEntry{
+		ingress80: {ProxyPort: byteorder.HostToNetwork(uint16(10080)).(uint16)},
+		ingressL3: {},
+	})
+	c.Assert(installed, checker.DeepEquals, desired)
+
+	diff := diffPolicyMapState(desired, desired)
+	c.Assert(len(diff.Missing), Equals, 0)
+	c.Assert(len(diff.Unexpected), Equals, 0)
+
+	realized := PolicyMapState{
+		ingress80: {},
+		egressL3:  {},
+	}
+	diff = diffPolicyMapState(desired, realized)
+	c.Assert(diff.Missing, checker.DeepEquals, []*models.PolicyMapEntry{
+		{Direction: "Ingress", Identity: 1000, Port: 80, Protocol: "TCP", ProxyPort: 10080},
+		{Direction: "Ingress", Identity: 1001, Protocol: "all"},
+	})
+	c.Assert(diff.Unexpected, checker.DeepEquals, []*models.PolicyMapEntry{
+		{Direction: "Ingress", Identity: 1000, Port: 80, Protocol: "TCP"},
+		{Direction: "Egress", Identity: 1000, Protocol: "all"},
+	})
+}
+
+func (s *EndpointSuite) TestDrainProxyStatistics(c *C) {
+	oldTimeout := option.Config.ProxyDrainTimeout
+	defer func() { option.Config.ProxyDrainTimeout = oldTimeout }()
+	option.Config.ProxyDrainTimeout = time.Minute
+
+	e := NewEndpointWithState(42, StateReady)
+	e.SecurityIdentity = &identity.Identity{ID: 1234}
+
+	e.proxyStatisticsMutex.Lock()
+	stats := e.getProxyStatisticsLocked("http", 80, true)
+	stats.AllocatedProxyPort = 10000
+	c.Assert(e.drainProxyStatisticsLocked(10001), IsNil)
+	c.Assert(e.drainProxyStatisticsLocked(10000), Equals, stats)
+	e.proxyStatisticsMutex.Unlock()
+
+	c.Assert(stats.AllocatedProxyPort, Equals, int64(0))
+	c.Assert(stats.DrainingProxyPort, Equals, int64(10000))
+	c.Assert(time.Time(stats.DrainDeadline).After(time.Now()), Equals, true)
+
+	mdl := e.GetPolicyModel()
+	c.Assert(mdl.ProxyStatistics, HasLen, 1)
+	c.Assert(mdl.ProxyStatistics[0].DrainingProxyPort, Equals, int64(10000))
+
+	// The drain state is not reported once the deadline has passed
+	stats.DrainDeadline = strfmt.DateTime(time.Now().Add(-time.Second))
+	mdl = e.GetPolicyModel()
+	c.Assert(mdl.ProxyStatistics[0].DrainingProxyPort, Equals, int64(0))
+	c.Assert(mdl.ProxyStatistics[0].DrainDeadline, Equals, strfmt.DateTime{})
+
+	e.proxyStatisticsMutex.Lock()
+	undrainProxyStatisticsLocked(stats, 10000)
+	e.proxyStatisticsMutex.Unlock()
+	c.Assert(stats.AllocatedProxyPort, Equals, int64(10000))
+	c.Assert(stats.DrainingProxyPort, Equals, int64(0))
+
+	// Without drain timeout the redirect is closed immediately
+	option.Config.ProxyDrainTimeout = 0
+	e.proxyStatisticsMutex.Lock()
+	c.Assert(e.drainProxyStatisticsLocked(10000), Equals, stats)
+	e.proxyStatisticsMutex.Unlock()
+	c.Assert(stats.DrainingProxyPort, Equals, int64(0))
+}
@@ -35,6 +35,10 @@ const (
 	// IPv4Prefix is the prefix used in Cilium IDs when the identifier is
 	// the IPv4 address of the endpoint
 	IPv4Prefix = "ipv4"
+
+	// IPv6Prefix is the prefix used in Cilium IDs when the identifier is
+	// the IPv6 address of the endpoint
+	IPv6Prefix = "ipv6"
 )
 
 func NewCiliumID(id int64) string {
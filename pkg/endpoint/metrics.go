@@ -31,6 +31,7 @@ var (
 type regenerationStatistics struct {
 	success                bool
 	endpointID             uint16
+	trigger                string
 	policyStatus           models.EndpointPolicyEnabled
 	totalTime              spanstat.SpanStat
 	waitingForLock         spanstat.SpanStat
@@ -40,6 +41,7 @@ type regenerationStatistics struct {
 	proxyPolicyCalculation spanstat.SpanStat
 	proxyWaitForAck        spanstat.SpanStat
 	bpfCompilation         spanstat.SpanStat
+	headerWrite            spanstat.SpanStat
 	mapSync                spanstat.SpanStat
 	prepareBuild           spanstat.SpanStat
 }
@@ -50,6 +52,12 @@ func (s *regenerationStatistics) SendMetrics() {
 	endpointPolicyStatus.Update(s.endpointID, s.policyStatus)
 	metrics.EndpointCountRegenerating.Dec()
 
+	for phase, stat := range s.getPhaseMap() {
+		if total := stat.Total(); total != time.Duration(0) {
+			metrics.EndpointRegenerationPhaseDuration.WithLabelValues(phase, s.trigger).Observe(total.Seconds())
+		}
+	}
+
 	if !s.success {
 		// Endpoint regeneration failed, increase on failed metrics
 		metrics.EndpointRegenerationCount.WithLabelValues(metrics.LabelValueOutcomeFail).Inc()
@@ -84,12 +92,26 @@ func (s *regenerationStatistics) GetMap() map[string]*spanstat.SpanStat {
 		"proxyPolicyCalculation": &s.proxyPolicyCalculation,
 		"proxyWaitForAck":        &s.proxyWaitForAck,
 		"bpfCompilation":         &s.bpfCompilation,
+		"headerWrite":            &s.headerWrite,
 		"mapSync":                &s.mapSync,
 		"prepareBuild":           &s.prepareBuild,
 		logfields.BuildDuration:  &s.totalTime,
 	}
 }
 
+// getPhaseMap returns the stats of the phases of the regeneration which are
+// reported per trigger, keyed by phase name
+func (s *regenerationStatistics) getPhaseMap() map[string]*spanstat.SpanStat {
+	return map[string]*spanstat.SpanStat{
+		"policyCalculation":  &s.policyCalculation,
+		"headerWrite":        &s.headerWrite,
+		"bpfCompilation":     &s.bpfCompilation,
+		"mapSync":            &s.mapSync,
+		"proxyConfiguration": &s.proxyConfiguration,
+		"proxyWaitForAck":    &s.proxyWaitForAck,
+	}
+}
+
 // endpointPolicyStatusMap is a map to store the endpoint id and the policy
 // enforcement status. It is used only to send metrics to prometheus.
 type endpointPolicyStatusMap struct {
@@ -0,0 +1,47 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	. "gopkg.in/check.v1"
+)
+
+func phaseSampleCount(c *C, phase, trigger string) uint64 {
+	var m dto.Metric
+	observer := metrics.EndpointRegenerationPhaseDuration.WithLabelValues(phase, trigger)
+	c.Assert(observer.(prometheus.Metric).Write(&m), IsNil)
+	return m.GetHistogram().GetSampleCount()
+}
+
+func (s *EndpointSuite) TestRegenerationPhaseMetrics(c *C) {
+	trigger := "phase metrics test"
+	stats := regenerationStatistics{trigger: trigger}
+
+	stats.policyCalculation.Start()
+	stats.policyCalculation.End(true)
+	stats.headerWrite.Start()
+	stats.headerWrite.End(false)
+	stats.SendMetrics()
+
+	// Phases which have not been hit are not observed
+	c.Assert(phaseSampleCount(c, "policyCalculation", trigger), Equals, uint64(1))
+	c.Assert(phaseSampleCount(c, "headerWrite", trigger), Equals, uint64(1))
+	c.Assert(phaseSampleCount(c, "bpfCompilation", trigger), Equals, uint64(0))
+	c.Assert(phaseSampleCount(c, "policyCalculation", "other"), Equals, uint64(0))
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/monitor"
 	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/cilium/cilium/pkg/revert"
 )
 
@@ -56,6 +57,15 @@ type Owner interface {
 
 	// SendNotification is called to emit an agent notification
 	SendNotification(typ monitor.AgentNotification, text string) error
+
+	// LookupEndpointIfIndex returns the interface index of the local
+	// endpoint with the lowest ID selected by selector.
+	LookupEndpointIfIndex(selector api.EndpointSelector) (int, bool)
+
+	// UpdateMirrorCollectors must be called when the identity of a local
+	// endpoint has changed, so that endpoints mirroring traffic to it are
+	// updated. Must be called without any endpoint lock held.
+	UpdateMirrorCollectors()
 }
 
 // Request is used to create the endpoint's request and send it to the endpoints
@@ -28,6 +28,7 @@ import (
 	"github.com/cilium/cilium/pkg/completion"
 	"github.com/cilium/cilium/pkg/controller"
 	endpointid "github.com/cilium/cilium/pkg/endpoint/id"
+	"github.com/cilium/cilium/pkg/hooks"
 	identityPkg "github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/ipcache"
 	"github.com/cilium/cilium/pkg/k8s"
@@ -65,7 +66,8 @@ var (
 // update the datapath to implement the new behavior.
 type RegenerationContext struct {
 	// Reason provides context to source for the regeneration, which is
-	// used to generate useful log messages.
+	// used to generate useful log messages. It is also used as label of
+	// the regeneration metrics and must therefore be a constant string.
 	Reason string
 
 	// ReloadDatapath forces the datapath programs to be reloaded. It does
@@ -218,8 +220,9 @@ func (e *Endpoint) resolveL4Policy(repo *policy.Repository) (policyChanged bool,
 
 	// ingressPolicy encodes whether any rules select this endpoint at all on
 	// ingress. If no rules select it, no need to iterate over policy repository
-	// to check if policy applies.
-	if !e.ingressPolicyEnabled {
+	// to check if policy applies. A quarantined endpoint has no L4 policy
+	// regardless of the rules in the repository.
+	if !e.ingressPolicyEnabled || e.Quarantined {
 		newL4IngressPolicy = &policy.L4PolicyMap{}
 	} else {
 		newL4IngressPolicy, err = repo.ResolveL4IngressPolicy(&ingressCtx)
@@ -231,7 +234,7 @@ func (e *Endpoint) resolveL4Policy(repo *policy.Repository) (policyChanged bool,
 	// egressPolicy encodes whether any rules select this endpoint at all on
 	// egress. If no rules select it, no need to iterate over policy repository
 	// to check if policy applies.
-	if !e.egressPolicyEnabled {
+	if !e.egressPolicyEnabled || e.Quarantined {
 		newL4EgressPolicy = &policy.L4PolicyMap{}
 	} else {
 		newL4EgressPolicy, err = repo.ResolveL4EgressPolicy(&egressCtx)
@@ -240,6 +243,10 @@ func (e *Endpoint) resolveL4Policy(repo *policy.Repository) (policyChanged bool,
 		}
 	}
 
+	if !e.Quarantined {
+		e.addDNSVisibility(*newL4EgressPolicy)
+	}
+
 	newL4Policy := &policy.L4Policy{Ingress: *newL4IngressPolicy,
 		Egress: *newL4EgressPolicy}
 
@@ -253,6 +260,12 @@ func (e *Endpoint) resolveL4Policy(repo *policy.Repository) (policyChanged bool,
 
 func (e *Endpoint) computeDesiredPolicyMapState(repo *policy.Repository) {
 	desiredPolicyKeys := make(PolicyMapState)
+	if e.Quarantined {
+		// Deny all traffic of the endpoint, including traffic from
+		// and to the local host.
+		e.desiredMapState = desiredPolicyKeys
+		return
+	}
 	e.computeDesiredL4PolicyMapEntries(desiredPolicyKeys)
 	e.determineAllowLocalhost(desiredPolicyKeys)
 	e.determineAllowFromWorld(desiredPolicyKeys)
@@ -397,6 +410,32 @@ func (e *Endpoint) regenerateL3Policy(repo *policy.Repository) (bool, error) {
 	return valid, err
 }
 
+// regenerateMirrorPolicy calculates the packet mirroring configuration of the
+// endpoint from the set of rules in the repository. Returns true if the
+// configuration changed.
+// Must be called with repo Mutex held for reading, e.Mutex held for writing.
+func (e *Endpoint) regenerateMirrorPolicy(repo *policy.Repository) bool {
+	ctx := policy.SearchContext{
+		To: e.SecurityIdentity.LabelArray,
+	}
+	if option.Config.TracingEnabled() {
+		ctx.Trace = policy.TRACE_ENABLED
+	}
+	newMirrorPolicy := repo.ResolveMirrorPolicy(&ctx)
+
+	if newMirrorPolicy != nil && len(newMirrorPolicy.IgnoredRules) > 0 {
+		e.logStatusLocked(Policy, Warning, fmt.Sprintf(
+			"Ignoring mirror rules %s, only a single collector is supported per endpoint",
+			newMirrorPolicy.IgnoredRules))
+	}
+
+	if reflect.DeepEqual(e.MirrorPolicy, newMirrorPolicy) {
+		return false
+	}
+	e.MirrorPolicy = newMirrorPolicy
+	return true
+}
+
 // Note that this function assumes that endpoint policy has already been generated!
 // must be called with endpoint.Mutex held for reading
 func (e *Endpoint) updateNetworkPolicy(owner Owner, proxyWaitGroup *completion.WaitGroup) (reterr error, revertFunc revert.RevertFunc) {
@@ -552,6 +591,11 @@ func (e *Endpoint) regeneratePolicy(owner Owner) error {
 		e.getLogger().Debug("regeneration of L3 (CIDR) policy caused policy change")
 	}
 
+	mirrorPolicyChanged := e.regenerateMirrorPolicy(repo)
+	if mirrorPolicyChanged {
+		e.getLogger().Debug("regeneration of mirror policy caused policy change")
+	}
+
 	// no failures after this point
 	// Note - endpoint policy enforcement must be determined BEFORE this function!
 	e.computeDesiredPolicyMapState(repo)
@@ -569,7 +613,7 @@ func (e *Endpoint) regeneratePolicy(owner Owner) error {
 	// If no policy or options change occurred for this endpoint then the endpoint is
 	// already running the latest revision, otherwise we have to wait for
 	// the regeneration of the endpoint to complete.
-	policyChanged := l3PolicyChanged || l4PolicyChanged
+	policyChanged := l3PolicyChanged || l4PolicyChanged || mirrorPolicyChanged
 
 	e.getLogger().WithFields(logrus.Fields{
 		"policyChanged":                  policyChanged,
@@ -598,10 +642,26 @@ func (e *Endpoint) updateAndOverrideEndpointOptions(opts option.OptionMap) (opts
 	}
 	// Apply possible option changes before regenerating maps, as map regeneration
 	// depends on the conntrack options
-	if e.DesiredL4Policy != nil {
-		if e.DesiredL4Policy.RequiresConntrack() {
-			opts[option.Conntrack] = option.OptionEnabled
+	bypass := e.Options.IsEnabled(option.ConntrackBypass)
+	if val, ok := opts[option.ConntrackBypass]; ok {
+		if bypass && val == option.OptionDisabled {
+			// Fall back to the daemon wide conntrack setting when the
+			// bypass is turned off again
+			opts[option.Conntrack] = option.Config.Opts.GetValue(option.Conntrack)
 		}
+		bypass = val != option.OptionDisabled
+	}
+
+	switch {
+	case bypass && e.DesiredL4Policy.HasRedirect():
+		// L7 proxy redirects rely on conntrack to direct replies back
+		// to the proxy, conntrack can't be bypassed.
+		e.logStatusLocked(Policy, Warning, "Unable to bypass conntrack for endpoint with L7 policy")
+		opts[option.Conntrack] = option.OptionEnabled
+	case bypass:
+		opts[option.Conntrack] = option.OptionDisabled
+	case e.DesiredL4Policy.RequiresConntrack():
+		opts[option.Conntrack] = option.OptionEnabled
 	}
 
 	optsChanged = e.applyOptsLocked(opts)
@@ -614,6 +674,12 @@ func (e *Endpoint) updateAndOverrideEndpointOptions(opts option.OptionMap) (opts
 //
 // Must be called with endpoint and repo mutexes held for reading.
 func (e *Endpoint) ComputePolicyEnforcement(repo *policy.Repository) (ingress bool, egress bool) {
+	// Policy is always enforced for a quarantined endpoint, regardless of
+	// the daemon level configuration.
+	if e.Quarantined {
+		return true, true
+	}
+
 	// Check if policy enforcement should be enabled at the daemon level.
 	switch policy.GetPolicyEnabled() {
 	case option.AlwaysEnforce:
@@ -644,7 +710,7 @@ func (e *Endpoint) regenerate(owner Owner, context *RegenerationContext) (retErr
 	var compilationExecuted bool
 	var err error
 
-	context.Stats = regenerationStatistics{}
+	context.Stats = regenerationStatistics{trigger: context.Reason}
 	stats := &context.Stats
 	metrics.EndpointCountRegenerating.Inc()
 	stats.totalTime.Start()
@@ -672,6 +738,7 @@ func (e *Endpoint) regenerate(owner Owner, context *RegenerationContext) (retErr
 			"proxyPolicyCalculation": stats.proxyPolicyCalculation.Total(),
 			"proxyWaitForAck":        stats.proxyWaitForAck.Total(),
 			"bpfCompilation":         stats.bpfCompilation.Total(),
+			"headerWrite":            stats.headerWrite.Total(),
 			"mapSync":                stats.mapSync.Total(),
 			"prepareBuild":           stats.prepareBuild.Total(),
 			logfields.BuildDuration:  stats.totalTime.Total(),
@@ -795,6 +862,7 @@ func (e *Endpoint) regenerate(owner Owner, context *RegenerationContext) (retErr
 	// Keep PolicyMap for this endpoint in sync with desired / realized state.
 	if !option.Config.DryMode {
 		e.syncPolicyMapController()
+		e.egressRateController()
 	}
 
 	e.RealizedL4Policy = e.DesiredL4Policy
@@ -894,7 +962,8 @@ func (e *Endpoint) runIdentityToK8sPodSync() {
 
 				return nil
 			},
-			RunInterval: 1 * time.Minute,
+			RunInterval:           1 * time.Minute,
+			ErrorRetryExponential: true,
 		},
 	)
 }
@@ -907,17 +976,27 @@ func (e *Endpoint) FormatGlobalEndpointID() string {
 	return strings.Join(metadata, ":")
 }
 
+// ipIdentitySyncControllerName returns the name of the controller
+// synchronizing the IP to identity mapping of endpointIP. The controller of an
+// additional IP of the endpoint is named after the IP as there may be several
+// of them per address family.
+func (e *Endpoint) ipIdentitySyncControllerName(endpointIP addressing.CiliumIP, additional bool) string {
+	addressFamily := endpointIP.GetFamilyString()
+	if additional {
+		return fmt.Sprintf("sync-%s-identity-mapping-%s (%d)", addressFamily, endpointIP.String(), e.ID)
+	}
+	return fmt.Sprintf("sync-%s-identity-mapping (%d)", addressFamily, e.ID)
+}
+
 // This synchronizes the key-value store with a mapping of the endpoint's IP
 // with the numerical ID representing its security identity.
-func (e *Endpoint) runIPIdentitySync(endpointIP addressing.CiliumIP) {
+func (e *Endpoint) runIPIdentitySync(endpointIP addressing.CiliumIP, additional bool) {
 
 	if endpointIP == nil {
 		return
 	}
 
-	addressFamily := endpointIP.GetFamilyString()
-
-	e.controllers.UpdateController(fmt.Sprintf("sync-%s-identity-mapping (%d)", addressFamily, e.ID),
+	e.controllers.UpdateController(e.ipIdentitySyncControllerName(endpointIP, additional),
 		controller.ControllerParams{
 			DoFunc: func() error {
 
@@ -958,11 +1037,23 @@ func (e *Endpoint) runIPIdentitySync(endpointIP addressing.CiliumIP) {
 				}
 				return nil
 			},
-			RunInterval: 5 * time.Minute,
+			RunInterval:           5 * time.Minute,
+			ErrorRetryExponential: true,
 		},
 	)
 }
 
+// stopIPIdentitySync stops the synchronization of the IP to identity mapping
+// of the additional IP endpointIP and deletes the mapping from the key-value
+// store.
+func (e *Endpoint) stopIPIdentitySync(endpointIP addressing.CiliumIP) {
+	ctrlName := e.ipIdentitySyncControllerName(endpointIP, true)
+	if err := e.controllers.RemoveController(ctrlName); err != nil {
+		e.getLogger().WithError(err).WithField(logfields.IPAddr, endpointIP.String()).
+			Debug("No IP to identity mapping to stop")
+	}
+}
+
 // SetIdentity resets endpoint's policy identity to 'id'.
 // Caller triggers policy regeneration if needed.
 // Called with e.Mutex Locked
@@ -991,8 +1082,18 @@ func (e *Endpoint) SetIdentity(identity *identityPkg.Identity) {
 
 	// Whenever the identity is updated, propagate change to key-value store
 	// of IP to identity mapping.
-	e.runIPIdentitySync(e.IPv4)
-	e.runIPIdentitySync(e.IPv6)
+	e.runIPIdentitySync(e.IPv4, false)
+	e.runIPIdentitySync(e.IPv6, false)
+	for _, ip := range e.AdditionalIPv4 {
+		e.runIPIdentitySync(ip, true)
+	}
+	for _, ip := range e.AdditionalIPv6 {
+		e.runIPIdentitySync(ip, true)
+	}
+
+	if hooks.Enabled() {
+		hooks.Notify(hooks.EventEndpointIdentityChange, e.GetModelRLocked())
+	}
 
 	e.getLogger().WithFields(logrus.Fields{
 		logfields.Identity:       identity.StringID(),
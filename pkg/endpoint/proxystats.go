@@ -15,6 +15,7 @@
 package endpoint
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/cilium/cilium/api/v1/models"
@@ -51,3 +52,29 @@ func sortProxyStats(proxyStats []*models.ProxyStatistics) {
 		return false
 	})
 }
+
+// getProxyRedirectSyncHealthModel returns the proxy synchronization health
+// caused by the first of the given redirects that is not ready to handle
+// traffic, or nil if all redirects are ready.
+func getProxyRedirectSyncHealthModel(redirects []*models.ProxyRedirectHealth) *models.EndpointSubsystemHealth {
+	for _, r := range redirects {
+		switch {
+		case !r.ListenerBound && r.LastError != "":
+			return &models.EndpointSubsystemHealth{
+				Status: models.EndpointSubsystemHealthStatusFailure,
+				Cause:  fmt.Sprintf("Proxy redirect %s is not listening on port %d: %s", r.ID, r.ProxyPort, r.LastError),
+			}
+		case !r.ListenerBound:
+			return &models.EndpointSubsystemHealth{
+				Status: models.EndpointSubsystemHealthStatusDegraded,
+				Cause:  fmt.Sprintf("Proxy redirect %s is not listening on port %d yet", r.ID, r.ProxyPort),
+			}
+		case !r.RulesLoaded:
+			return &models.EndpointSubsystemHealth{
+				Status: models.EndpointSubsystemHealthStatusDegraded,
+				Cause:  fmt.Sprintf("Proxy redirect %s has not loaded its rules yet", r.ID),
+			}
+		}
+	}
+	return nil
+}
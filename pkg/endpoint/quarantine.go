@@ -0,0 +1,104 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"fmt"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+// QuarantineError is the error returned when the quarantine of an endpoint
+// could not be enforced in the datapath.
+type QuarantineError struct {
+	msg string
+}
+
+func (e QuarantineError) Error() string { return e.msg }
+
+// Quarantine quarantines the endpoint or lifts its quarantine. All traffic
+// of a quarantined endpoint is denied regardless of the rules in the policy
+// repository. The endpoint is regenerated and the function blocks until the
+// regeneration has completed.
+//
+// If the regeneration fails, the endpoint remains marked accordingly and the
+// new policy is enforced by the next successful regeneration.
+func (e *Endpoint) Quarantine(owner Owner, quarantined bool, reason string) error {
+	if err := e.LockAlive(); err != nil {
+		return err
+	}
+
+	if !quarantined {
+		reason = ""
+	}
+
+	if e.Quarantined == quarantined && e.Status.CurrentStatus() == OK {
+		e.QuarantineReason = reason
+		e.Unlock()
+		return nil
+	}
+
+	e.Quarantined = quarantined
+	e.QuarantineReason = reason
+	e.forcePolicyCompute = true
+
+	var msg string
+	if quarantined {
+		msg = "Endpoint quarantined, denying all traffic"
+		if reason != "" {
+			msg = fmt.Sprintf("%s: %s", msg, reason)
+		}
+		e.logStatusLocked(Policy, Warning, msg)
+	} else {
+		msg = "Endpoint quarantine lifted"
+		e.logStatusLocked(Policy, OK, msg)
+	}
+
+	e.getLogger().WithFields(logrus.Fields{
+		"quarantined":    quarantined,
+		logfields.Reason: reason,
+	}).Info(msg)
+
+	// The regeneration reason is used as metrics label and must not
+	// contain the user provided reason.
+	regenerated, err := e.regenerateWhenReady(owner, "quarantine of endpoint updated via API")
+	if err != nil {
+		return err
+	}
+
+	if success := <-regenerated; !success {
+		return QuarantineError{fmt.Sprintf("regeneration of endpoint %d failed; check `cilium endpoint log %d` for more information", e.ID, e.ID)}
+	}
+
+	return nil
+}
+
+// getQuarantineModel returns the quarantine of the endpoint, nil if the
+// endpoint is not quarantined.
+//
+// Must be called with e.Mutex locked.
+func (e *Endpoint) getQuarantineModel() *models.EndpointQuarantine {
+	if !e.Quarantined {
+		return nil
+	}
+
+	return &models.EndpointQuarantine{
+		Enabled: true,
+		Reason:  e.QuarantineReason,
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	identityPkg "github.com/cilium/cilium/pkg/identity"
+	pkgLabels "github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/policy"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *EndpointSuite) TestQuarantinePolicy(c *C) {
+	oldEnforcement := policy.GetPolicyEnabled()
+	defer policy.SetPolicyEnabled(oldEnforcement)
+	policy.SetPolicyEnabled(option.NeverEnforce)
+
+	oldAllowLocalhost := option.Config.AllowLocalhost
+	defer func() { option.Config.AllowLocalhost = oldAllowLocalhost }()
+	option.Config.AllowLocalhost = option.AllowLocalhostAlways
+
+	lbls := pkgLabels.Labels{"foo": pkgLabels.NewLabel("foo", "", pkgLabels.LabelSourceK8s)}
+	identityCache := identityPkg.IdentityCache{
+		1000: lbls.LabelArray(),
+		1001: lbls.LabelArray(),
+	}
+	e := NewEndpointWithState(42, StateReady)
+	e.SecurityIdentity = identityPkg.NewIdentity(1000, lbls)
+	e.prevIdentityCache = &identityCache
+	repo := policy.NewPolicyRepository()
+
+	// Without enforcement, all identities and the local host are allowed
+	e.ingressPolicyEnabled, e.egressPolicyEnabled = e.ComputePolicyEnforcement(repo)
+	c.Assert(e.ingressPolicyEnabled, Equals, false)
+	c.Assert(e.egressPolicyEnabled, Equals, false)
+	e.computeDesiredPolicyMapState(repo)
+	c.Assert(len(e.desiredMapState), Equals, 5)
+	c.Assert(e.getQuarantineModel(), IsNil)
+
+	// A quarantined endpoint enforces policy and denies all traffic
+	e.Quarantined = true
+	e.QuarantineReason = "compromised"
+	e.ingressPolicyEnabled, e.egressPolicyEnabled = e.ComputePolicyEnforcement(repo)
+	c.Assert(e.ingressPolicyEnabled, Equals, true)
+	c.Assert(e.egressPolicyEnabled, Equals, true)
+	_, err := e.resolveL4Policy(repo)
+	c.Assert(err, IsNil)
+	c.Assert(len(e.DesiredL4Policy.Ingress), Equals, 0)
+	c.Assert(len(e.DesiredL4Policy.Egress), Equals, 0)
+	e.computeDesiredPolicyMapState(repo)
+	c.Assert(len(e.desiredMapState), Equals, 0)
+
+	mdl := e.getQuarantineModel()
+	c.Assert(mdl, Not(IsNil))
+	c.Assert(mdl.Enabled, Equals, true)
+	c.Assert(mdl.Reason, Equals, "compromised")
+}
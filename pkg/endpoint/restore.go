@@ -15,7 +15,20 @@
 package endpoint
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/option"
+
+	go_version "github.com/hashicorp/go-version"
 )
 
 // deprecatedOptions represents the 'Opts' field in the Endpoint structure from
@@ -44,10 +57,201 @@ func convertOptions(opts option.OptionMap) map[string]bool {
 	return result
 }
 
+// transformOptionsForDowngrade populates the 'Opts' field which is read by
+// Cilium 1.1 or earlier.
+func transformOptionsForDowngrade(ep *Endpoint) {
+	ep.DeprecatedOpts.Opts = convertOptions(ep.Options.Opts)
+}
+
+// DowngradeTransformer modifies an endpoint which can be restored by a
+// version of Cilium so that it can also be restored by the version preceding
+// it.
+type DowngradeTransformer func(ep *Endpoint)
+
+// downgradeStep is a transformer making endpoints compatible with the target
+// version of Cilium.
+type downgradeStep struct {
+	target    string
+	version   *go_version.Version
+	transform DowngradeTransformer
+}
+
+// downgradeRegistry holds the downgrade transformers ordered by decreasing
+// target version.
+type downgradeRegistry struct {
+	mutex lock.RWMutex
+	steps []downgradeStep
+}
+
+func newDowngradeRegistry() *downgradeRegistry {
+	return &downgradeRegistry{}
+}
+
+var downgradeTransformers = newDowngradeRegistry()
+
+func init() {
+	RegisterDowngradeTransformer("1.1", transformOptionsForDowngrade)
+}
+
+// RegisterDowngradeTransformer registers the transformer making endpoints
+// compatible with the target version of Cilium. It panics if target is not a
+// valid version or if a transformer is already registered for target.
+func RegisterDowngradeTransformer(target string, t DowngradeTransformer) {
+	if err := downgradeTransformers.register(target, t); err != nil {
+		panic(err)
+	}
+}
+
+func (r *downgradeRegistry) register(target string, t DowngradeTransformer) error {
+	version, err := go_version.NewVersion(target)
+	if err != nil {
+		return fmt.Errorf("invalid downgrade target version %q: %s", target, err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, step := range r.steps {
+		if step.version.Equal(version) {
+			return fmt.Errorf("downgrade transformer for version %s already registered", target)
+		}
+	}
+	r.steps = append(r.steps, downgradeStep{target: target, version: version, transform: t})
+	sort.Slice(r.steps, func(i, j int) bool {
+		return r.steps[j].version.LessThan(r.steps[i].version)
+	})
+	return nil
+}
+
+// oldest returns the oldest version a transformer is registered for, or an
+// empty string if no transformers are registered.
+func (r *downgradeRegistry) oldest() string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if len(r.steps) == 0 {
+		return ""
+	}
+	return r.steps[len(r.steps)-1].target
+}
+
+// chain returns the transformers to apply in order to make an endpoint
+// compatible with the target version, starting with the transformer for the
+// newest version.
+func (r *downgradeRegistry) chain(target string) ([]DowngradeTransformer, error) {
+	version, err := go_version.NewVersion(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid downgrade target version %q: %s", target, err)
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if n := len(r.steps); n > 0 && version.LessThan(r.steps[n-1].version) {
+		return nil, fmt.Errorf("downgrade to version %s is not supported, oldest supported version is %s",
+			target, r.steps[n-1].target)
+	}
+
+	chain := []DowngradeTransformer{}
+	for _, step := range r.steps {
+		if step.version.LessThan(version) {
+			break
+		}
+		chain = append(chain, step.transform)
+	}
+	return chain, nil
+}
+
+// TransformEndpointForDowngrade modifies the specified endpoint so that when
+// the endpoint is serialized, the target version of Cilium will understand
+// the format.
+func TransformEndpointForDowngrade(ep *Endpoint, target string) error {
+	chain, err := downgradeTransformers.chain(target)
+	if err != nil {
+		return err
+	}
+	for _, t := range chain {
+		t(ep)
+	}
+	return nil
+}
+
 // transformEndpointForDowngrade modifies the specified endpoint to populate
-// deprecated fields so that when the endpoint is serialized, an older version
-// of Cilium will understand the format. This allows safe downgrade from this
-// version to an older version.
+// deprecated fields so that when the endpoint is serialized, the oldest
+// version of Cilium supported by the downgrade transformers will understand
+// the format. This allows safe downgrade from this version to an older
+// version.
 func transformEndpointForDowngrade(ep *Endpoint) {
-	ep.DeprecatedOpts.Opts = convertOptions(ep.Options.Opts)
+	if oldest := downgradeTransformers.oldest(); oldest != "" {
+		TransformEndpointForDowngrade(ep, oldest)
+	}
+}
+
+// DowngradeHeaderfile transforms the endpoint serialized in the C header file
+// at path so that it can be restored by the target version of Cilium.
+func DowngradeHeaderfile(path, target string) error {
+	_, err := downgradeHeaderfile(path, target, true)
+	return err
+}
+
+// HeaderfileNeedsDowngrade returns true if the endpoint serialized in the C
+// header file at path must be transformed with DowngradeHeaderfile before it
+// can be restored by the target version of Cilium.
+func HeaderfileNeedsDowngrade(path, target string) (bool, error) {
+	return downgradeHeaderfile(path, target, false)
+}
+
+// downgradeHeaderfile applies the downgrade transformers for target to the
+// endpoint serialized in the C header file at path and returns whether they
+// modified the endpoint. The header file is only rewritten if write is true.
+func downgradeHeaderfile(path, target string, write bool) (bool, error) {
+	chain, err := downgradeTransformers.chain(target)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if !strings.Contains(line, common.CiliumCHeaderPrefix) {
+			continue
+		}
+
+		sep := strings.LastIndex(line, ":")
+		if sep < 0 {
+			return false, fmt.Errorf("invalid format %q. Should contain a single ':'", line)
+		}
+
+		var ep Endpoint
+		if err := parseBase64ToEndpoint(line[sep+1:], &ep); err != nil {
+			return false, fmt.Errorf("failed to parse base64toendpoint: %s", err)
+		}
+		origBytes, err := json.Marshal(&ep)
+		if err != nil {
+			return false, err
+		}
+		for _, t := range chain {
+			t(&ep)
+		}
+		jsonBytes, err := json.Marshal(&ep)
+		if err != nil {
+			return false, err
+		}
+
+		changed := !bytes.Equal(origBytes, jsonBytes)
+		if !write {
+			return changed, nil
+		}
+
+		lines[i] = line[:sep+1] + base64.StdEncoding.EncodeToString(jsonBytes)
+		return changed, ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode())
+	}
+
+	return false, fmt.Errorf("no endpoint found in %s", path)
 }
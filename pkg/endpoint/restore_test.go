@@ -15,6 +15,13 @@
 package endpoint
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/cilium/cilium/common"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -32,3 +39,98 @@ func (s *EndpointSuite) TesttransformEndpointForDowngrade(c *C) {
 	_, exists := e.DeprecatedOpts.Opts["baz"]
 	c.Assert(exists, Equals, false)
 }
+
+func (s *EndpointSuite) TestDowngradeRegistryChain(c *C) {
+	var applied []string
+	transformer := func(version string) DowngradeTransformer {
+		return func(ep *Endpoint) { applied = append(applied, version) }
+	}
+
+	r := newDowngradeRegistry()
+	c.Assert(r.register("1.1", transformer("1.1")), IsNil)
+	c.Assert(r.register("1.3", transformer("1.3")), IsNil)
+	c.Assert(r.register("1.2", transformer("1.2")), IsNil)
+	c.Assert(r.register("1.2", transformer("1.2")), NotNil)
+	c.Assert(r.register("foo", transformer("foo")), NotNil)
+	c.Assert(r.oldest(), Equals, "1.1")
+
+	for _, t := range []struct {
+		target  string
+		applied []string
+	}{
+		{"1.4", nil},
+		{"1.3", []string{"1.3"}},
+		{"1.2", []string{"1.3", "1.2"}},
+		{"1.1", []string{"1.3", "1.2", "1.1"}},
+	} {
+		applied = nil
+		chain, err := r.chain(t.target)
+		c.Assert(err, IsNil)
+		for _, transform := range chain {
+			transform(nil)
+		}
+		c.Assert(applied, DeepEquals, t.applied, Commentf("target %s", t.target))
+	}
+
+	_, err := r.chain("1.0")
+	c.Assert(err, ErrorMatches, "downgrade to version 1.0 is not supported.*")
+	_, err = r.chain("foo")
+	c.Assert(err, NotNil)
+}
+
+func (s *EndpointSuite) TestDowngradeHeaderfile(c *C) {
+	e := NewEndpointWithState(42, StateReady)
+	e.Options.Opts["foo"] = 1
+	jsonBytes, err := json.Marshal(e)
+	c.Assert(err, IsNil)
+
+	path := filepath.Join(c.MkDir(), common.CHeaderFileName)
+	header := "/*\n * " + common.CiliumCHeaderPrefix + "dmVyc2lvbg==:" +
+		base64.StdEncoding.EncodeToString(jsonBytes) + "\n * \n */\n"
+	c.Assert(ioutil.WriteFile(path, []byte(header), 0644), IsNil)
+
+	c.Assert(DowngradeHeaderfile(path, "1.1"), IsNil)
+
+	strEp, err := common.GetCiliumVersionString(path)
+	c.Assert(err, IsNil)
+	c.Assert(strEp, Matches, " \\* "+common.CiliumCHeaderPrefix+"dmVyc2lvbg==:.*\n")
+	restored, err := ParseEndpoint(strEp)
+	c.Assert(err, IsNil)
+	c.Assert(restored.ID, Equals, uint16(42))
+	c.Assert(restored.DeprecatedOpts.Opts["foo"], Equals, true)
+
+	c.Assert(DowngradeHeaderfile(path, "1.0"), NotNil)
+}
+
+func (s *EndpointSuite) TestHeaderfileNeedsDowngrade(c *C) {
+	e := NewEndpointWithState(42, StateReady)
+	e.Options.Opts["foo"] = 1
+	jsonBytes, err := json.Marshal(e)
+	c.Assert(err, IsNil)
+
+	path := filepath.Join(c.MkDir(), common.CHeaderFileName)
+	header := "/*\n * " + common.CiliumCHeaderPrefix + "dmVyc2lvbg==:" +
+		base64.StdEncoding.EncodeToString(jsonBytes) + "\n * \n */\n"
+	c.Assert(ioutil.WriteFile(path, []byte(header), 0644), IsNil)
+
+	needed, err := HeaderfileNeedsDowngrade(path, "1.2")
+	c.Assert(err, IsNil)
+	c.Assert(needed, Equals, false)
+
+	needed, err = HeaderfileNeedsDowngrade(path, "1.1")
+	c.Assert(err, IsNil)
+	c.Assert(needed, Equals, true)
+
+	// The header file must not be modified by the check.
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, header)
+
+	c.Assert(DowngradeHeaderfile(path, "1.1"), IsNil)
+	needed, err = HeaderfileNeedsDowngrade(path, "1.1")
+	c.Assert(err, IsNil)
+	c.Assert(needed, Equals, false)
+
+	_, err = HeaderfileNeedsDowngrade(path, "1.0")
+	c.Assert(err, NotNil)
+}
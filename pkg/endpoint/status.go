@@ -35,6 +35,7 @@ type StatusType int
 
 const (
 	BPF    StatusType = 200
+	Proxy  StatusType = 150
 	Policy StatusType = 100
 	Other  StatusType = 0
 )
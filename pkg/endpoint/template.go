@@ -0,0 +1,293 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+
+	"github.com/cilium/cilium/common"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/datapath/loader"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/maps/policymap"
+	"github.com/cilium/cilium/pkg/maps/throttlemap"
+	"github.com/cilium/cilium/pkg/option"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// templateEndpointID is the endpoint ID used in the names of the maps
+	// and sections of template objects. It is larger than any endpoint ID
+	// so that the names can be renamed in place for any endpoint.
+	templateEndpointID = 0xffff
+
+	// templatesDir is the directory in the state directory holding the
+	// compiled templates.
+	templatesDir = "templates"
+
+	// policySectionFmt is the format of the name of the section holding
+	// the policy program of an endpoint, see CILIUM_MAP_POLICY in
+	// bpf/lib/maps.h.
+	policySectionFmt = "1/%#x"
+)
+
+// templateEndpoint is the stand-in for the endpoint in the conntrack map
+// names of template objects.
+type templateEndpoint struct{}
+
+// StringID returns the endpoint ID of templates in a string.
+func (templateEndpoint) StringID() string {
+	return strconv.Itoa(templateEndpointID)
+}
+
+// templatesEnabled returns true if endpoint BPF programs are to be
+// instantiated from templates. The debug outputs of the compilation are
+// only created by a full compilation, which is why templates are not used
+// while debugging the compilation.
+func templatesEnabled() bool {
+	return viper.GetBool(option.BPFCompileTemplatesName) &&
+		!viper.GetBool(option.BPFCompileDebugName)
+}
+
+// templateInfo describes how to instantiate the BPF program of an endpoint
+// from a template.
+type templateInfo struct {
+	// header is the header file the template is compiled from
+	header []byte
+
+	// intOptions and strOptions are the endpoint specific values to
+	// substitute in the template, see elf.ELF.Write()
+	intOptions map[string]uint32
+	strOptions map[string]string
+}
+
+// createTemplateInfo returns the information necessary to instantiate the
+// BPF program of the endpoint from a template.
+// Must be called with e.Mutex held.
+func (e *Endpoint) createTemplateInfo(owner Owner) *templateInfo {
+	buf := &bytes.Buffer{}
+	fw := bufio.NewWriter(buf)
+	e.writeTemplateStaticData(fw)
+	e.writeConfig(fw, owner, templateEndpoint{})
+	fw.Flush()
+
+	info := &templateInfo{header: buf.Bytes()}
+	info.intOptions, info.strOptions = e.templateOptions()
+	return info
+}
+
+// writeTemplateStaticData writes the declarations of the endpoint specific
+// values of a template into the header file, see
+// bpf/include/bpf/static_data.h. Must be kept in sync with writeStaticData()
+// and templateOptions().
+func (e *Endpoint) writeTemplateStaticData(fw *bufio.Writer) {
+	fw.WriteString("#include <bpf/static_data.h>\n")
+	fw.WriteString("DEFINE_MAC(LXC_MAC);\n")
+	fw.WriteString("#define LXC_MAC fetch_mac(LXC_MAC)\n")
+	fw.WriteString("DEFINE_IPV6(LXC_IP);\n")
+	fw.WriteString("#define LXC_IP fetch_ipv6(LXC_IP)\n")
+	if e.IPv4 != nil {
+		fw.WriteString("DEFINE_U32(LXC_IPV4);\n")
+		fw.WriteString("#define LXC_IPV4 fetch_u32(LXC_IPV4)\n")
+	}
+	if e.HasAdditionalIPs() {
+		fw.WriteString("#define ENABLE_MULTI_IP 1\n")
+	}
+	fw.WriteString("DEFINE_MAC(NODE_MAC);\n")
+	fw.WriteString("#define NODE_MAC fetch_mac(NODE_MAC)\n")
+	for _, name := range []string{"LXC_ID", "LXC_ID_NB", "SECLABEL", "SECLABEL_NB"} {
+		fmt.Fprintf(fw, "DEFINE_U32(%s);\n", name)
+		fmt.Fprintf(fw, "#define %s fetch_u32(%s)\n", name, name)
+	}
+	fmt.Fprintf(fw, "#define TEMPLATE_LXC_ID %#x\n", templateEndpointID)
+	fmt.Fprintf(fw, "#define POLICY_MAP %s%d\n", policymap.MapName, templateEndpointID)
+	fmt.Fprintf(fw, "#define CALLS_MAP %s%d\n", CallsMapName, templateEndpointID)
+	fmt.Fprintf(fw, "#define THROTTLE_MAP %s%d\n", throttlemap.MapName, templateEndpointID)
+}
+
+// templateOptions returns the values of the endpoint to substitute in a
+// template written by writeTemplateStaticData().
+// Must be called with e.Mutex held.
+func (e *Endpoint) templateOptions() (map[string]uint32, map[string]string) {
+	intOptions := make(map[string]uint32)
+	addMAC := func(name string, mac []byte) {
+		if len(mac) != 6 {
+			mac = make([]byte, 6)
+		}
+		intOptions[name+"_1"] = byteorder.Native.Uint32(mac[0:4])
+		intOptions[name+"_2"] = uint32(byteorder.Native.Uint16(mac[4:6]))
+	}
+	addMAC("LXC_MAC", e.LXCMAC)
+	addMAC("NODE_MAC", e.NodeMAC)
+
+	ipv6 := make([]byte, 16)
+	copy(ipv6, e.IPv6)
+	for i := 0; i < 4; i++ {
+		intOptions[fmt.Sprintf("LXC_IP_%d", i+1)] = binary.BigEndian.Uint32(ipv6[i*4:])
+	}
+	if e.IPv4 != nil {
+		intOptions["LXC_IPV4"] = byteorder.HostSliceToNetwork(e.IPv4, reflect.Uint32).(uint32)
+	}
+
+	intOptions["LXC_ID"] = uint32(e.ID)
+	intOptions["LXC_ID_NB"] = uint32(byteorder.HostToNetwork(e.ID).(uint16))
+	secID := identity.InvalidIdentity
+	if e.SecurityIdentity != nil {
+		secID = e.SecurityIdentity.ID
+	}
+	intOptions["SECLABEL"] = secID.Uint32()
+	intOptions["SECLABEL_NB"] = byteorder.HostToNetwork(secID.Uint32()).(uint32)
+
+	strOptions := make(map[string]string)
+	for _, prefix := range []string{
+		policymap.MapName,
+		CallsMapName,
+		throttlemap.MapName,
+		ctmap.MapNameTCP6,
+		ctmap.MapNameTCP4,
+		ctmap.MapNameAny6,
+		ctmap.MapNameAny4,
+	} {
+		strOptions[prefix+templateEndpoint{}.StringID()] = prefix + e.StringID()
+	}
+	strOptions[fmt.Sprintf(policySectionFmt, templateEndpointID)] = fmt.Sprintf(policySectionFmt, e.ID)
+
+	return intOptions, strOptions
+}
+
+// compiledTemplate is a template object which is compiled at most once.
+type compiledTemplate struct {
+	// done is closed when the compilation has finished
+	done chan struct{}
+
+	// path and err are the result of the compilation, only valid after
+	// done has been closed
+	path string
+	err  error
+}
+
+// templateCache holds the templates compiled for all endpoint
+// configurations, keyed by the hash of the configuration.
+type templateCache struct {
+	mutex     lock.Mutex
+	templates map[string]*compiledTemplate
+}
+
+var templates = newTemplateCache()
+
+func newTemplateCache() *templateCache {
+	return &templateCache{
+		templates: make(map[string]*compiledTemplate),
+	}
+}
+
+// get returns the path of the template object compiled from the given header
+// file and the node configuration, compiling the template if no endpoint
+// with the same configuration has done so yet. Failed compilations are not
+// cached.
+func (c *templateCache) get(ctx context.Context, header []byte) (string, error) {
+	hash, err := hashTemplate(header)
+	if err != nil {
+		return "", err
+	}
+
+	c.mutex.Lock()
+	t, ok := c.templates[hash]
+	if !ok {
+		t = &compiledTemplate{done: make(chan struct{})}
+		c.templates[hash] = t
+	}
+	c.mutex.Unlock()
+
+	if !ok {
+		t.path, t.err = compileTemplate(ctx, hash, header)
+		if t.err != nil {
+			c.mutex.Lock()
+			delete(c.templates, hash)
+			c.mutex.Unlock()
+		}
+		close(t.done)
+	}
+
+	select {
+	case <-t.done:
+		return t.path, t.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// hashTemplate returns the MD5 hash of the template header file and the
+// node's headerfile.
+func hashTemplate(header []byte) (string, error) {
+	hashWriter := md5.New()
+	hashWriter.Write(header)
+	hashWriter, err := hashHeaderfile(hashWriter, option.Config.GetNodeConfigPath())
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hashWriter.Sum(nil)), nil
+}
+
+// compileTemplate compiles the template from the given header file in the
+// directory of the template with the given hash.
+func compileTemplate(ctx context.Context, hash string, header []byte) (string, error) {
+	dir := filepath.Join(option.Config.StateDir, templatesDir, hash)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("unable to create template directory %s: %s", dir, err)
+	}
+	headerPath := filepath.Join(dir, common.CHeaderFileName)
+	if err := ioutil.WriteFile(headerPath, header, 0644); err != nil {
+		return "", fmt.Errorf("failed to write template header file %s: %s", headerPath, err)
+	}
+
+	log.WithField(logfields.Path, dir).Info("Compiling endpoint BPF template")
+	return loader.CompileTemplate(ctx, dir)
+}
+
+// compileAndLoad loads the BPF program of the endpoint, instantiating it from
+// a template if possible and compiling it otherwise.
+func (e *Endpoint) compileAndLoad(ctx context.Context, epInfoCache *epInfoCache) error {
+	if info := epInfoCache.template; info != nil {
+		template, err := templates.get(ctx, info.header)
+		if err == nil {
+			err = loader.LoadFromTemplate(ctx, epInfoCache, template, info.intOptions, info.strOptions)
+		}
+		if err == nil {
+			return nil
+		}
+		e.getLogger().WithError(err).Warn("Unable to load endpoint BPF program from template, compiling it")
+	}
+
+	return loader.CompileAndLoad(ctx, epInfoCache)
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/cilium/cilium/common/addressing"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/mac"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *EndpointSuite) TestTemplateOptions(c *C) {
+	lxcMAC, err := mac.ParseMAC("01:02:03:04:05:06")
+	c.Assert(err, IsNil)
+	ipv6, err := addressing.NewCiliumIPv6("f00d::1:2")
+	c.Assert(err, IsNil)
+	ipv4, err := addressing.NewCiliumIPv4("10.0.0.1")
+	c.Assert(err, IsNil)
+
+	e := &Endpoint{
+		ID:               42,
+		LXCMAC:           lxcMAC,
+		IPv6:             ipv6,
+		IPv4:             ipv4,
+		SecurityIdentity: &identity.Identity{ID: 1234},
+	}
+
+	buf := &bytes.Buffer{}
+	fw := bufio.NewWriter(buf)
+	e.writeTemplateStaticData(fw)
+	fw.Flush()
+	header := buf.String()
+
+	intOptions, strOptions := e.templateOptions()
+
+	// Every endpoint specific value declared by the header has a value
+	hasValue := func(name string) {
+		_, ok := intOptions[name]
+		c.Assert(ok, Equals, true, Commentf("no value for %s", name))
+	}
+	for _, line := range strings.Split(header, "\n") {
+		if !strings.HasPrefix(line, "DEFINE_") {
+			continue
+		}
+		name := line[strings.Index(line, "(")+1 : strings.Index(line, ")")]
+		switch {
+		case strings.HasPrefix(line, "DEFINE_U32"):
+			hasValue(name)
+		case strings.HasPrefix(line, "DEFINE_MAC"):
+			hasValue(name + "_1")
+			hasValue(name + "_2")
+		case strings.HasPrefix(line, "DEFINE_IPV6"):
+			hasValue(name + "_4")
+		}
+	}
+
+	c.Assert(intOptions["LXC_MAC_1"], Equals, byteorder.Native.Uint32([]byte{1, 2, 3, 4}))
+	c.Assert(intOptions["LXC_MAC_2"], Equals, uint32(byteorder.Native.Uint16([]byte{5, 6})))
+	c.Assert(intOptions["NODE_MAC_1"], Equals, uint32(0))
+	c.Assert(intOptions["LXC_IP_1"], Equals, uint32(0xf00d0000))
+	c.Assert(intOptions["LXC_IP_4"], Equals, uint32(0x00010002))
+	c.Assert(intOptions["LXC_IPV4"], Equals, byteorder.Native.Uint32([]byte{10, 0, 0, 1}))
+	c.Assert(intOptions["LXC_ID"], Equals, uint32(42))
+	c.Assert(intOptions["SECLABEL"], Equals, uint32(1234))
+
+	c.Assert(header, Matches, "(?s).*#define POLICY_MAP cilium_policy_65535\n.*")
+	c.Assert(strOptions["cilium_policy_65535"], Equals, "cilium_policy_42")
+	c.Assert(strOptions["cilium_calls_65535"], Equals, "cilium_calls_42")
+	c.Assert(strOptions["1/0xffff"], Equals, "1/0x2a")
+
+	// The template does not depend on the endpoint
+	e.ID = 43
+	buf.Reset()
+	e.writeTemplateStaticData(fw)
+	fw.Flush()
+	c.Assert(buf.String(), Equals, header)
+}
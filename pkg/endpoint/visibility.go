@@ -0,0 +1,75 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// dnsVisibilityPort is the port of the DNS traffic which is redirected to the
+// DNS proxy if DNS visibility is enabled for an endpoint
+const dnsVisibilityPort = "53"
+
+// dnsVisibilityProtocols are the protocols of the DNS traffic which is
+// redirected to the DNS proxy if DNS visibility is enabled for an endpoint
+var dnsVisibilityProtocols = []api.L4Proto{api.ProtoUDP, api.ProtoTCP}
+
+// dnsVisibilityRules returns the L7 rules of a DNS visibility redirect, which
+// allow all DNS lookups
+func dnsVisibilityRules() api.L7Rules {
+	return api.L7Rules{DNS: []api.PortRuleDNS{{MatchPattern: "*"}}}
+}
+
+// addDNSVisibility adds a redirect of the DNS traffic of the endpoint to the
+// DNS proxy to the egress L4 policy if DNS visibility is enabled, without
+// changing which traffic is allowed.
+//
+// The egress program always looks up the policy map, and an entry for the
+// port of a destination takes precedence over the entry allowing all ports
+// of the destination. Without egress policy, the policy map allows all
+// destinations, so DNS traffic to all destinations is redirected. With
+// egress policy, only the DNS ports allowed by L4 rules are redirected, DNS
+// traffic allowed by L3 rules alone is not.
+// Must be called with e.Mutex held.
+func (e *Endpoint) addDNSVisibility(egress policy.L4PolicyMap) {
+	if !e.Options.IsEnabled(option.DNSVisibility) {
+		return
+	}
+
+	for _, proto := range dnsVisibilityProtocols {
+		key := dnsVisibilityPort + "/" + string(proto)
+		filter, ok := egress[key]
+		switch {
+		case !ok && !e.egressPolicyEnabled:
+			rules := dnsVisibilityRules()
+			egress[key] = policy.CreateL4EgressFilter(
+				api.EndpointSelectorSlice{api.WildcardEndpointSelector},
+				api.PortRule{Rules: &rules},
+				api.PortProtocol{Port: dnsVisibilityPort, Protocol: proto},
+				proto, nil)
+		case ok && filter.L7Parser == policy.ParserTypeNone:
+			// Filters with L7 rules of another parser are left
+			// alone, and DNS rules are already enforced by the proxy.
+			filter.L7Parser = policy.ParserTypeDNS
+			filter.L7RulesPerEp = make(policy.L7DataMap, len(filter.Endpoints))
+			for _, sel := range filter.Endpoints {
+				filter.L7RulesPerEp[sel] = dnsVisibilityRules()
+			}
+			egress[key] = filter
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpoint
+
+import (
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *EndpointSuite) TestDNSVisibility(c *C) {
+	e := NewEndpointWithState(42, StateReady)
+	fooSelector := api.NewESFromLabels()
+	fooSelector.MatchLabels = map[string]string{"any:foo": ""}
+
+	// Disabled visibility leaves the policy alone
+	egress := policy.L4PolicyMap{}
+	e.addDNSVisibility(egress)
+	c.Assert(len(egress), Equals, 0)
+
+	// Without egress policy, DNS traffic to all destinations is redirected
+	e.Options.SetBool(option.DNSVisibility, true)
+	e.addDNSVisibility(egress)
+	c.Assert(len(egress), Equals, 2)
+	for _, key := range []string{"53/UDP", "53/TCP"} {
+		filter := egress[key]
+		c.Assert(filter.L7Parser, Equals, policy.ParserTypeDNS)
+		c.Assert(filter.AllowsAllAtL3(), Equals, true)
+		c.Assert(filter.L7RulesPerEp[api.WildcardEndpointSelector], DeepEquals, dnsVisibilityRules())
+	}
+
+	// With egress policy, only DNS traffic allowed by L4 rules is
+	// redirected and DNS rules are preserved
+	e.egressPolicyEnabled = true
+	dnsRules := api.L7Rules{DNS: []api.PortRuleDNS{{MatchName: "example.com"}}}
+	egress = policy.L4PolicyMap{
+		"53/UDP": policy.L4Filter{
+			Port:      53,
+			Protocol:  api.ProtoUDP,
+			Endpoints: api.EndpointSelectorSlice{fooSelector},
+		},
+		"53/TCP": policy.L4Filter{
+			Port:         53,
+			Protocol:     api.ProtoTCP,
+			Endpoints:    api.EndpointSelectorSlice{fooSelector},
+			L7Parser:     policy.ParserTypeDNS,
+			L7RulesPerEp: policy.L7DataMap{fooSelector: dnsRules},
+		},
+	}
+	e.addDNSVisibility(egress)
+	c.Assert(len(egress), Equals, 2)
+	c.Assert(egress["53/UDP"].L7Parser, Equals, policy.ParserTypeDNS)
+	c.Assert(egress["53/UDP"].L7RulesPerEp, DeepEquals, policy.L7DataMap{fooSelector: dnsVisibilityRules()})
+	c.Assert(egress["53/TCP"].L7RulesPerEp, DeepEquals, policy.L7DataMap{fooSelector: dnsRules})
+
+	egress = policy.L4PolicyMap{}
+	e.addDNSVisibility(egress)
+	c.Assert(len(egress), Equals, 0)
+}
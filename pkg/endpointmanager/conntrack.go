@@ -18,9 +18,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/endpoint"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/maps/ctmap"
+	"github.com/cilium/cilium/pkg/maps/natmap"
+	"github.com/cilium/cilium/pkg/metrics"
 
 	"github.com/sirupsen/logrus"
 )
@@ -28,8 +31,84 @@ import (
 const (
 	// MinGcInterval is the minimum garbage collection interval.
 	MinGcInterval int = 5
+
+	// gcIntervalInitial is the interval after the first run of the
+	// adaptive garbage collection
+	gcIntervalInitial = time.Minute
+
+	// gcIntervalMin and gcIntervalMax bound the interval of the adaptive
+	// garbage collection
+	gcIntervalMin = 10 * time.Second
+	gcIntervalMax = 30 * time.Minute
+
+	// gcDeleteRatioHigh is the share of the capacity of a CT map deleted by
+	// a run above which the interval is halved
+	gcDeleteRatioHigh = 0.25
+
+	// gcDeleteRatioLow is the share of the capacity of a CT map deleted by
+	// a run below which the interval is increased
+	gcDeleteRatioLow = 0.05
+
+	// gcFillRatioHigh is the fill ratio of a non-LRU CT map above which
+	// the minimum interval is used as new connections are dropped once the
+	// map is full
+	gcFillRatioHigh = 0.9
 )
 
+// gcRound summarizes a round of garbage collection over several CT maps by
+// the highest ratios of any of the maps. The fill ratio only accounts for
+// non-LRU maps.
+type gcRound struct {
+	deleteRatio float64
+	fillRatio   float64
+}
+
+func (r *gcRound) add(result ctmap.GCResult) {
+	if ratio := result.DeleteRatio(); ratio > r.deleteRatio {
+		r.deleteRatio = ratio
+	}
+	// LRU maps evict old entries to make room for new connections, a
+	// full LRU map does not require an expedited run.
+	if ratio := result.FillRatio(); ratio > r.fillRatio && !result.LRU {
+		r.fillRatio = ratio
+	}
+}
+
+// initialGCInterval returns the interval until the first round of garbage
+// collection following the initial scan for the configured interval in
+// seconds, and whether the interval adapts to the usage of the maps. An
+// interval of 0 selects the adaptive interval.
+func initialGCInterval(gcinterval int) (time.Duration, bool) {
+	if gcinterval == 0 {
+		return gcIntervalInitial, true
+	}
+	if gcinterval < MinGcInterval {
+		gcinterval = MinGcInterval
+		log.Warnf("Setting conntrack garbage collector interval to its minimum value(%d seconds)", gcinterval)
+	}
+	return time.Duration(gcinterval) * time.Second, false
+}
+
+// nextGCInterval returns the interval until the next round of garbage
+// collection following a round with the given result and interval.
+func nextGCInterval(interval time.Duration, round gcRound) time.Duration {
+	switch {
+	case round.fillRatio >= gcFillRatioHigh:
+		interval = gcIntervalMin
+	case round.deleteRatio >= gcDeleteRatioHigh:
+		interval /= 2
+	case round.deleteRatio < gcDeleteRatioLow:
+		interval = interval * 3 / 2
+	}
+
+	if interval < gcIntervalMin {
+		interval = gcIntervalMin
+	} else if interval > gcIntervalMax {
+		interval = gcIntervalMax
+	}
+	return interval
+}
+
 // runGC run CT's garbage collector for the given endpoint. `isLocal` refers if
 // the CT map is set to local. If `isIPv6` is set specifies that is the IPv6
 // map. `filter` represents the filter type to be used while looping all CT
@@ -38,7 +117,9 @@ const (
 // The provided endpoint is optional; if it is provided, then its map will be
 // garbage collected and any failures will be logged to the endpoint log.
 // Otherwise it will garbage-collect the global map and use the global log.
-func runGC(e *endpoint.Endpoint, ipv4, ipv6 bool, filter *ctmap.GCFilter) {
+//
+// The results of all maps are added to round.
+func runGC(e *endpoint.Endpoint, ipv4, ipv6 bool, filter *ctmap.GCFilter, round *gcRound) {
 	var maps []*ctmap.Map
 
 	if e == nil {
@@ -60,17 +141,52 @@ func runGC(e *endpoint.Endpoint, ipv4, ipv6 bool, filter *ctmap.GCFilter) {
 		}
 		defer m.Close()
 
-		deleted := ctmap.GC(m, filter)
+		result := ctmap.GC(m, filter)
+		round.add(result)
 
-		if deleted > 0 {
+		if result.Deleted > 0 {
 			log.WithFields(logrus.Fields{
 				logfields.Path: path,
-				"count":        deleted,
+				"count":        result.Deleted,
 			}).Debug("Deleted filtered entries from map")
 		}
 	}
 }
 
+// runNatGC removes the entries of the NAT map of BPF masquerading whose
+// connections have expired. The result is added to round.
+func runNatGC(round *gcRound) {
+	m := natmap.GlobalMap()
+	path, err := m.Path()
+	if err == nil {
+		err = m.Open()
+	}
+	if err != nil {
+		log.WithError(err).WithField(logfields.Path, path).Warn("Unable to open map")
+		return
+	}
+	defer m.Close()
+
+	scanned, deleted, err := m.GC()
+	if err != nil {
+		log.WithError(err).WithField(logfields.Path, path).Warn("Unable to garbage collect NAT map")
+		return
+	}
+	round.add(ctmap.GCResult{
+		Scanned:    scanned,
+		Deleted:    deleted,
+		MaxEntries: int(m.MapInfo.MaxEntries),
+		LRU:        m.MapInfo.MapType == bpf.MapTypeLRUHash,
+	})
+
+	if deleted > 0 {
+		log.WithFields(logrus.Fields{
+			logfields.Path: path,
+			"count":        deleted,
+		}).Debug("Deleted expired entries from NAT map")
+	}
+}
+
 func createGCFilter(initialScan bool, restoredEndpoints []*endpoint.Endpoint) *ctmap.GCFilter {
 	filter := &ctmap.GCFilter{
 		RemoveExpired: true,
@@ -85,41 +201,65 @@ func createGCFilter(initialScan bool, restoredEndpoints []*endpoint.Endpoint) *c
 		for _, ep := range restoredEndpoints {
 			filter.ValidIPs[ep.IPv6.String()] = struct{}{}
 			filter.ValidIPs[ep.IPv4.String()] = struct{}{}
+			for _, ip := range ep.AdditionalIPv6 {
+				filter.ValidIPs[ip.String()] = struct{}{}
+			}
+			for _, ip := range ep.AdditionalIPv4 {
+				filter.ValidIPs[ip.String()] = struct{}{}
+			}
 		}
 	}
 
 	return filter
 }
 
-// EnableConntrackGC enables the connection tracking garbage collection.
-func EnableConntrackGC(ipv4, ipv6 bool, gcinterval int, restoredEndpoints []*endpoint.Endpoint) {
+// EnableConntrackGC enables the connection tracking garbage collection. If
+// gcinterval is 0, the interval between two runs adapts to the usage of the
+// CT maps: it shrinks while runs delete many entries or maps are almost full
+// and grows while the maps are idle. Otherwise runs are gcinterval seconds
+// apart. If nat is true, the NAT map of BPF masquerading is garbage
+// collected in each run as well.
+func EnableConntrackGC(ipv4, ipv6, nat bool, gcinterval int, restoredEndpoints []*endpoint.Endpoint) {
 	initialScan := true
 	initialScanComplete := make(chan struct{})
 
 	go func() {
-		if gcinterval < MinGcInterval {
-			gcinterval = MinGcInterval
-			log.Warnf("Setting conntrack garbage collector interval to its minimum value(%d seconds)", gcinterval)
-		}
-		sleepTime := time.Duration(gcinterval) * time.Second
+		sleepTime, adaptive := initialGCInterval(gcinterval)
 		for {
+			var round gcRound
+
 			eps := GetEndpoints()
 			if len(eps) > 0 || initialScan {
-				runGC(nil, ipv4, ipv6, createGCFilter(initialScan, restoredEndpoints))
+				runGC(nil, ipv4, ipv6, createGCFilter(initialScan, restoredEndpoints), &round)
 			}
 			for _, e := range eps {
 				if !e.ConntrackLocal() {
 					// Skip because GC was handled above.
 					continue
 				}
-				runGC(e, ipv4, ipv6, &ctmap.GCFilter{RemoveExpired: true})
+				runGC(e, ipv4, ipv6, &ctmap.GCFilter{RemoveExpired: true}, &round)
+			}
+			if nat {
+				runNatGC(&round)
 			}
 
 			if initialScan {
 				close(initialScanComplete)
 				initialScan = false
+			} else if adaptive {
+				// The initial scan removes stale entries of
+				// endpoints which have not been restored and is
+				// not representative of the usage of the maps.
+				sleepTime = nextGCInterval(sleepTime, round)
 			}
 
+			metrics.ConntrackGCInterval.Set(sleepTime.Seconds())
+			log.WithFields(logrus.Fields{
+				"interval":    sleepTime,
+				"deleteRatio": round.deleteRatio,
+				"fillRatio":   round.fillRatio,
+			}).Debug("Scheduled next conntrack garbage collection")
+
 			time.Sleep(sleepTime)
 		}
 	}()
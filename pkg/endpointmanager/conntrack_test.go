@@ -0,0 +1,77 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/pkg/maps/ctmap"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type EndpointManagerSuite struct{}
+
+var _ = Suite(&EndpointManagerSuite{})
+
+func (s *EndpointManagerSuite) TestInitialGCInterval(c *C) {
+	// 0 selects the adaptive interval
+	interval, adaptive := initialGCInterval(0)
+	c.Assert(interval, Equals, gcIntervalInitial)
+	c.Assert(adaptive, Equals, true)
+
+	// Fixed intervals are bounded by the minimum
+	interval, adaptive = initialGCInterval(1)
+	c.Assert(interval, Equals, time.Duration(MinGcInterval)*time.Second)
+	c.Assert(adaptive, Equals, false)
+
+	interval, adaptive = initialGCInterval(60)
+	c.Assert(interval, Equals, time.Minute)
+	c.Assert(adaptive, Equals, false)
+}
+
+func (s *EndpointManagerSuite) TestNextGCInterval(c *C) {
+	// Idle maps grow the interval up to the maximum
+	c.Assert(nextGCInterval(time.Minute, gcRound{}), Equals, 90*time.Second)
+	c.Assert(nextGCInterval(gcIntervalMax, gcRound{}), Equals, gcIntervalMax)
+
+	// Moderate churn keeps the interval
+	c.Assert(nextGCInterval(time.Minute, gcRound{deleteRatio: 0.1}), Equals, time.Minute)
+
+	// Heavy churn halves the interval down to the minimum
+	c.Assert(nextGCInterval(time.Minute, gcRound{deleteRatio: 0.5}), Equals, 30*time.Second)
+	c.Assert(nextGCInterval(gcIntervalMin, gcRound{deleteRatio: 0.5}), Equals, gcIntervalMin)
+
+	// Almost full maps are collected as often as possible
+	c.Assert(nextGCInterval(gcIntervalMax, gcRound{fillRatio: 0.95}), Equals, gcIntervalMin)
+}
+
+func (s *EndpointManagerSuite) TestGCRound(c *C) {
+	var round gcRound
+	round.add(ctmap.GCResult{Scanned: 100, Deleted: 50, MaxEntries: 1000})
+	round.add(ctmap.GCResult{Scanned: 800, Deleted: 10, MaxEntries: 1000})
+	c.Assert(round.deleteRatio, Equals, 0.05)
+	c.Assert(round.fillRatio, Equals, 0.79)
+
+	// Full LRU maps evict entries and do not expedite the next run
+	round.add(ctmap.GCResult{Scanned: 1000, MaxEntries: 1000, LRU: true})
+	c.Assert(round.fillRatio, Equals, 0.79)
+}
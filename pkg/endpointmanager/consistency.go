@@ -0,0 +1,117 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointmanager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/sirupsen/logrus"
+)
+
+// policyMapMetricName is the map name under which the drift and the pressure
+// of the policy maps of all endpoints are exported
+const policyMapMetricName = "cilium_policy"
+
+// checkPolicyMaps compares the policy maps of all endpoints with their
+// desired state and exports the sum of all differences as metrics. If repair
+// is true, drifted policy maps are synchronized with the desired state.
+func checkPolicyMaps(repair bool) error {
+	var (
+		total  bpf.MapDrift
+		failed int
+	)
+
+	for _, e := range GetEndpoints() {
+		drift, err := e.CheckPolicyMap(repair)
+		total.Add(drift)
+		if err != nil {
+			log.WithError(err).WithField(logfields.EndpointID, e.ID).
+				Debug("Unable to check consistency of policy map")
+			failed++
+			continue
+		}
+		if drift.Total() == 0 {
+			continue
+		}
+
+		scopedLog := log.WithFields(logrus.Fields{
+			logfields.EndpointID: e.ID,
+			"drift":              drift.String(),
+		})
+		if repair {
+			metrics.BPFMapRepairs.WithLabelValues(policyMapMetricName).Add(float64(drift.Total()))
+			scopedLog.Info("Repaired policy map which differed from the desired state")
+		} else {
+			scopedLog.Warning("Policy map differs from the desired state")
+		}
+	}
+
+	total.SetMetrics(policyMapMetricName)
+
+	if failed > 0 {
+		return fmt.Errorf("unable to check %d policy maps", failed)
+	}
+	return nil
+}
+
+// SamplePolicyMapPressure samples the fill ratio of the policy maps of all
+// endpoints. The highest ratio is exported as pressure of policyMapMetricName
+// and a warning is logged for each policy map which is almost full.
+func SamplePolicyMapPressure() {
+	highest := 0.0
+	for _, e := range GetEndpoints() {
+		ratio, previous, ok := e.SamplePolicyMapPressure()
+		if !ok {
+			continue
+		}
+		if ratio > highest {
+			highest = ratio
+		}
+		if bpf.PressureExceeded(ratio, previous) {
+			log.WithFields(logrus.Fields{
+				logfields.EndpointID: e.ID,
+				"ratio":              ratio,
+			}).Warning("Policy map is almost full, new policy entries are dropped once it is full")
+		}
+	}
+
+	metrics.BPFMapPressure.WithLabelValues(policyMapMetricName).Set(highest)
+}
+
+// EnableMapConsistencyCheck starts a controller which compares the policy
+// maps of all endpoints and all BPF maps with a cache of their desired
+// entries, such as the endpoint and load balancer maps, with their desired
+// state every interval. Differences are exported as metrics and reported in
+// the health of the affected endpoints. If repair is true, entries are
+// restored to the desired state.
+func EnableMapConsistencyCheck(interval time.Duration, repair bool) {
+	controller.NewManager().UpdateController("bpf-map-consistency",
+		controller.ControllerParams{
+			DoFunc: func() error {
+				policyErr := checkPolicyMaps(repair)
+				if err := bpf.CheckCachedMaps(repair); err != nil {
+					return err
+				}
+				return policyErr
+			},
+			RunInterval: interval,
+		})
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/cilium/cilium/common/addressing"
 	"github.com/cilium/cilium/pkg/endpoint"
 	endpointid "github.com/cilium/cilium/pkg/endpoint/id"
 	"github.com/cilium/cilium/pkg/lock"
@@ -175,6 +176,12 @@ func Remove(ep *endpoint.Endpoint) {
 		delete(endpointsAux, endpointid.NewID(endpointid.IPv4Prefix, ep.IPv4.String()))
 	}
 
+	if ep.IPv6.String() != "" {
+		delete(endpointsAux, endpointid.NewID(endpointid.IPv6Prefix, ep.IPv6.String()))
+	}
+
+	removeAdditionalAddressReferences(ep)
+
 	if ep.ContainerName != "" {
 		delete(endpointsAux, endpointid.NewID(endpointid.ContainerNamePrefix, ep.ContainerName))
 	}
@@ -254,6 +261,18 @@ func updateReferences(ep *endpoint.Endpoint) {
 		endpointsAux[endpointid.NewID(endpointid.IPv4Prefix, ep.IPv4.String())] = ep
 	}
 
+	if ep.IPv6.String() != "" {
+		endpointsAux[endpointid.NewID(endpointid.IPv6Prefix, ep.IPv6.String())] = ep
+	}
+
+	for _, ip := range ep.AdditionalIPv4 {
+		endpointsAux[endpointid.NewID(endpointid.IPv4Prefix, ip.String())] = ep
+	}
+
+	for _, ip := range ep.AdditionalIPv6 {
+		endpointsAux[endpointid.NewID(endpointid.IPv6Prefix, ip.String())] = ep
+	}
+
 	if ep.ContainerName != "" {
 		endpointsAux[endpointid.NewID(endpointid.ContainerNamePrefix, ep.ContainerName)] = ep
 	}
@@ -263,6 +282,57 @@ func updateReferences(ep *endpoint.Endpoint) {
 	}
 }
 
+// removeAdditionalAddressReferences removes the references of the additional
+// addresses of ep.
+func removeAdditionalAddressReferences(ep *endpoint.Endpoint) {
+	for _, ip := range ep.AdditionalIPv4 {
+		delete(endpointsAux, endpointid.NewID(endpointid.IPv4Prefix, ip.String()))
+	}
+
+	for _, ip := range ep.AdditionalIPv6 {
+		delete(endpointsAux, endpointid.NewID(endpointid.IPv6Prefix, ip.String()))
+	}
+}
+
+// lookupAddressConflict returns an error if any of the given addresses is
+// used by an endpoint other than ep.
+func lookupAddressConflict(ep *endpoint.Endpoint, ipv4 []addressing.CiliumIPv4, ipv6 []addressing.CiliumIPv6) error {
+	ips := make(map[string]string, len(ipv4)+len(ipv6))
+	for _, ip := range ipv4 {
+		ips[ip.String()] = endpointid.NewID(endpointid.IPv4Prefix, ip.String())
+	}
+	for _, ip := range ipv6 {
+		ips[ip.String()] = endpointid.NewID(endpointid.IPv6Prefix, ip.String())
+	}
+
+	for ip, id := range ips {
+		if other, ok := endpointsAux[id]; ok && other != ep {
+			return fmt.Errorf("address %s is already in use by endpoint %d", ip, other.ID)
+		}
+	}
+	return nil
+}
+
+// UpdateAdditionalAddresses replaces the additional addresses of ep and its
+// references. The addresses are left untouched if any of them is used by
+// another endpoint. Returns true if the addresses changed.
+// Must be called with ep.Mutex locked.
+func UpdateAdditionalAddresses(ep *endpoint.Endpoint, ipv4 []addressing.CiliumIPv4, ipv6 []addressing.CiliumIPv6) (bool, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if err := lookupAddressConflict(ep, ipv4, ipv6); err != nil {
+		return false, err
+	}
+
+	removeAdditionalAddressReferences(ep)
+	changed := ep.SetAdditionalAddressesLocked(ipv4, ipv6)
+	if _, ok := endpoints[ep.ID]; ok {
+		updateReferences(ep)
+	}
+	return changed, nil
+}
+
 // RegenerateAllEndpoints calls a SetStateLocked for each endpoint and
 // regenerates if state transaction is valid. During this process, the endpoint
 // list is locked and cannot be modified.
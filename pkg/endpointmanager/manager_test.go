@@ -0,0 +1,89 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package endpointmanager
+
+import (
+	"os"
+
+	"github.com/cilium/cilium/common/addressing"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/endpoint"
+
+	. "gopkg.in/check.v1"
+)
+
+func init() {
+	// Replaced addresses are deleted from the endpoints map which does not
+	// exist in the unit tests.
+	bpf.SetMapRoot(os.TempDir())
+}
+
+func mustIPv4(c *C, s string) addressing.CiliumIPv4 {
+	ip, err := addressing.NewCiliumIPv4(s)
+	c.Assert(err, IsNil)
+	return ip
+}
+
+func mustIPv6(c *C, s string) addressing.CiliumIPv6 {
+	ip, err := addressing.NewCiliumIPv6(s)
+	c.Assert(err, IsNil)
+	return ip
+}
+
+func (s *EndpointManagerSuite) TestUpdateAdditionalAddresses(c *C) {
+	defer RemoveAll()
+
+	ep1 := endpoint.NewEndpointWithState(1, endpoint.StateReady)
+	ep1.IPv4 = mustIPv4(c, "10.0.0.1")
+	ep1.IPv6 = mustIPv6(c, "f00d::1")
+	ep2 := endpoint.NewEndpointWithState(2, endpoint.StateReady)
+	ep2.IPv4 = mustIPv4(c, "10.0.0.2")
+
+	mutex.Lock()
+	for _, ep := range []*endpoint.Endpoint{ep1, ep2} {
+		endpoints[ep.ID] = ep
+		updateReferences(ep)
+	}
+	mutex.Unlock()
+
+	// Addresses of other endpoints are rejected
+	_, err := UpdateAdditionalAddresses(ep2, []addressing.CiliumIPv4{mustIPv4(c, "10.0.0.1")}, nil)
+	c.Assert(err, Not(IsNil))
+	_, err = UpdateAdditionalAddresses(ep2, nil, []addressing.CiliumIPv6{mustIPv6(c, "f00d::1")})
+	c.Assert(err, Not(IsNil))
+	c.Assert(ep2.AdditionalIPv4, IsNil)
+	c.Assert(ep2.AdditionalIPv6, IsNil)
+
+	ipv4 := []addressing.CiliumIPv4{mustIPv4(c, "10.0.0.3")}
+	ipv6 := []addressing.CiliumIPv6{mustIPv6(c, "f00d::3")}
+	changed, err := UpdateAdditionalAddresses(ep2, ipv4, ipv6)
+	c.Assert(err, IsNil)
+	c.Assert(changed, Equals, true)
+	c.Assert(LookupIPv4("10.0.0.3"), Equals, ep2)
+
+	changed, err = UpdateAdditionalAddresses(ep2, ipv4, ipv6)
+	c.Assert(err, IsNil)
+	c.Assert(changed, Equals, false)
+
+	// The references of replaced addresses are removed
+	changed, err = UpdateAdditionalAddresses(ep2, []addressing.CiliumIPv4{mustIPv4(c, "10.0.0.4")}, nil)
+	c.Assert(err, IsNil)
+	c.Assert(changed, Equals, true)
+	c.Assert(LookupIPv4("10.0.0.3"), IsNil)
+	c.Assert(LookupIPv4("10.0.0.4"), Equals, ep2)
+
+	_, err = UpdateAdditionalAddresses(ep1, nil, ipv6)
+	c.Assert(err, IsNil)
+}
@@ -17,6 +17,7 @@ package envoy
 import (
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,6 +26,9 @@ import (
 
 	"github.com/cilium/cilium/pkg/envoy/cilium"
 	"github.com/cilium/cilium/pkg/flowdebug"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/proxy/accesslog"
 	"github.com/cilium/cilium/pkg/proxy/logger"
 
@@ -36,9 +40,67 @@ func getAccessLogPath(stateDir string) string {
 	return filepath.Join(stateDir, "access_log.sock")
 }
 
+const (
+	// maxPendingRequests is the maximum number of HTTP requests awaiting
+	// their response for which the time of the request is kept
+	maxPendingRequests = 8192
+
+	// pendingRequestTimeout is the time after which a request without
+	// response is forgotten to make room for new requests
+	pendingRequestTimeout = time.Minute
+)
+
+// pendingRequests keeps the time of the HTTP requests logged by Envoy by
+// their request ID, which is retained in the log entry of the response, so
+// that the latency of the response can be computed.
+type pendingRequests struct {
+	mutex lock.Mutex
+	times map[string]time.Time
+}
+
+// add keeps the time of the request with the given ID.
+func (p *pendingRequests) add(id string, ts time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.times == nil {
+		p.times = make(map[string]time.Time)
+	}
+	if len(p.times) >= maxPendingRequests {
+		for pendingID, pendingTs := range p.times {
+			if ts.Sub(pendingTs) > pendingRequestTimeout {
+				delete(p.times, pendingID)
+			}
+		}
+		if len(p.times) >= maxPendingRequests {
+			return
+		}
+	}
+	p.times[id] = ts
+}
+
+// latency returns the time between the request with the given ID and its
+// response at ts and forgets the request. Returns false if the time of the
+// request is unknown.
+func (p *pendingRequests) latency(id string, ts time.Time) (time.Duration, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	requestTs, ok := p.times[id]
+	if !ok {
+		return 0, false
+	}
+	delete(p.times, id)
+	return ts.Sub(requestTs), true
+}
+
 type accessLogServer struct {
 	xdsServer            *XDSServer
 	endpointInfoRegistry logger.EndpointInfoRegistry
+
+	// pending keeps the time of the HTTP requests of all connections, the
+	// request and response of a stream are logged by the same listener
+	pending *pendingRequests
 }
 
 // StartAccessLogServer starts the access log server.
@@ -62,6 +124,7 @@ func StartAccessLogServer(stateDir string, xdsServer *XDSServer, endpointInfoReg
 	server := accessLogServer{
 		xdsServer:            xdsServer,
 		endpointInfoRegistry: endpointInfoRegistry,
+		pending:              &pendingRequests{},
 	}
 
 	go func() {
@@ -128,17 +191,29 @@ func (s *accessLogServer) accessLogger(conn *net.UnixConn) {
 	}
 }
 
+// isCircuitBreakerOverflow returns true if the log entry is a response
+// generated by Envoy for a request which exceeded the pending requests limit
+// of a circuit breaker. Envoy marks such responses with the
+// "x-envoy-overloaded" header.
+func isCircuitBreakerOverflow(pblog *cilium.LogEntry, headers http.Header) bool {
+	return pblog.EntryType == cilium.EntryType_Response && headers.Get("X-Envoy-Overloaded") != ""
+}
+
 func (s *accessLogServer) logRecord(localEndpoint logger.EndpointUpdater, pblog *cilium.LogEntry) {
 	// TODO: Support Kafka.
 
+	ts := time.Unix(int64(pblog.Timestamp/1000000000), int64(pblog.Timestamp%1000000000))
+
 	var l7tags logger.LogTag
+	var headers http.Header
 	if http := pblog.GetHttp(); http != nil {
+		headers = http.GetNetHttpHeaders()
 		l7tags = logger.LogTags.HTTP(&accesslog.LogRecordHTTP{
 			Method:   http.Method,
 			Code:     int(http.Status),
 			URL:      http.ParseURL(),
 			Protocol: http.GetProtocol(),
-			Headers:  http.GetNetHttpHeaders(),
+			Headers:  headers,
 		})
 	} else if l7 := pblog.GetGenericL7(); l7 != nil {
 		l7tags = logger.LogTags.L7(&accesslog.LogRecordL7{
@@ -147,23 +222,44 @@ func (s *accessLogServer) logRecord(localEndpoint logger.EndpointUpdater, pblog
 		})
 	} else {
 		// Default to the deprecated HTTP log format
+		headers = pblog.GetNetHttpHeaders()
 		l7tags = logger.LogTags.HTTP(&accesslog.LogRecordHTTP{
 			Method:   pblog.Method,
 			Code:     int(pblog.Status),
 			URL:      pblog.ParseURL(),
 			Protocol: pblog.GetProtocol(),
-			Headers:  pblog.GetNetHttpHeaders(),
+			Headers:  headers,
 		})
 	}
 
-	r := logger.NewLogRecord(s.endpointInfoRegistry, localEndpoint, pblog.GetFlowType(), pblog.IsIngress,
-		logger.LogTags.Timestamp(time.Unix(int64(pblog.Timestamp/1000000000), int64(pblog.Timestamp%1000000000))),
+	tags := []logger.LogTag{
+		logger.LogTags.Timestamp(ts),
 		logger.LogTags.Verdict(pblog.GetVerdict(), pblog.CiliumRuleRef),
 		logger.LogTags.Addressing(logger.AddressingInfo{
 			SrcIPPort:   pblog.SourceAddress,
 			DstIPPort:   pblog.DestinationAddress,
 			SrcIdentity: pblog.SourceSecurityId,
-		}), l7tags)
+		}), l7tags,
+	}
+
+	// Envoy adds a request ID to each HTTP request, the latency of a
+	// response is the time since the request with the same ID.
+	if requestID := headers.Get("X-Request-Id"); requestID != "" {
+		switch pblog.EntryType {
+		case cilium.EntryType_Request:
+			s.pending.add(requestID, ts)
+		case cilium.EntryType_Response:
+			if latency, ok := s.pending.latency(requestID, ts); ok {
+				tags = append(tags, logger.LogTags.Latency(latency))
+			}
+		}
+	}
+
+	if isCircuitBreakerOverflow(pblog, headers) {
+		metrics.ProxyCircuitBreakerOverflows.WithLabelValues(string(policy.ParserTypeHTTP), metrics.LimitPendingRequests).Inc()
+	}
+
+	r := logger.NewLogRecord(s.endpointInfoRegistry, localEndpoint, pblog.GetFlowType(), pblog.IsIngress, tags...)
 
 	r.Log()
 
@@ -15,6 +15,10 @@
 package envoy
 
 import (
+	"net/http"
+	"strconv"
+	"time"
+
 	"github.com/cilium/cilium/pkg/envoy/cilium"
 
 	. "gopkg.in/check.v1"
@@ -38,3 +42,40 @@ func (k *AccessLogServerSuite) TestParseURL(c *C) {
 		c.Assert(u.Path, Equals, "/foo")
 	}
 }
+
+func (k *AccessLogServerSuite) TestPendingRequests(c *C) {
+	p := &pendingRequests{}
+	start := time.Unix(1000, 0)
+
+	p.add("a", start)
+	latency, ok := p.latency("a", start.Add(20*time.Millisecond))
+	c.Assert(ok, Equals, true)
+	c.Assert(latency, Equals, 20*time.Millisecond)
+
+	// The request is forgotten once its response has been logged
+	_, ok = p.latency("a", start.Add(time.Second))
+	c.Assert(ok, Equals, false)
+
+	// Requests without response are forgotten after the timeout if the
+	// maximum number of requests is reached
+	for i := 0; i < maxPendingRequests; i++ {
+		p.add(strconv.Itoa(i), start)
+	}
+	p.add("b", start.Add(time.Second))
+	_, ok = p.latency("b", start.Add(2*time.Second))
+	c.Assert(ok, Equals, false)
+
+	p.add("b", start.Add(pendingRequestTimeout+time.Second))
+	_, ok = p.latency("b", start.Add(pendingRequestTimeout+2*time.Second))
+	c.Assert(ok, Equals, true)
+	_, ok = p.latency("0", start.Add(pendingRequestTimeout+2*time.Second))
+	c.Assert(ok, Equals, false)
+}
+
+func (k *AccessLogServerSuite) TestIsCircuitBreakerOverflow(c *C) {
+	overloaded := http.Header{"X-Envoy-Overloaded": []string{"true"}}
+
+	c.Assert(isCircuitBreakerOverflow(&cilium.LogEntry{EntryType: cilium.EntryType_Response}, overloaded), Equals, true)
+	c.Assert(isCircuitBreakerOverflow(&cilium.LogEntry{EntryType: cilium.EntryType_Request}, overloaded), Equals, false)
+	c.Assert(isCircuitBreakerOverflow(&cilium.LogEntry{EntryType: cilium.EntryType_Response}, http.Header{}), Equals, false)
+}
@@ -24,10 +24,13 @@ type BpfMetadata struct {
 	// File system root for bpf. Defaults to "/sys/fs/bpf" if left empty.
 	BpfRoot string `protobuf:"bytes,1,opt,name=bpf_root,json=bpfRoot,proto3" json:"bpf_root,omitempty"`
 	// 'true' if the filter is on ingress listener, 'false' for egress listener.
-	IsIngress            bool     `protobuf:"varint,2,opt,name=is_ingress,json=isIngress,proto3" json:"is_ingress,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	IsIngress bool `protobuf:"varint,2,opt,name=is_ingress,json=isIngress,proto3" json:"is_ingress,omitempty"`
+	// 'true' if upstream connections are made from the original source address
+	// of the downstream connection. Only applies to IPv4 connections.
+	UseOriginalSourceAddress bool     `protobuf:"varint,3,opt,name=use_original_source_address,json=useOriginalSourceAddress,proto3" json:"use_original_source_address,omitempty"`
+	XXX_NoUnkeyedLiteral     struct{} `json:"-"`
+	XXX_unrecognized         []byte   `json:"-"`
+	XXX_sizecache            int32    `json:"-"`
 }
 
 func (m *BpfMetadata) Reset()         { *m = BpfMetadata{} }
@@ -69,6 +72,13 @@ func (m *BpfMetadata) GetIsIngress() bool {
 	return false
 }
 
+func (m *BpfMetadata) GetUseOriginalSourceAddress() bool {
+	if m != nil {
+		return m.UseOriginalSourceAddress
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*BpfMetadata)(nil), "cilium.BpfMetadata")
 }
@@ -76,13 +86,16 @@ func init() {
 func init() { proto.RegisterFile("cilium/cilium_bpf_metadata.proto", fileDescriptor_1204b5bcdcef4958) }
 
 var fileDescriptor_1204b5bcdcef4958 = []byte{
-	// 124 bytes of a gzipped FileDescriptorProto
+	// 167 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x48, 0xce, 0xcc, 0xc9,
 	0x2c, 0xcd, 0xd5, 0x87, 0x50, 0xf1, 0x49, 0x05, 0x69, 0xf1, 0xb9, 0xa9, 0x25, 0x89, 0x29, 0x89,
-	0x25, 0x89, 0x7a, 0x05, 0x45, 0xf9, 0x25, 0xf9, 0x42, 0x6c, 0x10, 0x29, 0x25, 0x77, 0x2e, 0x6e,
-	0xa7, 0x82, 0x34, 0x5f, 0xa8, 0xa4, 0x90, 0x24, 0x17, 0x07, 0x48, 0x71, 0x51, 0x7e, 0x7e, 0x89,
-	0x04, 0xa3, 0x02, 0xa3, 0x06, 0x67, 0x10, 0x7b, 0x52, 0x41, 0x5a, 0x50, 0x7e, 0x7e, 0x89, 0x90,
-	0x2c, 0x17, 0x57, 0x66, 0x71, 0x7c, 0x66, 0x5e, 0x7a, 0x51, 0x6a, 0x71, 0xb1, 0x04, 0x93, 0x02,
-	0xa3, 0x06, 0x47, 0x10, 0x67, 0x66, 0xb1, 0x27, 0x44, 0x20, 0x89, 0x0d, 0x6c, 0xae, 0x31, 0x20,
-	0x00, 0x00, 0xff, 0xff, 0xb0, 0xea, 0x7e, 0xbe, 0x7b, 0x00, 0x00, 0x00,
+	0x25, 0x89, 0x7a, 0x05, 0x45, 0xf9, 0x25, 0xf9, 0x42, 0x6c, 0x10, 0x29, 0xa5, 0x36, 0x46, 0x2e,
+	0x6e, 0xa7, 0x82, 0x34, 0x5f, 0xa8, 0xac, 0x90, 0x24, 0x17, 0x07, 0x48, 0x75, 0x51, 0x7e, 0x7e,
+	0x89, 0x04, 0xa3, 0x02, 0xa3, 0x06, 0x67, 0x10, 0x7b, 0x52, 0x41, 0x5a, 0x50, 0x7e, 0x7e, 0x89,
+	0x90, 0x2c, 0x17, 0x57, 0x66, 0x71, 0x7c, 0x66, 0x5e, 0x7a, 0x51, 0x6a, 0x71, 0xb1, 0x04, 0x93,
+	0x02, 0xa3, 0x06, 0x47, 0x10, 0x67, 0x66, 0xb1, 0x27, 0x44, 0x40, 0xc8, 0x96, 0x4b, 0xba, 0xb4,
+	0x38, 0x35, 0x3e, 0xbf, 0x28, 0x33, 0x3d, 0x33, 0x2f, 0x31, 0x27, 0xbe, 0x38, 0xbf, 0xb4, 0x28,
+	0x39, 0x35, 0x3e, 0x31, 0x25, 0x05, 0xac, 0x9e, 0x19, 0xac, 0x5e, 0xa2, 0xb4, 0x38, 0xd5, 0x1f,
+	0xaa, 0x22, 0x18, 0xac, 0xc0, 0x11, 0x22, 0x9f, 0xc4, 0x06, 0x76, 0x97, 0x31, 0x60, 0x00, 0x5b,
+	0xa9, 0xe5, 0xf9, 0xbb, 0x00, 0x00, 0x00,
 }
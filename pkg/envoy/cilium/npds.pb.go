@@ -52,9 +52,14 @@ type NetworkPolicy struct {
 	// combination.
 	// Optional. If empty, all flows in this direction are denied.
 	EgressPerPortPolicies []*PortNetworkPolicy `protobuf:"bytes,4,rep,name=egress_per_port_policies,json=egressPerPortPolicies,proto3" json:"egress_per_port_policies,omitempty"`
-	XXX_NoUnkeyedLiteral  struct{}             `json:"-"`
-	XXX_unrecognized      []byte               `json:"-"`
-	XXX_sizecache         int32                `json:"-"`
+	// The labels of the source identities of requests allowed by HTTP rules
+	// which inject identity headers, keyed by numeric identity. Each value is
+	// a comma-separated list of labels in the form "source:key=value".
+	// Optional.
+	IdentityLabels       map[uint64]string `protobuf:"bytes,5,rep,name=identity_labels,json=identityLabels,proto3" json:"identity_labels,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *NetworkPolicy) Reset()         { *m = NetworkPolicy{} }
@@ -110,6 +115,13 @@ func (m *NetworkPolicy) GetEgressPerPortPolicies() []*PortNetworkPolicy {
 	return nil
 }
 
+func (m *NetworkPolicy) GetIdentityLabels() map[uint64]string {
+	if m != nil {
+		return m.IdentityLabels
+	}
+	return nil
+}
+
 // A network policy to whitelist flows to a specific destination L4 port,
 // as a conjunction of predicates on L3/L4/L7 flows.
 // If all the predicates of a policy match a flow, the flow is whitelisted.
@@ -441,10 +453,20 @@ type HttpNetworkPolicyRule struct {
 	// * *:authority*: Also maps to the HTTP 1.1 *Host* header.
 	//
 	// Optional. If empty, matches any HTTP request.
-	Headers              []*route.HeaderMatcher `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+	Headers []*route.HeaderMatcher `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
+	// If true, the X-Cilium-Source-Identity and X-Cilium-Source-Labels
+	// headers are injected into requests allowed by this rule.
+	// Optional.
+	InjectIdentityHeaders bool `protobuf:"varint,2,opt,name=inject_identity_headers,json=injectIdentityHeaders,proto3" json:"inject_identity_headers,omitempty"`
+	// Maximum time in milliseconds the proxy waits for the complete upstream
+	// response to a request allowed by this rule.
+	// Optional. If zero, the default timeout of the proxy applies.
+	TimeoutMs uint64 `protobuf:"varint,3,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	// Optional. If unset, requests are not retried.
+	RetryPolicy          *HttpNetworkPolicyRule_RetryPolicy `protobuf:"bytes,4,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                           `json:"-"`
+	XXX_unrecognized     []byte                             `json:"-"`
+	XXX_sizecache        int32                              `json:"-"`
 }
 
 func (m *HttpNetworkPolicyRule) Reset()         { *m = HttpNetworkPolicyRule{} }
@@ -479,6 +501,88 @@ func (m *HttpNetworkPolicyRule) GetHeaders() []*route.HeaderMatcher {
 	return nil
 }
 
+func (m *HttpNetworkPolicyRule) GetInjectIdentityHeaders() bool {
+	if m != nil {
+		return m.InjectIdentityHeaders
+	}
+	return false
+}
+
+func (m *HttpNetworkPolicyRule) GetTimeoutMs() uint64 {
+	if m != nil {
+		return m.TimeoutMs
+	}
+	return 0
+}
+
+func (m *HttpNetworkPolicyRule) GetRetryPolicy() *HttpNetworkPolicyRule_RetryPolicy {
+	if m != nil {
+		return m.RetryPolicy
+	}
+	return nil
+}
+
+// Policy by which the proxy retries requests allowed by this rule.
+type HttpNetworkPolicyRule_RetryPolicy struct {
+	// Maximum number of retries of a request.
+	NumRetries uint32 `protobuf:"varint,1,opt,name=num_retries,json=numRetries,proto3" json:"num_retries,omitempty"`
+	// Timeout in milliseconds of each attempt.
+	// Optional. If zero, each attempt is only limited by 'timeout_ms'.
+	PerTryTimeoutMs uint64 `protobuf:"varint,2,opt,name=per_try_timeout_ms,json=perTryTimeoutMs,proto3" json:"per_try_timeout_ms,omitempty"`
+	// Comma separated list of conditions under which a request is retried,
+	// in the format of the 'x-envoy-retry-on' header, e.g. "5xx,connect-failure".
+	RetryOn              string   `protobuf:"bytes,3,opt,name=retry_on,json=retryOn,proto3" json:"retry_on,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HttpNetworkPolicyRule_RetryPolicy) Reset()         { *m = HttpNetworkPolicyRule_RetryPolicy{} }
+func (m *HttpNetworkPolicyRule_RetryPolicy) String() string { return proto.CompactTextString(m) }
+func (*HttpNetworkPolicyRule_RetryPolicy) ProtoMessage()    {}
+func (*HttpNetworkPolicyRule_RetryPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_282feee65b187334, []int{4, 0}
+}
+
+func (m *HttpNetworkPolicyRule_RetryPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HttpNetworkPolicyRule_RetryPolicy.Unmarshal(m, b)
+}
+func (m *HttpNetworkPolicyRule_RetryPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HttpNetworkPolicyRule_RetryPolicy.Marshal(b, m, deterministic)
+}
+func (m *HttpNetworkPolicyRule_RetryPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HttpNetworkPolicyRule_RetryPolicy.Merge(m, src)
+}
+func (m *HttpNetworkPolicyRule_RetryPolicy) XXX_Size() int {
+	return xxx_messageInfo_HttpNetworkPolicyRule_RetryPolicy.Size(m)
+}
+func (m *HttpNetworkPolicyRule_RetryPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_HttpNetworkPolicyRule_RetryPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HttpNetworkPolicyRule_RetryPolicy proto.InternalMessageInfo
+
+func (m *HttpNetworkPolicyRule_RetryPolicy) GetNumRetries() uint32 {
+	if m != nil {
+		return m.NumRetries
+	}
+	return 0
+}
+
+func (m *HttpNetworkPolicyRule_RetryPolicy) GetPerTryTimeoutMs() uint64 {
+	if m != nil {
+		return m.PerTryTimeoutMs
+	}
+	return 0
+}
+
+func (m *HttpNetworkPolicyRule_RetryPolicy) GetRetryOn() string {
+	if m != nil {
+		return m.RetryOn
+	}
+	return ""
+}
+
 // A set of network policy rules that match Kafka requests.
 type KafkaNetworkPolicyRules struct {
 	// The set of Kafka network policy rules.
@@ -688,10 +792,12 @@ func (m *L7NetworkPolicyRule) GetRule() map[string]string {
 
 func init() {
 	proto.RegisterType((*NetworkPolicy)(nil), "cilium.NetworkPolicy")
+	proto.RegisterMapType((map[uint64]string)(nil), "cilium.NetworkPolicy.IdentityLabelsEntry")
 	proto.RegisterType((*PortNetworkPolicy)(nil), "cilium.PortNetworkPolicy")
 	proto.RegisterType((*PortNetworkPolicyRule)(nil), "cilium.PortNetworkPolicyRule")
 	proto.RegisterType((*HttpNetworkPolicyRules)(nil), "cilium.HttpNetworkPolicyRules")
 	proto.RegisterType((*HttpNetworkPolicyRule)(nil), "cilium.HttpNetworkPolicyRule")
+	proto.RegisterType((*HttpNetworkPolicyRule_RetryPolicy)(nil), "cilium.HttpNetworkPolicyRule.RetryPolicy")
 	proto.RegisterType((*KafkaNetworkPolicyRules)(nil), "cilium.KafkaNetworkPolicyRules")
 	proto.RegisterType((*KafkaNetworkPolicyRule)(nil), "cilium.KafkaNetworkPolicyRule")
 	proto.RegisterType((*L7NetworkPolicyRules)(nil), "cilium.L7NetworkPolicyRules")
@@ -840,57 +946,68 @@ var _NetworkPolicyDiscoveryService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("cilium/npds.proto", fileDescriptor_282feee65b187334) }
 
 var fileDescriptor_282feee65b187334 = []byte{
-	// 828 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x41, 0x6f, 0xe3, 0x44,
-	0x14, 0xde, 0x49, 0x9c, 0xb4, 0x79, 0xd1, 0x2e, 0x74, 0xb6, 0x49, 0xdd, 0xb0, 0x4d, 0x83, 0x01,
-	0x29, 0x5b, 0xa9, 0xce, 0x2a, 0x3d, 0x84, 0x96, 0x03, 0xda, 0x88, 0x45, 0x45, 0x05, 0x14, 0xb9,
-	0x2b, 0x0e, 0x8b, 0xd8, 0x68, 0xd6, 0x7e, 0x6d, 0x47, 0x71, 0x3d, 0x66, 0x3c, 0x09, 0x0a, 0xc7,
-	0x15, 0x17, 0xae, 0xf0, 0x3b, 0x90, 0x38, 0x73, 0xda, 0xff, 0xc0, 0x5f, 0x80, 0x03, 0xbf, 0xa2,
-	0xc8, 0x33, 0x76, 0xb6, 0x56, 0xdd, 0x72, 0xe1, 0x62, 0x8d, 0xe7, 0x7d, 0xdf, 0x37, 0xef, 0x7d,
-	0xf3, 0x9e, 0x0d, 0x1b, 0x3e, 0x0f, 0xf9, 0xfc, 0x72, 0x10, 0xc5, 0x41, 0xe2, 0xc6, 0x52, 0x28,
-	0x41, 0xeb, 0x66, 0xab, 0xb3, 0x8b, 0xd1, 0x42, 0x2c, 0x07, 0x2c, 0xe6, 0x83, 0xc5, 0x70, 0xe0,
-	0x0b, 0x89, 0x03, 0x16, 0x04, 0x12, 0x93, 0x0c, 0xd8, 0x79, 0x54, 0x00, 0x04, 0x3c, 0xf1, 0xc5,
-	0x02, 0xe5, 0x32, 0x8b, 0x76, 0x0b, 0x51, 0x29, 0xe6, 0x0a, 0xcd, 0x33, 0x67, 0x9f, 0x0b, 0x71,
-	0x1e, 0xa2, 0x06, 0xb0, 0x28, 0x12, 0x8a, 0x29, 0x2e, 0xa2, 0x5c, 0x7b, 0x6b, 0xc1, 0x42, 0x1e,
-	0x30, 0x85, 0x83, 0x7c, 0x61, 0x02, 0xce, 0xdf, 0x04, 0xee, 0x7f, 0x8d, 0xea, 0x07, 0x21, 0x67,
-	0x13, 0x11, 0x72, 0x7f, 0x49, 0x29, 0x58, 0x11, 0xbb, 0x44, 0x9b, 0xf4, 0x48, 0xbf, 0xe1, 0xe9,
-	0x35, 0x6d, 0x43, 0x3d, 0xd6, 0x51, 0xbb, 0xd2, 0x23, 0x7d, 0xcb, 0xcb, 0xde, 0xe8, 0x73, 0xd8,
-	0xe6, 0xd1, 0x79, 0x5a, 0xc3, 0x34, 0x46, 0x39, 0x8d, 0x85, 0x54, 0x53, 0x1d, 0xe2, 0x98, 0xd8,
-	0xd5, 0x5e, 0xb5, 0xdf, 0x1c, 0x6e, 0xbb, 0xa6, 0x7e, 0x77, 0x22, 0xa4, 0x2a, 0x9c, 0xe4, 0xb5,
-	0x33, 0xee, 0x04, 0x65, 0x1a, 0x9c, 0x64, 0x44, 0xea, 0x81, 0x8d, 0xb7, 0x89, 0x5a, 0xff, 0x25,
-	0xda, 0xc2, 0x32, 0x4d, 0xe7, 0x77, 0x02, 0x1b, 0x37, 0xc0, 0x74, 0x17, 0xac, 0x54, 0x5e, 0xd7,
-	0x7a, 0x7f, 0xdc, 0xfc, 0xe3, 0x9f, 0x37, 0xd5, 0xfa, 0x9e, 0x65, 0x5f, 0x5d, 0x55, 0x3d, 0x1d,
-	0xa0, 0xcf, 0x60, 0x5d, 0xfb, 0xe4, 0x8b, 0x50, 0x97, 0xfe, 0x60, 0xf8, 0xd8, 0xd5, 0x17, 0xe1,
-	0xb2, 0x98, 0xbb, 0x8b, 0xa1, 0x9b, 0xde, 0xa3, 0x7b, 0x2a, 0xfc, 0x19, 0xaa, 0xa7, 0xd9, 0x6d,
-	0x4e, 0x32, 0x82, 0xb7, 0xa2, 0xd2, 0x03, 0xa8, 0xc9, 0x79, 0xb8, 0xf2, 0x64, 0xe7, 0xf6, 0xf4,
-	0xe7, 0x21, 0x7a, 0x06, 0xeb, 0xfc, 0x56, 0x81, 0x56, 0x29, 0x80, 0x1e, 0xc0, 0x3b, 0x12, 0x2f,
-	0x85, 0xc2, 0xb7, 0xbe, 0x90, 0x5e, 0xb5, 0x6f, 0x8d, 0x21, 0xad, 0xa0, 0xf6, 0x0b, 0xa9, 0xd8,
-	0xc4, 0x7b, 0x60, 0x20, 0x2b, 0x57, 0xb7, 0x61, 0x3d, 0x1c, 0x4d, 0x75, 0x4a, 0xba, 0x94, 0x86,
-	0xb7, 0x16, 0x8e, 0x74, 0xae, 0xf4, 0x53, 0x80, 0x0b, 0xa5, 0xe2, 0xa9, 0xc9, 0x31, 0xe8, 0x91,
-	0x7e, 0x73, 0xd8, 0xcd, 0x73, 0x3c, 0x56, 0x2a, 0xbe, 0x91, 0x42, 0x72, 0x7c, 0xcf, 0x6b, 0xa4,
-	0x1c, 0xfd, 0x42, 0xc7, 0xd0, 0x9c, 0xb1, 0xb3, 0x19, 0xcb, 0x14, 0x50, 0x2b, 0xec, 0xe6, 0x0a,
-	0x27, 0x69, 0xa8, 0x54, 0x02, 0x34, 0xcb, 0x68, 0x1c, 0xea, 0xfc, 0x8c, 0xc0, 0x99, 0x16, 0x78,
-	0x94, 0x0b, 0x7c, 0x39, 0x2a, 0x65, 0xaf, 0x85, 0x23, 0xbd, 0x1c, 0x5b, 0x50, 0x09, 0x47, 0xce,
-	0x2b, 0x68, 0x97, 0xe7, 0x4a, 0x8f, 0x0b, 0xf5, 0x91, 0xe2, 0x1d, 0x94, 0x72, 0xde, 0x3a, 0xb9,
-	0x4e, 0xae, 0x15, 0xea, 0x3c, 0x87, 0x56, 0x29, 0x9e, 0x7e, 0x02, 0x6b, 0x17, 0xc8, 0x02, 0x94,
-	0xb9, 0xfe, 0xfb, 0xc5, 0x3e, 0x31, 0xa3, 0x7a, 0xac, 0x21, 0x5f, 0x31, 0xe5, 0x5f, 0xa0, 0xf4,
-	0x72, 0x86, 0x73, 0x06, 0x5b, 0xb7, 0x78, 0x44, 0x4f, 0x8a, 0xce, 0x1a, 0xed, 0xee, 0xdd, 0xce,
-	0x16, 0x92, 0xbf, 0x66, 0xb1, 0xf3, 0x86, 0x40, 0xbb, 0x9c, 0x42, 0xb7, 0x60, 0x8d, 0xc5, 0x7c,
-	0x3a, 0xc3, 0xa5, 0x1e, 0x86, 0x9a, 0x57, 0x67, 0x31, 0x3f, 0xc1, 0x74, 0x44, 0x9a, 0x69, 0x60,
-	0x81, 0x32, 0xe1, 0x22, 0xd2, 0x9d, 0x53, 0xf3, 0x80, 0xc5, 0xfc, 0x1b, 0xb3, 0x93, 0xf6, 0xb6,
-	0x12, 0x31, 0xf7, 0xed, 0x6a, 0xda, 0x54, 0xe3, 0x9d, 0xf4, 0x6c, 0x5b, 0xb6, 0xed, 0x2b, 0x32,
-	0xdc, 0x78, 0xf9, 0x2d, 0xdb, 0xff, 0xf1, 0xe9, 0xfe, 0x8b, 0x27, 0xfb, 0x87, 0xee, 0x74, 0xff,
-	0xbb, 0xbd, 0x0f, 0x3d, 0x83, 0xa5, 0x23, 0x68, 0xf8, 0x21, 0xc7, 0x48, 0x4d, 0x79, 0x60, 0x5b,
-	0x9a, 0xd8, 0x49, 0x89, 0x2d, 0xf9, 0xb0, 0x8c, 0xb5, 0x6e, 0xc0, 0x5f, 0x04, 0xce, 0x0b, 0xd8,
-	0x2c, 0xeb, 0x06, 0x3a, 0xbe, 0xd6, 0x3d, 0xc6, 0xa4, 0xf7, 0xee, 0xe8, 0x9e, 0x82, 0x43, 0x79,
-	0x1b, 0x39, 0x3f, 0x13, 0x78, 0x58, 0x02, 0xa6, 0x87, 0x60, 0xa5, 0xc2, 0x99, 0xee, 0x47, 0x77,
-	0xe8, 0xba, 0xe9, 0xe3, 0x59, 0xa4, 0xe4, 0xd2, 0xd3, 0x94, 0xce, 0x08, 0x1a, 0xab, 0x2d, 0xfa,
-	0x2e, 0x54, 0x73, 0x7f, 0x1b, 0x5e, 0xba, 0xa4, 0x9b, 0x50, 0x5b, 0xb0, 0x70, 0x8e, 0xd9, 0x40,
-	0x9a, 0x97, 0xa3, 0xca, 0xc7, 0x64, 0xf8, 0x53, 0x05, 0x76, 0x0a, 0xf2, 0x9f, 0xe5, 0xff, 0x83,
-	0x53, 0x94, 0x0b, 0xee, 0x23, 0x7d, 0x09, 0xad, 0x53, 0x25, 0x91, 0x5d, 0x5e, 0x87, 0xa5, 0x83,
-	0xde, 0x2d, 0x76, 0xde, 0x8a, 0xe8, 0xe1, 0xf7, 0x73, 0x4c, 0x54, 0x67, 0xf7, 0xd6, 0x78, 0x12,
-	0x8b, 0x28, 0x41, 0xe7, 0x5e, 0x9f, 0x3c, 0x21, 0xf4, 0x35, 0x81, 0xcd, 0xcf, 0x51, 0xf9, 0x17,
-	0xff, 0xbb, 0xfe, 0xe3, 0xd7, 0x7f, 0xfe, 0xf5, 0x6b, 0xe5, 0x03, 0xa7, 0x5b, 0xf8, 0xcf, 0x1d,
-	0x45, 0xe6, 0x9c, 0xd5, 0x37, 0xed, 0x88, 0xec, 0xbd, 0xaa, 0xeb, 0xef, 0xd5, 0xc1, 0xbf, 0x01,
-	0x00, 0x00, 0xff, 0xff, 0x9f, 0x7b, 0xa5, 0xa3, 0x58, 0x07, 0x00, 0x00,
+	// 995 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x4f, 0xe3, 0xc6,
+	0x1b, 0xde, 0x49, 0x1c, 0x48, 0xde, 0xfc, 0x80, 0x1f, 0x03, 0x81, 0x90, 0x2e, 0x90, 0xba, 0xad,
+	0x14, 0xa8, 0x70, 0x56, 0x41, 0x6a, 0x0a, 0x3d, 0x54, 0x44, 0xdd, 0x8a, 0x15, 0x6c, 0x8b, 0x06,
+	0xd4, 0xc3, 0x56, 0x5d, 0xcb, 0x38, 0x03, 0x4c, 0x71, 0x3c, 0xee, 0x78, 0x92, 0x2a, 0x3d, 0xae,
+	0x7a, 0xe9, 0xb5, 0xfd, 0x1c, 0x95, 0x7a, 0xee, 0x69, 0xbf, 0x43, 0xbf, 0x42, 0x2f, 0x3d, 0xf6,
+	0x5e, 0x89, 0x6a, 0x66, 0xec, 0x10, 0x6b, 0x1d, 0xf6, 0xd2, 0x4b, 0xe4, 0x99, 0xf7, 0x79, 0x9e,
+	0x79, 0xff, 0x03, 0x2c, 0xfb, 0x2c, 0x60, 0xc3, 0x41, 0x3b, 0x8c, 0xfa, 0xb1, 0x13, 0x09, 0x2e,
+	0x39, 0x9e, 0x33, 0x57, 0x8d, 0x6d, 0x1a, 0x8e, 0xf8, 0xb8, 0xed, 0x45, 0xac, 0x3d, 0xea, 0xb4,
+	0x7d, 0x2e, 0x68, 0xdb, 0xeb, 0xf7, 0x05, 0x8d, 0x13, 0x60, 0xe3, 0x71, 0x06, 0xd0, 0x67, 0xb1,
+	0xcf, 0x47, 0x54, 0x8c, 0x13, 0xeb, 0x56, 0xc6, 0x2a, 0xf8, 0x50, 0x52, 0xf3, 0x9b, 0xb2, 0xaf,
+	0x39, 0xbf, 0x0e, 0xa8, 0x06, 0x78, 0x61, 0xc8, 0xa5, 0x27, 0x19, 0x0f, 0x53, 0xed, 0xf5, 0x91,
+	0x17, 0xb0, 0xbe, 0x27, 0x69, 0x3b, 0xfd, 0x30, 0x06, 0xfb, 0x9f, 0x02, 0x2c, 0x7c, 0x41, 0xe5,
+	0xf7, 0x5c, 0xdc, 0x9e, 0xf1, 0x80, 0xf9, 0x63, 0x8c, 0xc1, 0x0a, 0xbd, 0x01, 0xad, 0xa3, 0x26,
+	0x6a, 0x55, 0x88, 0xfe, 0xc6, 0x6b, 0x30, 0x17, 0x69, 0x6b, 0xbd, 0xd0, 0x44, 0x2d, 0x8b, 0x24,
+	0x27, 0x7c, 0x01, 0x1b, 0x2c, 0xbc, 0x56, 0x31, 0xb8, 0x11, 0x15, 0x6e, 0xc4, 0x85, 0x74, 0xb5,
+	0x89, 0xd1, 0xb8, 0x5e, 0x6c, 0x16, 0x5b, 0xd5, 0xce, 0x86, 0x63, 0xe2, 0x77, 0xce, 0xb8, 0x90,
+	0x99, 0x97, 0xc8, 0x5a, 0xc2, 0x3d, 0xa3, 0x42, 0x19, 0xcf, 0x12, 0x22, 0x26, 0x50, 0xa7, 0xb3,
+	0x44, 0xad, 0xb7, 0x89, 0xd6, 0xe8, 0x0c, 0xcd, 0x25, 0xd6, 0xa7, 0xa1, 0x64, 0x72, 0xec, 0x06,
+	0xde, 0x25, 0x0d, 0xe2, 0x7a, 0x49, 0x4b, 0xed, 0xa4, 0x52, 0x19, 0x19, 0xe7, 0x59, 0x02, 0x3e,
+	0xd5, 0xd8, 0xa7, 0xa1, 0x14, 0x63, 0xb2, 0xc8, 0x32, 0x97, 0x8d, 0x23, 0x58, 0xc9, 0x81, 0xe1,
+	0xff, 0x43, 0xf1, 0x96, 0x8e, 0x75, 0xfe, 0x2c, 0xa2, 0x3e, 0xf1, 0x2a, 0x94, 0x46, 0x5e, 0x30,
+	0xa4, 0x3a, 0x7b, 0x15, 0x62, 0x0e, 0x87, 0x85, 0x8f, 0x91, 0xfd, 0x1b, 0x82, 0xe5, 0x37, 0x62,
+	0xc0, 0xdb, 0x60, 0xa9, 0xa8, 0xb5, 0xc4, 0x42, 0xaf, 0xfa, 0xfb, 0x5f, 0xaf, 0x8b, 0x73, 0xbb,
+	0x56, 0xfd, 0xee, 0xae, 0x48, 0xb4, 0x01, 0x3f, 0x85, 0xb2, 0x2e, 0x9f, 0xcf, 0x03, 0xad, 0xb9,
+	0xd8, 0xd9, 0x71, 0x74, 0x7f, 0x38, 0x5e, 0xc4, 0x9c, 0x51, 0xc7, 0x51, 0xed, 0xe5, 0x9c, 0x73,
+	0xff, 0x96, 0xca, 0xa3, 0xa4, 0xc9, 0xce, 0x12, 0x02, 0x99, 0x50, 0xf1, 0x3e, 0x94, 0xc4, 0x30,
+	0x98, 0x94, 0x6a, 0x73, 0x76, 0x56, 0x87, 0x01, 0x25, 0x06, 0x6b, 0xff, 0x5a, 0x80, 0x5a, 0x2e,
+	0x00, 0xef, 0xc3, 0x92, 0xa0, 0x03, 0x2e, 0xe9, 0x7d, 0xb9, 0x50, 0xb3, 0xd8, 0xb2, 0x7a, 0xa0,
+	0x22, 0x28, 0xfd, 0x8c, 0x0a, 0x75, 0x44, 0x16, 0x0d, 0x64, 0x52, 0x98, 0x0d, 0x28, 0x07, 0x5d,
+	0x57, 0xbb, 0x94, 0xa4, 0x67, 0x3e, 0xe8, 0x6a, 0x5f, 0xf1, 0xa7, 0x00, 0x37, 0x52, 0x46, 0xae,
+	0xf1, 0xb1, 0xdf, 0x44, 0xad, 0x6a, 0x67, 0x2b, 0xf5, 0xf1, 0x58, 0xca, 0xe8, 0x0d, 0x17, 0xe2,
+	0xe3, 0x47, 0xa4, 0xa2, 0x38, 0xfa, 0x80, 0x7b, 0x50, 0xbd, 0xf5, 0xae, 0x6e, 0xbd, 0x44, 0x81,
+	0x6a, 0x85, 0xed, 0x54, 0xe1, 0x44, 0x99, 0x72, 0x25, 0x40, 0xb3, 0x8c, 0xc6, 0x81, 0xf6, 0xcf,
+	0x08, 0x5c, 0x69, 0x81, 0xc7, 0xa9, 0xc0, 0x69, 0x37, 0x97, 0x3d, 0x1f, 0x74, 0xf5, 0x67, 0xcf,
+	0x82, 0x42, 0xd0, 0xb5, 0x2f, 0x61, 0x2d, 0xdf, 0x57, 0x7c, 0x9c, 0x89, 0x0f, 0x65, 0x6b, 0x90,
+	0xcb, 0xb9, 0xcf, 0x64, 0x19, 0x4d, 0x05, 0x6a, 0xff, 0x5d, 0x80, 0x5a, 0x2e, 0x01, 0x7f, 0x02,
+	0xf3, 0x37, 0xd4, 0xeb, 0x53, 0x91, 0x3e, 0xf0, 0x6e, 0xb6, 0x51, 0xcc, 0x0a, 0x39, 0xd6, 0x90,
+	0xe7, 0x9e, 0xf4, 0x6f, 0xa8, 0x20, 0x29, 0x03, 0x7f, 0x04, 0xeb, 0x2c, 0xfc, 0x96, 0xfa, 0xd2,
+	0x9d, 0xcc, 0x4e, 0x2a, 0xa6, 0x4a, 0x55, 0x26, 0x35, 0x63, 0x4e, 0xa7, 0xe0, 0x38, 0xe1, 0x6d,
+	0x02, 0x48, 0x36, 0xa0, 0x7c, 0x28, 0xdd, 0x81, 0x6a, 0x2e, 0x35, 0x08, 0x95, 0xe4, 0xe6, 0x79,
+	0x8c, 0x4f, 0xe1, 0x7f, 0x82, 0x4a, 0x31, 0x76, 0x93, 0x9d, 0x62, 0x35, 0xd1, 0xf4, 0x20, 0xe6,
+	0x06, 0xe2, 0x10, 0xc5, 0x48, 0xce, 0x55, 0x71, 0x7f, 0x68, 0x8c, 0xa0, 0x3a, 0x65, 0xc3, 0xdb,
+	0x50, 0x0d, 0x87, 0x03, 0x57, 0x21, 0x4c, 0x03, 0xa2, 0xd6, 0x02, 0x81, 0x70, 0x38, 0x20, 0xe6,
+	0x06, 0x7f, 0x08, 0x58, 0xad, 0x15, 0xf5, 0xfe, 0x94, 0x93, 0x66, 0xaf, 0x2d, 0x45, 0x54, 0x5c,
+	0x88, 0xf1, 0xc5, 0xc4, 0xd5, 0x0d, 0x28, 0x1b, 0x57, 0x79, 0xa8, 0xe3, 0xa8, 0x90, 0x79, 0x7d,
+	0xfe, 0x32, 0xb4, 0xaf, 0x60, 0x7d, 0x46, 0x07, 0xe1, 0x93, 0x6c, 0xdf, 0x99, 0xc4, 0x6f, 0x3d,
+	0xdc, 0x77, 0x99, 0xd2, 0x4e, 0x35, 0xa0, 0xfd, 0x1a, 0xc1, 0x5a, 0x3e, 0x05, 0xaf, 0xc3, 0xbc,
+	0x17, 0x31, 0x37, 0xdd, 0x36, 0x25, 0x32, 0xe7, 0x45, 0xec, 0x84, 0xea, 0x24, 0x28, 0xc3, 0x88,
+	0x8a, 0x98, 0xf1, 0x50, 0x07, 0x57, 0x22, 0xe0, 0x45, 0xec, 0x2b, 0x73, 0xa3, 0x26, 0x5f, 0xf2,
+	0x88, 0xf9, 0x26, 0xa8, 0xde, 0xa6, 0x7a, 0xbb, 0x2e, 0xd6, 0xea, 0x77, 0xa8, 0xb3, 0xfc, 0xf2,
+	0x6b, 0x6f, 0xef, 0x87, 0xa3, 0xbd, 0x17, 0x4f, 0xf6, 0x0e, 0x1c, 0x77, 0xef, 0x9b, 0xdd, 0xf7,
+	0x89, 0xc1, 0xe2, 0x2e, 0x54, 0xfc, 0x80, 0xd1, 0x50, 0xb5, 0x83, 0x2e, 0x5a, 0xa5, 0xd7, 0x50,
+	0xc4, 0x9a, 0x58, 0xc9, 0x63, 0x95, 0x0d, 0xf8, 0x59, 0xdf, 0x7e, 0x01, 0xab, 0x79, 0xb3, 0x82,
+	0x7b, 0x53, 0xb3, 0x65, 0x92, 0xf4, 0xce, 0x03, 0xb3, 0x95, 0xc9, 0x50, 0x3a, 0x64, 0xf6, 0x4f,
+	0x08, 0x56, 0x72, 0xc0, 0xf8, 0x00, 0x2c, 0x25, 0x9c, 0xe8, 0x7e, 0xf0, 0x80, 0xae, 0xa3, 0x7e,
+	0xcc, 0x86, 0xd7, 0x94, 0x46, 0x17, 0x2a, 0x93, 0xab, 0xe9, 0x6d, 0x5e, 0x79, 0xcb, 0x36, 0xef,
+	0xfc, 0x58, 0x80, 0xcd, 0x8c, 0xfc, 0x67, 0xe9, 0x1f, 0xf1, 0x73, 0x2a, 0x46, 0xcc, 0xa7, 0xf8,
+	0x25, 0xd4, 0xce, 0xa5, 0xa0, 0xde, 0x60, 0x1a, 0xa6, 0xba, 0x72, 0x2b, 0x3b, 0x96, 0x13, 0x22,
+	0xa1, 0xdf, 0x0d, 0x69, 0x2c, 0x1b, 0xdb, 0x33, 0xed, 0x71, 0xc4, 0xc3, 0x98, 0xda, 0x8f, 0x5a,
+	0xe8, 0x09, 0xc2, 0xaf, 0x10, 0xac, 0x7e, 0x4e, 0xa5, 0x7f, 0xf3, 0x9f, 0xeb, 0xef, 0xbc, 0xfa,
+	0xe3, 0xcf, 0x5f, 0x0a, 0xef, 0xd9, 0x5b, 0x99, 0x7f, 0x4e, 0x0e, 0x43, 0xf3, 0xce, 0x64, 0xe3,
+	0x1f, 0xa2, 0xdd, 0xcb, 0x39, 0xbd, 0xcd, 0xf7, 0xff, 0x1d, 0x00, 0xe1, 0x4f, 0x83, 0x93, 0x0d,
+	0x09, 0x00, 0x00,
 }
@@ -76,6 +76,8 @@ func (m *NetworkPolicy) Validate() error {
 
 	}
 
+	// no validation rules for IdentityLabels
+
 	return nil
 }
 
@@ -364,6 +366,20 @@ func (m *HttpNetworkPolicyRule) Validate() error {
 
 	}
 
+	// no validation rules for InjectIdentityHeaders
+
+	// no validation rules for TimeoutMs
+
+	if v, ok := interface{}(m.GetRetryPolicy()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return HttpNetworkPolicyRuleValidationError{
+				Field:  "RetryPolicy",
+				Reason: "embedded message failed validation",
+				Cause:  err,
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -398,6 +414,65 @@ func (e HttpNetworkPolicyRuleValidationError) Error() string {
 
 var _ error = HttpNetworkPolicyRuleValidationError{}
 
+// Validate checks the field values on HttpNetworkPolicyRule_RetryPolicy with
+// the rules defined in the proto definition for this message. If any rules
+// are violated, an error is returned.
+func (m *HttpNetworkPolicyRule_RetryPolicy) Validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if m.GetNumRetries() <= 0 {
+		return HttpNetworkPolicyRule_RetryPolicyValidationError{
+			Field:  "NumRetries",
+			Reason: "value must be greater than 0",
+		}
+	}
+
+	// no validation rules for PerTryTimeoutMs
+
+	if len(m.GetRetryOn()) < 1 {
+		return HttpNetworkPolicyRule_RetryPolicyValidationError{
+			Field:  "RetryOn",
+			Reason: "value length must be at least 1 bytes",
+		}
+	}
+
+	return nil
+}
+
+// HttpNetworkPolicyRule_RetryPolicyValidationError is the validation error
+// returned by HttpNetworkPolicyRule_RetryPolicy.Validate if the designated
+// constraints aren't met.
+type HttpNetworkPolicyRule_RetryPolicyValidationError struct {
+	Field  string
+	Reason string
+	Cause  error
+	Key    bool
+}
+
+// Error satisfies the builtin error interface
+func (e HttpNetworkPolicyRule_RetryPolicyValidationError) Error() string {
+	cause := ""
+	if e.Cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.Cause)
+	}
+
+	key := ""
+	if e.Key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sHttpNetworkPolicyRule_RetryPolicy.%s: %s%s",
+		key,
+		e.Field,
+		e.Reason,
+		cause)
+}
+
+var _ error = HttpNetworkPolicyRule_RetryPolicyValidationError{}
+
 // Validate checks the field values on KafkaNetworkPolicyRules with the rules
 // defined in the proto definition for this message. If any rules are
 // violated, an error is returned.
@@ -31,6 +31,7 @@ import (
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/option"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -169,7 +170,7 @@ func StartEnvoy(stateDir, logPath string, baseID uint64) *Envoy {
 
 	// Create static configuration
 	createBootstrap(bootstrapPath, nodeId, ingressClusterName, "version1",
-		xdsPath, egressClusterName, ingressClusterName, adminPath)
+		xdsPath, egressClusterName, ingressClusterName, adminPath, option.Config.ProxyTraceCollector)
 
 	log.Debugf("Envoy: Starting: %v", *e)
 
@@ -89,13 +89,13 @@ func (s *EnvoySuite) TestEnvoy(c *C) {
 	log.Debug("started Envoy")
 
 	log.Debug("adding listener1")
-	xdsServer.AddListener("listener1", policy.ParserTypeHTTP, "1.2.3.4", 8081, true, s.waitGroup)
+	xdsServer.AddListener("listener1", policy.ParserTypeHTTP, "1.2.3.4", 8081, true, nil, nil, s.waitGroup, nil)
 
 	log.Debug("adding listener2")
-	xdsServer.AddListener("listener2", policy.ParserTypeHTTP, "1.2.3.4", 8082, true, s.waitGroup)
+	xdsServer.AddListener("listener2", policy.ParserTypeHTTP, "1.2.3.4", 8082, true, nil, nil, s.waitGroup, nil)
 
 	log.Debug("adding listener3")
-	xdsServer.AddListener("listener3", policy.ParserTypeHTTP, "1.2.3.4", 8083, false, s.waitGroup)
+	xdsServer.AddListener("listener3", policy.ParserTypeHTTP, "1.2.3.4", 8083, false, nil, nil, s.waitGroup, nil)
 
 	err = s.waitForProxyCompletion()
 	c.Assert(err, IsNil)
@@ -113,7 +113,7 @@ func (s *EnvoySuite) TestEnvoy(c *C) {
 
 	// Add listener3 again
 	log.Debug("adding listener 3")
-	xdsServer.AddListener("listener3", policy.L7ParserType("test.headerparser"), "1.2.3.4", 8083, false, s.waitGroup)
+	xdsServer.AddListener("listener3", policy.L7ParserType("test.headerparser"), "1.2.3.4", 8083, false, nil, nil, s.waitGroup, nil)
 
 	err = s.waitForProxyCompletion()
 	c.Assert(err, IsNil)
@@ -164,7 +164,7 @@ func (s *EnvoySuite) TestEnvoyNACK(c *C) {
 	rName := "listener:22"
 
 	log.Debug("adding ", rName)
-	xdsServer.AddListener(rName, policy.ParserTypeHTTP, "1.2.3.4", 22, true, s.waitGroup)
+	xdsServer.AddListener(rName, policy.ParserTypeHTTP, "1.2.3.4", 22, true, nil, nil, s.waitGroup, nil)
 
 	err = s.waitForProxyCompletion()
 	c.Assert(err, Not(IsNil))
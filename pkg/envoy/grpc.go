@@ -39,11 +39,12 @@ var (
 // startXDSGRPCServer starts a gRPC server to serve xDS APIs using the given
 // resource watcher and network listener.
 // Returns a function that stops the GRPC server when called.
-func startXDSGRPCServer(listener net.Listener, ldsConfig, npdsConfig, nphdsConfig *xds.ResourceTypeConfiguration, resourceAccessTimeout time.Duration) context.CancelFunc {
+func startXDSGRPCServer(listener net.Listener, ldsConfig, cdsConfig, npdsConfig, nphdsConfig *xds.ResourceTypeConfiguration, resourceAccessTimeout time.Duration) context.CancelFunc {
 	grpcServer := grpc.NewServer()
 
 	xdsServer := xds.NewServer(map[string]*xds.ResourceTypeConfiguration{
 		ListenerTypeURL:           ldsConfig,
+		ClusterTypeURL:            cdsConfig,
 		NetworkPolicyTypeURL:      npdsConfig,
 		NetworkPolicyHostsTypeURL: nphdsConfig,
 	}, resourceAccessTimeout)
@@ -53,6 +54,7 @@ func startXDSGRPCServer(listener net.Listener, ldsConfig, npdsConfig, nphdsConfi
 	// Implement IncrementalAggregatedResources to support Incremental xDS.
 	//envoy_service_discovery_v2.RegisterAggregatedDiscoveryServiceServer(grpcServer, dsServer)
 	envoy_api_v2.RegisterListenerDiscoveryServiceServer(grpcServer, dsServer)
+	envoy_api_v2.RegisterClusterDiscoveryServiceServer(grpcServer, dsServer)
 	cilium.RegisterNetworkPolicyDiscoveryServiceServer(grpcServer, dsServer)
 	cilium.RegisterNetworkPolicyHostsDiscoveryServiceServer(grpcServer, dsServer)
 
@@ -88,6 +90,22 @@ func (s *xdsGRPCServer) FetchListeners(ctx net_context.Context, req *envoy_api_v
 	return nil, ErrNotImplemented
 }
 
+func (s *xdsGRPCServer) StreamClusters(stream envoy_api_v2.ClusterDiscoveryService_StreamClustersServer) error {
+	return (*xds.Server)(s).HandleRequestStream(stream.Context(), stream, ClusterTypeURL)
+}
+
+func (s *xdsGRPCServer) IncrementalClusters(stream envoy_api_v2.ClusterDiscoveryService_IncrementalClustersServer) error {
+	// TODO: https://github.com/cilium/cilium/issues/5051
+	// Implement IncrementalClusters to support Incremental xDS.
+	return ErrNotImplemented
+}
+
+func (s *xdsGRPCServer) FetchClusters(ctx net_context.Context, req *envoy_api_v2.DiscoveryRequest) (*envoy_api_v2.DiscoveryResponse, error) {
+	// The Fetch methods are only called via the REST API, which is not
+	// implemented in Cilium. Only the Stream methods are called over gRPC.
+	return nil, ErrNotImplemented
+}
+
 func (s *xdsGRPCServer) StreamNetworkPolicies(stream cilium.NetworkPolicyDiscoveryService_StreamNetworkPoliciesServer) error {
 	return (*xds.Server)(s).HandleRequestStream(stream.Context(), stream, NetworkPolicyTypeURL)
 }
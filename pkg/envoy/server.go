@@ -21,6 +21,8 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,13 +30,17 @@ import (
 	"github.com/cilium/cilium/pkg/completion"
 	"github.com/cilium/cilium/pkg/envoy/cilium"
 	envoy_api_v2 "github.com/cilium/cilium/pkg/envoy/envoy/api/v2"
+	envoy_api_v2_auth "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/auth"
+	envoy_api_v2_cluster "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/cluster"
 	envoy_api_v2_core "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/core"
 	envoy_api_v2_listener "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/listener"
 	envoy_api_v2_route "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/route"
 	envoy_config_bootstrap_v2 "github.com/cilium/cilium/pkg/envoy/envoy/config/bootstrap/v2"
+	envoy_config_trace_v2 "github.com/cilium/cilium/pkg/envoy/envoy/config/trace/v2"
 	"github.com/cilium/cilium/pkg/envoy/xds"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/cilium/cilium/pkg/proxy/logger"
@@ -43,6 +49,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/golang/protobuf/ptypes/struct"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/spf13/viper"
 )
 
@@ -60,6 +67,22 @@ var (
 const (
 	egressClusterName  = "egress-cluster"
 	ingressClusterName = "ingress-cluster"
+	zipkinClusterName  = "zipkin-collector"
+
+	// zipkinCollectorEndpoint is the API endpoint of the Zipkin collector
+	// spans are reported to.
+	zipkinCollectorEndpoint = "/api/v1/spans"
+
+	// traceparentHeader is the W3C trace context header. Envoy propagates
+	// B3 headers only, the W3C trace context of a request is attached to
+	// the span as a tag so that both can be correlated.
+	traceparentHeader = "traceparent"
+
+	// traceparentFormat is the W3C trace context of the span of the proxy,
+	// built from the B3 headers Envoy injects into the upstream request.
+	// The B3 sampling decision is "0" or "1", giving trace flags of "00"
+	// or "01".
+	traceparentFormat = "00-%REQ(x-b3-traceid)%-%REQ(x-b3-spanid)%-0%REQ(x-b3-sampled)%"
 )
 
 // XDSServer provides a high-lever interface to manage resources published
@@ -88,6 +111,15 @@ type XDSServer struct {
 	// mutex must be held when accessing this.
 	listeners map[string]struct{}
 
+	// clusterMutator publishes cluster updates to Envoy proxies.
+	clusterMutator xds.AckingResourceMutator
+
+	// clusterListeners is the set of names of listeners forwarding to a
+	// dedicated cluster which originates TLS connections or enforces
+	// circuit breakers.
+	// mutex must be held when accessing this.
+	clusterListeners map[string]struct{}
+
 	// networkPolicyCache publishes network policy configuration updates to
 	// Envoy proxies.
 	networkPolicyCache *xds.Cache
@@ -135,6 +167,13 @@ func StartXDSServer(stateDir string) *XDSServer {
 		AckObserver: ldsMutator,
 	}
 
+	cdsCache := xds.NewCache()
+	cdsMutator := xds.NewAckingResourceMutatorWrapper(cdsCache, xds.IstioNodeToIP)
+	cdsConfig := &xds.ResourceTypeConfiguration{
+		Source:      cdsCache,
+		AckObserver: cdsMutator,
+	}
+
 	npdsCache := xds.NewCache()
 	npdsMutator := xds.NewAckingResourceMutatorWrapper(npdsCache, xds.IstioNodeToIP)
 	npdsConfig := &xds.ResourceTypeConfiguration{
@@ -147,7 +186,7 @@ func StartXDSServer(stateDir string) *XDSServer {
 		AckObserver: nil, // We don't wait for ACKs for those resources.
 	}
 
-	stopServer := startXDSGRPCServer(socketListener, ldsConfig, npdsConfig, nphdsConfig, 5*time.Second)
+	stopServer := startXDSGRPCServer(socketListener, ldsConfig, cdsConfig, npdsConfig, nphdsConfig, 5*time.Second)
 
 	listenerProto := &envoy_api_v2.Listener{
 		Address: &envoy_api_v2_core.Address{
@@ -164,8 +203,9 @@ func StartXDSServer(stateDir string) *XDSServer {
 		ListenerFilters: []*envoy_api_v2_listener.ListenerFilter{{
 			Name: "cilium.bpf_metadata",
 			Config: &structpb.Struct{Fields: map[string]*structpb.Value{
-				"is_ingress": {Kind: &structpb.Value_BoolValue{BoolValue: false}},
-				"bpf_root":   {Kind: &structpb.Value_StringValue{StringValue: bpf.GetMapRoot()}},
+				"is_ingress":                  {Kind: &structpb.Value_BoolValue{BoolValue: false}},
+				"use_original_source_address": {Kind: &structpb.Value_BoolValue{BoolValue: false}},
+				"bpf_root":                    {Kind: &structpb.Value_StringValue{StringValue: bpf.GetMapRoot()}},
 			}},
 		}},
 	}
@@ -177,6 +217,18 @@ func StartXDSServer(stateDir string) *XDSServer {
 			Name: "envoy.http_connection_manager",
 			Config: &structpb.Struct{Fields: map[string]*structpb.Value{
 				"stat_prefix": {Kind: &structpb.Value_StringValue{StringValue: "proxy"}},
+				// Detect HTTP/2 with prior knowledge (h2c), including gRPC,
+				// by the connection preface so that HTTP/2 connections are
+				// not parsed as HTTP/1.1. Policy is enforced per stream.
+				"codec_type":             {Kind: &structpb.Value_StringValue{StringValue: "AUTO"}},
+				"http2_protocol_options": {Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{}}}},
+				// Policy is enforced on the upgrade request of a WebSocket,
+				// the upgraded connection is then proxied transparently.
+				"upgrade_configs": {Kind: &structpb.Value_ListValue{ListValue: &structpb.ListValue{Values: []*structpb.Value{
+					{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+						"upgrade_type": {Kind: &structpb.Value_StringValue{StringValue: "websocket"}},
+					}}}},
+				}}}},
 				"http_filters": {Kind: &structpb.Value_ListValue{ListValue: &structpb.ListValue{Values: []*structpb.Value{
 					{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
 						"name": {Kind: &structpb.Value_StringValue{StringValue: "cilium.l7policy"}},
@@ -199,6 +251,27 @@ func StartXDSServer(stateDir string) *XDSServer {
 								{Kind: &structpb.Value_StringValue{StringValue: "*"}},
 							}}}},
 							"routes": {Kind: &structpb.Value_ListValue{ListValue: &structpb.ListValue{Values: []*structpb.Value{
+								// Upgraded connections are long-lived and
+								// cannot be retried, so the request timeout
+								// and the retry policy of the default route
+								// must not apply to them. Only WebSocket
+								// upgrades are enabled, the header value is
+								// matched case-insensitively.
+								{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+									"match": {Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+										"prefix": {Kind: &structpb.Value_StringValue{StringValue: "/"}},
+										"headers": {Kind: &structpb.Value_ListValue{ListValue: &structpb.ListValue{Values: []*structpb.Value{
+											{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+												"name":        {Kind: &structpb.Value_StringValue{StringValue: "upgrade"}},
+												"regex_match": {Kind: &structpb.Value_StringValue{StringValue: "[wW][eE][bB][sS][oO][cC][kK][eE][tT]"}},
+											}}}},
+										}}}},
+									}}}},
+									"route": {Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+										// "cluster": {Kind: &structpb.Value_StringValue{StringValue: "cluster1"}},
+										"timeout": {Kind: &structpb.Value_StringValue{StringValue: "0s"}},
+									}}}},
+								}}}},
 								{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
 									"match": {Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
 										"prefix": {Kind: &structpb.Value_StringValue{StringValue: "/"}},
@@ -220,6 +293,17 @@ func StartXDSServer(stateDir string) *XDSServer {
 		}},
 	}
 
+	if option.Config.ProxyLuaScript != "" {
+		code, err := ioutil.ReadFile(option.Config.ProxyLuaScript)
+		if err != nil {
+			log.WithError(err).Fatalf("Envoy: Failed to read Lua script %s", option.Config.ProxyLuaScript)
+		}
+		// The script is run after the policy filter so that it only
+		// sees the requests allowed by policy.
+		httpFilters := httpFilterChainProto.Filters[1].Config.Fields["http_filters"].GetListValue()
+		httpFilters.Values = insertHTTPFilter(httpFilters.Values, getLuaFilter(string(code)))
+	}
+
 	tcpFilterChainProto := &envoy_api_v2_listener.FilterChain{
 		Filters: []*envoy_api_v2_listener.Filter{{
 			Name: "cilium.network",
@@ -248,6 +332,8 @@ func StartXDSServer(stateDir string) *XDSServer {
 		tcpFilterChainProto:    tcpFilterChainProto,
 		listenerMutator:        ldsMutator,
 		listeners:              make(map[string]struct{}),
+		clusterMutator:         cdsMutator,
+		clusterListeners:       make(map[string]struct{}),
 		networkPolicyCache:     npdsCache,
 		NetworkPolicyMutator:   npdsMutator,
 		networkPolicyEndpoints: make(map[string]logger.EndpointUpdater),
@@ -255,8 +341,37 @@ func StartXDSServer(stateDir string) *XDSServer {
 	}
 }
 
-// AddListener adds a listener to a running Envoy proxy.
-func (s *XDSServer) AddListener(name string, kind policy.L7ParserType, endpointPolicyName string, port uint16, isIngress bool, wg *completion.WaitGroup) {
+// getLuaFilter returns the configuration of an HTTP filter running the
+// given Lua code.
+func getLuaFilter(code string) *structpb.Value {
+	return &structpb.Value{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+		"name": {Kind: &structpb.Value_StringValue{StringValue: "envoy.lua"}},
+		"config": {Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"inline_code": {Kind: &structpb.Value_StringValue{StringValue: code}},
+		}}}},
+	}}}}
+}
+
+// insertHTTPFilter inserts filter into the list of HTTP filters right
+// before the router filter, which must be the last one.
+func insertHTTPFilter(filters []*structpb.Value, filter *structpb.Value) []*structpb.Value {
+	router := filters[len(filters)-1]
+	return append(filters[:len(filters)-1:len(filters)-1], filter, router)
+}
+
+// getListenerClusterName returns the name of the cluster dedicated to the
+// given listener.
+func getListenerClusterName(listenerName string) string {
+	return listenerName + "-cluster"
+}
+
+// AddListener adds a listener to a running Envoy proxy. If tls is not nil,
+// HTTP requests are forwarded to their original destination over TLS
+// connections originated with the given TLS context. If cb is not nil, the
+// connections and pending HTTP requests forwarded by the listener are limited
+// by the given circuit breaker. If callback is not nil, it is called with the
+// result of the listener update once Envoy has acknowledged or rejected it.
+func (s *XDSServer) AddListener(name string, kind policy.L7ParserType, endpointPolicyName string, port uint16, isIngress bool, tls *api.TLSContext, cb *api.CircuitBreaker, wg *completion.WaitGroup, callback func(err error)) {
 	log.Debugf("Envoy: %s AddListener %s", kind, name)
 
 	s.mutex.Lock()
@@ -267,19 +382,38 @@ func (s *XDSServer) AddListener(name string, kind policy.L7ParserType, endpointP
 	}
 	s.listeners[name] = struct{}{}
 
-	s.mutex.Unlock()
-
 	clusterName := egressClusterName
 	if isIngress {
 		clusterName = ingressClusterName
 	}
 
+	if kind != policy.ParserTypeHTTP {
+		tls, cb = nil, nil
+	}
+	listenerCluster := tls != nil || cb != nil
+	if listenerCluster {
+		clusterName = getListenerClusterName(name)
+		s.clusterListeners[name] = struct{}{}
+		s.clusterMutator.Upsert(ClusterTypeURL, clusterName, getListenerCluster(clusterName, tls, cb), []string{"127.0.0.1"}, wg.AddCompletion())
+	}
+
+	s.mutex.Unlock()
+
 	// Fill in the listener-specific parts.
 	listenerConf := proto.Clone(s.listenerProto).(*envoy_api_v2.Listener)
 	if kind == policy.ParserTypeHTTP {
 		listenerConf.FilterChains = append(listenerConf.FilterChains, proto.Clone(s.httpFilterChainProto).(*envoy_api_v2_listener.FilterChain))
 		listenerConf.FilterChains[0].Filters[1].Config.Fields["http_filters"].GetListValue().Values[0].GetStructValue().Fields["config"].GetStructValue().Fields["policy_name"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: endpointPolicyName}}
-		listenerConf.FilterChains[0].Filters[1].Config.Fields["route_config"].GetStructValue().Fields["virtual_hosts"].GetListValue().Values[0].GetStructValue().Fields["routes"].GetListValue().Values[0].GetStructValue().Fields["route"].GetStructValue().Fields["cluster"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: clusterName}}
+		routeConfig := listenerConf.FilterChains[0].Filters[1].Config.Fields["route_config"].GetStructValue()
+		for _, route := range routeConfig.Fields["virtual_hosts"].GetListValue().Values[0].GetStructValue().Fields["routes"].GetListValue().Values {
+			route.GetStructValue().Fields["route"].GetStructValue().Fields["cluster"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: clusterName}}
+		}
+		if listenerCluster {
+			// The listener and the cluster are delivered by
+			// different xDS streams, the listener must not be
+			// rejected if it arrives first.
+			routeConfig.Fields["validate_clusters"] = &structpb.Value{Kind: &structpb.Value_BoolValue{BoolValue: false}}
+		}
 	} else {
 		listenerConf.FilterChains = append(listenerConf.FilterChains, proto.Clone(s.tcpFilterChainProto).(*envoy_api_v2_listener.FilterChain))
 		listenerConf.FilterChains[0].Filters[0].Config.Fields["policy_name"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: endpointPolicyName}}
@@ -287,13 +421,39 @@ func (s *XDSServer) AddListener(name string, kind policy.L7ParserType, endpointP
 		listenerConf.FilterChains[0].Filters[1].Config.Fields["cluster"] = &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: clusterName}}
 	}
 
+	if kind == policy.ParserTypeHTTP && option.Config.ProxyTraceCollector != "" {
+		listenerConf.FilterChains[0].Filters[1].Config.Fields["tracing"] = getHTTPTracingConfig(isIngress, option.Config.ProxyTraceSampling)
+		routeConfig := listenerConf.FilterChains[0].Filters[1].Config.Fields["route_config"].GetStructValue()
+		addTraceparentPropagation(routeConfig.Fields["virtual_hosts"].GetListValue().Values[0].GetStructValue().Fields["routes"].GetListValue())
+	}
+
 	listenerConf.Name = name
 	listenerConf.Address.GetSocketAddress().PortSpecifier = &envoy_api_v2_core.SocketAddress_PortValue{PortValue: uint32(port)}
 	if isIngress {
 		listenerConf.ListenerFilters[0].Config.Fields["is_ingress"].GetKind().(*structpb.Value_BoolValue).BoolValue = true
+		listenerConf.ListenerFilters[0].Config.Fields["use_original_source_address"].GetKind().(*structpb.Value_BoolValue).BoolValue = option.Config.ProxyTransparent
+	}
+
+	s.listenerMutator.Upsert(ListenerTypeURL, name, listenerConf, []string{"127.0.0.1"}, wg.AddCompletionWithCallback(callback))
+}
+
+// UpdateListenerCluster updates the TLS context and the circuit breaker of
+// the cluster dedicated to an existing HTTP listener. The listener itself is
+// not changed so that its connections are retained. Returns false if the
+// listener forwards to a shared cluster, it must then be added again to
+// apply the change.
+func (s *XDSServer) UpdateListenerCluster(name string, tls *api.TLSContext, cb *api.CircuitBreaker, wg *completion.WaitGroup) (xds.AckingResourceMutatorRevertFunc, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.clusterListeners[name]; !ok {
+		return nil, false
 	}
 
-	s.listenerMutator.Upsert(ListenerTypeURL, name, listenerConf, []string{"127.0.0.1"}, wg.AddCompletion())
+	log.Debugf("Envoy: UpdateListenerCluster %s", name)
+
+	clusterName := getListenerClusterName(name)
+	return s.clusterMutator.Upsert(ClusterTypeURL, clusterName, getListenerCluster(clusterName, tls, cb), []string{"127.0.0.1"}, wg.AddCompletion()), true
 }
 
 // RemoveListener removes an existing Envoy Listener.
@@ -305,15 +465,30 @@ func (s *XDSServer) RemoveListener(name string, wg *completion.WaitGroup) xds.Ac
 		log.Fatalf("Envoy: Attempt to remove non-existent listener: %s", name)
 	}
 	delete(s.listeners, name)
+	_, listenerCluster := s.clusterListeners[name]
+	delete(s.clusterListeners, name)
 	s.mutex.Unlock()
 
 	listenerRevertFunc := s.listenerMutator.Delete(ListenerTypeURL, name, []string{"127.0.0.1"}, wg.AddCompletion())
 
+	var clusterRevertFunc xds.AckingResourceMutatorRevertFunc
+	if listenerCluster {
+		clusterRevertFunc = s.clusterMutator.Delete(ClusterTypeURL, getListenerClusterName(name), []string{"127.0.0.1"}, wg.AddCompletion())
+	}
+
 	return func(completion *completion.Completion) {
 		s.mutex.Lock()
 		s.listeners[name] = struct{}{}
+		if listenerCluster {
+			s.clusterListeners[name] = struct{}{}
+		}
 		s.mutex.Unlock()
 
+		// The completion is only used to wait for the listener, the
+		// cluster is restored on a best-effort basis.
+		if clusterRevertFunc != nil {
+			clusterRevertFunc(nil)
+		}
 		listenerRevertFunc(completion)
 	}
 }
@@ -333,6 +508,19 @@ func getL7Rule(l7 *api.PortRuleL7) *cilium.L7NetworkPolicyRule {
 	return rule // No ruleRef
 }
 
+// getHTTPRetryPolicy returns the retry policy of an HTTP rule, or nil if the
+// rule does not retry requests.
+func getHTTPRetryPolicy(r *api.HTTPRetryPolicy) *cilium.HttpNetworkPolicyRule_RetryPolicy {
+	if r == nil {
+		return nil
+	}
+	return &cilium.HttpNetworkPolicyRule_RetryPolicy{
+		NumRetries:      r.NumRetries,
+		PerTryTimeoutMs: uint64(r.GetPerTryTimeout() / time.Millisecond),
+		RetryOn:         r.GetRetryOn(),
+	}
+}
+
 func getHTTPRule(h *api.PortRuleHTTP) (headers []*envoy_api_v2_route.HeaderMatcher, ruleRef string) {
 	// Count the number of header matches we need
 	cnt := len(h.Headers)
@@ -398,27 +586,218 @@ func getHTTPRule(h *api.PortRuleHTTP) (headers []*envoy_api_v2_route.HeaderMatch
 	return
 }
 
-func createBootstrap(filePath string, name, cluster, version string, xdsSock, egressClusterName, ingressClusterName string, adminPath string) {
+// getHTTPTracingConfig returns the tracing configuration of the HTTP
+// connection manager. Requests carrying a B3 trace context are always traced,
+// requests without trace context start a new trace at the given sampling
+// percentage.
+func getHTTPTracingConfig(isIngress bool, samplingPercent int) *structpb.Value {
+	operationName := "EGRESS"
+	if isIngress {
+		operationName = "INGRESS"
+	}
+
+	return &structpb.Value{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+		"operation_name": {Kind: &structpb.Value_StringValue{StringValue: operationName}},
+		"request_headers_for_tags": {Kind: &structpb.Value_ListValue{ListValue: &structpb.ListValue{Values: []*structpb.Value{
+			{Kind: &structpb.Value_StringValue{StringValue: traceparentHeader}},
+		}}}},
+		"random_sampling": {Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"value": {Kind: &structpb.Value_NumberValue{NumberValue: float64(samplingPercent)}},
+		}}}},
+	}}}}
+}
+
+// addTraceparentPropagation makes the default route of routes propagate the
+// W3C trace context of the span of the proxy upstream. Envoy only continues
+// B3 traces, requests which already carry a W3C trace context are thus
+// forwarded with their traceparent header unchanged by a copy of the default
+// route.
+func addTraceparentPropagation(routes *structpb.ListValue) {
+	last := len(routes.Values) - 1
+	defaultRoute := routes.Values[last]
+
+	passthrough := proto.Clone(defaultRoute).(*structpb.Value)
+	passthrough.GetStructValue().Fields["match"].GetStructValue().Fields["headers"] = &structpb.Value{Kind: &structpb.Value_ListValue{ListValue: &structpb.ListValue{Values: []*structpb.Value{
+		{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"name":          {Kind: &structpb.Value_StringValue{StringValue: traceparentHeader}},
+			"present_match": {Kind: &structpb.Value_BoolValue{BoolValue: true}},
+		}}}},
+	}}}}
+
+	defaultRoute.GetStructValue().Fields["request_headers_to_add"] = &structpb.Value{Kind: &structpb.Value_ListValue{ListValue: &structpb.ListValue{Values: []*structpb.Value{
+		{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"header": {Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"key":   {Kind: &structpb.Value_StringValue{StringValue: traceparentHeader}},
+				"value": {Kind: &structpb.Value_StringValue{StringValue: traceparentFormat}},
+			}}}},
+			"append": {Kind: &structpb.Value_BoolValue{BoolValue: false}},
+		}}}},
+	}}}}
+
+	routes.Values = append(routes.Values[:last], passthrough, defaultRoute)
+}
+
+// getZipkinTracing returns the Envoy tracing configuration and the cluster
+// of the Zipkin compatible collector at collector (host:port).
+func getZipkinTracing(collector string) (*envoy_config_trace_v2.Tracing, *envoy_api_v2.Cluster, error) {
+	host, portStr, err := net.SplitHostPort(collector)
+	if err != nil {
+		return nil, nil, err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid port '%s' of trace collector: %s", portStr, err)
+	}
+
+	// Resolve the collector via DNS unless an IP address is given
+	clusterType := envoy_api_v2.Cluster_STRICT_DNS
+	if net.ParseIP(host) != nil {
+		clusterType = envoy_api_v2.Cluster_STATIC
+	}
+
+	cluster := &envoy_api_v2.Cluster{
+		Name:           zipkinClusterName,
+		Type:           clusterType,
+		ConnectTimeout: &duration.Duration{Seconds: 1, Nanos: 0},
+		LbPolicy:       envoy_api_v2.Cluster_ROUND_ROBIN,
+		Hosts: []*envoy_api_v2_core.Address{
+			{
+				Address: &envoy_api_v2_core.Address_SocketAddress{
+					SocketAddress: &envoy_api_v2_core.SocketAddress{
+						Protocol:      envoy_api_v2_core.SocketAddress_TCP,
+						Address:       host,
+						PortSpecifier: &envoy_api_v2_core.SocketAddress_PortValue{PortValue: uint32(port)},
+					},
+				},
+			},
+		},
+	}
+
+	tracing := &envoy_config_trace_v2.Tracing{
+		Http: &envoy_config_trace_v2.Tracing_Http{
+			Name: "envoy.zipkin",
+			Config: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"collector_cluster":  {Kind: &structpb.Value_StringValue{StringValue: zipkinClusterName}},
+				"collector_endpoint": {Kind: &structpb.Value_StringValue{StringValue: zipkinCollectorEndpoint}},
+				// W3C trace contexts require 128 bit trace IDs
+				"trace_id_128bit": {Kind: &structpb.Value_BoolValue{BoolValue: true}},
+			}},
+		},
+	}
+
+	return tracing, cluster, nil
+}
+
+// getOriginalDstCluster returns a cluster forwarding requests to their
+// original destination. Requests are forwarded with the HTTP version of the
+// downstream connection, HTTP/2 streams are thus not downgraded to HTTP/1.1,
+// which would break gRPC.
+func getOriginalDstCluster(name string) *envoy_api_v2.Cluster {
+	return &envoy_api_v2.Cluster{
+		Name:                 name,
+		Type:                 envoy_api_v2.Cluster_ORIGINAL_DST,
+		ConnectTimeout:       &duration.Duration{Seconds: 1, Nanos: 0},
+		CleanupInterval:      &duration.Duration{Seconds: 1, Nanos: 500000000},
+		LbPolicy:             envoy_api_v2.Cluster_ORIGINAL_DST_LB,
+		ProtocolSelection:    envoy_api_v2.Cluster_USE_DOWNSTREAM_PROTOCOL,
+		Http2ProtocolOptions: &envoy_api_v2_core.Http2ProtocolOptions{},
+	}
+}
+
+// getXDSConfigSource returns the configuration source of the resources
+// served by the xDS gRPC server of the agent.
+func getXDSConfigSource() *envoy_api_v2_core.ConfigSource {
+	return &envoy_api_v2_core.ConfigSource{
+		ConfigSourceSpecifier: &envoy_api_v2_core.ConfigSource_ApiConfigSource{
+			ApiConfigSource: &envoy_api_v2_core.ApiConfigSource{
+				ApiType: envoy_api_v2_core.ApiConfigSource_GRPC,
+				GrpcServices: []*envoy_api_v2_core.GrpcService{
+					{
+						TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+							EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+								ClusterName: "xds-grpc-cilium",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getListenerCluster returns a cluster dedicated to a listener, which
+// forwards requests to their original destination. If tls is not nil, the
+// connections are originated over TLS with the given TLS context. If cb is not
+// nil, the connections and pending requests are limited by the given circuit
+// breaker.
+func getListenerCluster(name string, tls *api.TLSContext, cb *api.CircuitBreaker) *envoy_api_v2.Cluster {
+	cluster := getOriginalDstCluster(name)
+	if tls != nil {
+		cluster.TlsContext = getUpstreamTLSContext(tls)
+	}
+	if cb != nil {
+		cluster.CircuitBreakers = getCircuitBreakers(cb)
+	}
+	return cluster
+}
+
+// getCircuitBreakers returns the circuit breakers of a cluster enforcing the
+// limits of the given circuit breaker. Envoy's defaults apply to the limits
+// which are not set. Requests overflowing a limit are answered with a 503
+// response with the "x-envoy-overloaded" header.
+func getCircuitBreakers(cb *api.CircuitBreaker) *envoy_api_v2_cluster.CircuitBreakers {
+	thresholds := &envoy_api_v2_cluster.CircuitBreakers_Thresholds{}
+	if cb.MaxConnections > 0 {
+		thresholds.MaxConnections = &wrappers.UInt32Value{Value: cb.MaxConnections}
+	}
+	if cb.MaxPendingRequests > 0 {
+		thresholds.MaxPendingRequests = &wrappers.UInt32Value{Value: cb.MaxPendingRequests}
+	}
+	return &envoy_api_v2_cluster.CircuitBreakers{
+		Thresholds: []*envoy_api_v2_cluster.CircuitBreakers_Thresholds{thresholds},
+	}
+}
+
+// getTLSFilename returns the path of a file of a TLS context within the TLS
+// directory. Paths leaving the directory are rejected when rules are
+// sanitized, they are confined to the directory here nonetheless.
+func getTLSFilename(path string) string {
+	return filepath.Join(option.Config.ProxyTLSDir, filepath.Clean("/"+path))
+}
+
+// getUpstreamTLSContext returns the context of TLS connections originated
+// with the given TLS context. The certificate of the upstream service is
+// verified against the trusted CAs of the TLS context.
+func getUpstreamTLSContext(tls *api.TLSContext) *envoy_api_v2_auth.UpstreamTlsContext {
+	return &envoy_api_v2_auth.UpstreamTlsContext{
+		CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
+			TlsCertificates: []*envoy_api_v2_auth.TlsCertificate{{
+				CertificateChain: &envoy_api_v2_core.DataSource{
+					Specifier: &envoy_api_v2_core.DataSource_Filename{Filename: getTLSFilename(tls.Certificate)},
+				},
+				PrivateKey: &envoy_api_v2_core.DataSource{
+					Specifier: &envoy_api_v2_core.DataSource_Filename{Filename: getTLSFilename(tls.PrivateKey)},
+				},
+			}},
+			ValidationContextType: &envoy_api_v2_auth.CommonTlsContext_ValidationContext{
+				ValidationContext: &envoy_api_v2_auth.CertificateValidationContext{
+					TrustedCa: &envoy_api_v2_core.DataSource{
+						Specifier: &envoy_api_v2_core.DataSource_Filename{Filename: getTLSFilename(tls.TrustedCA)},
+					},
+				},
+			},
+		},
+		Sni: tls.ServerName,
+	}
+}
+
+func createBootstrap(filePath string, name, cluster, version string, xdsSock, egressClusterName, ingressClusterName string, adminPath string, traceCollector string) {
 	bs := &envoy_config_bootstrap_v2.Bootstrap{
 		Node: &envoy_api_v2_core.Node{Id: name, Cluster: cluster, Metadata: nil, Locality: nil, BuildVersion: version},
 		StaticResources: &envoy_config_bootstrap_v2.Bootstrap_StaticResources{
 			Clusters: []*envoy_api_v2.Cluster{
-				{
-					Name:              egressClusterName,
-					Type:              envoy_api_v2.Cluster_ORIGINAL_DST,
-					ConnectTimeout:    &duration.Duration{Seconds: 1, Nanos: 0},
-					CleanupInterval:   &duration.Duration{Seconds: 1, Nanos: 500000000},
-					LbPolicy:          envoy_api_v2.Cluster_ORIGINAL_DST_LB,
-					ProtocolSelection: envoy_api_v2.Cluster_USE_DOWNSTREAM_PROTOCOL,
-				},
-				{
-					Name:              ingressClusterName,
-					Type:              envoy_api_v2.Cluster_ORIGINAL_DST,
-					ConnectTimeout:    &duration.Duration{Seconds: 1, Nanos: 0},
-					CleanupInterval:   &duration.Duration{Seconds: 1, Nanos: 500000000},
-					LbPolicy:          envoy_api_v2.Cluster_ORIGINAL_DST_LB,
-					ProtocolSelection: envoy_api_v2.Cluster_USE_DOWNSTREAM_PROTOCOL,
-				},
+				getOriginalDstCluster(egressClusterName),
+				getOriginalDstCluster(ingressClusterName),
 				{
 					Name:           "xds-grpc-cilium",
 					Type:           envoy_api_v2.Cluster_STATIC,
@@ -435,22 +814,8 @@ func createBootstrap(filePath string, name, cluster, version string, xdsSock, eg
 			},
 		},
 		DynamicResources: &envoy_config_bootstrap_v2.Bootstrap_DynamicResources{
-			LdsConfig: &envoy_api_v2_core.ConfigSource{
-				ConfigSourceSpecifier: &envoy_api_v2_core.ConfigSource_ApiConfigSource{
-					ApiConfigSource: &envoy_api_v2_core.ApiConfigSource{
-						ApiType: envoy_api_v2_core.ApiConfigSource_GRPC,
-						GrpcServices: []*envoy_api_v2_core.GrpcService{
-							{
-								TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
-									EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
-										ClusterName: "xds-grpc-cilium",
-									},
-								},
-							},
-						},
-					},
-				},
-			},
+			LdsConfig: getXDSConfigSource(),
+			CdsConfig: getXDSConfigSource(),
 		},
 		Admin: &envoy_config_bootstrap_v2.Admin{
 			AccessLogPath: "/dev/null",
@@ -462,6 +827,15 @@ func createBootstrap(filePath string, name, cluster, version string, xdsSock, eg
 		},
 	}
 
+	if traceCollector != "" {
+		tracing, collectorCluster, err := getZipkinTracing(traceCollector)
+		if err != nil {
+			log.WithError(err).Fatal("Envoy: Invalid trace collector address")
+		}
+		bs.Tracing = tracing
+		bs.StaticResources.Clusters = append(bs.StaticResources.Clusters, collectorCluster)
+	}
+
 	log.Debugf("Envoy: Bootstrap: %s", bs)
 	data, err := proto.Marshal(bs)
 	if err != nil {
@@ -504,7 +878,12 @@ func getPortNetworkPolicyRule(sel api.EndpointSelector, l7Parser policy.L7Parser
 			httpRules := make([]*cilium.HttpNetworkPolicyRule, 0, len(l7Rules.HTTP))
 			for _, l7 := range l7Rules.HTTP {
 				headers, _ := getHTTPRule(&l7)
-				httpRules = append(httpRules, &cilium.HttpNetworkPolicyRule{Headers: headers})
+				httpRules = append(httpRules, &cilium.HttpNetworkPolicyRule{
+					Headers:               headers,
+					InjectIdentityHeaders: l7.InjectIdentityHeaders,
+					TimeoutMs:             uint64(l7.GetTimeout() / time.Millisecond),
+					RetryPolicy:           getHTTPRetryPolicy(l7.Retries),
+				})
 			}
 			SortHTTPNetworkPolicyRules(httpRules)
 			r.L7 = &cilium.PortNetworkPolicyRule_HttpRules{
@@ -516,6 +895,9 @@ func getPortNetworkPolicyRule(sel api.EndpointSelector, l7Parser policy.L7Parser
 	case policy.ParserTypeKafka:
 		// TODO: Support Kafka. For now, just ignore any Kafka L7 rule.
 
+	case policy.ParserTypeDNS:
+		// DNS rules are enforced by the DNS proxy of the agent.
+
 	default:
 		// Assume unknown parser types use a Key-Value Pair policy
 		if len(l7Rules.L7) > 0 {
@@ -603,6 +985,67 @@ func getDirectionNetworkPolicy(l4Policy policy.L4PolicyMap, policyEnforced bool,
 	return PerPortPolicies
 }
 
+// injectsIdentityHeaders returns true if any of the given HTTP rules injects
+// identity headers into the requests it allows.
+func injectsIdentityHeaders(l7Rules api.L7Rules) bool {
+	for _, h := range l7Rules.HTTP {
+		if h.InjectIdentityHeaders {
+			return true
+		}
+	}
+	return false
+}
+
+// getIdentityLabels returns the labels of the identities which may be the
+// source of requests allowed by HTTP rules injecting identity headers. At
+// ingress, these are the remote identities selected by such rules, at
+// egress, the identity of the endpoint itself.
+func getIdentityLabels(id identity.NumericIdentity, policy *policy.L4Policy,
+	ingressPolicyEnforced, egressPolicyEnforced bool, labelsMap identity.IdentityCache) map[uint64]string {
+	identities := make(map[identity.NumericIdentity]struct{})
+
+	if ingressPolicyEnforced {
+		for _, l4 := range policy.Ingress {
+			for sel, l7 := range l4.L7RulesPerEp {
+				if !injectsIdentityHeaders(l7) {
+					continue
+				}
+				for remoteID, lbls := range labelsMap {
+					if sel.Matches(lbls) {
+						identities[remoteID] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+
+	if egressPolicyEnforced {
+	egress:
+		for _, l4 := range policy.Egress {
+			for _, l7 := range l4.L7RulesPerEp {
+				if injectsIdentityHeaders(l7) {
+					identities[id] = struct{}{}
+					break egress
+				}
+			}
+		}
+	}
+
+	if len(identities) == 0 {
+		return nil
+	}
+
+	identityLabels := make(map[uint64]string, len(identities))
+	for sourceID := range identities {
+		if lbls, ok := labelsMap[sourceID]; ok {
+			model := lbls.GetModel()
+			sort.Strings(model)
+			identityLabels[uint64(sourceID)] = strings.Join(model, ",")
+		}
+	}
+	return identityLabels
+}
+
 // getNetworkPolicy converts a network policy into a cilium.NetworkPolicy.
 func getNetworkPolicy(name string, id identity.NumericIdentity, policy *policy.L4Policy,
 	ingressPolicyEnforced, egressPolicyEnforced bool, labelsMap identity.IdentityCache,
@@ -616,6 +1059,7 @@ func getNetworkPolicy(name string, id identity.NumericIdentity, policy *policy.L
 	if policy != nil {
 		p.IngressPerPortPolicies = getDirectionNetworkPolicy(policy.Ingress, ingressPolicyEnforced, labelsMap, deniedIngressIdentities)
 		p.EgressPerPortPolicies = getDirectionNetworkPolicy(policy.Egress, egressPolicyEnforced, labelsMap, deniedEgressIdentities)
+		p.IdentityLabels = getIdentityLabels(id, policy, ingressPolicyEnforced, egressPolicyEnforced, labelsMap)
 	}
 
 	return p
@@ -17,13 +17,17 @@ package envoy
 import (
 	"github.com/cilium/cilium/pkg/checker"
 	"github.com/cilium/cilium/pkg/envoy/cilium"
+	envoy_api_v2 "github.com/cilium/cilium/pkg/envoy/envoy/api/v2"
 	envoy_api_v2_core "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/core"
 	envoy_api_v2_route "github.com/cilium/cilium/pkg/envoy/envoy/api/v2/route"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/policy/api"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/struct"
 	. "gopkg.in/check.v1"
 )
 
@@ -489,3 +493,218 @@ func (s *ServerSuite) TestGetNetworkPolicyL7(c *C) {
 	// XXX: DeepEquals on maps?
 	c.Assert(obtained, checker.DeepEquals, expected)
 }
+
+func (s *ServerSuite) TestGetZipkinTracing(c *C) {
+	tracing, cluster, err := getZipkinTracing("zipkin.tracing.svc:9411")
+	c.Assert(err, IsNil)
+	c.Assert(tracing.GetHttp().GetName(), Equals, "envoy.zipkin")
+	c.Assert(tracing.GetHttp().GetConfig().Fields["collector_cluster"].GetStringValue(), Equals, zipkinClusterName)
+	c.Assert(tracing.GetHttp().GetConfig().Fields["trace_id_128bit"].GetBoolValue(), Equals, true)
+	c.Assert(cluster.Name, Equals, zipkinClusterName)
+	c.Assert(cluster.Type, Equals, envoy_api_v2.Cluster_STRICT_DNS)
+	c.Assert(cluster.Hosts[0].GetSocketAddress().GetAddress(), Equals, "zipkin.tracing.svc")
+	c.Assert(cluster.Hosts[0].GetSocketAddress().GetPortValue(), Equals, uint32(9411))
+
+	_, cluster, err = getZipkinTracing("10.0.0.1:9411")
+	c.Assert(err, IsNil)
+	c.Assert(cluster.Type, Equals, envoy_api_v2.Cluster_STATIC)
+
+	_, _, err = getZipkinTracing("10.0.0.1")
+	c.Assert(err, Not(IsNil))
+	_, _, err = getZipkinTracing("10.0.0.1:http")
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *ServerSuite) TestGetHTTPTracingConfig(c *C) {
+	tracing := getHTTPTracingConfig(true, 10).GetStructValue()
+	c.Assert(tracing.Fields["operation_name"].GetStringValue(), Equals, "INGRESS")
+	c.Assert(tracing.Fields["random_sampling"].GetStructValue().Fields["value"].GetNumberValue(), Equals, float64(10))
+	c.Assert(tracing.Fields["request_headers_for_tags"].GetListValue().Values[0].GetStringValue(), Equals, traceparentHeader)
+
+	tracing = getHTTPTracingConfig(false, 100).GetStructValue()
+	c.Assert(tracing.Fields["operation_name"].GetStringValue(), Equals, "EGRESS")
+}
+
+func (s *ServerSuite) TestAddTraceparentPropagation(c *C) {
+	newRoute := func() *structpb.Value {
+		return &structpb.Value{Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"match": {Kind: &structpb.Value_StructValue{StructValue: &structpb.Struct{Fields: map[string]*structpb.Value{
+				"prefix": {Kind: &structpb.Value_StringValue{StringValue: "/"}},
+			}}}},
+		}}}}
+	}
+	upgradeRoute := newRoute()
+	routes := &structpb.ListValue{Values: []*structpb.Value{upgradeRoute, newRoute()}}
+
+	addTraceparentPropagation(routes)
+	c.Assert(routes.Values, HasLen, 3)
+	c.Assert(routes.Values[0], Equals, upgradeRoute)
+
+	passthrough := routes.Values[1].GetStructValue()
+	headers := passthrough.Fields["match"].GetStructValue().Fields["headers"].GetListValue().Values
+	c.Assert(headers[0].GetStructValue().Fields["name"].GetStringValue(), Equals, traceparentHeader)
+	c.Assert(passthrough.Fields["request_headers_to_add"], IsNil)
+
+	added := routes.Values[2].GetStructValue().Fields["request_headers_to_add"].GetListValue().Values
+	header := added[0].GetStructValue().Fields["header"].GetStructValue()
+	c.Assert(header.Fields["key"].GetStringValue(), Equals, traceparentHeader)
+	c.Assert(header.Fields["value"].GetStringValue(), Equals, traceparentFormat)
+}
+
+func (s *ServerSuite) TestInsertLuaFilter(c *C) {
+	policyFilter := &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: "cilium.l7policy"}}
+	routerFilter := &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: "envoy.router"}}
+	filters := []*structpb.Value{policyFilter, routerFilter}
+
+	code := "function envoy_on_request(request_handle) end"
+	filters = insertHTTPFilter(filters, getLuaFilter(code))
+	c.Assert(filters, HasLen, 3)
+	c.Assert(filters[0], Equals, policyFilter)
+	c.Assert(filters[2], Equals, routerFilter)
+
+	lua := filters[1].GetStructValue()
+	c.Assert(lua.Fields["name"].GetStringValue(), Equals, "envoy.lua")
+	c.Assert(lua.Fields["config"].GetStructValue().Fields["inline_code"].GetStringValue(), Equals, code)
+}
+
+func (s *ServerSuite) TestGetOriginalDstCluster(c *C) {
+	cluster := getOriginalDstCluster(ingressClusterName)
+	c.Assert(cluster.Name, Equals, ingressClusterName)
+	c.Assert(cluster.Type, Equals, envoy_api_v2.Cluster_ORIGINAL_DST)
+	c.Assert(cluster.ProtocolSelection, Equals, envoy_api_v2.Cluster_USE_DOWNSTREAM_PROTOCOL)
+	c.Assert(cluster.Http2ProtocolOptions, Not(IsNil))
+}
+
+func (s *ServerSuite) TestGetListenerCluster(c *C) {
+	tls := &api.TLSContext{
+		Certificate: "client.crt",
+		PrivateKey:  "client.key",
+		TrustedCA:   "ca.crt",
+		ServerName:  "cilium.io",
+	}
+	oldTLSDir := option.Config.ProxyTLSDir
+	defer func() { option.Config.ProxyTLSDir = oldTLSDir }()
+	option.Config.ProxyTLSDir = "/etc/cilium/tls"
+
+	name := getListenerClusterName("1:80")
+	cluster := getListenerCluster(name, tls, nil)
+	c.Assert(cluster.Name, Equals, "1:80-cluster")
+	c.Assert(cluster.Type, Equals, envoy_api_v2.Cluster_ORIGINAL_DST)
+	c.Assert(cluster.CircuitBreakers, IsNil)
+
+	ctx := cluster.TlsContext.GetCommonTlsContext()
+	c.Assert(ctx.TlsCertificates, HasLen, 1)
+	c.Assert(ctx.TlsCertificates[0].CertificateChain.GetFilename(), Equals, "/etc/cilium/tls/client.crt")
+	c.Assert(ctx.TlsCertificates[0].PrivateKey.GetFilename(), Equals, "/etc/cilium/tls/client.key")
+	c.Assert(ctx.GetValidationContext().TrustedCa.GetFilename(), Equals, "/etc/cilium/tls/ca.crt")
+
+	// Files are confined to the TLS directory
+	c.Assert(getTLSFilename("../../../etc/shadow"), Equals, "/etc/cilium/tls/etc/shadow")
+	c.Assert(cluster.TlsContext.Sni, Equals, tls.ServerName)
+
+	// Only the limits set in the circuit breaker are enforced
+	cluster = getListenerCluster(name, nil, &api.CircuitBreaker{MaxPendingRequests: 10})
+	c.Assert(cluster.TlsContext, IsNil)
+	c.Assert(cluster.CircuitBreakers.Thresholds, HasLen, 1)
+	c.Assert(cluster.CircuitBreakers.Thresholds[0].MaxConnections, IsNil)
+	c.Assert(cluster.CircuitBreakers.Thresholds[0].MaxPendingRequests.GetValue(), Equals, uint32(10))
+}
+
+func (s *ServerSuite) TestGetNetworkPolicyIdentityHeaders(c *C) {
+	injectingRule := *PortRuleHTTP2
+	injectingRule.InjectIdentityHeaders = true
+
+	l4Policy := &policy.L4Policy{
+		Ingress: map[string]policy.L4Filter{
+			"80/TCP": {
+				Port:     80,
+				Protocol: api.ProtoTCP,
+				L7Parser: policy.ParserTypeHTTP,
+				L7RulesPerEp: policy.L7DataMap{
+					EndpointSelector1: api.L7Rules{HTTP: []api.PortRuleHTTP{injectingRule}},
+					EndpointSelector2: L7Rules2,
+				},
+				Ingress: true,
+			},
+		},
+		Egress: map[string]policy.L4Filter{
+			"80/TCP": {
+				Port:     80,
+				Protocol: api.ProtoTCP,
+				L7Parser: policy.ParserTypeHTTP,
+				L7RulesPerEp: policy.L7DataMap{
+					api.WildcardEndpointSelector: api.L7Rules{HTTP: []api.PortRuleHTTP{injectingRule}},
+				},
+			},
+		},
+	}
+
+	obtained := getNetworkPolicy(IPv4Addr, 1003, l4Policy, true, true, IdentityCache, DeniedIdentitiesNone, DeniedIdentitiesNone)
+	c.Assert(obtained.IngressPerPortPolicies, HasLen, 1)
+	for _, rule := range obtained.IngressPerPortPolicies[0].Rules {
+		inject := len(rule.RemotePolicies) == 2 && rule.RemotePolicies[1] == 1002
+		c.Assert(rule.GetHttpRules().HttpRules[0].InjectIdentityHeaders, Equals, inject)
+	}
+
+	// Only the sources selected by the injecting ingress rule and the
+	// endpoint itself as the source at egress.
+	c.Assert(obtained.IdentityLabels, checker.DeepEquals, map[uint64]string{
+		1001: "k8s:app=etcd,k8s:version=v1",
+		1002: "k8s:app=etcd,k8s:version=v2",
+		1003: "k8s:app=cassandra,k8s:version=v1",
+	})
+
+	// No labels are needed without injecting rules or enforced policy
+	obtained = getNetworkPolicy(IPv4Addr, 1003, L4Policy1, true, true, IdentityCache, DeniedIdentitiesNone, DeniedIdentitiesNone)
+	c.Assert(obtained.IdentityLabels, IsNil)
+	obtained = getNetworkPolicy(IPv4Addr, 1003, l4Policy, false, false, IdentityCache, DeniedIdentitiesNone, DeniedIdentitiesNone)
+	c.Assert(obtained.IdentityLabels, IsNil)
+
+	// The identity labels survive the encoding of the xDS resource
+	data, err := proto.Marshal(getNetworkPolicy(IPv4Addr, 1003, l4Policy, true, true, IdentityCache, DeniedIdentitiesNone, DeniedIdentitiesNone))
+	c.Assert(err, IsNil)
+	decoded := &cilium.NetworkPolicy{}
+	c.Assert(proto.Unmarshal(data, decoded), IsNil)
+	c.Assert(decoded.IdentityLabels[1003], Equals, "k8s:app=cassandra,k8s:version=v1")
+	c.Assert(decoded.EgressPerPortPolicies[0].Rules[0].GetHttpRules().HttpRules[0].InjectIdentityHeaders, Equals, true)
+}
+
+func (s *ServerSuite) TestGetHTTPRetryPolicy(c *C) {
+	c.Assert(getHTTPRetryPolicy(nil), IsNil)
+
+	retryingRule := *PortRuleHTTP2
+	retryingRule.Timeout = "1.5s"
+	retryingRule.Retries = &api.HTTPRetryPolicy{NumRetries: 2, PerTryTimeout: "500ms"}
+
+	l4Policy := &policy.L4Policy{
+		Ingress: map[string]policy.L4Filter{
+			"80/TCP": {
+				Port:     80,
+				Protocol: api.ProtoTCP,
+				L7Parser: policy.ParserTypeHTTP,
+				L7RulesPerEp: policy.L7DataMap{
+					api.WildcardEndpointSelector: api.L7Rules{HTTP: []api.PortRuleHTTP{retryingRule}},
+				},
+				Ingress: true,
+			},
+		},
+	}
+
+	obtained := getNetworkPolicy(IPv4Addr, 1003, l4Policy, true, false, IdentityCache, DeniedIdentitiesNone, DeniedIdentitiesNone)
+	c.Assert(obtained.IngressPerPortPolicies, HasLen, 1)
+	rule := obtained.IngressPerPortPolicies[0].Rules[0].GetHttpRules().HttpRules[0]
+	c.Assert(rule.TimeoutMs, Equals, uint64(1500))
+	c.Assert(rule.RetryPolicy, checker.DeepEquals, &cilium.HttpNetworkPolicyRule_RetryPolicy{
+		NumRetries:      2,
+		PerTryTimeoutMs: 500,
+		RetryOn:         "5xx,connect-failure",
+	})
+	c.Assert(rule.Validate(), IsNil)
+
+	// The retry policy survives the encoding of the xDS resource
+	data, err := proto.Marshal(obtained)
+	c.Assert(err, IsNil)
+	decoded := &cilium.NetworkPolicy{}
+	c.Assert(proto.Unmarshal(data, decoded), IsNil)
+	c.Assert(decoded.IngressPerPortPolicies[0].Rules[0].GetHttpRules().HttpRules[0].RetryPolicy.NumRetries, Equals, uint32(2))
+}
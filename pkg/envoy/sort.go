@@ -179,6 +179,38 @@ func HTTPNetworkPolicyRuleLess(r1, r2 *cilium.HttpNetworkPolicyRule) bool {
 		}
 	}
 
+	if r1.InjectIdentityHeaders != r2.InjectIdentityHeaders {
+		return !r1.InjectIdentityHeaders
+	}
+
+	switch {
+	case r1.TimeoutMs < r2.TimeoutMs:
+		return true
+	case r1.TimeoutMs > r2.TimeoutMs:
+		return false
+	}
+
+	retry1, retry2 := r1.RetryPolicy, r2.RetryPolicy
+	switch {
+	case retry1 == nil && retry2 == nil:
+		// Elements are equal.
+		return false
+	case retry1 == nil:
+		return true
+	case retry2 == nil:
+		return false
+	case retry1.NumRetries < retry2.NumRetries:
+		return true
+	case retry1.NumRetries > retry2.NumRetries:
+		return false
+	case retry1.PerTryTimeoutMs < retry2.PerTryTimeoutMs:
+		return true
+	case retry1.PerTryTimeoutMs > retry2.PerTryTimeoutMs:
+		return false
+	case retry1.RetryOn < retry2.RetryOn:
+		return true
+	}
+
 	// Elements are equal.
 	return false
 }
@@ -79,11 +79,24 @@ var HTTPNetworkPolicyRule4 = &cilium.HttpNetworkPolicyRule{
 	Headers: []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1, HeaderMatcher3},
 }
 
+var HTTPNetworkPolicyRule5 = &cilium.HttpNetworkPolicyRule{
+	Headers:   []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1, HeaderMatcher3},
+	TimeoutMs: 1000,
+}
+
+var HTTPNetworkPolicyRule6 = &cilium.HttpNetworkPolicyRule{
+	Headers:     []*envoy_api_v2_route.HeaderMatcher{HeaderMatcher1, HeaderMatcher3},
+	TimeoutMs:   1000,
+	RetryPolicy: &cilium.HttpNetworkPolicyRule_RetryPolicy{NumRetries: 1, RetryOn: "5xx"},
+}
+
 func (s *SortSuite) TestSortHttpNetworkPolicyRules(c *C) {
 	var slice, expected []*cilium.HttpNetworkPolicyRule
 
 	slice = []*cilium.HttpNetworkPolicyRule{
+		HTTPNetworkPolicyRule6,
 		HTTPNetworkPolicyRule4,
+		HTTPNetworkPolicyRule5,
 		HTTPNetworkPolicyRule3,
 		HTTPNetworkPolicyRule2,
 		HTTPNetworkPolicyRule1,
@@ -93,6 +106,8 @@ func (s *SortSuite) TestSortHttpNetworkPolicyRules(c *C) {
 		HTTPNetworkPolicyRule2,
 		HTTPNetworkPolicyRule3,
 		HTTPNetworkPolicyRule4,
+		HTTPNetworkPolicyRule5,
+		HTTPNetworkPolicyRule6,
 	}
 	SortHTTPNetworkPolicyRules(slice)
 	c.Assert(slice, DeepEquals, expected)
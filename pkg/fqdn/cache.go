@@ -158,6 +158,76 @@ func (c *DNSCache) lookupByTime(now time.Time, name string) (ips []net.IP) {
 	return entries.getIPs(now)
 }
 
+// IPLookup is the DNS data of a single, unexpired, IP of a name as returned by
+// DNSCache.Dump.
+type IPLookup struct {
+	// Name is the DNS name the IP was returned for
+	Name string
+
+	// IP is the IP returned in the lookup
+	IP net.IP
+
+	// LookupTime, ExpirationTime and TTL are copied from the cacheEntry
+	// providing IP
+	LookupTime     time.Time
+	ExpirationTime time.Time
+	TTL            int
+}
+
+// Dump returns the unexpired IPs of all names in the cache, sorted by name
+// and IP.
+func (c *DNSCache) Dump() (lookups []IPLookup) {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.dumpByTime(time.Now())
+}
+
+// dumpByTime takes a timestamp for expiration comparisions, and is only
+// intended for testing.
+func (c *DNSCache) dumpByTime(now time.Time) (lookups []IPLookup) {
+	for name, entries := range c.forward {
+		for ipStr, entry := range entries {
+			if entry == nil || entry.isExpiredBy(now) {
+				continue
+			}
+			lookups = append(lookups, IPLookup{
+				Name:           name,
+				IP:             net.ParseIP(ipStr),
+				LookupTime:     entry.LookupTime,
+				ExpirationTime: entry.ExpirationTime,
+				TTL:            entry.TTL,
+			})
+		}
+	}
+
+	sort.Slice(lookups, func(i, j int) bool {
+		if lookups[i].Name != lookups[j].Name {
+			return lookups[i].Name < lookups[j].Name
+		}
+		return bytes.Compare(lookups[i].IP, lookups[j].IP) == -1
+	})
+
+	return lookups
+}
+
+// ForceExpire removes all data for name from the cache, or for all names if
+// name is empty. It returns the names that were removed.
+func (c *DNSCache) ForceExpire(name string) (removed []string) {
+	c.Lock()
+	defer c.Unlock()
+
+	for n := range c.forward {
+		if name == "" || n == name {
+			delete(c.forward, n)
+			removed = append(removed, n)
+		}
+	}
+	sort.Strings(removed)
+
+	return removed
+}
+
 // updateWithEntry adds a mapping for every IP found in `entry` to `ipEntries`
 // (which maps IP -> cacheEntry). It will replace existing IP->old mappings in
 // `entries` if the current entry expires sooner (or has already expired).
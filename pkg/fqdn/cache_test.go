@@ -112,6 +112,43 @@ func (ds *DNSCacheTestSuite) TestUpdateLookup(c *C) {
 	}
 }
 
+// TestDumpForceExpire tests that Dump returns the unexpired IPs of all names
+// and that ForceExpire removes the data of a single name, or of all names.
+func (ds *DNSCacheTestSuite) TestDumpForceExpire(c *C) {
+	now := time.Now()
+	cache := NewDNSCache()
+	cache.Update(now, "b.com.", []net.IP{net.ParseIP("2.2.2.2"), net.ParseIP("1.1.1.1")}, 60)
+	cache.Update(now, "a.com.", []net.IP{net.ParseIP("3.3.3.3")}, 10)
+	cache.Update(now, "b.com.", []net.IP{net.ParseIP("1.1.1.1")}, 120)
+
+	lookups := cache.dumpByTime(now.Add(time.Second))
+	c.Assert(lookups, HasLen, 3)
+	c.Assert(lookups[0].Name, Equals, "a.com.")
+	c.Assert(lookups[0].IP.String(), Equals, "3.3.3.3")
+	c.Assert(lookups[0].TTL, Equals, 10)
+	c.Assert(lookups[1].Name, Equals, "b.com.")
+	c.Assert(lookups[1].IP.String(), Equals, "1.1.1.1")
+	c.Assert(lookups[1].TTL, Equals, 120)
+	c.Assert(lookups[1].ExpirationTime, Equals, now.Add(120*time.Second))
+	c.Assert(lookups[2].Name, Equals, "b.com.")
+	c.Assert(lookups[2].IP.String(), Equals, "2.2.2.2")
+	c.Assert(lookups[2].TTL, Equals, 60)
+
+	// a.com. has expired
+	lookups = cache.dumpByTime(now.Add(30 * time.Second))
+	c.Assert(lookups, HasLen, 2)
+	c.Assert(lookups[0].Name, Equals, "b.com.")
+
+	c.Assert(cache.ForceExpire("c.com."), HasLen, 0)
+	c.Assert(cache.ForceExpire("b.com."), DeepEquals, []string{"b.com."})
+	c.Assert(cache.Lookup("b.com."), HasLen, 0)
+	c.Assert(cache.Lookup("a.com."), HasLen, 1)
+
+	cache.Update(now, "b.com.", []net.IP{net.ParseIP("1.1.1.1")}, 60)
+	c.Assert(cache.ForceExpire(""), DeepEquals, []string{"a.com.", "b.com."})
+	c.Assert(cache.Dump(), HasLen, 0)
+}
+
 /* Benchmarks
  * These are here to help gauge the relative costs of operations in DNSCache.
  * Note: some are on arrays `size` elements, so the benchmark "op time" is too
@@ -16,10 +16,12 @@ package fqdn
 
 import (
 	"net"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/policy/api"
@@ -274,6 +276,35 @@ func (poller *DNSPoller) LookupUpdateDNS() error {
 			Warn("Cannot resolve FQDN. Traffic egressing to this destination may be incorrectly dropped due to stale data.")
 	}
 
+	return poller.updateDNSIPsAndRules(lookupTime, updatedDNSIPs)
+}
+
+// UpdateFromDNSResponse updates the IPs of dnsName with the IPs of a DNS
+// response observed at lookupTime, such as one forwarded by the DNS proxy. If
+// any ToFQDNs rule depends on dnsName, its generated rules are updated before
+// returning. Other names are only stored in the cache, they are not polled.
+func (poller *DNSPoller) UpdateFromDNSResponse(lookupTime time.Time, dnsName string, ips []net.IP, ttl int) error {
+	dnsName = dns.Fqdn(dnsName)
+
+	poller.Lock()
+	if _, tracked := poller.IPs[dnsName]; !tracked {
+		if poller.config.MinTTL > ttl {
+			ttl = poller.config.MinTTL
+		}
+		poller.cache.Update(lookupTime, dnsName, ips, ttl)
+		poller.Unlock()
+		return nil
+	}
+	poller.Unlock()
+
+	return poller.updateDNSIPsAndRules(lookupTime, map[string]*DNSIPRecords{
+		dnsName: {IPs: ips, TTL: ttl},
+	})
+}
+
+// updateDNSIPsAndRules updates the IPs of the DNS names in updatedDNSIPs and
+// emits the regenerated rules depending on any name whose IPs changed.
+func (poller *DNSPoller) updateDNSIPsAndRules(lookupTime time.Time, updatedDNSIPs map[string]*DNSIPRecords) error {
 	// Update IPs in poller
 	uuidsToUpdate, updatedDNSNames := poller.UpdateDNSIPs(lookupTime, updatedDNSIPs)
 	for dnsName, IPs := range updatedDNSNames {
@@ -317,6 +348,32 @@ func (poller *DNSPoller) GetDNSNames() (dnsNames []string) {
 	return dnsNames
 }
 
+// GetDNSNamesSelecting returns the DNS names of the ToFQDN rules selecting an
+// endpoint with lbls, sorted.
+func (poller *DNSPoller) GetDNSNamesSelecting(lbls labels.LabelArray) (dnsNames []string) {
+	poller.Lock()
+	defer poller.Unlock()
+
+	names := make(map[string]struct{})
+	for _, rule := range poller.allRules {
+		if !rule.EndpointSelector.Matches(lbls) {
+			continue
+		}
+		for _, egressRule := range rule.Egress {
+			for _, ToFQDN := range egressRule.ToFQDNs {
+				names[dns.Fqdn(ToFQDN.MatchName)] = struct{}{}
+			}
+		}
+	}
+
+	for name := range names {
+		dnsNames = append(dnsNames, name)
+	}
+	sort.Strings(dnsNames)
+
+	return dnsNames
+}
+
 // UpdateDNSIPs updates the IPs for each DNS name in updatedDNSIPs.
 // It returns:
 // affectedRules: a list of rule UUIDs that were affected by the new IPs (lookup in .allRules)
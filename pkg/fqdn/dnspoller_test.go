@@ -19,7 +19,10 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
+	"github.com/cilium/cilium/pkg/checker"
+	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/miekg/dns"
 
@@ -400,6 +403,50 @@ func (ds *FQDNTestSuite) TestDNSPollerDropCIDROnReinsert(c *C) {
 	c.Assert(generatedRules[0].Egress[0].ToCIDRSet[0].Cidr, Equals, api.CIDR("1.1.1.1/32"), Commentf("Incorrect IP CIDR generated"))
 }
 
+// TestDNSPollerUpdateFromDNSResponse tests that DNS responses observed
+// outside of the poller update the rules depending on the name and only the
+// cache for other names
+func (ds *FQDNTestSuite) TestDNSPollerUpdateFromDNSResponse(c *C) {
+	var (
+		generatedRules = make([]*api.Rule, 0)
+		cache          = NewDNSCache()
+
+		poller = NewDNSPoller(DNSPollerConfig{
+			Cache: cache,
+
+			LookupDNSNames: func(dnsNames []string) (DNSIPs map[string]*DNSIPRecords, errorDNSNames map[string]error) {
+				return nil, nil
+			},
+
+			AddGeneratedRules: func(rules []*api.Rule) error {
+				generatedRules = append(generatedRules, rules...)
+				return nil
+			},
+		})
+	)
+
+	rulesToAdd := []*api.Rule{rule1.DeepCopy()}
+	poller.MarkToFQDNRules(rulesToAdd)
+	poller.StartPollForDNSName(rulesToAdd)
+
+	// A response for a name not used by any rule is only cached
+	err := poller.UpdateFromDNSResponse(time.Now(), "github.com", []net.IP{net.ParseIP("3.3.3.3")}, 60)
+	c.Assert(err, IsNil)
+	c.Assert(len(generatedRules), Equals, 0, Commentf("Generated rules for a name without rules"))
+	c.Assert(cache.Lookup(dns.Fqdn("github.com")), checker.DeepEquals, []net.IP{net.ParseIP("3.3.3.3")})
+	c.Assert(poller.GetDNSNames(), checker.DeepEquals, []string{dns.Fqdn("cilium.io")}, Commentf("Name without rules is polled"))
+
+	err = poller.UpdateFromDNSResponse(time.Now(), "cilium.io", []net.IP{net.ParseIP("1.1.1.1")}, 60)
+	c.Assert(err, IsNil)
+	c.Assert(len(generatedRules), Equals, 1, Commentf("Generated an unexpected number of rules"))
+	c.Assert(generatedRules[0].Egress[0].ToCIDRSet[0].Cidr, Equals, api.CIDR("1.1.1.1/32"), Commentf("Incorrect IP CIDR generated"))
+
+	// The same IPs do not generate new rules
+	err = poller.UpdateFromDNSResponse(time.Now(), "cilium.io.", []net.IP{net.ParseIP("1.1.1.1")}, 60)
+	c.Assert(err, IsNil)
+	c.Assert(len(generatedRules), Equals, 1, Commentf("Generated rules without IP changes"))
+}
+
 // Test that all IPs are updated when one is
 func (ds *FQDNTestSuite) TestDNSPollerMultiIPUpdate(c *C) {
 	var (
@@ -559,3 +606,22 @@ func (ds *FQDNTestSuite) TestDNSPollerUpdatesOnReplace(c *C) {
 	c.Assert(len(rules[0].Egress), Equals, 1, Commentf("Incorrect number of generated egress rules for testCase with single cached ToFQDNs DNS entry"))
 	c.Assert(len(rules[0].Egress[0].ToCIDRSet), Equals, 1, Commentf("Generated CIDR count is not the same as ToFQDNs DNS entries in cache"))
 }
+
+// TestGetDNSNamesSelecting tests that only the DNS names of rules selecting
+// the labels are returned.
+func (ds *FQDNTestSuite) TestGetDNSNamesSelecting(c *C) {
+	poller := NewDNSPoller(DNSPollerConfig{})
+
+	rules := []*api.Rule{makeRule("rule3", "cilium.io", "github.com"), makeRule("rule4", "github.com")}
+	rules[1].EndpointSelector = api.NewESFromLabels(labels.ParseSelectLabel("class=tiefighter"))
+	poller.MarkToFQDNRules(rules)
+	poller.StartPollForDNSName(rules)
+
+	xwing := labels.LabelArray{labels.ParseLabel("class=xwing")}
+	c.Assert(poller.GetDNSNamesSelecting(xwing), DeepEquals, []string{"cilium.io.", "github.com."})
+
+	tiefighter := labels.LabelArray{labels.ParseLabel("class=tiefighter")}
+	c.Assert(poller.GetDNSNamesSelecting(tiefighter), DeepEquals, []string{"github.com."})
+
+	c.Assert(poller.GetDNSNamesSelecting(labels.LabelArray{}), HasLen, 0)
+}
@@ -0,0 +1,237 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/sirupsen/logrus"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "hooks")
+
+const (
+	// hookTimeout is the maximum duration of a single hook invocation
+	hookTimeout = 10 * time.Second
+
+	// queueSize is the number of events buffered before events are
+	// dropped
+	queueSize = 1024
+)
+
+// Event is an endpoint lifecycle event
+type Event string
+
+const (
+	// EventEndpointCreate is emitted when an endpoint has been created
+	EventEndpointCreate Event = "endpoint-create"
+
+	// EventEndpointIdentityChange is emitted when an endpoint has been
+	// assigned a security identity
+	EventEndpointIdentityChange Event = "endpoint-identity-change"
+
+	// EventEndpointDelete is emitted when an endpoint is being deleted
+	EventEndpointDelete Event = "endpoint-delete"
+)
+
+// Payload is the JSON document passed to hooks
+type Payload struct {
+	Event     Event            `json:"event"`
+	Timestamp time.Time        `json:"timestamp"`
+	Endpoint  *models.Endpoint `json:"endpoint"`
+}
+
+// Hook is invoked for every endpoint lifecycle event
+type Hook interface {
+	// Run invokes the hook for the event with the JSON encoded payload
+	Run(ctx context.Context, event Event, payload []byte) error
+
+	String() string
+}
+
+// execHook runs an executable with the event as argument and the payload on
+// stdin
+type execHook struct {
+	path string
+}
+
+func (h *execHook) Run(ctx context.Context, event Event, payload []byte) error {
+	cmd := exec.CommandContext(ctx, h.path, string(event))
+	cmd.Stdin = bytes.NewReader(payload)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (h *execHook) String() string {
+	return h.path
+}
+
+// webHook posts the payload to a URL
+type webHook struct {
+	url string
+}
+
+func (h *webHook) Run(ctx context.Context, event Event, payload []byte) error {
+	req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (h *webHook) String() string {
+	return h.url
+}
+
+// ParseHooks parses a comma separated list of hooks. Each hook is either the
+// absolute path of an executable or a http or https URL.
+func ParseHooks(value string) ([]Hook, error) {
+	var hooks []Hook
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		switch {
+		case s == "":
+			continue
+		case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+			if _, err := url.Parse(s); err != nil {
+				return nil, fmt.Errorf("invalid hook URL %q: %s", s, err)
+			}
+			hooks = append(hooks, &webHook{url: s})
+		case filepath.IsAbs(s):
+			hooks = append(hooks, &execHook{path: s})
+		default:
+			return nil, fmt.Errorf("invalid hook %q: must be an absolute path or a http(s) URL", s)
+		}
+	}
+	return hooks, nil
+}
+
+// Dispatcher invokes hooks for events in the order in which the events
+// have been emitted. Hooks are invoked asynchronously, events are dropped if
+// the hooks can't keep up.
+type Dispatcher struct {
+	hooks  []Hook
+	events chan *Payload
+}
+
+// NewDispatcher returns a dispatcher invoking hooks
+func NewDispatcher(hooks []Hook) *Dispatcher {
+	d := &Dispatcher{
+		hooks:  hooks,
+		events: make(chan *Payload, queueSize),
+	}
+	go d.run()
+	return d
+}
+
+func (d *Dispatcher) run() {
+	for p := range d.events {
+		payload, err := json.Marshal(p)
+		if err != nil {
+			log.WithError(err).Error("Unable to marshal hook payload")
+			continue
+		}
+
+		for _, h := range d.hooks {
+			ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+			if err := h.Run(ctx, p.Event, payload); err != nil {
+				log.WithError(err).WithFields(logrus.Fields{
+					"hook":  h.String(),
+					"event": p.Event,
+				}).Warn("Endpoint hook failed")
+			}
+			cancel()
+		}
+	}
+}
+
+// Notify queues the invocation of all hooks for the event
+func (d *Dispatcher) Notify(event Event, ep *models.Endpoint) {
+	p := &Payload{
+		Event:     event,
+		Timestamp: time.Now().UTC(),
+		Endpoint:  ep,
+	}
+
+	select {
+	case d.events <- p:
+	default:
+		log.WithField("event", event).Warn("Hook queue is full, dropping event")
+	}
+}
+
+var (
+	mutex      lock.RWMutex
+	dispatcher *Dispatcher
+)
+
+// Configure sets the hooks invoked by Notify. Hooks are disabled if hooks is
+// empty.
+func Configure(hooks []Hook) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if dispatcher != nil {
+		close(dispatcher.events)
+		dispatcher = nil
+	}
+	if len(hooks) > 0 {
+		dispatcher = NewDispatcher(hooks)
+	}
+}
+
+// Enabled returns true if any hooks are configured. Callers should check
+// Enabled before building the endpoint model for Notify.
+func Enabled() bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return dispatcher != nil
+}
+
+// Notify queues the invocation of the configured hooks for the event
+func Notify(event Event, ep *models.Endpoint) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if dispatcher != nil {
+		dispatcher.Notify(event, ep)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type HooksSuite struct{}
+
+var _ = Suite(&HooksSuite{})
+
+func (s *HooksSuite) TestParseHooks(c *C) {
+	hooks, err := ParseHooks(" /usr/bin/ep-hook, https://inventory.local/endpoints ,")
+	c.Assert(err, IsNil)
+	c.Assert(hooks, DeepEquals, []Hook{
+		&execHook{path: "/usr/bin/ep-hook"},
+		&webHook{url: "https://inventory.local/endpoints"},
+	})
+
+	hooks, err = ParseHooks("")
+	c.Assert(err, IsNil)
+	c.Assert(hooks, HasLen, 0)
+
+	_, err = ParseHooks("ep-hook")
+	c.Assert(err, NotNil)
+}
+
+func (s *HooksSuite) TestDispatcher(c *C) {
+	received := make(chan *Payload, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := &Payload{}
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- p
+	}))
+	defer server.Close()
+
+	dir := c.MkDir()
+	out := filepath.Join(dir, "out")
+	script := filepath.Join(dir, "hook.sh")
+	err := ioutil.WriteFile(script, []byte("#!/bin/sh\necho $1 > "+out+"\ncat >> "+out+"\n"), 0755)
+	c.Assert(err, IsNil)
+
+	d := NewDispatcher([]Hook{&execHook{path: script}, &webHook{url: server.URL}})
+	defer close(d.events)
+
+	d.Notify(EventEndpointCreate, &models.Endpoint{ID: 1})
+	d.Notify(EventEndpointDelete, &models.Endpoint{ID: 1})
+
+	for _, event := range []Event{EventEndpointCreate, EventEndpointDelete} {
+		select {
+		case p := <-received:
+			c.Assert(p.Event, Equals, event)
+			c.Assert(p.Endpoint.ID, Equals, int64(1))
+		case <-time.After(5 * time.Second):
+			c.Fatalf("timeout while waiting for %s", event)
+		}
+	}
+
+	data, err := ioutil.ReadFile(out)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Matches, "endpoint-delete\n\\{\"event\":\"endpoint-delete\".*\n?")
+}
@@ -46,9 +46,30 @@ func AllocateCIDRs(impl Implementation, prefixes []*net.IPNet) error {
 				fieldIdentities: prefixIdentities,
 			}).Warn("Failed to release CIDRs during CIDR->ID mapping")
 		}
+		return err
 	}
 
-	return err
+	upsertIPNetsToCache(prefixes, prefixIdentities)
+
+	return nil
+}
+
+// upsertIPNetsToCache inserts the CIDR->Identity mappings into the local
+// ipcache so that the datapath can resolve the identities of the prefixes
+// without waiting for the kvstore watcher to observe them. The watcher later
+// observes the same mappings, which are then no-ops.
+func upsertIPNetsToCache(prefixes []*net.IPNet, identities []*identity.Identity) {
+	for i, prefix := range prefixes {
+		id := identities[i]
+		// Reserved identities are handled locally, cf. upsertIPNetToKVStore
+		if id == nil || id.IsReserved() {
+			continue
+		}
+		IPIdentityCache.Upsert(prefix.String(), nil, Identity{
+			ID:     id.ID,
+			Source: FromKVStore,
+		})
+	}
 }
 
 // ReleaseCIDRs attempts to release identities and IP<->Identity mappings for
@@ -18,7 +18,9 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"sort"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging/logfields"
@@ -488,9 +490,83 @@ func (ipc *IPCache) LookupByIdentity(id identity.NumericIdentity) (map[string]st
 	return ips, exists
 }
 
-// GetIPIdentityMapModel returns all known endpoint IP to security identity mappings
-// stored in the key-value store.
-func GetIPIdentityMapModel() {
-	// TODO (ianvernon) return model of ip to identity mapping. For use in CLI.
-	// see GH-2555
+// prefixOf returns the prefix of the ipcache key, endpoint IPs are returned
+// as fully specified prefixes.
+func prefixOf(key string) *net.IPNet {
+	if _, prefix, err := net.ParseCIDR(key); err == nil {
+		return prefix
+	}
+	if ip := net.ParseIP(key); ip != nil {
+		return endpointIPToCIDR(ip)
+	}
+	return nil
+}
+
+// prefixWithin returns true if prefix is contained in cidr
+func prefixWithin(prefix, cidr *net.IPNet) bool {
+	ones, bits := prefix.Mask.Size()
+	cidrOnes, cidrBits := cidr.Mask.Size()
+	return bits == cidrBits && ones >= cidrOnes && cidr.Contains(prefix.IP)
+}
+
+// entryModelRLocked returns the model of the mapping of the ipcache key.
+func (ipc *IPCache) entryModelRLocked(key string, prefix *net.IPNet, id Identity) *models.IPListEntry {
+	entry := &models.IPListEntry{
+		Cidr:     prefix.String(),
+		Identity: int64(id.ID),
+	}
+	if hostIP := ipc.ipToHostIPCache[key]; hostIP != nil {
+		entry.HostIP = hostIP.String()
+	}
+	return entry
+}
+
+// GetIPIdentityMapModel returns the mappings of all prefixes contained in
+// cidr to their security identity, sorted by prefix. All mappings are
+// returned if cidr is nil.
+func (ipc *IPCache) GetIPIdentityMapModel(cidr *net.IPNet) []*models.IPListEntry {
+	ipc.mutex.RLock()
+	defer ipc.mutex.RUnlock()
+
+	entries := make([]*models.IPListEntry, 0, len(ipc.ipToIdentityCache))
+	for key, id := range ipc.ipToIdentityCache {
+		prefix := prefixOf(key)
+		if prefix == nil || (cidr != nil && !prefixWithin(prefix, cidr)) {
+			continue
+		}
+		entries = append(entries, ipc.entryModelRLocked(key, prefix, id))
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Cidr < entries[j].Cidr
+	})
+	return entries
+}
+
+// LookupByAddr returns the mapping of the longest prefix containing addr,
+// which corresponds to the lookup performed by the datapath, as well as
+// whether such a prefix exists in the IPCache.
+func (ipc *IPCache) LookupByAddr(addr net.IP) (*models.IPListEntry, bool) {
+	bits := net.IPv6len * 8
+	if ip4 := addr.To4(); ip4 != nil {
+		addr = ip4
+		bits = net.IPv4len * 8
+	}
+
+	ipc.mutex.RLock()
+	defer ipc.mutex.RUnlock()
+
+	if id, ok := ipc.ipToIdentityCache[addr.String()]; ok {
+		return ipc.entryModelRLocked(addr.String(), endpointIPToCIDR(addr), id), true
+	}
+
+	for ones := bits; ones >= 0; ones-- {
+		mask := net.CIDRMask(ones, bits)
+		prefix := &net.IPNet{IP: addr.Mask(mask), Mask: mask}
+		if id, ok := ipc.ipToIdentityCache[prefix.String()]; ok {
+			return ipc.entryModelRLocked(prefix.String(), prefix, id), true
+		}
+	}
+
+	return nil, false
 }
@@ -253,3 +253,68 @@ func (s *IPCacheTestSuite) TestAllowOverwrite(c *C) {
 	c.Assert(allowOverwrite(FromAgentLocal, FromKVStore), Equals, false)
 	c.Assert(allowOverwrite(FromAgentLocal, FromAgentLocal), Equals, true)
 }
+
+func (s *IPCacheTestSuite) TestIPIdentityMapModel(c *C) {
+	ipc := NewIPCache()
+	ipc.Upsert("10.0.0.15", net.ParseIP("192.168.1.1"), Identity{ID: 68, Source: FromKVStore})
+	ipc.Upsert("10.0.0.0/8", nil, Identity{ID: 16777217, Source: FromAgentLocal})
+	ipc.Upsert("10.1.0.0/16", nil, Identity{ID: 16777218, Source: FromAgentLocal})
+	ipc.Upsert("f00d::/64", nil, Identity{ID: 16777219, Source: FromAgentLocal})
+
+	entries := ipc.GetIPIdentityMapModel(nil)
+	c.Assert(entries, HasLen, 4)
+	c.Assert(entries[0].Cidr, Equals, "10.0.0.0/8")
+	c.Assert(entries[1].Cidr, Equals, "10.0.0.15/32")
+	c.Assert(entries[1].Identity, Equals, int64(68))
+	c.Assert(entries[1].HostIP, Equals, "192.168.1.1")
+
+	_, cidr, _ := net.ParseCIDR("10.0.0.0/16")
+	entries = ipc.GetIPIdentityMapModel(cidr)
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].Cidr, Equals, "10.0.0.15/32")
+
+	// The longest prefix containing the address is returned
+	entry, ok := ipc.LookupByAddr(net.ParseIP("10.0.0.15"))
+	c.Assert(ok, Equals, true)
+	c.Assert(entry.Identity, Equals, int64(68))
+
+	entry, ok = ipc.LookupByAddr(net.ParseIP("10.1.2.3"))
+	c.Assert(ok, Equals, true)
+	c.Assert(entry.Cidr, Equals, "10.1.0.0/16")
+
+	entry, ok = ipc.LookupByAddr(net.ParseIP("10.2.2.3"))
+	c.Assert(ok, Equals, true)
+	c.Assert(entry.Cidr, Equals, "10.0.0.0/8")
+
+	entry, ok = ipc.LookupByAddr(net.ParseIP("f00d::1"))
+	c.Assert(ok, Equals, true)
+	c.Assert(entry.Identity, Equals, int64(16777219))
+
+	_, ok = ipc.LookupByAddr(net.ParseIP("192.168.1.1"))
+	c.Assert(ok, Equals, false)
+}
+
+func (s *IPCacheTestSuite) TestUpsertIPNetsToCache(c *C) {
+	_, prefix, err := net.ParseCIDR("192.0.2.3/32")
+	c.Assert(err, IsNil)
+	_, world, err := net.ParseCIDR("0.0.0.0/0")
+	c.Assert(err, IsNil)
+	_, missing, err := net.ParseCIDR("198.51.100.0/24")
+	c.Assert(err, IsNil)
+
+	id := identityPkg.NewIdentity(identityPkg.NumericIdentity(16777217), nil)
+	upsertIPNetsToCache(
+		[]*net.IPNet{prefix, world, missing},
+		[]*identityPkg.Identity{id, identityPkg.NewIdentity(identityPkg.ReservedIdentityWorld, nil), nil})
+	defer IPIdentityCache.Delete(prefix.String())
+
+	cachedIdentity, exists := IPIdentityCache.LookupByPrefix(prefix.String())
+	c.Assert(exists, Equals, true)
+	c.Assert(cachedIdentity, Equals, Identity{ID: id.ID, Source: FromKVStore})
+
+	// Reserved and missing identities are not cached
+	_, exists = IPIdentityCache.LookupByPrefix(world.String())
+	c.Assert(exists, Equals, false)
+	_, exists = IPIdentityCache.LookupByPrefix(missing.String())
+	c.Assert(exists, Equals, false)
+}
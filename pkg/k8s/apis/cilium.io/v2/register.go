@@ -41,7 +41,7 @@ const (
 
 	// CustomResourceDefinitionSchemaVersion is semver-conformant version of CRD schema
 	// Used to determine if CRD needs to be updated in cluster
-	CustomResourceDefinitionSchemaVersion = "1.10"
+	CustomResourceDefinitionSchemaVersion = "1.18"
 
 	// CustomResourceDefinitionSchemaVersionKey is key to label which holds the CRD schema version
 	CustomResourceDefinitionSchemaVersionKey = "io.cilium.k8s.crd.schema.version"
@@ -384,6 +384,7 @@ var (
 		"LabelSelectorRequirement": LabelSelectorRequirement,
 		"PortProtocol":             PortProtocol,
 		"PortRule":                 PortRule,
+		"PortRuleDNS":              PortRuleDNS,
 		"PortRuleHTTP":             PortRuleHTTP,
 		"PortRuleKafka":            PortRuleKafka,
 		"PortRuleL7":               PortRuleL7,
@@ -542,6 +543,53 @@ var (
 
 	EndpointSelector = *LabelSelector.DeepCopy()
 
+	HTTPRetryPolicy = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "HTTPRetryPolicy is the policy by which the proxy retries failed " +
+			"HTTP requests.",
+		Required: []string{"numRetries"},
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"numRetries": {
+				Description: "NumRetries is the maximum number of retries of a request, " +
+					"in addition to its initial attempt.",
+				Type: "integer",
+			},
+			"perTryTimeout": {
+				Description: "PerTryTimeout is the timeout of each attempt, e.g. \"250ms\". " +
+					"The timeout of the rule, if any, includes all retries.\n\nIf omitted or " +
+					"empty, each attempt is only limited by the timeout of the rule.",
+				Type: "string",
+			},
+			"retryOn": {
+				Description: "RetryOn is the list of conditions under which a request is " +
+					"retried.\n\nIf omitted or empty, requests are retried on \"5xx\" and " +
+					"\"connect-failure\".",
+				Type: "array",
+				Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{
+					Schema: &apiextensionsv1beta1.JSONSchemaProps{
+						Type: "string",
+						Enum: []apiextensionsv1beta1.JSON{
+							{
+								Raw: []byte(`"5xx"`),
+							},
+							{
+								Raw: []byte(`"gateway-error"`),
+							},
+							{
+								Raw: []byte(`"connect-failure"`),
+							},
+							{
+								Raw: []byte(`"retriable-4xx"`),
+							},
+							{
+								Raw: []byte(`"refused-stream"`),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
 	IngressRule = apiextensionsv1beta1.JSONSchemaProps{
 		Description: "IngressRule contains all rule types which can be applied at ingress, " +
 			"i.e. network traffic that originates outside of the endpoint and is entering " +
@@ -665,6 +713,13 @@ var (
 					Schema: &PortRuleKafka,
 				},
 			},
+			"dns": {
+				Description: "DNS-specific rules.",
+				Type:        "array",
+				Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{
+					Schema: &PortRuleDNS,
+				},
+			},
 			"l7proto": {
 				Description: "Parser type name that uses Key-Value pair rules.",
 				Type:        "string",
@@ -765,6 +820,58 @@ var (
 		Required: []string{"key", "operator"},
 	}
 
+	MirrorRule = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "MirrorRule mirrors a copy of the packets sent and received by the " +
+			"endpoints selected by the rule to a collector, e.g. an intrusion detection " +
+			"system. Mirroring has no effect on whether the packets are allowed or not.",
+		Required: []string{
+			"collector",
+		},
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"collector": {
+				Description: "Collector is the destination of the mirrored packets. Exactly " +
+					"one of the members must be specified.",
+				Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+					"endpoint": EndpointSelector,
+					"vxlan": {
+						Description: "VXLAN sends the mirrored packets VXLAN encapsulated to " +
+							"a remote collector. Requires tunneling to be enabled.",
+						Required: []string{
+							"ip",
+						},
+						Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+							"ip": {
+								Description: "IP is the IPv4 address of the collector.",
+								Type:        "string",
+								Format:      "ipv4",
+							},
+							"vni": {
+								Description: "VNI is the VXLAN network identifier of the " +
+									"mirrored packets.",
+								Type:   "integer",
+								Format: "uint32",
+							},
+						},
+					},
+				},
+			},
+			"samplingRate": {
+				Description: "SamplingRate mirrors one out of SamplingRate packets. If " +
+					"omitted, 0 or 1, every packet is mirrored.",
+				Type:   "integer",
+				Format: "uint32",
+			},
+			"toPorts": {
+				Description: "ToPorts limits mirroring to packets from or to the given ports " +
+					"of the endpoint. If omitted or empty, all packets are mirrored.",
+				Type: "array",
+				Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{
+					Schema: &PortProtocol,
+				},
+			},
+		},
+	}
+
 	PortProtocol = apiextensionsv1beta1.JSONSchemaProps{
 		Description: "PortProtocol specifies an L4 port with an optional transport protocol",
 		Required: []string{
@@ -821,7 +928,9 @@ var (
 				Type:   "integer",
 				Format: "uint16",
 			},
-			"rules": L7Rules,
+			"rules":          L7Rules,
+			"originatingTLS": TLSContext,
+			"circuitBreaker": CircuitBreaker,
 		},
 	}
 
@@ -845,6 +954,14 @@ var (
 					},
 				},
 			},
+			"injectIdentityHeaders": {
+				Description: "InjectIdentityHeaders enables the injection of the " +
+					"X-Cilium-Source-Identity and X-Cilium-Source-Labels headers, carrying " +
+					"the numeric security identity and the labels of the source of the " +
+					"request, into requests allowed by this rule. Headers of the same name " +
+					"sent by the source are always removed.",
+				Type: "boolean",
+			},
 			"host": {
 				Description: "Host is an extended POSIX regex matched against the host header " +
 					"of a request, e.g. \"foo.com\"\n\nIf omitted or empty, the value of the " +
@@ -865,6 +982,36 @@ var (
 					"If omitted or empty, all paths are all allowed.",
 				Type: "string",
 			},
+			"retries": HTTPRetryPolicy,
+			"timeout": {
+				Description: "Timeout is the maximum time the proxy waits for the complete " +
+					"response of the upstream service to a request allowed by this rule, " +
+					"e.g. \"1.5s\". If the timeout expires, a 504 response is returned.\n\n" +
+					"If omitted or empty, the default timeout of the proxy applies.",
+				Type: "string",
+			},
+		},
+	}
+
+	PortRuleDNS = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "PortRuleDNS is a list of allowed DNS lookups. At most one of the " +
+			"fields may be set. If all fields are empty or missing, the rule will match " +
+			"all DNS lookups.",
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"matchName": {
+				Description: "MatchName matches the name of a DNS lookup exactly. The " +
+					"match is case-insensitive and a trailing dot is optional.",
+				Type:    "string",
+				Pattern: `^[-a-zA-Z0-9_]+(\.[-a-zA-Z0-9_]+)*\.?$`,
+			},
+			"matchPattern": {
+				Description: "MatchPattern matches the name of a DNS lookup with a " +
+					"wildcard pattern. The wildcard \"*\" matches zero or more characters " +
+					"of a single label. A pattern consisting of only \"*\" matches all " +
+					"names.",
+				Type:    "string",
+				Pattern: `^[-a-zA-Z0-9_*]+(\.[-a-zA-Z0-9_*]+)*\.?$`,
+			},
 		},
 	}
 
@@ -919,6 +1066,27 @@ var (
 					"empty, all client identifiers are allowed.",
 				Type: "string",
 			},
+			"groupID": {
+				Description: "GroupID is the consumer group ID contained in the message. " +
+					"Requests of consumer group members such as \"joingroup\", \"heartbeat\", " +
+					"\"offsetcommit\" and \"offsetfetch\" are only allowed for the given group, " +
+					"allowing tenants sharing a Kafka cluster to be fenced at the group level." +
+					"\n\nThis constraint is ignored if the matched request message type " +
+					"doesn't contain any group ID.\n\nIf omitted or empty, all group IDs are " +
+					"allowed.",
+				Type: "string",
+			},
+			"transactionalID": {
+				Description: "TransactionalID is the transactional ID contained in the " +
+					"message. Transactional requests such as \"initproducerid\", \"endtxn\" " +
+					"and transactional \"produce\" requests are only allowed for the given " +
+					"transactional ID. If set along with the \"produce\" role, the role also " +
+					"expands into all APIKeys required by transactional producers.\n\nThis " +
+					"constraint is ignored if the matched request message type doesn't " +
+					"contain any transactional ID.\n\nIf omitted or empty, all transactional " +
+					"IDs are allowed.",
+				Type: "string",
+			},
 			"topic": {
 				Description: "Topic is the topic name contained in the message. If a Kafka " +
 					"request contains multiple topics, then all topics must be allowed or the " +
@@ -1001,6 +1169,14 @@ var (
 					Schema: &Label,
 				},
 			},
+			"mirror": {
+				Description: "Mirror is a list of MirrorRule which mirror the packets of the " +
+					"selected endpoints to a collector.",
+				Type: "array",
+				Items: &apiextensionsv1beta1.JSONSchemaPropsOrArray{
+					Schema: &MirrorRule,
+				},
+			},
 		},
 	}
 
@@ -1025,6 +1201,55 @@ var (
 		},
 	}
 
+	TLSContext = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "TLSContext provides the client certificate and trusted CAs used by " +
+			"the proxy to originate TLS connections to upstream services. All files are " +
+			"PEM encoded and read from the TLS directory of the node running the proxy, " +
+			"their paths are relative to that directory.",
+		Required: []string{"certificate", "privateKey", "trustedCA"},
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"certificate": {
+				Description: "Certificate is the path to the certificate chain presented " +
+					"to the upstream service as the identity of the workload.",
+				Type: "string",
+			},
+			"privateKey": {
+				Description: "PrivateKey is the path to the private key of Certificate.",
+				Type:        "string",
+			},
+			"trustedCA": {
+				Description: "TrustedCA is the path to the CA certificates used to verify " +
+					"the certificate of the upstream service.",
+				Type: "string",
+			},
+			"serverName": {
+				Description: "ServerName is the server name (SNI) requested by the proxy " +
+					"in the TLS handshake. If omitted, no server name is sent.",
+				Type: "string",
+			},
+		},
+	}
+
+	CircuitBreaker = apiextensionsv1beta1.JSONSchemaProps{
+		Description: "CircuitBreaker limits the load the proxy forwards to the port of an " +
+			"endpoint subject to L7 rules. Connections and requests exceeding a limit are " +
+			"rejected by the proxy instead of being forwarded.",
+		Properties: map[string]apiextensionsv1beta1.JSONSchemaProps{
+			"maxConnections": {
+				Description: "MaxConnections is the maximum number of concurrent connections " +
+					"the proxy forwards to the port. If omitted or zero, the circuit breaker " +
+					"does not limit connections.",
+				Type: "integer",
+			},
+			"maxPendingRequests": {
+				Description: "MaxPendingRequests is the maximum number of requests forwarded " +
+					"to the port which are waiting for a connection (HTTP) or for a response " +
+					"(Kafka). If omitted or zero, the circuit breaker does not limit requests.",
+				Type: "integer",
+			},
+		},
+	}
+
 	spec = *Rule.DeepCopy()
 
 	specs = apiextensionsv1beta1.JSONSchemaProps{
@@ -1045,6 +1270,24 @@ func init() {
 		"no layer 7 rules are enforced."
 	PortRule.Properties["rules"] = portRuleProps
 
+	portRuleProps = PortRule.Properties["originatingTLS"]
+	portRuleProps.Description = "OriginatingTLS is the TLS context used by the proxy to " +
+		"originate TLS connections to the upstream service. Only supported in egress " +
+		"rules with HTTP rules, which are enforced on the plaintext request sent by the " +
+		"endpoint before the proxy encrypts it with the client certificate of the TLS context."
+	PortRule.Properties["originatingTLS"] = portRuleProps
+
+	portRuleProps = PortRule.Properties["circuitBreaker"]
+	portRuleProps.Description = "CircuitBreaker limits the connections and requests the " +
+		"proxy forwards to the port. Only supported with HTTP and Kafka rules."
+	PortRule.Properties["circuitBreaker"] = portRuleProps
+
+	portRuleHTTPProps := PortRuleHTTP.Properties["retries"]
+	portRuleHTTPProps.Description = "Retries is the policy by which the proxy retries " +
+		"requests allowed by this rule if the upstream service fails to respond " +
+		"successfully.\n\nIf omitted, requests are not retried."
+	PortRuleHTTP.Properties["retries"] = portRuleHTTPProps
+
 	ruleProps := Rule.Properties["endpointSelector"]
 	ruleProps.Description = "EndpointSelector selects all endpoints which should be subject " +
 		"to this rule. Cannot be empty."
@@ -514,9 +514,12 @@ func equalV1Pod(o1, o2 interface{}) bool {
 		return false
 	}
 
-	// We only care about the HostIP, the PodIP and the labels of the pods.
+	// We only care about the HostIP, the PodIP, the labels and the
+	// annotations setting endpoint options of the pods.
 	if pod1.Status.PodIP != pod2.Status.PodIP ||
-		pod1.Status.HostIP != pod2.Status.HostIP {
+		pod1.Status.HostIP != pod2.Status.HostIP ||
+		pod1.GetAnnotations()[annotation.EgressBandwidth] != pod2.GetAnnotations()[annotation.EgressBandwidth] ||
+		pod1.GetAnnotations()[annotation.DNSVisibility] != pod2.GetAnnotations()[annotation.DNSVisibility] {
 		return false
 	}
 	oldPodLabels := pod1.GetLabels()
@@ -437,6 +437,63 @@ func (s *K8sSuite) Test_equalV1Pod(c *C) {
 			},
 			want: true,
 		},
+		{
+			name: "Pods with the same spec but different egress bandwidth",
+			args: args{
+				o1: &core_v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pod1",
+						Annotations: map[string]string{
+							annotation.EgressBandwidth: "10M",
+						},
+					},
+					Status: core_v1.PodStatus{
+						HostIP: "127.0.0.1",
+						PodIP:  "127.0.0.2",
+					},
+				},
+				o2: &core_v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pod1",
+						Annotations: map[string]string{
+							annotation.EgressBandwidth: "20M",
+						},
+					},
+					Status: core_v1.PodStatus{
+						HostIP: "127.0.0.1",
+						PodIP:  "127.0.0.2",
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "Pods with the same spec but different DNS visibility",
+			args: args{
+				o1: &core_v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pod1",
+					},
+					Status: core_v1.PodStatus{
+						HostIP: "127.0.0.1",
+						PodIP:  "127.0.0.2",
+					},
+				},
+				o2: &core_v1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pod1",
+						Annotations: map[string]string{
+							annotation.DNSVisibility: "true",
+						},
+					},
+					Status: core_v1.PodStatus{
+						HostIP: "127.0.0.1",
+						PodIP:  "127.0.0.2",
+					},
+				},
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		got := equalV1Pod(tt.args.o1, tt.args.o2)
@@ -0,0 +1,104 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	"github.com/optiopay/kafka/proto"
+)
+
+// maxParsedVersions is the highest version of each request type that the
+// proxy is able to parse in order to enforce topic and client ID rules.
+// Brokers may support newer versions, the proxy hides them from clients by
+// limiting the versions advertised in ApiVersions responses.
+var maxParsedVersions = map[int16]int16{
+	proto.ProduceReqKind:          proto.KafkaV3,
+	proto.FetchReqKind:            proto.KafkaV5,
+	proto.OffsetReqKind:           proto.KafkaV2,
+	proto.MetadataReqKind:         proto.KafkaV4,
+	proto.OffsetCommitReqKind:     proto.KafkaV3,
+	proto.OffsetFetchReqKind:      proto.KafkaV3,
+	proto.ConsumerMetadataReqKind: proto.KafkaV1,
+}
+
+const (
+	// maxAPIVersionsVersion is the highest version of ApiVersions
+	// responses which can be rewritten. Later versions use a different
+	// encoding and are forwarded unmodified.
+	maxAPIVersionsVersion = proto.KafkaV2
+
+	// apiVersionsHeaderLen is the length of the size, correlation ID,
+	// error code and array length preceding the API versions in an
+	// ApiVersions response
+	apiVersionsHeaderLen = 14
+
+	// apiVersionsEntryLen is the length of the API key, min version and
+	// max version of an entry in an ApiVersions response
+	apiVersionsEntryLen = 6
+)
+
+// isNegotiationAPIKey returns true if kind is the API key of a request which
+// negotiates the protocol versions or authenticates the connection. These
+// requests carry no topic and are required by clients before any other
+// request can be sent.
+func isNegotiationAPIKey(kind int16) bool {
+	switch kind {
+	case api.APIVersionsKey, api.SaslHandshakeKey, api.SaslAuthenticateKey:
+		return true
+	}
+	return false
+}
+
+// LimitAPIVersions limits the versions advertised in the ApiVersions
+// response res to the versions the proxy is able to parse. SASL handshakes
+// are restricted to version 1 and later if supported by the broker, as the
+// tokens of version 0 are exchanged without Kafka framing and cannot be
+// passed through. version is the version of the corresponding request.
+func (res *ResponseMessage) LimitAPIVersions(version int16) error {
+	if version > maxAPIVersionsVersion {
+		return nil
+	}
+
+	raw := res.rawMsg
+	if len(raw) < apiVersionsHeaderLen {
+		return fmt.Errorf("unexpected end of ApiVersions response (length < %d bytes)", apiVersionsHeaderLen)
+	}
+
+	n := int(int32(binary.BigEndian.Uint32(raw[10:14])))
+	if n < 0 || len(raw) < apiVersionsHeaderLen+n*apiVersionsEntryLen {
+		return fmt.Errorf("invalid number of API versions %d in ApiVersions response", n)
+	}
+
+	for i := 0; i < n; i++ {
+		entry := raw[apiVersionsHeaderLen+i*apiVersionsEntryLen:]
+		apiKey := int16(binary.BigEndian.Uint16(entry[0:2]))
+		minVersion := int16(binary.BigEndian.Uint16(entry[2:4]))
+		maxVersion := int16(binary.BigEndian.Uint16(entry[4:6]))
+
+		if limit, ok := maxParsedVersions[apiKey]; ok && maxVersion > limit && minVersion <= limit {
+			binary.BigEndian.PutUint16(entry[4:6], uint16(limit))
+		}
+
+		if apiKey == api.SaslHandshakeKey && minVersion < proto.KafkaV1 && maxVersion >= proto.KafkaV1 {
+			binary.BigEndian.PutUint16(entry[2:4], uint16(proto.KafkaV1))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	"github.com/optiopay/kafka/proto"
+	. "gopkg.in/check.v1"
+)
+
+type apiVersion struct {
+	apiKey, minVersion, maxVersion int16
+}
+
+// apiVersionsResponse returns a raw ApiVersions response of version 1
+func apiVersionsResponse(versions []apiVersion) *ResponseMessage {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, int32(10)) // correlation ID
+	binary.Write(&buf, binary.BigEndian, int16(0))  // error code
+	binary.Write(&buf, binary.BigEndian, int32(len(versions)))
+	for _, v := range versions {
+		binary.Write(&buf, binary.BigEndian, v)
+	}
+	binary.Write(&buf, binary.BigEndian, int32(0)) // throttle time
+
+	raw := make([]byte, 4, 4+buf.Len())
+	binary.BigEndian.PutUint32(raw, uint32(buf.Len()))
+	return &ResponseMessage{rawMsg: append(raw, buf.Bytes()...)}
+}
+
+func (k *kafkaTestSuite) TestLimitAPIVersions(c *C) {
+	rsp := apiVersionsResponse([]apiVersion{
+		{proto.ProduceReqKind, 0, 5},
+		{proto.FetchReqKind, 0, 5},
+		{proto.MetadataReqKind, 5, 5},
+		{api.SaslHandshakeKey, 0, 1},
+		{api.SaslAuthenticateKey, 0, 0},
+		{api.CreateTopicsKey, 0, 2},
+	})
+
+	c.Assert(rsp.LimitAPIVersions(proto.KafkaV1), IsNil)
+	c.Assert(rsp.GetRaw(), DeepEquals, apiVersionsResponse([]apiVersion{
+		{proto.ProduceReqKind, 0, 3},
+		{proto.FetchReqKind, 0, 5},
+		{proto.MetadataReqKind, 5, 5}, // unable to limit, left unmodified
+		{api.SaslHandshakeKey, 1, 1},
+		{api.SaslAuthenticateKey, 0, 0},
+		{api.CreateTopicsKey, 0, 2},
+	}).GetRaw())
+
+	// SASL handshakes are left unmodified if the broker only supports
+	// version 0
+	rsp = apiVersionsResponse([]apiVersion{{api.SaslHandshakeKey, 0, 0}})
+	c.Assert(rsp.LimitAPIVersions(proto.KafkaV0), IsNil)
+	c.Assert(rsp.GetRaw(), DeepEquals, apiVersionsResponse([]apiVersion{{api.SaslHandshakeKey, 0, 0}}).GetRaw())
+
+	// Later versions of the response are left unmodified
+	rsp = apiVersionsResponse([]apiVersion{{proto.ProduceReqKind, 0, 5}})
+	c.Assert(rsp.LimitAPIVersions(proto.KafkaV3), IsNil)
+	c.Assert(rsp.GetRaw(), DeepEquals, apiVersionsResponse([]apiVersion{{proto.ProduceReqKind, 0, 5}}).GetRaw())
+
+	rsp = &ResponseMessage{rawMsg: apiVersionsResponse([]apiVersion{{proto.ProduceReqKind, 0, 5}}).GetRaw()[:16]}
+	c.Assert(rsp.LimitAPIVersions(proto.KafkaV1), Not(IsNil))
+}
@@ -106,11 +106,23 @@ func NewCorrelationCache() *CorrelationCache {
 	return cc
 }
 
-// DeleteCache releases the cache and stops the garbage collector. This
+// DeleteCache releases the cache and stops the garbage collector. The
+// requests remaining in the cache are finished without response. This
 // function must be called when the cache is no longer required, otherwise go
 // routines are leaked.
 func (cc *CorrelationCache) DeleteCache() {
 	close(cc.stopGc)
+
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	for correlationID, entry := range cc.cache {
+		delete(cc.cache, correlationID)
+
+		if entry.finishFunc != nil {
+			entry.finishFunc(entry.request)
+		}
+	}
 }
 
 // HandleRequest must be called when a request is forwarded to the broker, will
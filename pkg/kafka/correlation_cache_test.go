@@ -73,6 +73,18 @@ func (k *kafkaTestSuite) TestCorrelation(c *C) {
 	cc.DeleteCache()
 }
 
+func (k *kafkaTestSuite) TestCorrelationDeleteCache(c *C) {
+	cc := NewCorrelationCache()
+
+	finished := 0
+	cc.HandleRequest(request1, func(req *RequestMessage) { finished++ })
+
+	// Requests remaining in a deleted cache are finished
+	cc.DeleteCache()
+	c.Assert(finished, Equals, 1)
+	c.Assert(cc.CorrelateResponse(createResponse(request1)), IsNil)
+}
+
 func (k *kafkaTestSuite) TestCorrelationGC(c *C) {
 	// reduce the lifetime of a request in the cache to 200 millisecond
 	RequestLifetime = 200 * time.Millisecond
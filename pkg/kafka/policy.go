@@ -160,6 +160,18 @@ func (req *RequestMessage) ruleMatches(rule api.PortRuleKafka) bool {
 		return false
 	}
 
+	if rule.GroupID != "" {
+		if groupID, ok := req.GetGroupID(); ok && groupID != rule.GroupID {
+			return false
+		}
+	}
+
+	if rule.TransactionalID != "" {
+		if transactionalID, ok := req.GetTransactionalID(); ok && transactionalID != rule.TransactionalID {
+			return false
+		}
+	}
+
 	// If the rule contains no additional conditionals, it is not required
 	// to match into the request specific fields.
 	if rule.Topic == "" && rule.ClientID == "" {
@@ -198,6 +210,13 @@ func (req *RequestMessage) ruleMatches(rule api.PortRuleKafka) bool {
 // rules. The function will return true if the policy allows the message,
 // otherwise false is returned.
 func (req *RequestMessage) MatchesRule(rules []api.PortRuleKafka) bool {
+	// Clients allowed to send any request at all must be able to negotiate
+	// the protocol versions and to authenticate, regardless of the API
+	// keys and versions allowed by the rules.
+	if len(rules) > 0 && isNegotiationAPIKey(req.kind) {
+		return true
+	}
+
 	topics := req.GetTopics()
 	// Maintain a map of all topics in the request.
 	// We should allow the request only if all topics are
@@ -15,6 +15,8 @@
 package kafka
 
 import (
+	"bytes"
+	"encoding/binary"
 	"testing"
 	"time"
 
@@ -125,3 +127,109 @@ func (k *kafkaTestSuite) TestUnknownRequest(c *C) {
 	reqMsg = RequestMessage{kind: 19}
 	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule1, rule2}), Equals, false)
 }
+
+func (k *kafkaTestSuite) TestNegotiationRequests(c *C) {
+	rule := api.PortRuleKafka{APIKey: "produce", APIVersion: "3", Topic: "foo"}
+	c.Assert(rule.Sanitize(), IsNil)
+
+	// Version negotiation and authentication are allowed regardless of
+	// the API key and version of the rules
+	for _, kind := range []int16{api.APIVersionsKey, api.SaslHandshakeKey, api.SaslAuthenticateKey} {
+		reqMsg := RequestMessage{kind: kind, version: 1}
+		c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, true)
+		c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{}), Equals, false)
+	}
+
+	reqMsg := RequestMessage{kind: api.HeartbeatKey, version: 1}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, false)
+}
+
+// rawRequest encodes a request of the given kind whose body starts with the
+// given nullable strings, nil strings are encoded as null.
+func rawRequest(kind int16, strs ...*string) []byte {
+	var buf bytes.Buffer
+	enc := proto.NewEncoder(&buf)
+	enc.Encode(int32(0))
+	enc.Encode(kind)
+	enc.Encode(int16(1))
+	enc.Encode(int32(1))
+	enc.Encode("client")
+	for _, s := range strs {
+		if s == nil {
+			enc.Encode(int16(-1))
+		} else {
+			enc.Encode(*s)
+		}
+	}
+	b := buf.Bytes()
+	binary.BigEndian.PutUint32(b, uint32(len(b)-4))
+	return b
+}
+
+func (k *kafkaTestSuite) TestGroupIDRequests(c *C) {
+	tenantA, tenantB := "tenant-a", "tenant-b"
+	rule := api.PortRuleKafka{Role: "consume", GroupID: tenantA}
+	c.Assert(rule.Sanitize(), IsNil)
+
+	reqMsg := RequestMessage{request: &proto.OffsetFetchReq{ConsumerGroup: tenantA}, kind: api.OffsetFetchKey}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, true)
+	reqMsg = RequestMessage{request: &proto.OffsetFetchReq{ConsumerGroup: tenantB}, kind: api.OffsetFetchKey}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, false)
+
+	reqMsg = RequestMessage{kind: api.JoinGroupKey, rawMsg: rawRequest(api.JoinGroupKey, &tenantA)}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, true)
+	reqMsg = RequestMessage{kind: api.HeartbeatKey, rawMsg: rawRequest(api.HeartbeatKey, &tenantB)}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, false)
+
+	// Truncated requests carrying a group ID never match
+	reqMsg = RequestMessage{kind: api.SyncgroupKey, rawMsg: rawRequest(api.SyncgroupKey)}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, false)
+
+	// Requests without a group ID are not constrained
+	reqMsg = RequestMessage{request: &proto.FetchReq{}, kind: api.FetchKey}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, true)
+}
+
+func (k *kafkaTestSuite) TestTransactionalIDRequests(c *C) {
+	txnA, txnB, group := "txn-a", "txn-b", "group"
+	rule := api.PortRuleKafka{Role: "produce", TransactionalID: txnA}
+	c.Assert(rule.Sanitize(), IsNil)
+
+	reqMsg := RequestMessage{request: &proto.ProduceReq{TransactionalID: txnA}, kind: api.ProduceKey}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, true)
+	reqMsg = RequestMessage{request: &proto.ProduceReq{TransactionalID: txnB}, kind: api.ProduceKey}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, false)
+	reqMsg = RequestMessage{request: &proto.ProduceReq{}, kind: api.ProduceKey}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, true)
+
+	// The produce role expands into the transactional API keys
+	reqMsg = RequestMessage{kind: api.EndTxnKey, rawMsg: rawRequest(api.EndTxnKey, &txnA)}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, true)
+	reqMsg = RequestMessage{kind: api.InitProducerIDKey, rawMsg: rawRequest(api.InitProducerIDKey, &txnB)}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, false)
+	reqMsg = RequestMessage{request: &proto.ConsumerMetadataReq{ConsumerGroup: txnA, CoordinatorType: 1}, kind: api.FindCoordinatorKey}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, true)
+	reqMsg = RequestMessage{request: &proto.ConsumerMetadataReq{ConsumerGroup: txnB, CoordinatorType: 1}, kind: api.FindCoordinatorKey}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, false)
+
+	// Idempotent producers without a transactional ID are not constrained
+	reqMsg = RequestMessage{kind: api.InitProducerIDKey, rawMsg: rawRequest(api.InitProducerIDKey, nil)}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{rule}), Equals, true)
+
+	// Group IDs of transactional offset commits
+	groupRule := api.PortRuleKafka{APIKey: "txnoffsetcommit", GroupID: group}
+	c.Assert(groupRule.Sanitize(), IsNil)
+	reqMsg = RequestMessage{kind: api.TxnOffsetCommitKey, rawMsg: rawRequest(api.TxnOffsetCommitKey, &txnA, &group)}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{groupRule}), Equals, true)
+	reqMsg = RequestMessage{kind: api.TxnOffsetCommitKey, rawMsg: rawRequest(api.TxnOffsetCommitKey, &txnA, &txnB)}
+	c.Assert(reqMsg.MatchesRule([]api.PortRuleKafka{groupRule}), Equals, false)
+}
+
+func (k *kafkaTestSuite) TestExpectsResponse(c *C) {
+	reqMsg := RequestMessage{request: &proto.ProduceReq{RequiredAcks: proto.RequiredAcksNone}, kind: api.ProduceKey}
+	c.Assert(reqMsg.ExpectsResponse(), Equals, false)
+	reqMsg = RequestMessage{request: &proto.ProduceReq{RequiredAcks: proto.RequiredAcksAll}, kind: api.ProduceKey}
+	c.Assert(reqMsg.ExpectsResponse(), Equals, true)
+	reqMsg = RequestMessage{request: &proto.FetchReq{}, kind: api.FetchKey}
+	c.Assert(reqMsg.ExpectsResponse(), Equals, true)
+}
@@ -20,8 +20,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/cilium/cilium/pkg/flowdebug"
+	"github.com/cilium/cilium/pkg/policy/api"
 
 	"github.com/optiopay/kafka/proto"
 )
@@ -32,6 +34,9 @@ type RequestMessage struct {
 	version int16
 	rawMsg  []byte
 	request interface{}
+
+	// received is the time the request was read
+	received time.Time
 }
 
 // CorrelationID represents the correlation id as defined in the Kafka protocol
@@ -53,6 +58,11 @@ func (req *RequestMessage) GetVersion() int16 {
 	return req.version
 }
 
+// GetReceived returns the time the request was read
+func (req *RequestMessage) GetReceived() time.Time {
+	return req.received
+}
+
 // GetCorrelationID returns the Kafka request correlationID
 func (req *RequestMessage) GetCorrelationID() CorrelationID {
 	if len(req.rawMsg) >= 12 {
@@ -69,6 +79,15 @@ func (req *RequestMessage) SetCorrelationID(id CorrelationID) {
 	}
 }
 
+// ExpectsResponse returns false if the broker does not respond to the
+// request, i.e. for produce requests which require no acknowledgements.
+func (req *RequestMessage) ExpectsResponse() bool {
+	if produce, ok := req.request.(*proto.ProduceReq); ok {
+		return produce.RequiredAcks != proto.RequiredAcksNone
+	}
+	return true
+}
+
 func (req *RequestMessage) extractVersion() int16 {
 	return int16(binary.BigEndian.Uint16(req.rawMsg[6:8]))
 }
@@ -152,6 +171,129 @@ func offsetFetchTopics(req *proto.OffsetFetchReq) []string {
 	return topics
 }
 
+// Coordinator types of FindCoordinator requests
+const (
+	coordinatorTypeGroup       = 0
+	coordinatorTypeTransaction = 1
+)
+
+// requestHeaderLen is the length of the size, API key, API version and
+// correlation ID preceding the client ID in a request
+const requestHeaderLen = 12
+
+// readString decodes the nullable string at offset off of the raw request b.
+// It returns the string, whether it is null and the offset following it.
+func readString(b []byte, off int) (string, bool, int, error) {
+	if len(b) < off+2 {
+		return "", false, 0, fmt.Errorf("unexpected end of request")
+	}
+	n := int(int16(binary.BigEndian.Uint16(b[off : off+2])))
+	off += 2
+	if n < 0 {
+		return "", true, off, nil
+	}
+	if len(b) < off+n {
+		return "", false, 0, fmt.Errorf("unexpected end of request")
+	}
+	return string(b[off : off+n]), false, off + n, nil
+}
+
+// readBodyString decodes the nullable string at the start of the request
+// body, following the client ID.
+func (req *RequestMessage) readBodyString() (string, bool, error) {
+	_, _, off, err := readString(req.rawMsg, requestHeaderLen)
+	if err != nil {
+		return "", false, err
+	}
+	s, null, _, err := readString(req.rawMsg, off)
+	return s, null, err
+}
+
+// readBodyStrings decodes the nullable string following the request header,
+// and the string following it after skip bytes.
+func (req *RequestMessage) readBodyStrings(skip int) (string, bool, string, error) {
+	_, _, off, err := readString(req.rawMsg, requestHeaderLen)
+	if err != nil {
+		return "", false, "", err
+	}
+	first, null, off, err := readString(req.rawMsg, off)
+	if err != nil {
+		return "", false, "", err
+	}
+	second, _, _, err := readString(req.rawMsg, off+skip)
+	return first, null, second, err
+}
+
+// GetGroupID returns the consumer group ID of the Kafka request and true if
+// the request carries a group ID. Requests which carry a group ID which
+// cannot be decoded return an empty group ID and true.
+func (req *RequestMessage) GetGroupID() (string, bool) {
+	switch val := req.request.(type) {
+	case *proto.OffsetCommitReq:
+		return val.ConsumerGroup, true
+	case *proto.OffsetFetchReq:
+		return val.ConsumerGroup, true
+	case *proto.ConsumerMetadataReq:
+		if val.CoordinatorType == coordinatorTypeGroup {
+			return val.ConsumerGroup, true
+		}
+		return "", false
+	}
+
+	var groupID string
+	var err error
+	switch req.kind {
+	case api.JoinGroupKey, api.HeartbeatKey, api.LeaveGroupKey, api.SyncgroupKey:
+		groupID, _, err = req.readBodyString()
+	case api.AddOffsetsToTxnKey:
+		// The group ID follows the transactional ID, producer ID and epoch
+		_, _, groupID, err = req.readBodyStrings(8 + 2)
+	case api.TxnOffsetCommitKey:
+		_, _, groupID, err = req.readBodyStrings(0)
+	default:
+		return "", false
+	}
+	if err != nil {
+		return "", true
+	}
+	return groupID, true
+}
+
+// GetTransactionalID returns the transactional ID of the Kafka request and
+// true if the request carries a transactional ID. Requests which carry a
+// transactional ID which cannot be decoded return an empty transactional ID
+// and true.
+func (req *RequestMessage) GetTransactionalID() (string, bool) {
+	switch val := req.request.(type) {
+	case *proto.ProduceReq:
+		// Non-transactional producers send a null transactional ID
+		if val.TransactionalID != "" {
+			return val.TransactionalID, true
+		}
+		return "", false
+	case *proto.ConsumerMetadataReq:
+		if val.CoordinatorType == coordinatorTypeTransaction {
+			return val.ConsumerGroup, true
+		}
+		return "", false
+	}
+
+	switch req.kind {
+	case api.InitProducerIDKey, api.AddPartitionsToTxnKey, api.AddOffsetsToTxnKey,
+		api.EndTxnKey, api.TxnOffsetCommitKey:
+		transactionalID, null, err := req.readBodyString()
+		switch {
+		case err != nil:
+			return "", true
+		case null:
+			// Idempotent producers without transactions
+			return "", false
+		}
+		return transactionalID, true
+	}
+	return "", false
+}
+
 // CreateResponse creates a response message based on the provided request
 // message. The response will have the specified error code set in all topics
 // and embedded partitions.
@@ -191,6 +333,7 @@ func ReadRequest(reader io.Reader) (*RequestMessage, error) {
 	if err != nil {
 		return nil, err
 	}
+	req.received = time.Now()
 
 	if len(req.rawMsg) < 12 {
 		return nil,
@@ -95,12 +95,21 @@ type BackendOperations interface {
 	// Get returns value of key
 	Get(key string) ([]byte, error)
 
+	// GetWithConsistency returns value of key read with the given
+	// consistency
+	GetWithConsistency(key string, consistency Consistency) ([]byte, error)
+
 	// GetPrefix returns the first key which matches the prefix
 	GetPrefix(prefix string) ([]byte, error)
 
 	// Set sets value of key
 	Set(key string, value []byte) error
 
+	// CompareAndSwap atomically sets key to newValue if its current value
+	// is oldValue. A nil oldValue requires the key to not exist. Returns
+	// ErrCompareFailed if the current value does not match.
+	CompareAndSwap(key string, oldValue, newValue []byte) error
+
 	// Delete deletes a key
 	Delete(key string) error
 
@@ -118,6 +127,10 @@ type BackendOperations interface {
 	// ListPrefix returns a list of keys matching the prefix
 	ListPrefix(prefix string) (KeyValuePairs, error)
 
+	// ListPrefixWithConsistency returns a list of keys matching the prefix
+	// read with the given consistency
+	ListPrefixWithConsistency(prefix string, consistency Consistency) (KeyValuePairs, error)
+
 	// Watch starts watching for changes in a prefix. If list is true, the
 	// current keys matching the prefix will be listed and reported as new
 	// keys first.
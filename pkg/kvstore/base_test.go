@@ -248,3 +248,28 @@ func (s *BaseTests) TestListAndWatch(c *C) {
 
 	w.Stop()
 }
+
+func (s *BaseTests) TestCompareAndSwap(c *C) {
+	prefix := "unit-test/"
+
+	DeletePrefix(prefix)
+	defer DeletePrefix(prefix)
+
+	key := testKey(prefix, 0)
+
+	// create only succeeds if the key does not exist
+	c.Assert(CompareAndSwap(key, nil, testValue(0)), IsNil)
+	c.Assert(CompareAndSwap(key, nil, testValue(1)), Equals, ErrCompareFailed)
+
+	val, err := GetWithConsistency(key, ConsistencyLinearizable)
+	c.Assert(err, IsNil)
+	c.Assert(val, checker.DeepEquals, testValue(0))
+
+	// swap only succeeds if the value matches
+	c.Assert(CompareAndSwap(key, testValue(1), testValue(2)), Equals, ErrCompareFailed)
+	c.Assert(CompareAndSwap(key, testValue(0), testValue(2)), IsNil)
+
+	pairs, err := ListPrefixWithConsistency(prefix, ConsistencySerializable)
+	c.Assert(err, IsNil)
+	c.Assert(pairs, checker.DeepEquals, KeyValuePairs{key: testValue(2)})
+}
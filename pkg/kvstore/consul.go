@@ -15,6 +15,7 @@
 package kvstore
 
 import (
+	"bytes"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -315,6 +316,35 @@ func (c *consulClient) Set(key string, value []byte) error {
 	return err
 }
 
+// CompareAndSwap sets key to newValue if its current value is oldValue
+func (c *consulClient) CompareAndSwap(key string, oldValue, newValue []byte) error {
+	pair, _, err := c.KV().Get(key, consulQueryOptions(ConsistencyLinearizable))
+	if err != nil {
+		return err
+	}
+
+	// The modify index of the key guarantees that the key has not been
+	// changed since the comparison, an index of 0 that it does not exist
+	k := &consulAPI.KVPair{Key: key, Value: newValue}
+	switch {
+	case pair == nil && oldValue == nil:
+	case pair != nil && oldValue != nil && bytes.Equal(pair.Value, oldValue):
+		k.ModifyIndex = pair.ModifyIndex
+	default:
+		return ErrCompareFailed
+	}
+
+	success, _, err := c.KV().CAS(k, nil)
+	if err != nil {
+		return fmt.Errorf("unable to compare-and-swap: %s", err)
+	}
+	if !success {
+		return ErrCompareFailed
+	}
+
+	return nil
+}
+
 // Delete deletes a key
 func (c *consulClient) Delete(key string) error {
 	_, err := c.KV().Delete(key, nil)
@@ -333,6 +363,27 @@ func (c *consulClient) Get(key string) ([]byte, error) {
 	return pair.Value, nil
 }
 
+// consulQueryOptions returns the query options of a read with the given
+// consistency
+func consulQueryOptions(consistency Consistency) *consulAPI.QueryOptions {
+	if consistency == ConsistencySerializable {
+		return &consulAPI.QueryOptions{AllowStale: true}
+	}
+	return &consulAPI.QueryOptions{RequireConsistent: true}
+}
+
+// GetWithConsistency returns value of key read with the given consistency
+func (c *consulClient) GetWithConsistency(key string, consistency Consistency) ([]byte, error) {
+	pair, _, err := c.KV().Get(key, consulQueryOptions(consistency))
+	if err != nil {
+		return nil, err
+	}
+	if pair == nil {
+		return nil, nil
+	}
+	return pair.Value, nil
+}
+
 // GetPrefix returns the first key which matches the prefix
 func (c *consulClient) GetPrefix(prefix string) ([]byte, error) {
 	pairs, _, err := c.KV().List(prefix, nil)
@@ -414,7 +465,17 @@ func (c *consulClient) CreateIfExists(condKey, key string, value []byte, lease b
 
 // ListPrefix returns a map of matching keys
 func (c *consulClient) ListPrefix(prefix string) (KeyValuePairs, error) {
-	pairs, _, err := c.KV().List(prefix, nil)
+	return c.listPrefix(prefix, nil)
+}
+
+// ListPrefixWithConsistency returns a map of matching keys read with the
+// given consistency
+func (c *consulClient) ListPrefixWithConsistency(prefix string, consistency Consistency) (KeyValuePairs, error) {
+	return c.listPrefix(prefix, consulQueryOptions(consistency))
+}
+
+func (c *consulClient) listPrefix(prefix string, q *consulAPI.QueryOptions) (KeyValuePairs, error) {
+	pairs, _, err := c.KV().List(prefix, q)
 	if err != nil {
 		return nil, err
 	}
@@ -557,7 +557,20 @@ func (e *etcdClient) Status() (string, error) {
 
 // Get returns value of key
 func (e *etcdClient) Get(key string) ([]byte, error) {
-	getR, err := e.client.Get(ctx.Background(), key)
+	return e.GetWithConsistency(key, ConsistencyLinearizable)
+}
+
+// consistencyOpts returns the options of a read with the given consistency
+func consistencyOpts(consistency Consistency) []client.OpOption {
+	if consistency == ConsistencySerializable {
+		return []client.OpOption{client.WithSerializable()}
+	}
+	return nil
+}
+
+// GetWithConsistency returns value of key read with the given consistency
+func (e *etcdClient) GetWithConsistency(key string, consistency Consistency) ([]byte, error) {
+	getR, err := e.client.Get(ctx.Background(), key, consistencyOpts(consistency)...)
 	if err != nil {
 		return nil, err
 	}
@@ -587,6 +600,24 @@ func (e *etcdClient) Set(key string, value []byte) error {
 	return err
 }
 
+// CompareAndSwap sets key to newValue if its current value is oldValue
+func (e *etcdClient) CompareAndSwap(key string, oldValue, newValue []byte) error {
+	cond := client.Compare(client.Version(key), "=", 0)
+	if oldValue != nil {
+		cond = client.Compare(client.Value(key), "=", string(oldValue))
+	}
+	txnresp, err := e.client.Txn(ctx.TODO()).If(cond).Then(client.OpPut(key, string(newValue))).Commit()
+	if err != nil {
+		return err
+	}
+
+	if txnresp.Succeeded == false {
+		return ErrCompareFailed
+	}
+
+	return nil
+}
+
 // Delete deletes a key
 func (e *etcdClient) Delete(key string) error {
 	_, err := e.client.Delete(ctx.Background(), key)
@@ -668,7 +699,14 @@ func (e *etcdClient) CreateIfExists(condKey, key string, value []byte, lease boo
 
 // ListPrefix returns a map of matching keys
 func (e *etcdClient) ListPrefix(prefix string) (KeyValuePairs, error) {
-	getR, err := e.client.Get(ctx.Background(), prefix, client.WithPrefix())
+	return e.ListPrefixWithConsistency(prefix, ConsistencyLinearizable)
+}
+
+// ListPrefixWithConsistency returns a map of matching keys read with the
+// given consistency
+func (e *etcdClient) ListPrefixWithConsistency(prefix string, consistency Consistency) (KeyValuePairs, error) {
+	opts := append(consistencyOpts(consistency), client.WithPrefix())
+	getR, err := e.client.Get(ctx.Background(), prefix, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -15,6 +15,9 @@
 package kvstore
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/sirupsen/logrus"
 )
 
@@ -35,6 +38,33 @@ const (
 	BaseKeyPrefix = "cilium"
 )
 
+// Consistency is the consistency guarantee of a read from the kvstore
+type Consistency string
+
+const (
+	// ConsistencyLinearizable reads reflect all writes committed before
+	// the read was issued
+	ConsistencyLinearizable Consistency = "linearizable"
+
+	// ConsistencySerializable reads may be served by any member of the
+	// kvstore cluster and can return stale data
+	ConsistencySerializable Consistency = "serializable"
+)
+
+// ErrCompareFailed is returned by CompareAndSwap if the current value of the
+// key does not match the expected value
+var ErrCompareFailed = errors.New("current value does not match expected value")
+
+// ParseConsistency parses the name of a consistency level
+func ParseConsistency(s string) (Consistency, error) {
+	switch c := Consistency(s); c {
+	case ConsistencyLinearizable, ConsistencySerializable:
+		return c, nil
+	}
+	return "", fmt.Errorf("invalid consistency %q, must be one of %s, %s",
+		s, ConsistencyLinearizable, ConsistencySerializable)
+}
+
 // Get returns value of key
 func Get(key string) ([]byte, error) {
 	v, err := Client().Get(key)
@@ -42,6 +72,13 @@ func Get(key string) ([]byte, error) {
 	return v, err
 }
 
+// GetWithConsistency returns value of key read with the given consistency
+func GetWithConsistency(key string, consistency Consistency) ([]byte, error) {
+	v, err := Client().GetWithConsistency(key, consistency)
+	Trace("GetWithConsistency", err, logrus.Fields{fieldKey: key, fieldValue: string(v), fieldConsistency: consistency})
+	return v, err
+}
+
 // GetPrefix returns the first key which matches the prefix
 func GetPrefix(prefix string) ([]byte, error) {
 	v, err := Client().GetPrefix(prefix)
@@ -56,6 +93,14 @@ func ListPrefix(prefix string) (KeyValuePairs, error) {
 	return v, err
 }
 
+// ListPrefixWithConsistency returns the list of keys matching the prefix
+// read with the given consistency
+func ListPrefixWithConsistency(prefix string, consistency Consistency) (KeyValuePairs, error) {
+	v, err := Client().ListPrefixWithConsistency(prefix, consistency)
+	Trace("ListPrefixWithConsistency", err, logrus.Fields{fieldPrefix: prefix, fieldNumEntries: len(v), fieldConsistency: consistency})
+	return v, err
+}
+
 // CreateOnly atomically creates a key or fails if it already exists
 func CreateOnly(key string, value []byte, lease bool) error {
 	err := Client().CreateOnly(key, value, lease)
@@ -84,6 +129,14 @@ func Set(key string, value []byte) error {
 	return err
 }
 
+// CompareAndSwap atomically sets key to newValue if its current value is
+// oldValue. Returns ErrCompareFailed if the current value does not match.
+func CompareAndSwap(key string, oldValue, newValue []byte) error {
+	err := Client().CompareAndSwap(key, oldValue, newValue)
+	Trace("CompareAndSwap", err, logrus.Fields{fieldKey: key, fieldOldValue: string(oldValue), fieldValue: string(newValue)})
+	return err
+}
+
 // Delete deletes a key
 func Delete(key string) error {
 	err := Client().Delete(key)
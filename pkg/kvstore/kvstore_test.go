@@ -33,3 +33,16 @@ func (s *independentSuite) TestGetLockPath(c *C) {
 	const path = "foo/path"
 	c.Assert(getLockPath(path), Equals, path+".lock")
 }
+
+func (s *independentSuite) TestParseConsistency(c *C) {
+	consistency, err := ParseConsistency("serializable")
+	c.Assert(err, IsNil)
+	c.Assert(consistency, Equals, ConsistencySerializable)
+
+	consistency, err = ParseConsistency("linearizable")
+	c.Assert(err, IsNil)
+	c.Assert(consistency, Equals, ConsistencyLinearizable)
+
+	_, err = ParseConsistency("eventual")
+	c.Assert(err, ErrorMatches, "invalid consistency \"eventual\".*")
+}
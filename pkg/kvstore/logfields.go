@@ -40,6 +40,12 @@ const (
 	// fieldValue is the prefix of the key used in the operation
 	fieldValue = "value"
 
+	// fieldOldValue is the value expected by a compare-and-swap operation
+	fieldOldValue = "oldValue"
+
+	// fieldConsistency is the consistency level of a read
+	fieldConsistency = "consistency"
+
 	// fieldCondition is the condition that requires to be met
 	fieldCondition = "condition"
 
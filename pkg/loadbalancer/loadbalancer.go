@@ -60,9 +60,15 @@ type ServiceID uint16
 type LBBackEnd struct {
 	L3n4Addr
 	Weight uint16
+	// Draining is true if the backend must not receive new connections.
+	// Established connections to the backend are not affected.
+	Draining bool
 }
 
 func (lbbe *LBBackEnd) String() string {
+	if lbbe.Draining {
+		return fmt.Sprintf("%s, weight: %d, draining", lbbe.L3n4Addr.String(), lbbe.Weight)
+	}
 	return fmt.Sprintf("%s, weight: %d", lbbe.L3n4Addr.String(), lbbe.Weight)
 }
 
@@ -448,6 +454,7 @@ func NewLBBackEndFromBackendModel(base *models.BackendAddress) (*LBBackEnd, erro
 	return &LBBackEnd{
 		L3n4Addr: L3n4Addr{IP: ip, L4Addr: *l4addr},
 		Weight:   base.Weight,
+		Draining: base.State == models.BackendAddressStateDraining,
 	}, nil
 }
 
@@ -487,10 +494,15 @@ func (b *LBBackEnd) GetBackendModel() *models.BackendAddress {
 	}
 
 	ip := b.IP.String()
+	state := models.BackendAddressStateActive
+	if b.Draining {
+		state = models.BackendAddressStateDraining
+	}
 	return &models.BackendAddress{
 		IP:     &ip,
 		Port:   b.Port,
 		Weight: b.Weight,
+		State:  state,
 	}
 }
 
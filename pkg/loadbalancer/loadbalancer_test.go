@@ -18,6 +18,8 @@ import (
 	"net"
 	"testing"
 
+	"github.com/cilium/cilium/api/v1/models"
+
 	"gopkg.in/check.v1"
 )
 
@@ -39,6 +41,29 @@ func (s *TypesSuite) TestIsK8ServiceExternal(c *check.C) {
 	c.Assert(si.IsExternal(), check.Equals, false)
 }
 
+func (s *TypesSuite) TestLBBackEndModel(c *check.C) {
+	be, err := NewLBBackEnd(TCP, net.ParseIP("10.0.0.1"), 80, 2)
+	c.Assert(err, check.IsNil)
+
+	model := be.GetBackendModel()
+	c.Assert(model.State, check.Equals, models.BackendAddressStateActive)
+	c.Assert(model.Weight, check.Equals, uint16(2))
+
+	be.Draining = true
+	model = be.GetBackendModel()
+	c.Assert(model.State, check.Equals, models.BackendAddressStateDraining)
+
+	be2, err := NewLBBackEndFromBackendModel(model)
+	c.Assert(err, check.IsNil)
+	c.Assert(be2.Draining, check.Equals, true)
+	c.Assert(be2.Weight, check.Equals, uint16(2))
+
+	model.State = ""
+	be2, err = NewLBBackEndFromBackendModel(model)
+	c.Assert(err, check.IsNil)
+	c.Assert(be2.Draining, check.Equals, false)
+}
+
 func TestL4Addr_Equals(t *testing.T) {
 	type args struct {
 		o *L4Addr
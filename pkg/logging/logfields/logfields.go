@@ -203,6 +203,9 @@ const (
 	// Route is a L2 or L3 Linux route
 	Route = "route"
 
+	// L7Parser is the name of a L7 protocol parser of the proxy
+	L7Parser = "l7Parser"
+
 	// RetryUUID is an UUID identical for all retries of a set
 	RetryUUID = "retryUUID"
 
@@ -25,10 +25,12 @@ import (
 	"unsafe"
 
 	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/u8proto"
 )
 
 var (
@@ -71,6 +73,16 @@ const (
 
 	MapNumEntriesLocal = 64000
 
+	// dumpChunkSize is the number of entries processed at once when
+	// iterating over a map. The GC deletes the entries of each chunk with
+	// a single batch operation.
+	dumpChunkSize = 1024
+
+	// schemaVersion is the version of the layout of CT keys and entries.
+	// It must be increased and a converter of entries of the previous
+	// version added to schemaConverters whenever the layout changes.
+	schemaVersion = 1
+
 	TUPLE_F_OUT     = 0
 	TUPLE_F_IN      = 1
 	TUPLE_F_RELATED = 2
@@ -86,6 +98,10 @@ const (
 	metricsDeleted = "deleted"
 )
 
+// schemaConverters convert CT entries of older schema versions, keyed by the
+// version they convert from
+var schemaConverters = map[int]bpf.MapConverter{}
+
 type mapAttributes struct {
 	keySize    int
 	maxEntries int
@@ -129,6 +145,12 @@ func InitMapInfo(tcpMaxEntries, anyMaxEntries int) {
 
 func init() {
 	InitMapInfo(option.CTMapEntriesGlobalTCPDefault, option.CTMapEntriesGlobalAnyDefault)
+
+	valueSize := int(unsafe.Sizeof(CtEntry{}))
+	bpf.RegisterMapCodec(MapNameTCP4, int(unsafe.Sizeof(CtKey4{})), valueSize, ct4DumpParser)
+	bpf.RegisterMapCodec(MapNameTCP6, int(unsafe.Sizeof(CtKey6{})), valueSize, ct6DumpParser)
+	bpf.RegisterMapCodec(MapNameAny4, int(unsafe.Sizeof(CtKey4{})), valueSize, ct4DumpParser)
+	bpf.RegisterMapCodec(MapNameAny6, int(unsafe.Sizeof(CtKey6{})), valueSize, ct6DumpParser)
 }
 
 // CtEndpoint represents an endpoint for the functions required to manage
@@ -180,24 +202,116 @@ type GCFilter struct {
 
 	// MatchIPs is the list of IPs to remove from the conntrack table
 	MatchIPs map[string]struct{}
+
+	// MatchTuple, if not nil, restricts the filter to entries whose tuple
+	// matches. All other entries are left untouched.
+	MatchTuple *TupleFilter
+}
+
+// TupleFilter selects conntrack entries by the tuple of the packet which
+// created them. Unset fields match any value.
+type TupleFilter struct {
+	// SrcIP is the source IP of the packet
+	SrcIP net.IP
+
+	// DstIP is the destination IP of the packet
+	DstIP net.IP
+
+	// Port matches either the source or the destination port
+	Port uint16
+
+	// Proto is the L4 protocol
+	Proto u8proto.U8proto
+
+	// MatchIPs is the list of IPs of which at least one must be the source
+	// or destination IP. The key is the IP in string form: net.IP.String()
+	MatchIPs map[string]struct{}
+}
+
+// Matches returns true if the given packet tuple is selected by f.
+func (f *TupleFilter) Matches(srcIP, dstIP net.IP, srcPort, dstPort uint16, proto u8proto.U8proto) bool {
+	if f.SrcIP != nil && !f.SrcIP.Equal(srcIP) {
+		return false
+	}
+	if f.DstIP != nil && !f.DstIP.Equal(dstIP) {
+		return false
+	}
+	if f.Port != 0 && f.Port != srcPort && f.Port != dstPort {
+		return false
+	}
+	if f.Proto != u8proto.All && f.Proto != proto {
+		return false
+	}
+	if f.MatchIPs != nil && !matchIPs(f.MatchIPs, srcIP, dstIP) {
+		return false
+	}
+	return true
+}
+
+// matchesKey returns true if the tuple of the conntrack key is selected by f.
+// As in the garbage collector, the source address of the conntrack entry
+// (`SourceAddr`) is the packet's destination IP, and the ports are stored in
+// network byte order.
+func (f *TupleFilter) matchesKey(key bpf.MapKey) bool {
+	switch k := key.(type) {
+	case *CtKey4Global:
+		return f.Matches(k.DestAddr.IP(), k.SourceAddr.IP(),
+			byteorder.NetworkToHost(k.DestPort).(uint16),
+			byteorder.NetworkToHost(k.SourcePort).(uint16), k.NextHeader)
+	case *CtKey6Global:
+		return f.Matches(k.DestAddr.IP(), k.SourceAddr.IP(),
+			byteorder.NetworkToHost(k.DestPort).(uint16),
+			byteorder.NetworkToHost(k.SourcePort).(uint16), k.NextHeader)
+	}
+	return false
 }
 
 // ToString iterates through Map m and writes the values of the ct entries in m
 // to a string.
 func (m *Map) DumpEntries() (string, error) {
+	return m.DumpEntriesMatching(nil)
+}
+
+// DumpEntriesMatching iterates through Map m and writes the values of the ct
+// entries in m selected by filter to a string. A nil filter selects all
+// entries.
+func (m *Map) DumpEntriesMatching(filter *TupleFilter) (string, error) {
 	var buffer bytes.Buffer
+	err := m.WriteEntries(&buffer, filter)
+	return buffer.String(), err
+}
 
-	cb := func(k bpf.MapKey, v bpf.MapValue) {
-		key := k.(CtKey)
-		if !key.ToHost().Dump(&buffer) {
+// WriteEntries iterates through Map m and writes the values of the ct
+// entries in m selected by filter to w. A nil filter selects all entries.
+// The entries are written in chunks so that the memory usage does not
+// depend on the size of the map.
+func (m *Map) WriteEntries(w io.Writer, filter *TupleFilter) error {
+	var (
+		buffer   bytes.Buffer
+		writeErr error
+	)
+
+	cb := func(keys []bpf.MapKey, values []bpf.MapValue) {
+		if writeErr != nil {
 			return
 		}
-		value := v.(*CtEntry)
-		buffer.WriteString(value.String())
+		for i, k := range keys {
+			if filter != nil && !filter.matchesKey(k) {
+				continue
+			}
+			key := k.(CtKey)
+			if !key.ToHost().Dump(&buffer) {
+				continue
+			}
+			value := values[i].(*CtEntry)
+			buffer.WriteString(value.String())
+		}
+		_, writeErr = buffer.WriteTo(w)
 	}
-	// DumpWithCallback() must be called before buffer.String().
-	err := m.DumpWithCallback(cb)
-	return buffer.String(), err
+	if err := m.DumpChunkedWithCallback(dumpChunkSize, cb, nil); err != nil {
+		return err
+	}
+	return writeErr
 }
 
 func ct4DumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
@@ -232,38 +346,61 @@ func NewMap(mapName string, mapType MapType) *Map {
 		mapType: mapType,
 		define:  mapInfo[mapType].bpfDefine,
 	}
+	result.Map.WithSchema(schemaVersion, schemaConverters)
 	return result
 }
 
+// deleteBatch deletes the given keys from m and accounts the deletions in
+// stats. Returns keys truncated for reuse.
+func deleteBatch(m *Map, keys []bpf.MapKey, stats *gcStats) []bpf.MapKey {
+	if len(keys) == 0 {
+		return keys
+	}
+
+	errors := m.DeleteBatch(keys)
+	for i, err := range errors {
+		log.WithError(err).Errorf("Unable to delete CT entry %s", keys[i].String())
+	}
+	stats.deleted += uint32(len(keys) - len(errors))
+
+	return keys[:0]
+}
+
 // doGC6 iterates through a CTv6 map and drops entries based on the given
 // filter.
 func doGC6(m *Map, filter *GCFilter) gcStats {
 	stats := statStartGc(m)
 	defer stats.finish()
 
-	filterCallback := func(key bpf.MapKey, value bpf.MapValue) {
-		currentKey := key.(*CtKey6Global)
-		entry := value.(*CtEntry)
-
-		// In CT entries, the source address of the conntrack entry (`SourceAddr`) is
-		// the destination of the packet received, therefore it's the packet's
-		// destination IP
-		action := filter.doFiltering(currentKey.DestAddr.IP(), currentKey.SourceAddr.IP(), currentKey.SourcePort,
-			uint8(currentKey.NextHeader), currentKey.Flags, entry)
-
-		switch action {
-		case deleteEntry:
-			err := m.Delete(currentKey)
-			if err != nil {
-				log.WithError(err).Errorf("Unable to delete CT entry %s", currentKey.String())
-			} else {
-				stats.deleted++
+	var pending []bpf.MapKey
+
+	filterCallback := func(keys []bpf.MapKey, values []bpf.MapValue) {
+		stats.scanned += uint32(len(keys))
+		for i := range keys {
+			currentKey := keys[i].(*CtKey6Global)
+			entry := values[i].(*CtEntry)
+
+			if filter.MatchTuple != nil && !filter.MatchTuple.matchesKey(currentKey) {
+				stats.aliveEntries++
+				continue
+			}
+
+			// In CT entries, the source address of the conntrack entry (`SourceAddr`) is
+			// the destination of the packet received, therefore it's the packet's
+			// destination IP
+			action := filter.doFiltering(currentKey.DestAddr.IP(), currentKey.SourceAddr.IP(), currentKey.SourcePort,
+				uint8(currentKey.NextHeader), currentKey.Flags, entry)
+
+			switch action {
+			case deleteEntry:
+				pending = append(pending, currentKey)
+			default:
+				stats.aliveEntries++
 			}
-		default:
-			stats.aliveEntries++
 		}
+		pending = deleteBatch(m, pending, &stats)
 	}
-	stats.dumpError = m.DumpReliablyWithCallback(filterCallback, stats.DumpStats)
+	stats.dumpError = m.DumpChunkedWithCallback(dumpChunkSize, filterCallback, stats.DumpStats)
 
 	return stats
 }
@@ -274,29 +411,35 @@ func doGC4(m *Map, filter *GCFilter) gcStats {
 	stats := statStartGc(m)
 	defer stats.finish()
 
-	filterCallback := func(key bpf.MapKey, value bpf.MapValue) {
-		currentKey := key.(*CtKey4Global)
-		entry := value.(*CtEntry)
-
-		// In CT entries, the source address of the conntrack entry (`SourceAddr`) is
-		// the destination of the packet received, therefore it's the packet's
-		// destination IP
-		action := filter.doFiltering(currentKey.DestAddr.IP(), currentKey.SourceAddr.IP(), currentKey.SourcePort,
-			uint8(currentKey.NextHeader), currentKey.Flags, entry)
-
-		switch action {
-		case deleteEntry:
-			err := m.Delete(currentKey)
-			if err != nil {
-				log.WithError(err).Errorf("Unable to delete CT entry %s", currentKey.String())
-			} else {
-				stats.deleted++
+	var pending []bpf.MapKey
+
+	filterCallback := func(keys []bpf.MapKey, values []bpf.MapValue) {
+		stats.scanned += uint32(len(keys))
+		for i := range keys {
+			currentKey := keys[i].(*CtKey4Global)
+			entry := values[i].(*CtEntry)
+
+			if filter.MatchTuple != nil && !filter.MatchTuple.matchesKey(currentKey) {
+				stats.aliveEntries++
+				continue
+			}
+
+			// In CT entries, the source address of the conntrack entry (`SourceAddr`) is
+			// the destination of the packet received, therefore it's the packet's
+			// destination IP
+			action := filter.doFiltering(currentKey.DestAddr.IP(), currentKey.SourceAddr.IP(), currentKey.SourcePort,
+				uint8(currentKey.NextHeader), currentKey.Flags, entry)
+
+			switch action {
+			case deleteEntry:
+				pending = append(pending, currentKey)
+			default:
+				stats.aliveEntries++
 			}
-		default:
-			stats.aliveEntries++
 		}
+		pending = deleteBatch(m, pending, &stats)
 	}
-	stats.dumpError = m.DumpReliablyWithCallback(filterCallback, stats.DumpStats)
+	stats.dumpError = m.DumpChunkedWithCallback(dumpChunkSize, filterCallback, stats.DumpStats)
 
 	return stats
 }
@@ -314,51 +457,176 @@ func (f *GCFilter) doFiltering(srcIP net.IP, dstIP net.IP, dstPort uint16, nextH
 		}
 	}
 
-	if f.MatchIPs != nil {
-		_, srcIPExists := f.MatchIPs[srcIP.String()]
-		_, dstIPExists := f.MatchIPs[dstIP.String()]
-		if srcIPExists || dstIPExists {
-			return deleteEntry
-		}
+	if f.MatchIPs != nil && matchIPs(f.MatchIPs, srcIP, dstIP) {
+		return deleteEntry
 	}
 
 	return noAction
 }
 
-func doGC(m *Map, filter *GCFilter) int {
+// GCResult is the result of a garbage collection run on a CT map
+type GCResult struct {
+	// Scanned is the number of entries visited
+	Scanned int
+
+	// Deleted is the number of entries deleted
+	Deleted int
+
+	// MaxEntries is the maximum number of entries in the map
+	MaxEntries int
+
+	// LRU is true if the map evicts the least recently used entries once
+	// it is full. Other maps fail to create entries for new connections.
+	LRU bool
+}
+
+// DeleteRatio returns the share of the map capacity freed by the run
+func (r GCResult) DeleteRatio() float64 {
+	if r.MaxEntries == 0 {
+		return 0
+	}
+	return float64(r.Deleted) / float64(r.MaxEntries)
+}
+
+// FillRatio returns the share of the map capacity in use after the run
+func (r GCResult) FillRatio() float64 {
+	if r.MaxEntries == 0 {
+		return 0
+	}
+	return float64(r.Scanned-r.Deleted) / float64(r.MaxEntries)
+}
+
+func doGC(m *Map, filter *GCFilter) gcStats {
 	if m.mapType.isIPv6() {
-		return int(doGC6(m, filter).deleted)
+		return doGC6(m, filter)
 	} else if m.mapType.isIPv4() {
-		return int(doGC4(m, filter).deleted)
+		return doGC4(m, filter)
 	}
 	log.Fatalf("Unsupported ct map type: %s", m.mapType.String())
-	return 0
+	return gcStats{}
 }
 
 // GC runs garbage collection for map m with name mapType with the given filter.
-// It returns how many items were deleted from m.
-func GC(m *Map, filter *GCFilter) int {
+// It returns the number of scanned and deleted entries.
+func GC(m *Map, filter *GCFilter) GCResult {
 	if filter.RemoveExpired {
-		// If LRUHashtable, no need to garbage collect as LRUHashtable cleans itself up.
-		// FIXME: GH-3239 LRU logic is not handling timeouts gracefully enough
-		// if m.MapInfo.MapType == bpf.MapTypeLRUHash {
-		// 	return 0
-		// }
+		// LRU maps only evict entries once they are full, expired
+		// entries are removed here regardless of the map type so
+		// that their lifetimes are enforced.
 		t, _ := bpf.GetMtime()
 		tsec := t / 1000000000
 		filter.Time = uint32(tsec)
 	}
 
-	return doGC(m, filter)
+	stats := doGC(m, filter)
+	return GCResult{
+		Scanned:    int(stats.scanned),
+		Deleted:    int(stats.deleted),
+		MaxEntries: int(m.MapInfo.MaxEntries),
+		LRU:        m.MapInfo.MapType == bpf.MapTypeLRUHash,
+	}
 }
 
 // Flush runs garbage collection for map m with the name mapType, deleting all
 // entries. The specified map must be already opened using bpf.OpenMap().
 func (m *Map) Flush() int {
-	return doGC(m, &GCFilter{
+	return int(doGC(m, &GCFilter{
 		RemoveExpired: true,
 		Time:          MaxTime,
-	})
+	}).deleted)
+}
+
+// FlushMatching deletes all entries of map m selected by filter. The
+// specified map must be already opened using bpf.OpenMap().
+func (m *Map) FlushMatching(filter *TupleFilter) int {
+	return int(doGC(m, &GCFilter{
+		RemoveExpired: true,
+		Time:          MaxTime,
+		MatchTuple:    filter,
+	}).deleted)
+}
+
+// matchIPs returns true if either srcIP or dstIP is contained in ips.
+func matchIPs(ips map[string]struct{}, srcIP, dstIP net.IP) bool {
+	_, srcIPExists := ips[srcIP.String()]
+	_, dstIPExists := ips[dstIP.String()]
+	return srcIPExists || dstIPExists
+}
+
+// copyEntries copies all entries of the src map for which the source or
+// destination IP matches one of the given IPs into the dst map. The dst map
+// is created if it does not exist yet. Returns the number of copied entries
+// and the first error that occurred.
+func copyEntries(src, dst *Map, ips map[string]struct{}) (int, error) {
+	if err := src.Open(); err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	if _, err := dst.OpenOrCreate(); err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	var (
+		copied    int
+		updateErr error
+	)
+	cb := func(key bpf.MapKey, value bpf.MapValue) {
+		var srcIP, dstIP net.IP
+		switch k := key.(type) {
+		case *CtKey4Global:
+			srcIP, dstIP = k.DestAddr.IP(), k.SourceAddr.IP()
+		case *CtKey6Global:
+			srcIP, dstIP = k.DestAddr.IP(), k.SourceAddr.IP()
+		default:
+			return
+		}
+		if !matchIPs(ips, srcIP, dstIP) {
+			return
+		}
+		if err := dst.Update(key, value); err != nil {
+			if updateErr == nil {
+				updateErr = err
+			}
+			return
+		}
+		copied++
+	}
+	if err := src.DumpReliablyWithCallback(cb, bpf.NewDumpStats(&src.Map)); err != nil {
+		return copied, err
+	}
+	return copied, updateErr
+}
+
+// MigrateEntries copies the conntrack entries for which the source or
+// destination IP matches one of the given IPs between the global maps and
+// the local maps of endpoint 'e'. If toLocal is true, the entries are copied
+// from the global maps into the local maps, otherwise from the local maps
+// into the global maps. Missing destination maps are created.
+//
+// The source maps are left untouched, it is up to the caller to remove the
+// migrated entries from them once the datapath uses the destination maps.
+// Returns the number of migrated entries.
+func MigrateEntries(e CtEndpoint, ips map[string]struct{}, toLocal, ipv4, ipv6 bool) (int, error) {
+	src, dst := maps(nil, ipv4, ipv6), maps(e, ipv4, ipv6)
+	if !toLocal {
+		src, dst = dst, src
+	}
+
+	var (
+		migrated int
+		firstErr error
+	)
+	for i := range src {
+		n, err := copyEntries(src[i], dst[i], ips)
+		migrated += n
+		if err != nil && firstErr == nil {
+			path, _ := src[i].Path()
+			firstErr = fmt.Errorf("unable to migrate entries of CT map %s: %s", path, err)
+		}
+	}
+	return migrated, firstErr
 }
 
 // DeleteIfUpgradeNeeded attempts to open the conntrack maps associated with
@@ -387,6 +655,9 @@ func DeleteIfUpgradeNeeded(e CtEndpoint) {
 			continue
 		}
 		scopedLog := log.WithField(logfields.Path, path)
+		if err := newMap.UpgradeSchema(); err != nil {
+			scopedLog.WithError(err).Warning("Unable to upgrade schema of CT map")
+		}
 		oldMap, err := bpf.OpenMap(path)
 		if err != nil {
 			scopedLog.WithError(err).Debug("Couldn't open CT map for upgrade")
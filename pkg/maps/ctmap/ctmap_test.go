@@ -15,11 +15,15 @@
 package ctmap
 
 import (
+	"net"
 	"strings"
 	"testing"
 	"unsafe"
 
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/byteorder"
 	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/u8proto"
 
 	. "gopkg.in/check.v1"
 )
@@ -62,3 +66,52 @@ func (t *CTMapTestSuite) TestInit(c *C) {
 		}
 	}
 }
+
+func (t *CTMapTestSuite) TestTupleFilter(c *C) {
+	src, dst := net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")
+
+	c.Assert((&TupleFilter{}).Matches(src, dst, 12345, 80, u8proto.TCP), Equals, true)
+
+	tests := []struct {
+		filter  TupleFilter
+		matches bool
+	}{
+		{TupleFilter{SrcIP: src}, true},
+		{TupleFilter{SrcIP: dst}, false},
+		{TupleFilter{DstIP: dst}, true},
+		{TupleFilter{SrcIP: src, DstIP: src}, false},
+		{TupleFilter{Port: 80}, true},
+		{TupleFilter{Port: 12345}, true},
+		{TupleFilter{Port: 443}, false},
+		{TupleFilter{Proto: u8proto.TCP}, true},
+		{TupleFilter{Proto: u8proto.UDP}, false},
+		{TupleFilter{MatchIPs: map[string]struct{}{"10.0.0.2": {}}}, true},
+		{TupleFilter{MatchIPs: map[string]struct{}{"10.0.0.3": {}}}, false},
+	}
+	for _, tt := range tests {
+		c.Assert(tt.filter.Matches(src, dst, 12345, 80, u8proto.TCP), Equals, tt.matches,
+			Commentf("filter %+v", tt.filter))
+	}
+
+	// The source address of the conntrack key is the packet's destination
+	key := &CtKey4Global{CtKey4{
+		DestAddr:   types.IPv4{10, 0, 0, 1},
+		SourceAddr: types.IPv4{10, 0, 0, 2},
+		DestPort:   byteorder.HostToNetwork(uint16(12345)).(uint16),
+		SourcePort: byteorder.HostToNetwork(uint16(80)).(uint16),
+		NextHeader: u8proto.TCP,
+	}}
+	c.Assert((&TupleFilter{SrcIP: src, DstIP: dst, Port: 80, Proto: u8proto.TCP}).matchesKey(key), Equals, true)
+	c.Assert((&TupleFilter{SrcIP: dst}).matchesKey(key), Equals, false)
+	c.Assert((&TupleFilter{Port: 8080}).matchesKey(key), Equals, false)
+}
+
+func (t *CTMapTestSuite) TestGCResult(c *C) {
+	r := GCResult{Scanned: 900, Deleted: 300, MaxEntries: 1000}
+	c.Assert(r.DeleteRatio(), Equals, 0.3)
+	c.Assert(r.FillRatio(), Equals, 0.6)
+
+	r = GCResult{}
+	c.Assert(r.DeleteRatio(), Equals, 0.0)
+	c.Assert(r.FillRatio(), Equals, 0.0)
+}
@@ -27,6 +27,9 @@ import (
 type gcStats struct {
 	*bpf.DumpStats
 
+	// scanned is the number of entries visited
+	scanned uint32
+
 	// aliveEntries is the number of scanned entries that are still alive.
 	aliveEntries uint32
 
@@ -124,6 +127,8 @@ func (s *gcStats) finish() {
 	metrics.ConntrackGCRuns.WithLabelValues(family, proto, status).Inc()
 	metrics.ConntrackGCDuration.WithLabelValues(family, proto, status).Observe(duration.Seconds())
 	metrics.ConntrackGCKeyFallbacks.WithLabelValues(family, proto).Add(float64(s.KeyFallback))
+	metrics.ConntrackGCScannedEntries.WithLabelValues(family, proto).Add(float64(s.scanned))
+	metrics.ConntrackGCDeletedEntries.WithLabelValues(family, proto).Add(float64(s.deleted))
 
 	log.WithFields(logrus.Fields{
 		logfields.StartTime: s.Started,
@@ -160,19 +160,21 @@ func NewMap(name string) *Map {
 			int(unsafe.Sizeof(RemoteEndpointInfo{})),
 			MaxEntries,
 			bpf.BPF_F_NO_PREALLOC,
-			func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
-				k, v := Key{}, RemoteEndpointInfo{}
-
-				if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
-					return nil, nil, err
-				}
-				return &k, &v, nil
-			},
+			dumpParser,
 		).WithCache(),
 		deleteSupport: true,
 	}
 }
 
+func dumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := Key{}, RemoteEndpointInfo{}
+
+	if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+		return nil, nil, err
+	}
+	return &k, &v, nil
+}
+
 // delete removes a key from the ipcache BPF map, and returns whether the
 // kernel supports the delete operation (true) or not (false), and any error
 // that may have occurred while attempting to delete the entry.
@@ -248,6 +250,9 @@ var (
 )
 
 func init() {
+	bpf.RegisterMapCodec(Name, int(unsafe.Sizeof(Key{})),
+		int(unsafe.Sizeof(RemoteEndpointInfo{})), dumpParser)
+
 	err := bpf.OpenAfterMount(&IPCache.Map)
 	if err != nil {
 		log.WithError(err).Error("unable to open map")
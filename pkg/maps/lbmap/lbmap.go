@@ -24,6 +24,7 @@ import (
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/option"
 
 	"github.com/sirupsen/logrus"
 )
@@ -37,14 +38,15 @@ var (
 )
 
 const (
-	// Maximum number of entries in each hashtable
-	MaxEntries   = 65536
 	maxFrontEnds = 256
 	// MaxSeq is used by daemon for generating bpf define LB_RR_MAX_SEQ.
 	MaxSeq = 31
 )
 
 var (
+	// MaxEntries is the maximum number of entries in each hashtable
+	MaxEntries = option.LBMapEntriesDefault
+
 	// cache contains *all* services of both IPv4 and IPv6 based maps
 	// combined
 	cache = newLBMapCache()
@@ -131,6 +133,16 @@ func (s *RRSeqValue) String() string {
 	return fmt.Sprintf("count=%d idx=%v", s.Count, s.Idx)
 }
 
+// SetMaxEntries sets the maximum number of entries in each service and
+// reverse NAT map. It must be called before the maps are opened, existing
+// maps of a different size are migrated to the new size.
+func SetMaxEntries(maxEntries int) {
+	MaxEntries = maxEntries
+	for _, m := range []*bpf.Map{Service4Map, RevNat4Map, Service6Map, RevNat6Map} {
+		m.MaxEntries = uint32(maxEntries)
+	}
+}
+
 func updateService(key ServiceKey, value ServiceValue) error {
 	log.WithFields(logrus.Fields{
 		"frontend": key,
@@ -437,6 +449,39 @@ func l3n4Addr2ServiceKey(l3n4Addr loadbalancer.L3n4AddrID) ServiceKey {
 	return NewService4Key(l3n4Addr.IP, l3n4Addr.Port, 0)
 }
 
+// backendWeights returns the weights of the backends in the BPF maps. The
+// datapath only selects backends for new connections from the weighted round
+// robin sequence, established connections keep their backend. If any backend
+// is draining, it is therefore given a weight of 0 while all active backends
+// are given a weight of at least 1, so that the sequence is used and only
+// contains active backends. If all backends are draining, new connections
+// are balanced across all of them.
+func backendWeights(bes []loadbalancer.LBBackEnd) []uint16 {
+	weights := make([]uint16, len(bes))
+	draining, active := 0, 0
+	for i, be := range bes {
+		weights[i] = be.Weight
+		if be.Draining {
+			draining++
+		} else {
+			active++
+		}
+	}
+	if draining == 0 || active == 0 {
+		return weights
+	}
+
+	for i, be := range bes {
+		switch {
+		case be.Draining:
+			weights[i] = 0
+		case weights[i] == 0:
+			weights[i] = 1
+		}
+	}
+	return weights
+}
+
 // LBSVC2ServiceKeynValue transforms the SVC Cilium type into a bpf SVC type.
 func LBSVC2ServiceKeynValue(svc loadbalancer.LBSVC) (ServiceKey, []ServiceValue, error) {
 	log.WithFields(logrus.Fields{
@@ -448,14 +493,15 @@ func LBSVC2ServiceKeynValue(svc loadbalancer.LBSVC) (ServiceKey, []ServiceValue,
 	// Create a list of ServiceValues so we know everything is safe to put in the lb
 	// map
 	besValues := []ServiceValue{}
-	for _, be := range svc.BES {
+	weights := backendWeights(svc.BES)
+	for i, be := range svc.BES {
 		beValue := fe.NewValue().(ServiceValue)
 		if err := beValue.SetAddress(be.IP); err != nil {
 			return nil, nil, err
 		}
 		beValue.SetPort(be.Port)
 		beValue.SetRevNat(int(svc.FE.ID))
-		beValue.SetWeight(be.Weight)
+		beValue.SetWeight(weights[i])
 
 		besValues = append(besValues, beValue)
 		log.WithFields(logrus.Fields{
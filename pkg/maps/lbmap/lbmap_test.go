@@ -0,0 +1,51 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lbmap
+
+import (
+	"github.com/cilium/cilium/pkg/loadbalancer"
+
+	. "gopkg.in/check.v1"
+)
+
+func (b *LBMapTestSuite) TestBackendWeights(c *C) {
+	backends := func(weights []uint16, draining ...int) []loadbalancer.LBBackEnd {
+		bes := make([]loadbalancer.LBBackEnd, len(weights))
+		for i, w := range weights {
+			bes[i].Weight = w
+		}
+		for _, i := range draining {
+			bes[i].Draining = true
+		}
+		return bes
+	}
+
+	// Without draining backends, weights are unchanged
+	c.Assert(backendWeights(backends([]uint16{0, 0, 0})), DeepEquals, []uint16{0, 0, 0})
+	c.Assert(backendWeights(backends([]uint16{3, 0, 1})), DeepEquals, []uint16{3, 0, 1})
+
+	// Draining backends are removed from the sequence, active backends
+	// are part of it
+	c.Assert(backendWeights(backends([]uint16{0, 0, 0}, 1)), DeepEquals, []uint16{1, 0, 1})
+	c.Assert(backendWeights(backends([]uint16{3, 2, 0}, 0)), DeepEquals, []uint16{0, 2, 1})
+
+	// If all backends are draining, weights are unchanged
+	c.Assert(backendWeights(backends([]uint16{2, 0}, 0, 1)), DeepEquals, []uint16{2, 0})
+
+	seq, err := generateWrrSeq(backendWeights(backends([]uint16{0, 0, 0}, 1)))
+	c.Assert(err, IsNil)
+	c.Assert(seq.Count, Equals, uint16(2))
+	c.Assert(seq.Idx[:seq.Count], DeepEquals, []uint16{0, 2})
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/cilium/cilium/pkg/bpf"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/option"
 )
 
 var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "map-lxc")
@@ -29,14 +30,14 @@ var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "map-lxc")
 const (
 	MapName = "cilium_lxc"
 
-	// MaxEntries represents the maximum number of endpoints in the map
-	MaxEntries = 65535
-
 	// PortMapMax represents the maximum number of Ports Mapping per container.
 	PortMapMax = 16
 )
 
 var (
+	// MaxEntries represents the maximum number of endpoints in the map
+	MaxEntries = option.LXCMapEntriesDefault
+
 	// LXCMap represents the BPF map for endpoints
 	LXCMap = bpf.NewMap(MapName,
 		bpf.MapTypeHash,
@@ -44,19 +45,31 @@ var (
 		int(unsafe.Sizeof(EndpointInfo{})),
 		MaxEntries,
 		0,
-		func(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
-			k, v := EndpointKey{}, EndpointInfo{}
-
-			if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
-				return nil, nil, err
-			}
-
-			return &k, &v, nil
-		},
+		dumpParser,
 	).WithCache()
 )
 
+// SetMaxEntries sets the maximum number of endpoints in the map. It must be
+// called before the map is opened, an existing map of a different size is
+// migrated to the new size.
+func SetMaxEntries(maxEntries int) {
+	MaxEntries = maxEntries
+	LXCMap.MaxEntries = uint32(maxEntries)
+}
+
+func dumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := EndpointKey{}, EndpointInfo{}
+
+	if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+		return nil, nil, err
+	}
+
+	return &k, &v, nil
+}
+
 func init() {
+	bpf.RegisterMapCodec(MapName, int(unsafe.Sizeof(EndpointKey{})),
+		int(unsafe.Sizeof(EndpointInfo{})), dumpParser)
 	bpf.OpenAfterMount(LXCMap)
 }
 
@@ -136,66 +136,95 @@ func (v *Value) GetValuePtr() unsafe.Pointer {
 	return unsafe.Pointer(v)
 }
 
+// addDelta increases counter to value. The datapath counters only ever
+// increase, the counter is left untouched if value is not larger.
+func addDelta(counter prometheus.Counter, value float64) {
+	if oldValue := metrics.GetCounterValue(counter); value > oldValue {
+		counter.Add(value - oldValue)
+	}
+}
+
 // updatePrometheusMetrics checks the metricsmap key value pair
 // and determines which prometheus metrics along with respective labels
 // need to be updated.
 func updatePrometheusMetrics(key *Key, val *Value) {
-	var counter prometheus.Counter
+	var count, bytes prometheus.Counter
 	var err error
 	if key.IsDrop() {
-		counter, err = metrics.DropCount.GetMetricWithLabelValues(key.DropForwardReason(), key.Direction())
+		count, err = metrics.DropCount.GetMetricWithLabelValues(key.DropForwardReason(), key.Direction())
+		if err == nil {
+			bytes, err = metrics.DropBytes.GetMetricWithLabelValues(key.DropForwardReason(), key.Direction())
+		}
 	} else {
-		counter, err = metrics.ForwardCount.GetMetricWithLabelValues(key.Direction())
+		count, err = metrics.ForwardCount.GetMetricWithLabelValues(key.Direction())
+		if err == nil {
+			bytes, err = metrics.ForwardBytes.GetMetricWithLabelValues(key.Direction())
+		}
 	}
 	if err != nil {
 		log.WithError(err).Warn("Failed to update prometheus metrics")
 		return
 	}
-	oldValue := metrics.GetCounterValue(counter)
-	newValue := val.CountFloat()
-	// Check if metrics have changed since the last poll.
-	// If yes, we need to add only the delta.
-	if newValue > oldValue {
-		if key.IsDrop() {
-			metrics.DropCount.WithLabelValues(key.DropForwardReason(), key.Direction()).Add((newValue - oldValue))
-		} else {
-			metrics.ForwardCount.WithLabelValues(key.Direction()).Add((newValue - oldValue))
-		}
+
+	addDelta(count, val.CountFloat())
+	addDelta(bytes, float64(val.Bytes))
+}
+
+// sumValues returns the sum of the per-CPU values
+func sumValues(values []Value) Value {
+	var sum Value
+	for _, v := range values {
+		sum.Count += v.Count
+		sum.Bytes += v.Bytes
 	}
+	return sum
 }
 
-// SyncMetricsMap is called periodically to sync off the metrics map by
-// aggregating it into drops (by drop reason and direction) and
-// forwards (by direction) with the prometheus server.
-func SyncMetricsMap() error {
-	entry := make([]Value, possibleCpus)
-	file := bpf.MapPath(MapName)
-	metricsmap, err := bpf.OpenMap(file)
+// DumpTotals returns the packet and byte counts of all entries of the
+// metrics map, summed up over all CPUs.
+func DumpTotals() (map[Key]Value, error) {
+	if possibleCpus == 0 {
+		return nil, fmt.Errorf("unable to determine the number of possible CPUs")
+	}
 
+	metricsmap, err := bpf.OpenMap(bpf.MapPath(MapName))
 	if err != nil {
-		return fmt.Errorf("unable to open metrics map: %s", err)
+		return nil, fmt.Errorf("unable to open metrics map: %s", err)
 	}
 	defer metricsmap.Close()
 
-	var key, nextKey Key
-	for {
-		err := bpf.GetNextKey(metricsmap.GetFd(), unsafe.Pointer(&key), unsafe.Pointer(&nextKey))
-		if err != nil {
-			break
-		}
-		err = bpf.LookupElement(metricsmap.GetFd(), unsafe.Pointer(&nextKey), unsafe.Pointer(&entry[0]))
-		if err != nil {
+	// The lookup of an element of a per-CPU map returns the values of all
+	// possible CPUs.
+	totals := map[Key]Value{}
+	values := make([]Value, possibleCpus)
+	valueSize := uint32(unsafe.Sizeof(Value{})) * uint32(possibleCpus)
+	err = bpf.IterateKeys(metricsmap.GetFd(), uint32(unsafe.Sizeof(Key{})), valueSize, MaxEntries, func(k []byte) error {
+		key := (*Key)(unsafe.Pointer(&k[0]))
+		if err := bpf.LookupElement(metricsmap.GetFd(), unsafe.Pointer(key), unsafe.Pointer(&values[0])); err != nil {
 			return fmt.Errorf("unable to lookup metrics map: %s", err)
 		}
+		totals[*key] = sumValues(values)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		// cannot use `range entry` since, if the first value for a particular
-		// CPU is zero, it never iterates over the next non-zero value.
-		for i := 0; i < possibleCpus; i++ {
-			// Increment Prometheus metrics here.
-			updatePrometheusMetrics(&nextKey, &entry[i])
-		}
-		key = nextKey
+	return totals, nil
+}
+
+// SyncMetricsMap is called periodically to sync off the metrics map by
+// aggregating it into drops (by drop reason and direction) and
+// forwards (by direction) with the prometheus server.
+func SyncMetricsMap() error {
+	totals, err := DumpTotals()
+	if err != nil {
+		return err
+	}
 
+	for key, value := range totals {
+		key, value := key, value
+		updatePrometheusMetrics(&key, &value)
 	}
 	return nil
 }
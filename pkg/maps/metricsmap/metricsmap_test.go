@@ -0,0 +1,57 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsmap
+
+import (
+	"testing"
+
+	"github.com/cilium/cilium/pkg/metrics"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type MetricsMapSuite struct{}
+
+var _ = Suite(&MetricsMapSuite{})
+
+func (s *MetricsMapSuite) TestSumValues(c *C) {
+	c.Assert(sumValues(nil), Equals, Value{})
+	c.Assert(sumValues([]Value{{Count: 1, Bytes: 100}, {}, {Count: 2, Bytes: 50}}), Equals, Value{Count: 3, Bytes: 150})
+}
+
+func (s *MetricsMapSuite) TestUpdatePrometheusMetrics(c *C) {
+	key := &Key{Reason: 132, Dir: dirIngress}
+	count := metrics.DropCount.WithLabelValues(key.DropForwardReason(), key.Direction())
+	bytes := metrics.DropBytes.WithLabelValues(key.DropForwardReason(), key.Direction())
+
+	updatePrometheusMetrics(key, &Value{Count: 3, Bytes: 300})
+	c.Assert(metrics.GetCounterValue(count), Equals, float64(3))
+	c.Assert(metrics.GetCounterValue(bytes), Equals, float64(300))
+
+	// Only the increase since the last sync is added
+	updatePrometheusMetrics(key, &Value{Count: 5, Bytes: 400})
+	c.Assert(metrics.GetCounterValue(count), Equals, float64(5))
+	c.Assert(metrics.GetCounterValue(bytes), Equals, float64(400))
+
+	key = &Key{Dir: dirEgress}
+	updatePrometheusMetrics(key, &Value{Count: 7, Bytes: 700})
+	c.Assert(metrics.GetCounterValue(metrics.ForwardCount.WithLabelValues("EGRESS")), Equals, float64(7))
+	c.Assert(metrics.GetCounterValue(metrics.ForwardBytes.WithLabelValues("EGRESS")), Equals, float64(700))
+}
@@ -0,0 +1,239 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package natmap
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "map-nat")
+
+const (
+	// MapName is the name of the map translating masqueraded IPv4
+	// connections
+	MapName = "cilium_snat_v4_external"
+
+	// MaxEntries is the maximum number of entries in the NAT map. Each
+	// masqueraded connection takes two entries.
+	MaxEntries = 131072
+
+	// FragMaxEntries is the maximum number of fragmented datagrams
+	// tracked to translate their fragments without L4 header.
+	FragMaxEntries = 8192
+
+	// MinPort and MaxPort bound the ports of the external IP allocated to
+	// masqueraded connections. The original source port is used if it is
+	// within the range and not in use. The range must not overlap the
+	// ephemeral ports of the node, replies to connections of the node
+	// would otherwise be translated.
+	MinPort = 61000
+	MaxPort = 65535
+
+	// localPortRangePath is the sysctl holding the ephemeral port range
+	// of the node
+	localPortRangePath = "/proc/sys/net/ipv4/ip_local_port_range"
+)
+
+// Map represents the NAT map
+type Map struct {
+	bpf.Map
+}
+
+// NewMap returns a new NAT map with the given name and size
+func NewMap(name string, maxEntries int) *Map {
+	return &Map{
+		Map: *bpf.NewMap(name,
+			bpf.GetLRUMapType(),
+			int(unsafe.Sizeof(NatKey4{})),
+			int(unsafe.Sizeof(NatEntry4{})),
+			maxEntries,
+			0,
+			natDumpParser,
+		),
+	}
+}
+
+// GlobalMap returns the NAT map of the node
+func GlobalMap() *Map {
+	return NewMap(MapName, MaxEntries)
+}
+
+// WriteBPFMacros writes the defines of the NAT map shared by all BPF
+// programs of the node to fw.
+func WriteBPFMacros(fw io.Writer) {
+	fmt.Fprintf(fw, "#define SNAT_MAPPING_IPV4_SIZE %d\n", MaxEntries)
+	fmt.Fprintf(fw, "#define SNAT_FRAG_MAPPING_IPV4_SIZE %d\n", FragMaxEntries)
+	fmt.Fprintf(fw, "#define SNAT_MIN_PORT %d\n", MinPort)
+	fmt.Fprintf(fw, "#define SNAT_MAX_PORT %d\n", MaxPort)
+}
+
+// parsePortRange parses the content of ip_local_port_range
+func parsePortRange(content string) (min, max int, err error) {
+	fields := strings.Fields(content)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("invalid port range %q", content)
+	}
+	if min, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %q: %s", content, err)
+	}
+	if max, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %q: %s", content, err)
+	}
+	return min, max, nil
+}
+
+// checkPortRange returns an error if the ephemeral port range in content
+// overlaps the ports allocated to masqueraded connections.
+func checkPortRange(content string) error {
+	min, max, err := parsePortRange(content)
+	if err != nil {
+		return err
+	}
+	if min <= MaxPort && max >= MinPort {
+		return fmt.Errorf("ephemeral port range %d-%d overlaps masquerading port range %d-%d",
+			min, max, MinPort, MaxPort)
+	}
+	return nil
+}
+
+// CheckLocalPortRange returns an error if the ephemeral port range of the
+// node overlaps the ports allocated to masqueraded connections.
+func CheckLocalPortRange() error {
+	content, err := ioutil.ReadFile(localPortRangePath)
+	if err != nil {
+		return err
+	}
+	return checkPortRange(string(content))
+}
+
+func init() {
+	bpf.RegisterMapCodec(MapName, int(unsafe.Sizeof(NatKey4{})),
+		int(unsafe.Sizeof(NatEntry4{})), natDumpParser)
+}
+
+func natDumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := NatKey4{}, NatEntry4{}
+
+	if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+		return nil, nil, err
+	}
+	return &k, &v, nil
+}
+
+// entries returns all entries of the map
+func (m *Map) entries() (map[NatKey4]NatEntry4, error) {
+	entries := map[NatKey4]NatEntry4{}
+	cb := func(k bpf.MapKey, v bpf.MapValue) {
+		entries[*k.(*NatKey4)] = *v.(*NatEntry4)
+	}
+	if err := m.DumpReliablyWithCallback(cb, bpf.NewDumpStats(&m.Map)); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// WriteEntries writes the entries of the map to w, one per line and sorted
+// so that both directions of a connection are next to each other.
+func (m *Map) WriteEntries(w io.Writer) error {
+	entries, err := m.entries()
+	if err != nil {
+		return err
+	}
+
+	lines := make([]string, 0, len(entries))
+	for k, e := range entries {
+		lines = append(lines, fmt.Sprintf("%s %s\n", &k, &e))
+	}
+	sort.Strings(lines)
+
+	var buffer bytes.Buffer
+	for _, line := range lines {
+		buffer.WriteString(line)
+	}
+	_, err = buffer.WriteTo(w)
+	return err
+}
+
+// DumpEntries returns the entries of the map as a string
+func (m *Map) DumpEntries() (string, error) {
+	var buffer bytes.Buffer
+	err := m.WriteEntries(&buffer)
+	return buffer.String(), err
+}
+
+// staleKeys returns the keys of entries which have expired at time now. Both
+// entries of a connection are kept as long as one of them has not expired
+// as the datapath only refreshes the lifetime of the direction a packet is
+// seen in.
+func staleKeys(entries map[NatKey4]NatEntry4, now uint32) []NatKey4 {
+	var stale []NatKey4
+	for k, e := range entries {
+		if e.Lifetime >= now {
+			continue
+		}
+		rkey := k.reverse(&e)
+		if r, ok := entries[rkey]; ok && r.Lifetime >= now {
+			continue
+		}
+		stale = append(stale, k)
+	}
+	return stale
+}
+
+// gc deletes all entries which have expired at time now. Returns the number
+// of scanned and deleted entries.
+func (m *Map) gc(now uint32) (int, int, error) {
+	entries, err := m.entries()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	deleted := 0
+	for _, k := range staleKeys(entries, now) {
+		key := k
+		if err := m.Delete(&key); err != nil {
+			log.WithError(err).WithField(logfields.BPFMapKey, key.String()).Debug("Unable to delete NAT entry")
+			continue
+		}
+		deleted++
+	}
+	return len(entries), deleted, nil
+}
+
+// GC deletes the expired entries of connections which are no longer in use.
+// Returns the number of scanned and deleted entries.
+func (m *Map) GC() (int, int, error) {
+	t, _ := bpf.GetMtime()
+	return m.gc(uint32(t / 1000000000))
+}
+
+// Flush deletes all entries of the map. Returns the number of deleted
+// entries.
+func (m *Map) Flush() (int, error) {
+	_, deleted, err := m.gc(math.MaxUint32)
+	return deleted, err
+}
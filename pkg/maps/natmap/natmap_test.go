@@ -0,0 +1,139 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package natmap
+
+import (
+	"net"
+	"sort"
+	"testing"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+type NATMapTestSuite struct{}
+
+var _ = Suite(&NATMapTestSuite{})
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+func ipv4(s string) (ip types.IPv4) {
+	copy(ip[:], net.ParseIP(s).To4())
+	return ip
+}
+
+func port(p uint16) uint16 {
+	return byteorder.HostToNetwork(p).(uint16)
+}
+
+// flow returns the egress and ingress entries of a connection from
+// 10.0.0.1:40000 to 1.1.1.1:53 masqueraded as 192.168.1.1:extPort
+func flow(extPort uint16, egressLifetime, ingressLifetime uint32) map[NatKey4]NatEntry4 {
+	egress := NatKey4{
+		SourceAddr: ipv4("10.0.0.1"),
+		DestAddr:   ipv4("1.1.1.1"),
+		SourcePort: port(40000),
+		DestPort:   port(53),
+		NextHeader: u8proto.UDP,
+		Dir:        DirEgress,
+	}
+	ingress := NatKey4{
+		SourceAddr: ipv4("1.1.1.1"),
+		DestAddr:   ipv4("192.168.1.1"),
+		SourcePort: port(53),
+		DestPort:   port(extPort),
+		NextHeader: u8proto.UDP,
+		Dir:        DirIngress,
+	}
+	return map[NatKey4]NatEntry4{
+		egress: {
+			Lifetime: egressLifetime,
+			Addr:     ipv4("192.168.1.1"),
+			Port:     port(extPort),
+		},
+		ingress: {
+			Lifetime: ingressLifetime,
+			Addr:     ipv4("10.0.0.1"),
+			Port:     port(40000),
+		},
+	}
+}
+
+func (s *NATMapTestSuite) TestSizes(c *C) {
+	// Must match the structs in bpf/lib/nat.h
+	c.Assert(int(unsafe.Sizeof(NatKey4{})), Equals, 16)
+	c.Assert(int(unsafe.Sizeof(NatEntry4{})), Equals, 24)
+}
+
+func (s *NATMapTestSuite) TestReverse(c *C) {
+	entries := flow(1234, 0, 0)
+	for k, e := range entries {
+		rkey := k.reverse(&e)
+		r, ok := entries[rkey]
+		c.Assert(ok, Equals, true)
+		c.Assert(rkey.reverse(&r), Equals, k)
+	}
+}
+
+func (s *NATMapTestSuite) TestString(c *C) {
+	var lines []string
+	for k, e := range flow(1234, 100, 200) {
+		lines = append(lines, k.String()+" "+e.String())
+	}
+	sort.Strings(lines)
+	c.Assert(lines, DeepEquals, []string{
+		"UDP IN 1.1.1.1:53 -> 192.168.1.1:1234 XLATE 10.0.0.1:40000 created=0 expires=200",
+		"UDP OUT 10.0.0.1:40000 -> 1.1.1.1:53 XLATE 192.168.1.1:1234 created=0 expires=100",
+	})
+}
+
+func (s *NATMapTestSuite) TestStaleKeys(c *C) {
+	// Both directions alive
+	c.Assert(staleKeys(flow(1234, 100, 100), 50), HasLen, 0)
+
+	// One direction expired, the connection is still in use
+	c.Assert(staleKeys(flow(1234, 10, 100), 50), HasLen, 0)
+	c.Assert(staleKeys(flow(1234, 100, 10), 50), HasLen, 0)
+
+	// Both directions expired
+	c.Assert(staleKeys(flow(1234, 10, 20), 50), HasLen, 2)
+
+	// An expired entry without its counterpart is removed
+	entries := flow(1234, 10, 100)
+	for k := range entries {
+		if k.Dir == DirIngress {
+			delete(entries, k)
+		}
+	}
+	stale := staleKeys(entries, 50)
+	c.Assert(stale, HasLen, 1)
+	c.Assert(stale[0].Dir, Equals, uint8(DirEgress))
+}
+
+func (s *NATMapTestSuite) TestCheckPortRange(c *C) {
+	c.Assert(checkPortRange("32768\t60999\n"), IsNil)
+	c.Assert(checkPortRange("1024 4999"), IsNil)
+	c.Assert(checkPortRange("32768\t61000\n"), Not(IsNil))
+	c.Assert(checkPortRange("1024 65535"), Not(IsNil))
+	c.Assert(checkPortRange("32768"), Not(IsNil))
+	c.Assert(checkPortRange("a b"), Not(IsNil))
+}
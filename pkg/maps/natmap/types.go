@@ -0,0 +1,107 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package natmap
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+const (
+	// DirEgress marks entries translating packets leaving the node
+	DirEgress = 0
+
+	// DirIngress marks entries translating replies entering the node
+	DirIngress = 1
+)
+
+// NatKey4 is the key of the IPv4 NAT map. It must match struct ipv4_nat_key
+// in bpf/lib/nat.h. Ports are in network byte order.
+type NatKey4 struct {
+	SourceAddr types.IPv4
+	DestAddr   types.IPv4
+	SourcePort uint16
+	DestPort   uint16
+	NextHeader u8proto.U8proto
+	Dir        uint8
+	Pad        uint16
+}
+
+// GetKeyPtr returns the unsafe.Pointer for k.
+func (k *NatKey4) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+
+// NewValue creates a new bpf.MapValue.
+func (k *NatKey4) NewValue() bpf.MapValue { return &NatEntry4{} }
+
+// String returns the readable format of the key
+func (k *NatKey4) String() string {
+	dir := "OUT"
+	if k.Dir == DirIngress {
+		dir = "IN"
+	}
+	return fmt.Sprintf("%s %s %s:%d -> %s:%d",
+		k.NextHeader, dir,
+		k.SourceAddr.IP(), byteorder.NetworkToHost(k.SourcePort),
+		k.DestAddr.IP(), byteorder.NetworkToHost(k.DestPort))
+}
+
+// reverse returns the key of the entry translating packets of the same flow
+// in the opposite direction as k, given the entry e of k.
+func (k *NatKey4) reverse(e *NatEntry4) NatKey4 {
+	if k.Dir == DirEgress {
+		return NatKey4{
+			SourceAddr: k.DestAddr,
+			DestAddr:   e.Addr,
+			SourcePort: k.DestPort,
+			DestPort:   e.Port,
+			NextHeader: k.NextHeader,
+			Dir:        DirIngress,
+		}
+	}
+	return NatKey4{
+		SourceAddr: e.Addr,
+		DestAddr:   k.SourceAddr,
+		SourcePort: e.Port,
+		DestPort:   k.SourcePort,
+		NextHeader: k.NextHeader,
+		Dir:        DirEgress,
+	}
+}
+
+// NatEntry4 is the value of the IPv4 NAT map. It must match struct
+// ipv4_nat_entry in bpf/lib/nat.h. Addr and Port are the address and port
+// (in network byte order) the packet is translated to.
+type NatEntry4 struct {
+	Created  uint64
+	Lifetime uint32
+	Addr     types.IPv4
+	Port     uint16
+	Pad1     uint16
+	Pad2     uint32
+}
+
+// GetValuePtr returns the unsafe.Pointer for e.
+func (e *NatEntry4) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(e) }
+
+// String returns the readable format of the entry
+func (e *NatEntry4) String() string {
+	return fmt.Sprintf("XLATE %s:%d created=%d expires=%d",
+		e.Addr.IP(), byteorder.NetworkToHost(e.Port), e.Created, e.Lifetime)
+}
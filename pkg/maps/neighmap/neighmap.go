@@ -0,0 +1,112 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package neighmap
+
+import (
+	"fmt"
+	"net"
+	"unsafe"
+
+	"github.com/cilium/cilium/common/types"
+	"github.com/cilium/cilium/pkg/bpf"
+)
+
+const (
+	// MapName is the name of the map of next hops towards other nodes
+	MapName = "cilium_node_neigh4"
+
+	// MaxEntries is the maximum number of nodes in the map
+	MaxEntries = 16384
+)
+
+// Key is the IPv4 address of a node
+type Key struct {
+	IP types.IPv4
+}
+
+// NewKey returns the key of the node with the given IPv4 address
+func NewKey(ip net.IP) Key {
+	var k Key
+	copy(k.IP[:], ip.To4())
+	return k
+}
+
+// GetKeyPtr returns the unsafe.Pointer for k.
+func (k *Key) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(k) }
+
+// NewValue creates a new bpf.MapValue.
+func (k *Key) NewValue() bpf.MapValue { return &Entry{} }
+
+func (k *Key) String() string { return k.IP.String() }
+
+// Entry is the MAC address of the next hop towards a node on the native
+// device. It must match struct node_neigh_entry in bpf/lib/maps.h.
+type Entry struct {
+	MAC [6]byte
+	Pad uint16
+}
+
+// NewEntry returns the entry for the given MAC address
+func NewEntry(mac net.HardwareAddr) Entry {
+	var e Entry
+	copy(e.MAC[:], mac)
+	return e
+}
+
+// GetValuePtr returns the unsafe.Pointer for e.
+func (e *Entry) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(e) }
+
+func (e *Entry) String() string { return net.HardwareAddr(e.MAC[:]).String() }
+
+// NeighMap represents the BPF map of next hops towards other nodes
+var NeighMap = bpf.NewMap(MapName,
+	bpf.MapTypeHash,
+	int(unsafe.Sizeof(Key{})),
+	int(unsafe.Sizeof(Entry{})),
+	MaxEntries,
+	0,
+	dumpParser,
+).WithCache()
+
+func dumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := Key{}, Entry{}
+
+	if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+		return nil, nil, err
+	}
+
+	return &k, &v, nil
+}
+
+func init() {
+	bpf.RegisterMapCodec(MapName, int(unsafe.Sizeof(Key{})),
+		int(unsafe.Sizeof(Entry{})), dumpParser)
+}
+
+// Update sets the MAC address of the next hop towards the node with the
+// given IPv4 address.
+func Update(ip net.IP, mac net.HardwareAddr) error {
+	if ip.To4() == nil || len(mac) != 6 {
+		return fmt.Errorf("invalid neighbor %s %s", ip, mac)
+	}
+	key, entry := NewKey(ip), NewEntry(mac)
+	return NeighMap.Update(&key, &entry)
+}
+
+// Delete removes the next hop towards the node with the given IPv4 address
+func Delete(ip net.IP) error {
+	key := NewKey(ip)
+	return NeighMap.Delete(&key)
+}
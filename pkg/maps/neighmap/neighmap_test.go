@@ -0,0 +1,49 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package neighmap
+
+import (
+	"net"
+	"testing"
+	"unsafe"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+type NeighMapTestSuite struct{}
+
+var _ = Suite(&NeighMapTestSuite{})
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+func (s *NeighMapTestSuite) TestEntries(c *C) {
+	// Must match the key and struct node_neigh_entry in bpf/lib/maps.h
+	c.Assert(int(unsafe.Sizeof(Key{})), Equals, 4)
+	c.Assert(int(unsafe.Sizeof(Entry{})), Equals, 8)
+
+	key := NewKey(net.ParseIP("192.168.1.2"))
+	c.Assert(key.String(), Equals, "192.168.1.2")
+
+	mac, err := net.ParseMAC("02:42:ac:11:00:02")
+	c.Assert(err, IsNil)
+	entry := NewEntry(mac)
+	c.Assert(entry.String(), Equals, "02:42:ac:11:00:02")
+
+	c.Assert(Update(net.ParseIP("f00d::1"), mac), Not(IsNil))
+	c.Assert(Update(net.ParseIP("192.168.1.2"), nil), Not(IsNil))
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/u8proto"
 
 	"github.com/sirupsen/logrus"
@@ -32,10 +33,6 @@ import (
 const (
 	MapName = "cilium_policy_"
 
-	// MaxEntries is the upper limit of entries in the per endpoint policy
-	// table
-	MaxEntries = 16384
-
 	// ProgArrayMaxEntries is the upper limit of entries in the program
 	// array for the tail calls to jump into the endpoint specific policy
 	// programs. This number *MUST* be identical to the maximum endponit ID.
@@ -49,10 +46,23 @@ const (
 
 var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "map-policy")
 
+// MaxEntries is the upper limit of entries in the per endpoint policy table
+var MaxEntries = option.PolicyMapEntriesDefault
+
+// SetMaxEntries sets the upper limit of entries in the per endpoint policy
+// tables opened or created from now on. Existing tables of a different size
+// are migrated to the new size when they are opened.
+func SetMaxEntries(maxEntries int) {
+	MaxEntries = maxEntries
+}
+
 type PolicyMap struct {
 	path  string
 	Fd    int
 	mutex lock.Mutex
+
+	// events is the journal of the most recent mutations of the map
+	events bpf.MapEvents
 }
 
 func (pe *PolicyEntry) String() string {
@@ -61,6 +71,10 @@ func (pe *PolicyEntry) String() string {
 
 // PolicyKey represents a key in the BPF policy map for an endpoint. It must
 // match the layout of policy_key in bpf/lib/common.h.
+//
+// Bit 0 of TrafficDirection is the direction of the traffic, bits 1-4 are the
+// number of low-order bits of DestPort which are wildcarded, see PortMask.
+// Keys created from a TrafficDirection alone match a single port.
 type PolicyKey struct {
 	Identity         uint32
 	DestPort         uint16 // In network byte-order
@@ -96,18 +110,47 @@ type PolicyEntriesDump []PolicyEntryDump
 // TrafficDirection lower than `j`'s TrafficDirection or if the element in index
 // `i` has the value of TrafficDirection lower and equal than `j`'s
 // TrafficDirection and the identity of element `i` is lower than the Identity
-// of element j.
+// of element j. Keys of port ranges are sorted with the keys of their
+// direction.
 func (p PolicyEntriesDump) Less(i, j int) bool {
-	if p[i].Key.TrafficDirection < p[j].Key.TrafficDirection {
+	if p[i].Key.GetDirection() < p[j].Key.GetDirection() {
 		return true
 	}
-	return p[i].Key.TrafficDirection <= p[j].Key.TrafficDirection &&
+	return p[i].Key.GetDirection() <= p[j].Key.GetDirection() &&
 		p[i].Key.Identity < p[j].Key.Identity
 }
 
+// GetKeyPtr returns the unsafe pointer to the BPF key
+func (key *PolicyKey) GetKeyPtr() unsafe.Pointer { return unsafe.Pointer(key) }
+
+// NewValue returns a new empty instance of the structure representing the BPF
+// map value
+func (key *PolicyKey) NewValue() bpf.MapValue { return &PolicyEntry{} }
+
+// GetValuePtr returns the unsafe pointer to the BPF value
+func (pe *PolicyEntry) GetValuePtr() unsafe.Pointer { return unsafe.Pointer(pe) }
+
+func dumpParser(key []byte, value []byte) (bpf.MapKey, bpf.MapValue, error) {
+	k, v := PolicyKey{}, PolicyEntry{}
+
+	if err := bpf.ConvertKeyValue(key, value, &k, &v); err != nil {
+		return nil, nil, err
+	}
+	return &k, &v, nil
+}
+
+func init() {
+	bpf.RegisterMapCodec(MapName, int(unsafe.Sizeof(PolicyKey{})),
+		int(unsafe.Sizeof(PolicyEntry{})), dumpParser)
+}
+
 func (key *PolicyKey) String() string {
 
-	trafficDirectionString := (TrafficDirection)(key.TrafficDirection).String()
+	trafficDirectionString := TrafficDirection(key.GetDirection()).String()
+	if key.PortWildcardBits() != 0 {
+		first, last := key.PortRange()
+		return fmt.Sprintf("%s: %d %d-%d/%d", trafficDirectionString, key.Identity, first, last, key.Nexthdr)
+	}
 	if key.DestPort != 0 {
 		return fmt.Sprintf("%s: %d %d/%d", trafficDirectionString, key.Identity, byteorder.NetworkToHost(key.DestPort), key.Nexthdr)
 	}
@@ -132,7 +175,7 @@ func (key *PolicyKey) GetProto() uint8 {
 
 // GetDirection returns the traffic direction for key.
 func (key *PolicyKey) GetDirection() uint8 {
-	return key.TrafficDirection
+	return key.TrafficDirection & trafficDirectionMask
 }
 
 // ToHost returns a copy of key with fields converted from network byte-order
@@ -171,7 +214,33 @@ func (pm *PolicyMap) AllowKey(k PolicyKey, proxyPort uint16) error {
 func (pm *PolicyMap) Allow(id uint32, dport uint16, proto u8proto.U8proto, trafficDirection TrafficDirection, proxyPort uint16) error {
 	key := PolicyKey{Identity: id, DestPort: byteorder.HostToNetwork(dport).(uint16), Nexthdr: uint8(proto), TrafficDirection: trafficDirection.Uint8()}
 	entry := PolicyEntry{ProxyPort: byteorder.HostToNetwork(proxyPort).(uint16)}
-	return bpf.UpdateElement(pm.Fd, unsafe.Pointer(&key), unsafe.Pointer(&entry), 0)
+	return pm.update(&key, &entry)
+}
+
+// AllowPortMask pushes an entry into the PolicyMap to allow traffic in the
+// given `trafficDirection` for identity `id` to all destination ports covered
+// by `mask` over protocol `proto`. It is assumed that `proxyPort` is in host
+// byte-order.
+func (pm *PolicyMap) AllowPortMask(id uint32, mask PortMask, proto u8proto.U8proto, trafficDirection TrafficDirection, proxyPort uint16) error {
+	key := newPortMaskKey(id, mask, proto, trafficDirection)
+	entry := PolicyEntry{ProxyPort: byteorder.HostToNetwork(proxyPort).(uint16)}
+	return pm.update(&key, &entry)
+}
+
+// update sets the entry of key and records the update in the journal of the
+// map.
+func (pm *PolicyMap) update(key *PolicyKey, entry *PolicyEntry) error {
+	err := bpf.UpdateElement(pm.Fd, unsafe.Pointer(key), unsafe.Pointer(entry), 0)
+	pm.events.Record(bpf.MapEventUpdate, key, entry, err, bpf.MapEventCaller())
+	return err
+}
+
+// delete removes the entry of key and records the deletion in the journal of
+// the map.
+func (pm *PolicyMap) delete(key *PolicyKey) error {
+	err := bpf.DeleteElement(pm.Fd, unsafe.Pointer(key))
+	pm.events.Record(bpf.MapEventDelete, key, nil, err, bpf.MapEventCaller())
+	return err
 }
 
 // Exists determines whether PolicyMap currently contains an entry that
@@ -195,13 +264,83 @@ func (pm *PolicyMap) DeleteKey(k PolicyKey) error {
 // Returns an error if the deletion did not succeed.
 func (pm *PolicyMap) Delete(id uint32, dport uint16, proto u8proto.U8proto, trafficDirection TrafficDirection) error {
 	key := PolicyKey{Identity: id, DestPort: byteorder.HostToNetwork(dport).(uint16), Nexthdr: uint8(proto), TrafficDirection: trafficDirection.Uint8()}
-	return bpf.DeleteElement(pm.Fd, unsafe.Pointer(&key))
+	return pm.delete(&key)
+}
+
+// DeletePortMask removes the entry for the destination ports covered by
+// `mask` from the PolicyMap. Returns an error if the deletion did not
+// succeed.
+func (pm *PolicyMap) DeletePortMask(id uint32, mask PortMask, proto u8proto.U8proto, trafficDirection TrafficDirection) error {
+	key := newPortMaskKey(id, mask, proto, trafficDirection)
+	return pm.delete(&key)
 }
 
 // DeleteEntry removes an entry from the PolicyMap. It can be used in
 // conjunction with DumpToSlice() to inspect and delete map entries.
 func (pm *PolicyMap) DeleteEntry(entry *PolicyEntryDump) error {
-	return bpf.DeleteElement(pm.Fd, unsafe.Pointer(&entry.Key))
+	return pm.delete(&entry.Key)
+}
+
+// Batch collects changes to a PolicyMap which are applied together by Flush()
+type Batch struct {
+	*bpf.MapBatch
+
+	pm *PolicyMap
+
+	// ops holds the pending operations in the order they were queued, so
+	// that they can be recorded in the journal of the map once flushed
+	ops []batchOp
+}
+
+// batchOp is an operation of a Batch. entry is nil for deletions.
+type batchOp struct {
+	key   PolicyKey
+	entry *PolicyEntry
+}
+
+// NewBatch returns a batch of changes to the PolicyMap
+func (pm *PolicyMap) NewBatch() *Batch {
+	return &Batch{
+		MapBatch: bpf.NewMapBatch(pm.Fd, int(unsafe.Sizeof(PolicyKey{})), int(unsafe.Sizeof(PolicyEntry{}))),
+		pm:       pm,
+	}
+}
+
+// AllowKey queues an entry for the given PolicyKey k in host byte-order, see
+// PolicyMap.AllowKey(). Returns the index of the operation.
+func (b *Batch) AllowKey(k PolicyKey, proxyPort uint16) int {
+	key := k.ToNetwork()
+	entry := PolicyEntry{ProxyPort: byteorder.HostToNetwork(proxyPort).(uint16)}
+	b.ops = append(b.ops, batchOp{key: key, entry: &entry})
+	return b.Update(unsafe.Pointer(&key), unsafe.Pointer(&entry))
+}
+
+// DeleteKey queues the deletion of the entry for the given PolicyKey k in
+// host byte-order, see PolicyMap.DeleteKey(). Returns the index of the
+// operation.
+func (b *Batch) DeleteKey(k PolicyKey) int {
+	key := k.ToNetwork()
+	b.ops = append(b.ops, batchOp{key: key})
+	return b.Delete(unsafe.Pointer(&key))
+}
+
+// Flush applies all pending operations, see bpf.MapBatch.Flush(), and records
+// them in the journal of the map.
+func (b *Batch) Flush() map[int]error {
+	caller := bpf.MapEventCaller()
+	errors := b.MapBatch.Flush()
+
+	for i := range b.ops {
+		op := &b.ops[i]
+		if op.entry == nil {
+			b.pm.events.Record(bpf.MapEventDelete, &op.key, nil, errors[i], caller)
+		} else {
+			b.pm.events.Record(bpf.MapEventUpdate, &op.key, op.entry, errors[i], caller)
+		}
+	}
+	b.ops = b.ops[:0]
+
+	return errors
 }
 
 func (pm *PolicyMap) String() string {
@@ -254,6 +393,12 @@ func (pm *PolicyMap) DumpToSlice() (PolicyEntriesDump, error) {
 	return entries, nil
 }
 
+// CountEntries returns the number of entries in the given policy map
+func (pm *PolicyMap) CountEntries() int {
+	return bpf.CountEntries(pm.Fd, uint32(unsafe.Sizeof(PolicyKey{})),
+		uint32(unsafe.Sizeof(PolicyEntry{})), uint32(MaxEntries))
+}
+
 // Flush deletes all entries from the given policy map
 func (pm *PolicyMap) Flush() error {
 	var key, nextKey PolicyKey
@@ -276,6 +421,7 @@ func (pm *PolicyMap) Flush() error {
 
 		key = nextKey
 	}
+	pm.events.Record(bpf.MapEventDeleteAll, nil, nil, nil, bpf.MapEventCaller())
 	return nil
 }
 
@@ -289,6 +435,7 @@ func (pm *PolicyMap) Close() error {
 		logfields.BPFMapPath: pm.path,
 		logfields.BPFMapFD:   pm.Fd,
 	}).Debug("closing PolicyMap")
+	bpf.UnregisterMapEvents(pm.path, &pm.events)
 	err := bpf.ObjClose(pm.Fd)
 
 	// Unconditionally set file descriptor to zero so that if accesses are
@@ -313,7 +460,7 @@ func OpenMap(path string) (*PolicyMap, bool, error) {
 		bpf.BPF_MAP_TYPE_HASH,
 		uint32(unsafe.Sizeof(PolicyKey{})),
 		uint32(unsafe.Sizeof(PolicyEntry{})),
-		MaxEntries,
+		uint32(MaxEntries),
 		0,
 	)
 
@@ -322,6 +469,7 @@ func OpenMap(path string) (*PolicyMap, bool, error) {
 	}
 
 	m := &PolicyMap{path: path, Fd: fd}
+	bpf.RegisterMapEvents(path, &m.events)
 
 	return m, isNewMap, nil
 }
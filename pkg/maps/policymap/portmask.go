@@ -0,0 +1,95 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policymap
+
+import (
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/u8proto"
+)
+
+const (
+	// PortWildcardMax is the maximum number of low-order bits of the
+	// destination port which can be wildcarded in a policy key. It must
+	// match POLICY_PORT_WILDCARD_MAX in bpf/lib/policy.h.
+	PortWildcardMax = 15
+
+	trafficDirectionMask = 0x1
+	portWildcardShift    = 1
+	portWildcardMask     = 0xf
+)
+
+// PortMask is a destination port of which the lowest WildcardBits bits are
+// wildcarded. It covers the ports Port to Port+2^WildcardBits-1, Port must
+// be aligned accordingly. The datapath looks up masked entries only if no
+// entry for the exact port or for all ports exists.
+type PortMask struct {
+	// Port is the first port covered by the mask, in host byte-order
+	Port uint16
+
+	// WildcardBits is the number of low-order bits of the port which
+	// are wildcarded, 0 to match Port only
+	WildcardBits uint8
+}
+
+// Last returns the last port covered by m.
+func (m PortMask) Last() uint16 {
+	return m.Port | (uint16(1)<<m.WildcardBits - 1)
+}
+
+// PortRangeMasks returns the smallest list of port masks covering exactly
+// the ports first to last, in ascending order. Returns nil if last is
+// smaller than first.
+func PortRangeMasks(first, last uint16) []PortMask {
+	var masks []PortMask
+	for port := int(first); port <= int(last); {
+		bits := uint8(0)
+		for bits < PortWildcardMax {
+			size := 1 << (bits + 1)
+			if port&(size-1) != 0 || port+size-1 > int(last) {
+				break
+			}
+			bits++
+		}
+		masks = append(masks, PortMask{Port: uint16(port), WildcardBits: bits})
+		port += 1 << bits
+	}
+	return masks
+}
+
+// newPortMaskKey returns the policy key matching the ports covered by mask.
+func newPortMaskKey(id uint32, mask PortMask, proto u8proto.U8proto, trafficDirection TrafficDirection) PolicyKey {
+	return PolicyKey{
+		Identity:         id,
+		DestPort:         byteorder.HostToNetwork(mask.Port).(uint16),
+		Nexthdr:          uint8(proto),
+		TrafficDirection: trafficDirection.Uint8() | (mask.WildcardBits&portWildcardMask)<<portWildcardShift,
+	}
+}
+
+// PortWildcardBits returns the number of low-order bits of the destination
+// port which are wildcarded in key, 0 if key matches a single port.
+func (key *PolicyKey) PortWildcardBits() uint8 {
+	return key.TrafficDirection >> portWildcardShift & portWildcardMask
+}
+
+// PortRange returns the first and the last destination port matched by key,
+// in host byte-order. Both are 0 if key matches all ports.
+func (key *PolicyKey) PortRange() (first, last uint16) {
+	mask := PortMask{
+		Port:         byteorder.NetworkToHost(key.DestPort).(uint16),
+		WildcardBits: key.PortWildcardBits(),
+	}
+	return mask.Port, mask.Last()
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policymap
+
+import (
+	"github.com/cilium/cilium/pkg/u8proto"
+
+	. "gopkg.in/check.v1"
+)
+
+func (pm *PolicyMapTestSuite) TestPortRangeMasks(c *C) {
+	tests := []struct {
+		first, last uint16
+		want        []PortMask
+	}{
+		{80, 80, []PortMask{{80, 0}}},
+		{80, 82, []PortMask{{80, 1}, {82, 0}}},
+		{8000, 8999, []PortMask{
+			{8000, 6}, {8064, 7}, {8192, 9}, {8704, 8}, {8960, 5}, {8992, 3},
+		}},
+		{1, 65535, []PortMask{
+			{1, 0}, {2, 1}, {4, 2}, {8, 3}, {16, 4}, {32, 5}, {64, 6}, {128, 7},
+			{256, 8}, {512, 9}, {1024, 10}, {2048, 11}, {4096, 12}, {8192, 13},
+			{16384, 14}, {32768, 15},
+		}},
+		{0, 65535, []PortMask{{0, 15}, {32768, 15}}},
+		{90, 80, nil},
+	}
+	for _, tt := range tests {
+		masks := PortRangeMasks(tt.first, tt.last)
+		c.Assert(masks, DeepEquals, tt.want, Commentf("range %d-%d", tt.first, tt.last))
+
+		// The masks cover the range without gaps or overlaps
+		next := int(tt.first)
+		for _, m := range masks {
+			c.Assert(int(m.Port), Equals, next)
+			next = int(m.Last()) + 1
+		}
+		if len(masks) > 0 {
+			c.Assert(next, Equals, int(tt.last)+1)
+		}
+	}
+}
+
+func (pm *PolicyMapTestSuite) TestPortMaskKey(c *C) {
+	key := newPortMaskKey(1000, PortMask{Port: 8064, WildcardBits: 7}, u8proto.TCP, Egress)
+	c.Assert(key.GetDirection(), Equals, Egress.Uint8())
+	c.Assert(key.PortWildcardBits(), Equals, uint8(7))
+	first, last := key.PortRange()
+	c.Assert(first, Equals, uint16(8064))
+	c.Assert(last, Equals, uint16(8191))
+	c.Assert(key.String(), Equals, "Egress: 1000 8064-8191/6")
+
+	key = newPortMaskKey(1000, PortMask{Port: 80}, u8proto.TCP, Ingress)
+	c.Assert(key.TrafficDirection, Equals, Ingress.Uint8())
+	c.Assert(key.PortWildcardBits(), Equals, uint8(0))
+	c.Assert(key.String(), Equals, "Ingress: 1000 80/6")
+}
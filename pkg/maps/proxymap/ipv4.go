@@ -186,7 +186,7 @@ func cleanupIPv4Redirects(proxyPort uint16) {
 			return
 		}
 
-		if nextKey.DPort == dportNetworkOrder && nextKey.Nexthdr == uint8(6) {
+		if nextKey.DPort == dportNetworkOrder {
 			log.Debugf("Cleaning up IPv4 proxymap, removing entry: %+v", nextKey)
 			bpf.DeleteElement(Proxy4Map.GetFd(), unsafe.Pointer(&nextKey))
 		}
@@ -160,7 +160,7 @@ func cleanupIPv6Redirects(proxyPort uint16) {
 			return
 		}
 
-		if nextKey.DPort == dportNetworkOrder && nextKey.Nexthdr == uint8(6) {
+		if nextKey.DPort == dportNetworkOrder {
 			log.Debugf("Cleaning up IPv6 proxymap, removing entry: %+v", nextKey)
 			bpf.DeleteElement(Proxy6Map.GetFd(), unsafe.Pointer(&nextKey))
 		}
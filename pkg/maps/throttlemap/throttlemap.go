@@ -0,0 +1,59 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttlemap
+
+import (
+	"strconv"
+	"unsafe"
+
+	"github.com/cilium/cilium/pkg/bpf"
+)
+
+// MapName is the prefix of the name of the map holding the egress bandwidth
+// limiting state of an endpoint, followed by the endpoint ID
+const MapName = "cilium_throttle_"
+
+// State is the egress bandwidth limiting state of an endpoint. It must match
+// struct throttle_state in bpf/lib/throttle.h.
+type State struct {
+	Tokens     uint64
+	LastRefill uint64
+	Bytes      uint64
+}
+
+// Path returns the path of the map of the endpoint with the given ID
+func Path(id uint16) string {
+	return bpf.MapPath(MapName + strconv.Itoa(int(id)))
+}
+
+// ReadBytes returns the number of bytes which passed the egress bandwidth
+// limit of the endpoint with the given ID. The map only exists once a limit
+// has been configured for the endpoint.
+func ReadBytes(id uint16) (uint64, error) {
+	fd, err := bpf.ObjGet(Path(id))
+	if err != nil {
+		return 0, err
+	}
+	defer bpf.ObjClose(fd)
+
+	var (
+		key   uint32
+		state State
+	)
+	if err := bpf.LookupElement(fd, unsafe.Pointer(&key), unsafe.Pointer(&state)); err != nil {
+		return 0, err
+	}
+	return state.Bytes, nil
+}
@@ -46,6 +46,14 @@ const (
 	// BuildStateRunning is the value of LabelBuildState to describe
 	// the number of builds currently running
 	BuildStateRunning = "running"
+
+	// LimitConnections is the value of LabelLimit to describe the
+	// connection limit of a circuit breaker
+	LimitConnections = "connections"
+
+	// LimitPendingRequests is the value of LabelLimit to describe the
+	// pending requests limit of a circuit breaker
+	LimitPendingRequests = "pending-requests"
 )
 
 var (
@@ -104,6 +112,9 @@ var (
 	// LabelProtocolL7 is the label used when working with layer 7 protocols.
 	LabelProtocolL7 = "protocol_l7"
 
+	// LabelLimit is the label used to refer to a limit of a circuit breaker
+	LabelLimit = "limit"
+
 	// LabelBuildState is the state a build queue entry is in
 	LabelBuildState = "state"
 
@@ -117,6 +128,23 @@ var (
 	// started by cilium (Envoy, monitor, etc..)
 	LabelSubsystem = "subsystem"
 
+	// LabelPhase is the label used to refer to a phase of an operation
+	LabelPhase = "phase"
+
+	// LabelTrigger is the label used to refer to the reason which triggered
+	// an operation
+	LabelTrigger = "trigger"
+
+	// LabelMapName is the label used to refer to the name of a BPF map
+	LabelMapName = "map_name"
+
+	// LabelDriftKind is the label used to refer to the kind of difference
+	// between the desired and the actual content of a BPF map
+	LabelDriftKind = "kind"
+
+	// LabelSink is the label used to refer to an access log sink
+	LabelSink = "sink"
+
 	// Endpoint
 
 	// EndpointCount is a function used to collect this metric.
@@ -174,6 +202,18 @@ var (
 		Help:      "Endpoint regeneration time stats labeled by the scope",
 	}, []string{LabelScope, LabelStatus})
 
+	// EndpointRegenerationPhaseDuration is the time taken by each phase of
+	// endpoint regenerations, labeled by phase and by the reason which
+	// triggered the regeneration
+	EndpointRegenerationPhaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "endpoint_regeneration_phase_duration_seconds",
+		Help:      "Duration of endpoint regeneration phases labeled by phase and trigger",
+		// BPF compilation can take several seconds on loaded nodes,
+		// cover durations from 1ms up to 32s
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{LabelPhase, LabelTrigger})
+
 	// Policy
 
 	// PolicyCount is the number of policies loaded into the agent
@@ -294,6 +334,22 @@ var (
 		Help:      "Number of total L7 received requests/responses",
 	})
 
+	// ProxyAccessLogSinkDropped is a count of access log records which
+	// could not be written to an access log sink
+	ProxyAccessLogSinkDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "proxy_access_log_sink_dropped_total",
+		Help:      "Number of access log records dropped by an access log sink, labeled by sink",
+	}, []string{LabelSink})
+
+	// ProxyCircuitBreakerOverflows is a count of connections and requests
+	// rejected by circuit breakers of the proxy
+	ProxyCircuitBreakerOverflows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "proxy_circuit_breaker_overflows_total",
+		Help:      "Number of connections and requests rejected by L7 proxy circuit breakers, labeled by protocol and limit",
+	}, []string{LabelProtocolL7, LabelLimit})
+
 	// L3-L4 statistics
 
 	// DropCount is the total drop requests,
@@ -314,6 +370,24 @@ var (
 	},
 		[]string{"direction"})
 
+	// DropBytes is the total dropped bytes,
+	// tagged by drop reason and direction(ingress/egress)
+	DropBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "drop_bytes_total",
+		Help:      "Total dropped bytes, tagged by drop reason and ingress/egress direction",
+	},
+		[]string{"reason", "direction"})
+
+	// ForwardBytes is the total forwarded bytes,
+	// tagged by ingress/egress direction
+	ForwardBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "forward_bytes_total",
+		Help:      "Total forwarded bytes, tagged by ingress/egress direction",
+	},
+		[]string{"direction"})
+
 	// Datapath statistics
 
 	// DatapathErrors is the number of errors managing datapath components
@@ -361,6 +435,59 @@ var (
 			"labeled by datapath family and completion status",
 	}, []string{LabelDatapathFamily, LabelProtocol, LabelStatus})
 
+	// ConntrackGCScannedEntries is the number of conntrack entries visited
+	// by the conntrack GC process
+	ConntrackGCScannedEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: Datapath,
+		Name:      "conntrack_gc_scanned_entries_total",
+		Help:      "Number of conntrack entries scanned by the garbage collector labeled by datapath family",
+	}, []string{LabelDatapathFamily, LabelProtocol})
+
+	// ConntrackGCDeletedEntries is the number of conntrack entries deleted
+	// by the conntrack GC process
+	ConntrackGCDeletedEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: Datapath,
+		Name:      "conntrack_gc_deleted_entries_total",
+		Help:      "Number of conntrack entries deleted by the garbage collector labeled by datapath family",
+	}, []string{LabelDatapathFamily, LabelProtocol})
+
+	// ConntrackGCInterval is the current interval between two runs of the
+	// conntrack GC process
+	ConntrackGCInterval = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: Datapath,
+		Name:      "conntrack_gc_interval_seconds",
+		Help:      "Interval in seconds until the next run of the conntrack garbage collector",
+	})
+
+	// BPFMapPressure is the fill ratio of BPF maps as of the last sample
+	BPFMapPressure = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: Datapath,
+		Name:      "bpf_map_pressure",
+		Help:      "Ratio of the number of entries to the maximum number of entries of BPF maps labeled by map name",
+	}, []string{LabelMapName})
+
+	// BPFMapDrift is the number of entries of BPF maps which differ from
+	// the desired state as of the last consistency check
+	BPFMapDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Subsystem: Datapath,
+		Name:      "bpf_map_drift_entries",
+		Help:      "Number of entries of BPF maps which differ from the desired state labeled by map name and kind of difference",
+	}, []string{LabelMapName, LabelDriftKind})
+
+	// BPFMapRepairs is the number of entries of BPF maps repaired by the
+	// consistency checker
+	BPFMapRepairs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: Datapath,
+		Name:      "bpf_map_repaired_entries_total",
+		Help:      "Number of entries of BPF maps restored to the desired state labeled by map name",
+	}, []string{LabelMapName})
+
 	// Services
 
 	// ServicesCount number of services
@@ -440,6 +567,7 @@ func init() {
 	MustRegister(EndpointRegenerationTimeSquare)
 	MustRegister(EndpointStateCount)
 	MustRegister(EndpointRegenerationTimeStats)
+	MustRegister(EndpointRegenerationPhaseDuration)
 
 	MustRegister(PolicyCount)
 	MustRegister(PolicyRegenerationCount)
@@ -458,9 +586,13 @@ func init() {
 	MustRegister(ProxyForwarded)
 	MustRegister(ProxyDenied)
 	MustRegister(ProxyReceived)
+	MustRegister(ProxyAccessLogSinkDropped)
+	MustRegister(ProxyCircuitBreakerOverflows)
 
 	MustRegister(DropCount)
 	MustRegister(ForwardCount)
+	MustRegister(DropBytes)
+	MustRegister(ForwardBytes)
 
 	MustRegister(newStatusCollector())
 
@@ -469,6 +601,12 @@ func init() {
 	MustRegister(ConntrackGCKeyFallbacks)
 	MustRegister(ConntrackGCSize)
 	MustRegister(ConntrackGCDuration)
+	MustRegister(ConntrackGCScannedEntries)
+	MustRegister(ConntrackGCDeletedEntries)
+	MustRegister(ConntrackGCInterval)
+	MustRegister(BPFMapPressure)
+	MustRegister(BPFMapDrift)
+	MustRegister(BPFMapRepairs)
 
 	MustRegister(ServicesCount)
 
@@ -40,6 +40,7 @@ const (
 	AgentNotifyEndpointRegenerateFail
 	AgentNotifyPolicyUpdated
 	AgentNotifyPolicyDeleted
+	AgentNotifyResourceBudgetExceeded
 )
 
 var notifyTable = map[AgentNotification]string{
@@ -50,6 +51,7 @@ var notifyTable = map[AgentNotification]string{
 	AgentNotifyEndpointRegenerateFail:    "Failed endpoint regeneration",
 	AgentNotifyPolicyUpdated:             "Policy updated",
 	AgentNotifyPolicyDeleted:             "Policy deleted",
+	AgentNotifyResourceBudgetExceeded:    "Resource budget exceeded",
 }
 
 func resolveAgentType(t AgentNotification) string {
@@ -113,16 +115,22 @@ func PolicyDeleteRepr(deleted int, labels []string, revision uint64) (string, er
 
 // EndpointRegenNotification structures regeneration notification
 type EndpointRegenNotification struct {
-	ID     uint64   `json:"id,omitempty"`
-	Labels []string `json:"labels,omitempty"`
-	Error  string   `json:"error,omitempty"`
+	ID               uint64   `json:"id,omitempty"`
+	Labels           []string `json:"labels,omitempty"`
+	Namespace        string   `json:"namespace,omitempty"`
+	PodName          string   `json:"pod_name,omitempty"`
+	ContainerRuntime string   `json:"container_runtime,omitempty"`
+	Error            string   `json:"error,omitempty"`
 }
 
 // EndpointRegenRepr returns string representation of monitor notification
 func EndpointRegenRepr(e notifications.RegenNotificationInfo, err error) (string, error) {
 	notification := EndpointRegenNotification{
-		ID:     e.GetID(),
-		Labels: e.GetOpLabels(),
+		ID:               e.GetID(),
+		Labels:           e.GetOpLabels(),
+		Namespace:        e.GetK8sNamespace(),
+		PodName:          e.GetK8sPodName(),
+		ContainerRuntime: e.GetContainerRuntime(),
 	}
 
 	if err != nil {
@@ -147,3 +155,18 @@ func TimeRepr(t time.Time) (string, error) {
 	repr, err := json.Marshal(notification)
 	return string(repr), err
 }
+
+// ResourceBudgetNotification structures resource budget notification
+type ResourceBudgetNotification struct {
+	Exceeded []string `json:"exceeded"`
+}
+
+// ResourceBudgetRepr returns string representation of monitor notification
+func ResourceBudgetRepr(exceeded []string) (string, error) {
+	notification := ResourceBudgetNotification{
+		Exceeded: exceeded,
+	}
+	repr, err := json.Marshal(notification)
+
+	return string(repr), err
+}
@@ -139,17 +139,29 @@ func (MockEndpoint) GetOpLabels() []string {
 	}.GetModel()
 }
 
+func (MockEndpoint) GetK8sNamespace() string {
+	return "default"
+}
+
+func (MockEndpoint) GetK8sPodName() string {
+	return "web-0"
+}
+
+func (MockEndpoint) GetContainerRuntime() string {
+	return "containerd"
+}
+
 func (s *MonitorSuite) TestEndpointRegenRepr(c *C) {
 	e := MockEndpoint{}
 	rerr := RegenError{}
 
 	repr, err := EndpointRegenRepr(e, rerr)
 	c.Assert(err, IsNil)
-	testEqualityEndpoint(repr, `{"id":10,"labels":["unspec:key1=value1","unspec:key2=value2"],"error":"RegenError"}`, c)
+	testEqualityEndpoint(repr, `{"id":10,"labels":["unspec:key1=value1","unspec:key2=value2"],"namespace":"default","pod_name":"web-0","container_runtime":"containerd","error":"RegenError"}`, c)
 
 	repr, err = EndpointRegenRepr(e, nil)
 	c.Assert(err, IsNil)
-	testEqualityEndpoint(repr, `{"id":10,"labels":["unspec:key1=value1","unspec:key2=value2"]}`, c)
+	testEqualityEndpoint(repr, `{"id":10,"labels":["unspec:key1=value1","unspec:key2=value2"],"namespace":"default","pod_name":"web-0","container_runtime":"containerd"}`, c)
 }
 
 func (s *MonitorSuite) TestTimeRepr(c *C) {
@@ -413,6 +413,12 @@ func (n *DebugCapture) infoPrefix() string {
 	}
 }
 
+// Summary returns a one-line description of the capture message without
+// the connection of the packet.
+func (n *DebugCapture) Summary() string {
+	return fmt.Sprintf("MARK %#x FROM %d DEBUG: %s", n.Hash, n.Source, n.subTypeString())
+}
+
 // DumpVerbose prints the captured packet in human readable format
 func (n *DebugCapture) DumpVerbose(dissect bool, data []byte, prefix string) {
 	fmt.Printf("%s MARK %#x FROM %d DEBUG: %d bytes, ", prefix, n.Hash, n.Source, n.Len)
@@ -75,6 +75,9 @@ var errors = map[uint8]string{
 	160: "No tunnel/encapsulation endpoint (datapath BUG!)",
 	161: "Failed to insert into proxymap",
 	162: "Policy denied (CIDR)",
+	163: "Rate limited",
+	164: "No mapping for NAT masquerade",
+	165: "Fragment of unknown datagram",
 }
 
 // DropReason prints the drop reason in a human readable string
@@ -85,11 +88,16 @@ func DropReason(reason uint8) string {
 	return fmt.Sprintf("%d", reason)
 }
 
+// Summary returns a one-line description of the drop notification without
+// the connection of the packet.
+func (n *DropNotify) Summary() string {
+	return fmt.Sprintf("xx drop (%s) flow %#x to endpoint %d, identity %d->%d",
+		DropReason(n.SubType), n.Hash, n.DstID, n.SrcLabel, n.DstLabel)
+}
+
 // DumpInfo prints a summary of the drop messages.
 func (n *DropNotify) DumpInfo(data []byte) {
-	fmt.Printf("xx drop (%s) flow %#x to endpoint %d, identity %d->%d: %s\n",
-		DropReason(n.SubType), n.Hash, n.DstID, n.SrcLabel, n.DstLabel,
-		GetConnectionSummary(data[DropNotifyLen:]))
+	fmt.Printf("%s: %s\n", n.Summary(), GetConnectionSummary(data[DropNotifyLen:]))
 }
 
 // DumpVerbose prints the drop notification in human readable form
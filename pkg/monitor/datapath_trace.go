@@ -124,11 +124,17 @@ func (n *TraceNotify) traceSummary() string {
 	}
 }
 
+// Summary returns a one-line description of the trace notification without
+// the connection of the packet.
+func (n *TraceNotify) Summary() string {
+	return fmt.Sprintf("%s flow %#x identity %d->%d state %s ifindex %s",
+		n.traceSummary(), n.Hash, n.SrcLabel, n.DstLabel,
+		connState(n.Reason), ifname(int(n.Ifindex)))
+}
+
 // DumpInfo prints a summary of the trace messages.
 func (n *TraceNotify) DumpInfo(data []byte) {
-	fmt.Printf("%s flow %#x identity %d->%d state %s ifindex %s: %s\n",
-		n.traceSummary(), n.Hash, n.SrcLabel, n.DstLabel,
-		connState(n.Reason), ifname(int(n.Ifindex)), GetConnectionSummary(data[TraceNotifyLen:]))
+	fmt.Printf("%s: %s\n", n.Summary(), GetConnectionSummary(data[TraceNotifyLen:]))
 }
 
 // DumpVerbose prints the trace notification in human readable form
@@ -0,0 +1,184 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+)
+
+// Verdicts of monitor events which can be used to filter events.
+const (
+	// VerdictForwarded matches trace events and forwarded L7 flows
+	VerdictForwarded = "forwarded"
+	// VerdictDropped matches drop notifications
+	VerdictDropped = "dropped"
+	// VerdictDenied matches L7 flows denied by policy
+	VerdictDenied = "denied"
+	// VerdictError matches L7 flows which failed to be processed
+	VerdictError = "error"
+)
+
+// GetAllVerdicts returns all verdicts which can be filtered on.
+func GetAllVerdicts() []string {
+	return []string{VerdictForwarded, VerdictDropped, VerdictDenied, VerdictError}
+}
+
+// EventFilter selects monitor events. It is sent by monitor clients to the
+// node monitor so that only the events the client is interested in are
+// transmitted. An empty field does not restrict the events.
+type EventFilter struct {
+	// Types is the list of message types to match
+	Types MessageTypeFilter
+	// FromSource is the list of source endpoint IDs to match
+	FromSource []uint16
+	// ToDst is the list of destination endpoint IDs to match
+	ToDst []uint16
+	// Related is the list of endpoint IDs to match as either source or
+	// destination
+	Related []uint16
+	// FromIdentity is the list of source security identities to match
+	FromIdentity []uint32
+	// Verdicts is the list of verdicts to match
+	Verdicts []string
+}
+
+func hasUint16(values []uint16, value uint16) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUint32(values []uint32, value uint32) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func hasString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchType returns true if events of the given message type pass the event
+// type filter.
+func (f *EventFilter) MatchType(messageType int) bool {
+	return len(f.Types) == 0 || f.Types.Contains(messageType)
+}
+
+// filtersFields returns true if the filter restricts events on more than
+// their message type.
+func (f *EventFilter) filtersFields() bool {
+	return len(f.FromSource) > 0 || len(f.ToDst) > 0 || len(f.Related) > 0 ||
+		len(f.FromIdentity) > 0 || len(f.Verdicts) > 0
+}
+
+// Match checks if the event type, from endpoint and / or to endpoint match
+// when they are supplied. The either part of from and to endpoint depends on
+// related to, which can match on both. The source identity and verdict
+// filters only match events which carry this information; an empty verdict
+// never matches a verdict filter.
+func (f *EventFilter) Match(messageType int, src uint16, dst uint16, srcIdentity uint32, verdict string) bool {
+	if !f.MatchType(messageType) {
+		return false
+	} else if len(f.FromSource) > 0 && !hasUint16(f.FromSource, src) {
+		return false
+	} else if len(f.ToDst) > 0 && !hasUint16(f.ToDst, dst) {
+		return false
+	} else if len(f.Related) > 0 && !hasUint16(f.Related, src) && !hasUint16(f.Related, dst) {
+		return false
+	} else if len(f.FromIdentity) > 0 && !hasUint32(f.FromIdentity, srcIdentity) {
+		return false
+	} else if len(f.Verdicts) > 0 && !hasString(f.Verdicts, verdict) {
+		return false
+	}
+
+	return true
+}
+
+// MatchSample returns true if the event in the raw data of a perf event
+// sample or agent event passes the filter. Only the fields of the event
+// which are filtered on are decoded. Events which cannot be decoded pass the
+// filter so that the client can report them.
+func (f *EventFilter) MatchSample(data []byte) bool {
+	if len(data) == 0 {
+		return true
+	}
+
+	messageType := int(data[0])
+	if !f.MatchType(messageType) {
+		return false
+	}
+	if !f.filtersFields() {
+		return true
+	}
+
+	switch messageType {
+	case MessageTypeDrop:
+		dn := DropNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err != nil {
+			return true
+		}
+		return f.Match(messageType, dn.Source, uint16(dn.DstID), dn.SrcLabel, VerdictDropped)
+
+	case MessageTypeTrace:
+		tn := TraceNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err != nil {
+			return true
+		}
+		return f.Match(messageType, tn.Source, tn.DstID, tn.SrcLabel, VerdictForwarded)
+
+	case MessageTypeDebug:
+		dm := DebugMsg{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dm); err != nil {
+			return true
+		}
+		return f.Match(messageType, dm.Source, 0, 0, "")
+
+	case MessageTypeCapture:
+		dc := DebugCapture{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dc); err != nil {
+			return true
+		}
+		return f.Match(messageType, dc.Source, 0, 0, "")
+
+	case MessageTypeAccessLog:
+		lr := LogRecordNotify{}
+		if err := gob.NewDecoder(bytes.NewReader(data[1:])).Decode(&lr); err != nil {
+			return true
+		}
+		return f.Match(messageType, uint16(lr.SourceEndpoint.ID), uint16(lr.DestinationEndpoint.ID),
+			uint32(lr.SourceEndpoint.Identity), strings.ToLower(string(lr.Verdict)))
+
+	case MessageTypeAgent:
+		return f.Match(messageType, 0, 0, 0, "")
+	}
+
+	return true
+}
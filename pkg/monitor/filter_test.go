@@ -0,0 +1,106 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package monitor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *MonitorSuite) TestEventFilterMatch(c *C) {
+	f := &EventFilter{}
+	c.Assert(f.Match(MessageTypeTrace, 1, 2, 100, VerdictForwarded), Equals, true)
+	c.Assert(f.Match(MessageTypeDebug, 1, 0, 0, ""), Equals, true)
+
+	f = &EventFilter{Types: MessageTypeFilter{MessageTypeDrop}}
+	c.Assert(f.MatchType(MessageTypeDrop), Equals, true)
+	c.Assert(f.MatchType(MessageTypeTrace), Equals, false)
+	c.Assert(f.Match(MessageTypeTrace, 1, 2, 100, VerdictForwarded), Equals, false)
+
+	f = &EventFilter{FromIdentity: []uint32{100}}
+	c.Assert(f.Match(MessageTypeDrop, 1, 2, 100, VerdictDropped), Equals, true)
+	c.Assert(f.Match(MessageTypeDrop, 1, 2, 101, VerdictDropped), Equals, false)
+	c.Assert(f.Match(MessageTypeDebug, 1, 0, 0, ""), Equals, false)
+
+	f = &EventFilter{Related: []uint16{2}, Verdicts: []string{VerdictDropped, VerdictDenied}}
+	c.Assert(f.Match(MessageTypeDrop, 1, 2, 100, VerdictDropped), Equals, true)
+	c.Assert(f.Match(MessageTypeAccessLog, 2, 3, 100, VerdictDenied), Equals, true)
+	c.Assert(f.Match(MessageTypeAccessLog, 1, 3, 100, VerdictDenied), Equals, false)
+	c.Assert(f.Match(MessageTypeTrace, 1, 2, 100, VerdictForwarded), Equals, false)
+}
+
+func (s *MonitorSuite) TestEventFilterMatchSample(c *C) {
+	drop := &bytes.Buffer{}
+	err := binary.Write(drop, byteorder.Native, DropNotify{
+		Type:     MessageTypeDrop,
+		Source:   1,
+		SrcLabel: 100,
+		DstID:    2,
+	})
+	c.Assert(err, IsNil)
+
+	trace := &bytes.Buffer{}
+	err = binary.Write(trace, byteorder.Native, TraceNotify{
+		Type:     MessageTypeTrace,
+		Source:   3,
+		SrcLabel: 200,
+		DstID:    4,
+	})
+	c.Assert(err, IsNil)
+
+	accessLog := bytes.NewBuffer([]byte{MessageTypeAccessLog})
+	err = gob.NewEncoder(accessLog).Encode(accesslog.LogRecord{
+		SourceEndpoint:      accesslog.EndpointInfo{ID: 5, Identity: 100},
+		DestinationEndpoint: accesslog.EndpointInfo{ID: 6},
+		Verdict:             accesslog.VerdictDenied,
+	})
+	c.Assert(err, IsNil)
+
+	all := &EventFilter{}
+	c.Assert(all.MatchSample(drop.Bytes()), Equals, true)
+	c.Assert(all.MatchSample(trace.Bytes()), Equals, true)
+	c.Assert(all.MatchSample(accessLog.Bytes()), Equals, true)
+
+	byType := &EventFilter{Types: MessageTypeFilter{MessageTypeTrace}}
+	c.Assert(byType.MatchSample(drop.Bytes()), Equals, false)
+	c.Assert(byType.MatchSample(trace.Bytes()), Equals, true)
+
+	byIdentity := &EventFilter{FromIdentity: []uint32{100}}
+	c.Assert(byIdentity.MatchSample(drop.Bytes()), Equals, true)
+	c.Assert(byIdentity.MatchSample(trace.Bytes()), Equals, false)
+	c.Assert(byIdentity.MatchSample(accessLog.Bytes()), Equals, true)
+
+	byDestination := &EventFilter{ToDst: []uint16{4}}
+	c.Assert(byDestination.MatchSample(drop.Bytes()), Equals, false)
+	c.Assert(byDestination.MatchSample(trace.Bytes()), Equals, true)
+
+	byVerdict := &EventFilter{Verdicts: []string{VerdictDenied}}
+	c.Assert(byVerdict.MatchSample(drop.Bytes()), Equals, false)
+	c.Assert(byVerdict.MatchSample(accessLog.Bytes()), Equals, true)
+
+	// The filter survives the encoding between client and node monitor
+	buf := &bytes.Buffer{}
+	c.Assert(gob.NewEncoder(buf).Encode(byIdentity), IsNil)
+	decoded := EventFilter{}
+	c.Assert(gob.NewDecoder(buf).Decode(&decoded), IsNil)
+	c.Assert(decoded.MatchSample(drop.Bytes()), Equals, true)
+	c.Assert(decoded.MatchSample(trace.Bytes()), Equals, false)
+}
@@ -0,0 +1,237 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+)
+
+const (
+	// CaptureText is the capture format printing events as text
+	CaptureText = "text"
+
+	// CapturePcap is the capture format writing packets to a pcapng file
+	CapturePcap = "pcap"
+
+	// CaptureHexdump is the capture format writing packets as hexdump
+	// which can be converted with text2pcap
+	CaptureHexdump = "hexdump"
+)
+
+// GetAllCaptureFormats returns the names of all capture formats
+func GetAllCaptureFormats() []string {
+	return []string{CaptureText, CapturePcap, CaptureHexdump}
+}
+
+// Packet is a packet carried by a monitor event together with the metadata
+// of the event.
+type Packet struct {
+	// Timestamp is the time at which the event was received
+	Timestamp time.Time
+
+	// Comment describes the event which carried the packet
+	Comment string
+
+	// OrigLen is the length of the packet on the wire
+	OrigLen uint32
+
+	// Data is the part of the packet captured by the datapath, starting
+	// with the ethernet header
+	Data []byte
+}
+
+// packetData returns the packet data following a notification header of
+// hdrLen bytes, truncated to the captured length.
+func packetData(data []byte, hdrLen int, capLen uint32) []byte {
+	if capLen == 0 || len(data) <= hdrLen {
+		return nil
+	}
+	pkt := data[hdrLen:]
+	if int(capLen) < len(pkt) {
+		pkt = pkt[:capLen]
+	}
+	return pkt
+}
+
+// EventPacket returns the packet carried by the event in pl. Returns nil if
+// the event carries no packet or is filtered out by the formatter.
+func (m *MonitorFormatter) EventPacket(pl *payload.Payload) *Packet {
+	if pl.Type != payload.EventSample || len(pl.Data) == 0 {
+		return nil
+	}
+
+	data := pl.Data
+	messageType := data[0]
+	if !m.matchType(int(messageType)) {
+		return nil
+	}
+
+	p := &Packet{Timestamp: time.Now()}
+	switch messageType {
+	case monitor.MessageTypeDrop:
+		dn := monitor.DropNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err != nil {
+			return nil
+		}
+		if !m.match(monitor.MessageTypeDrop, dn.Source, uint16(dn.DstID), dn.SrcLabel, monitor.VerdictDropped) {
+			return nil
+		}
+		p.Comment, p.OrigLen = dn.Summary(), dn.OrigLen
+		p.Data = packetData(data, monitor.DropNotifyLen, dn.CapLen)
+	case monitor.MessageTypeTrace:
+		tn := monitor.TraceNotify{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err != nil {
+			return nil
+		}
+		if !m.match(monitor.MessageTypeTrace, tn.Source, tn.DstID, tn.SrcLabel, monitor.VerdictForwarded) {
+			return nil
+		}
+		p.Comment, p.OrigLen = tn.Summary(), tn.OrigLen
+		p.Data = packetData(data, monitor.TraceNotifyLen, tn.CapLen)
+	case monitor.MessageTypeCapture:
+		dc := monitor.DebugCapture{}
+		if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dc); err != nil {
+			return nil
+		}
+		if !m.match(monitor.MessageTypeCapture, dc.Source, 0, 0, "") {
+			return nil
+		}
+		p.Comment, p.OrigLen = dc.Summary(), dc.OrigLen
+		p.Data = packetData(data, monitor.DebugCaptureLen, dc.Len)
+	default:
+		return nil
+	}
+
+	if len(p.Data) == 0 {
+		return nil
+	}
+	p.Comment = fmt.Sprintf("CPU %02d: %s", pl.CPU, p.Comment)
+	if p.OrigLen < uint32(len(p.Data)) {
+		p.OrigLen = uint32(len(p.Data))
+	}
+	return p
+}
+
+// PacketWriter writes packets in a capture format
+type PacketWriter interface {
+	WritePacket(p *Packet) error
+}
+
+// pcapng block types and options, see
+// https://github.com/pcapng/pcapng
+const (
+	pcapngSectionHeader     = 0x0A0D0D0A
+	pcapngInterfaceDesc     = 0x00000001
+	pcapngEnhancedPacket    = 0x00000006
+	pcapngByteOrderMagic    = 0x1A2B3C4D
+	pcapngOptEndOfOpt       = 0
+	pcapngOptComment        = 1
+	pcapngLinkTypeEthernet  = 1
+	pcapngSectionLengthNone = -1
+)
+
+type pcapWriter struct {
+	w io.Writer
+}
+
+// NewPcapWriter writes the header of a pcapng file with a single ethernet
+// interface to w and returns a writer for packets. Each packet is annotated
+// with the description of its event as comment.
+func NewPcapWriter(w io.Writer) (PacketWriter, error) {
+	var buf bytes.Buffer
+
+	// Section header block
+	binary.Write(&buf, binary.LittleEndian, []uint32{pcapngSectionHeader, 28, pcapngByteOrderMagic})
+	binary.Write(&buf, binary.LittleEndian, []uint16{1, 0})
+	binary.Write(&buf, binary.LittleEndian, int64(pcapngSectionLengthNone))
+	binary.Write(&buf, binary.LittleEndian, uint32(28))
+
+	// Interface description block, timestamps are in microseconds
+	binary.Write(&buf, binary.LittleEndian, []uint32{pcapngInterfaceDesc, 20})
+	binary.Write(&buf, binary.LittleEndian, []uint16{pcapngLinkTypeEthernet, 0})
+	binary.Write(&buf, binary.LittleEndian, []uint32{0, 20})
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return &pcapWriter{w: w}, nil
+}
+
+// pad4 returns the number of padding bytes to align n to 32 bits
+func pad4(n int) int {
+	return (4 - n%4) % 4
+}
+
+// WritePacket writes p as enhanced packet block. The block is written with
+// a single write so that the file remains valid if the program is
+// interrupted.
+func (pw *pcapWriter) WritePacket(p *Packet) error {
+	var options bytes.Buffer
+	if p.Comment != "" {
+		binary.Write(&options, binary.LittleEndian, []uint16{pcapngOptComment, uint16(len(p.Comment))})
+		options.WriteString(p.Comment)
+		options.Write(make([]byte, pad4(len(p.Comment))))
+		binary.Write(&options, binary.LittleEndian, []uint16{pcapngOptEndOfOpt, 0})
+	}
+
+	length := 32 + len(p.Data) + pad4(len(p.Data)) + options.Len()
+	ts := uint64(p.Timestamp.UnixNano() / int64(time.Microsecond))
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, []uint32{
+		pcapngEnhancedPacket, uint32(length), 0,
+		uint32(ts >> 32), uint32(ts),
+		uint32(len(p.Data)), p.OrigLen,
+	})
+	buf.Write(p.Data)
+	buf.Write(make([]byte, pad4(len(p.Data))))
+	buf.Write(options.Bytes())
+	binary.Write(&buf, binary.LittleEndian, uint32(length))
+
+	_, err := pw.w.Write(buf.Bytes())
+	return err
+}
+
+type hexdumpWriter struct {
+	w io.Writer
+}
+
+// NewHexdumpWriter returns a writer for packets which writes each packet as
+// hexdump preceded by a comment line describing its event. The output can
+// be converted to a pcap file with 'text2pcap -t "%Y-%m-%dT%H:%M:%S."'.
+func NewHexdumpWriter(w io.Writer) PacketWriter {
+	return &hexdumpWriter{w: w}
+}
+
+// WritePacket writes p as hexdump
+func (hw *hexdumpWriter) WritePacket(p *Packet) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", p.Comment)
+	fmt.Fprintf(&buf, "%s\n", p.Timestamp.UTC().Format("2006-01-02T15:04:05.000000"))
+	buf.WriteString(hex.Dump(p.Data))
+	buf.WriteString("\n")
+
+	_, err := hw.w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/byteorder"
+	"github.com/cilium/cilium/pkg/monitor"
+	"github.com/cilium/cilium/pkg/monitor/payload"
+
+	. "gopkg.in/check.v1"
+)
+
+func dropEvent(c *C, source uint16, pkt []byte) *payload.Payload {
+	var buf bytes.Buffer
+	dn := monitor.DropNotify{
+		Type:    monitor.MessageTypeDrop,
+		SubType: 133,
+		Source:  source,
+		OrigLen: 1500,
+		CapLen:  uint32(len(pkt)),
+	}
+	c.Assert(binary.Write(&buf, byteorder.Native, dn), IsNil)
+	buf.Write(pkt)
+	// Perf samples are padded
+	buf.Write([]byte{0, 0, 0})
+	return &payload.Payload{Type: payload.EventSample, CPU: 1, Data: buf.Bytes()}
+}
+
+func (s *FormatSuite) TestEventPacket(c *C) {
+	pkt := []byte{1, 2, 3, 4, 5}
+	m := NewMonitorFormatter(INFO)
+
+	p := m.EventPacket(dropEvent(c, 10, pkt))
+	c.Assert(p, Not(IsNil))
+	c.Assert(p.Data, DeepEquals, pkt)
+	c.Assert(p.OrigLen, Equals, uint32(1500))
+	c.Assert(p.Comment, Equals, "CPU 01: xx drop (Policy denied (L3)) flow 0x0 to endpoint 0, identity 0->0")
+
+	// Filtered events and events without packets are skipped
+	c.Assert(m.FromSource.Set("11"), IsNil)
+	c.Assert(m.EventPacket(dropEvent(c, 10, pkt)), IsNil)
+	c.Assert(m.EventPacket(dropEvent(c, 11, nil)), IsNil)
+	c.Assert(m.EventPacket(&payload.Payload{Type: payload.RecordLost, Lost: 3}), IsNil)
+}
+
+func (s *FormatSuite) TestPcapWriter(c *C) {
+	var buf bytes.Buffer
+	w, err := NewPcapWriter(&buf)
+	c.Assert(err, IsNil)
+
+	ts := time.Unix(1, 2000)
+	c.Assert(w.WritePacket(&Packet{Timestamp: ts, Comment: "drop", OrigLen: 100, Data: []byte{1, 2, 3, 4, 5}}), IsNil)
+
+	// Walk the blocks, each block is framed by its total length
+	type block struct {
+		typ  uint32
+		body []byte
+	}
+	var blocks []block
+	data := buf.Bytes()
+	for len(data) > 0 {
+		c.Assert(len(data) >= 12, Equals, true)
+		typ := binary.LittleEndian.Uint32(data[0:])
+		length := binary.LittleEndian.Uint32(data[4:])
+		c.Assert(length%4, Equals, uint32(0))
+		c.Assert(binary.LittleEndian.Uint32(data[length-4:]), Equals, length)
+		blocks = append(blocks, block{typ: typ, body: data[8 : length-4]})
+		data = data[length:]
+	}
+
+	c.Assert(blocks, HasLen, 3)
+	c.Assert(blocks[0].typ, Equals, uint32(pcapngSectionHeader))
+	c.Assert(binary.LittleEndian.Uint32(blocks[0].body), Equals, uint32(pcapngByteOrderMagic))
+	c.Assert(blocks[1].typ, Equals, uint32(pcapngInterfaceDesc))
+	c.Assert(binary.LittleEndian.Uint16(blocks[1].body), Equals, uint16(pcapngLinkTypeEthernet))
+
+	epb := blocks[2].body
+	c.Assert(blocks[2].typ, Equals, uint32(pcapngEnhancedPacket))
+	tsMicro := uint64(binary.LittleEndian.Uint32(epb[4:]))<<32 | uint64(binary.LittleEndian.Uint32(epb[8:]))
+	c.Assert(tsMicro, Equals, uint64(1000002))
+	c.Assert(binary.LittleEndian.Uint32(epb[12:]), Equals, uint32(5))
+	c.Assert(binary.LittleEndian.Uint32(epb[16:]), Equals, uint32(100))
+	c.Assert(epb[20:25], DeepEquals, []byte{1, 2, 3, 4, 5})
+
+	// Packet data is padded to 32 bits, followed by the comment option
+	opts := epb[28:]
+	c.Assert(binary.LittleEndian.Uint16(opts), Equals, uint16(pcapngOptComment))
+	c.Assert(binary.LittleEndian.Uint16(opts[2:]), Equals, uint16(4))
+	c.Assert(string(opts[4:8]), Equals, "drop")
+	c.Assert(opts[8:], DeepEquals, []byte{0, 0, 0, 0})
+}
+
+func (s *FormatSuite) TestHexdumpWriter(c *C) {
+	var buf bytes.Buffer
+	w := NewHexdumpWriter(&buf)
+	ts := time.Date(2018, 7, 1, 10, 0, 0, 1000, time.UTC)
+	c.Assert(w.WritePacket(&Packet{Timestamp: ts, Comment: "CPU 01: drop", Data: []byte{0x45, 0x00}}), IsNil)
+
+	lines := strings.Split(buf.String(), "\n")
+	c.Assert(lines[0], Equals, "# CPU 01: drop")
+	c.Assert(lines[1], Equals, "2018-07-01T10:00:00.000001")
+	c.Assert(strings.HasPrefix(lines[2], "00000000  45 00 "), Equals, true)
+}
@@ -15,9 +15,12 @@
 package format
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/cilium/cilium/pkg/monitor"
+
 	"github.com/spf13/pflag"
 )
 
@@ -51,12 +54,59 @@ func (i *Uint16Flags) Type() string {
 	return "[]uint16"
 }
 
-func (i *Uint16Flags) has(value uint16) bool {
+// Uint32Flags is a slice of unsigned 32-bit ints with some convenience methods.
+type Uint32Flags []uint32
+
+var _ pflag.Value = &Uint32Flags{}
+
+// String provides a human-readable string format of the received variable.
+func (i *Uint32Flags) String() string {
+	pieces := make([]string, 0, len(*i))
 	for _, v := range *i {
-		if v == value {
-			return true
+		pieces = append(pieces, strconv.FormatUint(uint64(v), 10))
+	}
+	return strings.Join(pieces, ", ")
+}
+
+// Set converts the specified value into an integer and appends it to the flags.
+// Returns an error if the value cannot be converted to a 32-bit unsigned value.
+func (i *Uint32Flags) Set(value string) error {
+	v, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return err
+	}
+	*i = append(*i, uint32(v))
+	return nil
+}
+
+// Type returns a human-readable string representing the type of the receiver.
+func (i *Uint32Flags) Type() string {
+	return "[]uint32"
+}
+
+// VerdictFlags is a slice of event verdicts with some convenience methods.
+type VerdictFlags []string
+
+var _ pflag.Value = &VerdictFlags{}
+
+// String provides a human-readable string format of the received variable.
+func (v *VerdictFlags) String() string {
+	return strings.Join(*v, ", ")
+}
+
+// Set validates the specified verdict and appends it to the flags.
+func (v *VerdictFlags) Set(value string) error {
+	value = strings.ToLower(value)
+	for _, verdict := range monitor.GetAllVerdicts() {
+		if verdict == value {
+			*v = append(*v, value)
+			return nil
 		}
 	}
+	return fmt.Errorf("unknown verdict %q, must be one of %v", value, monitor.GetAllVerdicts())
+}
 
-	return false
+// Type returns a human-readable string representing the type of the receiver.
+func (v *VerdictFlags) Type() string {
+	return "[]string"
 }
@@ -18,7 +18,9 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/gob"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/cilium/cilium/pkg/byteorder"
 	"github.com/cilium/cilium/pkg/logging"
@@ -50,46 +52,58 @@ const (
 
 // MonitorFormatter filters and formats monitor messages from a buffer.
 type MonitorFormatter struct {
-	EventTypes monitor.MessageTypeFilter
-	FromSource Uint16Flags
-	ToDst      Uint16Flags
-	Related    Uint16Flags
-	Verbose    bool
-	Hex        bool
-	JSONOutput bool
-	Verbosity  Verbosity
+	EventTypes   monitor.MessageTypeFilter
+	FromSource   Uint16Flags
+	ToDst        Uint16Flags
+	Related      Uint16Flags
+	FromIdentity Uint32Flags
+	Verdicts     VerdictFlags
+	Verbose      bool
+	Hex          bool
+	JSONOutput   bool
+	Verbosity    Verbosity
 }
 
 // NewMonitorFormatter returns a new formatter with default configuration.
 func NewMonitorFormatter(verbosity Verbosity) *MonitorFormatter {
 	return &MonitorFormatter{
-		Hex:        false,
-		EventTypes: monitor.MessageTypeFilter{},
-		FromSource: Uint16Flags{},
-		ToDst:      Uint16Flags{},
-		Related:    Uint16Flags{},
-		Verbose:    false,
-		JSONOutput: false,
-		Verbosity:  verbosity,
+		Hex:          false,
+		EventTypes:   monitor.MessageTypeFilter{},
+		FromSource:   Uint16Flags{},
+		ToDst:        Uint16Flags{},
+		Related:      Uint16Flags{},
+		FromIdentity: Uint32Flags{},
+		Verdicts:     VerdictFlags{},
+		Verbose:      false,
+		JSONOutput:   false,
+		Verbosity:    verbosity,
 	}
 }
 
-// match checks if the event type, from endpoint and / or to endpoint match
-// when they are supplied. The either part of from and to endpoint depends on
-// related to, which can match on both.  If either one of them is less than or
-// equal to zero, then it is assumed user did not use them.
-func (m *MonitorFormatter) match(messageType int, src uint16, dst uint16) bool {
-	if len(m.EventTypes) > 0 && !m.EventTypes.Contains(messageType) {
-		return false
-	} else if len(m.FromSource) > 0 && !m.FromSource.has(src) {
-		return false
-	} else if len(m.ToDst) > 0 && !m.ToDst.has(dst) {
-		return false
-	} else if len(m.Related) > 0 && !m.Related.has(src) && !m.Related.has(dst) {
-		return false
+// Filter returns the event filter configured in the formatter. It is sent to
+// the node monitor so that only matching events are transmitted.
+func (m *MonitorFormatter) Filter() *monitor.EventFilter {
+	return &monitor.EventFilter{
+		Types:        m.EventTypes,
+		FromSource:   m.FromSource,
+		ToDst:        m.ToDst,
+		Related:      m.Related,
+		FromIdentity: m.FromIdentity,
+		Verdicts:     m.Verdicts,
 	}
+}
 
-	return true
+// match returns true if the event passes the configured filter. Node
+// monitors which predate the v1.3 protocol send all events, so events
+// are filtered again before they are printed.
+func (m *MonitorFormatter) match(messageType int, src uint16, dst uint16, srcIdentity uint32, verdict string) bool {
+	return m.Filter().Match(messageType, src, dst, srcIdentity, verdict)
+}
+
+// matchType returns true if events of the given message type pass the event
+// type filter. It is evaluated before an event is decoded.
+func (m *MonitorFormatter) matchType(messageType int) bool {
+	return m.Filter().MatchType(messageType)
 }
 
 // dropEvents prints out all the received drop notifications.
@@ -99,7 +113,7 @@ func (m *MonitorFormatter) dropEvents(prefix string, data []byte) {
 	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dn); err != nil {
 		fmt.Printf("Error while parsing drop notification message: %s\n", err)
 	}
-	if m.match(monitor.MessageTypeDrop, dn.Source, uint16(dn.DstID)) {
+	if m.match(monitor.MessageTypeDrop, dn.Source, uint16(dn.DstID), dn.SrcLabel, monitor.VerdictDropped) {
 		switch m.Verbosity {
 		case INFO:
 			dn.DumpInfo(data)
@@ -119,7 +133,7 @@ func (m *MonitorFormatter) traceEvents(prefix string, data []byte) {
 	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &tn); err != nil {
 		fmt.Printf("Error while parsing trace notification message: %s\n", err)
 	}
-	if m.match(monitor.MessageTypeTrace, tn.Source, tn.DstID) {
+	if m.match(monitor.MessageTypeTrace, tn.Source, tn.DstID, tn.SrcLabel, monitor.VerdictForwarded) {
 		switch m.Verbosity {
 		case INFO:
 			tn.DumpInfo(data)
@@ -139,7 +153,7 @@ func (m *MonitorFormatter) debugEvents(prefix string, data []byte) {
 	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dm); err != nil {
 		fmt.Printf("Error while parsing debug message: %s\n", err)
 	}
-	if m.match(monitor.MessageTypeDebug, dm.Source, 0) {
+	if m.match(monitor.MessageTypeDebug, dm.Source, 0, 0, "") {
 		switch m.Verbosity {
 		case INFO:
 			dm.DumpInfo(data)
@@ -158,7 +172,7 @@ func (m *MonitorFormatter) captureEvents(prefix string, data []byte) {
 	if err := binary.Read(bytes.NewReader(data), byteorder.Native, &dc); err != nil {
 		fmt.Printf("Error while parsing debug capture message: %s\n", err)
 	}
-	if m.match(monitor.MessageTypeCapture, dc.Source, 0) {
+	if m.match(monitor.MessageTypeCapture, dc.Source, 0, 0, "") {
 		switch m.Verbosity {
 		case INFO:
 			dc.DumpInfo(data)
@@ -181,7 +195,8 @@ func (m *MonitorFormatter) logRecordEvents(prefix string, data []byte) {
 		fmt.Printf("Error while decoding LogRecord notification message: %s\n", err)
 	}
 
-	if m.match(monitor.MessageTypeAccessLog, uint16(lr.SourceEndpoint.ID), uint16(lr.DestinationEndpoint.ID)) {
+	if m.match(monitor.MessageTypeAccessLog, uint16(lr.SourceEndpoint.ID), uint16(lr.DestinationEndpoint.ID),
+		uint32(lr.SourceEndpoint.Identity), strings.ToLower(string(lr.Verdict))) {
 		if m.Verbosity == JSON {
 			lr.DumpJSON()
 		} else {
@@ -200,7 +215,7 @@ func (m *MonitorFormatter) agentEvents(prefix string, data []byte) {
 		fmt.Printf("Error while decoding agent notification message: %s\n", err)
 	}
 
-	if m.match(monitor.MessageTypeAgent, 0, 0) {
+	if m.match(monitor.MessageTypeAgent, 0, 0, 0, "") {
 		if m.Verbosity == JSON {
 			an.DumpJSON()
 		} else {
@@ -218,6 +233,11 @@ func (m *MonitorFormatter) FormatSample(data []byte, cpu int) {
 	prefix := fmt.Sprintf("CPU %02d:", cpu)
 	messageType := data[0]
 
+	// Skip decoding events which are filtered out by type anyway
+	if !m.matchType(int(messageType)) {
+		return
+	}
+
 	switch messageType {
 	case monitor.MessageTypeDrop:
 		m.dropEvents(prefix, data)
@@ -241,6 +261,28 @@ func LostEvent(lost uint64, cpu int) {
 	fmt.Printf("CPU %02d: Lost %d events\n", cpu, lost)
 }
 
+// lostEvent formats a lost event, as a JSON object if JSON output is
+// requested.
+func (m *MonitorFormatter) lostEvent(lost uint64, cpu int) {
+	if m.Verbosity != JSON {
+		LostEvent(lost, cpu)
+		return
+	}
+
+	ret, err := json.Marshal(struct {
+		CPUPrefix string `json:"cpu"`
+		Type      string `json:"type"`
+		Lost      uint64 `json:"lost"`
+	}{
+		CPUPrefix: fmt.Sprintf("CPU %02d:", cpu),
+		Type:      "lost",
+		Lost:      lost,
+	})
+	if err == nil {
+		fmt.Println(string(ret))
+	}
+}
+
 // FormatEvent formats an event from the specified payload to stdout.
 //
 // Returns true if the event was successfully printed, false otherwise.
@@ -249,7 +291,7 @@ func (m *MonitorFormatter) FormatEvent(pl *payload.Payload) bool {
 	case payload.EventSample:
 		m.FormatSample(pl.Data, pl.CPU)
 	case payload.RecordLost:
-		LostEvent(pl.Lost, pl.CPU)
+		m.lostEvent(pl.Lost, pl.CPU)
 	default:
 		return false
 	}
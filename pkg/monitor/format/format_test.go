@@ -0,0 +1,72 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"testing"
+
+	"github.com/cilium/cilium/pkg/monitor"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type FormatSuite struct{}
+
+var _ = Suite(&FormatSuite{})
+
+func (s *FormatSuite) TestMatch(c *C) {
+	m := NewMonitorFormatter(INFO)
+	c.Assert(m.match(monitor.MessageTypeTrace, 1, 2, 100, monitor.VerdictForwarded), Equals, true)
+	c.Assert(m.match(monitor.MessageTypeDebug, 1, 0, 0, ""), Equals, true)
+
+	c.Assert(m.EventTypes.Set("drop"), IsNil)
+	c.Assert(m.matchType(monitor.MessageTypeDrop), Equals, true)
+	c.Assert(m.matchType(monitor.MessageTypeTrace), Equals, false)
+	c.Assert(m.match(monitor.MessageTypeTrace, 1, 2, 100, monitor.VerdictForwarded), Equals, false)
+
+	m = NewMonitorFormatter(INFO)
+	c.Assert(m.FromIdentity.Set("100"), IsNil)
+	c.Assert(m.match(monitor.MessageTypeDrop, 1, 2, 100, monitor.VerdictDropped), Equals, true)
+	c.Assert(m.match(monitor.MessageTypeDrop, 1, 2, 101, monitor.VerdictDropped), Equals, false)
+	c.Assert(m.match(monitor.MessageTypeDebug, 1, 0, 0, ""), Equals, false)
+
+	m = NewMonitorFormatter(INFO)
+	c.Assert(m.ToDst.Set("2"), IsNil)
+	c.Assert(m.Verdicts.Set("Dropped"), IsNil)
+	c.Assert(m.Verdicts.Set("denied"), IsNil)
+	c.Assert(m.match(monitor.MessageTypeDrop, 1, 2, 100, monitor.VerdictDropped), Equals, true)
+	c.Assert(m.match(monitor.MessageTypeAccessLog, 1, 2, 100, monitor.VerdictDenied), Equals, true)
+	c.Assert(m.match(monitor.MessageTypeAccessLog, 1, 3, 100, monitor.VerdictDenied), Equals, false)
+	c.Assert(m.match(monitor.MessageTypeTrace, 1, 2, 100, monitor.VerdictForwarded), Equals, false)
+	c.Assert(m.match(monitor.MessageTypeAgent, 0, 0, 0, ""), Equals, false)
+}
+
+func (s *FormatSuite) TestFlags(c *C) {
+	var ids Uint32Flags
+	c.Assert(ids.Set("4294967295"), IsNil)
+	c.Assert(ids.Set("4294967296"), Not(IsNil))
+	c.Assert(ids.Set("foo"), Not(IsNil))
+	c.Assert(ids.String(), Equals, "4294967295")
+
+	var verdicts VerdictFlags
+	c.Assert(verdicts.Set("FORWARDED"), IsNil)
+	c.Assert(verdicts.Set("accepted"), Not(IsNil))
+	c.Assert(verdicts.String(), Equals, "forwarded")
+}
@@ -17,6 +17,8 @@ package monitor
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/cilium/cilium/pkg/proxy/accesslog"
 )
@@ -46,6 +48,10 @@ func (l *LogRecordNotify) l7Proto() string {
 		return "kafka"
 	}
 
+	if l.DNS != nil {
+		return "dns"
+	}
+
 	if l.L7 != nil {
 		return l.L7.Proto
 	}
@@ -61,6 +67,10 @@ func (l *LogRecordNotify) DumpInfo() {
 		l.SourceEndpoint.Identity, l.DestinationEndpoint.Identity,
 		l.Verdict)
 
+	if l.Latency != 0 {
+		fmt.Printf(", latency %s", l.Latency)
+	}
+
 	if http := l.HTTP; http != nil {
 		url := ""
 		if http.URL != nil {
@@ -74,6 +84,10 @@ func (l *LogRecordNotify) DumpInfo() {
 		fmt.Printf(" %s topic %s => %d\n", kafka.APIKey, kafka.Topic.Topic, kafka.ErrorCode)
 	}
 
+	if dns := l.DNS; dns != nil {
+		fmt.Printf(" %s %s => %d %v\n", dns.Query, strings.Join(dns.QueryTypes, ","), dns.Rcode, dns.IPs)
+	}
+
 	if l7 := l.L7; l7 != nil {
 		status := ""
 		for k, v := range l7.Fields {
@@ -118,8 +132,10 @@ type LogRecordNotifyVerbose struct {
 	DstEpLabels      []string                   `json:"dstEpLabels"`
 	DstIdentity      uint64                     `json:"dstIdentity"`
 	Verdict          accesslog.FlowVerdict      `json:"verdict"`
+	Latency          time.Duration              `json:"latency,omitempty"`
 	HTTP             *accesslog.LogRecordHTTP   `json:"http,omitempty"`
 	Kafka            *accesslog.LogRecordKafka  `json:"kafka,omitempty"`
+	DNS              *accesslog.LogRecordDNS    `json:"dns,omitempty"`
 	L7               *accesslog.LogRecordL7     `json:"l7,omitempty"`
 }
 
@@ -137,8 +153,10 @@ func LogRecordNotifyToVerbose(n *LogRecordNotify) LogRecordNotifyVerbose {
 		DstEpLabels:      n.DestinationEndpoint.Labels,
 		DstIdentity:      n.DestinationEndpoint.Identity,
 		Verdict:          n.Verdict,
+		Latency:          n.Latency,
 		HTTP:             n.HTTP,
 		Kafka:            n.Kafka,
+		DNS:              n.DNS,
 		L7:               n.L7,
 	}
 }
@@ -18,4 +18,7 @@ package notifications
 type RegenNotificationInfo interface {
 	GetID() uint64
 	GetOpLabels() []string
+	GetK8sNamespace() string
+	GetK8sPodName() string
+	GetContainerRuntime() string
 }
@@ -27,6 +27,10 @@ const (
 	// in /proc/pid/mountinfo
 	FilesystemTypeBPFFS = "bpf"
 
+	// FilesystemTypeCgroup2 is a filesystem type name for the cgroup2
+	// unified hierarchy which is used in /proc/pid/mountinfo
+	FilesystemTypeCgroup2 = "cgroup2"
+
 	mountInfoFilepath = "/proc/self/mountinfo"
 )
 
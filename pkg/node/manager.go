@@ -280,6 +280,13 @@ func UpdateNode(n *Node, routesTypes RouteType, ownAddr net.IP) {
 		updateIPRoute(oldNode, n, ownAddr)
 	}
 
+	if !n.IsLocal() {
+		if oldNodeExists && !oldNode.GetNodeIP(false).Equal(n.GetNodeIP(false)) {
+			neighbors.remove(oldNode.GetNodeIP(false))
+		}
+		neighbors.upsert(n.GetNodeIP(false))
+	}
+
 	clusterConf.nodes[ni] = n
 	clusterConf.replaceHostRoutes()
 }
@@ -308,6 +315,7 @@ func DeleteNode(ni Identity, routesTypes RouteType) {
 		if (routesTypes & DirectRoute) != 0 {
 			deleteIPRoute(n)
 		}
+		neighbors.remove(n.GetNodeIP(false))
 		delete(clusterConf.nodes, ni)
 		clusterConf.replaceHostRoutes()
 	}
@@ -0,0 +1,258 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/pkg/controller"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/neighmap"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// neighborRefreshInterval is the interval between two resolutions of
+	// the next hops towards all nodes
+	neighborRefreshInterval = 30 * time.Second
+
+	// validNeighStates are the states of kernel neighbor entries whose MAC
+	// address can be used
+	validNeighStates = netlink.NUD_REACHABLE | netlink.NUD_STALE |
+		netlink.NUD_DELAY | netlink.NUD_PROBE | netlink.NUD_PERMANENT
+)
+
+// nodeNeighbor is the next hop towards a node on the native device
+type nodeNeighbor struct {
+	nextHop net.IP
+	mac     net.HardwareAddr
+}
+
+// neighborTable maintains the BPF map of the MAC addresses of the next hops
+// towards other nodes on the native device. Entries are taken from the
+// neighbor table of the kernel and kept when the kernel entries expire so
+// that the datapath does not depend on a warm ARP cache.
+type neighborTable struct {
+	mutex       lock.Mutex
+	linkIndex   int
+	nodes       map[string]*nodeNeighbor
+	controllers *controller.Manager
+}
+
+var neighbors = &neighborTable{
+	nodes:       map[string]*nodeNeighbor{},
+	controllers: controller.NewManager(),
+}
+
+// neighMAC returns the MAC address of ip in neighs or nil if the kernel has
+// not resolved it.
+func neighMAC(neighs []netlink.Neigh, ip net.IP) net.HardwareAddr {
+	for _, n := range neighs {
+		if n.IP.Equal(ip) && n.State&validNeighStates != 0 && len(n.HardwareAddr) == 6 {
+			return n.HardwareAddr
+		}
+	}
+	return nil
+}
+
+// EnableNeighborTable starts maintaining the BPF neighbor table of the nodes
+// reachable via device.
+func EnableNeighborTable(device string) error {
+	link, err := netlink.LinkByName(device)
+	if err != nil {
+		return fmt.Errorf("unable to find device %s: %s", device, err)
+	}
+	if _, err := neighmap.NeighMap.OpenOrCreate(); err != nil {
+		return fmt.Errorf("unable to open %s: %s", neighmap.MapName, err)
+	}
+
+	neighbors.mutex.Lock()
+	neighbors.linkIndex = link.Attrs().Index
+	neighbors.mutex.Unlock()
+
+	for _, n := range GetNodes() {
+		if !n.IsLocal() {
+			neighbors.upsert(n.GetNodeIP(false))
+		}
+	}
+
+	neighbors.controllers.UpdateController("node-neighbor-refresh",
+		controller.ControllerParams{
+			DoFunc:      neighbors.refresh,
+			RunInterval: neighborRefreshInterval,
+		})
+	go neighbors.watch()
+
+	return nil
+}
+
+// upsert starts tracking the next hop towards the node with the given IP
+func (t *neighborTable) upsert(ip net.IP) {
+	if ip.To4() == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.linkIndex == 0 {
+		return
+	}
+	n, ok := t.nodes[ip.String()]
+	if !ok {
+		n = &nodeNeighbor{}
+		t.nodes[ip.String()] = n
+	}
+	neighs, err := netlink.NeighList(t.linkIndex, netlink.FAMILY_V4)
+	if err == nil {
+		err = t.resolve(ip, n, neighs)
+	}
+	if err != nil {
+		log.WithError(err).WithField(logfields.IPAddr, ip).Debug("Unable to resolve next hop towards node")
+	}
+}
+
+// remove stops tracking the next hop towards the node with the given IP
+func (t *neighborTable) remove(ip net.IP) {
+	if ip.To4() == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.nodes[ip.String()]; !ok {
+		return
+	}
+	delete(t.nodes, ip.String())
+	if err := neighmap.Delete(ip); err != nil {
+		log.WithError(err).WithField(logfields.IPAddr, ip).Debug("Unable to delete node neighbor")
+	}
+}
+
+// resolve looks up the next hop towards the node with the given IP and
+// updates the BPF map with its MAC address found in neighs, the kernel
+// neighbor entries of the device. If the kernel has not resolved the MAC
+// address, a resolution is triggered and the previous MAC address is kept.
+// t.mutex must be held.
+func (t *neighborTable) resolve(ip net.IP, n *nodeNeighbor, neighs []netlink.Neigh) error {
+	routes, err := netlink.RouteGet(ip)
+	if err != nil {
+		return err
+	}
+	if len(routes) == 0 || routes[0].LinkIndex != t.linkIndex {
+		// The node is not reachable via the device, let the
+		// stack route the traffic.
+		if n.mac != nil {
+			n.nextHop, n.mac = nil, nil
+			return neighmap.Delete(ip)
+		}
+		return nil
+	}
+
+	n.nextHop = ip
+	if routes[0].Gw != nil {
+		n.nextHop = routes[0].Gw
+	}
+
+	mac := neighMAC(neighs, n.nextHop)
+	if mac == nil {
+		return netlink.NeighSet(&netlink.Neigh{
+			LinkIndex: t.linkIndex,
+			Family:    netlink.FAMILY_V4,
+			IP:        n.nextHop,
+			Flags:     netlink.NTF_USE,
+		})
+	}
+	return t.update(ip, n, mac)
+}
+
+// update sets the MAC address of the next hop towards the node with the
+// given IP. t.mutex must be held.
+func (t *neighborTable) update(ip net.IP, n *nodeNeighbor, mac net.HardwareAddr) error {
+	if bytes.Equal(n.mac, mac) {
+		return nil
+	}
+	log.WithFields(logrus.Fields{
+		logfields.IPAddr: ip,
+		"nextHop":        n.nextHop,
+		"mac":            mac,
+	}).Debug("Updating node neighbor")
+
+	if err := neighmap.Update(ip, mac); err != nil {
+		return err
+	}
+	n.mac = mac
+	return nil
+}
+
+// refresh resolves the next hops towards all nodes. The kernel neighbor
+// entries of the device are dumped once for all nodes.
+func (t *neighborTable) refresh() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	neighs, err := netlink.NeighList(t.linkIndex, netlink.FAMILY_V4)
+	if err != nil {
+		return fmt.Errorf("unable to list neighbors: %s", err)
+	}
+
+	failed := 0
+	for ip, n := range t.nodes {
+		if err := t.resolve(net.ParseIP(ip), n, neighs); err != nil {
+			log.WithError(err).WithField(logfields.IPAddr, ip).Debug("Unable to resolve next hop towards node")
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("unable to resolve the next hop towards %d of %d nodes", failed, len(t.nodes))
+	}
+	return nil
+}
+
+// watch updates the BPF map as soon as the kernel resolves a next hop
+func (t *neighborTable) watch() {
+	updates := make(chan netlink.NeighUpdate)
+	if err := netlink.NeighSubscribe(updates, nil); err != nil {
+		log.WithError(err).Warn("Unable to subscribe to neighbor updates, relying on periodic refresh")
+		return
+	}
+
+	for u := range updates {
+		if u.Type != unix.RTM_NEWNEIGH || u.State&validNeighStates == 0 || len(u.HardwareAddr) != 6 {
+			continue
+		}
+
+		t.mutex.Lock()
+		if u.LinkIndex == t.linkIndex {
+			for ip, n := range t.nodes {
+				if !u.IP.Equal(n.nextHop) {
+					continue
+				}
+				if err := t.update(net.ParseIP(ip), n, u.HardwareAddr); err != nil {
+					log.WithError(err).WithField(logfields.IPAddr, ip).Warn("Unable to update node neighbor")
+				}
+			}
+		}
+		t.mutex.Unlock()
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"net"
+
+	"github.com/vishvananda/netlink"
+	. "gopkg.in/check.v1"
+)
+
+func (s *NodeSuite) TestNeighMAC(c *C) {
+	mac1, _ := net.ParseMAC("02:42:ac:11:00:01")
+	mac2, _ := net.ParseMAC("02:42:ac:11:00:02")
+	neighs := []netlink.Neigh{
+		{IP: net.ParseIP("10.0.0.1"), HardwareAddr: mac1, State: netlink.NUD_REACHABLE},
+		{IP: net.ParseIP("10.0.0.2"), HardwareAddr: mac2, State: netlink.NUD_STALE},
+		{IP: net.ParseIP("10.0.0.3"), State: netlink.NUD_INCOMPLETE},
+		{IP: net.ParseIP("10.0.0.4"), HardwareAddr: mac2, State: netlink.NUD_FAILED},
+	}
+
+	c.Assert(neighMAC(neighs, net.ParseIP("10.0.0.1")), DeepEquals, net.HardwareAddr(mac1))
+	c.Assert(neighMAC(neighs, net.ParseIP("10.0.0.2")), DeepEquals, net.HardwareAddr(mac2))
+	c.Assert(neighMAC(neighs, net.ParseIP("10.0.0.3")), IsNil)
+	c.Assert(neighMAC(neighs, net.ParseIP("10.0.0.4")), IsNil)
+	c.Assert(neighMAC(neighs, net.ParseIP("10.0.0.5")), IsNil)
+}
+
+func (s *NodeSuite) TestNeighborTableDisabled(c *C) {
+	// Nodes are not tracked until the table has been enabled
+	neighbors.upsert(net.ParseIP("10.0.0.1"))
+	c.Assert(neighbors.nodes, HasLen, 0)
+	neighbors.remove(net.ParseIP("10.0.0.1"))
+}
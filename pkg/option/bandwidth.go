@@ -0,0 +1,97 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package option
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// BandwidthMin is the minimum bandwidth limit in bits per second
+	BandwidthMin = 1000
+
+	// BandwidthMax is the maximum bandwidth limit in bits per second. The
+	// datapath token bucket overflows for larger values.
+	BandwidthMax = 100 * 1000 * 1000 * 1000
+)
+
+// bandwidthUnits maps the unit suffixes of a bandwidth to their multiplier.
+// The suffixes are the same as used by the kubernetes.io/egress-bandwidth
+// annotation.
+var bandwidthUnits = []struct {
+	suffix     string
+	multiplier OptionSetting
+}{
+	{"G", 1000 * 1000 * 1000},
+	{"M", 1000 * 1000},
+	{"K", 1000},
+}
+
+// VerifyBandwidth validates the specified key/value for a bandwidth limit.
+func VerifyBandwidth(key, value string) error {
+	_, err := ParseBandwidth(value)
+	return err
+}
+
+// ParseBandwidth turns a string into a bandwidth limit in bits per second.
+// The string contains an integer value with an optional unit suffix, e.g.
+// "10M" for 10 Mbit/s. A value of "0" or "disabled" removes the limit.
+func ParseBandwidth(value string) (OptionSetting, error) {
+	switch strings.ToLower(value) {
+	case "", "0", "disabled", "none":
+		return OptionDisabled, nil
+	}
+
+	number := strings.ToUpper(value)
+	multiplier := OptionSetting(1)
+	for _, unit := range bandwidthUnits {
+		if strings.HasSuffix(number, unit.suffix) {
+			number = strings.TrimSuffix(number, unit.suffix)
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseUint(number, 10, 32)
+	if err != nil {
+		return OptionDisabled, fmt.Errorf("Invalid bandwidth %q", value)
+	}
+
+	bandwidth := OptionSetting(n) * multiplier
+	if bandwidth < BandwidthMin || bandwidth > BandwidthMax {
+		return OptionDisabled, fmt.Errorf("Bandwidth must be between %s and %s",
+			FormatBandwidth(BandwidthMin), FormatBandwidth(BandwidthMax))
+	}
+
+	return bandwidth, nil
+}
+
+// FormatBandwidth formats a bandwidth limit in bits per second using the
+// largest unit which represents it exactly.
+func FormatBandwidth(bandwidth OptionSetting) string {
+	if bandwidth == OptionDisabled {
+		return "Disabled"
+	}
+
+	for _, unit := range bandwidthUnits {
+		if bandwidth%unit.multiplier == 0 {
+			return strconv.Itoa(int(bandwidth/unit.multiplier)) + unit.suffix
+		}
+	}
+
+	return strconv.Itoa(int(bandwidth))
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package option
+
+import (
+	. "gopkg.in/check.v1"
+)
+
+func (s *OptionSuite) TestParseBandwidth(c *C) {
+	for value, expected := range map[string]OptionSetting{
+		"":         OptionDisabled,
+		"0":        OptionDisabled,
+		"disabled": OptionDisabled,
+		"1500":     1500,
+		"100k":     100 * 1000,
+		"10M":      10 * 1000 * 1000,
+		"1G":       1000 * 1000 * 1000,
+		"100G":     BandwidthMax,
+	} {
+		bandwidth, err := ParseBandwidth(value)
+		c.Assert(err, IsNil, Commentf("value %q", value))
+		c.Assert(bandwidth, Equals, expected, Commentf("value %q", value))
+	}
+
+	for _, value := range []string{"foo", "-1M", "10T", "999", "101G", "1.5M"} {
+		_, err := ParseBandwidth(value)
+		c.Assert(err, Not(IsNil), Commentf("value %q", value))
+	}
+}
+
+func (s *OptionSuite) TestFormatBandwidth(c *C) {
+	c.Assert(FormatBandwidth(OptionDisabled), Equals, "Disabled")
+	c.Assert(FormatBandwidth(1500), Equals, "1500")
+	c.Assert(FormatBandwidth(100*1000), Equals, "100K")
+	c.Assert(FormatBandwidth(10*1000*1000), Equals, "10M")
+	c.Assert(FormatBandwidth(1500*1000*1000), Equals, "1500M")
+}
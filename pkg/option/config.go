@@ -19,7 +19,11 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/common"
@@ -116,6 +120,10 @@ const (
 	// BPFCompileDebugName is the name of the option to enable BPF compiliation debugging
 	BPFCompileDebugName = "bpf-compile-debug"
 
+	// BPFCompileTemplatesName is the name of the option to compile endpoint
+	// BPF programs from per-configuration templates
+	BPFCompileTemplatesName = "bpf-compile-templates"
+
 	// CTMapEntriesGlobalTCP retains the Cilium 1.2 (or earlier) size to
 	// minimize disruption during upgrade.
 	CTMapEntriesGlobalTCPDefault = 1000000
@@ -125,6 +133,49 @@ const (
 	CTMapEntriesGlobalTCPNameEnv = "CILIUM_GLOBAL_CT_MAX_TCP"
 	CTMapEntriesGlobalAnyNameEnv = "CILIUM_GLOBAL_CT_MAX_ANY"
 
+	// CTTimeoutTCPName is the name of the option to set the lifetime in
+	// seconds of established TCP connections in the CT table
+	CTTimeoutTCPName    = "ct-timeout-tcp"
+	CTTimeoutTCPNameEnv = "CILIUM_CT_TIMEOUT_TCP"
+	CTTimeoutTCPDefault = 21600 // 6 hours
+
+	// CTTimeoutTCPSynName is the name of the option to set the lifetime in
+	// seconds of TCP connections in the CT table which have only seen SYN
+	// packets
+	CTTimeoutTCPSynName    = "ct-timeout-tcp-syn"
+	CTTimeoutTCPSynNameEnv = "CILIUM_CT_TIMEOUT_TCP_SYN"
+	CTTimeoutTCPSynDefault = 60
+
+	// CTTimeoutUDPName is the name of the option to set the lifetime in
+	// seconds of UDP and other non-TCP flows in the CT table
+	CTTimeoutUDPName    = "ct-timeout-udp"
+	CTTimeoutUDPNameEnv = "CILIUM_CT_TIMEOUT_UDP"
+	CTTimeoutUDPDefault = 60
+
+	// CTTimeoutICMPName is the name of the option to set the lifetime in
+	// seconds of ICMP flows in the CT table
+	CTTimeoutICMPName    = "ct-timeout-icmp"
+	CTTimeoutICMPNameEnv = "CILIUM_CT_TIMEOUT_ICMP"
+	CTTimeoutICMPDefault = 60
+
+	// PolicyMapEntriesName is the name of the option to set the maximum
+	// number of entries in each endpoint policy map
+	PolicyMapEntriesName    = "bpf-policy-map-max"
+	PolicyMapEntriesNameEnv = "CILIUM_POLICY_MAP_MAX"
+	PolicyMapEntriesDefault = 16384
+
+	// LXCMapEntriesName is the name of the option to set the maximum
+	// number of entries in the endpoint map
+	LXCMapEntriesName    = "bpf-lxc-map-max"
+	LXCMapEntriesNameEnv = "CILIUM_LXC_MAP_MAX"
+	LXCMapEntriesDefault = 65535
+
+	// LBMapEntriesName is the name of the option to set the maximum number
+	// of entries in the load balancer service and reverse NAT maps
+	LBMapEntriesName    = "bpf-lb-map-max"
+	LBMapEntriesNameEnv = "CILIUM_LB_MAP_MAX"
+	LBMapEntriesDefault = 65536
+
 	// LogSystemLoadConfigName is the name of the option to enable system
 	// load loggging
 	LogSystemLoadConfigName = "log-system-load"
@@ -141,10 +192,141 @@ const (
 	// use of the CEP CRD
 	DisableCiliumEndpointCRDName = "disable-endpoint-crd"
 
+	// EndpointHooksName is the name of the option to specify the hooks
+	// invoked on endpoint lifecycle events
+	EndpointHooksName = "endpoint-hooks"
+
 	// MaxCtrlIntervalName and MaxCtrlIntervalNameEnv allow configuration
 	// of MaxControllerInterval.
 	MaxCtrlIntervalName    = "max-controller-interval"
 	MaxCtrlIntervalNameEnv = "CILIUM_MAX_CONTROLLER_INTERVAL"
+
+	// PrometheusServeAddrName is the name of the option to serve
+	// prometheus metrics
+	PrometheusServeAddrName = "prometheus-serve-addr"
+
+	// PrometheusServeAddrNameEnv is the name of the environment variable
+	// of the PrometheusServeAddrName option
+	PrometheusServeAddrNameEnv = "CILIUM_PROMETHEUS_SERVE_ADDR"
+
+	// ProxyTraceCollectorName is the name of the option to specify the
+	// address of the Zipkin compatible collector receiving proxy spans
+	ProxyTraceCollectorName = "proxy-trace-collector"
+
+	// ProxyTraceCollectorNameEnv is the name of the environment variable
+	// of the ProxyTraceCollectorName option
+	ProxyTraceCollectorNameEnv = "CILIUM_PROXY_TRACE_COLLECTOR"
+
+	// ProxyTraceSamplingName is the name of the option to specify the
+	// percentage of requests without trace context for which the proxy
+	// starts a new trace
+	ProxyTraceSamplingName = "proxy-trace-sampling"
+
+	// ProxyLuaScriptName is the name of the option to specify the path
+	// of a Lua script run by the HTTP proxy on allowed requests
+	ProxyLuaScriptName = "proxy-lua-script"
+
+	// ProxyLuaScriptNameEnv is the name of the environment variable of
+	// the ProxyLuaScriptName option
+	ProxyLuaScriptNameEnv = "CILIUM_PROXY_LUA_SCRIPT"
+
+	// ProxyTLSDirName is the name of the option to specify the directory
+	// containing the files referred to by TLS contexts of policy rules
+	ProxyTLSDirName = "proxy-tls-dir"
+
+	// ProxyTransparentName is the name of the option to preserve the
+	// source address of connections forwarded by ingress L7 proxies
+	ProxyTransparentName = "proxy-transparent"
+
+	// ProxyDrainTimeoutName is the name of the option to specify the time
+	// in seconds during which removed proxy redirects keep serving their
+	// existing connections
+	ProxyDrainTimeoutName = "proxy-drain-timeout"
+
+	// WatchdogRSSBudgetName is the name of the option to specify the
+	// maximum resident set size of the agent in MiB
+	WatchdogRSSBudgetName = "watchdog-rss-budget"
+
+	// WatchdogGoroutineBudgetName is the name of the option to specify the
+	// maximum number of goroutines of the agent
+	WatchdogGoroutineBudgetName = "watchdog-goroutine-budget"
+
+	// WatchdogMapMemoryBudgetName is the name of the option to specify the
+	// maximum memory of all BPF maps opened by the agent in MiB
+	WatchdogMapMemoryBudgetName = "watchdog-map-memory-budget"
+
+	// WatchdogActionsName is the name of the option to specify the actions
+	// taken when a watchdog budget is exceeded
+	WatchdogActionsName = "watchdog-actions"
+
+	// SockopsEnableName is the name of the option to enable the sockops
+	// acceleration of connections between local endpoints
+	SockopsEnableName = "sockops-enable"
+
+	// CgroupRootName is the name of the option to specify the root of the
+	// cgroup2 hierarchy the sockops programs are attached to
+	CgroupRootName = "cgroup-root"
+
+	// BPFMapCheckIntervalName is the name of the option to specify the
+	// interval in seconds between two consistency checks of BPF maps
+	BPFMapCheckIntervalName = "bpf-map-check-interval"
+
+	// BPFMapEventsName is the name of the option to journal the most
+	// recent mutations of BPF maps
+	BPFMapEventsName = "bpf-map-events"
+
+	// BPFMapRepairName is the name of the option to restore entries of BPF
+	// maps which differ from the desired state
+	BPFMapRepairName = "bpf-map-repair"
+
+	// BPFMasqueradeName is the name of the option to masquerade traffic
+	// leaving the node in BPF instead of iptables
+	BPFMasqueradeName = "bpf-masquerade"
+
+	// BPFPinPrefixName is the name of the option to pin all BPF objects in
+	// a subdirectory of the BPF filesystem
+	BPFPinPrefixName = "bpf-pin-prefix"
+
+	// BPFPinPrefixEnv is the name of the environment variable of the
+	// BPFPinPrefixName option
+	BPFPinPrefixEnv = "CILIUM_BPF_PIN_PREFIX"
+
+	// MonitorNumPagesName is the name of the option to specify the number
+	// of pages of each per-CPU perf ring buffer of the node monitor
+	MonitorNumPagesName = "monitor-num-pages"
+
+	// MonitorWakeupEventsName is the name of the option to specify the
+	// number of events after which the node monitor is woken up
+	MonitorWakeupEventsName = "monitor-wakeup-events"
+
+	// MonitorOverwriteName is the name of the option to overwrite the
+	// oldest events of full perf ring buffers
+	MonitorOverwriteName = "monitor-overwrite"
+
+	// NodeNeighborTableName is the name of the option to forward traffic
+	// to other nodes with the MAC addresses of a BPF neighbor table in
+	// direct routing mode
+	NodeNeighborTableName = "node-neighbor-table"
+
+	// AccessLogMaxSizeName is the name of the option to specify the size
+	// in megabytes after which the access log is rotated
+	AccessLogMaxSizeName = "access-log-max-size"
+
+	// AccessLogMaxBackupsName is the name of the option to specify the
+	// number of rotated access log files to retain
+	AccessLogMaxBackupsName = "access-log-max-backups"
+
+	// AccessLogMaxAgeName is the name of the option to specify the number
+	// of days to retain rotated access log files
+	AccessLogMaxAgeName = "access-log-max-age"
+
+	// AccessLogCompressName is the name of the option to compress rotated
+	// access log files
+	AccessLogCompressName = "access-log-compress"
+
+	// AccessLogSinkBufferSizeName is the name of the option to specify the
+	// number of records buffered per access log sink
+	AccessLogSinkBufferSizeName = "access-log-sink-buffer-size"
 )
 
 // Available option for daemonConfig.Tunnel
@@ -216,6 +398,10 @@ type daemonConfig struct {
 	// AccessLog is the path to the access log of supported L7 requests observed.
 	AccessLog string
 
+	// AccessLogSinks is the list of URLs of sinks to which access log
+	// records are streamed in addition to the access log file.
+	AccessLogSinks []string
+
 	// AgentLabels contains additional labels to identify this agent in monitor events.
 	AgentLabels []string
 
@@ -264,12 +450,83 @@ type daemonConfig struct {
 	// allowed in each non-TCP CT table for IPv4/IPv6.
 	CTMapEntriesGlobalAny int
 
+	// CTTimeoutTCP is the lifetime in seconds of established TCP
+	// connections in the CT table
+	CTTimeoutTCP int
+
+	// CTTimeoutTCPSyn is the lifetime in seconds of TCP connections in the
+	// CT table which have only seen SYN packets
+	CTTimeoutTCPSyn int
+
+	// CTTimeoutUDP is the lifetime in seconds of UDP and other non-TCP
+	// flows in the CT table
+	CTTimeoutUDP int
+
+	// CTTimeoutICMP is the lifetime in seconds of ICMP flows in the CT
+	// table
+	CTTimeoutICMP int
+
+	// PolicyMapEntries is the maximum number of entries in each endpoint
+	// policy map
+	PolicyMapEntries int
+
+	// LXCMapEntries is the maximum number of entries in the endpoint map
+	LXCMapEntries int
+
+	// LBMapEntries is the maximum number of entries in each load balancer
+	// service and reverse NAT map
+	LBMapEntries int
+
 	// DisableCiliumEndpointCRD disables the use of CiliumEndpoint CRD
 	DisableCiliumEndpointCRD bool
 
 	// MaxControllerInterval is the maximum value for a controller's
 	// RunInterval. Zero means unlimited.
 	MaxControllerInterval int
+
+	// ProxyTraceCollector is the host:port of the Zipkin compatible
+	// collector the L7 proxy reports spans to. Empty disables tracing.
+	ProxyTraceCollector string
+
+	// ProxyTraceSampling is the percentage of requests without trace
+	// context for which the L7 proxy starts a new trace.
+	ProxyTraceSampling int
+
+	// ProxyLuaScript is the path of the Lua script the HTTP proxy runs on
+	// the requests allowed by policy. Empty disables the script.
+	ProxyLuaScript string
+
+	// ProxyTLSDir is the directory containing the certificates, private
+	// keys and trusted CAs referred to by TLS contexts of policy rules.
+	ProxyTLSDir string
+
+	// ProxyTransparent is true if ingress L7 proxies connect to the
+	// destination from the original source address of the client.
+	ProxyTransparent bool
+
+	// ProxyDrainTimeout is the time during which a removed proxy redirect
+	// keeps serving its existing connections before it is closed.
+	ProxyDrainTimeout time.Duration
+
+	// EndpointHooks is the comma separated list of executables and http(s)
+	// URLs invoked on endpoint lifecycle events
+	EndpointHooks string
+
+	// WatchdogRSSBudget is the maximum resident set size in MiB of the
+	// agent before the watchdog takes action, 0 is off
+	WatchdogRSSBudget int
+
+	// WatchdogGoroutineBudget is the maximum number of goroutines of the
+	// agent before the watchdog takes action, 0 is off
+	WatchdogGoroutineBudget int
+
+	// WatchdogMapMemoryBudget is the maximum memory in MiB of all BPF maps
+	// before the watchdog takes action, 0 is off
+	WatchdogMapMemoryBudget int
+
+	// WatchdogActions is the comma separated list of actions taken in order
+	// when a watchdog budget is exceeded
+	WatchdogActions string
 }
 
 var (
@@ -336,6 +593,11 @@ func (c *daemonConfig) validateIPv6ClusterAllocCIDR() error {
 
 // Validate validates the daemon configuration
 func (c *daemonConfig) Validate() error {
+	if err := ValidateConfigSpecs(); err != nil {
+		return err
+	}
+	c.loadConfigSpecs()
+
 	if err := c.validateIPv6ClusterAllocCIDR(); err != nil {
 		return fmt.Errorf("unable to parse CIDR value '%s' of option --%s: %s",
 			c.IPv6ClusterAllocCIDR, IPv6ClusterAllocCIDRName, err)
@@ -345,25 +607,9 @@ func (c *daemonConfig) Validate() error {
 		return fmt.Errorf("MTU '%d' cannot be 0 or negative", c.MTU)
 	}
 
-	c.Tunnel = viper.GetString(TunnelName)
-	switch c.Tunnel {
-	case TunnelVXLAN, TunnelGeneve:
-	case TunnelDisabled:
-		if viper.GetBool(SingleClusterRouteName) {
-			return fmt.Errorf("option --%s cannot be used in combination with --%s=%s",
-				SingleClusterRouteName, TunnelName, TunnelDisabled)
-		}
-	default:
-		return fmt.Errorf("invalid tunnel mode '%s', valid modes = {%s}", c.Tunnel, GetTunnelModes())
-	}
-
-	c.ClusterName = viper.GetString(ClusterName)
-	c.ClusterID = viper.GetInt(ClusterIDName)
-	c.ClusterMeshConfig = viper.GetString(ClusterMeshConfigName)
-
-	if c.ClusterID < ClusterIDMin || c.ClusterID > ClusterIDMax {
-		return fmt.Errorf("invalid cluster id %d: must be in range %d..%d",
-			c.ClusterID, ClusterIDMin, ClusterIDMax)
+	if c.Tunnel == TunnelDisabled && viper.GetBool(SingleClusterRouteName) {
+		return fmt.Errorf("option --%s cannot be used in combination with --%s=%s",
+			SingleClusterRouteName, TunnelName, TunnelDisabled)
 	}
 
 	if c.ClusterID != 0 {
@@ -373,8 +619,11 @@ func (c *daemonConfig) Validate() error {
 		}
 	}
 
-	c.CTMapEntriesGlobalTCP = viper.GetInt(CTMapEntriesGlobalTCPName)
-	c.CTMapEntriesGlobalAny = viper.GetInt(CTMapEntriesGlobalAnyName)
+	if c.ProxyTransparent && c.IPv4Disabled {
+		return fmt.Errorf("option --%s requires IPv4", ProxyTransparentName)
+	}
+	c.ProxyDrainTimeout = time.Duration(viper.GetInt(ProxyDrainTimeoutName)) * time.Second
+
 	ctTableMin := 1 << 10 // 1Ki entries
 	ctTableMax := 1 << 24 // 16Mi entries (~1GiB of entries per map)
 	if c.CTMapEntriesGlobalTCP < ctTableMin || c.CTMapEntriesGlobalAny < ctTableMin {
@@ -386,5 +635,556 @@ func (c *daemonConfig) Validate() error {
 			c.CTMapEntriesGlobalTCP, c.CTMapEntriesGlobalAny, ctTableMax)
 	}
 
+	return c.ValidateMapSizes()
+}
+
+// ValidateMapSizes validates the configured sizes of the policy, endpoint
+// and load balancer maps. It is called before the BPF filesystem is mounted
+// as some maps are opened as soon as it is.
+func (c *daemonConfig) ValidateMapSizes() error {
+	for _, name := range []string{PolicyMapEntriesName, LXCMapEntriesName, LBMapEntriesName} {
+		if err := validateMapEntries(viper.GetString(name)); err != nil {
+			return fmt.Errorf("invalid value for option --%s: %s", name, err)
+		}
+	}
+
+	for _, name := range []string{PolicyMapEntriesName, LXCMapEntriesName, LBMapEntriesName} {
+		configSpecs[name].load(c)
+	}
+
+	return nil
+}
+
+func validateTunnelMode(value string) error {
+	switch value {
+	case TunnelVXLAN, TunnelGeneve, TunnelDisabled:
+		return nil
+	}
+	return fmt.Errorf("invalid tunnel mode '%s', valid modes = {%s}", value, GetTunnelModes())
+}
+
+func validateClusterID(value string) error {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if id < ClusterIDMin || id > ClusterIDMax {
+		return fmt.Errorf("invalid cluster id %d: must be in range %d..%d",
+			id, ClusterIDMin, ClusterIDMax)
+	}
 	return nil
 }
+
+func validateProxyTraceCollector(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, port, err := net.SplitHostPort(value)
+	if err != nil {
+		return err
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf("invalid port '%s'", port)
+	}
+	return nil
+}
+
+func validateProxyTraceSampling(value string) error {
+	percent, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("sampling percentage %d must be in range 0..100", percent)
+	}
+	return nil
+}
+
+func validateProxyLuaScript(value string) error {
+	if value == "" {
+		return nil
+	}
+	info, err := os.Stat(value)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", value)
+	}
+	return nil
+}
+
+func validateNonNegative(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return fmt.Errorf("value %d must not be negative", n)
+	}
+	return nil
+}
+
+func validatePositive(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 1 {
+		return fmt.Errorf("value %d must be positive", n)
+	}
+	return nil
+}
+
+// validateMonitorNumPages validates the number of pages of a perf ring
+// buffer, which the kernel requires to be a power of two
+func validateMonitorNumPages(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 1 || n&(n-1) != 0 {
+		return fmt.Errorf("number of pages %d must be a power of two", n)
+	}
+	return nil
+}
+
+// ctTimeoutMax is the maximum lifetime of CT entries in seconds. Lifetimes
+// are stored as 32 bit timestamps in seconds in the CT entries.
+const ctTimeoutMax = 1 << 30
+
+func validateCTTimeout(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 1 || n > ctTimeoutMax {
+		return fmt.Errorf("timeout %d must be in range 1..%d seconds", n, ctTimeoutMax)
+	}
+	return nil
+}
+
+const (
+	mapEntriesMin = 1 << 8  // 256 entries
+	mapEntriesMax = 1 << 24 // 16Mi entries
+)
+
+func validateMapEntries(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < mapEntriesMin || n > mapEntriesMax {
+		return fmt.Errorf("map size %d must be in range %d..%d", n, mapEntriesMin, mapEntriesMax)
+	}
+	return nil
+}
+
+var pinPrefixRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// reservedPinPrefixes are the top level directories of the BPF filesystem
+// used by iproute2
+var reservedPinPrefixes = map[string]bool{"tc": true, "xdp": true, "ip": true}
+
+// ValidatePinPrefix validates a subdirectory of the BPF filesystem in which
+// BPF objects are pinned. The prefix must be a single path element.
+func ValidatePinPrefix(value string) error {
+	if value == "" {
+		return nil
+	}
+	if !pinPrefixRegexp.MatchString(value) {
+		return fmt.Errorf("invalid pin prefix '%s': must consist of alphanumeric characters, '-', '_' or '.' and start with an alphanumeric character", value)
+	}
+	if reservedPinPrefixes[value] {
+		return fmt.Errorf("invalid pin prefix '%s': reserved by iproute2", value)
+	}
+	return nil
+}
+
+// validateEndpointHooks checks that value is a comma separated list of
+// absolute paths and http(s) URLs
+func validateEndpointHooks(value string) error {
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		switch {
+		case s == "", filepath.IsAbs(s):
+		case strings.HasPrefix(s, "http://"), strings.HasPrefix(s, "https://"):
+		default:
+			return fmt.Errorf("invalid hook %q: must be an absolute path or a http(s) URL", s)
+		}
+	}
+	return nil
+}
+
+// watchdogActionRegexp matches the name of a watchdog action
+var watchdogActionRegexp = regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+
+// validateWatchdogActions checks that value is a comma separated list of
+// action names. The names themselves are checked when the watchdog is
+// started.
+func validateWatchdogActions(value string) error {
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s != "" && !watchdogActionRegexp.MatchString(s) {
+			return fmt.Errorf("invalid watchdog action %q", s)
+		}
+	}
+	return nil
+}
+
+func init() {
+	for _, spec := range []*ConfigSpec{
+		{
+			Name:        AccessLogCompressName,
+			Default:     true,
+			Description: "Compress rotated access log files with gzip",
+			Since:       "1.3",
+		},
+		{
+			Name:        AccessLogMaxAgeName,
+			Default:     28,
+			Description: "Number of days to retain rotated access log files, 0 retains them regardless of their age",
+			Since:       "1.3",
+			Validate:    validateNonNegative,
+		},
+		{
+			Name:        AccessLogMaxBackupsName,
+			Default:     3,
+			Description: "Number of rotated access log files to retain, 0 retains all",
+			Since:       "1.3",
+			Validate:    validateNonNegative,
+		},
+		{
+			Name:        AccessLogMaxSizeName,
+			Default:     100,
+			Description: "Size in megabytes after which the access log is rotated",
+			Since:       "1.3",
+			Validate:    validatePositive,
+		},
+		{
+			Name:        AccessLogSinkBufferSizeName,
+			Default:     4096,
+			Description: "Number of access log records buffered per access log sink before records are dropped",
+			Since:       "1.3",
+			Validate:    validatePositive,
+		},
+		{
+			Name:        BPFCompileDebugName,
+			Default:     false,
+			Description: "Enable debugging of the BPF compilation process",
+		},
+		{
+			Name:        BPFCompileTemplatesName,
+			Default:     false,
+			Description: "Compile endpoint BPF programs once per configuration and instantiate them for each endpoint",
+			Since:       "1.3",
+		},
+		{
+			Name:        BPFMapCheckIntervalName,
+			Default:     300,
+			Description: "Interval in seconds between two comparisons of BPF maps with the desired state (0 is off)",
+			Since:       "1.3",
+			Validate:    validateNonNegative,
+		},
+		{
+			Name:        BPFMapEventsName,
+			Default:     true,
+			Description: "Journal the most recent mutations of BPF maps for 'cilium map events'",
+			Since:       "1.3",
+		},
+		{
+			Name:        BPFMapRepairName,
+			Default:     false,
+			Description: "Restore entries of BPF maps which differ from the desired state",
+			Since:       "1.3",
+		},
+		{
+			Name:        BPFMasqueradeName,
+			Default:     false,
+			Description: "Masquerade traffic leaving the node on the device in BPF instead of iptables, requires --device",
+			Since:       "1.3",
+		},
+		{
+			Name:        BPFPinPrefixName,
+			Env:         BPFPinPrefixEnv,
+			Default:     "",
+			Description: "Subdirectory of the BPF filesystem in which all maps and programs are pinned, allows multiple agents to share a host",
+			Since:       "1.3",
+			Validate:    ValidatePinPrefix,
+		},
+		{
+			Name:        CgroupRootName,
+			Default:     defaults.CgroupRoot,
+			Description: "Path to the cgroup2 filesystem, mounted if not present",
+			Since:       "1.3",
+		},
+		{
+			Name:        ClusterIDName,
+			Env:         ClusterIDEnv,
+			Default:     0,
+			Field:       func(c *daemonConfig) interface{} { return &c.ClusterID },
+			Description: "Unique identifier of the cluster",
+			Since:       "1.2",
+			Validate:    validateClusterID,
+		},
+		{
+			Name:        ClusterName,
+			Env:         ClusterNameEnv,
+			Default:     defaults.ClusterName,
+			Field:       func(c *daemonConfig) interface{} { return &c.ClusterName },
+			Description: "Name of the cluster",
+			Since:       "1.2",
+		},
+		{
+			Name:        ClusterMeshConfigName,
+			Env:         ClusterMeshConfigNameEnv,
+			Default:     "",
+			Field:       func(c *daemonConfig) interface{} { return &c.ClusterMeshConfig },
+			Description: "Path to the ClusterMesh configuration directory",
+			Since:       "1.2",
+		},
+		{
+			Name:        CTMapEntriesGlobalTCPName,
+			Env:         CTMapEntriesGlobalTCPNameEnv,
+			Default:     CTMapEntriesGlobalTCPDefault,
+			Field:       func(c *daemonConfig) interface{} { return &c.CTMapEntriesGlobalTCP },
+			Description: "Maximum number of entries in TCP CT table",
+			Since:       "1.3",
+		},
+		{
+			Name:        CTMapEntriesGlobalAnyName,
+			Env:         CTMapEntriesGlobalAnyNameEnv,
+			Default:     CTMapEntriesGlobalAnyDefault,
+			Field:       func(c *daemonConfig) interface{} { return &c.CTMapEntriesGlobalAny },
+			Description: "Maximum number of entries in non-TCP CT table",
+			Since:       "1.3",
+		},
+		{
+			Name:        CTTimeoutICMPName,
+			Env:         CTTimeoutICMPNameEnv,
+			Default:     CTTimeoutICMPDefault,
+			Field:       func(c *daemonConfig) interface{} { return &c.CTTimeoutICMP },
+			Description: "Lifetime in seconds of ICMP flows in the CT table",
+			Since:       "1.3",
+			Validate:    validateCTTimeout,
+		},
+		{
+			Name:        CTTimeoutTCPName,
+			Env:         CTTimeoutTCPNameEnv,
+			Default:     CTTimeoutTCPDefault,
+			Field:       func(c *daemonConfig) interface{} { return &c.CTTimeoutTCP },
+			Description: "Lifetime in seconds of established TCP connections in the CT table",
+			Since:       "1.3",
+			Validate:    validateCTTimeout,
+		},
+		{
+			Name:        CTTimeoutTCPSynName,
+			Env:         CTTimeoutTCPSynNameEnv,
+			Default:     CTTimeoutTCPSynDefault,
+			Field:       func(c *daemonConfig) interface{} { return &c.CTTimeoutTCPSyn },
+			Description: "Lifetime in seconds of TCP connections in the CT table which have only seen SYN packets",
+			Since:       "1.3",
+			Validate:    validateCTTimeout,
+		},
+		{
+			Name:        CTTimeoutUDPName,
+			Env:         CTTimeoutUDPNameEnv,
+			Default:     CTTimeoutUDPDefault,
+			Field:       func(c *daemonConfig) interface{} { return &c.CTTimeoutUDP },
+			Description: "Lifetime in seconds of UDP and other non-TCP flows in the CT table",
+			Since:       "1.3",
+			Validate:    validateCTTimeout,
+		},
+		{
+			Name:        LBMapEntriesName,
+			Env:         LBMapEntriesNameEnv,
+			Default:     LBMapEntriesDefault,
+			Field:       func(c *daemonConfig) interface{} { return &c.LBMapEntries },
+			Description: "Maximum number of entries in the load balancer service and reverse NAT maps",
+			Since:       "1.3",
+			Validate:    validateMapEntries,
+		},
+		{
+			Name:        LXCMapEntriesName,
+			Env:         LXCMapEntriesNameEnv,
+			Default:     LXCMapEntriesDefault,
+			Field:       func(c *daemonConfig) interface{} { return &c.LXCMapEntries },
+			Description: "Maximum number of entries in the endpoint map",
+			Since:       "1.3",
+			Validate:    validateMapEntries,
+		},
+		{
+			Name:        PolicyMapEntriesName,
+			Env:         PolicyMapEntriesNameEnv,
+			Default:     PolicyMapEntriesDefault,
+			Field:       func(c *daemonConfig) interface{} { return &c.PolicyMapEntries },
+			Description: "Maximum number of entries in each endpoint policy map",
+			Since:       "1.3",
+			Validate:    validateMapEntries,
+		},
+		{
+			Name:        EndpointHooksName,
+			Default:     "",
+			Field:       func(c *daemonConfig) interface{} { return &c.EndpointHooks },
+			Description: "Comma separated list of executables or http(s) URLs invoked with the endpoint as JSON on endpoint creation, identity change and deletion",
+			Since:       "1.3",
+			Validate:    validateEndpointHooks,
+		},
+		{
+			Name:        LogSystemLoadConfigName,
+			Default:     false,
+			Description: "Enable periodic logging of system load",
+		},
+		{
+			Name:        MonitorAggregationName,
+			Env:         "CILIUM_MONITOR_AGGREGATION_LEVEL",
+			Default:     "None",
+			Description: "Level of monitor aggregation for traces from the datapath",
+			Validate: func(value string) error {
+				_, err := ParseMonitorAggregationLevel(value)
+				return err
+			},
+		},
+		{
+			Name:        MonitorNumPagesName,
+			Default:     64,
+			Description: "Number of pages of each per-CPU perf ring buffer the node monitor reads events from, must be a power of two",
+			Since:       "1.3",
+			Validate:    validateMonitorNumPages,
+		},
+		{
+			Name:        MonitorOverwriteName,
+			Default:     false,
+			Description: "Overwrite the oldest events of full perf ring buffers instead of dropping new events (requires Linux 4.7)",
+			Since:       "1.3",
+		},
+		{
+			Name:        MonitorWakeupEventsName,
+			Default:     1,
+			Description: "Number of events after which the node monitor is woken up to read the perf ring buffers",
+			Since:       "1.3",
+			Validate:    validatePositive,
+		},
+		{
+			Name:        NodeNeighborTableName,
+			Default:     false,
+			Description: "Forward traffic to other nodes to the next hops of a BPF neighbor table maintained from netlink, requires --device and direct routing",
+			Since:       "1.3",
+		},
+		{
+			Name:        PrependIptablesChainsName,
+			Env:         PrependIptablesChainsNameEnv,
+			Default:     true,
+			Description: "Prepend custom iptables chains instead of appending",
+		},
+		{
+			Name:           PrometheusServeAddrName,
+			Env:            PrometheusServeAddrNameEnv,
+			RenamedFromEnv: []string{"PROMETHEUS_SERVE_ADDR"},
+			Default:        "",
+			Description:    "IP:Port on which to serve prometheus metrics (pass \":Port\" to bind on all interfaces, \"\" is off)",
+		},
+		{
+			Name:        ProxyTraceCollectorName,
+			Env:         ProxyTraceCollectorNameEnv,
+			Default:     "",
+			Field:       func(c *daemonConfig) interface{} { return &c.ProxyTraceCollector },
+			Description: "host:port of a Zipkin compatible collector to report L7 proxy spans to (\"\" is off)",
+			Since:       "1.3",
+			Validate:    validateProxyTraceCollector,
+		},
+		{
+			Name:        ProxyTraceSamplingName,
+			Default:     100,
+			Field:       func(c *daemonConfig) interface{} { return &c.ProxyTraceSampling },
+			Description: "Percentage of requests without trace context for which the L7 proxy starts a new trace",
+			Since:       "1.3",
+			Validate:    validateProxyTraceSampling,
+		},
+		{
+			Name:        ProxyLuaScriptName,
+			Env:         ProxyLuaScriptNameEnv,
+			Default:     "",
+			Field:       func(c *daemonConfig) interface{} { return &c.ProxyLuaScript },
+			Description: "Path of a Lua script run by the HTTP proxy on requests allowed by policy (\"\" is off)",
+			Since:       "1.3",
+			Validate:    validateProxyLuaScript,
+		},
+		{
+			Name:        ProxyTLSDirName,
+			Default:     "/etc/cilium/tls",
+			Field:       func(c *daemonConfig) interface{} { return &c.ProxyTLSDir },
+			Description: "Directory containing the certificates and keys referred to by TLS contexts of policy rules",
+			Since:       "1.3",
+		},
+		{
+			Name:        ProxyTransparentName,
+			Default:     false,
+			Field:       func(c *daemonConfig) interface{} { return &c.ProxyTransparent },
+			Description: "Preserve the IPv4 source address of connections forwarded by ingress L7 proxies",
+			Since:       "1.3",
+		},
+		{
+			Name:        ProxyDrainTimeoutName,
+			Default:     10,
+			Description: "Time in seconds during which removed L7 proxy redirects keep serving existing connections (0 is off)",
+			Since:       "1.3",
+			Validate:    validateNonNegative,
+		},
+		{
+			Name:        SingleClusterRouteName,
+			Default:     false,
+			Description: "Use a single cluster route instead of per node routes",
+		},
+		{
+			Name:        SockopsEnableName,
+			Default:     false,
+			Description: "Short-circuit TCP connections between local endpoints and the proxy with sockops programs",
+			Since:       "1.3",
+		},
+		{
+			Name:        TunnelName,
+			Shorthand:   "t",
+			Env:         TunnelNameEnv,
+			Default:     TunnelVXLAN,
+			Field:       func(c *daemonConfig) interface{} { return &c.Tunnel },
+			Description: fmt.Sprintf("Tunnel mode {%s}", GetTunnelModes()),
+			Since:       "1.0",
+			Validate:    validateTunnelMode,
+		},
+		{
+			Name:        WatchdogActionsName,
+			Default:     defaults.WatchdogActions,
+			Field:       func(c *daemonConfig) interface{} { return &c.WatchdogActions },
+			Description: "Comma separated list of actions taken in order when a watchdog budget is exceeded",
+			Since:       "1.3",
+			Validate:    validateWatchdogActions,
+		},
+		{
+			Name:        WatchdogGoroutineBudgetName,
+			Default:     0,
+			Field:       func(c *daemonConfig) interface{} { return &c.WatchdogGoroutineBudget },
+			Description: "Maximum number of goroutines of the agent before the watchdog takes action (0 is off)",
+			Since:       "1.3",
+			Validate:    validateNonNegative,
+		},
+		{
+			Name:        WatchdogMapMemoryBudgetName,
+			Default:     0,
+			Field:       func(c *daemonConfig) interface{} { return &c.WatchdogMapMemoryBudget },
+			Description: "Maximum memory in MiB of all BPF maps before the watchdog takes action (0 is off)",
+			Since:       "1.3",
+			Validate:    validateNonNegative,
+		},
+		{
+			Name:        WatchdogRSSBudgetName,
+			Default:     0,
+			Field:       func(c *daemonConfig) interface{} { return &c.WatchdogRSSBudget },
+			Description: "Maximum resident set size in MiB of the agent before the watchdog takes action (0 is off)",
+			Since:       "1.3",
+			Validate:    validateNonNegative,
+		},
+	} {
+		RegisterConfigSpec(spec)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package option
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// ConfigSpec describes a daemon configuration option together with the
+// metadata required to register, migrate, validate and document it.
+type ConfigSpec struct {
+	// Name is the name of the command line flag and of the configuration
+	// key of the option.
+	Name string
+
+	// Shorthand is the optional one-letter abbreviation of the flag.
+	Shorthand string
+
+	// Env is the name of the environment variable of the option, if any.
+	Env string
+
+	// Description is the help text of the option.
+	Description string
+
+	// Default is the default value of the option. Its type determines the
+	// type of the option and must be one of string, bool or int.
+	Default interface{}
+
+	// Field, if not nil, returns a pointer to the typed field of the daemon
+	// configuration which receives the configured value of the option. It
+	// must point to a field of the type of Default.
+	Field func(c *daemonConfig) interface{}
+
+	// Hidden hides the option from the help text and the documentation.
+	Hidden bool
+
+	// Since is the Cilium version in which the option was introduced.
+	Since string
+
+	// RenamedFrom is the list of previous names of the option. Flags using
+	// a previous name are accepted but deprecated.
+	RenamedFrom []string
+
+	// RenamedFromEnv is the list of previous names of the environment
+	// variable of the option.
+	RenamedFromEnv []string
+
+	// Validate, if not nil, validates the configured value of the option.
+	Validate func(value string) error
+}
+
+var configSpecs = map[string]*ConfigSpec{}
+
+// RegisterConfigSpec registers the specification of a daemon configuration
+// option. It panics if an option with the same name has already been
+// registered.
+func RegisterConfigSpec(spec *ConfigSpec) {
+	if _, ok := configSpecs[spec.Name]; ok {
+		panic(fmt.Sprintf("config option %s registered twice", spec.Name))
+	}
+	switch spec.Default.(type) {
+	case string, bool, int:
+	default:
+		panic(fmt.Sprintf("config option %s has unsupported type %T", spec.Name, spec.Default))
+	}
+	if spec.Field != nil && !spec.fieldMatchesDefault() {
+		panic(fmt.Sprintf("config option %s has field %T for default %T",
+			spec.Name, spec.Field(&daemonConfig{}), spec.Default))
+	}
+	configSpecs[spec.Name] = spec
+}
+
+// GetConfigSpec returns the specification of the option with the given name.
+func GetConfigSpec(name string) (*ConfigSpec, bool) {
+	spec, ok := configSpecs[name]
+	return spec, ok
+}
+
+// GetConfigSpecs returns the specifications of all registered options
+// sorted by name.
+func GetConfigSpecs() []*ConfigSpec {
+	specs := make([]*ConfigSpec, 0, len(configSpecs))
+	for _, spec := range configSpecs {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+	return specs
+}
+
+func (s *ConfigSpec) fieldMatchesDefault() bool {
+	switch s.Field(&daemonConfig{}).(type) {
+	case *string:
+		_, ok := s.Default.(string)
+		return ok
+	case *bool:
+		_, ok := s.Default.(bool)
+		return ok
+	case *int:
+		_, ok := s.Default.(int)
+		return ok
+	}
+	return false
+}
+
+// load stores the configured value of the option in its field of c.
+func (s *ConfigSpec) load(c *daemonConfig) {
+	if s.Field == nil {
+		return
+	}
+	switch v := s.Field(c).(type) {
+	case *string:
+		*v = viper.GetString(s.Name)
+	case *bool:
+		*v = viper.GetBool(s.Name)
+	case *int:
+		*v = viper.GetInt(s.Name)
+	}
+}
+
+// addFlag adds the flag of the option and all of its deprecated names to
+// flags and binds the environment variable of the option.
+func (s *ConfigSpec) addFlag(flags *pflag.FlagSet) {
+	switch def := s.Default.(type) {
+	case string:
+		flags.StringP(s.Name, s.Shorthand, def, s.Description)
+	case bool:
+		flags.BoolP(s.Name, s.Shorthand, def, s.Description)
+	case int:
+		flags.IntP(s.Name, s.Shorthand, def, s.Description)
+	}
+	if s.Hidden {
+		flags.MarkHidden(s.Name)
+	}
+	if s.Env != "" {
+		viper.BindEnv(s.Name, s.Env)
+	}
+
+	// A renamed flag shares the value of the current flag so that setting
+	// either of them results in the same configuration.
+	flag := flags.Lookup(s.Name)
+	for _, old := range s.RenamedFrom {
+		flags.AddFlag(&pflag.Flag{
+			Name:        old,
+			Usage:       s.Description,
+			Value:       flag.Value,
+			DefValue:    flag.DefValue,
+			NoOptDefVal: flag.NoOptDefVal,
+			Deprecated:  fmt.Sprintf("use --%s instead", s.Name),
+			Hidden:      true,
+		})
+	}
+}
+
+// AddConfigFlags adds the flags of all registered options to flags.
+func AddConfigFlags(flags *pflag.FlagSet) {
+	for _, spec := range GetConfigSpecs() {
+		spec.addFlag(flags)
+	}
+}
+
+// MigrateRenamedOptions makes options which have been configured via a
+// previous name of their environment variable or configuration key available
+// under their current name. A value configured under the current name always
+// takes precedence. Returns a deprecation warning for each migrated option.
+func MigrateRenamedOptions() []string {
+	var warnings []string
+
+	for _, spec := range GetConfigSpecs() {
+		if spec.Env != "" {
+			if _, ok := os.LookupEnv(spec.Env); !ok {
+				for _, oldEnv := range spec.RenamedFromEnv {
+					if value, ok := os.LookupEnv(oldEnv); ok {
+						os.Setenv(spec.Env, value)
+						warnings = append(warnings, fmt.Sprintf(
+							"environment variable %s is deprecated, use %s instead", oldEnv, spec.Env))
+						break
+					}
+				}
+			}
+		}
+
+		if viper.InConfig(spec.Name) {
+			continue
+		}
+		for _, old := range spec.RenamedFrom {
+			if viper.InConfig(old) {
+				viper.Set(spec.Name, viper.Get(old))
+				warnings = append(warnings, fmt.Sprintf(
+					"configuration key %s is deprecated, use %s instead", old, spec.Name))
+				break
+			}
+		}
+	}
+
+	return warnings
+}
+
+// ValidateConfigSpecs validates the configured value of all registered
+// options which provide a validation function.
+func ValidateConfigSpecs() error {
+	for _, spec := range GetConfigSpecs() {
+		if spec.Validate == nil {
+			continue
+		}
+		if err := spec.Validate(viper.GetString(spec.Name)); err != nil {
+			return fmt.Errorf("invalid value for option --%s: %s", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadConfigSpecs stores the configured value of all registered options
+// into their fields of c. The values should be validated with
+// ValidateConfigSpecs first.
+func (c *daemonConfig) loadConfigSpecs() {
+	for _, spec := range GetConfigSpecs() {
+		spec.load(c)
+	}
+}
+
+// WriteConfigSpecReference writes a markdown reference of all registered,
+// non-hidden options to w.
+func WriteConfigSpecReference(w io.Writer) error {
+	fmt.Fprintln(w, "## cilium-agent configuration options")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| Option | Environment variable | Default | Since | Description |")
+	fmt.Fprintln(w, "|--------|----------------------|---------|-------|-------------|")
+	for _, spec := range GetConfigSpecs() {
+		if spec.Hidden {
+			continue
+		}
+		name := "--" + spec.Name
+		for _, old := range spec.RenamedFrom {
+			name += fmt.Sprintf(" (was --%s)", old)
+		}
+		env := spec.Env
+		for _, old := range spec.RenamedFromEnv {
+			env += fmt.Sprintf(" (was %s)", old)
+		}
+		def := ""
+		if spec.Default != "" {
+			def = fmt.Sprintf("`%v`", spec.Default)
+		}
+		_, err := fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n",
+			name, env, def, spec.Since, spec.Description)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
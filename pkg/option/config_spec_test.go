@@ -0,0 +1,133 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package option
+
+import (
+	"bytes"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	. "gopkg.in/check.v1"
+)
+
+func newConfigSpecFlags(c *C) *pflag.FlagSet {
+	viper.Reset()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddConfigFlags(flags)
+	c.Assert(viper.BindPFlags(flags), IsNil)
+	return flags
+}
+
+func (s *OptionSuite) TestRegisterConfigSpec(c *C) {
+	spec, ok := GetConfigSpec(TunnelName)
+	c.Assert(ok, Equals, true)
+	c.Assert(spec.Shorthand, Equals, "t")
+
+	_, ok = GetConfigSpec("does-not-exist")
+	c.Assert(ok, Equals, false)
+
+	specs := GetConfigSpecs()
+	for i := 1; i < len(specs); i++ {
+		c.Assert(specs[i-1].Name < specs[i].Name, Equals, true)
+	}
+
+	c.Assert(func() { RegisterConfigSpec(&ConfigSpec{Name: TunnelName, Default: ""}) }, Panics,
+		"config option tunnel registered twice")
+	c.Assert(func() { RegisterConfigSpec(&ConfigSpec{Name: "test-float", Default: 1.0}) }, Panics,
+		"config option test-float has unsupported type float64")
+	field := func(c *daemonConfig) interface{} { return &c.ClusterID }
+	c.Assert(func() { RegisterConfigSpec(&ConfigSpec{Name: "test-field", Default: "", Field: field}) }, Panics,
+		"config option test-field has field *int for default string")
+}
+
+func (s *OptionSuite) TestConfigSpecRenamedFlag(c *C) {
+	spec := &ConfigSpec{
+		Name:        "test-new-name",
+		RenamedFrom: []string{"test-old-name"},
+		Default:     "foo",
+	}
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	spec.addFlag(flags)
+
+	old := flags.Lookup("test-old-name")
+	c.Assert(old, Not(IsNil))
+	c.Assert(old.Hidden, Equals, true)
+	c.Assert(old.Deprecated, Not(Equals), "")
+
+	c.Assert(flags.Parse([]string{"--test-old-name=bar"}), IsNil)
+	value, err := flags.GetString("test-new-name")
+	c.Assert(err, IsNil)
+	c.Assert(value, Equals, "bar")
+}
+
+func (s *OptionSuite) TestValidateConfigSpecs(c *C) {
+	defer viper.Reset()
+
+	flags := newConfigSpecFlags(c)
+	c.Assert(ValidateConfigSpecs(), IsNil)
+
+	c.Assert(flags.Set(TunnelName, "invalid"), IsNil)
+	c.Assert(ValidateConfigSpecs(), Not(IsNil))
+	c.Assert(flags.Set(TunnelName, TunnelGeneve), IsNil)
+	c.Assert(ValidateConfigSpecs(), IsNil)
+
+	c.Assert(flags.Set(ClusterIDName, "256"), IsNil)
+	c.Assert(ValidateConfigSpecs(), Not(IsNil))
+	c.Assert(flags.Set(ClusterIDName, "255"), IsNil)
+	c.Assert(ValidateConfigSpecs(), IsNil)
+}
+
+func (s *OptionSuite) TestLoadConfigSpecs(c *C) {
+	defer viper.Reset()
+
+	flags := newConfigSpecFlags(c)
+	c.Assert(flags.Set(TunnelName, TunnelGeneve), IsNil)
+	c.Assert(flags.Set(ClusterIDName, "42"), IsNil)
+
+	config := &daemonConfig{}
+	config.loadConfigSpecs()
+	c.Assert(config.Tunnel, Equals, TunnelGeneve)
+	c.Assert(config.ClusterID, Equals, 42)
+	c.Assert(config.CTTimeoutTCP, Equals, CTTimeoutTCPDefault)
+}
+
+func (s *OptionSuite) TestMigrateRenamedOptions(c *C) {
+	defer viper.Reset()
+	defer os.Unsetenv(PrometheusServeAddrNameEnv)
+	defer os.Unsetenv("PROMETHEUS_SERVE_ADDR")
+
+	os.Unsetenv(PrometheusServeAddrNameEnv)
+	os.Setenv("PROMETHEUS_SERVE_ADDR", ":9090")
+	newConfigSpecFlags(c)
+
+	warnings := MigrateRenamedOptions()
+	c.Assert(len(warnings), Equals, 1)
+	c.Assert(strings.Contains(warnings[0], "PROMETHEUS_SERVE_ADDR"), Equals, true)
+	c.Assert(viper.GetString(PrometheusServeAddrName), Equals, ":9090")
+
+	// The current name takes precedence over the previous one
+	os.Setenv(PrometheusServeAddrNameEnv, ":9091")
+	c.Assert(len(MigrateRenamedOptions()), Equals, 0)
+	c.Assert(viper.GetString(PrometheusServeAddrName), Equals, ":9091")
+}
+
+func (s *OptionSuite) TestWriteConfigSpecReference(c *C) {
+	var buf bytes.Buffer
+	c.Assert(WriteConfigSpecReference(&buf), IsNil)
+	c.Assert(strings.Contains(buf.String(), "`--"+TunnelName+"`"), Equals, true)
+	c.Assert(strings.Contains(buf.String(), "(was PROMETHEUS_SERVE_ADDR)"), Equals, true)
+}
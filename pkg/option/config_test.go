@@ -15,6 +15,9 @@
 package option
 
 import (
+	"strconv"
+
+	"github.com/spf13/viper"
 	. "gopkg.in/check.v1"
 )
 
@@ -39,3 +42,51 @@ func (s *OptionSuite) TestValidateIPv6ClusterAllocCIDR(c *C) {
 	invalid4 := &daemonConfig{}
 	c.Assert(invalid4.validateIPv6ClusterAllocCIDR(), Not(IsNil))
 }
+
+func (s *OptionSuite) TestValidateMapSizes(c *C) {
+	defer viper.Reset()
+
+	viper.Set(PolicyMapEntriesName, 1<<16)
+	viper.Set(LXCMapEntriesName, LXCMapEntriesDefault)
+	viper.Set(LBMapEntriesName, 1<<10)
+
+	config := &daemonConfig{}
+	c.Assert(config.ValidateMapSizes(), IsNil)
+	c.Assert(config.PolicyMapEntries, Equals, 1<<16)
+	c.Assert(config.LXCMapEntries, Equals, LXCMapEntriesDefault)
+	c.Assert(config.LBMapEntries, Equals, 1<<10)
+
+	for _, invalid := range []interface{}{"foo", mapEntriesMin - 1, mapEntriesMax + 1} {
+		viper.Set(LBMapEntriesName, invalid)
+		c.Assert(config.ValidateMapSizes(), Not(IsNil))
+	}
+}
+
+func (s *OptionSuite) TestValidateCTTimeout(c *C) {
+	c.Assert(validateCTTimeout("1"), IsNil)
+	c.Assert(validateCTTimeout("21600"), IsNil)
+	c.Assert(validateCTTimeout("0"), Not(IsNil))
+	c.Assert(validateCTTimeout("-60"), Not(IsNil))
+	c.Assert(validateCTTimeout("60s"), Not(IsNil))
+	c.Assert(validateCTTimeout(strconv.Itoa(ctTimeoutMax+1)), Not(IsNil))
+}
+
+func (s *OptionSuite) TestValidatePinPrefix(c *C) {
+	c.Assert(ValidatePinPrefix(""), IsNil)
+	c.Assert(ValidatePinPrefix("cluster1"), IsNil)
+	c.Assert(ValidatePinPrefix("test_harness-2.a"), IsNil)
+	c.Assert(ValidatePinPrefix("tc"), Not(IsNil))
+	c.Assert(ValidatePinPrefix("xdp"), Not(IsNil))
+	c.Assert(ValidatePinPrefix("a/b"), Not(IsNil))
+	c.Assert(ValidatePinPrefix(".."), Not(IsNil))
+	c.Assert(ValidatePinPrefix("-a"), Not(IsNil))
+}
+
+func (s *OptionSuite) TestValidateMonitorNumPages(c *C) {
+	c.Assert(validateMonitorNumPages("1"), IsNil)
+	c.Assert(validateMonitorNumPages("64"), IsNil)
+	c.Assert(validateMonitorNumPages("0"), Not(IsNil))
+	c.Assert(validateMonitorNumPages("-64"), Not(IsNil))
+	c.Assert(validateMonitorNumPages("48"), Not(IsNil))
+	c.Assert(validateMonitorNumPages("many"), Not(IsNil))
+}
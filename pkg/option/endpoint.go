@@ -19,12 +19,16 @@ var (
 		ConntrackAccounting: &specConntrackAccounting,
 		ConntrackLocal:      &specConntrackLocal,
 		Conntrack:           &specConntrack,
+		ConntrackBypass:     &specConntrackBypass,
 		Debug:               &specDebug,
 		DebugLB:             &specDebugLB,
+		DNSVisibility:       &specDNSVisibility,
 		DropNotify:          &specDropNotify,
+		EgressBandwidth:     &specEgressBandwidth,
 		TraceNotify:         &specTraceNotify,
 		MonitorAggregation:  &specMonitorAggregation,
 		NAT46:               &specNAT46,
+		PolicyPortRanges:    &specPolicyPortRanges,
 	}
 )
 
@@ -475,8 +475,8 @@ func (o *IntOptions) ApplyValidated(n OptionMap, changed ChangedFunc, data inter
 				changes = append(changes, changedOptions{key: k, value: optVal})
 			}
 		} else {
-			/* Only enable if not enabled already */
-			if !ok || val == OptionDisabled {
+			/* Only enable if not enabled already with the same value */
+			if !ok || val != optVal {
 				o.set(k, optVal)
 				changes = append(changes, changedOptions{key: k, value: optVal})
 			}
@@ -463,3 +463,38 @@ func (s *OptionSuite) TestApplyValidated(c *C) {
 		c.Assert(o.GetValue(k), Equals, v)
 	}
 }
+
+func (s *OptionSuite) TestApplyValidatedValueChange(c *C) {
+	o := IntOptions{
+		Opts: OptionMap{
+			MonitorAggregation: MonitorAggregationLevelLow,
+		},
+		Library: &OptionLibrary{
+			MonitorAggregation: &specMonitorAggregation,
+		},
+	}
+
+	changes := OptionMap{}
+	changed := func(key string, value OptionSetting, data interface{}) {
+		changes[key] = value
+	}
+
+	// Applying the current level is not a change
+	om, err := o.Library.ValidateConfigurationMap(models.ConfigurationMap{MonitorAggregation: "low"})
+	c.Assert(err, IsNil)
+	c.Assert(o.ApplyValidated(om, changed, nil), Equals, 0)
+	c.Assert(changes, DeepEquals, OptionMap{})
+
+	// Changing the level of an already enabled option is applied
+	om, err = o.Library.ValidateConfigurationMap(models.ConfigurationMap{MonitorAggregation: "medium"})
+	c.Assert(err, IsNil)
+	c.Assert(o.ApplyValidated(om, changed, nil), Equals, 1)
+	c.Assert(changes, DeepEquals, OptionMap{MonitorAggregation: MonitorAggregationLevelMedium})
+	c.Assert(o.GetValue(MonitorAggregation), Equals, MonitorAggregationLevelMedium)
+
+	// Disabling is still applied
+	om, err = o.Library.ValidateConfigurationMap(models.ConfigurationMap{MonitorAggregation: "none"})
+	c.Assert(err, IsNil)
+	c.Assert(o.ApplyValidated(om, changed, nil), Equals, 1)
+	c.Assert(o.GetValue(MonitorAggregation), Equals, MonitorAggregationLevelNone)
+}
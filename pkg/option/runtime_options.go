@@ -23,12 +23,16 @@ const (
 	ConntrackAccounting = "ConntrackAccounting"
 	ConntrackLocal      = "ConntrackLocal"
 	Conntrack           = "Conntrack"
+	ConntrackBypass     = "ConntrackBypass"
 	Debug               = "Debug"
 	DebugLB             = "DebugLB"
+	DNSVisibility       = "DNSVisibility"
 	DropNotify          = "DropNotification"
+	EgressBandwidth     = "EgressBandwidth"
 	TraceNotify         = "TraceNotification"
 	MonitorAggregation  = "MonitorAggregationLevel"
 	NAT46               = "NAT46"
+	PolicyPortRanges    = "PolicyPortRanges"
 	AlwaysEnforce       = "always"
 	NeverEnforce        = "never"
 	DefaultEnforcement  = "default"
@@ -57,6 +61,10 @@ var (
 		Description: "Enable stateful connection tracking",
 	}
 
+	specConntrackBypass = Option{
+		Description: "Bypass connection tracking and enforce policy statelessly",
+	}
+
 	specDebug = Option{
 		Define:      "DEBUG",
 		Description: "Enable debugging trace statements",
@@ -67,11 +75,23 @@ var (
 		Description: "Enable debugging trace statements for load balancer",
 	}
 
+	specDNSVisibility = Option{
+		Description: "Redirect DNS traffic to the DNS proxy for visibility",
+	}
+
 	specDropNotify = Option{
 		Define:      "DROP_NOTIFY",
 		Description: "Enable drop notifications",
 	}
 
+	specEgressBandwidth = Option{
+		Define:      "EGRESS_BANDWIDTH",
+		Description: "Limit the egress bandwidth (bits per second)",
+		Verify:      VerifyBandwidth,
+		Parse:       ParseBandwidth,
+		Format:      FormatBandwidth,
+	}
+
 	specTraceNotify = Option{
 		Define:      "TRACE_NOTIFY",
 		Description: "Enable trace notifications",
@@ -97,6 +117,11 @@ var (
 		},
 	}
 
+	specPolicyPortRanges = Option{
+		Define:      "POLICY_PORT_RANGES",
+		Description: "Look up port range entries of the policy map",
+	}
+
 	IngressSpecPolicy = Option{
 		Define:      "POLICY_INGRESS",
 		Description: "Enable ingress policy enforcement",
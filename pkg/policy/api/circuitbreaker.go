@@ -0,0 +1,44 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+// CircuitBreaker limits the load the proxy forwards to the port of an
+// endpoint subject to L7 rules. Connections and requests exceeding a limit
+// are rejected by the proxy instead of being forwarded.
+type CircuitBreaker struct {
+	// MaxConnections is the maximum number of concurrent connections the
+	// proxy forwards to the port. If omitted or zero, the circuit breaker
+	// does not limit connections.
+	//
+	// +optional
+	MaxConnections uint32 `json:"maxConnections,omitempty"`
+
+	// MaxPendingRequests is the maximum number of requests forwarded to the
+	// port which are waiting for a connection (HTTP) or for a response
+	// (Kafka). If omitted or zero, the circuit breaker does not limit
+	// requests.
+	//
+	// +optional
+	MaxPendingRequests uint32 `json:"maxPendingRequests,omitempty"`
+}
+
+// Equal returns true if both circuit breakers are nil or have the same
+// limits.
+func (cb *CircuitBreaker) Equal(o *CircuitBreaker) bool {
+	if cb == nil || o == nil {
+		return cb == o
+	}
+	return *cb == *o
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// PortRuleDNS is a list of allowed DNS lookups. At most one of the fields
+// may be set. If all fields are empty or missing, the rule will match all
+// DNS lookups.
+type PortRuleDNS struct {
+	// MatchName matches the name of a DNS lookup exactly, e.g.
+	// "api.example.com". The match is case-insensitive and a trailing dot is
+	// optional.
+	//
+	// +optional
+	MatchName string `json:"matchName,omitempty"`
+
+	// MatchPattern matches the name of a DNS lookup with a wildcard pattern.
+	// The wildcard "*" matches zero or more characters of a single label,
+	// e.g. "*.example.com" matches "api.example.com" but not
+	// "a.b.example.com". A pattern consisting of only "*" matches all names.
+	// The match is case-insensitive and a trailing dot is optional.
+	//
+	// +optional
+	MatchPattern string `json:"matchPattern,omitempty"`
+}
+
+var (
+	// dnsNameValidChars matches the names allowed in MatchName
+	dnsNameValidChars = regexp.MustCompile(`^[-a-zA-Z0-9_]+(\.[-a-zA-Z0-9_]+)*\.?$`)
+
+	// dnsPatternValidChars matches the patterns allowed in MatchPattern
+	dnsPatternValidChars = regexp.MustCompile(`^[-a-zA-Z0-9_*]+(\.[-a-zA-Z0-9_*]+)*\.?$`)
+)
+
+// Sanitize validates the name or pattern of a DNS rule.
+func (d *PortRuleDNS) Sanitize() error {
+	if d.MatchName != "" && d.MatchPattern != "" {
+		return fmt.Errorf("only one of matchName and matchPattern may be set in a DNS rule")
+	}
+
+	if d.MatchName != "" && !dnsNameValidChars.MatchString(d.MatchName) {
+		return fmt.Errorf("invalid DNS matchName %q", d.MatchName)
+	}
+
+	if d.MatchPattern != "" && !dnsPatternValidChars.MatchString(d.MatchPattern) {
+		return fmt.Errorf("invalid DNS matchPattern %q", d.MatchPattern)
+	}
+
+	return nil
+}
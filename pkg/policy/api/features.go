@@ -0,0 +1,66 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/cilium/cilium/pkg/versioncheck"
+
+	go_version "github.com/hashicorp/go-version"
+)
+
+// ruleFeature is a policy construct which is only understood by Cilium
+// versions matching the constraint.
+// +k8s:deepcopy-gen=false
+type ruleFeature struct {
+	name       string
+	constraint go_version.Constraints
+	usedBy     func(r *Rule) bool
+}
+
+// ruleFeatures lists the policy constructs which have been introduced after
+// the oldest version Cilium can be downgraded to.
+var ruleFeatures = []ruleFeature{
+	{
+		name:       "toFQDNs",
+		constraint: versioncheck.MustCompile(">= 1.2"),
+		usedBy: func(r *Rule) bool {
+			for _, e := range r.Egress {
+				if len(e.ToFQDNs) > 0 {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		name:       "mirror",
+		constraint: versioncheck.MustCompile(">= 1.3"),
+		usedBy: func(r *Rule) bool {
+			return len(r.Mirror) > 0
+		},
+	},
+}
+
+// UnsupportedFeatures returns the names of the policy constructs used by the
+// rule which are not supported by the target version of Cilium.
+func (r *Rule) UnsupportedFeatures(target *go_version.Version) []string {
+	var unsupported []string
+	for _, f := range ruleFeatures {
+		if f.usedBy(r) && !f.constraint.Check(target) {
+			unsupported = append(unsupported, f.name)
+		}
+	}
+	return unsupported
+}
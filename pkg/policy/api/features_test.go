@@ -0,0 +1,56 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/cilium/cilium/pkg/labels"
+
+	go_version "github.com/hashicorp/go-version"
+	. "gopkg.in/check.v1"
+)
+
+func (s *PolicyAPITestSuite) TestUnsupportedFeatures(c *C) {
+	collectorSelector := NewESFromLabels(labels.ParseSelectLabel("app=ids"))
+
+	rule := Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Egress: []EgressRule{{
+			ToFQDNs: []FQDNSelector{{MatchName: "cilium.io"}},
+		}},
+		Mirror: []MirrorRule{{
+			Collector: MirrorCollector{Endpoint: &collectorSelector},
+		}},
+	}
+
+	for _, t := range []struct {
+		target      string
+		unsupported []string
+	}{
+		{"1.3.0", nil},
+		{"1.2.90", []string{"mirror"}},
+		{"1.2.0", []string{"mirror"}},
+		{"1.1.5", []string{"toFQDNs", "mirror"}},
+	} {
+		target, err := go_version.NewVersion(t.target)
+		c.Assert(err, IsNil)
+		c.Assert(rule.UnsupportedFeatures(target), DeepEquals, t.unsupported,
+			Commentf("target %s", t.target))
+	}
+
+	rule = Rule{EndpointSelector: WildcardEndpointSelector}
+	target, err := go_version.NewVersion("1.0.0")
+	c.Assert(err, IsNil)
+	c.Assert(rule.UnsupportedFeatures(target), IsNil)
+}
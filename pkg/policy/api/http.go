@@ -14,7 +14,12 @@
 
 package api
 
-import "regexp"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
 
 // PortRuleHTTP is a list of HTTP protocol constraints. All fields are
 // optional, if all fields are empty or missing, the rule does not have any
@@ -57,6 +62,150 @@ type PortRuleHTTP struct {
 	//
 	// +optional
 	Headers []string `json:"headers,omitempty"`
+
+	// InjectIdentityHeaders enables the injection of the
+	// X-Cilium-Source-Identity and X-Cilium-Source-Labels headers, carrying
+	// the numeric security identity and the labels of the source of the
+	// request, into requests allowed by this rule. Headers of the same name
+	// sent by the source are always removed.
+	//
+	// +optional
+	InjectIdentityHeaders bool `json:"injectIdentityHeaders,omitempty"`
+
+	// Timeout is the maximum time the proxy waits for the complete
+	// response of the upstream service to a request allowed by this rule,
+	// e.g. "1.5s". If the timeout expires, a 504 response is returned.
+	//
+	// If omitted or empty, the default timeout of the proxy applies.
+	//
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+
+	// Retries is the policy by which the proxy retries requests allowed by
+	// this rule if the upstream service fails to respond successfully.
+	//
+	// If omitted, requests are not retried.
+	//
+	// +optional
+	Retries *HTTPRetryPolicy `json:"retries,omitempty"`
+}
+
+// HTTPRetryPolicy is the policy by which the proxy retries failed HTTP
+// requests.
+type HTTPRetryPolicy struct {
+	// NumRetries is the maximum number of retries of a request, in
+	// addition to its initial attempt.
+	NumRetries uint32 `json:"numRetries"`
+
+	// PerTryTimeout is the timeout of each attempt, e.g. "250ms". The
+	// timeout of the rule, if any, includes all retries.
+	//
+	// If omitted or empty, each attempt is only limited by the timeout of
+	// the rule.
+	//
+	// +optional
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+
+	// RetryOn is the list of conditions under which a request is
+	// retried. Supported conditions are "5xx", "gateway-error",
+	// "connect-failure", "retriable-4xx" and "refused-stream".
+	//
+	// If omitted or empty, requests are retried on "5xx" and
+	// "connect-failure".
+	//
+	// +optional
+	RetryOn []string `json:"retryOn,omitempty"`
+}
+
+var (
+	// DefaultHTTPRetryOn is the list of retry conditions used if a retry
+	// policy does not specify any.
+	DefaultHTTPRetryOn = []string{"5xx", "connect-failure"}
+
+	httpRetryConditions = map[string]struct{}{
+		"5xx":             {},
+		"gateway-error":   {},
+		"connect-failure": {},
+		"retriable-4xx":   {},
+		"refused-stream":  {},
+	}
+)
+
+// Equal returns true if both retry policies are equal
+func (r *HTTPRetryPolicy) Equal(o *HTTPRetryPolicy) bool {
+	if r == nil || o == nil {
+		return r == o
+	}
+	if r.NumRetries != o.NumRetries ||
+		r.PerTryTimeout != o.PerTryTimeout ||
+		len(r.RetryOn) != len(o.RetryOn) {
+		return false
+	}
+	for i, value := range r.RetryOn {
+		if o.RetryOn[i] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// GetRetryOn returns the retry conditions of the policy as the comma
+// separated list understood by the proxy.
+func (r *HTTPRetryPolicy) GetRetryOn() string {
+	if len(r.RetryOn) == 0 {
+		return strings.Join(DefaultHTTPRetryOn, ",")
+	}
+	return strings.Join(r.RetryOn, ",")
+}
+
+// parseHTTPTimeout parses a non-empty timeout of an HTTP rule. The proxy
+// enforces timeouts in milliseconds, shorter timeouts are rejected.
+func parseHTTPTimeout(timeout string) (time.Duration, error) {
+	d, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, err
+	}
+	if d < time.Millisecond {
+		return 0, fmt.Errorf("timeout %q must be at least 1ms", timeout)
+	}
+	return d, nil
+}
+
+// GetTimeout returns the timeout of the rule, or 0 if the rule has none. The
+// timeout is assumed to have been sanitized.
+func (h *PortRuleHTTP) GetTimeout() time.Duration {
+	if h.Timeout == "" {
+		return 0
+	}
+	d, _ := parseHTTPTimeout(h.Timeout)
+	return d
+}
+
+// GetPerTryTimeout returns the timeout of each attempt, or 0 if the policy has
+// none. The timeout is assumed to have been sanitized.
+func (r *HTTPRetryPolicy) GetPerTryTimeout() time.Duration {
+	if r.PerTryTimeout == "" {
+		return 0
+	}
+	d, _ := parseHTTPTimeout(r.PerTryTimeout)
+	return d
+}
+
+func (r *HTTPRetryPolicy) sanitize() error {
+	if r.NumRetries == 0 {
+		return fmt.Errorf("retry policy must allow at least one retry")
+	}
+	if r.PerTryTimeout != "" {
+		if _, err := parseHTTPTimeout(r.PerTryTimeout); err != nil {
+			return fmt.Errorf("invalid perTryTimeout: %s", err)
+		}
+	}
+	for _, cond := range r.RetryOn {
+		if _, ok := httpRetryConditions[cond]; !ok {
+			return fmt.Errorf("unsupported retry condition %q", cond)
+		}
+	}
+	return nil
 }
 
 // Sanitize sanitizes HTTP rules. It ensures that the path and method fields
@@ -79,6 +228,18 @@ func (h *PortRuleHTTP) Sanitize() error {
 		}
 	}
 
+	if h.Timeout != "" {
+		if _, err := parseHTTPTimeout(h.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout: %s", err)
+		}
+	}
+
+	if h.Retries != nil {
+		if err := h.Retries.sanitize(); err != nil {
+			return err
+		}
+	}
+
 	// Headers are not sanitized.
 	return nil
 }
@@ -30,7 +30,8 @@ type PortRuleKafka struct {
 	// to perform the specified higher-level operation.
 	//
 	// The following values are supported:
-	//  - "produce": Allow producing to the topics specified in the rule
+	//  - "produce": Allow producing to the topics specified in the rule,
+	//    transactionally if TransactionalID is specified
 	//  - "consume": Allow consuming from the topics specified in the rule
 	//
 	// This field is incompatible with the APIKey field, i.e APIKey and Role
@@ -93,6 +94,34 @@ type PortRuleKafka struct {
 	// +optional
 	Topic string `json:"topic,omitempty"`
 
+	// GroupID is the consumer group ID contained in the message. Requests
+	// of consumer group members such as "joingroup", "heartbeat",
+	// "offsetcommit" and "offsetfetch" are only allowed for the given
+	// group, allowing tenants sharing a Kafka cluster to be fenced at the
+	// group level.
+	//
+	// This constraint is ignored if the matched request message type
+	// doesn't contain any group ID.
+	//
+	// If omitted or empty, all group IDs are allowed.
+	//
+	// +optional
+	GroupID string `json:"groupID,omitempty"`
+
+	// TransactionalID is the transactional ID contained in the message.
+	// Transactional requests such as "initproducerid", "endtxn" and
+	// transactional "produce" requests are only allowed for the given
+	// transactional ID. If set along with the "produce" role, the role also
+	// expands into all APIKeys required by transactional producers.
+	//
+	// This constraint is ignored if the matched request message type
+	// doesn't contain any transactional ID.
+	//
+	// If omitted or empty, all transactional IDs are allowed.
+	//
+	// +optional
+	TransactionalID string `json:"transactionalID,omitempty"`
+
 	// --------------------------------------------------------------------
 	// Private fields. These fields are used internally and are not exposed
 	// via the API.
@@ -135,10 +164,15 @@ const (
 // List of Kafka apiKey which are not associated with
 // any topic
 const (
-	HeartbeatKey   = 12
-	LeaveGroupKey  = 13
-	SyncgroupKey   = 14
-	APIVersionsKey = 18
+	HeartbeatKey        = 12
+	LeaveGroupKey       = 13
+	SyncgroupKey        = 14
+	SaslHandshakeKey    = 17
+	APIVersionsKey      = 18
+	InitProducerIDKey   = 22
+	AddOffsetsToTxnKey  = 25
+	EndTxnKey           = 26
+	SaslAuthenticateKey = 36
 )
 
 // List of Kafka Roles
@@ -185,6 +219,10 @@ var KafkaAPIKeyMap = map[string]int16{
 	"deleteacls":           31, /* DeleteAcls */
 	"describeconfigs":      32, /* DescribeConfigs */
 	"alterconfigs":         33, /* AlterConfigs */
+	"alterreplicalogdirs":  34, /* AlterReplicaLogDirs */
+	"describelogdirs":      35, /* DescribeLogDirs */
+	"saslauthenticate":     36, /* SaslAuthenticate */
+	"createpartitions":     37, /* CreatePartitions */
 }
 
 // KafkaReverseApiKeyMap is the map of all allowed kafka API keys
@@ -225,6 +263,10 @@ var KafkaReverseAPIKeyMap = map[int16]string{
 	31: "deleteacls",           /* DeleteAcls */
 	32: "describeconfigs",      /* DescribeConfigs */
 	33: "alterconfigs",         /* AlterConfigs */
+	34: "alterreplicalogdirs",  /* AlterReplicaLogDirs */
+	35: "describelogdirs",      /* DescribeLogDirs */
+	36: "saslauthenticate",     /* SaslAuthenticate */
+	37: "createpartitions",     /* CreatePartitions */
 }
 
 // KafkaRole is the list of all low-level apiKeys to
@@ -274,13 +316,21 @@ func (kr *PortRuleKafka) GetAPIVersion() (int16, bool) {
 func (kr *PortRuleKafka) MapRoleToAPIKey() error {
 	// Expand the kr.apiKeyInt array based on the Role.
 	// For produce role, we need to add mandatory apiKeys produce, metadata and
-	// apiversions. While for consume, we need to add mandatory apiKeys like
+	// apiversions, as well as the transactional apiKeys if a transactional ID
+	// is given. While for consume, we need to add mandatory apiKeys like
 	// fetch, offsets, offsetcommit, offsetfetch, apiversions, metadata,
 	// findcoordinator, joingroup, heartbeat,
 	// leavegroup and syncgroup.
 	switch strings.ToLower(kr.Role) {
 	case ProduceRole:
 		kr.apiKeyInt = KafkaRole{ProduceKey, MetadataKey, APIVersionsKey}
+		// Transactional producers additionally need to find their
+		// transaction coordinator and to run the transactions.
+		if kr.TransactionalID != "" {
+			kr.apiKeyInt = append(kr.apiKeyInt, FindCoordinatorKey,
+				InitProducerIDKey, AddPartitionsToTxnKey, AddOffsetsToTxnKey,
+				EndTxnKey, TxnOffsetCommitKey)
+		}
 		return nil
 	case ConsumeRole:
 		kr.apiKeyInt = KafkaRole{FetchKey, OffsetsKey, MetadataKey,
@@ -57,6 +57,21 @@ type PortRule struct {
 	//
 	// +optional
 	Rules *L7Rules `json:"rules,omitempty"`
+
+	// OriginatingTLS is the TLS context used by the proxy to originate
+	// TLS connections to the upstream service. Only supported in egress
+	// rules with HTTP rules, which are enforced on the plaintext request
+	// sent by the endpoint before the proxy encrypts it with the client
+	// certificate of the TLS context.
+	//
+	// +optional
+	OriginatingTLS *TLSContext `json:"originatingTLS,omitempty"`
+
+	// CircuitBreaker limits the connections and requests the proxy
+	// forwards to the port. Only supported with HTTP and Kafka rules.
+	//
+	// +optional
+	CircuitBreaker *CircuitBreaker `json:"circuitBreaker,omitempty"`
 }
 
 // L7Rules is a union of port level rule types. Mixing of different port
@@ -73,6 +88,11 @@ type L7Rules struct {
 	// +optional
 	Kafka []PortRuleKafka `json:"kafka,omitempty"`
 
+	// DNS-specific rules.
+	//
+	// +optional
+	DNS []PortRuleDNS `json:"dns,omitempty"`
+
 	// Name of the L7 protocol for which the Key-value pair rules apply
 	//
 	// +optional
@@ -90,10 +110,10 @@ func (rules *L7Rules) Len() int {
 	if rules == nil {
 		return 0
 	}
-	return len(rules.HTTP) + len(rules.Kafka) + len(rules.L7)
+	return len(rules.HTTP) + len(rules.Kafka) + len(rules.DNS) + len(rules.L7)
 }
 
 // IsEmpty returns whether the `L7Rules` is nil or contains nil rules.
 func (rules *L7Rules) IsEmpty() bool {
-	return rules == nil || (rules.HTTP == nil && rules.Kafka == nil && rules.L7 == nil)
+	return rules == nil || (rules.HTTP == nil && rules.Kafka == nil && rules.DNS == nil && rules.L7 == nil)
 }
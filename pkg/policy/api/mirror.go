@@ -0,0 +1,63 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+// MirrorRule mirrors a copy of the packets sent and received by the endpoints
+// selected by the rule to a collector, e.g. an intrusion detection system.
+// Mirroring has no effect on whether the packets are allowed or not.
+type MirrorRule struct {
+	// ToPorts limits mirroring to packets from or to the given ports of
+	// the endpoint. If omitted or empty, all packets are mirrored.
+	//
+	// +optional
+	ToPorts []PortProtocol `json:"toPorts,omitempty"`
+
+	// Collector is the destination of the mirrored packets.
+	Collector MirrorCollector `json:"collector"`
+
+	// SamplingRate mirrors one out of SamplingRate packets. If omitted,
+	// 0 or 1, every packet is mirrored.
+	//
+	// +optional
+	SamplingRate uint32 `json:"samplingRate,omitempty"`
+}
+
+// MirrorCollector is the destination of mirrored packets. Exactly one of the
+// members must be specified.
+type MirrorCollector struct {
+	// Endpoint selects a local endpoint which receives the mirrored
+	// packets. If multiple local endpoints are selected, the one with the
+	// lowest endpoint ID is used.
+	//
+	// +optional
+	Endpoint *EndpointSelector `json:"endpoint,omitempty"`
+
+	// VXLAN sends the mirrored packets VXLAN encapsulated to a remote
+	// collector. Requires tunneling to be enabled.
+	//
+	// +optional
+	VXLAN *VXLANCollector `json:"vxlan,omitempty"`
+}
+
+// VXLANCollector is a remote collector receiving VXLAN encapsulated packets.
+type VXLANCollector struct {
+	// IP is the IPv4 address of the collector.
+	IP string `json:"ip"`
+
+	// VNI is the VXLAN network identifier of the mirrored packets.
+	//
+	// +optional
+	VNI uint32 `json:"vni,omitempty"`
+}
@@ -46,6 +46,12 @@ type Rule struct {
 	// +optional
 	Egress []EgressRule `json:"egress,omitempty"`
 
+	// Mirror is a list of MirrorRule which mirror the packets of the
+	// selected endpoints to a collector.
+	//
+	// +optional
+	Mirror []MirrorRule `json:"mirror,omitempty"`
+
 	// Labels is a list of optional strings which can be used to
 	// re-identify the rule or to store metadata. It is possible to lookup
 	// or delete strings based on labels. Labels are not required to be
@@ -65,6 +65,12 @@ func (r Rule) Sanitize() error {
 		}
 	}
 
+	for i := range r.Mirror {
+		if err := r.Mirror[i].sanitize(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -111,6 +117,9 @@ func (i *IngressRule) sanitize() error {
 		if err := i.ToPorts[n].sanitize(); err != nil {
 			return err
 		}
+		if i.ToPorts[n].OriginatingTLS != nil {
+			return fmt.Errorf("OriginatingTLS is only supported in egress rules")
+		}
 	}
 
 	prefixLengths := map[int]exists{}
@@ -295,6 +304,15 @@ func (pr *L7Rules) sanitize() error {
 		}
 	}
 
+	if pr.DNS != nil {
+		nTypes++
+		for i := range pr.DNS {
+			if err := pr.DNS[i].Sanitize(); err != nil {
+				return err
+			}
+		}
+	}
+
 	if pr.L7 != nil && pr.L7Proto == "" {
 		return fmt.Errorf("'l7' may only be specified when a 'l7proto' is also specified")
 	}
@@ -322,7 +340,10 @@ func (pr *PortRule) sanitize() error {
 			return err
 		}
 		if !pr.Rules.IsEmpty() && pr.Ports[i].Protocol != ProtoTCP {
-			return fmt.Errorf("L7 rules can only apply exclusively to TCP, not %s", pr.Ports[i].Protocol)
+			// DNS is commonly carried over UDP
+			if pr.Rules.DNS == nil || pr.Ports[i].Protocol != ProtoUDP {
+				return fmt.Errorf("L7 rules can only apply exclusively to TCP, not %s", pr.Ports[i].Protocol)
+			}
 		}
 	}
 
@@ -332,6 +353,71 @@ func (pr *PortRule) sanitize() error {
 			return err
 		}
 	}
+
+	if pr.OriginatingTLS != nil {
+		if pr.Rules == nil || len(pr.Rules.HTTP) == 0 {
+			return fmt.Errorf("OriginatingTLS requires HTTP rules")
+		}
+		if err := pr.OriginatingTLS.sanitize(); err != nil {
+			return err
+		}
+	}
+
+	if pr.CircuitBreaker != nil {
+		if pr.Rules == nil || (len(pr.Rules.HTTP) == 0 && len(pr.Rules.Kafka) == 0) {
+			return fmt.Errorf("CircuitBreaker requires HTTP or Kafka rules")
+		}
+	}
+	return nil
+}
+
+func (t *TLSContext) sanitize() error {
+	switch {
+	case t.Certificate == "":
+		return fmt.Errorf("TLS context must specify a certificate")
+	case t.PrivateKey == "":
+		return fmt.Errorf("TLS context must specify a private key")
+	case t.TrustedCA == "":
+		return fmt.Errorf("TLS context must specify trusted CAs")
+	}
+	for _, path := range []string{t.Certificate, t.PrivateKey, t.TrustedCA} {
+		if err := ValidateTLSPath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MirrorRule) sanitize() error {
+	if len(m.ToPorts) > maxPorts {
+		return fmt.Errorf("too many ports, the max is %d", maxPorts)
+	}
+	for i := range m.ToPorts {
+		if err := m.ToPorts[i].sanitize(); err != nil {
+			return err
+		}
+	}
+
+	c := &m.Collector
+	switch {
+	case c.Endpoint != nil && c.VXLAN != nil:
+		return fmt.Errorf("mirror collector cannot be both an endpoint and a VXLAN target")
+	case c.Endpoint != nil:
+		if c.Endpoint.LabelSelector == nil {
+			return fmt.Errorf("mirror collector cannot have nil EndpointSelector")
+		}
+		return c.Endpoint.sanitize()
+	case c.VXLAN != nil:
+		if ip := net.ParseIP(c.VXLAN.IP); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("invalid VXLAN collector IPv4 address %q", c.VXLAN.IP)
+		}
+		if c.VXLAN.VNI >= 1<<24 {
+			return fmt.Errorf("VXLAN collector VNI %d exceeds 24 bits", c.VXLAN.VNI)
+		}
+	default:
+		return fmt.Errorf("mirror collector must be specified")
+	}
+
 	return nil
 }
 
@@ -15,6 +15,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/cilium/cilium/pkg/labels"
 
 	. "gopkg.in/check.v1"
@@ -426,3 +428,219 @@ func (s *PolicyAPITestSuite) TestInvalidEndpointSelectors(c *C) {
 	c.Assert(err, Not(IsNil))
 
 }
+
+func (s *PolicyAPITestSuite) TestMirrorRuleSanitize(c *C) {
+	collectorSelector := NewESFromLabels(labels.ParseSelectLabel("app=ids"))
+
+	rule := Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Mirror: []MirrorRule{{
+			ToPorts:   []PortProtocol{{Port: "80", Protocol: "tcp"}},
+			Collector: MirrorCollector{Endpoint: &collectorSelector},
+		}},
+	}
+	c.Assert(rule.Sanitize(), IsNil)
+	c.Assert(rule.Mirror[0].ToPorts[0].Protocol, Equals, ProtoTCP)
+
+	rule.Mirror[0].Collector = MirrorCollector{VXLAN: &VXLANCollector{IP: "192.0.2.1", VNI: 10}}
+	c.Assert(rule.Sanitize(), IsNil)
+
+	// IPv6 collectors are not supported
+	rule.Mirror[0].Collector.VXLAN.IP = "f00d::1"
+	c.Assert(rule.Sanitize(), Not(IsNil))
+
+	rule.Mirror[0].Collector.VXLAN = &VXLANCollector{IP: "192.0.2.1", VNI: 1 << 24}
+	c.Assert(rule.Sanitize(), Not(IsNil))
+
+	rule.Mirror[0].Collector = MirrorCollector{}
+	c.Assert(rule.Sanitize(), Not(IsNil))
+
+	rule.Mirror[0].Collector = MirrorCollector{
+		Endpoint: &collectorSelector,
+		VXLAN:    &VXLANCollector{IP: "192.0.2.1"},
+	}
+	c.Assert(rule.Sanitize(), Not(IsNil))
+
+	rule.Mirror[0].Collector = MirrorCollector{Endpoint: &collectorSelector}
+	rule.Mirror[0].ToPorts = []PortProtocol{{Port: "0"}}
+	c.Assert(rule.Sanitize(), Not(IsNil))
+}
+
+// This test ensures that DNS rules may apply to UDP ports and that their
+// names and patterns are validated.
+func (s *PolicyAPITestSuite) TestDNSRuleSanitize(c *C) {
+	dnsRule := func(dns ...PortRuleDNS) Rule {
+		return Rule{
+			EndpointSelector: WildcardEndpointSelector,
+			Egress: []EgressRule{
+				{
+					ToPorts: []PortRule{{
+						Ports: []PortProtocol{
+							{Port: "53", Protocol: ProtoUDP},
+							{Port: "53", Protocol: ProtoTCP},
+						},
+						Rules: &L7Rules{
+							DNS: dns,
+						},
+					}},
+				},
+			},
+		}
+	}
+
+	validRule := dnsRule(
+		PortRuleDNS{},
+		PortRuleDNS{MatchName: "cilium.io"},
+		PortRuleDNS{MatchName: "Cilium.IO."},
+		PortRuleDNS{MatchPattern: "*"},
+		PortRuleDNS{MatchPattern: "*.cilium.io"},
+		PortRuleDNS{MatchPattern: "api-*.cilium.io."},
+	)
+	c.Assert(validRule.Sanitize(), IsNil)
+
+	invalidRule := dnsRule(PortRuleDNS{MatchName: "cilium.io", MatchPattern: "*.cilium.io"})
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+
+	invalidRule = dnsRule(PortRuleDNS{MatchName: "*.cilium.io"})
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+
+	invalidRule = dnsRule(PortRuleDNS{MatchName: "cilium..io"})
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+
+	invalidRule = dnsRule(PortRuleDNS{MatchPattern: "cilium.io/*"})
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+
+	// DNS rules cannot be combined with other L7 rules
+	invalidRule = dnsRule(PortRuleDNS{MatchName: "cilium.io"})
+	invalidRule.Egress[0].ToPorts[0].Ports = []PortProtocol{{Port: "53", Protocol: ProtoTCP}}
+	invalidRule.Egress[0].ToPorts[0].Rules.HTTP = []PortRuleHTTP{{Method: "GET"}}
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+}
+
+func (s *PolicyAPITestSuite) TestOriginatingTLSSanitize(c *C) {
+	tlsPortRule := func(rules *L7Rules, tls *TLSContext) PortRule {
+		return PortRule{
+			Ports:          []PortProtocol{{Port: "80", Protocol: ProtoTCP}},
+			Rules:          rules,
+			OriginatingTLS: tls,
+		}
+	}
+	httpRules := &L7Rules{HTTP: []PortRuleHTTP{{Method: "GET"}}}
+	tls := &TLSContext{
+		Certificate: "client.crt",
+		PrivateKey:  "client.key",
+		TrustedCA:   "ca.crt",
+	}
+
+	validRule := Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Egress:           []EgressRule{{ToPorts: []PortRule{tlsPortRule(httpRules, tls)}}},
+	}
+	c.Assert(validRule.Sanitize(), IsNil)
+
+	// TLS is only originated for egress traffic
+	invalidRule := Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Ingress:          []IngressRule{{ToPorts: []PortRule{tlsPortRule(httpRules, tls)}}},
+	}
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+
+	// HTTP rules are required
+	invalidRule = Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Egress:           []EgressRule{{ToPorts: []PortRule{tlsPortRule(nil, tls)}}},
+	}
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+
+	invalidRule.Egress[0].ToPorts[0].Rules = &L7Rules{Kafka: []PortRuleKafka{{Topic: "foo"}}}
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+
+	// Certificate, private key and trusted CAs are required
+	invalidRule = Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Egress:           []EgressRule{{ToPorts: []PortRule{tlsPortRule(httpRules, &TLSContext{Certificate: tls.Certificate, TrustedCA: tls.TrustedCA})}}},
+	}
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+
+	// Files must be within the TLS directory
+	for _, path := range []string{"/etc/cilium/tls/client.key", "../client.key", "certs/../../client.key", ".."} {
+		invalidRule.Egress[0].ToPorts[0].OriginatingTLS = &TLSContext{Certificate: tls.Certificate, PrivateKey: path, TrustedCA: tls.TrustedCA}
+		c.Assert(invalidRule.Sanitize(), Not(IsNil), Commentf("path %q", path))
+	}
+	invalidRule.Egress[0].ToPorts[0].OriginatingTLS = &TLSContext{Certificate: "payments/client.crt", PrivateKey: "payments/../payments/client.key", TrustedCA: tls.TrustedCA}
+	c.Assert(invalidRule.Sanitize(), IsNil)
+}
+
+func (s *PolicyAPITestSuite) TestCircuitBreakerSanitize(c *C) {
+	cbPortRule := func(rules *L7Rules) PortRule {
+		return PortRule{
+			Ports:          []PortProtocol{{Port: "80", Protocol: ProtoTCP}},
+			Rules:          rules,
+			CircuitBreaker: &CircuitBreaker{MaxConnections: 100, MaxPendingRequests: 10},
+		}
+	}
+
+	validRule := Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Ingress: []IngressRule{{ToPorts: []PortRule{
+			cbPortRule(&L7Rules{HTTP: []PortRuleHTTP{{Method: "GET"}}}),
+		}}},
+		Egress: []EgressRule{{ToPorts: []PortRule{
+			cbPortRule(&L7Rules{Kafka: []PortRuleKafka{{Topic: "foo"}}}),
+		}}},
+	}
+	c.Assert(validRule.Sanitize(), IsNil)
+
+	// HTTP or Kafka rules are required
+	invalidRule := Rule{
+		EndpointSelector: WildcardEndpointSelector,
+		Ingress:          []IngressRule{{ToPorts: []PortRule{cbPortRule(nil)}}},
+	}
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+
+	invalidRule.Ingress[0].ToPorts[0].Ports = []PortProtocol{{Port: "53", Protocol: ProtoUDP}}
+	invalidRule.Ingress[0].ToPorts[0].Rules = &L7Rules{DNS: []PortRuleDNS{{MatchName: "cilium.io"}}}
+	c.Assert(invalidRule.Sanitize(), Not(IsNil))
+}
+
+func (s *PolicyAPITestSuite) TestHTTPTimeoutAndRetriesSanitize(c *C) {
+	httpRule := func(h PortRuleHTTP) Rule {
+		return Rule{
+			EndpointSelector: WildcardEndpointSelector,
+			Egress: []EgressRule{{ToPorts: []PortRule{{
+				Ports: []PortProtocol{{Port: "80", Protocol: ProtoTCP}},
+				Rules: &L7Rules{HTTP: []PortRuleHTTP{h}},
+			}}}},
+		}
+	}
+
+	validRule := httpRule(PortRuleHTTP{
+		Method:  "GET",
+		Timeout: "1.5s",
+		Retries: &HTTPRetryPolicy{
+			NumRetries:    3,
+			PerTryTimeout: "250ms",
+			RetryOn:       []string{"gateway-error", "refused-stream"},
+		},
+	})
+	c.Assert(validRule.Sanitize(), IsNil)
+
+	h := validRule.Egress[0].ToPorts[0].Rules.HTTP[0]
+	c.Assert(h.GetTimeout(), Equals, 1500*time.Millisecond)
+	c.Assert(h.Retries.GetPerTryTimeout(), Equals, 250*time.Millisecond)
+	c.Assert(h.Retries.GetRetryOn(), Equals, "gateway-error,refused-stream")
+	c.Assert((&HTTPRetryPolicy{NumRetries: 1}).GetRetryOn(), Equals, "5xx,connect-failure")
+
+	for _, h := range []PortRuleHTTP{
+		{Timeout: "5"},
+		{Timeout: "-1s"},
+		{Timeout: "0s"},
+		{Timeout: "500us"},
+		{Retries: &HTTPRetryPolicy{}},
+		{Retries: &HTTPRetryPolicy{NumRetries: 1, PerTryTimeout: "soon"}},
+		{Retries: &HTTPRetryPolicy{NumRetries: 1, RetryOn: []string{"5xx", "always"}}},
+	} {
+		invalidRule := httpRule(h)
+		c.Assert(invalidRule.Sanitize(), Not(IsNil), Commentf("rule %+v", h))
+	}
+}
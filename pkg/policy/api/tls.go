@@ -0,0 +1,66 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// TLSContext provides the client certificate and trusted CAs used by the
+// proxy to originate TLS connections to upstream services. All files are
+// PEM encoded and read from the TLS directory of the node running the proxy,
+// their paths are relative to that directory.
+type TLSContext struct {
+	// Certificate is the path to the certificate chain presented to the
+	// upstream service as the identity of the workload.
+	Certificate string `json:"certificate"`
+
+	// PrivateKey is the path to the private key of Certificate.
+	PrivateKey string `json:"privateKey"`
+
+	// TrustedCA is the path to the CA certificates used to verify the
+	// certificate of the upstream service.
+	TrustedCA string `json:"trustedCA"`
+
+	// ServerName is the server name (SNI) requested by the proxy in the
+	// TLS handshake. If omitted, no server name is sent.
+	//
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// Equal returns true if both TLS contexts are nil or refer to the same
+// files and server name.
+func (t *TLSContext) Equal(o *TLSContext) bool {
+	if t == nil || o == nil {
+		return t == o
+	}
+	return *t == *o
+}
+
+// ValidateTLSPath returns an error unless path is a relative path which stays
+// within the directory it is relative to.
+func ValidateTLSPath(path string) error {
+	if filepath.IsAbs(path) {
+		return fmt.Errorf("TLS path %q must be relative to the TLS directory", path)
+	}
+	cleaned := filepath.Clean(path)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("TLS path %q must refer to a file within the TLS directory", path)
+	}
+	return nil
+}
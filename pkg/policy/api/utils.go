@@ -35,6 +35,9 @@ func (h *PortRuleHTTP) Equal(o PortRuleHTTP) bool {
 	if h.Path != o.Path ||
 		h.Method != o.Method ||
 		h.Host != o.Host ||
+		h.InjectIdentityHeaders != o.InjectIdentityHeaders ||
+		h.Timeout != o.Timeout ||
+		!h.Retries.Equal(o.Retries) ||
 		len(h.Headers) != len(o.Headers) {
 		return false
 	}
@@ -61,7 +64,24 @@ func (k *PortRuleKafka) Exists(rules L7Rules) bool {
 // Equal returns true if both rules are equal
 func (k *PortRuleKafka) Equal(o PortRuleKafka) bool {
 	return k.APIVersion == o.APIVersion && k.APIKey == o.APIKey &&
-		k.Topic == o.Topic && k.ClientID == o.ClientID && k.Role == o.Role
+		k.Topic == o.Topic && k.ClientID == o.ClientID && k.Role == o.Role &&
+		k.GroupID == o.GroupID && k.TransactionalID == o.TransactionalID
+}
+
+// Exists returns true if the DNS rule already exists in the list of rules
+func (d *PortRuleDNS) Exists(rules L7Rules) bool {
+	for _, existingRule := range rules.DNS {
+		if d.Equal(existingRule) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Equal returns true if both DNS rules are equal
+func (d *PortRuleDNS) Equal(o PortRuleDNS) bool {
+	return d.MatchName == o.MatchName && d.MatchPattern == o.MatchPattern
 }
 
 // Exists returns true if the L7 rule already exists in the list of rules
@@ -38,6 +38,14 @@ func (s *PolicyAPITestSuite) TestHTTPEqual(c *C) {
 	c.Assert(rule1.Equal(rule2), Equals, false)
 	c.Assert(rule1.Equal(rule3), Equals, false)
 
+	rule4 := PortRuleHTTP{Path: "/foo$", Method: "GET", Headers: []string{"X-Test: Foo"}, Timeout: "1s"}
+	rule5 := PortRuleHTTP{Path: "/foo$", Method: "GET", Headers: []string{"X-Test: Foo"}, Retries: &HTTPRetryPolicy{NumRetries: 2}}
+	rule6 := PortRuleHTTP{Path: "/foo$", Method: "GET", Headers: []string{"X-Test: Foo"}, Retries: &HTTPRetryPolicy{NumRetries: 2, RetryOn: []string{"5xx"}}}
+	c.Assert(rule1.Equal(rule4), Equals, false)
+	c.Assert(rule1.Equal(rule5), Equals, false)
+	c.Assert(rule5.Equal(rule5), Equals, true)
+	c.Assert(rule5.Equal(rule6), Equals, false)
+
 	rules := L7Rules{
 		HTTP: []PortRuleHTTP{rule1, rule2},
 	}
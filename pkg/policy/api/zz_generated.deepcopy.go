@@ -44,6 +44,22 @@ func (in *CIDRRule) DeepCopy() *CIDRRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreaker) DeepCopyInto(out *CircuitBreaker) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreaker.
+func (in *CircuitBreaker) DeepCopy() *CircuitBreaker {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreaker)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in CIDRRuleSlice) DeepCopyInto(out *CIDRRuleSlice) {
 	{
@@ -242,6 +258,27 @@ func (in *FQDNSelector) DeepCopy() *FQDNSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRetryPolicy) DeepCopyInto(out *HTTPRetryPolicy) {
+	*out = *in
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRetryPolicy.
+func (in *HTTPRetryPolicy) DeepCopy() *HTTPRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressRule) DeepCopyInto(out *IngressRule) {
 	*out = *in
@@ -366,6 +403,11 @@ func (in *L7Rules) DeepCopyInto(out *L7Rules) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = make([]PortRuleDNS, len(*in))
+		copy(*out, *in)
+	}
 	if in.L7 != nil {
 		in, out := &in.L7, &out.L7
 		*out = make([]PortRuleL7, len(*in))
@@ -392,6 +434,54 @@ func (in *L7Rules) DeepCopy() *L7Rules {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirrorCollector) DeepCopyInto(out *MirrorCollector) {
+	*out = *in
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(EndpointSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VXLAN != nil {
+		in, out := &in.VXLAN, &out.VXLAN
+		*out = new(VXLANCollector)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorCollector.
+func (in *MirrorCollector) DeepCopy() *MirrorCollector {
+	if in == nil {
+		return nil
+	}
+	out := new(MirrorCollector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirrorRule) DeepCopyInto(out *MirrorRule) {
+	*out = *in
+	if in.ToPorts != nil {
+		in, out := &in.ToPorts, &out.ToPorts
+		*out = make([]PortProtocol, len(*in))
+		copy(*out, *in)
+	}
+	in.Collector.DeepCopyInto(&out.Collector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirrorRule.
+func (in *MirrorRule) DeepCopy() *MirrorRule {
+	if in == nil {
+		return nil
+	}
+	out := new(MirrorRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PortProtocol) DeepCopyInto(out *PortProtocol) {
 	*out = *in
@@ -421,6 +511,16 @@ func (in *PortRule) DeepCopyInto(out *PortRule) {
 		*out = new(L7Rules)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OriginatingTLS != nil {
+		in, out := &in.OriginatingTLS, &out.OriginatingTLS
+		*out = new(TLSContext)
+		**out = **in
+	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(CircuitBreaker)
+		**out = **in
+	}
 	return
 }
 
@@ -434,6 +534,22 @@ func (in *PortRule) DeepCopy() *PortRule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PortRuleDNS) DeepCopyInto(out *PortRuleDNS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PortRuleDNS.
+func (in *PortRuleDNS) DeepCopy() *PortRuleDNS {
+	if in == nil {
+		return nil
+	}
+	out := new(PortRuleDNS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PortRuleHTTP) DeepCopyInto(out *PortRuleHTTP) {
 	*out = *in
@@ -442,6 +558,11 @@ func (in *PortRuleHTTP) DeepCopyInto(out *PortRuleHTTP) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Retries != nil {
+		in, out := &in.Retries, &out.Retries
+		*out = new(HTTPRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -521,6 +642,13 @@ func (in *Rule) DeepCopyInto(out *Rule) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = make([]MirrorRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	out.Labels = in.Labels.DeepCopy()
 	return
 }
@@ -618,3 +746,35 @@ func (in *ServiceSelector) DeepCopy() *ServiceSelector {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSContext) DeepCopyInto(out *TLSContext) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSContext.
+func (in *TLSContext) DeepCopy() *TLSContext {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSContext)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VXLANCollector) DeepCopyInto(out *VXLANCollector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VXLANCollector.
+func (in *VXLANCollector) DeepCopy() *VXLANCollector {
+	if in == nil {
+		return nil
+	}
+	out := new(VXLANCollector)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -84,6 +84,8 @@ const (
 	ParserTypeHTTP L7ParserType = "http"
 	// ParserTypeKafka specifies a Kafka parser type
 	ParserTypeKafka L7ParserType = "kafka"
+	// ParserTypeDNS specifies a DNS parser type
+	ParserTypeDNS L7ParserType = "dns"
 )
 
 type L4Filter struct {
@@ -104,6 +106,12 @@ type L4Filter struct {
 	L7RulesPerEp L7DataMap `json:"l7-rules,omitempty"`
 	// Ingress is true if filter applies at ingress; false if it applies at egress.
 	Ingress bool `json:"-"`
+	// OriginatingTLS is the TLS context used by the proxy to originate TLS
+	// connections to the upstream service (optional, egress only).
+	OriginatingTLS *api.TLSContext `json:"originating-tls,omitempty"`
+	// CircuitBreaker limits the connections and requests forwarded by the
+	// proxy (optional).
+	CircuitBreaker *api.CircuitBreaker `json:"circuit-breaker,omitempty"`
 	// The rule labels of this Filter
 	DerivedFromRules labels.LabelArrayList `json:"-"`
 }
@@ -123,6 +131,7 @@ func (l7 L7DataMap) GetRelevantRules(identity *identity.Identity) api.L7Rules {
 			if selector.Matches(identity.Labels.LabelArray()) {
 				rules.HTTP = append(rules.HTTP, endpointRules.HTTP...)
 				rules.Kafka = append(rules.Kafka, endpointRules.Kafka...)
+				rules.DNS = append(rules.DNS, endpointRules.DNS...)
 				rules.L7Proto = endpointRules.L7Proto
 				rules.L7 = append(rules.L7, endpointRules.L7...)
 			}
@@ -133,6 +142,7 @@ func (l7 L7DataMap) GetRelevantRules(identity *identity.Identity) api.L7Rules {
 	if r, ok := l7[api.WildcardEndpointSelector]; ok {
 		rules.HTTP = append(rules.HTTP, r.HTTP...)
 		rules.Kafka = append(rules.Kafka, r.Kafka...)
+		rules.DNS = append(rules.DNS, r.DNS...)
 		rules.L7Proto = r.L7Proto // XXX
 		rules.L7 = append(rules.L7, r.L7...)
 	}
@@ -182,12 +192,15 @@ func CreateL4Filter(peerEndpoints api.EndpointSelectorSlice, rule api.PortRule,
 		Ingress:          ingress,
 	}
 
-	if protocol == api.ProtoTCP && rule.Rules != nil {
+	// DNS rules are the only L7 rules which also apply to UDP
+	if rule.Rules != nil && (protocol == api.ProtoTCP || protocol == api.ProtoUDP && len(rule.Rules.DNS) > 0) {
 		switch {
 		case len(rule.Rules.HTTP) > 0:
 			l4.L7Parser = ParserTypeHTTP
 		case len(rule.Rules.Kafka) > 0:
 			l4.L7Parser = ParserTypeKafka
+		case len(rule.Rules.DNS) > 0:
+			l4.L7Parser = ParserTypeDNS
 		case rule.Rules.L7Proto != "":
 			l4.L7Parser = (L7ParserType)(rule.Rules.L7Proto)
 		}
@@ -196,6 +209,14 @@ func CreateL4Filter(peerEndpoints api.EndpointSelectorSlice, rule api.PortRule,
 		}
 	}
 
+	if !ingress && l4.L7Parser == ParserTypeHTTP {
+		l4.OriginatingTLS = rule.OriginatingTLS
+	}
+
+	if l4.L7Parser == ParserTypeHTTP || l4.L7Parser == ParserTypeKafka {
+		l4.CircuitBreaker = rule.CircuitBreaker
+	}
+
 	return l4
 }
 
@@ -287,6 +308,29 @@ func (l4 L4PolicyMap) HasRedirect() bool {
 	return false
 }
 
+// AllowingSource returns the L4 filters of an ingress L4PolicyMap which allow
+// traffic from the provided source labels. The L7 rules of the returned
+// filters are restricted to those applying to the source.
+func (l4 L4PolicyMap) AllowingSource(labels labels.LabelArray) L4PolicyMap {
+	result := L4PolicyMap{}
+	for key, filter := range l4 {
+		if !filter.matchesLabels(labels) {
+			continue
+		}
+		if len(filter.L7RulesPerEp) > 0 {
+			l7Rules := L7DataMap{}
+			for selector, rules := range filter.L7RulesPerEp {
+				if selector.Matches(labels) {
+					l7Rules[selector] = rules
+				}
+			}
+			filter.L7RulesPerEp = l7Rules
+		}
+		result[key] = filter
+	}
+	return result
+}
+
 // containsAllL3L4 checks if the L4PolicyMap contains all L4 ports in `ports`.
 // For L4Filters that specify ToEndpoints or FromEndpoints, uses `labels` to
 // determine whether the policy allows L4 communication between the corresponding
@@ -244,3 +244,54 @@ func (s *PolicyTestSuite) TestJSONMarshal(c *C) {
 		c.Assert(model.Ingress[i].Rule, Equals, expectedIngress[i])
 	}
 }
+
+func (s *PolicyTestSuite) TestAllowingSource(c *C) {
+	fooSelector := api.NewESFromLabels(labels.ParseSelectLabel("foo"))
+	barSelector := api.NewESFromLabels(labels.ParseSelectLabel("bar"))
+	wildcardSelector := api.NewESFromLabels()
+
+	fooRules := api.L7Rules{HTTP: []api.PortRuleHTTP{{Path: "/foo", Method: "GET"}}}
+	wildcardRules := api.L7Rules{HTTP: []api.PortRuleHTTP{{Path: "/", Method: "GET"}}}
+
+	l4 := L4PolicyMap{
+		"80/TCP": {
+			Port: 80, Protocol: api.ProtoTCP,
+			Endpoints: []api.EndpointSelector{fooSelector, barSelector},
+			L7Parser:  "http",
+			L7RulesPerEp: L7DataMap{
+				fooSelector:      fooRules,
+				wildcardSelector: wildcardRules,
+			},
+			Ingress: true,
+		},
+		"8080/TCP": {
+			Port: 8080, Protocol: api.ProtoTCP,
+			Endpoints: []api.EndpointSelector{barSelector},
+			Ingress:   true,
+		},
+		"53/UDP": {
+			Port: 53, Protocol: api.ProtoUDP,
+			Ingress: true,
+		},
+	}
+
+	fromFoo := l4.AllowingSource(labels.ParseSelectLabelArray("foo"))
+	c.Assert(len(fromFoo), Equals, 2)
+	c.Assert(fromFoo["80/TCP"].L7RulesPerEp, checker.DeepEquals, L7DataMap{
+		fooSelector:      fooRules,
+		wildcardSelector: wildcardRules,
+	})
+	c.Assert(fromFoo["53/UDP"].Port, Equals, 53)
+
+	fromBar := l4.AllowingSource(labels.ParseSelectLabelArray("bar"))
+	c.Assert(len(fromBar), Equals, 3)
+	c.Assert(fromBar["80/TCP"].L7RulesPerEp, checker.DeepEquals, L7DataMap{
+		wildcardSelector: wildcardRules,
+	})
+
+	// The original map is not modified
+	c.Assert(len(l4["80/TCP"].L7RulesPerEp), Equals, 2)
+
+	fromBaz := l4.AllowingSource(labels.ParseSelectLabelArray("baz"))
+	c.Assert(fromBaz, checker.DeepEquals, L4PolicyMap{"53/UDP": l4["53/UDP"]})
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"reflect"
+
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// MirrorPolicy is the packet mirroring configuration of an endpoint, merged
+// from all mirror rules selecting the endpoint.
+//
+// The datapath supports a single collector per endpoint. Rules mirroring to
+// the same collector with the same sampling rate are merged, rules with a
+// different collector or sampling rate than the first selecting rule are
+// ignored.
+type MirrorPolicy struct {
+	// Ports is the list of ports for which packets are mirrored. If
+	// empty, all packets are mirrored.
+	Ports []api.PortProtocol
+
+	// Collector is the destination of the mirrored packets.
+	Collector api.MirrorCollector
+
+	// SamplingRate mirrors one out of SamplingRate packets.
+	SamplingRate uint32
+
+	// DerivedFromRules is the list of labels of the rules which were
+	// merged into this policy.
+	DerivedFromRules labels.LabelArrayList
+
+	// IgnoredRules is the list of labels of the rules which were ignored
+	// because they conflict with the collector of the policy.
+	IgnoredRules labels.LabelArrayList
+}
+
+// newMirrorPolicy returns the MirrorPolicy of a single mirror rule.
+func newMirrorPolicy(m *api.MirrorRule, ruleLabels labels.LabelArray) *MirrorPolicy {
+	policy := &MirrorPolicy{
+		Collector:        m.Collector,
+		SamplingRate:     m.SamplingRate,
+		DerivedFromRules: labels.LabelArrayList{ruleLabels},
+	}
+	if policy.SamplingRate == 0 {
+		policy.SamplingRate = 1
+	}
+	policy.Ports = append(policy.Ports, m.ToPorts...)
+	return policy
+}
+
+// merge merges the mirror rule m into the policy. Returns false if m
+// conflicts with the policy.
+func (p *MirrorPolicy) merge(m *api.MirrorRule, ruleLabels labels.LabelArray) bool {
+	other := newMirrorPolicy(m, ruleLabels)
+	if other.SamplingRate != p.SamplingRate ||
+		!reflect.DeepEqual(other.Collector, p.Collector) {
+		p.IgnoredRules = append(p.IgnoredRules, ruleLabels)
+		return false
+	}
+
+	p.DerivedFromRules = append(p.DerivedFromRules, ruleLabels)
+
+	// An empty list of ports mirrors all packets
+	if len(p.Ports) == 0 || len(other.Ports) == 0 {
+		p.Ports = nil
+		return true
+	}
+
+	for _, port := range other.Ports {
+		if !p.hasPort(port) {
+			p.Ports = append(p.Ports, port)
+		}
+	}
+	return true
+}
+
+func (p *MirrorPolicy) hasPort(port api.PortProtocol) bool {
+	for _, existing := range p.Ports {
+		if existing == port {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveMirrorPolicy merges the mirror rules of r into result if r selects
+// ctx.To and returns the resulting MirrorPolicy.
+func (r *rule) resolveMirrorPolicy(ctx *SearchContext, state *traceState, result *MirrorPolicy) *MirrorPolicy {
+	if !r.EndpointSelector.Matches(ctx.To) {
+		state.unSelectRule(ctx, ctx.To, r)
+		return result
+	}
+
+	if len(r.Mirror) == 0 {
+		ctx.PolicyTraceVerbose("  Rule %s: no mirror rules\n", r)
+		return result
+	}
+
+	state.selectRule(ctx, r)
+
+	for i := range r.Mirror {
+		m := &r.Mirror[i]
+		if result == nil {
+			ctx.PolicyTrace("  Mirrors to %+v\n", m.Collector)
+			result = newMirrorPolicy(m, r.Labels)
+		} else if !result.merge(m, r.Labels) {
+			ctx.PolicyTrace("  Ignoring mirror to %+v, conflicts with collector %+v\n",
+				m.Collector, result.Collector)
+		}
+	}
+
+	return result
+}
+
+// ResolveMirrorPolicy resolves the packet mirroring configuration of the
+// endpoint with the labels ctx.To. Returns nil if no mirror rule selects the
+// endpoint. The policy repository mutex must be held.
+func (p *Repository) ResolveMirrorPolicy(ctx *SearchContext) *MirrorPolicy {
+	var result *MirrorPolicy
+
+	ctx.PolicyTrace("Resolving mirror policy for %+v\n", ctx.To)
+
+	state := traceState{}
+	for _, r := range p.rules {
+		result = r.resolveMirrorPolicy(ctx, &state, result)
+		state.ruleID++
+	}
+
+	state.trace(p, ctx)
+	return result
+}
@@ -0,0 +1,105 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"github.com/cilium/cilium/pkg/checker"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+
+	. "gopkg.in/check.v1"
+)
+
+func (ds *PolicyTestSuite) TestResolveMirrorPolicy(c *C) {
+	repo := NewPolicyRepository()
+
+	collector := api.MirrorCollector{VXLAN: &api.VXLANCollector{IP: "192.0.2.1"}}
+	otherCollector := api.MirrorCollector{VXLAN: &api.VXLANCollector{IP: "192.0.2.2"}}
+	lbls1 := labels.LabelArray{labels.ParseLabel("rule1")}
+	lbls2 := labels.LabelArray{labels.ParseLabel("rule2")}
+	lbls3 := labels.LabelArray{labels.ParseLabel("rule3")}
+
+	rules := api.Rules{
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("foo")),
+			Mirror: []api.MirrorRule{{
+				ToPorts:   []api.PortProtocol{{Port: "80", Protocol: api.ProtoTCP}},
+				Collector: collector,
+			}},
+			Labels: lbls1,
+		},
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("foo")),
+			Mirror: []api.MirrorRule{{
+				ToPorts: []api.PortProtocol{
+					{Port: "80", Protocol: api.ProtoTCP},
+					{Port: "53", Protocol: api.ProtoUDP},
+				},
+				Collector: collector,
+			}},
+			Labels: lbls2,
+		},
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("foo")),
+			Mirror: []api.MirrorRule{{
+				Collector: otherCollector,
+			}},
+			Labels: lbls3,
+		},
+	}
+	repo.AddList(rules)
+
+	repo.Mutex.RLock()
+	defer repo.Mutex.RUnlock()
+
+	ctx := &SearchContext{To: labels.ParseSelectLabelArray("bar")}
+	c.Assert(repo.ResolveMirrorPolicy(ctx), IsNil)
+
+	ctx = &SearchContext{To: labels.ParseSelectLabelArray("foo")}
+	c.Assert(repo.ResolveMirrorPolicy(ctx), checker.DeepEquals, &MirrorPolicy{
+		Ports: []api.PortProtocol{
+			{Port: "80", Protocol: api.ProtoTCP},
+			{Port: "53", Protocol: api.ProtoUDP},
+		},
+		Collector:        collector,
+		SamplingRate:     1,
+		DerivedFromRules: labels.LabelArrayList{lbls1, lbls2},
+		IgnoredRules:     labels.LabelArrayList{lbls3},
+	})
+}
+
+func (ds *PolicyTestSuite) TestMirrorPolicyMergeAllPorts(c *C) {
+	collector := api.MirrorCollector{VXLAN: &api.VXLANCollector{IP: "192.0.2.1"}}
+
+	p := newMirrorPolicy(&api.MirrorRule{
+		ToPorts:      []api.PortProtocol{{Port: "80", Protocol: api.ProtoTCP}},
+		Collector:    collector,
+		SamplingRate: 10,
+	}, nil)
+
+	// Different sampling rates conflict
+	c.Assert(p.merge(&api.MirrorRule{Collector: collector}, nil), Equals, false)
+
+	// A rule without ports mirrors all packets
+	c.Assert(p.merge(&api.MirrorRule{Collector: collector, SamplingRate: 10}, nil), Equals, true)
+	c.Assert(len(p.Ports), Equals, 0)
+
+	c.Assert(p.merge(&api.MirrorRule{
+		ToPorts:      []api.PortProtocol{{Port: "443", Protocol: api.ProtoTCP}},
+		Collector:    collector,
+		SamplingRate: 10,
+	}, nil), Equals, true)
+	c.Assert(len(p.Ports), Equals, 0)
+}
@@ -150,6 +150,13 @@ func wildcardL3L4Rule(proto api.L4Proto, port int, endpoints api.EndpointSelecto
 					Kafka: []api.PortRuleKafka{rule},
 				}
 			}
+		case ParserTypeDNS:
+			// Wildcard at L7 all the endpoints allowed at L3 or L4.
+			for _, sel := range endpoints {
+				filter.L7RulesPerEp[sel] = api.L7Rules{
+					DNS: []api.PortRuleDNS{{}},
+				}
+			}
 		default:
 			// Wildcard at L7 all the endpoints allowed at L3 or L4.
 			for _, sel := range endpoints {
@@ -642,6 +649,51 @@ func (p *Repository) GetRulesMatching(labels labels.LabelArray) (ingressMatch bo
 	return
 }
 
+// GetIngressRuleLabelsMatching returns the labels of the rules in the
+// repository which select the destination LabelArray `to` and contain an
+// ingress rule allowing traffic from the source LabelArray `from`, in the
+// order of the repository. As FromRequires of any rule selecting `to`
+// applies to all of its ingress rules, no rule is returned if `from` does
+// not meet all of them.
+//
+// Must be called with p.Mutex held
+func (p *Repository) GetIngressRuleLabelsMatching(from, to labels.LabelArray) labels.LabelArrayList {
+	result := labels.LabelArrayList{}
+
+	for _, r := range p.rules {
+		if !r.EndpointSelector.Matches(to) {
+			continue
+		}
+		for _, ingressRule := range r.Ingress {
+			for _, requirement := range ingressRule.FromRequires {
+				if !requirement.Matches(from) {
+					return result
+				}
+			}
+		}
+	}
+
+	for _, r := range p.rules {
+		if !r.EndpointSelector.Matches(to) {
+			continue
+		}
+		for _, ingressRule := range r.Ingress {
+			// FromRequires only restricts the sources allowed by other
+			// rules, it does not allow anything by itself.
+			sources := ingressRule.GetSourceEndpointSelectors()
+			if len(sources) == 0 && len(ingressRule.FromRequires) > 0 {
+				continue
+			}
+			if len(sources) == 0 || sources.Matches(from) {
+				result = append(result, r.Labels)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
 // NumRules returns the amount of rules in the policy repository.
 //
 // Must be called with p.Mutex held
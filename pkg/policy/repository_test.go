@@ -111,6 +111,95 @@ func (ds *PolicyTestSuite) TestAddSearchDelete(c *C) {
 	repo.Mutex.RUnlock()
 }
 
+func (ds *PolicyTestSuite) TestGetIngressRuleLabelsMatching(c *C) {
+	repo := NewPolicyRepository()
+
+	tag1 := labels.LabelArray{labels.ParseLabel("tag1")}
+	tag2 := labels.LabelArray{labels.ParseLabel("tag2")}
+	tag3 := labels.LabelArray{labels.ParseLabel("tag3")}
+	fromFoo := []api.IngressRule{
+		{
+			FromEndpoints: []api.EndpointSelector{
+				api.NewESFromLabels(labels.ParseSelectLabel("foo")),
+			},
+		},
+	}
+	toFoo := []api.EgressRule{
+		{
+			ToEndpoints: []api.EndpointSelector{
+				api.NewESFromLabels(labels.ParseSelectLabel("foo")),
+			},
+		},
+	}
+
+	repo.AddList(api.Rules{
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+			Ingress:          fromFoo,
+			Labels:           tag1,
+		},
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+			Egress:           toFoo,
+			Labels:           tag2,
+		},
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("baz")),
+			Ingress:          fromFoo,
+			Labels:           tag3,
+		},
+	})
+
+	repo.Mutex.RLock()
+	defer repo.Mutex.RUnlock()
+	foo := labels.ParseSelectLabelArray("foo")
+	c.Assert(repo.GetIngressRuleLabelsMatching(foo, labels.ParseSelectLabelArray("bar")), checker.DeepEquals, labels.LabelArrayList{tag1})
+	c.Assert(repo.GetIngressRuleLabelsMatching(foo, labels.ParseSelectLabelArray("bar", "baz")), checker.DeepEquals, labels.LabelArrayList{tag1, tag3})
+	c.Assert(repo.GetIngressRuleLabelsMatching(foo, labels.ParseSelectLabelArray("foo")), checker.DeepEquals, labels.LabelArrayList{})
+
+	// Rules whose ingress rules do not allow the source are not matched
+	c.Assert(repo.GetIngressRuleLabelsMatching(labels.ParseSelectLabelArray("qux"), labels.ParseSelectLabelArray("bar", "baz")), checker.DeepEquals, labels.LabelArrayList{})
+}
+
+func (ds *PolicyTestSuite) TestGetIngressRuleLabelsMatchingFromRequires(c *C) {
+	repo := NewPolicyRepository()
+
+	tag1 := labels.LabelArray{labels.ParseLabel("tag1")}
+	tag2 := labels.LabelArray{labels.ParseLabel("tag2")}
+
+	repo.AddList(api.Rules{
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+			Ingress: []api.IngressRule{
+				{
+					// Allows all sources
+					ToPorts: []api.PortRule{{
+						Ports: []api.PortProtocol{{Port: "80", Protocol: api.ProtoTCP}},
+					}},
+				},
+			},
+			Labels: tag1,
+		},
+		{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+			Ingress: []api.IngressRule{
+				{
+					FromRequires: []api.EndpointSelector{
+						api.NewESFromLabels(labels.ParseSelectLabel("prod")),
+					},
+				},
+			},
+			Labels: tag2,
+		},
+	})
+
+	repo.Mutex.RLock()
+	defer repo.Mutex.RUnlock()
+	bar := labels.ParseSelectLabelArray("bar")
+	c.Assert(repo.GetIngressRuleLabelsMatching(labels.ParseSelectLabelArray("foo", "prod"), bar), checker.DeepEquals, labels.LabelArrayList{tag1})
+	c.Assert(repo.GetIngressRuleLabelsMatching(labels.ParseSelectLabelArray("foo"), bar), checker.DeepEquals, labels.LabelArrayList{})
+}
+
 func (ds *PolicyTestSuite) TestContainsAllRLocked(c *C) {
 	a := []labels.LabelArray{
 		{
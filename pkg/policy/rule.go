@@ -58,11 +58,27 @@ func mergeL4Port(ctx *SearchContext, endpoints []api.EndpointSelector, existingF
 		}
 	}
 
+	// The TLS context applies to all destinations of the filter, so a rule
+	// without TLS context cannot be merged with a rule originating TLS.
+	if !filterToMerge.OriginatingTLS.Equal(existingFilter.OriginatingTLS) {
+		ctx.PolicyTrace("   Merge conflict: mismatching originating TLS contexts\n")
+		return fmt.Errorf("Cannot merge conflicting originating TLS contexts")
+	}
+
+	if filterToMerge.CircuitBreaker != nil {
+		if existingFilter.CircuitBreaker == nil {
+			existingFilter.CircuitBreaker = filterToMerge.CircuitBreaker
+		} else if !filterToMerge.CircuitBreaker.Equal(existingFilter.CircuitBreaker) {
+			ctx.PolicyTrace("   Merge conflict: mismatching circuit breakers\n")
+			return fmt.Errorf("Cannot merge conflicting circuit breakers")
+		}
+	}
+
 	for hash, newL7Rules := range filterToMerge.L7RulesPerEp {
 		if ep, ok := existingFilter.L7RulesPerEp[hash]; ok {
 			switch {
 			case len(newL7Rules.HTTP) > 0:
-				if len(ep.Kafka) > 0 || ep.L7Proto != "" {
+				if len(ep.Kafka) > 0 || len(ep.DNS) > 0 || ep.L7Proto != "" {
 					ctx.PolicyTrace("   Merge conflict: mismatching L7 rule types.\n")
 					return fmt.Errorf("Cannot merge conflicting L7 rule types")
 				}
@@ -73,7 +89,7 @@ func mergeL4Port(ctx *SearchContext, endpoints []api.EndpointSelector, existingF
 					}
 				}
 			case len(newL7Rules.Kafka) > 0:
-				if len(ep.HTTP) > 0 || ep.L7Proto != "" {
+				if len(ep.HTTP) > 0 || len(ep.DNS) > 0 || ep.L7Proto != "" {
 					ctx.PolicyTrace("   Merge conflict: mismatching L7 rule types.\n")
 					return fmt.Errorf("Cannot merge conflicting L7 rule types")
 				}
@@ -83,8 +99,19 @@ func mergeL4Port(ctx *SearchContext, endpoints []api.EndpointSelector, existingF
 						ep.Kafka = append(ep.Kafka, newRule)
 					}
 				}
+			case len(newL7Rules.DNS) > 0:
+				if len(ep.HTTP) > 0 || len(ep.Kafka) > 0 || ep.L7Proto != "" {
+					ctx.PolicyTrace("   Merge conflict: mismatching L7 rule types.\n")
+					return fmt.Errorf("Cannot merge conflicting L7 rule types")
+				}
+
+				for _, newRule := range newL7Rules.DNS {
+					if !newRule.Exists(ep) {
+						ep.DNS = append(ep.DNS, newRule)
+					}
+				}
 			case newL7Rules.L7Proto != "":
-				if len(ep.Kafka) > 0 || len(ep.HTTP) > 0 || (ep.L7Proto != "" && ep.L7Proto != newL7Rules.L7Proto) {
+				if len(ep.Kafka) > 0 || len(ep.HTTP) > 0 || len(ep.DNS) > 0 || (ep.L7Proto != "" && ep.L7Proto != newL7Rules.L7Proto) {
 					ctx.PolicyTrace("   Merge conflict: mismatching L7 rule types.\n")
 					return fmt.Errorf("Cannot merge conflicting L7 rule types")
 				}
@@ -183,6 +210,9 @@ func mergeL4Ingress(ctx *SearchContext, rule api.IngressRule, ruleLabels labels.
 			for _, l7 := range r.Rules.Kafka {
 				ctx.PolicyTrace("        %+v\n", l7)
 			}
+			for _, l7 := range r.Rules.DNS {
+				ctx.PolicyTrace("        %+v\n", l7)
+			}
 			for _, l7 := range r.Rules.L7 {
 				ctx.PolicyTrace("        %+v\n", l7)
 			}
@@ -460,6 +490,9 @@ func mergeL4Egress(ctx *SearchContext, rule api.EgressRule, ruleLabels labels.La
 			for _, l7 := range r.Rules.Kafka {
 				ctx.PolicyTrace("        %+v\n", l7)
 			}
+			for _, l7 := range r.Rules.DNS {
+				ctx.PolicyTrace("        %+v\n", l7)
+			}
 			for _, l7 := range r.Rules.L7 {
 				ctx.PolicyTrace("        %+v\n", l7)
 			}
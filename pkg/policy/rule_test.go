@@ -862,6 +862,135 @@ func (ds *PolicyTestSuite) TestMergeL7PolicyEgress(c *C) {
 	c.Assert(state.matchedRules, Equals, 0)
 }
 
+func (ds *PolicyTestSuite) TestMergeOriginatingTLSEgress(c *C) {
+	fromBar := &SearchContext{From: labels.ParseSelectLabelArray("bar")}
+
+	tls := &api.TLSContext{
+		Certificate: "client.crt",
+		PrivateKey:  "client.key",
+		TrustedCA:   "ca.crt",
+	}
+	tlsPortRule := func(tls *api.TLSContext) api.PortRule {
+		return api.PortRule{
+			Ports: []api.PortProtocol{
+				{Port: "80", Protocol: api.ProtoTCP},
+			},
+			Rules: &api.L7Rules{
+				HTTP: []api.PortRuleHTTP{
+					{Method: "GET", Path: "/"},
+				},
+			},
+			OriginatingTLS: tls,
+		}
+	}
+
+	// Rules with the same TLS context are merged
+	rule1 := &rule{
+		Rule: api.Rule{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+			Egress: []api.EgressRule{
+				{ToPorts: []api.PortRule{tlsPortRule(tls)}},
+				{ToPorts: []api.PortRule{tlsPortRule(tls)}},
+			},
+		},
+	}
+
+	state := traceState{}
+	res, err := rule1.resolveL4EgressPolicy(fromBar, &state, NewL4Policy(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(res, Not(IsNil))
+	c.Assert(res.Egress["80/TCP"].OriginatingTLS, checker.DeepEquals, tls)
+
+	// A rule without a TLS context cannot be merged with a rule
+	// originating TLS, in either order
+	for _, contexts := range [][]*api.TLSContext{{nil, tls}, {tls, nil}} {
+		r := &rule{
+			Rule: api.Rule{
+				EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+				Egress: []api.EgressRule{
+					{ToPorts: []api.PortRule{tlsPortRule(contexts[0])}},
+					{ToPorts: []api.PortRule{tlsPortRule(contexts[1])}},
+				},
+			},
+		}
+
+		state = traceState{}
+		res, err = r.resolveL4EgressPolicy(fromBar, &state, NewL4Policy(), nil)
+		c.Assert(err, Not(IsNil))
+		c.Assert(res, IsNil)
+	}
+
+	// Conflicting TLS contexts cannot be merged
+	otherTLS := *tls
+	otherTLS.ServerName = "cilium.io"
+	rule2 := &rule{
+		Rule: api.Rule{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+			Egress: []api.EgressRule{
+				{ToPorts: []api.PortRule{tlsPortRule(tls)}},
+				{ToPorts: []api.PortRule{tlsPortRule(&otherTLS)}},
+			},
+		},
+	}
+
+	state = traceState{}
+	res, err = rule2.resolveL4EgressPolicy(fromBar, &state, NewL4Policy(), nil)
+	c.Assert(err, Not(IsNil))
+	c.Assert(res, IsNil)
+}
+
+func (ds *PolicyTestSuite) TestMergeCircuitBreakerIngress(c *C) {
+	toBar := &SearchContext{To: labels.ParseSelectLabelArray("bar")}
+
+	cb := &api.CircuitBreaker{MaxConnections: 100}
+	cbPortRule := func(cb *api.CircuitBreaker) api.PortRule {
+		return api.PortRule{
+			Ports: []api.PortProtocol{
+				{Port: "9092", Protocol: api.ProtoTCP},
+			},
+			Rules: &api.L7Rules{
+				Kafka: []api.PortRuleKafka{
+					{Topic: "foo"},
+				},
+			},
+			CircuitBreaker: cb,
+		}
+	}
+
+	// A rule without a circuit breaker does not remove the limits
+	rule1 := &rule{
+		Rule: api.Rule{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+			Ingress: []api.IngressRule{
+				{ToPorts: []api.PortRule{cbPortRule(nil)}},
+				{ToPorts: []api.PortRule{cbPortRule(cb)}},
+			},
+		},
+	}
+
+	state := traceState{}
+	res, err := rule1.resolveL4IngressPolicy(toBar, &state, NewL4Policy(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(res, Not(IsNil))
+	c.Assert(res.Ingress["9092/TCP"].CircuitBreaker, checker.DeepEquals, cb)
+
+	// Conflicting circuit breakers cannot be merged
+	rule2 := &rule{
+		Rule: api.Rule{
+			EndpointSelector: api.NewESFromLabels(labels.ParseSelectLabel("bar")),
+			Ingress: []api.IngressRule{
+				{ToPorts: []api.PortRule{cbPortRule(cb)}},
+				{ToPorts: []api.PortRule{cbPortRule(&api.CircuitBreaker{MaxConnections: 10})}},
+			},
+		},
+	}
+
+	state = traceState{}
+	res, err = rule2.resolveL4IngressPolicy(toBar, &state, NewL4Policy(), nil)
+	c.Assert(err, Not(IsNil))
+	c.Assert(res, IsNil)
+}
+
 func (ds *PolicyTestSuite) TestRuleWithNoEndpointSelector(c *C) {
 	apiRule1 := api.Rule{
 		Ingress: []api.IngressRule{
@@ -15,8 +15,10 @@
 package accesslog
 
 import (
+	"net"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 // FlowType is the type to indicate the flow direction
@@ -186,6 +188,15 @@ type LogRecord struct {
 	// the Verdict field is set to VerdictDenied. Otherwise it's set to nil.
 	DropReason *DropReason
 
+	// Latency is the time between a request and its response in
+	// nanoseconds. It is only set for responses of protocols for which the
+	// proxy correlates responses with requests.
+	Latency time.Duration `json:"Latency,omitempty"`
+
+	// TraceID is the ID of the distributed trace the request is part of.
+	// It is only set if the request carried W3C or B3 trace context.
+	TraceID string `json:"TraceID,omitempty"`
+
 	// The following are the protocol specific parts. Only one of the
 	// following should ever be set. Unused fields will be omitted
 
@@ -195,6 +206,9 @@ type LogRecord struct {
 	// Kafka contains information for Kafka request/responses
 	Kafka *LogRecordKafka `json:"Kafka,omitempty"`
 
+	// DNS contains information for DNS queries/responses
+	DNS *LogRecordDNS `json:"DNS,omitempty"`
+
 	// L7 contains information about generic L7 protocols
 	L7 *LogRecordL7 `json:"L7,omitempty"`
 }
@@ -244,6 +258,24 @@ type LogRecordKafka struct {
 	Topic KafkaTopic
 }
 
+// LogRecordDNS contains the DNS-specific portion of a log record
+type LogRecordDNS struct {
+	// Query is the name of the DNS lookup
+	Query string `json:"Query,omitempty"`
+
+	// QueryTypes are the types of the records requested, e.g. "A", "AAAA"
+	QueryTypes []string `json:"QueryTypes,omitempty"`
+
+	// Rcode is the DNS response code
+	Rcode int
+
+	// IPs are the IPs of the A and AAAA records of a response
+	IPs []net.IP `json:"IPs,omitempty"`
+
+	// TTL is the lowest TTL of the A and AAAA records of a response
+	TTL uint32 `json:"TTL,omitempty"`
+}
+
 // LogRecordL7 contains the generic L7 portion of a log record
 type LogRecordL7 struct {
 	// Proto is the name of the protocol this record represents
@@ -20,6 +20,8 @@ import (
 	"os"
 	"syscall"
 	"time"
+
+	"github.com/cilium/cilium/pkg/option"
 )
 
 // ProxyKeepAlivePeriod is the time used for sending periodic keepalives on
@@ -40,7 +42,46 @@ func setKeepAlive(c net.Conn) error {
 	return nil
 }
 
-func ciliumDialer(identity int, network, address string) (net.Conn, error) {
+// originalSource returns the address from which an upstream connection of
+// a redirect is made to preserve the source address of the client, or nil if
+// the connection is made from the address of the host. Only ingress
+// redirects of IPv4 connections are transparent.
+func originalSource(ingress bool, remoteAddr net.Addr) *net.TCPAddr {
+	if !option.Config.ProxyTransparent || !ingress {
+		return nil
+	}
+
+	addr, ok := remoteAddr.(*net.TCPAddr)
+	if !ok || addr.IP.To4() == nil {
+		return nil
+	}
+
+	return addr
+}
+
+// bindOriginalSource binds the socket fd to source. IP_TRANSPARENT allows to
+// bind to the address although it is not local.
+func bindOriginalSource(fd int, source *net.TCPAddr) error {
+	if err := syscall.SetsockoptInt(fd, syscall.SOL_IP, syscall.IP_TRANSPARENT, 1); err != nil {
+		return fmt.Errorf("unable to set IP_TRANSPARENT socket option: %s", err)
+	}
+
+	sockAddr, err := ipToSockaddr(syscall.AF_INET, source.IP, source.Port, source.Zone)
+	if err != nil {
+		return fmt.Errorf("unable to create sockaddr: %s", err)
+	}
+
+	if err := syscall.Bind(fd, sockAddr); err != nil {
+		return fmt.Errorf("unable to bind to original source %s: %s", source, err)
+	}
+
+	return nil
+}
+
+// ciliumDialer returns a TCP connection to address. If identity is not 0,
+// all packets sent on the connection are marked with it. If source is not
+// nil, the connection is made from the source address.
+func ciliumDialer(identity int, network, address string, source *net.TCPAddr) (net.Conn, error) {
 	addr, err := net.ResolveTCPAddr(network, address)
 	if err != nil {
 		return nil, fmt.Errorf("unable resolve address %s/%s: %s", network, address, err)
@@ -68,6 +109,13 @@ func ciliumDialer(identity int, network, address string) (net.Conn, error) {
 		setSocketMark(c, identity)
 	}
 
+	if source != nil {
+		if err := bindOriginalSource(fd, source); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
 	sockAddr, err := ipToSockaddr(family, addr.IP, addr.Port, addr.Zone)
 	if err != nil {
 		c.Close()
@@ -91,3 +139,42 @@ func ciliumDialer(identity int, network, address string) (net.Conn, error) {
 
 	return c, nil
 }
+
+// ciliumPacketDialer returns a UDP socket connected to address. If identity
+// is not 0, all packets sent on the socket are marked with it.
+func ciliumPacketDialer(identity int, address string) (net.Conn, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("unable resolve address udp/%s: %s", address, err)
+	}
+
+	family := syscall.AF_INET
+	if addr.IP.To4() == nil {
+		family = syscall.AF_INET6
+	}
+
+	fd, err := syscall.Socket(family, syscall.SOCK_DGRAM, 0)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create socket: %s", err)
+	}
+
+	if identity != 0 {
+		setFdMark(fd, identity)
+	}
+
+	sockAddr, err := ipToSockaddr(family, addr.IP, addr.Port, addr.Zone)
+	if err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("unable to create sockaddr: %s", err)
+	}
+
+	if err := syscall.Connect(fd, sockAddr); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("unable to connect: %s", err)
+	}
+
+	f := os.NewFile(uintptr(fd), addr.String())
+	defer f.Close()
+
+	return net.FileConn(f)
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"net"
+
+	"github.com/cilium/cilium/pkg/option"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *proxyTestSuite) TestOriginalSource(c *C) {
+	oldTransparent := option.Config.ProxyTransparent
+	defer func() { option.Config.ProxyTransparent = oldTransparent }()
+
+	addr4 := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 43210}
+	addr6 := &net.TCPAddr{IP: net.ParseIP("f00d::1"), Port: 43210}
+	udpAddr := &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 53}
+
+	option.Config.ProxyTransparent = false
+	c.Assert(originalSource(true, addr4), IsNil)
+
+	option.Config.ProxyTransparent = true
+	c.Assert(originalSource(true, addr4), Equals, addr4)
+	c.Assert(originalSource(false, addr4), IsNil)
+	c.Assert(originalSource(true, addr6), IsNil)
+	c.Assert(originalSource(true, udpAddr), IsNil)
+}
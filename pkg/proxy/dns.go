@@ -0,0 +1,445 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/completion"
+	"github.com/cilium/cilium/pkg/flowdebug"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/ipcache"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+	"github.com/cilium/cilium/pkg/proxy/logger"
+	"github.com/cilium/cilium/pkg/revert"
+
+	"github.com/miekg/dns"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// dnsTimeout is the timeout of forwarding a DNS query to its original
+	// destination and receiving the response
+	dnsTimeout = 5 * time.Second
+
+	// dnsAllowedChars are the characters matched by the wildcard of a DNS
+	// matchPattern
+	dnsAllowedChars = "[-a-zA-Z0-9_]"
+)
+
+// DNSResponseNotifier is called with the IPs of each successful DNS response
+// forwarded by the DNS proxy before the response is returned to the client.
+// epID is the ID of the local endpoint which has sent the query, or 0 if the
+// query has been received from a remote client.
+type DNSResponseNotifier func(lookupTime time.Time, epID uint64, name string, ips []net.IP, ttl int) error
+
+type dnsExchangeFunc func(marker int, network, address string, req *dns.Msg) (*dns.Msg, error)
+
+// dnsRedirect implements the Redirect interface for the DNS proxy
+type dnsRedirect struct {
+	redirect             *Redirect
+	endpointInfoRegistry logger.EndpointInfoRegistry
+	conf                 dnsConfiguration
+	server               *dns.Server
+}
+
+type dnsConfiguration struct {
+	// protocol is the L4 protocol of the redirect, TCP or UDP
+	protocol api.L4Proto
+
+	noMarker       bool
+	lookupNewDest  destLookupFunc
+	exchange       dnsExchangeFunc
+	notifyResponse DNSResponseNotifier
+}
+
+// createDNSRedirect creates a redirect to the DNS proxy. The redirect
+// structure passed in is safe to access for reading and writing.
+func createDNSRedirect(r *Redirect, conf dnsConfiguration, endpointInfoRegistry logger.EndpointInfoRegistry) (RedirectImplementation, error) {
+	redir := &dnsRedirect{
+		redirect:             r,
+		conf:                 conf,
+		endpointInfoRegistry: endpointInfoRegistry,
+	}
+
+	if redir.conf.lookupNewDest == nil {
+		if conf.protocol == api.ProtoUDP {
+			redir.conf.lookupNewDest = lookupNewUDPDest
+		} else {
+			redir.conf.lookupNewDest = lookupNewDest
+		}
+	}
+
+	if redir.conf.exchange == nil {
+		redir.conf.exchange = dnsExchange
+	}
+
+	marker := 0
+	if !conf.noMarker {
+		markIdentity := int(0)
+		// As ingress proxy, all replies to incoming requests must have the
+		// identity of the endpoint we are proxying for
+		if r.ingress {
+			markIdentity = int(r.localEndpoint.GetIdentity())
+		}
+
+		marker = getMagicMark(r.ingress, markIdentity)
+	}
+
+	redir.server = &dns.Server{
+		Handler: dns.HandlerFunc(redir.ServeDNS),
+	}
+
+	// Listen needs to be in the synchronous part of this function to ensure
+	// that the proxy port is never refusing queries.
+	address := fmt.Sprintf(":%d", r.ProxyPort)
+	if conf.protocol == api.ProtoUDP {
+		conn, err := listenPacketSocket(address, marker)
+		if err != nil {
+			return nil, err
+		}
+		redir.server.PacketConn = conn
+	} else {
+		socket, err := listenSocket(address, marker)
+		if err != nil {
+			return nil, err
+		}
+		redir.server.Listener = socket.listener
+	}
+	r.setListenerBound(nil)
+
+	go func() {
+		if err := redir.server.ActivateAndServe(); err != nil {
+			log.WithError(err).WithField(logfields.Port, r.ProxyPort).Error("DNS proxy stopped")
+			r.setListenerBound(err)
+		}
+	}()
+
+	return redir, nil
+}
+
+// dnsExchange forwards req to address and returns the response
+func dnsExchange(marker int, network, address string, req *dns.Msg) (*dns.Msg, error) {
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if network == "udp" {
+		conn, err = ciliumPacketDialer(marker, address)
+	} else {
+		conn, err = ciliumDialer(marker, network, address, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	co := &dns.Conn{Conn: conn}
+	defer co.Close()
+
+	// Accept responses as large as the client does
+	if opt := req.IsEdns0(); opt != nil && opt.UDPSize() > dns.MinMsgSize {
+		co.UDPSize = opt.UDPSize()
+	}
+
+	co.SetDeadline(time.Now().Add(dnsTimeout))
+	if err := co.WriteMsg(req); err != nil {
+		return nil, err
+	}
+
+	return co.ReadMsg()
+}
+
+// matchPatternRegexp returns the regular expression for a DNS matchPattern.
+// The pattern is expected to be a lowercase FQDN.
+func matchPatternRegexp(pattern string) (*regexp.Regexp, error) {
+	if pattern == "*." {
+		return regexp.Compile(".*")
+	}
+
+	pattern = regexp.QuoteMeta(pattern)
+	pattern = strings.Replace(pattern, `\*`, dnsAllowedChars+"*", -1)
+	return regexp.Compile("^" + pattern + "$")
+}
+
+// dnsMatchPatterns returns the regular expressions of the matchPatterns of
+// all DNS rules, indexed by matchPattern. Invalid patterns are omitted, they
+// don't match any name.
+func dnsMatchPatterns(rules policy.L7DataMap) map[string]*regexp.Regexp {
+	patterns := make(map[string]*regexp.Regexp)
+	for _, l7 := range rules {
+		for _, rule := range l7.DNS {
+			if rule.MatchPattern == "" {
+				continue
+			}
+			if _, ok := patterns[rule.MatchPattern]; ok {
+				continue
+			}
+			re, err := matchPatternRegexp(dns.Fqdn(strings.ToLower(rule.MatchPattern)))
+			if err != nil {
+				log.WithError(err).WithField(logfields.DNSName, rule.MatchPattern).Warn("Ignoring invalid DNS matchPattern")
+				continue
+			}
+			patterns[rule.MatchPattern] = re
+		}
+	}
+	return patterns
+}
+
+// dnsRuleMatches returns true if the DNS rule allows a lookup of the
+// lowercase FQDN name. patterns are the compiled matchPatterns returned by
+// dnsMatchPatterns.
+func dnsRuleMatches(rule api.PortRuleDNS, name string, patterns map[string]*regexp.Regexp) bool {
+	switch {
+	case rule.MatchName != "":
+		return dns.Fqdn(strings.ToLower(rule.MatchName)) == name
+
+	case rule.MatchPattern != "":
+		re, ok := patterns[rule.MatchPattern]
+		return ok && re.MatchString(name)
+
+	default:
+		return true
+	}
+}
+
+// peerIdentity returns the security identity of the peer of the local
+// endpoint which L7 rules apply to: the source of a request for an ingress
+// redirect and the original destination for an egress redirect.
+func peerIdentity(ingress bool, srcIdentity uint32, origDstAddr string) identity.NumericIdentity {
+	if ingress {
+		return identity.NumericIdentity(srcIdentity)
+	}
+
+	host, _, err := net.SplitHostPort(origDstAddr)
+	if err != nil {
+		return identity.ReservedIdentityWorld
+	}
+
+	if id, ok := ipcache.IPIdentityCache.LookupByIP(host); ok {
+		return id.ID
+	}
+
+	return identity.ReservedIdentityWorld
+}
+
+// canAccess determines if all names of the query may be looked up by the
+// peer according to the rules configured on the redirect
+func (d *dnsRedirect) canAccess(names []string, peer identity.NumericIdentity) bool {
+	id := identity.LookupIdentityByID(peer)
+	if id == nil {
+		log.WithFields(logrus.Fields{
+			logfields.DNSName:  names,
+			logfields.Identity: peer,
+		}).Warn("Unable to resolve identity to labels")
+	}
+
+	d.redirect.mutex.RLock()
+	rules := d.redirect.rules.GetRelevantRules(id)
+	patterns := d.redirect.dnsPatterns
+	d.redirect.mutex.RUnlock()
+
+	scopedLog := log.WithFields(logrus.Fields{
+		logfields.DNSName:  names,
+		logfields.Identity: id,
+	})
+
+	if rules.DNS == nil {
+		flowdebug.Log(scopedLog, "No DNS rules matching identity, rejecting")
+		return false
+	}
+
+nextName:
+	for _, name := range names {
+		for _, rule := range rules.DNS {
+			if dnsRuleMatches(rule, name, patterns) {
+				continue nextName
+			}
+		}
+		flowdebug.Log(scopedLog.WithField(logfields.DNSName, name), "DNS name is not allowed by any rule")
+		return false
+	}
+
+	return true
+}
+
+// responseIPs returns the IPs of the A and AAAA records in the answer
+// section of resp together with their lowest TTL
+func responseIPs(resp *dns.Msg) (ips []net.IP, ttl uint32) {
+	for _, rr := range resp.Answer {
+		var ip net.IP
+		switch rec := rr.(type) {
+		case *dns.A:
+			ip = rec.A
+		case *dns.AAAA:
+			ip = rec.AAAA
+		default:
+			continue
+		}
+
+		if len(ips) == 0 || rr.Header().Ttl < ttl {
+			ttl = rr.Header().Ttl
+		}
+		ips = append(ips, ip)
+	}
+
+	return ips, ttl
+}
+
+func (d *dnsRedirect) newLogRecord(t accesslog.FlowType, req *dns.Msg, remoteAddr, origDstAddr string, srcIdentity uint32) *logger.LogRecord {
+	record := &accesslog.LogRecordDNS{}
+	for _, q := range req.Question {
+		record.Query = q.Name
+		record.QueryTypes = append(record.QueryTypes, dns.TypeToString[q.Qtype])
+	}
+
+	return logger.NewLogRecord(d.endpointInfoRegistry, d.redirect.localEndpoint,
+		t, d.redirect.ingress,
+		logger.LogTags.DNS(record),
+		logger.LogTags.Addressing(logger.AddressingInfo{
+			SrcIPPort:   remoteAddr,
+			DstIPPort:   origDstAddr,
+			SrcIdentity: srcIdentity,
+		}))
+}
+
+// logRecord logs record with the verdict and updates the proxy statistics
+// of the local endpoint
+func (d *dnsRedirect) logRecord(record *logger.LogRecord, verdict accesslog.FlowVerdict, info string, tags ...logger.LogTag) {
+	record.ApplyTags(logger.LogTags.Verdict(verdict, info))
+	record.ApplyTags(tags...)
+	record.Log()
+
+	// The statistics are kept per port of the DNS server, the destination of
+	// the queries in both directions.
+	ingress := record.ObservationPoint == accesslog.Ingress
+	port := record.DestinationEndpoint.Port
+	if port == 0 {
+		// Something went wrong when identifying the endpoints.
+		// Ignore in order to avoid polluting the stats.
+		return
+	}
+	request := record.Type == accesslog.TypeRequest
+	d.redirect.localEndpoint.UpdateProxyStatistics("dns", port, ingress, request, verdict)
+}
+
+// ServeDNS enforces the DNS rules of the redirect on a query, forwards
+// allowed queries to their original destination and returns the response.
+func (d *dnsRedirect) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	remoteAddr := w.RemoteAddr().String()
+	scopedLog := log.WithFields(logrus.Fields{
+		"source":                remoteAddr,
+		fieldProxyRedirectID:    d.redirect.id,
+		logfields.DNSName + "s": req.Question,
+	})
+	flowdebug.Log(scopedLog, "Handling DNS query")
+
+	// retrieve identity of source together with original destination IP
+	// and destination port
+	srcIdentity, origDstAddr, err := d.conf.lookupNewDest(remoteAddr, d.redirect.ProxyPort)
+	if err != nil {
+		scopedLog.WithError(err).Error("Unable to lookup original destination")
+		d.reply(w, req, dns.RcodeServerFailure)
+		return
+	}
+
+	record := d.newLogRecord(accesslog.TypeRequest, req, remoteAddr, origDstAddr, srcIdentity)
+
+	names := make([]string, 0, len(req.Question))
+	for _, q := range req.Question {
+		names = append(names, strings.ToLower(dns.Fqdn(q.Name)))
+	}
+
+	if len(names) == 0 || !d.canAccess(names, peerIdentity(d.redirect.ingress, srcIdentity, origDstAddr)) {
+		flowdebug.Log(scopedLog, "DNS query is denied by policy")
+		record.DNS.Rcode = dns.RcodeRefused
+		d.logRecord(record, accesslog.VerdictDenied, "DNS query is denied by policy")
+		d.reply(w, req, dns.RcodeRefused)
+		return
+	}
+
+	d.logRecord(record, accesslog.VerdictForwarded, "")
+
+	marker := 0
+	if !d.conf.noMarker {
+		marker = getMagicMark(d.redirect.ingress, int(srcIdentity))
+	}
+
+	network := "tcp"
+	if d.conf.protocol == api.ProtoUDP {
+		network = "udp"
+	}
+
+	lookupTime := time.Now()
+	resp, err := d.conf.exchange(marker, network, origDstAddr, req)
+	response := d.newLogRecord(accesslog.TypeResponse, req, remoteAddr, origDstAddr, srcIdentity)
+	response.ApplyTags(logger.LogTags.Latency(time.Since(lookupTime)))
+	if err != nil {
+		scopedLog.WithError(err).WithField("destination", origDstAddr).Warning("Unable to forward DNS query")
+		response.DNS.Rcode = dns.RcodeServerFailure
+		d.logRecord(response, accesslog.VerdictError, fmt.Sprintf("Unable to forward DNS query: %s", err),
+			logger.LogTags.ErrorClass(logger.ErrorClassForward))
+		d.reply(w, req, dns.RcodeServerFailure)
+		return
+	}
+
+	ips, ttl := responseIPs(resp)
+	response.DNS.Rcode = resp.Rcode
+	response.DNS.IPs = ips
+	response.DNS.TTL = ttl
+
+	// The IPs must be known to the ToFQDNs policy before the client
+	// receives the response and connects to them.
+	if resp.Rcode == dns.RcodeSuccess && len(ips) > 0 && d.conf.notifyResponse != nil {
+		epID := d.redirect.endpointID
+		if d.redirect.ingress {
+			epID = 0
+		}
+		for _, name := range names {
+			if err := d.conf.notifyResponse(lookupTime, epID, name, ips, int(ttl)); err != nil {
+				scopedLog.WithError(err).Warning("Unable to update FQDN policy with DNS response")
+			}
+		}
+	}
+
+	d.logRecord(response, accesslog.VerdictForwarded, "")
+
+	if err := w.WriteMsg(resp); err != nil {
+		scopedLog.WithError(err).Warning("Unable to return DNS response")
+	}
+}
+
+// reply returns an empty response with rcode to the client
+func (d *dnsRedirect) reply(w dns.ResponseWriter, req *dns.Msg, rcode int) {
+	resp := new(dns.Msg)
+	resp.SetRcode(req, rcode)
+	if err := w.WriteMsg(resp); err != nil {
+		log.WithError(err).Debug("Unable to return DNS response")
+	}
+}
+
+// Close the redirect.
+func (d *dnsRedirect) Close(wg *completion.WaitGroup) (revert.FinalizeFunc, revert.RevertFunc) {
+	return func() {
+		d.server.Shutdown()
+	}, nil
+}
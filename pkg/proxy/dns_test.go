@@ -0,0 +1,102 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"github.com/cilium/cilium/pkg/checker"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+	"github.com/cilium/cilium/pkg/proxy/logger"
+
+	"github.com/miekg/dns"
+	. "gopkg.in/check.v1"
+)
+
+func (s *proxyTestSuite) TestDNSRuleMatches(c *C) {
+	testCases := []struct {
+		rule    api.PortRuleDNS
+		name    string
+		matches bool
+	}{
+		{api.PortRuleDNS{}, "cilium.io.", true},
+		{api.PortRuleDNS{MatchName: "cilium.io"}, "cilium.io.", true},
+		{api.PortRuleDNS{MatchName: "Cilium.IO."}, "cilium.io.", true},
+		{api.PortRuleDNS{MatchName: "cilium.io"}, "api.cilium.io.", false},
+		{api.PortRuleDNS{MatchName: "cilium.io"}, "ciliumxio.", false},
+		{api.PortRuleDNS{MatchPattern: "*"}, "api.cilium.io.", true},
+		{api.PortRuleDNS{MatchPattern: "*.cilium.io"}, "api.cilium.io.", true},
+		{api.PortRuleDNS{MatchPattern: "*.cilium.io"}, "cilium.io.", false},
+		{api.PortRuleDNS{MatchPattern: "*.cilium.io"}, "a.b.cilium.io.", false},
+		{api.PortRuleDNS{MatchPattern: "*.cilium.io"}, "api.cilium.io.evil.com.", false},
+		{api.PortRuleDNS{MatchPattern: "api-*.cilium.io"}, "api-v1.cilium.io.", true},
+		{api.PortRuleDNS{MatchPattern: "api-*.cilium.io"}, "web.cilium.io.", false},
+	}
+
+	for _, tc := range testCases {
+		patterns := dnsMatchPatterns(policy.L7DataMap{
+			api.WildcardEndpointSelector: api.L7Rules{DNS: []api.PortRuleDNS{tc.rule}},
+		})
+		c.Assert(dnsRuleMatches(tc.rule, tc.name, patterns), Equals, tc.matches,
+			Commentf("rule %+v, name %s", tc.rule, tc.name))
+	}
+}
+
+func (s *proxyTestSuite) TestDNSResponseIPs(c *C) {
+	resp := new(dns.Msg)
+	for _, rr := range []string{
+		"cilium.io. 60 IN A 1.1.1.1",
+		"cilium.io. 30 IN AAAA f00d::1",
+		"cilium.io. 10 IN TXT \"text\"",
+	} {
+		r, err := dns.NewRR(rr)
+		c.Assert(err, IsNil)
+		resp.Answer = append(resp.Answer, r)
+	}
+
+	ips, ttl := responseIPs(resp)
+	c.Assert(len(ips), Equals, 2)
+	c.Assert(ips[0].String(), Equals, "1.1.1.1")
+	c.Assert(ips[1].String(), Equals, "f00d::1")
+	c.Assert(ttl, Equals, uint32(30))
+}
+
+func (s *proxyTestSuite) TestDNSProxyStatisticsPort(c *C) {
+	for _, ingress := range []bool{false, true} {
+		ep := &proxyUpdaterMock{id: 1000, ipv4: "10.0.0.1"}
+		redir := newRedirect(ep, "test-dns")
+		redir.ingress = ingress
+		d := &dnsRedirect{redirect: redir}
+
+		// The client port differs for each query, the statistics are
+		// kept for the port of the DNS server
+		for _, t := range []accesslog.FlowType{accesslog.TypeRequest, accesslog.TypeResponse} {
+			record := &logger.LogRecord{}
+			record.Type = t
+			record.ObservationPoint = accesslog.Egress
+			if ingress {
+				record.ObservationPoint = accesslog.Ingress
+			}
+			record.SourceEndpoint.Port = 34567
+			record.DestinationEndpoint.Port = 53
+			d.logRecord(record, accesslog.VerdictForwarded, "")
+		}
+
+		c.Assert(ep.proxyStats, checker.DeepEquals, []proxyStatsUpdate{
+			{"dns", 53, ingress, true, accesslog.VerdictForwarded},
+			{"dns", 53, ingress, false, accesslog.VerdictForwarded},
+		})
+	}
+}
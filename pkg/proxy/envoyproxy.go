@@ -21,6 +21,8 @@ import (
 
 	"github.com/cilium/cilium/pkg/completion"
 	"github.com/cilium/cilium/pkg/envoy"
+	"github.com/cilium/cilium/pkg/envoy/xds"
+	"github.com/cilium/cilium/pkg/policy/api"
 
 	"github.com/spf13/viper"
 )
@@ -57,7 +59,9 @@ func createEnvoyRedirect(r *Redirect, stateDir string, xdsServer *envoy.XDSServe
 		if ip == "" {
 			return nil, fmt.Errorf("%s: Cannot create redirect, proxy local endpoint has no IP address", r.id)
 		}
-		xdsServer.AddListener(redir.listenerName, r.parserType, ip, r.ProxyPort, r.ingress, wg)
+		// Envoy is listening on the proxy port once it has acknowledged
+		// the listener.
+		xdsServer.AddListener(redir.listenerName, r.parserType, ip, r.ProxyPort, r.ingress, r.originatingTLS, r.circuitBreaker, wg, r.onListenerUpdate)
 
 		return redir, nil
 	}
@@ -65,6 +69,13 @@ func createEnvoyRedirect(r *Redirect, stateDir string, xdsServer *envoy.XDSServe
 	return nil, fmt.Errorf("%s: Envoy proxy process failed to start, cannot add redirect", r.id)
 }
 
+// updateCluster updates the TLS context and the circuit breaker of the
+// cluster dedicated to the listener of the redirect. Returns false if the
+// listener has no dedicated cluster.
+func (r *envoyRedirect) updateCluster(tls *api.TLSContext, cb *api.CircuitBreaker, wg *completion.WaitGroup) (xds.AckingResourceMutatorRevertFunc, bool) {
+	return r.xdsServer.UpdateListenerCluster(r.listenerName, tls, cb, wg)
+}
+
 // Close the redirect.
 func (r *envoyRedirect) Close(wg *completion.WaitGroup) (revert.FinalizeFunc, revert.RevertFunc) {
 	if envoyProxy == nil {
@@ -20,6 +20,7 @@ import (
 	"github.com/cilium/cilium/pkg/revert"
 	"io"
 	"net"
+	"sync/atomic"
 	"time"
 
 	"github.com/cilium/cilium/pkg/completion"
@@ -27,6 +28,7 @@ import (
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/kafka"
 	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/cilium/cilium/pkg/proxy/accesslog"
@@ -47,6 +49,12 @@ type kafkaRedirect struct {
 	conf                 kafkaConfiguration
 	rules                policy.L7DataMap
 	socket               *proxySocket
+
+	// connections and pendingRequests are the number of connections
+	// and of requests waiting for a response currently forwarded by the
+	// redirect, they must be accessed atomically
+	connections     int32
+	pendingRequests int32
 }
 
 type destLookupFunc func(remoteAddr string, dport uint16) (uint32, string, error)
@@ -89,6 +97,7 @@ func createKafkaRedirect(r *Redirect, conf kafkaConfiguration, endpointInfoRegis
 	}
 
 	redir.socket = socket
+	r.setListenerBound(nil)
 
 	go func() {
 		for {
@@ -102,6 +111,7 @@ func createKafkaRedirect(r *Redirect, conf kafkaConfiguration, endpointInfoRegis
 
 			if err != nil {
 				log.WithField(logfields.Port, r.ProxyPort).WithError(err).Error("Unable to accept connection on port")
+				r.setError(err)
 				continue
 			}
 
@@ -152,6 +162,46 @@ func (k *kafkaRedirect) canAccess(req *kafka.RequestMessage, srcIdentity identit
 	return req.MatchesRule(rules.Kafka)
 }
 
+// acquire accounts for a connection or request added to the counter, unless
+// the counter would exceed the given limit of the circuit breaker. A limit of
+// 0 means unlimited. Returns false if the limit is exceeded.
+func (k *kafkaRedirect) acquire(counter *int32, limit uint32, limitName string) bool {
+	n := atomic.AddInt32(counter, 1)
+	if limit > 0 && uint32(n) > limit {
+		atomic.AddInt32(counter, -1)
+		metrics.ProxyCircuitBreakerOverflows.WithLabelValues(string(policy.ParserTypeKafka), limitName).Inc()
+		return false
+	}
+	return true
+}
+
+// acquireConnection accounts for a new connection of the redirect. Returns
+// false if the connection exceeds the connection limit of the circuit breaker.
+func (k *kafkaRedirect) acquireConnection() bool {
+	var limit uint32
+	if cb := k.redirect.getCircuitBreaker(); cb != nil {
+		limit = cb.MaxConnections
+	}
+	return k.acquire(&k.connections, limit, metrics.LimitConnections)
+}
+
+// acquirePendingRequest accounts for a new request waiting for a response.
+// Returns false if the request exceeds the pending requests limit of the
+// circuit breaker.
+func (k *kafkaRedirect) acquirePendingRequest() bool {
+	var limit uint32
+	if cb := k.redirect.getCircuitBreaker(); cb != nil {
+		limit = cb.MaxPendingRequests
+	}
+	return k.acquire(&k.pendingRequests, limit, metrics.LimitPendingRequests)
+}
+
+// releasePendingRequest is the kafka.FinishFunc of requests accounted for by
+// acquirePendingRequest.
+func (k *kafkaRedirect) releasePendingRequest(req *kafka.RequestMessage) {
+	atomic.AddInt32(&k.pendingRequests, -1)
+}
+
 // kafkaLogRecord wraps an accesslog.LogRecord so that we can define methods with a receiver
 type kafkaLogRecord struct {
 	*logger.LogRecord
@@ -195,14 +245,18 @@ func (k *kafkaRedirect) newLogRecordFromResponse(res *kafka.ResponseMessage, req
 		lr.Kafka.APIVersion = req.GetVersion()
 		lr.Kafka.APIKey = apiKeyToString(req.GetAPIKey())
 		lr.topics = req.GetTopics()
+		if res != nil && !req.GetReceived().IsZero() {
+			lr.ApplyTags(logger.LogTags.Latency(time.Since(req.GetReceived())))
+		}
 	}
 
 	return lr
 }
 
 // log Kafka log records
-func (l *kafkaLogRecord) log(verdict accesslog.FlowVerdict, code int, info string) {
+func (l *kafkaLogRecord) log(verdict accesslog.FlowVerdict, code int, info string, tags ...logger.LogTag) {
 	l.ApplyTags(logger.LogTags.Verdict(verdict, info))
+	l.ApplyTags(tags...)
 	l.Kafka.ErrorCode = code
 
 	// Log multiple entries for multiple Kafka topics in a single request.
@@ -249,7 +303,8 @@ func (k *kafkaRedirect) handleRequest(pair *connectionPair, req *kafka.RequestMe
 		resp, err := req.CreateResponse(proto.ErrTopicAuthorizationFailed)
 		if err != nil {
 			record.log(accesslog.VerdictError,
-				kafka.ErrInvalidMessage, fmt.Sprintf("Unable to create response: %s", err))
+				kafka.ErrInvalidMessage, fmt.Sprintf("Unable to create response: %s", err),
+				logger.LogTags.ErrorClass(logger.ErrorClassParse))
 			scopedLog.WithError(err).Error("Unable to create Kafka response")
 			return
 		}
@@ -272,7 +327,8 @@ func (k *kafkaRedirect) handleRequest(pair *connectionPair, req *kafka.RequestMe
 			"destination": origDstAddr,
 		}), "Dialing original destination")
 
-		txConn, err := ciliumDialer(marker, remoteAddr.Network(), origDstAddr)
+		txConn, err := ciliumDialer(marker, remoteAddr.Network(), origDstAddr,
+			originalSource(k.redirect.ingress, remoteAddr))
 		if err != nil {
 			scopedLog.WithError(err).WithFields(logrus.Fields{
 				"origNetwork": remoteAddr.Network(),
@@ -280,7 +336,8 @@ func (k *kafkaRedirect) handleRequest(pair *connectionPair, req *kafka.RequestMe
 			}).Error("Unable to dial original destination")
 
 			record.log(accesslog.VerdictError,
-				kafka.ErrNetwork, fmt.Sprintf("Unable to dial original destination: %s", err))
+				kafka.ErrNetwork, fmt.Sprintf("Unable to dial original destination: %s", err),
+				logger.LogTags.ErrorClass(logger.ErrorClassDial))
 
 			return
 		}
@@ -292,11 +349,37 @@ func (k *kafkaRedirect) handleRequest(pair *connectionPair, req *kafka.RequestMe
 		go k.handleResponseConnection(pair, correlationCache, remoteAddr, remoteIdentity, origDstAddr)
 	}
 
+	// Requests exceeding the pending requests limit of the circuit breaker
+	// are answered by the proxy so that the client backs off and retries.
+	var finishFunc kafka.FinishFunc
+	if req.ExpectsResponse() {
+		if !k.acquirePendingRequest() {
+			flowdebug.Log(scopedLog, "Kafka request exceeds the pending requests limit")
+
+			resp, err := req.CreateResponse(proto.ErrBrokerNotAvailable)
+			if err != nil {
+				record.log(accesslog.VerdictError,
+					kafka.ErrInvalidMessage, fmt.Sprintf("Unable to create response: %s", err),
+					logger.LogTags.ErrorClass(logger.ErrorClassParse))
+				scopedLog.WithError(err).Error("Unable to create Kafka response")
+				return
+			}
+
+			record.log(accesslog.VerdictError,
+				kafka.ErrBrokerNotAvailable, "Kafka request exceeds the pending requests limit of the circuit breaker",
+				logger.LogTags.ErrorClass(logger.ErrorClassCircuitBreaker))
+
+			pair.Rx.Enqueue(resp.GetRaw())
+			return
+		}
+		finishFunc = k.releasePendingRequest
+	}
+
 	// The request is allowed so we will forward it:
 	// 1. Rewrite the correlation ID to a unique ID, it will be restored in
 	//    the response direction
 	// 2. Store the request in the correlation cache
-	correlationCache.HandleRequest(req, nil)
+	correlationCache.HandleRequest(req, finishFunc)
 
 	flowdebug.Log(scopedLog, "Forwarding Kafka request")
 	// log valid request
@@ -379,7 +462,8 @@ func (k *kafkaRedirect) handleResponses(done <-chan struct{}, pair *connectionPa
 			record := k.newLogRecordFromResponse(nil, nil)
 			record.log(accesslog.VerdictError,
 				kafka.ErrInvalidMessage,
-				fmt.Sprintf("Unable to parse Kafka response: %s", err))
+				fmt.Sprintf("Unable to parse Kafka response: %s", err),
+				logger.LogTags.ErrorClass(logger.ErrorClassParse))
 			scopedLog.WithError(err).Error("Unable to parse Kafka response; closing Kafka response connection")
 			return
 		}
@@ -391,6 +475,14 @@ func (k *kafkaRedirect) handleResponses(done <-chan struct{}, pair *connectionPa
 		//    correlation id as expected
 		req := correlationCache.CorrelateResponse(rsp)
 
+		// Hide the request versions which the proxy is unable to parse
+		// from the client
+		if req != nil && req.GetAPIKey() == api.APIVersionsKey {
+			if err := rsp.LimitAPIVersions(req.GetVersion()); err != nil {
+				scopedLog.WithError(err).Warning("Unable to limit API versions of Kafka ApiVersions response")
+			}
+		}
+
 		record := k.newLogRecordFromResponse(rsp, req)
 		record.ApplyTags(logger.LogTags.Addressing(logger.AddressingInfo{
 			SrcIPPort:   remoteAddr.String(),
@@ -409,7 +501,14 @@ func (k *kafkaRedirect) handleRequestConnection(pair *connectionPair) {
 		"to":   pair.Tx,
 	}), "Proxying request Kafka connection")
 
-	k.handleRequests(k.socket.closing, pair, pair.Rx, k.handleRequest)
+	if k.acquireConnection() {
+		k.handleRequests(k.socket.closing, pair, pair.Rx, k.handleRequest)
+		atomic.AddInt32(&k.connections, -1)
+	} else {
+		log.WithField(logfields.Port, k.redirect.ProxyPort).
+			Debug("Kafka connection exceeds the connection limit of the circuit breaker; closing")
+		pair.Rx.Close()
+	}
 
 	// The proxymap contains an entry with metadata for the receive side of the
 	// connection, remove it after the connection has been closed.
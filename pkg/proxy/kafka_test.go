@@ -264,3 +264,30 @@ func (k *proxyTestSuite) TestKafkaRedirect(c *C) {
 	// 1-minute timeout, uncomment this line:
 	// time.Sleep(2 * time.Minute)
 }
+
+func (k *proxyTestSuite) TestKafkaCircuitBreaker(c *C) {
+	redir := &kafkaRedirect{
+		redirect: &Redirect{
+			circuitBreaker: &api.CircuitBreaker{MaxConnections: 1, MaxPendingRequests: 2},
+		},
+	}
+
+	c.Assert(redir.acquireConnection(), Equals, true)
+	c.Assert(redir.acquireConnection(), Equals, false)
+	c.Assert(redir.connections, Equals, int32(1))
+
+	c.Assert(redir.acquirePendingRequest(), Equals, true)
+	c.Assert(redir.acquirePendingRequest(), Equals, true)
+	c.Assert(redir.acquirePendingRequest(), Equals, false)
+
+	// A finished request makes room for another one
+	redir.releasePendingRequest(nil)
+	c.Assert(redir.acquirePendingRequest(), Equals, true)
+	c.Assert(redir.pendingRequests, Equals, int32(2))
+
+	// Without circuit breaker the redirect is not limited
+	redir = &kafkaRedirect{redirect: &Redirect{}}
+	for i := 0; i < 10; i++ {
+		c.Assert(redir.acquireConnection(), Equals, true)
+	}
+}
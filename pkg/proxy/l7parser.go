@@ -0,0 +1,470 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/cilium/cilium/pkg/completion"
+	"github.com/cilium/cilium/pkg/flowdebug"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+	"github.com/cilium/cilium/pkg/proxy/logger"
+	"github.com/cilium/cilium/pkg/revert"
+
+	"github.com/sirupsen/logrus"
+)
+
+// L7Frame is a request or response of a L7 protocol decoded by a
+// L7FrameDecoder.
+type L7Frame interface {
+	// GetRaw returns the frame as read from the connection. Allowed
+	// frames are forwarded unmodified.
+	GetRaw() []byte
+
+	// GetFields returns key-value pairs describing the frame for the
+	// access log
+	GetFields() map[string]string
+}
+
+// L7FrameDecoder decodes the frames of a single proxied connection.
+// ReadRequest and ReadResponse are called from separate goroutines.
+type L7FrameDecoder interface {
+	// ReadRequest reads the next request sent by the client
+	ReadRequest(r io.Reader) (L7Frame, error)
+
+	// ReadResponse reads the next response sent by the server
+	ReadResponse(r io.Reader) (L7Frame, error)
+
+	// DenyResponse returns the response sent to the client in place of a
+	// request denied by policy. If nil is returned, the connection is
+	// closed instead.
+	DenyResponse(req L7Frame) []byte
+}
+
+// L7Parser implements a L7 protocol with generic key-value pair rules
+// (l7proto and l7 in the policy API) in the proxy of the agent. It must be
+// safe for concurrent use.
+type L7Parser interface {
+	// NewDecoder returns the frame decoder for a new connection
+	NewDecoder() L7FrameDecoder
+
+	// Matches returns true if the request is allowed by rule. Rules
+	// without any key-value pairs allow all requests and are not passed
+	// to Matches.
+	Matches(req L7Frame, rule api.PortRuleL7) bool
+}
+
+var (
+	l7ParsersMutex lock.RWMutex
+	l7Parsers      = map[policy.L7ParserType]L7Parser{}
+)
+
+// RegisterL7Parser registers parser as the implementation of the L7 protocol
+// parserType. Redirects for rules with a matching l7proto are then
+// implemented by the proxy of the agent instead of Envoy. Parsers are
+// typically registered from init functions. The built-in parser types cannot
+// be registered.
+func RegisterL7Parser(parserType policy.L7ParserType, parser L7Parser) error {
+	switch parserType {
+	case policy.ParserTypeNone, policy.ParserTypeHTTP, policy.ParserTypeKafka, policy.ParserTypeDNS:
+		return fmt.Errorf("cannot register L7 parser for built-in parser type %q", parserType)
+	}
+
+	l7ParsersMutex.Lock()
+	defer l7ParsersMutex.Unlock()
+
+	if _, ok := l7Parsers[parserType]; ok {
+		return fmt.Errorf("L7 parser %q is already registered", parserType)
+	}
+
+	log.WithField(logfields.L7Parser, parserType).Debug("Registered L7 parser")
+	l7Parsers[parserType] = parser
+	return nil
+}
+
+// getL7Parser returns the parser registered for parserType or nil
+func getL7Parser(parserType policy.L7ParserType) L7Parser {
+	l7ParsersMutex.RLock()
+	defer l7ParsersMutex.RUnlock()
+	return l7Parsers[parserType]
+}
+
+// l7ParserRedirect implements the Redirect interface for registered L7
+// parsers
+type l7ParserRedirect struct {
+	redirect             *Redirect
+	endpointInfoRegistry logger.EndpointInfoRegistry
+	conf                 l7ParserConfiguration
+	parser               L7Parser
+	socket               *proxySocket
+}
+
+type l7ParserConfiguration struct {
+	noMarker      bool
+	lookupNewDest destLookupFunc
+}
+
+// createL7ParserRedirect creates a redirect to the proxy using parser. The
+// redirect structure passed in is safe to access for reading and writing.
+func createL7ParserRedirect(r *Redirect, parser L7Parser, conf l7ParserConfiguration, endpointInfoRegistry logger.EndpointInfoRegistry) (RedirectImplementation, error) {
+	redir := &l7ParserRedirect{
+		redirect:             r,
+		conf:                 conf,
+		parser:               parser,
+		endpointInfoRegistry: endpointInfoRegistry,
+	}
+
+	if redir.conf.lookupNewDest == nil {
+		redir.conf.lookupNewDest = lookupNewDest
+	}
+
+	marker := 0
+	if !conf.noMarker {
+		markIdentity := int(0)
+		// As ingress proxy, all replies to incoming requests must have the
+		// identity of the endpoint we are proxying for
+		if r.ingress {
+			markIdentity = int(r.localEndpoint.GetIdentity())
+		}
+
+		marker = getMagicMark(r.ingress, markIdentity)
+	}
+
+	// Listen needs to be in the synchronous part of this function to ensure that
+	// the proxy port is never refusing connections.
+	socket, err := listenSocket(fmt.Sprintf(":%d", r.ProxyPort), marker)
+	if err != nil {
+		return nil, err
+	}
+
+	redir.socket = socket
+	r.setListenerBound(nil)
+
+	go func() {
+		for {
+			pair, err := socket.Accept(true)
+			select {
+			case <-socket.closing:
+				// Don't report errors while the socket is being closed
+				return
+			default:
+			}
+
+			if err != nil {
+				log.WithField(logfields.Port, r.ProxyPort).WithError(err).Error("Unable to accept connection on port")
+				r.setError(err)
+				continue
+			}
+
+			go redir.handleRequestConnection(pair)
+		}
+	}()
+
+	return redir, nil
+}
+
+// canAccess determines if the request req may be sent to or received from
+// the peer according to the rules configured on the redirect
+func (l *l7ParserRedirect) canAccess(req L7Frame, peer identity.NumericIdentity) bool {
+	id := identity.LookupIdentityByID(peer)
+	if id == nil {
+		log.WithFields(logrus.Fields{
+			logfields.Request:  req.GetFields(),
+			logfields.Identity: peer,
+		}).Warn("Unable to resolve identity to labels")
+	}
+
+	scopedLog := log.WithFields(logrus.Fields{
+		logfields.Request:  req.GetFields(),
+		logfields.Identity: id,
+	})
+
+	l.redirect.mutex.RLock()
+	rules := l.redirect.rules.GetRelevantRules(id)
+	l.redirect.mutex.RUnlock()
+
+	// L7Proto is set if any rules apply to the identity, including rules
+	// allowing all requests without any key-value pairs.
+	if rules.L7Proto == "" {
+		flowdebug.Log(scopedLog, "No L7 rules matching identity, rejecting")
+		return false
+	}
+
+	if len(rules.L7) == 0 {
+		return true
+	}
+
+	for _, rule := range rules.L7 {
+		if len(rule) == 0 || l.parser.Matches(req, rule) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maxPendingRequests is the maximum number of forwarded requests of a
+// connection awaiting their response for which the time of forwarding is kept
+const maxPendingRequests = 1024
+
+// pendingRequests keeps the time at which the requests of a connection were
+// forwarded, so that the latency of their responses can be computed. The
+// responses are assumed to answer the requests in order.
+type pendingRequests struct {
+	mutex lock.Mutex
+	times []time.Time
+}
+
+// push keeps the time at which a request was forwarded. The oldest request
+// is forgotten if the maximum number of requests is reached.
+func (p *pendingRequests) push(ts time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(p.times) >= maxPendingRequests {
+		p.times = p.times[1:]
+	}
+	p.times = append(p.times, ts)
+}
+
+// pop returns the time since the oldest request without response was
+// forwarded and forgets the request. Returns false if there is no such
+// request.
+func (p *pendingRequests) pop() (time.Duration, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(p.times) == 0 {
+		return 0, false
+	}
+	ts := p.times[0]
+	p.times = p.times[1:]
+	return time.Since(ts), true
+}
+
+func (l *l7ParserRedirect) newLogRecord(t accesslog.FlowType, frame L7Frame, remoteAddr net.Addr, remoteIdentity uint32, origDstAddr string) *logger.LogRecord {
+	l7 := &accesslog.LogRecordL7{Proto: string(l.redirect.parserType)}
+	if frame != nil {
+		l7.Fields = frame.GetFields()
+	}
+
+	return logger.NewLogRecord(l.endpointInfoRegistry, l.redirect.localEndpoint,
+		t, l.redirect.ingress,
+		logger.LogTags.L7(l7),
+		logger.LogTags.Addressing(logger.AddressingInfo{
+			SrcIPPort:   remoteAddr.String(),
+			DstIPPort:   origDstAddr,
+			SrcIdentity: remoteIdentity,
+		}))
+}
+
+// logRecord logs record with the verdict and updates the proxy statistics
+// of the local endpoint
+func (l *l7ParserRedirect) logRecord(record *logger.LogRecord, verdict accesslog.FlowVerdict, info string, tags ...logger.LogTag) {
+	record.ApplyTags(logger.LogTags.Verdict(verdict, info))
+	record.ApplyTags(tags...)
+	record.Log()
+
+	// The statistics are kept per port of the server, the destination of
+	// the requests in both directions.
+	ingress := record.ObservationPoint == accesslog.Ingress
+	port := record.DestinationEndpoint.Port
+	if port == 0 {
+		// Something went wrong when identifying the endpoints.
+		// Ignore in order to avoid polluting the stats.
+		return
+	}
+	request := record.Type == accesslog.TypeRequest
+	l.redirect.localEndpoint.UpdateProxyStatistics(string(l.redirect.parserType), port, ingress, request, verdict)
+}
+
+// handleRequest enforces the rules on req and forwards it if allowed. It
+// returns false if the connection must be closed.
+func (l *l7ParserRedirect) handleRequest(pair *connectionPair, decoder L7FrameDecoder, req L7Frame,
+	pending *pendingRequests, remoteAddr net.Addr, remoteIdentity uint32, origDstAddr string) bool {
+	scopedLog := log.WithField(fieldID, pair.String())
+	flowdebug.Log(scopedLog.WithField(logfields.Request, req.GetFields()), "Handling L7 request")
+
+	record := l.newLogRecord(accesslog.TypeRequest, req, remoteAddr, remoteIdentity, origDstAddr)
+
+	if !l.canAccess(req, peerIdentity(l.redirect.ingress, remoteIdentity, origDstAddr)) {
+		flowdebug.Log(scopedLog, "L7 request is denied by policy")
+		l.logRecord(record, accesslog.VerdictDenied, "L7 request is denied by policy")
+
+		resp := decoder.DenyResponse(req)
+		if resp == nil {
+			return false
+		}
+
+		pair.Rx.Enqueue(resp)
+		return true
+	}
+
+	if pair.Tx.Closed() {
+		marker := 0
+		if !l.conf.noMarker {
+			marker = getMagicMark(l.redirect.ingress, int(remoteIdentity))
+		}
+
+		flowdebug.Log(scopedLog.WithFields(logrus.Fields{
+			"marker":      marker,
+			"destination": origDstAddr,
+		}), "Dialing original destination")
+
+		txConn, err := ciliumDialer(marker, remoteAddr.Network(), origDstAddr,
+			originalSource(l.redirect.ingress, remoteAddr))
+		if err != nil {
+			scopedLog.WithError(err).WithFields(logrus.Fields{
+				"origNetwork": remoteAddr.Network(),
+				"origDest":    origDstAddr,
+			}).Error("Unable to dial original destination")
+
+			l.logRecord(record, accesslog.VerdictError, fmt.Sprintf("Unable to dial original destination: %s", err),
+				logger.LogTags.ErrorClass(logger.ErrorClassDial))
+			return false
+		}
+
+		pair.Tx.SetConnection(txConn)
+
+		go l.handleResponses(pair, decoder, pending, remoteAddr, remoteIdentity, origDstAddr)
+	}
+
+	flowdebug.Log(scopedLog, "Forwarding L7 request")
+	l.logRecord(record, accesslog.VerdictForwarded, "")
+
+	pending.push(time.Now())
+	pair.Tx.Enqueue(req.GetRaw())
+	return true
+}
+
+func (l *l7ParserRedirect) handleResponses(pair *connectionPair, decoder L7FrameDecoder,
+	pending *pendingRequests, remoteAddr net.Addr, remoteIdentity uint32, origDstAddr string) {
+	defer pair.Tx.Close()
+	scopedLog := log.WithField(fieldID, pair.String())
+
+	for {
+		rsp, err := decoder.ReadResponse(pair.Tx.conn)
+
+		// Ignore any error if the listen socket has been closed, i.e. the
+		// port redirect has been removed.
+		select {
+		case <-l.socket.closing:
+			scopedLog.Debug("Redirect removed; closing L7 response connection")
+			return
+		default:
+		}
+
+		if err != nil {
+			if err != io.ErrUnexpectedEOF && err != io.EOF {
+				record := l.newLogRecord(accesslog.TypeResponse, nil, remoteAddr, remoteIdentity, origDstAddr)
+				l.logRecord(record, accesslog.VerdictError, fmt.Sprintf("Unable to parse L7 response: %s", err),
+					logger.LogTags.ErrorClass(logger.ErrorClassParse))
+				scopedLog.WithError(err).Error("Unable to parse L7 response; closing L7 response connection")
+			}
+			return
+		}
+
+		record := l.newLogRecord(accesslog.TypeResponse, rsp, remoteAddr, remoteIdentity, origDstAddr)
+		if latency, ok := pending.pop(); ok {
+			record.ApplyTags(logger.LogTags.Latency(latency))
+		}
+		l.logRecord(record, accesslog.VerdictForwarded, "")
+
+		pair.Rx.Enqueue(rsp.GetRaw())
+	}
+}
+
+func (l *l7ParserRedirect) handleRequests(pair *connectionPair) {
+	defer pair.Rx.Close()
+	scopedLog := log.WithField(fieldID, pair.String())
+
+	remoteAddr := pair.Rx.conn.RemoteAddr()
+	if remoteAddr == nil {
+		scopedLog.Error("L7 request connection has no remote address")
+		return
+	}
+
+	// retrieve identity of source together with original destination IP
+	// and destination port
+	srcIdentity, dstIPPort, err := l.conf.lookupNewDest(remoteAddr.String(), l.redirect.ProxyPort)
+	if err != nil {
+		scopedLog.WithField("source",
+			remoteAddr.String()).WithError(err).Error("Unable to lookup original destination")
+		return
+	}
+
+	decoder := l.parser.NewDecoder()
+	pending := &pendingRequests{}
+	for {
+		req, err := decoder.ReadRequest(pair.Rx.conn)
+
+		// Ignore any error if the listen socket has been closed, i.e. the
+		// port redirect has been removed.
+		select {
+		case <-l.socket.closing:
+			scopedLog.Debug("Redirect removed; closing L7 request connection")
+			return
+		default:
+		}
+
+		if err != nil {
+			if err != io.ErrUnexpectedEOF && err != io.EOF {
+				scopedLog.WithError(err).Error("Unable to parse L7 request; closing L7 request connection")
+			}
+			return
+		}
+
+		if !l.handleRequest(pair, decoder, req, pending, remoteAddr, srcIdentity, dstIPPort) {
+			return
+		}
+	}
+}
+
+func (l *l7ParserRedirect) handleRequestConnection(pair *connectionPair) {
+	flowdebug.Log(log.WithFields(logrus.Fields{
+		"from": pair.Rx,
+		"to":   pair.Tx,
+	}), "Proxying request L7 connection")
+
+	l.handleRequests(pair)
+
+	// The proxymap contains an entry with metadata for the receive side of the
+	// connection, remove it after the connection has been closed.
+	if pair.Rx != nil {
+		// We are running in our own go routine here so we can just
+		// block this go routine until after the connection is
+		// guaranteed to have been closed
+		time.Sleep(proxyConnectionCloseTimeout + time.Second)
+
+		if err := l.redirect.removeProxyMapEntryOnClose(pair.Rx.conn); err != nil {
+			log.WithError(err).WithFields(logrus.Fields{
+				"from": pair.Rx,
+			}).Warning("Unable to remove proxymap entry after closing connection")
+		}
+	}
+}
+
+// Close the redirect.
+func (l *l7ParserRedirect) Close(wg *completion.WaitGroup) (revert.FinalizeFunc, revert.RevertFunc) {
+	return l.socket.Close, nil
+}
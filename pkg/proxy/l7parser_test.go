@@ -0,0 +1,212 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cilium/cilium/pkg/checker"
+	"github.com/cilium/cilium/pkg/identity"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	. "gopkg.in/check.v1"
+)
+
+// lineFrame is a newline terminated command of the line protocol
+type lineFrame string
+
+func (f lineFrame) GetRaw() []byte { return []byte(f) }
+
+func (f lineFrame) GetFields() map[string]string {
+	return map[string]string{"cmd": strings.TrimSpace(string(f))}
+}
+
+// lineDecoder buffers each direction of a connection in its own reader, as
+// the requests and responses are read from separate goroutines
+type lineDecoder struct {
+	requests, responses *bufio.Reader
+}
+
+func readLine(reader **bufio.Reader, r io.Reader) (L7Frame, error) {
+	if *reader == nil {
+		*reader = bufio.NewReader(r)
+	}
+	line, err := (*reader).ReadString('\n')
+	return lineFrame(line), err
+}
+
+func (d *lineDecoder) ReadRequest(r io.Reader) (L7Frame, error)  { return readLine(&d.requests, r) }
+func (d *lineDecoder) ReadResponse(r io.Reader) (L7Frame, error) { return readLine(&d.responses, r) }
+func (*lineDecoder) DenyResponse(req L7Frame) []byte             { return []byte("DENIED\n") }
+
+// lineParser implements a trivial line based protocol for testing
+type lineParser struct{}
+
+func (lineParser) NewDecoder() L7FrameDecoder { return &lineDecoder{} }
+
+func (lineParser) Matches(req L7Frame, rule api.PortRuleL7) bool {
+	return req.GetFields()["cmd"] == rule["cmd"]
+}
+
+func (s *proxyTestSuite) TestRegisterL7Parser(c *C) {
+	c.Assert(RegisterL7Parser(policy.ParserTypeHTTP, lineParser{}), Not(IsNil))
+	c.Assert(RegisterL7Parser(policy.ParserTypeKafka, lineParser{}), Not(IsNil))
+	c.Assert(RegisterL7Parser(policy.ParserTypeNone, lineParser{}), Not(IsNil))
+
+	c.Assert(getL7Parser("testline"), IsNil)
+	c.Assert(RegisterL7Parser("testline", lineParser{}), IsNil)
+	c.Assert(getL7Parser("testline"), Equals, lineParser{})
+	c.Assert(RegisterL7Parser("testline", lineParser{}), Not(IsNil))
+}
+
+func (s *proxyTestSuite) TestL7ParserCanAccess(c *C) {
+	redir := newRedirect(localEndpointMock, "test-l7parser")
+	redir.ingress = true
+	redir.parserType = "testline"
+	l := &l7ParserRedirect{redirect: redir, parser: lineParser{}}
+
+	get := lineFrame("GET\n")
+	put := lineFrame("PUT\n")
+	peer := identity.ReservedIdentityWorld
+
+	// No rules apply to the peer
+	redir.rules = policy.L7DataMap{}
+	c.Assert(l.canAccess(get, peer), Equals, false)
+
+	// Rules without key-value pairs allow all requests
+	redir.rules = policy.L7DataMap{
+		api.WildcardEndpointSelector: api.L7Rules{L7Proto: "testline", L7: []api.PortRuleL7{}},
+	}
+	c.Assert(l.canAccess(get, peer), Equals, true)
+	c.Assert(l.canAccess(put, peer), Equals, true)
+
+	redir.rules = policy.L7DataMap{
+		api.WildcardEndpointSelector: api.L7Rules{L7Proto: "testline", L7: []api.PortRuleL7{{"cmd": "GET"}}},
+	}
+	c.Assert(l.canAccess(get, peer), Equals, true)
+	c.Assert(l.canAccess(put, peer), Equals, false)
+
+	redir.rules = policy.L7DataMap{
+		api.WildcardEndpointSelector: api.L7Rules{L7Proto: "testline", L7: []api.PortRuleL7{{"cmd": "GET"}, {}}},
+	}
+	c.Assert(l.canAccess(put, peer), Equals, true)
+}
+
+func (s *proxyTestSuite) TestL7ParserPendingRequests(c *C) {
+	p := &pendingRequests{}
+	_, ok := p.pop()
+	c.Assert(ok, Equals, false)
+
+	// Responses answer the requests in order
+	p.push(time.Now().Add(-2 * time.Second))
+	p.push(time.Now().Add(-time.Second))
+	latency, ok := p.pop()
+	c.Assert(ok, Equals, true)
+	c.Assert(latency >= 2*time.Second, Equals, true)
+	latency, ok = p.pop()
+	c.Assert(ok, Equals, true)
+	c.Assert(latency < 2*time.Second, Equals, true)
+
+	// The oldest requests are forgotten once the maximum is reached
+	for i := 0; i <= maxPendingRequests; i++ {
+		p.push(time.Now())
+	}
+	c.Assert(p.times, HasLen, maxPendingRequests)
+}
+
+func (s *proxyTestSuite) TestL7ParserRedirect(c *C) {
+	// The server replies to each command with OK and the command
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer server.Close()
+	go func() {
+		conn, err := server.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("OK " + line))
+		}
+	}()
+	serverPort := uint16(server.Addr().(*net.TCPAddr).Port)
+
+	ep := &proxyUpdaterMock{id: 1000, ipv4: "10.0.0.1"}
+	r := newRedirect(ep, "test-l7parser-redirect")
+	r.ProxyPort = 15001
+	r.ingress = true
+	r.parserType = "testline"
+	r.rules = policy.L7DataMap{
+		api.WildcardEndpointSelector: api.L7Rules{L7Proto: "testline", L7: []api.PortRuleL7{{"cmd": "GET"}}},
+	}
+
+	redir, err := createL7ParserRedirect(r, lineParser{}, l7ParserConfiguration{
+		lookupNewDest: func(remoteAddr string, dport uint16) (uint32, string, error) {
+			return uint32(200), server.Addr().String(), nil
+		},
+		// Disable use of SO_MARK
+		noMarker: true,
+	}, DefaultEndpointInfoRegistry)
+	c.Assert(err, IsNil)
+	closeFunc, _ := redir.Close(nil)
+	defer closeFunc()
+
+	client, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", r.ProxyPort))
+	c.Assert(err, IsNil)
+	defer client.Close()
+	client.SetDeadline(time.Now().Add(5 * time.Second))
+	reader := bufio.NewReader(client)
+
+	// Both requests are received at once, none may be lost
+	_, err = client.Write([]byte("GET\nGET\n"))
+	c.Assert(err, IsNil)
+	for i := 0; i < 2; i++ {
+		line, err := reader.ReadString('\n')
+		c.Assert(err, IsNil)
+		c.Assert(line, Equals, "OK GET\n")
+	}
+
+	_, err = client.Write([]byte("PUT\n"))
+	c.Assert(err, IsNil)
+	line, err := reader.ReadString('\n')
+	c.Assert(err, IsNil)
+	c.Assert(line, Equals, "DENIED\n")
+
+	// The statistics are kept for the port of the server. Requests and
+	// responses are handled concurrently, only count them.
+	ep.Lock()
+	defer ep.Unlock()
+	counts := make(map[proxyStatsUpdate]int)
+	for _, update := range ep.proxyStats {
+		counts[update]++
+	}
+	c.Assert(counts, checker.DeepEquals, map[proxyStatsUpdate]int{
+		{"testline", serverPort, true, true, accesslog.VerdictForwarded}:  2,
+		{"testline", serverPort, true, false, accesslog.VerdictForwarded}: 2,
+		{"testline", serverPort, true, true, accesslog.VerdictDenied}:     1,
+	})
+}
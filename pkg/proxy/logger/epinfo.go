@@ -17,6 +17,7 @@ package logger
 import (
 	"net"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/proxy/accesslog"
 )
@@ -63,6 +64,10 @@ type EndpointUpdater interface {
 	// UpdateProxyStatistics updates the Endpoint's proxy statistics to account
 	// for a new observed flow with the given characteristics.
 	UpdateProxyStatistics(l7Protocol string, port uint16, ingress, request bool, verdict accesslog.FlowVerdict)
+
+	// UpdateProxyRedirectHealth updates the health of the proxy redirect
+	// with the given ID. A nil health removes the redirect.
+	UpdateProxyRedirectHealth(id string, health *models.ProxyRedirectHealth)
 }
 
 // EndpointInfoRegistry provides endpoint information lookup by endpoint IP
@@ -0,0 +1,260 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cilium/cilium/api/v1/models"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/miekg/dns"
+)
+
+// maxLatencySamples is the number of most recent latencies kept per L7
+// filter to compute the latency percentiles
+const maxLatencySamples = 1024
+
+// Error classes of records reporting a proxy error, see LogTags.ErrorClass()
+const (
+	// ErrorClassParse is the error class of messages which could not be
+	// parsed
+	ErrorClassParse = "parse-error"
+
+	// ErrorClassDial is the error class of requests which could not be
+	// forwarded because the connection to the destination failed
+	ErrorClassDial = "dial-error"
+
+	// ErrorClassForward is the error class of requests which could not be
+	// forwarded to or answered by the destination
+	ErrorClassForward = "forward-error"
+
+	// ErrorClassCircuitBreaker is the error class of requests rejected
+	// because they exceed a limit of the circuit breaker
+	ErrorClassCircuitBreaker = "circuit-breaker-overflow"
+
+	// ErrorClassUnknown is the error class of records reporting an error
+	// without a class
+	ErrorClassUnknown = "error"
+)
+
+// l7StatsKey identifies the L7 traffic of an endpoint subject to an L7 filter
+type l7StatsKey struct {
+	port    uint16
+	ingress bool
+	parser  string
+}
+
+// l7Stats is the L7 statistics of a single L7 filter
+type l7Stats struct {
+	requests int64
+	denied   int64
+	errors   map[string]int64
+
+	// latencies is a ring buffer of the most recent latencies, next is the
+	// index of the next sample to overwrite once the buffer is full
+	latencies []time.Duration
+	next      int
+}
+
+// L7Statistics is the statistics of the L7 traffic of an endpoint per port
+// and L7 parser. The zero value is ready to use.
+type L7Statistics struct {
+	mutex lock.Mutex
+	stats map[l7StatsKey]*l7Stats
+}
+
+// L7StatisticsSource is implemented by the endpoints which keep statistics
+// of their L7 traffic. The records of these endpoints are accounted in the
+// statistics returned by GetL7Statistics() when they are logged.
+type L7StatisticsSource interface {
+	GetL7Statistics() *L7Statistics
+}
+
+// l7ParserOf returns the name of the L7 parser which produced the record
+func l7ParserOf(lr *LogRecord) string {
+	switch {
+	case lr.HTTP != nil:
+		return "http"
+	case lr.Kafka != nil:
+		return "kafka"
+	case lr.DNS != nil:
+		return "dns"
+	case lr.L7 != nil:
+		return lr.L7.Proto
+	}
+	return ""
+}
+
+// errorClassOf returns the class of the error reported by the record, or an
+// empty string if the record does not report an error. A proxy error is
+// classified by the class attached to the record. Of the other records only
+// errors of responses forwarded to the client are classified, a response
+// generated to deny a request is accounted as a denial.
+func errorClassOf(lr *LogRecord) string {
+	if lr.Verdict == accesslog.VerdictError {
+		if lr.errorClass != "" {
+			return lr.errorClass
+		}
+		return ErrorClassUnknown
+	}
+	if lr.Type != accesslog.TypeResponse || lr.Verdict != accesslog.VerdictForwarded {
+		return ""
+	}
+
+	switch {
+	case lr.HTTP != nil:
+		switch {
+		case lr.HTTP.Code >= 500:
+			return "5xx"
+		case lr.HTTP.Code >= 400:
+			return "4xx"
+		}
+	case lr.Kafka != nil:
+		if lr.Kafka.ErrorCode != 0 {
+			return "kafka-" + strconv.Itoa(lr.Kafka.ErrorCode)
+		}
+	case lr.DNS != nil:
+		if lr.DNS.Rcode != dns.RcodeSuccess {
+			if name, ok := dns.RcodeToString[lr.DNS.Rcode]; ok {
+				return name
+			}
+			return "rcode-" + strconv.Itoa(lr.DNS.Rcode)
+		}
+	}
+	return ""
+}
+
+// update accounts for the record in the statistics
+func (l *L7Statistics) update(lr *LogRecord) {
+	parser := l7ParserOf(lr)
+	if parser == "" || lr.DestinationEndpoint.Port == 0 {
+		// Something went wrong when identifying the endpoints.
+		// Ignore in order to avoid polluting the stats.
+		return
+	}
+
+	key := l7StatsKey{
+		port:    lr.DestinationEndpoint.Port,
+		ingress: lr.ObservationPoint == accesslog.Ingress,
+		parser:  parser,
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.stats == nil {
+		l.stats = make(map[l7StatsKey]*l7Stats)
+	}
+	stats, ok := l.stats[key]
+	if !ok {
+		stats = &l7Stats{errors: map[string]int64{}}
+		l.stats[key] = stats
+	}
+
+	if lr.Type == accesslog.TypeRequest {
+		stats.requests++
+		if lr.Verdict == accesslog.VerdictDenied {
+			stats.denied++
+		}
+	}
+
+	if class := errorClassOf(lr); class != "" {
+		stats.errors[class]++
+	}
+
+	if lr.Type == accesslog.TypeResponse && lr.Latency > 0 {
+		if len(stats.latencies) < maxLatencySamples {
+			stats.latencies = append(stats.latencies, lr.Latency)
+		} else {
+			stats.latencies[stats.next] = lr.Latency
+			stats.next = (stats.next + 1) % maxLatencySamples
+		}
+	}
+}
+
+// percentile returns the p-th percentile of the sorted samples using the
+// nearest-rank method
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func (s *l7Stats) getLatencyModel() *models.LatencyPercentiles {
+	if len(s.latencies) == 0 {
+		return nil
+	}
+
+	sorted := make([]time.Duration, len(s.latencies))
+	copy(sorted, s.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &models.LatencyPercentiles{
+		P50:     int64(percentile(sorted, 50) / time.Microsecond),
+		P90:     int64(percentile(sorted, 90) / time.Microsecond),
+		P99:     int64(percentile(sorted, 99) / time.Microsecond),
+		Samples: int64(len(sorted)),
+	}
+}
+
+// GetModel returns the statistics as API model, sorted by location, port and
+// parser. The latency percentiles are computed from the retained samples, so
+// this should only be called on request.
+func (l *L7Statistics) GetModel() []*models.L7Statistics {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	result := make([]*models.L7Statistics, 0, len(l.stats))
+	for key, stats := range l.stats {
+		location := models.L7StatisticsLocationEgress
+		if key.ingress {
+			location = models.L7StatisticsLocationIngress
+		}
+
+		errors := make(map[string]int64, len(stats.errors))
+		for class, n := range stats.errors {
+			errors[class] = n
+		}
+
+		result = append(result, &models.L7Statistics{
+			Location: location,
+			Port:     int64(key.port),
+			Parser:   key.parser,
+			Requests: stats.requests,
+			Denied:   stats.denied,
+			Errors:   errors,
+			Latency:  stats.getLatencyModel(),
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Location != result[j].Location {
+			return result[i].Location > result[j].Location
+		}
+		if result[i].Port != result[j].Port {
+			return result[i].Port < result[j].Port
+		}
+		return result[i].Parser < result[j].Parser
+	})
+
+	return result
+}
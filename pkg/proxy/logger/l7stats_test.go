@@ -0,0 +1,104 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"time"
+
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	"github.com/miekg/dns"
+	. "gopkg.in/check.v1"
+)
+
+func newTestRecord(t accesslog.FlowType, verdict accesslog.FlowVerdict, tags ...LogTag) *LogRecord {
+	lr := &LogRecord{
+		LogRecord: accesslog.LogRecord{
+			Type:                t,
+			ObservationPoint:    accesslog.Ingress,
+			Verdict:             verdict,
+			DestinationEndpoint: accesslog.EndpointInfo{Port: 80},
+		},
+	}
+	lr.ApplyTags(tags...)
+	return lr
+}
+
+func (s *LoggerSuite) TestL7Statistics(c *C) {
+	var l L7Statistics
+
+	http := func(code int) LogTag {
+		return LogTags.HTTP(&accesslog.LogRecordHTTP{Code: code})
+	}
+
+	c.Assert(l.GetModel(), HasLen, 0)
+
+	for i := 1; i <= 100; i++ {
+		l.update(newTestRecord(accesslog.TypeRequest, accesslog.VerdictForwarded, http(0)))
+		l.update(newTestRecord(accesslog.TypeResponse, accesslog.VerdictForwarded, http(200),
+			LogTags.Latency(time.Duration(i)*time.Millisecond)))
+	}
+	l.update(newTestRecord(accesslog.TypeRequest, accesslog.VerdictDenied, http(403)))
+	l.update(newTestRecord(accesslog.TypeResponse, accesslog.VerdictForwarded, http(503)))
+	l.update(newTestRecord(accesslog.TypeRequest, accesslog.VerdictError, http(0),
+		LogTags.ErrorClass(ErrorClassParse)))
+	l.update(newTestRecord(accesslog.TypeRequest, accesslog.VerdictError, http(0),
+		LogTags.ErrorClass(ErrorClassDial)))
+	l.update(newTestRecord(accesslog.TypeRequest, accesslog.VerdictError, http(0)))
+	l.update(newTestRecord(accesslog.TypeResponse, accesslog.VerdictForwarded,
+		LogTags.DNS(&accesslog.LogRecordDNS{Rcode: dns.RcodeNameError})))
+
+	// Records without a port are ignored
+	noPort := newTestRecord(accesslog.TypeRequest, accesslog.VerdictForwarded, http(0))
+	noPort.DestinationEndpoint.Port = 0
+	l.update(noPort)
+
+	stats := l.GetModel()
+	c.Assert(stats, HasLen, 2)
+
+	c.Assert(stats[0].Parser, Equals, "dns")
+	c.Assert(stats[0].Errors, DeepEquals, map[string]int64{"NXDOMAIN": 1})
+
+	c.Assert(stats[1].Location, Equals, "ingress")
+	c.Assert(stats[1].Port, Equals, int64(80))
+	c.Assert(stats[1].Parser, Equals, "http")
+	c.Assert(stats[1].Requests, Equals, int64(104))
+	c.Assert(stats[1].Denied, Equals, int64(1))
+	c.Assert(stats[1].Errors, DeepEquals, map[string]int64{
+		"5xx": 1, ErrorClassParse: 1, ErrorClassDial: 1, ErrorClassUnknown: 1})
+	c.Assert(stats[1].Latency.Samples, Equals, int64(100))
+	c.Assert(stats[1].Latency.P50, Equals, int64(50000))
+	c.Assert(stats[1].Latency.P90, Equals, int64(90000))
+	c.Assert(stats[1].Latency.P99, Equals, int64(99000))
+}
+
+func (s *LoggerSuite) TestL7StatisticsLatencySamples(c *C) {
+	var l L7Statistics
+
+	for i := 0; i < maxLatencySamples+10; i++ {
+		latency := time.Second
+		if i >= 10 {
+			latency = time.Millisecond
+		}
+		l.update(newTestRecord(accesslog.TypeResponse, accesslog.VerdictForwarded,
+			LogTags.L7(&accesslog.LogRecordL7{Proto: "test"}), LogTags.Latency(latency)))
+	}
+
+	// The oldest samples have been overwritten
+	stats := l.GetModel()
+	c.Assert(stats, HasLen, 1)
+	c.Assert(stats[0].Latency.Samples, Equals, int64(maxLatencySamples))
+	c.Assert(stats[0].Latency.P99, Equals, int64(1000))
+}
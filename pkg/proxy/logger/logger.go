@@ -17,7 +17,9 @@ package logger
 import (
 	"encoding/json"
 	"net"
+	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cilium/cilium/pkg/flowdebug"
@@ -51,10 +53,17 @@ const (
 	FieldURL      = "url"
 	FieldProtocol = "protocol"
 	FieldHeader   = "header"
+	FieldTraceID  = "traceID"
 	FieldFilePath = logfields.Path
 	FieldMessage  = "message"
 )
 
+// fields used for structured logging of DNS messages
+const (
+	FieldDNSQuery = "dnsQuery"
+	FieldDNSRcode = "dnsRcode"
+)
+
 // fields used for structured logging of Kafka messages
 const (
 	FieldKafkaAPIKey        = "kafkaApiKey"
@@ -74,6 +83,14 @@ type LogRecord struct {
 	// either sent the request (for egress) or is receiving the request
 	// (for ingress)
 	localEndpointInfo *accesslog.EndpointInfo
+
+	// errorClass is the class of the error reported by a record with the
+	// error verdict, it is only accounted in the L7 statistics
+	errorClass string
+
+	// l7Statistics is the L7 statistics of the local endpoint, nil if the
+	// endpoint does not keep L7 statistics
+	l7Statistics *L7Statistics
 }
 
 // NewLogRecord creates a new log record and applies optional tags
@@ -104,6 +121,9 @@ func NewLogRecord(endpointInfoRegistry EndpointInfoRegistry, localEndpointInfoSo
 		endpointInfoRegistry: endpointInfoRegistry,
 		localEndpointInfo:    getEndpointInfo(localEndpointInfoSource),
 	}
+	if source, ok := localEndpointInfoSource.(L7StatisticsSource); ok {
+		lr.l7Statistics = source.GetL7Statistics()
+	}
 
 	for _, tagFn := range tags {
 		tagFn(&lr)
@@ -194,6 +214,14 @@ func (logTags) Verdict(v accesslog.FlowVerdict, info string) LogTag {
 	}
 }
 
+// ErrorClass attaches the class of the error reported by the log record, one
+// of the ErrorClass constants
+func (logTags) ErrorClass(class string) LogTag {
+	return func(lr *LogRecord) {
+		lr.errorClass = class
+	}
+}
+
 // Timestamp overwrites the starting timestamp of the log record
 func (logTags) Timestamp(ts time.Time) LogTag {
 	return func(lr *LogRecord) {
@@ -252,6 +280,36 @@ func (logTags) Addressing(i AddressingInfo) LogTag {
 func (logTags) HTTP(h *accesslog.LogRecordHTTP) LogTag {
 	return func(lr *LogRecord) {
 		lr.HTTP = h
+		lr.TraceID = traceIDFromHeaders(h.Headers)
+	}
+}
+
+// traceIDFromHeaders returns the trace ID of the W3C or B3 trace context
+// carried in the HTTP headers, or an empty string if there is none. The W3C
+// traceparent header takes precedence.
+func traceIDFromHeaders(headers http.Header) string {
+	// traceparent: {version}-{trace-id}-{parent-id}-{trace-flags}
+	if parts := strings.Split(headers.Get("traceparent"), "-"); len(parts) >= 4 && len(parts[1]) == 32 {
+		return parts[1]
+	}
+
+	if traceID := headers.Get("X-B3-TraceId"); traceID != "" {
+		return traceID
+	}
+
+	// b3: {trace-id}-{span-id}[-{sampled}[-{parent-span-id}]]
+	if parts := strings.Split(headers.Get("b3"), "-"); len(parts) >= 2 {
+		return parts[0]
+	}
+
+	return ""
+}
+
+// Latency attaches the time between a request and its response to the log
+// record
+func (logTags) Latency(d time.Duration) LogTag {
+	return func(lr *LogRecord) {
+		lr.Latency = d
 	}
 }
 
@@ -262,6 +320,13 @@ func (logTags) Kafka(k *accesslog.LogRecordKafka) LogTag {
 	}
 }
 
+// DNS attaches DNS information to the log record
+func (logTags) DNS(d *accesslog.LogRecordDNS) LogTag {
+	return func(lr *LogRecord) {
+		lr.DNS = d
+	}
+}
+
 // L7 attaches generic L7 information to the log record
 func (logTags) L7(h *accesslog.LogRecordL7) LogTag {
 	return func(lr *LogRecord) {
@@ -296,6 +361,10 @@ func (lr *LogRecord) getLogFields() *logrus.Entry {
 		})
 	}
 
+	if lr.TraceID != "" {
+		fields = fields.WithField(FieldTraceID, lr.TraceID)
+	}
+
 	if lr.Kafka != nil {
 		fields = fields.WithFields(logrus.Fields{
 			FieldCode:               lr.Kafka.ErrorCode,
@@ -305,6 +374,13 @@ func (lr *LogRecord) getLogFields() *logrus.Entry {
 		})
 	}
 
+	if lr.DNS != nil {
+		fields = fields.WithFields(logrus.Fields{
+			FieldDNSQuery: lr.DNS.Query,
+			FieldDNSRcode: lr.DNS.Rcode,
+		})
+	}
+
 	return fields
 }
 
@@ -317,10 +393,14 @@ func (lr *LogRecord) getRawLogMessage() []byte {
 	return append(b, byte('\n'))
 }
 
-// Log logs a record to the logfile and flushes the buffer
+// Log logs a record to the logfile and all sinks and flushes the buffer
 func (lr *LogRecord) Log() {
 	flowdebug.Log(lr.getLogFields(), "Logging flow record")
 
+	if lr.l7Statistics != nil {
+		lr.l7Statistics.update(lr)
+	}
+
 	// Lock while writing access log so we serialize writes as we may have
 	// to reopen the logfile and parallel writes could fail because of that
 	logMutex.Lock()
@@ -332,29 +412,53 @@ func (lr *LogRecord) Log() {
 		notifier.NewProxyLogRecord(lr)
 	}
 
+	raw := lr.getRawLogMessage()
+	writeToSinks(raw)
+
 	if logger == nil {
 		flowdebug.Log(log.WithField(FieldFilePath, logPath),
 			"Skipping writing to access log (logger nil)")
 		return
 	}
 
-	if _, err := logger.Write(lr.getRawLogMessage()); err != nil {
+	if _, err := logger.Write(raw); err != nil {
 		log.WithError(err).WithField(FieldFilePath, logPath).
 			Errorf("Error writing to access file")
 	}
 }
 
+// LogfileRotation configures the rotation of the access log file
+type LogfileRotation struct {
+	// MaxSize is the size in megabytes after which the file is rotated
+	MaxSize int
+
+	// MaxBackups is the number of rotated files to retain, 0 retains all
+	MaxBackups int
+
+	// MaxAge is the number of days to retain rotated files, 0 retains
+	// them regardless of their age
+	MaxAge int
+
+	// Compress enables gzip compression of rotated files
+	Compress bool
+}
+
 // Called with lock held
-func openLogfileLocked(lf string) error {
+func openLogfileLocked(lf string, rotation LogfileRotation) error {
 	logPath = lf
-	log.WithField(FieldFilePath, logPath).Info("Opened access log")
+	log.WithFields(logrus.Fields{
+		FieldFilePath: logPath,
+		"maxSize":     rotation.MaxSize,
+		"maxBackups":  rotation.MaxBackups,
+		"maxAge":      rotation.MaxAge,
+	}).Info("Opened access log")
 
 	logger = &lumberjack.Logger{
 		Filename:   lf,
-		MaxSize:    100, // megabytes
-		MaxBackups: 3,
-		MaxAge:     28,   //days
-		Compress:   true, // disabled by default
+		MaxSize:    rotation.MaxSize,
+		MaxBackups: rotation.MaxBackups,
+		MaxAge:     rotation.MaxAge,
+		Compress:   rotation.Compress,
 	}
 
 	return nil
@@ -366,12 +470,13 @@ type LogRecordNotifier interface {
 	NewProxyLogRecord(l *LogRecord) error
 }
 
-// OpenLogfile opens a file for logging
-func OpenLogfile(lf string) error {
+// OpenLogfile opens a file for logging JSON encoded records, one per line.
+// The file is rotated according to rotation.
+func OpenLogfile(lf string, rotation LogfileRotation) error {
 	logMutex.Lock()
 	defer logMutex.Unlock()
 
-	return openLogfileLocked(lf)
+	return openLogfileLocked(lf, rotation)
 }
 
 // SetNotifier sets the notifier to call for all L7 records
@@ -0,0 +1,93 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type LoggerSuite struct{}
+
+var _ = Suite(&LoggerSuite{})
+
+func (s *LoggerSuite) TestTraceIDFromHeaders(c *C) {
+	headers := http.Header{}
+	c.Assert(traceIDFromHeaders(headers), Equals, "")
+
+	headers.Set("b3", "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1")
+	c.Assert(traceIDFromHeaders(headers), Equals, "80f198ee56343ba864fe8b2a57d3eff7")
+
+	headers.Set("X-B3-TraceId", "463ac35c9f6413ad48485a3953bb6124")
+	c.Assert(traceIDFromHeaders(headers), Equals, "463ac35c9f6413ad48485a3953bb6124")
+
+	headers.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	c.Assert(traceIDFromHeaders(headers), Equals, "0af7651916cd43dd8448eb211c80319c")
+
+	// A malformed traceparent header is ignored
+	headers.Set("traceparent", "garbage")
+	c.Assert(traceIDFromHeaders(headers), Equals, "463ac35c9f6413ad48485a3953bb6124")
+}
+
+func (s *LoggerSuite) TestLogfile(c *C) {
+	dir, err := ioutil.TempDir("", "cilium-access-log")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "access.log")
+	rotation := LogfileRotation{MaxSize: 10, MaxBackups: 2, MaxAge: 1}
+	c.Assert(OpenLogfile(path, rotation), IsNil)
+	defer func() {
+		logMutex.Lock()
+		logger.Close()
+		logger = nil
+		logMutex.Unlock()
+	}()
+
+	c.Assert(logger.MaxSize, Equals, 10)
+	c.Assert(logger.MaxBackups, Equals, 2)
+	c.Assert(logger.MaxAge, Equals, 1)
+	c.Assert(logger.Compress, Equals, false)
+
+	record := &LogRecord{LogRecord: accesslog.LogRecord{Type: accesslog.TypeResponse}}
+	record.ApplyTags(LogTags.Verdict(accesslog.VerdictForwarded, ""),
+		LogTags.Latency(25*time.Millisecond),
+		LogTags.L7(&accesslog.LogRecordL7{Proto: "test", Fields: map[string]string{"cmd": "GET"}}))
+	record.Log()
+
+	b, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+
+	var logged accesslog.LogRecord
+	c.Assert(json.Unmarshal(b, &logged), IsNil)
+	c.Assert(logged.Type, Equals, accesslog.TypeResponse)
+	c.Assert(logged.Verdict, Equals, accesslog.VerdictForwarded)
+	c.Assert(logged.Latency, Equals, 25*time.Millisecond)
+	c.Assert(logged.L7.Fields["cmd"], Equals, "GET")
+}
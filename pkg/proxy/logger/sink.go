@@ -0,0 +1,267 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"log/syslog"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/metrics"
+
+	"github.com/optiopay/kafka"
+	"github.com/optiopay/kafka/proto"
+)
+
+const (
+	// FieldSink is the name of an access log sink
+	FieldSink = "sink"
+
+	// syslogTag is the tag of access log records sent to syslog
+	syslogTag = "cilium-access-log"
+
+	// kafkaClientID is the client ID used to produce access log records
+	kafkaClientID = "cilium-access-log"
+)
+
+// Sink is a destination to which access log records are streamed in addition
+// to the access log file.
+type Sink interface {
+	// Write writes a single JSON encoded record terminated by a newline
+	Write(record []byte) error
+
+	// Close releases all resources held by the sink
+	Close() error
+}
+
+// sinks is the list of all sinks, protected by logMutex
+var sinks []*bufferedSink
+
+// NewSink returns the sink described by rawurl. Supported are:
+//
+//	syslog://                      local syslog daemon
+//	syslog://host:port             remote syslog daemon via UDP
+//	unix:///path                   collector listening on a unix stream socket
+//	kafka://broker1,broker2/topic  Kafka topic
+//
+// Connections are established on the first write and re-established after
+// a failed write.
+func NewSink(rawurl string) (Sink, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid access log sink %q: %s", rawurl, err)
+	}
+
+	switch u.Scheme {
+	case "syslog":
+		return &syslogSink{addr: u.Host}, nil
+	case "unix":
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid access log sink %q: missing socket path", rawurl)
+		}
+		return &unixSink{path: u.Path}, nil
+	case "kafka":
+		topic := strings.TrimPrefix(u.Path, "/")
+		if u.Host == "" || topic == "" {
+			return nil, fmt.Errorf("invalid access log sink %q: expected kafka://broker[,broker...]/topic", rawurl)
+		}
+		return &kafkaSink{brokers: strings.Split(u.Host, ","), topic: topic}, nil
+	default:
+		return nil, fmt.Errorf("invalid access log sink %q: unsupported type %q", rawurl, u.Scheme)
+	}
+}
+
+// AddSink streams all subsequent access log records to sink. Up to
+// bufferSize records are buffered while the sink is busy, further records
+// are dropped and counted.
+func AddSink(name string, sink Sink, bufferSize int) {
+	b := newBufferedSink(name, sink, bufferSize)
+
+	logMutex.Lock()
+	sinks = append(sinks, b)
+	logMutex.Unlock()
+
+	log.WithField(FieldSink, name).Info("Streaming access log records to sink")
+}
+
+// bufferedSink decouples a sink from the proxies logging records
+type bufferedSink struct {
+	name    string
+	sink    Sink
+	records chan []byte
+}
+
+func newBufferedSink(name string, sink Sink, bufferSize int) *bufferedSink {
+	b := &bufferedSink{
+		name:    name,
+		sink:    sink,
+		records: make(chan []byte, bufferSize),
+	}
+	go b.run()
+	return b
+}
+
+// enqueue queues a record for writing without blocking. The record is
+// dropped if the buffer is full.
+func (b *bufferedSink) enqueue(record []byte) {
+	select {
+	case b.records <- record:
+	default:
+		b.drop()
+	}
+}
+
+// drop counts a record which could not be written to the sink
+func (b *bufferedSink) drop() {
+	metrics.ProxyAccessLogSinkDropped.WithLabelValues(b.name).Inc()
+}
+
+func (b *bufferedSink) run() {
+	failing := false
+	for record := range b.records {
+		if err := b.sink.Write(record); err != nil {
+			// Only log the first of a series of errors
+			if !failing {
+				log.WithError(err).WithField(FieldSink, b.name).
+					Warning("Unable to write to access log sink, dropping records")
+				failing = true
+			}
+			b.drop()
+			continue
+		}
+		failing = false
+	}
+}
+
+// syslogSink writes records to a local or remote syslog daemon
+type syslogSink struct {
+	addr   string
+	writer *syslog.Writer
+}
+
+func (s *syslogSink) Write(record []byte) error {
+	if s.writer == nil {
+		network := ""
+		if s.addr != "" {
+			network = "udp"
+		}
+		w, err := syslog.Dial(network, s.addr, syslog.LOG_INFO|syslog.LOG_DAEMON, syslogTag)
+		if err != nil {
+			return err
+		}
+		s.writer = w
+	}
+
+	// The syslog writer reconnects on its own
+	_, err := s.writer.Write(bytes.TrimSuffix(record, []byte{'\n'}))
+	return err
+}
+
+func (s *syslogSink) Close() error {
+	if s.writer == nil {
+		return nil
+	}
+	return s.writer.Close()
+}
+
+// unixSink writes newline delimited records to a unix stream socket
+type unixSink struct {
+	path string
+	conn net.Conn
+}
+
+func (s *unixSink) Write(record []byte) error {
+	if s.conn == nil {
+		conn, err := net.Dial("unix", s.path)
+		if err != nil {
+			return err
+		}
+		s.conn = conn
+	}
+
+	if _, err := s.conn.Write(record); err != nil {
+		s.Close()
+		return err
+	}
+	return nil
+}
+
+func (s *unixSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// kafkaSink produces each record as a message to a Kafka topic
+type kafkaSink struct {
+	brokers  []string
+	topic    string
+	broker   *kafka.Broker
+	producer kafka.DistributingProducer
+}
+
+func (s *kafkaSink) connect() error {
+	broker, err := kafka.Dial(s.brokers, kafka.NewBrokerConf(kafkaClientID))
+	if err != nil {
+		return err
+	}
+
+	partitions, err := broker.PartitionCount(s.topic)
+	if err != nil {
+		broker.Close()
+		return err
+	}
+
+	s.broker = broker
+	s.producer = kafka.NewRoundRobinProducer(broker.Producer(kafka.NewProducerConf()), partitions)
+	return nil
+}
+
+func (s *kafkaSink) Write(record []byte) error {
+	if s.broker == nil {
+		if err := s.connect(); err != nil {
+			return err
+		}
+	}
+
+	msg := &proto.Message{Value: bytes.TrimSuffix(record, []byte{'\n'})}
+	if _, err := s.producer.Distribute(s.topic, msg); err != nil {
+		s.Close()
+		return err
+	}
+	return nil
+}
+
+func (s *kafkaSink) Close() error {
+	if s.broker != nil {
+		s.broker.Close()
+		s.broker = nil
+		s.producer = nil
+	}
+	return nil
+}
+
+// writeToSinks queues record for all sinks, called with logMutex held
+func writeToSinks(record []byte) {
+	for _, b := range sinks {
+		b.enqueue(record)
+	}
+}
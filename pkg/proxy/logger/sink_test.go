@@ -0,0 +1,130 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/pkg/metrics"
+	"github.com/cilium/cilium/pkg/proxy/accesslog"
+
+	dto "github.com/prometheus/client_model/go"
+	. "gopkg.in/check.v1"
+)
+
+func (s *LoggerSuite) TestNewSink(c *C) {
+	sink, err := NewSink("syslog://")
+	c.Assert(err, IsNil)
+	c.Assert(sink.(*syslogSink).addr, Equals, "")
+
+	sink, err = NewSink("syslog://127.0.0.1:514")
+	c.Assert(err, IsNil)
+	c.Assert(sink.(*syslogSink).addr, Equals, "127.0.0.1:514")
+
+	sink, err = NewSink("unix:///var/run/collector.sock")
+	c.Assert(err, IsNil)
+	c.Assert(sink.(*unixSink).path, Equals, "/var/run/collector.sock")
+
+	sink, err = NewSink("kafka://broker1:9092,broker2:9092/access-log")
+	c.Assert(err, IsNil)
+	c.Assert(sink.(*kafkaSink).brokers, DeepEquals, []string{"broker1:9092", "broker2:9092"})
+	c.Assert(sink.(*kafkaSink).topic, Equals, "access-log")
+
+	for _, invalid := range []string{"", "/var/log/access.log", "unix://", "kafka://broker1:9092", "kafka:///topic", "http://collector"} {
+		_, err = NewSink(invalid)
+		c.Assert(err, Not(IsNil), Commentf("%q", invalid))
+	}
+}
+
+func (s *LoggerSuite) TestUnixSink(c *C) {
+	dir, err := ioutil.TempDir("", "cilium-access-log")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "collector.sock")
+	listener, err := net.Listen("unix", path)
+	c.Assert(err, IsNil)
+	defer listener.Close()
+
+	sink, err := NewSink("unix://" + path)
+	c.Assert(err, IsNil)
+	AddSink("test", sink, 16)
+	defer func() {
+		logMutex.Lock()
+		sinks = nil
+		logMutex.Unlock()
+	}()
+
+	record := &LogRecord{LogRecord: accesslog.LogRecord{Type: accesslog.TypeRequest}}
+	record.ApplyTags(LogTags.Verdict(accesslog.VerdictForwarded, ""))
+	record.Log()
+
+	conn, err := listener.Accept()
+	c.Assert(err, IsNil)
+	defer conn.Close()
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	c.Assert(err, IsNil)
+
+	var logged accesslog.LogRecord
+	c.Assert(json.Unmarshal(line, &logged), IsNil)
+	c.Assert(logged.Type, Equals, accesslog.TypeRequest)
+	c.Assert(logged.Verdict, Equals, accesslog.VerdictForwarded)
+}
+
+// blockingSink blocks each write until it is released with a result
+type blockingSink struct {
+	writing chan struct{}
+	release chan error
+}
+
+func (s *blockingSink) Write(record []byte) error {
+	s.writing <- struct{}{}
+	return <-s.release
+}
+
+func (s *blockingSink) Close() error {
+	return nil
+}
+
+func (s *LoggerSuite) TestBufferedSinkDrops(c *C) {
+	sink := &blockingSink{writing: make(chan struct{}), release: make(chan error)}
+	b := newBufferedSink("drops", sink, 1)
+	dropped := func() float64 {
+		var m dto.Metric
+		c.Assert(metrics.ProxyAccessLogSinkDropped.WithLabelValues("drops").Write(&m), IsNil)
+		return m.GetCounter().GetValue()
+	}
+
+	// The first record is being written, the second fills the buffer and
+	// the third is dropped.
+	b.enqueue([]byte("1\n"))
+	<-sink.writing
+	b.enqueue([]byte("2\n"))
+	b.enqueue([]byte("3\n"))
+	c.Assert(dropped(), Equals, 1.0)
+
+	// A failed write is counted as a drop before the next record is written
+	sink.release <- errors.New("connection refused")
+	<-sink.writing
+	c.Assert(dropped(), Equals, 2.0)
+	sink.release <- nil
+}
@@ -37,6 +37,7 @@ package proxy
 //
 // Cilium Mark (4 bits):
 // M M M M
+// 0 0 1 0 To transparent proxy socket
 // 1 0 1 0 Ingress proxy
 // 1 0 1 1 Egress proxy
 // 1 1 0 0 From host
@@ -53,6 +54,10 @@ const (
 	// with a proxy.
 	MagicMarkIsProxy int = 0x0A00
 
+	// MagicMarkIsToProxy determines that the traffic is destined to a
+	// transparent socket of a proxy and must be delivered locally.
+	MagicMarkIsToProxy int = 0x0200
+
 	// MagicMarkIngress determines that the traffic is sourced from the
 	// proxy which is applying Ingress policy
 	MagicMarkIngress int = 0x0A00
@@ -15,6 +15,7 @@
 package proxy
 
 import (
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/identity"
 	"github.com/cilium/cilium/pkg/labels"
 	"github.com/cilium/cilium/pkg/lock"
@@ -29,6 +30,17 @@ type proxyUpdaterMock struct {
 	labels          []string
 	identity        identity.NumericIdentity
 	hasSidecarProxy bool
+	redirectHealth  *models.ProxyRedirectHealth
+	proxyStats      []proxyStatsUpdate
+}
+
+// proxyStatsUpdate records a call of UpdateProxyStatistics
+type proxyStatsUpdate struct {
+	l7Protocol string
+	port       uint16
+	ingress    bool
+	request    bool
+	verdict    accesslog.FlowVerdict
 }
 
 func (m *proxyUpdaterMock) UnconditionalRLock() { m.RWMutex.RLock() }
@@ -47,4 +59,12 @@ func (m *proxyUpdaterMock) HasSidecarProxy() bool { return m.hasSidecarProxy }
 func (m *proxyUpdaterMock) OnProxyPolicyUpdate(policyRevision uint64) {}
 func (m *proxyUpdaterMock) UpdateProxyStatistics(l7Protocol string, port uint16, ingress, request bool,
 	verdict accesslog.FlowVerdict) {
+	m.Lock()
+	defer m.Unlock()
+	m.proxyStats = append(m.proxyStats, proxyStatsUpdate{l7Protocol, port, ingress, request, verdict})
+}
+func (m *proxyUpdaterMock) UpdateProxyRedirectHealth(id string, health *models.ProxyRedirectHealth) {
+	m.Lock()
+	defer m.Unlock()
+	m.redirectHealth = health
 }
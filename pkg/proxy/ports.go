@@ -0,0 +1,244 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/policy"
+)
+
+// portsFile is the name of the file in the state directory in which the
+// proxy port assignments are persisted across restarts of the agent
+const portsFile = "proxy-ports.json"
+
+// portAssignment is the assignment of a proxy port to a redirect
+type portAssignment struct {
+	Port uint16 `json:"port"`
+
+	// Released is the time at which the redirect has been removed. It is
+	// zero while the redirect exists.
+	Released time.Time `json:"released"`
+}
+
+// portAllocator assigns proxy ports to redirects. The port of a redirect is
+// retained for portReuseDelay after the redirect has been removed, so a
+// redirect which is created again in the meantime, e.g. after a policy update
+// or a restart of the agent, is assigned the same port and the redirects in
+// the datapath remain unchanged.
+type portAllocator struct {
+	// rangeMin and rangeMax are the bounds of the allocated ports
+	rangeMin uint16
+	rangeMax uint16
+
+	// path is the file in which the assignments are persisted. Empty
+	// disables persistence.
+	path string
+
+	// assignments maps the key of a redirect to its port, see portKey
+	assignments map[string]*portAssignment
+
+	// openLocalPorts returns the set of ports currently open locally
+	openLocalPorts func() (map[uint16]struct{}, error)
+}
+
+// portKey returns the key of the port assignment of the redirect with the
+// given ID and L7 parser. The ID identifies the endpoint, direction and port.
+func portKey(id string, parser policy.L7ParserType) string {
+	return fmt.Sprintf("%s:%s", id, parser)
+}
+
+// newPortAllocator returns a port allocator for the range rangeMin-rangeMax.
+// If stateDir is not empty, the assignments are persisted in it and the
+// assignments of a previous run are restored as released assignments.
+func newPortAllocator(rangeMin, rangeMax uint16, stateDir string) *portAllocator {
+	a := &portAllocator{
+		rangeMin:    rangeMin,
+		rangeMax:    rangeMax,
+		assignments: make(map[string]*portAssignment),
+		openLocalPorts: func() (map[uint16]struct{}, error) {
+			return readOpenLocalPorts(procNetTCPFiles)
+		},
+	}
+
+	if stateDir != "" {
+		a.path = filepath.Join(stateDir, portsFile)
+		if err := a.restore(); err != nil && !os.IsNotExist(err) {
+			log.WithError(err).WithField(logfields.Path, a.path).
+				Warning("Unable to restore proxy port assignments")
+		}
+	}
+
+	return a
+}
+
+// restore reads the assignments of a previous run. The redirects of the
+// previous run do not exist yet, so all assignments are restored as released
+// at the current time.
+func (a *portAllocator) restore() error {
+	b, err := ioutil.ReadFile(a.path)
+	if err != nil {
+		return err
+	}
+
+	assignments := make(map[string]*portAssignment)
+	if err := json.Unmarshal(b, &assignments); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	allocated := make(map[uint16]struct{}, len(assignments))
+	for key, as := range assignments {
+		if as == nil || as.Port < a.rangeMin || as.Port > a.rangeMax {
+			continue
+		}
+		if _, ok := allocated[as.Port]; ok {
+			continue
+		}
+		allocated[as.Port] = struct{}{}
+		a.assignments[key] = &portAssignment{Port: as.Port, Released: now}
+	}
+
+	log.WithField("count", len(a.assignments)).Info("Restored proxy port assignments")
+	return nil
+}
+
+// save persists the assignments. Errors are logged, the assignments remain
+// valid for the current run.
+func (a *portAllocator) save() {
+	if a.path == "" {
+		return
+	}
+
+	b, err := json.Marshal(a.assignments)
+	if err == nil {
+		tmp := a.path + ".tmp"
+		if err = ioutil.WriteFile(tmp, b, 0600); err == nil {
+			err = os.Rename(tmp, a.path)
+		}
+	}
+	if err != nil {
+		log.WithError(err).WithField(logfields.Path, a.path).
+			Warning("Unable to persist proxy port assignments")
+	}
+}
+
+// allocate returns a port of the range which is neither assigned nor open
+// locally
+func (a *portAllocator) allocate() (uint16, error) {
+	// Get a snapshot of the TCP ports already open locally.
+	openLocalPorts, err := a.openLocalPorts()
+	if err != nil {
+		return 0, fmt.Errorf("couldn't read local ports from /proc: %s", err)
+	}
+
+	allocated := make(map[uint16]struct{}, len(a.assignments))
+	for _, as := range a.assignments {
+		allocated[as.Port] = struct{}{}
+	}
+
+	portRandomizerMutex.Lock()
+	defer portRandomizerMutex.Unlock()
+
+	for _, r := range portRandomizer.Perm(int(a.rangeMax - a.rangeMin + 1)) {
+		resPort := uint16(r) + a.rangeMin
+
+		if _, ok := allocated[resPort]; !ok {
+			if _, alreadyOpen := openLocalPorts[resPort]; !alreadyOpen {
+				return resPort, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("no available proxy ports")
+}
+
+// acquire returns the port for the redirect identified by key. The port
+// previously assigned to key is reused unless reuse is false, e.g. because
+// the port could not be opened. In that case, a different port is assigned.
+func (a *portAllocator) acquire(key string, reuse bool) (uint16, error) {
+	if as, ok := a.assignments[key]; ok && reuse {
+		if !as.Released.IsZero() {
+			as.Released = time.Time{}
+			a.save()
+		}
+		return as.Port, nil
+	}
+
+	port, err := a.allocate()
+	if err != nil {
+		return 0, err
+	}
+
+	a.assignments[key] = &portAssignment{Port: port}
+	a.save()
+
+	return port, nil
+}
+
+// remove removes the assignment of key immediately, the port may be
+// allocated again right away.
+func (a *portAllocator) remove(key string) {
+	if _, ok := a.assignments[key]; ok {
+		delete(a.assignments, key)
+		a.save()
+	}
+}
+
+// release marks the port of key as no longer in use and returns the time of
+// the release which must be passed to expire.
+func (a *portAllocator) release(key string) time.Time {
+	as, ok := a.assignments[key]
+	if !ok {
+		return time.Time{}
+	}
+
+	as.Released = time.Now()
+	a.save()
+
+	return as.Released
+}
+
+// expire removes the assignment of key if it has not been acquired again
+// since it was released at the given time. Returns true if the assignment
+// has been removed.
+func (a *portAllocator) expire(key string, released time.Time) bool {
+	as, ok := a.assignments[key]
+	if !ok || released.IsZero() || !as.Released.Equal(released) {
+		return false
+	}
+
+	delete(a.assignments, key)
+	a.save()
+
+	return true
+}
+
+// released returns the released assignments indexed by key
+func (a *portAllocator) released() map[string]portAssignment {
+	result := make(map[string]portAssignment)
+	for key, as := range a.assignments {
+		if !as.Released.IsZero() {
+			result[key] = *as
+		}
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/pkg/policy"
+
+	. "gopkg.in/check.v1"
+)
+
+func newTestPortAllocator(rangeMin, rangeMax uint16, stateDir string) *portAllocator {
+	a := newPortAllocator(rangeMin, rangeMax, stateDir)
+	a.openLocalPorts = func() (map[uint16]struct{}, error) {
+		return map[uint16]struct{}{}, nil
+	}
+	return a
+}
+
+func (s *proxyTestSuite) TestPortAllocatorReuse(c *C) {
+	a := newTestPortAllocator(10000, 10001, "")
+	httpKey := portKey("1:ingress:TCP:80", policy.ParserTypeHTTP)
+	kafkaKey := portKey("1:ingress:TCP:80", policy.ParserTypeKafka)
+
+	port, err := a.acquire(httpKey, true)
+	c.Assert(err, IsNil)
+
+	// A released port is retained for the same redirect
+	released := a.release(httpKey)
+	c.Assert(released.IsZero(), Equals, false)
+	reused, err := a.acquire(httpKey, true)
+	c.Assert(err, IsNil)
+	c.Assert(reused, Equals, port)
+
+	// Expiry of a release after the port has been acquired again has no
+	// effect
+	c.Assert(a.expire(httpKey, released), Equals, false)
+
+	// A different parser is assigned a different port
+	other, err := a.acquire(kafkaKey, true)
+	c.Assert(err, IsNil)
+	c.Assert(other, Not(Equals), port)
+
+	// All ports of the range are assigned
+	_, err = a.acquire(portKey("2:egress:TCP:53", policy.ParserTypeDNS), true)
+	c.Assert(err, Not(IsNil))
+
+	released = a.release(httpKey)
+	c.Assert(a.expire(httpKey, released), Equals, true)
+	_, err = a.acquire(portKey("2:egress:TCP:53", policy.ParserTypeDNS), true)
+	c.Assert(err, IsNil)
+}
+
+func (s *proxyTestSuite) TestPortAllocatorNoReuse(c *C) {
+	a := newTestPortAllocator(10000, 10001, "")
+	key := portKey("1:ingress:TCP:80", policy.ParserTypeHTTP)
+
+	port, err := a.acquire(key, true)
+	c.Assert(err, IsNil)
+
+	other, err := a.acquire(key, false)
+	c.Assert(err, IsNil)
+	c.Assert(other, Not(Equals), port)
+
+	a.remove(key)
+	c.Assert(a.assignments, HasLen, 0)
+}
+
+func (s *proxyTestSuite) TestPortAllocatorRestore(c *C) {
+	stateDir, err := ioutil.TempDir("", "proxy-ports")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(stateDir)
+
+	key := portKey("1:ingress:TCP:80", policy.ParserTypeHTTP)
+
+	a := newTestPortAllocator(10000, 20000, stateDir)
+	port, err := a.acquire(key, true)
+	c.Assert(err, IsNil)
+
+	// The assignment is restored as released by the next run
+	b := newTestPortAllocator(10000, 20000, stateDir)
+	released := b.released()
+	c.Assert(released, HasLen, 1)
+	c.Assert(released[key].Port, Equals, port)
+
+	reused, err := b.acquire(key, true)
+	c.Assert(err, IsNil)
+	c.Assert(reused, Equals, port)
+	c.Assert(b.released(), HasLen, 0)
+
+	// Assignments outside of the range are dropped
+	d := newTestPortAllocator(port+1, port+1, stateDir)
+	c.Assert(d.assignments, HasLen, 0)
+
+	// A corrupt file is ignored
+	c.Assert(ioutil.WriteFile(filepath.Join(stateDir, portsFile), []byte("{"), 0600), IsNil)
+	e := newTestPortAllocator(10000, 20000, stateDir)
+	c.Assert(e.assignments, HasLen, 0)
+}
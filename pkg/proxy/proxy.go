@@ -30,6 +30,7 @@ import (
 	"github.com/cilium/cilium/pkg/maps/proxymap"
 	"github.com/cilium/cilium/pkg/metrics"
 	"github.com/cilium/cilium/pkg/node"
+	"github.com/cilium/cilium/pkg/option"
 	"github.com/cilium/cilium/pkg/policy"
 	"github.com/cilium/cilium/pkg/proxy/logger"
 	"github.com/cilium/cilium/pkg/revert"
@@ -74,23 +75,34 @@ type Proxy struct {
 	// ports out of the rangeMin-rangeMax range.
 	rangeMax uint16
 
-	// allocatedPorts is the map of all allocated proxy ports
-	allocatedPorts map[uint16]struct{}
+	// ports assigns proxy ports to redirects
+	ports *portAllocator
 
 	// redirects is the map of all redirect configurations indexed by
 	// the redirect identifier. Redirects may be implemented by different
 	// proxies.
 	redirects map[string]*Redirect
+
+	// draining is the map of removed redirects which keep serving their
+	// existing connections until option.Config.ProxyDrainTimeout has
+	// passed, indexed by the port key of the redirect.
+	draining map[string]*Redirect
+
+	// dnsResponseNotifier is called by DNS redirects with the IPs of
+	// each forwarded DNS response
+	dnsResponseNotifier DNSResponseNotifier
 }
 
 // StartProxySupport starts the servers to support L7 proxies: xDS GRPC server
-// and access log server.
+// and access log server. The access log file is rotated according to
+// accessLogRotation.
 func StartProxySupport(minPort uint16, maxPort uint16, stateDir string,
-	accessLogFile string, accessLogNotifier logger.LogRecordNotifier, accessLogMetadata []string) *Proxy {
+	accessLogFile string, accessLogRotation logger.LogfileRotation, accessLogNotifier logger.LogRecordNotifier,
+	accessLogMetadata []string) *Proxy {
 	xdsServer := envoy.StartXDSServer(stateDir)
 
 	if accessLogFile != "" {
-		if err := logger.OpenLogfile(accessLogFile); err != nil {
+		if err := logger.OpenLogfile(accessLogFile, accessLogRotation); err != nil {
 			log.WithError(err).WithField(logger.FieldFilePath, accessLogFile).
 				Warn("Cannot open L7 access log")
 		}
@@ -106,14 +118,32 @@ func StartProxySupport(minPort uint16, maxPort uint16, stateDir string,
 
 	envoy.StartAccessLogServer(stateDir, xdsServer, DefaultEndpointInfoRegistry)
 
-	return &Proxy{
-		XDSServer:      xdsServer,
-		stateDir:       stateDir,
-		rangeMin:       minPort,
-		rangeMax:       maxPort,
-		redirects:      make(map[string]*Redirect),
-		allocatedPorts: make(map[uint16]struct{}),
+	p := &Proxy{
+		XDSServer: xdsServer,
+		stateDir:  stateDir,
+		rangeMin:  minPort,
+		rangeMax:  maxPort,
+		redirects: make(map[string]*Redirect),
+		draining:  make(map[string]*Redirect),
+		ports:     newPortAllocator(minPort, maxPort, stateDir),
+	}
+
+	// The ports of the previous run are retained for the redirects
+	// created again by the restored endpoints.
+	for key, as := range p.ports.released() {
+		p.expirePort(key, as.Port, as.Released)
 	}
+
+	return p
+}
+
+// SetDNSResponseNotifier sets the function called by DNS redirects with the
+// IPs of each forwarded DNS response. Only redirects created afterwards are
+// affected.
+func (p *Proxy) SetDNSResponseNotifier(notifier DNSResponseNotifier) {
+	p.mutex.Lock()
+	p.dnsResponseNotifier = notifier
+	p.mutex.Unlock()
 }
 
 var (
@@ -121,28 +151,25 @@ var (
 	portRandomizerMutex lock.Mutex
 )
 
-func (p *Proxy) allocatePort() (uint16, error) {
-	// Get a snapshot of the TCP ports already open locally.
-	openLocalPorts, err := readOpenLocalPorts(procNetTCPFiles)
-	if err != nil {
-		return 0, fmt.Errorf("couldn't read local ports from /proc: %s", err)
-	}
+// expirePort removes the assignment of the port released at the given time
+// after portReuseDelay unless the port has been acquired again in the
+// meantime.
+func (p *Proxy) expirePort(key string, port uint16, released time.Time) {
+	go func() {
+		time.Sleep(portReuseDelay)
 
-	portRandomizerMutex.Lock()
-	defer portRandomizerMutex.Unlock()
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
 
-	for _, r := range portRandomizer.Perm(int(p.rangeMax - p.rangeMin + 1)) {
-		resPort := uint16(r) + p.rangeMin
+		if p.ports.expire(key, released) {
+			// The cleanup of the proxymap is delayed a bit to ensure
+			// that the datapath has implemented the redirect change
+			// and we cleanup the map before the port can be reused
+			proxymap.CleanupOnRedirectClose(port)
 
-		if _, ok := p.allocatedPorts[resPort]; !ok {
-			if _, alreadyOpen := openLocalPorts[resPort]; !alreadyOpen {
-				return resPort, nil
-			}
+			log.WithField(fieldProxyRedirectID, key).Debugf("Delayed release of proxy port %d", port)
 		}
-
-	}
-
-	return 0, fmt.Errorf("no available proxy ports")
+	}()
 }
 
 var gcOnce sync.Once
@@ -180,10 +207,19 @@ func (p *Proxy) CreateOrUpdateRedirect(l4 *policy.L4Filter, id string, localEndp
 	if redir, ok = p.redirects[id]; ok {
 		redir.mutex.Lock()
 
-		if redir.parserType != l4.L7Parser {
+		// The TLS context and the circuit breaker are part of the proxy
+		// configuration, they are updated in place if possible. The
+		// redirect is recreated with the same port otherwise.
+		var configRevertFunc revert.RevertFunc
+		updated := redir.parserType == l4.L7Parser
+		if updated {
+			configRevertFunc, updated = redir.updateProxyConfig(l4, wg)
+		}
+		if !updated {
+			redir.mutex.Unlock()
+
 			var removeRevertFunc revert.RevertFunc
 			err, finalizeFunc, removeRevertFunc = p.removeRedirect(id, wg)
-			redir.mutex.Unlock()
 
 			if err != nil {
 				err = fmt.Errorf("unable to remove old redirect: %s", err)
@@ -195,10 +231,12 @@ func (p *Proxy) CreateOrUpdateRedirect(l4 *policy.L4Filter, id string, localEndp
 			goto create
 		}
 
+		revertStack.Push(configRevertFunc)
 		updateRevertFunc := redir.updateRules(l4)
 		revertStack.Push(updateRevertFunc)
 
 		redir.lastUpdated = time.Now()
+		redir.reportHealth()
 
 		scopedLog.WithField(logfields.Object, logfields.Repr(redir)).
 			Debug("updated existing ", l4.L7Parser, " proxy instance")
@@ -212,12 +250,27 @@ create:
 	redir.endpointID = localEndpoint.GetID()
 	redir.ingress = l4.Ingress
 	redir.parserType = l4.L7Parser
+	redir.originatingTLS = l4.OriginatingTLS
+	redir.circuitBreaker = l4.CircuitBreaker
 	redir.updateRules(l4)
 
+	key := portKey(id, l4.L7Parser)
+
+	// A draining redirect listens on the port which is reused for the
+	// redirect, its remaining connections are closed.
+	if draining, ok := p.draining[key]; ok {
+		scopedLog.Debug("Closing draining ", l4.L7Parser, " proxy instance")
+		if implFinalizeFunc := p.closeDrainingRedirect(key, draining); implFinalizeFunc != nil {
+			implFinalizeFunc()
+		}
+	}
+
 retryCreatePort:
 	for nRetry := 0; ; nRetry++ {
 		var to uint16
-		to, err = p.allocatePort()
+		// The port previously assigned to the redirect is only reused
+		// in the first attempt, it may be in use by another process.
+		to, err = p.ports.acquire(key, nRetry == 0)
 		if err != nil {
 			revertFunc() // Ignore errors while reverting. This is best-effort.
 			return
@@ -229,10 +282,20 @@ retryCreatePort:
 		case policy.ParserTypeKafka:
 			redir.implementation, err = createKafkaRedirect(redir, kafkaConfiguration{}, DefaultEndpointInfoRegistry)
 
+		case policy.ParserTypeDNS:
+			redir.implementation, err = createDNSRedirect(redir, dnsConfiguration{
+				protocol:       l4.Protocol,
+				notifyResponse: p.dnsResponseNotifier,
+			}, DefaultEndpointInfoRegistry)
+
 		case policy.ParserTypeHTTP:
 			redir.implementation, err = createEnvoyRedirect(redir, p.stateDir, p.XDSServer, wg)
 		default:
-			redir.implementation, err = createEnvoyRedirect(redir, p.stateDir, p.XDSServer, wg)
+			if parser := getL7Parser(l4.L7Parser); parser != nil {
+				redir.implementation, err = createL7ParserRedirect(redir, parser, l7ParserConfiguration{}, DefaultEndpointInfoRegistry)
+			} else {
+				redir.implementation, err = createEnvoyRedirect(redir, p.stateDir, p.XDSServer, wg)
+			}
 		}
 
 		switch {
@@ -240,9 +303,12 @@ retryCreatePort:
 			scopedLog.WithField(logfields.Object, logfields.Repr(redir)).
 				Debug("Created new ", l4.L7Parser, " proxy instance")
 
-			p.allocatedPorts[to] = struct{}{}
 			p.redirects[id] = redir
 
+			redir.mutex.Lock()
+			redir.setHealthReported(true)
+			redir.mutex.Unlock()
+
 			revertStack.Push(func() error {
 				completionCtx, cancel := context.WithCancel(context.Background())
 				proxyWaitGroup := completion.NewWaitGroup(completionCtx)
@@ -262,6 +328,7 @@ retryCreatePort:
 		case nRetry >= redirectCreationAttempts:
 			scopedLog.WithError(err).Error("Unable to create ", l4.L7Parser, " proxy")
 
+			p.ports.remove(key)
 			revertFunc() // Ignore errors while reverting. This is best-effort.
 			return
 
@@ -295,48 +362,85 @@ func (p *Proxy) removeRedirect(id string, wg *completion.WaitGroup) (err error,
 	}
 	delete(p.redirects, id)
 
-	implFinalizeFunc, implRevertFunc := r.implementation.Close(wg)
+	// The redirect no longer handles new traffic of the endpoint
+	r.mutex.Lock()
+	r.setHealthReported(false)
+	r.mutex.Unlock()
 
-	// Delay the release and reuse of the port number so it is guaranteed to be
-	// safe to listen on the port again. This can't be reverted, so do it in a
-	// FinalizeFunc.
-	proxyPort := r.ProxyPort
-	finalizeFunc = func() {
-		if implFinalizeFunc != nil {
-			implFinalizeFunc()
-		}
-
-		go func() {
-			time.Sleep(portReuseDelay)
-
-			// The cleanup of the proxymap is delayed a bit to ensure that
-			// the datapath has implemented the redirect change and we
-			// cleanup the map before we release the port and allow reuse
-			proxymap.CleanupOnRedirectClose(proxyPort)
+	// The implementation keeps serving the existing connections of the
+	// redirect until the drain timeout has passed. Closing it and releasing
+	// the port can't be reverted, so do it in a FinalizeFunc.
+	key := portKey(id, r.parserType)
+	r.drainDeadline = time.Now().Add(option.Config.ProxyDrainTimeout)
+	p.draining[key] = r
 
+	finalizeFunc = func() {
+		closeFunc := func() {
 			p.mutex.Lock()
-			delete(p.allocatedPorts, proxyPort)
+			implFinalizeFunc := p.closeDrainingRedirect(key, r)
 			p.mutex.Unlock()
 
-			log.WithField(fieldProxyRedirectID, id).Debugf("Delayed release of proxy port %d", proxyPort)
-		}()
-	}
+			if implFinalizeFunc != nil {
+				implFinalizeFunc()
+			}
+		}
 
-	revertFunc = func() error {
-		if implRevertFunc != nil {
-			return implRevertFunc()
+		if option.Config.ProxyDrainTimeout == 0 {
+			closeFunc()
+			return
 		}
 
+		log.WithField(fieldProxyRedirectID, id).
+			Debugf("Draining proxy redirect on port %d for %s", r.ProxyPort, option.Config.ProxyDrainTimeout)
+		time.AfterFunc(option.Config.ProxyDrainTimeout, closeFunc)
+	}
+
+	revertFunc = func() error {
 		p.mutex.Lock()
+		if p.draining[key] == r {
+			delete(p.draining, key)
+		}
+		r.drainDeadline = time.Time{}
 		p.redirects[id] = r
 		p.mutex.Unlock()
 
+		r.mutex.Lock()
+		r.setHealthReported(true)
+		r.mutex.Unlock()
+
 		return nil
 	}
 
 	return
 }
 
+// closeDrainingRedirect closes the implementation of the draining redirect r
+// unless it has been closed already. The release and reuse of the port number
+// is delayed so it is guaranteed to be safe to listen on the port again. Until
+// then, the port is reused if the redirect is created again. Returns the
+// FinalizeFunc of the implementation which must be called after p.mutex has
+// been released. p.mutex must be held.
+func (p *Proxy) closeDrainingRedirect(key string, r *Redirect) revert.FinalizeFunc {
+	if p.draining[key] != r {
+		return nil
+	}
+	delete(p.draining, key)
+
+	// Don't wait for an ACK of the removal. This is best-effort.
+	completionCtx, cancel := context.WithCancel(context.Background())
+	proxyWaitGroup := completion.NewWaitGroup(completionCtx)
+	implFinalizeFunc, _ := r.implementation.Close(proxyWaitGroup)
+	cancel()
+	proxyWaitGroup.Wait() // Ignore the returned error.
+
+	// The redirect may have been created again with the same port
+	if cur, ok := p.redirects[r.id]; !ok || cur.parserType != r.parserType {
+		p.expirePort(key, r.ProxyPort, p.ports.release(key))
+	}
+
+	return implFinalizeFunc
+}
+
 // ChangeLogLevel changes proxy log level to correspond to the logrus log level 'level'.
 func ChangeLogLevel(level logrus.Level) {
 	if envoyProxy != nil {
@@ -0,0 +1,233 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cilium/cilium/pkg/completion"
+	"github.com/cilium/cilium/pkg/option"
+	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/revert"
+
+	. "gopkg.in/check.v1"
+)
+
+type closeCounter struct {
+	closed    int
+	finalized chan struct{}
+}
+
+func (r *closeCounter) Close(wg *completion.WaitGroup) (revert.FinalizeFunc, revert.RevertFunc) {
+	r.closed++
+	return func() { close(r.finalized) }, nil
+}
+
+func newTestProxy() *Proxy {
+	return &Proxy{
+		redirects: make(map[string]*Redirect),
+		draining:  make(map[string]*Redirect),
+		ports:     newTestPortAllocator(10000, 10010, ""),
+	}
+}
+
+func (p *Proxy) addTestRedirect(c *C, id string, parserType policy.L7ParserType) (*Redirect, *closeCounter) {
+	port, err := p.ports.acquire(portKey(id, parserType), true)
+	c.Assert(err, IsNil)
+
+	impl := &closeCounter{finalized: make(chan struct{})}
+	r := newRedirect(&proxyUpdaterMock{}, id)
+	r.parserType = parserType
+	r.ProxyPort = port
+	r.implementation = impl
+	p.redirects[id] = r
+	return r, impl
+}
+
+func (s *proxyTestSuite) TestRemoveRedirectDrain(c *C) {
+	oldTimeout := option.Config.ProxyDrainTimeout
+	defer func() { option.Config.ProxyDrainTimeout = oldTimeout }()
+	option.Config.ProxyDrainTimeout = 100 * time.Millisecond
+
+	p := newTestProxy()
+	id := "1:ingress:TCP:9092"
+	key := portKey(id, policy.ParserTypeKafka)
+	r, impl := p.addTestRedirect(c, id, policy.ParserTypeKafka)
+
+	// A removed redirect is draining until the drain timeout has passed
+	p.mutex.Lock()
+	err, finalizeFunc, _ := p.removeRedirect(id, nil)
+	p.mutex.Unlock()
+	c.Assert(err, IsNil)
+	c.Assert(p.redirects[id], IsNil)
+	c.Assert(p.draining[key], Equals, r)
+	c.Assert(r.drainDeadline.IsZero(), Equals, false)
+
+	finalizeFunc()
+	p.mutex.Lock()
+	c.Assert(impl.closed, Equals, 0)
+	p.mutex.Unlock()
+
+	select {
+	case <-impl.finalized:
+	case <-time.After(5 * time.Second):
+		c.Fatal("Draining redirect not closed after the drain timeout")
+	}
+
+	p.mutex.Lock()
+	c.Assert(impl.closed, Equals, 1)
+	c.Assert(p.draining[key], IsNil)
+	p.mutex.Unlock()
+}
+
+func (s *proxyTestSuite) TestRemoveRedirectNoDrain(c *C) {
+	oldTimeout := option.Config.ProxyDrainTimeout
+	defer func() { option.Config.ProxyDrainTimeout = oldTimeout }()
+	option.Config.ProxyDrainTimeout = 0
+
+	p := newTestProxy()
+	id := "1:egress:UDP:53"
+	_, impl := p.addTestRedirect(c, id, policy.ParserTypeDNS)
+
+	p.mutex.Lock()
+	err, finalizeFunc, _ := p.removeRedirect(id, nil)
+	p.mutex.Unlock()
+	c.Assert(err, IsNil)
+
+	// Without drain timeout the redirect is closed when finalized
+	finalizeFunc()
+	c.Assert(impl.closed, Equals, 1)
+	c.Assert(p.draining, HasLen, 0)
+}
+
+func (s *proxyTestSuite) TestRemoveRedirectRevert(c *C) {
+	oldTimeout := option.Config.ProxyDrainTimeout
+	defer func() { option.Config.ProxyDrainTimeout = oldTimeout }()
+	option.Config.ProxyDrainTimeout = time.Hour
+
+	p := newTestProxy()
+	id := "1:ingress:TCP:80"
+	r, impl := p.addTestRedirect(c, id, policy.ParserTypeHTTP)
+
+	p.mutex.Lock()
+	err, _, revertFunc := p.removeRedirect(id, nil)
+	p.mutex.Unlock()
+	c.Assert(err, IsNil)
+
+	// A reverted removal restores the redirect without closing it
+	c.Assert(revertFunc(), IsNil)
+	c.Assert(p.redirects[id], Equals, r)
+	c.Assert(p.draining, HasLen, 0)
+	c.Assert(r.drainDeadline.IsZero(), Equals, true)
+	c.Assert(impl.closed, Equals, 0)
+}
+
+func (s *proxyTestSuite) TestCloseDrainingRedirect(c *C) {
+	oldTimeout := option.Config.ProxyDrainTimeout
+	defer func() { option.Config.ProxyDrainTimeout = oldTimeout }()
+	option.Config.ProxyDrainTimeout = time.Hour
+
+	p := newTestProxy()
+	id := "1:ingress:TCP:80"
+	key := portKey(id, policy.ParserTypeHTTP)
+	r, impl := p.addTestRedirect(c, id, policy.ParserTypeHTTP)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	err, _, _ := p.removeRedirect(id, nil)
+	c.Assert(err, IsNil)
+
+	// The draining redirect is closed once, e.g. when its port is reused
+	c.Assert(p.closeDrainingRedirect(key, r), NotNil)
+	c.Assert(p.closeDrainingRedirect(key, r), IsNil)
+	c.Assert(impl.closed, Equals, 1)
+	c.Assert(p.draining, HasLen, 0)
+}
+
+func (s *proxyTestSuite) TestRedirectHealth(c *C) {
+	oldTimeout := option.Config.ProxyDrainTimeout
+	defer func() { option.Config.ProxyDrainTimeout = oldTimeout }()
+	option.Config.ProxyDrainTimeout = time.Hour
+
+	p := newTestProxy()
+	id := "1:ingress:TCP:9092"
+	r, _ := p.addTestRedirect(c, id, policy.ParserTypeKafka)
+	ep := r.localEndpoint.(*proxyUpdaterMock)
+
+	// Health is not reported before the redirect has been created
+	r.setListenerBound(nil)
+	c.Assert(ep.redirectHealth, IsNil)
+
+	r.mutex.Lock()
+	r.rulesLoaded = true
+	r.setHealthReported(true)
+	r.mutex.Unlock()
+	c.Assert(ep.redirectHealth.ID, Equals, id)
+	c.Assert(ep.redirectHealth.Protocol, Equals, "kafka")
+	c.Assert(ep.redirectHealth.ProxyPort, Equals, int64(r.ProxyPort))
+	c.Assert(ep.redirectHealth.ListenerBound, Equals, true)
+	c.Assert(ep.redirectHealth.RulesLoaded, Equals, true)
+	c.Assert(ep.redirectHealth.LastError, Equals, "")
+
+	r.setError(errors.New("too many open files"))
+	c.Assert(ep.redirectHealth.ListenerBound, Equals, true)
+	c.Assert(ep.redirectHealth.LastError, Equals, "too many open files")
+
+	// Only a rejected listener update is a failure to listen
+	r.onListenerUpdate(context.Canceled)
+	r.onListenerUpdate(context.DeadlineExceeded)
+	c.Assert(ep.redirectHealth.ListenerBound, Equals, true)
+	c.Assert(ep.redirectHealth.LastError, Equals, "too many open files")
+
+	r.onListenerUpdate(errors.New("address already in use"))
+	c.Assert(ep.redirectHealth.ListenerBound, Equals, false)
+	c.Assert(ep.redirectHealth.LastError, Equals, "address already in use")
+
+	// A removed redirect is no longer reported, even while draining
+	p.mutex.Lock()
+	err, _, revertFunc := p.removeRedirect(id, nil)
+	p.mutex.Unlock()
+	c.Assert(err, IsNil)
+	c.Assert(ep.redirectHealth, IsNil)
+	r.setListenerBound(nil)
+	c.Assert(ep.redirectHealth, IsNil)
+
+	// A reverted removal reports the redirect again
+	c.Assert(revertFunc(), IsNil)
+	c.Assert(ep.redirectHealth.ListenerBound, Equals, true)
+}
+
+func (s *proxyTestSuite) TestUpdateProxyConfig(c *C) {
+	p := newTestProxy()
+	r, _ := p.addTestRedirect(c, "1:ingress:TCP:9092", policy.ParserTypeKafka)
+	cb := &api.CircuitBreaker{MaxConnections: 10}
+
+	// An unchanged configuration requires no update
+	revertFunc, ok := r.updateProxyConfig(&policy.L4Filter{}, nil)
+	c.Assert(ok, Equals, true)
+	c.Assert(revertFunc, IsNil)
+
+	// The circuit breaker is updated in place
+	revertFunc, ok = r.updateProxyConfig(&policy.L4Filter{CircuitBreaker: cb}, nil)
+	c.Assert(ok, Equals, true)
+	c.Assert(r.getCircuitBreaker(), Equals, cb)
+
+	c.Assert(revertFunc(), IsNil)
+	c.Assert(r.getCircuitBreaker(), IsNil)
+}
@@ -15,16 +15,23 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"github.com/cilium/cilium/pkg/revert"
 	"net"
+	"regexp"
 	"time"
 
+	"github.com/cilium/cilium/api/v1/models"
 	"github.com/cilium/cilium/pkg/completion"
+	"github.com/cilium/cilium/pkg/envoy/xds"
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/maps/proxymap"
 	"github.com/cilium/cilium/pkg/policy"
+	"github.com/cilium/cilium/pkg/policy/api"
 	"github.com/cilium/cilium/pkg/proxy/logger"
+
+	"github.com/go-openapi/strfmt"
 )
 
 // RedirectImplementation is the generic proxy redirect interface that each
@@ -50,9 +57,26 @@ type Redirect struct {
 
 	// The following fields are updated while the redirect is alive, the
 	// mutex must be held to read and write these fields
-	mutex       lock.RWMutex
-	lastUpdated time.Time
-	rules       policy.L7DataMap
+	mutex          lock.RWMutex
+	lastUpdated    time.Time
+	rules          policy.L7DataMap
+	originatingTLS *api.TLSContext
+	circuitBreaker *api.CircuitBreaker
+
+	// dnsPatterns are the compiled DNS matchPatterns of rules
+	dnsPatterns map[string]*regexp.Regexp
+
+	// The health of the redirect, it is reported to the local endpoint
+	// while healthReported is true
+	listenerBound  bool
+	rulesLoaded    bool
+	lastError      string
+	lastErrorTime  time.Time
+	healthReported bool
+
+	// drainDeadline is the time at which the redirect is closed after it
+	// has been removed, Proxy.mutex must be held to access it
+	drainDeadline time.Time
 }
 
 func newRedirect(localEndpoint logger.EndpointUpdater, id string) *Redirect {
@@ -66,19 +90,135 @@ func newRedirect(localEndpoint logger.EndpointUpdater, id string) *Redirect {
 
 // updateRules updates the rules of the redirect, Redirect.mutex must be held
 func (r *Redirect) updateRules(l4 *policy.L4Filter) revert.RevertFunc {
-	oldRules := r.rules
+	oldRules, oldDNSPatterns, oldRulesLoaded := r.rules, r.dnsPatterns, r.rulesLoaded
 	r.rules = make(policy.L7DataMap, len(l4.L7RulesPerEp))
 	for key, val := range l4.L7RulesPerEp {
 		r.rules[key] = val
 	}
+	if r.parserType == policy.ParserTypeDNS {
+		r.dnsPatterns = dnsMatchPatterns(r.rules)
+	}
+	r.rulesLoaded = true
 	return func() error {
 		r.mutex.Lock()
-		r.rules = oldRules
+		r.rules, r.dnsPatterns, r.rulesLoaded = oldRules, oldDNSPatterns, oldRulesLoaded
 		r.mutex.Unlock()
 		return nil
 	}
 }
 
+// updateProxyConfig updates the TLS context and the circuit breaker of the
+// redirect. The cluster of an HTTP redirect is updated in place so that the
+// connections of its listener are retained. Returns false if the redirect
+// must be recreated to apply the change, Redirect.mutex must be held
+func (r *Redirect) updateProxyConfig(l4 *policy.L4Filter, wg *completion.WaitGroup) (revert.RevertFunc, bool) {
+	if r.originatingTLS.Equal(l4.OriginatingTLS) && r.circuitBreaker.Equal(l4.CircuitBreaker) {
+		return nil, true
+	}
+
+	var clusterRevertFunc xds.AckingResourceMutatorRevertFunc
+	if envoyRedir, ok := r.implementation.(*envoyRedirect); ok && r.parserType == policy.ParserTypeHTTP {
+		clusterRevertFunc, ok = envoyRedir.updateCluster(l4.OriginatingTLS, l4.CircuitBreaker, wg)
+		if !ok {
+			return nil, false
+		}
+	}
+
+	oldTLS, oldCircuitBreaker := r.originatingTLS, r.circuitBreaker
+	r.originatingTLS, r.circuitBreaker = l4.OriginatingTLS, l4.CircuitBreaker
+	return func() error {
+		r.mutex.Lock()
+		r.originatingTLS, r.circuitBreaker = oldTLS, oldCircuitBreaker
+		r.mutex.Unlock()
+		if clusterRevertFunc != nil {
+			// Don't wait for an ACK for the reverted xDS update.
+			// This is best-effort.
+			clusterRevertFunc(completion.NewCompletion(nil, nil))
+		}
+		return nil
+	}, true
+}
+
+// getCircuitBreaker returns the circuit breaker of the redirect
+func (r *Redirect) getCircuitBreaker() *api.CircuitBreaker {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.circuitBreaker
+}
+
+// getHealthModel returns the health of the redirect as API model,
+// Redirect.mutex must be held
+func (r *Redirect) getHealthModel() *models.ProxyRedirectHealth {
+	health := &models.ProxyRedirectHealth{
+		ID:            r.id,
+		Protocol:      string(r.parserType),
+		ProxyPort:     int64(r.ProxyPort),
+		ListenerBound: r.listenerBound,
+		RulesLoaded:   r.rulesLoaded,
+		LastError:     r.lastError,
+	}
+	if !r.lastErrorTime.IsZero() {
+		health.LastErrorTime = strfmt.DateTime(r.lastErrorTime)
+	}
+	return health
+}
+
+// setHealthReported starts or stops reporting the health of the redirect to
+// the local endpoint, Redirect.mutex must be held
+func (r *Redirect) setHealthReported(reported bool) {
+	r.healthReported = reported
+	if reported {
+		r.localEndpoint.UpdateProxyRedirectHealth(r.id, r.getHealthModel())
+	} else {
+		r.localEndpoint.UpdateProxyRedirectHealth(r.id, nil)
+	}
+}
+
+// reportHealth reports the health of the redirect to the local endpoint
+// unless the redirect has been removed, Redirect.mutex must be held
+func (r *Redirect) reportHealth() {
+	if r.healthReported {
+		r.localEndpoint.UpdateProxyRedirectHealth(r.id, r.getHealthModel())
+	}
+}
+
+// setListenerBound records whether the proxy is listening on the proxy port.
+// If err is not nil, the proxy failed to listen on the port.
+func (r *Redirect) setListenerBound(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.listenerBound = err == nil
+	if err != nil {
+		r.lastError = err.Error()
+		r.lastErrorTime = time.Now()
+	}
+	r.reportHealth()
+}
+
+// onListenerUpdate records the result of a listener update of the proxy as
+// reported by the completion of the update. The completion is also called if
+// the wait for the proxy was canceled or timed out, in which case it is
+// unknown whether the proxy is listening on the port and only a rejection of
+// the update is recorded as a failure to listen.
+func (r *Redirect) onListenerUpdate(err error) {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return
+	}
+	r.setListenerBound(err)
+}
+
+// setError records an error of the proxy that doesn't affect whether it is
+// listening on the proxy port.
+func (r *Redirect) setError(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lastError = err.Error()
+	r.lastErrorTime = time.Now()
+	r.reportHealth()
+}
+
 // removeProxyMapEntryOnClose is called after the proxy has closed a connection
 // and will remove the proxymap entry for that connection
 func (r *Redirect) removeProxyMapEntryOnClose(c net.Conn) error {
@@ -27,6 +27,7 @@ import (
 	"github.com/cilium/cilium/pkg/lock"
 	"github.com/cilium/cilium/pkg/logging/logfields"
 	"github.com/cilium/cilium/pkg/maps/proxymap"
+	"github.com/cilium/cilium/pkg/u8proto"
 
 	"github.com/sirupsen/logrus"
 )
@@ -105,6 +106,50 @@ func listenSocket(address string, mark int) (*proxySocket, error) {
 	return socket, nil
 }
 
+// listenPacketSocket opens a UDP socket bound to address. If mark is not 0,
+// all packets sent on the socket are marked with it.
+func listenPacketSocket(address string, mark int) (net.PacketConn, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	family := syscall.AF_INET
+	if addr.IP.To4() == nil {
+		family = syscall.AF_INET6
+	}
+
+	fd, err := syscall.Socket(family, syscall.SOCK_DGRAM, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("unable to set SO_REUSEADDR socket option: %s", err)
+	}
+
+	if mark != 0 {
+		setFdMark(fd, mark)
+	}
+
+	sockAddr, err := ipToSockaddr(family, addr.IP, addr.Port, addr.Zone)
+	if err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+
+	if err := syscall.Bind(fd, sockAddr); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+
+	f := os.NewFile(uintptr(fd), addr.String())
+	defer f.Close()
+
+	return net.FilePacketConn(f)
+}
+
 func setLinger(c net.Conn, linger time.Duration) error {
 	if tcp, ok := c.(*net.TCPConn); ok {
 		if err := tcp.SetLinger(int(linger.Seconds())); err != nil {
@@ -433,7 +478,18 @@ func (p *connectionPair) close() {
 }
 
 func lookupNewDest(remoteAddr string, dport uint16) (uint32, string, error) {
-	key, err := createProxyMapKey(remoteAddr, dport)
+	return lookupProxyMapDest(remoteAddr, dport, u8proto.TCP)
+}
+
+// lookupNewUDPDest is the destLookupFunc for redirects of UDP traffic
+func lookupNewUDPDest(remoteAddr string, dport uint16) (uint32, string, error) {
+	return lookupProxyMapDest(remoteAddr, dport, u8proto.UDP)
+}
+
+// lookupProxyMapDest returns the identity of the source together with the
+// original destination of the flow from remoteAddr to the proxy port dport
+func lookupProxyMapDest(remoteAddr string, dport uint16, nexthdr u8proto.U8proto) (uint32, string, error) {
+	key, err := createProxyMapKey(remoteAddr, dport, nexthdr)
 	if err != nil {
 		return 0, "", err
 	}
@@ -477,10 +533,10 @@ func getProxyMapKey(c net.Conn, proxyPort uint16) (proxymap.ProxyMapKey, error)
 		return nil, fmt.Errorf("RemoteAddr() returned nil")
 	}
 
-	return createProxyMapKey(addr.String(), proxyPort)
+	return createProxyMapKey(addr.String(), proxyPort, u8proto.TCP)
 }
 
-func createProxyMapKey(addr string, proxyPort uint16) (proxymap.ProxyMapKey, error) {
+func createProxyMapKey(addr string, proxyPort uint16, nexthdr u8proto.U8proto) (proxymap.ProxyMapKey, error) {
 	ip, port, err := net.SplitHostPort(addr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid remote address '%s': %s", addr, err)
@@ -500,7 +556,7 @@ func createProxyMapKey(addr string, proxyPort uint16) (proxymap.ProxyMapKey, err
 		key := proxymap.Proxy4Key{
 			SPort:   uint16(sport),
 			DPort:   proxyPort,
-			Nexthdr: 6,
+			Nexthdr: uint8(nexthdr),
 		}
 
 		copy(key.SAddr[:], pIP.To4())
@@ -510,7 +566,7 @@ func createProxyMapKey(addr string, proxyPort uint16) (proxymap.ProxyMapKey, err
 	key := proxymap.Proxy6Key{
 		SPort:   uint16(sport),
 		DPort:   proxyPort,
-		Nexthdr: 6,
+		Nexthdr: uint8(nexthdr),
 	}
 
 	copy(key.SAddr[:], pIP.To16())
@@ -0,0 +1,65 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sockops
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/cilium/pkg/mountinfo"
+
+	"golang.org/x/sys/unix"
+)
+
+// cgroupMountType returns the filesystem type mounted at path, or an empty
+// string if nothing is mounted at path
+func cgroupMountType(mounts []*mountinfo.MountInfo, path string) string {
+	path = filepath.Clean(path)
+	fsType := ""
+	// Later mounts hide earlier mounts at the same mount point
+	for _, m := range mounts {
+		if filepath.Clean(m.MountPoint) == path {
+			fsType = m.FilesystemType
+		}
+	}
+	return fsType
+}
+
+// checkOrMountCgroup mounts the cgroup2 filesystem at path unless it is
+// already mounted there.
+func checkOrMountCgroup(path string) error {
+	mounts, err := mountinfo.GetMountInfo()
+	if err != nil {
+		return err
+	}
+
+	switch fsType := cgroupMountType(mounts, path); fsType {
+	case mountinfo.FilesystemTypeCgroup2:
+		return nil
+	case "":
+	default:
+		return fmt.Errorf("mount at %s has type %s, expected %s",
+			path, fsType, mountinfo.FilesystemTypeCgroup2)
+	}
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("unable to create cgroup2 mount directory: %s", err)
+	}
+	if err := unix.Mount("none", path, mountinfo.FilesystemTypeCgroup2, 0, ""); err != nil {
+		return fmt.Errorf("failed to mount %s: %s", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sockops
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/cilium/cilium/pkg/bpf"
+	"github.com/cilium/cilium/pkg/datapath/loader"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+	"github.com/cilium/cilium/pkg/maps/lxcmap"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "sockops")
+
+const (
+	// MapName is the name of the sockhash map holding the sockets of
+	// connections between local endpoints
+	MapName = "cilium_sock_ops"
+
+	// MaxEntries is the maximum number of sockets in the sockhash map
+	MaxEntries = 65535
+
+	// keySize is the size of struct sock_key in bpf/sockops/bpf_sockops.h
+	keySize = 20
+
+	// valueSize is the size of the socket file descriptors in the map
+	valueSize = 4
+
+	// bpftool is used to load and attach the programs as the sock_ops and
+	// sk_msg program types are not supported by iproute2
+	bpftool = "bpftool"
+
+	// bpftoolTimeout is the maximum duration of a single bpftool command
+	bpftoolTimeout = 10 * time.Second
+
+	// progDir is the directory relative to the BPF filesystem root in
+	// which the programs are pinned
+	progDir = "sockops"
+)
+
+// program is a sockops BPF program, its attach type and the pinned maps it
+// refers to
+type program struct {
+	// source is the path of the program relative to the BPF library
+	// directory
+	source string
+
+	// object is the file name of the compiled program
+	object string
+
+	// progType is the program type passed to bpftool
+	progType string
+
+	// attachType is the attach type passed to bpftool
+	attachType string
+
+	// maps are the names of the pinned maps the program refers to
+	maps []string
+}
+
+var (
+	sockopsProg = program{
+		source:     "sockops/bpf_sockops.c",
+		object:     "bpf_sockops.o",
+		progType:   "sockops",
+		attachType: "sock_ops",
+		maps:       []string{lxcmap.MapName, MapName},
+	}
+
+	redirProg = program{
+		source:     "sockops/bpf_redir.c",
+		object:     "bpf_redir.o",
+		progType:   "sk_msg",
+		attachType: "msg_verdict",
+		maps:       []string{MapName},
+	}
+)
+
+// mapPath returns the path of the pinned map with the given name
+func mapPath(name string) string {
+	return filepath.Join(bpf.MapPrefixPath(), name)
+}
+
+// pinPath returns the path the program is pinned at
+func (p *program) pinPath() string {
+	return filepath.Join(bpf.GetMapRoot(), progDir, p.progType)
+}
+
+// loadArgs returns the bpftool arguments to load the compiled program at the
+// path object and to pin it
+func (p *program) loadArgs(object string) []string {
+	args := []string{"prog", "load", object, p.pinPath(), "type", p.progType}
+	for _, name := range p.maps {
+		args = append(args, "map", "name", name, "pinned", mapPath(name))
+	}
+	return args
+}
+
+// sockopsAttachArgs returns the bpftool arguments to attach or detach the
+// sock_ops program to the cgroup at cgroupRoot, cmd is either "attach" or
+// "detach"
+func sockopsAttachArgs(cmd, cgroupRoot string) []string {
+	return []string{"cgroup", cmd, cgroupRoot, sockopsProg.attachType, "pinned", sockopsProg.pinPath()}
+}
+
+// redirAttachArgs returns the bpftool arguments to attach or detach the
+// sk_msg program to the sockhash map, cmd is either "attach" or "detach"
+func redirAttachArgs(cmd string) []string {
+	return []string{"prog", cmd, "pinned", redirProg.pinPath(), redirProg.attachType, "pinned", mapPath(MapName)}
+}
+
+// runBpftool runs bpftool with the given arguments
+func runBpftool(args ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), bpftoolTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, bpftool, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %v failed: %s: %s", bpftool, args, err, out)
+	}
+	return nil
+}
+
+// createMap creates the sockhash map unless it is already pinned. Fails on
+// kernels without support for sockhash maps.
+func createMap() error {
+	fd, _, err := bpf.OpenOrCreateMap(mapPath(MapName), bpf.BPF_MAP_TYPE_SOCKHASH,
+		keySize, valueSize, MaxEntries, 0)
+	if err != nil {
+		return fmt.Errorf("unable to create sockhash map %s: %s", MapName, err)
+	}
+	unix.Close(fd)
+	return nil
+}
+
+// Enable compiles the sockops programs into the directory dir, attaches the
+// sock_ops program to the cgroup2 hierarchy at cgroupRoot, which is mounted if
+// necessary, and attaches the sk_msg program to the sockhash map.
+//
+// Returns an error if the kernel or bpftool lack support for sockops, the
+// caller is expected to continue without acceleration and to call Disable to
+// remove any partially attached programs.
+func Enable(ctx context.Context, dir, cgroupRoot string) error {
+	if _, err := exec.LookPath(bpftool); err != nil {
+		return fmt.Errorf("%s not found: %s", bpftool, err)
+	}
+
+	if err := checkOrMountCgroup(cgroupRoot); err != nil {
+		return err
+	}
+
+	if err := createMap(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(bpf.GetMapRoot(), progDir), 0755); err != nil {
+		return err
+	}
+
+	for _, prog := range []*program{&sockopsProg, &redirProg} {
+		if err := loader.CompileObject(ctx, prog.source, prog.object, dir); err != nil {
+			return err
+		}
+
+		// Replace the programs of a previous run
+		os.Remove(prog.pinPath())
+		if err := runBpftool(prog.loadArgs(filepath.Join(dir, prog.object))...); err != nil {
+			return err
+		}
+	}
+
+	if err := runBpftool(sockopsAttachArgs("attach", cgroupRoot)...); err != nil {
+		return err
+	}
+	if err := runBpftool(redirAttachArgs("attach")...); err != nil {
+		return err
+	}
+
+	log.WithFields(logrus.Fields{
+		logfields.Path:       cgroupRoot,
+		logfields.BPFMapName: MapName,
+	}).Info("Sockops acceleration enabled")
+
+	return nil
+}
+
+// Disable detaches the sockops programs from the cgroup2 hierarchy at
+// cgroupRoot and from the sockhash map, and removes the pinned programs and
+// the map. Programs which are not attached are ignored.
+func Disable(cgroupRoot string) {
+	if _, err := os.Stat(sockopsProg.pinPath()); err == nil {
+		if err := runBpftool(sockopsAttachArgs("detach", cgroupRoot)...); err != nil {
+			log.WithError(err).Debug("Unable to detach sock_ops program")
+		}
+	}
+	if _, err := os.Stat(redirProg.pinPath()); err == nil {
+		if err := runBpftool(redirAttachArgs("detach")...); err != nil {
+			log.WithError(err).Debug("Unable to detach sk_msg program")
+		}
+	}
+
+	for _, path := range []string{sockopsProg.pinPath(), redirProg.pinPath(), mapPath(MapName)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.WithError(err).WithField(logfields.Path, path).Warning("Unable to remove sockops object")
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sockops
+
+import (
+	"testing"
+
+	"github.com/cilium/cilium/pkg/mountinfo"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type SockopsSuite struct{}
+
+var _ = Suite(&SockopsSuite{})
+
+func (s *SockopsSuite) TestLoadArgs(c *C) {
+	args := sockopsProg.loadArgs("/run/bpf_sockops.o")
+	c.Assert(args, DeepEquals, []string{
+		"prog", "load", "/run/bpf_sockops.o", sockopsProg.pinPath(), "type", "sockops",
+		"map", "name", "cilium_lxc", "pinned", mapPath("cilium_lxc"),
+		"map", "name", MapName, "pinned", mapPath(MapName),
+	})
+
+	args = redirProg.loadArgs("/run/bpf_redir.o")
+	c.Assert(args, DeepEquals, []string{
+		"prog", "load", "/run/bpf_redir.o", redirProg.pinPath(), "type", "sk_msg",
+		"map", "name", MapName, "pinned", mapPath(MapName),
+	})
+	c.Assert(redirProg.pinPath(), Not(Equals), sockopsProg.pinPath())
+}
+
+func (s *SockopsSuite) TestAttachArgs(c *C) {
+	c.Assert(sockopsAttachArgs("attach", "/cgroup"), DeepEquals, []string{
+		"cgroup", "attach", "/cgroup", "sock_ops", "pinned", sockopsProg.pinPath(),
+	})
+	c.Assert(redirAttachArgs("detach"), DeepEquals, []string{
+		"prog", "detach", "pinned", redirProg.pinPath(), "msg_verdict", "pinned", mapPath(MapName),
+	})
+}
+
+func (s *SockopsSuite) TestCgroupMountType(c *C) {
+	mounts := []*mountinfo.MountInfo{
+		{MountPoint: "/sys/fs/cgroup", FilesystemType: "tmpfs"},
+		{MountPoint: "/var/run/cilium/cgroupv2", FilesystemType: "tmpfs"},
+		{MountPoint: "/var/run/cilium/cgroupv2", FilesystemType: mountinfo.FilesystemTypeCgroup2},
+	}
+
+	c.Assert(cgroupMountType(mounts, "/var/run/cilium/cgroupv2/"), Equals, mountinfo.FilesystemTypeCgroup2)
+	c.Assert(cgroupMountType(mounts, "/sys/fs/cgroup"), Equals, "tmpfs")
+	c.Assert(cgroupMountType(mounts, "/sys/fs/cgroup/unified"), Equals, "")
+}
@@ -1,4 +1,4 @@
-// Copyright 2017 Authors of Cilium
+// Copyright 2018 Authors of Cilium
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -12,15 +12,26 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package main
+package versioncheck
 
 import (
+	"testing"
+
 	go_version "github.com/hashicorp/go-version"
 
 	. "gopkg.in/check.v1"
 )
 
-func (ds *DaemonSuite) TestParseKernelVersion(c *C) {
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type VersionCheckSuite struct{}
+
+var _ = Suite(&VersionCheckSuite{})
+
+func (s *VersionCheckSuite) TestParseKernelVersion(c *C) {
 	mustHaveVersion := func(v string) *go_version.Version {
 		ver, err := go_version.NewVersion(v)
 		c.Assert(err, IsNil)
@@ -40,8 +51,8 @@ func (ds *DaemonSuite) TestParseKernelVersion(c *C) {
 		{"4.9.generic", mustHaveVersion("4.9.0")},
 	}
 	for _, tt := range flagtests {
-		s, err := parseKernelVersion(tt.in)
+		v, err := ParseKernelVersion(tt.in)
 		c.Assert(err, IsNil)
-		c.Assert(tt.out.Equal(s), Equals, true)
+		c.Assert(tt.out.Equal(v), Equals, true)
 	}
 }
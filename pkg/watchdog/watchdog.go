@@ -0,0 +1,251 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchdog
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/lock"
+	"github.com/cilium/cilium/pkg/logging"
+	"github.com/cilium/cilium/pkg/logging/logfields"
+
+	"github.com/shirou/gopsutil/process"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logging.DefaultLogger.WithField(logfields.LogSubsys, "watchdog")
+
+// Action is an action taken by the watchdog when a budget is exceeded
+type Action string
+
+const (
+	// ActionLog logs a warning and emits an agent event
+	ActionLog Action = "log"
+
+	// ActionGC runs the garbage collector and returns memory to the
+	// operating system
+	ActionGC Action = "gc"
+
+	// ActionRestartMonitor restarts the monitor and thereby drops all
+	// monitor consumers and their buffered events
+	ActionRestartMonitor Action = "restart-monitor"
+
+	// ActionDegrade marks the node as degraded in the agent status
+	ActionDegrade Action = "degrade"
+)
+
+// DefaultActions is the default list of actions taken in order when a budget
+// is exceeded
+const DefaultActions = defaults.WatchdogActions
+
+// ParseActions parses a comma separated list of actions
+func ParseActions(value string) ([]Action, error) {
+	var actions []Action
+	for _, s := range strings.Split(value, ",") {
+		a := Action(strings.TrimSpace(s))
+		switch a {
+		case "":
+			continue
+		case ActionLog, ActionGC, ActionRestartMonitor, ActionDegrade:
+			actions = append(actions, a)
+		default:
+			return nil, fmt.Errorf("invalid watchdog action %q, valid actions = {%s}", a, DefaultActions)
+		}
+	}
+	return actions, nil
+}
+
+// Budget is the maximum resource usage of the agent. A zero value disables
+// the respective check.
+type Budget struct {
+	// RSS is the maximum resident set size in bytes
+	RSS uint64
+
+	// Goroutines is the maximum number of goroutines
+	Goroutines int
+
+	// MapMemory is the maximum memory of all open BPF maps in bytes
+	MapMemory uint64
+}
+
+// IsEnabled returns true if at least one budget is configured
+func (b Budget) IsEnabled() bool {
+	return b.RSS != 0 || b.Goroutines != 0 || b.MapMemory != 0
+}
+
+// Usage is the resource usage of the agent
+type Usage struct {
+	RSS        uint64
+	Goroutines int
+	MapMemory  uint64
+}
+
+// Exceeded returns a description of each budget exceeded by u
+func (b Budget) Exceeded(u Usage) []string {
+	var exceeded []string
+	if b.RSS != 0 && u.RSS > b.RSS {
+		exceeded = append(exceeded, fmt.Sprintf("RSS %d MiB exceeds budget of %d MiB",
+			toMiB(u.RSS), toMiB(b.RSS)))
+	}
+	if b.Goroutines != 0 && u.Goroutines > b.Goroutines {
+		exceeded = append(exceeded, fmt.Sprintf("%d goroutines exceed budget of %d",
+			u.Goroutines, b.Goroutines))
+	}
+	if b.MapMemory != 0 && u.MapMemory > b.MapMemory {
+		exceeded = append(exceeded, fmt.Sprintf("BPF map memory %d MiB exceeds budget of %d MiB",
+			toMiB(u.MapMemory), toMiB(b.MapMemory)))
+	}
+	return exceeded
+}
+
+func toMiB(bytes uint64) uint64 {
+	return bytes / 1024 / 1024
+}
+
+// Handlers implement the actions which depend on other parts of the agent.
+// Nil handlers are skipped.
+type Handlers struct {
+	// SendEvent emits an agent event listing the exceeded budgets
+	SendEvent func(exceeded []string)
+
+	// RestartMonitor restarts the monitor
+	RestartMonitor func()
+
+	// SetDegraded marks the node as degraded for the given reason, an
+	// empty reason clears the mark
+	SetDegraded func(reason string)
+
+	// MapMemory returns the memory of all open BPF maps in bytes
+	MapMemory func() uint64
+}
+
+// Watchdog compares the resource usage of the agent against a budget
+type Watchdog struct {
+	budget   Budget
+	actions  []Action
+	handlers Handlers
+
+	// readUsage returns the current resource usage, it is replaced in
+	// unit tests
+	readUsage func() Usage
+
+	mutex lock.Mutex
+
+	// exceeded is true while a budget is exceeded. Actions which are
+	// disruptive are only taken once each time a budget is exceeded.
+	exceeded bool
+}
+
+// NewWatchdog returns a watchdog taking actions in the given order when
+// budget is exceeded
+func NewWatchdog(budget Budget, actions []Action, handlers Handlers) *Watchdog {
+	w := &Watchdog{
+		budget:   budget,
+		actions:  actions,
+		handlers: handlers,
+	}
+	w.readUsage = w.getUsage
+	return w
+}
+
+func (w *Watchdog) getUsage() Usage {
+	u := Usage{
+		Goroutines: runtime.NumGoroutine(),
+	}
+	if p, err := process.NewProcess(int32(os.Getpid())); err == nil {
+		if memInfo, err := p.MemoryInfo(); err == nil && memInfo != nil {
+			u.RSS = memInfo.RSS
+		}
+	}
+	if w.handlers.MapMemory != nil {
+		u.MapMemory = w.handlers.MapMemory()
+	}
+	return u
+}
+
+// Check compares the current resource usage against the budget and takes
+// the configured actions if it is exceeded. Actions are taken in order until
+// the usage is back within the budget. Returns an error describing the
+// exceeded budgets if the usage is still above the budget afterwards.
+func (w *Watchdog) Check() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	usage := w.readUsage()
+	exceeded := w.budget.Exceeded(usage)
+	if len(exceeded) == 0 {
+		if w.exceeded {
+			w.setRecovered(log)
+		}
+		return nil
+	}
+
+	firstViolation := !w.exceeded
+	w.exceeded = true
+
+	scopedLog := log.WithFields(logrus.Fields{
+		"rss":        usage.RSS,
+		"goroutines": usage.Goroutines,
+		"mapMemory":  usage.MapMemory,
+	})
+
+	for _, action := range w.actions {
+		switch action {
+		case ActionLog:
+			scopedLog.WithField("exceeded", exceeded).Warning("Resource budget exceeded")
+			if firstViolation && w.handlers.SendEvent != nil {
+				w.handlers.SendEvent(exceeded)
+			}
+		case ActionGC:
+			debug.FreeOSMemory()
+		case ActionRestartMonitor:
+			if firstViolation && w.handlers.RestartMonitor != nil {
+				scopedLog.Warning("Restarting monitor to reduce resource usage")
+				w.handlers.RestartMonitor()
+			}
+		case ActionDegrade:
+			if w.handlers.SetDegraded != nil {
+				w.handlers.SetDegraded(strings.Join(exceeded, ", "))
+			}
+		}
+
+		// Stop as soon as an action brought the usage back within
+		// the budget
+		if action == ActionGC || action == ActionRestartMonitor {
+			usage = w.readUsage()
+			if exceeded = w.budget.Exceeded(usage); len(exceeded) == 0 {
+				w.setRecovered(scopedLog.WithField("action", action))
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("resource budget exceeded: %s", strings.Join(exceeded, ", "))
+}
+
+// setRecovered clears the exceeded state after the usage is back within the
+// budget. Must be called with w.mutex held.
+func (w *Watchdog) setRecovered(scopedLog *logrus.Entry) {
+	scopedLog.Info("Resource usage is back within budget")
+	w.exceeded = false
+	if w.handlers.SetDegraded != nil {
+		w.handlers.SetDegraded("")
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2018 Authors of Cilium
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchdog
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+// Hook up gocheck into the "go test" runner.
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type WatchdogSuite struct{}
+
+var _ = Suite(&WatchdogSuite{})
+
+func (s *WatchdogSuite) TestParseActions(c *C) {
+	actions, err := ParseActions(DefaultActions)
+	c.Assert(err, IsNil)
+	c.Assert(actions, DeepEquals, []Action{ActionLog, ActionGC, ActionRestartMonitor, ActionDegrade})
+
+	actions, err = ParseActions(" log, degrade ")
+	c.Assert(err, IsNil)
+	c.Assert(actions, DeepEquals, []Action{ActionLog, ActionDegrade})
+
+	actions, err = ParseActions("")
+	c.Assert(err, IsNil)
+	c.Assert(len(actions), Equals, 0)
+
+	_, err = ParseActions("log,reboot")
+	c.Assert(err, Not(IsNil))
+}
+
+func (s *WatchdogSuite) TestBudgetExceeded(c *C) {
+	b := Budget{RSS: 100 << 20, Goroutines: 1000}
+	c.Assert(b.IsEnabled(), Equals, true)
+	c.Assert(Budget{}.IsEnabled(), Equals, false)
+
+	c.Assert(len(b.Exceeded(Usage{RSS: 100 << 20, Goroutines: 1000, MapMemory: 1 << 30})), Equals, 0)
+	c.Assert(b.Exceeded(Usage{RSS: 200 << 20, Goroutines: 1001}), DeepEquals, []string{
+		"RSS 200 MiB exceeds budget of 100 MiB",
+		"1001 goroutines exceed budget of 1000",
+	})
+}
+
+func (s *WatchdogSuite) TestCheck(c *C) {
+	var (
+		events, restarts int
+		degraded         string
+		usage            Usage
+	)
+
+	w := NewWatchdog(Budget{Goroutines: 10}, []Action{ActionLog, ActionRestartMonitor, ActionDegrade}, Handlers{
+		SendEvent:      func(exceeded []string) { events++ },
+		RestartMonitor: func() { restarts++ },
+		SetDegraded:    func(reason string) { degraded = reason },
+	})
+	w.readUsage = func() Usage { return usage }
+
+	usage.Goroutines = 10
+	c.Assert(w.Check(), IsNil)
+	c.Assert(events, Equals, 0)
+
+	// Disruptive actions are only taken once while the budget is exceeded
+	usage.Goroutines = 20
+	c.Assert(w.Check(), Not(IsNil))
+	c.Assert(w.Check(), Not(IsNil))
+	c.Assert(events, Equals, 1)
+	c.Assert(restarts, Equals, 1)
+	c.Assert(degraded, Equals, "20 goroutines exceed budget of 10")
+
+	usage.Goroutines = 5
+	c.Assert(w.Check(), IsNil)
+	c.Assert(degraded, Equals, "")
+
+	usage.Goroutines = 20
+	c.Assert(w.Check(), Not(IsNil))
+	c.Assert(events, Equals, 2)
+	c.Assert(restarts, Equals, 2)
+}
+
+func (s *WatchdogSuite) TestCheckRecoversAfterAction(c *C) {
+	var degraded bool
+	usage := Usage{Goroutines: 20}
+
+	w := NewWatchdog(Budget{Goroutines: 10}, []Action{ActionRestartMonitor, ActionDegrade}, Handlers{
+		RestartMonitor: func() { usage.Goroutines = 5 },
+		SetDegraded:    func(reason string) { degraded = reason != "" },
+	})
+	w.readUsage = func() Usage { return usage }
+
+	// The node is not marked as degraded if restarting the monitor
+	// brought the usage back within the budget
+	c.Assert(w.Check(), IsNil)
+	c.Assert(degraded, Equals, false)
+}
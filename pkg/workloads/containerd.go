@@ -106,7 +106,7 @@ func newContainerDClient(opts workloadRuntimeOpts) (WorkloadRuntime, error) {
 	if p.Scheme == "" {
 		ep = "unix://" + ep
 	}
-	rsc, err := newCRIClient(context.WithValue(context.Background(), epOpt, ep))
+	rsc, err := newCRIClient(context.WithValue(context.Background(), epOpt, ep), ContainerD)
 	return &containerDClient{c, rsc}, err
 }
 
@@ -62,15 +62,18 @@ func getGRPCCLient(ctx context.Context) (*grpc.ClientConn, error) {
 
 type criClient struct {
 	criRuntime.RuntimeServiceClient
+
+	// runtime is the container runtime implementing the CRI
+	runtime workloadRuntimeType
 }
 
-func newCRIClient(ctx context.Context) (*criClient, error) {
+func newCRIClient(ctx context.Context, runtime workloadRuntimeType) (*criClient, error) {
 	cc, err := getGRPCCLient(ctx)
 	if err != nil {
 		return nil, err
 	}
 	rsc := criRuntime.NewRuntimeServiceClient(cc)
-	return &criClient{rsc}, nil
+	return &criClient{rsc, runtime}, nil
 }
 
 // IsRunning returns false if the provided endpoint cannot be associated with a
@@ -262,6 +265,7 @@ func (c *criClient) handleCreateWorkload(id string, retry bool) {
 		}
 
 		ep.SetContainerID(id)
+		ep.SetContainerRuntime(string(c.runtime))
 
 		// In Kubernetes mode, attempt to retrieve pod name stored in
 		// pod runtime label
@@ -449,6 +449,7 @@ func (d *dockerClient) handleCreateWorkload(id string, retry bool) {
 		}
 
 		ep.SetContainerID(id)
+		ep.SetContainerRuntime(string(Docker))
 
 		if dockerContainer.NetworkSettings != nil {
 			id := dockerContainer.NetworkSettings.EndpointID
@@ -92,6 +92,14 @@ type NetworkInfo struct {
 	} `json:"labels,omitempty"`
 }
 
+// K8sArgs contains the arguments passed by Kubernetes to the cni plugin in
+// CNI_ARGS
+type K8sArgs struct {
+	cniTypes.CommonArgs
+	K8S_POD_NAME      cniTypes.UnmarshallableString
+	K8S_POD_NAMESPACE cniTypes.UnmarshallableString
+}
+
 func main() {
 	skel.PluginMain(cmdAdd, cmdDel, version.All)
 }
@@ -339,6 +347,14 @@ func cmdAdd(args *skel.CmdArgs) error {
 		Addressing:  &models.AddressPair{},
 	}
 
+	k8sArgs := K8sArgs{}
+	if err := cniTypes.LoadArgs(args.Args, &k8sArgs); err != nil {
+		logger.WithError(err).Warn("Unable to parse CNI arguments, pod name will be unknown")
+	} else {
+		ep.K8sPodName = string(k8sArgs.K8S_POD_NAME)
+		ep.K8sNamespace = string(k8sArgs.K8S_POD_NAMESPACE)
+	}
+
 	veth, peer, tmpIfName, err := connector.SetupVeth(ep.ContainerID, int(conf.DeviceMTU), ep)
 	if err != nil {
 		return err
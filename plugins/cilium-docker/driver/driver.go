@@ -318,6 +318,7 @@ func (driver *driver) createEndpoint(w http.ResponseWriter, r *http.Request) {
 		State:            models.EndpointStateWaitingForIdentity,
 		DockerEndpointID: create.EndpointID,
 		DockerNetworkID:  create.NetworkID,
+		ContainerRuntime: "docker",
 		Addressing: &models.AddressPair{
 			IPV6: ip6.String(),
 			IPV4: create.Interface.Address,